@@ -1,7 +1,9 @@
 package v1
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
 	"github.com/odpf/optimus/models"
 	"github.com/pkg/errors"
+	protov2 "google.golang.org/protobuf/proto"
 )
 
 // Note: all config keys will be converted to upper case automatically
@@ -42,7 +45,8 @@ func (adapt *Adapter) FromJobProto(spec *pb.JobSpecification) (models.JobSpec, e
 	dependencies := map[string]models.JobSpecDependency{}
 	for _, dep := range spec.Dependencies {
 		dependencies[dep.GetName()] = models.JobSpecDependency{
-			Type: models.JobSpecDependencyType(dep.GetType()),
+			Type:        models.JobSpecDependencyType(dep.GetType()),
+			ProjectName: dep.GetProjectName(),
 		}
 	}
 
@@ -91,6 +95,21 @@ func (adapt *Adapter) FromJobProto(spec *pb.JobSpecification) (models.JobSpec, e
 			})
 		}
 	}
+
+	slaDuration := time.Duration(0)
+	if spec.SlaDuration != nil && spec.SlaDuration.IsValid() {
+		slaDuration = spec.SlaDuration.AsDuration()
+	}
+
+	var resources models.JobSpecTaskResources
+	if spec.Resources != nil {
+		resources = models.JobSpecTaskResources{
+			CPURequest:    spec.Resources.CpuRequest,
+			CPULimit:      spec.Resources.CpuLimit,
+			MemoryRequest: spec.Resources.MemoryRequest,
+			MemoryLimit:   spec.Resources.MemoryLimit,
+		}
+	}
 	return models.JobSpec{
 		Version:     int(spec.Version),
 		Name:        spec.Name,
@@ -101,6 +120,7 @@ func (adapt *Adapter) FromJobProto(spec *pb.JobSpecification) (models.JobSpec, e
 			Interval:  spec.Interval,
 			StartDate: startDate,
 			EndDate:   endDate,
+			Timezone:  spec.Timezone,
 		},
 		Assets: models.JobAssets{}.FromMap(spec.Assets),
 		Behavior: models.JobSpecBehavior{
@@ -114,12 +134,16 @@ func (adapt *Adapter) FromJobProto(spec *pb.JobSpecification) (models.JobSpec, e
 			Notify: notifiers,
 		},
 		Task: models.JobSpecTask{
-			Unit:   execUnit,
-			Config: taskConfigs,
-			Window: window,
+			Unit:      execUnit,
+			Config:    taskConfigs,
+			Window:    window,
+			Resources: resources,
 		},
 		Dependencies: dependencies,
 		Hooks:        hooks,
+		Extends:      spec.Extends,
+		IsTemplate:   spec.IsTemplate,
+		SLADuration:  slaDuration,
 	}, nil
 }
 
@@ -168,6 +192,7 @@ func (adapt *Adapter) ToJobProto(spec models.JobSpec) (*pb.JobSpecification, err
 		Name:             spec.Name,
 		Owner:            spec.Owner,
 		Interval:         spec.Schedule.Interval,
+		Timezone:         spec.Schedule.Timezone,
 		StartDate:        spec.Schedule.StartDate.Format(models.JobDatetimeLayout),
 		DependsOnPast:    spec.Behavior.DependsOnPast,
 		CatchUp:          spec.Behavior.CatchUp,
@@ -180,6 +205,8 @@ func (adapt *Adapter) ToJobProto(spec models.JobSpec) (*pb.JobSpecification, err
 		Hooks:            adaptedHook,
 		Description:      spec.Description,
 		Labels:           spec.Labels,
+		Extends:          spec.Extends,
+		IsTemplate:       spec.IsTemplate,
 		Behavior: &pb.JobSpecification_Behavior{
 			Retry: &pb.JobSpecification_Behavior_Retry{
 				Count:              int32(spec.Behavior.Retry.Count),
@@ -192,10 +219,23 @@ func (adapt *Adapter) ToJobProto(spec models.JobSpec) (*pb.JobSpecification, err
 	if spec.Schedule.EndDate != nil {
 		conf.EndDate = spec.Schedule.EndDate.Format(models.JobDatetimeLayout)
 	}
+	// left out when not set so checksums of specs without it don't change
+	if spec.SLADuration > 0 {
+		conf.SlaDuration = ptypes.DurationProto(spec.SLADuration)
+	}
+	if !spec.Task.Resources.IsZero() {
+		conf.Resources = &pb.JobSpecification_Resources{
+			CpuRequest:    spec.Task.Resources.CPURequest,
+			CpuLimit:      spec.Task.Resources.CPULimit,
+			MemoryRequest: spec.Task.Resources.MemoryRequest,
+			MemoryLimit:   spec.Task.Resources.MemoryLimit,
+		}
+	}
 	for name, dep := range spec.Dependencies {
 		conf.Dependencies = append(conf.Dependencies, &pb.JobDependency{
-			Name: name,
-			Type: dep.Type.String(),
+			Name:        name,
+			Type:        dep.Type.String(),
+			ProjectName: dep.ProjectName,
 		})
 	}
 
@@ -211,10 +251,31 @@ func (adapt *Adapter) ToJobProto(spec models.JobSpec) (*pb.JobSpecification, err
 	return conf, nil
 }
 
+// JobSpecChecksum returns the sha256 checksum of the job spec serialized as
+// proto, dependencies are sorted and maps are serialized deterministically so
+// the same spec always produces the same checksum
+func (adapt *Adapter) JobSpecChecksum(spec models.JobSpec) (string, error) {
+	jobProto, err := adapt.ToJobProto(spec)
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(jobProto.Dependencies, func(i, j int) bool {
+		return jobProto.Dependencies[i].Name < jobProto.Dependencies[j].Name
+	})
+	serialized, err := protov2.MarshalOptions{Deterministic: true}.Marshal(jobProto)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(serialized)), nil
+}
+
 func (adapt *Adapter) ToProjectProto(spec models.ProjectSpec) *pb.ProjectSpecification {
 	return &pb.ProjectSpecification{
-		Name:   spec.Name,
-		Config: spec.Config,
+		Name:            spec.Name,
+		Config:          spec.Config,
+		FeatureFlags:    spec.FeatureFlags,
+		Webhooks:        toWebhooksProto(spec.Webhooks),
+		BlackoutWindows: toBlackoutWindowsProto(spec.BlackoutWindows),
 	}
 }
 
@@ -224,8 +285,11 @@ func (adapt *Adapter) FromProjectProto(conf *pb.ProjectSpecification) models.Pro
 		pConf[strings.ToUpper(key)] = val
 	}
 	return models.ProjectSpec{
-		Name:   conf.GetName(),
-		Config: pConf,
+		Name:            conf.GetName(),
+		Config:          pConf,
+		FeatureFlags:    conf.GetFeatureFlags(),
+		Webhooks:        fromWebhooksProto(conf.GetWebhooks()),
+		BlackoutWindows: fromBlackoutWindowsProto(conf.GetBlackoutWindows()),
 	}
 }
 
@@ -238,9 +302,12 @@ func (adapt *Adapter) ToProjectProtoWithSecrets(spec models.ProjectSpec) *pb.Pro
 		})
 	}
 	return &pb.ProjectSpecification{
-		Name:    spec.Name,
-		Config:  spec.Config,
-		Secrets: secrets,
+		Name:            spec.Name,
+		Config:          spec.Config,
+		Secrets:         secrets,
+		FeatureFlags:    spec.FeatureFlags,
+		Webhooks:        toWebhooksProto(spec.Webhooks),
+		BlackoutWindows: toBlackoutWindowsProto(spec.BlackoutWindows),
 	}
 }
 
@@ -264,9 +331,12 @@ func (adapt *Adapter) FromProjectProtoWithSecrets(conf *pb.ProjectSpecification)
 		}
 	}
 	return models.ProjectSpec{
-		Name:   conf.GetName(),
-		Config: pConf,
-		Secret: pSec,
+		Name:            conf.GetName(),
+		Config:          pConf,
+		Secret:          pSec,
+		FeatureFlags:    conf.GetFeatureFlags(),
+		Webhooks:        fromWebhooksProto(conf.GetWebhooks()),
+		BlackoutWindows: fromBlackoutWindowsProto(conf.GetBlackoutWindows()),
 	}
 }
 
@@ -283,12 +353,63 @@ func (adapt *Adapter) ToProjectProtoWithSecret(spec models.ProjectSpec, pluginTy
 		})
 	}
 	return &pb.ProjectSpecification{
-		Name:    spec.Name,
-		Config:  spec.Config,
-		Secrets: secrets,
+		Name:            spec.Name,
+		Config:          spec.Config,
+		Secrets:         secrets,
+		FeatureFlags:    spec.FeatureFlags,
+		Webhooks:        toWebhooksProto(spec.Webhooks),
+		BlackoutWindows: toBlackoutWindowsProto(spec.BlackoutWindows),
 	}
 }
 
+func toWebhooksProto(webhooks []models.WebhookConfig) []*pb.ProjectSpecification_ProjectWebhook {
+	var protos []*pb.ProjectSpecification_ProjectWebhook
+	for _, webhook := range webhooks {
+		protos = append(protos, &pb.ProjectSpecification_ProjectWebhook{
+			Url:        webhook.URL,
+			SecretName: webhook.SecretName,
+			Events:     webhook.Events,
+		})
+	}
+	return protos
+}
+
+func fromWebhooksProto(protos []*pb.ProjectSpecification_ProjectWebhook) []models.WebhookConfig {
+	var webhooks []models.WebhookConfig
+	for _, webhook := range protos {
+		webhooks = append(webhooks, models.WebhookConfig{
+			URL:        webhook.GetUrl(),
+			SecretName: webhook.GetSecretName(),
+			Events:     webhook.GetEvents(),
+		})
+	}
+	return webhooks
+}
+
+func toBlackoutWindowsProto(windows []models.BlackoutWindow) []*pb.ProjectSpecification_ProjectBlackoutWindow {
+	var protos []*pb.ProjectSpecification_ProjectBlackoutWindow
+	for _, window := range windows {
+		protos = append(protos, &pb.ProjectSpecification_ProjectBlackoutWindow{
+			Start:      window.Start,
+			Duration:   ptypes.DurationProto(window.Duration),
+			JobPattern: window.JobPattern,
+		})
+	}
+	return protos
+}
+
+func fromBlackoutWindowsProto(protos []*pb.ProjectSpecification_ProjectBlackoutWindow) []models.BlackoutWindow {
+	var windows []models.BlackoutWindow
+	for _, window := range protos {
+		windows = append(windows, models.BlackoutWindow{
+			Start:      window.GetStart(),
+			Duration:   window.GetDuration().AsDuration(),
+			JobPattern: window.GetJobPattern(),
+		})
+	}
+	return windows
+}
+
 func (adapt *Adapter) ToNamespaceProto(spec models.NamespaceSpec) *pb.NamespaceSpecification {
 	return &pb.NamespaceSpecification{
 		Name:   spec.Name,
@@ -370,9 +491,20 @@ func (adapt *Adapter) FromHookProto(hooksProto []*pb.JobSpecHook) ([]models.JobS
 			})
 		}
 
+		var retry models.JobSpecBehaviorRetry
+		if hook.Retry != nil {
+			retry.Count = int(hook.Retry.Count)
+			retry.ExponentialBackoff = hook.Retry.ExponentialBackoff
+			if hook.Retry.Delay != nil && hook.Retry.Delay.IsValid() {
+				retry.Delay = hook.Retry.Delay.AsDuration()
+			}
+		}
+
 		hooks = append(hooks, models.JobSpecHook{
-			Config: configs,
-			Unit:   hookUnit,
+			Config:   configs,
+			Unit:     hookUnit,
+			RunAfter: hook.RunAfter,
+			Retry:    retry,
 		})
 	}
 	return hooks, nil
@@ -388,10 +520,20 @@ func (adapt *Adapter) ToHookProto(hooks []models.JobSpecHook) (protoHooks []*pb.
 			})
 		}
 
-		protoHooks = append(protoHooks, &pb.JobSpecHook{
-			Name:   hook.Unit.Info().Name,
-			Config: hookConfigs,
-		})
+		protoHook := &pb.JobSpecHook{
+			Name:     hook.Unit.Info().Name,
+			Config:   hookConfigs,
+			RunAfter: hook.RunAfter,
+		}
+		// left out when not set so checksums of specs without it don't change
+		if hook.Retry != (models.JobSpecBehaviorRetry{}) {
+			protoHook.Retry = &pb.JobSpecification_Behavior_Retry{
+				Count:              int32(hook.Retry.Count),
+				Delay:              ptypes.DurationProto(hook.Retry.Delay),
+				ExponentialBackoff: hook.Retry.ExponentialBackoff,
+			}
+		}
+		protoHooks = append(protoHooks, protoHook)
 	}
 	return
 }
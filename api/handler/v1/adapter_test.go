@@ -47,6 +47,7 @@ func TestAdapter(t *testing.T) {
 			Schedule: models.JobSpecSchedule{
 				StartDate: time.Date(2021, 10, 6, 0, 0, 0, 0, time.UTC),
 				Interval:  "@daily",
+				Timezone:  "Asia/Jakarta",
 			},
 			Behavior: models.JobSpecBehavior{
 				DependsOnPast: false,
@@ -79,6 +80,10 @@ func TestAdapter(t *testing.T) {
 					Offset:     time.Hour,
 					TruncateTo: "h",
 				},
+				Resources: models.JobSpecTaskResources{
+					CPURequest:  "250m",
+					MemoryLimit: "512Mi",
+				},
 			},
 			Assets: *models.JobAssets{}.New(
 				[]models.JobSpecAsset{
@@ -108,6 +113,42 @@ func TestAdapter(t *testing.T) {
 		assert.Equal(t, jobSpec, original)
 		assert.Nil(t, err)
 	})
+	t.Run("should compute same checksum for identical job specs", func(t *testing.T) {
+		execUnit := new(mock.BasePlugin)
+		execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name: "sample-task",
+		}, nil)
+		adapter := v1.NewAdapter(nil, nil)
+
+		newJobSpec := func(owner string) models.JobSpec {
+			return models.JobSpec{
+				Name:   "test-job",
+				Owner:  owner,
+				Labels: map[string]string{"team": "data", "env": "prod", "tier": "1"},
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{Base: execUnit},
+				},
+				Dependencies: map[string]models.JobSpecDependency{
+					"upstream-a": {Type: models.JobSpecDependencyTypeIntra},
+					"upstream-b": {Type: models.JobSpecDependencyTypeIntra},
+					"upstream-c": {Type: models.JobSpecDependencyTypeIntra},
+				},
+			}
+		}
+
+		checksum, err := adapter.JobSpecChecksum(newJobSpec("mee@mee"))
+		assert.Nil(t, err)
+		assert.Len(t, checksum, 64)
+		for i := 0; i < 10; i++ {
+			same, err := adapter.JobSpecChecksum(newJobSpec("mee@mee"))
+			assert.Nil(t, err)
+			assert.Equal(t, checksum, same)
+		}
+
+		changed, err := adapter.JobSpecChecksum(newJobSpec("new-owner@mee"))
+		assert.Nil(t, err)
+		assert.NotEqual(t, checksum, changed)
+	})
 }
 
 func TestAdapter_FromProjectProtoWithSecrets(t *testing.T) {
@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"strings"
+
+	"github.com/odpf/optimus/models"
+)
+
+const runtimeServicePrefix = "/odpf.optimus.RuntimeService/"
+
+// MethodAuthorizationMap is the minimum role callers need for each method of
+// the runtime service. Reads require a viewer, changes to a project require
+// an editor and operations across projects require an admin
+var MethodAuthorizationMap = map[string]models.Role{
+	// clients negotiate versions before they are authenticated
+	runtimeServicePrefix + "Version":             models.RoleNone,
+	runtimeServicePrefix + "NegotiateAPIVersion": models.RoleNone,
+
+	// reads
+	runtimeServicePrefix + "ReadJobSpecification":       models.RoleViewer,
+	runtimeServicePrefix + "ListJobSpecification":       models.RoleViewer,
+	runtimeServicePrefix + "DumpJobSpecification":       models.RoleViewer,
+	runtimeServicePrefix + "CheckJobSpecification":      models.RoleViewer,
+	runtimeServicePrefix + "CheckJobSpecifications":     models.RoleViewer,
+	runtimeServicePrefix + "ListProjects":               models.RoleViewer,
+	runtimeServicePrefix + "ListProjectNamespaces":      models.RoleViewer,
+	runtimeServicePrefix + "JobStatus":                  models.RoleViewer,
+	runtimeServicePrefix + "GetWindow":                  models.RoleViewer,
+	runtimeServicePrefix + "ListResourceSpecification":  models.RoleViewer,
+	runtimeServicePrefix + "ReadResource":               models.RoleViewer,
+	runtimeServicePrefix + "ReplayDryRun":               models.RoleViewer,
+	runtimeServicePrefix + "PreviewSchedulePropagation": models.RoleViewer,
+	runtimeServicePrefix + "ListActiveJobInstances":     models.RoleViewer,
+	runtimeServicePrefix + "ListRunningJobs":            models.RoleViewer,
+	runtimeServicePrefix + "ListDependencyPins":         models.RoleViewer,
+	runtimeServicePrefix + "GetProjectDiagnostics":      models.RoleViewer,
+	runtimeServicePrefix + "GetInstanceStatusSummary":   models.RoleViewer,
+	runtimeServicePrefix + "CheckJobSchemaDrift":        models.RoleViewer,
+	runtimeServicePrefix + "GetReplayStatus":            models.RoleViewer,
+	runtimeServicePrefix + "ListSecrets":                models.RoleViewer,
+	runtimeServicePrefix + "DiffJobSpec":                models.RoleViewer,
+	runtimeServicePrefix + "ListProjectRoles":           models.RoleViewer,
+	runtimeServicePrefix + "GetProjectVariables":        models.RoleViewer,
+	runtimeServicePrefix + "EstimateJobCost":            models.RoleViewer,
+	runtimeServicePrefix + "ListSLABreaches":            models.RoleViewer,
+	runtimeServicePrefix + "ExplainJobDependencies":     models.RoleViewer,
+	runtimeServicePrefix + "ValidateSchedule":           models.RoleViewer,
+	runtimeServicePrefix + "ListSuppressedJobRuns":      models.RoleViewer,
+	runtimeServicePrefix + "GetDependencyGraph":         models.RoleViewer,
+
+	// changes to a project
+	runtimeServicePrefix + "DeployJobSpecification":       models.RoleEditor,
+	runtimeServicePrefix + "CreateJobSpecification":       models.RoleEditor,
+	runtimeServicePrefix + "DeleteJobSpecification":       models.RoleEditor,
+	runtimeServicePrefix + "RegisterProject":              models.RoleEditor,
+	runtimeServicePrefix + "RegisterProjectNamespace":     models.RoleEditor,
+	runtimeServicePrefix + "RegisterSecret":               models.RoleEditor,
+	runtimeServicePrefix + "RevealProjectSecret":          models.RoleEditor,
+	runtimeServicePrefix + "RegisterInstance":             models.RoleEditor,
+	runtimeServicePrefix + "RegisterJobEvent":             models.RoleEditor,
+	runtimeServicePrefix + "DeployResourceSpecification":  models.RoleEditor,
+	runtimeServicePrefix + "CreateResource":               models.RoleEditor,
+	runtimeServicePrefix + "UpdateResource":               models.RoleEditor,
+	runtimeServicePrefix + "Replay":                       models.RoleEditor,
+	runtimeServicePrefix + "TriggerJobRun":                models.RoleEditor,
+	runtimeServicePrefix + "PauseJob":                     models.RoleEditor,
+	runtimeServicePrefix + "ResumeJob":                    models.RoleEditor,
+	runtimeServicePrefix + "PauseProject":                 models.RoleEditor,
+	runtimeServicePrefix + "ResumeProject":                models.RoleEditor,
+	runtimeServicePrefix + "SmartBackfillJob":             models.RoleEditor,
+	runtimeServicePrefix + "PinDependencyVersion":         models.RoleEditor,
+	runtimeServicePrefix + "UnpinDependency":              models.RoleEditor,
+	runtimeServicePrefix + "TagRelease":                   models.RoleEditor,
+	runtimeServicePrefix + "RollbackToRelease":            models.RoleEditor,
+	runtimeServicePrefix + "UndeleteJobSpecification":     models.RoleEditor,
+	runtimeServicePrefix + "CloneJobSpecification":        models.RoleEditor,
+	runtimeServicePrefix + "CloneProject":                 models.RoleEditor,
+	runtimeServicePrefix + "BatchCreateJobSpecifications": models.RoleEditor,
+	runtimeServicePrefix + "RegisterProjectVariables":     models.RoleEditor,
+
+	// operations across projects and managing roles of a project
+	runtimeServicePrefix + "ListJobsByOwner":               models.RoleAdmin,
+	runtimeServicePrefix + "ReconcileJobInstances":         models.RoleAdmin,
+	runtimeServicePrefix + "PurgeDeletedJobSpecifications": models.RoleAdmin,
+	runtimeServicePrefix + "RotateSecrets":                 models.RoleAdmin,
+	runtimeServicePrefix + "ListSchedulerJobs":             models.RoleAdmin,
+	runtimeServicePrefix + "GrantProjectRole":              models.RoleAdmin,
+	runtimeServicePrefix + "RevokeProjectRole":             models.RoleAdmin,
+	runtimeServicePrefix + "GenerateAPIKey":                models.RoleAdmin,
+	runtimeServicePrefix + "ListAuditLog":                  models.RoleAdmin,
+	runtimeServicePrefix + "SetProjectQuota":               models.RoleAdmin,
+}
+
+// readMethodPrefixes name the methods beyond the reach of viewers that don't
+// change state
+var readMethodPrefixes = []string{"Get", "List"}
+
+// IsMutatingMethod reports if calls of the method change state, they are
+// recorded in the audit log. Methods viewers can call are reads, the others
+// change state unless their name starts with a read prefix
+func IsMutatingMethod(fullMethod string) bool {
+	if role, ok := MethodAuthorizationMap[fullMethod]; ok && role <= models.RoleViewer {
+		return false
+	}
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	for _, prefix := range readMethodPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package v1_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/api/middleware"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMethodAuthorizationMap(t *testing.T) {
+	withRole := func(role models.Role) context.Context {
+		return middleware.ContextWithClaims(context.Background(), jwt.MapClaims{
+			"sub":   "user@example.io",
+			"roles": []interface{}{role.String()},
+		})
+	}
+	authorizer := middleware.NewMethodAuthorizer(v1.MethodAuthorizationMap, models.RoleNone)
+
+	t.Run("should list every method of the runtime service", func(t *testing.T) {
+		for _, method := range pb.RuntimeService_ServiceDesc.Methods {
+			_, ok := v1.MethodAuthorizationMap["/"+pb.RuntimeService_ServiceDesc.ServiceName+"/"+method.MethodName]
+			assert.True(t, ok, method.MethodName)
+		}
+		for _, stream := range pb.RuntimeService_ServiceDesc.Streams {
+			_, ok := v1.MethodAuthorizationMap["/"+pb.RuntimeService_ServiceDesc.ServiceName+"/"+stream.StreamName]
+			assert.True(t, ok, stream.StreamName)
+		}
+	})
+	t.Run("should require the minimum role of each method", func(t *testing.T) {
+		cases := map[string]models.Role{
+			"Version":                       models.RoleNone,
+			"NegotiateAPIVersion":           models.RoleNone,
+			"ListJobSpecification":          models.RoleViewer,
+			"ReadJobSpecification":          models.RoleViewer,
+			"ListProjects":                  models.RoleViewer,
+			"ListSecrets":                   models.RoleViewer,
+			"GetReplayStatus":               models.RoleViewer,
+			"ListSLABreaches":               models.RoleViewer,
+			"ExplainJobDependencies":        models.RoleViewer,
+			"ValidateSchedule":              models.RoleViewer,
+			"ListSuppressedJobRuns":         models.RoleViewer,
+			"GetDependencyGraph":            models.RoleViewer,
+			"DeployJobSpecification":        models.RoleEditor,
+			"DeleteJobSpecification":        models.RoleEditor,
+			"RegisterSecret":                models.RoleEditor,
+			"RevealProjectSecret":           models.RoleEditor,
+			"Replay":                        models.RoleEditor,
+			"TriggerJobRun":                 models.RoleEditor,
+			"PauseJob":                      models.RoleEditor,
+			"ResumeJob":                     models.RoleEditor,
+			"PauseProject":                  models.RoleEditor,
+			"ResumeProject":                 models.RoleEditor,
+			"ListJobsByOwner":               models.RoleAdmin,
+			"ReconcileJobInstances":         models.RoleAdmin,
+			"PurgeDeletedJobSpecifications": models.RoleAdmin,
+			"RotateSecrets":                 models.RoleAdmin,
+			"ListSchedulerJobs":             models.RoleAdmin,
+			"ListProjectRoles":              models.RoleViewer,
+			"GrantProjectRole":              models.RoleAdmin,
+			"RevokeProjectRole":             models.RoleAdmin,
+			"GenerateAPIKey":                models.RoleAdmin,
+			"ListAuditLog":                  models.RoleAdmin,
+			"SetProjectQuota":               models.RoleAdmin,
+		}
+		for method, required := range cases {
+			fullMethod := "/odpf.optimus.RuntimeService/" + method
+			assert.Equal(t, required, authorizer.RequiredRole(fullMethod), method)
+			assert.Nil(t, authorizer.Authorize(withRole(required), fullMethod, nil), method)
+			if required > models.RoleNone {
+				err := authorizer.Authorize(withRole(required-1), fullMethod, nil)
+				assert.Equal(t, codes.PermissionDenied, status.Code(err), method)
+			}
+		}
+	})
+}
+
+func TestIsMutatingMethod(t *testing.T) {
+	cases := map[string]bool{
+		"Version":                      false,
+		"ListJobSpecification":         false,
+		"CheckJobSpecifications":       false,
+		"ReplayDryRun":                 false,
+		"ListJobsByOwner":              false,
+		"ListAuditLog":                 false,
+		"DeployJobSpecification":       true,
+		"RegisterProject":              true,
+		"RevealProjectSecret":          true,
+		"ReconcileJobInstances":        true,
+		"GrantProjectRole":             true,
+		"UnknownMethodAddedWithoutMap": true,
+	}
+	for method, mutating := range cases {
+		assert.Equal(t, mutating, v1.IsMutatingMethod("/odpf.optimus.RuntimeService/"+method), method)
+	}
+}
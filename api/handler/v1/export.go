@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+)
+
+// ProjectJobSpecRepoFactory is used to manage job specs at project level
+type ProjectJobSpecRepoFactory interface {
+	New(proj models.ProjectSpec) store.ProjectJobSpecRepository
+}
+
+// JobSpecCSVHandler serves the job specifications of a project as csv, it is
+// registered on the grpc gateway as csv can't be represented as a proto response
+func JobSpecCSVHandler(projectRepoFactory ProjectRepoFactory, projectJobSpecRepoFactory ProjectJobSpecRepoFactory) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		projectName := pathParams["name"]
+		projSpec, err := projectRepoFactory.New().GetByName(projectName)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("%s: project %s not found", err.Error(), projectName), http.StatusNotFound)
+			return
+		}
+
+		export, err := projectJobSpecRepoFactory.New(projSpec).ExportCSV(r.Context())
+		if err != nil {
+			http.Error(w, fmt.Sprintf("%s: failed to export jobs of %s", err.Error(), projectName), http.StatusInternalServerError)
+			return
+		}
+		defer export.Close()
+
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", projSpec.Name+"-jobs.csv"))
+		if _, err := io.Copy(w, export); err != nil {
+			// headers are already sent, the export can only be cut short
+			logger.W(fmt.Sprintf("failed to stream jobs of %s: %s", projectName, err))
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package v1_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestJobSpecCSVHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		Name: "a-data-project",
+	}
+
+	t.Run("should stream job specs of the project as csv", func(t *testing.T) {
+		var export bytes.Buffer
+		exportWriter := csv.NewWriter(&export)
+		exportWriter.WriteAll([][]string{
+			store.JobSpecCSVColumns,
+			{"job-1", "bq2bq", "mee@mee", "0 2 * * *", "2h", `{"orchestrator": "optimus"}`, "1", "", "2021-08-01T00:00:00Z", "2021-08-01T00:00:00Z"},
+			{"job-2", "bq2bq", "mee@mee", "0 3 * * *", "", "{}", "0", "", "2021-08-01T00:00:00Z", "2021-08-01T00:00:00Z"},
+		})
+
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		defer projectRepository.AssertExpectations(t)
+
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+		defer projectRepoFactory.AssertExpectations(t)
+
+		projectJobSpecRepository := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepository.On("ExportCSV", mock2.Anything).Return(ioutil.NopCloser(&export), nil)
+		defer projectJobSpecRepository.AssertExpectations(t)
+
+		projectJobSpecRepoFactory := new(mock.ProjectJobSpecRepoFactory)
+		projectJobSpecRepoFactory.On("New", projectSpec).Return(projectJobSpecRepository)
+		defer projectJobSpecRepoFactory.AssertExpectations(t)
+
+		handler := v1.JobSpecCSVHandler(projectRepoFactory, projectJobSpecRepoFactory)
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/v1/projects/a-data-project/jobs.csv", nil)
+		handler(rec, req, map[string]string{"name": projectSpec.Name})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+		records, err := csv.NewReader(rec.Body).ReadAll()
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(records))
+		assert.Equal(t, store.JobSpecCSVColumns, records[0])
+	})
+	t.Run("should return not found when project is not registered", func(t *testing.T) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(models.ProjectSpec{}, errors.New("project not found"))
+		defer projectRepository.AssertExpectations(t)
+
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+		defer projectRepoFactory.AssertExpectations(t)
+
+		handler := v1.JobSpecCSVHandler(projectRepoFactory, nil)
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/v1/projects/a-data-project/jobs.csv", nil)
+		handler(rec, req, map[string]string{"name": projectSpec.Name})
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+)
+
+// ReadinessProbeTimeout bounds each probe of a readiness check
+const ReadinessProbeTimeout = 5 * time.Second
+
+// ReadinessProbe checks a subsystem the server depends on to serve calls,
+// e.g. the database or the storage of compiled jobs
+type ReadinessProbe struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type readinessCheck struct {
+	Name    string                   `json:"name"`
+	Status  models.HealthCheckStatus `json:"status"`
+	Message string                   `json:"message,omitempty"`
+}
+
+type readinessResponse struct {
+	Status models.HealthCheckStatus `json:"status"`
+	Checks []readinessCheck         `json:"checks"`
+}
+
+// LivenessHandler responds OK as long as the server is able to serve
+// http calls, it is used as kubernetes liveness probe
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "ok")
+	}
+}
+
+// ReadinessHandler runs all probes and responds with 503 if any of them
+// fails, the body lists the status of each subsystem so deployment tools
+// can hold traffic until the server is ready
+func ReadinessHandler(probes []ReadinessProbe) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp := readinessResponse{
+			Status: models.HealthCheckStatusOK,
+		}
+		for _, probe := range probes {
+			check := readinessCheck{
+				Name:   probe.Name,
+				Status: models.HealthCheckStatusOK,
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), ReadinessProbeTimeout)
+			if err := probe.Check(ctx); err != nil {
+				check.Status = models.HealthCheckStatusFailed
+				check.Message = err.Error()
+				resp.Status = models.HealthCheckStatusFailed
+			}
+			cancel()
+			resp.Checks = append(resp.Checks, check)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if resp.Status != models.HealthCheckStatusOK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			logger.W(fmt.Sprintf("failed to write readiness response: %s", err))
+		}
+	}
+}
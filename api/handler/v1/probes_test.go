@@ -0,0 +1,72 @@
+package v1_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeHandlers(t *testing.T) {
+	type readinessResponse struct {
+		Status string `json:"status"`
+		Checks []struct {
+			Name    string `json:"name"`
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"checks"`
+	}
+	passingProbe := v1.ReadinessProbe{
+		Name:  "database",
+		Check: func(ctx context.Context) error { return nil },
+	}
+
+	t.Run("LivenessHandler", func(t *testing.T) {
+		t.Run("should respond ok", func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			v1.LivenessHandler()(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+		})
+	})
+	t.Run("ReadinessHandler", func(t *testing.T) {
+		t.Run("should respond ok if all probes pass", func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			v1.ReadinessHandler([]v1.ReadinessProbe{passingProbe})(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.JSONEq(t, `{"status": "ok", "checks": [{"name": "database", "status": "ok"}]}`, rec.Body.String())
+		})
+		t.Run("should respond unavailable with the failing subsystem", func(t *testing.T) {
+			probes := []v1.ReadinessProbe{
+				passingProbe,
+				{
+					Name: "storage",
+					Check: func(ctx context.Context) error {
+						_, hasDeadline := ctx.Deadline()
+						assert.True(t, hasDeadline)
+						return errors.New("bucket not reachable")
+					},
+				},
+			}
+			rec := httptest.NewRecorder()
+			v1.ReadinessHandler(probes)(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+			var resp readinessResponse
+			assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, "failed", resp.Status)
+			assert.Equal(t, 2, len(resp.Checks))
+			assert.Equal(t, "ok", resp.Checks[0].Status)
+			assert.Equal(t, "storage", resp.Checks[1].Name)
+			assert.Equal(t, "failed", resp.Checks[1].Status)
+			assert.Equal(t, "bucket not reachable", resp.Checks[1].Message)
+		})
+	})
+}
@@ -2,11 +2,16 @@ package v1
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"google.golang.org/protobuf/types/known/structpb"
@@ -16,13 +21,18 @@ import (
 	"github.com/odpf/optimus/datastore"
 
 	"github.com/golang/protobuf/ptypes"
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-version"
+	"github.com/odpf/optimus/api/middleware"
 	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
 	"github.com/odpf/optimus/core/logger"
 	log "github.com/odpf/optimus/core/logger"
 	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/instance"
 	"github.com/odpf/optimus/job"
 	"github.com/odpf/optimus/models"
 	"github.com/odpf/optimus/store"
+	"github.com/odpf/optimus/utils"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
@@ -41,6 +51,19 @@ type SecretRepoFactory interface {
 	New(spec models.ProjectSpec) store.ProjectSecretRepository
 }
 
+type RoleBindingRepoFactory interface {
+	New(spec models.ProjectSpec) store.ProjectRoleBindingRepository
+}
+
+type APIKeyRepoFactory interface {
+	New(spec models.ProjectSpec) store.APIKeyRepository
+}
+
+// ProjectHealthService diagnoses the subsystems used by a project
+type ProjectHealthService interface {
+	GetProjectHealth(context.Context, models.ProjectSpec) (*models.ProjectHealth, error)
+}
+
 type JobEventService interface {
 	Register(context.Context, models.NamespaceSpec, models.JobSpec, models.JobEvent) error
 }
@@ -65,6 +88,31 @@ type ProtoAdapter interface {
 	ToReplayExecutionTreeNode(res *tree.TreeNode) (*pb.ReplayExecutionTreeNode, error)
 }
 
+const (
+	// APIMajorVersion is the latest major version of the runtime api served
+	APIMajorVersion = 1
+
+	// DefaultJobSpecPageSize is the number of jobs listed when a page
+	// size isn't requested, MaxJobSpecPageSize caps the requested size
+	DefaultJobSpecPageSize = 100
+	MaxJobSpecPageSize     = 1000
+
+	// DefaultAuditLogLimit is the number of audit log entries listed when a
+	// limit isn't requested, MaxAuditLogLimit caps the requested limit
+	DefaultAuditLogLimit = 100
+	MaxAuditLogLimit     = 1000
+
+	// DefaultSLABreachLimit is the number of sla breaches listed when a
+	// limit isn't requested, MaxSLABreachLimit caps the requested limit
+	DefaultSLABreachLimit = 100
+	MaxSLABreachLimit     = 1000
+)
+
+var (
+	// MinClientVersion is the oldest client version accepted by the server
+	MinClientVersion = "0.0.1"
+)
+
 type RuntimeServiceServer struct {
 	version              string
 	jobSvc               models.JobService
@@ -76,10 +124,41 @@ type RuntimeServiceServer struct {
 	secretRepoFactory    SecretRepoFactory
 	instSvc              models.InstanceService
 	scheduler            models.SchedulerUnit
+	projectHealthSvc     ProjectHealthService
 
 	progressObserver progress.Observer
 	Now              func() time.Time
 
+	// SecretRevealPassphrase has to be confirmed to reveal secrets, reveals
+	// are rejected if not set
+	SecretRevealPassphrase string
+	// AuditLog records security sensitive calls like secret reveals
+	AuditLog logrus.FieldLogger
+	// SecretRotator re-encrypts secrets with a new application key, secrets
+	// can't be rotated if not set
+	SecretRotator store.SecretRotator
+	// AppKey is the application key of the server with its previous key,
+	// secrets are only rotated from the previous key to it
+	AppKey models.ApplicationKey
+	// RoleBindingRepoFactory stores roles of subjects in projects, roles
+	// can't be managed if not set
+	RoleBindingRepoFactory RoleBindingRepoFactory
+	// APIKeyRepoFactory stores api keys of projects, keys can't be
+	// generated if not set
+	APIKeyRepoFactory APIKeyRepoFactory
+	// AuditLogRepo stores the calls changing state, the audit log can't be
+	// listed if not set
+	AuditLogRepo store.AuditLogRepository
+	// SLABreachRepo stores runs of jobs not started within their sla, breaches
+	// can't be listed if not set
+	SLABreachRepo store.SLABreachRepository
+	// JobRunTriggerRepo stores runs of jobs triggered on demand, triggers
+	// aren't recorded if not set
+	JobRunTriggerRepo store.JobRunTriggerRepository
+	// JobPauseRepo stores the jobs paused, the paused state isn't restored
+	// if the scheduler loses it when not set
+	JobPauseRepo store.JobPauseRepository
+
 	pb.UnimplementedRuntimeServiceServer
 }
 
@@ -91,6 +170,39 @@ func (sv *RuntimeServiceServer) Version(ctx context.Context, version *pb.Version
 	return response, nil
 }
 
+func (sv *RuntimeServiceServer) NegotiateAPIVersion(ctx context.Context, req *pb.NegotiateAPIVersionRequest) (*pb.NegotiateAPIVersionResponse, error) {
+	minClientVersion, err := version.NewVersion(MinClientVersion)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: invalid minimum client version %s", err.Error(), MinClientVersion)
+	}
+	response := &pb.NegotiateAPIVersionResponse{
+		ServerVersion:    sv.version,
+		ApiMajorVersion:  APIMajorVersion,
+		MinClientVersion: MinClientVersion,
+	}
+
+	// development builds don't carry a semantic version and can't be verified
+	clientVersion, err := version.NewVersion(req.GetClientVersion())
+	if err != nil {
+		return response, nil
+	}
+	if clientVersion.LessThan(minClientVersion) {
+		return nil, status.Errorf(codes.FailedPrecondition, "client version %s is not supported by server %s, upgrade the client to %s or later",
+			req.GetClientVersion(), sv.version, MinClientVersion)
+	}
+	response.Supported = true
+	return response, nil
+}
+
+// jobNamespaceName is the namespace of a job request, jobs of requests
+// without one belong to the default namespace of the project
+func jobNamespaceName(name string) string {
+	if name == "" {
+		return models.DefaultNamespaceName
+	}
+	return name
+}
+
 func (sv *RuntimeServiceServer) DeployJobSpecification(req *pb.DeployJobSpecificationRequest, respStream pb.RuntimeService_DeployJobSpecificationServer) error {
 	startTime := time.Now()
 
@@ -101,9 +213,9 @@ func (sv *RuntimeServiceServer) DeployJobSpecification(req *pb.DeployJobSpecific
 	}
 
 	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
-	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	namespaceSpec, err := namespaceRepo.GetByName(jobNamespaceName(req.GetNamespace()))
 	if err != nil {
-		return status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
+		return status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), jobNamespaceName(req.GetNamespace()))
 	}
 
 	var jobsToKeep []models.JobSpec
@@ -112,13 +224,18 @@ func (sv *RuntimeServiceServer) DeployJobSpecification(req *pb.DeployJobSpecific
 		if err != nil {
 			return status.Errorf(codes.Internal, "%s: cannot adapt job %s", err.Error(), reqJob.GetName())
 		}
-
-		err = sv.jobSvc.Create(namespaceSpec, adaptJob)
-		if err != nil {
-			return status.Errorf(codes.Internal, "%s: failed to save %s", err.Error(), adaptJob.Name)
-		}
 		jobsToKeep = append(jobsToKeep, adaptJob)
 	}
+	// templates are saved before the jobs extending them
+	if err := sv.jobSvc.SaveAll(namespaceSpec, job.OrderByInheritance(jobsToKeep)); err != nil {
+		if errors.Is(err, models.ErrQuotaExceeded) {
+			return status.Errorf(codes.ResourceExhausted, "%s: failed to save jobs", err.Error())
+		}
+		if errors.Is(err, models.ErrInvalidTimezone) {
+			return status.Errorf(codes.InvalidArgument, "%s: failed to save jobs", err.Error())
+		}
+		return status.Errorf(codes.Internal, "%s: failed to save jobs", err.Error())
+	}
 
 	observers := new(progress.ObserverChain)
 	observers.Join(sv.progressObserver)
@@ -132,7 +249,16 @@ func (sv *RuntimeServiceServer) DeployJobSpecification(req *pb.DeployJobSpecific
 		return status.Errorf(codes.Internal, "%s: failed to delete jobs", err.Error())
 	}
 
-	if err := sv.jobSvc.Sync(respStream.Context(), namespaceSpec, observers); err != nil {
+	syncCtx := respStream.Context()
+	if git := req.GetGit(); git != nil {
+		syncCtx = job.WithGitContext(syncCtx, job.GitContext{
+			CommitSHA:  git.GetCommitSha(),
+			Branch:     git.GetBranch(),
+			Author:     git.GetAuthor(),
+			CommitTime: git.GetCommitTime(),
+		})
+	}
+	if err := sv.jobSvc.Sync(syncCtx, namespaceSpec, observers); err != nil {
 		return status.Errorf(codes.Internal, "%s\nfailed to sync jobs", err.Error())
 	}
 
@@ -148,29 +274,89 @@ func (sv *RuntimeServiceServer) ListJobSpecification(ctx context.Context, req *p
 	}
 
 	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
-	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	namespaceSpec, err := namespaceRepo.GetByName(jobNamespaceName(req.GetNamespace()))
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
+		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), jobNamespaceName(req.GetNamespace()))
+	}
+
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = DefaultJobSpecPageSize
+	} else if pageSize > MaxJobSpecPageSize {
+		pageSize = MaxJobSpecPageSize
+	}
+	afterName, err := decodeJobSpecPageToken(req.GetPageToken())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: invalid page token", err.Error())
+	}
+	labels, err := parseLabelFilter(req.GetLabels())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	jobSpecs, err := sv.jobSvc.GetAll(namespaceSpec)
+	// one more job than the page size is read to tell if there is a next page
+	jobSpecs, err := sv.jobSvc.GetPage(namespaceSpec, afterName, pageSize+1, labels)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to retrieve jobs for project %s", err.Error(), req.GetProjectName())
 	}
+	var nextPageToken string
+	if len(jobSpecs) > pageSize {
+		jobSpecs = jobSpecs[:pageSize]
+		nextPageToken = encodeJobSpecPageToken(jobSpecs[pageSize-1].Name)
+	}
 
 	jobProtos := []*pb.JobSpecification{}
+	var jobNames []string
 	for _, jobSpec := range jobSpecs {
 		jobProto, err := sv.adapter.ToJobProto(jobSpec)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "%s: failed to parse job spec %s", err.Error(), jobSpec.Name)
 		}
 		jobProtos = append(jobProtos, jobProto)
+		jobNames = append(jobNames, jobSpec.Name)
+	}
+	// checksums of the page are read at once
+	specChecksums, err := sv.jobSvc.GetSpecChecksums(jobNames, namespaceSpec)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to retrieve checksums of job specs", err.Error())
 	}
 	return &pb.ListJobSpecificationResponse{
-		Jobs: jobProtos,
+		Jobs:          jobProtos,
+		SpecChecksums: specChecksums,
+		NextPageToken: nextPageToken,
 	}, nil
 }
 
+// encodeJobSpecPageToken keeps the name of the last listed job in the
+// token, the next page continues with the jobs named after it
+func encodeJobSpecPageToken(lastName string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(lastName))
+}
+
+func decodeJobSpecPageToken(token string) (string, error) {
+	lastName, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", err
+	}
+	return string(lastName), nil
+}
+
+// parseLabelFilter reads key=value pairs of a label filter into a map
+func parseLabelFilter(filter []string) (map[string]string, error) {
+	if len(filter) == 0 {
+		return nil, nil
+	}
+	labels := map[string]string{}
+	for _, pair := range filter {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("label filter %q should be formatted as key=value", pair)
+		}
+		labels[parts[0]] = parts[1]
+	}
+	return labels, nil
+}
+
 func (sv *RuntimeServiceServer) DumpJobSpecification(ctx context.Context, req *pb.DumpJobSpecificationRequest) (*pb.DumpJobSpecificationResponse, error) {
 	projectRepo := sv.projectRepoFactory.New()
 	projSpec, err := projectRepo.GetByName(req.GetProjectName())
@@ -179,9 +365,9 @@ func (sv *RuntimeServiceServer) DumpJobSpecification(ctx context.Context, req *p
 	}
 
 	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
-	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	namespaceSpec, err := namespaceRepo.GetByName(jobNamespaceName(req.GetNamespace()))
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
+		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), jobNamespaceName(req.GetNamespace()))
 	}
 
 	reqJobSpec, err := sv.jobSvc.GetByName(req.GetJobName(), namespaceSpec)
@@ -205,9 +391,9 @@ func (sv *RuntimeServiceServer) CheckJobSpecification(ctx context.Context, req *
 	}
 
 	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
-	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	namespaceSpec, err := namespaceRepo.GetByName(jobNamespaceName(req.GetNamespace()))
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
+		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), jobNamespaceName(req.GetNamespace()))
 	}
 
 	j, err := sv.adapter.FromJobProto(req.GetJob())
@@ -230,9 +416,9 @@ func (sv *RuntimeServiceServer) CheckJobSpecifications(req *pb.CheckJobSpecifica
 	}
 
 	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
-	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	namespaceSpec, err := namespaceRepo.GetByName(jobNamespaceName(req.GetNamespace()))
 	if err != nil {
-		return status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
+		return status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), jobNamespaceName(req.GetNamespace()))
 	}
 
 	observers := new(progress.ObserverChain)
@@ -254,36 +440,119 @@ func (sv *RuntimeServiceServer) CheckJobSpecifications(req *pb.CheckJobSpecifica
 	if err = sv.jobSvc.Check(namespaceSpec, reqJobs, observers); err != nil {
 		return status.Errorf(codes.Internal, "failed to compile jobs\n%s", err.Error())
 	}
+
+	// incompatible changes don't fail the check, dependents may be updated along
+	warnings, err := sv.jobSvc.ValidateDependencyVersionCompatibility(respStream.Context(), namespaceSpec, reqJobs)
+	if err != nil {
+		logger.W(fmt.Sprintf("failed to validate compatibility with dependent jobs: %s", err))
+		return nil
+	}
+	for _, warning := range warnings {
+		if err := respStream.Send(&pb.CheckJobSpecificationsResponse{
+			Success: true,
+			Ack:     false,
+			JobName: warning.Job,
+			Message: compatibilityWarning(warning),
+		}); err != nil {
+			return status.Errorf(codes.Internal, "failed to send compatibility warning\n%s", err.Error())
+		}
+	}
 	return nil
 }
 
+// compatibilityWarning describes the change of a job affecting a dependent
+func compatibilityWarning(warning models.CompatibilityWarning) string {
+	if len(warning.Dataset.Columns) == 0 {
+		return fmt.Sprintf("warning: %s may break dependent job %s", warning.Reason, warning.Dependent)
+	}
+	return fmt.Sprintf("warning: %s may break dependent job %s, %s of %s", warning.Reason, warning.Dependent,
+		strings.Join(warning.Dataset.Columns, ", "), warning.Dataset.Destination)
+}
+
 func (sv *RuntimeServiceServer) RegisterProject(ctx context.Context, req *pb.RegisterProjectRequest) (*pb.RegisterProjectResponse, error) {
 	projectRepo := sv.projectRepoFactory.New()
 	projectSpec := sv.adapter.FromProjectProto(req.GetProject())
+	for _, webhook := range projectSpec.Webhooks {
+		if err := webhook.Validate(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: project %s", err.Error(), projectSpec.Name)
+		}
+	}
+	for _, window := range projectSpec.BlackoutWindows {
+		if err := window.Validate(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: project %s", err.Error(), projectSpec.Name)
+		}
+	}
+
+	// blackout windows are compiled in the jobs, jobs of registered projects
+	// are deployed again when their windows change
+	previousSpec, err := projectRepo.GetByName(projectSpec.Name)
+	isNewProject := errors.Is(err, store.ErrResourceNotFound)
+	redeploy := err == nil && blackoutWindowsChanged(previousSpec.BlackoutWindows, projectSpec.BlackoutWindows)
 
 	if err := projectRepo.Save(projectSpec); err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to save project %s", err.Error(), req.GetProject().GetName())
 	}
+	response := &pb.RegisterProjectResponse{
+		Success: true,
+		Message: "saved successfully",
+	}
+	if !isNewProject && !redeploy && req.GetNamespace() == nil {
+		return response, nil
+	}
 
-	if req.GetNamespace() != nil {
-		savedProjectSpec, err := projectRepo.GetByName(projectSpec.Name)
+	savedProjectSpec, err := projectRepo.GetByName(projectSpec.Name)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: failed to find project %s",
+			err.Error(), req.GetProject().GetName())
+	}
+	namespaceRepo := sv.namespaceRepoFactory.New(savedProjectSpec)
+
+	// jobs of requests without a namespace belong to the default namespace
+	if isNewProject && req.GetNamespace().GetName() != models.DefaultNamespaceName {
+		if err := namespaceRepo.Save(models.NamespaceSpec{
+			Name:   models.DefaultNamespaceName,
+			Config: map[string]string{},
+		}); err != nil {
+			return nil, status.Errorf(codes.Internal, "%s: failed to save default namespace of project %s",
+				err.Error(), req.GetProject().GetName())
+		}
+	}
+
+	if redeploy {
+		namespaceSpecs, err := namespaceRepo.GetAll()
 		if err != nil {
-			return nil, status.Errorf(codes.NotFound, "%s: failed to find project %s",
+			return nil, status.Errorf(codes.Internal, "%s: failed to list namespaces of project %s",
 				err.Error(), req.GetProject().GetName())
 		}
+		for _, namespaceSpec := range namespaceSpecs {
+			if err := sv.jobSvc.Sync(ctx, namespaceSpec, sv.progressObserver); err != nil {
+				return nil, status.Errorf(codes.Internal, "%s\nfailed to deploy jobs of namespace %s with the changed blackout windows",
+					err.Error(), namespaceSpec.Name)
+			}
+		}
+	}
 
-		namespaceRepo := sv.namespaceRepoFactory.New(savedProjectSpec)
+	if req.GetNamespace() != nil {
 		namespaceSpec := sv.adapter.FromNamespaceProto(req.GetNamespace())
 		if err = namespaceRepo.Save(namespaceSpec); err != nil {
 			return nil, status.Errorf(codes.Internal, "%s: failed to save project %s with namespace %s",
 				err.Error(), req.GetProject().GetName(), req.GetNamespace().GetName())
 		}
 	}
+	return response, nil
+}
 
-	return &pb.RegisterProjectResponse{
-		Success: true,
-		Message: "saved successfully",
-	}, nil
+// blackoutWindowsChanged is true if windows were added, removed or changed
+func blackoutWindowsChanged(previous, current []models.BlackoutWindow) bool {
+	if len(previous) != len(current) {
+		return true
+	}
+	for idx := range previous {
+		if previous[idx] != current[idx] {
+			return true
+		}
+	}
+	return false
 }
 
 func (sv *RuntimeServiceServer) RegisterProjectNamespace(ctx context.Context, req *pb.RegisterProjectNamespaceRequest) (*pb.RegisterProjectNamespaceResponse, error) {
@@ -313,9 +582,9 @@ func (sv *RuntimeServiceServer) CreateJobSpecification(ctx context.Context, req
 	}
 
 	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
-	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	namespaceSpec, err := namespaceRepo.GetByName(jobNamespaceName(req.GetNamespace()))
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found. Is it registered?", err.Error(), req.GetNamespace())
+		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found. Is it registered?", err.Error(), jobNamespaceName(req.GetNamespace()))
 	}
 
 	jobSpec, err := sv.adapter.FromJobProto(req.GetSpec())
@@ -330,6 +599,12 @@ func (sv *RuntimeServiceServer) CreateJobSpecification(ctx context.Context, req
 
 	err = sv.jobSvc.Create(namespaceSpec, jobSpec)
 	if err != nil {
+		if errors.Is(err, models.ErrQuotaExceeded) {
+			return nil, status.Errorf(codes.ResourceExhausted, "%s: failed to save job %s", err.Error(), jobSpec.Name)
+		}
+		if errors.Is(err, models.ErrInvalidTimezone) {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: failed to save job %s", err.Error(), jobSpec.Name)
+		}
 		return nil, status.Errorf(codes.Internal, "%s: failed to save job %s", err.Error(), jobSpec.Name)
 	}
 
@@ -337,12 +612,125 @@ func (sv *RuntimeServiceServer) CreateJobSpecification(ctx context.Context, req
 		return nil, status.Errorf(codes.Internal, "%s\nfailed to sync jobs", err.Error())
 	}
 
+	message := fmt.Sprintf("job %s is created and deployed successfully on project %s", jobSpec.Name, req.GetProjectName())
+	// drift doesn't block the deployment, the job may be meant to change its destination
+	drift, err := sv.jobSvc.DetectSchemaDrift(ctx, projSpec, jobSpec)
+	if err != nil && !errors.Is(err, job.ErrSchemaDriftUnsupported) {
+		logger.W(fmt.Sprintf("failed to detect schema drift of job %s: %s", jobSpec.Name, err))
+	} else if err == nil && drift.HasDrift() {
+		message += "\n" + schemaDriftWarning(drift)
+	}
+	for _, warning := range sv.jobSvc.ValidateAssetReferences(ctx, projSpec, jobSpec) {
+		message += fmt.Sprintf("\nwarning: %s read by %s: %s", warning.Table, warning.Asset, warning.Reason)
+	}
+
 	return &pb.CreateJobSpecificationResponse{
 		Success: true,
-		Message: fmt.Sprintf("job %s is created and deployed successfully on project %s", jobSpec.Name, req.GetProjectName()),
+		Message: message,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) BatchCreateJobSpecifications(ctx context.Context, req *pb.BatchCreateJobSpecificationsRequest) (*pb.BatchCreateJobSpecificationsResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
+	namespaceSpec, err := namespaceRepo.GetByName(jobNamespaceName(req.GetNamespace()))
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found. Is it registered?", err.Error(), jobNamespaceName(req.GetNamespace()))
+	}
+
+	// specs are validated before any of them is saved
+	statuses := make([]*pb.JobSpecificationStatus, len(req.GetSpecs()))
+	var validSpecs []models.JobSpec
+	var failures []string
+	seen := map[string]bool{}
+	for i, specProto := range req.GetSpecs() {
+		statuses[i] = &pb.JobSpecificationStatus{JobName: specProto.GetName(), Success: true}
+		jobSpec, failure := sv.validateNewJobSpec(projSpec, namespaceSpec, specProto, seen)
+		if failure != "" {
+			statuses[i].Success = false
+			statuses[i].Message = failure
+			failures = append(failures, fmt.Sprintf("%s: %s", specProto.GetName(), failure))
+			continue
+		}
+		validSpecs = append(validSpecs, jobSpec)
+	}
+	if len(failures) > 0 && !req.GetAllowPartialSuccess() {
+		return nil, status.Errorf(codes.InvalidArgument, "no job is created since %d of %d specs failed\n%s",
+			len(failures), len(statuses), strings.Join(failures, "\n"))
+	}
+
+	if len(validSpecs) > 0 {
+		if err := sv.jobSvc.CreateAll(namespaceSpec, validSpecs); err != nil {
+			if errors.Is(err, store.ErrResourceExists) {
+				return nil, status.Error(codes.AlreadyExists, err.Error())
+			}
+			if errors.Is(err, models.ErrQuotaExceeded) {
+				return nil, status.Error(codes.ResourceExhausted, err.Error())
+			}
+			if errors.Is(err, models.ErrInvalidTimezone) {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			return nil, status.Errorf(codes.Internal, "%s: failed to save jobs", err.Error())
+		}
+		if err := sv.jobSvc.Sync(ctx, namespaceSpec, sv.progressObserver); err != nil {
+			return nil, status.Errorf(codes.Internal, "%s\nfailed to sync jobs", err.Error())
+		}
+	}
+
+	return &pb.BatchCreateJobSpecificationsResponse{
+		Success: len(failures) == 0,
+		Message: fmt.Sprintf("%d of %d jobs are created and deployed successfully on project %s",
+			len(validSpecs), len(statuses), req.GetProjectName()),
+		Statuses: statuses,
 	}, nil
 }
 
+// validateNewJobSpec adapts a spec of a batch and returns the reason it
+// can't be created if any, names of the batch seen so far are tracked in seen
+func (sv *RuntimeServiceServer) validateNewJobSpec(projSpec models.ProjectSpec, namespaceSpec models.NamespaceSpec,
+	specProto *pb.JobSpecification, seen map[string]bool) (models.JobSpec, string) {
+	if seen[specProto.GetName()] {
+		return models.JobSpec{}, "job is specified more than once"
+	}
+	seen[specProto.GetName()] = true
+
+	jobSpec, err := sv.adapter.FromJobProto(specProto)
+	if err != nil {
+		return models.JobSpec{}, fmt.Sprintf("cannot deserialize job: %s", err.Error())
+	}
+	if _, _, err := sv.jobSvc.GetByNameForProject(jobSpec.Name, projSpec); err == nil {
+		return models.JobSpec{}, "job is already registered"
+	} else if !errors.Is(err, store.ErrResourceNotFound) {
+		return models.JobSpec{}, fmt.Sprintf("failed to check if job is registered: %s", err.Error())
+	}
+	if err := sv.jobSvc.Check(namespaceSpec, []models.JobSpec{jobSpec}, sv.progressObserver); err != nil {
+		return models.JobSpec{}, fmt.Sprintf("spec validation failed: %s", err.Error())
+	}
+	return jobSpec, ""
+}
+
+// schemaDriftWarning describes the columns of the drifted destination
+func schemaDriftWarning(drift *models.SchemaDrift) string {
+	var changes []string
+	for _, column := range drift.AddedColumns {
+		changes = append(changes, fmt.Sprintf("%s %s is missing in the table", column.Name, column.Type))
+	}
+	for _, column := range drift.RemovedColumns {
+		changes = append(changes, fmt.Sprintf("%s %s is no longer produced", column.Name, column.Type))
+	}
+	for _, change := range drift.TypeChanges {
+		changes = append(changes, fmt.Sprintf("%s is %s in the table but %s in the query", change.Name,
+			change.CurrentType, change.ExpectedType))
+	}
+	return fmt.Sprintf("warning: schema of %s has drifted from the job query: %s", drift.Destination,
+		strings.Join(changes, ", "))
+}
+
 func (sv *RuntimeServiceServer) ReadJobSpecification(ctx context.Context, req *pb.ReadJobSpecificationRequest) (*pb.ReadJobSpecificationResponse, error) {
 	projectRepo := sv.projectRepoFactory.New()
 	projSpec, err := projectRepo.GetByName(req.GetProjectName())
@@ -351,9 +739,9 @@ func (sv *RuntimeServiceServer) ReadJobSpecification(ctx context.Context, req *p
 	}
 
 	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
-	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	namespaceSpec, err := namespaceRepo.GetByName(jobNamespaceName(req.GetNamespace()))
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found. Is it registered?", err.Error(), req.GetNamespace())
+		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found. Is it registered?", err.Error(), jobNamespaceName(req.GetNamespace()))
 	}
 
 	jobSpec, err := sv.jobSvc.GetByName(req.GetJobName(), namespaceSpec)
@@ -379,9 +767,9 @@ func (sv *RuntimeServiceServer) DeleteJobSpecification(ctx context.Context, req
 	}
 
 	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
-	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	namespaceSpec, err := namespaceRepo.GetByName(jobNamespaceName(req.GetNamespace()))
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found. Is it registered?", err.Error(), req.GetNamespace())
+		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found. Is it registered?", err.Error(), jobNamespaceName(req.GetNamespace()))
 	}
 
 	jobSpecToDelete, err := sv.jobSvc.GetByName(req.GetJobName(), namespaceSpec)
@@ -399,6 +787,133 @@ func (sv *RuntimeServiceServer) DeleteJobSpecification(ctx context.Context, req
 	}, nil
 }
 
+func (sv *RuntimeServiceServer) UndeleteJobSpecification(ctx context.Context, req *pb.UndeleteJobSpecificationRequest) (*pb.UndeleteJobSpecificationResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
+	namespaceSpec, err := namespaceRepo.GetByName(jobNamespaceName(req.GetNamespace()))
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found. Is it registered?", err.Error(), jobNamespaceName(req.GetNamespace()))
+	}
+
+	if err := sv.jobSvc.Undelete(ctx, namespaceSpec, req.GetJobName()); err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, status.Errorf(codes.NotFound, "%s: deleted job %s not found", err.Error(), req.GetJobName())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to undelete job %s", err.Error(), req.GetJobName())
+	}
+
+	return &pb.UndeleteJobSpecificationResponse{
+		Success: true,
+		Message: fmt.Sprintf("job %s has been restored", req.GetJobName()),
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) PurgeDeletedJobSpecifications(ctx context.Context, req *pb.PurgeDeletedJobSpecificationsRequest) (*pb.PurgeDeletedJobSpecificationsResponse, error) {
+	if req.GetOlderThanDays() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "older than days can't be negative, provided: %d", req.GetOlderThanDays())
+	}
+
+	purged, err := sv.jobSvc.PurgeDeleted(ctx, time.Duration(req.GetOlderThanDays())*24*time.Hour)
+	if err != nil {
+		if errors.Is(err, job.ErrCrossProjectPurgeUnsupported) {
+			return nil, status.Error(codes.Unimplemented, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to purge deleted jobs", err.Error())
+	}
+	return &pb.PurgeDeletedJobSpecificationsResponse{
+		PurgedCount: int32(purged),
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) CloneJobSpecification(ctx context.Context, req *pb.CloneJobSpecificationRequest) (*pb.CloneJobSpecificationResponse, error) {
+	if req.GetDestJob() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name of the cloned job can't be empty")
+	}
+
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	clonedSpec, namespaceSpec, err := sv.jobSvc.CloneJobSpec(projSpec, req.GetSourceJob(), req.GetDestJob())
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, status.Errorf(codes.NotFound, "%s: job %s not found", err.Error(), req.GetSourceJob())
+		}
+		if errors.Is(err, job.ErrJobAlreadyExists) {
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to clone job %s", err.Error(), req.GetSourceJob())
+	}
+
+	jobSpecAdapt, err := sv.adapter.ToJobProto(clonedSpec)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: cannot serialize job", err.Error())
+	}
+	return &pb.CloneJobSpecificationResponse{
+		Spec:      jobSpecAdapt,
+		Namespace: namespaceSpec.Name,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) CloneProject(ctx context.Context, req *pb.CloneProjectRequest) (*pb.CloneProjectResponse, error) {
+	var replacements []models.StringReplacement
+	for _, replacement := range req.GetReplacements() {
+		if replacement.GetFind() == "" {
+			return nil, status.Error(codes.InvalidArgument, "find string of a replacement can't be empty")
+		}
+		replacements = append(replacements, models.StringReplacement{
+			Find:    replacement.GetFind(),
+			Replace: replacement.GetReplace(),
+		})
+	}
+	if req.GetSourceProjectName() == req.GetDestProjectName() {
+		return nil, status.Error(codes.InvalidArgument, "a project can't be cloned to itself")
+	}
+
+	projectRepo := sv.projectRepoFactory.New()
+	sourceProjSpec, err := projectRepo.GetByName(req.GetSourceProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetSourceProjectName())
+	}
+	destProjSpec, err := projectRepo.GetByName(req.GetDestProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found. Is it registered?", err.Error(), req.GetDestProjectName())
+	}
+
+	sourceNamespaces, err := sv.namespaceRepoFactory.New(sourceProjSpec).GetAll()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to retrieve namespaces of project %s", err.Error(), sourceProjSpec.Name)
+	}
+	destNamespaces, err := sv.namespaceRepoFactory.New(destProjSpec).GetAll()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to retrieve namespaces of project %s", err.Error(), destProjSpec.Name)
+	}
+
+	created, err := sv.jobSvc.CloneProject(ctx, sourceNamespaces, destNamespaces, replacements)
+	if err != nil {
+		if errors.Is(err, job.ErrJobAlreadyExists) || errors.Is(err, store.ErrResourceExists) {
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if errors.Is(err, job.ErrCrossProjectCloneUnsupported) {
+			return nil, status.Error(codes.Unimplemented, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to clone project %s", err.Error(), sourceProjSpec.Name)
+	}
+	return &pb.CloneProjectResponse{
+		CreatedCount: int32(created),
+	}, nil
+}
+
 func (sv *RuntimeServiceServer) ListProjects(ctx context.Context, req *pb.ListProjectsRequest) (*pb.ListProjectsResponse, error) {
 	projectRepo := sv.projectRepoFactory.New()
 	projects, err := projectRepo.GetAll()
@@ -591,6 +1106,9 @@ func (sv *RuntimeServiceServer) RegisterSecret(ctx context.Context, req *pb.Regi
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "%s: failed to decode base64 string", err.Error())
 	}
+	if req.GetTtlSeconds() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "ttl of secret %s can't be negative", req.GetSecretName())
+	}
 
 	projectRepo := sv.projectRepoFactory.New()
 	projSpec, err := projectRepo.GetByName(req.GetProjectName())
@@ -598,11 +1116,16 @@ func (sv *RuntimeServiceServer) RegisterSecret(ctx context.Context, req *pb.Regi
 		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
 	}
 
-	secretRepo := sv.secretRepoFactory.New(projSpec)
-	if err := secretRepo.Save(models.ProjectSecretItem{
+	secret := models.ProjectSecretItem{
 		Name:  req.GetSecretName(),
 		Value: string(base64Decoded),
-	}); err != nil {
+	}
+	if req.GetTtlSeconds() > 0 {
+		expiresAt := sv.Now().Add(time.Duration(req.GetTtlSeconds()) * time.Second)
+		secret.ExpiresAt = &expiresAt
+	}
+	secretRepo := sv.secretRepoFactory.New(projSpec)
+	if err := secretRepo.Save(secret); err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to save secret %s", err.Error(), req.GetSecretName())
 	}
 
@@ -611,59 +1134,110 @@ func (sv *RuntimeServiceServer) RegisterSecret(ctx context.Context, req *pb.Regi
 	}, nil
 }
 
-func (sv *RuntimeServiceServer) CreateResource(ctx context.Context, req *pb.CreateResourceRequest) (*pb.CreateResourceResponse, error) {
+func (sv *RuntimeServiceServer) ListSecrets(ctx context.Context, req *pb.ListSecretsRequest) (*pb.ListSecretsResponse, error) {
 	projectRepo := sv.projectRepoFactory.New()
 	projSpec, err := projectRepo.GetByName(req.GetProjectName())
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
 	}
 
-	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
-	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	secrets, err := sv.secretRepoFactory.New(projSpec).GetAll()
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
+		return nil, status.Errorf(codes.Internal, "%s: failed to list secrets of project %s", err.Error(), req.GetProjectName())
 	}
-
-	optResource, err := sv.adapter.FromResourceProto(req.Resource, req.DatastoreName)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "%s: failed to parse resource %s", err.Error(), req.Resource.GetName())
+	secretNames := []string{}
+	for _, secret := range secrets {
+		secretNames = append(secretNames, secret.Name)
 	}
+	sort.Strings(secretNames)
 
-	if err := sv.resourceSvc.CreateResource(ctx, namespaceSpec, []models.ResourceSpec{optResource}, sv.progressObserver); err != nil {
-		return nil, status.Errorf(codes.Internal, "%s: failed to create resource %s", err.Error(), req.Resource.GetName())
-	}
-	return &pb.CreateResourceResponse{
-		Success: true,
+	return &pb.ListSecretsResponse{
+		SecretNames: secretNames,
 	}, nil
 }
 
-func (sv *RuntimeServiceServer) UpdateResource(ctx context.Context, req *pb.UpdateResourceRequest) (*pb.UpdateResourceResponse, error) {
+// RevealProjectSecret returns a secret value once the caller confirms the
+// passphrase configured on the server, every attempt is audit logged
+func (sv *RuntimeServiceServer) RevealProjectSecret(ctx context.Context, req *pb.RevealProjectSecretRequest) (*pb.RevealProjectSecretResponse, error) {
+	auditLog := sv.AuditLog.WithFields(logrus.Fields{
+		"event":     "secret_reveal",
+		"principal": middleware.PrincipalFromContext(ctx),
+		"project":   req.GetProjectName(),
+		"secret":    req.GetSecretName(),
+	})
+	if sv.SecretRevealPassphrase == "" {
+		auditLog.WithField("revealed", false).Warn("secret reveal is disabled")
+		return nil, status.Error(codes.FailedPrecondition, "secret reveal is disabled on the server")
+	}
+	if subtle.ConstantTimeCompare([]byte(req.GetConfirmationPhrase()), []byte(sv.SecretRevealPassphrase)) != 1 {
+		auditLog.WithField("revealed", false).Warn("secret reveal denied, confirmation phrase doesn't match")
+		return nil, status.Error(codes.PermissionDenied, "confirmation phrase doesn't match")
+	}
+
 	projectRepo := sv.projectRepoFactory.New()
 	projSpec, err := projectRepo.GetByName(req.GetProjectName())
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
 	}
 
-	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
-	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	secret, err := sv.secretRepoFactory.New(projSpec).GetByName(req.GetSecretName())
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, status.Errorf(codes.NotFound, "%s: secret %s not found", err.Error(), req.GetSecretName())
+		}
+		if errors.Is(err, store.ErrSecretExpired) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s: failed to read secret %s", err.Error(), req.GetSecretName())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to read secret %s", err.Error(), req.GetSecretName())
 	}
+	auditLog.WithField("revealed", true).Info("secret revealed")
 
-	optResource, err := sv.adapter.FromResourceProto(req.Resource, req.DatastoreName)
+	return &pb.RevealProjectSecretResponse{
+		Value: base64.StdEncoding.EncodeToString([]byte(secret.Value)),
+	}, nil
+}
+
+// RotateSecrets re-encrypts secrets of all projects from the old to the new
+// application key, the server has to be configured with the new key as its
+// app key and the old key as its previous key until the rotation completes
+func (sv *RuntimeServiceServer) RotateSecrets(ctx context.Context, req *pb.RotateSecretsRequest) (*pb.RotateSecretsResponse, error) {
+	oldKey, err := models.NewApplicationSecret(req.GetOldAppKey())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "%s: failed to parse resource %s", err.Error(), req.Resource.GetName())
+		return nil, status.Errorf(codes.InvalidArgument, "%s: invalid old app key", err.Error())
+	}
+	newKey, err := models.NewApplicationSecret(req.GetNewAppKey())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: invalid new app key", err.Error())
+	}
+	if sv.SecretRotator == nil {
+		return nil, status.Error(codes.Unimplemented, "secret rotation is not supported by the server")
+	}
+	// secrets encrypted with keys the server doesn't run with couldn't be
+	// read anymore
+	if !sameAppKey(sv.AppKey.GetKey(), newKey.GetKey()) {
+		return nil, status.Error(codes.FailedPrecondition, "new app key is not the serve.app_key of the server")
+	}
+	if !sameAppKey(sv.AppKey.GetPreviousKey(), oldKey.GetKey()) {
+		return nil, status.Error(codes.FailedPrecondition, "old app key is not the serve.previous_app_key of the server")
 	}
 
-	if err := sv.resourceSvc.UpdateResource(ctx, namespaceSpec, []models.ResourceSpec{optResource}, sv.progressObserver); err != nil {
-		return nil, status.Errorf(codes.Internal, "%s: failed to create resource %s", err.Error(), req.Resource.GetName())
+	if err := sv.SecretRotator.RotateSecrets(oldKey, newKey); err != nil {
+		if errors.Is(err, store.ErrSecretDecryption) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to rotate secrets", err.Error())
 	}
-	return &pb.UpdateResourceResponse{
+	return &pb.RotateSecretsResponse{
 		Success: true,
 	}, nil
 }
 
-func (sv *RuntimeServiceServer) ReadResource(ctx context.Context, req *pb.ReadResourceRequest) (*pb.ReadResourceResponse, error) {
+// sameAppKey compares app keys in constant time, missing keys never match
+func sameAppKey(a, b *[32]byte) bool {
+	return a != nil && b != nil && subtle.ConstantTimeCompare(a[:], b[:]) == 1
+}
+
+func (sv *RuntimeServiceServer) CreateResource(ctx context.Context, req *pb.CreateResourceRequest) (*pb.CreateResourceResponse, error) {
 	projectRepo := sv.projectRepoFactory.New()
 	projSpec, err := projectRepo.GetByName(req.GetProjectName())
 	if err != nil {
@@ -676,130 +1250,1415 @@ func (sv *RuntimeServiceServer) ReadResource(ctx context.Context, req *pb.ReadRe
 		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
 	}
 
-	response, err := sv.resourceSvc.ReadResource(ctx, namespaceSpec, req.DatastoreName, req.ResourceName)
+	optResource, err := sv.adapter.FromResourceProto(req.Resource, req.DatastoreName)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "%s: failed to read resource %s", err.Error(), req.ResourceName)
+		return nil, status.Errorf(codes.Internal, "%s: failed to parse resource %s", err.Error(), req.Resource.GetName())
+	}
+
+	if err := sv.resourceSvc.CreateResource(ctx, namespaceSpec, []models.ResourceSpec{optResource}, sv.progressObserver); err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to create resource %s", err.Error(), req.Resource.GetName())
+	}
+	return &pb.CreateResourceResponse{
+		Success: true,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) UpdateResource(ctx context.Context, req *pb.UpdateResourceRequest) (*pb.UpdateResourceResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
+	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
+	}
+
+	optResource, err := sv.adapter.FromResourceProto(req.Resource, req.DatastoreName)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to parse resource %s", err.Error(), req.Resource.GetName())
+	}
+
+	if err := sv.resourceSvc.UpdateResource(ctx, namespaceSpec, []models.ResourceSpec{optResource}, sv.progressObserver); err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to create resource %s", err.Error(), req.Resource.GetName())
+	}
+	return &pb.UpdateResourceResponse{
+		Success: true,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) ReadResource(ctx context.Context, req *pb.ReadResourceRequest) (*pb.ReadResourceResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
+	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
+	}
+
+	response, err := sv.resourceSvc.ReadResource(ctx, namespaceSpec, req.DatastoreName, req.ResourceName)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to read resource %s", err.Error(), req.ResourceName)
+	}
+
+	protoResource, err := sv.adapter.ToResourceProto(response)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to adapt resource %s", err.Error(), req.ResourceName)
+	}
+
+	return &pb.ReadResourceResponse{
+		Success:  true,
+		Resource: protoResource,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) DeployResourceSpecification(req *pb.DeployResourceSpecificationRequest, respStream pb.RuntimeService_DeployResourceSpecificationServer) error {
+	startTime := time.Now()
+
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
+	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	if err != nil {
+		return status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
+	}
+
+	var resourceSpecs []models.ResourceSpec
+	for _, resourceProto := range req.GetResources() {
+		adapted, err := sv.adapter.FromResourceProto(resourceProto, req.DatastoreName)
+		if err != nil {
+			return status.Errorf(codes.Internal, "%s: cannot adapt resource %s", err.Error(), resourceProto.GetName())
+		}
+		resourceSpecs = append(resourceSpecs, adapted)
+	}
+
+	observers := new(progress.ObserverChain)
+	observers.Join(sv.progressObserver)
+	observers.Join(&resourceObserver{
+		stream: respStream,
+		log:    logrus.New(),
+	})
+
+	if err := sv.resourceSvc.UpdateResource(respStream.Context(), namespaceSpec, resourceSpecs, observers); err != nil {
+		return status.Errorf(codes.Internal, "failed to update resources:\n%s", err.Error())
+	}
+	logger.I("finished resource deployment in", time.Since(startTime))
+	return nil
+}
+
+func (sv *RuntimeServiceServer) ListResourceSpecification(ctx context.Context, req *pb.ListResourceSpecificationRequest) (*pb.ListResourceSpecificationResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
+	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
+	}
+
+	resourceSpecs, err := sv.resourceSvc.GetAll(namespaceSpec, req.DatastoreName)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to retrieve jobs for project %s", err.Error(), req.GetProjectName())
+	}
+
+	resourceProtos := []*pb.ResourceSpecification{}
+	for _, resourceSpec := range resourceSpecs {
+		resourceProto, err := sv.adapter.ToResourceProto(resourceSpec)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "%s: failed to parse job spec %s", err.Error(), resourceSpec.Name)
+		}
+		resourceProtos = append(resourceProtos, resourceProto)
+	}
+	return &pb.ListResourceSpecificationResponse{
+		Resources: resourceProtos,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) ReplayDryRun(ctx context.Context, req *pb.ReplayRequest) (*pb.ReplayDryRunResponse, error) {
+	replayWorkerRequest, err := sv.parseReplayRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rootNode, err := sv.jobSvc.ReplayDryRun(replayWorkerRequest)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error while processing replay dry run: %v", err)
+	}
+
+	node, err := sv.adapter.ToReplayExecutionTreeNode(rootNode)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error while preparing replay dry run response: %v", err)
+	}
+	return &pb.ReplayDryRunResponse{
+		Success:  true,
+		Response: node,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) Replay(ctx context.Context, req *pb.ReplayRequest) (*pb.ReplayResponse, error) {
+	replayWorkerRequest, err := sv.parseReplayRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	replayUUID, err := sv.jobSvc.Replay(ctx, replayWorkerRequest)
+	if err != nil {
+		if errors.Is(err, job.ErrRequestQueueFull) {
+			return nil, status.Errorf(codes.Unavailable, "error while processing replay: %v", err)
+		} else if errors.Is(err, job.ErrConflictedJobRun) {
+			return nil, status.Errorf(codes.FailedPrecondition, "error while validating replay: %v", err)
+		} else if errors.Is(err, job.ErrReplayRangeTooLarge) {
+			return nil, status.Errorf(codes.InvalidArgument, "error while validating replay: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "error while processing replay: %v", err)
+	}
+
+	return &pb.ReplayResponse{
+		Id: replayUUID,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) GetReplayStatus(ctx context.Context, req *pb.GetReplayStatusRequest) (*pb.GetReplayStatusResponse, error) {
+	replayID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: invalid replay id %s", err.Error(), req.GetId())
+	}
+
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	jobSpec, _, err := sv.jobSvc.GetByNameForProject(req.GetJobName(), projSpec)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: failed to find the job %s for project %s", err.Error(),
+			req.GetJobName(), req.GetProjectName())
+	}
+
+	replaySpec, err := sv.jobSvc.GetReplay(jobSpec, replayID)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, status.Errorf(codes.NotFound, "%s: replay %s not found", err.Error(), req.GetId())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to get replay %s", err.Error(), req.GetId())
+	}
+
+	return &pb.GetReplayStatusResponse{
+		Id:        replaySpec.ID.String(),
+		JobName:   jobSpec.Name,
+		StartDate: replaySpec.StartDate.Format(job.ReplayDateFormat),
+		EndDate:   replaySpec.EndDate.Format(job.ReplayDateFormat),
+		Status:    replaySpec.Status,
+		Message:   replaySpec.Message.Message,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) PreviewSchedulePropagation(ctx context.Context, req *pb.PreviewSchedulePropagationRequest) (*pb.PreviewSchedulePropagationResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	if _, _, err = sv.jobSvc.GetByNameForProject(req.GetJobName(), projSpec); err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: failed to find the job %s for project %s", err.Error(),
+			req.GetJobName(), req.GetProjectName())
+	}
+
+	scheduleChanges, err := sv.jobSvc.PropagateScheduleChanges(ctx, projSpec, req.GetJobName())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to compute schedule propagation for %s", err.Error(),
+			req.GetJobName())
+	}
+
+	var adaptedChanges []*pb.ScheduleChange
+	for _, scheduleChange := range scheduleChanges {
+		adaptedChanges = append(adaptedChanges, &pb.ScheduleChange{
+			JobName:          scheduleChange.JobName,
+			UpstreamJobName:  scheduleChange.UpstreamJobName,
+			CurrentInterval:  scheduleChange.CurrentInterval,
+			ProposedInterval: scheduleChange.ProposedInterval,
+		})
+	}
+	return &pb.PreviewSchedulePropagationResponse{
+		Changes: adaptedChanges,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) SmartBackfillJob(ctx context.Context, req *pb.SmartBackfillJobRequest) (*pb.SmartBackfillJobResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	if _, _, err = sv.jobSvc.GetByNameForProject(req.GetJobName(), projSpec); err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: failed to find the job %s for project %s", err.Error(),
+			req.GetJobName(), req.GetProjectName())
+	}
+
+	startDate, err := time.Parse(job.ReplayDateFormat, req.GetStartDate())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to parse backfill start date(e.g. %s): %v", job.ReplayDateFormat, err)
+	}
+	endDate := startDate
+	if req.GetEndDate() != "" {
+		if endDate, err = time.Parse(job.ReplayDateFormat, req.GetEndDate()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "unable to parse backfill end date(e.g. %s): %v", job.ReplayDateFormat, err)
+		}
+	}
+	if endDate.Before(startDate) {
+		return nil, status.Errorf(codes.InvalidArgument, "backfill end date cannot be before start date")
+	}
+
+	plan, err := sv.jobSvc.BackfillMissingRuns(ctx, projSpec, req.GetJobName(), startDate, endDate, req.GetDryRun())
+	if err != nil {
+		if errors.Is(err, job.ErrRequestQueueFull) {
+			return nil, status.Errorf(codes.Unavailable, "error while processing backfill: %v", err)
+		} else if errors.Is(err, job.ErrConflictedJobRun) {
+			return nil, status.Errorf(codes.FailedPrecondition, "error while validating backfill: %v", err)
+		} else if errors.Is(err, job.ErrReplayRangeTooLarge) {
+			return nil, status.Errorf(codes.InvalidArgument, "error while validating backfill: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "error while processing backfill: %v", err)
+	}
+
+	resp := &pb.SmartBackfillJobResponse{
+		DryRun:    plan.DryRun,
+		ReplayIds: plan.ReplayIDs,
+	}
+	for _, run := range plan.MissingRuns {
+		resp.MissingRuns = append(resp.MissingRuns, timestamppb.New(run))
+	}
+	for _, skipped := range plan.SkippedRuns {
+		resp.SkippedRuns = append(resp.SkippedRuns, &pb.BackfillSkippedRun{
+			ScheduledAt: timestamppb.New(skipped.ScheduledAt),
+			Reason:      skipped.Reason,
+		})
+	}
+	return resp, nil
+}
+
+func (sv *RuntimeServiceServer) PinDependencyVersion(ctx context.Context, req *pb.PinDependencyVersionRequest) (*pb.PinDependencyVersionResponse, error) {
+	if req.GetSpecVersion() == "" {
+		return nil, status.Error(codes.InvalidArgument, "spec version is required to pin a dependency")
+	}
+
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	if err := sv.jobSvc.PinDependencyVersion(projSpec, req.GetJobName(), req.GetDependencyName(), req.GetSpecVersion()); err != nil {
+		if errors.Is(err, job.ErrNotAnUpstream) {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: failed to pin dependency", err.Error())
+		} else if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, status.Errorf(codes.NotFound, "%s: failed to pin dependency", err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to pin dependency", err.Error())
+	}
+	return &pb.PinDependencyVersionResponse{
+		Success: true,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) UnpinDependency(ctx context.Context, req *pb.UnpinDependencyRequest) (*pb.UnpinDependencyResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	if err := sv.jobSvc.UnpinDependency(projSpec, req.GetJobName(), req.GetDependencyName()); err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, status.Errorf(codes.NotFound, "%s: failed to unpin dependency", err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to unpin dependency", err.Error())
+	}
+	return &pb.UnpinDependencyResponse{
+		Success: true,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) ListDependencyPins(ctx context.Context, req *pb.ListDependencyPinsRequest) (*pb.ListDependencyPinsResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	pins, err := sv.jobSvc.ListDependencyPins(projSpec)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to list dependency pins of %s", err.Error(), req.GetProjectName())
+	}
+
+	var adaptedPins []*pb.DependencyPin
+	for _, pin := range pins {
+		adaptedPins = append(adaptedPins, &pb.DependencyPin{
+			JobName:        pin.JobName,
+			DependencyName: pin.DependencyName,
+			SpecVersion:    pin.SpecVersion,
+			CreatedAt:      timestamppb.New(pin.CreatedAt),
+		})
+	}
+	return &pb.ListDependencyPinsResponse{
+		Pins: adaptedPins,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) GetProjectDiagnostics(ctx context.Context, req *pb.GetProjectDiagnosticsRequest) (*pb.GetProjectDiagnosticsResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	health, err := sv.projectHealthSvc.GetProjectHealth(ctx, projSpec)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to diagnose project %s", err.Error(), req.GetProjectName())
+	}
+
+	resp := &pb.GetProjectDiagnosticsResponse{
+		Healthy:                  health.Healthy(),
+		UnresolvableDependencies: int32(health.UnresolvableDependencies),
+		CheckedAt:                timestamppb.New(health.CheckedAt),
+	}
+	if !health.LastDeployedAt.IsZero() {
+		resp.LastDeployedAt = timestamppb.New(health.LastDeployedAt)
+	}
+	for _, check := range health.Checks {
+		resp.Checks = append(resp.Checks, &pb.ProjectHealthCheck{
+			Name:    check.Name,
+			Status:  check.Status.String(),
+			Message: check.Message,
+			Latency: durationpb.New(check.Latency),
+		})
+	}
+	return resp, nil
+}
+
+func (sv *RuntimeServiceServer) TagRelease(ctx context.Context, req *pb.TagReleaseRequest) (*pb.TagReleaseResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	if err := sv.jobSvc.TagRelease(projSpec, req.GetTag(), req.GetDescription(), sv.progressObserver); err != nil {
+		if errors.Is(err, job.ErrEmptyReleaseTag) {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: failed to tag release", err.Error())
+		} else if errors.Is(err, store.ErrResourceExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "%s: failed to tag release", err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to tag release", err.Error())
+	}
+	return &pb.TagReleaseResponse{
+		Success: true,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) RollbackToRelease(ctx context.Context, req *pb.RollbackToReleaseRequest) (*pb.RollbackToReleaseResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	release, err := sv.jobSvc.RollbackToRelease(ctx, projSpec, req.GetTag(), sv.progressObserver)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, status.Errorf(codes.NotFound, "%s: failed to rollback to release %s", err.Error(), req.GetTag())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to rollback to release %s", err.Error(), req.GetTag())
+	}
+	var jobNames []string
+	for _, releaseJob := range release.Jobs {
+		jobNames = append(jobNames, releaseJob.Spec.Name)
+	}
+	return &pb.RollbackToReleaseResponse{
+		Success:  true,
+		JobNames: jobNames,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) ListActiveJobInstances(ctx context.Context, req *pb.ListActiveJobInstancesRequest) (*pb.ListActiveJobInstancesResponse, error) {
+	if req.GetMaxAgeMinutes() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "max age minutes should be positive, provided: %d", req.GetMaxAgeMinutes())
+	}
+
+	instances, err := sv.instSvc.ListActive(ctx, time.Duration(req.GetMaxAgeMinutes())*time.Minute)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to list active instances", err.Error())
+	}
+
+	// cross validate with the scheduler, a project whose running jobs can't be
+	// fetched has all of its instances reported as not running on the scheduler
+	runningOnScheduler := map[string]map[string]bool{}
+	for _, instance := range instances {
+		if _, ok := runningOnScheduler[instance.Project.Name]; ok {
+			continue
+		}
+		runningOnScheduler[instance.Project.Name] = sv.runningJobNames(ctx, instance.Project.Name)
+	}
+
+	var activeInstances []*pb.ActiveJobInstance
+	for _, instance := range instances {
+		instanceProto, err := sv.adapter.ToInstanceProto(instance)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "%s: cannot adapt instance for job %s", err.Error(), instance.Job.Name)
+		}
+		activeInstances = append(activeInstances, &pb.ActiveJobInstance{
+			ProjectName:      instance.Project.Name,
+			JobName:          instance.Job.Name,
+			Instance:         instanceProto,
+			SchedulerRunning: runningOnScheduler[instance.Project.Name][instance.Job.Name],
+		})
+	}
+	return &pb.ListActiveJobInstancesResponse{
+		Instances: activeInstances,
+	}, nil
+}
+
+// runningJobNames returns names of jobs of the project running on the scheduler
+func (sv *RuntimeServiceServer) runningJobNames(ctx context.Context, projectName string) map[string]bool {
+	jobNames := map[string]bool{}
+	projSpec, err := sv.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		return jobNames
+	}
+	runningJobs, err := sv.scheduler.GetRunningJobs(ctx, projSpec)
+	if err != nil {
+		return jobNames
+	}
+	for _, runningJob := range runningJobs {
+		jobNames[runningJob.JobName] = true
+	}
+	return jobNames
+}
+
+func (sv *RuntimeServiceServer) ListRunningJobs(ctx context.Context, req *pb.ListRunningJobsRequest) (*pb.ListRunningJobsResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	runningJobs, err := sv.scheduler.GetRunningJobs(ctx, projSpec)
+	if err != nil {
+		if errors.Is(err, models.ErrUnsupportedSchedulerOperation) {
+			return nil, status.Errorf(codes.Unimplemented, "%s: failed to list running jobs of project %s", err.Error(), req.GetProjectName())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to list running jobs of project %s", err.Error(), req.GetProjectName())
+	}
+
+	var adaptedJobs []*pb.RunningJob
+	for _, runningJob := range runningJobs {
+		adaptedJobs = append(adaptedJobs, &pb.RunningJob{
+			JobName:       runningJob.JobName,
+			DagRunId:      runningJob.DAGRunID,
+			StartTime:     timestamppb.New(runningJob.StartTime),
+			ExecutorState: runningJob.ExecutorState,
+		})
+	}
+	return &pb.ListRunningJobsResponse{
+		Jobs: adaptedJobs,
+	}, nil
+}
+
+// ListSchedulerJobs lists jobs of the project deployed to the scheduler,
+// including the ones left deployed without a job spec
+func (sv *RuntimeServiceServer) ListSchedulerJobs(ctx context.Context, req *pb.ListSchedulerJobsRequest) (*pb.ListSchedulerJobsResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	jobNames, err := sv.scheduler.ListDeployedJobs(ctx, projSpec)
+	if err != nil {
+		if errors.Is(err, models.ErrUnsupportedSchedulerOperation) {
+			return nil, status.Errorf(codes.Unimplemented, "%s: failed to list scheduler jobs of project %s", err.Error(), req.GetProjectName())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to list scheduler jobs of project %s", err.Error(), req.GetProjectName())
+	}
+	sort.Strings(jobNames)
+	return &pb.ListSchedulerJobsResponse{
+		JobNames: jobNames,
+	}, nil
+}
+
+// roleBindingRepo returns the role bindings of the project of the request
+func (sv *RuntimeServiceServer) roleBindingRepo(projectName string) (store.ProjectRoleBindingRepository, error) {
+	if sv.RoleBindingRepoFactory == nil {
+		return nil, status.Error(codes.Unimplemented, "project roles are not supported by the server")
+	}
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(projectName)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), projectName)
+	}
+	return sv.RoleBindingRepoFactory.New(projSpec), nil
+}
+
+func (sv *RuntimeServiceServer) GrantProjectRole(ctx context.Context, req *pb.GrantProjectRoleRequest) (*pb.GrantProjectRoleResponse, error) {
+	if req.GetSubject() == "" {
+		return nil, status.Error(codes.InvalidArgument, "subject is required")
+	}
+	role, err := models.ParseRole(req.GetRole())
+	if err != nil || role == models.RoleNone {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid role %s, should be one of %s, %s, %s", req.GetRole(),
+			models.RoleViewer, models.RoleEditor, models.RoleAdmin)
+	}
+	roleBindingRepo, err := sv.roleBindingRepo(req.GetProjectName())
+	if err != nil {
+		return nil, err
+	}
+	if err := roleBindingRepo.Save(models.ProjectRoleBinding{Subject: req.GetSubject(), Role: role}); err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to grant role to %s", err.Error(), req.GetSubject())
+	}
+	sv.AuditLog.WithFields(logrus.Fields{
+		"event":     "project_role_grant",
+		"principal": middleware.PrincipalFromContext(ctx),
+		"project":   req.GetProjectName(),
+		"subject":   req.GetSubject(),
+		"role":      role.String(),
+	}).Info("project role granted")
+	return &pb.GrantProjectRoleResponse{}, nil
+}
+
+func (sv *RuntimeServiceServer) RevokeProjectRole(ctx context.Context, req *pb.RevokeProjectRoleRequest) (*pb.RevokeProjectRoleResponse, error) {
+	roleBindingRepo, err := sv.roleBindingRepo(req.GetProjectName())
+	if err != nil {
+		return nil, err
+	}
+	if err := roleBindingRepo.Delete(req.GetSubject()); err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, status.Errorf(codes.NotFound, "%s: %s has no role in project %s", err.Error(), req.GetSubject(), req.GetProjectName())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to revoke role of %s", err.Error(), req.GetSubject())
+	}
+	sv.AuditLog.WithFields(logrus.Fields{
+		"event":     "project_role_revoke",
+		"principal": middleware.PrincipalFromContext(ctx),
+		"project":   req.GetProjectName(),
+		"subject":   req.GetSubject(),
+	}).Info("project role revoked")
+	return &pb.RevokeProjectRoleResponse{}, nil
+}
+
+func (sv *RuntimeServiceServer) ListProjectRoles(ctx context.Context, req *pb.ListProjectRolesRequest) (*pb.ListProjectRolesResponse, error) {
+	roleBindingRepo, err := sv.roleBindingRepo(req.GetProjectName())
+	if err != nil {
+		return nil, err
+	}
+	bindings, err := roleBindingRepo.GetAll()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to list roles of project %s", err.Error(), req.GetProjectName())
+	}
+	bindingProtos := []*pb.ProjectRoleBinding{}
+	for _, binding := range bindings {
+		bindingProtos = append(bindingProtos, &pb.ProjectRoleBinding{
+			Subject: binding.Subject,
+			Role:    binding.Role.String(),
+		})
+	}
+	return &pb.ListProjectRolesResponse{
+		Bindings: bindingProtos,
+	}, nil
+}
+
+// GenerateAPIKey returns a new api key of the project, only the hash of the
+// key is stored so it can't be returned again
+func (sv *RuntimeServiceServer) GenerateAPIKey(ctx context.Context, req *pb.GenerateAPIKeyRequest) (*pb.GenerateAPIKeyResponse, error) {
+	if sv.APIKeyRepoFactory == nil {
+		return nil, status.Error(codes.Unimplemented, "api keys are not supported by the server")
+	}
+	role, err := models.ParseRole(req.GetRole())
+	if err != nil || role == models.RoleNone {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid role %s, should be one of %s, %s, %s", req.GetRole(),
+			models.RoleViewer, models.RoleEditor, models.RoleAdmin)
+	}
+	if req.GetTtlSeconds() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "ttl of api key can't be negative")
+	}
+
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	key, keyHash, err := middleware.GenerateAPIKey()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	apiKey := models.APIKey{
+		ID:          uuid.New(),
+		ProjectName: projSpec.Name,
+		Description: req.GetDescription(),
+		Role:        role,
+		CreatedAt:   sv.Now(),
+	}
+	var expiresAt *timestamppb.Timestamp
+	if req.GetTtlSeconds() > 0 {
+		expiry := apiKey.CreatedAt.Add(time.Duration(req.GetTtlSeconds()) * time.Second)
+		apiKey.ExpiresAt = &expiry
+		expiresAt = timestamppb.New(expiry)
+	}
+	if err := sv.APIKeyRepoFactory.New(projSpec).Save(apiKey, keyHash); err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to save api key", err.Error())
+	}
+	sv.AuditLog.WithFields(logrus.Fields{
+		"event":     "api_key_generate",
+		"principal": middleware.PrincipalFromContext(ctx),
+		"project":   projSpec.Name,
+		"api_key":   apiKey.ID.String(),
+		"role":      role.String(),
+	}).Info("api key generated")
+
+	return &pb.GenerateAPIKeyResponse{
+		Id:        apiKey.ID.String(),
+		Key:       key,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) ListAuditLog(ctx context.Context, req *pb.ListAuditLogRequest) (*pb.ListAuditLogResponse, error) {
+	if sv.AuditLogRepo == nil {
+		return nil, status.Error(codes.Unimplemented, "audit log is not supported by the server")
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = DefaultAuditLogLimit
+	} else if limit > MaxAuditLogLimit {
+		limit = MaxAuditLogLimit
+	}
+	filter := models.AuditLogFilter{
+		ProjectName: req.GetProjectName(),
+		Principal:   req.GetPrincipal(),
+		Limit:       limit,
+	}
+	if req.GetSince() != nil {
+		filter.Since = req.GetSince().AsTime()
+	}
+	if req.GetUntil() != nil {
+		filter.Until = req.GetUntil().AsTime()
+	}
+	entries, err := sv.AuditLogRepo.GetAll(ctx, filter)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to list audit log", err.Error())
+	}
+	entryProtos := []*pb.AuditLogEntry{}
+	for _, entry := range entries {
+		entryProtos = append(entryProtos, &pb.AuditLogEntry{
+			Time:        timestamppb.New(entry.Time),
+			Principal:   entry.Principal,
+			Method:      entry.Method,
+			ProjectName: entry.ProjectName,
+			Request:     entry.Request,
+			Status:      entry.Status,
+		})
+	}
+	return &pb.ListAuditLogResponse{
+		Entries: entryProtos,
+	}, nil
+}
+
+// ListSLABreaches lists the runs of jobs of the project detected not starting
+// within the sla of their job, the most recently scheduled first
+func (sv *RuntimeServiceServer) ListSLABreaches(ctx context.Context, req *pb.ListSLABreachesRequest) (*pb.ListSLABreachesResponse, error) {
+	if sv.SLABreachRepo == nil {
+		return nil, status.Error(codes.Unimplemented, "sla monitoring is not supported by the server")
+	}
+	projectRepo := sv.projectRepoFactory.New()
+	if _, err := projectRepo.GetByName(req.GetProjectName()); err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to read project %s", err.Error(), req.GetProjectName())
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = DefaultSLABreachLimit
+	} else if limit > MaxSLABreachLimit {
+		limit = MaxSLABreachLimit
+	}
+	filter := models.SLABreachFilter{
+		JobName: req.GetJobName(),
+		Limit:   limit,
+	}
+	if req.GetSince() != nil {
+		filter.Since = req.GetSince().AsTime()
+	}
+	breaches, err := sv.SLABreachRepo.GetAll(ctx, req.GetProjectName(), filter)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to list sla breaches of project %s", err.Error(), req.GetProjectName())
+	}
+	breachProtos := []*pb.SLABreach{}
+	for _, breach := range breaches {
+		breachProto := &pb.SLABreach{
+			Namespace:   breach.NamespaceName,
+			JobName:     breach.JobName,
+			ScheduledAt: timestamppb.New(breach.ScheduledAt),
+			DueAt:       timestamppb.New(breach.DueAt),
+			SlaDuration: durationpb.New(breach.SLADuration),
+			DetectedAt:  timestamppb.New(breach.DetectedAt),
+		}
+		if !breach.StartedAt.IsZero() {
+			breachProto.StartedAt = timestamppb.New(breach.StartedAt)
+		}
+		breachProtos = append(breachProtos, breachProto)
+	}
+	return &pb.ListSLABreachesResponse{
+		Breaches: breachProtos,
+	}, nil
+}
+
+// RegisterProjectVariables replaces the variables of the project, jobs use
+// them when their assets are compiled
+func (sv *RuntimeServiceServer) RegisterProjectVariables(ctx context.Context, req *pb.RegisterProjectVariablesRequest) (*pb.RegisterProjectVariablesResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	if err := projectRepo.SaveVariables(req.GetProjectName(), req.GetVariables()); err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to save variables of project %s", err.Error(), req.GetProjectName())
+	}
+	return &pb.RegisterProjectVariablesResponse{}, nil
+}
+
+// SetProjectQuota replaces the quota limiting the jobs of the project, jobs
+// registered before are kept. Negative limits lift the default ones
+func (sv *RuntimeServiceServer) SetProjectQuota(ctx context.Context, req *pb.SetProjectQuotaRequest) (*pb.SetProjectQuotaResponse, error) {
+	quota := models.ProjectQuota{
+		MaxJobCount: int(req.GetMaxJobCount()),
+	}
+	if req.GetMinScheduleInterval() != nil {
+		quota.MinScheduleInterval = req.GetMinScheduleInterval().AsDuration()
+	}
+	projectRepo := sv.projectRepoFactory.New()
+	if err := projectRepo.SaveQuota(req.GetProjectName(), quota); err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to save quota of project %s", err.Error(), req.GetProjectName())
+	}
+	return &pb.SetProjectQuotaResponse{}, nil
+}
+
+func (sv *RuntimeServiceServer) GetProjectVariables(ctx context.Context, req *pb.GetProjectVariablesRequest) (*pb.GetProjectVariablesResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+	return &pb.GetProjectVariablesResponse{
+		Variables: projSpec.Variables,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) GetInstanceStatusSummary(ctx context.Context, req *pb.GetInstanceStatusSummaryRequest) (*pb.GetInstanceStatusSummaryResponse, error) {
+	if req.GetWindowMinutes() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "window minutes should be positive, provided: %d", req.GetWindowMinutes())
+	}
+
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	counts, err := sv.instSvc.GetStatusSummary(ctx, projSpec, time.Duration(req.GetWindowMinutes())*time.Minute)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to summarize instances of project %s", err.Error(), req.GetProjectName())
+	}
+	return &pb.GetInstanceStatusSummaryResponse{
+		InstancesByStatus: counts,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) CheckJobSchemaDrift(ctx context.Context, req *pb.CheckJobSchemaDriftRequest) (*pb.CheckJobSchemaDriftResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	jobSpec, _, err := sv.jobSvc.GetByNameForProject(req.GetJobName(), projSpec)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: failed to find the job %s for project %s", err.Error(),
+			req.GetJobName(), req.GetProjectName())
+	}
+
+	drift, err := sv.jobSvc.DetectSchemaDrift(ctx, projSpec, jobSpec)
+	if err != nil {
+		if errors.Is(err, job.ErrSchemaDriftUnsupported) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s: %s", err.Error(), req.GetJobName())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to detect schema drift of job %s", err.Error(),
+			req.GetJobName())
+	}
+
+	adaptColumns := func(fields []models.SchemaField) []*pb.SchemaDriftColumn {
+		var columns []*pb.SchemaDriftColumn
+		for _, field := range fields {
+			columns = append(columns, &pb.SchemaDriftColumn{
+				Name: field.Name,
+				Type: field.Type,
+			})
+		}
+		return columns
+	}
+	var typeChanges []*pb.SchemaDriftTypeChange
+	for _, change := range drift.TypeChanges {
+		typeChanges = append(typeChanges, &pb.SchemaDriftTypeChange{
+			Name:         change.Name,
+			CurrentType:  change.CurrentType,
+			ExpectedType: change.ExpectedType,
+		})
+	}
+	return &pb.CheckJobSchemaDriftResponse{
+		HasDrift:       drift.HasDrift(),
+		Destination:    drift.Destination,
+		AddedColumns:   adaptColumns(drift.AddedColumns),
+		RemovedColumns: adaptColumns(drift.RemovedColumns),
+		TypeChanges:    typeChanges,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) EstimateJobCost(ctx context.Context, req *pb.EstimateJobCostRequest) (*pb.EstimateJobCostResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
 	}
 
-	protoResource, err := sv.adapter.ToResourceProto(response)
+	jobSpec, _, err := sv.jobSvc.GetByNameForProject(req.GetJobName(), projSpec)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "%s: failed to adapt resource %s", err.Error(), req.ResourceName)
+		return nil, status.Errorf(codes.NotFound, "%s: failed to find the job %s for project %s", err.Error(),
+			req.GetJobName(), req.GetProjectName())
 	}
 
-	return &pb.ReadResourceResponse{
-		Success:  true,
-		Resource: protoResource,
+	estimate, err := sv.jobSvc.EstimateCost(ctx, projSpec, jobSpec)
+	if err != nil {
+		if errors.Is(err, job.ErrCostEstimateUnsupported) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s: %s", err.Error(), req.GetJobName())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to estimate cost of job %s", err.Error(),
+			req.GetJobName())
+	}
+	return &pb.EstimateJobCostResponse{
+		TotalBytesProcessed: estimate.TotalBytesProcessed,
+		EstimatedCostUsd:    estimate.EstimatedCostUSD,
 	}, nil
 }
 
-func (sv *RuntimeServiceServer) DeployResourceSpecification(req *pb.DeployResourceSpecificationRequest, respStream pb.RuntimeService_DeployResourceSpecificationServer) error {
-	startTime := time.Now()
-
+func (sv *RuntimeServiceServer) ExplainJobDependencies(ctx context.Context, req *pb.ExplainJobDependenciesRequest) (*pb.ExplainJobDependenciesResponse, error) {
 	projectRepo := sv.projectRepoFactory.New()
 	projSpec, err := projectRepo.GetByName(req.GetProjectName())
 	if err != nil {
-		return status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
 	}
 
-	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
-	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	jobSpec, _, err := sv.jobSvc.GetByNameForProject(req.GetJobName(), projSpec)
 	if err != nil {
-		return status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
+		return nil, status.Errorf(codes.NotFound, "%s: failed to find the job %s for project %s", err.Error(),
+			req.GetJobName(), req.GetProjectName())
 	}
 
-	var resourceSpecs []models.ResourceSpec
-	for _, resourceProto := range req.GetResources() {
-		adapted, err := sv.adapter.FromResourceProto(resourceProto, req.DatastoreName)
-		if err != nil {
-			return status.Errorf(codes.Internal, "%s: cannot adapt resource %s", err.Error(), resourceProto.GetName())
+	trace, err := sv.jobSvc.ExplainDependencyResolution(ctx, projSpec, jobSpec)
+	if err != nil {
+		if errors.Is(err, job.ErrDependencyExplanationUnsupported) {
+			return nil, status.Errorf(codes.Unimplemented, "%s: %s", err.Error(), req.GetJobName())
 		}
-		resourceSpecs = append(resourceSpecs, adapted)
+		return nil, status.Errorf(codes.Internal, "%s: failed to explain dependencies of job %s", err.Error(),
+			req.GetJobName())
 	}
 
-	observers := new(progress.ObserverChain)
-	observers.Join(sv.progressObserver)
-	observers.Join(&resourceObserver{
-		stream: respStream,
-		log:    logrus.New(),
-	})
+	steps := []*pb.DependencyResolutionStep{}
+	for _, step := range trace.Steps {
+		steps = append(steps, &pb.DependencyResolutionStep{
+			Source:      step.Source,
+			Dependency:  step.Dependency,
+			JobName:     step.JobName,
+			ProjectName: step.ProjectName,
+			Type:        step.Type.String(),
+			Included:    step.Included,
+			Reason:      step.Reason,
+		})
+	}
+	return &pb.ExplainJobDependenciesResponse{
+		DeclaredDependencies: trace.DeclaredDependencies,
+		InferenceEnabled:     trace.InferenceEnabled,
+		InferredDependencies: trace.InferredDependencies,
+		Steps:                steps,
+	}, nil
+}
 
-	if err := sv.resourceSvc.UpdateResource(respStream.Context(), namespaceSpec, resourceSpecs, observers); err != nil {
-		return status.Errorf(codes.Internal, "failed to update resources:\n%s", err.Error())
+func (sv *RuntimeServiceServer) GetDependencyGraph(ctx context.Context, req *pb.GetDependencyGraphRequest) (*pb.GetDependencyGraphResponse, error) {
+	if req.GetDepth() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "depth %d should not be negative", req.GetDepth())
 	}
-	logger.I("finished resource deployment in", time.Since(startTime))
-	return nil
+	var render func(models.DependencyGraph) string
+	switch req.GetFormat() {
+	case pb.GetDependencyGraphRequest_UNKNOWN, pb.GetDependencyGraphRequest_DOT:
+		render = job.RenderDependencyGraphDOT
+	case pb.GetDependencyGraphRequest_MERMAID:
+		render = job.RenderDependencyGraphMermaid
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown graph format %s", req.GetFormat())
+	}
+
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	graph, err := sv.jobSvc.GetDependencyGraph(ctx, projSpec, req.GetJobName(), int(req.GetDepth()))
+	if err != nil {
+		if errors.Is(err, models.ErrNoSuchJob) {
+			return nil, status.Errorf(codes.NotFound, "%s: failed to find the job %s for project %s", err.Error(),
+				req.GetJobName(), req.GetProjectName())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to build dependency graph of project %s", err.Error(),
+			req.GetProjectName())
+	}
+	return &pb.GetDependencyGraphResponse{
+		Graph: render(graph),
+	}, nil
 }
 
-func (sv *RuntimeServiceServer) ListResourceSpecification(ctx context.Context, req *pb.ListResourceSpecificationRequest) (*pb.ListResourceSpecificationResponse, error) {
+func (sv *RuntimeServiceServer) ValidateSchedule(ctx context.Context, req *pb.ValidateScheduleRequest) (*pb.ValidateScheduleResponse, error) {
+	validation, err := job.ValidateSchedule(models.JobSpecSchedule{
+		Interval: req.GetInterval(),
+		Timezone: req.GetTimezone(),
+	}, sv.Now(), int(req.GetCount()))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: invalid schedule", err.Error())
+	}
+
+	nextRuns := []string{}
+	for _, run := range validation.NextRuns {
+		nextRuns = append(nextRuns, run.Format(time.RFC3339))
+	}
+	return &pb.ValidateScheduleResponse{
+		NextRuns: nextRuns,
+		Warnings: validation.Warnings,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) ListSuppressedJobRuns(ctx context.Context, req *pb.ListSuppressedJobRunsRequest) (*pb.ListSuppressedJobRunsResponse, error) {
 	projectRepo := sv.projectRepoFactory.New()
 	projSpec, err := projectRepo.GetByName(req.GetProjectName())
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
 	}
 
-	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
-	namespaceSpec, err := namespaceRepo.GetByName(req.GetNamespace())
+	jobSpec, _, err := sv.jobSvc.GetByNameForProject(req.GetJobName(), projSpec)
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
+		return nil, status.Errorf(codes.NotFound, "%s: failed to find the job %s for project %s", err.Error(),
+			req.GetJobName(), req.GetProjectName())
 	}
 
-	resourceSpecs, err := sv.resourceSvc.GetAll(namespaceSpec, req.DatastoreName)
+	suppressed, err := job.SuppressedRuns(projSpec, jobSpec, sv.Now(), int(req.GetCount()))
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "%s: failed to retrieve jobs for project %s", err.Error(), req.GetProjectName())
+		return nil, status.Errorf(codes.InvalidArgument, "%s: failed to list suppressed runs of job %s", err.Error(),
+			req.GetJobName())
 	}
 
-	resourceProtos := []*pb.ResourceSpecification{}
-	for _, resourceSpec := range resourceSpecs {
-		resourceProto, err := sv.adapter.ToResourceProto(resourceSpec)
+	runs := []*pb.SuppressedJobRun{}
+	for _, run := range suppressed {
+		runs = append(runs, &pb.SuppressedJobRun{
+			ScheduledAt: run.ScheduledAt.Format(time.RFC3339),
+			WindowStart: run.WindowStart.Format(time.RFC3339),
+			WindowEnd:   run.WindowStart.Add(run.Window.Duration).Format(time.RFC3339),
+			Window:      run.Window.Start,
+		})
+	}
+	return &pb.ListSuppressedJobRunsResponse{
+		Runs: runs,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) TriggerJobRun(ctx context.Context, req *pb.TriggerJobRunRequest) (*pb.TriggerJobRunResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	jobSpec, _, err := sv.jobSvc.GetByNameForProject(req.GetJobName(), projSpec)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: failed to find the job %s for project %s", err.Error(),
+			req.GetJobName(), req.GetProjectName())
+	}
+
+	logicalDate := sv.Now()
+	if req.GetLogicalDate() != nil {
+		if err := req.GetLogicalDate().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: invalid logical date", err.Error())
+		}
+		logicalDate = req.GetLogicalDate().AsTime()
+	}
+	if logicalDate.Before(jobSpec.Schedule.StartDate) {
+		return nil, status.Errorf(codes.InvalidArgument, "logical date %s is before the start date %s of job %s",
+			logicalDate.Format(time.RFC3339), jobSpec.Schedule.StartDate.Format(time.RFC3339), jobSpec.Name)
+	}
+	if jobSpec.Schedule.EndDate != nil && logicalDate.After(*jobSpec.Schedule.EndDate) {
+		return nil, status.Errorf(codes.InvalidArgument, "logical date %s is after the end date %s of job %s",
+			logicalDate.Format(time.RFC3339), jobSpec.Schedule.EndDate.Format(time.RFC3339), jobSpec.Name)
+	}
+
+	// schedulers not listing their jobs fail to trigger runs of jobs not
+	// deployed to them instead
+	deployedJobs, err := sv.scheduler.ListDeployedJobs(ctx, projSpec)
+	if err != nil && !errors.Is(err, models.ErrUnsupportedSchedulerOperation) {
+		return nil, status.Errorf(codes.Internal, "%s: failed to list scheduler jobs of project %s", err.Error(),
+			req.GetProjectName())
+	}
+	if err == nil && !utils.ContainsString(deployedJobs, jobSpec.Name) {
+		return nil, status.Errorf(codes.FailedPrecondition, "job %s is not deployed to the scheduler", jobSpec.Name)
+	}
+
+	if req.GetDryRun() {
+		runs, err := sv.scheduler.GetDagRunStatus(ctx, projSpec, jobSpec.Name, logicalDate, logicalDate, 1)
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "%s: failed to parse job spec %s", err.Error(), resourceSpec.Name)
+			return nil, status.Errorf(codes.Internal, "%s: failed to fetch runs of job %s", err.Error(), jobSpec.Name)
 		}
-		resourceProtos = append(resourceProtos, resourceProto)
+		if len(runs) > 0 {
+			return nil, status.Errorf(codes.AlreadyExists, "%s: job %s has a run at %s", models.ErrJobRunExists.Error(),
+				jobSpec.Name, logicalDate.Format(time.RFC3339))
+		}
+		return &pb.TriggerJobRunResponse{
+			LogicalDate: timestamppb.New(logicalDate),
+			Message:     "run can be triggered",
+		}, nil
 	}
-	return &pb.ListResourceSpecificationResponse{
-		Resources: resourceProtos,
+
+	runID, err := sv.scheduler.TriggerJobRun(ctx, projSpec, jobSpec.Name, logicalDate, req.GetConfig())
+	if err != nil {
+		if errors.Is(err, models.ErrJobRunExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "%s: failed to trigger run of job %s", err.Error(), jobSpec.Name)
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to trigger run of job %s", err.Error(), jobSpec.Name)
+	}
+	if sv.JobRunTriggerRepo != nil {
+		if err := sv.JobRunTriggerRepo.Save(ctx, models.JobRunTrigger{
+			RunID:       runID,
+			ProjectName: projSpec.Name,
+			JobName:     jobSpec.Name,
+			LogicalDate: logicalDate,
+			Config:      req.GetConfig(),
+			Principal:   middleware.PrincipalFromContext(ctx),
+			TriggeredAt: sv.Now(),
+		}); err != nil {
+			// the run is started already, failing the call would have it
+			// triggered again on retries
+			logger.W(fmt.Sprintf("run %s of job %s triggered but not recorded: %s", runID, jobSpec.Name, err))
+		}
+	}
+	return &pb.TriggerJobRunResponse{
+		RunId:       runID,
+		LogicalDate: timestamppb.New(logicalDate),
+		Message:     "run triggered",
 	}, nil
 }
 
-func (sv *RuntimeServiceServer) ReplayDryRun(ctx context.Context, req *pb.ReplayRequest) (*pb.ReplayDryRunResponse, error) {
-	replayWorkerRequest, err := sv.parseReplayRequest(req)
+func (sv *RuntimeServiceServer) PauseJob(ctx context.Context, req *pb.PauseJobRequest) (*pb.PauseJobResponse, error) {
+	projSpec, jobSpec, err := sv.getProjectJob(req.GetProjectName(), req.GetJobName())
 	if err != nil {
 		return nil, err
 	}
+	if err := sv.setJobsPaused(ctx, projSpec, []string{jobSpec.Name}, true); err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to pause job %s", err.Error(), jobSpec.Name)
+	}
+	return &pb.PauseJobResponse{
+		Success: true,
+		Message: fmt.Sprintf("job %s paused", jobSpec.Name),
+	}, nil
+}
 
-	rootNode, err := sv.jobSvc.ReplayDryRun(replayWorkerRequest)
+func (sv *RuntimeServiceServer) ResumeJob(ctx context.Context, req *pb.ResumeJobRequest) (*pb.ResumeJobResponse, error) {
+	projSpec, jobSpec, err := sv.getProjectJob(req.GetProjectName(), req.GetJobName())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error while processing replay dry run: %v", err)
+		return nil, err
 	}
+	if err := sv.setJobsPaused(ctx, projSpec, []string{jobSpec.Name}, false); err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to resume job %s", err.Error(), jobSpec.Name)
+	}
+	return &pb.ResumeJobResponse{
+		Success: true,
+		Message: fmt.Sprintf("job %s resumed", jobSpec.Name),
+	}, nil
+}
 
-	node, err := sv.adapter.ToReplayExecutionTreeNode(rootNode)
+func (sv *RuntimeServiceServer) PauseProject(ctx context.Context, req *pb.PauseProjectRequest) (*pb.PauseProjectResponse, error) {
+	projSpec, jobNames, err := sv.getProjectJobNames(req.GetProjectName())
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error while preparing replay dry run response: %v", err)
+		return nil, err
 	}
-	return &pb.ReplayDryRunResponse{
-		Success:  true,
-		Response: node,
+	if err := sv.setJobsPaused(ctx, projSpec, jobNames, true); err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to pause jobs of project %s", err.Error(), projSpec.Name)
+	}
+	return &pb.PauseProjectResponse{
+		JobNames: jobNames,
+		Message:  fmt.Sprintf("%d jobs paused", len(jobNames)),
 	}, nil
 }
 
-func (sv *RuntimeServiceServer) Replay(ctx context.Context, req *pb.ReplayRequest) (*pb.ReplayResponse, error) {
-	replayWorkerRequest, err := sv.parseReplayRequest(req)
+func (sv *RuntimeServiceServer) ResumeProject(ctx context.Context, req *pb.ResumeProjectRequest) (*pb.ResumeProjectResponse, error) {
+	projSpec, jobNames, err := sv.getProjectJobNames(req.GetProjectName())
 	if err != nil {
 		return nil, err
 	}
+	if err := sv.setJobsPaused(ctx, projSpec, jobNames, false); err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: failed to resume jobs of project %s", err.Error(), projSpec.Name)
+	}
+	return &pb.ResumeProjectResponse{
+		JobNames: jobNames,
+		Message:  fmt.Sprintf("%d jobs resumed", len(jobNames)),
+	}, nil
+}
 
-	replayUUID, err := sv.jobSvc.Replay(ctx, replayWorkerRequest)
+func (sv *RuntimeServiceServer) getProjectJob(projectName, jobName string) (models.ProjectSpec, models.JobSpec, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(projectName)
 	if err != nil {
-		if errors.Is(err, job.ErrRequestQueueFull) {
-			return nil, status.Errorf(codes.Unavailable, "error while processing replay: %v", err)
-		} else if errors.Is(err, job.ErrConflictedJobRun) {
-			return nil, status.Errorf(codes.FailedPrecondition, "error while validating replay: %v", err)
+		return models.ProjectSpec{}, models.JobSpec{}, status.Errorf(codes.NotFound, "%s: project %s not found",
+			err.Error(), projectName)
+	}
+	jobSpec, _, err := sv.jobSvc.GetByNameForProject(jobName, projSpec)
+	if err != nil {
+		return models.ProjectSpec{}, models.JobSpec{}, status.Errorf(codes.NotFound, "%s: failed to find the job %s for project %s",
+			err.Error(), jobName, projectName)
+	}
+	return projSpec, jobSpec, nil
+}
+
+// getProjectJobNames returns names of the jobs in all namespaces of the project
+func (sv *RuntimeServiceServer) getProjectJobNames(projectName string) (models.ProjectSpec, []string, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(projectName)
+	if err != nil {
+		return models.ProjectSpec{}, nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), projectName)
+	}
+	namespaceSpecs, err := sv.namespaceRepoFactory.New(projSpec).GetAll()
+	if err != nil {
+		return models.ProjectSpec{}, nil, status.Errorf(codes.Internal, "%s: failed to list namespaces of project %s",
+			err.Error(), projectName)
+	}
+	jobNames := []string{}
+	for _, namespaceSpec := range namespaceSpecs {
+		jobSpecs, err := sv.jobSvc.GetAll(namespaceSpec)
+		if err != nil {
+			return models.ProjectSpec{}, nil, status.Errorf(codes.Internal, "%s: failed to list jobs of namespace %s",
+				err.Error(), namespaceSpec.Name)
+		}
+		for _, jobSpec := range jobSpecs {
+			jobNames = append(jobNames, jobSpec.Name)
 		}
-		return nil, status.Errorf(codes.Internal, "error while processing replay: %v", err)
 	}
+	return projSpec, jobNames, nil
+}
 
-	return &pb.ReplayResponse{
-		Id: replayUUID,
+// setJobsPaused changes the paused state of the jobs on the scheduler and
+// records it, only jobs in the other state are changed. Jobs already changed
+// are changed back to their prior state if any of them fails so either all
+// jobs end up changed or none
+func (sv *RuntimeServiceServer) setJobsPaused(ctx context.Context, projSpec models.ProjectSpec, jobNames []string,
+	paused bool) error {
+	// jobs are assumed to be in the other state if states aren't recorded
+	wasPaused := map[string]bool{}
+	for _, jobName := range jobNames {
+		wasPaused[jobName] = !paused
+	}
+	if sv.JobPauseRepo != nil {
+		pausedJobs, err := sv.JobPauseRepo.GetPaused(ctx, projSpec.Name)
+		if err != nil {
+			return errors.Wrap(err, "failed to read paused jobs")
+		}
+		pausedSet := map[string]bool{}
+		for _, jobName := range pausedJobs {
+			pausedSet[jobName] = true
+		}
+		for _, jobName := range jobNames {
+			wasPaused[jobName] = pausedSet[jobName]
+		}
+	}
+
+	var changed []string
+	revert := func() {
+		for _, jobName := range changed {
+			if err := sv.scheduler.SetJobPaused(ctx, projSpec, jobName, wasPaused[jobName]); err != nil {
+				logger.W(fmt.Sprintf("failed to revert paused state of job %s: %s", jobName, err))
+			}
+		}
+	}
+	for _, jobName := range jobNames {
+		if wasPaused[jobName] == paused {
+			continue
+		}
+		if err := sv.scheduler.SetJobPaused(ctx, projSpec, jobName, paused); err != nil {
+			revert()
+			return errors.Wrapf(err, "job %s", jobName)
+		}
+		changed = append(changed, jobName)
+	}
+	if sv.JobPauseRepo != nil && len(changed) > 0 {
+		if err := sv.JobPauseRepo.Save(ctx, projSpec.Name, changed, paused, middleware.PrincipalFromContext(ctx),
+			sv.Now()); err != nil {
+			revert()
+			return err
+		}
+	}
+	return nil
+}
+
+func (sv *RuntimeServiceServer) DiffJobSpec(ctx context.Context, req *pb.DiffJobSpecRequest) (*pb.DiffJobSpecResponse, error) {
+	fromVersion, err := parseJobSpecVersion(req.GetFromVersion())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: invalid from version", err.Error())
+	}
+	toVersion, err := parseJobSpecVersion(req.GetToVersion())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: invalid to version", err.Error())
+	}
+
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	diff, err := sv.jobSvc.DiffJobSpec(projSpec, req.GetJobName(), fromVersion, toVersion)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, status.Errorf(codes.NotFound, "%s: failed to find the versions of job %s", err.Error(), req.GetJobName())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to diff job %s", err.Error(), req.GetJobName())
+	}
+
+	var hooks []*pb.JobSpecHookDiff
+	for _, hook := range diff.Hooks {
+		hooks = append(hooks, &pb.JobSpecHookDiff{
+			Name:   hook.Name,
+			Type:   adaptJobSpecChangeType(hook.Type),
+			Config: adaptJobSpecChanges(hook.Config),
+		})
+	}
+	return &pb.DiffJobSpecResponse{
+		Metadata: adaptJobSpecChanges(diff.Metadata),
+		Schedule: adaptJobSpecChanges(diff.Schedule),
+		Task:     adaptJobSpecChanges(diff.Task),
+		Config:   adaptJobSpecChanges(diff.Config),
+		Assets:   adaptJobSpecChanges(diff.Assets),
+		Hooks:    hooks,
+		Behavior: adaptJobSpecChanges(diff.Behavior),
+	}, nil
+}
+
+// parseJobSpecVersion reads a version number or "current" for the
+// registered spec
+func parseJobSpecVersion(version string) (int, error) {
+	if version == "current" {
+		return models.JobSpecVersionCurrent, nil
+	}
+	number, err := strconv.Atoi(version)
+	if err != nil || number < 1 {
+		return 0, errors.Errorf("version %q is neither a version number nor current", version)
+	}
+	return number, nil
+}
+
+func adaptJobSpecChanges(changes []models.JobSpecChange) []*pb.JobSpecChange {
+	var adapted []*pb.JobSpecChange
+	for _, change := range changes {
+		adapted = append(adapted, &pb.JobSpecChange{
+			Name: change.Name,
+			Type: adaptJobSpecChangeType(change.Type),
+			From: change.From,
+			To:   change.To,
+		})
+	}
+	return adapted
+}
+
+func adaptJobSpecChangeType(changeType models.JobSpecChangeType) pb.JobSpecChange_Type {
+	switch changeType {
+	case models.JobSpecChangeAdded:
+		return pb.JobSpecChange_ADDED
+	case models.JobSpecChangeRemoved:
+		return pb.JobSpecChange_REMOVED
+	case models.JobSpecChangeModified:
+		return pb.JobSpecChange_MODIFIED
+	}
+	return pb.JobSpecChange_UNKNOWN
+}
+
+func (sv *RuntimeServiceServer) ListJobsByOwner(ctx context.Context, req *pb.ListJobsByOwnerRequest) (*pb.ListJobsByOwnerResponse, error) {
+	if req.GetOwnerEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "owner email is required")
+	}
+
+	jobSpecs, err := sv.jobSvc.GetJobsByOwner(ctx, req.GetOwnerEmail())
+	if err != nil {
+		if errors.Is(err, job.ErrCrossProjectListingUnsupported) {
+			return nil, status.Error(codes.Unimplemented, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to list jobs of owner %s", err.Error(), req.GetOwnerEmail())
+	}
+
+	var jobs []*pb.OwnedJobSpecification
+	for _, jobSpec := range jobSpecs {
+		jobProto, err := sv.adapter.ToJobProto(jobSpec.Spec)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "%s: failed to parse job spec %s", err.Error(), jobSpec.Spec.Name)
+		}
+		jobs = append(jobs, &pb.OwnedJobSpecification{
+			ProjectName: jobSpec.Namespace.ProjectSpec.Name,
+			Namespace:   jobSpec.Namespace.Name,
+			Job:         jobProto,
+		})
+	}
+	return &pb.ListJobsByOwnerResponse{
+		Jobs: jobs,
+	}, nil
+}
+
+func (sv *RuntimeServiceServer) ReconcileJobInstances(ctx context.Context, req *pb.ReconcileJobInstancesRequest) (*pb.ReconcileJobInstancesResponse, error) {
+	projectRepo := sv.projectRepoFactory.New()
+	projSpec, err := projectRepo.GetByName(req.GetProjectName())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
+	}
+
+	reconciled, err := sv.instSvc.ReconcileDeadInstances(ctx, projSpec)
+	if err != nil {
+		if errors.Is(err, instance.ErrReconcileUnsupported) {
+			return nil, status.Error(codes.Unimplemented, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to reconcile instances of project %s, %d reconciled",
+			err.Error(), req.GetProjectName(), reconciled)
+	}
+	return &pb.ReconcileJobInstancesResponse{
+		ReconciledCount: int32(reconciled),
 	}, nil
 }
 
@@ -858,6 +2717,7 @@ func NewRuntimeServiceServer(
 	progressObserver progress.Observer,
 	instSvc models.InstanceService,
 	scheduler models.SchedulerUnit,
+	projectHealthSvc ProjectHealthService,
 ) *RuntimeServiceServer {
 	return &RuntimeServiceServer{
 		version:              version,
@@ -871,12 +2731,18 @@ func NewRuntimeServiceServer(
 		instSvc:              instSvc,
 		scheduler:            scheduler,
 		secretRepoFactory:    secretRepoFactory,
+		projectHealthSvc:     projectHealthSvc,
+		Now:                  time.Now,
+		AuditLog:             logrus.New(),
 	}
 }
 
+// jobSyncObserver streams the progress of a deployment, jobs are compiled
+// and uploaded concurrently so sends are serialized
 type jobSyncObserver struct {
 	stream pb.RuntimeService_DeployJobSpecificationServer
 	log    logrus.FieldLogger
+	mu     sync.Mutex
 }
 
 func (obs *jobSyncObserver) Notify(e progress.Event) {
@@ -891,26 +2757,41 @@ func (obs *jobSyncObserver) Notify(e progress.Event) {
 			resp.Success = false
 			resp.Message = evt.Err.Error()
 		}
-
-		if err := obs.stream.Send(resp); err != nil {
-			obs.log.Error(errors.Wrapf(err, "failed to send deploy spec ack for: %s", evt.Job.Name))
-		}
+		obs.send(resp, "deploy spec ack for: "+evt.Job.Name)
+	case *job.EventJobSpecCompile:
+		obs.send(&pb.DeployJobSpecificationResponse{
+			JobName: evt.Name,
+			Message: evt.String(),
+		}, "compile notification for: "+evt.Name)
+	case *job.EventJobCompileWarning:
+		obs.send(&pb.DeployJobSpecificationResponse{
+			JobName: evt.Name,
+			Message: evt.String(),
+		}, "compile warning for: "+evt.Name)
+	case *job.EventJobSpecFetch, *job.EventJobSpecDependencyResolve, *job.EventJobPriorityWeightAssign,
+		*job.EventDeployedJobsListFailed:
+		// stages of the whole deployment, not of a single job
+		obs.send(&pb.DeployJobSpecificationResponse{
+			Message: evt.String(),
+		}, "progress notification: "+evt.String())
 	case *job.EventJobRemoteDelete:
-		resp := &pb.DeployJobSpecificationResponse{
+		obs.send(&pb.DeployJobSpecificationResponse{
 			JobName: evt.Name,
 			Message: evt.String(),
-		}
-		if err := obs.stream.Send(resp); err != nil {
-			obs.log.Error(errors.Wrapf(err, "failed to send delete notification for: %s", evt.Name))
-		}
+		}, "delete notification for: "+evt.Name)
 	case *job.EventJobSpecUnknownDependencyUsed:
-		resp := &pb.DeployJobSpecificationResponse{
+		obs.send(&pb.DeployJobSpecificationResponse{
 			JobName: evt.Job,
 			Message: evt.String(),
-		}
-		if err := obs.stream.Send(resp); err != nil {
-			obs.log.Error(errors.Wrapf(err, "failed to send unknown dependency notification for: %s", evt.Job))
-		}
+		}, "unknown dependency notification for: "+evt.Job)
+	}
+}
+
+func (obs *jobSyncObserver) send(resp *pb.DeployJobSpecificationResponse, what string) {
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	if err := obs.stream.Send(resp); err != nil {
+		obs.log.Error(errors.Wrapf(err, "failed to send %s", what))
 	}
 }
 
@@ -964,5 +2845,15 @@ func (obs *jobCheckObserver) Notify(e progress.Event) {
 		if err := obs.stream.Send(resp); err != nil {
 			obs.log.Error(errors.Wrapf(err, "failed to send check ack for: %s", evt.Name))
 		}
+	case *job.EventJobCompileWarning:
+		resp := &pb.CheckJobSpecificationsResponse{
+			Success: true,
+			Ack:     false,
+			JobName: evt.Name,
+			Message: evt.String(),
+		}
+		if err := obs.stream.Send(resp); err != nil {
+			obs.log.Error(errors.Wrapf(err, "failed to send check warning for: %s", evt.Name))
+		}
 	}
 }
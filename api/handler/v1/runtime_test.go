@@ -5,12 +5,17 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"google.golang.org/protobuf/types/known/structpb"
@@ -20,17 +25,21 @@ import (
 
 	"github.com/odpf/optimus/instance"
 
+	"github.com/golang-jwt/jwt"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/uuid"
 	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/api/middleware"
 	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
 	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/core/progress"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	mock2 "github.com/stretchr/testify/mock"
 
 	"github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
 )
 
 func TestRuntimeServiceServer(t *testing.T) {
@@ -50,6 +59,7 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 			)
 			versionRequest := pb.VersionRequest{Client: Version}
 			resp, err := runtimeServiceServer.Version(context.Background(), &versionRequest)
@@ -59,6 +69,48 @@ func TestRuntimeServiceServer(t *testing.T) {
 		})
 	})
 
+	t.Run("NegotiateAPIVersion", func(t *testing.T) {
+		serverVersion := "1.0.1"
+		runtimeServiceServer := v1.NewRuntimeServiceServer(
+			serverVersion,
+			nil, nil, nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		)
+		minClientVersion := v1.MinClientVersion
+		v1.MinClientVersion = "0.2.0"
+		defer func() { v1.MinClientVersion = minClientVersion }()
+
+		t.Run("should accept client at or above minimum version", func(t *testing.T) {
+			resp, err := runtimeServiceServer.NegotiateAPIVersion(context.Background(), &pb.NegotiateAPIVersionRequest{ClientVersion: "0.2.0"})
+			assert.Nil(t, err)
+			assert.Equal(t, &pb.NegotiateAPIVersionResponse{
+				ServerVersion:    serverVersion,
+				ApiMajorVersion:  v1.APIMajorVersion,
+				MinClientVersion: "0.2.0",
+				Supported:        true,
+			}, resp)
+		})
+		t.Run("should reject client below minimum version", func(t *testing.T) {
+			resp, err := runtimeServiceServer.NegotiateAPIVersion(context.Background(), &pb.NegotiateAPIVersionRequest{ClientVersion: "0.1.9"})
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+			assert.Contains(t, err.Error(), "upgrade the client to 0.2.0")
+			assert.Nil(t, resp)
+		})
+		t.Run("should not verify development client builds", func(t *testing.T) {
+			resp, err := runtimeServiceServer.NegotiateAPIVersion(context.Background(), &pb.NegotiateAPIVersionRequest{ClientVersion: "dev"})
+			assert.Nil(t, err)
+			assert.False(t, resp.Supported)
+			assert.Equal(t, serverVersion, resp.ServerVersion)
+		})
+	})
+
 	t.Run("RegisterInstance", func(t *testing.T) {
 		t.Run("should register a job instance", func(t *testing.T) {
 			Version := "1.0.1"
@@ -175,6 +227,7 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				instanceService,
 				nil,
+				nil,
 			)
 
 			versionRequest := pb.RegisterInstanceRequest{ProjectName: projectName, JobName: jobName,
@@ -222,6 +275,7 @@ func TestRuntimeServiceServer(t *testing.T) {
 			adapter := v1.NewAdapter(nil, nil)
 
 			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(models.ProjectSpec{}, store.ErrResourceNotFound)
 			projectRepository.On("Save", projectSpec).Return(errors.New("a random error"))
 			defer projectRepository.AssertExpectations(t)
 
@@ -243,6 +297,7 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 			)
 
 			projectRequest := pb.RegisterProjectRequest{Project: adapter.ToProjectProto(projectSpec)}
@@ -250,7 +305,71 @@ func TestRuntimeServiceServer(t *testing.T) {
 			assert.Equal(t, "rpc error: code = Internal desc = a random error: failed to save project a-data-project", err.Error())
 			assert.Nil(t, resp)
 		})
-		t.Run("should register a project without a namespace", func(t *testing.T) {
+		t.Run("should return error if a webhook is invalid", func(t *testing.T) {
+			projectSpec := models.ProjectSpec{
+				Name: "a-data-project",
+				Webhooks: []models.WebhookConfig{
+					{URL: "https://hooks.example.io/optimus", SecretName: "WEBHOOK_SECRET", Events: []string{"job_started"}},
+				},
+			}
+			adapter := v1.NewAdapter(nil, nil)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(new(mock.ProjectRepository))
+			defer projectRepoFactory.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"someVersion1.0",
+				nil, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			projectRequest := pb.RegisterProjectRequest{Project: adapter.ToProjectProto(projectSpec)}
+			resp, err := runtimeServiceServer.RegisterProject(context.Background(), &projectRequest)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Contains(t, err.Error(), `unknown webhook event "job_started"`)
+			assert.Nil(t, resp)
+		})
+		t.Run("should return error if a blackout window is invalid", func(t *testing.T) {
+			projectSpec := models.ProjectSpec{
+				Name: "a-data-project",
+				BlackoutWindows: []models.BlackoutWindow{
+					{Start: "0 2 * * 0"},
+				},
+			}
+			adapter := v1.NewAdapter(nil, nil)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(new(mock.ProjectRepository))
+			defer projectRepoFactory.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"someVersion1.0",
+				nil, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			projectRequest := pb.RegisterProjectRequest{Project: adapter.ToProjectProto(projectSpec)}
+			resp, err := runtimeServiceServer.RegisterProject(context.Background(), &projectRequest)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Contains(t, err.Error(), `duration of blackout window "0 2 * * 0" should be positive`)
+			assert.Nil(t, resp)
+		})
+		t.Run("should register a new project without a namespace with its default namespace", func(t *testing.T) {
 			projectName := "a-data-project"
 
 			projectSpec := models.ProjectSpec{
@@ -262,13 +381,26 @@ func TestRuntimeServiceServer(t *testing.T) {
 			adapter := v1.NewAdapter(nil, nil)
 
 			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(models.ProjectSpec{}, store.ErrResourceNotFound).Once()
 			projectRepository.On("Save", projectSpec).Return(nil)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil).Once()
 			defer projectRepository.AssertExpectations(t)
 
 			projectRepoFactory := new(mock.ProjectRepoFactory)
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("Save", models.NamespaceSpec{
+				Name:   models.DefaultNamespaceName,
+				Config: map[string]string{},
+			}).Return(nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
 			jobService := new(mock.JobService)
 			defer jobService.AssertExpectations(t)
 
@@ -276,13 +408,13 @@ func TestRuntimeServiceServer(t *testing.T) {
 				"someVersion1.0",
 				jobService, nil, nil,
 				projectRepoFactory,
-
-				nil,
+				namespaceRepoFact,
 				nil,
 				v1.NewAdapter(nil, nil),
 				nil,
 				nil,
 				nil,
+				nil,
 			)
 
 			projectRequest := pb.RegisterProjectRequest{Project: adapter.ToProjectProto(projectSpec)}
@@ -341,6 +473,7 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 			)
 
 			projectRequest := pb.RegisterProjectRequest{
@@ -354,6 +487,176 @@ func TestRuntimeServiceServer(t *testing.T) {
 				Message: "saved successfully",
 			}, resp)
 		})
+		t.Run("should register a new project with the default namespace of the request", func(t *testing.T) {
+			projectSpec := models.ProjectSpec{
+				Name:   "a-data-project",
+				Config: map[string]string{},
+			}
+			namespaceSpec := models.NamespaceSpec{
+				Name:   models.DefaultNamespaceName,
+				Config: map[string]string{"BUCKET": "gs://some_folder"},
+			}
+			adapter := v1.NewAdapter(nil, nil)
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(models.ProjectSpec{}, store.ErrResourceNotFound).Once()
+			projectRepository.On("Save", projectSpec).Return(nil)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil).Once()
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("Save", namespaceSpec).Return(nil).Once()
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer("someVersion1.0", nil, nil, nil, projectRepoFactory,
+				namespaceRepoFact, nil, adapter, nil, nil, nil, nil)
+
+			resp, err := runtimeServiceServer.RegisterProject(context.Background(), &pb.RegisterProjectRequest{
+				Project:   adapter.ToProjectProto(projectSpec),
+				Namespace: adapter.ToNamespaceProto(namespaceSpec),
+			})
+			assert.Nil(t, err)
+			assert.True(t, resp.GetSuccess())
+		})
+		t.Run("should return error if the default namespace of a new project can't be saved", func(t *testing.T) {
+			projectSpec := models.ProjectSpec{
+				Name:   "a-data-project",
+				Config: map[string]string{},
+			}
+			adapter := v1.NewAdapter(nil, nil)
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(models.ProjectSpec{}, store.ErrResourceNotFound).Once()
+			projectRepository.On("Save", projectSpec).Return(nil)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil).Once()
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("Save", mock2.Anything).Return(errors.New("connection refused"))
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer("someVersion1.0", nil, nil, nil, projectRepoFactory,
+				namespaceRepoFact, nil, adapter, nil, nil, nil, nil)
+
+			_, err := runtimeServiceServer.RegisterProject(context.Background(), &pb.RegisterProjectRequest{
+				Project: adapter.ToProjectProto(projectSpec),
+			})
+			assert.Equal(t, codes.Internal, status.Code(err))
+			assert.Contains(t, err.Error(), "failed to save default namespace of project a-data-project")
+		})
+		t.Run("should deploy jobs of all namespaces again when blackout windows change", func(t *testing.T) {
+			previousSpec := models.ProjectSpec{
+				Name:   "a-data-project",
+				Config: map[string]string{},
+				BlackoutWindows: []models.BlackoutWindow{
+					{Start: "0 2 * * 0", Duration: time.Hour},
+				},
+			}
+			projectSpec := previousSpec
+			projectSpec.BlackoutWindows = []models.BlackoutWindow{
+				{Start: "0 2 * * 0", Duration: time.Hour * 2},
+			}
+			namespaceSpecs := []models.NamespaceSpec{
+				{Name: "dev-test-namespace-1", ProjectSpec: projectSpec},
+				{Name: "dev-test-namespace-2", ProjectSpec: projectSpec},
+			}
+			adapter := v1.NewAdapter(nil, nil)
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(previousSpec, nil).Once()
+			projectRepository.On("Save", projectSpec).Return(nil)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil).Once()
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetAll").Return(namespaceSpecs, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
+			jobSvc := new(mock.JobService)
+			jobSvc.On("Sync", mock2.Anything, namespaceSpecs[0], mock2.Anything).Return(nil)
+			jobSvc.On("Sync", mock2.Anything, namespaceSpecs[1], mock2.Anything).Return(nil)
+			defer jobSvc.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"someVersion1.0",
+				jobSvc, nil, nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			projectRequest := pb.RegisterProjectRequest{Project: adapter.ToProjectProto(projectSpec)}
+			resp, err := runtimeServiceServer.RegisterProject(context.Background(), &projectRequest)
+			assert.Nil(t, err)
+			assert.True(t, resp.GetSuccess())
+		})
+		t.Run("should not deploy jobs again when blackout windows are unchanged", func(t *testing.T) {
+			projectSpec := models.ProjectSpec{
+				Name:   "a-data-project",
+				Config: map[string]string{"BUCKET": "gs://some_folder"},
+				BlackoutWindows: []models.BlackoutWindow{
+					{Start: "0 2 * * 0", Duration: time.Hour},
+				},
+			}
+			previousSpec := projectSpec
+			previousSpec.Config = map[string]string{"BUCKET": "gs://other_folder"}
+			adapter := v1.NewAdapter(nil, nil)
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(previousSpec, nil)
+			projectRepository.On("Save", projectSpec).Return(nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobSvc := new(mock.JobService)
+			defer jobSvc.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"someVersion1.0",
+				jobSvc, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			projectRequest := pb.RegisterProjectRequest{Project: adapter.ToProjectProto(projectSpec)}
+			resp, err := runtimeServiceServer.RegisterProject(context.Background(), &projectRequest)
+			assert.Nil(t, err)
+			assert.True(t, resp.GetSuccess())
+		})
 	})
 
 	t.Run("RegisterProjectNamespace", func(t *testing.T) {
@@ -404,6 +707,7 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 			)
 
 			namespaceRequest := pb.RegisterProjectNamespaceRequest{
@@ -461,6 +765,7 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 			)
 
 			namespaceRequest := pb.RegisterProjectNamespaceRequest{
@@ -544,6 +849,8 @@ func TestRuntimeServiceServer(t *testing.T) {
 			jobSvc.On("Create", jobSpec, namespaceSpec).Return(nil)
 			jobSvc.On("Check", namespaceSpec, []models.JobSpec{jobSpec}, mock2.Anything).Return(nil)
 			jobSvc.On("Sync", mock2.Anything, namespaceSpec, mock2.Anything).Return(nil)
+			jobSvc.On("DetectSchemaDrift", mock2.Anything, projectSpec, jobSpec).Return(nil, job.ErrSchemaDriftUnsupported)
+			jobSvc.On("ValidateAssetReferences", mock2.Anything, projectSpec, jobSpec).Return(nil)
 			defer jobSvc.AssertExpectations(t)
 
 			namespaceRepository := new(mock.NamespaceRepository)
@@ -565,6 +872,7 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 			)
 
 			jobProto, _ := adapter.ToJobProto(jobSpec)
@@ -580,10 +888,7 @@ func TestRuntimeServiceServer(t *testing.T) {
 				Message: "job my-job is created and deployed successfully on project a-data-project",
 			}, resp)
 		})
-	})
-
-	t.Run("RegisterSecret", func(t *testing.T) {
-		t.Run("should register a secret successfully", func(t *testing.T) {
+		t.Run("should warn about schema drift of the job destination", func(t *testing.T) {
 			projectName := "a-data-project"
 
 			projectSpec := models.ProjectSpec{
@@ -592,7 +897,54 @@ func TestRuntimeServiceServer(t *testing.T) {
 					"BUCKET": "gs://some_folder",
 				},
 			}
-			adapter := v1.NewAdapter(nil, nil)
+
+			namespaceSpec := models.NamespaceSpec{
+				Name:        "dev-test-namespace-1",
+				ProjectSpec: projectSpec,
+			}
+
+			jobName := "my-job"
+			taskName := "bq2bq"
+			execUnit1 := new(mock.BasePlugin)
+			execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
+				Name:  taskName,
+				Image: "random-image",
+			}, nil)
+			defer execUnit1.AssertExpectations(t)
+
+			pluginRepo := new(mock.SupportedPluginRepo)
+			pluginRepo.On("GetByName", taskName).Return(&models.Plugin{
+				Base: execUnit1,
+			}, nil)
+			adapter := v1.NewAdapter(pluginRepo, nil)
+
+			jobSpec := models.JobSpec{
+				Name: jobName,
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{
+						Base: execUnit1,
+					},
+					Config: models.JobSpecConfigs{
+						{
+							Name:  "DO",
+							Value: "THIS",
+						},
+					},
+					Window: models.JobSpecTaskWindow{
+						Size:       time.Hour,
+						Offset:     0,
+						TruncateTo: "d",
+					},
+				},
+				Assets: *models.JobAssets{}.New(
+					[]models.JobSpecAsset{
+						{
+							Name:  "query.sql",
+							Value: "select * from 1",
+						},
+					}),
+				Dependencies: map[string]models.JobSpecDependency{},
+			}
 
 			projectRepository := new(mock.ProjectRepository)
 			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
@@ -602,8 +954,293 @@ func TestRuntimeServiceServer(t *testing.T) {
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
-			sec := models.ProjectSecretItem{
-				Name:  "hello",
+			jobSvc := new(mock.JobService)
+			jobSvc.On("Create", jobSpec, namespaceSpec).Return(nil)
+			jobSvc.On("Check", namespaceSpec, []models.JobSpec{jobSpec}, mock2.Anything).Return(nil)
+			jobSvc.On("Sync", mock2.Anything, namespaceSpec, mock2.Anything).Return(nil)
+			jobSvc.On("DetectSchemaDrift", mock2.Anything, projectSpec, jobSpec).Return(&models.SchemaDrift{
+				Destination:    "project:dataset.table",
+				AddedColumns:   []models.SchemaField{{Name: "name", Type: "STRING"}},
+				RemovedColumns: []models.SchemaField{{Name: "code", Type: "STRING"}},
+				TypeChanges:    []models.SchemaTypeChange{{Name: "amount", CurrentType: "INTEGER", ExpectedType: "FLOAT"}},
+			}, nil)
+			jobSvc.On("ValidateAssetReferences", mock2.Anything, projectSpec, jobSpec).Return([]models.AssetWarning{
+				{Asset: "query.sql", Table: "project.dataset.missing", Reason: "table doesn't exist"},
+			})
+			defer jobSvc.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"someVersion1.0",
+				jobSvc,
+				nil, nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			jobProto, _ := adapter.ToJobProto(jobSpec)
+			request := pb.CreateJobSpecificationRequest{
+				ProjectName: projectName,
+				Namespace:   namespaceSpec.Name,
+				Spec:        jobProto,
+			}
+			resp, err := runtimeServiceServer.CreateJobSpecification(context.Background(), &request)
+			assert.Nil(t, err)
+			assert.Equal(t, &pb.CreateJobSpecificationResponse{
+				Success: true,
+				Message: "job my-job is created and deployed successfully on project a-data-project\n" +
+					"warning: schema of project:dataset.table has drifted from the job query: name STRING is missing in the table, " +
+					"code STRING is no longer produced, amount is INTEGER in the table but FLOAT in the query\n" +
+					"warning: project.dataset.missing read by query.sql: table doesn't exist",
+			}, resp)
+		})
+	})
+
+	t.Run("BatchCreateJobSpecifications", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			Name: "a-data-project",
+		}
+		namespaceSpec := models.NamespaceSpec{
+			Name:        "dev-test-namespace-1",
+			ProjectSpec: projectSpec,
+		}
+		execUnit := new(mock.BasePlugin)
+		execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name:  "bq2bq",
+			Image: "random-image",
+		}, nil)
+		pluginRepo := new(mock.SupportedPluginRepo)
+		pluginRepo.On("GetByName", "bq2bq").Return(&models.Plugin{
+			Base: execUnit,
+		}, nil)
+		adapter := v1.NewAdapter(pluginRepo, nil)
+
+		newJobSpec := func(name string) models.JobSpec {
+			return models.JobSpec{
+				Name: name,
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{
+						Base: execUnit,
+					},
+					Config: models.JobSpecConfigs{},
+					Window: models.JobSpecTaskWindow{
+						Size:       time.Hour,
+						TruncateTo: "d",
+					},
+				},
+				Assets:       *models.JobAssets{}.New([]models.JobSpecAsset{{Name: "query.sql", Value: "select * from 1"}}),
+				Dependencies: map[string]models.JobSpecDependency{},
+			}
+		}
+		newRequest := func(allowPartialSuccess bool, specs ...models.JobSpec) *pb.BatchCreateJobSpecificationsRequest {
+			req := &pb.BatchCreateJobSpecificationsRequest{
+				ProjectName:         projectSpec.Name,
+				Namespace:           namespaceSpec.Name,
+				AllowPartialSuccess: allowPartialSuccess,
+			}
+			for _, spec := range specs {
+				specProto, err := adapter.ToJobProto(spec)
+				assert.Nil(t, err)
+				req.Specs = append(req.Specs, specProto)
+			}
+			return req
+		}
+		newServer := func(t *testing.T, jobService models.JobService) *v1.RuntimeServiceServer {
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			return newTestServer(t, testServerDeps{
+				jobService:     jobService,
+				adapter:        adapter,
+				projects:       []models.ProjectSpec{projectSpec},
+				namespaceRepos: map[string]*mock.NamespaceRepository{projectSpec.Name: namespaceRepository},
+			})
+		}
+		ordersSpec, customersSpec := newJobSpec("orders"), newJobSpec("customers")
+
+		t.Run("should save all jobs at once and deploy them", func(t *testing.T) {
+			jobService := new(mock.JobService)
+			defer jobService.AssertExpectations(t)
+			for _, spec := range []models.JobSpec{ordersSpec, customersSpec} {
+				jobService.On("GetByNameForProject", spec.Name, projectSpec).Return(models.JobSpec{}, models.NamespaceSpec{}, store.ErrResourceNotFound)
+				jobService.On("Check", namespaceSpec, []models.JobSpec{spec}, mock2.Anything).Return(nil)
+			}
+			jobService.On("CreateAll", namespaceSpec, []models.JobSpec{ordersSpec, customersSpec}).Return(nil)
+			jobService.On("Sync", mock2.Anything, namespaceSpec, mock2.Anything).Return(nil)
+
+			resp, err := newServer(t, jobService).BatchCreateJobSpecifications(context.Background(), newRequest(false, ordersSpec, customersSpec))
+			assert.Nil(t, err)
+			assert.Equal(t, &pb.BatchCreateJobSpecificationsResponse{
+				Success: true,
+				Message: "2 of 2 jobs are created and deployed successfully on project a-data-project",
+				Statuses: []*pb.JobSpecificationStatus{
+					{JobName: "orders", Success: true},
+					{JobName: "customers", Success: true},
+				},
+			}, resp)
+		})
+		t.Run("should reject the batch without saving if a spec fails", func(t *testing.T) {
+			jobService := new(mock.JobService)
+			defer jobService.AssertExpectations(t)
+			jobService.On("GetByNameForProject", "orders", projectSpec).Return(models.JobSpec{}, models.NamespaceSpec{}, store.ErrResourceNotFound)
+			jobService.On("Check", namespaceSpec, []models.JobSpec{ordersSpec}, mock2.Anything).Return(nil)
+			jobService.On("GetByNameForProject", "customers", projectSpec).Return(customersSpec, namespaceSpec, nil)
+
+			_, err := newServer(t, jobService).BatchCreateJobSpecifications(context.Background(), newRequest(false, ordersSpec, customersSpec))
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Contains(t, err.Error(), "customers: job is already registered")
+		})
+		t.Run("should save valid specs and report failed ones if partial success is allowed", func(t *testing.T) {
+			jobService := new(mock.JobService)
+			defer jobService.AssertExpectations(t)
+			jobService.On("GetByNameForProject", "orders", projectSpec).Return(models.JobSpec{}, models.NamespaceSpec{}, store.ErrResourceNotFound)
+			jobService.On("Check", namespaceSpec, []models.JobSpec{ordersSpec}, mock2.Anything).Return(errors.New("invalid window"))
+			jobService.On("GetByNameForProject", "customers", projectSpec).Return(models.JobSpec{}, models.NamespaceSpec{}, store.ErrResourceNotFound)
+			jobService.On("Check", namespaceSpec, []models.JobSpec{customersSpec}, mock2.Anything).Return(nil)
+			jobService.On("CreateAll", namespaceSpec, []models.JobSpec{customersSpec}).Return(nil)
+			jobService.On("Sync", mock2.Anything, namespaceSpec, mock2.Anything).Return(nil)
+
+			resp, err := newServer(t, jobService).BatchCreateJobSpecifications(context.Background(), newRequest(true, ordersSpec, customersSpec, customersSpec))
+			assert.Nil(t, err)
+			assert.Equal(t, &pb.BatchCreateJobSpecificationsResponse{
+				Success: false,
+				Message: "1 of 3 jobs are created and deployed successfully on project a-data-project",
+				Statuses: []*pb.JobSpecificationStatus{
+					{JobName: "orders", Message: "spec validation failed: invalid window"},
+					{JobName: "customers", Success: true},
+					{JobName: "customers", Message: "job is specified more than once"},
+				},
+			}, resp)
+		})
+		t.Run("should fail with resource exhausted if the jobs exceed the quota of the project", func(t *testing.T) {
+			jobService := new(mock.JobService)
+			defer jobService.AssertExpectations(t)
+			jobService.On("GetByNameForProject", "orders", projectSpec).Return(models.JobSpec{}, models.NamespaceSpec{}, store.ErrResourceNotFound)
+			jobService.On("Check", namespaceSpec, []models.JobSpec{ordersSpec}, mock2.Anything).Return(nil)
+			jobService.On("CreateAll", namespaceSpec, []models.JobSpec{ordersSpec}).
+				Return(errors.Wrap(models.ErrQuotaExceeded, "project a-data-project can't have more than 10 jobs"))
+
+			_, err := newServer(t, jobService).BatchCreateJobSpecifications(context.Background(), newRequest(false, ordersSpec))
+			assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		})
+	})
+
+	t.Run("RotateSecrets", func(t *testing.T) {
+		oldKey := "32charshtesthashtesthashtesthash"
+		newKey := "32charsnewhashnewhashnewhashnewh"
+		oldHash, _ := models.NewApplicationSecret(oldKey)
+		newHash, _ := models.NewApplicationSecret(newKey)
+		adminCtx := middleware.ContextWithClaims(context.Background(), jwt.MapClaims{
+			"sub":   "admin@example.io",
+			"roles": []interface{}{"ADMIN"},
+		})
+		newServer := func(rotator store.SecretRotator) *v1.RuntimeServiceServer {
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				nil,
+				nil, nil,
+				nil,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			runtimeServiceServer.SecretRotator = rotator
+			runtimeServiceServer.AppKey = newHash.WithPrevious(oldHash)
+			return runtimeServiceServer
+		}
+
+		t.Run("should rotate secrets from the old to the new key", func(t *testing.T) {
+			rotator := new(mock.SecretRotator)
+			rotator.On("RotateSecrets", oldHash, newHash).Return(nil)
+			defer rotator.AssertExpectations(t)
+
+			resp, err := newServer(rotator).RotateSecrets(adminCtx, &pb.RotateSecretsRequest{
+				OldAppKey: oldKey,
+				NewAppKey: newKey,
+			})
+			assert.Nil(t, err)
+			assert.True(t, resp.GetSuccess())
+		})
+		t.Run("should fail if secrets can't be decrypted with the old key", func(t *testing.T) {
+			rotator := new(mock.SecretRotator)
+			rotator.On("RotateSecrets", oldHash, newHash).Return(errors.Wrap(store.ErrSecretDecryption, "secrets proj/secret-1"))
+			defer rotator.AssertExpectations(t)
+
+			_, err := newServer(rotator).RotateSecrets(adminCtx, &pb.RotateSecretsRequest{
+				OldAppKey: oldKey,
+				NewAppKey: newKey,
+			})
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		})
+		t.Run("should fail for invalid keys", func(t *testing.T) {
+			_, err := newServer(new(mock.SecretRotator)).RotateSecrets(adminCtx, &pb.RotateSecretsRequest{
+				OldAppKey: oldKey,
+				NewAppKey: "too-short",
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+		t.Run("should fail if the keys aren't the ones the server runs with", func(t *testing.T) {
+			otherKey := "32charsotherhashotherhashotherha"
+			for name, req := range map[string]*pb.RotateSecretsRequest{
+				"other new key": {OldAppKey: oldKey, NewAppKey: otherKey},
+				"other old key": {OldAppKey: otherKey, NewAppKey: newKey},
+				"swapped keys":  {OldAppKey: newKey, NewAppKey: oldKey},
+			} {
+				rotator := new(mock.SecretRotator)
+				_, err := newServer(rotator).RotateSecrets(adminCtx, req)
+				assert.Equal(t, codes.FailedPrecondition, status.Code(err), name)
+				rotator.AssertNotCalled(t, "RotateSecrets", mock2.Anything, mock2.Anything)
+			}
+		})
+		t.Run("should fail if the server has no previous key", func(t *testing.T) {
+			runtimeServiceServer := newServer(new(mock.SecretRotator))
+			runtimeServiceServer.AppKey = newHash
+
+			_, err := runtimeServiceServer.RotateSecrets(adminCtx, &pb.RotateSecretsRequest{
+				OldAppKey: oldKey,
+				NewAppKey: newKey,
+			})
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		})
+	})
+	t.Run("RegisterSecret", func(t *testing.T) {
+		t.Run("should register a secret successfully", func(t *testing.T) {
+			projectName := "a-data-project"
+
+			projectSpec := models.ProjectSpec{
+				Name: projectName,
+				Config: map[string]string{
+					"BUCKET": "gs://some_folder",
+				},
+			}
+			adapter := v1.NewAdapter(nil, nil)
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			sec := models.ProjectSecretItem{
+				Name:  "hello",
 				Value: "world",
 			}
 
@@ -628,6 +1265,7 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 			)
 
 			secretRequest := pb.RegisterSecretRequest{
@@ -641,6 +1279,76 @@ func TestRuntimeServiceServer(t *testing.T) {
 				Success: true,
 			}, resp)
 		})
+		t.Run("should register a secret expiring after its ttl", func(t *testing.T) {
+			projectSpec := models.ProjectSpec{
+				Name: "a-data-project",
+			}
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+			expiresAt := now.Add(24 * time.Hour)
+			projectSecretRepository := new(mock.ProjectSecretRepository)
+			projectSecretRepository.On("Save", models.ProjectSecretItem{
+				Name:      "hello",
+				Value:     "world",
+				ExpiresAt: &expiresAt,
+			}).Return(nil)
+			defer projectSecretRepository.AssertExpectations(t)
+
+			projectSecretRepoFactory := new(mock.ProjectSecretRepoFactory)
+			projectSecretRepoFactory.On("New", projectSpec).Return(projectSecretRepository)
+			defer projectSecretRepoFactory.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"someVersion1.0",
+				nil, nil, nil,
+				projectRepoFactory,
+				nil,
+				projectSecretRepoFactory,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			runtimeServiceServer.Now = func() time.Time { return now }
+
+			resp, err := runtimeServiceServer.RegisterSecret(context.Background(), &pb.RegisterSecretRequest{
+				ProjectName: projectSpec.Name,
+				SecretName:  "hello",
+				Value:       base64.StdEncoding.EncodeToString([]byte("world")),
+				TtlSeconds:  86400,
+			})
+			assert.Nil(t, err)
+			assert.True(t, resp.GetSuccess())
+		})
+		t.Run("should fail to register a secret with a negative ttl", func(t *testing.T) {
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"someVersion1.0",
+				nil, nil, nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			_, err := runtimeServiceServer.RegisterSecret(context.Background(), &pb.RegisterSecretRequest{
+				ProjectName: "a-data-project",
+				SecretName:  "hello",
+				Value:       base64.StdEncoding.EncodeToString([]byte("world")),
+				TtlSeconds:  -1,
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
 		t.Run("should return error if saving to secret repository fails", func(t *testing.T) {
 			projectName := "a-data-project"
 
@@ -686,6 +1394,7 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 			)
 
 			secretRequest := pb.RegisterSecretRequest{
@@ -699,131 +1408,204 @@ func TestRuntimeServiceServer(t *testing.T) {
 		})
 	})
 
-	t.Run("DeployJobSpecification", func(t *testing.T) {
-		t.Run("should deploy the job", func(t *testing.T) {
-			Version := "1.0.1"
-
-			projectName := "a-data-project"
-			jobName1 := "a-data-job"
-			taskName := "a-data-task"
-
+	t.Run("ListSecrets", func(t *testing.T) {
+		t.Run("should list names of project secrets", func(t *testing.T) {
 			projectSpec := models.ProjectSpec{
-				ID:   uuid.Must(uuid.NewRandom()),
-				Name: projectName,
-				Config: map[string]string{
-					"bucket": "gs://some_folder",
-				},
-			}
-
-			namespaceSpec := models.NamespaceSpec{
-				ID:   uuid.Must(uuid.NewRandom()),
-				Name: "dev-test-namespace-1",
-				Config: map[string]string{
-					"bucket": "gs://some_folder",
-				},
-				ProjectSpec: projectSpec,
-			}
-
-			execUnit1 := new(mock.BasePlugin)
-			execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
-				Name: taskName,
-			}, nil)
-			defer execUnit1.AssertExpectations(t)
-
-			jobSpecs := []models.JobSpec{
-				{
-					Name: jobName1,
-					Task: models.JobSpecTask{
-						Unit: &models.Plugin{
-							Base: execUnit1,
-						},
-						Config: models.JobSpecConfigs{
-							{
-								Name:  "do",
-								Value: "this",
-							},
-						},
-					},
-					Assets: *models.JobAssets{}.New(
-						[]models.JobSpecAsset{
-							{
-								Name:  "query.sql",
-								Value: "select * from 1",
-							},
-						}),
-				},
+				Name: "a-data-project",
 			}
 
 			projectRepository := new(mock.ProjectRepository)
-			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
 			defer projectRepository.AssertExpectations(t)
 
 			projectRepoFactory := new(mock.ProjectRepoFactory)
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
-			jobSpecRepository := new(mock.JobSpecRepository)
-			defer jobSpecRepository.AssertExpectations(t)
+			projectSecretRepository := new(mock.ProjectSecretRepository)
+			projectSecretRepository.On("GetAll").Return([]models.ProjectSecretItem{
+				{Name: "world", Value: "hello"},
+				{Name: "hello", Value: "world"},
+			}, nil)
+			defer projectSecretRepository.AssertExpectations(t)
 
-			jobSpecRepoFactory := new(mock.JobSpecRepoFactory)
-			defer jobSpecRepoFactory.AssertExpectations(t)
+			projectSecretRepoFactory := new(mock.ProjectSecretRepoFactory)
+			projectSecretRepoFactory.On("New", projectSpec).Return(projectSecretRepository)
+			defer projectSecretRepoFactory.AssertExpectations(t)
 
-			pluginRepo := new(mock.SupportedPluginRepo)
-			pluginRepo.On("GetByName", taskName).Return(&models.Plugin{
-				Base: execUnit1,
-			}, nil)
-			adapter := v1.NewAdapter(pluginRepo, nil)
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"someVersion1.0",
+				nil, nil, nil,
+				projectRepoFactory,
+				nil,
+				projectSecretRepoFactory,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
 
-			namespaceRepository := new(mock.NamespaceRepository)
-			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
-			defer namespaceRepository.AssertExpectations(t)
+			resp, err := runtimeServiceServer.ListSecrets(context.Background(), &pb.ListSecretsRequest{
+				ProjectName: projectSpec.Name,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"hello", "world"}, resp.GetSecretNames())
+		})
+	})
 
-			namespaceRepoFact := new(mock.NamespaceRepoFactory)
-			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
-			defer namespaceRepoFact.AssertExpectations(t)
+	t.Run("RevealProjectSecret", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			Name: "a-data-project",
+		}
+		revealRequest := func(phrase string) *pb.RevealProjectSecretRequest {
+			return &pb.RevealProjectSecretRequest{
+				ProjectName:        projectSpec.Name,
+				SecretName:         "hello",
+				ConfirmationPhrase: phrase,
+			}
+		}
+		ctx := peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 51000},
+		})
 
-			projectJobSpecRepository := new(mock.ProjectJobSpecRepository)
-			defer projectJobSpecRepository.AssertExpectations(t)
+		t.Run("should reveal the secret and audit log it when the confirmation phrase matches", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
 
-			projectJobSpecRepoFactory := new(mock.ProjectJobSpecRepoFactory)
-			defer projectJobSpecRepoFactory.AssertExpectations(t)
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
 
-			jobService := new(mock.JobService)
-			jobService.On("Create", mock2.Anything, namespaceSpec).Return(nil)
-			jobService.On("KeepOnly", namespaceSpec, mock2.Anything, mock2.Anything).Return(nil)
-			jobService.On("Sync", mock2.Anything, namespaceSpec, mock2.Anything).Return(nil)
-			defer jobService.AssertExpectations(t)
+			projectSecretRepository := new(mock.ProjectSecretRepository)
+			projectSecretRepository.On("GetByName", "hello").Return(models.ProjectSecretItem{
+				Name:  "hello",
+				Value: "world",
+			}, nil)
+			defer projectSecretRepository.AssertExpectations(t)
 
-			grpcRespStream := new(mock.RuntimeService_DeployJobSpecificationServer)
-			grpcRespStream.On("Context").Return(context.Background())
-			defer grpcRespStream.AssertExpectations(t)
+			projectSecretRepoFactory := new(mock.ProjectSecretRepoFactory)
+			projectSecretRepoFactory.On("New", projectSpec).Return(projectSecretRepository)
+			defer projectSecretRepoFactory.AssertExpectations(t)
 
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				Version,
-				jobService,
-				nil, nil,
+				"someVersion1.0",
+				nil, nil, nil,
 				projectRepoFactory,
-				namespaceRepoFact,
 				nil,
-				adapter,
+				projectSecretRepoFactory,
+				nil,
+				nil,
 				nil,
 				nil,
 				nil,
 			)
+			auditLog, hook := logrustest.NewNullLogger()
+			runtimeServiceServer.AuditLog = auditLog
+			runtimeServiceServer.SecretRevealPassphrase = "reveal secrets of optimus"
 
-			jobSpecsAdapted := []*pb.JobSpecification{}
-			for _, jobSpec := range jobSpecs {
-				jobSpecAdapted, _ := adapter.ToJobProto(jobSpec)
-				jobSpecsAdapted = append(jobSpecsAdapted, jobSpecAdapted)
-			}
-			deployRequest := pb.DeployJobSpecificationRequest{ProjectName: projectName, Jobs: jobSpecsAdapted, Namespace: namespaceSpec.Name}
-			err := runtimeServiceServer.DeployJobSpecification(&deployRequest, grpcRespStream)
+			resp, err := runtimeServiceServer.RevealProjectSecret(ctx, revealRequest("reveal secrets of optimus"))
 			assert.Nil(t, err)
+			assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("world")), resp.GetValue())
+
+			assert.Len(t, hook.AllEntries(), 1)
+			assert.Equal(t, "secret revealed", hook.LastEntry().Message)
+			assert.Equal(t, logrus.Fields{
+				"event":     "secret_reveal",
+				"principal": "10.0.0.1",
+				"project":   "a-data-project",
+				"secret":    "hello",
+				"revealed":  true,
+			}, hook.LastEntry().Data)
+		})
+		t.Run("should fail to reveal an expired secret", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			projectSecretRepository := new(mock.ProjectSecretRepository)
+			projectSecretRepository.On("GetByName", "hello").Return(models.ProjectSecretItem{}, store.ErrSecretExpired)
+			defer projectSecretRepository.AssertExpectations(t)
+
+			projectSecretRepoFactory := new(mock.ProjectSecretRepoFactory)
+			projectSecretRepoFactory.On("New", projectSpec).Return(projectSecretRepository)
+			defer projectSecretRepoFactory.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"someVersion1.0",
+				nil, nil, nil,
+				projectRepoFactory,
+				nil,
+				projectSecretRepoFactory,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			runtimeServiceServer.AuditLog, _ = logrustest.NewNullLogger()
+			runtimeServiceServer.SecretRevealPassphrase = "reveal secrets of optimus"
+
+			_, err := runtimeServiceServer.RevealProjectSecret(ctx, revealRequest("reveal secrets of optimus"))
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		})
+		t.Run("should deny and audit log the reveal when the confirmation phrase doesn't match", func(t *testing.T) {
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"someVersion1.0",
+				nil, nil, nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			auditLog, hook := logrustest.NewNullLogger()
+			runtimeServiceServer.AuditLog = auditLog
+			runtimeServiceServer.SecretRevealPassphrase = "reveal secrets of optimus"
+
+			resp, err := runtimeServiceServer.RevealProjectSecret(ctx, revealRequest("reveal secrets"))
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+			assert.Len(t, hook.AllEntries(), 1)
+			assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+			assert.Equal(t, false, hook.LastEntry().Data["revealed"])
+			assert.Equal(t, "10.0.0.1", hook.LastEntry().Data["principal"])
+		})
+		t.Run("should deny the reveal when no passphrase is configured", func(t *testing.T) {
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"someVersion1.0",
+				nil, nil, nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			auditLog, hook := logrustest.NewNullLogger()
+			runtimeServiceServer.AuditLog = auditLog
+
+			resp, err := runtimeServiceServer.RevealProjectSecret(ctx, revealRequest(""))
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+			assert.Len(t, hook.AllEntries(), 1)
+			assert.Equal(t, false, hook.LastEntry().Data["revealed"])
 		})
 	})
 
-	t.Run("ReadJobSpecification", func(t *testing.T) {
-		t.Run("should read a job spec", func(t *testing.T) {
+	t.Run("DeployJobSpecification", func(t *testing.T) {
+		t.Run("should deploy the job", func(t *testing.T) {
 			Version := "1.0.1"
 
 			projectName := "a-data-project"
@@ -885,9 +1667,17 @@ func TestRuntimeServiceServer(t *testing.T) {
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
-			allTasksRepo := new(mock.SupportedPluginRepo)
-			allTasksRepo.On("GetByName", taskName).Return(execUnit1, nil)
-			adapter := v1.NewAdapter(allTasksRepo, nil)
+			jobSpecRepository := new(mock.JobSpecRepository)
+			defer jobSpecRepository.AssertExpectations(t)
+
+			jobSpecRepoFactory := new(mock.JobSpecRepoFactory)
+			defer jobSpecRepoFactory.AssertExpectations(t)
+
+			pluginRepo := new(mock.SupportedPluginRepo)
+			pluginRepo.On("GetByName", taskName).Return(&models.Plugin{
+				Base: execUnit1,
+			}, nil)
+			adapter := v1.NewAdapter(pluginRepo, nil)
 
 			namespaceRepository := new(mock.NamespaceRepository)
 			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
@@ -897,10 +1687,22 @@ func TestRuntimeServiceServer(t *testing.T) {
 			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
 			defer namespaceRepoFact.AssertExpectations(t)
 
+			projectJobSpecRepository := new(mock.ProjectJobSpecRepository)
+			defer projectJobSpecRepository.AssertExpectations(t)
+
+			projectJobSpecRepoFactory := new(mock.ProjectJobSpecRepoFactory)
+			defer projectJobSpecRepoFactory.AssertExpectations(t)
+
 			jobService := new(mock.JobService)
-			jobService.On("GetByName", jobSpecs[0].Name, namespaceSpec).Return(jobSpecs[0], nil)
+			jobService.On("SaveAll", namespaceSpec, mock2.Anything).Return(nil)
+			jobService.On("KeepOnly", namespaceSpec, mock2.Anything, mock2.Anything).Return(nil)
+			jobService.On("Sync", mock2.Anything, namespaceSpec, mock2.Anything).Return(nil)
 			defer jobService.AssertExpectations(t)
 
+			grpcRespStream := new(mock.RuntimeService_DeployJobSpecificationServer)
+			grpcRespStream.On("Context").Return(context.Background())
+			defer grpcRespStream.AssertExpectations(t)
+
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
 				Version,
 				jobService,
@@ -912,39 +1714,48 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 			)
 
-			jobSpecAdapted, _ := adapter.ToJobProto(jobSpecs[0])
-			deployRequest := pb.ReadJobSpecificationRequest{ProjectName: projectName, JobName: jobSpecs[0].Name, Namespace: namespaceSpec.Name}
-			jobSpecResp, err := runtimeServiceServer.ReadJobSpecification(context.Background(), &deployRequest)
+			jobSpecsAdapted := []*pb.JobSpecification{}
+			for _, jobSpec := range jobSpecs {
+				jobSpecAdapted, _ := adapter.ToJobProto(jobSpec)
+				jobSpecsAdapted = append(jobSpecsAdapted, jobSpecAdapted)
+			}
+			deployRequest := pb.DeployJobSpecificationRequest{ProjectName: projectName, Jobs: jobSpecsAdapted, Namespace: namespaceSpec.Name}
+			err := runtimeServiceServer.DeployJobSpecification(&deployRequest, grpcRespStream)
 			assert.Nil(t, err)
-			assert.Equal(t, jobSpecAdapted, jobSpecResp.Spec)
 		})
-	})
-
-	t.Run("ListProjectNamespaces", func(t *testing.T) {
-		t.Run("should read namespaces of a project", func(t *testing.T) {
-			Version := "1.0.1"
-
+		t.Run("should stream progress of the deployment of each job", func(t *testing.T) {
 			projectName := "a-data-project"
+			taskName := "a-data-task"
 
 			projectSpec := models.ProjectSpec{
 				ID:   uuid.Must(uuid.NewRandom()),
 				Name: projectName,
-				Config: map[string]string{
-					"bucket": "gs://some_folder",
-				},
 			}
 
 			namespaceSpec := models.NamespaceSpec{
-				ID:   uuid.Must(uuid.NewRandom()),
-				Name: "dev-test-namespace-1",
-				Config: map[string]string{
-					"bucket": "gs://some_folder",
-				},
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "dev-test-namespace-1",
 				ProjectSpec: projectSpec,
 			}
 
+			execUnit1 := new(mock.BasePlugin)
+			execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
+				Name: taskName,
+			}, nil)
+			defer execUnit1.AssertExpectations(t)
+
+			jobSpec := models.JobSpec{
+				Name: "a-data-job",
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{
+						Base: execUnit1,
+					},
+				},
+			}
+
 			projectRepository := new(mock.ProjectRepository)
 			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
 			defer projectRepository.AssertExpectations(t)
@@ -953,66 +1764,210 @@ func TestRuntimeServiceServer(t *testing.T) {
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
-			adapter := v1.NewAdapter(nil, nil)
+			pluginRepo := new(mock.SupportedPluginRepo)
+			pluginRepo.On("GetByName", taskName).Return(&models.Plugin{
+				Base: execUnit1,
+			}, nil)
+			adapter := v1.NewAdapter(pluginRepo, nil)
 
 			namespaceRepository := new(mock.NamespaceRepository)
-			namespaceRepository.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
 			defer namespaceRepository.AssertExpectations(t)
 
 			namespaceRepoFact := new(mock.NamespaceRepoFactory)
 			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
 			defer namespaceRepoFact.AssertExpectations(t)
 
+			progressObserver := new(mock.PipelineLogObserver)
+			progressObserver.On("Notify", mock2.Anything)
+			defer progressObserver.AssertExpectations(t)
+
 			jobService := new(mock.JobService)
+			jobService.On("SaveAll", namespaceSpec, mock2.Anything).Return(nil)
+			jobService.On("KeepOnly", namespaceSpec, mock2.Anything, mock2.Anything).Return(nil)
+			jobService.On("Sync", mock2.Anything, namespaceSpec, mock2.Anything).Run(func(args mock2.Arguments) {
+				observer := args.Get(2).(progress.Observer)
+				observer.Notify(&job.EventJobSpecDependencyResolve{})
+				observer.Notify(&job.EventJobSpecCompile{Name: jobSpec.Name})
+				observer.Notify(&job.EventJobUpload{Job: jobSpec})
+			}).Return(nil)
 			defer jobService.AssertExpectations(t)
 
+			grpcRespStream := new(mock.RuntimeService_DeployJobSpecificationServer)
+			grpcRespStream.On("Context").Return(context.Background())
+			grpcRespStream.On("Send", &pb.DeployJobSpecificationResponse{
+				Message: "dependencies resolved",
+			}).Return(nil).Once()
+			grpcRespStream.On("Send", &pb.DeployJobSpecificationResponse{
+				JobName: "a-data-job",
+				Message: "compiling: a-data-job",
+			}).Return(nil).Once()
+			grpcRespStream.On("Send", &pb.DeployJobSpecificationResponse{
+				Success: true,
+				Ack:     true,
+				JobName: "a-data-job",
+			}).Return(nil).Once()
+			defer grpcRespStream.AssertExpectations(t)
+
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				Version,
+				"1.0.1",
 				jobService,
 				nil, nil,
 				projectRepoFactory,
 				namespaceRepoFact,
 				nil,
 				adapter,
+				progressObserver,
 				nil,
 				nil,
 				nil,
 			)
 
-			namespaceAdapted := adapter.ToNamespaceProto(namespaceSpec)
-			request := pb.ListProjectNamespacesRequest{ProjectName: projectName}
-			resp, err := runtimeServiceServer.ListProjectNamespaces(context.Background(), &request)
+			jobProto, _ := adapter.ToJobProto(jobSpec)
+			deployRequest := pb.DeployJobSpecificationRequest{ProjectName: projectName, Jobs: []*pb.JobSpecification{jobProto}, Namespace: namespaceSpec.Name}
+			err := runtimeServiceServer.DeployJobSpecification(&deployRequest, grpcRespStream)
 			assert.Nil(t, err)
-			assert.Equal(t, []*pb.NamespaceSpecification{namespaceAdapted}, resp.GetNamespaces())
 		})
 	})
 
-	t.Run("DeleteJobSpecification", func(t *testing.T) {
-		t.Run("should delete the job", func(t *testing.T) {
-			Version := "1.0.1"
-
+	t.Run("CheckJobSpecifications", func(t *testing.T) {
+		t.Run("should send warnings about changes breaking dependent jobs", func(t *testing.T) {
 			projectName := "a-data-project"
-			jobName1 := "a-data-job"
 			taskName := "a-data-task"
 
 			projectSpec := models.ProjectSpec{
 				ID:   uuid.Must(uuid.NewRandom()),
 				Name: projectName,
-				Config: map[string]string{
-					"bucket": "gs://some_folder",
-				},
 			}
 
 			namespaceSpec := models.NamespaceSpec{
-				ID:   uuid.Must(uuid.NewRandom()),
-				Name: "dev-test-namespace-1",
-				Config: map[string]string{
-					"bucket": "gs://some_folder",
-				},
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "dev-test-namespace-1",
 				ProjectSpec: projectSpec,
 			}
 
 			execUnit1 := new(mock.BasePlugin)
+			execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
+				Name: taskName,
+			}, nil)
+			defer execUnit1.AssertExpectations(t)
+
+			jobSpec := models.JobSpec{
+				Name: "upstream-job",
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{
+						Base: execUnit1,
+					},
+					Config: models.JobSpecConfigs{},
+					Window: models.JobSpecTaskWindow{
+						Size:       time.Hour,
+						TruncateTo: "d",
+					},
+				},
+				Assets: *models.JobAssets{}.New(
+					[]models.JobSpecAsset{
+						{
+							Name:  "query.sql",
+							Value: "select id from 1",
+						},
+					}),
+				Dependencies: map[string]models.JobSpecDependency{},
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			pluginRepo := new(mock.SupportedPluginRepo)
+			pluginRepo.On("GetByName", taskName).Return(&models.Plugin{
+				Base: execUnit1,
+			}, nil)
+			adapter := v1.NewAdapter(pluginRepo, nil)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("Check", namespaceSpec, []models.JobSpec{jobSpec}, mock2.Anything).Return(nil)
+			jobService.On("ValidateDependencyVersionCompatibility", context.Background(), namespaceSpec, []models.JobSpec{jobSpec}).
+				Return([]models.CompatibilityWarning{
+					{
+						Job:       "upstream-job",
+						Dependent: "downstream-job",
+						Dataset:   models.DatasetRef{Destination: "proj:dataset.upstream", Columns: []string{"legacy_code"}},
+						Reason:    "referenced columns are no longer produced",
+					},
+				}, nil)
+			defer jobService.AssertExpectations(t)
+
+			grpcRespStream := new(mock.RuntimeService_CheckJobSpecificationsServer)
+			grpcRespStream.On("Context").Return(context.Background())
+			grpcRespStream.On("Send", &pb.CheckJobSpecificationsResponse{
+				Success: true,
+				JobName: "upstream-job",
+				Message: "warning: referenced columns are no longer produced may break dependent job downstream-job, legacy_code of proj:dataset.upstream",
+			}).Return(nil)
+			defer grpcRespStream.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.1",
+				jobService,
+				nil, nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			jobProto, _ := adapter.ToJobProto(jobSpec)
+			checkRequest := pb.CheckJobSpecificationsRequest{ProjectName: projectName, Jobs: []*pb.JobSpecification{jobProto}, Namespace: namespaceSpec.Name}
+			err := runtimeServiceServer.CheckJobSpecifications(&checkRequest, grpcRespStream)
+			assert.Nil(t, err)
+		})
+	})
+
+	t.Run("ReadJobSpecification", func(t *testing.T) {
+		t.Run("should read a job spec", func(t *testing.T) {
+			Version := "1.0.1"
+
+			projectName := "a-data-project"
+			jobName1 := "a-data-job"
+			taskName := "a-data-task"
+
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: projectName,
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
+			}
+
+			namespaceSpec := models.NamespaceSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "dev-test-namespace-1",
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
+				ProjectSpec: projectSpec,
+			}
+
+			execUnit1 := new(mock.BasePlugin)
+			execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
+				Name: taskName,
+			}, nil)
 			defer execUnit1.AssertExpectations(t)
 
 			jobSpecs := []models.JobSpec{
@@ -1047,11 +2002,9 @@ func TestRuntimeServiceServer(t *testing.T) {
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
-			pluginRepo := new(mock.SupportedPluginRepo)
-			pluginRepo.On("GetByName", taskName).Return(&models.Plugin{
-				Base: execUnit1,
-			}, nil)
-			adapter := v1.NewAdapter(pluginRepo, nil)
+			allTasksRepo := new(mock.SupportedPluginRepo)
+			allTasksRepo.On("GetByName", taskName).Return(execUnit1, nil)
+			adapter := v1.NewAdapter(allTasksRepo, nil)
 
 			namespaceRepository := new(mock.NamespaceRepository)
 			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
@@ -1061,11 +2014,8 @@ func TestRuntimeServiceServer(t *testing.T) {
 			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
 			defer namespaceRepoFact.AssertExpectations(t)
 
-			jobSpec := jobSpecs[0]
-
 			jobService := new(mock.JobService)
 			jobService.On("GetByName", jobSpecs[0].Name, namespaceSpec).Return(jobSpecs[0], nil)
-			jobService.On("Delete", mock2.Anything, namespaceSpec, jobSpec).Return(nil)
 			defer jobService.AssertExpectations(t)
 
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
@@ -1079,120 +2029,140 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 			)
 
-			deployRequest := pb.DeleteJobSpecificationRequest{ProjectName: projectName, JobName: jobSpec.Name, Namespace: namespaceSpec.Name}
-			resp, err := runtimeServiceServer.DeleteJobSpecification(context.Background(), &deployRequest)
+			jobSpecAdapted, _ := adapter.ToJobProto(jobSpecs[0])
+			deployRequest := pb.ReadJobSpecificationRequest{ProjectName: projectName, JobName: jobSpecs[0].Name, Namespace: namespaceSpec.Name}
+			jobSpecResp, err := runtimeServiceServer.ReadJobSpecification(context.Background(), &deployRequest)
 			assert.Nil(t, err)
-			assert.Equal(t, "job a-data-job has been deleted", resp.GetMessage())
+			assert.Equal(t, jobSpecAdapted, jobSpecResp.Spec)
 		})
-	})
-
-	t.Run("JobStatus", func(t *testing.T) {
-		t.Run("should return all job status via scheduler if valid inputs", func(t *testing.T) {
-			Version := "1.0.0"
-
+		t.Run("should read a job spec of the default namespace when the request has no namespace", func(t *testing.T) {
 			projectSpec := models.ProjectSpec{
 				ID:   uuid.Must(uuid.NewRandom()),
 				Name: "a-data-project",
 			}
-
 			namespaceSpec := models.NamespaceSpec{
 				ID:          uuid.Must(uuid.NewRandom()),
-				Name:        "game_jam",
+				Name:        models.DefaultNamespaceName,
 				ProjectSpec: projectSpec,
 			}
 
-			jobSpec := models.JobSpec{
-				Name: "transform-tables",
-			}
-
 			projectRepository := new(mock.ProjectRepository)
 			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
-			defer projectRepository.AssertExpectations(t)
-
 			projectRepoFactory := new(mock.ProjectRepoFactory)
 			projectRepoFactory.On("New").Return(projectRepository)
-			defer projectRepoFactory.AssertExpectations(t)
 
-			adapter := v1.NewAdapter(nil, nil)
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", models.DefaultNamespaceName).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
 
 			jobService := new(mock.JobService)
-			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, namespaceSpec, nil)
+			jobService.On("GetByName", "a-data-job", namespaceSpec).Return(models.JobSpec{}, store.ErrResourceNotFound)
 			defer jobService.AssertExpectations(t)
 
-			jobStatuses := []models.JobStatus{
-				{
-					ScheduledAt: time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC),
-					State:       "failed",
-				},
-				{
-					ScheduledAt: time.Date(2020, 11, 10, 0, 0, 0, 0, time.UTC),
-					State:       "success",
-				},
-			}
-			scheduler := new(mock.Scheduler)
-			scheduler.On("GetJobStatus", context.Background(), projectSpec, jobSpec.Name).Return(jobStatuses, nil)
-			defer scheduler.AssertExpectations(t)
-
-			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				Version,
-				jobService, nil, nil,
-				projectRepoFactory,
-				nil,
-				nil,
-				adapter,
-				nil,
-				nil,
-				scheduler,
-			)
+			runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.1", jobService, nil, nil, projectRepoFactory,
+				namespaceRepoFact, nil, v1.NewAdapter(nil, nil), nil, nil, nil, nil)
 
-			req := &pb.JobStatusRequest{
+			_, err := runtimeServiceServer.ReadJobSpecification(context.Background(), &pb.ReadJobSpecificationRequest{
 				ProjectName: projectSpec.Name,
-				JobName:     jobSpec.Name,
-			}
-			resp, err := runtimeServiceServer.JobStatus(context.Background(), req)
-			assert.Nil(t, err)
-			assert.Equal(t, len(jobStatuses), len(resp.Statuses))
-			for _, expectedStatus := range jobStatuses {
-				var found bool
-				for _, respVal := range resp.Statuses {
-					if expectedStatus.ScheduledAt.Equal(respVal.ScheduledAt.AsTime()) &&
-						expectedStatus.State.String() == respVal.State {
-						found = true
-						break
-					}
-				}
-				if !found {
-					assert.Fail(t, fmt.Sprintf("failed to find expected job status %v", expectedStatus))
-				}
-			}
+				JobName:     "a-data-job",
+			})
+			assert.Equal(t, codes.NotFound, status.Code(err))
 		})
 	})
 
-	t.Run("RegisterJobEvent", func(t *testing.T) {
-		t.Run("should register the event if valid inputs", func(t *testing.T) {
-			Version := "1.0.0"
+	t.Run("job requests without a namespace", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		newServer := func(t *testing.T) *v1.RuntimeServiceServer {
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", models.DefaultNamespaceName).
+				Return(models.NamespaceSpec{}, store.ErrResourceNotFound).Once()
+			return newTestServer(t, testServerDeps{
+				jobService:     new(mock.JobService),
+				projects:       []models.ProjectSpec{projectSpec},
+				namespaceRepos: map[string]*mock.NamespaceRepository{projectSpec.Name: namespaceRepository},
+			})
+		}
+		ctx := context.Background()
+
+		testCases := []struct {
+			name string
+			call func(sv *v1.RuntimeServiceServer) error
+		}{
+			{"ListJobSpecification", func(sv *v1.RuntimeServiceServer) error {
+				_, err := sv.ListJobSpecification(ctx, &pb.ListJobSpecificationRequest{ProjectName: projectSpec.Name})
+				return err
+			}},
+			{"DumpJobSpecification", func(sv *v1.RuntimeServiceServer) error {
+				_, err := sv.DumpJobSpecification(ctx, &pb.DumpJobSpecificationRequest{ProjectName: projectSpec.Name,
+					JobName: "a-data-job"})
+				return err
+			}},
+			{"CheckJobSpecification", func(sv *v1.RuntimeServiceServer) error {
+				_, err := sv.CheckJobSpecification(ctx, &pb.CheckJobSpecificationRequest{ProjectName: projectSpec.Name})
+				return err
+			}},
+			{"CreateJobSpecification", func(sv *v1.RuntimeServiceServer) error {
+				_, err := sv.CreateJobSpecification(ctx, &pb.CreateJobSpecificationRequest{ProjectName: projectSpec.Name})
+				return err
+			}},
+			{"DeleteJobSpecification", func(sv *v1.RuntimeServiceServer) error {
+				_, err := sv.DeleteJobSpecification(ctx, &pb.DeleteJobSpecificationRequest{ProjectName: projectSpec.Name,
+					JobName: "a-data-job"})
+				return err
+			}},
+			{"UndeleteJobSpecification", func(sv *v1.RuntimeServiceServer) error {
+				_, err := sv.UndeleteJobSpecification(ctx, &pb.UndeleteJobSpecificationRequest{ProjectName: projectSpec.Name,
+					JobName: "a-data-job"})
+				return err
+			}},
+			{"DeployJobSpecification", func(sv *v1.RuntimeServiceServer) error {
+				return sv.DeployJobSpecification(&pb.DeployJobSpecificationRequest{ProjectName: projectSpec.Name},
+					new(mock.RuntimeService_DeployJobSpecificationServer))
+			}},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name+" should use the default namespace of the project", func(t *testing.T) {
+				err := tc.call(newServer(t))
+
+				assert.Equal(t, codes.NotFound, status.Code(err))
+				assert.Contains(t, err.Error(), "namespace default not found")
+			})
+		}
+	})
+
+	t.Run("ListProjectNamespaces", func(t *testing.T) {
+		t.Run("should read namespaces of a project", func(t *testing.T) {
+			Version := "1.0.1"
+
+			projectName := "a-data-project"
 
 			projectSpec := models.ProjectSpec{
 				ID:   uuid.Must(uuid.NewRandom()),
-				Name: "a-data-project",
+				Name: projectName,
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
 			}
 
 			namespaceSpec := models.NamespaceSpec{
-				ID:          uuid.Must(uuid.NewRandom()),
-				Name:        "game_jam",
-				ProjectSpec: projectSpec,
-			}
-
-			jobSpecs := []models.JobSpec{
-				{
-					Name: "transform-tables",
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "dev-test-namespace-1",
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
 				},
+				ProjectSpec: projectSpec,
 			}
 
 			projectRepository := new(mock.ProjectRepository)
-			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
 			defer projectRepository.AssertExpectations(t)
 
 			projectRepoFactory := new(mock.ProjectRepoFactory)
@@ -1202,7 +2172,7 @@ func TestRuntimeServiceServer(t *testing.T) {
 			adapter := v1.NewAdapter(nil, nil)
 
 			namespaceRepository := new(mock.NamespaceRepository)
-			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			namespaceRepository.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
 			defer namespaceRepository.AssertExpectations(t)
 
 			namespaceRepoFact := new(mock.NamespaceRepoFactory)
@@ -1210,24 +2180,12 @@ func TestRuntimeServiceServer(t *testing.T) {
 			defer namespaceRepoFact.AssertExpectations(t)
 
 			jobService := new(mock.JobService)
-			jobService.On("GetByName", jobSpecs[0].Name, namespaceSpec).Return(jobSpecs[0], nil)
 			defer jobService.AssertExpectations(t)
 
-			eventValues, _ := structpb.NewStruct(
-				map[string]interface{}{
-					"url": "http://example.io",
-				},
-			)
-			eventSvc := new(mock.EventService)
-			eventSvc.On("Register", context.Background(), namespaceSpec, jobSpecs[0], models.JobEvent{
-				Type:  models.JobEventTypeFailure,
-				Value: eventValues.GetFields(),
-			}).Return(nil)
-			defer eventSvc.AssertExpectations(t)
-
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
 				Version,
-				jobService, eventSvc, nil,
+				jobService,
+				nil, nil,
 				projectRepoFactory,
 				namespaceRepoFact,
 				nil,
@@ -1235,83 +2193,24 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 			)
-			req := &pb.RegisterJobEventRequest{
-				ProjectName: projectSpec.Name,
-				JobName:     jobSpecs[0].Name,
-				Namespace:   namespaceSpec.Name,
-				Event: &pb.JobEvent{
-					Type:  pb.JobEvent_FAILURE,
-					Value: eventValues,
-				},
-			}
-			_, err := runtimeServiceServer.RegisterJobEvent(context.Background(), req)
-			assert.Nil(t, err)
-		})
-	})
 
-	t.Run("GetWindow", func(t *testing.T) {
-		t.Run("should return the correct window date range", func(t *testing.T) {
-			Version := "1.0.1"
-
-			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				Version,
-				nil, nil, nil,
-				nil,
-				nil,
-				nil,
-				nil,
-				nil,
-				nil,
-				nil,
-			)
-			scheduledAt := time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC)
-			scheduledAtTimestamp := timestamppb.New(scheduledAt)
-			req := pb.GetWindowRequest{
-				ScheduledAt: scheduledAtTimestamp,
-				Size:        "24h",
-				Offset:      "24h",
-				TruncateTo:  "d",
-			}
-			resp, err := runtimeServiceServer.GetWindow(context.Background(), &req)
+			namespaceAdapted := adapter.ToNamespaceProto(namespaceSpec)
+			request := pb.ListProjectNamespacesRequest{ProjectName: projectName}
+			resp, err := runtimeServiceServer.ListProjectNamespaces(context.Background(), &request)
 			assert.Nil(t, err)
-
-			assert.Equal(t, "2020-11-11T00:00:00Z", ptypes.TimestampString(resp.GetStart()))
-			assert.Equal(t, "2020-11-12T00:00:00Z", ptypes.TimestampString(resp.GetEnd()))
-		})
-		t.Run("should return error if any of the required fields in request is missing", func(t *testing.T) {
-			Version := "1.0.1"
-
-			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				Version,
-				nil, nil,
-				nil, nil,
-				nil,
-				nil,
-				nil,
-				nil,
-				nil,
-				nil,
-			)
-			scheduledAt := time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC)
-			scheduledAtTimestamp := timestamppb.New(scheduledAt)
-			req := pb.GetWindowRequest{
-				ScheduledAt: scheduledAtTimestamp,
-				Size:        "",
-				Offset:      "24h",
-				TruncateTo:  "d",
-			}
-			_, err := runtimeServiceServer.GetWindow(context.Background(), &req)
-			assert.Equal(t, "rpc error: code = InvalidArgument desc = window size, offset and truncate_to must be provided", err.Error())
+			assert.Equal(t, []*pb.NamespaceSpecification{namespaceAdapted}, resp.GetNamespaces())
 		})
 	})
 
-	t.Run("DumpJobSpecification", func(t *testing.T) {
-		t.Run("should dump specification of a job", func(t *testing.T) {
+	t.Run("DeleteJobSpecification", func(t *testing.T) {
+		t.Run("should delete the job", func(t *testing.T) {
 			Version := "1.0.1"
 
 			projectName := "a-data-project"
-			jobName := "a-data-job"
+			jobName1 := "a-data-job"
+			taskName := "a-data-task"
 
 			projectSpec := models.ProjectSpec{
 				ID:   uuid.Must(uuid.NewRandom()),
@@ -1330,36 +2229,31 @@ func TestRuntimeServiceServer(t *testing.T) {
 				ProjectSpec: projectSpec,
 			}
 
-			compiledJob := models.Job{
-				Name:        jobName,
-				NamespaceID: namespaceSpec.ID.String(),
-				Contents:    []byte("content-of-dag"),
-			}
-
-			baseUnit := new(mock.BasePlugin)
-			defer baseUnit.AssertExpectations(t)
+			execUnit1 := new(mock.BasePlugin)
+			defer execUnit1.AssertExpectations(t)
 
-			jobSpec := models.JobSpec{
-				ID:   uuid.Must(uuid.NewRandom()),
-				Name: jobName,
-				Task: models.JobSpecTask{
-					Unit: &models.Plugin{
-						Base: baseUnit,
-					},
-					Config: models.JobSpecConfigs{
-						{
-							Name:  "do",
-							Value: "this",
+			jobSpecs := []models.JobSpec{
+				{
+					Name: jobName1,
+					Task: models.JobSpecTask{
+						Unit: &models.Plugin{
+							Base: execUnit1,
+						},
+						Config: models.JobSpecConfigs{
+							{
+								Name:  "do",
+								Value: "this",
+							},
 						},
 					},
+					Assets: *models.JobAssets{}.New(
+						[]models.JobSpecAsset{
+							{
+								Name:  "query.sql",
+								Value: "select * from 1",
+							},
+						}),
 				},
-				Assets: *models.JobAssets{}.New(
-					[]models.JobSpecAsset{
-						{
-							Name:  "query.sql",
-							Value: "select * from 1",
-						},
-					}),
 			}
 
 			projectRepository := new(mock.ProjectRepository)
@@ -1370,25 +2264,11 @@ func TestRuntimeServiceServer(t *testing.T) {
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
-			jobService := new(mock.JobService)
-			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
-			jobService.On("Dump", namespaceSpec, jobSpec).Return(compiledJob, nil)
-			defer jobService.AssertExpectations(t)
-
-			jobSpecRepository := new(mock.JobSpecRepository)
-			defer jobSpecRepository.AssertExpectations(t)
-
-			jobSpecRepoFactory := new(mock.JobSpecRepoFactory)
-			defer jobSpecRepoFactory.AssertExpectations(t)
-
-			compiler := new(mock.Compiler)
-			defer compiler.AssertExpectations(t)
-
-			dependencyResolver := new(mock.DependencyResolver)
-			defer dependencyResolver.AssertExpectations(t)
-
-			priorityResolver := new(mock.PriorityResolver)
-			defer priorityResolver.AssertExpectations(t)
+			pluginRepo := new(mock.SupportedPluginRepo)
+			pluginRepo.On("GetByName", taskName).Return(&models.Plugin{
+				Base: execUnit1,
+			}, nil)
+			adapter := v1.NewAdapter(pluginRepo, nil)
 
 			namespaceRepository := new(mock.NamespaceRepository)
 			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
@@ -1398,11 +2278,12 @@ func TestRuntimeServiceServer(t *testing.T) {
 			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
 			defer namespaceRepoFact.AssertExpectations(t)
 
-			projectJobSpecRepository := new(mock.ProjectJobSpecRepository)
-			defer projectJobSpecRepository.AssertExpectations(t)
+			jobSpec := jobSpecs[0]
 
-			projectJobSpecRepoFactory := new(mock.ProjectJobSpecRepoFactory)
-			defer projectJobSpecRepoFactory.AssertExpectations(t)
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobSpecs[0].Name, namespaceSpec).Return(jobSpecs[0], nil)
+			jobService.On("Delete", mock2.Anything, namespaceSpec, jobSpec).Return(nil)
+			defer jobService.AssertExpectations(t)
 
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
 				Version,
@@ -1411,192 +2292,133 @@ func TestRuntimeServiceServer(t *testing.T) {
 				projectRepoFactory,
 				namespaceRepoFact,
 				nil,
-				v1.NewAdapter(nil, nil),
+				adapter,
+				nil,
 				nil,
 				nil,
 				nil,
 			)
 
-			req := pb.DumpJobSpecificationRequest{
-				ProjectName: projectName,
-				JobName:     jobName,
-				Namespace:   namespaceSpec.Name,
-			}
-			resp, err := runtimeServiceServer.DumpJobSpecification(context.Background(), &req)
+			deployRequest := pb.DeleteJobSpecificationRequest{ProjectName: projectName, JobName: jobSpec.Name, Namespace: namespaceSpec.Name}
+			resp, err := runtimeServiceServer.DeleteJobSpecification(context.Background(), &deployRequest)
 			assert.Nil(t, err)
-			assert.Equal(t, true, resp.GetSuccess())
-			assert.Equal(t, "content-of-dag", resp.GetContent())
+			assert.Equal(t, "job a-data-job has been deleted", resp.GetMessage())
 		})
 	})
 
-	t.Run("CreateResource", func(t *testing.T) {
-		t.Run("should create datastore resource successfully", func(t *testing.T) {
-			projectName := "a-data-project"
+	t.Run("JobStatus", func(t *testing.T) {
+		t.Run("should return all job status via scheduler if valid inputs", func(t *testing.T) {
+			Version := "1.0.0"
+
 			projectSpec := models.ProjectSpec{
 				ID:   uuid.Must(uuid.NewRandom()),
-				Name: projectName,
-				Config: map[string]string{
-					"bucket": "gs://some_folder",
-				},
+				Name: "a-data-project",
 			}
 
 			namespaceSpec := models.NamespaceSpec{
-				ID:   uuid.Must(uuid.NewRandom()),
-				Name: "dev-test-namespace-1",
-				Config: map[string]string{
-					"bucket": "gs://some_folder",
-				},
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "game_jam",
 				ProjectSpec: projectSpec,
 			}
 
-			// prepare mocked datastore
-			dsTypeTableAdapter := new(mock.DatastoreTypeAdapter)
-
-			dsTypeTableController := new(mock.DatastoreTypeController)
-			dsTypeTableController.On("Adapter").Return(dsTypeTableAdapter)
-
-			dsTypeDatasetController := new(mock.DatastoreTypeController)
-			dsTypeDatasetController.On("Adapter").Return(dsTypeTableAdapter)
-
-			dsController := map[models.ResourceType]models.DatastoreTypeController{
-				models.ResourceTypeDataset: dsTypeTableController,
-			}
-			datastorer := new(mock.Datastorer)
-			datastorer.On("Types").Return(dsController)
-			datastorer.On("Name").Return("bq")
-
-			dsRepo := new(mock.SupportedDatastoreRepo)
-			dsRepo.On("GetByName", "bq").Return(datastorer, nil)
-
-			resourceSpec := models.ResourceSpec{
-				Version:   1,
-				Name:      "proj.datas",
-				Type:      models.ResourceTypeDataset,
-				Datastore: datastorer,
-			}
-
-			dsTypeTableAdapter.On("FromProtobuf", mock2.Anything).Return(resourceSpec, nil)
-
-			req := pb.CreateResourceRequest{
-				ProjectName:   projectName,
-				DatastoreName: "bq",
-				Resource: &pb.ResourceSpecification{
-					Version: 1,
-					Name:    "proj.datas",
-					Type:    models.ResourceTypeDataset.String(),
-				},
-				Namespace: namespaceSpec.Name,
+			jobSpec := models.JobSpec{
+				Name: "transform-tables",
 			}
 
 			projectRepository := new(mock.ProjectRepository)
-			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
 			defer projectRepository.AssertExpectations(t)
 
 			projectRepoFactory := new(mock.ProjectRepoFactory)
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
-			resourceSvc := new(mock.DatastoreService)
-			resourceSvc.On("CreateResource", context.Background(), namespaceSpec, []models.ResourceSpec{resourceSpec}, nil).Return(nil)
-			defer resourceSvc.AssertExpectations(t)
+			adapter := v1.NewAdapter(nil, nil)
 
-			namespaceRepository := new(mock.NamespaceRepository)
-			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
-			defer namespaceRepository.AssertExpectations(t)
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, namespaceSpec, nil)
+			defer jobService.AssertExpectations(t)
 
-			namespaceRepoFact := new(mock.NamespaceRepoFactory)
-			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
-			defer namespaceRepoFact.AssertExpectations(t)
+			jobStatuses := []models.JobStatus{
+				{
+					ScheduledAt: time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC),
+					State:       "failed",
+				},
+				{
+					ScheduledAt: time.Date(2020, 11, 10, 0, 0, 0, 0, time.UTC),
+					State:       "success",
+				},
+			}
+			scheduler := new(mock.Scheduler)
+			scheduler.On("GetJobStatus", context.Background(), projectSpec, jobSpec.Name).Return(jobStatuses, nil)
+			defer scheduler.AssertExpectations(t)
 
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				"Version",
-				nil, nil,
-				resourceSvc,
+				Version,
+				jobService, nil, nil,
 				projectRepoFactory,
-				namespaceRepoFact,
 				nil,
-				v1.NewAdapter(nil, dsRepo),
+				nil,
+				adapter,
 				nil,
 				nil,
+				scheduler,
 				nil,
 			)
 
-			resp, err := runtimeServiceServer.CreateResource(context.Background(), &req)
-			assert.Nil(t, err)
-			assert.Equal(t, true, resp.GetSuccess())
-		})
+			req := &pb.JobStatusRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+			}
+			resp, err := runtimeServiceServer.JobStatus(context.Background(), req)
+			assert.Nil(t, err)
+			assert.Equal(t, len(jobStatuses), len(resp.Statuses))
+			for _, expectedStatus := range jobStatuses {
+				var found bool
+				for _, respVal := range resp.Statuses {
+					if expectedStatus.ScheduledAt.Equal(respVal.ScheduledAt.AsTime()) &&
+						expectedStatus.State.String() == respVal.State {
+						found = true
+						break
+					}
+				}
+				if !found {
+					assert.Fail(t, fmt.Sprintf("failed to find expected job status %v", expectedStatus))
+				}
+			}
+		})
 	})
 
-	t.Run("UpdateResource", func(t *testing.T) {
-		t.Run("should update datastore resource successfully", func(t *testing.T) {
-			projectName := "a-data-project"
+	t.Run("RegisterJobEvent", func(t *testing.T) {
+		t.Run("should register the event if valid inputs", func(t *testing.T) {
+			Version := "1.0.0"
+
 			projectSpec := models.ProjectSpec{
 				ID:   uuid.Must(uuid.NewRandom()),
-				Name: projectName,
-				Config: map[string]string{
-					"bucket": "gs://some_folder",
-				},
+				Name: "a-data-project",
 			}
 
 			namespaceSpec := models.NamespaceSpec{
-				ID:   uuid.Must(uuid.NewRandom()),
-				Name: "dev-test-namespace-1",
-				Config: map[string]string{
-					"bucket": "gs://some_folder",
-				},
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "game_jam",
 				ProjectSpec: projectSpec,
 			}
 
-			// prepare mocked datastore
-			dsTypeTableAdapter := new(mock.DatastoreTypeAdapter)
-
-			dsTypeTableController := new(mock.DatastoreTypeController)
-			dsTypeTableController.On("Adapter").Return(dsTypeTableAdapter)
-
-			dsTypeDatasetController := new(mock.DatastoreTypeController)
-			dsTypeDatasetController.On("Adapter").Return(dsTypeTableAdapter)
-
-			dsController := map[models.ResourceType]models.DatastoreTypeController{
-				models.ResourceTypeDataset: dsTypeTableController,
-			}
-			datastorer := new(mock.Datastorer)
-			datastorer.On("Types").Return(dsController)
-			datastorer.On("Name").Return("bq")
-
-			dsRepo := new(mock.SupportedDatastoreRepo)
-			dsRepo.On("GetByName", "bq").Return(datastorer, nil)
-
-			resourceSpec := models.ResourceSpec{
-				Version:   1,
-				Name:      "proj.datas",
-				Type:      models.ResourceTypeDataset,
-				Datastore: datastorer,
-			}
-
-			dsTypeTableAdapter.On("FromProtobuf", mock2.Anything).Return(resourceSpec, nil)
-
-			req := pb.UpdateResourceRequest{
-				ProjectName:   projectName,
-				DatastoreName: "bq",
-				Resource: &pb.ResourceSpecification{
-					Version: 1,
-					Name:    "proj.datas",
-					Type:    models.ResourceTypeDataset.String(),
+			jobSpecs := []models.JobSpec{
+				{
+					Name: "transform-tables",
 				},
-				Namespace: namespaceSpec.Name,
 			}
 
 			projectRepository := new(mock.ProjectRepository)
-			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
 			defer projectRepository.AssertExpectations(t)
 
 			projectRepoFactory := new(mock.ProjectRepoFactory)
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
-			resourceSvc := new(mock.DatastoreService)
-			resourceSvc.On("UpdateResource", context.Background(), namespaceSpec, []models.ResourceSpec{resourceSpec}, nil).Return(nil)
-			defer resourceSvc.AssertExpectations(t)
+			adapter := v1.NewAdapter(nil, nil)
 
 			namespaceRepository := new(mock.NamespaceRepository)
 			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
@@ -1606,103 +2428,25 @@ func TestRuntimeServiceServer(t *testing.T) {
 			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
 			defer namespaceRepoFact.AssertExpectations(t)
 
-			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				"Version",
-				nil, nil,
-				resourceSvc,
-				projectRepoFactory,
-				namespaceRepoFact,
-				nil,
-				v1.NewAdapter(nil, dsRepo),
-				nil,
-				nil,
-				nil,
-			)
-
-			resp, err := runtimeServiceServer.UpdateResource(context.Background(), &req)
-			assert.Nil(t, err)
-			assert.Equal(t, true, resp.GetSuccess())
-		})
-	})
-
-	t.Run("ReplayDryRun", func(t *testing.T) {
-		projectName := "a-data-project"
-		jobName := "a-data-job"
-		timeLayout := "2006-01-02"
-		projectSpec := models.ProjectSpec{
-			ID:   uuid.Must(uuid.NewRandom()),
-			Name: projectName,
-			Config: map[string]string{
-				"bucket": "gs://some_folder",
-			},
-		}
-		namespaceSpec := models.NamespaceSpec{
-			ID:   uuid.Must(uuid.NewRandom()),
-			Name: "dev-test-namespace-1",
-			Config: map[string]string{
-				"bucket": "gs://some_folder",
-			},
-			ProjectSpec: projectSpec,
-		}
-		jobSpec := models.JobSpec{
-			ID:   uuid.Must(uuid.NewRandom()),
-			Name: jobName,
-			Task: models.JobSpecTask{
-				Config: models.JobSpecConfigs{
-					{
-						Name:  "do",
-						Value: "this",
-					},
-				},
-			},
-			Assets: *models.JobAssets{}.New(
-				[]models.JobSpecAsset{
-					{
-						Name:  "query.sql",
-						Value: "select * from 1",
-					},
-				}),
-		}
-		t.Run("should do replay dry run successfully", func(t *testing.T) {
-			startDate, _ := time.Parse(timeLayout, "2020-11-25")
-			endDate, _ := time.Parse(timeLayout, "2020-11-28")
-			replayWorkerRequest := &models.ReplayWorkerRequest{
-				Job:     jobSpec,
-				Start:   startDate,
-				End:     endDate,
-				Project: projectSpec,
-			}
-			dagNode := tree.NewTreeNode(jobSpec)
-			dagNode.Runs.Add(time.Date(2020, 11, 25, 2, 0, 0, 0, time.UTC))
-			dagNode.Runs.Add(time.Date(2020, 11, 26, 2, 0, 0, 0, time.UTC))
-			dagNode.Runs.Add(time.Date(2020, 11, 27, 2, 0, 0, 0, time.UTC))
-			dagNode.Runs.Add(time.Date(2020, 11, 28, 2, 0, 0, 0, time.UTC))
-
 			jobService := new(mock.JobService)
-			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
-			jobService.On("ReplayDryRun", replayWorkerRequest).Return(dagNode, nil)
+			jobService.On("GetByName", jobSpecs[0].Name, namespaceSpec).Return(jobSpecs[0], nil)
 			defer jobService.AssertExpectations(t)
 
-			projectRepository := new(mock.ProjectRepository)
-			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
-			defer projectRepository.AssertExpectations(t)
-
-			projectRepoFactory := new(mock.ProjectRepoFactory)
-			projectRepoFactory.On("New").Return(projectRepository)
-			defer projectRepoFactory.AssertExpectations(t)
-
-			namespaceRepository := new(mock.NamespaceRepository)
-			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
-			defer namespaceRepository.AssertExpectations(t)
+			eventValues, _ := structpb.NewStruct(
+				map[string]interface{}{
+					"url": "http://example.io",
+				},
+			)
+			eventSvc := new(mock.EventService)
+			eventSvc.On("Register", context.Background(), namespaceSpec, jobSpecs[0], models.JobEvent{
+				Type:  models.JobEventTypeFailure,
+				Value: eventValues.GetFields(),
+			}).Return(nil)
+			defer eventSvc.AssertExpectations(t)
 
-			namespaceRepoFact := new(mock.NamespaceRepoFactory)
-			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
-			defer namespaceRepoFact.AssertExpectations(t)
-			adapter := v1.NewAdapter(nil, nil)
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				"Version",
-				jobService, nil,
-				nil,
+				Version,
+				jobService, eventSvc, nil,
 				projectRepoFactory,
 				namespaceRepoFact,
 				nil,
@@ -1710,180 +2454,3355 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
 			)
-			replayRequest := pb.ReplayRequest{
-				ProjectName: projectName,
+			req := &pb.RegisterJobEventRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpecs[0].Name,
 				Namespace:   namespaceSpec.Name,
-				JobName:     jobName,
-				StartDate:   startDate.Format(timeLayout),
-				EndDate:     endDate.Format(timeLayout),
+				Event: &pb.JobEvent{
+					Type:  pb.JobEvent_FAILURE,
+					Value: eventValues,
+				},
 			}
-			replayResponse, err := runtimeServiceServer.ReplayDryRun(context.Background(), &replayRequest)
-			assert.Nil(t, err)
-			assert.Equal(t, true, replayResponse.Success)
-			expectedReplayResponse, err := adapter.ToReplayExecutionTreeNode(dagNode)
+			_, err := runtimeServiceServer.RegisterJobEvent(context.Background(), req)
 			assert.Nil(t, err)
-			assert.Equal(t, expectedReplayResponse.JobName, replayResponse.Response.JobName)
-			assert.Equal(t, expectedReplayResponse.Dependents, replayResponse.Response.Dependents)
-			assert.Equal(t, expectedReplayResponse.Runs, replayResponse.Response.Runs)
 		})
-		t.Run("should failed when replay request is invalid", func(t *testing.T) {
-			startDate, _ := time.Parse(timeLayout, "2020-11-25")
-			endDate, _ := time.Parse(timeLayout, "2020-11-24")
-
-			jobService := new(mock.JobService)
-			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
-			defer jobService.AssertExpectations(t)
-
-			projectRepository := new(mock.ProjectRepository)
-			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
-			defer projectRepository.AssertExpectations(t)
-
-			projectRepoFactory := new(mock.ProjectRepoFactory)
-			projectRepoFactory.On("New").Return(projectRepository)
-			defer projectRepoFactory.AssertExpectations(t)
+	})
 
-			namespaceRepository := new(mock.NamespaceRepository)
-			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
-			defer namespaceRepository.AssertExpectations(t)
+	t.Run("GetWindow", func(t *testing.T) {
+		t.Run("should return the correct window date range", func(t *testing.T) {
+			Version := "1.0.1"
 
-			namespaceRepoFact := new(mock.NamespaceRepoFactory)
-			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
-			defer namespaceRepoFact.AssertExpectations(t)
-			adapter := v1.NewAdapter(nil, nil)
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				"Version",
-				jobService,
+				Version,
+				nil, nil, nil,
+				nil,
+				nil,
 				nil,
 				nil,
-				projectRepoFactory,
-				namespaceRepoFact,
 				nil,
-				adapter,
 				nil,
 				nil,
 				nil,
 			)
-			replayRequest := pb.ReplayRequest{
-				ProjectName: projectName,
-				Namespace:   namespaceSpec.Name,
-				JobName:     jobName,
-				StartDate:   startDate.Format(timeLayout),
-				EndDate:     endDate.Format(timeLayout),
-			}
-			replayResponse, err := runtimeServiceServer.ReplayDryRun(context.TODO(), &replayRequest)
-			assert.NotNil(t, err)
-			assert.Nil(t, replayResponse)
+			scheduledAt := time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC)
+			scheduledAtTimestamp := timestamppb.New(scheduledAt)
+			req := pb.GetWindowRequest{
+				ScheduledAt: scheduledAtTimestamp,
+				Size:        "24h",
+				Offset:      "24h",
+				TruncateTo:  "d",
+			}
+			resp, err := runtimeServiceServer.GetWindow(context.Background(), &req)
+			assert.Nil(t, err)
+
+			assert.Equal(t, "2020-11-11T00:00:00Z", ptypes.TimestampString(resp.GetStart()))
+			assert.Equal(t, "2020-11-12T00:00:00Z", ptypes.TimestampString(resp.GetEnd()))
 		})
-		t.Run("should failed when unable to prepare the job specs", func(t *testing.T) {
-			startDate, _ := time.Parse(timeLayout, "2020-11-25")
-			endDate, _ := time.Parse(timeLayout, "2020-11-28")
-			replayWorkerRequest := &models.ReplayWorkerRequest{
-				Job:     jobSpec,
-				Start:   startDate,
-				End:     endDate,
-				Project: projectSpec,
+		t.Run("should return error if any of the required fields in request is missing", func(t *testing.T) {
+			Version := "1.0.1"
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				Version,
+				nil, nil,
+				nil, nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			scheduledAt := time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC)
+			scheduledAtTimestamp := timestamppb.New(scheduledAt)
+			req := pb.GetWindowRequest{
+				ScheduledAt: scheduledAtTimestamp,
+				Size:        "",
+				Offset:      "24h",
+				TruncateTo:  "d",
+			}
+			_, err := runtimeServiceServer.GetWindow(context.Background(), &req)
+			assert.Equal(t, "rpc error: code = InvalidArgument desc = window size, offset and truncate_to must be provided", err.Error())
+		})
+	})
+
+	t.Run("DumpJobSpecification", func(t *testing.T) {
+		t.Run("should dump specification of a job", func(t *testing.T) {
+			Version := "1.0.1"
+
+			projectName := "a-data-project"
+			jobName := "a-data-job"
+
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: projectName,
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
 			}
-			dagNode := tree.NewTreeNode(jobSpec)
+
+			namespaceSpec := models.NamespaceSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "dev-test-namespace-1",
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
+				ProjectSpec: projectSpec,
+			}
+
+			compiledJob := models.Job{
+				Name:        jobName,
+				NamespaceID: namespaceSpec.ID.String(),
+				Contents:    []byte("content-of-dag"),
+			}
+
+			baseUnit := new(mock.BasePlugin)
+			defer baseUnit.AssertExpectations(t)
+
+			jobSpec := models.JobSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: jobName,
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{
+						Base: baseUnit,
+					},
+					Config: models.JobSpecConfigs{
+						{
+							Name:  "do",
+							Value: "this",
+						},
+					},
+				},
+				Assets: *models.JobAssets{}.New(
+					[]models.JobSpecAsset{
+						{
+							Name:  "query.sql",
+							Value: "select * from 1",
+						},
+					}),
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
 
 			jobService := new(mock.JobService)
 			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
-			jobService.On("ReplayDryRun", replayWorkerRequest).Return(dagNode, errors.New("populating jobs spec failed"))
+			jobService.On("Dump", namespaceSpec, jobSpec).Return(compiledJob, nil)
 			defer jobService.AssertExpectations(t)
 
+			jobSpecRepository := new(mock.JobSpecRepository)
+			defer jobSpecRepository.AssertExpectations(t)
+
+			jobSpecRepoFactory := new(mock.JobSpecRepoFactory)
+			defer jobSpecRepoFactory.AssertExpectations(t)
+
+			compiler := new(mock.Compiler)
+			defer compiler.AssertExpectations(t)
+
+			dependencyResolver := new(mock.DependencyResolver)
+			defer dependencyResolver.AssertExpectations(t)
+
+			priorityResolver := new(mock.PriorityResolver)
+			defer priorityResolver.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
+			projectJobSpecRepository := new(mock.ProjectJobSpecRepository)
+			defer projectJobSpecRepository.AssertExpectations(t)
+
+			projectJobSpecRepoFactory := new(mock.ProjectJobSpecRepoFactory)
+			defer projectJobSpecRepoFactory.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				Version,
+				jobService,
+				nil, nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			req := pb.DumpJobSpecificationRequest{
+				ProjectName: projectName,
+				JobName:     jobName,
+				Namespace:   namespaceSpec.Name,
+			}
+			resp, err := runtimeServiceServer.DumpJobSpecification(context.Background(), &req)
+			assert.Nil(t, err)
+			assert.Equal(t, true, resp.GetSuccess())
+			assert.Equal(t, "content-of-dag", resp.GetContent())
+		})
+	})
+
+	t.Run("CreateResource", func(t *testing.T) {
+		t.Run("should create datastore resource successfully", func(t *testing.T) {
+			projectName := "a-data-project"
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: projectName,
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
+			}
+
+			namespaceSpec := models.NamespaceSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "dev-test-namespace-1",
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
+				ProjectSpec: projectSpec,
+			}
+
+			// prepare mocked datastore
+			dsTypeTableAdapter := new(mock.DatastoreTypeAdapter)
+
+			dsTypeTableController := new(mock.DatastoreTypeController)
+			dsTypeTableController.On("Adapter").Return(dsTypeTableAdapter)
+
+			dsTypeDatasetController := new(mock.DatastoreTypeController)
+			dsTypeDatasetController.On("Adapter").Return(dsTypeTableAdapter)
+
+			dsController := map[models.ResourceType]models.DatastoreTypeController{
+				models.ResourceTypeDataset: dsTypeTableController,
+			}
+			datastorer := new(mock.Datastorer)
+			datastorer.On("Types").Return(dsController)
+			datastorer.On("Name").Return("bq")
+
+			dsRepo := new(mock.SupportedDatastoreRepo)
+			dsRepo.On("GetByName", "bq").Return(datastorer, nil)
+
+			resourceSpec := models.ResourceSpec{
+				Version:   1,
+				Name:      "proj.datas",
+				Type:      models.ResourceTypeDataset,
+				Datastore: datastorer,
+			}
+
+			dsTypeTableAdapter.On("FromProtobuf", mock2.Anything).Return(resourceSpec, nil)
+
+			req := pb.CreateResourceRequest{
+				ProjectName:   projectName,
+				DatastoreName: "bq",
+				Resource: &pb.ResourceSpecification{
+					Version: 1,
+					Name:    "proj.datas",
+					Type:    models.ResourceTypeDataset.String(),
+				},
+				Namespace: namespaceSpec.Name,
+			}
+
 			projectRepository := new(mock.ProjectRepository)
 			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
 			defer projectRepository.AssertExpectations(t)
 
-			projectRepoFactory := new(mock.ProjectRepoFactory)
-			projectRepoFactory.On("New").Return(projectRepository)
-			defer projectRepoFactory.AssertExpectations(t)
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			resourceSvc := new(mock.DatastoreService)
+			resourceSvc.On("CreateResource", context.Background(), namespaceSpec, []models.ResourceSpec{resourceSpec}, nil).Return(nil)
+			defer resourceSvc.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				nil, nil,
+				resourceSvc,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				v1.NewAdapter(nil, dsRepo),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.CreateResource(context.Background(), &req)
+			assert.Nil(t, err)
+			assert.Equal(t, true, resp.GetSuccess())
+		})
+	})
+
+	t.Run("UpdateResource", func(t *testing.T) {
+		t.Run("should update datastore resource successfully", func(t *testing.T) {
+			projectName := "a-data-project"
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: projectName,
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
+			}
+
+			namespaceSpec := models.NamespaceSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "dev-test-namespace-1",
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
+				ProjectSpec: projectSpec,
+			}
+
+			// prepare mocked datastore
+			dsTypeTableAdapter := new(mock.DatastoreTypeAdapter)
+
+			dsTypeTableController := new(mock.DatastoreTypeController)
+			dsTypeTableController.On("Adapter").Return(dsTypeTableAdapter)
+
+			dsTypeDatasetController := new(mock.DatastoreTypeController)
+			dsTypeDatasetController.On("Adapter").Return(dsTypeTableAdapter)
+
+			dsController := map[models.ResourceType]models.DatastoreTypeController{
+				models.ResourceTypeDataset: dsTypeTableController,
+			}
+			datastorer := new(mock.Datastorer)
+			datastorer.On("Types").Return(dsController)
+			datastorer.On("Name").Return("bq")
+
+			dsRepo := new(mock.SupportedDatastoreRepo)
+			dsRepo.On("GetByName", "bq").Return(datastorer, nil)
+
+			resourceSpec := models.ResourceSpec{
+				Version:   1,
+				Name:      "proj.datas",
+				Type:      models.ResourceTypeDataset,
+				Datastore: datastorer,
+			}
+
+			dsTypeTableAdapter.On("FromProtobuf", mock2.Anything).Return(resourceSpec, nil)
+
+			req := pb.UpdateResourceRequest{
+				ProjectName:   projectName,
+				DatastoreName: "bq",
+				Resource: &pb.ResourceSpecification{
+					Version: 1,
+					Name:    "proj.datas",
+					Type:    models.ResourceTypeDataset.String(),
+				},
+				Namespace: namespaceSpec.Name,
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			resourceSvc := new(mock.DatastoreService)
+			resourceSvc.On("UpdateResource", context.Background(), namespaceSpec, []models.ResourceSpec{resourceSpec}, nil).Return(nil)
+			defer resourceSvc.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				nil, nil,
+				resourceSvc,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				v1.NewAdapter(nil, dsRepo),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.UpdateResource(context.Background(), &req)
+			assert.Nil(t, err)
+			assert.Equal(t, true, resp.GetSuccess())
+		})
+	})
+
+	t.Run("ReplayDryRun", func(t *testing.T) {
+		projectName := "a-data-project"
+		jobName := "a-data-job"
+		timeLayout := "2006-01-02"
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: projectName,
+			Config: map[string]string{
+				"bucket": "gs://some_folder",
+			},
+		}
+		namespaceSpec := models.NamespaceSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "dev-test-namespace-1",
+			Config: map[string]string{
+				"bucket": "gs://some_folder",
+			},
+			ProjectSpec: projectSpec,
+		}
+		jobSpec := models.JobSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: jobName,
+			Task: models.JobSpecTask{
+				Config: models.JobSpecConfigs{
+					{
+						Name:  "do",
+						Value: "this",
+					},
+				},
+			},
+			Assets: *models.JobAssets{}.New(
+				[]models.JobSpecAsset{
+					{
+						Name:  "query.sql",
+						Value: "select * from 1",
+					},
+				}),
+		}
+		t.Run("should do replay dry run successfully", func(t *testing.T) {
+			startDate, _ := time.Parse(timeLayout, "2020-11-25")
+			endDate, _ := time.Parse(timeLayout, "2020-11-28")
+			replayWorkerRequest := &models.ReplayWorkerRequest{
+				Job:     jobSpec,
+				Start:   startDate,
+				End:     endDate,
+				Project: projectSpec,
+			}
+			dagNode := tree.NewTreeNode(jobSpec)
+			dagNode.Runs.Add(time.Date(2020, 11, 25, 2, 0, 0, 0, time.UTC))
+			dagNode.Runs.Add(time.Date(2020, 11, 26, 2, 0, 0, 0, time.UTC))
+			dagNode.Runs.Add(time.Date(2020, 11, 27, 2, 0, 0, 0, time.UTC))
+			dagNode.Runs.Add(time.Date(2020, 11, 28, 2, 0, 0, 0, time.UTC))
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
+			jobService.On("ReplayDryRun", replayWorkerRequest).Return(dagNode, nil)
+			defer jobService.AssertExpectations(t)
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+			adapter := v1.NewAdapter(nil, nil)
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				jobService, nil,
+				nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			replayRequest := pb.ReplayRequest{
+				ProjectName: projectName,
+				Namespace:   namespaceSpec.Name,
+				JobName:     jobName,
+				StartDate:   startDate.Format(timeLayout),
+				EndDate:     endDate.Format(timeLayout),
+			}
+			replayResponse, err := runtimeServiceServer.ReplayDryRun(context.Background(), &replayRequest)
+			assert.Nil(t, err)
+			assert.Equal(t, true, replayResponse.Success)
+			expectedReplayResponse, err := adapter.ToReplayExecutionTreeNode(dagNode)
+			assert.Nil(t, err)
+			assert.Equal(t, expectedReplayResponse.JobName, replayResponse.Response.JobName)
+			assert.Equal(t, expectedReplayResponse.Dependents, replayResponse.Response.Dependents)
+			assert.Equal(t, expectedReplayResponse.Runs, replayResponse.Response.Runs)
+		})
+		t.Run("should failed when replay request is invalid", func(t *testing.T) {
+			startDate, _ := time.Parse(timeLayout, "2020-11-25")
+			endDate, _ := time.Parse(timeLayout, "2020-11-24")
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
+			defer jobService.AssertExpectations(t)
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+			adapter := v1.NewAdapter(nil, nil)
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				jobService,
+				nil,
+				nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			replayRequest := pb.ReplayRequest{
+				ProjectName: projectName,
+				Namespace:   namespaceSpec.Name,
+				JobName:     jobName,
+				StartDate:   startDate.Format(timeLayout),
+				EndDate:     endDate.Format(timeLayout),
+			}
+			replayResponse, err := runtimeServiceServer.ReplayDryRun(context.TODO(), &replayRequest)
+			assert.NotNil(t, err)
+			assert.Nil(t, replayResponse)
+		})
+		t.Run("should failed when unable to prepare the job specs", func(t *testing.T) {
+			startDate, _ := time.Parse(timeLayout, "2020-11-25")
+			endDate, _ := time.Parse(timeLayout, "2020-11-28")
+			replayWorkerRequest := &models.ReplayWorkerRequest{
+				Job:     jobSpec,
+				Start:   startDate,
+				End:     endDate,
+				Project: projectSpec,
+			}
+			dagNode := tree.NewTreeNode(jobSpec)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
+			jobService.On("ReplayDryRun", replayWorkerRequest).Return(dagNode, errors.New("populating jobs spec failed"))
+			defer jobService.AssertExpectations(t)
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+			adapter := v1.NewAdapter(nil, nil)
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				jobService,
+				nil,
+				nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			replayRequest := pb.ReplayRequest{
+				ProjectName: projectName,
+				Namespace:   namespaceSpec.Name,
+				JobName:     jobName,
+				StartDate:   startDate.Format(timeLayout),
+				EndDate:     endDate.Format(timeLayout),
+			}
+			replayResponse, err := runtimeServiceServer.ReplayDryRun(context.TODO(), &replayRequest)
+			assert.NotNil(t, err)
+			assert.Nil(t, replayResponse)
+		})
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		projectName := "a-data-project"
+		jobName := "a-data-job"
+		timeLayout := "2006-01-02"
+		startDate, _ := time.Parse(timeLayout, "2020-11-25")
+		endDate, _ := time.Parse(timeLayout, "2020-11-28")
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: projectName,
+			Config: map[string]string{
+				"bucket": "gs://some_folder",
+			},
+		}
+		namespaceSpec := models.NamespaceSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "dev-test-namespace-1",
+			Config: map[string]string{
+				"bucket": "gs://some_folder",
+			},
+			ProjectSpec: projectSpec,
+		}
+		jobSpec := models.JobSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: jobName,
+			Task: models.JobSpecTask{
+				Config: models.JobSpecConfigs{
+					{
+						Name:  "do",
+						Value: "this",
+					},
+				},
+			},
+			Assets: *models.JobAssets{}.New(
+				[]models.JobSpecAsset{
+					{
+						Name:  "query.sql",
+						Value: "select * from 1",
+					},
+				}),
+		}
+		t.Run("should do replay successfully", func(t *testing.T) {
+			replayWorkerRequest := &models.ReplayWorkerRequest{
+				Job:     jobSpec,
+				Start:   startDate,
+				End:     endDate,
+				Project: projectSpec,
+			}
+			randomUUID := "random-uuid"
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
+			jobService.On("Replay", context.TODO(), replayWorkerRequest).Return(randomUUID, nil)
+			defer jobService.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+			adapter := v1.NewAdapter(nil, nil)
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				jobService,
+				nil,
+				nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			replayRequest := pb.ReplayRequest{
+				ProjectName: projectName,
+				Namespace:   namespaceSpec.Name,
+				JobName:     jobName,
+				StartDate:   startDate.Format(timeLayout),
+				EndDate:     endDate.Format(timeLayout),
+			}
+			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
+			assert.Nil(t, err)
+			assert.Equal(t, randomUUID, replayResponse.Id)
+		})
+		t.Run("should failed when replay request is invalid", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(models.NamespaceSpec{}, errors.New("Namespace not found"))
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+			adapter := v1.NewAdapter(nil, nil)
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				nil,
+				nil,
+				nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			replayRequest := pb.ReplayRequest{
+				ProjectName: projectName,
+				Namespace:   namespaceSpec.Name,
+				JobName:     jobName,
+				StartDate:   startDate.Format(timeLayout),
+				EndDate:     endDate.Format(timeLayout),
+			}
+			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
+			assert.NotNil(t, err)
+			assert.Nil(t, replayResponse)
+		})
+		t.Run("should failed when replay process is failed", func(t *testing.T) {
+			replayWorkerRequest := &models.ReplayWorkerRequest{
+				Job:     jobSpec,
+				Start:   startDate,
+				End:     endDate,
+				Project: projectSpec,
+			}
+			emptyUUID := ""
+			errMessage := "internal error"
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
+			jobService.On("Replay", context.TODO(), replayWorkerRequest).Return(emptyUUID, errors.New(errMessage))
+			defer jobService.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+			adapter := v1.NewAdapter(nil, nil)
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				jobService,
+				nil,
+				nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			replayRequest := pb.ReplayRequest{
+				ProjectName: projectName,
+				Namespace:   namespaceSpec.Name,
+				JobName:     jobName,
+				StartDate:   startDate.Format(timeLayout),
+				EndDate:     endDate.Format(timeLayout),
+			}
+			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), errMessage)
+			assert.Equal(t, codes.Internal, status.Code(err))
+			assert.Nil(t, replayResponse)
+		})
+		t.Run("should failed when project is not found", func(t *testing.T) {
+			errMessage := "project not found"
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(models.ProjectSpec{}, errors.New(errMessage))
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			adapter := v1.NewAdapter(nil, nil)
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				nil,
+				nil,
+				nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			replayRequest := pb.ReplayRequest{
+				ProjectName: projectName,
+				Namespace:   namespaceSpec.Name,
+				JobName:     jobName,
+				StartDate:   startDate.Format(timeLayout),
+				EndDate:     endDate.Format(timeLayout),
+			}
+			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), errMessage)
+			assert.Nil(t, replayResponse)
+		})
+		t.Run("should failed when job is not found in the namespace", func(t *testing.T) {
+			errMessage := "job not found in namespace"
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobName, namespaceSpec).Return(models.JobSpec{}, errors.New(errMessage))
+			defer jobService.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+			adapter := v1.NewAdapter(nil, nil)
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				jobService,
+				nil,
+				nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			replayRequest := pb.ReplayRequest{
+				ProjectName: projectName,
+				Namespace:   namespaceSpec.Name,
+				JobName:     jobName,
+				StartDate:   startDate.Format(timeLayout),
+				EndDate:     endDate.Format(timeLayout),
+			}
+			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), errMessage)
+			assert.Nil(t, replayResponse)
+		})
+		t.Run("should failed when replay validation is failed", func(t *testing.T) {
+			replayWorkerRequest := &models.ReplayWorkerRequest{
+				Job:     jobSpec,
+				Start:   startDate,
+				End:     endDate,
+				Project: projectSpec,
+			}
+			emptyUUID := ""
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
+			jobService.On("Replay", context.TODO(), replayWorkerRequest).Return(emptyUUID, job.ErrConflictedJobRun)
+			defer jobService.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+			adapter := v1.NewAdapter(nil, nil)
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				jobService,
+				nil,
+				nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			replayRequest := pb.ReplayRequest{
+				ProjectName: projectName,
+				Namespace:   namespaceSpec.Name,
+				JobName:     jobName,
+				StartDate:   startDate.Format(timeLayout),
+				EndDate:     endDate.Format(timeLayout),
+			}
+			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), job.ErrConflictedJobRun.Error())
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+			assert.Nil(t, replayResponse)
+		})
+		t.Run("should failed when request queue is full", func(t *testing.T) {
+			replayWorkerRequest := &models.ReplayWorkerRequest{
+				Job:     jobSpec,
+				Start:   startDate,
+				End:     endDate,
+				Project: projectSpec,
+			}
+			emptyUUID := ""
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
+			jobService.On("Replay", context.TODO(), replayWorkerRequest).Return(emptyUUID, job.ErrRequestQueueFull)
+			defer jobService.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+			adapter := v1.NewAdapter(nil, nil)
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				jobService,
+				nil,
+				nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			replayRequest := pb.ReplayRequest{
+				ProjectName: projectName,
+				Namespace:   namespaceSpec.Name,
+				JobName:     jobName,
+				StartDate:   startDate.Format(timeLayout),
+				EndDate:     endDate.Format(timeLayout),
+			}
+			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), job.ErrRequestQueueFull.Error())
+			assert.Equal(t, codes.Unavailable, status.Code(err))
+			assert.Nil(t, replayResponse)
+		})
+	})
+	t.Run("PreviewSchedulePropagation", func(t *testing.T) {
+		ctx := context.Background()
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		namespaceSpec := models.NamespaceSpec{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Name:        "game_jam",
+			ProjectSpec: projectSpec,
+		}
+		jobSpec := models.JobSpec{
+			Name: "transform-tables",
+		}
+		req := &pb.PreviewSchedulePropagationRequest{
+			ProjectName: projectSpec.Name,
+			JobName:     jobSpec.Name,
+		}
+
+		t.Run("should return proposed schedule changes of downstream jobs", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			scheduleChanges := []models.ScheduleChange{
+				{
+					JobName:          "downstream-tables",
+					UpstreamJobName:  jobSpec.Name,
+					CurrentInterval:  "0 * * * *",
+					ProposedInterval: "0 0 * * *",
+				},
+			}
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, namespaceSpec, nil)
+			jobService.On("PropagateScheduleChanges", ctx, projectSpec, jobSpec.Name).Return(scheduleChanges, nil)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.PreviewSchedulePropagation(ctx, req)
+			assert.Nil(t, err)
+			assert.Equal(t, []*pb.ScheduleChange{
+				{
+					JobName:          "downstream-tables",
+					UpstreamJobName:  jobSpec.Name,
+					CurrentInterval:  "0 * * * *",
+					ProposedInterval: "0 0 * * *",
+				},
+			}, resp.Changes)
+		})
+		t.Run("should return not found if job does not exist in project", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(models.JobSpec{}, models.NamespaceSpec{}, errors.New("job not found"))
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.PreviewSchedulePropagation(ctx, req)
+			assert.Equal(t, codes.NotFound, status.Code(err))
+			assert.Nil(t, resp)
+		})
+		t.Run("should return internal error if schedule propagation fails", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, namespaceSpec, nil)
+			jobService.On("PropagateScheduleChanges", ctx, projectSpec, jobSpec.Name).Return(nil, errors.New("failed to resolve dependencies"))
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.PreviewSchedulePropagation(ctx, req)
+			assert.Equal(t, codes.Internal, status.Code(err))
+			assert.Nil(t, resp)
+		})
+	})
+	t.Run("ListActiveJobInstances", func(t *testing.T) {
+		scheduledAt := time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+		t.Run("should return running instances cross validated with the scheduler", func(t *testing.T) {
+			ctx := context.Background()
+			projectSpec := models.ProjectSpec{
+				Name: "a-data-project",
+			}
+			instances := []models.InstanceSpec{
+				{
+					Job:         models.JobSpec{Name: "transform-tables"},
+					Project:     projectSpec,
+					ScheduledAt: scheduledAt,
+					State:       models.InstanceStateRunning,
+				},
+				{
+					Job:         models.JobSpec{Name: "stale-tables"},
+					Project:     projectSpec,
+					ScheduledAt: scheduledAt,
+					State:       models.InstanceStateRunning,
+				},
+			}
+			instanceService := new(mock.InstanceService)
+			instanceService.On("ListActive", ctx, 30*time.Minute).Return(instances, nil)
+			defer instanceService.AssertExpectations(t)
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil).Once()
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			scheduler := new(mock.Scheduler)
+			scheduler.On("GetRunningJobs", ctx, projectSpec).Return([]models.RunningJob{
+				{JobName: "transform-tables", DAGRunID: "scheduled__2021-02-01T00:00:00+00:00", ExecutorState: "running"},
+			}, nil).Once()
+			defer scheduler.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				nil, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				instanceService,
+				scheduler,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.ListActiveJobInstances(ctx, &pb.ListActiveJobInstancesRequest{MaxAgeMinutes: 30})
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(resp.Instances))
+			assert.Equal(t, "a-data-project", resp.Instances[0].ProjectName)
+			assert.Equal(t, "transform-tables", resp.Instances[0].JobName)
+			assert.Equal(t, models.InstanceStateRunning, resp.Instances[0].Instance.State)
+			assert.Equal(t, timestamppb.New(scheduledAt), resp.Instances[0].Instance.ScheduledAt)
+			assert.True(t, resp.Instances[0].SchedulerRunning)
+			assert.Equal(t, "stale-tables", resp.Instances[1].JobName)
+			assert.False(t, resp.Instances[1].SchedulerRunning)
+		})
+		t.Run("should not fail if scheduler can't list running jobs", func(t *testing.T) {
+			ctx := context.Background()
+			projectSpec := models.ProjectSpec{
+				Name: "a-data-project",
+			}
+			instances := []models.InstanceSpec{
+				{
+					Job:         models.JobSpec{Name: "transform-tables"},
+					Project:     projectSpec,
+					ScheduledAt: scheduledAt,
+					State:       models.InstanceStateRunning,
+				},
+			}
+			instanceService := new(mock.InstanceService)
+			instanceService.On("ListActive", ctx, 30*time.Minute).Return(instances, nil)
+			defer instanceService.AssertExpectations(t)
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			scheduler := new(mock.Scheduler)
+			scheduler.On("GetRunningJobs", ctx, projectSpec).Return(nil, models.ErrUnsupportedSchedulerOperation)
+			defer scheduler.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				nil, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				instanceService,
+				scheduler,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.ListActiveJobInstances(ctx, &pb.ListActiveJobInstancesRequest{MaxAgeMinutes: 30})
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(resp.Instances))
+			assert.False(t, resp.Instances[0].SchedulerRunning)
+		})
+		t.Run("should fail for non positive max age", func(t *testing.T) {
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				nil, nil, nil,
+				nil,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.ListActiveJobInstances(context.Background(), &pb.ListActiveJobInstancesRequest{MaxAgeMinutes: 0})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Nil(t, resp)
+		})
+		t.Run("should return internal error if listing fails", func(t *testing.T) {
+			ctx := context.Background()
+			instanceService := new(mock.InstanceService)
+			instanceService.On("ListActive", ctx, time.Hour).Return(nil, errors.New("db down"))
+			defer instanceService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				nil, nil, nil,
+				nil,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				instanceService,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.ListActiveJobInstances(ctx, &pb.ListActiveJobInstancesRequest{MaxAgeMinutes: 60})
+			assert.Equal(t, codes.Internal, status.Code(err))
+			assert.Nil(t, resp)
+		})
+	})
+	t.Run("ListSchedulerJobs", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		req := &pb.ListSchedulerJobsRequest{
+			ProjectName: projectSpec.Name,
+		}
+		adminCtx := middleware.ContextWithClaims(context.Background(), jwt.MapClaims{
+			"sub":   "admin@example.io",
+			"roles": []interface{}{"ADMIN"},
+		})
+		t.Run("should return jobs deployed to the scheduler in order", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("ListDeployedJobs", adminCtx, projectSpec).Return([]string{"transform-tables", "load-orders"}, nil)
+			defer scheduler.AssertExpectations(t)
+
+			resp, err := newTestServer(t, testServerDeps{scheduler: scheduler, projects: []models.ProjectSpec{projectSpec}}).ListSchedulerJobs(adminCtx, req)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"load-orders", "transform-tables"}, resp.GetJobNames())
+		})
+		t.Run("should return unimplemented if the scheduler can't list its jobs", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("ListDeployedJobs", adminCtx, projectSpec).Return([]string{},
+				errors.Wrap(models.ErrUnsupportedSchedulerOperation, "listing deployed jobs with airflow"))
+			defer scheduler.AssertExpectations(t)
+
+			_, err := newTestServer(t, testServerDeps{scheduler: scheduler, projects: []models.ProjectSpec{projectSpec}}).ListSchedulerJobs(adminCtx, req)
+			assert.Equal(t, codes.Unimplemented, status.Code(err))
+		})
+	})
+	t.Run("GenerateAPIKey", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		adminCtx := middleware.ContextWithClaims(context.Background(), jwt.MapClaims{
+			"sub":   "admin@example.io",
+			"roles": []interface{}{"ADMIN"},
+		})
+
+		t.Run("should return a key stored by its hash", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+			expiresAt := now.Add(time.Hour)
+			var savedHash string
+			apiKeyRepo := new(mock.APIKeyRepository)
+			apiKeyRepo.On("Save", mock2.MatchedBy(func(key models.APIKey) bool {
+				return key.ProjectName == projectSpec.Name && key.Role == models.RoleEditor &&
+					key.Description == "deploy pipeline" && key.CreatedAt.Equal(now) && key.ExpiresAt.Equal(expiresAt)
+			}), mock2.AnythingOfType("string")).Run(func(args mock2.Arguments) {
+				savedHash = args.String(1)
+			}).Return(nil)
+			defer apiKeyRepo.AssertExpectations(t)
+
+			apiKeyRepoFactory := new(mock.APIKeyRepoFactory)
+			apiKeyRepoFactory.On("New", projectSpec).Return(apiKeyRepo)
+			defer apiKeyRepoFactory.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				nil, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			runtimeServiceServer.APIKeyRepoFactory = apiKeyRepoFactory
+			runtimeServiceServer.Now = func() time.Time { return now }
+
+			resp, err := runtimeServiceServer.GenerateAPIKey(adminCtx, &pb.GenerateAPIKeyRequest{
+				ProjectName: projectSpec.Name,
+				Description: "deploy pipeline",
+				Role:        "EDITOR",
+				TtlSeconds:  3600,
+			})
+			assert.Nil(t, err)
+			assert.NotEmpty(t, resp.GetId())
+			assert.Equal(t, middleware.HashAPIKey(resp.GetKey()), savedHash)
+			assert.Equal(t, expiresAt, resp.GetExpiresAt().AsTime())
+		})
+		t.Run("should fail to generate keys with an invalid role", func(t *testing.T) {
+			runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.0", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+			runtimeServiceServer.APIKeyRepoFactory = new(mock.APIKeyRepoFactory)
+			_, err := runtimeServiceServer.GenerateAPIKey(adminCtx, &pb.GenerateAPIKeyRequest{
+				ProjectName: projectSpec.Name,
+				Role:        "OWNER",
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+		t.Run("should return unimplemented if the server doesn't store api keys", func(t *testing.T) {
+			runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.0", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+			_, err := runtimeServiceServer.GenerateAPIKey(adminCtx, &pb.GenerateAPIKeyRequest{
+				ProjectName: projectSpec.Name,
+				Role:        "VIEWER",
+			})
+			assert.Equal(t, codes.Unimplemented, status.Code(err))
+		})
+	})
+	t.Run("ProjectRoles", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		adminCtx := middleware.ContextWithClaims(context.Background(), jwt.MapClaims{
+			"sub":   "admin@example.io",
+			"roles": []interface{}{"ADMIN"},
+		})
+		newServer := func(t *testing.T, roleBindingRepo *mock.RoleBindingRepository) (*v1.RuntimeServiceServer, *logrustest.Hook) {
+			roleBindingRepoFactory := new(mock.RoleBindingRepoFactory)
+			roleBindingRepoFactory.On("New", projectSpec).Return(roleBindingRepo)
+			runtimeServiceServer := newTestServer(t, testServerDeps{
+				projects:               []models.ProjectSpec{projectSpec},
+				roleBindingRepoFactory: roleBindingRepoFactory,
+			})
+			auditLog, hook := logrustest.NewNullLogger()
+			runtimeServiceServer.AuditLog = auditLog
+			return runtimeServiceServer, hook
+		}
+
+		t.Run("should grant a role in the project and audit log it", func(t *testing.T) {
+			roleBindingRepo := new(mock.RoleBindingRepository)
+			roleBindingRepo.On("Save", models.ProjectRoleBinding{Subject: "user@example.io", Role: models.RoleEditor}).Return(nil)
+			defer roleBindingRepo.AssertExpectations(t)
+			runtimeServiceServer, hook := newServer(t, roleBindingRepo)
+
+			_, err := runtimeServiceServer.GrantProjectRole(adminCtx, &pb.GrantProjectRoleRequest{
+				ProjectName: projectSpec.Name,
+				Subject:     "user@example.io",
+				Role:        "editor",
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, logrus.Fields{
+				"event":     "project_role_grant",
+				"principal": "admin@example.io",
+				"project":   "a-data-project",
+				"subject":   "user@example.io",
+				"role":      "EDITOR",
+			}, hook.LastEntry().Data)
+		})
+		t.Run("should fail to grant unknown roles", func(t *testing.T) {
+			runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.0", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+			for _, role := range []string{"OWNER", "NONE"} {
+				_, err := runtimeServiceServer.GrantProjectRole(adminCtx, &pb.GrantProjectRoleRequest{
+					ProjectName: projectSpec.Name,
+					Subject:     "user@example.io",
+					Role:        role,
+				})
+				assert.Equal(t, codes.InvalidArgument, status.Code(err), role)
+			}
+		})
+		t.Run("should return unimplemented if the server doesn't store roles", func(t *testing.T) {
+			runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.0", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+			_, err := runtimeServiceServer.ListProjectRoles(adminCtx, &pb.ListProjectRolesRequest{ProjectName: projectSpec.Name})
+			assert.Equal(t, codes.Unimplemented, status.Code(err))
+		})
+		t.Run("should revoke the role of a subject", func(t *testing.T) {
+			roleBindingRepo := new(mock.RoleBindingRepository)
+			roleBindingRepo.On("Delete", "user@example.io").Return(nil)
+			roleBindingRepo.On("Delete", "other@example.io").Return(store.ErrResourceNotFound)
+			defer roleBindingRepo.AssertExpectations(t)
+			runtimeServiceServer, _ := newServer(t, roleBindingRepo)
+
+			_, err := runtimeServiceServer.RevokeProjectRole(adminCtx, &pb.RevokeProjectRoleRequest{
+				ProjectName: projectSpec.Name,
+				Subject:     "user@example.io",
+			})
+			assert.Nil(t, err)
+			_, err = runtimeServiceServer.RevokeProjectRole(adminCtx, &pb.RevokeProjectRoleRequest{
+				ProjectName: projectSpec.Name,
+				Subject:     "other@example.io",
+			})
+			assert.Equal(t, codes.NotFound, status.Code(err))
+		})
+		t.Run("should list role bindings of the project", func(t *testing.T) {
+			roleBindingRepo := new(mock.RoleBindingRepository)
+			roleBindingRepo.On("GetAll").Return([]models.ProjectRoleBinding{
+				{Subject: "admin@example.io", Role: models.RoleAdmin},
+				{Subject: "user@example.io", Role: models.RoleViewer},
+			}, nil)
+			defer roleBindingRepo.AssertExpectations(t)
+			runtimeServiceServer, _ := newServer(t, roleBindingRepo)
+
+			resp, err := runtimeServiceServer.ListProjectRoles(adminCtx, &pb.ListProjectRolesRequest{ProjectName: projectSpec.Name})
+			assert.Nil(t, err)
+			assert.Equal(t, []*pb.ProjectRoleBinding{
+				{Subject: "admin@example.io", Role: "ADMIN"},
+				{Subject: "user@example.io", Role: "VIEWER"},
+			}, resp.GetBindings())
+		})
+	})
+	t.Run("ListAuditLog", func(t *testing.T) {
+		t.Run("should list filtered entries with the default limit", func(t *testing.T) {
+			since := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+			entryTime := since.Add(time.Hour)
+			auditLogRepo := new(mock.AuditLogRepository)
+			auditLogRepo.On("GetAll", mock2.Anything, models.AuditLogFilter{
+				ProjectName: "a-data-project",
+				Principal:   "user@example.io",
+				Since:       since,
+				Limit:       v1.DefaultAuditLogLimit,
+			}).Return([]models.AuditLogEntry{
+				{
+					Time:        entryTime,
+					Principal:   "user@example.io",
+					Method:      "/odpf.optimus.RuntimeService/RegisterProject",
+					ProjectName: "a-data-project",
+					Request:     `{"project":{"name":"a-data-project"}}`,
+					Status:      "OK",
+				},
+			}, nil)
+			defer auditLogRepo.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.0", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+			runtimeServiceServer.AuditLogRepo = auditLogRepo
+			resp, err := runtimeServiceServer.ListAuditLog(context.Background(), &pb.ListAuditLogRequest{
+				ProjectName: "a-data-project",
+				Principal:   "user@example.io",
+				Since:       timestamppb.New(since),
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(resp.GetEntries()))
+			assert.Equal(t, entryTime, resp.GetEntries()[0].GetTime().AsTime())
+			assert.Equal(t, "/odpf.optimus.RuntimeService/RegisterProject", resp.GetEntries()[0].GetMethod())
+		})
+		t.Run("should fail if the server doesn't store the audit log", func(t *testing.T) {
+			runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.0", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+			_, err := runtimeServiceServer.ListAuditLog(context.Background(), &pb.ListAuditLogRequest{})
+			assert.Equal(t, codes.Unimplemented, status.Code(err))
+		})
+	})
+	t.Run("ListSLABreaches", func(t *testing.T) {
+		projectName := "a-data-project"
+		t.Run("should list breaches of the project with the default limit", func(t *testing.T) {
+			since := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+			scheduledAt := since.Add(time.Hour)
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(models.ProjectSpec{Name: projectName}, nil)
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+
+			breachRepo := new(mock.SLABreachRepository)
+			breachRepo.On("GetAll", mock2.Anything, projectName, models.SLABreachFilter{
+				JobName: "transform-tables",
+				Since:   since,
+				Limit:   v1.DefaultSLABreachLimit,
+			}).Return([]models.SLABreach{
+				{
+					ProjectName:   projectName,
+					NamespaceName: "dev-team-1",
+					JobName:       "transform-tables",
+					ScheduledAt:   scheduledAt,
+					DueAt:         scheduledAt.Add(time.Hour),
+					SLADuration:   time.Minute * 30,
+					DetectedAt:    scheduledAt.Add(time.Hour * 2),
+				},
+			}, nil)
+			defer breachRepo.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.0", nil, nil, nil, projectRepoFactory, nil, nil, nil, nil, nil, nil, nil)
+			runtimeServiceServer.SLABreachRepo = breachRepo
+			resp, err := runtimeServiceServer.ListSLABreaches(context.Background(), &pb.ListSLABreachesRequest{
+				ProjectName: projectName,
+				JobName:     "transform-tables",
+				Since:       timestamppb.New(since),
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(resp.GetBreaches()))
+			assert.Equal(t, "dev-team-1", resp.GetBreaches()[0].GetNamespace())
+			assert.Equal(t, scheduledAt, resp.GetBreaches()[0].GetScheduledAt().AsTime())
+			assert.Equal(t, time.Minute*30, resp.GetBreaches()[0].GetSlaDuration().AsDuration())
+			assert.Nil(t, resp.GetBreaches()[0].GetStartedAt())
+		})
+		t.Run("should fail if the project is not registered", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(models.ProjectSpec{}, store.ErrResourceNotFound)
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.0", nil, nil, nil, projectRepoFactory, nil, nil, nil, nil, nil, nil, nil)
+			runtimeServiceServer.SLABreachRepo = new(mock.SLABreachRepository)
+			_, err := runtimeServiceServer.ListSLABreaches(context.Background(), &pb.ListSLABreachesRequest{
+				ProjectName: projectName,
+			})
+			assert.Equal(t, codes.NotFound, status.Code(err))
+		})
+		t.Run("should fail if the server doesn't monitor slas", func(t *testing.T) {
+			runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.0", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+			_, err := runtimeServiceServer.ListSLABreaches(context.Background(), &pb.ListSLABreachesRequest{})
+			assert.Equal(t, codes.Unimplemented, status.Code(err))
+		})
+	})
+	t.Run("SetProjectQuota", func(t *testing.T) {
+		projectName := "a-data-project"
+		quota := models.ProjectQuota{MaxJobCount: 100, MinScheduleInterval: time.Hour}
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("SaveQuota", projectName, quota).Return(nil)
+		projectRepository.On("SaveQuota", "unknown-project", quota).Return(store.ErrResourceNotFound)
+		projectRepository.On("SaveQuota", "unlimited-project", models.ProjectQuota{
+			MaxJobCount: models.QuotaUnlimited,
+		}).Return(nil)
+		defer projectRepository.AssertExpectations(t)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+		runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.0", nil, nil, nil, projectRepoFactory, nil, nil, nil, nil, nil, nil, nil)
+
+		t.Run("should save the quota of the project", func(t *testing.T) {
+			_, err := runtimeServiceServer.SetProjectQuota(context.Background(), &pb.SetProjectQuotaRequest{
+				ProjectName:         projectName,
+				MaxJobCount:         100,
+				MinScheduleInterval: durationpb.New(time.Hour),
+			})
+			assert.Nil(t, err)
+		})
+		t.Run("should fail if the project is not registered", func(t *testing.T) {
+			_, err := runtimeServiceServer.SetProjectQuota(context.Background(), &pb.SetProjectQuotaRequest{
+				ProjectName:         "unknown-project",
+				MaxJobCount:         100,
+				MinScheduleInterval: durationpb.New(time.Hour),
+			})
+			assert.Equal(t, codes.NotFound, status.Code(err))
+		})
+		t.Run("should save negative limits as unlimited", func(t *testing.T) {
+			_, err := runtimeServiceServer.SetProjectQuota(context.Background(), &pb.SetProjectQuotaRequest{
+				ProjectName: "unlimited-project",
+				MaxJobCount: models.QuotaUnlimited,
+			})
+			assert.Nil(t, err)
+		})
+	})
+	t.Run("ProjectVariables", func(t *testing.T) {
+		projectName := "a-data-project"
+		variables := map[string]string{"GCP_PROJECT": "data-project"}
+
+		t.Run("should register variables of the project", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("SaveVariables", projectName, models.ProjectVariables(variables)).Return(nil)
+			projectRepository.On("SaveVariables", "unknown-project", models.ProjectVariables(variables)).Return(store.ErrResourceNotFound)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.0", nil, nil, nil, projectRepoFactory, nil, nil, nil, nil, nil, nil, nil)
+			_, err := runtimeServiceServer.RegisterProjectVariables(context.Background(), &pb.RegisterProjectVariablesRequest{
+				ProjectName: projectName,
+				Variables:   variables,
+			})
+			assert.Nil(t, err)
+			_, err = runtimeServiceServer.RegisterProjectVariables(context.Background(), &pb.RegisterProjectVariablesRequest{
+				ProjectName: "unknown-project",
+				Variables:   variables,
+			})
+			assert.Equal(t, codes.NotFound, status.Code(err))
+		})
+		t.Run("should return variables of the project", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(models.ProjectSpec{
+				Name:      projectName,
+				Variables: variables,
+			}, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.0", nil, nil, nil, projectRepoFactory, nil, nil, nil, nil, nil, nil, nil)
+			resp, err := runtimeServiceServer.GetProjectVariables(context.Background(), &pb.GetProjectVariablesRequest{
+				ProjectName: projectName,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, variables, resp.GetVariables())
+		})
+	})
+	t.Run("ListRunningJobs", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		req := &pb.ListRunningJobsRequest{
+			ProjectName: projectSpec.Name,
+		}
+		newServer := func(projectRepoFactory *mock.ProjectRepoFactory, scheduler *mock.Scheduler) *v1.RuntimeServiceServer {
+			return v1.NewRuntimeServiceServer(
+				"1.0.0",
+				nil, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				scheduler,
+				nil,
+			)
+		}
+
+		t.Run("should return jobs running on the scheduler", func(t *testing.T) {
+			ctx := context.Background()
+			startTime := time.Date(2021, 2, 1, 1, 0, 0, 0, time.UTC)
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			scheduler := new(mock.Scheduler)
+			scheduler.On("GetRunningJobs", ctx, projectSpec).Return([]models.RunningJob{
+				{
+					JobName:       "transform-tables",
+					DAGRunID:      "scheduled__2021-02-01T00:00:00+00:00",
+					StartTime:     startTime,
+					ExecutorState: "running",
+				},
+			}, nil)
+			defer scheduler.AssertExpectations(t)
+
+			resp, err := newServer(projectRepoFactory, scheduler).ListRunningJobs(ctx, req)
+			assert.Nil(t, err)
+			assert.Equal(t, []*pb.RunningJob{
+				{
+					JobName:       "transform-tables",
+					DagRunId:      "scheduled__2021-02-01T00:00:00+00:00",
+					StartTime:     timestamppb.New(startTime),
+					ExecutorState: "running",
+				},
+			}, resp.Jobs)
+		})
+		t.Run("should return not found if project doesn't exist", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(models.ProjectSpec{}, errors.New("project not found"))
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			resp, err := newServer(projectRepoFactory, nil).ListRunningJobs(context.Background(), req)
+			assert.Equal(t, codes.NotFound, status.Code(err))
+			assert.Nil(t, resp)
+		})
+		t.Run("should return unimplemented if scheduler can't list running jobs", func(t *testing.T) {
+			ctx := context.Background()
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			scheduler := new(mock.Scheduler)
+			scheduler.On("GetRunningJobs", ctx, projectSpec).Return(nil, models.ErrUnsupportedSchedulerOperation)
+			defer scheduler.AssertExpectations(t)
+
+			resp, err := newServer(projectRepoFactory, scheduler).ListRunningJobs(ctx, req)
+			assert.Equal(t, codes.Unimplemented, status.Code(err))
+			assert.Nil(t, resp)
+		})
+		t.Run("should return internal error if scheduler call fails", func(t *testing.T) {
+			ctx := context.Background()
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			scheduler := new(mock.Scheduler)
+			scheduler.On("GetRunningJobs", ctx, projectSpec).Return(nil, errors.New("airflow unreachable"))
+			defer scheduler.AssertExpectations(t)
+
+			resp, err := newServer(projectRepoFactory, scheduler).ListRunningJobs(ctx, req)
+			assert.Equal(t, codes.Internal, status.Code(err))
+			assert.Nil(t, resp)
+		})
+	})
+	t.Run("SmartBackfillJob", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		namespaceSpec := models.NamespaceSpec{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Name:        "game_jam",
+			ProjectSpec: projectSpec,
+		}
+		jobSpec := models.JobSpec{
+			Name: "transform-tables",
+		}
+		startDate := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+		endDate := time.Date(2021, 8, 3, 0, 0, 0, 0, time.UTC)
+
+		t.Run("should return backfill plan of missing runs", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			plan := models.BackfillPlan{
+				Job:         jobSpec,
+				MissingRuns: []time.Time{startDate.AddDate(0, 0, 1)},
+				SkippedRuns: []models.BackfillSkippedRun{
+					{ScheduledAt: endDate, Reason: job.BackfillSkipReasonNotDue},
+				},
+				DryRun: true,
+			}
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, namespaceSpec, nil)
+			jobService.On("BackfillMissingRuns", context.Background(), projectSpec, jobSpec.Name, startDate, endDate, true).Return(plan, nil)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.SmartBackfillJob(context.Background(), &pb.SmartBackfillJobRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				StartDate:   "2021-08-01",
+				EndDate:     "2021-08-03",
+				DryRun:      true,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, &pb.SmartBackfillJobResponse{
+				MissingRuns: []*timestamppb.Timestamp{timestamppb.New(startDate.AddDate(0, 0, 1))},
+				SkippedRuns: []*pb.BackfillSkippedRun{
+					{ScheduledAt: timestamppb.New(endDate), Reason: job.BackfillSkipReasonNotDue},
+				},
+				DryRun: true,
+			}, resp)
+		})
+		t.Run("should return invalid argument if end date is before start date", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, namespaceSpec, nil)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.SmartBackfillJob(context.Background(), &pb.SmartBackfillJobRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				StartDate:   "2021-08-03",
+				EndDate:     "2021-08-01",
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Nil(t, resp)
+		})
+		t.Run("should return unavailable if replay queue is full", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, namespaceSpec, nil)
+			jobService.On("BackfillMissingRuns", context.Background(), projectSpec, jobSpec.Name, startDate, startDate, false).
+				Return(models.BackfillPlan{}, job.ErrRequestQueueFull)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.SmartBackfillJob(context.Background(), &pb.SmartBackfillJobRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				StartDate:   "2021-08-01",
+			})
+			assert.Equal(t, codes.Unavailable, status.Code(err))
+			assert.Nil(t, resp)
+		})
+	})
+	t.Run("PinDependencyVersion", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		req := &pb.PinDependencyVersionRequest{
+			ProjectName:    projectSpec.Name,
+			JobName:        "downstream",
+			DependencyName: "upstream",
+			SpecVersion:    "v1",
+		}
+
+		t.Run("should pin dependency of the job", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("PinDependencyVersion", projectSpec, req.JobName, req.DependencyName, req.SpecVersion).Return(nil)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.PinDependencyVersion(context.Background(), req)
+			assert.Nil(t, err)
+			assert.Equal(t, &pb.PinDependencyVersionResponse{Success: true}, resp)
+		})
+		t.Run("should return invalid argument if dependency is not an upstream", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("PinDependencyVersion", projectSpec, req.JobName, req.DependencyName, req.SpecVersion).
+				Return(errors.Wrap(job.ErrNotAnUpstream, "upstream for downstream"))
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.PinDependencyVersion(context.Background(), req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Nil(t, resp)
+		})
+	})
+	t.Run("UnpinDependency", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		req := &pb.UnpinDependencyRequest{
+			ProjectName:    projectSpec.Name,
+			JobName:        "downstream",
+			DependencyName: "upstream",
+		}
+
+		t.Run("should return not found if dependency is not pinned", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("UnpinDependency", projectSpec, req.JobName, req.DependencyName).Return(store.ErrResourceNotFound)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.UnpinDependency(context.Background(), req)
+			assert.Equal(t, codes.NotFound, status.Code(err))
+			assert.Nil(t, resp)
+		})
+	})
+	t.Run("ListDependencyPins", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+
+		t.Run("should list dependency pins of the project", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			createdAt := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+			jobService := new(mock.JobService)
+			jobService.On("ListDependencyPins", projectSpec).Return([]models.DependencyPin{
+				{JobName: "downstream", DependencyName: "upstream", SpecVersion: "v1", CreatedAt: createdAt},
+			}, nil)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.ListDependencyPins(context.Background(), &pb.ListDependencyPinsRequest{ProjectName: projectSpec.Name})
+			assert.Nil(t, err)
+			assert.Equal(t, []*pb.DependencyPin{
+				{JobName: "downstream", DependencyName: "upstream", SpecVersion: "v1", CreatedAt: timestamppb.New(createdAt)},
+			}, resp.Pins)
+		})
+	})
+	t.Run("GetProjectDiagnostics", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+
+		t.Run("should return diagnostics of the project", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			checkedAt := time.Date(2021, 8, 2, 0, 0, 0, 0, time.UTC)
+			projectHealthSvc := new(mock.ProjectHealthService)
+			projectHealthSvc.On("GetProjectHealth", context.Background(), projectSpec).Return(&models.ProjectHealth{
+				Checks: []models.HealthCheck{
+					{Name: models.ProjectHealthCheckDatabase, Status: models.HealthCheckStatusOK, Latency: time.Millisecond},
+					{Name: models.ProjectHealthCheckScheduler, Status: models.HealthCheckStatusFailed, Message: "connection refused"},
+				},
+				UnresolvableDependencies: 2,
+				CheckedAt:                checkedAt,
+			}, nil)
+			defer projectHealthSvc.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				nil, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				projectHealthSvc,
+			)
+
+			resp, err := runtimeServiceServer.GetProjectDiagnostics(context.Background(), &pb.GetProjectDiagnosticsRequest{ProjectName: projectSpec.Name})
+			assert.Nil(t, err)
+			assert.False(t, resp.Healthy)
+			assert.Equal(t, int32(2), resp.UnresolvableDependencies)
+			assert.Nil(t, resp.LastDeployedAt)
+			assert.Equal(t, timestamppb.New(checkedAt), resp.CheckedAt)
+			assert.Equal(t, 2, len(resp.Checks))
+			assert.Equal(t, "ok", resp.Checks[0].Status)
+			assert.Equal(t, time.Millisecond, resp.Checks[0].Latency.AsDuration())
+			assert.Equal(t, "connection refused", resp.Checks[1].Message)
+		})
+		t.Run("should return not found if project doesn't exist", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(models.ProjectSpec{}, store.ErrResourceNotFound)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				nil, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			_, err := runtimeServiceServer.GetProjectDiagnostics(context.Background(), &pb.GetProjectDiagnosticsRequest{ProjectName: projectSpec.Name})
+			assert.Equal(t, codes.NotFound, status.Code(err))
+		})
+	})
+
+	t.Run("ListJobSpecification", func(t *testing.T) {
+		t.Run("should return job specs with their checksums", func(t *testing.T) {
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "a-data-project",
+			}
+			namespaceSpec := models.NamespaceSpec{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "dev-test-namespace-1",
+				ProjectSpec: projectSpec,
+			}
+
+			baseUnit := new(mock.BasePlugin)
+			baseUnit.On("PluginInfo").Return(&models.PluginInfoResponse{Name: "bq2bq"}, nil)
+			jobSpecs := []models.JobSpec{
+				{Name: "job-1", Task: models.JobSpecTask{Unit: &models.Plugin{Base: baseUnit}}},
+				{Name: "job-2", Task: models.JobSpecTask{Unit: &models.Plugin{Base: baseUnit}}},
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetPage", namespaceSpec, "", v1.DefaultJobSpecPageSize+1, map[string]string(nil)).Return(jobSpecs, nil)
+			jobService.On("GetSpecChecksums", []string{"job-1", "job-2"}, namespaceSpec).Return(map[string]string{
+				"job-1": "checksum-1",
+				"job-2": "checksum-2",
+			}, nil)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.ListJobSpecification(context.Background(), &pb.ListJobSpecificationRequest{
+				ProjectName: projectSpec.Name,
+				Namespace:   namespaceSpec.Name,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(resp.Jobs))
+			assert.Equal(t, map[string]string{
+				"job-1": "checksum-1",
+				"job-2": "checksum-2",
+			}, resp.SpecChecksums)
+			assert.Empty(t, resp.NextPageToken)
+		})
+		t.Run("should page through job specs ordered by name", func(t *testing.T) {
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "a-data-project",
+			}
+			namespaceSpec := models.NamespaceSpec{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "dev-test-namespace-1",
+				ProjectSpec: projectSpec,
+			}
+
+			baseUnit := new(mock.BasePlugin)
+			baseUnit.On("PluginInfo").Return(&models.PluginInfoResponse{Name: "bq2bq"}, nil)
+			newJobSpec := func(name string) models.JobSpec {
+				return models.JobSpec{Name: name, Task: models.JobSpecTask{Unit: &models.Plugin{Base: baseUnit}}}
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetPage", namespaceSpec, "", 3, map[string]string(nil)).Return([]models.JobSpec{
+				newJobSpec("job-1"), newJobSpec("job-2"), newJobSpec("job-3"),
+			}, nil)
+			jobService.On("GetPage", namespaceSpec, "job-2", 3, map[string]string(nil)).Return([]models.JobSpec{
+				newJobSpec("job-3"),
+			}, nil)
+			// checksums of each page are read at once
+			jobService.On("GetSpecChecksums", []string{"job-1", "job-2"}, namespaceSpec).Return(map[string]string{
+				"job-1": "checksum-1",
+				"job-2": "checksum-2",
+			}, nil).Once()
+			jobService.On("GetSpecChecksums", []string{"job-3"}, namespaceSpec).Return(map[string]string{"job-3": "checksum-3"}, nil).Once()
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.ListJobSpecification(context.Background(), &pb.ListJobSpecificationRequest{
+				ProjectName: projectSpec.Name,
+				Namespace:   namespaceSpec.Name,
+				PageSize:    2,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"job-1", "job-2"}, []string{resp.Jobs[0].Name, resp.Jobs[1].Name})
+			assert.NotEmpty(t, resp.NextPageToken)
+
+			resp, err = runtimeServiceServer.ListJobSpecification(context.Background(), &pb.ListJobSpecificationRequest{
+				ProjectName: projectSpec.Name,
+				Namespace:   namespaceSpec.Name,
+				PageSize:    2,
+				PageToken:   resp.NextPageToken,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(resp.Jobs))
+			assert.Equal(t, "job-3", resp.Jobs[0].Name)
+			assert.Empty(t, resp.NextPageToken)
+
+			_, err = runtimeServiceServer.ListJobSpecification(context.Background(), &pb.ListJobSpecificationRequest{
+				ProjectName: projectSpec.Name,
+				Namespace:   namespaceSpec.Name,
+				PageToken:   "not a token",
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+		t.Run("should list job specs having all of the labels", func(t *testing.T) {
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "a-data-project",
+			}
+			namespaceSpec := models.NamespaceSpec{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "dev-test-namespace-1",
+				ProjectSpec: projectSpec,
+			}
+
+			baseUnit := new(mock.BasePlugin)
+			baseUnit.On("PluginInfo").Return(&models.PluginInfoResponse{Name: "bq2bq"}, nil)
+			criticalJob := models.JobSpec{
+				Name:   "job-1",
+				Labels: map[string]string{"tier": "critical", "team": "data"},
+				Task:   models.JobSpecTask{Unit: &models.Plugin{Base: baseUnit}},
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetPage", namespaceSpec, "", v1.DefaultJobSpecPageSize+1, map[string]string{
+				"tier": "critical",
+				"team": "data",
+			}).Return([]models.JobSpec{criticalJob}, nil)
+			jobService.On("GetSpecChecksums", []string{"job-1"}, namespaceSpec).Return(map[string]string{"job-1": "checksum-1"}, nil)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.ListJobSpecification(context.Background(), &pb.ListJobSpecificationRequest{
+				ProjectName: projectSpec.Name,
+				Namespace:   namespaceSpec.Name,
+				Labels:      []string{"tier=critical", "team=data"},
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(resp.Jobs))
+			assert.Equal(t, criticalJob.Labels, resp.Jobs[0].Labels)
+
+			_, err = runtimeServiceServer.ListJobSpecification(context.Background(), &pb.ListJobSpecificationRequest{
+				ProjectName: projectSpec.Name,
+				Namespace:   namespaceSpec.Name,
+				Labels:      []string{"tier"},
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	})
+	t.Run("TagRelease", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		req := &pb.TagReleaseRequest{
+			ProjectName: projectSpec.Name,
+			Tag:         "v1.0.0",
+			Description: "first release",
+		}
+
+		t.Run("should tag release of the project", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("TagRelease", projectSpec, req.Tag, req.Description, nil).Return(nil)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.TagRelease(context.Background(), req)
+			assert.Nil(t, err)
+			assert.Equal(t, &pb.TagReleaseResponse{Success: true}, resp)
+		})
+		t.Run("should return already exists if tag is used", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("TagRelease", projectSpec, req.Tag, req.Description, nil).
+				Return(errors.Wrap(store.ErrResourceExists, "release v1.0.0"))
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.TagRelease(context.Background(), req)
+			assert.Equal(t, codes.AlreadyExists, status.Code(err))
+			assert.Nil(t, resp)
+		})
+	})
+	t.Run("RollbackToRelease", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		req := &pb.RollbackToReleaseRequest{
+			ProjectName: projectSpec.Name,
+			Tag:         "v1.0.0",
+		}
+
+		t.Run("should rollback job specs of the project to the release", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("RollbackToRelease", context.Background(), projectSpec, req.Tag, nil).Return(models.Release{
+				Tag: req.Tag,
+				Jobs: []models.ReleaseJob{
+					{Spec: models.JobSpec{Name: "job-a"}},
+					{Spec: models.JobSpec{Name: "job-b"}},
+				},
+			}, nil)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.RollbackToRelease(context.Background(), req)
+			assert.Nil(t, err)
+			assert.Equal(t, &pb.RollbackToReleaseResponse{
+				Success:  true,
+				JobNames: []string{"job-a", "job-b"},
+			}, resp)
+		})
+		t.Run("should return not found if release doesn't exist", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("RollbackToRelease", context.Background(), projectSpec, req.Tag, nil).
+				Return(models.Release{}, errors.Wrap(store.ErrResourceNotFound, "release v1.0.0"))
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.RollbackToRelease(context.Background(), req)
+			assert.Equal(t, codes.NotFound, status.Code(err))
+			assert.Nil(t, resp)
+		})
+	})
+	t.Run("GetInstanceStatusSummary", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		req := &pb.GetInstanceStatusSummaryRequest{
+			ProjectName:   projectSpec.Name,
+			WindowMinutes: 60,
+		}
+
+		t.Run("should return instance counts of the project by state", func(t *testing.T) {
+			ctx := context.Background()
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			counts := map[string]int64{
+				models.InstanceStateRunning: 3,
+				models.InstanceStateSuccess: 10,
+			}
+			instanceService := new(mock.InstanceService)
+			instanceService.On("GetStatusSummary", ctx, projectSpec, time.Hour).Return(counts, nil)
+			defer instanceService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				nil, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				instanceService,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.GetInstanceStatusSummary(ctx, req)
+			assert.Nil(t, err)
+			assert.Equal(t, counts, resp.InstancesByStatus)
+		})
+		t.Run("should return invalid argument if window is not positive", func(t *testing.T) {
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				nil, nil, nil,
+				nil,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.GetInstanceStatusSummary(context.Background(), &pb.GetInstanceStatusSummaryRequest{
+				ProjectName: projectSpec.Name,
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Nil(t, resp)
+		})
+		t.Run("should return internal error if instances can't be counted", func(t *testing.T) {
+			ctx := context.Background()
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			instanceService := new(mock.InstanceService)
+			instanceService.On("GetStatusSummary", ctx, projectSpec, time.Hour).Return(nil, errors.New("db down"))
+			defer instanceService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				nil, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				instanceService,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.GetInstanceStatusSummary(ctx, req)
+			assert.Equal(t, codes.Internal, status.Code(err))
+			assert.Nil(t, resp)
+		})
+	})
+	t.Run("CheckJobSchemaDrift", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		jobSpec := models.JobSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-job",
+		}
+		req := &pb.CheckJobSchemaDriftRequest{
+			ProjectName: projectSpec.Name,
+			JobName:     jobSpec.Name,
+		}
+
+		t.Run("should return schema drift of the job destination", func(t *testing.T) {
+			ctx := context.Background()
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+			jobService.On("DetectSchemaDrift", ctx, projectSpec, jobSpec).Return(&models.SchemaDrift{
+				Destination:    "project:dataset.table",
+				AddedColumns:   []models.SchemaField{{Name: "name", Type: "STRING"}},
+				RemovedColumns: []models.SchemaField{{Name: "code", Type: "STRING"}},
+				TypeChanges:    []models.SchemaTypeChange{{Name: "amount", CurrentType: "INTEGER", ExpectedType: "FLOAT"}},
+			}, nil)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService,
+				nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.CheckJobSchemaDrift(ctx, req)
+			assert.Nil(t, err)
+			assert.Equal(t, &pb.CheckJobSchemaDriftResponse{
+				HasDrift:       true,
+				Destination:    "project:dataset.table",
+				AddedColumns:   []*pb.SchemaDriftColumn{{Name: "name", Type: "STRING"}},
+				RemovedColumns: []*pb.SchemaDriftColumn{{Name: "code", Type: "STRING"}},
+				TypeChanges:    []*pb.SchemaDriftTypeChange{{Name: "amount", CurrentType: "INTEGER", ExpectedType: "FLOAT"}},
+			}, resp)
+		})
+		t.Run("should return failed precondition if the job doesn't support schema drift detection", func(t *testing.T) {
+			ctx := context.Background()
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+			jobService.On("DetectSchemaDrift", ctx, projectSpec, jobSpec).Return(nil, job.ErrSchemaDriftUnsupported)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService,
+				nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.CheckJobSchemaDrift(ctx, req)
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+			assert.Nil(t, resp)
+		})
+		t.Run("should return not found if the project doesn't exist", func(t *testing.T) {
+			ctx := context.Background()
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(models.ProjectSpec{}, errors.New("project not found"))
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				nil,
+				nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.CheckJobSchemaDrift(ctx, req)
+			assert.Equal(t, codes.NotFound, status.Code(err))
+			assert.Nil(t, resp)
+		})
+	})
+	t.Run("EstimateJobCost", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		jobSpec := models.JobSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-job",
+		}
+		req := &pb.EstimateJobCostRequest{
+			ProjectName: projectSpec.Name,
+			JobName:     jobSpec.Name,
+		}
+		newServer := func(t *testing.T, estimate *models.CostEstimate, estimateErr error) *v1.RuntimeServiceServer {
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+			if estimate != nil {
+				jobService.On("EstimateCost", mock2.Anything, projectSpec, jobSpec).Return(estimate, estimateErr)
+			} else {
+				jobService.On("EstimateCost", mock2.Anything, projectSpec, jobSpec).Return(nil, estimateErr)
+			}
+			return newTestServer(t, testServerDeps{jobService: jobService, projects: []models.ProjectSpec{projectSpec}})
+		}
+
+		t.Run("should return the estimated cost of the job", func(t *testing.T) {
+			runtimeServiceServer := newServer(t, &models.CostEstimate{
+				TotalBytesProcessed: 549755813888,
+				EstimatedCostUSD:    2.5,
+			}, nil)
+
+			resp, err := runtimeServiceServer.EstimateJobCost(context.Background(), req)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(549755813888), resp.GetTotalBytesProcessed())
+			assert.Equal(t, 2.5, resp.GetEstimatedCostUsd())
+		})
+		t.Run("should return failed precondition if the job doesn't support cost estimation", func(t *testing.T) {
+			runtimeServiceServer := newServer(t, nil, job.ErrCostEstimateUnsupported)
+
+			_, err := runtimeServiceServer.EstimateJobCost(context.Background(), req)
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		})
+	})
+	t.Run("GetDependencyGraph", func(t *testing.T) {
+		ctx := context.Background()
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		nodeA := models.DependencyGraphNode{ProjectName: projectSpec.Name, JobName: "job-a"}
+		nodeB := models.DependencyGraphNode{ProjectName: projectSpec.Name, JobName: "job-b"}
+		graph := models.DependencyGraph{
+			Nodes: []models.DependencyGraphNode{nodeA, nodeB},
+			Edges: []models.DependencyGraphEdge{
+				{Job: nodeA, Dependency: nodeB, Type: models.JobSpecDependencyTypeIntra},
+			},
+		}
+		newServer := func(t *testing.T, jobService *mock.JobService) *v1.RuntimeServiceServer {
+			return newTestServer(t, testServerDeps{jobService: jobService, projects: []models.ProjectSpec{projectSpec}})
+		}
+
+		t.Run("should render the graph in the requested format", func(t *testing.T) {
+			jobService := new(mock.JobService)
+			jobService.On("GetDependencyGraph", ctx, projectSpec, "job-a", 2).Return(graph, nil)
+
+			resp, err := newServer(t, jobService).GetDependencyGraph(ctx, &pb.GetDependencyGraphRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     "job-a",
+				Depth:       2,
+				Format:      pb.GetDependencyGraphRequest_MERMAID,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, job.RenderDependencyGraphMermaid(graph), resp.GetGraph())
+		})
+		t.Run("should render the graph as dot if the format isn't set", func(t *testing.T) {
+			jobService := new(mock.JobService)
+			jobService.On("GetDependencyGraph", ctx, projectSpec, "", 0).Return(graph, nil)
+
+			resp, err := newServer(t, jobService).GetDependencyGraph(ctx, &pb.GetDependencyGraphRequest{
+				ProjectName: projectSpec.Name,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, job.RenderDependencyGraphDOT(graph), resp.GetGraph())
+		})
+		t.Run("should return not found if the root job isn't in the project", func(t *testing.T) {
+			jobService := new(mock.JobService)
+			jobService.On("GetDependencyGraph", ctx, projectSpec, "job-z", 0).
+				Return(models.DependencyGraph{}, errors.Wrap(models.ErrNoSuchJob, "job-z"))
+
+			_, err := newServer(t, jobService).GetDependencyGraph(ctx, &pb.GetDependencyGraphRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     "job-z",
+			})
+			assert.Equal(t, codes.NotFound, status.Code(err))
+		})
+		t.Run("should return invalid argument for negative depths", func(t *testing.T) {
+			runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.0", nil, nil, nil, nil, nil, nil,
+				v1.NewAdapter(nil, nil), nil, nil, nil, nil)
+			_, err := runtimeServiceServer.GetDependencyGraph(ctx, &pb.GetDependencyGraphRequest{
+				ProjectName: projectSpec.Name,
+				Depth:       -1,
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	})
+	t.Run("ExplainJobDependencies", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		jobSpec := models.JobSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-job",
+		}
+		req := &pb.ExplainJobDependenciesRequest{
+			ProjectName: projectSpec.Name,
+			JobName:     jobSpec.Name,
+		}
+		newServer := func(t *testing.T, trace *models.ResolutionTrace, explainErr error) *v1.RuntimeServiceServer {
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+			if trace != nil {
+				jobService.On("ExplainDependencyResolution", mock2.Anything, projectSpec, jobSpec).Return(trace, explainErr)
+			} else {
+				jobService.On("ExplainDependencyResolution", mock2.Anything, projectSpec, jobSpec).Return(nil, explainErr)
+			}
+			return newTestServer(t, testServerDeps{jobService: jobService, projects: []models.ProjectSpec{projectSpec}})
+		}
+
+		t.Run("should return how the dependencies of the job are resolved", func(t *testing.T) {
+			runtimeServiceServer := newServer(t, &models.ResolutionTrace{
+				JobName:              jobSpec.Name,
+				DeclaredDependencies: []string{"removed-job"},
+				InferenceEnabled:     true,
+				InferredDependencies: []string{"project.dataset.table"},
+				Steps: []models.DependencyResolutionStep{
+					{
+						Source:     models.DependencySourceDeclared,
+						Dependency: "removed-job",
+						Reason:     models.DependencyReasonNotFound,
+					},
+					{
+						Source:      models.DependencySourceInferred,
+						Dependency:  "project.dataset.table",
+						JobName:     "upstream-job",
+						ProjectName: projectSpec.Name,
+						Type:        models.JobSpecDependencyTypeIntra,
+						Included:    true,
+						Reason:      models.DependencyReasonDestinationMatch,
+					},
+				},
+			}, nil)
+
+			resp, err := runtimeServiceServer.ExplainJobDependencies(context.Background(), req)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"removed-job"}, resp.GetDeclaredDependencies())
+			assert.True(t, resp.GetInferenceEnabled())
+			assert.Equal(t, []string{"project.dataset.table"}, resp.GetInferredDependencies())
+			assert.Len(t, resp.GetSteps(), 2)
+			assert.False(t, resp.GetSteps()[0].GetIncluded())
+			assert.Equal(t, models.DependencyReasonNotFound, resp.GetSteps()[0].GetReason())
+			assert.Equal(t, &pb.DependencyResolutionStep{
+				Source:      models.DependencySourceInferred,
+				Dependency:  "project.dataset.table",
+				JobName:     "upstream-job",
+				ProjectName: projectSpec.Name,
+				Type:        models.JobSpecDependencyTypeIntra.String(),
+				Included:    true,
+				Reason:      models.DependencyReasonDestinationMatch,
+			}, resp.GetSteps()[1])
+		})
+		t.Run("should return unimplemented if the resolver can't explain dependencies", func(t *testing.T) {
+			runtimeServiceServer := newServer(t, nil, job.ErrDependencyExplanationUnsupported)
+
+			_, err := runtimeServiceServer.ExplainJobDependencies(context.Background(), req)
+			assert.Equal(t, codes.Unimplemented, status.Code(err))
+		})
+	})
+	t.Run("ValidateSchedule", func(t *testing.T) {
+		runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.0", nil, nil, nil, nil, nil, nil,
+			v1.NewAdapter(nil, nil), nil, nil, nil, nil)
+		runtimeServiceServer.Now = func() time.Time {
+			return time.Date(2021, 2, 27, 10, 30, 0, 0, time.UTC)
+		}
+
+		t.Run("should return the upcoming runs of the schedule", func(t *testing.T) {
+			resp, err := runtimeServiceServer.ValidateSchedule(context.Background(), &pb.ValidateScheduleRequest{
+				Interval: "*/20 * * * *",
+				Count:    3,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"2021-02-27T10:40:00Z", "2021-02-27T11:00:00Z", "2021-02-27T11:20:00Z"},
+				resp.GetNextRuns())
+			assert.Empty(t, resp.GetWarnings())
+		})
+		t.Run("should return the upcoming runs in the timezone of the schedule", func(t *testing.T) {
+			resp, err := runtimeServiceServer.ValidateSchedule(context.Background(), &pb.ValidateScheduleRequest{
+				Interval: "0 2 * * *",
+				Count:    1,
+				Timezone: "Asia/Jakarta",
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"2021-02-28T02:00:00+07:00"}, resp.GetNextRuns())
+		})
+		t.Run("should return invalid argument if the schedule can't be parsed", func(t *testing.T) {
+			_, err := runtimeServiceServer.ValidateSchedule(context.Background(), &pb.ValidateScheduleRequest{
+				Interval: "every day",
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	})
+	t.Run("ListSuppressedJobRuns", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+			BlackoutWindows: []models.BlackoutWindow{
+				{Start: "0 2 * * 0", Duration: time.Hour * 2},
+			},
+		}
+		jobSpec := models.JobSpec{
+			ID:       uuid.Must(uuid.NewRandom()),
+			Name:     "a-data-job",
+			Schedule: models.JobSpecSchedule{Interval: "0 * * * *"},
+		}
+		newServer := func(t *testing.T) *v1.RuntimeServiceServer {
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+			runtimeServiceServer := newTestServer(t, testServerDeps{jobService: jobService, projects: []models.ProjectSpec{projectSpec}})
+			runtimeServiceServer.Now = func() time.Time {
+				return time.Date(2021, 2, 28, 1, 30, 0, 0, time.UTC)
+			}
+			return runtimeServiceServer
+		}
+
+		t.Run("should return the upcoming runs of the job within blackout windows", func(t *testing.T) {
+			resp, err := newServer(t).ListSuppressedJobRuns(context.Background(), &pb.ListSuppressedJobRunsRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				Count:       5,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, []*pb.SuppressedJobRun{
+				{
+					ScheduledAt: "2021-02-28T02:00:00Z",
+					WindowStart: "2021-02-28T02:00:00Z",
+					WindowEnd:   "2021-02-28T04:00:00Z",
+					Window:      "0 2 * * 0",
+				},
+				{
+					ScheduledAt: "2021-02-28T03:00:00Z",
+					WindowStart: "2021-02-28T02:00:00Z",
+					WindowEnd:   "2021-02-28T04:00:00Z",
+					Window:      "0 2 * * 0",
+				},
+			}, resp.GetRuns())
+		})
+		t.Run("should return invalid argument if too many runs are requested", func(t *testing.T) {
+			_, err := newServer(t).ListSuppressedJobRuns(context.Background(), &pb.ListSuppressedJobRunsRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				Count:       1000,
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	})
+	t.Run("TriggerJobRun", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		jobSpec := models.JobSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-job",
+			Schedule: models.JobSpecSchedule{
+				StartDate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				Interval:  "0 2 * * *",
+			},
+		}
+		now := time.Date(2021, 3, 1, 10, 30, 0, 0, time.UTC)
+		logicalDate := time.Date(2021, 3, 1, 2, 0, 0, 0, time.UTC)
+		editorCtx := middleware.ContextWithClaims(context.Background(), jwt.MapClaims{
+			"sub": "data@example.io",
+		})
+		newServer := func(t *testing.T, scheduler *mock.Scheduler, triggerRepo *mock.JobRunTriggerRepository) *v1.RuntimeServiceServer {
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+			runtimeServiceServer := newTestServer(t, testServerDeps{
+				jobService:        jobService,
+				scheduler:         scheduler,
+				projects:          []models.ProjectSpec{projectSpec},
+				jobRunTriggerRepo: triggerRepo,
+			})
+			runtimeServiceServer.Now = func() time.Time { return now }
+			return runtimeServiceServer
+		}
+
+		t.Run("should trigger a run at the logical date and record it", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("ListDeployedJobs", editorCtx, projectSpec).Return([]string{jobSpec.Name}, nil)
+			scheduler.On("TriggerJobRun", editorCtx, projectSpec, jobSpec.Name, logicalDate,
+				map[string]string{"backfill": "true"}).Return("manual__2021-03-01T02:00:00+00:00", nil)
+			triggerRepo := new(mock.JobRunTriggerRepository)
+			triggerRepo.On("Save", editorCtx, models.JobRunTrigger{
+				RunID:       "manual__2021-03-01T02:00:00+00:00",
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				LogicalDate: logicalDate,
+				Config:      map[string]string{"backfill": "true"},
+				Principal:   "data@example.io",
+				TriggeredAt: now,
+			}).Return(nil)
+
+			resp, err := newServer(t, scheduler, triggerRepo).TriggerJobRun(editorCtx, &pb.TriggerJobRunRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				LogicalDate: timestamppb.New(logicalDate),
+				Config:      map[string]string{"backfill": "true"},
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, "manual__2021-03-01T02:00:00+00:00", resp.GetRunId())
+			assert.Equal(t, logicalDate, resp.GetLogicalDate().AsTime())
+		})
+		t.Run("should trigger a run even if it can't be recorded", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("ListDeployedJobs", editorCtx, projectSpec).Return([]string{jobSpec.Name}, nil)
+			scheduler.On("TriggerJobRun", editorCtx, projectSpec, jobSpec.Name, logicalDate, map[string]string(nil)).
+				Return("manual__2021-03-01T02:00:00+00:00", nil)
+			triggerRepo := new(mock.JobRunTriggerRepository)
+			triggerRepo.On("Save", editorCtx, mock2.Anything).Return(errors.New("connection refused"))
+
+			resp, err := newServer(t, scheduler, triggerRepo).TriggerJobRun(editorCtx, &pb.TriggerJobRunRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				LogicalDate: timestamppb.New(logicalDate),
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, "manual__2021-03-01T02:00:00+00:00", resp.GetRunId())
+		})
+		t.Run("should only check the run can be triggered on dry runs", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("ListDeployedJobs", editorCtx, projectSpec).Return([]string{jobSpec.Name}, nil)
+			scheduler.On("GetDagRunStatus", editorCtx, projectSpec, jobSpec.Name, now, now, 1).
+				Return([]models.JobStatus{}, nil)
+			triggerRepo := new(mock.JobRunTriggerRepository)
+
+			resp, err := newServer(t, scheduler, triggerRepo).TriggerJobRun(editorCtx, &pb.TriggerJobRunRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				DryRun:      true,
+			})
+			assert.Nil(t, err)
+			assert.Empty(t, resp.GetRunId())
+			assert.Equal(t, now, resp.GetLogicalDate().AsTime())
+		})
+		t.Run("should return already exists on dry runs if the job has a run at the logical date", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("ListDeployedJobs", editorCtx, projectSpec).Return([]string{jobSpec.Name}, nil)
+			scheduler.On("GetDagRunStatus", editorCtx, projectSpec, jobSpec.Name, logicalDate, logicalDate, 1).
+				Return([]models.JobStatus{{ScheduledAt: logicalDate, State: models.JobStatusStateSuccess}}, nil)
+
+			_, err := newServer(t, scheduler, new(mock.JobRunTriggerRepository)).TriggerJobRun(editorCtx, &pb.TriggerJobRunRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				LogicalDate: timestamppb.New(logicalDate),
+				DryRun:      true,
+			})
+			assert.Equal(t, codes.AlreadyExists, status.Code(err))
+		})
+		t.Run("should return invalid argument if the logical date is before the start date of the job", func(t *testing.T) {
+			_, err := newServer(t, new(mock.Scheduler), new(mock.JobRunTriggerRepository)).TriggerJobRun(editorCtx, &pb.TriggerJobRunRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				LogicalDate: timestamppb.New(time.Date(2020, 12, 31, 2, 0, 0, 0, time.UTC)),
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+		t.Run("should return failed precondition if the job isn't deployed to the scheduler", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("ListDeployedJobs", editorCtx, projectSpec).Return([]string{"another-job"}, nil)
+
+			_, err := newServer(t, scheduler, new(mock.JobRunTriggerRepository)).TriggerJobRun(editorCtx, &pb.TriggerJobRunRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				DryRun:      true,
+			})
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		})
+		t.Run("should return already exists if the job has a run at the logical date", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("ListDeployedJobs", editorCtx, projectSpec).Return([]string(nil), models.ErrUnsupportedSchedulerOperation)
+			scheduler.On("TriggerJobRun", editorCtx, projectSpec, jobSpec.Name, logicalDate, map[string]string(nil)).
+				Return("", models.ErrJobRunExists)
+
+			_, err := newServer(t, scheduler, new(mock.JobRunTriggerRepository)).TriggerJobRun(editorCtx, &pb.TriggerJobRunRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				LogicalDate: timestamppb.New(logicalDate),
+			})
+			assert.Equal(t, codes.AlreadyExists, status.Code(err))
+		})
+	})
+	t.Run("PauseJob", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		jobSpec := models.JobSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-job",
+		}
+		now := time.Date(2021, 3, 1, 10, 30, 0, 0, time.UTC)
+		editorCtx := middleware.ContextWithClaims(context.Background(), jwt.MapClaims{
+			"sub": "data@example.io",
+		})
+		newServer := func(t *testing.T, scheduler *mock.Scheduler, pauseRepo *mock.JobPauseRepository) *v1.RuntimeServiceServer {
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+			runtimeServiceServer := newTestServer(t, testServerDeps{
+				jobService:   jobService,
+				scheduler:    scheduler,
+				projects:     []models.ProjectSpec{projectSpec},
+				jobPauseRepo: pauseRepo,
+			})
+			runtimeServiceServer.Now = func() time.Time { return now }
+			return runtimeServiceServer
+		}
+
+		t.Run("should pause the job on the scheduler and record it", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("SetJobPaused", editorCtx, projectSpec, jobSpec.Name, true).Return(nil)
+			pauseRepo := new(mock.JobPauseRepository)
+			pauseRepo.On("GetPaused", editorCtx, projectSpec.Name).Return([]string{}, nil)
+			pauseRepo.On("Save", editorCtx, projectSpec.Name, []string{jobSpec.Name}, true, "data@example.io", now).Return(nil)
+
+			resp, err := newServer(t, scheduler, pauseRepo).PauseJob(editorCtx, &pb.PauseJobRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+			})
+			assert.Nil(t, err)
+			assert.True(t, resp.GetSuccess())
+		})
+		t.Run("should not record the job paused if the scheduler fails to pause it", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("SetJobPaused", editorCtx, projectSpec, jobSpec.Name, true).Return(errors.New("dag not found"))
+			pauseRepo := new(mock.JobPauseRepository)
+			pauseRepo.On("GetPaused", editorCtx, projectSpec.Name).Return([]string{}, nil)
+
+			_, err := newServer(t, scheduler, pauseRepo).PauseJob(editorCtx, &pb.PauseJobRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+			})
+			assert.Equal(t, codes.Internal, status.Code(err))
+		})
+		t.Run("should resume the job on the scheduler and record it", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("SetJobPaused", editorCtx, projectSpec, jobSpec.Name, false).Return(nil)
+			pauseRepo := new(mock.JobPauseRepository)
+			pauseRepo.On("GetPaused", editorCtx, projectSpec.Name).Return([]string{jobSpec.Name}, nil)
+			pauseRepo.On("Save", editorCtx, projectSpec.Name, []string{jobSpec.Name}, false, "data@example.io", now).Return(nil)
+
+			resp, err := newServer(t, scheduler, pauseRepo).ResumeJob(editorCtx, &pb.ResumeJobRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+			})
+			assert.Nil(t, err)
+			assert.True(t, resp.GetSuccess())
+		})
+	})
+	t.Run("PauseProject", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		namespaceSpecs := []models.NamespaceSpec{
+			{Name: "sales", ProjectSpec: projectSpec},
+			{Name: "marketing", ProjectSpec: projectSpec},
+		}
+		now := time.Date(2021, 3, 1, 10, 30, 0, 0, time.UTC)
+		editorCtx := middleware.ContextWithClaims(context.Background(), jwt.MapClaims{
+			"sub": "data@example.io",
+		})
+		newServer := func(t *testing.T, scheduler *mock.Scheduler, pauseRepo *mock.JobPauseRepository) *v1.RuntimeServiceServer {
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetAll").Return(namespaceSpecs, nil)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetAll", namespaceSpecs[0]).Return([]models.JobSpec{{Name: "daily-orders"}, {Name: "hourly-orders"}}, nil)
+			jobService.On("GetAll", namespaceSpecs[1]).Return([]models.JobSpec{{Name: "daily-campaigns"}}, nil)
+			runtimeServiceServer := newTestServer(t, testServerDeps{
+				jobService:     jobService,
+				scheduler:      scheduler,
+				projects:       []models.ProjectSpec{projectSpec},
+				namespaceRepos: map[string]*mock.NamespaceRepository{projectSpec.Name: namespaceRepository},
+				jobPauseRepo:   pauseRepo,
+			})
+			runtimeServiceServer.Now = func() time.Time { return now }
+			return runtimeServiceServer
+		}
+		jobNames := []string{"daily-orders", "hourly-orders", "daily-campaigns"}
+
+		t.Run("should pause all jobs of the project and record them", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			for _, jobName := range jobNames {
+				scheduler.On("SetJobPaused", editorCtx, projectSpec, jobName, true).Return(nil).Once()
+			}
+			pauseRepo := new(mock.JobPauseRepository)
+			pauseRepo.On("GetPaused", editorCtx, projectSpec.Name).Return([]string{}, nil)
+			pauseRepo.On("Save", editorCtx, projectSpec.Name, jobNames, true, "data@example.io", now).Return(nil)
+
+			resp, err := newServer(t, scheduler, pauseRepo).PauseProject(editorCtx, &pb.PauseProjectRequest{
+				ProjectName: projectSpec.Name,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, jobNames, resp.GetJobNames())
+		})
+		t.Run("should resume the jobs already paused if pausing a job fails", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("SetJobPaused", editorCtx, projectSpec, "daily-orders", true).Return(nil).Once()
+			scheduler.On("SetJobPaused", editorCtx, projectSpec, "hourly-orders", true).Return(errors.New("dag not found")).Once()
+			scheduler.On("SetJobPaused", editorCtx, projectSpec, "daily-orders", false).Return(nil).Once()
+			pauseRepo := new(mock.JobPauseRepository)
+			pauseRepo.On("GetPaused", editorCtx, projectSpec.Name).Return([]string{}, nil)
 
-			namespaceRepository := new(mock.NamespaceRepository)
-			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
-			defer namespaceRepository.AssertExpectations(t)
+			_, err := newServer(t, scheduler, pauseRepo).PauseProject(editorCtx, &pb.PauseProjectRequest{
+				ProjectName: projectSpec.Name,
+			})
+			assert.Equal(t, codes.Internal, status.Code(err))
+		})
+		t.Run("should only pause jobs not paused yet and keep paused jobs paused if pausing fails", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("SetJobPaused", editorCtx, projectSpec, "daily-orders", true).Return(nil).Once()
+			scheduler.On("SetJobPaused", editorCtx, projectSpec, "daily-campaigns", true).Return(errors.New("dag not found")).Once()
+			scheduler.On("SetJobPaused", editorCtx, projectSpec, "daily-orders", false).Return(nil).Once()
+			pauseRepo := new(mock.JobPauseRepository)
+			pauseRepo.On("GetPaused", editorCtx, projectSpec.Name).Return([]string{"hourly-orders"}, nil)
 
-			namespaceRepoFact := new(mock.NamespaceRepoFactory)
-			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
-			defer namespaceRepoFact.AssertExpectations(t)
-			adapter := v1.NewAdapter(nil, nil)
-			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				"Version",
+			_, err := newServer(t, scheduler, pauseRepo).PauseProject(editorCtx, &pb.PauseProjectRequest{
+				ProjectName: projectSpec.Name,
+			})
+			assert.Equal(t, codes.Internal, status.Code(err))
+			scheduler.AssertNotCalled(t, "SetJobPaused", editorCtx, projectSpec, "hourly-orders", false)
+		})
+		t.Run("should record only the jobs whose state changed", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("SetJobPaused", editorCtx, projectSpec, "daily-orders", true).Return(nil).Once()
+			scheduler.On("SetJobPaused", editorCtx, projectSpec, "daily-campaigns", true).Return(nil).Once()
+			pauseRepo := new(mock.JobPauseRepository)
+			pauseRepo.On("GetPaused", editorCtx, projectSpec.Name).Return([]string{"hourly-orders"}, nil)
+			pauseRepo.On("Save", editorCtx, projectSpec.Name, []string{"daily-orders", "daily-campaigns"}, true,
+				"data@example.io", now).Return(nil)
+
+			resp, err := newServer(t, scheduler, pauseRepo).PauseProject(editorCtx, &pb.PauseProjectRequest{
+				ProjectName: projectSpec.Name,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, jobNames, resp.GetJobNames())
+		})
+		t.Run("should pause the jobs again if resuming them can't be recorded", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			for _, jobName := range jobNames {
+				scheduler.On("SetJobPaused", editorCtx, projectSpec, jobName, false).Return(nil).Once()
+				scheduler.On("SetJobPaused", editorCtx, projectSpec, jobName, true).Return(nil).Once()
+			}
+			pauseRepo := new(mock.JobPauseRepository)
+			pauseRepo.On("GetPaused", editorCtx, projectSpec.Name).Return(jobNames, nil)
+			pauseRepo.On("Save", editorCtx, projectSpec.Name, jobNames, false, "data@example.io", now).
+				Return(errors.New("connection refused"))
+
+			_, err := newServer(t, scheduler, pauseRepo).ResumeProject(editorCtx, &pb.ResumeProjectRequest{
+				ProjectName: projectSpec.Name,
+			})
+			assert.Equal(t, codes.Internal, status.Code(err))
+		})
+	})
+	t.Run("DiffJobSpec", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		newServer := func(jobService models.JobService, projectRepoFactory v1.ProjectRepoFactory) *v1.RuntimeServiceServer {
+			return v1.NewRuntimeServiceServer(
+				"1.0.0",
 				jobService,
+				nil, nil,
+				projectRepoFactory,
 				nil,
 				nil,
-				projectRepoFactory,
-				namespaceRepoFact,
+				v1.NewAdapter(nil, nil),
 				nil,
-				adapter,
 				nil,
 				nil,
 				nil,
 			)
-			replayRequest := pb.ReplayRequest{
-				ProjectName: projectName,
-				Namespace:   namespaceSpec.Name,
-				JobName:     jobName,
-				StartDate:   startDate.Format(timeLayout),
-				EndDate:     endDate.Format(timeLayout),
-			}
-			replayResponse, err := runtimeServiceServer.ReplayDryRun(context.TODO(), &replayRequest)
-			assert.NotNil(t, err)
-			assert.Nil(t, replayResponse)
-		})
-	})
-
-	t.Run("Replay", func(t *testing.T) {
-		projectName := "a-data-project"
-		jobName := "a-data-job"
-		timeLayout := "2006-01-02"
-		startDate, _ := time.Parse(timeLayout, "2020-11-25")
-		endDate, _ := time.Parse(timeLayout, "2020-11-28")
-		projectSpec := models.ProjectSpec{
-			ID:   uuid.Must(uuid.NewRandom()),
-			Name: projectName,
-			Config: map[string]string{
-				"bucket": "gs://some_folder",
-			},
-		}
-		namespaceSpec := models.NamespaceSpec{
-			ID:   uuid.Must(uuid.NewRandom()),
-			Name: "dev-test-namespace-1",
-			Config: map[string]string{
-				"bucket": "gs://some_folder",
-			},
-			ProjectSpec: projectSpec,
-		}
-		jobSpec := models.JobSpec{
-			ID:   uuid.Must(uuid.NewRandom()),
-			Name: jobName,
-			Task: models.JobSpecTask{
-				Config: models.JobSpecConfigs{
-					{
-						Name:  "do",
-						Value: "this",
-					},
-				},
-			},
-			Assets: *models.JobAssets{}.New(
-				[]models.JobSpecAsset{
-					{
-						Name:  "query.sql",
-						Value: "select * from 1",
-					},
-				}),
 		}
-		t.Run("should do replay successfully", func(t *testing.T) {
-			replayWorkerRequest := &models.ReplayWorkerRequest{
-				Job:     jobSpec,
-				Start:   startDate,
-				End:     endDate,
-				Project: projectSpec,
-			}
-			randomUUID := "random-uuid"
 
+		t.Run("should return the diff between a version and the current spec", func(t *testing.T) {
+			ctx := context.Background()
 			projectRepository := new(mock.ProjectRepository)
-			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
 			defer projectRepository.AssertExpectations(t)
 
 			projectRepoFactory := new(mock.ProjectRepoFactory)
@@ -1891,291 +5810,490 @@ func TestRuntimeServiceServer(t *testing.T) {
 			defer projectRepoFactory.AssertExpectations(t)
 
 			jobService := new(mock.JobService)
-			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
-			jobService.On("Replay", context.TODO(), replayWorkerRequest).Return(randomUUID, nil)
+			jobService.On("DiffJobSpec", projectSpec, "a-data-job", 2, models.JobSpecVersionCurrent).Return(models.JobSpecDiff{
+				Schedule: []models.JobSpecChange{{Name: "interval", Type: models.JobSpecChangeModified, From: "@daily", To: "@hourly"}},
+				Assets:   []models.JobSpecChange{{Name: "query.sql", Type: models.JobSpecChangeAdded, To: "select 1"}},
+				Hooks: []models.JobSpecHookDiff{
+					{
+						Name:   "transporter",
+						Type:   models.JobSpecChangeModified,
+						Config: []models.JobSpecChange{{Name: "TOPIC", Type: models.JobSpecChangeRemoved, From: "events"}},
+					},
+				},
+			}, nil)
 			defer jobService.AssertExpectations(t)
 
-			namespaceRepository := new(mock.NamespaceRepository)
-			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
-			defer namespaceRepository.AssertExpectations(t)
-
-			namespaceRepoFact := new(mock.NamespaceRepoFactory)
-			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
-			defer namespaceRepoFact.AssertExpectations(t)
-			adapter := v1.NewAdapter(nil, nil)
-			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				"Version",
-				jobService,
-				nil,
-				nil,
-				projectRepoFactory,
-				namespaceRepoFact,
-				nil,
-				adapter,
-				nil,
-				nil,
-				nil,
-			)
-			replayRequest := pb.ReplayRequest{
-				ProjectName: projectName,
-				Namespace:   namespaceSpec.Name,
-				JobName:     jobName,
-				StartDate:   startDate.Format(timeLayout),
-				EndDate:     endDate.Format(timeLayout),
-			}
-			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
+			resp, err := newServer(jobService, projectRepoFactory).DiffJobSpec(ctx, &pb.DiffJobSpecRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     "a-data-job",
+				FromVersion: "2",
+				ToVersion:   "current",
+			})
 			assert.Nil(t, err)
-			assert.Equal(t, randomUUID, replayResponse.Id)
+			assert.Equal(t, &pb.DiffJobSpecResponse{
+				Schedule: []*pb.JobSpecChange{{Name: "interval", Type: pb.JobSpecChange_MODIFIED, From: "@daily", To: "@hourly"}},
+				Assets:   []*pb.JobSpecChange{{Name: "query.sql", Type: pb.JobSpecChange_ADDED, To: "select 1"}},
+				Hooks: []*pb.JobSpecHookDiff{
+					{
+						Name:   "transporter",
+						Type:   pb.JobSpecChange_MODIFIED,
+						Config: []*pb.JobSpecChange{{Name: "TOPIC", Type: pb.JobSpecChange_REMOVED, From: "events"}},
+					},
+				},
+			}, resp)
 		})
-		t.Run("should failed when replay request is invalid", func(t *testing.T) {
+		t.Run("should return invalid argument for an unknown version", func(t *testing.T) {
+			_, err := newServer(nil, nil).DiffJobSpec(context.Background(), &pb.DiffJobSpecRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     "a-data-job",
+				FromVersion: "latest",
+				ToVersion:   "current",
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+		t.Run("should return not found if the version doesn't exist", func(t *testing.T) {
+			ctx := context.Background()
 			projectRepository := new(mock.ProjectRepository)
-			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
 			defer projectRepository.AssertExpectations(t)
 
 			projectRepoFactory := new(mock.ProjectRepoFactory)
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
-			namespaceRepository := new(mock.NamespaceRepository)
-			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(models.NamespaceSpec{}, errors.New("Namespace not found"))
-			defer namespaceRepository.AssertExpectations(t)
+			jobService := new(mock.JobService)
+			jobService.On("DiffJobSpec", projectSpec, "a-data-job", 1, 7).Return(models.JobSpecDiff{},
+				errors.Wrap(store.ErrResourceNotFound, "version 7 of a-data-job"))
+			defer jobService.AssertExpectations(t)
 
-			namespaceRepoFact := new(mock.NamespaceRepoFactory)
-			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
-			defer namespaceRepoFact.AssertExpectations(t)
-			adapter := v1.NewAdapter(nil, nil)
-			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				"Version",
+			_, err := newServer(jobService, projectRepoFactory).DiffJobSpec(ctx, &pb.DiffJobSpecRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     "a-data-job",
+				FromVersion: "1",
+				ToVersion:   "7",
+			})
+			assert.Equal(t, codes.NotFound, status.Code(err))
+		})
+	})
+	t.Run("ListJobsByOwner", func(t *testing.T) {
+		execUnit := new(mock.BasePlugin)
+		execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{Name: "bq2bq"}, nil)
+		jobSpec := models.JobSpec{
+			Name:  "campaign-daily",
+			Owner: "data@example.io",
+			Schedule: models.JobSpecSchedule{
+				StartDate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				Interval:  "@daily",
+			},
+			Task: models.JobSpecTask{
+				Unit: &models.Plugin{Base: execUnit},
+			},
+		}
+		namespaceSpec := models.NamespaceSpec{
+			Name: "marketing-team",
+			ProjectSpec: models.ProjectSpec{
+				Name: "marketing",
+			},
+		}
+		newServer := func(jobService models.JobService) *v1.RuntimeServiceServer {
+			return v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService,
+				nil, nil,
 				nil,
 				nil,
 				nil,
-				projectRepoFactory,
-				namespaceRepoFact,
+				v1.NewAdapter(nil, nil),
 				nil,
-				adapter,
 				nil,
 				nil,
 				nil,
 			)
-			replayRequest := pb.ReplayRequest{
-				ProjectName: projectName,
-				Namespace:   namespaceSpec.Name,
-				JobName:     jobName,
-				StartDate:   startDate.Format(timeLayout),
-				EndDate:     endDate.Format(timeLayout),
-			}
-			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
-			assert.NotNil(t, err)
-			assert.Nil(t, replayResponse)
+		}
+		adminCtx := middleware.ContextWithClaims(context.Background(), jwt.MapClaims{
+			"sub":   "admin@example.io",
+			"roles": []interface{}{"ADMIN"},
+		})
+
+		t.Run("should list jobs of the owner across projects for admins", func(t *testing.T) {
+			jobService := new(mock.JobService)
+			jobService.On("GetJobsByOwner", adminCtx, "data@example.io").Return([]models.JobSpecWithNamespace{
+				{Namespace: namespaceSpec, Spec: jobSpec},
+			}, nil)
+			defer jobService.AssertExpectations(t)
+
+			resp, err := newServer(jobService).ListJobsByOwner(adminCtx, &pb.ListJobsByOwnerRequest{
+				OwnerEmail: "data@example.io",
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(resp.GetJobs()))
+			assert.Equal(t, "marketing", resp.GetJobs()[0].GetProjectName())
+			assert.Equal(t, "marketing-team", resp.GetJobs()[0].GetNamespace())
+			assert.Equal(t, "campaign-daily", resp.GetJobs()[0].GetJob().GetName())
+			assert.Equal(t, "bq2bq", resp.GetJobs()[0].GetJob().GetTaskName())
+		})
+	})
+	t.Run("ReconcileJobInstances", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		req := &pb.ReconcileJobInstancesRequest{
+			ProjectName: projectSpec.Name,
+		}
+		adminCtx := middleware.ContextWithClaims(context.Background(), jwt.MapClaims{
+			"sub":   "admin@example.io",
+			"roles": []interface{}{"ADMIN"},
 		})
-		t.Run("should failed when replay process is failed", func(t *testing.T) {
-			replayWorkerRequest := &models.ReplayWorkerRequest{
-				Job:     jobSpec,
-				Start:   startDate,
-				End:     endDate,
-				Project: projectSpec,
-			}
-			emptyUUID := ""
-			errMessage := "internal error"
 
+		t.Run("should return the number of instances failed", func(t *testing.T) {
 			projectRepository := new(mock.ProjectRepository)
-			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
 			defer projectRepository.AssertExpectations(t)
 
 			projectRepoFactory := new(mock.ProjectRepoFactory)
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
-			jobService := new(mock.JobService)
-			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
-			jobService.On("Replay", context.TODO(), replayWorkerRequest).Return(emptyUUID, errors.New(errMessage))
-			defer jobService.AssertExpectations(t)
-
-			namespaceRepository := new(mock.NamespaceRepository)
-			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
-			defer namespaceRepository.AssertExpectations(t)
+			instanceService := new(mock.InstanceService)
+			instanceService.On("ReconcileDeadInstances", adminCtx, projectSpec).Return(2, nil)
+			defer instanceService.AssertExpectations(t)
 
-			namespaceRepoFact := new(mock.NamespaceRepoFactory)
-			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
-			defer namespaceRepoFact.AssertExpectations(t)
-			adapter := v1.NewAdapter(nil, nil)
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				"Version",
-				jobService,
-				nil,
-				nil,
+				"1.0.0",
+				nil, nil, nil,
 				projectRepoFactory,
-				namespaceRepoFact,
 				nil,
-				adapter,
 				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				instanceService,
 				nil,
 				nil,
 			)
-			replayRequest := pb.ReplayRequest{
-				ProjectName: projectName,
-				Namespace:   namespaceSpec.Name,
-				JobName:     jobName,
-				StartDate:   startDate.Format(timeLayout),
-				EndDate:     endDate.Format(timeLayout),
-			}
-			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
-			assert.NotNil(t, err)
-			assert.Contains(t, err.Error(), errMessage)
-			assert.Equal(t, codes.Internal, status.Code(err))
-			assert.Nil(t, replayResponse)
+
+			resp, err := runtimeServiceServer.ReconcileJobInstances(adminCtx, req)
+			assert.Nil(t, err)
+			assert.Equal(t, int32(2), resp.ReconciledCount)
 		})
-		t.Run("should failed when project is not found", func(t *testing.T) {
-			errMessage := "project not found"
+		t.Run("should return unimplemented if the scheduler isn't set", func(t *testing.T) {
 			projectRepository := new(mock.ProjectRepository)
-			projectRepository.On("GetByName", projectName).Return(models.ProjectSpec{}, errors.New(errMessage))
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
 			defer projectRepository.AssertExpectations(t)
 
 			projectRepoFactory := new(mock.ProjectRepoFactory)
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
-			adapter := v1.NewAdapter(nil, nil)
+			instanceService := new(mock.InstanceService)
+			instanceService.On("ReconcileDeadInstances", adminCtx, projectSpec).Return(0, instance.ErrReconcileUnsupported)
+			defer instanceService.AssertExpectations(t)
+
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				"Version",
+				"1.0.0",
+				nil, nil, nil,
+				projectRepoFactory,
 				nil,
 				nil,
+				v1.NewAdapter(nil, nil),
 				nil,
-				projectRepoFactory,
+				instanceService,
+				nil,
+				nil,
+			)
+
+			_, err := runtimeServiceServer.ReconcileJobInstances(adminCtx, req)
+			assert.Equal(t, codes.Unimplemented, status.Code(err))
+		})
+	})
+	t.Run("UndeleteJobSpecification", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		namespaceSpec := models.NamespaceSpec{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Name:        "dev-test-namespace-1",
+			ProjectSpec: projectSpec,
+		}
+		req := &pb.UndeleteJobSpecificationRequest{
+			ProjectName: projectSpec.Name,
+			Namespace:   namespaceSpec.Name,
+			JobName:     "a-deleted-job",
+		}
+		newServer := func(t *testing.T, jobService models.JobService) *v1.RuntimeServiceServer {
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			return newTestServer(t, testServerDeps{
+				jobService:     jobService,
+				projects:       []models.ProjectSpec{projectSpec},
+				namespaceRepos: map[string]*mock.NamespaceRepository{projectSpec.Name: namespaceRepository},
+			})
+		}
+
+		t.Run("should restore the deleted job", func(t *testing.T) {
+			ctx := context.Background()
+			jobService := new(mock.JobService)
+			jobService.On("Undelete", ctx, namespaceSpec, "a-deleted-job").Return(nil)
+			defer jobService.AssertExpectations(t)
+
+			resp, err := newServer(t, jobService).UndeleteJobSpecification(ctx, req)
+			assert.Nil(t, err)
+			assert.True(t, resp.Success)
+		})
+		t.Run("should return not found if the job isn't deleted", func(t *testing.T) {
+			ctx := context.Background()
+			jobService := new(mock.JobService)
+			jobService.On("Undelete", ctx, namespaceSpec, "a-deleted-job").
+				Return(errors.Wrap(store.ErrResourceNotFound, "failed to undelete spec: a-deleted-job"))
+			defer jobService.AssertExpectations(t)
+
+			_, err := newServer(t, jobService).UndeleteJobSpecification(ctx, req)
+			assert.Equal(t, codes.NotFound, status.Code(err))
+		})
+	})
+	t.Run("PurgeDeletedJobSpecifications", func(t *testing.T) {
+		req := &pb.PurgeDeletedJobSpecificationsRequest{
+			OlderThanDays: 30,
+		}
+		newServer := func(jobService models.JobService) *v1.RuntimeServiceServer {
+			return v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
 				nil,
 				nil,
-				adapter,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
 				nil,
 				nil,
 				nil,
 			)
-			replayRequest := pb.ReplayRequest{
-				ProjectName: projectName,
-				Namespace:   namespaceSpec.Name,
-				JobName:     jobName,
-				StartDate:   startDate.Format(timeLayout),
-				EndDate:     endDate.Format(timeLayout),
+		}
+		adminCtx := middleware.ContextWithClaims(context.Background(), jwt.MapClaims{
+			"sub":   "admin@example.io",
+			"roles": []interface{}{"ADMIN"},
+		})
+
+		t.Run("should return the number of purged jobs", func(t *testing.T) {
+			jobService := new(mock.JobService)
+			jobService.On("PurgeDeleted", adminCtx, 30*24*time.Hour).Return(3, nil)
+			defer jobService.AssertExpectations(t)
+
+			resp, err := newServer(jobService).PurgeDeletedJobSpecifications(adminCtx, req)
+			assert.Nil(t, err)
+			assert.Equal(t, int32(3), resp.PurgedCount)
+		})
+		t.Run("should return unimplemented if jobs of all projects can't be purged", func(t *testing.T) {
+			jobService := new(mock.JobService)
+			jobService.On("PurgeDeleted", adminCtx, 30*24*time.Hour).Return(0, job.ErrCrossProjectPurgeUnsupported)
+			defer jobService.AssertExpectations(t)
+
+			_, err := newServer(jobService).PurgeDeletedJobSpecifications(adminCtx, req)
+			assert.Equal(t, codes.Unimplemented, status.Code(err))
+		})
+	})
+	t.Run("CloneJobSpecification", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		namespaceSpec := models.NamespaceSpec{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Name:        "dev-test-namespace-1",
+			ProjectSpec: projectSpec,
+		}
+		newServer := func(t *testing.T, jobService models.JobService) *v1.RuntimeServiceServer {
+			return newTestServer(t, testServerDeps{jobService: jobService, projects: []models.ProjectSpec{projectSpec}})
+		}
+		req := &pb.CloneJobSpecificationRequest{
+			ProjectName: projectSpec.Name,
+			SourceJob:   "a-data-job",
+			DestJob:     "a-data-job-copy",
+		}
+
+		t.Run("should return the cloned job spec", func(t *testing.T) {
+			execUnit := new(mock.BasePlugin)
+			execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{Name: "a-data-task"}, nil)
+			defer execUnit.AssertExpectations(t)
+			clonedSpec := models.JobSpec{
+				Name: "a-data-job-copy",
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{Base: execUnit},
+				},
 			}
-			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
-			assert.NotNil(t, err)
-			assert.Contains(t, err.Error(), errMessage)
-			assert.Nil(t, replayResponse)
+
+			jobService := new(mock.JobService)
+			jobService.On("CloneJobSpec", projectSpec, "a-data-job", "a-data-job-copy").Return(clonedSpec, namespaceSpec, nil)
+			defer jobService.AssertExpectations(t)
+
+			resp, err := newServer(t, jobService).CloneJobSpecification(context.Background(), req)
+			assert.Nil(t, err)
+			assert.Equal(t, "a-data-job-copy", resp.Spec.Name)
+			assert.Equal(t, "a-data-task", resp.Spec.TaskName)
+			assert.Equal(t, namespaceSpec.Name, resp.Namespace)
 		})
-		t.Run("should failed when job is not found in the namespace", func(t *testing.T) {
-			errMessage := "job not found in namespace"
+		t.Run("should return already exists if the cloned job is registered", func(t *testing.T) {
+			jobService := new(mock.JobService)
+			jobService.On("CloneJobSpec", projectSpec, "a-data-job", "a-data-job-copy").
+				Return(models.JobSpec{}, models.NamespaceSpec{}, errors.Wrap(job.ErrJobAlreadyExists, "failed to clone job"))
+			defer jobService.AssertExpectations(t)
 
-			projectRepository := new(mock.ProjectRepository)
-			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
-			defer projectRepository.AssertExpectations(t)
+			_, err := newServer(t, jobService).CloneJobSpecification(context.Background(), req)
+			assert.Equal(t, codes.AlreadyExists, status.Code(err))
+		})
+		t.Run("should return not found if the source job isn't registered", func(t *testing.T) {
+			jobService := new(mock.JobService)
+			jobService.On("CloneJobSpec", projectSpec, "a-data-job", "a-data-job-copy").
+				Return(models.JobSpec{}, models.NamespaceSpec{}, errors.Wrap(store.ErrResourceNotFound, "failed to find job a-data-job"))
+			defer jobService.AssertExpectations(t)
 
-			projectRepoFactory := new(mock.ProjectRepoFactory)
-			projectRepoFactory.On("New").Return(projectRepository)
-			defer projectRepoFactory.AssertExpectations(t)
+			_, err := newServer(t, jobService).CloneJobSpecification(context.Background(), req)
+			assert.Equal(t, codes.NotFound, status.Code(err))
+		})
+	})
+	t.Run("CloneProject", func(t *testing.T) {
+		prodProject := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "prod",
+		}
+		stagingProject := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "staging",
+		}
+		prodNamespaces := []models.NamespaceSpec{{Name: "team-a", ProjectSpec: prodProject}}
+		stagingNamespaces := []models.NamespaceSpec{{Name: "team-a", ProjectSpec: stagingProject}}
+		req := &pb.CloneProjectRequest{
+			SourceProjectName: prodProject.Name,
+			DestProjectName:   stagingProject.Name,
+			Replacements: []*pb.CloneProjectRequest_Replacement{
+				{Find: "prod_dataset", Replace: "staging_dataset"},
+			},
+		}
+		replacements := []models.StringReplacement{{Find: "prod_dataset", Replace: "staging_dataset"}}
+		newServer := func(t *testing.T, jobService models.JobService) *v1.RuntimeServiceServer {
+			prodNamespaceRepo := new(mock.NamespaceRepository)
+			prodNamespaceRepo.On("GetAll").Return(prodNamespaces, nil)
+			stagingNamespaceRepo := new(mock.NamespaceRepository)
+			stagingNamespaceRepo.On("GetAll").Return(stagingNamespaces, nil)
+			return newTestServer(t, testServerDeps{
+				jobService: jobService,
+				projects:   []models.ProjectSpec{prodProject, stagingProject},
+				namespaceRepos: map[string]*mock.NamespaceRepository{
+					prodProject.Name:    prodNamespaceRepo,
+					stagingProject.Name: stagingNamespaceRepo,
+				},
+			})
+		}
 
-			namespaceRepository := new(mock.NamespaceRepository)
-			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
-			defer namespaceRepository.AssertExpectations(t)
+		t.Run("should return the number of jobs created", func(t *testing.T) {
+			ctx := context.Background()
+			jobService := new(mock.JobService)
+			jobService.On("CloneProject", ctx, prodNamespaces, stagingNamespaces, replacements).Return(4, nil)
+			defer jobService.AssertExpectations(t)
 
+			resp, err := newServer(t, jobService).CloneProject(ctx, req)
+			assert.Nil(t, err)
+			assert.Equal(t, int32(4), resp.CreatedCount)
+		})
+		t.Run("should return already exists if the destination project has jobs of the same names", func(t *testing.T) {
+			ctx := context.Background()
 			jobService := new(mock.JobService)
-			jobService.On("GetByName", jobName, namespaceSpec).Return(models.JobSpec{}, errors.New(errMessage))
+			jobService.On("CloneProject", ctx, prodNamespaces, stagingNamespaces, replacements).
+				Return(0, errors.Wrap(job.ErrJobAlreadyExists, "jobs daily-orders of project staging"))
 			defer jobService.AssertExpectations(t)
 
-			namespaceRepoFact := new(mock.NamespaceRepoFactory)
-			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
-			defer namespaceRepoFact.AssertExpectations(t)
-			adapter := v1.NewAdapter(nil, nil)
+			_, err := newServer(t, jobService).CloneProject(ctx, req)
+			assert.Equal(t, codes.AlreadyExists, status.Code(err))
+		})
+		t.Run("should fail for empty find strings", func(t *testing.T) {
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				"Version",
-				jobService,
+				"1.0.0",
+				nil, nil, nil,
 				nil,
 				nil,
-				projectRepoFactory,
-				namespaceRepoFact,
 				nil,
-				adapter,
+				v1.NewAdapter(nil, nil),
+				nil,
 				nil,
 				nil,
 				nil,
 			)
-			replayRequest := pb.ReplayRequest{
-				ProjectName: projectName,
-				Namespace:   namespaceSpec.Name,
-				JobName:     jobName,
-				StartDate:   startDate.Format(timeLayout),
-				EndDate:     endDate.Format(timeLayout),
-			}
-			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
-			assert.NotNil(t, err)
-			assert.Contains(t, err.Error(), errMessage)
-			assert.Nil(t, replayResponse)
+			_, err := runtimeServiceServer.CloneProject(context.Background(), &pb.CloneProjectRequest{
+				SourceProjectName: prodProject.Name,
+				DestProjectName:   stagingProject.Name,
+				Replacements:      []*pb.CloneProjectRequest_Replacement{{Find: "", Replace: "staging"}},
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
 		})
-		t.Run("should failed when replay validation is failed", func(t *testing.T) {
-			replayWorkerRequest := &models.ReplayWorkerRequest{
-				Job:     jobSpec,
-				Start:   startDate,
-				End:     endDate,
-				Project: projectSpec,
-			}
-			emptyUUID := ""
+	})
+	t.Run("GetReplayStatus", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-project",
+		}
+		jobSpec := models.JobSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "a-data-job",
+		}
+		replayID := uuid.Must(uuid.NewRandom())
+		req := &pb.GetReplayStatusRequest{
+			ProjectName: projectSpec.Name,
+			JobName:     jobSpec.Name,
+			Id:          replayID.String(),
+		}
 
+		t.Run("should return the status of the replay", func(t *testing.T) {
 			projectRepository := new(mock.ProjectRepository)
-			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
 			defer projectRepository.AssertExpectations(t)
 
 			projectRepoFactory := new(mock.ProjectRepoFactory)
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
+			startDate, _ := time.Parse(job.ReplayDateFormat, "2020-08-22")
+			endDate, _ := time.Parse(job.ReplayDateFormat, "2020-08-26")
 			jobService := new(mock.JobService)
-			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
-			jobService.On("Replay", context.TODO(), replayWorkerRequest).Return(emptyUUID, job.ErrConflictedJobRun)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+			jobService.On("GetReplay", jobSpec, replayID).Return(models.ReplaySpec{
+				ID:        replayID,
+				Job:       jobSpec,
+				StartDate: startDate,
+				EndDate:   endDate,
+				Status:    models.ReplayStatusFailed,
+				Message:   models.ReplayMessage{Type: job.ReplayRunTimeout, Message: "replay has been running since 2020-08-27T00:00:00+00:00"},
+			}, nil)
 			defer jobService.AssertExpectations(t)
 
-			namespaceRepository := new(mock.NamespaceRepository)
-			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
-			defer namespaceRepository.AssertExpectations(t)
-
-			namespaceRepoFact := new(mock.NamespaceRepoFactory)
-			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
-			defer namespaceRepoFact.AssertExpectations(t)
-			adapter := v1.NewAdapter(nil, nil)
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				"Version",
+				"1.0.0",
 				jobService,
+				nil, nil,
+				projectRepoFactory,
 				nil,
 				nil,
-				projectRepoFactory,
-				namespaceRepoFact,
+				v1.NewAdapter(nil, nil),
 				nil,
-				adapter,
 				nil,
 				nil,
 				nil,
 			)
-			replayRequest := pb.ReplayRequest{
-				ProjectName: projectName,
-				Namespace:   namespaceSpec.Name,
-				JobName:     jobName,
-				StartDate:   startDate.Format(timeLayout),
-				EndDate:     endDate.Format(timeLayout),
-			}
-			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
-			assert.NotNil(t, err)
-			assert.Contains(t, err.Error(), job.ErrConflictedJobRun.Error())
-			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
-			assert.Nil(t, replayResponse)
-		})
-		t.Run("should failed when request queue is full", func(t *testing.T) {
-			replayWorkerRequest := &models.ReplayWorkerRequest{
-				Job:     jobSpec,
-				Start:   startDate,
-				End:     endDate,
-				Project: projectSpec,
-			}
-			emptyUUID := ""
 
+			resp, err := runtimeServiceServer.GetReplayStatus(context.Background(), req)
+			assert.Nil(t, err)
+			assert.Equal(t, &pb.GetReplayStatusResponse{
+				Id:        replayID.String(),
+				JobName:   jobSpec.Name,
+				StartDate: "2020-08-22",
+				EndDate:   "2020-08-26",
+				Status:    models.ReplayStatusFailed,
+				Message:   "replay has been running since 2020-08-27T00:00:00+00:00",
+			}, resp)
+		})
+		t.Run("should return not found if the replay doesn't exist", func(t *testing.T) {
 			projectRepository := new(mock.ProjectRepository)
-			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
 			defer projectRepository.AssertExpectations(t)
 
 			projectRepoFactory := new(mock.ProjectRepoFactory)
@@ -2183,43 +6301,104 @@ func TestRuntimeServiceServer(t *testing.T) {
 			defer projectRepoFactory.AssertExpectations(t)
 
 			jobService := new(mock.JobService)
-			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
-			jobService.On("Replay", context.TODO(), replayWorkerRequest).Return(emptyUUID, job.ErrRequestQueueFull)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+			jobService.On("GetReplay", jobSpec, replayID).Return(models.ReplaySpec{}, store.ErrResourceNotFound)
 			defer jobService.AssertExpectations(t)
 
-			namespaceRepository := new(mock.NamespaceRepository)
-			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
-			defer namespaceRepository.AssertExpectations(t)
-
-			namespaceRepoFact := new(mock.NamespaceRepoFactory)
-			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
-			defer namespaceRepoFact.AssertExpectations(t)
-			adapter := v1.NewAdapter(nil, nil)
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				"Version",
+				"1.0.0",
 				jobService,
+				nil, nil,
+				projectRepoFactory,
 				nil,
 				nil,
-				projectRepoFactory,
-				namespaceRepoFact,
+				v1.NewAdapter(nil, nil),
 				nil,
-				adapter,
 				nil,
 				nil,
 				nil,
 			)
-			replayRequest := pb.ReplayRequest{
-				ProjectName: projectName,
-				Namespace:   namespaceSpec.Name,
-				JobName:     jobName,
-				StartDate:   startDate.Format(timeLayout),
-				EndDate:     endDate.Format(timeLayout),
-			}
-			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
-			assert.NotNil(t, err)
-			assert.Contains(t, err.Error(), job.ErrRequestQueueFull.Error())
-			assert.Equal(t, codes.Unavailable, status.Code(err))
-			assert.Nil(t, replayResponse)
+
+			resp, err := runtimeServiceServer.GetReplayStatus(context.Background(), req)
+			assert.Equal(t, codes.NotFound, status.Code(err))
+			assert.Nil(t, resp)
+		})
+		t.Run("should return invalid argument for a malformed replay id", func(t *testing.T) {
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				nil,
+				nil, nil,
+				nil,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			resp, err := runtimeServiceServer.GetReplayStatus(context.Background(), &pb.GetReplayStatusRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				Id:          "not-a-uuid",
+			})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Nil(t, resp)
 		})
 	})
 }
+
+// testServerDeps are the dependencies newTestServer builds a server with,
+// dependencies left nil aren't used by the calls under test
+type testServerDeps struct {
+	jobService models.JobService
+	scheduler  models.SchedulerUnit
+	adapter    v1.ProtoAdapter
+
+	// projects are found by name, the namespaces of a project by the
+	// repository under its name in namespaceRepos
+	projects       []models.ProjectSpec
+	namespaceRepos map[string]*mock.NamespaceRepository
+
+	jobPauseRepo           store.JobPauseRepository
+	jobRunTriggerRepo      store.JobRunTriggerRepository
+	roleBindingRepoFactory v1.RoleBindingRepoFactory
+}
+
+// newTestServer builds a server with repositories finding the projects of
+// deps, expectations of the repositories and of the mocks in deps are asserted
+// when the test ends
+func newTestServer(t *testing.T, deps testServerDeps) *v1.RuntimeServiceServer {
+	projectRepository := new(mock.ProjectRepository)
+	projectRepoFactory := new(mock.ProjectRepoFactory)
+	projectRepoFactory.On("New").Return(projectRepository)
+	namespaceRepoFactory := new(mock.NamespaceRepoFactory)
+	expectations := []interface{}{projectRepository, projectRepoFactory, namespaceRepoFactory, deps.jobService,
+		deps.scheduler, deps.jobPauseRepo, deps.jobRunTriggerRepo, deps.roleBindingRepoFactory}
+	for _, projectSpec := range deps.projects {
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		if namespaceRepo, ok := deps.namespaceRepos[projectSpec.Name]; ok {
+			namespaceRepoFactory.On("New", projectSpec).Return(namespaceRepo)
+			expectations = append(expectations, namespaceRepo)
+		}
+	}
+	t.Cleanup(func() {
+		for _, m := range expectations {
+			if m, ok := m.(interface{ AssertExpectations(mock2.TestingT) bool }); ok {
+				m.AssertExpectations(t)
+			}
+		}
+	})
+
+	adapter := deps.adapter
+	if adapter == nil {
+		adapter = v1.NewAdapter(nil, nil)
+	}
+	runtimeServiceServer := v1.NewRuntimeServiceServer("1.0.0", deps.jobService, nil, nil, projectRepoFactory,
+		namespaceRepoFactory, nil, adapter, nil, nil, deps.scheduler, nil)
+	runtimeServiceServer.JobPauseRepo = deps.jobPauseRepo
+	runtimeServiceServer.JobRunTriggerRepo = deps.jobRunTriggerRepo
+	runtimeServiceServer.RoleBindingRepoFactory = deps.roleBindingRepoFactory
+	return runtimeServiceServer
+}
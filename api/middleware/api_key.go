@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// headerAPIKey carries the api key of machine callers, in place of a
+	// bearer token
+	headerAPIKey = "x-api-key"
+
+	apiKeyPrefix = "optimus_"
+)
+
+type apiKeyContextKey struct{}
+
+// APIKeyFromContext returns the api key the call was authenticated with
+func APIKeyFromContext(ctx context.Context) (models.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey{}).(models.APIKey)
+	return key, ok
+}
+
+// ContextWithAPIKey returns a copy of ctx carrying the api key the call was
+// authenticated with
+func ContextWithAPIKey(ctx context.Context, key models.APIKey) context.Context {
+	ctx = context.WithValue(ctx, apiKeyContextKey{}, key)
+	setAuditPrincipal(ctx)
+	return ctx
+}
+
+// GenerateAPIKey returns a new random api key and the hash it is stored by
+func GenerateAPIKey() (key, keyHash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", errors.Wrap(err, "failed to generate api key")
+	}
+	key = apiKeyPrefix + hex.EncodeToString(b)
+	return key, HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hex encoded sha256 hash of key, keys are looked up
+// by it so they are never stored
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyStore looks up api keys of all projects by their hash
+type APIKeyStore interface {
+	GetByHash(ctx context.Context, keyHash string) (models.APIKey, error)
+}
+
+// APIKeyAuthenticator verifies the api key in the x-api-key header of calls
+type APIKeyAuthenticator struct {
+	keys APIKeyStore
+	Now  func() time.Time
+}
+
+// Authenticate adds the api key of the call to the context, calls with an
+// unknown or expired key fail with codes.Unauthenticated
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context) (context.Context, error) {
+	values := apiKeyValues(ctx)
+	if len(values) == 0 || values[0] == "" {
+		return nil, status.Error(codes.Unauthenticated, "invalid api key: missing x-api-key header")
+	}
+	key, err := a.keys.GetByHash(ctx, HashAPIKey(values[0]))
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, status.Error(codes.Unauthenticated, "invalid api key: unknown key")
+		}
+		return nil, status.Errorf(codes.Internal, "%s: failed to verify api key", err.Error())
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(a.Now()) {
+		return nil, status.Error(codes.Unauthenticated, "invalid api key: key has expired")
+	}
+	return ContextWithAPIKey(ctx, key), nil
+}
+
+// GatewayHeaderMatcher forwards the api key header of http calls to grpc
+// along with the headers forwarded by default
+func GatewayHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, headerAPIKey) {
+		return headerAPIKey, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+func apiKeyValues(ctx context.Context) []string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+	return md.Get(headerAPIKey)
+}
+
+// hasAPIKey reports if the call carries an api key instead of a token
+func hasAPIKey(ctx context.Context) bool {
+	return len(apiKeyValues(ctx)) > 0
+}
+
+func NewAPIKeyAuthenticator(keys APIKeyStore) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{
+		keys: keys,
+		Now:  time.Now,
+	}
+}
@@ -0,0 +1,113 @@
+package middleware_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/api/middleware"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type apiKeyStore map[string]models.APIKey
+
+func (s apiKeyStore) GetByHash(ctx context.Context, keyHash string) (models.APIKey, error) {
+	key, ok := s[keyHash]
+	if !ok {
+		return models.APIKey{}, store.ErrResourceNotFound
+	}
+	return key, nil
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	expiredAt := now.Add(-time.Minute)
+	ciKey := models.APIKey{ID: uuid.New(), ProjectName: "a-data-project", Role: models.RoleEditor}
+	keys := apiKeyStore{
+		middleware.HashAPIKey("optimus_ci"):      ciKey,
+		middleware.HashAPIKey("optimus_expired"): {ID: uuid.New(), ProjectName: "a-data-project", ExpiresAt: &expiredAt},
+	}
+	authenticator := middleware.NewAPIKeyAuthenticator(keys)
+	authenticator.Now = func() time.Time { return now }
+	withKey := func(key string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", key))
+	}
+
+	t.Run("should generate keys stored by their hash", func(t *testing.T) {
+		key, keyHash, err := middleware.GenerateAPIKey()
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(key, "optimus_"))
+		assert.Equal(t, middleware.HashAPIKey(key), keyHash)
+		assert.NotContains(t, keyHash, key)
+
+		otherKey, _, err := middleware.GenerateAPIKey()
+		assert.Nil(t, err)
+		assert.NotEqual(t, key, otherKey)
+	})
+	t.Run("should forward the api key header of http calls", func(t *testing.T) {
+		key, ok := middleware.GatewayHeaderMatcher("X-Api-Key")
+		assert.True(t, ok)
+		assert.Equal(t, "x-api-key", key)
+		key, ok = middleware.GatewayHeaderMatcher("Authorization")
+		assert.True(t, ok)
+		assert.Equal(t, "grpcgateway-Authorization", key)
+		_, ok = middleware.GatewayHeaderMatcher("X-Forwarded-Host")
+		assert.False(t, ok)
+	})
+	t.Run("should add the api key of the call to the context", func(t *testing.T) {
+		ctx, err := authenticator.Authenticate(withKey("optimus_ci"))
+		assert.Nil(t, err)
+		key, ok := middleware.APIKeyFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, ciKey, key)
+		assert.Equal(t, "apikey/"+ciKey.ID.String(), middleware.PrincipalFromContext(ctx))
+	})
+	t.Run("should reject calls", func(t *testing.T) {
+		cases := map[string]context.Context{
+			"without api key":      context.Background(),
+			"with unknown api key": withKey("optimus_unknown"),
+			"with expired api key": withKey("optimus_expired"),
+		}
+		for name, ctx := range cases {
+			t.Run(name, func(t *testing.T) {
+				_, err := authenticator.Authenticate(ctx)
+				assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			})
+		}
+	})
+	t.Run("should authenticate calls with an api key in place of a token", func(t *testing.T) {
+		jwtAuthenticator := middleware.NewJWTAuthenticator(nil, "", "")
+		jwtAuthenticator.APIKeys = authenticator
+		resp, err := jwtAuthenticator.UnaryServerInterceptor()(withKey("optimus_ci"), "req", &grpc.UnaryServerInfo{},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				_, ok := middleware.APIKeyFromContext(ctx)
+				return ok, nil
+			})
+		assert.Nil(t, err)
+		assert.Equal(t, true, resp)
+	})
+	t.Run("should authorize api keys with their role in their project only", func(t *testing.T) {
+		authorizer := middleware.NewMethodAuthorizer(map[string]models.Role{
+			"/odpf.optimus.RuntimeService/DeleteJobSpecification": models.RoleEditor,
+			"/odpf.optimus.RuntimeService/RotateSecrets":          models.RoleAdmin,
+		}, models.RoleNone)
+		ctx := middleware.ContextWithAPIKey(context.Background(), ciKey)
+
+		assert.Nil(t, authorizer.Authorize(ctx, "/odpf.optimus.RuntimeService/DeleteJobSpecification",
+			&pb.DeleteJobSpecificationRequest{ProjectName: "a-data-project"}))
+		err := authorizer.Authorize(ctx, "/odpf.optimus.RuntimeService/DeleteJobSpecification",
+			&pb.DeleteJobSpecificationRequest{ProjectName: "other-data-project"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		err = authorizer.Authorize(ctx, "/odpf.optimus.RuntimeService/RotateSecrets", &pb.RotateSecretsRequest{})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
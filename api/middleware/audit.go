@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/odpf/optimus/models"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// AuditRequestMaxSize is the number of bytes of a request kept in the audit
+	// log, larger requests are truncated
+	AuditRequestMaxSize = 4 * 1024
+
+	// auditWriteTimeout bounds recording a call, calls are recorded after
+	// they returned so the context of the call can't be used
+	auditWriteTimeout = 10 * time.Second
+)
+
+// AuditLogWriter persists entries of the audit log
+type AuditLogWriter interface {
+	Save(ctx context.Context, entry models.AuditLogEntry) error
+}
+
+// Auditor records calls of mutating methods in the audit log once they
+// return, along with the status code they returned. It runs before calls are
+// authenticated so rejected calls are recorded too. Calls are recorded in
+// background, failing to record a call doesn't fail it and is logged
+type Auditor struct {
+	writer   AuditLogWriter
+	mutating func(fullMethod string) bool
+	log      logrus.FieldLogger
+	wg       sync.WaitGroup
+
+	// Now defaults to time.Now
+	Now func() time.Time
+}
+
+type auditCallContextKey struct{}
+
+// auditCall is the caller of a call being audited, it is only known once
+// interceptors after the auditor authenticated the call
+type auditCall struct {
+	mu        sync.Mutex
+	principal string
+}
+
+// setAuditPrincipal records the caller of the call audited with ctx, ctx
+// carries the credentials the call was authenticated with
+func setAuditPrincipal(ctx context.Context) {
+	if call, ok := ctx.Value(auditCallContextKey{}).(*auditCall); ok {
+		call.mu.Lock()
+		call.principal = PrincipalFromContext(ctx)
+		call.mu.Unlock()
+	}
+}
+
+// startAuditCall marks ctx as the context of a call being audited
+func startAuditCall(ctx context.Context) (context.Context, *auditCall) {
+	call := &auditCall{}
+	return context.WithValue(ctx, auditCallContextKey{}, call), call
+}
+
+func (a *Auditor) record(ctx context.Context, call *auditCall, fullMethod string, req interface{}, err error) {
+	call.mu.Lock()
+	principal := call.principal
+	call.mu.Unlock()
+	// callers of servers without authentication are told apart by address
+	if principal == "" {
+		principal = PrincipalFromContext(ctx)
+	}
+	entry := models.AuditLogEntry{
+		Time:      a.Now(),
+		Principal: principal,
+		Method:    fullMethod,
+		Status:    status.Code(err).String(),
+	}
+	if r, ok := req.(projectNameGetter); ok {
+		entry.ProjectName = r.GetProjectName()
+	}
+	if msg, ok := req.(proto.Message); ok {
+		serialized, marshalErr := protojson.Marshal(Redact(msg))
+		if marshalErr != nil {
+			a.log.WithField("grpc.method", fullMethod).Warnf("failed to serialize request for the audit log: %v", marshalErr)
+		}
+		entry.Request = truncateUTF8(string(serialized), AuditRequestMaxSize)
+	}
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		saveCtx, cancel := context.WithTimeout(context.Background(), auditWriteTimeout)
+		defer cancel()
+		if saveErr := a.writer.Save(saveCtx, entry); saveErr != nil {
+			a.log.WithFields(logrus.Fields{
+				"grpc.method": fullMethod,
+				"principal":   entry.Principal,
+				"project":     entry.ProjectName,
+			}).Errorf("failed to record call in the audit log: %v", saveErr)
+		}
+	}()
+}
+
+// Close waits for the calls being recorded
+func (a *Auditor) Close() error {
+	a.wg.Wait()
+	return nil
+}
+
+// UnaryServerInterceptor records unary calls of mutating methods
+func (a *Auditor) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !a.mutating(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, call := startAuditCall(ctx)
+		resp, err := handler(ctx, req)
+		a.record(ctx, call, info.FullMethod, req, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records streams of mutating methods with the first
+// message received on them
+func (a *Auditor) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !a.mutating(info.FullMethod) {
+			return handler(srv, stream)
+		}
+		ctx, call := startAuditCall(stream.Context())
+		auditStream := &auditServerStream{ServerStream: stream, ctx: ctx}
+		err := handler(srv, auditStream)
+		a.record(ctx, call, info.FullMethod, auditStream.firstMsg, err)
+		return err
+	}
+}
+
+type auditServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	firstMsg interface{}
+}
+
+func (s *auditServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *auditServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.firstMsg == nil {
+		s.firstMsg = m
+	}
+	return nil
+}
+
+// truncateUTF8 cuts s to at most max bytes without splitting a character
+func truncateUTF8(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	s = s[:max]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// NewAuditor records calls of methods mutating reports as changing state with
+// writer, failures to record them are logged with log
+func NewAuditor(writer AuditLogWriter, mutating func(fullMethod string) bool, log logrus.FieldLogger) *Auditor {
+	return &Auditor{
+		writer:   writer,
+		mutating: mutating,
+		log:      log,
+		Now:      time.Now,
+	}
+}
@@ -0,0 +1,186 @@
+package middleware_test
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/odpf/optimus/api/middleware"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type auditLogRecorder struct {
+	mu      sync.Mutex
+	entries []models.AuditLogEntry
+	err     error
+	// blocks saves till closed if set
+	blocked chan struct{}
+}
+
+func (r *auditLogRecorder) Save(ctx context.Context, entry models.AuditLogEntry) error {
+	if r.blocked != nil {
+		<-r.blocked
+	}
+	if r.err != nil {
+		return r.err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func TestAuditor(t *testing.T) {
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	mutating := func(fullMethod string) bool {
+		return !strings.HasSuffix(fullMethod, "/ListJobSpecification")
+	}
+	userCtx := middleware.ContextWithClaims(context.Background(), jwt.MapClaims{"sub": "user@example.io"})
+	newAuditor := func(recorder *auditLogRecorder) (*middleware.Auditor, *test.Hook) {
+		logger, hook := test.NewNullLogger()
+		auditor := middleware.NewAuditor(recorder, mutating, logrus.NewEntry(logger))
+		auditor.Now = func() time.Time { return now }
+		return auditor, hook
+	}
+	call := func(auditor *middleware.Auditor, method string, req interface{}, handlerErr error) (interface{}, error) {
+		return auditor.UnaryServerInterceptor()(userCtx, req, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "resp", handlerErr
+			})
+	}
+
+	t.Run("should record calls of mutating methods with a redacted request", func(t *testing.T) {
+		recorder := &auditLogRecorder{}
+		auditor, _ := newAuditor(recorder)
+
+		_, err := call(auditor, "/odpf.optimus.RuntimeService/RegisterSecret", &pb.RegisterSecretRequest{
+			ProjectName: "a-data-project",
+			SecretName:  "hello",
+			Value:       "c2VjcmV0",
+		}, nil)
+		assert.Nil(t, err)
+		_, err = call(auditor, "/odpf.optimus.RuntimeService/DeleteJobSpecification", &pb.DeleteJobSpecificationRequest{
+			ProjectName: "a-data-project",
+			JobName:     "a-job",
+		}, status.Error(codes.NotFound, "job not found"))
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		_, err = call(auditor, "/odpf.optimus.RuntimeService/ListJobSpecification", &pb.ListJobSpecificationRequest{
+			ProjectName: "a-data-project",
+		}, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, auditor.Close())
+
+		assert.Equal(t, 2, len(recorder.entries))
+		sort.Slice(recorder.entries, func(i, j int) bool {
+			return recorder.entries[i].Method > recorder.entries[j].Method
+		})
+		entry := recorder.entries[0]
+		assert.Equal(t, now, entry.Time)
+		assert.Equal(t, "user@example.io", entry.Principal)
+		assert.Equal(t, "/odpf.optimus.RuntimeService/RegisterSecret", entry.Method)
+		assert.Equal(t, "a-data-project", entry.ProjectName)
+		assert.Equal(t, "OK", entry.Status)
+		assert.Contains(t, entry.Request, middleware.RedactedValue)
+		assert.NotContains(t, entry.Request, "c2VjcmV0")
+		assert.Equal(t, "NotFound", recorder.entries[1].Status)
+	})
+	t.Run("should truncate large requests", func(t *testing.T) {
+		recorder := &auditLogRecorder{}
+		auditor, _ := newAuditor(recorder)
+
+		_, err := call(auditor, "/odpf.optimus.RuntimeService/RegisterProjectVariables", &pb.RegisterProjectVariablesRequest{
+			ProjectName: "a-data-project",
+			Variables:   map[string]string{"QUERY": strings.Repeat("é", middleware.AuditRequestMaxSize)},
+		}, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, auditor.Close())
+		assert.Equal(t, 1, len(recorder.entries))
+		assert.LessOrEqual(t, len(recorder.entries[0].Request), middleware.AuditRequestMaxSize)
+		assert.True(t, strings.HasPrefix(recorder.entries[0].Request, "{"))
+	})
+	t.Run("should not fail calls if they can't be recorded", func(t *testing.T) {
+		recorder := &auditLogRecorder{err: errors.New("connection refused")}
+		auditor, hook := newAuditor(recorder)
+
+		resp, err := call(auditor, "/odpf.optimus.RuntimeService/RegisterProject", &pb.RegisterProjectRequest{}, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "resp", resp)
+		assert.Nil(t, auditor.Close())
+		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+	})
+	t.Run("should not wait for calls being recorded", func(t *testing.T) {
+		recorder := &auditLogRecorder{blocked: make(chan struct{})}
+		auditor, _ := newAuditor(recorder)
+
+		resp, err := call(auditor, "/odpf.optimus.RuntimeService/RegisterProject", &pb.RegisterProjectRequest{}, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "resp", resp)
+
+		close(recorder.blocked)
+		assert.Nil(t, auditor.Close())
+		assert.Equal(t, 1, len(recorder.entries))
+	})
+	t.Run("should record callers authenticated after the auditor and rejected calls", func(t *testing.T) {
+		recorder := &auditLogRecorder{}
+		auditor, _ := newAuditor(recorder)
+		authenticate := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if info.FullMethod == "/odpf.optimus.RuntimeService/DeleteProjectSecret" {
+				return nil, status.Error(codes.Unauthenticated, "invalid token")
+			}
+			return handler(middleware.ContextWithClaims(ctx, jwt.MapClaims{"sub": "admin@example.io"}), req)
+		}
+		chainedCall := func(method string) error {
+			_, err := auditor.UnaryServerInterceptor()(context.Background(), &pb.RegisterProjectRequest{},
+				&grpc.UnaryServerInfo{FullMethod: method},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					return authenticate(ctx, req, &grpc.UnaryServerInfo{FullMethod: method},
+						func(ctx context.Context, req interface{}) (interface{}, error) {
+							return "resp", nil
+						})
+				})
+			return err
+		}
+
+		assert.Nil(t, chainedCall("/odpf.optimus.RuntimeService/RegisterProject"))
+		assert.Equal(t, codes.Unauthenticated, status.Code(chainedCall("/odpf.optimus.RuntimeService/DeleteProjectSecret")))
+		assert.Nil(t, auditor.Close())
+
+		assert.Equal(t, 2, len(recorder.entries))
+		byMethod := map[string]models.AuditLogEntry{}
+		for _, entry := range recorder.entries {
+			byMethod[entry.Method] = entry
+		}
+		registered := byMethod["/odpf.optimus.RuntimeService/RegisterProject"]
+		assert.Equal(t, "admin@example.io", registered.Principal)
+		assert.Equal(t, "OK", registered.Status)
+		rejected := byMethod["/odpf.optimus.RuntimeService/DeleteProjectSecret"]
+		assert.Equal(t, "", rejected.Principal)
+		assert.Equal(t, "Unauthenticated", rejected.Status)
+	})
+	t.Run("should record streams with their first message", func(t *testing.T) {
+		recorder := &auditLogRecorder{}
+		auditor, _ := newAuditor(recorder)
+
+		err := auditor.StreamServerInterceptor()(nil, &fakeServerStream{ctx: userCtx, msg: "a-data-project"},
+			&grpc.StreamServerInfo{FullMethod: "/odpf.optimus.RuntimeService/DeployJobSpecification"},
+			func(srv interface{}, ss grpc.ServerStream) error {
+				return ss.RecvMsg(&pb.DeployJobSpecificationRequest{})
+			})
+		assert.Nil(t, err)
+		assert.Nil(t, auditor.Close())
+		assert.Equal(t, 1, len(recorder.entries))
+		assert.Equal(t, "a-data-project", recorder.entries[0].ProjectName)
+	})
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/odpf/optimus/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RoleFromContext returns the highest role listed in the roles claim of the
+// token of the call, the role holds across all projects. Callers without a
+// known role in the claim have none
+func RoleFromContext(ctx context.Context) models.Role {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return models.RoleNone
+	}
+	role := models.RoleNone
+	roles, _ := claims["roles"].([]interface{})
+	for _, r := range roles {
+		name, _ := r.(string)
+		if parsed, err := models.ParseRole(name); err == nil && parsed > role {
+			role = parsed
+		}
+	}
+	return role
+}
+
+// ProjectRoleResolver looks up the role a subject has in a project,
+// subjects without one have models.RoleNone
+type ProjectRoleResolver interface {
+	GetProjectRole(ctx context.Context, projectName, subject string) (models.Role, error)
+}
+
+// MethodAuthorizer rejects calls of callers without the minimum role of the
+// method, methods missing from the roles require models.RoleAdmin. Callers
+// hold the role of their token in every project and, if ProjectRoles is set,
+// their role in the project of the request. Callers with an api key only
+// hold its role in its project
+type MethodAuthorizer struct {
+	methodRoles   map[string]models.Role
+	anonymousRole models.Role
+
+	ProjectRoles ProjectRoleResolver
+}
+
+// Authorize fails with codes.PermissionDenied if the caller of ctx doesn't
+// hold the role fullMethod requires, req may be nil if not received yet
+func (a *MethodAuthorizer) Authorize(ctx context.Context, fullMethod string, req interface{}) error {
+	required := a.RequiredRole(fullMethod)
+	if required == models.RoleNone {
+		return nil
+	}
+	if key, ok := APIKeyFromContext(ctx); ok {
+		return authorizeAPIKey(key, fullMethod, required, req)
+	}
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		if a.anonymousRole < required {
+			return status.Errorf(codes.PermissionDenied, "%s requires the %s role", fullMethod, required)
+		}
+		return nil
+	}
+	if RoleFromContext(ctx) >= required {
+		return nil
+	}
+
+	subject, _ := claims["sub"].(string)
+	r, ok := req.(projectNameGetter)
+	if !ok || r.GetProjectName() == "" || subject == "" || a.ProjectRoles == nil {
+		return status.Errorf(codes.PermissionDenied, "%s requires the %s role", fullMethod, required)
+	}
+	projectRole, err := a.ProjectRoles.GetProjectRole(ctx, r.GetProjectName(), subject)
+	if err != nil {
+		return status.Errorf(codes.Internal, "%s: failed to authorize call", err.Error())
+	}
+	if projectRole < required {
+		return status.Errorf(codes.PermissionDenied, "%s requires the %s role in project %s", fullMethod,
+			required, r.GetProjectName())
+	}
+	return nil
+}
+
+// authorizeAPIKey allows calls with the role of the key in its project only
+func authorizeAPIKey(key models.APIKey, fullMethod string, required models.Role, req interface{}) error {
+	r, ok := req.(projectNameGetter)
+	if !ok || r.GetProjectName() != key.ProjectName {
+		return status.Errorf(codes.PermissionDenied, "api key can only be used in project %s", key.ProjectName)
+	}
+	if key.Role < required {
+		return status.Errorf(codes.PermissionDenied, "%s requires the %s role in project %s", fullMethod,
+			required, key.ProjectName)
+	}
+	return nil
+}
+
+// RequiredRole is the minimum role callers of fullMethod need
+func (a *MethodAuthorizer) RequiredRole(fullMethod string) models.Role {
+	if role, ok := a.methodRoles[fullMethod]; ok {
+		return role
+	}
+	return models.RoleAdmin
+}
+
+// UnaryServerInterceptor authorizes every unary call
+func (a *MethodAuthorizer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.Authorize(ctx, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authorizes every stream call, calls not allowed
+// by the role of the token are authorized by the project of the first
+// message
+func (a *MethodAuthorizer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.Authorize(ss.Context(), info.FullMethod, nil); err == nil {
+			return handler(srv, ss)
+		} else if _, withKey := APIKeyFromContext(ss.Context()); status.Code(err) != codes.PermissionDenied ||
+			(a.ProjectRoles == nil && !withKey) {
+			return err
+		}
+		return handler(srv, &authorizedServerStream{ServerStream: ss, authorizer: a, fullMethod: info.FullMethod})
+	}
+}
+
+// authorizedServerStream authorizes the call once the first message is received
+type authorizedServerStream struct {
+	grpc.ServerStream
+	authorizer *MethodAuthorizer
+	fullMethod string
+	authorized bool
+}
+
+func (s *authorizedServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !s.authorized {
+		if err := s.authorizer.Authorize(s.Context(), s.fullMethod, m); err != nil {
+			return err
+		}
+		s.authorized = true
+	}
+	return nil
+}
+
+// NewMethodAuthorizer authorizes calls by the minimum role of each method.
+// Calls without a token are given anonymousRole, servers without
+// authentication use it to keep operations across projects out of reach
+func NewMethodAuthorizer(methodRoles map[string]models.Role, anonymousRole models.Role) *MethodAuthorizer {
+	return &MethodAuthorizer{
+		methodRoles:   methodRoles,
+		anonymousRole: anonymousRole,
+	}
+}
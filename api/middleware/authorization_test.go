@@ -0,0 +1,153 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/odpf/optimus/api/middleware"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMethodAuthorizer(t *testing.T) {
+	methodRoles := map[string]models.Role{
+		"/odpf.optimus.RuntimeService/Version":                models.RoleNone,
+		"/odpf.optimus.RuntimeService/ListJobSpecification":   models.RoleViewer,
+		"/odpf.optimus.RuntimeService/DeleteJobSpecification": models.RoleEditor,
+	}
+	withRoles := func(roles ...interface{}) context.Context {
+		return middleware.ContextWithClaims(context.Background(), jwt.MapClaims{
+			"sub":   "user@example.io",
+			"roles": roles,
+		})
+	}
+	call := func(authorizer *middleware.MethodAuthorizer, ctx context.Context, method string) error {
+		_, err := authorizer.UnaryServerInterceptor()(ctx, "req", &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "resp", nil
+			})
+		return err
+	}
+
+	t.Run("should read the highest role of the caller", func(t *testing.T) {
+		assert.Equal(t, models.RoleNone, middleware.RoleFromContext(context.Background()))
+		assert.Equal(t, models.RoleNone, middleware.RoleFromContext(withRoles()))
+		assert.Equal(t, models.RoleNone, middleware.RoleFromContext(withRoles("OWNER")))
+		assert.Equal(t, models.RoleViewer, middleware.RoleFromContext(withRoles("VIEWER")))
+		assert.Equal(t, models.RoleAdmin, middleware.RoleFromContext(withRoles("EDITOR", "ADMIN")))
+	})
+	t.Run("should allow callers with the minimum role of the method", func(t *testing.T) {
+		authorizer := middleware.NewMethodAuthorizer(methodRoles, models.RoleNone)
+		assert.Nil(t, call(authorizer, context.Background(), "/odpf.optimus.RuntimeService/Version"))
+		assert.Nil(t, call(authorizer, withRoles("VIEWER"), "/odpf.optimus.RuntimeService/ListJobSpecification"))
+		assert.Nil(t, call(authorizer, withRoles("EDITOR"), "/odpf.optimus.RuntimeService/DeleteJobSpecification"))
+		assert.Nil(t, call(authorizer, withRoles("ADMIN"), "/odpf.optimus.RuntimeService/DeleteJobSpecification"))
+	})
+	t.Run("should deny callers with insufficient roles", func(t *testing.T) {
+		authorizer := middleware.NewMethodAuthorizer(methodRoles, models.RoleNone)
+		err := call(authorizer, withRoles("VIEWER"), "/odpf.optimus.RuntimeService/DeleteJobSpecification")
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Equal(t, "/odpf.optimus.RuntimeService/DeleteJobSpecification requires the EDITOR role", status.Convert(err).Message())
+
+		err = call(authorizer, context.Background(), "/odpf.optimus.RuntimeService/ListJobSpecification")
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		err = call(authorizer, withRoles(), "/odpf.optimus.RuntimeService/ListJobSpecification")
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+	t.Run("should require admin for methods without a role", func(t *testing.T) {
+		authorizer := middleware.NewMethodAuthorizer(methodRoles, models.RoleNone)
+		err := call(authorizer, withRoles("EDITOR"), "/odpf.optimus.RuntimeService/RotateSecrets")
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Nil(t, call(authorizer, withRoles("ADMIN"), "/odpf.optimus.RuntimeService/RotateSecrets"))
+	})
+	t.Run("should give calls without a token the anonymous role", func(t *testing.T) {
+		authorizer := middleware.NewMethodAuthorizer(methodRoles, models.RoleEditor)
+		assert.Nil(t, call(authorizer, context.Background(), "/odpf.optimus.RuntimeService/DeleteJobSpecification"))
+		err := call(authorizer, context.Background(), "/odpf.optimus.RuntimeService/RotateSecrets")
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+type projectRoles map[string]models.Role
+
+func (r projectRoles) GetProjectRole(ctx context.Context, projectName, subject string) (models.Role, error) {
+	if projectName == "broken-project" {
+		return models.RoleNone, errors.New("connection refused")
+	}
+	return r[projectName+"/"+subject], nil
+}
+
+func TestMethodAuthorizerProjectRoles(t *testing.T) {
+	methodRoles := map[string]models.Role{
+		"/odpf.optimus.RuntimeService/ListJobSpecification":   models.RoleViewer,
+		"/odpf.optimus.RuntimeService/DeleteJobSpecification": models.RoleEditor,
+		"/odpf.optimus.RuntimeService/DeployJobSpecification": models.RoleEditor,
+	}
+	authorizer := middleware.NewMethodAuthorizer(methodRoles, models.RoleNone)
+	authorizer.ProjectRoles = projectRoles{
+		"a-data-project/user@example.io":     models.RoleEditor,
+		"other-data-project/user@example.io": models.RoleViewer,
+	}
+	userCtx := middleware.ContextWithClaims(context.Background(), jwt.MapClaims{"sub": "user@example.io"})
+	call := func(ctx context.Context, method string, req interface{}) error {
+		_, err := authorizer.UnaryServerInterceptor()(ctx, req, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "resp", nil
+			})
+		return err
+	}
+
+	t.Run("should allow callers with the role in the project of the request", func(t *testing.T) {
+		assert.Nil(t, call(userCtx, "/odpf.optimus.RuntimeService/DeleteJobSpecification",
+			&pb.DeleteJobSpecificationRequest{ProjectName: "a-data-project"}))
+		assert.Nil(t, call(userCtx, "/odpf.optimus.RuntimeService/ListJobSpecification",
+			&pb.ListJobSpecificationRequest{ProjectName: "other-data-project"}))
+	})
+	t.Run("should deny callers without the role in the project of the request", func(t *testing.T) {
+		err := call(userCtx, "/odpf.optimus.RuntimeService/DeleteJobSpecification",
+			&pb.DeleteJobSpecificationRequest{ProjectName: "other-data-project"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		err = call(userCtx, "/odpf.optimus.RuntimeService/ListJobSpecification",
+			&pb.ListJobSpecificationRequest{ProjectName: "third-data-project"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		err = call(userCtx, "/odpf.optimus.RuntimeService/ListJobSpecification", &pb.ListProjectsRequest{})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+	t.Run("should fail if the role in the project can't be looked up", func(t *testing.T) {
+		err := call(userCtx, "/odpf.optimus.RuntimeService/ListJobSpecification",
+			&pb.ListJobSpecificationRequest{ProjectName: "broken-project"})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+	t.Run("should authorize streams by the project of the first message", func(t *testing.T) {
+		stream := func(projectName string) error {
+			return authorizer.StreamServerInterceptor()(nil, &fakeServerStream{ctx: userCtx, msg: projectName},
+				&grpc.StreamServerInfo{FullMethod: "/odpf.optimus.RuntimeService/DeployJobSpecification"},
+				func(srv interface{}, ss grpc.ServerStream) error {
+					return ss.RecvMsg(&pb.DeployJobSpecificationRequest{})
+				})
+		}
+		assert.Nil(t, stream("a-data-project"))
+		assert.Equal(t, codes.PermissionDenied, status.Code(stream("other-data-project")))
+	})
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	msg string
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	m.(*pb.DeployJobSpecificationRequest).ProjectName = s.msg
+	return nil
+}
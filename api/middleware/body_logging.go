@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// RedactedValue replaces the value of sensitive string fields in logged bodies
+const RedactedValue = "<redacted>"
+
+// BodyLoggingUnaryServerInterceptor logs the request and response body of every
+// unary call when the logger is at trace level. Fields annotated with
+// (optimus.sensitive) are redacted before logging.
+func BodyLoggingUnaryServerInterceptor(entry *logrus.Entry) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !entry.Logger.IsLevelEnabled(logrus.TraceLevel) {
+			return handler(ctx, req)
+		}
+		logger := entry.WithField("grpc.method", info.FullMethod)
+		logBody(logger, "request", req)
+		resp, err := handler(ctx, req)
+		if err == nil {
+			logBody(logger, "response", resp)
+		}
+		return resp, err
+	}
+}
+
+// BodyLoggingStreamServerInterceptor logs every message received and sent on a
+// stream when the logger is at trace level. Fields annotated with
+// (optimus.sensitive) are redacted before logging.
+func BodyLoggingStreamServerInterceptor(entry *logrus.Entry) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !entry.Logger.IsLevelEnabled(logrus.TraceLevel) {
+			return handler(srv, stream)
+		}
+		return handler(srv, &bodyLoggingServerStream{
+			ServerStream: stream,
+			logger:       entry.WithField("grpc.method", info.FullMethod),
+		})
+	}
+}
+
+type bodyLoggingServerStream struct {
+	grpc.ServerStream
+	logger *logrus.Entry
+}
+
+func (s *bodyLoggingServerStream) SendMsg(m interface{}) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	logBody(s.logger, "response", m)
+	return nil
+}
+
+func (s *bodyLoggingServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	logBody(s.logger, "request", m)
+	return nil
+}
+
+func logBody(logger *logrus.Entry, kind string, body interface{}) {
+	msg, ok := body.(proto.Message)
+	if !ok {
+		return
+	}
+	serialized, err := protojson.Marshal(Redact(msg))
+	if err != nil {
+		logger.Tracef("failed to serialize %s body: %v", kind, err)
+		return
+	}
+	logger.WithField("grpc."+kind+".body", string(serialized)).Tracef("%s body", kind)
+}
+
+// Redact returns a copy of msg with all fields annotated with (optimus.sensitive)
+// redacted, including the ones in nested messages
+func Redact(msg proto.Message) proto.Message {
+	redacted := proto.Clone(msg)
+	redactMessage(redacted.ProtoReflect())
+	return redacted
+}
+
+func redactMessage(m protoreflect.Message) {
+	var sensitiveFields []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if isSensitive(fd) {
+			sensitiveFields = append(sensitiveFields, fd)
+			return true
+		}
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					redactMessage(mv.Message())
+					return true
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				for i := 0; i < v.List().Len(); i++ {
+					redactMessage(v.List().Get(i).Message())
+				}
+			}
+		case fd.Message() != nil:
+			redactMessage(v.Message())
+		}
+		return true
+	})
+
+	// fields are only modified once ranging over the message is done
+	for _, fd := range sensitiveFields {
+		if fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated {
+			m.Set(fd, protoreflect.ValueOfString(RedactedValue))
+			continue
+		}
+		m.Clear(fd)
+	}
+}
+
+func isSensitive(fd protoreflect.FieldDescriptor) bool {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil {
+		return false
+	}
+	sensitive, ok := proto.GetExtension(opts, pb.E_Sensitive).(bool)
+	return ok && sensitive
+}
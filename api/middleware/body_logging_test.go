@@ -0,0 +1,76 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/optimus/api/middleware"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func TestBodyLoggingUnaryServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/odpf.optimus.RuntimeService/RegisterSecret"}
+	req := &pb.RegisterSecretRequest{
+		ProjectName: "a-data-project",
+		SecretName:  "hello",
+		Value:       "c2VjcmV0",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.RegisterSecretResponse{Success: true}, nil
+	}
+
+	t.Run("should log redacted request and response bodies at trace level", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		logger.SetLevel(logrus.TraceLevel)
+		interceptor := middleware.BodyLoggingUnaryServerInterceptor(logrus.NewEntry(logger))
+
+		resp, err := interceptor(context.Background(), req, info, handler)
+		assert.Nil(t, err)
+		assert.True(t, resp.(*pb.RegisterSecretResponse).Success)
+
+		entries := hook.AllEntries()
+		assert.Equal(t, 2, len(entries))
+		assert.Equal(t, logrus.TraceLevel, entries[0].Level)
+		assert.Equal(t, info.FullMethod, entries[0].Data["grpc.method"])
+		reqBody := entries[0].Data["grpc.request.body"].(string)
+		assert.Contains(t, reqBody, "a-data-project")
+		assert.Contains(t, reqBody, middleware.RedactedValue)
+		assert.NotContains(t, reqBody, "c2VjcmV0")
+		assert.Contains(t, entries[1].Data["grpc.response.body"], "true")
+
+		// request passed to the handler is left untouched
+		assert.Equal(t, "c2VjcmV0", req.Value)
+	})
+	t.Run("should not log bodies at debug level", func(t *testing.T) {
+		logger, hook := test.NewNullLogger()
+		logger.SetLevel(logrus.DebugLevel)
+		interceptor := middleware.BodyLoggingUnaryServerInterceptor(logrus.NewEntry(logger))
+
+		_, err := interceptor(context.Background(), req, info, handler)
+		assert.Nil(t, err)
+		assert.Empty(t, hook.AllEntries())
+	})
+}
+
+func TestRedact(t *testing.T) {
+	t.Run("should redact sensitive fields of nested messages", func(t *testing.T) {
+		msg := &pb.RegisterProjectRequest{
+			Project: &pb.ProjectSpecification{
+				Name: "a-data-project",
+				Secrets: []*pb.ProjectSpecification_ProjectSecret{
+					{Name: "hello", Value: "world"},
+				},
+			},
+		}
+
+		redacted := middleware.Redact(msg).(*pb.RegisterProjectRequest)
+		assert.Equal(t, "a-data-project", redacted.Project.Name)
+		assert.Equal(t, "hello", redacted.Project.Secrets[0].Name)
+		assert.Equal(t, middleware.RedactedValue, redacted.Project.Secrets[0].Value)
+		assert.Equal(t, "world", msg.Project.Secrets[0].Value)
+	})
+}
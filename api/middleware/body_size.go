@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricRequestBodyBytes  = "optimus_http_request_body_bytes"
+	metricResponseBodyBytes = "optimus_http_response_body_bytes"
+)
+
+// bodySizeBuckets ranges from 128B to ~32MB
+var bodySizeBuckets = prometheus.ExponentialBuckets(128, 4, 10)
+
+// RouteResolver returns a low cardinality name of the route serving the request
+type RouteResolver func(r *http.Request) string
+
+// BodySizeMetrics records the size of http request and response bodies
+type BodySizeMetrics struct {
+	requestBytes  *prometheus.HistogramVec
+	responseBytes *prometheus.HistogramVec
+}
+
+// Handler wraps next and observes the number of bytes read from the request body
+// and written to the response once next returns
+func (m *BodySizeMetrics) Handler(next http.Handler, route RouteResolver) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqBody := &countingReader{ReadCloser: r.Body}
+		if r.Body != nil {
+			r.Body = reqBody
+		}
+		respWriter := &countingResponseWriter{ResponseWriter: w}
+
+		next.ServeHTTP(respWriter, r)
+
+		routeName := route(r)
+		m.requestBytes.WithLabelValues(r.Method, routeName).Observe(float64(reqBody.count))
+		m.responseBytes.WithLabelValues(r.Method, routeName).Observe(float64(respWriter.count))
+	})
+}
+
+// NewBodySizeMetrics creates the body size histograms and registers them with reg
+func NewBodySizeMetrics(reg prometheus.Registerer) (*BodySizeMetrics, error) {
+	m := &BodySizeMetrics{
+		requestBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    metricRequestBodyBytes,
+			Help:    "Size of http request bodies in bytes",
+			Buckets: bodySizeBuckets,
+		}, []string{"method", "route"}),
+		responseBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    metricResponseBodyBytes,
+			Help:    "Size of http response bodies in bytes",
+			Buckets: bodySizeBuckets,
+		}, []string{"method", "route"}),
+	}
+	for _, collector := range []prometheus.Collector{m.requestBytes, m.responseBytes} {
+		if err := reg.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+type countingReader struct {
+	io.ReadCloser
+	count int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.count += int64(n)
+	return n, err
+}
+
+type countingResponseWriter struct {
+	http.ResponseWriter
+	count int64
+}
+
+func (c *countingResponseWriter) Write(p []byte) (int, error) {
+	n, err := c.ResponseWriter.Write(p)
+	c.count += int64(n)
+	return n, err
+}
+
+// Flush keeps streaming responses of grpc-gateway working
+func (c *countingResponseWriter) Flush() {
+	if flusher, ok := c.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack lets handlers take over connections of the server, e.g. to upgrade
+// them to websockets
+func (c *countingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := c.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer doesn't support hijacking connections")
+	}
+	return hijacker.Hijack()
+}
+
+// Push forwards http/2 server pushes
+func (c *countingResponseWriter) Push(target string, opts *http.PushOptions) error {
+	if pusher, ok := c.ResponseWriter.(http.Pusher); ok {
+		return pusher.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
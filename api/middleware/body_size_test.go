@@ -0,0 +1,115 @@
+package middleware_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/odpf/optimus/api/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func findHistogram(t *testing.T, reg *prometheus.Registry, name, method, route string) *dto.Histogram {
+	families, err := reg.Gather()
+	assert.Nil(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["method"] == method && labels["route"] == route {
+				return metric.GetHistogram()
+			}
+		}
+	}
+	return nil
+}
+
+func TestBodySizeMetrics(t *testing.T) {
+	staticRoute := func(r *http.Request) string {
+		return "/api/"
+	}
+	echoHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Write(body)
+		w.Write([]byte("-done"))
+	})
+
+	t.Run("should observe request and response body sizes", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		bodyMetrics, err := middleware.NewBodySizeMetrics(reg)
+		assert.Nil(t, err)
+		handler := bodyMetrics.Handler(echoHandler, staticRoute)
+
+		for _, payloadSize := range []int{10, 2048} {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/project", strings.NewReader(strings.Repeat("a", payloadSize)))
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		reqHistogram := findHistogram(t, reg, "optimus_http_request_body_bytes", http.MethodPost, "/api/")
+		assert.NotNil(t, reqHistogram)
+		assert.Equal(t, uint64(2), reqHistogram.GetSampleCount())
+		assert.Equal(t, float64(10+2048), reqHistogram.GetSampleSum())
+
+		respHistogram := findHistogram(t, reg, "optimus_http_response_body_bytes", http.MethodPost, "/api/")
+		assert.NotNil(t, respHistogram)
+		assert.Equal(t, uint64(2), respHistogram.GetSampleCount())
+		assert.Equal(t, float64(10+2048+2*len("-done")), respHistogram.GetSampleSum())
+	})
+	t.Run("should observe zero bytes for requests without body", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		bodyMetrics, err := middleware.NewBodySizeMetrics(reg)
+		assert.Nil(t, err)
+		handler := bodyMetrics.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("pong"))
+		}), func(r *http.Request) string {
+			return "/ping"
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Body = nil
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		reqHistogram := findHistogram(t, reg, "optimus_http_request_body_bytes", http.MethodGet, "/ping")
+		assert.NotNil(t, reqHistogram)
+		assert.Equal(t, uint64(1), reqHistogram.GetSampleCount())
+		assert.Equal(t, float64(0), reqHistogram.GetSampleSum())
+
+		respHistogram := findHistogram(t, reg, "optimus_http_response_body_bytes", http.MethodGet, "/ping")
+		assert.Equal(t, float64(len("pong")), respHistogram.GetSampleSum())
+	})
+	t.Run("should let handlers hijack connections and push responses", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		bodyMetrics, err := middleware.NewBodySizeMetrics(reg)
+		assert.Nil(t, err)
+		handler := bodyMetrics.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.ErrNotSupported, w.(http.Pusher).Push("/static.css", nil))
+			conn, _, err := w.(http.Hijacker).Hijack()
+			assert.Nil(t, err)
+			conn.Write([]byte("HTTP/1.1 101 Switching Protocols\r\n\r\n"))
+			conn.Close()
+		}), staticRoute)
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		resp, err := http.Get(server.URL)
+		assert.Nil(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	})
+	t.Run("should fail when metrics are already registered", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		_, err := middleware.NewBodySizeMetrics(reg)
+		assert.Nil(t, err)
+		_, err = middleware.NewBodySizeMetrics(reg)
+		assert.NotNil(t, err)
+	})
+}
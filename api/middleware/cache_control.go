@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	headerCacheControl = "Cache-Control"
+	headerVary         = "Vary"
+
+	cacheControlNoStore = "no-store"
+
+	// headers callers are authenticated with, responses are cached per caller
+	cacheControlVary = "Authorization, X-Api-Key"
+)
+
+// CacheRoute lets responses to GET requests of paths matching Pattern be
+// cached for MaxAge. Segments of the pattern in braces, e.g. {project_name},
+// match any value of the segment
+type CacheRoute struct {
+	Pattern string
+	MaxAge  time.Duration
+}
+
+type cacheRoute struct {
+	segments []string
+	maxAge   time.Duration
+}
+
+func (r cacheRoute) matches(segments []string) bool {
+	if len(segments) != len(r.segments) {
+		return false
+	}
+	for i, segment := range r.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			continue
+		}
+		if segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// CacheControl sets caching headers of http responses, only responses of
+// whitelisted read routes are cacheable and responses of writes are never
+// stored
+type CacheControl struct {
+	routes []cacheRoute
+}
+
+// Handler wraps next and sets Cache-Control with the max-age of the first
+// route matching GET requests, varying by Authorization and X-Api-Key so
+// responses of a caller aren't served to others. Responses of writes and failed requests
+// get no-store
+func (c *CacheControl) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			if maxAge, ok := c.maxAge(r.URL.Path); ok {
+				w.Header().Set(headerCacheControl, fmt.Sprintf("max-age=%d", int(maxAge.Seconds())))
+				w.Header().Add(headerVary, cacheControlVary)
+				w = &cacheControlResponseWriter{ResponseWriter: w}
+			}
+		default:
+			w.Header().Set(headerCacheControl, cacheControlNoStore)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (c *CacheControl) maxAge(path string) (time.Duration, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, route := range c.routes {
+		if route.matches(segments) {
+			return route.maxAge, true
+		}
+	}
+	return 0, false
+}
+
+// NewCacheControl validates the patterns of routes
+func NewCacheControl(routes []CacheRoute) (*CacheControl, error) {
+	c := &CacheControl{}
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Pattern, "/") {
+			return nil, errors.Errorf("cache route pattern %q should start with /", route.Pattern)
+		}
+		if route.MaxAge < time.Second {
+			return nil, errors.Errorf("max age of cache route %s should be at least a second", route.Pattern)
+		}
+		c.routes = append(c.routes, cacheRoute{
+			segments: strings.Split(strings.Trim(route.Pattern, "/"), "/"),
+			maxAge:   route.MaxAge,
+		})
+	}
+	return c, nil
+}
+
+// cacheControlResponseWriter keeps unsuccessful responses of cacheable
+// routes from being cached
+type cacheControlResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (c *cacheControlResponseWriter) WriteHeader(code int) {
+	if !c.wroteHeader && code != http.StatusOK {
+		c.Header().Set(headerCacheControl, cacheControlNoStore)
+	}
+	c.wroteHeader = true
+	c.ResponseWriter.WriteHeader(code)
+}
+
+func (c *cacheControlResponseWriter) Write(p []byte) (int, error) {
+	c.wroteHeader = true
+	return c.ResponseWriter.Write(p)
+}
+
+// Flush keeps streaming responses of grpc-gateway working
+func (c *cacheControlResponseWriter) Flush() {
+	if flusher, ok := c.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
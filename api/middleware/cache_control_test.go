@@ -0,0 +1,83 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/api/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControl(t *testing.T) {
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	})
+	cacheControl, err := middleware.NewCacheControl([]middleware.CacheRoute{
+		{Pattern: "/api/v1/project/{project_name}/job", MaxAge: time.Minute},
+		{Pattern: "/api/v1/project/{project_name}", MaxAge: 5 * time.Minute},
+	})
+	assert.Nil(t, err)
+
+	t.Run("should set the max age of whitelisted read routes", func(t *testing.T) {
+		for path, expected := range map[string]string{
+			"/api/v1/project/a-data-project/job": "max-age=60",
+			"/api/v1/project/a-data-project":     "max-age=300",
+		} {
+			resp := httptest.NewRecorder()
+			cacheControl.Handler(okHandler).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path, nil))
+
+			assert.Equal(t, expected, resp.Header().Get("Cache-Control"), path)
+			assert.Equal(t, "Authorization, X-Api-Key", resp.Header().Get("Vary"), path)
+		}
+	})
+	t.Run("should cache responses per api key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/project/a-data-project", nil)
+		req.Header.Set("X-Api-Key", "a-project-key")
+		resp := httptest.NewRecorder()
+		cacheControl.Handler(okHandler).ServeHTTP(resp, req)
+
+		var varies []string
+		for _, vary := range resp.Header().Values("Vary") {
+			for _, header := range strings.Split(vary, ",") {
+				varies = append(varies, http.CanonicalHeaderKey(strings.TrimSpace(header)))
+			}
+		}
+		assert.Contains(t, varies, "X-Api-Key")
+		assert.Contains(t, varies, "Authorization")
+	})
+	t.Run("should not set caching headers of read routes not whitelisted", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		cacheControl.Handler(okHandler).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/project/a-data-project/job/a-job", nil))
+
+		assert.Empty(t, resp.Header().Get("Cache-Control"))
+		assert.Empty(t, resp.Header().Get("Vary"))
+	})
+	t.Run("should not store responses of writes", func(t *testing.T) {
+		for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+			resp := httptest.NewRecorder()
+			cacheControl.Handler(okHandler).ServeHTTP(resp, httptest.NewRequest(method, "/api/v1/project/a-data-project/job", nil))
+
+			assert.Equal(t, "no-store", resp.Header().Get("Cache-Control"), method)
+		}
+	})
+	t.Run("should not store failed responses of whitelisted read routes", func(t *testing.T) {
+		notFoundHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		resp := httptest.NewRecorder()
+		cacheControl.Handler(notFoundHandler).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/project/unknown", nil))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		assert.Equal(t, "no-store", resp.Header().Get("Cache-Control"))
+	})
+	t.Run("should fail for invalid routes", func(t *testing.T) {
+		_, err := middleware.NewCacheControl([]middleware.CacheRoute{{Pattern: "api/v1/project", MaxAge: time.Minute}})
+		assert.EqualError(t, err, `cache route pattern "api/v1/project" should start with /`)
+
+		_, err = middleware.NewCacheControl([]middleware.CacheRoute{{Pattern: "/api/v1/project", MaxAge: time.Millisecond}})
+		assert.EqualError(t, err, "max age of cache route /api/v1/project should be at least a second")
+	})
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/encoding"
+	grpcgzip "google.golang.org/grpc/encoding/gzip"
+)
+
+const (
+	// CompressionGzip compresses grpc messages with gzip
+	CompressionGzip = grpcgzip.Name
+
+	// CompressionSnappy compresses grpc messages with the snappy framing format,
+	// it trades compression ratio for speed
+	CompressionSnappy = "snappy"
+
+	headerAcceptEncoding  = "Accept-Encoding"
+	headerContentEncoding = "Content-Encoding"
+	headerContentLength   = "Content-Length"
+)
+
+func init() {
+	// gzip registers itself once imported, servers decompress messages of
+	// clients using either of them
+	encoding.RegisterCompressor(snappyCompressor{})
+}
+
+// ValidateCompression fails for compressors that aren't registered, no
+// compression is used when name is empty
+func ValidateCompression(name string) error {
+	if name == "" || encoding.GetCompressor(name) != nil {
+		return nil
+	}
+	return errors.Errorf("unsupported grpc compression %q, should be one of %s, %s", name,
+		CompressionGzip, CompressionSnappy)
+}
+
+type snappyCompressor struct{}
+
+func (snappyCompressor) Name() string {
+	return CompressionSnappy
+}
+
+func (snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+func (snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return snappy.NewReader(r), nil
+}
+
+// GzipHandler compresses http responses of next with gzip for clients that
+// accept it. Responses already encoded by next, e.g. of prometheus metrics,
+// are passed through as they are
+func GzipHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add(headerVary, headerAcceptEncoding)
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer gw.Close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get(headerAcceptEncoding), ",") {
+		if strings.TrimSpace(strings.SplitN(encoding, ";", 2)[0]) == "gzip" {
+			return true
+		}
+	}
+	return false
+}
+
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(ioutil.Discard)
+	},
+}
+
+// gzipResponseWriter decides to compress on the first write of the response,
+// once the headers set by the wrapped handler are known
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz          *gzip.Writer
+	wroteHeader bool
+}
+
+func (g *gzipResponseWriter) WriteHeader(code int) {
+	if g.wroteHeader {
+		return
+	}
+	g.wroteHeader = true
+	if g.Header().Get(headerContentEncoding) == "" && code != http.StatusNoContent && code != http.StatusNotModified {
+		g.Header().Set(headerContentEncoding, "gzip")
+		g.Header().Del(headerContentLength)
+		g.gz = gzipWriterPool.Get().(*gzip.Writer)
+		g.gz.Reset(g.ResponseWriter)
+	}
+	g.ResponseWriter.WriteHeader(code)
+}
+
+func (g *gzipResponseWriter) Write(p []byte) (int, error) {
+	if !g.wroteHeader {
+		if g.Header().Get("Content-Type") == "" {
+			g.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		g.WriteHeader(http.StatusOK)
+	}
+	if g.gz == nil {
+		return g.ResponseWriter.Write(p)
+	}
+	return g.gz.Write(p)
+}
+
+// Flush keeps streaming responses of grpc-gateway working
+func (g *gzipResponseWriter) Flush() {
+	if g.gz != nil {
+		g.gz.Flush()
+	}
+	if flusher, ok := g.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Close completes the gzip stream of the response
+func (g *gzipResponseWriter) Close() error {
+	if g.gz == nil {
+		return nil
+	}
+	err := g.gz.Close()
+	g.gz.Reset(ioutil.Discard)
+	gzipWriterPool.Put(g.gz)
+	g.gz = nil
+	return err
+}
@@ -0,0 +1,87 @@
+package middleware_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/odpf/optimus/api/middleware"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/encoding"
+)
+
+func TestValidateCompression(t *testing.T) {
+	for _, name := range []string{"", middleware.CompressionGzip, middleware.CompressionSnappy} {
+		assert.Nil(t, middleware.ValidateCompression(name), name)
+	}
+	assert.NotNil(t, middleware.ValidateCompression("zstd"))
+}
+
+func TestSnappyCompressor(t *testing.T) {
+	compressor := encoding.GetCompressor(middleware.CompressionSnappy)
+	assert.NotNil(t, compressor)
+
+	message := strings.Repeat("select * from `proj.dataset.table`\n", 100)
+	var compressed bytes.Buffer
+	w, err := compressor.Compress(&compressed)
+	assert.Nil(t, err)
+	_, err = w.Write([]byte(message))
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+	assert.Less(t, compressed.Len(), len(message))
+
+	r, err := compressor.Decompress(&compressed)
+	assert.Nil(t, err)
+	decompressed, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, message, string(decompressed))
+}
+
+func TestGzipHandler(t *testing.T) {
+	body := strings.Repeat(`{"name":"a-job"}`, 100)
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", "1600")
+		w.Write([]byte(body))
+	})
+
+	t.Run("should compress responses for clients accepting gzip", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/project/a-data-project/job", nil)
+		req.Header.Set("Accept-Encoding", "deflate, gzip;q=0.9")
+		resp := httptest.NewRecorder()
+		middleware.GzipHandler(okHandler).ServeHTTP(resp, req)
+
+		assert.Equal(t, "gzip", resp.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", resp.Header().Get("Vary"))
+		assert.Empty(t, resp.Header().Get("Content-Length"))
+		r, err := gzip.NewReader(resp.Body)
+		assert.Nil(t, err)
+		decompressed, err := ioutil.ReadAll(r)
+		assert.Nil(t, err)
+		assert.Equal(t, body, string(decompressed))
+	})
+	t.Run("should not compress responses for other clients", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		middleware.GzipHandler(okHandler).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/project/a-data-project/job", nil))
+
+		assert.Empty(t, resp.Header().Get("Content-Encoding"))
+		assert.Equal(t, body, resp.Body.String())
+	})
+	t.Run("should pass through responses already encoded", func(t *testing.T) {
+		encodedHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "br")
+			w.Write([]byte("encoded"))
+		})
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Accept-Encoding", "gzip, br")
+		resp := httptest.NewRecorder()
+		middleware.GzipHandler(encodedHandler).ServeHTTP(resp, req)
+
+		assert.Equal(t, "br", resp.Header().Get("Content-Encoding"))
+		assert.Equal(t, "encoded", resp.Body.String())
+	})
+}
@@ -0,0 +1,410 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultJWKSMinRefreshInterval limits how often keys are fetched again
+	// when tokens are signed with unknown key ids
+	DefaultJWKSMinRefreshInterval = time.Minute
+
+	// DefaultJWKSMaxFetchBackoff limits how long fetches wait after failed
+	// ones, the wait doubles from the refresh interval with each failure
+	DefaultJWKSMaxFetchBackoff = 10 * time.Minute
+)
+
+var (
+	// jwtSigningMethods are the asymmetric algorithms accepted for tokens,
+	// symmetric algorithms are rejected since keys are public
+	jwtSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+	errUnknownKey = errors.New("no key found to verify token")
+)
+
+type claimsContextKey struct{}
+
+// ClaimsFromContext returns the claims of the token the call was authenticated with
+func ClaimsFromContext(ctx context.Context) (jwt.MapClaims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(jwt.MapClaims)
+	return claims, ok
+}
+
+// ContextWithClaims returns a copy of ctx carrying the claims of the token
+// the call was authenticated with
+func ContextWithClaims(ctx context.Context, claims jwt.MapClaims) context.Context {
+	ctx = context.WithValue(ctx, claimsContextKey{}, claims)
+	setAuditPrincipal(ctx)
+	return ctx
+}
+
+// PrincipalFromContext identifies the caller by the subject of its token or
+// the id of its api key, callers of servers without authentication are told
+// apart by address
+func PrincipalFromContext(ctx context.Context) string {
+	if key, ok := APIKeyFromContext(ctx); ok {
+		return "apikey/" + key.ID.String()
+	}
+	if claims, ok := ClaimsFromContext(ctx); ok {
+		if subject, _ := claims["sub"].(string); subject != "" {
+			return subject
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// KeyProvider returns the public key to verify tokens signed with the key id,
+// the key id is empty for tokens without one
+type KeyProvider interface {
+	PublicKey(ctx context.Context, kid string) (interface{}, error)
+}
+
+type staticKeyProvider struct {
+	key interface{}
+}
+
+func (p *staticKeyProvider) PublicKey(ctx context.Context, kid string) (interface{}, error) {
+	return p.key, nil
+}
+
+// NewStaticKeyProvider verifies all tokens with the pem encoded rsa or ecdsa public key
+func NewStaticKeyProvider(publicKeyPEM []byte) (KeyProvider, error) {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(publicKeyPEM); err == nil {
+		return &staticKeyProvider{key: key}, nil
+	}
+	key, err := jwt.ParseECPublicKeyFromPEM(publicKeyPEM)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse rsa or ecdsa public key")
+	}
+	return &staticKeyProvider{key: key}, nil
+}
+
+// JWKSKeyProvider fetches the keys from a json web key set url, keys are
+// fetched again when a token is signed with an unknown key id to pick up
+// rotated keys. Known keys are served while the set is fetched, calls with
+// unknown key ids wait for the fetch in progress. Fetches back off after
+// failed ones, calls with unknown key ids fail with the last error meanwhile
+type JWKSKeyProvider struct {
+	url    string
+	client *http.Client
+
+	mu   sync.Mutex
+	keys map[string]interface{}
+	// nextFetchAt is when keys can be fetched again after the last attempt,
+	// fetchErr and failures are of the last attempts if they failed
+	nextFetchAt time.Time
+	fetchErr    error
+	failures    int
+	// fetching is closed once the fetch in progress is done, nil if none is
+	fetching chan struct{}
+
+	MinRefreshInterval time.Duration
+	MaxFetchBackoff    time.Duration
+	Now                func() time.Time
+}
+
+func (p *JWKSKeyProvider) PublicKey(ctx context.Context, kid string) (interface{}, error) {
+	for {
+		p.mu.Lock()
+		if key, ok := p.lookup(kid); ok {
+			p.mu.Unlock()
+			return key, nil
+		}
+		if fetching := p.fetching; fetching != nil {
+			p.mu.Unlock()
+			select {
+			case <-fetching:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if p.Now().Before(p.nextFetchAt) {
+			fetchErr := p.fetchErr
+			p.mu.Unlock()
+			if fetchErr != nil {
+				return nil, fetchErr
+			}
+			return nil, errors.Wrapf(errUnknownKey, "key id %s", kid)
+		}
+		fetching := make(chan struct{})
+		p.fetching = fetching
+		p.mu.Unlock()
+
+		keys, err := p.fetch(ctx)
+
+		p.mu.Lock()
+		if err == nil {
+			p.keys = keys
+			p.failures = 0
+			p.nextFetchAt = p.Now().Add(p.MinRefreshInterval)
+		} else if ctx.Err() == nil {
+			// fetches given up by their caller don't count as failures
+			p.failures++
+			p.nextFetchAt = p.Now().Add(p.fetchBackoff())
+		}
+		p.fetchErr = err
+		p.fetching = nil
+		close(fetching)
+		key, ok := p.lookup(kid)
+		p.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return key, nil
+		}
+		return nil, errors.Wrapf(errUnknownKey, "key id %s", kid)
+	}
+}
+
+// fetchBackoff doubles the refresh interval with each failed fetch, up to
+// MaxFetchBackoff
+func (p *JWKSKeyProvider) fetchBackoff() time.Duration {
+	backoff := p.MinRefreshInterval
+	for i := 1; i < p.failures && backoff < p.MaxFetchBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxFetchBackoff {
+		backoff = p.MaxFetchBackoff
+	}
+	return backoff
+}
+
+// lookup returns the key of kid, tokens without a key id can only be
+// verified if the set has a single key
+func (p *JWKSKeyProvider) lookup(kid string) (interface{}, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// fetch reads the signature keys of the set by key id, it is called without
+// holding mu
+func (p *JWKSKeyProvider) fetch(ctx context.Context) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch jwks")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to fetch jwks: %s", resp.Status)
+	}
+
+	var keySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&keySet); err != nil {
+		return nil, errors.Wrap(err, "failed to decode jwks")
+	}
+	keys := map[string]interface{}{}
+	for _, jwk := range keySet.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid key %s in jwks", jwk.Kid)
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+// NewJWKSKeyProvider verifies tokens with the keys published at url
+func NewJWKSKeyProvider(url string, client *http.Client) *JWKSKeyProvider {
+	return &JWKSKeyProvider{
+		url:                url,
+		client:             client,
+		keys:               map[string]interface{}{},
+		MinRefreshInterval: DefaultJWKSMinRefreshInterval,
+		MaxFetchBackoff:    DefaultJWKSMaxFetchBackoff,
+		Now:                time.Now,
+	}
+}
+
+// jsonWebKey is a rsa or elliptic curve public key of a json web key set
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBase64URLInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBase64URLInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := decodeBase64URLInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBase64URLInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, errors.Errorf("unsupported key type %s", k.Kty)
+}
+
+func decodeBase64URLInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// JWTAuthenticator verifies the bearer token in the authorization header of
+// calls, tokens must expire. Issuer and audience claims are only checked if
+// configured
+type JWTAuthenticator struct {
+	keys     KeyProvider
+	issuer   string
+	audience string
+	parser   *jwt.Parser
+
+	// PublicMethods can be called without a token, tokens sent to them are
+	// still verified
+	PublicMethods map[string]bool
+	// APIKeys authenticates calls with an x-api-key header in place of a
+	// token, api keys are rejected if not set
+	APIKeys *APIKeyAuthenticator
+}
+
+// Authenticate adds the claims of a valid token to the context, calls
+// without a valid token fail with codes.Unauthenticated
+func (a *JWTAuthenticator) Authenticate(ctx context.Context) (context.Context, error) {
+	tokenString, err := grpc_auth.AuthFromMD(ctx, "bearer")
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := a.parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return a.keys.PublicKey(ctx, kid)
+	}); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+	// the parser only checks exp if it is set
+	if _, ok := claims["exp"]; !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid token: missing expiry")
+	}
+	if a.issuer != "" && !claims.VerifyIssuer(a.issuer, true) {
+		return nil, status.Error(codes.Unauthenticated, "invalid token: unexpected issuer")
+	}
+	if a.audience != "" && !claims.VerifyAudience(a.audience, true) {
+		return nil, status.Error(codes.Unauthenticated, "invalid token: unexpected audience")
+	}
+	return ContextWithClaims(ctx, claims), nil
+}
+
+// authenticateMethod lets calls of public methods without a token through,
+// calls with an api key are authenticated by it
+func (a *JWTAuthenticator) authenticateMethod(ctx context.Context, fullMethod string) (context.Context, error) {
+	if a.APIKeys != nil && hasAPIKey(ctx) {
+		return a.APIKeys.Authenticate(ctx)
+	}
+	if a.PublicMethods[fullMethod] {
+		if _, err := grpc_auth.AuthFromMD(ctx, "bearer"); err != nil {
+			return ctx, nil
+		}
+	}
+	return a.Authenticate(ctx)
+}
+
+// UnaryServerInterceptor authenticates every unary call
+func (a *JWTAuthenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		newCtx, err := a.authenticateMethod(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(newCtx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates every stream call
+func (a *JWTAuthenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		newCtx, err := a.authenticateMethod(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		wrapped := grpc_middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = newCtx
+		return handler(srv, wrapped)
+	}
+}
+
+// NewJWTAuthenticator verifies tokens with the keys of keys, issuer and
+// audience aren't checked if empty
+func NewJWTAuthenticator(keys KeyProvider, issuer, audience string) *JWTAuthenticator {
+	return &JWTAuthenticator{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+		parser: &jwt.Parser{
+			ValidMethods: jwtSigningMethods,
+		},
+	}
+}
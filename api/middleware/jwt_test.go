@@ -0,0 +1,294 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/odpf/optimus/api/middleware"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestJWTAuthenticator(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	sign := func(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(method, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		assert.Nil(t, err)
+		return signed
+	}
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub": "optimus@example.com",
+			"iss": "https://auth.example.com/",
+			"aud": "optimus",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+	}
+	publicKeyPEM := func(t *testing.T, key interface{}) []byte {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		assert.Nil(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	}
+	b64 := func(b []byte) string {
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	jwksServer := func(t *testing.T, requests *int) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*requests++
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"keys": []map[string]string{
+					{
+						"kid": "rsa-key", "kty": "RSA", "use": "sig",
+						"n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+					},
+					{
+						"kid": "ec-key", "kty": "EC", "crv": "P-256",
+						"x": b64(ecKey.X.Bytes()), "y": b64(ecKey.Y.Bytes()),
+					},
+					{
+						"kid": "enc-key", "kty": "RSA", "use": "enc",
+						"n": b64(otherKey.N.Bytes()), "e": b64(big.NewInt(int64(otherKey.E)).Bytes()),
+					},
+				},
+			})
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+
+	t.Run("should propagate claims of a valid token to the handler", func(t *testing.T) {
+		keys, err := middleware.NewStaticKeyProvider(publicKeyPEM(t, &rsaKey.PublicKey))
+		assert.Nil(t, err)
+		authenticator := middleware.NewJWTAuthenticator(keys, "https://auth.example.com/", "optimus")
+		ctx := withToken(sign(t, jwt.SigningMethodRS256, rsaKey, "", validClaims()))
+
+		resp, err := authenticator.UnaryServerInterceptor()(ctx, "req", &grpc.UnaryServerInfo{},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				claims, ok := middleware.ClaimsFromContext(ctx)
+				assert.True(t, ok)
+				assert.Equal(t, claims["sub"], middleware.PrincipalFromContext(ctx))
+				return claims["sub"], nil
+			})
+		assert.Nil(t, err)
+		assert.Equal(t, "optimus@example.com", resp)
+	})
+	t.Run("should let calls of public methods without a token through", func(t *testing.T) {
+		keys, err := middleware.NewStaticKeyProvider(publicKeyPEM(t, &rsaKey.PublicKey))
+		assert.Nil(t, err)
+		authenticator := middleware.NewJWTAuthenticator(keys, "https://auth.example.com/", "optimus")
+		authenticator.PublicMethods = map[string]bool{"/odpf.optimus.RuntimeService/Version": true}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			_, ok := middleware.ClaimsFromContext(ctx)
+			return ok, nil
+		}
+
+		authenticated, err := authenticator.UnaryServerInterceptor()(context.Background(), "req",
+			&grpc.UnaryServerInfo{FullMethod: "/odpf.optimus.RuntimeService/Version"}, handler)
+		assert.Nil(t, err)
+		assert.Equal(t, false, authenticated)
+
+		authenticated, err = authenticator.UnaryServerInterceptor()(withToken(sign(t, jwt.SigningMethodRS256, rsaKey, "", validClaims())),
+			"req", &grpc.UnaryServerInfo{FullMethod: "/odpf.optimus.RuntimeService/Version"}, handler)
+		assert.Nil(t, err)
+		assert.Equal(t, true, authenticated)
+
+		_, err = authenticator.UnaryServerInterceptor()(withToken("not-a-jwt"), "req",
+			&grpc.UnaryServerInfo{FullMethod: "/odpf.optimus.RuntimeService/Version"}, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+	t.Run("should verify tokens signed with static ecdsa key", func(t *testing.T) {
+		keys, err := middleware.NewStaticKeyProvider(publicKeyPEM(t, &ecKey.PublicKey))
+		assert.Nil(t, err)
+		authenticator := middleware.NewJWTAuthenticator(keys, "", "")
+
+		_, err = authenticator.Authenticate(withToken(sign(t, jwt.SigningMethodES256, ecKey, "", validClaims())))
+		assert.Nil(t, err)
+	})
+	t.Run("should reject calls", func(t *testing.T) {
+		keys, err := middleware.NewStaticKeyProvider(publicKeyPEM(t, &rsaKey.PublicKey))
+		assert.Nil(t, err)
+		authenticator := middleware.NewJWTAuthenticator(keys, "https://auth.example.com/", "optimus")
+
+		expired := validClaims()
+		expired["exp"] = time.Now().Add(-time.Minute).Unix()
+		wrongAudience := validClaims()
+		wrongAudience["aud"] = "siren"
+		wrongIssuer := validClaims()
+		wrongIssuer["iss"] = "https://other.example.com/"
+		withoutExpiry := validClaims()
+		delete(withoutExpiry, "exp")
+		cases := map[string]context.Context{
+			"without authorization header": context.Background(),
+			"with malformed token":         withToken("not-a-jwt"),
+			"with token signed by another key": withToken(
+				sign(t, jwt.SigningMethodRS256, otherKey, "", validClaims())),
+			"with symmetric signed token": withToken(
+				sign(t, jwt.SigningMethodHS256, publicKeyPEM(t, &rsaKey.PublicKey), "", validClaims())),
+			"with expired token":       withToken(sign(t, jwt.SigningMethodRS256, rsaKey, "", expired)),
+			"with unexpected audience": withToken(sign(t, jwt.SigningMethodRS256, rsaKey, "", wrongAudience)),
+			"with unexpected issuer":   withToken(sign(t, jwt.SigningMethodRS256, rsaKey, "", wrongIssuer)),
+			"without expiry":           withToken(sign(t, jwt.SigningMethodRS256, rsaKey, "", withoutExpiry)),
+		}
+		for name, ctx := range cases {
+			t.Run(name, func(t *testing.T) {
+				_, err := authenticator.UnaryServerInterceptor()(ctx, "req", &grpc.UnaryServerInfo{},
+					func(ctx context.Context, req interface{}) (interface{}, error) {
+						t.Fatal("handler should not be called")
+						return nil, nil
+					})
+				assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			})
+		}
+	})
+	t.Run("should verify tokens with keys of jwks url", func(t *testing.T) {
+		var requests int
+		srv := jwksServer(t, &requests)
+		keys := middleware.NewJWKSKeyProvider(srv.URL, srv.Client())
+		authenticator := middleware.NewJWTAuthenticator(keys, "", "")
+
+		_, err := authenticator.Authenticate(withToken(sign(t, jwt.SigningMethodRS256, rsaKey, "rsa-key", validClaims())))
+		assert.Nil(t, err)
+		_, err = authenticator.Authenticate(withToken(sign(t, jwt.SigningMethodES256, ecKey, "ec-key", validClaims())))
+		assert.Nil(t, err)
+		// keys not meant for signatures are ignored
+		_, err = authenticator.Authenticate(withToken(sign(t, jwt.SigningMethodRS256, otherKey, "enc-key", validClaims())))
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		// tokens without key id can't be matched to one of multiple keys
+		_, err = authenticator.Authenticate(withToken(sign(t, jwt.SigningMethodRS256, rsaKey, "", validClaims())))
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		assert.Equal(t, 1, requests)
+	})
+	t.Run("should fetch jwks again for unknown key ids after refresh interval", func(t *testing.T) {
+		var requests int
+		srv := jwksServer(t, &requests)
+		now := time.Now()
+		keys := middleware.NewJWKSKeyProvider(srv.URL, srv.Client())
+		keys.Now = func() time.Time { return now }
+
+		_, err := keys.PublicKey(context.Background(), "rsa-key")
+		assert.Nil(t, err)
+		_, err = keys.PublicKey(context.Background(), "rotated-key")
+		assert.NotNil(t, err)
+		assert.Equal(t, 1, requests)
+
+		now = now.Add(middleware.DefaultJWKSMinRefreshInterval)
+		_, err = keys.PublicKey(context.Background(), "rotated-key")
+		assert.NotNil(t, err)
+		assert.Equal(t, 2, requests)
+	})
+	t.Run("should back off fetching jwks while it fails", func(t *testing.T) {
+		var requests int
+		srv := jwksServer(t, &requests)
+		failing := true
+		client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if failing {
+				requests++
+				return &http.Response{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable",
+					Body: http.NoBody, Request: req}, nil
+			}
+			return srv.Client().Transport.RoundTrip(req)
+		})}
+		now := time.Now()
+		keys := middleware.NewJWKSKeyProvider(srv.URL, client)
+		keys.Now = func() time.Time { return now }
+
+		for i := 0; i < 3; i++ {
+			_, err := keys.PublicKey(context.Background(), "rsa-key")
+			assert.NotNil(t, err)
+		}
+		assert.Equal(t, 1, requests)
+
+		// the wait doubles with each failure
+		now = now.Add(middleware.DefaultJWKSMinRefreshInterval)
+		_, err := keys.PublicKey(context.Background(), "rsa-key")
+		assert.NotNil(t, err)
+		assert.Equal(t, 2, requests)
+		now = now.Add(middleware.DefaultJWKSMinRefreshInterval)
+		_, err = keys.PublicKey(context.Background(), "rsa-key")
+		assert.NotNil(t, err)
+		assert.Equal(t, 2, requests)
+
+		failing = false
+		now = now.Add(middleware.DefaultJWKSMinRefreshInterval)
+		_, err = keys.PublicKey(context.Background(), "rsa-key")
+		assert.Nil(t, err)
+		assert.Equal(t, 3, requests)
+	})
+	t.Run("should serve known keys while jwks is fetched", func(t *testing.T) {
+		var requests int
+		srv := jwksServer(t, &requests)
+		// fetches after the first one hang until released
+		fetchStarted, releaseFetch := make(chan struct{}), make(chan struct{})
+		client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if requests > 0 {
+				close(fetchStarted)
+				<-releaseFetch
+			}
+			return srv.Client().Transport.RoundTrip(req)
+		})}
+		now := time.Now()
+		keys := middleware.NewJWKSKeyProvider(srv.URL, client)
+		keys.Now = func() time.Time { return now }
+		_, err := keys.PublicKey(context.Background(), "rsa-key")
+		assert.Nil(t, err)
+
+		now = now.Add(middleware.DefaultJWKSMinRefreshInterval)
+		fetchDone := make(chan error)
+		go func() {
+			_, err := keys.PublicKey(context.Background(), "rotated-key")
+			fetchDone <- err
+		}()
+		<-fetchStarted
+
+		_, err = keys.PublicKey(context.Background(), "ec-key")
+		assert.Nil(t, err)
+		// unknown key ids wait for the fetch in progress
+		waitCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = keys.PublicKey(waitCtx, "another-rotated-key")
+		assert.Equal(t, context.DeadlineExceeded, err)
+
+		close(releaseFetch)
+		assert.NotNil(t, <-fetchDone)
+		assert.Equal(t, 2, requests)
+	})
+	t.Run("should fail to create static key provider with invalid key", func(t *testing.T) {
+		_, err := middleware.NewStaticKeyProvider([]byte("not a key"))
+		assert.NotNil(t, err)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,214 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	grpctags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// projectNameTag is the grpctags key of the project a request belongs to
+	projectNameTag = "grpc.request.project_name"
+
+	// RetryAfterHeader is set on rate limited calls with the number of
+	// seconds to wait before the call can be retried
+	RetryAfterHeader = "retry-after"
+)
+
+type projectNameGetter interface {
+	GetProjectName() string
+}
+
+// ProjectRequestFieldExtractor tags requests with the name of the project
+// they belong to, requests without a project name are not tagged
+func ProjectRequestFieldExtractor(fullMethod string, req interface{}) map[string]interface{} {
+	if r, ok := req.(projectNameGetter); ok && r.GetProjectName() != "" {
+		return map[string]interface{}{
+			"project_name": r.GetProjectName(),
+		}
+	}
+	return nil
+}
+
+// RateLimiter limits calls with a token bucket per caller of each project,
+// calls without a project share a bucket per caller. Callers and project
+// names are read from the context of the call so the authentication and tags
+// interceptors must run first.
+type RateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	// key returns the bucket of a call, fullMethod limits only calls of
+	// that method if set
+	key        func(ctx context.Context) string
+	fullMethod string
+
+	// ceiling limits the calls of all buckets of a caller together, as
+	// project names are sent by callers and would give them new buckets
+	ceiling *RateLimiter
+
+	mu       sync.Mutex
+	limiters map[string]*rateLimiterEntry
+	// idle limiters are evicted once they have been refilled, they are
+	// no different from a new one by then
+	idleTimeout time.Duration
+	lastEvicted time.Time
+
+	Now func() time.Time
+}
+
+type rateLimiterEntry struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+func (l *RateLimiter) limiter(key string, now time.Time) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastEvicted) >= l.idleTimeout {
+		for entryKey, entry := range l.limiters {
+			if now.Sub(entry.lastUsed) >= l.idleTimeout {
+				delete(l.limiters, entryKey)
+			}
+		}
+		l.lastEvicted = now
+	}
+	entry, ok := l.limiters[key]
+	if !ok {
+		entry = &rateLimiterEntry{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[key] = entry
+	}
+	entry.lastUsed = now
+	return entry.limiter
+}
+
+// Len returns the number of buckets kept for callers of projects, ceilings
+// of callers aren't counted
+func (l *RateLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.limiters)
+}
+
+// allow takes a token of the bucket of the call, and of the ceiling of its
+// caller if set, or returns the number of seconds to wait for them
+func (l *RateLimiter) allow(ctx context.Context) (string, error) {
+	now := l.Now()
+	limiters := []*rate.Limiter{l.limiter(l.key(ctx), now)}
+	if l.ceiling != nil {
+		limiters = append(limiters, l.ceiling.limiter(l.ceiling.key(ctx), now))
+	}
+
+	var reservations []*rate.Reservation
+	var delay time.Duration
+	exhausted := false
+	for _, limiter := range limiters {
+		reservation := limiter.ReserveN(now, 1)
+		if !reservation.OK() {
+			exhausted = true
+			continue
+		}
+		reservations = append(reservations, reservation)
+		if d := reservation.DelayFrom(now); d > delay {
+			delay = d
+		}
+	}
+	if exhausted || delay > 0 {
+		// tokens are given back since the call is not made
+		for _, reservation := range reservations {
+			reservation.CancelAt(now)
+		}
+	}
+	if exhausted {
+		return "", status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	if delay > 0 {
+		retryAfter := strconv.Itoa(int(math.Ceil(delay.Seconds())))
+		return retryAfter, status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry after %ss", retryAfter)
+	}
+	return "", nil
+}
+
+// UnaryServerInterceptor rejects calls with codes.ResourceExhausted once the
+// bucket of the project is exhausted
+func (l *RateLimiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if l.fullMethod != "" && info.FullMethod != l.fullMethod {
+			return handler(ctx, req)
+		}
+		if retryAfter, err := l.allow(ctx); err != nil {
+			if retryAfter != "" {
+				_ = grpc.SetHeader(ctx, metadata.Pairs(RetryAfterHeader, retryAfter))
+			}
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects streams like UnaryServerInterceptor, a
+// stream takes a single token however many messages it carries. Requests
+// of streams are received by their handler, so streams are limited by the
+// bucket of their caller without a project
+func (l *RateLimiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if l.fullMethod != "" && info.FullMethod != l.fullMethod {
+			return handler(srv, ss)
+		}
+		if retryAfter, err := l.allow(ss.Context()); err != nil {
+			if retryAfter != "" {
+				_ = ss.SetHeader(metadata.Pairs(RetryAfterHeader, retryAfter))
+			}
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// NewRateLimiter allows requestsPerSecond calls per caller of each project
+// with bursts of up to burst calls, and callerRequestsPerSecond calls per
+// caller across projects with bursts scaled alike. burst defaults to
+// requestsPerSecond and callerRequestsPerSecond to requestsPerSecond if not
+// positive
+func NewRateLimiter(requestsPerSecond, burst, callerRequestsPerSecond int) *RateLimiter {
+	if burst <= 0 {
+		burst = requestsPerSecond
+	}
+	if callerRequestsPerSecond <= 0 {
+		callerRequestsPerSecond = requestsPerSecond
+	}
+	l := newRateLimiter(rate.Limit(requestsPerSecond), burst, func(ctx context.Context) string {
+		project, _ := grpctags.Extract(ctx).Values()[projectNameTag].(string)
+		return PrincipalFromContext(ctx) + "/" + project
+	}, "")
+	callerBurst := int(math.Ceil(float64(burst) * float64(callerRequestsPerSecond) / float64(requestsPerSecond)))
+	l.ceiling = newRateLimiter(rate.Limit(callerRequestsPerSecond), callerBurst, PrincipalFromContext, "")
+	return l
+}
+
+// NewMethodRateLimiter allows burst calls of fullMethod per principal, a call
+// is given back every burst-th part of period
+func NewMethodRateLimiter(fullMethod string, burst int, period time.Duration) *RateLimiter {
+	return newRateLimiter(rate.Every(period/time.Duration(burst)), burst, PrincipalFromContext, fullMethod)
+}
+
+func newRateLimiter(limit rate.Limit, burst int, key func(ctx context.Context) string, fullMethod string) *RateLimiter {
+	return &RateLimiter{
+		limit:       limit,
+		burst:       burst,
+		key:         key,
+		fullMethod:  fullMethod,
+		limiters:    map[string]*rateLimiterEntry{},
+		idleTimeout: time.Duration(float64(burst) / float64(limit) * float64(time.Second)),
+		Now:         time.Now,
+	}
+}
@@ -0,0 +1,188 @@
+package middleware_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	grpctags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"github.com/odpf/optimus/api/middleware"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimiter(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "resp", nil
+	}
+	// call runs req through the tags interceptor first to tag the project
+	call := func(limiter *middleware.RateLimiter, recorder *headerRecorder, req interface{}) (interface{}, error) {
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), recorder)
+		tagsInterceptor := grpctags.UnaryServerInterceptor(grpctags.WithFieldExtractor(middleware.ProjectRequestFieldExtractor))
+		return tagsInterceptor(ctx, req, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return limiter.UnaryServerInterceptor()(ctx, req, &grpc.UnaryServerInfo{}, handler)
+		})
+	}
+
+	t.Run("should reject calls with retry after hint once burst is exhausted", func(t *testing.T) {
+		now := time.Now()
+		limiter := middleware.NewRateLimiter(2, 3, 0)
+		limiter.Now = func() time.Time { return now }
+		req := &pb.ListJobSpecificationRequest{ProjectName: "a-data-project"}
+
+		for i := 0; i < 3; i++ {
+			resp, err := call(limiter, &headerRecorder{}, req)
+			assert.Nil(t, err)
+			assert.Equal(t, "resp", resp)
+		}
+		recorder := &headerRecorder{}
+		_, err := call(limiter, recorder, req)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Equal(t, metadata.Pairs(middleware.RetryAfterHeader, "1"), recorder.header)
+
+		// rejected calls don't consume tokens
+		now = now.Add(500 * time.Millisecond)
+		_, err = call(limiter, &headerRecorder{}, req)
+		assert.Nil(t, err)
+		_, err = call(limiter, &headerRecorder{}, req)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+	t.Run("should limit each project separately", func(t *testing.T) {
+		now := time.Now()
+		limiter := middleware.NewRateLimiter(1, 0, 10)
+		limiter.Now = func() time.Time { return now }
+
+		_, err := call(limiter, &headerRecorder{}, &pb.ListJobSpecificationRequest{ProjectName: "a-data-project"})
+		assert.Nil(t, err)
+		_, err = call(limiter, &headerRecorder{}, &pb.ListJobSpecificationRequest{ProjectName: "a-data-project"})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+		_, err = call(limiter, &headerRecorder{}, &pb.ListJobSpecificationRequest{ProjectName: "b-data-project"})
+		assert.Nil(t, err)
+		// calls without project share a bucket
+		_, err = call(limiter, &headerRecorder{}, &pb.VersionRequest{})
+		assert.Nil(t, err)
+		_, err = call(limiter, &headerRecorder{}, &pb.VersionRequest{})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+	t.Run("should limit each caller of a project separately", func(t *testing.T) {
+		now := time.Now()
+		limiter := middleware.NewRateLimiter(1, 0, 0)
+		limiter.Now = func() time.Time { return now }
+		callFrom := func(addr string) error {
+			recorder := &headerRecorder{}
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 51000}})
+			ctx = grpc.NewContextWithServerTransportStream(ctx, recorder)
+			tagsInterceptor := grpctags.UnaryServerInterceptor(grpctags.WithFieldExtractor(middleware.ProjectRequestFieldExtractor))
+			_, err := tagsInterceptor(ctx, &pb.ListJobSpecificationRequest{ProjectName: "a-data-project"}, &grpc.UnaryServerInfo{},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					return limiter.UnaryServerInterceptor()(ctx, req, &grpc.UnaryServerInfo{}, handler)
+				})
+			return err
+		}
+
+		assert.Nil(t, callFrom("10.0.0.1"))
+		assert.Equal(t, codes.ResourceExhausted, status.Code(callFrom("10.0.0.1")))
+		assert.Nil(t, callFrom("10.0.0.2"))
+	})
+	t.Run("should limit the calls of a caller across projects", func(t *testing.T) {
+		now := time.Now()
+		limiter := middleware.NewRateLimiter(1, 0, 2)
+		limiter.Now = func() time.Time { return now }
+
+		_, err := call(limiter, &headerRecorder{}, &pb.ListJobSpecificationRequest{ProjectName: "a-data-project"})
+		assert.Nil(t, err)
+		_, err = call(limiter, &headerRecorder{}, &pb.ListJobSpecificationRequest{ProjectName: "b-data-project"})
+		assert.Nil(t, err)
+		// a project name the caller made up doesn't get around the ceiling
+		recorder := &headerRecorder{}
+		_, err = call(limiter, recorder, &pb.ListJobSpecificationRequest{ProjectName: "made-up-project"})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Equal(t, metadata.Pairs(middleware.RetryAfterHeader, "1"), recorder.header)
+
+		// rejected calls don't consume tokens of the project
+		now = now.Add(500 * time.Millisecond)
+		_, err = call(limiter, &headerRecorder{}, &pb.ListJobSpecificationRequest{ProjectName: "made-up-project"})
+		assert.Nil(t, err)
+	})
+	t.Run("should limit streams of a caller", func(t *testing.T) {
+		now := time.Now()
+		limiter := middleware.NewRateLimiter(1, 0, 0)
+		limiter.Now = func() time.Time { return now }
+		streamHandler := func(srv interface{}, stream grpc.ServerStream) error {
+			return nil
+		}
+		stream := &rateLimitedServerStream{ctx: context.Background()}
+
+		assert.Nil(t, limiter.StreamServerInterceptor()(nil, stream, &grpc.StreamServerInfo{}, streamHandler))
+		err := limiter.StreamServerInterceptor()(nil, stream, &grpc.StreamServerInfo{}, streamHandler)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Equal(t, metadata.Pairs(middleware.RetryAfterHeader, "1"), stream.header)
+	})
+	t.Run("should evict buckets of callers once they are refilled", func(t *testing.T) {
+		now := time.Now()
+		limiter := middleware.NewRateLimiter(2, 4, 0)
+		limiter.Now = func() time.Time { return now }
+
+		_, err := call(limiter, &headerRecorder{}, &pb.ListJobSpecificationRequest{ProjectName: "a-data-project"})
+		assert.Nil(t, err)
+		_, err = call(limiter, &headerRecorder{}, &pb.ListJobSpecificationRequest{ProjectName: "b-data-project"})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, limiter.Len())
+
+		// buckets are refilled after burst/rate seconds
+		now = now.Add(time.Second)
+		_, err = call(limiter, &headerRecorder{}, &pb.ListJobSpecificationRequest{ProjectName: "a-data-project"})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, limiter.Len())
+
+		now = now.Add(time.Second)
+		_, err = call(limiter, &headerRecorder{}, &pb.ListJobSpecificationRequest{ProjectName: "a-data-project"})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, limiter.Len())
+	})
+	t.Run("should limit calls of a method for each principal", func(t *testing.T) {
+		now := time.Now()
+		limiter := middleware.NewMethodRateLimiter("/odpf.optimus.RuntimeService/RevealProjectSecret", 5, time.Hour)
+		limiter.Now = func() time.Time { return now }
+		callFrom := func(addr, method string) error {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 51000}})
+			ctx = grpc.NewContextWithServerTransportStream(ctx, &headerRecorder{})
+			_, err := limiter.UnaryServerInterceptor()(ctx, &pb.RevealProjectSecretRequest{}, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+			return err
+		}
+
+		for i := 0; i < 5; i++ {
+			assert.Nil(t, callFrom("10.0.0.1", "/odpf.optimus.RuntimeService/RevealProjectSecret"))
+		}
+		assert.Equal(t, codes.ResourceExhausted, status.Code(callFrom("10.0.0.1", "/odpf.optimus.RuntimeService/RevealProjectSecret")))
+		// other methods and principals aren't limited
+		assert.Nil(t, callFrom("10.0.0.1", "/odpf.optimus.RuntimeService/ListSecrets"))
+		assert.Nil(t, callFrom("10.0.0.2", "/odpf.optimus.RuntimeService/RevealProjectSecret"))
+
+		now = now.Add(12 * time.Minute)
+		assert.Nil(t, callFrom("10.0.0.1", "/odpf.optimus.RuntimeService/RevealProjectSecret"))
+		assert.Equal(t, codes.ResourceExhausted, status.Code(callFrom("10.0.0.1", "/odpf.optimus.RuntimeService/RevealProjectSecret")))
+	})
+}
+
+type rateLimitedServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *rateLimitedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *rateLimitedServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// GatewayRoutes resolves requests to the path template of the grpc-gateway
+// route serving them, e.g. /api/v1/project/{project_name}/job, so requests
+// of all projects and jobs share a route
+type GatewayRoutes struct {
+	prefix string
+	routes []gatewayRoute
+}
+
+type gatewayRoute struct {
+	method   string
+	template string
+	segments []string
+	literals int
+}
+
+func (r gatewayRoute) matches(method string, segments []string) bool {
+	if method != r.method || len(segments) != len(r.segments) {
+		return false
+	}
+	for i, segment := range r.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			continue
+		}
+		if segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// NewGatewayRoutes collects the http bindings of the methods of services
+// served by the gateway mounted at prefix
+func NewGatewayRoutes(prefix string, services ...protoreflect.ServiceDescriptor) *GatewayRoutes {
+	g := &GatewayRoutes{prefix: strings.TrimSuffix(prefix, "/")}
+	for _, service := range services {
+		methods := service.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			rule, ok := proto.GetExtension(methods.Get(i).Options(), annotations.E_Http).(*annotations.HttpRule)
+			if ok && rule != nil {
+				g.addRule(rule)
+			}
+		}
+	}
+	return g
+}
+
+func (g *GatewayRoutes) addRule(rule *annotations.HttpRule) {
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		g.Add(http.MethodGet, pattern.Get)
+	case *annotations.HttpRule_Put:
+		g.Add(http.MethodPut, pattern.Put)
+	case *annotations.HttpRule_Post:
+		g.Add(http.MethodPost, pattern.Post)
+	case *annotations.HttpRule_Delete:
+		g.Add(http.MethodDelete, pattern.Delete)
+	case *annotations.HttpRule_Patch:
+		g.Add(http.MethodPatch, pattern.Patch)
+	case *annotations.HttpRule_Custom:
+		g.Add(pattern.Custom.GetKind(), pattern.Custom.GetPath())
+	}
+	for _, binding := range rule.GetAdditionalBindings() {
+		g.addRule(binding)
+	}
+}
+
+// Add registers a route of the gateway not bound to a grpc method, like paths
+// handled with HandlePath
+func (g *GatewayRoutes) Add(method, template string) {
+	route := gatewayRoute{
+		method:   method,
+		template: g.prefix + template,
+		segments: strings.Split(strings.Trim(template, "/"), "/"),
+	}
+	for _, segment := range route.segments {
+		if !strings.HasPrefix(segment, "{") {
+			route.literals++
+		}
+	}
+	g.routes = append(g.routes, route)
+}
+
+// Resolve returns the template of the route serving r, routes with more
+// literal segments win if several match. It returns false if r isn't served
+// by any route of the gateway
+func (g *GatewayRoutes) Resolve(r *http.Request) (string, bool) {
+	if !strings.HasPrefix(r.URL.Path, g.prefix+"/") {
+		return "", false
+	}
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, g.prefix), "/"), "/")
+	var best *gatewayRoute
+	for i, route := range g.routes {
+		if route.matches(r.Method, segments) && (best == nil || route.literals > best.literals) {
+			best = &g.routes[i]
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	return best.template, true
+}
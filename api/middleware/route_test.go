@@ -0,0 +1,44 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/odpf/optimus/api/middleware"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGatewayRoutes(t *testing.T) {
+	gatewayRoutes := middleware.NewGatewayRoutes("/api", pb.File_odpf_optimus_runtime_service_proto.Services().ByName("RuntimeService"))
+	gatewayRoutes.Add(http.MethodGet, "/v1/projects/{name}/jobs.csv")
+	gatewayRoutes.Add(http.MethodGet, "/v1/project/{project_name}/namespace/{namespace}/job/latest")
+
+	testCases := []struct {
+		name             string
+		method, path     string
+		expectedTemplate string
+		expectedOk       bool
+	}{
+		{"should resolve routes of grpc methods", http.MethodGet, "/api/v1/project/a-data-project/namespace/sales/job/daily-orders",
+			"/api/v1/project/{project_name}/namespace/{namespace}/job/{job_name}", true},
+		{"should resolve routes by method", http.MethodDelete, "/api/v1/project/a-data-project/namespace/sales/job/daily-orders",
+			"/api/v1/project/{project_name}/namespace/{namespace}/job/{job_name}", true},
+		{"should resolve routes added to the gateway", http.MethodGet, "/api/v1/projects/a-data-project/jobs.csv",
+			"/api/v1/projects/{name}/jobs.csv", true},
+		{"should prefer routes with more literal segments", http.MethodGet, "/api/v1/project/a-data-project/namespace/sales/job/latest",
+			"/api/v1/project/{project_name}/namespace/{namespace}/job/latest", true},
+		{"should not resolve routes with another method", http.MethodPatch, "/api/v1/project/a-data-project/namespace/sales/job/daily-orders",
+			"", false},
+		{"should not resolve paths outside of the gateway", http.MethodGet, "/ping", "", false},
+		{"should not resolve unknown paths", http.MethodGet, "/api/v1/unknown", "", false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			template, ok := gatewayRoutes.Resolve(httptest.NewRequest(testCase.method, testCase.path, nil))
+			assert.Equal(t, testCase.expectedOk, ok)
+			assert.Equal(t, testCase.expectedTemplate, template)
+		})
+	}
+}
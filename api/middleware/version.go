@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// MetadataServerVersion is the response header carrying the server version
+	MetadataServerVersion = "optimus-server-version"
+	// MetadataAPIVersion is the response header carrying the api major version
+	MetadataAPIVersion = "optimus-api-version"
+)
+
+func versionMetadata(serverVersion string, apiMajorVersion int) metadata.MD {
+	return metadata.Pairs(
+		MetadataServerVersion, serverVersion,
+		MetadataAPIVersion, strconv.Itoa(apiMajorVersion),
+	)
+}
+
+// VersionUnaryServerInterceptor adds the server and api version to the header
+// of every unary response
+func VersionUnaryServerInterceptor(serverVersion string, apiMajorVersion int) grpc.UnaryServerInterceptor {
+	md := versionMetadata(serverVersion, apiMajorVersion)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := grpc.SetHeader(ctx, md); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// VersionStreamServerInterceptor adds the server and api version to the header
+// of every stream response
+func VersionStreamServerInterceptor(serverVersion string, apiMajorVersion int) grpc.StreamServerInterceptor {
+	md := versionMetadata(serverVersion, apiMajorVersion)
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := stream.SetHeader(md); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/optimus/api/middleware"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// headerRecorder captures headers set on a unary call
+type headerRecorder struct {
+	header metadata.MD
+}
+
+func (r *headerRecorder) Method() string {
+	return "/odpf.optimus.RuntimeService/Version"
+}
+
+func (r *headerRecorder) SetHeader(md metadata.MD) error {
+	r.header = metadata.Join(r.header, md)
+	return nil
+}
+
+func (r *headerRecorder) SendHeader(md metadata.MD) error {
+	return r.SetHeader(md)
+}
+
+func (r *headerRecorder) SetTrailer(md metadata.MD) error {
+	return nil
+}
+
+// streamHeaderRecorder captures headers set on a stream
+type streamHeaderRecorder struct {
+	grpc.ServerStream
+	header metadata.MD
+}
+
+func (r *streamHeaderRecorder) SetHeader(md metadata.MD) error {
+	r.header = metadata.Join(r.header, md)
+	return nil
+}
+
+func TestVersionInterceptors(t *testing.T) {
+	expectedHeader := metadata.Pairs(
+		middleware.MetadataServerVersion, "0.1.0",
+		middleware.MetadataAPIVersion, "1",
+	)
+
+	t.Run("should add version to unary response header", func(t *testing.T) {
+		recorder := &headerRecorder{}
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), recorder)
+		interceptor := middleware.VersionUnaryServerInterceptor("0.1.0", 1)
+
+		resp, err := interceptor(ctx, "req", &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "resp", nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "resp", resp)
+		assert.Equal(t, expectedHeader, recorder.header)
+	})
+	t.Run("should add version to stream response header", func(t *testing.T) {
+		recorder := &streamHeaderRecorder{}
+		interceptor := middleware.VersionStreamServerInterceptor("0.1.0", 1)
+
+		handlerCalled := false
+		err := interceptor(nil, recorder, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+			handlerCalled = true
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.True(t, handlerCalled)
+		assert.Equal(t, expectedHeader, recorder.header)
+	})
+}
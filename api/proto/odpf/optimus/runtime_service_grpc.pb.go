@@ -72,6 +72,126 @@ type RuntimeServiceClient interface {
 	UpdateResource(ctx context.Context, in *UpdateResourceRequest, opts ...grpc.CallOption) (*UpdateResourceResponse, error)
 	ReplayDryRun(ctx context.Context, in *ReplayRequest, opts ...grpc.CallOption) (*ReplayDryRunResponse, error)
 	Replay(ctx context.Context, in *ReplayRequest, opts ...grpc.CallOption) (*ReplayResponse, error)
+	// PreviewSchedulePropagation lists the schedule updates proposed for downstream
+	// jobs aligned to the schedule of the provided job
+	PreviewSchedulePropagation(ctx context.Context, in *PreviewSchedulePropagationRequest, opts ...grpc.CallOption) (*PreviewSchedulePropagationResponse, error)
+	// ListActiveJobInstances is an internal admin command listing running instances
+	// of jobs across all projects
+	ListActiveJobInstances(ctx context.Context, in *ListActiveJobInstancesRequest, opts ...grpc.CallOption) (*ListActiveJobInstancesResponse, error)
+	// ListRunningJobs lists runs of jobs currently being executed by the scheduler
+	ListRunningJobs(ctx context.Context, in *ListRunningJobsRequest, opts ...grpc.CallOption) (*ListRunningJobsResponse, error)
+	// NegotiateAPIVersion should be called by clients before any other call to
+	// verify the client version is supported by the server
+	NegotiateAPIVersion(ctx context.Context, in *NegotiateAPIVersionRequest, opts ...grpc.CallOption) (*NegotiateAPIVersionResponse, error)
+	// SmartBackfillJob replays the runs of a job missing in the scheduler between
+	// the provided dates, with dry_run only the backfill plan is returned
+	SmartBackfillJob(ctx context.Context, in *SmartBackfillJobRequest, opts ...grpc.CallOption) (*SmartBackfillJobResponse, error)
+	// PinDependencyVersion compiles a job against the current spec of one of its
+	// upstream jobs, even after the upstream is updated, until it is unpinned
+	PinDependencyVersion(ctx context.Context, in *PinDependencyVersionRequest, opts ...grpc.CallOption) (*PinDependencyVersionResponse, error)
+	// UnpinDependency compiles a job against the latest spec of the upstream again
+	UnpinDependency(ctx context.Context, in *UnpinDependencyRequest, opts ...grpc.CallOption) (*UnpinDependencyResponse, error)
+	// ListDependencyPins lists the pinned upstream specs of jobs in a project
+	ListDependencyPins(ctx context.Context, in *ListDependencyPinsRequest, opts ...grpc.CallOption) (*ListDependencyPinsResponse, error)
+	// GetProjectDiagnostics checks the database, storage, scheduler and job dependencies of a project
+	GetProjectDiagnostics(ctx context.Context, in *GetProjectDiagnosticsRequest, opts ...grpc.CallOption) (*GetProjectDiagnosticsResponse, error)
+	// TagRelease is an admin call snapshotting the current job specs of a project under a release tag
+	TagRelease(ctx context.Context, in *TagReleaseRequest, opts ...grpc.CallOption) (*TagReleaseResponse, error)
+	// RollbackToRelease is an admin call restoring and deploying the job specs of a project tagged in a release
+	RollbackToRelease(ctx context.Context, in *RollbackToReleaseRequest, opts ...grpc.CallOption) (*RollbackToReleaseResponse, error)
+	// GetInstanceStatusSummary counts the instances of jobs in a project by their state
+	GetInstanceStatusSummary(ctx context.Context, in *GetInstanceStatusSummaryRequest, opts ...grpc.CallOption) (*GetInstanceStatusSummaryResponse, error)
+	// CheckJobSchemaDrift compares the schema of the destination table of a job with the schema its query produces
+	CheckJobSchemaDrift(ctx context.Context, in *CheckJobSchemaDriftRequest, opts ...grpc.CallOption) (*CheckJobSchemaDriftResponse, error)
+	// GetReplayStatus returns the status of a replay started with Replay, replays
+	// are persisted so they can be polled across server restarts
+	GetReplayStatus(ctx context.Context, in *GetReplayStatusRequest, opts ...grpc.CallOption) (*GetReplayStatusResponse, error)
+	// ListSecrets returns names of the secrets registered in a project
+	ListSecrets(ctx context.Context, in *ListSecretsRequest, opts ...grpc.CallOption) (*ListSecretsResponse, error)
+	// RevealProjectSecret returns the value of a secret when the confirmation
+	// phrase matches the one configured on the server, reveals are audit logged
+	RevealProjectSecret(ctx context.Context, in *RevealProjectSecretRequest, opts ...grpc.CallOption) (*RevealProjectSecretResponse, error)
+	// DiffJobSpec compares two versions of a job spec archived on each change,
+	// either version can be "current" for the registered spec
+	DiffJobSpec(ctx context.Context, in *DiffJobSpecRequest, opts ...grpc.CallOption) (*DiffJobSpecResponse, error)
+	// ListJobsByOwner lists jobs of all projects owned by the owner, callers
+	// need the ADMIN role since jobs of other projects are listed
+	ListJobsByOwner(ctx context.Context, in *ListJobsByOwnerRequest, opts ...grpc.CallOption) (*ListJobsByOwnerResponse, error)
+	// ReconcileJobInstances fails the long running instances of a project
+	// orphaned by the scheduler, callers need the ADMIN role
+	ReconcileJobInstances(ctx context.Context, in *ReconcileJobInstancesRequest, opts ...grpc.CallOption) (*ReconcileJobInstancesResponse, error)
+	// UndeleteJobSpecification restores a deleted job specification and
+	// deploys it again
+	UndeleteJobSpecification(ctx context.Context, in *UndeleteJobSpecificationRequest, opts ...grpc.CallOption) (*UndeleteJobSpecificationResponse, error)
+	// PurgeDeletedJobSpecifications permanently removes job specifications of
+	// all projects deleted a while ago, callers need the ADMIN role
+	PurgeDeletedJobSpecifications(ctx context.Context, in *PurgeDeletedJobSpecificationsRequest, opts ...grpc.CallOption) (*PurgeDeletedJobSpecificationsResponse, error)
+	// CloneJobSpecification copies a job spec to a new job, the clone is
+	// saved as a draft and deployed with the next deployment of its namespace
+	CloneJobSpecification(ctx context.Context, in *CloneJobSpecificationRequest, opts ...grpc.CallOption) (*CloneJobSpecificationResponse, error)
+	// CloneProject copies the job specifications of a project to another
+	// registered project, no jobs are created if any of them is already
+	// registered in the destination project
+	CloneProject(ctx context.Context, in *CloneProjectRequest, opts ...grpc.CallOption) (*CloneProjectResponse, error)
+	// BatchCreateJobSpecifications registers multiple new jobs of a namespace
+	// and deploys them, specs of jobs already registered in the project fail
+	BatchCreateJobSpecifications(ctx context.Context, in *BatchCreateJobSpecificationsRequest, opts ...grpc.CallOption) (*BatchCreateJobSpecificationsResponse, error)
+	// RotateSecrets re-encrypts secrets of all projects with a new application
+	// key in a single transaction, callers need the ADMIN role
+	RotateSecrets(ctx context.Context, in *RotateSecretsRequest, opts ...grpc.CallOption) (*RotateSecretsResponse, error)
+	// ListSchedulerJobs lists the jobs of a project currently deployed to the
+	// scheduler, callers need the ADMIN role
+	ListSchedulerJobs(ctx context.Context, in *ListSchedulerJobsRequest, opts ...grpc.CallOption) (*ListSchedulerJobsResponse, error)
+	// GrantProjectRole binds a role in the project to a subject, replacing the
+	// role it had, callers need the ADMIN role in the project
+	GrantProjectRole(ctx context.Context, in *GrantProjectRoleRequest, opts ...grpc.CallOption) (*GrantProjectRoleResponse, error)
+	// RevokeProjectRole removes the role of a subject in the project, callers
+	// need the ADMIN role in the project
+	RevokeProjectRole(ctx context.Context, in *RevokeProjectRoleRequest, opts ...grpc.CallOption) (*RevokeProjectRoleResponse, error)
+	// ListProjectRoles lists the role bindings of the project
+	ListProjectRoles(ctx context.Context, in *ListProjectRolesRequest, opts ...grpc.CallOption) (*ListProjectRolesResponse, error)
+	// GenerateAPIKey creates a key authenticating machine callers with a role
+	// in the project, callers need the ADMIN role in the project
+	GenerateAPIKey(ctx context.Context, in *GenerateAPIKeyRequest, opts ...grpc.CallOption) (*GenerateAPIKeyResponse, error)
+	// RegisterProjectVariables replaces the variables shared by the jobs of the
+	// project, they are available in the templates of job assets
+	RegisterProjectVariables(ctx context.Context, in *RegisterProjectVariablesRequest, opts ...grpc.CallOption) (*RegisterProjectVariablesResponse, error)
+	// GetProjectVariables reads the variables shared by the jobs of the project
+	GetProjectVariables(ctx context.Context, in *GetProjectVariablesRequest, opts ...grpc.CallOption) (*GetProjectVariablesResponse, error)
+	// SetProjectQuota limits the jobs the project can register, unset limits
+	// fall back to the default quota of the server
+	SetProjectQuota(ctx context.Context, in *SetProjectQuotaRequest, opts ...grpc.CallOption) (*SetProjectQuotaResponse, error)
+	// ListAuditLog lists the calls that changed the state of optimus, from
+	// the latest
+	ListAuditLog(ctx context.Context, in *ListAuditLogRequest, opts ...grpc.CallOption) (*ListAuditLogResponse, error)
+	// EstimateJobCost estimates the cost of a run of a job from the bytes its query reads
+	EstimateJobCost(ctx context.Context, in *EstimateJobCostRequest, opts ...grpc.CallOption) (*EstimateJobCostResponse, error)
+	// ListSLABreaches lists runs of jobs of the project that didn't start
+	// within the sla duration of the job, from the latest run
+	ListSLABreaches(ctx context.Context, in *ListSLABreachesRequest, opts ...grpc.CallOption) (*ListSLABreachesResponse, error)
+	// ExplainJobDependencies lists the dependencies of a job as declared and
+	// inferred, with how each one is resolved to a job or why it is excluded
+	ExplainJobDependencies(ctx context.Context, in *ExplainJobDependenciesRequest, opts ...grpc.CallOption) (*ExplainJobDependenciesResponse, error)
+	// ValidateSchedule parses a cron schedule interval and lists its upcoming
+	// runs, with warnings for schedules running more often than once a minute
+	// or never running
+	ValidateSchedule(ctx context.Context, in *ValidateScheduleRequest, opts ...grpc.CallOption) (*ValidateScheduleResponse, error)
+	// ListSuppressedJobRuns lists which of the upcoming runs of a job fall in
+	// a blackout window of its project and are skipped
+	ListSuppressedJobRuns(ctx context.Context, in *ListSuppressedJobRunsRequest, opts ...grpc.CallOption) (*ListSuppressedJobRunsResponse, error)
+	// TriggerJobRun starts a run of a job on demand outside its schedule
+	TriggerJobRun(ctx context.Context, in *TriggerJobRunRequest, opts ...grpc.CallOption) (*TriggerJobRunResponse, error)
+	// PauseJob stops the scheduled runs of a job until it is resumed
+	PauseJob(ctx context.Context, in *PauseJobRequest, opts ...grpc.CallOption) (*PauseJobResponse, error)
+	// ResumeJob restarts the scheduled runs of a paused job
+	ResumeJob(ctx context.Context, in *ResumeJobRequest, opts ...grpc.CallOption) (*ResumeJobResponse, error)
+	// PauseProject stops the scheduled runs of all jobs of a project, either all
+	// of them are paused or none
+	PauseProject(ctx context.Context, in *PauseProjectRequest, opts ...grpc.CallOption) (*PauseProjectResponse, error)
+	// ResumeProject restarts the scheduled runs of all jobs of a project
+	ResumeProject(ctx context.Context, in *ResumeProjectRequest, opts ...grpc.CallOption) (*ResumeProjectResponse, error)
+	// GetDependencyGraph renders the resolved dependencies of the jobs of a project
+	GetDependencyGraph(ctx context.Context, in *GetDependencyGraphRequest, opts ...grpc.CallOption) (*GetDependencyGraphResponse, error)
 }
 
 type runtimeServiceClient struct {
@@ -376,6 +496,411 @@ func (c *runtimeServiceClient) Replay(ctx context.Context, in *ReplayRequest, op
 	return out, nil
 }
 
+func (c *runtimeServiceClient) PreviewSchedulePropagation(ctx context.Context, in *PreviewSchedulePropagationRequest, opts ...grpc.CallOption) (*PreviewSchedulePropagationResponse, error) {
+	out := new(PreviewSchedulePropagationResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/PreviewSchedulePropagation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) ListActiveJobInstances(ctx context.Context, in *ListActiveJobInstancesRequest, opts ...grpc.CallOption) (*ListActiveJobInstancesResponse, error) {
+	out := new(ListActiveJobInstancesResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/ListActiveJobInstances", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) ListRunningJobs(ctx context.Context, in *ListRunningJobsRequest, opts ...grpc.CallOption) (*ListRunningJobsResponse, error) {
+	out := new(ListRunningJobsResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/ListRunningJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) NegotiateAPIVersion(ctx context.Context, in *NegotiateAPIVersionRequest, opts ...grpc.CallOption) (*NegotiateAPIVersionResponse, error) {
+	out := new(NegotiateAPIVersionResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/NegotiateAPIVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) SmartBackfillJob(ctx context.Context, in *SmartBackfillJobRequest, opts ...grpc.CallOption) (*SmartBackfillJobResponse, error) {
+	out := new(SmartBackfillJobResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/SmartBackfillJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) PinDependencyVersion(ctx context.Context, in *PinDependencyVersionRequest, opts ...grpc.CallOption) (*PinDependencyVersionResponse, error) {
+	out := new(PinDependencyVersionResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/PinDependencyVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) UnpinDependency(ctx context.Context, in *UnpinDependencyRequest, opts ...grpc.CallOption) (*UnpinDependencyResponse, error) {
+	out := new(UnpinDependencyResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/UnpinDependency", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) ListDependencyPins(ctx context.Context, in *ListDependencyPinsRequest, opts ...grpc.CallOption) (*ListDependencyPinsResponse, error) {
+	out := new(ListDependencyPinsResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/ListDependencyPins", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) GetProjectDiagnostics(ctx context.Context, in *GetProjectDiagnosticsRequest, opts ...grpc.CallOption) (*GetProjectDiagnosticsResponse, error) {
+	out := new(GetProjectDiagnosticsResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/GetProjectDiagnostics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) TagRelease(ctx context.Context, in *TagReleaseRequest, opts ...grpc.CallOption) (*TagReleaseResponse, error) {
+	out := new(TagReleaseResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/TagRelease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) RollbackToRelease(ctx context.Context, in *RollbackToReleaseRequest, opts ...grpc.CallOption) (*RollbackToReleaseResponse, error) {
+	out := new(RollbackToReleaseResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/RollbackToRelease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) GetInstanceStatusSummary(ctx context.Context, in *GetInstanceStatusSummaryRequest, opts ...grpc.CallOption) (*GetInstanceStatusSummaryResponse, error) {
+	out := new(GetInstanceStatusSummaryResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/GetInstanceStatusSummary", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) CheckJobSchemaDrift(ctx context.Context, in *CheckJobSchemaDriftRequest, opts ...grpc.CallOption) (*CheckJobSchemaDriftResponse, error) {
+	out := new(CheckJobSchemaDriftResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/CheckJobSchemaDrift", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) GetReplayStatus(ctx context.Context, in *GetReplayStatusRequest, opts ...grpc.CallOption) (*GetReplayStatusResponse, error) {
+	out := new(GetReplayStatusResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/GetReplayStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) ListSecrets(ctx context.Context, in *ListSecretsRequest, opts ...grpc.CallOption) (*ListSecretsResponse, error) {
+	out := new(ListSecretsResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/ListSecrets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) RevealProjectSecret(ctx context.Context, in *RevealProjectSecretRequest, opts ...grpc.CallOption) (*RevealProjectSecretResponse, error) {
+	out := new(RevealProjectSecretResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/RevealProjectSecret", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) DiffJobSpec(ctx context.Context, in *DiffJobSpecRequest, opts ...grpc.CallOption) (*DiffJobSpecResponse, error) {
+	out := new(DiffJobSpecResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/DiffJobSpec", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) ListJobsByOwner(ctx context.Context, in *ListJobsByOwnerRequest, opts ...grpc.CallOption) (*ListJobsByOwnerResponse, error) {
+	out := new(ListJobsByOwnerResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/ListJobsByOwner", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) ReconcileJobInstances(ctx context.Context, in *ReconcileJobInstancesRequest, opts ...grpc.CallOption) (*ReconcileJobInstancesResponse, error) {
+	out := new(ReconcileJobInstancesResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/ReconcileJobInstances", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) UndeleteJobSpecification(ctx context.Context, in *UndeleteJobSpecificationRequest, opts ...grpc.CallOption) (*UndeleteJobSpecificationResponse, error) {
+	out := new(UndeleteJobSpecificationResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/UndeleteJobSpecification", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) PurgeDeletedJobSpecifications(ctx context.Context, in *PurgeDeletedJobSpecificationsRequest, opts ...grpc.CallOption) (*PurgeDeletedJobSpecificationsResponse, error) {
+	out := new(PurgeDeletedJobSpecificationsResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/PurgeDeletedJobSpecifications", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) CloneJobSpecification(ctx context.Context, in *CloneJobSpecificationRequest, opts ...grpc.CallOption) (*CloneJobSpecificationResponse, error) {
+	out := new(CloneJobSpecificationResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/CloneJobSpecification", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) CloneProject(ctx context.Context, in *CloneProjectRequest, opts ...grpc.CallOption) (*CloneProjectResponse, error) {
+	out := new(CloneProjectResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/CloneProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) BatchCreateJobSpecifications(ctx context.Context, in *BatchCreateJobSpecificationsRequest, opts ...grpc.CallOption) (*BatchCreateJobSpecificationsResponse, error) {
+	out := new(BatchCreateJobSpecificationsResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/BatchCreateJobSpecifications", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) RotateSecrets(ctx context.Context, in *RotateSecretsRequest, opts ...grpc.CallOption) (*RotateSecretsResponse, error) {
+	out := new(RotateSecretsResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/RotateSecrets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) ListSchedulerJobs(ctx context.Context, in *ListSchedulerJobsRequest, opts ...grpc.CallOption) (*ListSchedulerJobsResponse, error) {
+	out := new(ListSchedulerJobsResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/ListSchedulerJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) GrantProjectRole(ctx context.Context, in *GrantProjectRoleRequest, opts ...grpc.CallOption) (*GrantProjectRoleResponse, error) {
+	out := new(GrantProjectRoleResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/GrantProjectRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) RevokeProjectRole(ctx context.Context, in *RevokeProjectRoleRequest, opts ...grpc.CallOption) (*RevokeProjectRoleResponse, error) {
+	out := new(RevokeProjectRoleResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/RevokeProjectRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) ListProjectRoles(ctx context.Context, in *ListProjectRolesRequest, opts ...grpc.CallOption) (*ListProjectRolesResponse, error) {
+	out := new(ListProjectRolesResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/ListProjectRoles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) GenerateAPIKey(ctx context.Context, in *GenerateAPIKeyRequest, opts ...grpc.CallOption) (*GenerateAPIKeyResponse, error) {
+	out := new(GenerateAPIKeyResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/GenerateAPIKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) RegisterProjectVariables(ctx context.Context, in *RegisterProjectVariablesRequest, opts ...grpc.CallOption) (*RegisterProjectVariablesResponse, error) {
+	out := new(RegisterProjectVariablesResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/RegisterProjectVariables", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) GetProjectVariables(ctx context.Context, in *GetProjectVariablesRequest, opts ...grpc.CallOption) (*GetProjectVariablesResponse, error) {
+	out := new(GetProjectVariablesResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/GetProjectVariables", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) SetProjectQuota(ctx context.Context, in *SetProjectQuotaRequest, opts ...grpc.CallOption) (*SetProjectQuotaResponse, error) {
+	out := new(SetProjectQuotaResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/SetProjectQuota", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) ListAuditLog(ctx context.Context, in *ListAuditLogRequest, opts ...grpc.CallOption) (*ListAuditLogResponse, error) {
+	out := new(ListAuditLogResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/ListAuditLog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) EstimateJobCost(ctx context.Context, in *EstimateJobCostRequest, opts ...grpc.CallOption) (*EstimateJobCostResponse, error) {
+	out := new(EstimateJobCostResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/EstimateJobCost", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) ListSLABreaches(ctx context.Context, in *ListSLABreachesRequest, opts ...grpc.CallOption) (*ListSLABreachesResponse, error) {
+	out := new(ListSLABreachesResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/ListSLABreaches", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) ExplainJobDependencies(ctx context.Context, in *ExplainJobDependenciesRequest, opts ...grpc.CallOption) (*ExplainJobDependenciesResponse, error) {
+	out := new(ExplainJobDependenciesResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/ExplainJobDependencies", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) ValidateSchedule(ctx context.Context, in *ValidateScheduleRequest, opts ...grpc.CallOption) (*ValidateScheduleResponse, error) {
+	out := new(ValidateScheduleResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/ValidateSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) ListSuppressedJobRuns(ctx context.Context, in *ListSuppressedJobRunsRequest, opts ...grpc.CallOption) (*ListSuppressedJobRunsResponse, error) {
+	out := new(ListSuppressedJobRunsResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/ListSuppressedJobRuns", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) TriggerJobRun(ctx context.Context, in *TriggerJobRunRequest, opts ...grpc.CallOption) (*TriggerJobRunResponse, error) {
+	out := new(TriggerJobRunResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/TriggerJobRun", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) PauseJob(ctx context.Context, in *PauseJobRequest, opts ...grpc.CallOption) (*PauseJobResponse, error) {
+	out := new(PauseJobResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/PauseJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) ResumeJob(ctx context.Context, in *ResumeJobRequest, opts ...grpc.CallOption) (*ResumeJobResponse, error) {
+	out := new(ResumeJobResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/ResumeJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) PauseProject(ctx context.Context, in *PauseProjectRequest, opts ...grpc.CallOption) (*PauseProjectResponse, error) {
+	out := new(PauseProjectResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/PauseProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) ResumeProject(ctx context.Context, in *ResumeProjectRequest, opts ...grpc.CallOption) (*ResumeProjectResponse, error) {
+	out := new(ResumeProjectResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/ResumeProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeServiceClient) GetDependencyGraph(ctx context.Context, in *GetDependencyGraphRequest, opts ...grpc.CallOption) (*GetDependencyGraphResponse, error) {
+	out := new(GetDependencyGraphResponse)
+	err := c.cc.Invoke(ctx, "/odpf.optimus.RuntimeService/GetDependencyGraph", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RuntimeServiceServer is the server API for RuntimeService service.
 // All implementations must embed UnimplementedRuntimeServiceServer
 // for forward compatibility
@@ -434,556 +959,1621 @@ type RuntimeServiceServer interface {
 	UpdateResource(context.Context, *UpdateResourceRequest) (*UpdateResourceResponse, error)
 	ReplayDryRun(context.Context, *ReplayRequest) (*ReplayDryRunResponse, error)
 	Replay(context.Context, *ReplayRequest) (*ReplayResponse, error)
+	// PreviewSchedulePropagation lists the schedule updates proposed for downstream
+	// jobs aligned to the schedule of the provided job
+	PreviewSchedulePropagation(context.Context, *PreviewSchedulePropagationRequest) (*PreviewSchedulePropagationResponse, error)
+	// ListActiveJobInstances is an internal admin command listing running instances
+	// of jobs across all projects
+	ListActiveJobInstances(context.Context, *ListActiveJobInstancesRequest) (*ListActiveJobInstancesResponse, error)
+	// ListRunningJobs lists runs of jobs currently being executed by the scheduler
+	ListRunningJobs(context.Context, *ListRunningJobsRequest) (*ListRunningJobsResponse, error)
+	// NegotiateAPIVersion should be called by clients before any other call to
+	// verify the client version is supported by the server
+	NegotiateAPIVersion(context.Context, *NegotiateAPIVersionRequest) (*NegotiateAPIVersionResponse, error)
+	// SmartBackfillJob replays the runs of a job missing in the scheduler between
+	// the provided dates, with dry_run only the backfill plan is returned
+	SmartBackfillJob(context.Context, *SmartBackfillJobRequest) (*SmartBackfillJobResponse, error)
+	// PinDependencyVersion compiles a job against the current spec of one of its
+	// upstream jobs, even after the upstream is updated, until it is unpinned
+	PinDependencyVersion(context.Context, *PinDependencyVersionRequest) (*PinDependencyVersionResponse, error)
+	// UnpinDependency compiles a job against the latest spec of the upstream again
+	UnpinDependency(context.Context, *UnpinDependencyRequest) (*UnpinDependencyResponse, error)
+	// ListDependencyPins lists the pinned upstream specs of jobs in a project
+	ListDependencyPins(context.Context, *ListDependencyPinsRequest) (*ListDependencyPinsResponse, error)
+	// GetProjectDiagnostics checks the database, storage, scheduler and job dependencies of a project
+	GetProjectDiagnostics(context.Context, *GetProjectDiagnosticsRequest) (*GetProjectDiagnosticsResponse, error)
+	// TagRelease is an admin call snapshotting the current job specs of a project under a release tag
+	TagRelease(context.Context, *TagReleaseRequest) (*TagReleaseResponse, error)
+	// RollbackToRelease is an admin call restoring and deploying the job specs of a project tagged in a release
+	RollbackToRelease(context.Context, *RollbackToReleaseRequest) (*RollbackToReleaseResponse, error)
+	// GetInstanceStatusSummary counts the instances of jobs in a project by their state
+	GetInstanceStatusSummary(context.Context, *GetInstanceStatusSummaryRequest) (*GetInstanceStatusSummaryResponse, error)
+	// CheckJobSchemaDrift compares the schema of the destination table of a job with the schema its query produces
+	CheckJobSchemaDrift(context.Context, *CheckJobSchemaDriftRequest) (*CheckJobSchemaDriftResponse, error)
+	// GetReplayStatus returns the status of a replay started with Replay, replays
+	// are persisted so they can be polled across server restarts
+	GetReplayStatus(context.Context, *GetReplayStatusRequest) (*GetReplayStatusResponse, error)
+	// ListSecrets returns names of the secrets registered in a project
+	ListSecrets(context.Context, *ListSecretsRequest) (*ListSecretsResponse, error)
+	// RevealProjectSecret returns the value of a secret when the confirmation
+	// phrase matches the one configured on the server, reveals are audit logged
+	RevealProjectSecret(context.Context, *RevealProjectSecretRequest) (*RevealProjectSecretResponse, error)
+	// DiffJobSpec compares two versions of a job spec archived on each change,
+	// either version can be "current" for the registered spec
+	DiffJobSpec(context.Context, *DiffJobSpecRequest) (*DiffJobSpecResponse, error)
+	// ListJobsByOwner lists jobs of all projects owned by the owner, callers
+	// need the ADMIN role since jobs of other projects are listed
+	ListJobsByOwner(context.Context, *ListJobsByOwnerRequest) (*ListJobsByOwnerResponse, error)
+	// ReconcileJobInstances fails the long running instances of a project
+	// orphaned by the scheduler, callers need the ADMIN role
+	ReconcileJobInstances(context.Context, *ReconcileJobInstancesRequest) (*ReconcileJobInstancesResponse, error)
+	// UndeleteJobSpecification restores a deleted job specification and
+	// deploys it again
+	UndeleteJobSpecification(context.Context, *UndeleteJobSpecificationRequest) (*UndeleteJobSpecificationResponse, error)
+	// PurgeDeletedJobSpecifications permanently removes job specifications of
+	// all projects deleted a while ago, callers need the ADMIN role
+	PurgeDeletedJobSpecifications(context.Context, *PurgeDeletedJobSpecificationsRequest) (*PurgeDeletedJobSpecificationsResponse, error)
+	// CloneJobSpecification copies a job spec to a new job, the clone is
+	// saved as a draft and deployed with the next deployment of its namespace
+	CloneJobSpecification(context.Context, *CloneJobSpecificationRequest) (*CloneJobSpecificationResponse, error)
+	// CloneProject copies the job specifications of a project to another
+	// registered project, no jobs are created if any of them is already
+	// registered in the destination project
+	CloneProject(context.Context, *CloneProjectRequest) (*CloneProjectResponse, error)
+	// BatchCreateJobSpecifications registers multiple new jobs of a namespace
+	// and deploys them, specs of jobs already registered in the project fail
+	BatchCreateJobSpecifications(context.Context, *BatchCreateJobSpecificationsRequest) (*BatchCreateJobSpecificationsResponse, error)
+	// RotateSecrets re-encrypts secrets of all projects with a new application
+	// key in a single transaction, callers need the ADMIN role
+	RotateSecrets(context.Context, *RotateSecretsRequest) (*RotateSecretsResponse, error)
+	// ListSchedulerJobs lists the jobs of a project currently deployed to the
+	// scheduler, callers need the ADMIN role
+	ListSchedulerJobs(context.Context, *ListSchedulerJobsRequest) (*ListSchedulerJobsResponse, error)
+	// GrantProjectRole binds a role in the project to a subject, replacing the
+	// role it had, callers need the ADMIN role in the project
+	GrantProjectRole(context.Context, *GrantProjectRoleRequest) (*GrantProjectRoleResponse, error)
+	// RevokeProjectRole removes the role of a subject in the project, callers
+	// need the ADMIN role in the project
+	RevokeProjectRole(context.Context, *RevokeProjectRoleRequest) (*RevokeProjectRoleResponse, error)
+	// ListProjectRoles lists the role bindings of the project
+	ListProjectRoles(context.Context, *ListProjectRolesRequest) (*ListProjectRolesResponse, error)
+	// GenerateAPIKey creates a key authenticating machine callers with a role
+	// in the project, callers need the ADMIN role in the project
+	GenerateAPIKey(context.Context, *GenerateAPIKeyRequest) (*GenerateAPIKeyResponse, error)
+	// RegisterProjectVariables replaces the variables shared by the jobs of the
+	// project, they are available in the templates of job assets
+	RegisterProjectVariables(context.Context, *RegisterProjectVariablesRequest) (*RegisterProjectVariablesResponse, error)
+	// GetProjectVariables reads the variables shared by the jobs of the project
+	GetProjectVariables(context.Context, *GetProjectVariablesRequest) (*GetProjectVariablesResponse, error)
+	// SetProjectQuota limits the jobs the project can register, unset limits
+	// fall back to the default quota of the server
+	SetProjectQuota(context.Context, *SetProjectQuotaRequest) (*SetProjectQuotaResponse, error)
+	// ListAuditLog lists the calls that changed the state of optimus, from
+	// the latest
+	ListAuditLog(context.Context, *ListAuditLogRequest) (*ListAuditLogResponse, error)
+	// EstimateJobCost estimates the cost of a run of a job from the bytes its query reads
+	EstimateJobCost(context.Context, *EstimateJobCostRequest) (*EstimateJobCostResponse, error)
+	// ListSLABreaches lists runs of jobs of the project that didn't start
+	// within the sla duration of the job, from the latest run
+	ListSLABreaches(context.Context, *ListSLABreachesRequest) (*ListSLABreachesResponse, error)
+	// ExplainJobDependencies lists the dependencies of a job as declared and
+	// inferred, with how each one is resolved to a job or why it is excluded
+	ExplainJobDependencies(context.Context, *ExplainJobDependenciesRequest) (*ExplainJobDependenciesResponse, error)
+	// ValidateSchedule parses a cron schedule interval and lists its upcoming
+	// runs, with warnings for schedules running more often than once a minute
+	// or never running
+	ValidateSchedule(context.Context, *ValidateScheduleRequest) (*ValidateScheduleResponse, error)
+	// ListSuppressedJobRuns lists which of the upcoming runs of a job fall in
+	// a blackout window of its project and are skipped
+	ListSuppressedJobRuns(context.Context, *ListSuppressedJobRunsRequest) (*ListSuppressedJobRunsResponse, error)
+	// TriggerJobRun starts a run of a job on demand outside its schedule
+	TriggerJobRun(context.Context, *TriggerJobRunRequest) (*TriggerJobRunResponse, error)
+	// PauseJob stops the scheduled runs of a job until it is resumed
+	PauseJob(context.Context, *PauseJobRequest) (*PauseJobResponse, error)
+	// ResumeJob restarts the scheduled runs of a paused job
+	ResumeJob(context.Context, *ResumeJobRequest) (*ResumeJobResponse, error)
+	// PauseProject stops the scheduled runs of all jobs of a project, either all
+	// of them are paused or none
+	PauseProject(context.Context, *PauseProjectRequest) (*PauseProjectResponse, error)
+	// ResumeProject restarts the scheduled runs of all jobs of a project
+	ResumeProject(context.Context, *ResumeProjectRequest) (*ResumeProjectResponse, error)
+	// GetDependencyGraph renders the resolved dependencies of the jobs of a project
+	GetDependencyGraph(context.Context, *GetDependencyGraphRequest) (*GetDependencyGraphResponse, error)
+	mustEmbedUnimplementedRuntimeServiceServer()
+}
+
+// UnimplementedRuntimeServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedRuntimeServiceServer struct {
+}
+
+func (UnimplementedRuntimeServiceServer) Version(context.Context, *VersionRequest) (*VersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
+}
+func (UnimplementedRuntimeServiceServer) DeployJobSpecification(*DeployJobSpecificationRequest, RuntimeService_DeployJobSpecificationServer) error {
+	return status.Errorf(codes.Unimplemented, "method DeployJobSpecification not implemented")
+}
+func (UnimplementedRuntimeServiceServer) CreateJobSpecification(context.Context, *CreateJobSpecificationRequest) (*CreateJobSpecificationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateJobSpecification not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ReadJobSpecification(context.Context, *ReadJobSpecificationRequest) (*ReadJobSpecificationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadJobSpecification not implemented")
+}
+func (UnimplementedRuntimeServiceServer) DeleteJobSpecification(context.Context, *DeleteJobSpecificationRequest) (*DeleteJobSpecificationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteJobSpecification not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ListJobSpecification(context.Context, *ListJobSpecificationRequest) (*ListJobSpecificationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobSpecification not implemented")
+}
+func (UnimplementedRuntimeServiceServer) DumpJobSpecification(context.Context, *DumpJobSpecificationRequest) (*DumpJobSpecificationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DumpJobSpecification not implemented")
+}
+func (UnimplementedRuntimeServiceServer) CheckJobSpecification(context.Context, *CheckJobSpecificationRequest) (*CheckJobSpecificationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckJobSpecification not implemented")
+}
+func (UnimplementedRuntimeServiceServer) CheckJobSpecifications(*CheckJobSpecificationsRequest, RuntimeService_CheckJobSpecificationsServer) error {
+	return status.Errorf(codes.Unimplemented, "method CheckJobSpecifications not implemented")
+}
+func (UnimplementedRuntimeServiceServer) RegisterProject(context.Context, *RegisterProjectRequest) (*RegisterProjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterProject not implemented")
+}
+func (UnimplementedRuntimeServiceServer) RegisterProjectNamespace(context.Context, *RegisterProjectNamespaceRequest) (*RegisterProjectNamespaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterProjectNamespace not implemented")
+}
+func (UnimplementedRuntimeServiceServer) RegisterSecret(context.Context, *RegisterSecretRequest) (*RegisterSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterSecret not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ListProjects(context.Context, *ListProjectsRequest) (*ListProjectsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProjects not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ListProjectNamespaces(context.Context, *ListProjectNamespacesRequest) (*ListProjectNamespacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProjectNamespaces not implemented")
+}
+func (UnimplementedRuntimeServiceServer) RegisterInstance(context.Context, *RegisterInstanceRequest) (*RegisterInstanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterInstance not implemented")
+}
+func (UnimplementedRuntimeServiceServer) JobStatus(context.Context, *JobStatusRequest) (*JobStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JobStatus not implemented")
+}
+func (UnimplementedRuntimeServiceServer) RegisterJobEvent(context.Context, *RegisterJobEventRequest) (*RegisterJobEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterJobEvent not implemented")
+}
+func (UnimplementedRuntimeServiceServer) GetWindow(context.Context, *GetWindowRequest) (*GetWindowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWindow not implemented")
+}
+func (UnimplementedRuntimeServiceServer) DeployResourceSpecification(*DeployResourceSpecificationRequest, RuntimeService_DeployResourceSpecificationServer) error {
+	return status.Errorf(codes.Unimplemented, "method DeployResourceSpecification not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ListResourceSpecification(context.Context, *ListResourceSpecificationRequest) (*ListResourceSpecificationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListResourceSpecification not implemented")
+}
+func (UnimplementedRuntimeServiceServer) CreateResource(context.Context, *CreateResourceRequest) (*CreateResourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateResource not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ReadResource(context.Context, *ReadResourceRequest) (*ReadResourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadResource not implemented")
+}
+func (UnimplementedRuntimeServiceServer) UpdateResource(context.Context, *UpdateResourceRequest) (*UpdateResourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateResource not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ReplayDryRun(context.Context, *ReplayRequest) (*ReplayDryRunResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayDryRun not implemented")
+}
+func (UnimplementedRuntimeServiceServer) Replay(context.Context, *ReplayRequest) (*ReplayResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Replay not implemented")
+}
+func (UnimplementedRuntimeServiceServer) PreviewSchedulePropagation(context.Context, *PreviewSchedulePropagationRequest) (*PreviewSchedulePropagationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreviewSchedulePropagation not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ListActiveJobInstances(context.Context, *ListActiveJobInstancesRequest) (*ListActiveJobInstancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListActiveJobInstances not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ListRunningJobs(context.Context, *ListRunningJobsRequest) (*ListRunningJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRunningJobs not implemented")
+}
+func (UnimplementedRuntimeServiceServer) NegotiateAPIVersion(context.Context, *NegotiateAPIVersionRequest) (*NegotiateAPIVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NegotiateAPIVersion not implemented")
+}
+func (UnimplementedRuntimeServiceServer) SmartBackfillJob(context.Context, *SmartBackfillJobRequest) (*SmartBackfillJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SmartBackfillJob not implemented")
+}
+func (UnimplementedRuntimeServiceServer) PinDependencyVersion(context.Context, *PinDependencyVersionRequest) (*PinDependencyVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PinDependencyVersion not implemented")
+}
+func (UnimplementedRuntimeServiceServer) UnpinDependency(context.Context, *UnpinDependencyRequest) (*UnpinDependencyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnpinDependency not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ListDependencyPins(context.Context, *ListDependencyPinsRequest) (*ListDependencyPinsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDependencyPins not implemented")
+}
+func (UnimplementedRuntimeServiceServer) GetProjectDiagnostics(context.Context, *GetProjectDiagnosticsRequest) (*GetProjectDiagnosticsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProjectDiagnostics not implemented")
+}
+func (UnimplementedRuntimeServiceServer) TagRelease(context.Context, *TagReleaseRequest) (*TagReleaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TagRelease not implemented")
+}
+func (UnimplementedRuntimeServiceServer) RollbackToRelease(context.Context, *RollbackToReleaseRequest) (*RollbackToReleaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackToRelease not implemented")
+}
+func (UnimplementedRuntimeServiceServer) GetInstanceStatusSummary(context.Context, *GetInstanceStatusSummaryRequest) (*GetInstanceStatusSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInstanceStatusSummary not implemented")
+}
+func (UnimplementedRuntimeServiceServer) CheckJobSchemaDrift(context.Context, *CheckJobSchemaDriftRequest) (*CheckJobSchemaDriftResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckJobSchemaDrift not implemented")
+}
+func (UnimplementedRuntimeServiceServer) GetReplayStatus(context.Context, *GetReplayStatusRequest) (*GetReplayStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReplayStatus not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ListSecrets(context.Context, *ListSecretsRequest) (*ListSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSecrets not implemented")
+}
+func (UnimplementedRuntimeServiceServer) RevealProjectSecret(context.Context, *RevealProjectSecretRequest) (*RevealProjectSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevealProjectSecret not implemented")
+}
+func (UnimplementedRuntimeServiceServer) DiffJobSpec(context.Context, *DiffJobSpecRequest) (*DiffJobSpecResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffJobSpec not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ListJobsByOwner(context.Context, *ListJobsByOwnerRequest) (*ListJobsByOwnerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobsByOwner not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ReconcileJobInstances(context.Context, *ReconcileJobInstancesRequest) (*ReconcileJobInstancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReconcileJobInstances not implemented")
+}
+func (UnimplementedRuntimeServiceServer) UndeleteJobSpecification(context.Context, *UndeleteJobSpecificationRequest) (*UndeleteJobSpecificationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UndeleteJobSpecification not implemented")
+}
+func (UnimplementedRuntimeServiceServer) PurgeDeletedJobSpecifications(context.Context, *PurgeDeletedJobSpecificationsRequest) (*PurgeDeletedJobSpecificationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeDeletedJobSpecifications not implemented")
+}
+func (UnimplementedRuntimeServiceServer) CloneJobSpecification(context.Context, *CloneJobSpecificationRequest) (*CloneJobSpecificationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneJobSpecification not implemented")
+}
+func (UnimplementedRuntimeServiceServer) CloneProject(context.Context, *CloneProjectRequest) (*CloneProjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneProject not implemented")
+}
+func (UnimplementedRuntimeServiceServer) BatchCreateJobSpecifications(context.Context, *BatchCreateJobSpecificationsRequest) (*BatchCreateJobSpecificationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreateJobSpecifications not implemented")
+}
+func (UnimplementedRuntimeServiceServer) RotateSecrets(context.Context, *RotateSecretsRequest) (*RotateSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateSecrets not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ListSchedulerJobs(context.Context, *ListSchedulerJobsRequest) (*ListSchedulerJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSchedulerJobs not implemented")
+}
+func (UnimplementedRuntimeServiceServer) GrantProjectRole(context.Context, *GrantProjectRoleRequest) (*GrantProjectRoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrantProjectRole not implemented")
+}
+func (UnimplementedRuntimeServiceServer) RevokeProjectRole(context.Context, *RevokeProjectRoleRequest) (*RevokeProjectRoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeProjectRole not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ListProjectRoles(context.Context, *ListProjectRolesRequest) (*ListProjectRolesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProjectRoles not implemented")
+}
+func (UnimplementedRuntimeServiceServer) GenerateAPIKey(context.Context, *GenerateAPIKeyRequest) (*GenerateAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateAPIKey not implemented")
+}
+func (UnimplementedRuntimeServiceServer) RegisterProjectVariables(context.Context, *RegisterProjectVariablesRequest) (*RegisterProjectVariablesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterProjectVariables not implemented")
+}
+func (UnimplementedRuntimeServiceServer) GetProjectVariables(context.Context, *GetProjectVariablesRequest) (*GetProjectVariablesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProjectVariables not implemented")
+}
+func (UnimplementedRuntimeServiceServer) SetProjectQuota(context.Context, *SetProjectQuotaRequest) (*SetProjectQuotaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetProjectQuota not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ListAuditLog(context.Context, *ListAuditLogRequest) (*ListAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditLog not implemented")
+}
+func (UnimplementedRuntimeServiceServer) EstimateJobCost(context.Context, *EstimateJobCostRequest) (*EstimateJobCostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EstimateJobCost not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ListSLABreaches(context.Context, *ListSLABreachesRequest) (*ListSLABreachesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSLABreaches not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ExplainJobDependencies(context.Context, *ExplainJobDependenciesRequest) (*ExplainJobDependenciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExplainJobDependencies not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ValidateSchedule(context.Context, *ValidateScheduleRequest) (*ValidateScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateSchedule not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ListSuppressedJobRuns(context.Context, *ListSuppressedJobRunsRequest) (*ListSuppressedJobRunsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSuppressedJobRuns not implemented")
+}
+func (UnimplementedRuntimeServiceServer) TriggerJobRun(context.Context, *TriggerJobRunRequest) (*TriggerJobRunResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerJobRun not implemented")
+}
+func (UnimplementedRuntimeServiceServer) PauseJob(context.Context, *PauseJobRequest) (*PauseJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseJob not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ResumeJob(context.Context, *ResumeJobRequest) (*ResumeJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeJob not implemented")
+}
+func (UnimplementedRuntimeServiceServer) PauseProject(context.Context, *PauseProjectRequest) (*PauseProjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseProject not implemented")
+}
+func (UnimplementedRuntimeServiceServer) ResumeProject(context.Context, *ResumeProjectRequest) (*ResumeProjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeProject not implemented")
+}
+func (UnimplementedRuntimeServiceServer) GetDependencyGraph(context.Context, *GetDependencyGraphRequest) (*GetDependencyGraphResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDependencyGraph not implemented")
+}
+func (UnimplementedRuntimeServiceServer) mustEmbedUnimplementedRuntimeServiceServer() {}
+
+// UnsafeRuntimeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RuntimeServiceServer will
+// result in compilation errors.
+type UnsafeRuntimeServiceServer interface {
 	mustEmbedUnimplementedRuntimeServiceServer()
 }
 
-// UnimplementedRuntimeServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedRuntimeServiceServer struct {
+func RegisterRuntimeServiceServer(s grpc.ServiceRegistrar, srv RuntimeServiceServer) {
+	s.RegisterService(&RuntimeService_ServiceDesc, srv)
+}
+
+func _RuntimeService_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).Version(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/Version",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).Version(ctx, req.(*VersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeService_DeployJobSpecification_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DeployJobSpecificationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RuntimeServiceServer).DeployJobSpecification(m, &runtimeServiceDeployJobSpecificationServer{stream})
+}
+
+type RuntimeService_DeployJobSpecificationServer interface {
+	Send(*DeployJobSpecificationResponse) error
+	grpc.ServerStream
+}
+
+type runtimeServiceDeployJobSpecificationServer struct {
+	grpc.ServerStream
+}
+
+func (x *runtimeServiceDeployJobSpecificationServer) Send(m *DeployJobSpecificationResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _RuntimeService_CreateJobSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateJobSpecificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).CreateJobSpecification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/CreateJobSpecification",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).CreateJobSpecification(ctx, req.(*CreateJobSpecificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeService_ReadJobSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadJobSpecificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).ReadJobSpecification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/ReadJobSpecification",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).ReadJobSpecification(ctx, req.(*ReadJobSpecificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeService_DeleteJobSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteJobSpecificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).DeleteJobSpecification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/DeleteJobSpecification",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).DeleteJobSpecification(ctx, req.(*DeleteJobSpecificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeService_ListJobSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobSpecificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).ListJobSpecification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/ListJobSpecification",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).ListJobSpecification(ctx, req.(*ListJobSpecificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeService_DumpJobSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DumpJobSpecificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).DumpJobSpecification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/DumpJobSpecification",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).DumpJobSpecification(ctx, req.(*DumpJobSpecificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeService_CheckJobSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckJobSpecificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).CheckJobSpecification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/CheckJobSpecification",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).CheckJobSpecification(ctx, req.(*CheckJobSpecificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeService_CheckJobSpecifications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CheckJobSpecificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RuntimeServiceServer).CheckJobSpecifications(m, &runtimeServiceCheckJobSpecificationsServer{stream})
+}
+
+type RuntimeService_CheckJobSpecificationsServer interface {
+	Send(*CheckJobSpecificationsResponse) error
+	grpc.ServerStream
+}
+
+type runtimeServiceCheckJobSpecificationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *runtimeServiceCheckJobSpecificationsServer) Send(m *CheckJobSpecificationsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _RuntimeService_RegisterProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).RegisterProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/RegisterProject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).RegisterProject(ctx, req.(*RegisterProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeService_RegisterProjectNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterProjectNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).RegisterProjectNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/RegisterProjectNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).RegisterProjectNamespace(ctx, req.(*RegisterProjectNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeService_RegisterSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).RegisterSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/RegisterSecret",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).RegisterSecret(ctx, req.(*RegisterSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeService_ListProjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProjectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).ListProjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/ListProjects",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).ListProjects(ctx, req.(*ListProjectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeService_ListProjectNamespaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProjectNamespacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).ListProjectNamespaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/ListProjectNamespaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).ListProjectNamespaces(ctx, req.(*ListProjectNamespacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeService_RegisterInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).RegisterInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/RegisterInstance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).RegisterInstance(ctx, req.(*RegisterInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (UnimplementedRuntimeServiceServer) Version(context.Context, *VersionRequest) (*VersionResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
+func _RuntimeService_JobStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).JobStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/JobStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).JobStatus(ctx, req.(*JobStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) DeployJobSpecification(*DeployJobSpecificationRequest, RuntimeService_DeployJobSpecificationServer) error {
-	return status.Errorf(codes.Unimplemented, "method DeployJobSpecification not implemented")
+
+func _RuntimeService_RegisterJobEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterJobEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).RegisterJobEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/RegisterJobEvent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).RegisterJobEvent(ctx, req.(*RegisterJobEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) CreateJobSpecification(context.Context, *CreateJobSpecificationRequest) (*CreateJobSpecificationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateJobSpecification not implemented")
+
+func _RuntimeService_GetWindow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWindowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).GetWindow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/GetWindow",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).GetWindow(ctx, req.(*GetWindowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) ReadJobSpecification(context.Context, *ReadJobSpecificationRequest) (*ReadJobSpecificationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ReadJobSpecification not implemented")
+
+func _RuntimeService_DeployResourceSpecification_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DeployResourceSpecificationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RuntimeServiceServer).DeployResourceSpecification(m, &runtimeServiceDeployResourceSpecificationServer{stream})
 }
-func (UnimplementedRuntimeServiceServer) DeleteJobSpecification(context.Context, *DeleteJobSpecificationRequest) (*DeleteJobSpecificationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteJobSpecification not implemented")
+
+type RuntimeService_DeployResourceSpecificationServer interface {
+	Send(*DeployResourceSpecificationResponse) error
+	grpc.ServerStream
 }
-func (UnimplementedRuntimeServiceServer) ListJobSpecification(context.Context, *ListJobSpecificationRequest) (*ListJobSpecificationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListJobSpecification not implemented")
+
+type runtimeServiceDeployResourceSpecificationServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedRuntimeServiceServer) DumpJobSpecification(context.Context, *DumpJobSpecificationRequest) (*DumpJobSpecificationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DumpJobSpecification not implemented")
+
+func (x *runtimeServiceDeployResourceSpecificationServer) Send(m *DeployResourceSpecificationResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
-func (UnimplementedRuntimeServiceServer) CheckJobSpecification(context.Context, *CheckJobSpecificationRequest) (*CheckJobSpecificationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CheckJobSpecification not implemented")
+
+func _RuntimeService_ListResourceSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListResourceSpecificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).ListResourceSpecification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/ListResourceSpecification",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).ListResourceSpecification(ctx, req.(*ListResourceSpecificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) CheckJobSpecifications(*CheckJobSpecificationsRequest, RuntimeService_CheckJobSpecificationsServer) error {
-	return status.Errorf(codes.Unimplemented, "method CheckJobSpecifications not implemented")
+
+func _RuntimeService_CreateResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateResourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).CreateResource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/CreateResource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).CreateResource(ctx, req.(*CreateResourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) RegisterProject(context.Context, *RegisterProjectRequest) (*RegisterProjectResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RegisterProject not implemented")
+
+func _RuntimeService_ReadResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadResourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).ReadResource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/ReadResource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).ReadResource(ctx, req.(*ReadResourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) RegisterProjectNamespace(context.Context, *RegisterProjectNamespaceRequest) (*RegisterProjectNamespaceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RegisterProjectNamespace not implemented")
+
+func _RuntimeService_UpdateResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateResourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).UpdateResource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/UpdateResource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).UpdateResource(ctx, req.(*UpdateResourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) RegisterSecret(context.Context, *RegisterSecretRequest) (*RegisterSecretResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RegisterSecret not implemented")
+
+func _RuntimeService_ReplayDryRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).ReplayDryRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/ReplayDryRun",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).ReplayDryRun(ctx, req.(*ReplayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) ListProjects(context.Context, *ListProjectsRequest) (*ListProjectsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListProjects not implemented")
+
+func _RuntimeService_Replay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).Replay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/Replay",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).Replay(ctx, req.(*ReplayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeService_PreviewSchedulePropagation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreviewSchedulePropagationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).PreviewSchedulePropagation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/PreviewSchedulePropagation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).PreviewSchedulePropagation(ctx, req.(*PreviewSchedulePropagationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeService_ListActiveJobInstances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActiveJobInstancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).ListActiveJobInstances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/ListActiveJobInstances",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).ListActiveJobInstances(ctx, req.(*ListActiveJobInstancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RuntimeService_ListRunningJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRunningJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).ListRunningJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/ListRunningJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).ListRunningJobs(ctx, req.(*ListRunningJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) ListProjectNamespaces(context.Context, *ListProjectNamespacesRequest) (*ListProjectNamespacesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListProjectNamespaces not implemented")
+
+func _RuntimeService_NegotiateAPIVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NegotiateAPIVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).NegotiateAPIVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/NegotiateAPIVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).NegotiateAPIVersion(ctx, req.(*NegotiateAPIVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) RegisterInstance(context.Context, *RegisterInstanceRequest) (*RegisterInstanceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RegisterInstance not implemented")
+
+func _RuntimeService_SmartBackfillJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SmartBackfillJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).SmartBackfillJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/SmartBackfillJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).SmartBackfillJob(ctx, req.(*SmartBackfillJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) JobStatus(context.Context, *JobStatusRequest) (*JobStatusResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method JobStatus not implemented")
+
+func _RuntimeService_PinDependencyVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PinDependencyVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).PinDependencyVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/PinDependencyVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).PinDependencyVersion(ctx, req.(*PinDependencyVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) RegisterJobEvent(context.Context, *RegisterJobEventRequest) (*RegisterJobEventResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RegisterJobEvent not implemented")
+
+func _RuntimeService_UnpinDependency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnpinDependencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).UnpinDependency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/UnpinDependency",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).UnpinDependency(ctx, req.(*UnpinDependencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) GetWindow(context.Context, *GetWindowRequest) (*GetWindowResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetWindow not implemented")
+
+func _RuntimeService_ListDependencyPins_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDependencyPinsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).ListDependencyPins(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/ListDependencyPins",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).ListDependencyPins(ctx, req.(*ListDependencyPinsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) DeployResourceSpecification(*DeployResourceSpecificationRequest, RuntimeService_DeployResourceSpecificationServer) error {
-	return status.Errorf(codes.Unimplemented, "method DeployResourceSpecification not implemented")
+
+func _RuntimeService_GetProjectDiagnostics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProjectDiagnosticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).GetProjectDiagnostics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/GetProjectDiagnostics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).GetProjectDiagnostics(ctx, req.(*GetProjectDiagnosticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) ListResourceSpecification(context.Context, *ListResourceSpecificationRequest) (*ListResourceSpecificationResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListResourceSpecification not implemented")
+
+func _RuntimeService_TagRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TagReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).TagRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/TagRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).TagRelease(ctx, req.(*TagReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) CreateResource(context.Context, *CreateResourceRequest) (*CreateResourceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateResource not implemented")
+
+func _RuntimeService_RollbackToRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackToReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).RollbackToRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/RollbackToRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).RollbackToRelease(ctx, req.(*RollbackToReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) ReadResource(context.Context, *ReadResourceRequest) (*ReadResourceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ReadResource not implemented")
+
+func _RuntimeService_GetInstanceStatusSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInstanceStatusSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).GetInstanceStatusSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/GetInstanceStatusSummary",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).GetInstanceStatusSummary(ctx, req.(*GetInstanceStatusSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) UpdateResource(context.Context, *UpdateResourceRequest) (*UpdateResourceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateResource not implemented")
+
+func _RuntimeService_CheckJobSchemaDrift_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckJobSchemaDriftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).CheckJobSchemaDrift(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/CheckJobSchemaDrift",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).CheckJobSchemaDrift(ctx, req.(*CheckJobSchemaDriftRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) ReplayDryRun(context.Context, *ReplayRequest) (*ReplayDryRunResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ReplayDryRun not implemented")
+
+func _RuntimeService_GetReplayStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReplayStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).GetReplayStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/GetReplayStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).GetReplayStatus(ctx, req.(*GetReplayStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) Replay(context.Context, *ReplayRequest) (*ReplayResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Replay not implemented")
+
+func _RuntimeService_ListSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).ListSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/ListSecrets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).ListSecrets(ctx, req.(*ListSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedRuntimeServiceServer) mustEmbedUnimplementedRuntimeServiceServer() {}
 
-// UnsafeRuntimeServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to RuntimeServiceServer will
-// result in compilation errors.
-type UnsafeRuntimeServiceServer interface {
-	mustEmbedUnimplementedRuntimeServiceServer()
+func _RuntimeService_RevealProjectSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevealProjectSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).RevealProjectSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/RevealProjectSecret",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).RevealProjectSecret(ctx, req.(*RevealProjectSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterRuntimeServiceServer(s grpc.ServiceRegistrar, srv RuntimeServiceServer) {
-	s.RegisterService(&RuntimeService_ServiceDesc, srv)
+func _RuntimeService_DiffJobSpec_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffJobSpecRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).DiffJobSpec(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/DiffJobSpec",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).DiffJobSpec(ctx, req.(*DiffJobSpecRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(VersionRequest)
+func _RuntimeService_ListJobsByOwner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsByOwnerRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).Version(ctx, in)
+		return srv.(RuntimeServiceServer).ListJobsByOwner(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/Version",
+		FullMethod: "/odpf.optimus.RuntimeService/ListJobsByOwner",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).Version(ctx, req.(*VersionRequest))
+		return srv.(RuntimeServiceServer).ListJobsByOwner(ctx, req.(*ListJobsByOwnerRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_DeployJobSpecification_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(DeployJobSpecificationRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _RuntimeService_ReconcileJobInstances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReconcileJobInstancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(RuntimeServiceServer).DeployJobSpecification(m, &runtimeServiceDeployJobSpecificationServer{stream})
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).ReconcileJobInstances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/ReconcileJobInstances",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).ReconcileJobInstances(ctx, req.(*ReconcileJobInstancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type RuntimeService_DeployJobSpecificationServer interface {
-	Send(*DeployJobSpecificationResponse) error
-	grpc.ServerStream
+func _RuntimeService_UndeleteJobSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteJobSpecificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).UndeleteJobSpecification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/UndeleteJobSpecification",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).UndeleteJobSpecification(ctx, req.(*UndeleteJobSpecificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type runtimeServiceDeployJobSpecificationServer struct {
-	grpc.ServerStream
+func _RuntimeService_PurgeDeletedJobSpecifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeDeletedJobSpecificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).PurgeDeletedJobSpecifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/PurgeDeletedJobSpecifications",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).PurgeDeletedJobSpecifications(ctx, req.(*PurgeDeletedJobSpecificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *runtimeServiceDeployJobSpecificationServer) Send(m *DeployJobSpecificationResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _RuntimeService_CloneJobSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneJobSpecificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).CloneJobSpecification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/CloneJobSpecification",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).CloneJobSpecification(ctx, req.(*CloneJobSpecificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_CreateJobSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateJobSpecificationRequest)
+func _RuntimeService_CloneProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneProjectRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).CreateJobSpecification(ctx, in)
+		return srv.(RuntimeServiceServer).CloneProject(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/CreateJobSpecification",
+		FullMethod: "/odpf.optimus.RuntimeService/CloneProject",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).CreateJobSpecification(ctx, req.(*CreateJobSpecificationRequest))
+		return srv.(RuntimeServiceServer).CloneProject(ctx, req.(*CloneProjectRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_ReadJobSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ReadJobSpecificationRequest)
+func _RuntimeService_BatchCreateJobSpecifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateJobSpecificationsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).ReadJobSpecification(ctx, in)
+		return srv.(RuntimeServiceServer).BatchCreateJobSpecifications(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/ReadJobSpecification",
+		FullMethod: "/odpf.optimus.RuntimeService/BatchCreateJobSpecifications",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).ReadJobSpecification(ctx, req.(*ReadJobSpecificationRequest))
+		return srv.(RuntimeServiceServer).BatchCreateJobSpecifications(ctx, req.(*BatchCreateJobSpecificationsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_DeleteJobSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteJobSpecificationRequest)
+func _RuntimeService_RotateSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateSecretsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).DeleteJobSpecification(ctx, in)
+		return srv.(RuntimeServiceServer).RotateSecrets(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/DeleteJobSpecification",
+		FullMethod: "/odpf.optimus.RuntimeService/RotateSecrets",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).DeleteJobSpecification(ctx, req.(*DeleteJobSpecificationRequest))
+		return srv.(RuntimeServiceServer).RotateSecrets(ctx, req.(*RotateSecretsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_ListJobSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListJobSpecificationRequest)
+func _RuntimeService_ListSchedulerJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSchedulerJobsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).ListJobSpecification(ctx, in)
+		return srv.(RuntimeServiceServer).ListSchedulerJobs(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/ListJobSpecification",
+		FullMethod: "/odpf.optimus.RuntimeService/ListSchedulerJobs",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).ListJobSpecification(ctx, req.(*ListJobSpecificationRequest))
+		return srv.(RuntimeServiceServer).ListSchedulerJobs(ctx, req.(*ListSchedulerJobsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_DumpJobSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DumpJobSpecificationRequest)
+func _RuntimeService_GrantProjectRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantProjectRoleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).DumpJobSpecification(ctx, in)
+		return srv.(RuntimeServiceServer).GrantProjectRole(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/DumpJobSpecification",
+		FullMethod: "/odpf.optimus.RuntimeService/GrantProjectRole",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).DumpJobSpecification(ctx, req.(*DumpJobSpecificationRequest))
+		return srv.(RuntimeServiceServer).GrantProjectRole(ctx, req.(*GrantProjectRoleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_CheckJobSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CheckJobSpecificationRequest)
+func _RuntimeService_RevokeProjectRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeProjectRoleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).CheckJobSpecification(ctx, in)
+		return srv.(RuntimeServiceServer).RevokeProjectRole(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/CheckJobSpecification",
+		FullMethod: "/odpf.optimus.RuntimeService/RevokeProjectRole",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).CheckJobSpecification(ctx, req.(*CheckJobSpecificationRequest))
+		return srv.(RuntimeServiceServer).RevokeProjectRole(ctx, req.(*RevokeProjectRoleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_CheckJobSpecifications_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(CheckJobSpecificationsRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _RuntimeService_ListProjectRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProjectRolesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(RuntimeServiceServer).CheckJobSpecifications(m, &runtimeServiceCheckJobSpecificationsServer{stream})
-}
-
-type RuntimeService_CheckJobSpecificationsServer interface {
-	Send(*CheckJobSpecificationsResponse) error
-	grpc.ServerStream
-}
-
-type runtimeServiceCheckJobSpecificationsServer struct {
-	grpc.ServerStream
-}
-
-func (x *runtimeServiceCheckJobSpecificationsServer) Send(m *CheckJobSpecificationsResponse) error {
-	return x.ServerStream.SendMsg(m)
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).ListProjectRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/ListProjectRoles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).ListProjectRoles(ctx, req.(*ListProjectRolesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_RegisterProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RegisterProjectRequest)
+func _RuntimeService_GenerateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateAPIKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).RegisterProject(ctx, in)
+		return srv.(RuntimeServiceServer).GenerateAPIKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/RegisterProject",
+		FullMethod: "/odpf.optimus.RuntimeService/GenerateAPIKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).RegisterProject(ctx, req.(*RegisterProjectRequest))
+		return srv.(RuntimeServiceServer).GenerateAPIKey(ctx, req.(*GenerateAPIKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_RegisterProjectNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RegisterProjectNamespaceRequest)
+func _RuntimeService_RegisterProjectVariables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterProjectVariablesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).RegisterProjectNamespace(ctx, in)
+		return srv.(RuntimeServiceServer).RegisterProjectVariables(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/RegisterProjectNamespace",
+		FullMethod: "/odpf.optimus.RuntimeService/RegisterProjectVariables",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).RegisterProjectNamespace(ctx, req.(*RegisterProjectNamespaceRequest))
+		return srv.(RuntimeServiceServer).RegisterProjectVariables(ctx, req.(*RegisterProjectVariablesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_RegisterSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RegisterSecretRequest)
+func _RuntimeService_GetProjectVariables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProjectVariablesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).RegisterSecret(ctx, in)
+		return srv.(RuntimeServiceServer).GetProjectVariables(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/RegisterSecret",
+		FullMethod: "/odpf.optimus.RuntimeService/GetProjectVariables",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).RegisterSecret(ctx, req.(*RegisterSecretRequest))
+		return srv.(RuntimeServiceServer).GetProjectVariables(ctx, req.(*GetProjectVariablesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_ListProjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListProjectsRequest)
+func _RuntimeService_SetProjectQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetProjectQuotaRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).ListProjects(ctx, in)
+		return srv.(RuntimeServiceServer).SetProjectQuota(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/ListProjects",
+		FullMethod: "/odpf.optimus.RuntimeService/SetProjectQuota",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).ListProjects(ctx, req.(*ListProjectsRequest))
+		return srv.(RuntimeServiceServer).SetProjectQuota(ctx, req.(*SetProjectQuotaRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_ListProjectNamespaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListProjectNamespacesRequest)
+func _RuntimeService_ListAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditLogRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).ListProjectNamespaces(ctx, in)
+		return srv.(RuntimeServiceServer).ListAuditLog(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/ListProjectNamespaces",
+		FullMethod: "/odpf.optimus.RuntimeService/ListAuditLog",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).ListProjectNamespaces(ctx, req.(*ListProjectNamespacesRequest))
+		return srv.(RuntimeServiceServer).ListAuditLog(ctx, req.(*ListAuditLogRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_RegisterInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RegisterInstanceRequest)
+func _RuntimeService_EstimateJobCost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateJobCostRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).RegisterInstance(ctx, in)
+		return srv.(RuntimeServiceServer).EstimateJobCost(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/RegisterInstance",
+		FullMethod: "/odpf.optimus.RuntimeService/EstimateJobCost",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).RegisterInstance(ctx, req.(*RegisterInstanceRequest))
+		return srv.(RuntimeServiceServer).EstimateJobCost(ctx, req.(*EstimateJobCostRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_JobStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(JobStatusRequest)
+func _RuntimeService_ListSLABreaches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSLABreachesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).JobStatus(ctx, in)
+		return srv.(RuntimeServiceServer).ListSLABreaches(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/JobStatus",
+		FullMethod: "/odpf.optimus.RuntimeService/ListSLABreaches",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).JobStatus(ctx, req.(*JobStatusRequest))
+		return srv.(RuntimeServiceServer).ListSLABreaches(ctx, req.(*ListSLABreachesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_RegisterJobEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RegisterJobEventRequest)
+func _RuntimeService_ExplainJobDependencies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExplainJobDependenciesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).RegisterJobEvent(ctx, in)
+		return srv.(RuntimeServiceServer).ExplainJobDependencies(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/RegisterJobEvent",
+		FullMethod: "/odpf.optimus.RuntimeService/ExplainJobDependencies",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).RegisterJobEvent(ctx, req.(*RegisterJobEventRequest))
+		return srv.(RuntimeServiceServer).ExplainJobDependencies(ctx, req.(*ExplainJobDependenciesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_GetWindow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetWindowRequest)
+func _RuntimeService_ValidateSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateScheduleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).GetWindow(ctx, in)
+		return srv.(RuntimeServiceServer).ValidateSchedule(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/GetWindow",
+		FullMethod: "/odpf.optimus.RuntimeService/ValidateSchedule",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).GetWindow(ctx, req.(*GetWindowRequest))
+		return srv.(RuntimeServiceServer).ValidateSchedule(ctx, req.(*ValidateScheduleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_DeployResourceSpecification_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(DeployResourceSpecificationRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _RuntimeService_ListSuppressedJobRuns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSuppressedJobRunsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(RuntimeServiceServer).DeployResourceSpecification(m, &runtimeServiceDeployResourceSpecificationServer{stream})
-}
-
-type RuntimeService_DeployResourceSpecificationServer interface {
-	Send(*DeployResourceSpecificationResponse) error
-	grpc.ServerStream
-}
-
-type runtimeServiceDeployResourceSpecificationServer struct {
-	grpc.ServerStream
-}
-
-func (x *runtimeServiceDeployResourceSpecificationServer) Send(m *DeployResourceSpecificationResponse) error {
-	return x.ServerStream.SendMsg(m)
+	if interceptor == nil {
+		return srv.(RuntimeServiceServer).ListSuppressedJobRuns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odpf.optimus.RuntimeService/ListSuppressedJobRuns",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServiceServer).ListSuppressedJobRuns(ctx, req.(*ListSuppressedJobRunsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_ListResourceSpecification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListResourceSpecificationRequest)
+func _RuntimeService_TriggerJobRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerJobRunRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).ListResourceSpecification(ctx, in)
+		return srv.(RuntimeServiceServer).TriggerJobRun(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/ListResourceSpecification",
+		FullMethod: "/odpf.optimus.RuntimeService/TriggerJobRun",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).ListResourceSpecification(ctx, req.(*ListResourceSpecificationRequest))
+		return srv.(RuntimeServiceServer).TriggerJobRun(ctx, req.(*TriggerJobRunRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_CreateResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateResourceRequest)
+func _RuntimeService_PauseJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseJobRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).CreateResource(ctx, in)
+		return srv.(RuntimeServiceServer).PauseJob(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/CreateResource",
+		FullMethod: "/odpf.optimus.RuntimeService/PauseJob",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).CreateResource(ctx, req.(*CreateResourceRequest))
+		return srv.(RuntimeServiceServer).PauseJob(ctx, req.(*PauseJobRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_ReadResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ReadResourceRequest)
+func _RuntimeService_ResumeJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeJobRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).ReadResource(ctx, in)
+		return srv.(RuntimeServiceServer).ResumeJob(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/ReadResource",
+		FullMethod: "/odpf.optimus.RuntimeService/ResumeJob",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).ReadResource(ctx, req.(*ReadResourceRequest))
+		return srv.(RuntimeServiceServer).ResumeJob(ctx, req.(*ResumeJobRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_UpdateResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateResourceRequest)
+func _RuntimeService_PauseProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseProjectRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).UpdateResource(ctx, in)
+		return srv.(RuntimeServiceServer).PauseProject(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/UpdateResource",
+		FullMethod: "/odpf.optimus.RuntimeService/PauseProject",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).UpdateResource(ctx, req.(*UpdateResourceRequest))
+		return srv.(RuntimeServiceServer).PauseProject(ctx, req.(*PauseProjectRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_ReplayDryRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ReplayRequest)
+func _RuntimeService_ResumeProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeProjectRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).ReplayDryRun(ctx, in)
+		return srv.(RuntimeServiceServer).ResumeProject(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/ReplayDryRun",
+		FullMethod: "/odpf.optimus.RuntimeService/ResumeProject",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).ReplayDryRun(ctx, req.(*ReplayRequest))
+		return srv.(RuntimeServiceServer).ResumeProject(ctx, req.(*ResumeProjectRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _RuntimeService_Replay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ReplayRequest)
+func _RuntimeService_GetDependencyGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDependencyGraphRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RuntimeServiceServer).Replay(ctx, in)
+		return srv.(RuntimeServiceServer).GetDependencyGraph(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/odpf.optimus.RuntimeService/Replay",
+		FullMethod: "/odpf.optimus.RuntimeService/GetDependencyGraph",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RuntimeServiceServer).Replay(ctx, req.(*ReplayRequest))
+		return srv.(RuntimeServiceServer).GetDependencyGraph(ctx, req.(*GetDependencyGraphRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -1083,6 +2673,186 @@ var RuntimeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Replay",
 			Handler:    _RuntimeService_Replay_Handler,
 		},
+		{
+			MethodName: "PreviewSchedulePropagation",
+			Handler:    _RuntimeService_PreviewSchedulePropagation_Handler,
+		},
+		{
+			MethodName: "ListActiveJobInstances",
+			Handler:    _RuntimeService_ListActiveJobInstances_Handler,
+		},
+		{
+			MethodName: "ListRunningJobs",
+			Handler:    _RuntimeService_ListRunningJobs_Handler,
+		},
+		{
+			MethodName: "NegotiateAPIVersion",
+			Handler:    _RuntimeService_NegotiateAPIVersion_Handler,
+		},
+		{
+			MethodName: "SmartBackfillJob",
+			Handler:    _RuntimeService_SmartBackfillJob_Handler,
+		},
+		{
+			MethodName: "PinDependencyVersion",
+			Handler:    _RuntimeService_PinDependencyVersion_Handler,
+		},
+		{
+			MethodName: "UnpinDependency",
+			Handler:    _RuntimeService_UnpinDependency_Handler,
+		},
+		{
+			MethodName: "ListDependencyPins",
+			Handler:    _RuntimeService_ListDependencyPins_Handler,
+		},
+		{
+			MethodName: "GetProjectDiagnostics",
+			Handler:    _RuntimeService_GetProjectDiagnostics_Handler,
+		},
+		{
+			MethodName: "TagRelease",
+			Handler:    _RuntimeService_TagRelease_Handler,
+		},
+		{
+			MethodName: "RollbackToRelease",
+			Handler:    _RuntimeService_RollbackToRelease_Handler,
+		},
+		{
+			MethodName: "GetInstanceStatusSummary",
+			Handler:    _RuntimeService_GetInstanceStatusSummary_Handler,
+		},
+		{
+			MethodName: "CheckJobSchemaDrift",
+			Handler:    _RuntimeService_CheckJobSchemaDrift_Handler,
+		},
+		{
+			MethodName: "GetReplayStatus",
+			Handler:    _RuntimeService_GetReplayStatus_Handler,
+		},
+		{
+			MethodName: "ListSecrets",
+			Handler:    _RuntimeService_ListSecrets_Handler,
+		},
+		{
+			MethodName: "RevealProjectSecret",
+			Handler:    _RuntimeService_RevealProjectSecret_Handler,
+		},
+		{
+			MethodName: "DiffJobSpec",
+			Handler:    _RuntimeService_DiffJobSpec_Handler,
+		},
+		{
+			MethodName: "ListJobsByOwner",
+			Handler:    _RuntimeService_ListJobsByOwner_Handler,
+		},
+		{
+			MethodName: "ReconcileJobInstances",
+			Handler:    _RuntimeService_ReconcileJobInstances_Handler,
+		},
+		{
+			MethodName: "UndeleteJobSpecification",
+			Handler:    _RuntimeService_UndeleteJobSpecification_Handler,
+		},
+		{
+			MethodName: "PurgeDeletedJobSpecifications",
+			Handler:    _RuntimeService_PurgeDeletedJobSpecifications_Handler,
+		},
+		{
+			MethodName: "CloneJobSpecification",
+			Handler:    _RuntimeService_CloneJobSpecification_Handler,
+		},
+		{
+			MethodName: "CloneProject",
+			Handler:    _RuntimeService_CloneProject_Handler,
+		},
+		{
+			MethodName: "BatchCreateJobSpecifications",
+			Handler:    _RuntimeService_BatchCreateJobSpecifications_Handler,
+		},
+		{
+			MethodName: "RotateSecrets",
+			Handler:    _RuntimeService_RotateSecrets_Handler,
+		},
+		{
+			MethodName: "ListSchedulerJobs",
+			Handler:    _RuntimeService_ListSchedulerJobs_Handler,
+		},
+		{
+			MethodName: "GrantProjectRole",
+			Handler:    _RuntimeService_GrantProjectRole_Handler,
+		},
+		{
+			MethodName: "RevokeProjectRole",
+			Handler:    _RuntimeService_RevokeProjectRole_Handler,
+		},
+		{
+			MethodName: "ListProjectRoles",
+			Handler:    _RuntimeService_ListProjectRoles_Handler,
+		},
+		{
+			MethodName: "GenerateAPIKey",
+			Handler:    _RuntimeService_GenerateAPIKey_Handler,
+		},
+		{
+			MethodName: "RegisterProjectVariables",
+			Handler:    _RuntimeService_RegisterProjectVariables_Handler,
+		},
+		{
+			MethodName: "GetProjectVariables",
+			Handler:    _RuntimeService_GetProjectVariables_Handler,
+		},
+		{
+			MethodName: "SetProjectQuota",
+			Handler:    _RuntimeService_SetProjectQuota_Handler,
+		},
+		{
+			MethodName: "ListAuditLog",
+			Handler:    _RuntimeService_ListAuditLog_Handler,
+		},
+		{
+			MethodName: "EstimateJobCost",
+			Handler:    _RuntimeService_EstimateJobCost_Handler,
+		},
+		{
+			MethodName: "ListSLABreaches",
+			Handler:    _RuntimeService_ListSLABreaches_Handler,
+		},
+		{
+			MethodName: "ExplainJobDependencies",
+			Handler:    _RuntimeService_ExplainJobDependencies_Handler,
+		},
+		{
+			MethodName: "ValidateSchedule",
+			Handler:    _RuntimeService_ValidateSchedule_Handler,
+		},
+		{
+			MethodName: "ListSuppressedJobRuns",
+			Handler:    _RuntimeService_ListSuppressedJobRuns_Handler,
+		},
+		{
+			MethodName: "TriggerJobRun",
+			Handler:    _RuntimeService_TriggerJobRun_Handler,
+		},
+		{
+			MethodName: "PauseJob",
+			Handler:    _RuntimeService_PauseJob_Handler,
+		},
+		{
+			MethodName: "ResumeJob",
+			Handler:    _RuntimeService_ResumeJob_Handler,
+		},
+		{
+			MethodName: "PauseProject",
+			Handler:    _RuntimeService_PauseProject_Handler,
+		},
+		{
+			MethodName: "ResumeProject",
+			Handler:    _RuntimeService_ResumeProject_Handler,
+		},
+		{
+			MethodName: "GetDependencyGraph",
+			Handler:    _RuntimeService_GetDependencyGraph_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
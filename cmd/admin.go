@@ -1,18 +1,21 @@
 package cmd
 
 import (
+	"github.com/odpf/optimus/config"
 	"github.com/odpf/optimus/models"
 	cli "github.com/spf13/cobra"
 )
 
 // adminCommand requests a resource from optimus
-func adminCommand(l logger, pluginRepo models.PluginRepository) *cli.Command {
+func adminCommand(l logger, conf config.Provider, pluginRepo models.PluginRepository) *cli.Command {
 	cmd := &cli.Command{
 		Use:   "admin",
 		Short: "administration commands, should not be used by user",
 	}
 	cmd.AddCommand(adminBuildCommand(l))
+	cmd.AddCommand(adminRunCommand(l, conf.GetExecutor()))
 	cmd.AddCommand(adminGetCommand(l, pluginRepo))
+	cmd.AddCommand(adminRotateCommand(l))
 	return cmd
 }
 
@@ -26,6 +29,16 @@ func adminBuildCommand(l logger) *cli.Command {
 	return cmd
 }
 
+// adminRunCommand executes a resource
+func adminRunCommand(l logger, executorConf config.ExecutorConfig) *cli.Command {
+	cmd := &cli.Command{
+		Use:   "run",
+		Short: "Register a job run and execute it on an external task server",
+	}
+	cmd.AddCommand(adminRunInstanceCommand(l, executorConf))
+	return cmd
+}
+
 // adminGetCommand gets a resource
 func adminGetCommand(l logger, pluginRepo models.PluginRepository) *cli.Command {
 	cmd := &cli.Command{
@@ -34,3 +47,12 @@ func adminGetCommand(l logger, pluginRepo models.PluginRepository) *cli.Command
 	cmd.AddCommand(adminGetStatusCommand(l))
 	return cmd
 }
+
+// adminRotateCommand rotates a resource
+func adminRotateCommand(l logger) *cli.Command {
+	cmd := &cli.Command{
+		Use: "rotate",
+	}
+	cmd.AddCommand(adminRotateSecretsCommand(l))
+	return cmd
+}
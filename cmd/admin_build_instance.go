@@ -69,13 +69,12 @@ func adminBuildInstanceCommand(l logger) *cli.Command {
 	return cmd
 }
 
-// getInstanceBuildRequest fetches a JobRun from the store (eg, postgres)
-// Based on the response, it builds assets like query, env and config
-// for the Job Run which is saved into output files.
-func getInstanceBuildRequest(l logger, jobName, inputDirectory, host, projectName, scheduledAt, runType, runName string) (err error) {
+// registerInstance registers a JobRun of the job scheduled at scheduledAt
+// with the optimus service and returns its compiled assets
+func registerInstance(l logger, jobName, host, projectName, scheduledAt, runType, runName string) (*pb.RegisterInstanceResponse, error) {
 	jobScheduledTime, err := time.Parse(models.InstanceScheduledAtTimeLayout, scheduledAt)
 	if err != nil {
-		return errors.Wrapf(err, "invalid time format, please use %s", models.InstanceScheduledAtTimeLayout)
+		return nil, errors.Wrapf(err, "invalid time format, please use %s", models.InstanceScheduledAtTimeLayout)
 	}
 	jobScheduledTimeProto := timestamppb.New(jobScheduledTime)
 
@@ -87,7 +86,7 @@ func getInstanceBuildRequest(l logger, jobName, inputDirectory, host, projectNam
 		if errors.Is(err, context.DeadlineExceeded) {
 			l.Println("can't reach optimus service, timing out")
 		}
-		return err
+		return nil, err
 	}
 	defer conn.Close()
 
@@ -104,7 +103,18 @@ func getInstanceBuildRequest(l logger, jobName, inputDirectory, host, projectNam
 		InstanceName: runName,
 	})
 	if err != nil {
-		return errors.Wrapf(err, "request failed for job %s", jobName)
+		return nil, errors.Wrapf(err, "request failed for job %s", jobName)
+	}
+	return jobResponse, nil
+}
+
+// getInstanceBuildRequest fetches a JobRun from the store (eg, postgres)
+// Based on the response, it builds assets like query, env and config
+// for the Job Run which is saved into output files.
+func getInstanceBuildRequest(l logger, jobName, inputDirectory, host, projectName, scheduledAt, runType, runName string) (err error) {
+	jobResponse, err := registerInstance(l, jobName, host, projectName, scheduledAt, runType, runName)
+	if err != nil {
+		return err
 	}
 
 	// make sure output dir exists
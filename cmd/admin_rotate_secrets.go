@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+const (
+	adminRotateSecretsTimeout = time.Minute * 5
+)
+
+func adminRotateSecretsCommand(l logger) *cli.Command {
+	var (
+		optimusHost string
+		oldAppKey   string
+		newAppKey   string
+	)
+	cmd := &cli.Command{
+		Use:   "secrets",
+		Short: "Re-encrypt secrets of all projects with a new app key",
+		Long: `Re-encrypt secrets of all projects with a new app key in a single transaction.
+The server should be running with the new key as serve.app_key and the old one as
+serve.previous_app_key, previous_app_key can be removed once the rotation succeeds`,
+		Example: `optimus admin rotate secrets --host localhost:9100 --old-key <old-key> --new-key <new-key>`,
+	}
+	cmd.Flags().StringVar(&optimusHost, "host", "", "optimus service endpoint url")
+	cmd.MarkFlagRequired("host")
+	cmd.Flags().StringVar(&oldAppKey, "old-key", "", "app key the secrets are encrypted with")
+	cmd.MarkFlagRequired("old-key")
+	cmd.Flags().StringVar(&newAppKey, "new-key", "", "app key to encrypt the secrets with")
+	cmd.MarkFlagRequired("new-key")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		l.Printf("rotating secrets at %s\nplease wait...\n", optimusHost)
+		if err := rotateSecretsRequest(l, optimusHost, oldAppKey, newAppKey); err != nil {
+			return err
+		}
+		l.Println("secrets rotated successfully, previous_app_key can be removed from the server config")
+		return nil
+	}
+	return cmd
+}
+
+func rotateSecretsRequest(l logger, host, oldAppKey, newAppKey string) error {
+	var err error
+	dialTimeoutCtx, dialCancel := context.WithTimeout(context.Background(), OptimusDialTimeout)
+	defer dialCancel()
+
+	var conn *grpc.ClientConn
+	if conn, err = createConnection(dialTimeoutCtx, host); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			l.Println("can't reach optimus service, timing out")
+		}
+		return err
+	}
+	defer conn.Close()
+
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), adminRotateSecretsTimeout)
+	defer cancel()
+
+	runtime := pb.NewRuntimeServiceClient(conn)
+	if _, err := runtime.RotateSecrets(timeoutCtx, &pb.RotateSecretsRequest{
+		OldAppKey: oldAppKey,
+		NewAppKey: newAppKey,
+	}); err != nil {
+		return errors.Wrap(err, "request failed to rotate secrets")
+	}
+	return nil
+}
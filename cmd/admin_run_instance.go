@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+	"google.golang.org/grpc"
+
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/core/progress"
+	executor "github.com/odpf/optimus/ext/executor/grpc"
+	"github.com/odpf/optimus/models"
+)
+
+func adminRunInstanceCommand(l logger, executorConf config.ExecutorConfig) *cli.Command {
+	var (
+		optimusHost string
+		projectName string
+		scheduledAt string
+		runType     string
+		runName     string
+	)
+	cmd := &cli.Command{
+		Use:     "instance",
+		Short:   "Executes a Job instance on the external server of its task",
+		Example: "optimus admin run instance sample_replace --project \"project-id\" --name bq2bq",
+		Args:    cli.MinimumNArgs(1),
+	}
+
+	cmd.Flags().StringVar(&scheduledAt, "scheduled-at", "", "time at which the job was scheduled for execution")
+	cmd.MarkFlagRequired("scheduled-at")
+	cmd.Flags().StringVar(&runType, "type", "task", "type of task, could be task/hook")
+	cmd.Flags().StringVar(&runName, "name", "", "name of task, could be bq2bq/transporter/predator")
+	cmd.MarkFlagRequired("name")
+
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&optimusHost, "host", "", "optimus service endpoint url")
+	cmd.MarkFlagRequired("host")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		jobName := args[0]
+		l.Printf("requesting resources for project %s, job %s at %s\nplease wait...\n", projectName, jobName, optimusHost)
+		l.Printf("run name %s, run type %s, scheduled at %s\n", runName, runType, scheduledAt)
+
+		jobResponse, err := registerInstance(l, jobName, optimusHost, projectName, scheduledAt, runType, runName)
+		if err != nil {
+			return err
+		}
+		// already validated while registering
+		jobScheduledTime, _ := time.Parse(models.InstanceScheduledAtTimeLayout, scheduledAt)
+
+		servers := map[string]executor.TaskServer{}
+		for _, task := range executorConf.Tasks {
+			servers[task.Name] = executor.TaskServer{
+				Address: task.Address,
+				Command: task.Command,
+			}
+		}
+		creds, err := transportCredentials()
+		if err != nil {
+			return err
+		}
+		taskExecutor := executor.NewExecutor(servers, hclog.New(&hclog.LoggerOptions{
+			Name:   "optimus",
+			Output: l.Writer(),
+		}), grpc.WithTransportCredentials(creds))
+		defer taskExecutor.Close()
+
+		l.Printf("executing %s on its task server\n", runName)
+		if err := taskExecutor.Execute(context.Background(), executor.Task{
+			ProjectName: projectName,
+			JobName:     jobName,
+			Name:        runName,
+			ScheduledAt: jobScheduledTime,
+			Assets:      jobResponse.Context.Files,
+			Config:      jobResponse.Context.Envs,
+		}, &taskLogObserver{l: l}); err != nil {
+			return errors.Wrapf(err, "failed to run job %s", jobName)
+		}
+		l.Println("job run finished")
+		return nil
+	}
+	return cmd
+}
+
+// taskLogObserver prints the lines logged by a task executed on its
+// task server
+type taskLogObserver struct {
+	l logger
+}
+
+func (o *taskLogObserver) Notify(evt progress.Event) {
+	o.l.Println(evt.String())
+}
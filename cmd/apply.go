@@ -0,0 +1,543 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store/local"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	applyTimeout = time.Minute * 10
+)
+
+const (
+	applyProjectFileName = "project.yaml"
+	applyJobsDirName     = "jobs"
+
+	applyActionCreate    = "create"
+	applyActionUpdate    = "update"
+	applyActionDelete    = "delete"
+	applyActionUnchanged = "unchanged"
+	applyActionUnmanaged = "unmanaged"
+)
+
+// applyFile is the yaml representation of a project managed declaratively,
+// it is either read from a single file or from a directory with the file as
+// applyProjectFileName and a file per job in applyJobsDirName
+type applyFile struct {
+	Project struct {
+		Name         string            `yaml:"name"`
+		Config       map[string]string `yaml:"config"`
+		FeatureFlags map[string]bool   `yaml:"feature_flags"`
+		// Variables are left as they are on the server if not set
+		Variables map[string]string `yaml:"variables,omitempty"`
+		Webhooks  []config.Webhook  `yaml:"webhooks,omitempty"`
+		// BlackoutWindows skip scheduled runs of jobs, e.g. during maintenance
+		BlackoutWindows []config.BlackoutWindow `yaml:"blackout_windows,omitempty"`
+	} `yaml:"project"`
+	Namespace struct {
+		Name   string            `yaml:"name"`
+		Config map[string]string `yaml:"config"`
+	} `yaml:"namespace"`
+	Secrets []struct {
+		Name  string `yaml:"name"`
+		Value string `yaml:"value"`
+		// TTL expires the secret after the duration, e.g. 720h
+		TTL time.Duration `yaml:"ttl"`
+	} `yaml:"secrets,omitempty"`
+	Jobs []local.Job `yaml:"jobs,omitempty"`
+}
+
+type applyChange struct {
+	Kind   string
+	Name   string
+	Action string
+}
+
+// applyPlan lists the changes required to bring the server state in line
+// with the apply file
+type applyPlan struct {
+	changes []applyChange
+
+	registerProject   bool
+	registerVariables bool
+	deployJobs        bool
+	// jobs sent to the server on deploy, jobs left out are deleted
+	jobsToDeploy []*pb.JobSpecification
+	// jobs soft deleted after the deployment, they can be restored with undelete
+	jobsToDelete []string
+	// commit the file is applied from, if it is in a git repository
+	git *pb.GitContext
+}
+
+// applyCommand declaratively manages a project, its secrets and jobs from a single file
+func applyCommand(l logger, conf config.Provider, pluginRepo models.PluginRepository, datastoreRepo models.DatastoreRepo) *cli.Command {
+	var (
+		filePath string
+		prune    bool
+		confirm  bool
+	)
+	cmd := &cli.Command{
+		Use:     "apply",
+		Short:   "Apply project configuration, secrets and jobs from a file",
+		Example: "optimus apply --file=project.yaml --prune --confirm",
+		Long: `
+Compares the project, namespace, secrets and jobs described in the file with
+the state on the server and prints the changes. Changes are only applied when
+--confirm is provided. Jobs on the server missing in the file are left as is
+unless --prune is provided, pruned jobs are soft deleted and can be restored
+with undelete. Secret values can't be read back from the server so secrets in
+the file are always registered.
+		`,
+	}
+	cmd.Flags().StringVarP(&filePath, "file", "f", "", "path of the project configuration file or of a directory exported with optimus export")
+	cmd.MarkFlagRequired("file")
+	cmd.Flags().BoolVar(&prune, "prune", false, "delete jobs on the server that are not in the file")
+	cmd.Flags().BoolVar(&confirm, "confirm", false, "apply the changes, without it only the diff is printed")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		file, err := readApplyFile(filePath)
+		if err != nil {
+			return err
+		}
+		if file.Project.Name == "" || file.Namespace.Name == "" {
+			return errors.New("project and namespace names are required")
+		}
+
+		adapt := v1handler.NewAdapter(pluginRepo, datastoreRepo)
+		jobAdapter := local.NewJobSpecAdapter(pluginRepo)
+		var fileJobs []*pb.JobSpecification
+		for _, localJob := range file.Jobs {
+			jobSpec, err := jobAdapter.ToSpec(localJob)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read job %s", localJob.Name)
+			}
+			adaptedJob, err := adapt.ToJobProto(jobSpec)
+			if err != nil {
+				return errors.Wrapf(err, "failed to serialize: %s", jobSpec.Name)
+			}
+			fileJobs = append(fileJobs, adaptedJob)
+		}
+
+		dialTimeoutCtx, dialCancel := context.WithTimeout(context.Background(), OptimusDialTimeout)
+		defer dialCancel()
+		conn, err := createConnection(dialTimeoutCtx, conf.GetHost())
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				l.Println("can't reach optimus service")
+			}
+			return err
+		}
+		defer conn.Close()
+
+		applyTimeoutCtx, applyCancel := context.WithTimeout(context.Background(), applyTimeout)
+		defer applyCancel()
+		runtime := pb.NewRuntimeServiceClient(conn)
+
+		plan, err := planApply(applyTimeoutCtx, runtime, file, fileJobs, prune)
+		if err != nil {
+			return err
+		}
+		plan.git = readGitContext(filePath)
+		printApplyPlan(l.Writer(), plan)
+
+		if !confirm {
+			l.Println(coloredNotice("no changes applied, run again with --confirm to apply"))
+			return nil
+		}
+		if err := executeApplyPlan(applyTimeoutCtx, l, runtime, file, plan); err != nil {
+			return err
+		}
+		l.Println(coloredSuccess("apply completed successfully"))
+		return nil
+	}
+	return cmd
+}
+
+// readApplyFile reads the file at path, or the project file and the job
+// files of the directory at path
+func readApplyFile(path string) (applyFile, error) {
+	var file applyFile
+	info, err := os.Stat(path)
+	if err != nil {
+		return file, errors.Wrapf(err, "failed to read %s", path)
+	}
+	projectFilePath := path
+	if info.IsDir() {
+		projectFilePath = filepath.Join(path, applyProjectFileName)
+	}
+	if err := readYAMLFile(projectFilePath, &file); err != nil {
+		return file, err
+	}
+	if !info.IsDir() {
+		return file, nil
+	}
+
+	jobFilePaths, err := filepath.Glob(filepath.Join(path, applyJobsDirName, "*.yaml"))
+	if err != nil {
+		return file, err
+	}
+	sort.Strings(jobFilePaths)
+	for _, jobFilePath := range jobFilePaths {
+		var localJob local.Job
+		if err := readYAMLFile(jobFilePath, &localJob); err != nil {
+			return file, err
+		}
+		file.Jobs = append(file.Jobs, localJob)
+	}
+	return file, nil
+}
+
+func readYAMLFile(path string, out interface{}) error {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", path)
+	}
+	if err := yaml.Unmarshal(raw, out); err != nil {
+		return errors.Wrapf(err, "failed to parse %s", path)
+	}
+	return nil
+}
+
+// planApply diffs the apply file against the current state of the server
+func planApply(ctx context.Context, runtime pb.RuntimeServiceClient, file applyFile, fileJobs []*pb.JobSpecification,
+	prune bool) (applyPlan, error) {
+	plan := applyPlan{}
+
+	projectsResponse, err := runtime.ListProjects(ctx, &pb.ListProjectsRequest{})
+	if err != nil {
+		return plan, errors.Wrap(err, "failed to list projects")
+	}
+	projectAction := applyActionCreate
+	for _, project := range projectsResponse.GetProjects() {
+		if project.GetName() == file.Project.Name {
+			projectAction = configAction(project.GetConfig(), file.Project.Config)
+			if projectAction == applyActionUnchanged && !featureFlagsEqual(project.GetFeatureFlags(), file.Project.FeatureFlags) {
+				projectAction = applyActionUpdate
+			}
+			if projectAction == applyActionUnchanged && !webhooksEqual(project.GetWebhooks(), toProjectWebhooksProto(file.Project.Webhooks)) {
+				projectAction = applyActionUpdate
+			}
+			if projectAction == applyActionUnchanged && !blackoutWindowsEqual(project.GetBlackoutWindows(),
+				toProjectBlackoutWindowsProto(file.Project.BlackoutWindows)) {
+				projectAction = applyActionUpdate
+			}
+		}
+	}
+	plan.changes = append(plan.changes, applyChange{Kind: "project", Name: file.Project.Name, Action: projectAction})
+
+	if file.Project.Variables != nil {
+		variablesAction := applyActionCreate
+		if projectAction != applyActionCreate {
+			variablesResponse, err := runtime.GetProjectVariables(ctx, &pb.GetProjectVariablesRequest{
+				ProjectName: file.Project.Name,
+			})
+			if err != nil {
+				return plan, errors.Wrap(err, "failed to read project variables")
+			}
+			variablesAction = configAction(variablesResponse.GetVariables(), file.Project.Variables)
+		}
+		plan.registerVariables = variablesAction != applyActionUnchanged
+		plan.changes = append(plan.changes, applyChange{Kind: "variables", Name: file.Project.Name, Action: variablesAction})
+	}
+
+	namespaceAction := applyActionCreate
+	var serverJobs []*pb.JobSpecification
+	if projectAction != applyActionCreate {
+		namespacesResponse, err := runtime.ListProjectNamespaces(ctx, &pb.ListProjectNamespacesRequest{
+			ProjectName: file.Project.Name,
+		})
+		if err != nil {
+			return plan, errors.Wrap(err, "failed to list namespaces")
+		}
+		for _, namespace := range namespacesResponse.GetNamespaces() {
+			if namespace.GetName() == file.Namespace.Name {
+				namespaceAction = configAction(namespace.GetConfig(), file.Namespace.Config)
+			}
+		}
+	}
+	if namespaceAction != applyActionCreate {
+		var pageToken string
+		for {
+			jobsResponse, err := runtime.ListJobSpecification(ctx, &pb.ListJobSpecificationRequest{
+				ProjectName: file.Project.Name,
+				Namespace:   file.Namespace.Name,
+				PageToken:   pageToken,
+			})
+			if err != nil {
+				return plan, errors.Wrap(err, "failed to list jobs")
+			}
+			serverJobs = append(serverJobs, jobsResponse.GetJobs()...)
+			if pageToken = jobsResponse.GetNextPageToken(); pageToken == "" {
+				break
+			}
+		}
+	}
+	plan.changes = append(plan.changes, applyChange{Kind: "namespace", Name: file.Namespace.Name, Action: namespaceAction})
+	plan.registerProject = projectAction != applyActionUnchanged || namespaceAction != applyActionUnchanged
+
+	for _, secret := range file.Secrets {
+		plan.changes = append(plan.changes, applyChange{Kind: "secret", Name: secret.Name, Action: "register"})
+	}
+
+	serverJobsByName := map[string]*pb.JobSpecification{}
+	for _, serverJob := range serverJobs {
+		serverJobsByName[serverJob.GetName()] = serverJob
+	}
+	fileJobNames := map[string]bool{}
+	for _, fileJob := range fileJobs {
+		fileJobNames[fileJob.GetName()] = true
+		plan.jobsToDeploy = append(plan.jobsToDeploy, fileJob)
+
+		action := applyActionCreate
+		if serverJob, ok := serverJobsByName[fileJob.GetName()]; ok {
+			action = applyActionUnchanged
+			if !jobProtoEqual(serverJob, fileJob) {
+				action = applyActionUpdate
+			}
+		}
+		plan.deployJobs = plan.deployJobs || action != applyActionUnchanged
+		plan.changes = append(plan.changes, applyChange{Kind: "job", Name: fileJob.GetName(), Action: action})
+	}
+	for _, serverJob := range serverJobs {
+		if fileJobNames[serverJob.GetName()] {
+			continue
+		}
+		// deployment deletes every job not sent, jobs not in the file are sent
+		// back as they are, pruned jobs are deleted on their own afterwards so
+		// jobs depending on them are checked
+		plan.jobsToDeploy = append(plan.jobsToDeploy, serverJob)
+		if prune {
+			plan.jobsToDelete = append(plan.jobsToDelete, serverJob.GetName())
+			plan.changes = append(plan.changes, applyChange{Kind: "job", Name: serverJob.GetName(), Action: applyActionDelete})
+			continue
+		}
+		plan.changes = append(plan.changes, applyChange{Kind: "job", Name: serverJob.GetName(), Action: applyActionUnmanaged})
+	}
+	return plan, nil
+}
+
+func configAction(current, desired map[string]string) string {
+	if len(current) == 0 && len(desired) == 0 {
+		return applyActionUnchanged
+	}
+	if reflect.DeepEqual(current, desired) {
+		return applyActionUnchanged
+	}
+	return applyActionUpdate
+}
+
+func featureFlagsEqual(current, desired map[string]bool) bool {
+	return (len(current) == 0 && len(desired) == 0) || reflect.DeepEqual(current, desired)
+}
+
+// jobProtoEqual compares jobs read from the server and from files after
+// normalising both of them
+func jobProtoEqual(current, desired *pb.JobSpecification) bool {
+	return proto.Equal(normaliseJobProto(current), normaliseJobProto(desired))
+}
+
+// normaliseJobProto returns a copy of job without differences that don't
+// change the job: dependencies, read from a map, and hooks are sorted by
+// name, configs by name and messages set to their empty value are unset
+func normaliseJobProto(job *pb.JobSpecification) *pb.JobSpecification {
+	job = proto.Clone(job).(*pb.JobSpecification)
+	sort.Slice(job.Dependencies, func(i, j int) bool {
+		return job.Dependencies[i].GetName() < job.Dependencies[j].GetName()
+	})
+	sortJobConfigs(job.Config)
+	sort.SliceStable(job.Hooks, func(i, j int) bool {
+		return job.Hooks[i].GetName() < job.Hooks[j].GetName()
+	})
+	for _, hook := range job.Hooks {
+		sortJobConfigs(hook.Config)
+	}
+	clearEmptyMessages(job.ProtoReflect())
+	return job
+}
+
+func sortJobConfigs(configs []*pb.JobConfigItem) {
+	sort.SliceStable(configs, func(i, j int) bool {
+		return configs[i].GetName() < configs[j].GetName()
+	})
+}
+
+// clearEmptyMessages unsets message fields of m, and of the messages in it,
+// set to an empty message
+func clearEmptyMessages(m protoreflect.Message) {
+	var emptyFields []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind || fd.IsMap() {
+			return true
+		}
+		if fd.IsList() {
+			for i := 0; i < v.List().Len(); i++ {
+				clearEmptyMessages(v.List().Get(i).Message())
+			}
+			return true
+		}
+		clearEmptyMessages(v.Message())
+		if proto.Size(v.Message().Interface()) == 0 {
+			emptyFields = append(emptyFields, fd)
+		}
+		return true
+	})
+	for _, fd := range emptyFields {
+		m.Clear(fd)
+	}
+}
+
+func webhooksEqual(current, desired []*pb.ProjectSpecification_ProjectWebhook) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	for i := range current {
+		if !proto.Equal(current[i], desired[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func blackoutWindowsEqual(current, desired []*pb.ProjectSpecification_ProjectBlackoutWindow) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	for i := range current {
+		if !proto.Equal(current[i], desired[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func printApplyPlan(w io.Writer, plan applyPlan) {
+	table := tablewriter.NewWriter(w)
+	table.SetBorder(false)
+	table.SetHeader([]string{"Kind", "Name", "Action"})
+	for _, change := range plan.changes {
+		table.Append([]string{change.Kind, change.Name, change.Action})
+	}
+	table.Render()
+}
+
+// executeApplyPlan applies the planned changes using the existing runtime calls
+func executeApplyPlan(ctx context.Context, l logger, runtime pb.RuntimeServiceClient, file applyFile, plan applyPlan) error {
+	if plan.registerProject {
+		registerResponse, err := runtime.RegisterProject(ctx, &pb.RegisterProjectRequest{
+			Project: &pb.ProjectSpecification{
+				Name:            file.Project.Name,
+				Config:          file.Project.Config,
+				FeatureFlags:    file.Project.FeatureFlags,
+				Webhooks:        toProjectWebhooksProto(file.Project.Webhooks),
+				BlackoutWindows: toProjectBlackoutWindowsProto(file.Project.BlackoutWindows),
+			},
+			Namespace: &pb.NamespaceSpecification{
+				Name:   file.Namespace.Name,
+				Config: file.Namespace.Config,
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to update project configurations")
+		} else if !registerResponse.Success {
+			return fmt.Errorf("failed to update project configurations, %s", registerResponse.Message)
+		}
+		l.Println("updated project configuration")
+	}
+
+	if plan.registerVariables {
+		if _, err := runtime.RegisterProjectVariables(ctx, &pb.RegisterProjectVariablesRequest{
+			ProjectName: file.Project.Name,
+			Variables:   file.Project.Variables,
+		}); err != nil {
+			return errors.Wrap(err, "failed to register project variables")
+		}
+		l.Println("registered project variables")
+	}
+
+	for _, secret := range file.Secrets {
+		secretResponse, err := runtime.RegisterSecret(ctx, &pb.RegisterSecretRequest{
+			ProjectName: file.Project.Name,
+			SecretName:  secret.Name,
+			Value:       base64.StdEncoding.EncodeToString([]byte(secret.Value)),
+			TtlSeconds:  int64(secret.TTL / time.Second),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to register secret %s", secret.Name)
+		} else if !secretResponse.Success {
+			return fmt.Errorf("failed to register secret %s, %s", secret.Name, secretResponse.Message)
+		}
+	}
+	if len(file.Secrets) > 0 {
+		l.Println("registered secrets")
+	}
+
+	if !plan.deployJobs {
+		l.Println("jobs are up to date")
+		return deleteJobs(ctx, l, runtime, file, plan.jobsToDelete)
+	}
+	respStream, err := runtime.DeployJobSpecification(ctx, &pb.DeployJobSpecificationRequest{
+		Jobs:        plan.jobsToDeploy,
+		ProjectName: file.Project.Name,
+		Namespace:   file.Namespace.Name,
+		Git:         plan.git,
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			l.Println("apply process took too long, timing out")
+		}
+		return errors.Wrap(err, "job deployment failed")
+	}
+	for {
+		resp, err := respStream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return errors.Wrap(err, "failed to receive deployment ack")
+		}
+		if resp.Ack && !resp.GetSuccess() {
+			return errors.Errorf("unable to deploy: %s %s", resp.GetJobName(), resp.GetMessage())
+		}
+	}
+	l.Println("deployed jobs")
+	return deleteJobs(ctx, l, runtime, file, plan.jobsToDelete)
+}
+
+// deleteJobs soft deletes the jobs of the namespace of the file, the server
+// refuses to delete jobs other jobs depend on
+func deleteJobs(ctx context.Context, l logger, runtime pb.RuntimeServiceClient, file applyFile, jobNames []string) error {
+	for _, jobName := range jobNames {
+		deleteResponse, err := runtime.DeleteJobSpecification(ctx, &pb.DeleteJobSpecificationRequest{
+			ProjectName: file.Project.Name,
+			Namespace:   file.Namespace.Name,
+			JobName:     jobName,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete job %s", jobName)
+		} else if !deleteResponse.GetSuccess() {
+			return fmt.Errorf("failed to delete job %s, %s", jobName, deleteResponse.GetMessage())
+		}
+		l.Printf("deleted job %s, it can be restored with undelete\n", jobName)
+	}
+	return nil
+}
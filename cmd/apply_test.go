@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestConfigAction(t *testing.T) {
+	testCases := []struct {
+		name             string
+		current, desired map[string]string
+		expected         string
+	}{
+		{"nil and empty configs are unchanged", nil, map[string]string{}, applyActionUnchanged},
+		{"equal configs are unchanged", map[string]string{"BUCKET": "gs://a"}, map[string]string{"BUCKET": "gs://a"}, applyActionUnchanged},
+		{"changed values are updated", map[string]string{"BUCKET": "gs://a"}, map[string]string{"BUCKET": "gs://b"}, applyActionUpdate},
+		{"added keys are updated", nil, map[string]string{"BUCKET": "gs://a"}, applyActionUpdate},
+		{"removed keys are updated", map[string]string{"BUCKET": "gs://a"}, nil, applyActionUpdate},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, configAction(testCase.current, testCase.desired))
+		})
+	}
+}
+
+func TestJobProtoEqual(t *testing.T) {
+	newJob := func() *pb.JobSpecification {
+		return &pb.JobSpecification{
+			Name:     "daily-orders",
+			Interval: "0 2 * * *",
+			Config: []*pb.JobConfigItem{
+				{Name: "PROJECT", Value: "data-project"},
+				{Name: "DATASET", Value: "sales"},
+			},
+			Dependencies: []*pb.JobDependency{{Name: "daily-refunds"}, {Name: "daily-invoices"}},
+			Hooks: []*pb.JobSpecHook{
+				{Name: "transporter", Config: []*pb.JobConfigItem{{Name: "B", Value: "b"}, {Name: "A", Value: "a"}}},
+				{Name: "predator"},
+			},
+		}
+	}
+	testCases := []struct {
+		name     string
+		change   func(job *pb.JobSpecification)
+		expected bool
+	}{
+		{"should ignore the order of dependencies", func(job *pb.JobSpecification) {
+			job.Dependencies[0], job.Dependencies[1] = job.Dependencies[1], job.Dependencies[0]
+		}, true},
+		{"should ignore the order of configs", func(job *pb.JobSpecification) {
+			job.Config[0], job.Config[1] = job.Config[1], job.Config[0]
+		}, true},
+		{"should ignore the order of hooks and their configs", func(job *pb.JobSpecification) {
+			job.Hooks[0], job.Hooks[1] = job.Hooks[1], job.Hooks[0]
+			job.Hooks[1].Config[0], job.Hooks[1].Config[1] = job.Hooks[1].Config[1], job.Hooks[1].Config[0]
+		}, true},
+		{"should treat empty messages as unset", func(job *pb.JobSpecification) {
+			job.Behavior = &pb.JobSpecification_Behavior{Retry: &pb.JobSpecification_Behavior_Retry{}}
+		}, true},
+		{"should detect changed configs", func(job *pb.JobSpecification) {
+			job.Config[0].Value = "another-project"
+		}, false},
+		{"should detect changed behavior", func(job *pb.JobSpecification) {
+			job.Behavior = &pb.JobSpecification_Behavior{Retry: &pb.JobSpecification_Behavior_Retry{Count: 2}}
+		}, false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			current, desired := newJob(), newJob()
+			testCase.change(desired)
+			compared := proto.Clone(desired)
+
+			assert.Equal(t, testCase.expected, jobProtoEqual(current, desired))
+			assert.True(t, proto.Equal(compared, desired), "jobs compared should be left as they are")
+		})
+	}
+}
+
+func TestPlanApply(t *testing.T) {
+	newFile := func() applyFile {
+		file := applyFile{}
+		file.Project.Name = "a-data-project"
+		file.Project.Config = map[string]string{"STORAGE_PATH": "gs://optimus/a-data-project"}
+		file.Namespace.Name = "sales"
+		return file
+	}
+	newClient := func() *exportRuntimeClient {
+		return &exportRuntimeClient{
+			projects: []*pb.ProjectSpecification{
+				{Name: "a-data-project", Config: map[string]string{"STORAGE_PATH": "gs://optimus/a-data-project"}},
+			},
+			namespaces: map[string][]*pb.NamespaceSpecification{
+				"a-data-project": {{Name: "sales"}},
+			},
+			jobs: map[string][]*pb.JobSpecification{
+				"a-data-project/sales": {
+					{Name: "daily-orders", Interval: "0 2 * * *"},
+					{Name: "daily-refunds", Interval: "0 3 * * *"},
+				},
+			},
+		}
+	}
+	jobActions := func(plan applyPlan) map[string]string {
+		actions := map[string]string{}
+		for _, change := range plan.changes {
+			if change.Kind == "job" {
+				actions[change.Name] = change.Action
+			}
+		}
+		return actions
+	}
+
+	testCases := []struct {
+		name     string
+		file     func() applyFile
+		client   func() *exportRuntimeClient
+		fileJobs []*pb.JobSpecification
+		prune    bool
+
+		expectedJobActions    map[string]string
+		expectedRegister      bool
+		expectedDeploy        bool
+		expectedJobsToDeploy  []string
+		expectedJobsToDelete  []string
+		expectedProjectAction string
+	}{
+		{
+			name:   "should create a project that isn't registered with its jobs",
+			file:   newFile,
+			client: func() *exportRuntimeClient { return &exportRuntimeClient{} },
+			fileJobs: []*pb.JobSpecification{
+				{Name: "daily-orders", Interval: "0 2 * * *"},
+			},
+			expectedJobActions:    map[string]string{"daily-orders": applyActionCreate},
+			expectedRegister:      true,
+			expectedDeploy:        true,
+			expectedJobsToDeploy:  []string{"daily-orders"},
+			expectedProjectAction: applyActionCreate,
+		},
+		{
+			name:   "should keep jobs missing in the file without prune",
+			file:   newFile,
+			client: newClient,
+			fileJobs: []*pb.JobSpecification{
+				{Name: "daily-orders", Interval: "0 2 * * *"},
+			},
+			expectedJobActions: map[string]string{
+				"daily-orders":  applyActionUnchanged,
+				"daily-refunds": applyActionUnmanaged,
+			},
+			expectedJobsToDeploy:  []string{"daily-orders", "daily-refunds"},
+			expectedProjectAction: applyActionUnchanged,
+		},
+		{
+			name:   "should delete jobs missing in the file with prune after deploying them",
+			file:   newFile,
+			client: newClient,
+			fileJobs: []*pb.JobSpecification{
+				{Name: "daily-orders", Interval: "0 4 * * *"},
+			},
+			prune: true,
+			expectedJobActions: map[string]string{
+				"daily-orders":  applyActionUpdate,
+				"daily-refunds": applyActionDelete,
+			},
+			expectedDeploy:        true,
+			expectedJobsToDeploy:  []string{"daily-orders", "daily-refunds"},
+			expectedJobsToDelete:  []string{"daily-refunds"},
+			expectedProjectAction: applyActionUnchanged,
+		},
+		{
+			name: "should update the project if its config changed",
+			file: func() applyFile {
+				file := newFile()
+				file.Project.Config = map[string]string{"STORAGE_PATH": "gs://optimus/another-path"}
+				return file
+			},
+			client: newClient,
+			fileJobs: []*pb.JobSpecification{
+				{Name: "daily-orders", Interval: "0 2 * * *"},
+				{Name: "daily-refunds", Interval: "0 3 * * *"},
+			},
+			expectedJobActions: map[string]string{
+				"daily-orders":  applyActionUnchanged,
+				"daily-refunds": applyActionUnchanged,
+			},
+			expectedRegister:      true,
+			expectedJobsToDeploy:  []string{"daily-orders", "daily-refunds"},
+			expectedProjectAction: applyActionUpdate,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			plan, err := planApply(context.Background(), testCase.client(), testCase.file(), testCase.fileJobs, testCase.prune)
+			assert.Nil(t, err)
+
+			assert.Equal(t, applyChange{Kind: "project", Name: "a-data-project", Action: testCase.expectedProjectAction},
+				plan.changes[0])
+			assert.Equal(t, testCase.expectedJobActions, jobActions(plan))
+			assert.Equal(t, testCase.expectedRegister, plan.registerProject)
+			assert.Equal(t, testCase.expectedDeploy, plan.deployJobs)
+			var jobsToDeploy []string
+			for _, job := range plan.jobsToDeploy {
+				jobsToDeploy = append(jobsToDeploy, job.GetName())
+			}
+			assert.Equal(t, testCase.expectedJobsToDeploy, jobsToDeploy)
+			assert.Equal(t, testCase.expectedJobsToDelete, plan.jobsToDelete)
+		})
+	}
+}
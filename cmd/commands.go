@@ -9,7 +9,14 @@ import (
 
 	"github.com/odpf/optimus/store/local"
 
+	"github.com/odpf/optimus/api/middleware"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	"github.com/fatih/color"
 	"github.com/odpf/optimus/config"
@@ -34,6 +41,12 @@ var (
 	GRPCMaxClientRecvSize = 45 << 20 // 45MB
 
 	OptimusDialTimeout = time.Second * 2
+
+	// compressor of grpc messages sent to the server, uncompressed if empty
+	grpcCompression = ""
+
+	// tls used to connect the server, connections are insecure if not enabled
+	clientTLS config.ClientTLSConfig
 )
 
 func programPrologue(ver string) string {
@@ -70,6 +83,12 @@ func New(
 		SilenceUsage: true,
 	}
 	cmd.PersistentFlags().BoolVar(&disableColoredOut, "no-color", disableColoredOut, "disable colored output")
+	cmd.PersistentFlags().StringVar(&grpcCompression, "grpc-compression", conf.GetGRPCCompression(),
+		fmt.Sprintf("compress messages sent to the server with %s or %s", middleware.CompressionGzip, middleware.CompressionSnappy))
+	clientTLS = conf.GetTLS()
+	// read before the command is built, declared to be accepted and listed in help
+	cmd.PersistentFlags().String(config.FileFlag, "",
+		fmt.Sprintf("config file used instead of %s.%s, can also be set with %s", config.FileName, config.FileExtension, config.FileEnv))
 
 	//init local specs
 	var jobSpecRepo JobSpecRepository
@@ -89,34 +108,83 @@ func New(
 	cmd.AddCommand(configCommand(l, dsRepo))
 	cmd.AddCommand(createCommand(l, jobSpecFs, datastoreSpecsFs, pluginRepo, dsRepo))
 	cmd.AddCommand(deployCommand(l, conf, jobSpecRepo, pluginRepo, dsRepo, datastoreSpecsFs))
-	cmd.AddCommand(renderCommand(l, conf.GetHost(), jobSpecRepo))
+	cmd.AddCommand(renderCommand(l, conf.GetHost(), conf.GetProjectConfig().Global, jobSpecRepo))
 	cmd.AddCommand(validateCommand(l, conf.GetHost(), pluginRepo, jobSpecRepo))
 	cmd.AddCommand(optimusServeCommand(l, conf))
 	cmd.AddCommand(replayCommand(l, conf))
+	cmd.AddCommand(applyCommand(l, conf, pluginRepo, dsRepo))
+	cmd.AddCommand(exportCommand(l, conf, pluginRepo, dsRepo))
+	cmd.AddCommand(secretCommand(l, conf))
 
 	// admin specific commands
 	if conf.GetAdmin().Enabled {
-		cmd.AddCommand(adminCommand(l, pluginRepo))
+		cmd.AddCommand(adminCommand(l, conf, pluginRepo))
 	}
 
 	return cmd
 }
 
 func createConnection(ctx context.Context, host string) (*grpc.ClientConn, error) {
+	if err := middleware.ValidateCompression(grpcCompression); err != nil {
+		return nil, err
+	}
+	callOpts := []grpc.CallOption{
+		grpc.MaxCallSendMsgSize(GRPCMaxClientSendSize),
+		grpc.MaxCallRecvMsgSize(GRPCMaxClientRecvSize),
+	}
+	if grpcCompression != "" {
+		callOpts = append(callOpts, grpc.UseCompressor(grpcCompression))
+	}
+	creds, err := transportCredentials()
+	if err != nil {
+		return nil, err
+	}
 	var opts []grpc.DialOption
 	opts = append(opts,
-		grpc.WithInsecure(),
+		grpc.WithTransportCredentials(creds),
 		grpc.WithBlock(),
-		grpc.WithDefaultCallOptions(
-			grpc.MaxCallSendMsgSize(GRPCMaxClientSendSize),
-			grpc.MaxCallRecvMsgSize(GRPCMaxClientRecvSize),
-		),
+		grpc.WithDefaultCallOptions(callOpts...),
 	)
 
 	conn, err := grpc.DialContext(ctx, host, opts...)
 	if err != nil {
 		return nil, err
 	}
+	if err := negotiateAPIVersion(ctx, conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
 
 	return conn, nil
 }
+
+// negotiateAPIVersion fails if the server doesn't accept the version of this client
+func negotiateAPIVersion(ctx context.Context, conn *grpc.ClientConn) error {
+	_, err := pb.NewRuntimeServiceClient(conn).NegotiateAPIVersion(ctx, &pb.NegotiateAPIVersionRequest{
+		ClientVersion: config.Version,
+	})
+	if err != nil {
+		// older servers don't support negotiation
+		if status.Code(err) == codes.Unimplemented {
+			return nil
+		}
+		if status.Code(err) == codes.FailedPrecondition {
+			return errors.New(status.Convert(err).Message())
+		}
+		return errors.Wrap(err, "failed to negotiate api version")
+	}
+	return nil
+}
+
+// transportCredentials are tls credentials of the configured client
+// certificates, or insecure ones if tls isn't configured
+func transportCredentials() (credentials.TransportCredentials, error) {
+	if !clientTLS.Enabled() {
+		return insecure.NewCredentials(), nil
+	}
+	tlsConfig, err := newClientTLSConfig(clientTLS)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
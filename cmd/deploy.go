@@ -18,6 +18,7 @@ import (
 	"github.com/pkg/errors"
 	cli "github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 var (
@@ -88,8 +89,11 @@ func postDeploymentRequest(l logger, projectName string, namespace string, jobSp
 	// update project config if needed
 	registerResponse, err := runtime.RegisterProject(deployTimeoutCtx, &pb.RegisterProjectRequest{
 		Project: &pb.ProjectSpecification{
-			Name:   projectName,
-			Config: conf.GetProjectConfig().Global,
+			Name:            projectName,
+			Config:          conf.GetProjectConfig().Global,
+			FeatureFlags:    conf.GetProjectConfig().FeatureFlags,
+			Webhooks:        toProjectWebhooksProto(conf.GetProjectConfig().Webhooks),
+			BlackoutWindows: toProjectBlackoutWindowsProto(conf.GetProjectConfig().BlackoutWindows),
 		},
 		Namespace: &pb.NamespaceSpecification{
 			Name:   namespace,
@@ -103,6 +107,20 @@ func postDeploymentRequest(l logger, projectName string, namespace string, jobSp
 	}
 	l.Println("updated project configuration")
 
+	variables, err := readProjectVariables()
+	if err != nil {
+		return err
+	}
+	if variables != nil {
+		if _, err := runtime.RegisterProjectVariables(deployTimeoutCtx, &pb.RegisterProjectVariablesRequest{
+			ProjectName: projectName,
+			Variables:   variables,
+		}); err != nil {
+			return errors.Wrap(err, "failed to update project variables")
+		}
+		l.Println("updated project variables")
+	}
+
 	if !ignoreResources {
 		// deploy datastore resources
 		for storeName, repoFS := range datastoreSpecFs {
@@ -193,6 +211,7 @@ func postDeploymentRequest(l logger, projectName string, namespace string, jobSp
 			Jobs:        adaptedJobSpecs,
 			ProjectName: projectName,
 			Namespace:   namespace,
+			Git:         readGitContext(conf.GetJob().Path),
 		})
 		if err != nil {
 			if errors.Is(err, context.DeadlineExceeded) {
@@ -218,6 +237,9 @@ func postDeploymentRequest(l logger, projectName string, namespace string, jobSp
 				}
 				jobCounter++
 				l.Printf("%d/%d. %s successfully deployed\n", jobCounter, totalJobs, resp.GetJobName())
+			} else if resp.GetJobName() == "" {
+				// progress of the whole deployment
+				l.Printf("info: %s\n", resp.GetMessage())
 			} else {
 				// ordinary progress event
 				l.Printf("info '%s': %s\n", resp.GetJobName(), resp.GetMessage())
@@ -231,3 +253,27 @@ func postDeploymentRequest(l logger, projectName string, namespace string, jobSp
 	l.Println("deployment completed successfully")
 	return nil
 }
+
+func toProjectWebhooksProto(webhooks []config.Webhook) []*pb.ProjectSpecification_ProjectWebhook {
+	var protos []*pb.ProjectSpecification_ProjectWebhook
+	for _, webhook := range webhooks {
+		protos = append(protos, &pb.ProjectSpecification_ProjectWebhook{
+			Url:        webhook.URL,
+			SecretName: webhook.SecretName,
+			Events:     webhook.Events,
+		})
+	}
+	return protos
+}
+
+func toProjectBlackoutWindowsProto(windows []config.BlackoutWindow) []*pb.ProjectSpecification_ProjectBlackoutWindow {
+	var protos []*pb.ProjectSpecification_ProjectBlackoutWindow
+	for _, window := range windows {
+		protos = append(protos, &pb.ProjectSpecification_ProjectBlackoutWindow{
+			Start:      window.Start,
+			Duration:   durationpb.New(window.Duration),
+			JobPattern: window.JobPattern,
+		})
+	}
+	return protos
+}
@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store/local"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	exportTimeout = time.Minute * 10
+)
+
+// exportCommand writes the projects, namespaces and jobs registered on the
+// server to a directory, every namespace can be restored with optimus apply
+func exportCommand(l logger, conf config.Provider, pluginRepo models.PluginRepository, datastoreRepo models.DatastoreRepo) *cli.Command {
+	var (
+		projectName string
+		outputDir   string
+		allProjects bool
+	)
+	cmd := &cli.Command{
+		Use:     "export",
+		Short:   "Export project configuration and jobs to a directory",
+		Example: "optimus export --project=a-data-project --output-dir=backup",
+		Long: `
+Writes a directory per namespace of the project at
+<output-dir>/<project>/<namespace>, with the project and namespace configuration
+in project.yaml and a file per job in jobs/. Each of them can be restored with
+optimus apply --file=<output-dir>/<project>/<namespace>. Secret values can't be
+read back from the server and are not exported.
+		`,
+	}
+	cmd.Flags().StringVarP(&projectName, "project", "p", "", "name of the project to export")
+	cmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "directory to write the project files to")
+	cmd.MarkFlagRequired("output-dir")
+	cmd.Flags().BoolVar(&allProjects, "all-projects", false, "export every project registered on the server")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		if (projectName == "") == !allProjects {
+			return errors.New("either --project or --all-projects is required")
+		}
+
+		dialTimeoutCtx, dialCancel := context.WithTimeout(context.Background(), OptimusDialTimeout)
+		defer dialCancel()
+		conn, err := createConnection(dialTimeoutCtx, conf.GetHost())
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				l.Println("can't reach optimus service")
+			}
+			return err
+		}
+		defer conn.Close()
+
+		exportTimeoutCtx, exportCancel := context.WithTimeout(context.Background(), exportTimeout)
+		defer exportCancel()
+		exporter := &projectExporter{
+			runtime:    pb.NewRuntimeServiceClient(conn),
+			adapt:      v1handler.NewAdapter(pluginRepo, datastoreRepo),
+			jobAdapter: local.NewJobSpecAdapter(pluginRepo),
+			outputDir:  outputDir,
+		}
+		exported, err := exporter.Export(exportTimeoutCtx, projectName)
+		if err != nil {
+			return err
+		}
+		for _, namespaceDir := range exported {
+			l.Println(fmt.Sprintf("exported %s", namespaceDir))
+		}
+		l.Println(coloredSuccess("export completed successfully"))
+		return nil
+	}
+	return cmd
+}
+
+type projectExporter struct {
+	runtime    pb.RuntimeServiceClient
+	adapt      *v1handler.Adapter
+	jobAdapter *local.JobSpecAdapter
+	outputDir  string
+}
+
+// Export writes the namespaces of the project, or of every project if
+// projectName is empty, and returns the directories written
+func (e *projectExporter) Export(ctx context.Context, projectName string) ([]string, error) {
+	projectsResponse, err := e.runtime.ListProjects(ctx, &pb.ListProjectsRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list projects")
+	}
+	var projects []*pb.ProjectSpecification
+	for _, project := range projectsResponse.GetProjects() {
+		if projectName == "" || project.GetName() == projectName {
+			projects = append(projects, project)
+		}
+	}
+	if len(projects) == 0 {
+		return nil, errors.Errorf("project %s is not registered", projectName)
+	}
+
+	var exported []string
+	for _, project := range projects {
+		dirs, err := e.exportProject(ctx, project)
+		if err != nil {
+			return exported, errors.Wrapf(err, "failed to export project %s", project.GetName())
+		}
+		exported = append(exported, dirs...)
+	}
+	return exported, nil
+}
+
+func (e *projectExporter) exportProject(ctx context.Context, project *pb.ProjectSpecification) ([]string, error) {
+	variablesResponse, err := e.runtime.GetProjectVariables(ctx, &pb.GetProjectVariablesRequest{
+		ProjectName: project.GetName(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read project variables")
+	}
+	namespacesResponse, err := e.runtime.ListProjectNamespaces(ctx, &pb.ListProjectNamespacesRequest{
+		ProjectName: project.GetName(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list namespaces")
+	}
+
+	var exported []string
+	for _, namespace := range namespacesResponse.GetNamespaces() {
+		var file applyFile
+		file.Project.Name = project.GetName()
+		file.Project.Config = project.GetConfig()
+		file.Project.FeatureFlags = project.GetFeatureFlags()
+		file.Project.Variables = variablesResponse.GetVariables()
+		for _, webhook := range project.GetWebhooks() {
+			file.Project.Webhooks = append(file.Project.Webhooks, config.Webhook{
+				URL:        webhook.GetUrl(),
+				SecretName: webhook.GetSecretName(),
+				Events:     webhook.GetEvents(),
+			})
+		}
+		file.Namespace.Name = namespace.GetName()
+		file.Namespace.Config = namespace.GetConfig()
+
+		jobs, err := e.listJobs(ctx, project.GetName(), namespace.GetName())
+		if err != nil {
+			return exported, errors.Wrapf(err, "failed to list jobs of namespace %s", namespace.GetName())
+		}
+
+		namespaceDir, err := exportPath(e.outputDir, project.GetName(), namespace.GetName())
+		if err != nil {
+			return exported, err
+		}
+		if err := writeApplyDir(namespaceDir, file, jobs); err != nil {
+			return exported, err
+		}
+		exported = append(exported, namespaceDir)
+	}
+	return exported, nil
+}
+
+func (e *projectExporter) listJobs(ctx context.Context, projectName, namespace string) ([]local.Job, error) {
+	var jobs []local.Job
+	var pageToken string
+	for {
+		jobsResponse, err := e.runtime.ListJobSpecification(ctx, &pb.ListJobSpecificationRequest{
+			ProjectName: projectName,
+			Namespace:   namespace,
+			PageToken:   pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, jobProto := range jobsResponse.GetJobs() {
+			jobSpec, err := e.adapt.FromJobProto(jobProto)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read job %s", jobProto.GetName())
+			}
+			localJob, err := e.jobAdapter.FromSpec(jobSpec)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read job %s", jobProto.GetName())
+			}
+			// dependencies are kept in a map, sorting them keeps exports
+			// of the same jobs identical
+			sort.Slice(localJob.Dependencies, func(i, j int) bool {
+				return localJob.Dependencies[i].JobName < localJob.Dependencies[j].JobName
+			})
+			jobs = append(jobs, localJob)
+		}
+		if pageToken = jobsResponse.GetNextPageToken(); pageToken == "" {
+			break
+		}
+	}
+	return jobs, nil
+}
+
+// writeApplyDir writes file in the directory layout read by readApplyFile,
+// job files of jobs no longer registered are removed
+func writeApplyDir(dir string, file applyFile, jobs []local.Job) error {
+	jobsDir := filepath.Join(dir, applyJobsDirName)
+	// job names come from the server, they are checked before anything is
+	// removed or written
+	jobFiles := make([]string, len(jobs))
+	for idx, localJob := range jobs {
+		jobFile, err := exportPath(jobsDir, localJob.Name)
+		if err != nil {
+			return err
+		}
+		jobFiles[idx] = jobFile + ".yaml"
+	}
+	if err := os.RemoveAll(jobsDir); err != nil {
+		return errors.Wrapf(err, "failed to clean %s", jobsDir)
+	}
+	if err := os.MkdirAll(jobsDir, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create %s", jobsDir)
+	}
+	if err := writeYAMLFile(filepath.Join(dir, applyProjectFileName), file); err != nil {
+		return err
+	}
+	for idx, localJob := range jobs {
+		if err := writeYAMLFile(jobFiles[idx], localJob); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportPath joins names of projects, namespaces and jobs to dir, failing
+// for names that aren't a single path segment or would leave dir
+func exportPath(dir string, segments ...string) (string, error) {
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsAny(segment, `/\`) {
+			return "", errors.Errorf("%q can't be used as a file name", segment)
+		}
+	}
+	path := filepath.Join(append([]string{dir}, segments...)...)
+	rel, err := filepath.Rel(filepath.Clean(dir), path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("%s is outside of %s", path, dir)
+	}
+	return path, nil
+}
+
+func writeYAMLFile(path string, in interface{}) error {
+	raw, err := yaml.Marshal(in)
+	if err != nil {
+		return errors.Wrapf(err, "failed to serialize %s", path)
+	}
+	if err := ioutil.WriteFile(path, raw, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write %s", path)
+	}
+	return nil
+}
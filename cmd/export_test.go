@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store/local"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// exportRuntimeClient serves the projects, namespaces and jobs read by
+// projectExporter and planApply from memory
+type exportRuntimeClient struct {
+	pb.RuntimeServiceClient
+
+	projects   []*pb.ProjectSpecification
+	namespaces map[string][]*pb.NamespaceSpecification
+	variables  map[string]map[string]string
+	// project/namespace -> jobs, served one per page
+	jobs map[string][]*pb.JobSpecification
+}
+
+func (c *exportRuntimeClient) ListProjects(ctx context.Context, in *pb.ListProjectsRequest, opts ...grpc.CallOption) (*pb.ListProjectsResponse, error) {
+	return &pb.ListProjectsResponse{Projects: c.projects}, nil
+}
+
+func (c *exportRuntimeClient) ListProjectNamespaces(ctx context.Context, in *pb.ListProjectNamespacesRequest, opts ...grpc.CallOption) (*pb.ListProjectNamespacesResponse, error) {
+	return &pb.ListProjectNamespacesResponse{Namespaces: c.namespaces[in.GetProjectName()]}, nil
+}
+
+func (c *exportRuntimeClient) GetProjectVariables(ctx context.Context, in *pb.GetProjectVariablesRequest, opts ...grpc.CallOption) (*pb.GetProjectVariablesResponse, error) {
+	return &pb.GetProjectVariablesResponse{Variables: c.variables[in.GetProjectName()]}, nil
+}
+
+func (c *exportRuntimeClient) ListJobSpecification(ctx context.Context, in *pb.ListJobSpecificationRequest, opts ...grpc.CallOption) (*pb.ListJobSpecificationResponse, error) {
+	jobs := c.jobs[in.GetProjectName()+"/"+in.GetNamespace()]
+	page := 0
+	if in.GetPageToken() != "" {
+		page = int(in.GetPageToken()[0] - '0')
+	}
+	if page >= len(jobs) {
+		return &pb.ListJobSpecificationResponse{}, nil
+	}
+	resp := &pb.ListJobSpecificationResponse{Jobs: jobs[page : page+1]}
+	if page+1 < len(jobs) {
+		resp.NextPageToken = string(rune('0' + page + 1))
+	}
+	return resp, nil
+}
+
+func TestExport(t *testing.T) {
+	execUnit := new(mock.BasePlugin)
+	execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{Name: "bq2bq"}, nil)
+	pluginRepo := new(mock.SupportedPluginRepo)
+	pluginRepo.On("GetByName", "bq2bq").Return(&models.Plugin{Base: execUnit}, nil)
+	adapt := v1handler.NewAdapter(pluginRepo, nil)
+
+	newJob := func(name string, dependencies ...string) *pb.JobSpecification {
+		spec := models.JobSpec{
+			Version: 1,
+			Name:    name,
+			Owner:   "data@example.io",
+			Schedule: models.JobSpecSchedule{
+				StartDate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				Interval:  "0 2 * * *",
+			},
+			Behavior: models.JobSpecBehavior{
+				Retry: models.JobSpecBehaviorRetry{Count: 2, Delay: time.Minute},
+			},
+			Task: models.JobSpecTask{
+				Unit:   &models.Plugin{Base: execUnit},
+				Config: models.JobSpecConfigs{{Name: "PROJECT", Value: "data-project"}},
+				Window: models.JobSpecTaskWindow{Size: time.Hour * 24, TruncateTo: "d"},
+			},
+			Assets: *models.JobAssets{}.New([]models.JobSpecAsset{
+				{Name: "query.sql", Value: "select * from `data-project.sales.orders`"},
+			}),
+			Labels:       map[string]string{"team": "data"},
+			Dependencies: map[string]models.JobSpecDependency{},
+		}
+		for _, dependency := range dependencies {
+			spec.Dependencies[dependency] = models.JobSpecDependency{Type: models.JobSpecDependencyTypeIntra}
+		}
+		jobProto, err := adapt.ToJobProto(spec)
+		assert.Nil(t, err)
+		return jobProto
+	}
+	newClient := func() *exportRuntimeClient {
+		return &exportRuntimeClient{
+			projects: []*pb.ProjectSpecification{
+				{
+					Name:         "a-data-project",
+					Config:       map[string]string{"STORAGE_PATH": "gs://optimus/a-data-project"},
+					FeatureFlags: map[string]bool{"use_new_hook": true},
+					Webhooks: []*pb.ProjectSpecification_ProjectWebhook{
+						{Url: "https://hooks.example.io/optimus", SecretName: "WEBHOOK_SECRET", Events: []string{models.WebhookEventJobFailure}},
+					},
+				},
+				{Name: "another-project"},
+			},
+			namespaces: map[string][]*pb.NamespaceSpecification{
+				"a-data-project": {
+					{Name: "sales", Config: map[string]string{"DATASET": "sales"}},
+					{Name: "marketing"},
+				},
+				"another-project": {{Name: "finance"}},
+			},
+			variables: map[string]map[string]string{
+				"a-data-project": {"ENV": "production"},
+			},
+			jobs: map[string][]*pb.JobSpecification{
+				"a-data-project/sales":    {newJob("daily-orders"), newJob("weekly-orders", "daily-orders", "daily-refunds")},
+				"another-project/finance": {newJob("daily-invoices")},
+			},
+		}
+	}
+
+	t.Run("should export every namespace of the project in the layout read by apply", func(t *testing.T) {
+		outputDir := t.TempDir()
+		client := newClient()
+		exporter := &projectExporter{
+			runtime:    client,
+			adapt:      adapt,
+			jobAdapter: local.NewJobSpecAdapter(pluginRepo),
+			outputDir:  outputDir,
+		}
+
+		exported, err := exporter.Export(context.Background(), "a-data-project")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			filepath.Join(outputDir, "a-data-project", "sales"),
+			filepath.Join(outputDir, "a-data-project", "marketing"),
+		}, exported)
+
+		file, err := readApplyFile(exported[0])
+		assert.Nil(t, err)
+		assert.Equal(t, "a-data-project", file.Project.Name)
+		assert.Equal(t, map[string]string{"STORAGE_PATH": "gs://optimus/a-data-project"}, file.Project.Config)
+		assert.Equal(t, map[string]bool{"use_new_hook": true}, file.Project.FeatureFlags)
+		assert.Equal(t, map[string]string{"ENV": "production"}, file.Project.Variables)
+		assert.Equal(t, 1, len(file.Project.Webhooks))
+		assert.Equal(t, "sales", file.Namespace.Name)
+		assert.Equal(t, map[string]string{"DATASET": "sales"}, file.Namespace.Config)
+		assert.Equal(t, 2, len(file.Jobs))
+		assert.Equal(t, "daily-orders", file.Jobs[0].Name)
+		assert.Equal(t, "select * from `data-project.sales.orders`", file.Jobs[0].Asset["query.sql"])
+		assert.Equal(t, "daily-orders", file.Jobs[1].Dependencies[0].JobName)
+		assert.Equal(t, "daily-refunds", file.Jobs[1].Dependencies[1].JobName)
+
+		// restoring the export on the same server is a no-op
+		var fileJobs []*pb.JobSpecification
+		jobAdapter := local.NewJobSpecAdapter(pluginRepo)
+		for _, localJob := range file.Jobs {
+			jobSpec, err := jobAdapter.ToSpec(localJob)
+			assert.Nil(t, err)
+			jobProto, err := adapt.ToJobProto(jobSpec)
+			assert.Nil(t, err)
+			fileJobs = append(fileJobs, jobProto)
+		}
+		plan, err := planApply(context.Background(), client, file, fileJobs, true)
+		assert.Nil(t, err)
+		for _, change := range plan.changes {
+			assert.Equal(t, applyActionUnchanged, change.Action, change.Kind+" "+change.Name)
+		}
+		assert.False(t, plan.registerProject)
+		assert.False(t, plan.registerVariables)
+		assert.False(t, plan.deployJobs)
+
+		marketing, err := readApplyFile(exported[1])
+		assert.Nil(t, err)
+		assert.Empty(t, marketing.Jobs)
+	})
+	t.Run("should export every project and give the same files on every run", func(t *testing.T) {
+		outputDir := t.TempDir()
+		exporter := &projectExporter{
+			runtime:    newClient(),
+			adapt:      adapt,
+			jobAdapter: local.NewJobSpecAdapter(pluginRepo),
+			outputDir:  outputDir,
+		}
+
+		exported, err := exporter.Export(context.Background(), "")
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(exported))
+		jobFile := filepath.Join(outputDir, "a-data-project", "sales", applyJobsDirName, "weekly-orders.yaml")
+		first, err := ioutil.ReadFile(jobFile)
+		assert.Nil(t, err)
+
+		_, err = exporter.Export(context.Background(), "")
+		assert.Nil(t, err)
+		second, err := ioutil.ReadFile(jobFile)
+		assert.Nil(t, err)
+		assert.True(t, bytes.Equal(first, second))
+		assert.FileExists(t, filepath.Join(outputDir, "another-project", "finance", applyJobsDirName, "daily-invoices.yaml"))
+	})
+	t.Run("should fail for names that would be written outside of the output directory", func(t *testing.T) {
+		outputDir := t.TempDir()
+		client := newClient()
+		client.projects = client.projects[:1]
+		client.namespaces["a-data-project"] = []*pb.NamespaceSpecification{{Name: ".."}}
+		exporter := &projectExporter{
+			runtime:    client,
+			adapt:      adapt,
+			jobAdapter: local.NewJobSpecAdapter(pluginRepo),
+			outputDir:  outputDir,
+		}
+
+		_, err := exporter.Export(context.Background(), "a-data-project")
+		assert.EqualError(t, err, `failed to export project a-data-project: ".." can't be used as a file name`)
+	})
+	t.Run("should fail for job names that aren't a file name without removing exported jobs", func(t *testing.T) {
+		dir := t.TempDir()
+		jobFile := filepath.Join(dir, applyJobsDirName, "daily-orders.yaml")
+		assert.Nil(t, writeApplyDir(dir, applyFile{}, []local.Job{{Name: "daily-orders"}}))
+		assert.FileExists(t, jobFile)
+
+		for _, name := range []string{"../../escape", "a/b", `a\b`, ".."} {
+			err := writeApplyDir(dir, applyFile{}, []local.Job{{Name: name}})
+			assert.NotNil(t, err, name)
+		}
+		assert.FileExists(t, jobFile)
+		assert.NoFileExists(t, filepath.Join(filepath.Dir(dir), "escape.yaml"))
+	})
+	t.Run("should fail if the project isn't registered", func(t *testing.T) {
+		exporter := &projectExporter{runtime: newClient(), outputDir: t.TempDir()}
+		_, err := exporter.Export(context.Background(), "unknown-project")
+		assert.Equal(t, "project unknown-project is not registered", err.Error())
+	})
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+)
+
+// readGitContext describes the commit checked out at path, a file or a
+// directory, nil is returned if path isn't in a git repository or git isn't
+// installed
+func readGitContext(path string) *pb.GitContext {
+	dir := path
+	if dir == "" {
+		dir = "."
+	} else if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	commitSHA, err := runGit(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil
+	}
+	git := &pb.GitContext{CommitSha: commitSHA}
+	// detached checkouts, like those of CI pipelines, have no branch
+	if branch, err := runGit(dir, "rev-parse", "--abbrev-ref", "HEAD"); err == nil && branch != "HEAD" {
+		git.Branch = branch
+	}
+	if author, err := runGit(dir, "log", "-1", "--format=%an <%ae>", commitSHA); err == nil {
+		git.Author = author
+	}
+	if commitTime, err := runGit(dir, "log", "-1", "--format=%cI", commitSHA); err == nil {
+		git.CommitTime = commitTime
+	}
+	return git
+}
+
+func runGit(dir string, args ...string) (string, error) {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
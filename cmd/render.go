@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/odpf/optimus/instance"
@@ -18,23 +19,22 @@ import (
 )
 
 var (
-	renderTimeout  = time.Minute * 2
-	templateEngine = instance.NewGoEngine()
+	renderTimeout = time.Minute * 2
 )
 
-func renderCommand(l logger, host string, jobSpecRepo JobSpecRepository) *cli.Command {
+func renderCommand(l logger, host string, projectConfig map[string]string, jobSpecRepo JobSpecRepository) *cli.Command {
 	cmd := &cli.Command{
 		Use:   "render",
 		Short: "convert raw representation of specification to consumables",
 	}
 	if jobSpecRepo != nil {
-		cmd.AddCommand(renderTemplateCommand(l, jobSpecRepo))
+		cmd.AddCommand(renderTemplateCommand(l, projectConfig, jobSpecRepo))
 	}
 	cmd.AddCommand(renderJobCommand(l, host))
 	return cmd
 }
 
-func renderTemplateCommand(l logger, jobSpecRepo JobSpecRepository) *cli.Command {
+func renderTemplateCommand(l logger, projectConfig map[string]string, jobSpecRepo JobSpecRepository) *cli.Command {
 	cmd := &cli.Command{
 		Use:     "template",
 		Short:   "render templates for a job to current 'render' directory",
@@ -64,7 +64,24 @@ func renderTemplateCommand(l logger, jobSpecRepo JobSpecRepository) *cli.Command
 		now := time.Now()
 		l.Println("assuming execution time as current time of", now.Format(models.InstanceScheduledAtTimeLayout))
 
-		templates, err := instance.DumpAssets(jobSpec, now, templateEngine, true)
+		variables, err := readProjectVariables()
+		if err != nil {
+			return err
+		}
+		// assets are rendered like the server does for projects with the same
+		// TEMPLATE_ENGINE config, the server upper cases registered keys
+		config := map[string]string{}
+		for key, value := range projectConfig {
+			config[strings.ToUpper(key)] = value
+		}
+		engine, err := instance.ProjectTemplateEngine(models.ProjectSpec{
+			Config:    config,
+			Variables: variables,
+		}, instance.NewGoEngine())
+		if err != nil {
+			return err
+		}
+		templates, err := instance.DumpAssets(jobSpec, variables, now, engine, true)
 		if err != nil {
 			return err
 		}
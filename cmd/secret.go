@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/config"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	secretTimeout = time.Second * 10
+)
+
+func secretCommand(l logger, conf config.Provider) *cli.Command {
+	cmd := &cli.Command{
+		Use:   "secret",
+		Short: "Manage secrets of a project",
+	}
+	cmd.AddCommand(secretListSubCommand(l, conf))
+	return cmd
+}
+
+func secretListSubCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		projectName string
+		revealName  string
+	)
+	cmd := &cli.Command{
+		Use:     "list",
+		Short:   "List secrets registered in a project",
+		Example: "optimus secret list --project project-id --reveal SECRET_NAME",
+		Long: `
+Lists names of the secrets registered in a project. Value of a single secret
+can be read back with --reveal after typing the confirmation phrase configured
+on the server, reveals are audit logged and limited to 5 an hour.
+		`,
+	}
+	cmd.Flags().StringVarP(&projectName, "project", "p", "", "project name of optimus managed repository")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&revealName, "reveal", "", "name of the secret to reveal the value of")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		var confirmationPhrase string
+		if revealName != "" {
+			// the phrase is asked before connecting so it isn't typed
+			// against a timed out connection
+			if err := survey.AskOne(&survey.Password{
+				Message: "Type the confirmation phrase to reveal " + revealName + ":",
+			}, &confirmationPhrase); err != nil {
+				return err
+			}
+		}
+
+		dialTimeoutCtx, dialCancel := context.WithTimeout(context.Background(), OptimusDialTimeout)
+		defer dialCancel()
+
+		conn, err := createConnection(dialTimeoutCtx, conf.GetHost())
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				l.Println("can't reach optimus service, timing out")
+			}
+			return err
+		}
+		defer conn.Close()
+
+		timeoutCtx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+		defer cancel()
+
+		runtime := pb.NewRuntimeServiceClient(conn)
+		if revealName != "" {
+			return revealSecret(timeoutCtx, l, runtime, projectName, revealName, confirmationPhrase)
+		}
+
+		listResponse, err := runtime.ListSecrets(timeoutCtx, &pb.ListSecretsRequest{
+			ProjectName: projectName,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to list secrets of project %s", projectName)
+		}
+		for _, secretName := range listResponse.GetSecretNames() {
+			l.Println(secretName)
+		}
+		return nil
+	}
+	return cmd
+}
+
+func revealSecret(ctx context.Context, l logger, runtime pb.RuntimeServiceClient, projectName, secretName,
+	confirmationPhrase string) error {
+	revealResponse, err := runtime.RevealProjectSecret(ctx, &pb.RevealProjectSecretRequest{
+		ProjectName:        projectName,
+		SecretName:         secretName,
+		ConfirmationPhrase: confirmationPhrase,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to reveal secret %s", secretName)
+	}
+	value, err := base64.StdEncoding.DecodeString(revealResponse.GetValue())
+	if err != nil {
+		return errors.Wrapf(err, "failed to decode secret %s", secretName)
+	}
+	l.Printf("%s=%s\n", secretName, value)
+	return nil
+}
@@ -1,19 +1,50 @@
 package cmd
 
 import (
+	"encoding/json"
+	"net/http"
+	"os"
+
 	"github.com/odpf/optimus/cmd/server"
 	"github.com/odpf/optimus/config"
 
 	cli "github.com/spf13/cobra"
 )
 
+// serveFlagsConfig overrides server configuration with flags of serve
+type serveFlagsConfig struct {
+	config.Provider
+	embedDocs bool
+}
+
+func (c serveFlagsConfig) GetServe() config.ServerConfig {
+	serve := c.Provider.GetServe()
+	serve.EmbedJobDocs = serve.EmbedJobDocs || c.embedDocs
+	return serve
+}
+
 func optimusServeCommand(l logger, conf config.Provider) *cli.Command {
+	var dryRun, embedDocs bool
 	c := &cli.Command{
 		Use:   "serve",
 		Short: "Starts optimus service",
 		RunE: func(c *cli.Command, args []string) error {
-			return server.Initialize(conf)
+			conf := serveFlagsConfig{Provider: conf, embedDocs: embedDocs}
+			if dryRun {
+				report, err := server.DryRun(conf, &http.Client{})
+				out, jsonErr := json.MarshalIndent(report, "", "  ")
+				if jsonErr != nil {
+					return jsonErr
+				}
+				l.Println(string(out))
+				return err
+			}
+			return server.Initialize(conf, func() (config.Provider, error) {
+				return config.InitOptimus(config.LookupFile(os.Args[1:]))
+			})
 		},
 	}
+	c.Flags().BoolVar(&dryRun, "dry-run", dryRun, "validate configuration and dependencies without starting the server")
+	c.Flags().BoolVar(&embedDocs, "embed-docs", embedDocs, "embed description, owner, labels and dependencies of jobs as a docstring in compiled dags")
 	return c
 }
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/odpf/optimus/api/middleware"
+	"github.com/odpf/optimus/config"
+	"github.com/pkg/errors"
+)
+
+const jwksFetchTimeout = 10 * time.Second
+
+// newJWTAuthenticator verifies tokens with the keys of the jwks url,
+// the static public key file is only used if no url is configured
+func newJWTAuthenticator(conf config.AuthConfig) (*middleware.JWTAuthenticator, error) {
+	var keys middleware.KeyProvider
+	if conf.JWKSURL != "" {
+		keys = middleware.NewJWKSKeyProvider(conf.JWKSURL, &http.Client{Timeout: jwksFetchTimeout})
+	} else {
+		publicKey, err := ioutil.ReadFile(conf.PublicKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read auth public key")
+		}
+		if keys, err = middleware.NewStaticKeyProvider(publicKey); err != nil {
+			return nil, err
+		}
+	}
+	return middleware.NewJWTAuthenticator(keys, conf.Issuer, conf.Audience), nil
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+// projectBootstrapTimeout is how long the scheduler of a single project is
+// given to bootstrap
+const projectBootstrapTimeout = 10 * time.Second
+
+// projectBootstrapper prepares the scheduler of every registered project when
+// the server starts, projects are bootstrapped in parallel and a project
+// failing to bootstrap is logged without stopping the others
+type projectBootstrapper struct {
+	scheduler    models.SchedulerUnit
+	versionCheck *schedulerVersionChecker
+	pauseRepo    store.JobPauseRepository
+	concurrency  int
+	log          logrus.FieldLogger
+}
+
+// Run bootstraps all projects and returns once all of them are done
+func (b *projectBootstrapper) Run(projects []models.ProjectSpec) {
+	concurrency := b.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var group errgroup.Group
+	group.SetLimit(concurrency)
+	for _, proj := range projects {
+		proj := proj
+		group.Go(func() error {
+			b.bootstrap(proj)
+			return nil
+		})
+	}
+	_ = group.Wait()
+}
+
+func (b *projectBootstrapper) bootstrap(proj models.ProjectSpec) {
+	log := b.log.WithField("project", proj.Name)
+	defer func() {
+		// other projects might be working fine though
+		if r := recover(); r != nil {
+			log.Error(fmt.Sprintf("panic while bootstrapping project: %v", r))
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), projectBootstrapTimeout)
+	defer cancel()
+
+	log.Info("bootstrapping project")
+	if err := b.scheduler.Bootstrap(ctx, proj); err != nil {
+		// Major ERROR, but we can't make this fatal
+		log.WithError(err).Error("failed to bootstrap project")
+	}
+	if b.versionCheck != nil {
+		if err := b.versionCheck.Check(ctx, proj); err != nil {
+			log.Warn(err)
+		}
+	}
+	if b.pauseRepo != nil {
+		// jobs paused through optimus stay paused if the scheduler lost the state
+		pausedJobs, err := b.pauseRepo.GetPaused(ctx, proj.Name)
+		if err != nil {
+			log.WithError(err).Warn("failed to list paused jobs")
+		}
+		for _, jobName := range pausedJobs {
+			if err := b.scheduler.SetJobPaused(ctx, proj, jobName, true); err != nil {
+				log.WithError(err).Warnf("failed to pause job %s", jobName)
+			}
+		}
+	}
+	log.Info("bootstrapped project")
+}
+
+func newProjectBootstrapper(scheduler models.SchedulerUnit, versionCheck *schedulerVersionChecker,
+	pauseRepo store.JobPauseRepository, concurrency int, log logrus.FieldLogger) *projectBootstrapper {
+	return &projectBootstrapper{
+		scheduler:    scheduler,
+		versionCheck: versionCheck,
+		pauseRepo:    pauseRepo,
+		concurrency:  concurrency,
+		log:          log,
+	}
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+// concurrentScheduler records how many projects are bootstrapped at once
+type concurrentScheduler struct {
+	*mock.Scheduler
+
+	mu           sync.Mutex
+	running      int
+	maxRunning   int
+	bootstrapped []string
+}
+
+func (s *concurrentScheduler) Bootstrap(ctx context.Context, proj models.ProjectSpec) error {
+	s.mu.Lock()
+	s.running++
+	if s.running > s.maxRunning {
+		s.maxRunning = s.running
+	}
+	s.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	s.mu.Lock()
+	s.running--
+	s.bootstrapped = append(s.bootstrapped, proj.Name)
+	s.mu.Unlock()
+	return nil
+}
+
+func TestProjectBootstrapper(t *testing.T) {
+	t.Run("should bootstrap projects in parallel up to the concurrency", func(t *testing.T) {
+		var projects []models.ProjectSpec
+		for idx := 0; idx < 6; idx++ {
+			projects = append(projects, models.ProjectSpec{Name: fmt.Sprintf("project-%d", idx)})
+		}
+		scheduler := &concurrentScheduler{Scheduler: new(mock.Scheduler)}
+		logger, _ := logrustest.NewNullLogger()
+
+		newProjectBootstrapper(scheduler, nil, nil, 2, logger).Run(projects)
+		assert.Equal(t, 6, len(scheduler.bootstrapped))
+		assert.Equal(t, 2, scheduler.maxRunning)
+	})
+	t.Run("should log projects failing to bootstrap and bootstrap the others", func(t *testing.T) {
+		projects := []models.ProjectSpec{{Name: "failing-project"}, {Name: "panicking-project"}, {Name: "a-data-project"}}
+		scheduler := new(mock.Scheduler)
+		scheduler.On("Bootstrap", mock2.Anything, projects[0]).Return(errors.New("bucket not found"))
+		scheduler.On("Bootstrap", mock2.Anything, projects[1]).Run(func(args mock2.Arguments) {
+			panic("nil pointer dereference")
+		})
+		scheduler.On("Bootstrap", mock2.Anything, projects[2]).Return(nil)
+		defer scheduler.AssertExpectations(t)
+		logger, hook := logrustest.NewNullLogger()
+
+		newProjectBootstrapper(scheduler, nil, nil, 5, logger).Run(projects)
+		var failedProjects []string
+		for _, entry := range hook.AllEntries() {
+			if entry.Level == logrus.ErrorLevel {
+				failedProjects = append(failedProjects, entry.Data["project"].(string))
+			}
+		}
+		assert.ElementsMatch(t, []string{"failing-project", "panicking-project"}, failedProjects)
+	})
+	t.Run("should pause the jobs recorded as paused", func(t *testing.T) {
+		proj := models.ProjectSpec{Name: "a-data-project"}
+		scheduler := new(mock.Scheduler)
+		scheduler.On("Bootstrap", mock2.Anything, proj).Return(nil)
+		scheduler.On("SetJobPaused", mock2.Anything, proj, "daily-orders", true).Return(nil)
+		defer scheduler.AssertExpectations(t)
+		pauseRepo := new(mock.JobPauseRepository)
+		pauseRepo.On("GetPaused", mock2.Anything, proj.Name).Return([]string{"daily-orders"}, nil)
+		defer pauseRepo.AssertExpectations(t)
+		logger, _ := logrustest.NewNullLogger()
+
+		newProjectBootstrapper(scheduler, nil, pauseRepo, 5, logger).Run([]models.ProjectSpec{proj})
+	})
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/core/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// connectionPool is the part of sql.DB resized when the config is reloaded
+type connectionPool interface {
+	SetMaxIdleConns(n int)
+	SetMaxOpenConns(n int)
+}
+
+// configReloader reads the config again when the server receives SIGHUP and
+// applies changed db pool sizes and log level. Secrets like the app key and
+// the db dsn are never reloaded, changing them requires a restart
+type configReloader struct {
+	load func() (config.Provider, error)
+	// config the server started with, secrets are compared with it
+	started config.Provider
+	current config.Provider
+	pool    connectionPool
+	logger  *logrus.Logger
+	log     logrus.FieldLogger
+}
+
+// Start reloads the config on SIGHUP till ctx is done
+func (r *configReloader) Start(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				r.log.Info("reloading config")
+				if err := r.Reload(); err != nil {
+					r.log.WithError(err).Error("failed to reload config, keeping the current one")
+				}
+			}
+		}
+	}()
+}
+
+// Reload applies the changes of the config loaded again
+func (r *configReloader) Reload() error {
+	conf, err := r.load()
+	if err != nil {
+		return err
+	}
+	oldDB, newDB := r.current.GetServe().DB, conf.GetServe().DB
+	if newDB.MaxIdleConnection != oldDB.MaxIdleConnection {
+		r.pool.SetMaxIdleConns(newDB.MaxIdleConnection)
+		r.log.Infof("%s changed from %d to %d", config.KeyServeDBMaxIdleConnection,
+			oldDB.MaxIdleConnection, newDB.MaxIdleConnection)
+	}
+	if newDB.MaxOpenConnection != oldDB.MaxOpenConnection {
+		r.pool.SetMaxOpenConns(newDB.MaxOpenConnection)
+		r.log.Infof("%s changed from %d to %d", config.KeyServeDBMaxOpenConnection,
+			oldDB.MaxOpenConnection, newDB.MaxOpenConnection)
+	}
+	if oldLevel, newLevel := r.current.GetLog().Level, conf.GetLog().Level; newLevel != oldLevel {
+		level, err := logrus.ParseLevel(newLevel)
+		if err != nil {
+			r.log.WithError(err).Warnf("keeping log level %s", oldLevel)
+		} else {
+			r.logger.SetLevel(level)
+			logger.SetLevel(level)
+			r.log.Infof("%s changed from %s to %s", config.KeyLogLevel, oldLevel, newLevel)
+		}
+	}
+
+	// values of secrets are not logged
+	if conf.GetServe().AppKey != r.started.GetServe().AppKey ||
+		conf.GetServe().PreviousAppKey != r.started.GetServe().PreviousAppKey {
+		r.log.Warn("app keys changed, restart the server to use them")
+	}
+	if newDB.DSN != r.started.GetServe().DB.DSN {
+		r.log.Warnf("%s changed, restart the server to use it", config.KeyServeDBDSN)
+	}
+
+	r.current = conf
+	return nil
+}
+
+func newConfigReloader(load func() (config.Provider, error), current config.Provider, pool connectionPool,
+	logger *logrus.Logger, log logrus.FieldLogger) *configReloader {
+	return &configReloader{
+		load:    load,
+		started: current,
+		current: current,
+		pool:    pool,
+		logger:  logger,
+		log:     log,
+	}
+}
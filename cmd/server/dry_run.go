@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store/postgres"
+	"github.com/pkg/errors"
+)
+
+var ErrDryRunFailed = errors.New("dry run failed")
+
+// DryRunCheck is the outcome of a single initialization step
+type DryRunCheck struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+// DryRunReport collects the outcome of every initialization step executed
+// during a dry run
+type DryRunReport struct {
+	Checks []DryRunCheck `json:"checks"`
+}
+
+func (r *DryRunReport) record(name string, err error) bool {
+	check := DryRunCheck{Name: name}
+	if err != nil {
+		check.Error = err.Error()
+	}
+	r.Checks = append(r.Checks, check)
+	return err == nil
+}
+
+// Failed returns true if any of the checks failed
+func (r DryRunReport) Failed() bool {
+	for _, check := range r.Checks {
+		if check.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// DryRun executes the initialization steps of the server without migrating
+// the database or binding to a port, project bootstrap is simulated by only
+// validating the project storage configs. ErrDryRunFailed is returned if any
+// of the steps failed, the report has the details of each step.
+func DryRun(conf config.Provider, httpClient *http.Client) (DryRunReport, error) {
+	report := DryRunReport{}
+	if !report.record("config", checkRequiredConfigs(conf)) {
+		return report, ErrDryRunFailed
+	}
+
+	appHash, err := models.NewApplicationSecret(conf.GetServe().AppKey)
+	if err == nil && conf.GetServe().PreviousAppKey != "" {
+		var prevHash models.ApplicationKey
+		prevHash, err = models.NewApplicationSecret(conf.GetServe().PreviousAppKey)
+		appHash = appHash.WithPrevious(prevHash)
+	}
+	appKeyValid := report.record("app_key", err)
+
+	scheduler, err := initScheduler(conf.GetScheduler().Name, conf.GetServe().FileStorageRoot)
+	schedulerValid := report.record("scheduler", err)
+
+	dbConn, err := postgres.Connect(conf.GetServe().DB.DSN, conf.GetServe().DB.MaxIdleConnection, conf.GetServe().DB.MaxOpenConnection,
+		conf.GetServe().DB.PoolerMode)
+	if !report.record("database", err) || !appKeyValid {
+		return report, ErrDryRunFailed
+	}
+	defer dbConn.Close()
+
+	projectRepoFac := &projectRepoFactory{
+		db:   dbConn,
+		hash: appHash,
+	}
+	registeredProjects, err := projectRepoFac.New().GetAll()
+	if !report.record("projects", err) {
+		return report, ErrDryRunFailed
+	}
+	for _, proj := range registeredProjects {
+		report.record(fmt.Sprintf("bootstrap/%s", proj.Name), simulateBootstrap(proj, conf.GetServe().FileStorageRoot))
+		if schedulerValid {
+			report.record(fmt.Sprintf("scheduler_health/%s", proj.Name), checkSchedulerHealth(httpClient, scheduler, proj))
+		}
+	}
+
+	if report.Failed() {
+		return report, ErrDryRunFailed
+	}
+	return report, nil
+}
+
+// simulateBootstrap validates the storage configs used by the scheduler to
+// bootstrap a project without writing anything to the storage
+func simulateBootstrap(proj models.ProjectSpec, fileRoot string) error {
+	storagePath, ok := proj.Config[models.ProjectStoragePathKey]
+	if !ok {
+		return errors.Errorf("%s config not configured for project %s", models.ProjectStoragePathKey, proj.Name)
+	}
+	storageSecret, ok := proj.Secret.GetByName(models.ProjectSecretStorageKey)
+	if !ok {
+		return errors.Errorf("%s secret not configured for project %s", models.ProjectSecretStorageKey, proj.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	if _, err := (&objectWriterFactory{fileRoot: fileRoot}).New(ctx, storagePath, storageSecret); err != nil {
+		return errors.Wrapf(err, "object writer failed for %s", proj.Name)
+	}
+	return nil
+}
+
+// checkSchedulerHealth calls the health endpoint of the scheduler configured
+// for the project
+func checkSchedulerHealth(httpClient *http.Client, scheduler models.SchedulerUnit, proj models.ProjectSpec) error {
+	schdHost, ok := proj.Config[models.ProjectSchedulerHost]
+	if !ok {
+		return errors.Errorf("scheduler host not set for %s", proj.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	healthURL := fmt.Sprintf("%s/health", strings.TrimRight(schdHost, "/"))
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to build http request for %s", healthURL)
+	}
+	resp, err := httpClient.Do(request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to reach %s scheduler at %s", scheduler.GetName(), healthURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("%s scheduler at %s is unhealthy: %d", scheduler.GetName(), healthURL, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/odpf/optimus/core/progress"
+	"github.com/sirupsen/logrus"
+)
+
+// gracefulStopWarnInterval is how often the calls still being served are
+// reported while the grpc server stops
+const gracefulStopWarnInterval = 5 * time.Second
+
+// the server waits at least minShutdownWait and at most maxShutdownWait for
+// the calls being served on shutdown
+const (
+	minShutdownWait = 5 * time.Second
+	maxShutdownWait = 300 * time.Second
+)
+
+// shutdownWaitFromSeconds bounds the configured shutdown wait, values out of
+// range are logged and replaced by the closest bound
+func shutdownWaitFromSeconds(seconds int, log logrus.FieldLogger) time.Duration {
+	wait := time.Duration(seconds) * time.Second
+	switch {
+	case wait < minShutdownWait:
+		log.Warnf("shutdown wait of %s is too short, waiting %s", wait, minShutdownWait)
+		return minShutdownWait
+	case wait > maxShutdownWait:
+		log.Warnf("shutdown wait of %s is too long, waiting %s", wait, maxShutdownWait)
+		return maxShutdownWait
+	}
+	return wait
+}
+
+// stoppableServer is the part of grpc.Server used to stop it
+type stoppableServer interface {
+	GracefulStop()
+	Stop()
+}
+
+// gracefulStopper stops a grpc server once the calls being served finish,
+// calls that don't finish within timeout are reported and, if forceStop is
+// set, cancelled. Without forceStop the server keeps waiting for them
+type gracefulStopper struct {
+	server       stoppableServer
+	timeout      time.Duration
+	warnInterval time.Duration
+	forceStop    bool
+	log          logrus.FieldLogger
+	observer     progress.Observer
+	host         string
+}
+
+// Stop blocks till the server is stopped
+func (s *gracefulStopper) Stop() {
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	startedAt := time.Now()
+	warnTicker := time.NewTicker(s.warnInterval)
+	defer warnTicker.Stop()
+	deadline := time.NewTimer(s.timeout)
+	defer deadline.Stop()
+	for {
+		select {
+		case <-stopped:
+			return
+		case <-warnTicker.C:
+			s.log.Warnf("waiting %s for grpc calls to finish", time.Since(startedAt).Round(time.Second))
+		case <-deadline.C:
+			s.log.Errorf("grpc calls didn't finish within %s", s.timeout)
+			s.observer.Notify(&EventGracefulStopTimeout{
+				Host:         s.host,
+				Timeout:      s.timeout,
+				ForceStopped: s.forceStop,
+			})
+			if s.forceStop {
+				// closes the connections, GracefulStop returns with them
+				s.server.Stop()
+			}
+		}
+	}
+}
+
+func newGracefulStopper(server stoppableServer, timeout time.Duration, forceStop bool, log logrus.FieldLogger,
+	observer progress.Observer, host string) *gracefulStopper {
+	return &gracefulStopper{
+		server:       server,
+		timeout:      timeout,
+		warnInterval: gracefulStopWarnInterval,
+		forceStop:    forceStop,
+		log:          log,
+		observer:     observer,
+		host:         host,
+	}
+}
+
+// EventGracefulStopTimeout signifies that grpc calls being served didn't
+// finish within the time the server waits for them while shutting down
+type EventGracefulStopTimeout struct {
+	Host         string
+	Timeout      time.Duration
+	ForceStopped bool
+}
+
+func (e *EventGracefulStopTimeout) String() string {
+	if e.ForceStopped {
+		return fmt.Sprintf("grpc calls on %s didn't finish within %s, they are cancelled", e.Host, e.Timeout)
+	}
+	return fmt.Sprintf("grpc calls on %s didn't finish within %s, still waiting for them", e.Host, e.Timeout)
+}
+
+// IncidentKey pages for the timeout with the server routing key of the
+// pagerduty notifier, once per host
+func (e *EventGracefulStopTimeout) IncidentKey() string {
+	return fmt.Sprintf("optimus/server/%s/graceful-stop-timeout", e.Host)
+}
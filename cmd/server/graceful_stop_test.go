@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/mock"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	testMock "github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// blockingServiceDesc has a streaming method that doesn't return till its
+// call is cancelled
+var blockingServiceDesc = grpc.ServiceDesc{
+	ServiceName: "optimus.test.Blocking",
+	HandlerType: (*interface{})(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName: "Block",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				<-stream.Context().Done()
+				return stream.Context().Err()
+			},
+			ServerStreams: true,
+		},
+	},
+}
+
+func TestGracefulStopper(t *testing.T) {
+	// serves a call that refuses to terminate, the returned func ends it
+	startBlockingCall := func(t *testing.T) (*grpc.Server, context.CancelFunc) {
+		listener := bufconn.Listen(1024 * 1024)
+		server := grpc.NewServer()
+		server.RegisterService(&blockingServiceDesc, struct{}{})
+		go server.Serve(listener)
+
+		conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(
+			func(ctx context.Context, s string) (net.Conn, error) {
+				return listener.Dial()
+			}))
+		assert.Nil(t, err)
+		t.Cleanup(func() { conn.Close() })
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stream, err := conn.NewStream(ctx, &blockingServiceDesc.Streams[0], "/optimus.test.Blocking/Block")
+		assert.Nil(t, err)
+		assert.Nil(t, stream.CloseSend())
+		// the call is being served once its headers are received
+		go stream.RecvMsg(new(struct{}))
+		time.Sleep(time.Millisecond * 50)
+		return server, cancel
+	}
+
+	t.Run("should stop right away when no calls are being served", func(t *testing.T) {
+		observer := new(mock.PipelineLogObserver)
+		logger, hook := logrustest.NewNullLogger()
+		stopper := newGracefulStopper(grpc.NewServer(), time.Second, true, logger, observer, "host-1")
+
+		stopper.Stop()
+		assert.Empty(t, hook.Entries)
+		observer.AssertNotCalled(t, "Notify", testMock.Anything)
+	})
+	t.Run("should warn while waiting and force stop calls not finishing in time", func(t *testing.T) {
+		server, cancel := startBlockingCall(t)
+		defer cancel()
+		observer := new(mock.PipelineLogObserver)
+		observer.On("Notify", &EventGracefulStopTimeout{
+			Host:         "host-1",
+			Timeout:      time.Millisecond * 200,
+			ForceStopped: true,
+		}).Once()
+		defer observer.AssertExpectations(t)
+		logger, hook := logrustest.NewNullLogger()
+		stopper := newGracefulStopper(server, time.Millisecond*200, true, logger, observer, "host-1")
+		stopper.warnInterval = time.Millisecond * 50
+
+		stopped := make(chan struct{})
+		go func() {
+			stopper.Stop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(time.Second * 5):
+			t.Fatal("server wasn't stopped")
+		}
+		assert.Equal(t, logrus.WarnLevel, hook.Entries[0].Level)
+		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+		assert.Equal(t, "grpc calls didn't finish within 200ms", hook.LastEntry().Message)
+	})
+	t.Run("should keep waiting for calls when not forced to stop", func(t *testing.T) {
+		server, cancel := startBlockingCall(t)
+		defer cancel()
+		observer := new(mock.PipelineLogObserver)
+		observer.On("Notify", &EventGracefulStopTimeout{
+			Host:    "host-1",
+			Timeout: time.Millisecond * 100,
+		}).Once()
+		defer observer.AssertExpectations(t)
+		logger, _ := logrustest.NewNullLogger()
+		stopper := newGracefulStopper(server, time.Millisecond*100, false, logger, observer, "host-1")
+		stopper.warnInterval = time.Millisecond * 50
+
+		stopped := make(chan struct{})
+		go func() {
+			stopper.Stop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+			t.Fatal("server stopped with a call being served")
+		case <-time.After(time.Millisecond * 300):
+		}
+
+		cancel()
+		select {
+		case <-stopped:
+		case <-time.After(time.Second * 5):
+			t.Fatal("server wasn't stopped once the call finished")
+		}
+	})
+}
+
+func TestShutdownWaitFromSeconds(t *testing.T) {
+	t.Run("should wait the configured seconds", func(t *testing.T) {
+		logger, hook := logrustest.NewNullLogger()
+		assert.Equal(t, time.Minute, shutdownWaitFromSeconds(60, logger))
+		assert.Empty(t, hook.AllEntries())
+	})
+	t.Run("should bound waits out of range", func(t *testing.T) {
+		logger, hook := logrustest.NewNullLogger()
+		assert.Equal(t, minShutdownWait, shutdownWaitFromSeconds(0, logger))
+		assert.Equal(t, maxShutdownWait, shutdownWaitFromSeconds(3600, logger))
+		assert.Equal(t, 2, len(hook.AllEntries()))
+	})
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/go-version"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+// schedulerVersionChecker verifies the scheduler of a project runs at least
+// the minimum version compiled jobs are compatible with. Projects found
+// compatible are not checked again, incompatible ones are checked on every
+// call so upgrading the scheduler unblocks them without a restart.
+type schedulerVersionChecker struct {
+	scheduler models.SchedulerUnit
+	minimum   *version.Version
+
+	mu         sync.Mutex
+	compatible map[string]bool
+}
+
+// Check fails if the scheduler version of the project is older than the
+// minimum, schedulers not exposing their version are assumed compatible
+func (c *schedulerVersionChecker) Check(ctx context.Context, proj models.ProjectSpec) error {
+	c.mu.Lock()
+	checked := c.compatible[proj.Name]
+	c.mu.Unlock()
+	if checked {
+		return nil
+	}
+
+	schedulerVersion, err := c.scheduler.GetSchedulerVersion(ctx, proj)
+	if err != nil {
+		if errors.Is(err, models.ErrUnsupportedSchedulerOperation) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to fetch scheduler version of project %s", proj.Name)
+	}
+	current, err := version.NewVersion(schedulerVersion)
+	if err != nil {
+		return errors.Wrapf(err, "invalid scheduler version %s of project %s", schedulerVersion, proj.Name)
+	}
+	if current.LessThan(c.minimum) {
+		return errors.Errorf("scheduler version %s of project %s is older than the minimum supported version %s",
+			current, proj.Name, c.minimum)
+	}
+
+	c.mu.Lock()
+	c.compatible[proj.Name] = true
+	c.mu.Unlock()
+	return nil
+}
+
+func newSchedulerVersionChecker(scheduler models.SchedulerUnit, minimumVersion string) (*schedulerVersionChecker, error) {
+	minimum, err := version.NewVersion(minimumVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid minimum scheduler version %s", minimumVersion)
+	}
+	return &schedulerVersionChecker{
+		scheduler:  scheduler,
+		minimum:    minimum,
+		compatible: map[string]bool{},
+	}, nil
+}
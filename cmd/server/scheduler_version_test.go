@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerVersionChecker(t *testing.T) {
+	ctx := context.Background()
+	projectSpec := models.ProjectSpec{Name: "a-data-project"}
+
+	t.Run("should pass if scheduler version meets the minimum", func(t *testing.T) {
+		scheduler := new(mock.Scheduler)
+		scheduler.On("GetSchedulerVersion", ctx, projectSpec).Return("2.1.2", nil).Once()
+		defer scheduler.AssertExpectations(t)
+		checker, err := newSchedulerVersionChecker(scheduler, "2.1.0")
+		assert.Nil(t, err)
+
+		assert.Nil(t, checker.Check(ctx, projectSpec))
+		// compatible projects are not checked again
+		assert.Nil(t, checker.Check(ctx, projectSpec))
+	})
+	t.Run("should fail if scheduler version is older than the minimum until upgraded", func(t *testing.T) {
+		scheduler := new(mock.Scheduler)
+		scheduler.On("GetSchedulerVersion", ctx, projectSpec).Return("2.0.2", nil).Twice()
+		scheduler.On("GetSchedulerVersion", ctx, projectSpec).Return("2.1.0", nil).Once()
+		defer scheduler.AssertExpectations(t)
+		checker, err := newSchedulerVersionChecker(scheduler, "2.1.0")
+		assert.Nil(t, err)
+
+		err = checker.Check(ctx, projectSpec)
+		assert.Equal(t, "scheduler version 2.0.2 of project a-data-project is older than the minimum supported version 2.1.0", err.Error())
+		assert.NotNil(t, checker.Check(ctx, projectSpec))
+		assert.Nil(t, checker.Check(ctx, projectSpec))
+	})
+	t.Run("should pass if scheduler doesn't expose its version", func(t *testing.T) {
+		scheduler := new(mock.Scheduler)
+		scheduler.On("GetSchedulerVersion", ctx, projectSpec).Return("", errors.Wrap(models.ErrUnsupportedSchedulerOperation, "fetching version"))
+		checker, err := newSchedulerVersionChecker(scheduler, "1.10.0")
+		assert.Nil(t, err)
+
+		assert.Nil(t, checker.Check(ctx, projectSpec))
+	})
+	t.Run("should fail if scheduler version can't be fetched", func(t *testing.T) {
+		scheduler := new(mock.Scheduler)
+		scheduler.On("GetSchedulerVersion", ctx, projectSpec).Return("", errors.New("connection refused"))
+		checker, err := newSchedulerVersionChecker(scheduler, "2.1.0")
+		assert.Nil(t, err)
+
+		assert.NotNil(t, checker.Check(ctx, projectSpec))
+	})
+	t.Run("should fail if minimum version is invalid", func(t *testing.T) {
+		_, err := newSchedulerVersionChecker(new(mock.Scheduler), "two")
+		assert.NotNil(t, err)
+	})
+}
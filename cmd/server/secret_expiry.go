@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/odpf/optimus/store"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// secretExpiryWarningWindow is how long before their expiry secrets are
+	// reported
+	secretExpiryWarningWindow = 7 * 24 * time.Hour
+
+	// secretExpiryCheckInterval is how often secrets close to their expiry
+	// are looked up
+	secretExpiryCheckInterval = time.Hour
+)
+
+// secretExpiryMonitor warns about secrets of all projects expiring within
+// the warning window, including the ones already expired, so they can be
+// rotated before jobs fail to read them
+type secretExpiryMonitor struct {
+	lister store.SecretExpiryLister
+	log    logrus.FieldLogger
+	now    func() time.Time
+}
+
+// Run checks secrets once right away and then on every interval until ctx
+// is cancelled
+func (m *secretExpiryMonitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.check()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *secretExpiryMonitor) check() {
+	now := m.now()
+	secrets, err := m.lister.GetExpiring(now.Add(secretExpiryWarningWindow))
+	if err != nil {
+		m.log.WithError(err).Error("failed to check expiring secrets")
+		return
+	}
+	for _, secret := range secrets {
+		msg := "secret is expiring soon"
+		if !secret.ExpiresAt.After(now) {
+			msg = "secret has expired"
+		}
+		m.log.WithFields(logrus.Fields{
+			"project":    secret.ProjectName,
+			"secret":     secret.SecretName,
+			"expires_at": secret.ExpiresAt.UTC().Format(time.RFC3339),
+		}).Warn(msg)
+	}
+}
+
+func newSecretExpiryMonitor(lister store.SecretExpiryLister, log logrus.FieldLogger) *secretExpiryMonitor {
+	return &secretExpiryMonitor{
+		lister: lister,
+		log:    log,
+		now:    time.Now,
+	}
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretExpiryMonitor(t *testing.T) {
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("should warn about secrets expiring within the window", func(t *testing.T) {
+		lister := new(mock.SecretExpiryLister)
+		lister.On("GetExpiring", now.Add(7*24*time.Hour)).Return([]models.ExpiringProjectSecret{
+			{ProjectName: "a-data-project", SecretName: "old_token", ExpiresAt: now.Add(-time.Hour)},
+			{ProjectName: "a-data-project", SecretName: "bq_service_account", ExpiresAt: now.Add(48 * time.Hour)},
+		}, nil)
+		defer lister.AssertExpectations(t)
+		logger, hook := logrustest.NewNullLogger()
+		monitor := newSecretExpiryMonitor(lister, logger)
+		monitor.now = func() time.Time { return now }
+
+		monitor.check()
+		assert.Equal(t, 2, len(hook.Entries))
+		assert.Equal(t, logrus.WarnLevel, hook.Entries[0].Level)
+		assert.Equal(t, "secret has expired", hook.Entries[0].Message)
+		assert.Equal(t, "old_token", hook.Entries[0].Data["secret"])
+		assert.Equal(t, "secret is expiring soon", hook.Entries[1].Message)
+		assert.Equal(t, logrus.Fields{
+			"project":    "a-data-project",
+			"secret":     "bq_service_account",
+			"expires_at": "2021-03-03T10:00:00Z",
+		}, hook.Entries[1].Data)
+	})
+	t.Run("should log an error if secrets can't be listed", func(t *testing.T) {
+		lister := new(mock.SecretExpiryLister)
+		lister.On("GetExpiring", now.Add(7*24*time.Hour)).Return([]models.ExpiringProjectSecret{}, errors.New("connection refused"))
+		defer lister.AssertExpectations(t)
+		logger, hook := logrustest.NewNullLogger()
+		monitor := newSecretExpiryMonitor(lister, logger)
+		monitor.now = func() time.Time { return now }
+
+		monitor.check()
+		assert.Equal(t, 1, len(hook.Entries))
+		assert.Equal(t, logrus.ErrorLevel, hook.Entries[0].Level)
+	})
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -14,7 +15,9 @@ import (
 
 	"github.com/hashicorp/go-multierror"
 
+	"github.com/odpf/optimus/ext/notify/pagerduty"
 	"github.com/odpf/optimus/ext/notify/slack"
+	"github.com/odpf/optimus/ext/notify/webhook"
 
 	"github.com/odpf/optimus/utils"
 
@@ -31,19 +34,28 @@ import (
 	"cloud.google.com/go/storage"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	grpctags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 	slackapi "github.com/slack-go/slack"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 
 	v1 "github.com/odpf/optimus/api/handler/v1"
 	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/api/middleware"
 	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
 	"github.com/odpf/optimus/core/logger"
 	"github.com/odpf/optimus/core/progress"
@@ -54,17 +66,22 @@ import (
 	"github.com/odpf/optimus/models"
 	_ "github.com/odpf/optimus/plugin"
 	"github.com/odpf/optimus/store"
+	"github.com/odpf/optimus/store/azure"
+	"github.com/odpf/optimus/store/cache"
+	"github.com/odpf/optimus/store/file"
 	"github.com/odpf/optimus/store/gcs"
 	"github.com/odpf/optimus/store/postgres"
+	"github.com/odpf/optimus/store/s3"
 )
 
 var (
 	//listen for sigterm
 	termChan = make(chan os.Signal, 1)
 
-	shutdownWait = 30 * time.Second
-
 	GRPCMaxRecvMsgSize = 45 << 20 // 45MB
+
+	// secret reveals allowed to each caller per hour
+	secretRevealsPerHour = 5
 )
 
 // projectJobSpecRepoFactory stores raw specifications
@@ -76,6 +93,24 @@ func (fac *projectJobSpecRepoFactory) New(project models.ProjectSpec) store.Proj
 	return postgres.NewProjectJobSpecRepository(fac.db, project, postgres.NewAdapter(models.PluginRegistry))
 }
 
+// dependencyPinRepoFactory stores upstream specs pinned by jobs
+type dependencyPinRepoFactory struct {
+	db *gorm.DB
+}
+
+func (fac *dependencyPinRepoFactory) New(project models.ProjectSpec) store.DependencyPinRepository {
+	return postgres.NewDependencyPinRepository(fac.db, project, postgres.NewAdapter(models.PluginRegistry))
+}
+
+// releaseRepoFactory stores tagged snapshots of job specs
+type releaseRepoFactory struct {
+	db *gorm.DB
+}
+
+func (fac *releaseRepoFactory) New(project models.ProjectSpec) store.ReleaseRepository {
+	return postgres.NewReleaseRepository(fac.db, project, postgres.NewAdapter(models.PluginRegistry))
+}
+
 type replaySpecRepoRepository struct {
 	db             *gorm.DB
 	jobSpecRepoFac jobSpecRepoFactory
@@ -97,6 +132,7 @@ func (fac *jobSpecRepoFactory) New(namespace models.NamespaceSpec) job.SpecRepos
 		namespace,
 		fac.projectJobSpecRepoFac.New(namespace.ProjectSpec),
 		postgres.NewAdapter(models.PluginRegistry),
+		v1.NewAdapter(models.PluginRegistry, models.DatastoreRegistry),
 	)
 }
 
@@ -104,9 +140,20 @@ func (fac *jobSpecRepoFactory) New(namespace models.NamespaceSpec) job.SpecRepos
 // scheduler
 type jobRepoFactory struct {
 	schd models.SchedulerUnit
+
+	// directory file storage paths are confined to, disabled if empty
+	fileRoot string
+
+	// blocks deployment to projects with incompatible scheduler versions if set
+	versionChecker *schedulerVersionChecker
 }
 
 func (fac *jobRepoFactory) New(ctx context.Context, proj models.ProjectSpec) (store.JobRepository, error) {
+	if fac.versionChecker != nil {
+		if err := fac.versionChecker.Check(ctx, proj); err != nil {
+			return nil, errors.Wrap(err, "deployment blocked by strict version check")
+		}
+	}
 	storagePath, ok := proj.Config[models.ProjectStoragePathKey]
 	if !ok {
 		return nil, errors.Errorf("%s not configured for project %s", models.ProjectStoragePathKey, proj.Name)
@@ -127,16 +174,55 @@ func (fac *jobRepoFactory) New(ctx context.Context, proj models.ProjectSpec) (st
 			return nil, errors.Wrap(err, "error creating google storage client")
 		}
 		return gcs.NewJobRepository(p.Hostname(), filepath.Join(p.Path, fac.schd.GetJobsDir()), fac.schd.GetJobsExtension(), storageClient), nil
+	case "s3":
+		bucket, prefix, err := s3.ParseURL(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s of project %s", models.ProjectStoragePathKey, proj.Name)
+		}
+		s3Client, err := s3.NewClient(storageSecret)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating s3 client")
+		}
+		return s3.NewJobRepository(bucket, filepath.Join(prefix, fac.schd.GetJobsDir()), fac.schd.GetJobsExtension(), s3Client), nil
+	case "az", "abfs":
+		container, prefix, err := azure.ParseURL(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s of project %s", models.ProjectStoragePathKey, proj.Name)
+		}
+		serviceURL, err := azure.NewServiceURL(storageSecret)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating azure blob client")
+		}
+		return azure.NewJobRepository(container, filepath.Join(prefix, fac.schd.GetJobsDir()), fac.schd.GetJobsExtension(), serviceURL), nil
+	case "file":
+		dir, err := file.ParseURL(p, fac.fileRoot)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s of project %s", models.ProjectStoragePathKey, proj.Name)
+		}
+		return file.NewJobRepository(fac.fileRoot, filepath.Join(dir, fac.schd.GetJobsDir()), fac.schd.GetJobsExtension()), nil
 	}
 	return nil, errors.Errorf("unsupported storage config %s in %s of project %s", storagePath, models.ProjectStoragePathKey, proj.Name)
 }
 
+type deploymentStateRepoFactory struct {
+	db *gorm.DB
+}
+
+func (fac *deploymentStateRepoFactory) New(project models.ProjectSpec) store.DeploymentStateRepository {
+	return postgres.NewDeploymentStateRepository(fac.db, project)
+}
+
 type projectRepoFactory struct {
 	db   *gorm.DB
 	hash models.ApplicationKey
+	// projects are read through the cache if set
+	cache cache.ProjectRepository
 }
 
 func (fac *projectRepoFactory) New() store.ProjectRepository {
+	if fac.cache != nil {
+		return fac.cache
+	}
 	return postgres.NewProjectRepository(fac.db, fac.hash)
 }
 
@@ -150,12 +236,39 @@ func (fac *namespaceRepoFactory) New(projectSpec models.ProjectSpec) store.Names
 }
 
 type projectSecretRepoFactory struct {
-	db   *gorm.DB
-	hash models.ApplicationKey
+	db               *gorm.DB
+	hash             models.ApplicationKey
+	versionRetention int
+	// secrets are read with projects, cached projects are evicted when
+	// their secrets change
+	projectCache cache.ProjectRepository
 }
 
 func (fac *projectSecretRepoFactory) New(spec models.ProjectSpec) store.ProjectSecretRepository {
-	return postgres.NewSecretRepository(fac.db, spec, fac.hash)
+	repo := postgres.NewSecretRepository(fac.db, spec, fac.hash)
+	if fac.versionRetention > 0 {
+		repo.VersionRetention = fac.versionRetention
+	}
+	if fac.projectCache != nil {
+		return cache.NewSecretRepository(spec.Name, repo, fac.projectCache)
+	}
+	return repo
+}
+
+type roleBindingRepoFactory struct {
+	db *gorm.DB
+}
+
+func (fac *roleBindingRepoFactory) New(spec models.ProjectSpec) store.ProjectRoleBindingRepository {
+	return postgres.NewRoleBindingRepository(fac.db, spec)
+}
+
+type apiKeyRepoFactory struct {
+	db *gorm.DB
+}
+
+func (fac *apiKeyRepoFactory) New(spec models.ProjectSpec) store.APIKeyRepository {
+	return postgres.NewAPIKeyRepository(fac.db, spec)
 }
 
 type instanceRepoFactory struct {
@@ -186,6 +299,8 @@ func (fac *resourceSpecRepoFactory) New(namespace models.NamespaceSpec, ds model
 }
 
 type objectWriterFactory struct {
+	// directory file storage paths are confined to, disabled if empty
+	fileRoot string
 }
 
 func (o *objectWriterFactory) New(ctx context.Context, writerPath, writerSecret string) (store.ObjectWriter, error) {
@@ -203,6 +318,32 @@ func (o *objectWriterFactory) New(ctx context.Context, writerPath, writerSecret
 		return &gcs.GcsObjectWriter{
 			Client: gcsClient,
 		}, nil
+	case "s3":
+		s3Client, err := s3.NewClient(writerSecret)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating s3 client")
+		}
+		return &s3.S3ObjectWriter{
+			Client: s3Client,
+		}, nil
+	case "az", "abfs":
+		container, _, err := azure.ParseURL(p)
+		if err != nil {
+			return nil, err
+		}
+		serviceURL, err := azure.NewServiceURL(writerSecret)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating azure blob client")
+		}
+		return &azure.AzureBlobObjectWriter{
+			ServiceURL: serviceURL,
+			Container:  container,
+		}, nil
+	case "file":
+		if _, err := file.ParseURL(p, o.fileRoot); err != nil {
+			return nil, err
+		}
+		return &file.FileObjectWriter{Root: o.fileRoot}, nil
 	}
 	return nil, errors.Errorf("unsupported storage config %s", writerPath)
 }
@@ -226,10 +367,13 @@ func (obs *pipelineLogObserver) Notify(evt progress.Event) {
 	obs.log.Info(evt)
 }
 
-func jobSpecAssetDump() func(jobSpec models.JobSpec, scheduledAt time.Time) (models.JobAssets, error) {
-	engine := instance.NewGoEngine()
-	return func(jobSpec models.JobSpec, scheduledAt time.Time) (models.JobAssets, error) {
-		aMap, err := instance.DumpAssets(jobSpec, scheduledAt, engine, false)
+func jobSpecAssetDump(defaultEngine models.TemplateEngine) job.AssetCompiler {
+	return func(jobSpec models.JobSpec, projectSpec models.ProjectSpec, scheduledAt time.Time) (models.JobAssets, error) {
+		engine, err := instance.ProjectTemplateEngine(projectSpec, defaultEngine)
+		if err != nil {
+			return models.JobAssets{}, err
+		}
+		aMap, err := instance.DumpAssets(jobSpec, projectSpec.Variables, scheduledAt, engine, false)
 		if err != nil {
 			return models.JobAssets{}, err
 		}
@@ -237,6 +381,22 @@ func jobSpecAssetDump() func(jobSpec models.JobSpec, scheduledAt time.Time) (mod
 	}
 }
 
+// projectJobLookup finds jobs of any registered project, for dependencies
+// that cross projects
+func projectJobLookup(projectRepoFac *projectRepoFactory, projectJobSpecRepoFac projectJobSpecRepoFactory) job.ProjectJobLookup {
+	return func(projectName, jobName string) (models.JobSpec, models.ProjectSpec, error) {
+		projectSpec, err := projectRepoFac.New().GetByName(projectName)
+		if err != nil {
+			return models.JobSpec{}, models.ProjectSpec{}, errors.Wrapf(err, "failed to find project %s", projectName)
+		}
+		jobSpec, _, err := projectJobSpecRepoFac.New(projectSpec).GetByName(jobName)
+		if err != nil {
+			return models.JobSpec{}, models.ProjectSpec{}, err
+		}
+		return jobSpec, projectSpec, nil
+	}
+}
+
 func checkRequiredConfigs(conf config.Provider) error {
 	errRequiredMissing := errors.New("required config missing")
 	if conf.GetServe().IngressHost == "" {
@@ -258,7 +418,34 @@ func checkRequiredConfigs(conf config.Provider) error {
 	return nil
 }
 
-func Initialize(conf config.Provider) error {
+// initScheduler returns the scheduler called name, file storage paths of
+// projects are confined to fileRoot
+func initScheduler(name, fileRoot string) (models.SchedulerUnit, error) {
+	switch name {
+	case "airflow":
+		return airflow.NewScheduler(
+			&objectWriterFactory{fileRoot: fileRoot},
+			newSchedulerHTTPClient(),
+		), nil
+	case "airflow2":
+		return airflow2.NewScheduler(
+			&objectWriterFactory{fileRoot: fileRoot},
+			newSchedulerHTTPClient(),
+		), nil
+	}
+	return nil, errors.Errorf("unsupported scheduler: %s", name)
+}
+
+// newSchedulerHTTPClient propagates the trace of a request to the scheduler
+func newSchedulerHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+}
+
+// Initialize starts the server and blocks till it's stopped, loadConf reads
+// the config again when the server receives SIGHUP
+func Initialize(conf config.Provider, loadConf func() (config.Provider, error)) error {
 	if err := checkRequiredConfigs(conf); err != nil {
 		return err
 	}
@@ -277,29 +464,40 @@ func Initialize(conf config.Provider) error {
 		log: log.WithField("reporter", "pipeline"),
 	}
 
+	shutdownTracer := func(context.Context) error { return nil }
+	if tracingConf := conf.GetServe().Tracing; tracingConf.Exporter != "" {
+		shutdown, err := initTracer(context.Background(), tracingConf)
+		if err != nil {
+			return err
+		}
+		shutdownTracer = shutdown
+		mainLog.Infof("tracing is enabled with %s exporter", tracingConf.Exporter)
+	}
+
 	// setup db
-	if err := postgres.Migrate(conf.GetServe().DB.DSN); err != nil {
+	if err := postgres.Migrate(conf.GetServe().DB.DSN, conf.GetServe().DB.PoolerMode); err != nil {
 		return errors.Wrap(err, "postgres.Migrate")
 	}
-	dbConn, err := postgres.Connect(conf.GetServe().DB.DSN, conf.GetServe().DB.MaxIdleConnection, conf.GetServe().DB.MaxOpenConnection)
+	dbConn, err := postgres.Connect(conf.GetServe().DB.DSN, conf.GetServe().DB.MaxIdleConnection, conf.GetServe().DB.MaxOpenConnection,
+		conf.GetServe().DB.PoolerMode)
 	if err != nil {
 		return errors.Wrap(err, "postgres.Connect")
 	}
 
 	// init default scheduler
-	switch conf.GetScheduler().Name {
-	case "airflow":
-		models.Scheduler = airflow.NewScheduler(
-			&objectWriterFactory{},
-			&http.Client{},
-		)
-	case "airflow2":
-		models.Scheduler = airflow2.NewScheduler(
-			&objectWriterFactory{},
-			&http.Client{},
-		)
-	default:
-		return errors.Errorf("unsupported scheduler: %s", conf.GetScheduler().Name)
+	if models.Scheduler, err = initScheduler(conf.GetScheduler().Name, conf.GetServe().FileStorageRoot); err != nil {
+		return err
+	}
+	templateEngine, err := instance.TemplateEngineByName(conf.GetServe().TemplateEngine)
+	if err != nil {
+		return err
+	}
+	var schedulerVersionCheck *schedulerVersionChecker
+	if conf.GetScheduler().MinimumAirflowVersion != "" {
+		if schedulerVersionCheck, err = newSchedulerVersionChecker(models.Scheduler,
+			conf.GetScheduler().MinimumAirflowVersion); err != nil {
+			return err
+		}
 	}
 
 	// used to encrypt secrets
@@ -307,36 +505,54 @@ func Initialize(conf config.Provider) error {
 	if err != nil {
 		return errors.Wrap(err, "NewApplicationSecret")
 	}
+	if conf.GetServe().PreviousAppKey != "" {
+		prevHash, err := models.NewApplicationSecret(conf.GetServe().PreviousAppKey)
+		if err != nil {
+			return errors.Wrap(err, "NewApplicationSecret")
+		}
+		appHash = appHash.WithPrevious(prevHash)
+	}
 
 	// registered project store repository factory, its a wrapper over a storage
 	// interface
+	// projects are cached in redis when shared by multiple servers
+	var projectCache cache.ProjectRepository
+	if redisURL := conf.GetServe().RedisURL; redisURL != "" {
+		redisCache, err := cache.NewRedisProjectCache(postgres.NewProjectRepository(dbConn, appHash), redisURL, appHash,
+			conf.GetServe().ProjectCacheTTL, prometheus.DefaultRegisterer)
+		if err != nil {
+			return errors.Wrap(err, "cache.NewRedisProjectCache")
+		}
+		defer redisCache.Close()
+		projectCache = redisCache
+		mainLog.Info("projects are cached in redis")
+	} else {
+		memoryCache, err := cache.NewProjectCache(postgres.NewProjectRepository(dbConn, appHash),
+			conf.GetServe().ProjectCacheSize, conf.GetServe().ProjectCacheTTL, prometheus.DefaultRegisterer)
+		if err != nil {
+			return errors.Wrap(err, "cache.NewProjectCache")
+		}
+		projectCache = memoryCache
+	}
 	projectRepoFac := &projectRepoFactory{
-		db:   dbConn,
-		hash: appHash,
+		db:    dbConn,
+		hash:  appHash,
+		cache: projectCache,
 	}
 	registeredProjects, err := projectRepoFac.New().GetAll()
 	if err != nil {
 		return errors.Wrap(err, "projectRepoFactory.GetAll()")
 	}
+	jobPauseRepo := postgres.NewJobPauseRepository(dbConn)
 	// bootstrap scheduler for registered projects
-	for _, proj := range registeredProjects {
-		func() {
-			bootstrapCtx, cancel := context.WithTimeout(context.Background(), time.Second*10)
-			defer cancel()
-
-			logger.I("bootstrapping project ", proj.Name)
-			if err := models.Scheduler.Bootstrap(bootstrapCtx, proj); err != nil {
-				// Major ERROR, but we can't make this fatal
-				// other projects might be working fine though
-				logger.E(err)
-			}
-			logger.I("bootstrapped project ", proj.Name)
-		}()
-	}
+	newProjectBootstrapper(models.Scheduler, schedulerVersionCheck, jobPauseRepo, conf.GetServe().BootstrapConcurrency,
+		log.WithField("reporter", "bootstrap")).Run(registeredProjects)
 
 	projectSecretRepoFac := &projectSecretRepoFactory{
-		db:   dbConn,
-		hash: appHash,
+		db:               dbConn,
+		hash:             appHash,
+		versionRetention: conf.GetServe().SecretVersionRetention,
+		projectCache:     projectCache,
 	}
 	namespaceSpecRepoFac := &namespaceRepoFactory{
 		db:   dbConn,
@@ -351,8 +567,17 @@ func Initialize(conf config.Provider) error {
 		db:                    dbConn,
 		projectJobSpecRepoFac: projectJobSpecRepoFac,
 	}
-	jobCompiler := job.NewCompiler(models.Scheduler.GetTemplate(), conf.GetServe().IngressHost)
-	dependencyResolver := job.NewDependencyResolver()
+	dependencyPinRepoFac := &dependencyPinRepoFactory{
+		db: dbConn,
+	}
+	compiler := job.NewCompiler(models.Scheduler.GetTemplate(), conf.GetServe().IngressHost, dependencyPinRepoFac,
+		job.ValidateAirflowDAG)
+	compiler.EmbedDocs = conf.GetServe().EmbedJobDocs
+	jobCompiler, err := job.NewInstrumentedCompiler(compiler, prometheus.DefaultRegisterer)
+	if err != nil {
+		return errors.Wrap(err, "job.NewInstrumentedCompiler")
+	}
+	dependencyResolver := job.NewDependencyResolver(projectJobLookup(projectRepoFac, projectJobSpecRepoFac))
 	priorityResolver := job.NewPriorityResolver()
 
 	// Logrus entry is used, allowing pre-definition of certain fields by the user.
@@ -365,12 +590,96 @@ func Initialize(conf config.Provider) error {
 	grpc_logrus.ReplaceGrpcLogger(logrusEntry)
 
 	grpcAddr := fmt.Sprintf("%s:%d", conf.GetServe().Host, conf.GetServe().Port)
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		grpctags.UnaryServerInterceptor(grpctags.WithFieldExtractor(middleware.ProjectRequestFieldExtractor)),
+		grpc_logrus.UnaryServerInterceptor(logrusEntry, opts...),
+		grpc_prometheus.UnaryServerInterceptor,
+		middleware.VersionUnaryServerInterceptor(config.Version, v1handler.APIMajorVersion),
+		// bodies are only logged when log level is trace
+		middleware.BodyLoggingUnaryServerInterceptor(logrusEntry),
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		grpc_prometheus.StreamServerInterceptor,
+		middleware.VersionStreamServerInterceptor(config.Version, v1handler.APIMajorVersion),
+		middleware.BodyLoggingStreamServerInterceptor(logrusEntry),
+	}
+	if conf.GetServe().Tracing.Exporter != "" {
+		// spans start first to cover the time spent in the rest of the chain
+		unaryInterceptors = append([]grpc.UnaryServerInterceptor{otelgrpc.UnaryServerInterceptor()}, unaryInterceptors...)
+		streamInterceptors = append([]grpc.StreamServerInterceptor{otelgrpc.StreamServerInterceptor()}, streamInterceptors...)
+	}
+	// calls are recorded with their outcome before the rest of the chain
+	// can reject them, callers are known once they are authenticated
+	auditLogRepo := postgres.NewAuditLogRepository(dbConn)
+	auditor := middleware.NewAuditor(auditLogRepo, v1handler.IsMutatingMethod, logrusEntry)
+	unaryInterceptors = append(unaryInterceptors, auditor.UnaryServerInterceptor())
+	streamInterceptors = append(streamInterceptors, auditor.StreamServerInterceptor())
+	// callers of servers without authentication can change projects but
+	// not run operations across them
+	anonymousRole := models.RoleEditor
+	if authConf := conf.GetServe().Auth; authConf.JWKSURL != "" || authConf.PublicKeyFile != "" {
+		authenticator, err := newJWTAuthenticator(authConf)
+		if err != nil {
+			return err
+		}
+		authenticator.APIKeys = middleware.NewAPIKeyAuthenticator(postgres.NewAPIKeyRepository(dbConn, models.ProjectSpec{}))
+		authenticator.PublicMethods = map[string]bool{}
+		for method, role := range v1handler.MethodAuthorizationMap {
+			if role == models.RoleNone {
+				authenticator.PublicMethods[method] = true
+			}
+		}
+		// calls are logged before authentication to keep track of rejected ones
+		unaryInterceptors = append(unaryInterceptors, authenticator.UnaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, authenticator.StreamServerInterceptor())
+		anonymousRole = models.RoleNone
+	}
+	// callers are known after authentication, each caller of a project is
+	// limited on its own and within a ceiling across projects
+	if conf.GetServe().MaxRequestsPerSecond > 0 {
+		rateLimiter := middleware.NewRateLimiter(conf.GetServe().MaxRequestsPerSecond, conf.GetServe().MaxBurst,
+			conf.GetServe().CallerRequestsPerSecond)
+		unaryInterceptors = append(unaryInterceptors, rateLimiter.UnaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, rateLimiter.StreamServerInterceptor())
+	}
+	authorizer := middleware.NewMethodAuthorizer(v1handler.MethodAuthorizationMap, anonymousRole)
+	authorizer.ProjectRoles = postgres.NewRoleBindingRepository(dbConn, models.ProjectSpec{})
+	unaryInterceptors = append(unaryInterceptors, authorizer.UnaryServerInterceptor())
+	streamInterceptors = append(streamInterceptors, authorizer.StreamServerInterceptor())
+	// only callers allowed to reveal secrets spend their reveals
+	secretRevealLimiter := middleware.NewMethodRateLimiter("/odpf.optimus.RuntimeService/RevealProjectSecret",
+		secretRevealsPerHour, time.Hour)
+	unaryInterceptors = append(unaryInterceptors, secretRevealLimiter.UnaryServerInterceptor())
 	grpcOpts := []grpc.ServerOption{
-		grpc_middleware.WithUnaryServerChain(
-			grpctags.UnaryServerInterceptor(grpctags.WithFieldExtractor(grpctags.CodeGenRequestFieldExtractor)),
-			grpc_logrus.UnaryServerInterceptor(logrusEntry, opts...),
-		),
+		grpc_middleware.WithUnaryServerChain(unaryInterceptors...),
+		grpc_middleware.WithStreamServerChain(streamInterceptors...),
 		grpc.MaxRecvMsgSize(GRPCMaxRecvMsgSize),
+		// idle connections don't hold up shutdown
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle: conf.GetServe().ConnectionIdleTimeout,
+		}),
+	}
+	var tlsConfig *tls.Config
+	gatewayCreds := insecure.NewCredentials()
+	if conf.GetServe().TLSCertFile != "" || conf.GetServe().TLSKeyFile != "" {
+		tlsConfig, err = newServerTLSConfig(conf.GetServe().TLSCertFile, conf.GetServe().TLSKeyFile,
+			conf.GetServe().TLSCACertFile)
+		if err != nil {
+			return err
+		}
+		// tls is terminated by the http server sharing the port, credentials
+		// are still set to keep grpcServer secure if served directly
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		gatewayCreds = credentials.NewTLS(newGatewayTLSConfig(tlsConfig))
+	} else if conf.GetServe().TLSCACertFile != "" {
+		return errors.Errorf("%s requires %s and %s", config.KeyServeTLSCACertFile,
+			config.KeyServeTLSCertFile, config.KeyServeTLSKeyFile)
+	}
+	// compressors are registered by the middleware package, responses are
+	// compressed with the compressor of the request
+	grpcCompression := conf.GetGRPCCompression()
+	if err := middleware.ValidateCompression(grpcCompression); err != nil {
+		return err
 	}
 	grpcServer := grpc.NewServer(grpcOpts...)
 	reflection.Register(grpcServer)
@@ -410,6 +719,7 @@ func Initialize(conf config.Provider) error {
 		NumWorkers:    conf.GetServe().ReplayNumWorkers,
 		WorkerTimeout: conf.GetServe().ReplayWorkerTimeoutSecs,
 		RunTimeout:    conf.GetServe().ReplayRunTimeoutSecs,
+		MaxRangeDays:  conf.GetServe().ReplayMaxRangeDays,
 	}, models.Scheduler)
 
 	notificationContext, cancelNotifiers := context.WithCancel(context.Background())
@@ -422,23 +732,136 @@ func Initialize(conf config.Provider) error {
 			},
 		),
 	})
+	webhookObserver := webhook.NewObserver(nil, logrusEntry.WithField("notifier", "webhook"))
+	// posts to projects with the SLACK_WEBHOOK_URL secret only
+	slackWebhookObserver := slack.NewWebhookObserver(nil, func(err error) {
+		logger.E(err)
+	})
+	// pages for critical jobs of projects with the PAGERDUTY_ROUTING_KEY secret
+	pagerdutyNotifier := pagerduty.NewNotifier(pagerduty.DefaultEventsURL, nil, func(err error) {
+		logger.E(err)
+	})
+	pagerdutyNotifier.ServerRoutingKey = conf.GetServe().PagerDutyRoutingKey
+	lifecycleObservers := new(progress.ObserverChain)
+	lifecycleObservers.Join(webhookObserver)
+	lifecycleObservers.Join(slackWebhookObserver)
+	lifecycleObservers.Join(pagerdutyNotifier)
+	eventService.LifecycleObserver = lifecycleObservers
+
+	deployJobRepoFac := &jobRepoFactory{
+		schd:     models.Scheduler,
+		fileRoot: conf.GetServe().FileStorageRoot,
+	}
+	if conf.GetScheduler().StrictVersionCheck {
+		deployJobRepoFac.versionChecker = schedulerVersionCheck
+	}
+	jobSvc := job.NewService(
+		&jobSpecRepoFac,
+		deployJobRepoFac,
+		jobCompiler,
+		jobSpecAssetDump(templateEngine),
+		dependencyResolver,
+		priorityResolver,
+		metaSvcFactory,
+		&projectJobSpecRepoFac,
+		replayManager,
+		dependencyPinRepoFac,
+		&releaseRepoFactory{
+			db: dbConn,
+		},
+	)
+	jobSvc.MaxBackfillLookback = time.Hour * 24 * time.Duration(conf.GetServe().MaxBackfillLookbackDays)
+	jobSvc.PauseRepo = jobPauseRepo
+	jobSvc.DefaultQuota = models.ProjectQuota{
+		MaxJobCount:         conf.GetServe().DefaultQuota.MaxJobCount,
+		MinScheduleInterval: conf.GetServe().DefaultQuota.MinScheduleInterval,
+	}
+	jobSvc.Scheduler = models.Scheduler
+	jobSvc.LifecycleObserver = lifecycleObservers
+	jobSvc.CrossProjectJobSpecRepo = postgres.NewCrossProjectJobSpecRepository(dbConn, postgres.NewAdapter(models.PluginRegistry),
+		v1.NewAdapter(models.PluginRegistry, models.DatastoreRegistry))
+	if bqDatastore, err := models.DatastoreRegistry.GetByName("bigquery"); err == nil {
+		if schemaInspector, ok := bqDatastore.(models.DatastoreSchemaInspector); ok {
+			jobSvc.SchemaInspector = schemaInspector
+		}
+		if costEstimator, ok := bqDatastore.(models.DatastoreCostEstimator); ok {
+			jobSvc.CostEstimator = costEstimator
+		}
+	}
+
+	// deploy modified job specs in background
+	autoDeployCtx, cancelAutoDeploy := context.WithCancel(context.Background())
+	defer cancelAutoDeploy()
+	if interval := conf.GetServe().AutoDeployInterval; interval > 0 {
+		mainLog.Infof("auto deployment of modified jobs is enabled every %s", interval)
+		autoDeployer := job.NewAutoDeployer(jobSvc, projectRepoFac, &deploymentStateRepoFactory{
+			db: dbConn,
+		}, interval, progressObs)
+		go autoDeployer.Run(autoDeployCtx)
+	}
+
+	// record and notify runs not started within the sla of their job in background
+	slaBreachRepo := postgres.NewSLABreachRepository(dbConn)
+	slaMonitorCtx, cancelSLAMonitor := context.WithCancel(context.Background())
+	defer cancelSLAMonitor()
+	if interval := conf.GetServe().SLACheckInterval; interval > 0 {
+		mainLog.Infof("sla monitoring of jobs is enabled every %s", interval)
+		slaObservers := new(progress.ObserverChain)
+		slaObservers.Join(lifecycleObservers)
+		slaObservers.Join(progressObs)
+		slaMonitor := job.NewSLAMonitor(projectRepoFac, &projectJobSpecRepoFac, models.Scheduler, slaBreachRepo,
+			interval, slaObservers)
+		go slaMonitor.Run(slaMonitorCtx)
+	}
+
+	// db pool sizes and log level are reloaded on SIGHUP
+	configReloadCtx, cancelConfigReload := context.WithCancel(context.Background())
+	defer cancelConfigReload()
+	newConfigReloader(loadConf, conf, dbConn.DB(), log, log.WithField("reporter", "config")).Start(configReloadCtx)
+
+	projectHealthSvc := job.NewProjectHealthService(
+		projectRepoFac,
+		&jobRepoFactory{
+			schd:     models.Scheduler,
+			fileRoot: conf.GetServe().FileStorageRoot,
+		},
+		&projectJobSpecRepoFac,
+		dependencyResolver,
+		models.Scheduler,
+	)
+
+	instanceService := instance.NewService(
+		&instanceRepoFactory{
+			db: dbConn,
+		},
+		func() time.Time {
+			return time.Now().UTC()
+		},
+		templateEngine,
+	)
+	instanceService.Scheduler = models.Scheduler
+	instanceService.DeadInstanceThreshold = conf.GetServe().DeadInstanceThreshold
+	instanceService.AssetCache = instance.NewAssetCache(instance.AssetCacheSize, conf.GetServe().AssetCacheTTL)
+
+	// fail instances orphaned by a scheduler crash in background
+	reconcileCtx, cancelReconcile := context.WithCancel(context.Background())
+	defer cancelReconcile()
+	go func() {
+		for _, proj := range registeredProjects {
+			reconciled, err := instanceService.ReconcileDeadInstances(reconcileCtx, proj)
+			if err != nil {
+				mainLog.WithError(err).Warnf("failed to reconcile instances of project %s", proj.Name)
+			}
+			if reconciled > 0 {
+				mainLog.Infof("failed %d orphaned instances of project %s", reconciled, proj.Name)
+			}
+		}
+	}()
 
 	// runtime service instance over grpc
-	pb.RegisterRuntimeServiceServer(grpcServer, v1handler.NewRuntimeServiceServer(
+	runtimeService := v1handler.NewRuntimeServiceServer(
 		config.Version,
-		job.NewService(
-			&jobSpecRepoFac,
-			&jobRepoFactory{
-				schd: models.Scheduler,
-			},
-			jobCompiler,
-			jobSpecAssetDump(),
-			dependencyResolver,
-			priorityResolver,
-			metaSvcFactory,
-			&projectJobSpecRepoFac,
-			replayManager,
-		),
+		jobSvc,
 		eventService,
 		datastore.NewService(&resourceSpecRepoFac, models.DatastoreRegistry),
 		projectRepoFac,
@@ -446,17 +869,40 @@ func Initialize(conf config.Provider) error {
 		projectSecretRepoFac,
 		v1.NewAdapter(models.PluginRegistry, models.DatastoreRegistry),
 		progressObs,
-		instance.NewService(
-			&instanceRepoFactory{
-				db: dbConn,
-			},
-			func() time.Time {
-				return time.Now().UTC()
-			},
-			instance.NewGoEngine(),
-		),
+		instanceService,
 		models.Scheduler,
-	))
+		projectHealthSvc,
+	)
+	runtimeService.SecretRevealPassphrase = conf.GetServe().SecretRevealPassphrase
+	runtimeService.AuditLog = logrusEntry.WithField("audit", true)
+	secretRepo := postgres.NewSecretRepository(dbConn, models.ProjectSpec{}, appHash)
+	runtimeService.SecretRotator = secretRepo
+	runtimeService.AppKey = appHash
+	runtimeService.RoleBindingRepoFactory = &roleBindingRepoFactory{db: dbConn}
+	runtimeService.APIKeyRepoFactory = &apiKeyRepoFactory{db: dbConn}
+	runtimeService.AuditLogRepo = auditLogRepo
+	runtimeService.SLABreachRepo = slaBreachRepo
+	runtimeService.JobRunTriggerRepo = postgres.NewJobRunTriggerRepository(dbConn)
+	runtimeService.JobPauseRepo = jobPauseRepo
+	pb.RegisterRuntimeServiceServer(grpcServer, runtimeService)
+
+	// initialize grpc metrics of all registered services
+	grpc_prometheus.EnableHandlingTimeHistogram()
+	grpc_prometheus.Register(grpcServer)
+
+	// export connection pool stats of the database in background
+	dbStatsMetrics, err := postgres.NewDBStatsMetrics(prometheus.DefaultRegisterer)
+	if err != nil {
+		return errors.Wrap(err, "postgres.NewDBStatsMetrics")
+	}
+	dbStatsCtx, cancelDBStats := context.WithCancel(context.Background())
+	defer cancelDBStats()
+	go dbStatsMetrics.Run(dbStatsCtx, dbConn.DB(), postgres.DBStatsExportInterval)
+
+	// warn about secrets close to their expiry in background
+	secretExpiryCtx, cancelSecretExpiry := context.WithCancel(context.Background())
+	defer cancelSecretExpiry()
+	go newSecretExpiryMonitor(secretRepo, logrusEntry).Run(secretExpiryCtx, secretExpiryCheckInterval)
 
 	timeoutGrpcDialCtx, grpcDialCancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer grpcDialCancel()
@@ -464,11 +910,16 @@ func Initialize(conf config.Provider) error {
 	// prepare http proxy
 	gwmux := runtime.NewServeMux(
 		runtime.WithErrorHandler(runtime.DefaultHTTPErrorHandler),
+		runtime.WithIncomingHeaderMatcher(middleware.GatewayHeaderMatcher),
 	)
 	// gRPC dialup options to proxy http connections
-	grpcConn, err := grpc.DialContext(timeoutGrpcDialCtx, grpcAddr, []grpc.DialOption{
-		grpc.WithInsecure(),
-	}...)
+	gatewayDialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(gatewayCreds),
+	}
+	if grpcCompression != "" {
+		gatewayDialOpts = append(gatewayDialOpts, grpc.WithDefaultCallOptions(grpc.UseCompressor(grpcCompression)))
+	}
+	grpcConn, err := grpc.DialContext(timeoutGrpcDialCtx, grpcAddr, gatewayDialOpts...)
 	if err != nil {
 		return errors.Wrap(err, "grpc.DialContext")
 	}
@@ -477,6 +928,13 @@ func Initialize(conf config.Provider) error {
 	if err := pb.RegisterRuntimeServiceHandler(runtimeCtx, gwmux, grpcConn); err != nil {
 		return errors.Wrap(err, "RegisterRuntimeServiceHandler")
 	}
+	const jobSpecCSVPath = "/v1/projects/{name}/jobs.csv"
+	if err := gwmux.HandlePath(http.MethodGet, jobSpecCSVPath,
+		v1handler.JobSpecCSVHandler(projectRepoFac, &projectJobSpecRepoFac)); err != nil {
+		return errors.Wrap(err, "HandlePath")
+	}
+	gatewayRoutes := middleware.NewGatewayRoutes("/api", pb.File_odpf_optimus_runtime_service_proto.Services().ByName("RuntimeService"))
+	gatewayRoutes.Add(http.MethodGet, jobSpecCSVPath)
 
 	// base router
 	baseMux := http.NewServeMux()
@@ -484,19 +942,74 @@ func Initialize(conf config.Provider) error {
 		fmt.Fprintf(w, "pong")
 	})
 	baseMux.Handle("/api/", http.StripPrefix("/api", gwmux))
+	baseMux.Handle("/metrics", promhttp.Handler())
+	baseMux.Handle("/healthz", v1handler.LivenessHandler())
+	baseMux.Handle("/readyz", v1handler.ReadinessHandler([]v1handler.ReadinessProbe{
+		{
+			Name: models.ProjectHealthCheckDatabase,
+			Check: func(ctx context.Context) error {
+				_, err := dbConn.DB().ExecContext(ctx, "SELECT 1")
+				return err
+			},
+		},
+		{
+			// storage is shared by projects in most setups, probing one is enough
+			Name: models.ProjectHealthCheckStorage,
+			Check: func(ctx context.Context) error {
+				projects, err := projectRepoFac.New().GetAll()
+				if err != nil || len(projects) == 0 {
+					return err
+				}
+				return projectHealthSvc.ProbeStorage(ctx, projects[0])
+			},
+		},
+	}))
+
+	// track payload sizes of http calls, routes are labelled by the matched
+	// gateway path template or mux pattern
+	bodySizeMetrics, err := middleware.NewBodySizeMetrics(prometheus.DefaultRegisterer)
+	if err != nil {
+		return errors.Wrap(err, "middleware.NewBodySizeMetrics")
+	}
+
+	// only responses of whitelisted read routes are cacheable
+	var cacheRoutes []middleware.CacheRoute
+	for _, route := range conf.GetServe().CacheControl {
+		cacheRoutes = append(cacheRoutes, middleware.CacheRoute{
+			Pattern: route.Path,
+			MaxAge:  route.MaxAge,
+		})
+	}
+	cacheControl, err := middleware.NewCacheControl(cacheRoutes)
+	if err != nil {
+		return errors.Wrap(err, "middleware.NewCacheControl")
+	}
+	httpHandler := bodySizeMetrics.Handler(cacheControl.Handler(baseMux), func(r *http.Request) string {
+		if template, ok := gatewayRoutes.Resolve(r); ok {
+			return template
+		}
+		_, pattern := baseMux.Handler(r)
+		return pattern
+	})
 
 	srv := &http.Server{
-		Handler:      grpcHandlerFunc(grpcServer, baseMux),
+		Handler:      grpcHandlerFunc(grpcServer, httpHandler, grpcCompression != ""),
 		Addr:         grpcAddr,
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
-		IdleTimeout:  120 * time.Second,
+		IdleTimeout:  conf.GetServe().ConnectionIdleTimeout,
+		TLSConfig:    tlsConfig,
 	}
 
 	// run our server in a goroutine so that it doesn't block to wait for termination requests
 	go func() {
 		mainLog.Infoln("starting listening at ", grpcAddr)
-		if err := srv.ListenAndServe(); err != nil {
+		serve := srv.ListenAndServe
+		if tlsConfig != nil {
+			// certificates are already loaded in TLSConfig
+			serve = func() error { return srv.ListenAndServeTLS("", "") }
+		}
+		if err := serve(); err != nil {
 			if err != http.ErrServerClosed {
 				mainLog.Fatalf("server error: %v\n", err)
 			}
@@ -513,11 +1026,15 @@ func Initialize(conf config.Provider) error {
 	mainLog.Info("termination request received")
 	var terminalError error
 
+	cancelAutoDeploy()
+	cancelSLAMonitor()
+	cancelConfigReload()
 	if err = replayManager.Close(); err != nil {
 		terminalError = multierror.Append(terminalError, errors.Wrap(err, "replayManager.Close"))
 	}
 
 	// Create a deadline to wait for server
+	shutdownWait := shutdownWaitFromSeconds(conf.GetServe().ShutdownWaitSeconds, mainLog)
 	ctxProxy, cancelProxy := context.WithTimeout(context.Background(), shutdownWait)
 	defer cancelProxy()
 
@@ -526,13 +1043,38 @@ func Initialize(conf config.Provider) error {
 	if err := srv.Shutdown(ctxProxy); err != nil {
 		terminalError = multierror.Append(terminalError, errors.Wrap(err, "srv.Shutdown"))
 	}
-	grpcServer.GracefulStop()
+	// calls still being served are cancelled after shutdownWait only if forced
+	shutdownObservers := new(progress.ObserverChain)
+	shutdownObservers.Join(lifecycleObservers)
+	shutdownObservers.Join(progressObs)
+	hostname, _ := os.Hostname()
+	newGracefulStopper(grpcServer, shutdownWait, conf.GetServe().ForceStopOnTimeout, mainLog,
+		shutdownObservers, hostname).Stop()
 
 	// gracefully shutdown event service, e.g. slack notifiers flush in memory batches
 	cancelNotifiers()
 	if err := eventService.Close(); err != nil && len(err.Error()) != 0 {
 		terminalError = multierror.Append(terminalError, errors.Wrap(err, "eventService.Close"))
 	}
+	// wait for calls served till now being recorded in the audit log
+	if err := auditor.Close(); err != nil {
+		terminalError = multierror.Append(terminalError, errors.Wrap(err, "auditor.Close"))
+	}
+	// wait for webhooks being delivered, including their retries
+	if err := webhookObserver.Close(); err != nil {
+		terminalError = multierror.Append(terminalError, errors.Wrap(err, "webhookObserver.Close"))
+	}
+	if err := slackWebhookObserver.Close(); err != nil {
+		terminalError = multierror.Append(terminalError, errors.Wrap(err, "slackWebhookObserver.Close"))
+	}
+	if err := pagerdutyNotifier.Close(); err != nil {
+		terminalError = multierror.Append(terminalError, errors.Wrap(err, "pagerdutyNotifier.Close"))
+	}
+
+	// flush spans of the calls served till now
+	if err := shutdownTracer(ctxProxy); err != nil {
+		terminalError = multierror.Append(terminalError, errors.Wrap(err, "shutdownTracer"))
+	}
 
 	mainLog.Info("bye")
 	return terminalError
@@ -545,7 +1087,12 @@ func Initialize(conf config.Provider) error {
 // into two ports, default port for grpc and default+1 for grpc-gateway proxy.
 // We can also use something like a connection multiplexer
 // https://github.com/soheilhy/cmux to achieve the same.
-func grpcHandlerFunc(grpcServer *grpc.Server, otherHandler http.Handler) http.Handler {
+// When compressHTTP is set, http1 responses are gzipped for clients sending
+// Accept-Encoding as grpc calls are compressed.
+func grpcHandlerFunc(grpcServer *grpc.Server, otherHandler http.Handler, compressHTTP bool) http.Handler {
+	if compressHTTP {
+		otherHandler = middleware.GzipHandler(otherHandler)
+	}
 	return h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.ProtoMajor == 2 && strings.Contains(r.Header.Get("Content-Type"), "application/grpc") {
 			grpcServer.ServeHTTP(w, r)
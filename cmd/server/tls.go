@@ -0,0 +1,63 @@
+package server
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// newServerTLSConfig loads the server certificate, client certificates are
+// required and verified against the authorities of caCertFile when set
+func newServerTLSConfig(certFile, keyFile, caCertFile string) (*tls.Config, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("both tls certificate and key files are required")
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load tls key pair")
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		// grpc requires http2, http1 is still served for the gateway
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+	if caCertFile != "" {
+		caCert, err := ioutil.ReadFile(caCertFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read tls ca certificate")
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caCert) {
+			return nil, errors.Errorf("no certificates found in %s", caCertFile)
+		}
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// newGatewayTLSConfig is used by the http gateway to dial the grpc server
+// running in the same process. The dialed address is usually 0.0.0.0 which
+// can't be verified against the certificate, so the server certificate is
+// pinned instead. The server certificate is presented as client certificate
+// for mutual tls.
+func newGatewayTLSConfig(serverTLSConfig *tls.Config) *tls.Config {
+	serverCert := serverTLSConfig.Certificates[0].Certificate[0]
+	return &tls.Config{
+		Certificates: serverTLSConfig.Certificates,
+		MinVersion:   tls.VersionTLS12,
+		// verification is done by VerifyPeerCertificate
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], serverCert) {
+				return errors.New("grpc server presented an unexpected certificate")
+			}
+			return nil
+		},
+	}
+}
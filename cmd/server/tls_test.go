@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCertificate(t *testing.T, name string, parent *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{name},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	assert.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+	return &testCertificate{cert: cert, key: key, der: der}
+}
+
+// write stores the certificate and key as pem files in dir
+func (c *testCertificate) write(t *testing.T, dir, name string) (string, string) {
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	keyDer, err := x509.MarshalECPrivateKey(c.key)
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0600))
+	assert.Nil(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
+
+func TestTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCertificate(t, "optimus-ca", nil)
+	caCertFile, _ := ca.write(t, dir, "ca")
+	serverCertFile, serverKeyFile := newTestCertificate(t, "optimus", ca).write(t, dir, "server")
+	otherCA := newTestCertificate(t, "other-ca", nil)
+
+	// serve starts grpc and http handlers on the same tls listener
+	serve := func(t *testing.T, tlsConfig *tls.Config) string {
+		grpcServer := grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)))
+		healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+		httpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "pong")
+		})
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.Nil(t, err)
+		srv := &http.Server{
+			Handler:   grpcHandlerFunc(grpcServer, httpHandler, false),
+			TLSConfig: tlsConfig,
+		}
+		go srv.ServeTLS(lis, "", "")
+		t.Cleanup(func() { srv.Close() })
+		return lis.Addr().String()
+	}
+	checkGRPC := func(addr string, clientTLSConfig *tls.Config) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn, err := grpc.DialContext(ctx, addr, grpc.WithTransportCredentials(credentials.NewTLS(clientTLSConfig)))
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		return err
+	}
+	getHTTP := func(addr string, clientTLSConfig *tls.Config) (string, error) {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLSConfig}}
+		resp, err := client.Get("https://" + addr + "/ping")
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	t.Run("should serve grpc and http over tls", func(t *testing.T) {
+		tlsConfig, err := newServerTLSConfig(serverCertFile, serverKeyFile, "")
+		assert.Nil(t, err)
+		addr := serve(t, tlsConfig)
+
+		assert.Nil(t, checkGRPC(addr, newGatewayTLSConfig(tlsConfig)))
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.cert)
+		body, err := getHTTP(addr, &tls.Config{RootCAs: roots, ServerName: "optimus"})
+		assert.Nil(t, err)
+		assert.Equal(t, "pong", body)
+	})
+	t.Run("should require client certificates signed by the ca for mutual tls", func(t *testing.T) {
+		tlsConfig, err := newServerTLSConfig(serverCertFile, serverKeyFile, caCertFile)
+		assert.Nil(t, err)
+		addr := serve(t, tlsConfig)
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.cert)
+
+		// gateway presents the server certificate
+		assert.Nil(t, checkGRPC(addr, newGatewayTLSConfig(tlsConfig)))
+
+		_, err = getHTTP(addr, &tls.Config{RootCAs: roots, ServerName: "optimus"})
+		assert.NotNil(t, err)
+
+		otherClient := newTestCertificate(t, "client", otherCA)
+		_, err = getHTTP(addr, &tls.Config{
+			RootCAs:    roots,
+			ServerName: "optimus",
+			Certificates: []tls.Certificate{
+				{Certificate: [][]byte{otherClient.der}, PrivateKey: otherClient.key},
+			},
+		})
+		assert.NotNil(t, err)
+
+		client := newTestCertificate(t, "client", ca)
+		body, err := getHTTP(addr, &tls.Config{
+			RootCAs:    roots,
+			ServerName: "optimus",
+			Certificates: []tls.Certificate{
+				{Certificate: [][]byte{client.der}, PrivateKey: client.key},
+			},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "pong", body)
+	})
+	t.Run("should reject gateway connections to a different server", func(t *testing.T) {
+		tlsConfig, err := newServerTLSConfig(serverCertFile, serverKeyFile, "")
+		assert.Nil(t, err)
+		otherCertFile, otherKeyFile := newTestCertificate(t, "optimus", ca).write(t, dir, "other")
+		otherTLSConfig, err := newServerTLSConfig(otherCertFile, otherKeyFile, "")
+		assert.Nil(t, err)
+		addr := serve(t, otherTLSConfig)
+
+		assert.NotNil(t, checkGRPC(addr, newGatewayTLSConfig(tlsConfig)))
+	})
+	t.Run("should fail if key file is missing", func(t *testing.T) {
+		_, err := newServerTLSConfig(serverCertFile, "", "")
+		assert.NotNil(t, err)
+	})
+	t.Run("should fail if ca file has no certificates", func(t *testing.T) {
+		_, err := newServerTLSConfig(serverCertFile, serverKeyFile, serverKeyFile)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+
+	"github.com/odpf/optimus/config"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+const tracingServiceName = "optimus"
+
+// initTracer registers a global tracer provider exporting spans to the
+// configured exporter, the returned func flushes pending spans on shutdown.
+// Exporter specific OTEL_EXPORTER_* environment variables are honoured as well
+func initTracer(ctx context.Context, conf config.TracingConfig) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch conf.Exporter {
+	case "otlp":
+		var opts []otlptracegrpc.Option
+		if conf.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(conf.Endpoint))
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	case "jaeger":
+		var opts []jaeger.CollectorEndpointOption
+		if conf.Endpoint != "" {
+			opts = append(opts, jaeger.WithEndpoint(conf.Endpoint))
+		}
+		exporter, err = jaeger.New(jaeger.WithCollectorEndpoint(opts...))
+	default:
+		return nil, errors.Errorf("unsupported tracing exporter: %s", conf.Exporter)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create %s trace exporter", conf.Exporter)
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if conf.SampleRatio > 0 {
+		sampler = sdktrace.TraceIDRatioBased(conf.SampleRatio)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(tracingServiceName),
+			semconv.ServiceVersionKey.String(config.Version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
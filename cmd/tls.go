@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/odpf/optimus/config"
+	"github.com/pkg/errors"
+)
+
+// newClientTLSConfig verifies the server against the authorities of
+// CACertFile, or the system pool if not set, and presents the client
+// certificate when CertFile and KeyFile are set for mutual tls
+func newClientTLSConfig(conf config.ClientTLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if conf.CACertFile != "" {
+		caCert, err := ioutil.ReadFile(conf.CACertFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read tls ca certificate")
+		}
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(caCert) {
+			return nil, errors.Errorf("no certificates found in %s", conf.CACertFile)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	if conf.CertFile != "" || conf.KeyFile != "" {
+		if conf.CertFile == "" || conf.KeyFile == "" {
+			return nil, errors.Errorf("both %s and %s are required for mutual tls",
+				config.KeyTLSCertFile, config.KeyTLSKeyFile)
+		}
+		cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load tls key pair")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
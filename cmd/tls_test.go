@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/config"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// writeTestCertificate stores a certificate for name signed by parent, or a
+// self signed ca if parent is nil, as pem files in dir
+func writeTestCertificate(t *testing.T, dir, name string, parent *tls.Certificate) (tls.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{name},
+	}
+	signer, signerKey := template, interface{}(key)
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	assert.Nil(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.Nil(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, certFile, keyFile
+}
+
+func TestCreateConnectionTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caCertFile, _ := writeTestCertificate(t, dir, "optimus-ca", nil)
+	server, _, _ := writeTestCertificate(t, dir, "localhost", &ca)
+	_, clientCertFile, clientKeyFile := writeTestCertificate(t, dir, "client", &ca)
+	otherCA, otherCACertFile, _ := writeTestCertificate(t, dir, "other-ca", nil)
+	_, otherClientCertFile, otherClientKeyFile := writeTestCertificate(t, dir, "other-client", &otherCA)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.Leaf)
+	grpcServer := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{server},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+	_, port, _ := net.SplitHostPort(lis.Addr().String())
+	host := net.JoinHostPort("localhost", port)
+
+	connect := func(conf config.ClientTLSConfig) error {
+		previous := clientTLS
+		clientTLS = conf
+		defer func() { clientTLS = previous }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		conn, err := createConnection(ctx, host)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	t.Run("should connect with a client certificate signed by the ca of the server", func(t *testing.T) {
+		assert.Nil(t, connect(config.ClientTLSConfig{
+			CACertFile: caCertFile,
+			CertFile:   clientCertFile,
+			KeyFile:    clientKeyFile,
+		}))
+	})
+	t.Run("should not connect without tls", func(t *testing.T) {
+		assert.NotNil(t, connect(config.ClientTLSConfig{}))
+	})
+	t.Run("should not connect without a client certificate", func(t *testing.T) {
+		assert.NotNil(t, connect(config.ClientTLSConfig{CACertFile: caCertFile}))
+	})
+	t.Run("should not connect with a client certificate of another ca", func(t *testing.T) {
+		assert.NotNil(t, connect(config.ClientTLSConfig{
+			CACertFile: caCertFile,
+			CertFile:   otherClientCertFile,
+			KeyFile:    otherClientKeyFile,
+		}))
+	})
+	t.Run("should not connect to servers signed by another ca", func(t *testing.T) {
+		assert.NotNil(t, connect(config.ClientTLSConfig{
+			CACertFile: otherCACertFile,
+			CertFile:   clientCertFile,
+			KeyFile:    clientKeyFile,
+		}))
+	})
+	t.Run("should fail if key file is missing", func(t *testing.T) {
+		_, err := newClientTLSConfig(config.ClientTLSConfig{CACertFile: caCertFile, CertFile: clientCertFile})
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ProjectVariablesFileName declares the variables shared by the jobs of the
+// project as key: value pairs, it's read from the root of the project
+const ProjectVariablesFileName = "variables.yaml"
+
+// readProjectVariables returns no variables when the project doesn't declare
+// them
+func readProjectVariables() (models.ProjectVariables, error) {
+	rawFile, err := ioutil.ReadFile(ProjectVariablesFileName)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", ProjectVariablesFileName)
+	}
+	variables := models.ProjectVariables{}
+	if err := yaml.Unmarshal(rawFile, &variables); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", ProjectVariablesFileName)
+	}
+	return variables, nil
+}
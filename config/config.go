@@ -8,8 +8,13 @@ import (
 )
 
 var (
-	KeyVersion = "version"
-	KeyHost    = "host"
+	KeyVersion         = "version"
+	KeyHost            = "host"
+	KeyGRPCCompression = "grpc_compression"
+
+	KeyTLSCACertFile = "tls.ca_cert_file"
+	KeyTLSCertFile   = "tls.cert_file"
+	KeyTLSKeyFile    = "tls.key_file"
 
 	KeyJobPath = "job.path"
 
@@ -18,6 +23,9 @@ var (
 
 	KeyProjectConfigGlobal = "config.global"
 	KeyProjectConfigLocal  = "config.local"
+	KeyProjectFeatureFlags = "config.feature_flags"
+	KeyProjectWebhooks     = "config.webhooks"
+	KeyProjectBlackouts    = "config.blackout_windows"
 
 	KeyLogLevel  = "log.level"
 	KeyLogFormat = "log.format"
@@ -25,10 +33,12 @@ var (
 	KeyServeHost                    = "serve.host"
 	KeyServePort                    = "serve.port"
 	KeyServeAppKey                  = "serve.app_key"
+	KeyServePreviousAppKey          = "serve.previous_app_key"
 	KeyServeIngressHost             = "serve.ingress_host"
 	KeyServeDBDSN                   = "serve.db.dsn"
 	KeyServeDBMaxIdleConnection     = "serve.db.max_idle_connection"
 	KeyServeDBMaxOpenConnection     = "serve.db.max_open_connection"
+	KeyServeDBPoolerMode            = "serve.db.pooler_mode"
 	KeyServeMetadataWriterBatchSize = "serve.metadata.writer_batch_size"
 	KeyServeMetadataKafkaBrokers    = "serve.metadata.kafka_brokers"
 	KeyServeMetadataKafkaJobTopic   = "serve.metadata.kafka_job_topic"
@@ -36,10 +46,49 @@ var (
 	KeyServeReplayNumWorkers        = "serve.replay_num_workers"
 	KeyServeReplayWorkerTimeoutSecs = "serve.replay_worker_timeout_secs"
 	KeyServeReplayRunTimeoutSecs    = "serve.replay_run_timeout_secs"
-
-	KeySchedulerName = "scheduler.name"
+	KeyServeMaxBackfillLookbackDays = "serve.max_backfill_lookback_days"
+	KeyServeReplayMaxRangeDays      = "serve.replay_max_range_days"
+	KeyServeAutoDeployInterval      = "serve.auto_deploy_interval"
+	KeyServeSLACheckInterval        = "serve.sla_check_interval"
+	KeyServeTLSCertFile             = "serve.tls_cert_file"
+	KeyServeTLSKeyFile              = "serve.tls_key_file"
+	KeyServeTLSCACertFile           = "serve.tls_ca_cert_file"
+	KeyServeMaxRequestsPerSecond    = "serve.max_requests_per_second"
+	KeyServeMaxBurst                = "serve.max_burst"
+	KeyServeCallerRequestsPerSecond = "serve.caller_requests_per_second"
+	KeyServeAuthJWKSURL             = "serve.auth.jwks_url"
+	KeyServeAuthPublicKeyFile       = "serve.auth.public_key_file"
+	KeyServeAuthIssuer              = "serve.auth.issuer"
+	KeyServeAuthAudience            = "serve.auth.audience"
+	KeyServeSecretRevealPassphrase  = "serve.secret_reveal_passphrase"
+	KeyServeSecretVersionRetention  = "serve.secret_version_retention"
+	KeyServeEmbedJobDocs            = "serve.embed_job_docs"
+	KeyServeDeadInstanceThreshold   = "serve.dead_instance_threshold"
+	KeyServeForceStopOnTimeout      = "serve.force_stop_on_timeout"
+	KeyServePagerDutyRoutingKey     = "serve.pagerduty_routing_key"
+	KeyServeCacheControl            = "serve.cache_control"
+	KeyServeTracingExporter         = "serve.tracing.exporter"
+	KeyServeTracingEndpoint         = "serve.tracing.endpoint"
+	KeyServeTracingSampleRatio      = "serve.tracing.sample_ratio"
+	KeyServeDefaultQuotaMaxJobCount = "serve.default_quota.max_job_count"
+	KeyServeDefaultQuotaMinInterval = "serve.default_quota.min_schedule_interval"
+	KeyServeTemplateEngine          = "serve.template_engine"
+	KeyServeAssetCacheTTL           = "serve.asset_cache_ttl"
+	KeyServeBootstrapConcurrency    = "serve.bootstrap_concurrency"
+	KeyServeShutdownWaitSeconds     = "serve.shutdown_wait_seconds"
+	KeyServeConnectionIdleTimeout   = "serve.connection_idle_timeout"
+	KeyServeProjectCacheSize        = "serve.project_cache_size"
+	KeyServeProjectCacheTTL         = "serve.project_cache_ttl"
+	KeyServeRedisURL                = "serve.redis_url"
+	KeyServeFileStorageRoot         = "serve.file_storage_root"
+
+	KeySchedulerName                  = "scheduler.name"
+	KeySchedulerMinimumAirflowVersion = "scheduler.minimum_airflow_version"
+	KeySchedulerStrictVersionCheck    = "scheduler.strict_version_check"
 
 	KeyAdminEnabled = "admin.enabled"
+
+	KeyExecutorTasks = "executor.tasks"
 )
 
 type Optimus struct {
@@ -50,15 +99,40 @@ type Optimus struct {
 	Version int `yaml:"version"`
 	// optimus server host
 	Host string `yaml:"host"`
+	// compression of grpc messages sent to optimus, one of gzip or snappy,
+	// messages are sent uncompressed if not set
+	GRPCCompression string `yaml:"grpc_compression"`
+	// tls used to connect optimus
+	TLS ClientTLSConfig `yaml:"tls"`
 
 	Job       Job           `yaml:"job"`
 	Datastore []Datastore   `yaml:"datastore"`
 	Config    ProjectConfig `yaml:"config"`
 
+	// external servers tasks are executed on
+	Executor ExecutorConfig `yaml:"executor"`
+
 	k      *koanf.Koanf
 	parser koanf.Parser
 }
 
+type ClientTLSConfig struct {
+	// pem encoded certificate authorities used to verify the server
+	// certificate, the system pool is used if not set
+	CACertFile string `yaml:"ca_cert_file"`
+
+	// pem encoded client certificate and private key presented to
+	// servers requiring mutual tls
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+// Enabled is true if any of the tls files is set, connections are
+// made without tls otherwise
+func (c ClientTLSConfig) Enabled() bool {
+	return c.CACertFile != "" || c.CertFile != "" || c.KeyFile != ""
+}
+
 type Datastore struct {
 	// type could be bigquery/postgres/gcs
 	Type string `yaml:"type" koanf:"type"`
@@ -78,6 +152,35 @@ type ProjectConfig struct {
 
 	// per namespace
 	Local map[string]string `yaml:"local"`
+
+	// per project, switch parts of compiled jobs
+	FeatureFlags map[string]bool `yaml:"feature_flags"`
+
+	// per project, called back on lifecycle events of jobs
+	Webhooks []Webhook `yaml:"webhooks"`
+
+	// per project, periods scheduled runs of jobs are skipped in
+	BlackoutWindows []BlackoutWindow `yaml:"blackout_windows"`
+}
+
+type Webhook struct {
+	URL string `yaml:"url" koanf:"url"`
+
+	// name of the project secret payloads are signed with
+	SecretName string `yaml:"secret_name" koanf:"secret_name"`
+
+	// job_deployed, job_failure or job_success, all events if empty
+	Events []string `yaml:"events" koanf:"events"`
+}
+
+type BlackoutWindow struct {
+	// cron expression of when the window starts in UTC
+	Start string `yaml:"start" koanf:"start"`
+
+	Duration time.Duration `yaml:"duration" koanf:"duration"`
+
+	// regular expression of the names of the jobs skipped, all jobs if empty
+	JobPattern string `yaml:"job_pattern" koanf:"job_pattern"`
 }
 
 type LogConfig struct {
@@ -100,11 +203,163 @@ type ServerConfig struct {
 	// random 32 character hash used for encrypting secrets
 	AppKey string `yaml:"app_key"`
 
+	// app key used before the current one, secrets not rotated to the
+	// current key yet are decrypted with it
+	PreviousAppKey string `yaml:"previous_app_key"`
+
 	DB                      DBConfig       `yaml:"db"`
 	Metadata                MetadataConfig `yaml:"metadata"`
 	ReplayNumWorkers        int            `yaml:"replay_num_workers"`
 	ReplayWorkerTimeoutSecs time.Duration  `yaml:"replay_worker_timeout_secs"`
 	ReplayRunTimeoutSecs    time.Duration  `yaml:"replay_run_timeout_secs"`
+	MaxBackfillLookbackDays int            `yaml:"max_backfill_lookback_days"`
+
+	// maximum days a single replay can cover, unlimited if not set
+	ReplayMaxRangeDays int `yaml:"replay_max_range_days"`
+
+	// interval to deploy job specs modified and undeploy the ones deleted
+	// since the last deployment e.g. 5m, auto deployment is disabled if not set
+	AutoDeployInterval time.Duration `yaml:"auto_deploy_interval"`
+
+	// interval to check that runs of jobs having an sla duration started
+	// in time, e.g. 5m, sla monitoring is disabled if not set
+	SLACheckInterval time.Duration `yaml:"sla_check_interval"`
+
+	// pem encoded certificate and private key to serve over tls,
+	// server runs without tls if not set
+	TLSCertFile string `yaml:"tls_cert_file"`
+	TLSKeyFile  string `yaml:"tls_key_file"`
+
+	// pem encoded certificate authorities used to verify client
+	// certificates, enables mutual tls when set
+	TLSCACertFile string `yaml:"tls_ca_cert_file"`
+
+	Auth AuthConfig `yaml:"auth"`
+
+	// phrase callers have to confirm to read back secret values,
+	// secrets can't be revealed if not set
+	SecretRevealPassphrase string `yaml:"secret_reveal_passphrase"`
+
+	// number of previous values kept for each secret, defaults to 5
+	SecretVersionRetention int `yaml:"secret_version_retention"`
+
+	// insert a docstring with the description, owner, labels and
+	// dependencies of the job in compiled jobs
+	EmbedJobDocs bool `yaml:"embed_job_docs"`
+
+	// cancel grpc calls still being served once the server has waited for
+	// them while shutting down, the server keeps waiting if not set
+	ForceStopOnTimeout bool `yaml:"force_stop_on_timeout"`
+
+	// integration key of the pagerduty service paged for incidents of the
+	// server itself like a shutdown timing out, not paged if not set
+	PagerDutyRoutingKey string `yaml:"pagerduty_routing_key"`
+
+	// running instances older than the threshold are failed on startup
+	// and on reconciliation if the scheduler has no active run for them,
+	// defaults to 2h
+	DeadInstanceThreshold time.Duration `yaml:"dead_instance_threshold"`
+
+	// http read routes whose responses can be cached by clients and
+	// proxies, responses of other routes aren't cacheable
+	CacheControl []CacheControlRoute `yaml:"cache_control"`
+
+	// calls allowed per second for each caller of a project, rate limiting is
+	// disabled if not set. Burst defaults to the requests per second
+	MaxRequestsPerSecond int `yaml:"max_requests_per_second"`
+	MaxBurst             int `yaml:"max_burst"`
+
+	// calls allowed per second for each caller across projects, defaults to
+	// the requests per second
+	CallerRequestsPerSecond int `yaml:"caller_requests_per_second"`
+
+	Tracing TracingConfig `yaml:"tracing"`
+
+	// quota of projects that haven't been set one, projects are not
+	// limited if not set
+	DefaultQuota QuotaConfig `yaml:"default_quota"`
+
+	// engine job assets are rendered with, go or jinja, defaults to go
+	TemplateEngine string `yaml:"template_engine"`
+
+	// duration assets compiled for a run of a job are reused for by its
+	// tasks and hooks while the job is unchanged, defaults to 5m
+	AssetCacheTTL time.Duration `yaml:"asset_cache_ttl"`
+
+	// projects whose scheduler is bootstrapped at the same time when the
+	// server starts, defaults to 5
+	BootstrapConcurrency int `yaml:"bootstrap_concurrency"`
+
+	// seconds the server waits for calls being served on shutdown, between
+	// 5 and 300, defaults to 30
+	ShutdownWaitSeconds int `yaml:"shutdown_wait_seconds"`
+
+	// connections without a call for this long are closed, defaults to 2m
+	ConnectionIdleTimeout time.Duration `yaml:"connection_idle_timeout"`
+
+	// projects kept in memory after being read, the least recently used
+	// are evicted first, defaults to 100
+	ProjectCacheSize int `yaml:"project_cache_size"`
+
+	// duration projects are kept in memory for, defaults to 1m
+	ProjectCacheTTL time.Duration `yaml:"project_cache_ttl"`
+
+	// redis projects are cached in instead of memory, shared by all servers
+	// using it. e.g. redis://:password@localhost:6379/0
+	RedisURL string `yaml:"redis_url"`
+
+	// absolute path of the directory projects can store jobs in with
+	// file:// storage paths, file storage is disabled if not set
+	FileStorageRoot string `yaml:"file_storage_root"`
+}
+
+// QuotaConfig limits the jobs a project can register, limits are not
+// applied if zero
+type QuotaConfig struct {
+	MaxJobCount int `yaml:"max_job_count"`
+
+	// shortest time allowed between runs of a job e.g. 1h
+	MinScheduleInterval time.Duration `yaml:"min_schedule_interval"`
+}
+
+// CacheControlRoute sets the max-age of responses to GET requests of a path
+type CacheControlRoute struct {
+	// path served over http including the /api prefix, segments in
+	// braces match any value e.g. /api/v1/project/{project_name}/job
+	Path string `yaml:"path" koanf:"path"`
+
+	// duration responses are cached for e.g. 30s
+	MaxAge time.Duration `yaml:"max_age" koanf:"max_age"`
+}
+
+// TracingConfig enables exporting of opentelemetry traces when an
+// exporter is set
+type TracingConfig struct {
+	// exporter to send spans with, one of otlp or jaeger
+	Exporter string `yaml:"exporter"`
+
+	// host:port of the otlp grpc receiver or url of the jaeger
+	// collector, exporter defaults are used if not set
+	Endpoint string `yaml:"endpoint"`
+
+	// fraction of traces sampled between 0 and 1, all traces
+	// are sampled if not set
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// AuthConfig enables bearer token authentication of grpc calls when
+// either a jwks url or a public key file is set
+type AuthConfig struct {
+	// url of the json web key set used to verify bearer tokens
+	JWKSURL string `yaml:"jwks_url"`
+
+	// pem encoded rsa or ecdsa public key used to verify bearer
+	// tokens when jwks url is not set
+	PublicKeyFile string `yaml:"public_key_file"`
+
+	// expected iss and aud claims of tokens, not verified if empty
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
 }
 
 type DBConfig struct {
@@ -117,6 +372,10 @@ type DBConfig struct {
 
 	// maximum allowed open DB connections
 	MaxOpenConnection int `yaml:"max_open_connection"`
+
+	// pooler between optimus and the DB, "pgbouncer" when connecting
+	// through pgbouncer in transaction mode
+	PoolerMode string `yaml:"pooler_mode"`
 }
 
 type MetadataConfig struct {
@@ -135,27 +394,78 @@ type MetadataConfig struct {
 
 type SchedulerConfig struct {
 	Name string `yaml:"name"`
+
+	// oldest airflow version compiled jobs are compatible with, a warning
+	// is logged at boot for projects deploying to older versions
+	MinimumAirflowVersion string `yaml:"minimum_airflow_version"`
+
+	// block deployment of jobs to projects with an incompatible
+	// scheduler version instead of only logging a warning
+	StrictVersionCheck bool `yaml:"strict_version_check"`
 }
 
 type AdminConfig struct {
 	Enabled bool `yaml:"enabled"`
 }
 
+type ExecutorConfig struct {
+	Tasks []TaskExecutor `yaml:"tasks"`
+}
+
+type TaskExecutor struct {
+	// name of the task, e.g. bq2bq
+	Name string `yaml:"name" koanf:"name"`
+
+	// host:port of a running server of the task
+	Address string `yaml:"address" koanf:"address"`
+
+	// path of a plugin binary serving the task, launched when address
+	// is not set
+	Command string `yaml:"command" koanf:"command"`
+}
+
 func (o Optimus) GetVersion() string {
 	return o.k.String(KeyVersion)
 }
 
 func (o Optimus) GetProjectConfig() ProjectConfig {
 	return ProjectConfig{
-		Global: o.k.StringMap(KeyProjectConfigGlobal),
-		Local:  o.k.StringMap(KeyProjectConfigLocal),
+		Global:          o.k.StringMap(KeyProjectConfigGlobal),
+		Local:           o.k.StringMap(KeyProjectConfigLocal),
+		FeatureFlags:    o.k.BoolMap(KeyProjectFeatureFlags),
+		Webhooks:        o.getWebhooks(),
+		BlackoutWindows: o.getBlackoutWindows(),
 	}
 }
 
+func (o Optimus) getWebhooks() []Webhook {
+	webhooks := []Webhook{}
+	_ = o.k.Unmarshal(KeyProjectWebhooks, &webhooks)
+	return webhooks
+}
+
+func (o Optimus) getBlackoutWindows() []BlackoutWindow {
+	windows := []BlackoutWindow{}
+	_ = o.k.Unmarshal(KeyProjectBlackouts, &windows)
+	return windows
+}
+
 func (o Optimus) GetHost() string {
 	return o.k.String(KeyHost)
 }
 
+func (o Optimus) GetGRPCCompression() string {
+	return o.eKs(KeyGRPCCompression)
+}
+
+func (o Optimus) GetTLS() ClientTLSConfig {
+	return ClientTLSConfig{
+		CACertFile: o.eKs(KeyTLSCACertFile),
+		CertFile:   o.eKs(KeyTLSCertFile),
+		KeyFile:    o.eKs(KeyTLSKeyFile),
+	}
+}
+
 func (o Optimus) GetJob() Job {
 	return Job{
 		Path: o.k.String(KeyJobPath),
@@ -177,14 +487,16 @@ func (o Optimus) GetLog() LogConfig {
 
 func (o Optimus) GetServe() ServerConfig {
 	return ServerConfig{
-		Port:        o.k.Int(KeyServePort),
-		Host:        o.k.String(KeyServeHost),
-		IngressHost: o.eKs(KeyServeIngressHost),
-		AppKey:      o.eKs(KeyServeAppKey),
+		Port:           o.k.Int(KeyServePort),
+		Host:           o.k.String(KeyServeHost),
+		IngressHost:    o.eKs(KeyServeIngressHost),
+		AppKey:         o.eKs(KeyServeAppKey),
+		PreviousAppKey: o.eKs(KeyServePreviousAppKey),
 		DB: DBConfig{
 			DSN:               o.k.String(KeyServeDBDSN),
 			MaxIdleConnection: o.eKi(KeyServeDBMaxIdleConnection),
 			MaxOpenConnection: o.eKi(KeyServeDBMaxOpenConnection),
+			PoolerMode:        o.eKs(KeyServeDBPoolerMode),
 		},
 		Metadata: MetadataConfig{
 			WriterBatchSize: o.eKi(KeyServeMetadataWriterBatchSize),
@@ -195,12 +507,62 @@ func (o Optimus) GetServe() ServerConfig {
 		ReplayNumWorkers:        o.k.Int(KeyServeReplayNumWorkers),
 		ReplayWorkerTimeoutSecs: time.Second * time.Duration(o.k.Int(KeyServeReplayWorkerTimeoutSecs)),
 		ReplayRunTimeoutSecs:    time.Second * time.Duration(o.k.Int(KeyServeReplayRunTimeoutSecs)),
+		MaxBackfillLookbackDays: o.eKi(KeyServeMaxBackfillLookbackDays),
+		ReplayMaxRangeDays:      o.eKi(KeyServeReplayMaxRangeDays),
+		AutoDeployInterval:      o.eKd(KeyServeAutoDeployInterval),
+		SLACheckInterval:        o.eKd(KeyServeSLACheckInterval),
+		TLSCertFile:             o.eKs(KeyServeTLSCertFile),
+		TLSKeyFile:              o.eKs(KeyServeTLSKeyFile),
+		TLSCACertFile:           o.eKs(KeyServeTLSCACertFile),
+		MaxRequestsPerSecond:    o.eKi(KeyServeMaxRequestsPerSecond),
+		MaxBurst:                o.eKi(KeyServeMaxBurst),
+		CallerRequestsPerSecond: o.eKi(KeyServeCallerRequestsPerSecond),
+		Auth: AuthConfig{
+			JWKSURL:       o.eKs(KeyServeAuthJWKSURL),
+			PublicKeyFile: o.eKs(KeyServeAuthPublicKeyFile),
+			Issuer:        o.eKs(KeyServeAuthIssuer),
+			Audience:      o.eKs(KeyServeAuthAudience),
+		},
+		SecretRevealPassphrase: o.eKs(KeyServeSecretRevealPassphrase),
+		SecretVersionRetention: o.eKi(KeyServeSecretVersionRetention),
+		EmbedJobDocs:           o.eKb(KeyServeEmbedJobDocs),
+		DeadInstanceThreshold:  o.eKd(KeyServeDeadInstanceThreshold),
+		ForceStopOnTimeout:     o.eKb(KeyServeForceStopOnTimeout),
+		PagerDutyRoutingKey:    o.eKs(KeyServePagerDutyRoutingKey),
+		CacheControl:           o.getCacheControl(),
+		Tracing: TracingConfig{
+			Exporter:    o.k.String(KeyServeTracingExporter),
+			Endpoint:    o.k.String(KeyServeTracingEndpoint),
+			SampleRatio: o.eKf(KeyServeTracingSampleRatio),
+		},
+		DefaultQuota: QuotaConfig{
+			MaxJobCount:         o.eKi(KeyServeDefaultQuotaMaxJobCount),
+			MinScheduleInterval: o.eKd(KeyServeDefaultQuotaMinInterval),
+		},
+		TemplateEngine: o.eKs(KeyServeTemplateEngine),
+		AssetCacheTTL:  o.eKd(KeyServeAssetCacheTTL),
+
+		BootstrapConcurrency:  o.eKi(KeyServeBootstrapConcurrency),
+		ShutdownWaitSeconds:   o.eKi(KeyServeShutdownWaitSeconds),
+		ConnectionIdleTimeout: o.eKd(KeyServeConnectionIdleTimeout),
+		ProjectCacheSize:      o.eKi(KeyServeProjectCacheSize),
+		ProjectCacheTTL:       o.eKd(KeyServeProjectCacheTTL),
+		RedisURL:              o.eKs(KeyServeRedisURL),
+		FileStorageRoot:       o.eKs(KeyServeFileStorageRoot),
 	}
 }
 
+func (o Optimus) getCacheControl() []CacheControlRoute {
+	routes := []CacheControlRoute{}
+	_ = o.k.Unmarshal(KeyServeCacheControl, &routes)
+	return routes
+}
+
 func (o Optimus) GetScheduler() SchedulerConfig {
 	return SchedulerConfig{
-		Name: o.k.String(KeySchedulerName),
+		Name:                  o.k.String(KeySchedulerName),
+		MinimumAirflowVersion: o.eKs(KeySchedulerMinimumAirflowVersion),
+		StrictVersionCheck:    o.eKb(KeySchedulerStrictVersionCheck),
 	}
 }
 
@@ -210,6 +572,14 @@ func (o Optimus) GetAdmin() AdminConfig {
 	}
 }
 
+func (o Optimus) GetExecutor() ExecutorConfig {
+	tasks := []TaskExecutor{}
+	_ = o.k.Unmarshal(KeyExecutorTasks, &tasks)
+	return ExecutorConfig{
+		Tasks: tasks,
+	}
+}
+
 // eKs replaces . with _ to support buggy koanf config loader from ENV
 // this should be used in all keys where underscore is used
 func (o Optimus) eKs(e string) string {
@@ -235,3 +605,42 @@ func (o Optimus) eKi(e string) int {
 	}
 	return res
 }
+
+// eKd reads durations like 30s or 5m the same way as eKi, a zero duration
+// set in env can't override the config file
+func (o Optimus) eKd(e string) time.Duration {
+	// read with default key - used in config file
+	res := o.k.Duration(e)
+
+	// read with replaced key - used in env
+	if v := o.k.Duration(strings.Replace(e, "_", ".", -1)); v != 0 {
+		res = v
+	}
+	return res
+}
+
+// eKb reads flags the same way as eKs, the env value wins whenever it is set
+// so false in env can turn off a flag enabled in the config file
+func (o Optimus) eKb(e string) bool {
+	// read with default key - used in config file
+	res := o.k.Bool(e)
+
+	// read with replaced key - used in env
+	if envKey := strings.Replace(e, "_", ".", -1); envKey != e && o.k.Exists(envKey) {
+		res = o.k.Bool(envKey)
+	}
+	return res
+}
+
+// eKf reads fractional numbers like rates the same way as eKi, zero set in
+// env can't override the config file
+func (o Optimus) eKf(e string) float64 {
+	// read with default key - used in config file
+	res := o.k.Float64(e)
+
+	// read with replaced key - used in env
+	if v := o.k.Float64(strings.Replace(e, "_", ".", -1)); v != 0 {
+		res = v
+	}
+	return res
+}
@@ -3,6 +3,8 @@ package config
 type Provider interface {
 	GetVersion() string
 	GetHost() string
+	GetGRPCCompression() string
+	GetTLS() ClientTLSConfig
 	GetJob() Job
 	GetDatastore() []Datastore
 	GetProjectConfig() ProjectConfig
@@ -11,4 +13,5 @@ type Provider interface {
 	GetServe() ServerConfig
 	GetScheduler() SchedulerConfig
 	GetAdmin() AdminConfig
+	GetExecutor() ExecutorConfig
 }
@@ -25,12 +25,39 @@ var (
 	FileExtension   = "yaml"
 )
 
-// Load configuration file from following paths
+const (
+	// FileFlag and FileEnv select the config file loaded instead of looking
+	// up .optimus.yaml, the flag takes precedence over the env
+	FileFlag = "config-file"
+	FileEnv  = "CONFIG_FILE"
+)
+
+// LookupFile returns the config file selected with --config-file in args or
+// with CONFIG_FILE, empty if neither is set
+func LookupFile(args []string) string {
+	flag := "--" + FileFlag
+	for idx, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == flag && idx+1 < len(args) {
+			return args[idx+1]
+		}
+		if strings.HasPrefix(arg, flag+"=") {
+			return strings.TrimPrefix(arg, flag+"=")
+		}
+	}
+	return os.Getenv(FileEnv)
+}
+
+// Load configuration file from configFile if set, otherwise from the first
+// of following paths having one
 // ./
 // <exec>/
 // ~/.config/
 // ~/.optimus/
-func InitOptimus() (*Optimus, error) {
+// Values are taken from env vars first, then the file and then defaults
+func InitOptimus(configFile string) (*Optimus, error) {
 	configuration := &Optimus{
 		k:      koanf.New("."),
 		parser: yaml.Parser(),
@@ -61,6 +88,11 @@ func InitOptimus() (*Optimus, error) {
 		KeySchedulerName:                "airflow2",
 		KeyServeReplayNumWorkers:        1,
 		KeyServeReplayWorkerTimeoutSecs: 120,
+		KeyServeMaxBackfillLookbackDays: 30,
+		KeyServeTemplateEngine:          "go",
+		KeyServeBootstrapConcurrency:    5,
+		KeyServeShutdownWaitSeconds:     30,
+		KeyServeConnectionIdleTimeout:   "2m",
 	}, "."), nil); err != nil {
 		return nil, errors.Wrap(err, "k.Load: error loading config defaults")
 	}
@@ -68,6 +100,19 @@ func InitOptimus() (*Optimus, error) {
 	// Load yaml config
 	fs := afero.NewOsFs()
 	pathUsed := ""
+	if configFile != "" {
+		if ok, err := exists(fs, configFile); err != nil {
+			return nil, errors.Wrapf(err, "unable to read config file %s", configFile)
+		} else if !ok {
+			return nil, errors.Errorf("config file %s not found", configFile)
+		}
+		// the file given is used instead of looking up one
+		configDirs = nil
+		if err := configuration.k.Load(file.Provider(configFile), configuration.parser); err != nil {
+			return nil, errors.Wrapf(err, "k.Load: error loading config from %s", configFile)
+		}
+		pathUsed = configFile
+	}
 	for _, path := range configDirs {
 		path = filepath.Join(path, fmt.Sprintf("%s.%s", FileName, FileExtension))
 		if ok, err := exists(fs, path); !ok || err != nil {
@@ -96,3 +96,7 @@ func Logger(k, v string) *logrus.Entry {
 func Level() logrus.Level {
 	return log.Level
 }
+
+func SetLevel(level logrus.Level) {
+	log.SetLevel(level)
+}
@@ -0,0 +1,44 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/odpf/optimus/core/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	t.Run("should start spans as children of the span in context", func(t *testing.T) {
+		ctx, parent := tracing.Start(context.Background(), "parent")
+		_, child := tracing.Start(ctx, "child", attribute.String("job", "foo"))
+		tracing.End(child, nil)
+		tracing.End(parent, nil)
+
+		spans := recorder.Ended()
+		childSpan := spans[len(spans)-2]
+		assert.Equal(t, "child", childSpan.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), childSpan.Parent().SpanID())
+		assert.Equal(t, []attribute.KeyValue{attribute.String("job", "foo")}, childSpan.Attributes())
+		assert.Equal(t, codes.Unset, childSpan.Status().Code)
+	})
+	t.Run("should mark span as failed when ended with an error", func(t *testing.T) {
+		_, span := tracing.Start(context.Background(), "failing")
+		tracing.End(span, errors.New("compilation failed"))
+
+		spans := recorder.Ended()
+		failed := spans[len(spans)-1]
+		assert.Equal(t, codes.Error, failed.Status().Code)
+		assert.Equal(t, "compilation failed", failed.Status().Description)
+		assert.Len(t, failed.Events(), 1)
+	})
+}
@@ -43,7 +43,7 @@ func (cli *BqClientMock) DatasetsInProject(context.Context, string) bqiface.Data
 }
 
 func (cli *BqClientMock) Query(q string) bqiface.Query {
-	panic("not implemented")
+	return cli.Called(q).Get(0).(bqiface.Query)
 }
 
 func (cli *BqClientMock) JobFromID(context.Context, string) (bqiface.Job, error) {
@@ -161,6 +161,32 @@ func (table *BqTableMock) Uploader() bqiface.Uploader {
 	panic("not implemented")
 }
 
+type BqQueryMock struct {
+	mock.Mock
+	bqiface.Query
+}
+
+func (query *BqQueryMock) SetQueryConfig(config bqiface.QueryConfig) {
+	query.Called(config)
+}
+
+func (query *BqQueryMock) Run(ctx context.Context) (bqiface.Job, error) {
+	args := query.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(bqiface.Job), args.Error(1)
+}
+
+type BqJobMock struct {
+	mock.Mock
+	bqiface.Job
+}
+
+func (job *BqJobMock) LastStatus() *bigquery.JobStatus {
+	return job.Called().Get(0).(*bigquery.JobStatus)
+}
+
 type BQClientFactoryMock struct {
 	mock.Mock
 }
@@ -0,0 +1,145 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
+)
+
+var (
+	// job destinations are written as project:dataset.table, the table
+	// resource format project.dataset.table is accepted as well
+	destinationParseRegex = regexp.MustCompile(`^([\w-]+)[:.](\w+)\.([\w-]+)$`)
+)
+
+// TableSchema returns the columns of an existing table
+func (b *BigQuery) TableSchema(ctx context.Context, project models.ProjectSpec, tableName string) ([]models.SchemaField, error) {
+	parsedTableName := destinationParseRegex.FindStringSubmatch(tableName)
+	if len(parsedTableName) < 4 {
+		return nil, errors.Errorf("invalid bigquery table name: %s", tableName)
+	}
+
+	svcAcc, ok := project.Secret.GetByName(SecretName)
+	if !ok || len(svcAcc) == 0 {
+		return nil, errors.New(fmt.Sprintf(errSecretNotFoundStr, SecretName, b.Name()))
+	}
+	client, err := b.ClientFac.New(ctx, svcAcc)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := client.DatasetInProject(parsedTableName[1], parsedTableName[2]).Table(parsedTableName[3]).Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toSchemaFields(meta.Schema, ""), nil
+}
+
+// TableExists reports if the table exists, errors other than the table
+// not being found are returned
+func (b *BigQuery) TableExists(ctx context.Context, project models.ProjectSpec, tableName string) (bool, error) {
+	parsedTableName := destinationParseRegex.FindStringSubmatch(tableName)
+	if len(parsedTableName) < 4 {
+		return false, errors.Errorf("invalid bigquery table name: %s", tableName)
+	}
+
+	svcAcc, ok := project.Secret.GetByName(SecretName)
+	if !ok || len(svcAcc) == 0 {
+		return false, errors.New(fmt.Sprintf(errSecretNotFoundStr, SecretName, b.Name()))
+	}
+	client, err := b.ClientFac.New(ctx, svcAcc)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := client.DatasetInProject(parsedTableName[1], parsedTableName[2]).Table(parsedTableName[3]).Metadata(ctx); err != nil {
+		if metaErr, ok := err.(*googleapi.Error); ok && metaErr.Code == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// QuerySchema returns the columns the query would produce, found through a
+// dry run of the query which is free of cost
+func (b *BigQuery) QuerySchema(ctx context.Context, project models.ProjectSpec, query string) ([]models.SchemaField, error) {
+	queryStats, err := b.dryRunQuery(ctx, project, query)
+	if err != nil {
+		return nil, err
+	}
+	return toSchemaFields(queryStats.Schema, ""), nil
+}
+
+// QueryBytesProcessed returns the bytes the query would read, found through a
+// dry run of the query. On-demand queries are billed by these bytes
+func (b *BigQuery) QueryBytesProcessed(ctx context.Context, project models.ProjectSpec, query string) (int64, error) {
+	queryStats, err := b.dryRunQuery(ctx, project, query)
+	if err != nil {
+		return 0, err
+	}
+	return queryStats.TotalBytesProcessed, nil
+}
+
+func (b *BigQuery) dryRunQuery(ctx context.Context, project models.ProjectSpec, query string) (*bigquery.QueryStatistics, error) {
+	svcAcc, ok := project.Secret.GetByName(SecretName)
+	if !ok || len(svcAcc) == 0 {
+		return nil, errors.New(fmt.Sprintf(errSecretNotFoundStr, SecretName, b.Name()))
+	}
+	client, err := b.ClientFac.New(ctx, svcAcc)
+	if err != nil {
+		return nil, err
+	}
+
+	q := client.Query(query)
+	q.SetQueryConfig(bqiface.QueryConfig{
+		QueryConfig: bigquery.QueryConfig{
+			Q:      query,
+			DryRun: true,
+		},
+	})
+	job, err := q.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobStatus := job.LastStatus()
+	if jobStatus == nil || jobStatus.Statistics == nil {
+		return nil, errors.New("dry run returned no statistics")
+	}
+	if err := jobStatus.Err(); err != nil {
+		return nil, err
+	}
+	queryStats, ok := jobStatus.Statistics.Details.(*bigquery.QueryStatistics)
+	if !ok {
+		return nil, errors.New("dry run returned no query statistics")
+	}
+	return queryStats, nil
+}
+
+// toSchemaFields flattens schema, columns of records are named by their
+// path and repeated columns are typed as arrays
+func toSchemaFields(schema bigquery.Schema, prefix string) []models.SchemaField {
+	var fields []models.SchemaField
+	for _, field := range schema {
+		name := prefix + field.Name
+		fieldType := string(field.Type)
+		if field.Repeated {
+			fieldType = fmt.Sprintf("ARRAY<%s>", fieldType)
+		}
+		fields = append(fields, models.SchemaField{
+			Name: name,
+			Type: fieldType,
+		})
+		if field.Type == bigquery.RecordFieldType {
+			fields = append(fields, toSchemaFields(field.Schema, name+".")...)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,230 @@
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+)
+
+func TestSchema(t *testing.T) {
+	testingContext := context.Background()
+	secret := "some_secret"
+	projectSpec := models.ProjectSpec{
+		Secret: models.ProjectSecrets{{
+			Name:  SecretName,
+			Value: secret,
+		}},
+	}
+	schema := bigquery.Schema{
+		{Name: "id", Type: bigquery.IntegerFieldType, Required: true},
+		{Name: "tags", Type: bigquery.StringFieldType, Repeated: true},
+		{Name: "address", Type: bigquery.RecordFieldType, Schema: bigquery.Schema{
+			{Name: "city", Type: bigquery.StringFieldType},
+		}},
+	}
+	schemaFields := []models.SchemaField{
+		{Name: "id", Type: "INTEGER"},
+		{Name: "tags", Type: "ARRAY<STRING>"},
+		{Name: "address", Type: "RECORD"},
+		{Name: "address.city", Type: "STRING"},
+	}
+
+	t.Run("TableSchema", func(t *testing.T) {
+		t.Run("should return flattened columns of the table", func(t *testing.T) {
+			for _, tableName := range []string{"project:dataset.table", "project.dataset.table"} {
+				bQTable := new(BqTableMock)
+				bQTable.On("Metadata", testingContext).Return(&bigquery.TableMetadata{Schema: schema}, nil)
+				bQDataset := new(BqDatasetMock)
+				bQDataset.On("Table", "table").Return(bQTable)
+				bQClient := new(BqClientMock)
+				bQClient.On("DatasetInProject", "project", "dataset").Return(bQDataset)
+				bQClientFactory := new(BQClientFactoryMock)
+				bQClientFactory.On("New", testingContext, secret).Return(bQClient, nil)
+
+				bq := BigQuery{ClientFac: bQClientFactory}
+				fields, err := bq.TableSchema(testingContext, projectSpec, tableName)
+				assert.Nil(t, err)
+				assert.Equal(t, schemaFields, fields)
+				bQTable.AssertExpectations(t)
+				bQDataset.AssertExpectations(t)
+				bQClient.AssertExpectations(t)
+			}
+		})
+		t.Run("should return error for invalid table names", func(t *testing.T) {
+			bq := BigQuery{}
+			_, err := bq.TableSchema(testingContext, projectSpec, "dataset_table")
+			assert.Equal(t, "invalid bigquery table name: dataset_table", err.Error())
+		})
+		t.Run("should return error when secret not found", func(t *testing.T) {
+			bq := BigQuery{}
+			_, err := bq.TableSchema(testingContext, models.ProjectSpec{}, "project:dataset.table")
+			assert.NotNil(t, err)
+		})
+		t.Run("should return error when reading table metadata fails", func(t *testing.T) {
+			bQTable := new(BqTableMock)
+			defer bQTable.AssertExpectations(t)
+			bQTable.On("Metadata", testingContext).Return((*bigquery.TableMetadata)(nil), errors.New("not found"))
+			bQDataset := new(BqDatasetMock)
+			bQDataset.On("Table", "table").Return(bQTable)
+			bQClient := new(BqClientMock)
+			bQClient.On("DatasetInProject", "project", "dataset").Return(bQDataset)
+			bQClientFactory := new(BQClientFactoryMock)
+			bQClientFactory.On("New", testingContext, secret).Return(bQClient, nil)
+
+			bq := BigQuery{ClientFac: bQClientFactory}
+			_, err := bq.TableSchema(testingContext, projectSpec, "project:dataset.table")
+			assert.Equal(t, "not found", err.Error())
+		})
+	})
+
+	t.Run("TableExists", func(t *testing.T) {
+		newBigQuery := func(t *testing.T, metadataErr error) *BigQuery {
+			bQTable := new(BqTableMock)
+			bQTable.On("Metadata", testingContext).Return(&bigquery.TableMetadata{Schema: schema}, metadataErr)
+			t.Cleanup(func() { bQTable.AssertExpectations(t) })
+			bQDataset := new(BqDatasetMock)
+			bQDataset.On("Table", "table").Return(bQTable)
+			bQClient := new(BqClientMock)
+			bQClient.On("DatasetInProject", "project", "dataset").Return(bQDataset)
+			bQClientFactory := new(BQClientFactoryMock)
+			bQClientFactory.On("New", testingContext, secret).Return(bQClient, nil)
+			return &BigQuery{ClientFac: bQClientFactory}
+		}
+
+		t.Run("should return true for existing tables", func(t *testing.T) {
+			exists, err := newBigQuery(t, nil).TableExists(testingContext, projectSpec, "project.dataset.table")
+			assert.Nil(t, err)
+			assert.True(t, exists)
+		})
+		t.Run("should return false for tables not found", func(t *testing.T) {
+			exists, err := newBigQuery(t, &googleapi.Error{Code: http.StatusNotFound}).TableExists(testingContext, projectSpec, "project:dataset.table")
+			assert.Nil(t, err)
+			assert.False(t, exists)
+		})
+		t.Run("should return error when the table can't be read", func(t *testing.T) {
+			_, err := newBigQuery(t, &googleapi.Error{Code: http.StatusForbidden}).TableExists(testingContext, projectSpec, "project.dataset.table")
+			assert.NotNil(t, err)
+		})
+		t.Run("should return error for invalid table names", func(t *testing.T) {
+			bq := BigQuery{}
+			_, err := bq.TableExists(testingContext, projectSpec, "dataset_table")
+			assert.Equal(t, "invalid bigquery table name: dataset_table", err.Error())
+		})
+	})
+
+	t.Run("QuerySchema", func(t *testing.T) {
+		query := "SELECT id, tags, address FROM `project.dataset.source`"
+		queryConfig := bqiface.QueryConfig{
+			QueryConfig: bigquery.QueryConfig{Q: query, DryRun: true},
+		}
+
+		t.Run("should return flattened columns from a dry run of the query", func(t *testing.T) {
+			bQJob := new(BqJobMock)
+			defer bQJob.AssertExpectations(t)
+			bQJob.On("LastStatus").Return(&bigquery.JobStatus{
+				State: bigquery.Done,
+				Statistics: &bigquery.JobStatistics{
+					Details: &bigquery.QueryStatistics{Schema: schema},
+				},
+			})
+			bQQuery := new(BqQueryMock)
+			defer bQQuery.AssertExpectations(t)
+			bQQuery.On("SetQueryConfig", queryConfig)
+			bQQuery.On("Run", testingContext).Return(bQJob, nil)
+			bQClient := new(BqClientMock)
+			defer bQClient.AssertExpectations(t)
+			bQClient.On("Query", query).Return(bQQuery)
+			bQClientFactory := new(BQClientFactoryMock)
+			bQClientFactory.On("New", testingContext, secret).Return(bQClient, nil)
+
+			bq := BigQuery{ClientFac: bQClientFactory}
+			fields, err := bq.QuerySchema(testingContext, projectSpec, query)
+			assert.Nil(t, err)
+			assert.Equal(t, schemaFields, fields)
+		})
+		t.Run("should return error when the dry run fails", func(t *testing.T) {
+			bQQuery := new(BqQueryMock)
+			defer bQQuery.AssertExpectations(t)
+			bQQuery.On("SetQueryConfig", queryConfig)
+			bQQuery.On("Run", testingContext).Return(nil, errors.New("syntax error"))
+			bQClient := new(BqClientMock)
+			bQClient.On("Query", query).Return(bQQuery)
+			bQClientFactory := new(BQClientFactoryMock)
+			bQClientFactory.On("New", testingContext, secret).Return(bQClient, nil)
+
+			bq := BigQuery{ClientFac: bQClientFactory}
+			_, err := bq.QuerySchema(testingContext, projectSpec, query)
+			assert.Equal(t, "syntax error", err.Error())
+		})
+		t.Run("should return error when the dry run has no query statistics", func(t *testing.T) {
+			bQJob := new(BqJobMock)
+			bQJob.On("LastStatus").Return(&bigquery.JobStatus{State: bigquery.Done})
+			bQQuery := new(BqQueryMock)
+			bQQuery.On("SetQueryConfig", queryConfig)
+			bQQuery.On("Run", testingContext).Return(bQJob, nil)
+			bQClient := new(BqClientMock)
+			bQClient.On("Query", query).Return(bQQuery)
+			bQClientFactory := new(BQClientFactoryMock)
+			bQClientFactory.On("New", testingContext, secret).Return(bQClient, nil)
+
+			bq := BigQuery{ClientFac: bQClientFactory}
+			_, err := bq.QuerySchema(testingContext, projectSpec, query)
+			assert.Equal(t, "dry run returned no statistics", err.Error())
+		})
+	})
+	t.Run("QueryBytesProcessed", func(t *testing.T) {
+		query := "SELECT id FROM `project.dataset.source`"
+		queryConfig := bqiface.QueryConfig{
+			QueryConfig: bigquery.QueryConfig{Q: query, DryRun: true},
+		}
+
+		t.Run("should return the bytes processed by a dry run of the query", func(t *testing.T) {
+			bQJob := new(BqJobMock)
+			defer bQJob.AssertExpectations(t)
+			bQJob.On("LastStatus").Return(&bigquery.JobStatus{
+				State: bigquery.Done,
+				Statistics: &bigquery.JobStatistics{
+					TotalBytesProcessed: 1099511627776,
+					Details: &bigquery.QueryStatistics{
+						TotalBytesProcessed: 1099511627776,
+						Schema:              schema,
+					},
+				},
+			})
+			bQQuery := new(BqQueryMock)
+			defer bQQuery.AssertExpectations(t)
+			bQQuery.On("SetQueryConfig", queryConfig)
+			bQQuery.On("Run", testingContext).Return(bQJob, nil)
+			bQClient := new(BqClientMock)
+			defer bQClient.AssertExpectations(t)
+			bQClient.On("Query", query).Return(bQQuery)
+			bQClientFactory := new(BQClientFactoryMock)
+			bQClientFactory.On("New", testingContext, secret).Return(bQClient, nil)
+
+			bq := BigQuery{ClientFac: bQClientFactory}
+			processed, err := bq.QueryBytesProcessed(testingContext, projectSpec, query)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1099511627776), processed)
+		})
+		t.Run("should return error when the dry run fails", func(t *testing.T) {
+			bQQuery := new(BqQueryMock)
+			bQQuery.On("SetQueryConfig", queryConfig)
+			bQQuery.On("Run", testingContext).Return(nil, errors.New("Table not found: project.dataset.source"))
+			bQClient := new(BqClientMock)
+			bQClient.On("Query", query).Return(bQQuery)
+			bQClientFactory := new(BQClientFactoryMock)
+			bQClientFactory.On("New", testingContext, secret).Return(bQClient, nil)
+
+			bq := BigQuery{ClientFac: bQClientFactory}
+			_, err := bq.QueryBytesProcessed(testingContext, projectSpec, query)
+			assert.Equal(t, "Table not found: project.dataset.source", err.Error())
+		})
+	})
+}
@@ -0,0 +1,195 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
+	hplugin "github.com/hashicorp/go-plugin"
+	"github.com/pkg/errors"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/ext/executor/grpc/taskplugin"
+	"github.com/odpf/optimus/plugin/base"
+)
+
+// TaskServer is where the tasks of a task type are executed, a server
+// already listening on Address or a plugin binary at Command launched
+// with go-plugin when Address is not set
+type TaskServer struct {
+	Address string
+	Command string
+}
+
+// Task is an instance of a task executed on the server of its name
+type Task struct {
+	ProjectName string
+	JobName     string
+
+	// name of the task, e.g. bq2bq
+	Name        string
+	ScheduledAt time.Time
+
+	// compiled assets keyed by file name
+	Assets map[string]string
+
+	// compiled config of the task
+	Config map[string]string
+}
+
+type (
+	// EventTaskLog is a line logged by the server executing a task
+	EventTaskLog struct {
+		Task     string
+		LoggedAt time.Time
+		Message  string
+	}
+)
+
+func (e *EventTaskLog) String() string {
+	return fmt.Sprintf("[%s] %s: %s", e.LoggedAt.Format(time.RFC3339), e.Task, e.Message)
+}
+
+// Executor executes tasks on external servers implementing the
+// taskplugin.TaskPlugin service. Servers are connected to, or launched,
+// on the first task executed on them and kept until Close
+type Executor struct {
+	servers  map[string]TaskServer
+	dialOpts []grpclib.DialOption
+	logger   hclog.Logger
+
+	mu      sync.Mutex
+	clients map[string]taskplugin.TaskPluginClient
+	conns   []*grpclib.ClientConn
+	plugins []*hplugin.Client
+}
+
+// Execute forwards task to the server of its name and notifies observer
+// of the lines it logs, it returns once the server finishes the task
+func (e *Executor) Execute(ctx context.Context, task Task, observer progress.Observer) error {
+	client, err := e.client(ctx, task.Name)
+	if err != nil {
+		return err
+	}
+
+	assets := make(map[string][]byte, len(task.Assets))
+	for fileName, content := range task.Assets {
+		assets[fileName] = []byte(content)
+	}
+	stream, err := client.Execute(ctx, &taskplugin.ExecuteRequest{
+		ProjectName: task.ProjectName,
+		JobName:     task.JobName,
+		TaskName:    task.Name,
+		ScheduledAt: timestamppb.New(task.ScheduledAt),
+		Assets:      assets,
+		Config:      task.Config,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to execute task %s", task.Name)
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "task %s failed", task.Name)
+		}
+		if observer != nil {
+			observer.Notify(&EventTaskLog{
+				Task:     task.Name,
+				LoggedAt: resp.GetLoggedAt().AsTime(),
+				Message:  resp.GetMessage(),
+			})
+		}
+	}
+}
+
+func (e *Executor) client(ctx context.Context, name string) (taskplugin.TaskPluginClient, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if client, ok := e.clients[name]; ok {
+		return client, nil
+	}
+	server, ok := e.servers[name]
+	if !ok {
+		return nil, fmt.Errorf("no server is configured for task %s", name)
+	}
+
+	var client taskplugin.TaskPluginClient
+	switch {
+	case server.Address != "":
+		conn, err := grpclib.DialContext(ctx, server.Address, e.dialOpts...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to dial server of task %s at %s", name, server.Address)
+		}
+		e.conns = append(e.conns, conn)
+		client = taskplugin.NewTaskPluginClient(conn)
+	case server.Command != "":
+		pluginClient := hplugin.NewClient(&hplugin.ClientConfig{
+			HandshakeConfig: base.Handshake,
+			Plugins: map[string]hplugin.Plugin{
+				PluginName: &Connector{},
+			},
+			Cmd:              exec.Command(server.Command),
+			AllowedProtocols: []hplugin.Protocol{hplugin.ProtocolGRPC},
+			Logger:           e.logger,
+		})
+		rpcClient, err := pluginClient.Client()
+		if err != nil {
+			pluginClient.Kill()
+			return nil, errors.Wrapf(err, "failed to launch plugin of task %s: %s", name, server.Command)
+		}
+		raw, err := rpcClient.Dispense(PluginName)
+		if err != nil {
+			pluginClient.Kill()
+			return nil, errors.Wrapf(err, "failed to dispense plugin of task %s: %s", name, server.Command)
+		}
+		e.plugins = append(e.plugins, pluginClient)
+		client = raw.(taskplugin.TaskPluginClient)
+	default:
+		return nil, fmt.Errorf("server of task %s has neither an address nor a command", name)
+	}
+	e.clients[name] = client
+	return client, nil
+}
+
+// Close closes the connections to task servers and stops the plugin
+// binaries launched
+func (e *Executor) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var errs error
+	for _, conn := range e.conns {
+		if err := conn.Close(); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	for _, pluginClient := range e.plugins {
+		pluginClient.Kill()
+	}
+	e.clients = map[string]taskplugin.TaskPluginClient{}
+	e.conns = nil
+	e.plugins = nil
+	return errs
+}
+
+// NewExecutor creates an executor of the tasks servers are given for,
+// keyed by task name. dialOpts are used to connect servers with an address
+func NewExecutor(servers map[string]TaskServer, logger hclog.Logger, dialOpts ...grpclib.DialOption) *Executor {
+	return &Executor{
+		servers:  servers,
+		dialOpts: dialOpts,
+		logger:   logger,
+		clients:  map[string]taskplugin.TaskPluginClient{},
+	}
+}
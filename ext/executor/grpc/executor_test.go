@@ -0,0 +1,150 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	hplugin "github.com/hashicorp/go-plugin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/odpf/optimus/core/progress"
+	executor "github.com/odpf/optimus/ext/executor/grpc"
+	"github.com/odpf/optimus/ext/executor/grpc/taskplugin"
+)
+
+type taskServer struct {
+	taskplugin.UnimplementedTaskPluginServer
+
+	mu       sync.Mutex
+	requests []*taskplugin.ExecuteRequest
+}
+
+func (s *taskServer) Execute(req *taskplugin.ExecuteRequest, stream taskplugin.TaskPlugin_ExecuteServer) error {
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+
+	loggedAt := timestamppb.New(time.Date(2021, 3, 1, 2, 0, 5, 0, time.UTC))
+	for _, msg := range []string{"query started", "query finished"} {
+		if err := stream.Send(&taskplugin.ExecuteResponse{LoggedAt: loggedAt, Message: msg}); err != nil {
+			return err
+		}
+	}
+	if req.Config["FAIL"] == "true" {
+		return status.Error(codes.Aborted, "table not found")
+	}
+	return nil
+}
+
+type recordingObserver struct {
+	events []progress.Event
+}
+
+func (o *recordingObserver) Notify(evt progress.Event) {
+	o.events = append(o.events, evt)
+}
+
+func TestExecutor(t *testing.T) {
+	scheduledAt := time.Date(2021, 3, 1, 2, 0, 0, 0, time.UTC)
+	newTask := func(config map[string]string) executor.Task {
+		return executor.Task{
+			ProjectName: "a-data-project",
+			JobName:     "daily-orders",
+			Name:        "bq2bq",
+			ScheduledAt: scheduledAt,
+			Assets:      map[string]string{"query.sql": "select * from orders"},
+			Config:      config,
+		}
+	}
+	newServer := func(t *testing.T) (*taskServer, string) {
+		impl := &taskServer{}
+		grpcServer := grpclib.NewServer()
+		taskplugin.RegisterTaskPluginServer(grpcServer, impl)
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.Nil(t, err)
+		go grpcServer.Serve(lis)
+		t.Cleanup(grpcServer.Stop)
+		return impl, lis.Addr().String()
+	}
+
+	t.Run("should forward the task to the server of its name and notify its logs", func(t *testing.T) {
+		impl, address := newServer(t)
+		exec := executor.NewExecutor(map[string]executor.TaskServer{
+			"bq2bq": {Address: address},
+		}, nil, grpclib.WithTransportCredentials(insecure.NewCredentials()))
+		defer exec.Close()
+
+		observer := &recordingObserver{}
+		err := exec.Execute(context.Background(), newTask(map[string]string{"DATASET": "orders"}), observer)
+		assert.Nil(t, err)
+
+		assert.Len(t, impl.requests, 1)
+		req := impl.requests[0]
+		assert.Equal(t, "a-data-project", req.ProjectName)
+		assert.Equal(t, "daily-orders", req.JobName)
+		assert.Equal(t, "bq2bq", req.TaskName)
+		assert.Equal(t, scheduledAt, req.ScheduledAt.AsTime())
+		assert.Equal(t, map[string][]byte{"query.sql": []byte("select * from orders")}, req.Assets)
+		assert.Equal(t, map[string]string{"DATASET": "orders"}, req.Config)
+
+		assert.Len(t, observer.events, 2)
+		assert.Equal(t, "[2021-03-01T02:00:05Z] bq2bq: query started", observer.events[0].String())
+		assert.Equal(t, "[2021-03-01T02:00:05Z] bq2bq: query finished", observer.events[1].String())
+	})
+	t.Run("should return the error a task fails with after notifying its logs", func(t *testing.T) {
+		_, address := newServer(t)
+		exec := executor.NewExecutor(map[string]executor.TaskServer{
+			"bq2bq": {Address: address},
+		}, nil, grpclib.WithTransportCredentials(insecure.NewCredentials()))
+		defer exec.Close()
+
+		observer := &recordingObserver{}
+		err := exec.Execute(context.Background(), newTask(map[string]string{"FAIL": "true"}), observer)
+		assert.NotNil(t, err)
+		assert.Equal(t, codes.Aborted, status.Code(errors.Cause(err)))
+		assert.Len(t, observer.events, 2)
+	})
+	t.Run("should return error for tasks without a server", func(t *testing.T) {
+		exec := executor.NewExecutor(map[string]executor.TaskServer{
+			"transporter": {Address: "127.0.0.1:0"},
+			"predator":    {},
+		}, nil)
+		defer exec.Close()
+
+		err := exec.Execute(context.Background(), newTask(nil), nil)
+		assert.Equal(t, "no server is configured for task bq2bq", err.Error())
+
+		task := newTask(nil)
+		task.Name = "predator"
+		err = exec.Execute(context.Background(), task, nil)
+		assert.Equal(t, "server of task predator has neither an address nor a command", err.Error())
+	})
+}
+
+func TestConnector(t *testing.T) {
+	t.Run("should serve the task plugin to the clients it dispenses", func(t *testing.T) {
+		impl := &taskServer{}
+		client, server := hplugin.TestPluginGRPCConn(t, map[string]hplugin.Plugin{
+			executor.PluginName: executor.NewConnector(impl),
+		})
+		defer server.Stop()
+		defer client.Close()
+
+		raw, err := client.Dispense(executor.PluginName)
+		assert.Nil(t, err)
+		stream, err := raw.(taskplugin.TaskPluginClient).Execute(context.Background(), &taskplugin.ExecuteRequest{TaskName: "bq2bq"})
+		assert.Nil(t, err)
+		resp, err := stream.Recv()
+		assert.Nil(t, err)
+		assert.Equal(t, "query started", resp.Message)
+	})
+}
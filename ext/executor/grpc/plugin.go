@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-hclog"
+	hplugin "github.com/hashicorp/go-plugin"
+	grpclib "google.golang.org/grpc"
+
+	"github.com/odpf/optimus/ext/executor/grpc/taskplugin"
+	"github.com/odpf/optimus/plugin/base"
+)
+
+// PluginName is the name task plugin binaries are dispensed with
+const PluginName = "task"
+
+var _ hplugin.GRPCPlugin = &Connector{}
+
+// Connector serves and dispenses the taskplugin.TaskPlugin service
+// over the grpc connection go-plugin sets up with plugin binaries
+type Connector struct {
+	hplugin.NetRPCUnsupportedPlugin
+
+	impl taskplugin.TaskPluginServer
+}
+
+func (p *Connector) GRPCServer(broker *hplugin.GRPCBroker, s *grpclib.Server) error {
+	taskplugin.RegisterTaskPluginServer(s, p.impl)
+	return nil
+}
+
+func (p *Connector) GRPCClient(ctx context.Context, broker *hplugin.GRPCBroker, c *grpclib.ClientConn) (interface{}, error) {
+	return taskplugin.NewTaskPluginClient(c), nil
+}
+
+// NewConnector creates a connector serving impl, connectors
+// dispensing clients don't need one
+func NewConnector(impl taskplugin.TaskPluginServer) *Connector {
+	return &Connector{
+		impl: impl,
+	}
+}
+
+// Serve is called by task plugin binaries to serve impl to the
+// executor that launched them
+func Serve(impl taskplugin.TaskPluginServer, logger hclog.Logger) {
+	hplugin.Serve(&hplugin.ServeConfig{
+		HandshakeConfig: base.Handshake,
+		Plugins: map[string]hplugin.Plugin{
+			PluginName: NewConnector(impl),
+		},
+		GRPCServer: hplugin.DefaultGRPCServer,
+		Logger:     logger,
+	})
+}
@@ -0,0 +1,134 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package taskplugin
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// TaskPluginClient is the client API for TaskPlugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TaskPluginClient interface {
+	// Execute runs a task instance with its compiled assets and streams
+	// back the logs of the run, a run that fails ends the stream with
+	// a non OK status
+	Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (TaskPlugin_ExecuteClient, error)
+}
+
+type taskPluginClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTaskPluginClient(cc grpc.ClientConnInterface) TaskPluginClient {
+	return &taskPluginClient{cc}
+}
+
+func (c *taskPluginClient) Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (TaskPlugin_ExecuteClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TaskPlugin_ServiceDesc.Streams[0], "/odpf.optimus.executor.TaskPlugin/Execute", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taskPluginExecuteClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TaskPlugin_ExecuteClient interface {
+	Recv() (*ExecuteResponse, error)
+	grpc.ClientStream
+}
+
+type taskPluginExecuteClient struct {
+	grpc.ClientStream
+}
+
+func (x *taskPluginExecuteClient) Recv() (*ExecuteResponse, error) {
+	m := new(ExecuteResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TaskPluginServer is the server API for TaskPlugin service.
+// All implementations must embed UnimplementedTaskPluginServer
+// for forward compatibility
+type TaskPluginServer interface {
+	// Execute runs a task instance with its compiled assets and streams
+	// back the logs of the run, a run that fails ends the stream with
+	// a non OK status
+	Execute(*ExecuteRequest, TaskPlugin_ExecuteServer) error
+	mustEmbedUnimplementedTaskPluginServer()
+}
+
+// UnimplementedTaskPluginServer must be embedded to have forward compatible implementations.
+type UnimplementedTaskPluginServer struct {
+}
+
+func (UnimplementedTaskPluginServer) Execute(*ExecuteRequest, TaskPlugin_ExecuteServer) error {
+	return status.Errorf(codes.Unimplemented, "method Execute not implemented")
+}
+func (UnimplementedTaskPluginServer) mustEmbedUnimplementedTaskPluginServer() {}
+
+// UnsafeTaskPluginServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TaskPluginServer will
+// result in compilation errors.
+type UnsafeTaskPluginServer interface {
+	mustEmbedUnimplementedTaskPluginServer()
+}
+
+func RegisterTaskPluginServer(s grpc.ServiceRegistrar, srv TaskPluginServer) {
+	s.RegisterService(&TaskPlugin_ServiceDesc, srv)
+}
+
+func _TaskPlugin_Execute_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecuteRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaskPluginServer).Execute(m, &taskPluginExecuteServer{stream})
+}
+
+type TaskPlugin_ExecuteServer interface {
+	Send(*ExecuteResponse) error
+	grpc.ServerStream
+}
+
+type taskPluginExecuteServer struct {
+	grpc.ServerStream
+}
+
+func (x *taskPluginExecuteServer) Send(m *ExecuteResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// TaskPlugin_ServiceDesc is the grpc.ServiceDesc for TaskPlugin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TaskPlugin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "odpf.optimus.executor.TaskPlugin",
+	HandlerType: (*TaskPluginServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Execute",
+			Handler:       _TaskPlugin_Execute_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "odpf/optimus/executor/task_plugin.proto",
+}
@@ -0,0 +1,245 @@
+package pagerduty
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	// RoutingKeySecretName is the project secret with the integration key
+	// of the PagerDuty service alerts of the project are routed to
+	RoutingKeySecretName = "PAGERDUTY_ROUTING_KEY"
+
+	// TierLabel and CriticalTier select the jobs paged for, jobs labeled
+	// tier: critical
+	TierLabel    = "tier"
+	CriticalTier = "critical"
+
+	DefaultEventsURL = "https://events.pagerduty.com/v2/enqueue"
+	DefaultTimeout   = time.Second * 10
+
+	eventActionTrigger = "trigger"
+	eventActionResolve = "resolve"
+)
+
+// Event is an event of the PagerDuty Events API v2
+type Event struct {
+	RoutingKey  string        `json:"routing_key"`
+	EventAction string        `json:"event_action"`
+	DedupKey    string        `json:"dedup_key"`
+	Payload     *EventPayload `json:"payload,omitempty"`
+	Links       []EventLink   `json:"links,omitempty"`
+}
+
+type EventPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Component     string            `json:"component,omitempty"`
+	Group         string            `json:"group,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+type EventLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// ServerIncident is an event of the optimus server itself rather than of a
+// project, like the server failing to shut down in time
+type ServerIncident interface {
+	progress.Event
+	// IncidentKey is the dedup key of the alert
+	IncidentKey() string
+}
+
+// Notifier triggers an alert for every run of a critical job breaching its
+// sla and resolves it once the run succeeds. Alerts of a run share the
+// dedup key of the job and the scheduled time of the run, projects without
+// the RoutingKeySecretName secret are skipped
+type Notifier struct {
+	eventsURL  string
+	client     *http.Client
+	errHandler func(error)
+	wg         sync.WaitGroup
+
+	// ServerRoutingKey is the integration key of the PagerDuty service
+	// paged for server incidents, they are ignored if not set
+	ServerRoutingKey string
+}
+
+// Notify sends job.EventJobSLAMiss and successful job.EventJobRunFinished
+// events of critical jobs and server incidents, other events are ignored
+func (n *Notifier) Notify(evt progress.Event) {
+	var events []Event
+	var namespace models.NamespaceSpec
+	switch e := evt.(type) {
+	case ServerIncident:
+		if n.ServerRoutingKey == "" {
+			return
+		}
+		n.sendAsync(Event{
+			RoutingKey:  n.ServerRoutingKey,
+			EventAction: eventActionTrigger,
+			DedupKey:    e.IncidentKey(),
+			Payload: &EventPayload{
+				Summary:  fmt.Sprintf("[Server] %s", e.String()),
+				Source:   "optimus",
+				Severity: CriticalTier,
+			},
+		})
+		return
+	case *job.EventJobSLAMiss:
+		namespace = e.Namespace
+		events = slaMissEvents(e.Namespace, e.Job, e.Event)
+	case *job.EventJobRunFinished:
+		scheduledAt := e.Event.Value["scheduled_at"].GetStringValue()
+		if e.Event.Type != models.JobEventTypeSuccess || !isCritical(e.Job) || scheduledAt == "" {
+			return
+		}
+		// resolving an alert never triggered is a no-op for pagerduty
+		namespace = e.Namespace
+		events = []Event{{
+			EventAction: eventActionResolve,
+			DedupKey:    DedupKey(e.Namespace.ProjectSpec.Name, e.Job.Name, scheduledAt),
+		}}
+	default:
+		return
+	}
+	routingKey, ok := namespace.ProjectSpec.Secret.GetByName(RoutingKeySecretName)
+	if !ok || len(events) == 0 {
+		return
+	}
+
+	for _, event := range events {
+		event.RoutingKey = routingKey
+		n.sendAsync(event)
+	}
+}
+
+func (n *Notifier) sendAsync(event Event) {
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		if err := n.send(event); err != nil {
+			n.errHandler(errors.Wrapf(err, "failed to %s pagerduty alert %s", event.EventAction, event.DedupKey))
+		}
+	}()
+}
+
+// Close waits for the events being sent
+func (n *Notifier) Close() error {
+	n.wg.Wait()
+	return nil
+}
+
+func (n *Notifier) send(event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	resp, err := n.client.Post(n.eventsURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode != http.StatusAccepted {
+		return errors.Errorf("pagerduty responded with %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// slaMissEvents triggers an alert for every breached run of a critical job
+func slaMissEvents(namespace models.NamespaceSpec, jobSpec models.JobSpec, jobEvent models.JobEvent) []Event {
+	if !isCritical(jobSpec) {
+		return nil
+	}
+	jobURL := jobEvent.Value["job_url"].GetStringValue()
+
+	// the same run is reported once however many of its tasks breach
+	seen := map[string]bool{}
+	var events []Event
+	for _, sla := range jobEvent.Value["slas"].GetListValue().GetValues() {
+		slaFields := sla.GetStructValue().GetFields()
+		scheduledAt := slaFields["scheduled_at"].GetStringValue()
+		if scheduledAt == "" || seen[scheduledAt] {
+			continue
+		}
+		seen[scheduledAt] = true
+
+		event := Event{
+			EventAction: eventActionTrigger,
+			DedupKey:    DedupKey(namespace.ProjectSpec.Name, jobSpec.Name, scheduledAt),
+			Payload: &EventPayload{
+				Summary: fmt.Sprintf("[Job] SLA Breached | %s/%s: %s scheduled at %s", namespace.ProjectSpec.Name,
+					namespace.Name, jobSpec.Name, scheduledAt),
+				Source:    namespace.ProjectSpec.Name,
+				Severity:  CriticalTier,
+				Component: jobSpec.Name,
+				Group:     namespace.Name,
+				CustomDetails: map[string]string{
+					"owner":        jobSpec.Owner,
+					"scheduled_at": scheduledAt,
+					"task_id":      slaFields["task_id"].GetStringValue(),
+				},
+			},
+		}
+		if runURL := airflowRunURL(jobURL, jobSpec.Name, scheduledAt); runURL != "" {
+			event.Links = []EventLink{{Href: runURL, Text: "Airflow run"}}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// DedupKey identifies the alert of a run of a job
+func DedupKey(projectName, jobName, scheduledAt string) string {
+	return fmt.Sprintf("optimus/%s/%s/%s", projectName, jobName, scheduledAt)
+}
+
+func isCritical(jobSpec models.JobSpec) bool {
+	return strings.EqualFold(jobSpec.Labels[TierLabel], CriticalTier)
+}
+
+// airflowRunURL links the graph of the run from the job url sent by the
+// scheduler, e.g. http://airflow.example.io/tree?dag_id=<job>
+func airflowRunURL(jobURL, jobName, scheduledAt string) string {
+	u, err := url.Parse(jobURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/tree") + "/graph"
+	u.RawQuery = url.Values{
+		"dag_id":         []string{jobName},
+		"execution_date": []string{scheduledAt},
+	}.Encode()
+	return u.String()
+}
+
+// NewNotifier sends events to eventsURL with client, a client timing out
+// after DefaultTimeout is used if nil
+func NewNotifier(eventsURL string, client *http.Client, errHandler func(error)) *Notifier {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &Notifier{
+		eventsURL:  eventsURL,
+		client:     client,
+		errHandler: errHandler,
+	}
+}
@@ -0,0 +1,178 @@
+package pagerduty
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+)
+
+func TestNotifier(t *testing.T) {
+	namespace := models.NamespaceSpec{
+		Name: "data-namespace",
+		ProjectSpec: models.ProjectSpec{
+			Name:   "a-data-project",
+			Secret: models.ProjectSecrets{{Name: RoutingKeySecretName, Value: "routing-key"}},
+		},
+	}
+	criticalJob := models.JobSpec{
+		Name:   "daily-orders",
+		Owner:  "data@example.io",
+		Labels: map[string]string{TierLabel: CriticalTier},
+	}
+	slaMissValues, _ := structpb.NewStruct(map[string]interface{}{
+		"slas": []interface{}{
+			map[string]interface{}{"task_id": "bq2bq", "scheduled_at": "2021-03-01T02:00:00Z"},
+			map[string]interface{}{"task_id": "transporter", "scheduled_at": "2021-03-01T02:00:00Z"},
+			map[string]interface{}{"task_id": "bq2bq", "scheduled_at": "2021-03-02T02:00:00Z"},
+		},
+		"job_url": "http://airflow.example.io/tree?dag_id=daily-orders",
+	})
+	successValues, _ := structpb.NewStruct(map[string]interface{}{
+		"scheduled_at": "2021-03-01T02:00:00Z",
+	})
+
+	newServer := func(t *testing.T) (*httptest.Server, func() []Event) {
+		var mu sync.Mutex
+		var events []Event
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			var event Event
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&event))
+			mu.Lock()
+			events = append(events, event)
+			mu.Unlock()
+			rw.WriteHeader(http.StatusAccepted)
+		}))
+		t.Cleanup(server.Close)
+		return server, func() []Event {
+			mu.Lock()
+			defer mu.Unlock()
+			return events
+		}
+	}
+
+	t.Run("should trigger an alert for every breached run and resolve it once the run succeeds", func(t *testing.T) {
+		server, events := newServer(t)
+		notifier := NewNotifier(server.URL, nil, func(err error) { assert.Nil(t, err) })
+
+		notifier.Notify(&job.EventJobSLAMiss{
+			Namespace: namespace,
+			Job:       criticalJob,
+			Event:     models.JobEvent{Type: models.JobEventTypeSLAMiss, Value: slaMissValues.GetFields()},
+		})
+		assert.Nil(t, notifier.Close())
+		triggered := events()
+		assert.Equal(t, 2, len(triggered))
+
+		var run Event
+		for _, event := range triggered {
+			if event.DedupKey == DedupKey("a-data-project", "daily-orders", "2021-03-01T02:00:00Z") {
+				run = event
+			}
+		}
+		assert.Equal(t, "routing-key", run.RoutingKey)
+		assert.Equal(t, "trigger", run.EventAction)
+		assert.Equal(t, "critical", run.Payload.Severity)
+		assert.Equal(t, "a-data-project", run.Payload.Source)
+		assert.Equal(t, "data@example.io", run.Payload.CustomDetails["owner"])
+		assert.Equal(t, []EventLink{{
+			Href: "http://airflow.example.io/graph?dag_id=daily-orders&execution_date=2021-03-01T02%3A00%3A00Z",
+			Text: "Airflow run",
+		}}, run.Links)
+
+		notifier.Notify(&job.EventJobRunFinished{
+			Namespace: namespace,
+			Job:       criticalJob,
+			Event:     models.JobEvent{Type: models.JobEventTypeSuccess, Value: successValues.GetFields()},
+		})
+		assert.Nil(t, notifier.Close())
+		assert.Equal(t, 3, len(events()))
+		resolved := events()[2]
+		assert.Equal(t, "resolve", resolved.EventAction)
+		assert.Equal(t, run.DedupKey, resolved.DedupKey)
+		assert.Nil(t, resolved.Payload)
+	})
+	t.Run("should skip jobs not labeled critical and projects without a routing key", func(t *testing.T) {
+		server, events := newServer(t)
+		notifier := NewNotifier(server.URL, nil, func(err error) { assert.Nil(t, err) })
+
+		notifier.Notify(&job.EventJobSLAMiss{
+			Namespace: namespace,
+			Job:       models.JobSpec{Name: "hourly-orders", Labels: map[string]string{TierLabel: "low"}},
+			Event:     models.JobEvent{Type: models.JobEventTypeSLAMiss, Value: slaMissValues.GetFields()},
+		})
+		withoutKey := namespace
+		withoutKey.ProjectSpec.Secret = nil
+		notifier.Notify(&job.EventJobSLAMiss{
+			Namespace: withoutKey,
+			Job:       criticalJob,
+			Event:     models.JobEvent{Type: models.JobEventTypeSLAMiss, Value: slaMissValues.GetFields()},
+		})
+		notifier.Notify(&job.EventJobRunFinished{
+			Namespace: namespace,
+			Job:       criticalJob,
+			Event:     models.JobEvent{Type: models.JobEventTypeFailure, Value: successValues.GetFields()},
+		})
+		assert.Nil(t, notifier.Close())
+		assert.Empty(t, events())
+	})
+	t.Run("should page for server incidents with the server routing key", func(t *testing.T) {
+		server, events := newServer(t)
+		notifier := NewNotifier(server.URL, nil, func(err error) { assert.Nil(t, err) })
+
+		notifier.Notify(serverIncident{})
+		assert.Nil(t, notifier.Close())
+		assert.Empty(t, events())
+
+		notifier.ServerRoutingKey = "server-routing-key"
+		notifier.Notify(serverIncident{})
+		assert.Nil(t, notifier.Close())
+		assert.Equal(t, 1, len(events()))
+		incident := events()[0]
+		assert.Equal(t, "server-routing-key", incident.RoutingKey)
+		assert.Equal(t, "trigger", incident.EventAction)
+		assert.Equal(t, "optimus/server/host-1/shutdown", incident.DedupKey)
+		assert.Equal(t, "[Server] server is stuck shutting down", incident.Payload.Summary)
+	})
+	t.Run("should report events pagerduty fails to accept", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(`{"status":"invalid event"}`))
+		}))
+		defer server.Close()
+		var mu sync.Mutex
+		var errs []error
+		notifier := NewNotifier(server.URL, nil, func(err error) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		})
+
+		notifier.Notify(&job.EventJobRunFinished{
+			Namespace: namespace,
+			Job:       criticalJob,
+			Event:     models.JobEvent{Type: models.JobEventTypeSuccess, Value: successValues.GetFields()},
+		})
+		assert.Nil(t, notifier.Close())
+		assert.Equal(t, 1, len(errs))
+		assert.Contains(t, errs[0].Error(), "failed to resolve pagerduty alert optimus/a-data-project/daily-orders/2021-03-01T02:00:00Z")
+		assert.Contains(t, errs[0].Error(), "invalid event")
+	})
+}
+
+type serverIncident struct{}
+
+func (serverIncident) String() string {
+	return "server is stuck shutting down"
+}
+
+func (serverIncident) IncidentKey() string {
+	return "optimus/server/host-1/shutdown"
+}
@@ -0,0 +1,110 @@
+package slack
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	api "github.com/slack-go/slack"
+
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/ext/notify/webhook"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	// WebhookURLSecretName is the project secret with the url of the slack
+	// incoming webhook failures and sla misses of jobs are posted to
+	WebhookURLSecretName = "SLACK_WEBHOOK_URL"
+
+	DefaultWebhookTimeout = time.Second * 10
+)
+
+// WebhookObserver posts failures and sla misses of jobs to the incoming
+// webhook of their project, unlike Notifier it doesn't need the notify
+// behavior of jobs to be configured. Projects without the
+// WebhookURLSecretName secret are skipped
+type WebhookObserver struct {
+	client     *http.Client
+	errHandler func(error)
+	wg         sync.WaitGroup
+}
+
+// Notify posts job.EventJobRunFinished events of failures and
+// job.EventJobSLAMiss events, other events are ignored
+func (o *WebhookObserver) Notify(evt progress.Event) {
+	var namespace models.NamespaceSpec
+	var jobSpec models.JobSpec
+	var jobEvent models.JobEvent
+	var channelKey string
+	switch e := evt.(type) {
+	case *job.EventJobRunFinished:
+		if e.Event.Type != models.JobEventTypeFailure {
+			return
+		}
+		namespace, jobSpec, jobEvent = e.Namespace, e.Job, e.Event
+		channelKey = models.ProjectSlackFailureChannel
+	case *job.EventJobSLAMiss:
+		namespace, jobSpec, jobEvent = e.Namespace, e.Job, e.Event
+		channelKey = models.ProjectSlackSLAMissChannel
+	default:
+		return
+	}
+	webhookURL, ok := namespace.ProjectSpec.Secret.GetByName(WebhookURLSecretName)
+	if !ok {
+		return
+	}
+
+	msg := buildWebhookMessage(event{
+		projectName:   namespace.ProjectSpec.Name,
+		namespaceName: namespace.Name,
+		jobName:       jobSpec.Name,
+		owner:         jobSpec.Owner,
+		meta:          jobEvent,
+	}, namespace.ProjectSpec.Config[channelKey])
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		if err := api.PostWebhookCustomHTTP(webhookURL, o.client, msg); err != nil {
+			o.errHandler(errors.Wrapf(err, "failed to post %s of job %s to slack", jobEvent.Type, jobSpec.Name))
+		}
+	}()
+}
+
+// Close waits for the messages being posted
+func (o *WebhookObserver) Close() error {
+	o.wg.Wait()
+	return nil
+}
+
+// buildWebhookMessage posts to the default channel of the webhook if channel
+// is empty
+func buildWebhookMessage(evt event, channel string) *api.WebhookMessage {
+	heading := "Failure"
+	if evt.meta.Type == models.JobEventTypeSLAMiss {
+		heading = "SLA Breached"
+	}
+	return &api.WebhookMessage{
+		Channel: channel,
+		// shown in notifications of clients not rendering blocks
+		Text:   fmt.Sprintf("[Job] %s | %s/%s: %s", heading, evt.projectName, evt.namespaceName, evt.jobName),
+		Blocks: &api.Blocks{BlockSet: buildMessageBlocks([]event{evt})},
+	}
+}
+
+// NewWebhookObserver posts messages with client, a client timing out after
+// DefaultWebhookTimeout and only connecting to public addresses is used if
+// nil, as the webhook url is set by editors of the project
+func NewWebhookObserver(client *http.Client, errHandler func(error)) *WebhookObserver {
+	if client == nil {
+		client = webhook.NewPublicClient(DefaultWebhookTimeout)
+	}
+	return &WebhookObserver{
+		client:     client,
+		errHandler: errHandler,
+	}
+}
@@ -0,0 +1,152 @@
+package slack
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/utils"
+)
+
+func TestWebhookObserver(t *testing.T) {
+	eventValues, _ := structpb.NewStruct(map[string]interface{}{
+		"task_id":      "bq2bq",
+		"scheduled_at": "2021-03-01T02:00:00Z",
+		"exception":    "table not found",
+	})
+	newNamespace := func(webhookURL string) models.NamespaceSpec {
+		return models.NamespaceSpec{
+			Name: "data-namespace",
+			ProjectSpec: models.ProjectSpec{
+				Name:   "a-data-project",
+				Config: map[string]string{models.ProjectSlackSLAMissChannel: "#data-sla"},
+				Secret: models.ProjectSecrets{{Name: WebhookURLSecretName, Value: webhookURL}},
+			},
+		}
+	}
+	jobSpec := models.JobSpec{Name: "daily-orders", Owner: "data@example.io"}
+
+	type postedMessage struct {
+		Channel string `json:"channel"`
+		Text    string `json:"text"`
+		Blocks  []struct {
+			Type string `json:"type"`
+		} `json:"blocks"`
+	}
+	newServer := func(t *testing.T) (*httptest.Server, func() []postedMessage) {
+		var mu sync.Mutex
+		var messages []postedMessage
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			var msg postedMessage
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&msg))
+			mu.Lock()
+			messages = append(messages, msg)
+			mu.Unlock()
+		}))
+		t.Cleanup(server.Close)
+		return server, func() []postedMessage {
+			mu.Lock()
+			defer mu.Unlock()
+			return messages
+		}
+	}
+
+	t.Run("should post failures to the default channel of the webhook", func(t *testing.T) {
+		server, messages := newServer(t)
+		observer := NewWebhookObserver(server.Client(), func(err error) { assert.Nil(t, err) })
+
+		observer.Notify(&job.EventJobRunFinished{
+			Namespace: newNamespace(server.URL),
+			Job:       jobSpec,
+			Event:     models.JobEvent{Type: models.JobEventTypeFailure, Value: eventValues.GetFields()},
+		})
+		observer.Notify(&job.EventJobRunFinished{
+			Namespace: newNamespace(server.URL),
+			Job:       jobSpec,
+			Event:     models.JobEvent{Type: models.JobEventTypeSuccess},
+		})
+		assert.Nil(t, observer.Close())
+
+		assert.Equal(t, 1, len(messages()))
+		msg := messages()[0]
+		assert.Equal(t, "", msg.Channel)
+		assert.Equal(t, "[Job] Failure | a-data-project/data-namespace: daily-orders", msg.Text)
+		assert.Equal(t, "header", msg.Blocks[0].Type)
+		assert.Equal(t, "context", msg.Blocks[len(msg.Blocks)-1].Type)
+	})
+	t.Run("should post sla misses to the channel of the project", func(t *testing.T) {
+		server, messages := newServer(t)
+		observer := NewWebhookObserver(server.Client(), func(err error) { assert.Nil(t, err) })
+
+		observer.Notify(&job.EventJobSLAMiss{
+			Namespace: newNamespace(server.URL),
+			Job:       jobSpec,
+			Event:     models.JobEvent{Type: models.JobEventTypeSLAMiss},
+		})
+		assert.Nil(t, observer.Close())
+
+		assert.Equal(t, 1, len(messages()))
+		assert.Equal(t, "#data-sla", messages()[0].Channel)
+		assert.Equal(t, "[Job] SLA Breached | a-data-project/data-namespace: daily-orders", messages()[0].Text)
+	})
+	t.Run("should skip projects without the webhook secret", func(t *testing.T) {
+		_, messages := newServer(t)
+		observer := NewWebhookObserver(nil, func(err error) { assert.Nil(t, err) })
+
+		namespace := newNamespace("")
+		namespace.ProjectSpec.Secret = nil
+		observer.Notify(&job.EventJobSLAMiss{Namespace: namespace, Job: jobSpec})
+		assert.Nil(t, observer.Close())
+		assert.Empty(t, messages())
+	})
+	t.Run("should report messages slack fails to accept", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+		var errs []error
+		observer := NewWebhookObserver(server.Client(), func(err error) { errs = append(errs, err) })
+
+		observer.Notify(&job.EventJobSLAMiss{
+			Namespace: newNamespace(server.URL),
+			Job:       jobSpec,
+			Event:     models.JobEvent{Type: models.JobEventTypeSLAMiss},
+		})
+		assert.Nil(t, observer.Close())
+		assert.Equal(t, 1, len(errs))
+		assert.Contains(t, errs[0].Error(), "failed to post sla_miss of job daily-orders to slack")
+	})
+	t.Run("should not post to internal addresses by default", func(t *testing.T) {
+		server, messages := newServer(t)
+		var mu sync.Mutex
+		var errs []error
+		observer := NewWebhookObserver(nil, func(err error) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		})
+
+		for _, webhookURL := range []string{server.URL, "http://10.0.0.1/hooks", "http://169.254.169.254/hooks"} {
+			observer.Notify(&job.EventJobSLAMiss{
+				Namespace: newNamespace(webhookURL),
+				Job:       jobSpec,
+				Event:     models.JobEvent{Type: models.JobEventTypeSLAMiss},
+			})
+		}
+		assert.Nil(t, observer.Close())
+
+		assert.Empty(t, messages())
+		assert.Equal(t, 3, len(errs))
+		for _, err := range errs {
+			assert.True(t, errors.Is(err, utils.ErrNonPublicAddress))
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/utils"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// SignatureHeader carries the HMAC-SHA256 of the payload as
+	// sha256=<hex digest>, signed with the secret of the webhook
+	SignatureHeader = "X-Optimus-Signature"
+
+	// EventHeader carries the event of the payload
+	EventHeader = "X-Optimus-Event"
+
+	// DefaultMaxRetries is the number of times failed deliveries are retried
+	DefaultMaxRetries = 3
+
+	// DefaultBackoff is the wait before the first retry, doubled for the
+	// following ones
+	DefaultBackoff = time.Second
+
+	DefaultTimeout = time.Second * 10
+)
+
+// Payload is the json body posted to webhooks
+type Payload struct {
+	Event     string    `json:"event"`
+	Project   string    `json:"project"`
+	Namespace string    `json:"namespace"`
+	Job       string    `json:"job"`
+	Owner     string    `json:"owner"`
+	Time      time.Time `json:"time"`
+
+	// Value is sent by the scheduler with failed and successful runs
+	Value map[string]interface{} `json:"value,omitempty"`
+}
+
+// Observer posts lifecycle events of jobs to the webhooks of their project,
+// deliveries don't block the notifier and are retried with exponential
+// backoff
+type Observer struct {
+	client *http.Client
+	log    logrus.FieldLogger
+	wg     sync.WaitGroup
+
+	MaxRetries int
+	Backoff    time.Duration
+	Now        func() time.Time
+}
+
+// Notify delivers job.EventJobDeployed and job.EventJobRunFinished events,
+// other events are ignored
+func (o *Observer) Notify(evt progress.Event) {
+	var namespace models.NamespaceSpec
+	var payload Payload
+	switch e := evt.(type) {
+	case *job.EventJobDeployed:
+		namespace = e.Namespace
+		payload = newPayload(models.WebhookEventJobDeployed, e.Namespace, e.Job)
+	case *job.EventJobRunFinished:
+		event := models.WebhookEventJobSuccess
+		if e.Event.Type == models.JobEventTypeFailure {
+			event = models.WebhookEventJobFailure
+		}
+		namespace = e.Namespace
+		payload = newPayload(event, e.Namespace, e.Job)
+		payload.Value = (&structpb.Struct{Fields: e.Event.Value}).AsMap()
+	default:
+		return
+	}
+	payload.Time = o.Now()
+
+	var body []byte
+	for _, webhook := range namespace.ProjectSpec.Webhooks {
+		if !webhook.Subscribes(payload.Event) {
+			continue
+		}
+		secret, ok := namespace.ProjectSpec.Secret.GetByName(webhook.SecretName)
+		if !ok {
+			o.log.Errorf("failed to deliver %s webhook of project %s to %s: secret %q is not registered",
+				payload.Event, payload.Project, webhook.URL, webhook.SecretName)
+			continue
+		}
+		if body == nil {
+			var err error
+			if body, err = json.Marshal(payload); err != nil {
+				o.log.WithError(err).Errorf("failed to encode %s webhook of job %s", payload.Event, payload.Job)
+				return
+			}
+		}
+
+		o.wg.Add(1)
+		go func(url string) {
+			defer o.wg.Done()
+			o.deliver(url, secret, payload, body)
+		}(webhook.URL)
+	}
+}
+
+// Close waits for the deliveries in progress
+func (o *Observer) Close() error {
+	o.wg.Wait()
+	return nil
+}
+
+func (o *Observer) deliver(url, secret string, payload Payload, body []byte) {
+	backoff := o.Backoff
+	var err error
+	for attempt := 0; attempt <= o.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		if err = o.post(url, secret, payload.Event, body); err == nil {
+			return
+		}
+		o.log.WithError(err).Warnf("failed to deliver %s webhook of job %s to %s, attempt %d",
+			payload.Event, payload.Job, url, attempt+1)
+	}
+	o.log.WithError(err).Errorf("gave up delivering %s webhook of job %s to %s after %d retries",
+		payload.Event, payload.Job, url, o.MaxRetries)
+}
+
+func (o *Observer) post(url, secret, event string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(SignatureHeader, Sign(secret, body))
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign is the value of SignatureHeader for a payload, receivers verify it by
+// computing the HMAC-SHA256 of the request body with their secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil)))
+}
+
+// NewPublicClient only connects to public addresses, webhooks resolving to
+// internal addresses like the cloud metadata endpoint fail, including the
+// ones redirected to such addresses. Proxies are not used as they would be
+// connected to instead of the webhook
+func NewPublicClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         utils.NewPublicDialer(timeout).DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+func newPayload(event string, namespace models.NamespaceSpec, jobSpec models.JobSpec) Payload {
+	return Payload{
+		Event:     event,
+		Project:   namespace.ProjectSpec.Name,
+		Namespace: namespace.Name,
+		Job:       jobSpec.Name,
+		Owner:     jobSpec.Owner,
+	}
+}
+
+// NewObserver delivers webhooks with client, a client timing out after
+// DefaultTimeout and only connecting to public addresses is used if nil
+func NewObserver(client *http.Client, log logrus.FieldLogger) *Observer {
+	if client == nil {
+		client = NewPublicClient(DefaultTimeout)
+	}
+	return &Observer{
+		client:     client,
+		log:        log,
+		MaxRetries: DefaultMaxRetries,
+		Backoff:    DefaultBackoff,
+		Now:        time.Now,
+	}
+}
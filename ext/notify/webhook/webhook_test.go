@@ -0,0 +1,192 @@
+package webhook_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/ext/notify/webhook"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type webhookRecorder struct {
+	mu       sync.Mutex
+	failures int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *webhookRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	body, _ := ioutil.ReadAll(req.Body)
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
+
+func TestObserver(t *testing.T) {
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	jobSpec := models.JobSpec{Name: "daily-orders", Owner: "data@example.io"}
+	newNamespace := func(webhooks ...models.WebhookConfig) models.NamespaceSpec {
+		return models.NamespaceSpec{
+			Name: "data-namespace",
+			ProjectSpec: models.ProjectSpec{
+				Name:     "a-data-project",
+				Secret:   models.ProjectSecrets{{Name: "WEBHOOK_SECRET", Value: "s3cr3t"}},
+				Webhooks: webhooks,
+			},
+		}
+	}
+	newObserver := func() (*webhook.Observer, *test.Hook) {
+		logger, hook := test.NewNullLogger()
+		// test servers listen on loopback, refused by the default client
+		observer := webhook.NewObserver(&http.Client{}, logrus.NewEntry(logger))
+		observer.Backoff = time.Millisecond
+		observer.Now = func() time.Time { return now }
+		return observer, hook
+	}
+
+	t.Run("should post signed payloads of deployed jobs", func(t *testing.T) {
+		recorder := &webhookRecorder{}
+		srv := httptest.NewServer(recorder)
+		defer srv.Close()
+		observer, _ := newObserver()
+
+		observer.Notify(&job.EventJobDeployed{
+			Namespace: newNamespace(models.WebhookConfig{URL: srv.URL, SecretName: "WEBHOOK_SECRET"}),
+			Job:       jobSpec,
+		})
+		assert.Nil(t, observer.Close())
+
+		assert.Equal(t, 1, len(recorder.requests))
+		req, body := recorder.requests[0], recorder.bodies[0]
+		assert.Equal(t, webhook.Sign("s3cr3t", body), req.Header.Get(webhook.SignatureHeader))
+		assert.Equal(t, models.WebhookEventJobDeployed, req.Header.Get(webhook.EventHeader))
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+		var payload webhook.Payload
+		assert.Nil(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, webhook.Payload{
+			Event:     models.WebhookEventJobDeployed,
+			Project:   "a-data-project",
+			Namespace: "data-namespace",
+			Job:       "daily-orders",
+			Owner:     "data@example.io",
+			Time:      now,
+		}, payload)
+	})
+	t.Run("should post failed runs only to webhooks subscribing to them", func(t *testing.T) {
+		recorder := &webhookRecorder{}
+		srv := httptest.NewServer(recorder)
+		defer srv.Close()
+		observer, _ := newObserver()
+
+		namespace := newNamespace(
+			models.WebhookConfig{URL: srv.URL + "/failures", SecretName: "WEBHOOK_SECRET", Events: []string{models.WebhookEventJobFailure}},
+			models.WebhookConfig{URL: srv.URL + "/deployments", SecretName: "WEBHOOK_SECRET", Events: []string{models.WebhookEventJobDeployed}},
+		)
+		observer.Notify(&job.EventJobRunFinished{
+			Namespace: namespace,
+			Job:       jobSpec,
+			Event: models.JobEvent{
+				Type:  models.JobEventTypeFailure,
+				Value: map[string]*structpb.Value{"task_id": structpb.NewStringValue("bq2bq")},
+			},
+		})
+		assert.Nil(t, observer.Close())
+
+		assert.Equal(t, 1, len(recorder.requests))
+		assert.Equal(t, "/failures", recorder.requests[0].URL.Path)
+		var payload webhook.Payload
+		assert.Nil(t, json.Unmarshal(recorder.bodies[0], &payload))
+		assert.Equal(t, models.WebhookEventJobFailure, payload.Event)
+		assert.Equal(t, map[string]interface{}{"task_id": "bq2bq"}, payload.Value)
+	})
+	t.Run("should retry failed deliveries with backoff", func(t *testing.T) {
+		recorder := &webhookRecorder{failures: 2}
+		srv := httptest.NewServer(recorder)
+		defer srv.Close()
+		observer, hook := newObserver()
+
+		observer.Notify(&job.EventJobDeployed{
+			Namespace: newNamespace(models.WebhookConfig{URL: srv.URL, SecretName: "WEBHOOK_SECRET"}),
+			Job:       jobSpec,
+		})
+		assert.Nil(t, observer.Close())
+
+		assert.Equal(t, 3, len(recorder.requests))
+		assert.Equal(t, recorder.bodies[0], recorder.bodies[2])
+		assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+	})
+	t.Run("should log a permanent failure once retries are exhausted", func(t *testing.T) {
+		recorder := &webhookRecorder{failures: 10}
+		srv := httptest.NewServer(recorder)
+		defer srv.Close()
+		observer, hook := newObserver()
+
+		observer.Notify(&job.EventJobDeployed{
+			Namespace: newNamespace(models.WebhookConfig{URL: srv.URL, SecretName: "WEBHOOK_SECRET"}),
+			Job:       jobSpec,
+		})
+		assert.Nil(t, observer.Close())
+
+		assert.Equal(t, 1+webhook.DefaultMaxRetries, len(recorder.requests))
+		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+	})
+	t.Run("should not post payloads if the secret isn't registered", func(t *testing.T) {
+		recorder := &webhookRecorder{}
+		srv := httptest.NewServer(recorder)
+		defer srv.Close()
+		observer, hook := newObserver()
+
+		observer.Notify(&job.EventJobDeployed{
+			Namespace: newNamespace(models.WebhookConfig{URL: srv.URL, SecretName: "MISSING_SECRET"}),
+			Job:       jobSpec,
+		})
+		assert.Nil(t, observer.Close())
+
+		assert.Equal(t, 0, len(recorder.requests))
+		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+	})
+	t.Run("should not post payloads to names resolving to internal addresses", func(t *testing.T) {
+		recorder := &webhookRecorder{}
+		srv := httptest.NewServer(recorder)
+		defer srv.Close()
+		srvURL, err := url.Parse(srv.URL)
+		assert.Nil(t, err)
+		logger, hook := test.NewNullLogger()
+		observer := webhook.NewObserver(nil, logrus.NewEntry(logger))
+		observer.MaxRetries = 0
+
+		observer.Notify(&job.EventJobDeployed{
+			Namespace: newNamespace(models.WebhookConfig{URL: "http://localhost:" + srvURL.Port(), SecretName: "WEBHOOK_SECRET"}),
+			Job:       jobSpec,
+		})
+		assert.Nil(t, observer.Close())
+
+		assert.Equal(t, 0, len(recorder.requests))
+		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+		assert.True(t, errors.Is(hook.LastEntry().Data[logrus.ErrorKey].(error), utils.ErrNonPublicAddress))
+	})
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, "sha256=8b5f48702995c1598c573db1e21866a9b825d4a794d169d7060a03605796360b",
+		webhook.Sign("secret", []byte("message")))
+}
@@ -30,6 +30,7 @@ const (
 	baseLibFileName = "__lib.py"
 	dagStatusURL    = "api/experimental/dags/%s/dag_runs"
 	dagRunClearURL  = "clear&dag_id=%s&start_date=%s&end_date=%s"
+	dagPausedURL    = "api/experimental/dags/%s/paused/%t"
 )
 
 type HTTPClient interface {
@@ -118,7 +119,7 @@ func (a *scheduler) GetJobStatus(ctx context.Context, projSpec models.ProjectSpe
 	schdHost = strings.Trim(schdHost, "/")
 
 	fetchURL := fmt.Sprintf(fmt.Sprintf("%s/%s", schdHost, dagStatusURL), jobName)
-	request, err := http.NewRequest(http.MethodGet, fetchURL, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fetchURL, nil)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to build http request for %s", fetchURL)
 	}
@@ -166,12 +167,27 @@ func (a *scheduler) GetJobStatus(ctx context.Context, projSpec models.ProjectSpe
 		jobStatus = append(jobStatus, models.JobStatus{
 			ScheduledAt: schdAt,
 			State:       models.JobStatusState(status["state"].(string)),
+			StartTime:   runStartTime(status),
 		})
 	}
 
 	return jobStatus, nil
 }
 
+// runStartTime is zero for runs airflow hasn't started yet, their start_date
+// is null
+func runStartTime(dagRun map[string]interface{}) time.Time {
+	startDate, ok := dagRun["start_date"].(string)
+	if !ok {
+		return time.Time{}
+	}
+	startTime, err := time.Parse(time.RFC3339Nano, startDate)
+	if err != nil {
+		return time.Time{}
+	}
+	return startTime
+}
+
 func (a *scheduler) Clear(ctx context.Context, projSpec models.ProjectSpec, jobName string, startDate, endDate time.Time) error {
 	schdHost, ok := projSpec.Config[models.ProjectSchedulerHost]
 	if !ok {
@@ -186,7 +202,7 @@ func (a *scheduler) Clear(ctx context.Context, projSpec models.ProjectSpec, jobN
 		jobName,
 		startDate.In(utcTimezone).Format(airflowDateFormat),
 		endDate.In(utcTimezone).Format(airflowDateFormat))
-	request, err := http.NewRequest(http.MethodGet, clearDagRunURL, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, clearDagRunURL, nil)
 	if err != nil {
 		return errors.Wrapf(err, "failed to build http request for %s", clearDagRunURL)
 	}
@@ -239,3 +255,85 @@ func (a *scheduler) GetDagRunStatus(ctx context.Context, projSpec models.Project
 
 	return requestedJobStatus, nil
 }
+
+func (a *scheduler) GetRunningJobs(ctx context.Context, projSpec models.ProjectSpec) ([]models.RunningJob, error) {
+	// experimental api of airflow 1 can't list runs across dags
+	return nil, errors.Wrapf(models.ErrUnsupportedSchedulerOperation, "listing running jobs with %s", a.GetName())
+}
+
+// TriggerJobRun creates a dag run with the experimental api, the run is
+// identified like the manual runs of airflow
+func (a *scheduler) TriggerJobRun(ctx context.Context, projSpec models.ProjectSpec, jobName string, logicalDate time.Time,
+	conf map[string]string) (string, error) {
+	schdHost, ok := projSpec.Config[models.ProjectSchedulerHost]
+	if !ok {
+		return "", errors.Errorf("scheduler host not set for %s", projSpec.Name)
+	}
+	schdHost = strings.Trim(schdHost, "/")
+
+	if conf == nil {
+		conf = map[string]string{}
+	}
+	runID := "manual__" + logicalDate.UTC().Format(time.RFC3339)
+	payload, err := json.Marshal(map[string]interface{}{
+		"run_id":         runID,
+		"execution_date": logicalDate.UTC().Format(time.RFC3339),
+		"conf":           conf,
+	})
+	if err != nil {
+		return "", err
+	}
+	postURL := schdHost + "/" + fmt.Sprintf(dagStatusURL, url.PathEscape(jobName))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, postURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to build http request for %s", postURL)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.httpClient.Do(request)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to trigger airflow dag run from %s", postURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusConflict {
+		return "", errors.Wrapf(models.ErrJobRunExists, "%s at %s", jobName, logicalDate.UTC().Format(time.RFC3339))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("failed to trigger airflow dag run from %s: %d", postURL, resp.StatusCode)
+	}
+	return runID, nil
+}
+
+func (a *scheduler) SetJobPaused(ctx context.Context, projSpec models.ProjectSpec, jobName string, paused bool) error {
+	schdHost, ok := projSpec.Config[models.ProjectSchedulerHost]
+	if !ok {
+		return errors.Errorf("scheduler host not set for %s", projSpec.Name)
+	}
+	schdHost = strings.Trim(schdHost, "/")
+
+	pausedURL := fmt.Sprintf(fmt.Sprintf("%s/%s", schdHost, dagPausedURL), jobName, paused)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, pausedURL, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to build http request for %s", pausedURL)
+	}
+
+	resp, err := a.httpClient.Do(request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set paused state of airflow dag from %s", pausedURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to set paused state of airflow dag from %s: %d", pausedURL, resp.StatusCode)
+	}
+	return nil
+}
+
+func (a *scheduler) GetSchedulerVersion(ctx context.Context, projSpec models.ProjectSpec) (string, error) {
+	// experimental api of airflow 1 doesn't expose the version
+	return "", errors.Wrapf(models.ErrUnsupportedSchedulerOperation, "fetching version of %s", a.GetName())
+}
+
+func (a *scheduler) ListDeployedJobs(ctx context.Context, projSpec models.ProjectSpec) ([]string, error) {
+	// experimental api of airflow 1 can't list dags
+	return nil, errors.Wrapf(models.ErrUnsupportedSchedulerOperation, "listing deployed jobs with %s", a.GetName())
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/odpf/optimus/ext/scheduler/airflow"
 	mocked "github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -254,14 +255,18 @@ func TestAirflow(t *testing.T) {
 ]`
 			expectedExecutionTime0, _ := time.Parse(models.InstanceScheduledAtTimeLayout, "2020-03-25T02:00:00+00:00")
 			expectedExecutionTime1, _ := time.Parse(models.InstanceScheduledAtTimeLayout, "2020-03-26T02:00:00+00:00")
+			expectedStartTime0, _ := time.Parse(time.RFC3339Nano, "2020-06-01T16:32:58.489042+00:00")
+			expectedStartTime1, _ := time.Parse(time.RFC3339Nano, "2020-06-01T16:33:01.020645+00:00")
 			expectedStatus := []models.JobStatus{
 				{
 					ScheduledAt: expectedExecutionTime0,
 					State:       models.JobStatusStateSuccess,
+					StartTime:   expectedStartTime0,
 				},
 				{
 					ScheduledAt: expectedExecutionTime1,
 					State:       models.JobStatusStateRunning,
+					StartTime:   expectedStartTime1,
 				},
 			}
 
@@ -332,4 +337,136 @@ func TestAirflow(t *testing.T) {
 			assert.Len(t, status, 0)
 		})
 	})
+	t.Run("GetRunningJobs", func(t *testing.T) {
+		t.Run("should fail as listing runs is not supported", func(t *testing.T) {
+			air := airflow.NewScheduler(nil, &MockHttpClient{})
+			_, err := air.GetRunningJobs(ctx, models.ProjectSpec{Name: "test-proj"})
+			assert.True(t, errors.Is(err, models.ErrUnsupportedSchedulerOperation))
+		})
+	})
+	t.Run("GetSchedulerVersion", func(t *testing.T) {
+		t.Run("should fail as version is not exposed", func(t *testing.T) {
+			air := airflow.NewScheduler(nil, &MockHttpClient{})
+			_, err := air.GetSchedulerVersion(ctx, models.ProjectSpec{Name: "test-proj"})
+			assert.True(t, errors.Is(err, models.ErrUnsupportedSchedulerOperation))
+		})
+	})
+	t.Run("ListDeployedJobs", func(t *testing.T) {
+		t.Run("should fail as listing dags is not supported", func(t *testing.T) {
+			air := airflow.NewScheduler(nil, &MockHttpClient{})
+			_, err := air.ListDeployedJobs(ctx, models.ProjectSpec{Name: "test-proj"})
+			assert.True(t, errors.Is(err, models.ErrUnsupportedSchedulerOperation))
+		})
+	})
+	t.Run("TriggerJobRun", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			Name: "test-proj",
+			Config: map[string]string{
+				models.ProjectSchedulerHost: "http://airflow.example.io",
+			},
+		}
+		logicalDate := time.Date(2021, 3, 1, 2, 0, 0, 0, time.UTC)
+
+		t.Run("should create a dag run at the logical date", func(t *testing.T) {
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, http.MethodPost, req.Method)
+					assert.Equal(t, "http://airflow.example.io/api/experimental/dags/sample_select/dag_runs", req.URL.String())
+					body, _ := ioutil.ReadAll(req.Body)
+					assert.JSONEq(t, `{"run_id": "manual__2021-03-01T02:00:00Z", "execution_date": "2021-03-01T02:00:00Z",
+						"conf": {"backfill": "true"}}`, string(body))
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"message": "Created"}`))),
+					}, nil
+				},
+			}
+
+			air := airflow.NewScheduler(nil, client)
+			runID, err := air.TriggerJobRun(ctx, projectSpec, "sample_select", logicalDate, map[string]string{"backfill": "true"})
+			assert.Nil(t, err)
+			assert.Equal(t, "manual__2021-03-01T02:00:00Z", runID)
+		})
+		t.Run("should fail if host fails to return OK", func(t *testing.T) {
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusBadRequest,
+						Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"error": "dag run exists"}`))),
+					}, nil
+				},
+			}
+
+			air := airflow.NewScheduler(nil, client)
+			_, err := air.TriggerJobRun(ctx, projectSpec, "sample_select", logicalDate, nil)
+			assert.NotNil(t, err)
+		})
+		t.Run("should escape the name of the dag in the path", func(t *testing.T) {
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, "http://airflow.example.io/api/experimental/dags/sample%2Fselect%3F/dag_runs", req.URL.String())
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"message": "Created"}`))),
+					}, nil
+				},
+			}
+
+			air := airflow.NewScheduler(nil, client)
+			_, err := air.TriggerJobRun(ctx, projectSpec, "sample/select?", logicalDate, nil)
+			assert.Nil(t, err)
+		})
+		t.Run("should return run exists if the dag has a run at the logical date", func(t *testing.T) {
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusConflict,
+						Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"error": "dag run exists"}`))),
+					}, nil
+				},
+			}
+
+			air := airflow.NewScheduler(nil, client)
+			_, err := air.TriggerJobRun(ctx, projectSpec, "sample_select", logicalDate, nil)
+			assert.True(t, errors.Is(err, models.ErrJobRunExists))
+		})
+	})
+	t.Run("SetJobPaused", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			Name: "test-proj",
+			Config: map[string]string{
+				models.ProjectSchedulerHost: "http://airflow.example.io",
+			},
+		}
+
+		t.Run("should set paused state of the dag", func(t *testing.T) {
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, "http://airflow.example.io/api/experimental/dags/sample_select/paused/true", req.URL.String())
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"response": "ok"}`))),
+					}, nil
+				},
+			}
+
+			air := airflow.NewScheduler(nil, client)
+			err := air.SetJobPaused(ctx, projectSpec, "sample_select", true)
+			assert.Nil(t, err)
+		})
+		t.Run("should fail if host fails to return OK", func(t *testing.T) {
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusNotFound,
+						Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"error": "Dag id sample_select not found"}`))),
+					}, nil
+				},
+			}
+
+			air := airflow.NewScheduler(nil, client)
+			err := air.SetJobPaused(ctx, projectSpec, "sample_select", false)
+			assert.NotNil(t, err)
+		})
+	})
 }
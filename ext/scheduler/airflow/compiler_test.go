@@ -48,6 +48,10 @@ func TestCompiler(t *testing.T) {
 
 	projSpec := models.ProjectSpec{
 		Name: "foo-project",
+		BlackoutWindows: []models.BlackoutWindow{
+			{Start: "0 2 * * 0", Duration: time.Hour * 2},
+			{Start: "0 0 1 * *", Duration: time.Hour, JobPattern: "^bar-"},
+		},
 	}
 
 	namespaceSpec := models.NamespaceSpec{
@@ -123,6 +127,11 @@ func TestCompiler(t *testing.T) {
 		},
 		Unit:      &models.Plugin{Base: hookUnit2},
 		DependsOn: []*models.JobSpecHook{&hook1},
+		Retry: models.JobSpecBehaviorRetry{
+			Count:              2,
+			Delay:              time.Minute * 5,
+			ExponentialBackoff: true,
+		},
 	}
 	hook3 := models.JobSpecHook{
 		Unit: &models.Plugin{Base: hookUnit3},
@@ -147,6 +156,11 @@ func TestCompiler(t *testing.T) {
 				Offset:     0,
 				TruncateTo: "d",
 			},
+			Resources: models.JobSpecTaskResources{
+				CPURequest:    "500m",
+				MemoryRequest: "1Gi",
+				MemoryLimit:   "2Gi",
+			},
 		},
 		Dependencies: map[string]models.JobSpecDependency{
 			// we'll add resolved dependencies
@@ -173,6 +187,8 @@ func TestCompiler(t *testing.T) {
 			com := job.NewCompiler(
 				scheduler.GetTemplate(),
 				"http://airflow.example.io",
+				nil,
+				job.ValidateAirflowDAG,
 			)
 			job, err := com.Compile(namespaceSpec, spec)
 			assert.Nil(t, err)
@@ -3,13 +3,15 @@
 from typing import Any, Callable, Dict, Optional
 from datetime import datetime, timedelta, timezone
 
+import pendulum
 from airflow.models import DAG, Variable, DagRun, DagModel, TaskInstance, BaseOperator, XCom, XCOM_RETURN_KEY
 from airflow.kubernetes.secret import Secret
 from airflow.configuration import conf
+from airflow.operators.python_operator import ShortCircuitOperator
 from airflow.utils.weight_rule import WeightRule
 
-from __lib import optimus_failure_notify, optimus_sla_miss_notify, SuperKubernetesPodOperator, \
-    SuperExternalTaskSensor, CrossTenantDependencySensor
+from __lib import optimus_failure_notify, optimus_sla_miss_notify, optimus_success_notify, \
+    SuperKubernetesPodOperator, SuperExternalTaskSensor, CrossTenantDependencySensor, outside_blackout_windows
 
 SENSOR_DEFAULT_POKE_INTERVAL_IN_SECS = int(Variable.get("sensor_poke_interval_in_secs", default_var=15 * 60))
 SENSOR_DEFAULT_TIMEOUT_IN_SECS = int(Variable.get("sensor_timeout_in_secs", default_var=15 * 60 * 60))
@@ -40,9 +42,21 @@ dag = DAG(
     default_args=default_args,
     schedule_interval="* * * * *",
     sla_miss_callback=optimus_sla_miss_notify,
+    on_success_callback=optimus_success_notify,
     catchup = True
 )
 
+# skips runs scheduled within the blackout windows of the project
+blackout_windows = ShortCircuitOperator(
+    task_id="blackout_windows",
+    python_callable=outside_blackout_windows,
+    op_kwargs={"blackout_windows": [
+        {"start": "0 2 * * 0", "duration_in_secs": 7200},
+    ]},
+    provide_context=True,
+    dag=dag
+)
+
 transformation_secret = Secret(
     "volume",
     "/opt/optimus/secrets",
@@ -69,6 +83,11 @@ transformation_bq = SuperKubernetesPodOperator(
         "INSTANCE_TYPE":'task', "INSTANCE_NAME":'bq',
         "SCHEDULED_AT":'{{ next_execution_date }}',
     },
+    resources={
+        "request_cpu": "500m",
+        "request_memory": "1Gi",
+        "limit_memory": "2Gi",
+    },
 
     reattach_on_restart=True
 )
@@ -128,6 +147,9 @@ hook_predator = SuperKubernetesPodOperator(
         "SCHEDULED_AT":'{{ next_execution_date }}',
         # rest of the env vars are pulled from the container by making a GRPC call to optimus
     },
+    retries=2,
+    retry_delay=timedelta(seconds=300),
+    retry_exponential_backoff=True,
     reattach_on_restart=True
 )
 
@@ -185,6 +207,12 @@ wait_foo__dash__inter__dash__dep__dash__job = CrossTenantDependencySensor(
 # arrange inter task dependencies
 ####################################
 
+# blackout windows -> upstream sensors, pre hooks and base transformation task
+blackout_windows >> transformation_bq
+blackout_windows >> wait_foo__dash__intra__dash__dep__dash__job
+blackout_windows >> wait_foo__dash__inter__dash__dep__dash__job
+blackout_windows >> hook_transporter
+
 # upstream sensors -> base transformation task
 wait_foo__dash__intra__dash__dep__dash__job >> transformation_bq
 wait_foo__dash__inter__dash__dep__dash__job >> transformation_bq
@@ -2,6 +2,7 @@ package airflow2
 
 import (
 	_ "embed"
+	"strings"
 	"testing"
 	"time"
 
@@ -48,6 +49,10 @@ func TestCompiler(t *testing.T) {
 
 	projSpec := models.ProjectSpec{
 		Name: "foo-project",
+		BlackoutWindows: []models.BlackoutWindow{
+			{Start: "0 2 * * 0", Duration: time.Hour * 2},
+			{Start: "0 0 1 * *", Duration: time.Hour, JobPattern: "^bar-"},
+		},
 	}
 
 	namespaceSpec := models.NamespaceSpec{
@@ -123,6 +128,11 @@ func TestCompiler(t *testing.T) {
 		},
 		Unit:      &models.Plugin{Base: hookUnit2},
 		DependsOn: []*models.JobSpecHook{&hook1},
+		Retry: models.JobSpecBehaviorRetry{
+			Count:              2,
+			Delay:              time.Minute * 5,
+			ExponentialBackoff: true,
+		},
 	}
 	hook3 := models.JobSpecHook{
 		Config: []models.JobSpecConfigItem{},
@@ -151,6 +161,7 @@ func TestCompiler(t *testing.T) {
 			StartDate: time.Date(2000, 11, 11, 0, 0, 0, 0, time.UTC),
 			EndDate:   &scheduleEndDate,
 			Interval:  "* * * * *",
+			Timezone:  "Asia/Jakarta",
 		},
 		Task: models.JobSpecTask{
 			Unit:     &models.Plugin{Base: execUnit},
@@ -160,6 +171,11 @@ func TestCompiler(t *testing.T) {
 				Offset:     0,
 				TruncateTo: "d",
 			},
+			Resources: models.JobSpecTaskResources{
+				CPURequest:    "500m",
+				MemoryRequest: "1Gi",
+				MemoryLimit:   "2Gi",
+			},
 		},
 		Dependencies: map[string]models.JobSpecDependency{
 			// we'll add resolved dependencies
@@ -186,10 +202,36 @@ func TestCompiler(t *testing.T) {
 			com := job.NewCompiler(
 				scheduler.GetTemplate(),
 				"http://airflow.example.io",
+				nil,
+				job.ValidateAirflowDAG,
 			)
 			job, err := com.Compile(namespaceSpec, spec)
 			assert.Nil(t, err)
 			assert.Equal(t, string(CompiledTemplate), string(job.Contents))
 		})
+		t.Run("should insert the git commit the job is deployed from", func(t *testing.T) {
+			scheduler := NewScheduler(nil, nil)
+			com := job.NewCompiler(
+				scheduler.GetTemplate(),
+				"http://airflow.example.io",
+				nil,
+				job.ValidateAirflowDAG,
+			)
+			compiled, err := com.CompileWithGit(namespaceSpec, spec, job.GitContext{
+				CommitSHA:  "4f1b2c3d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b",
+				Branch:     "main",
+				Author:     "Jane Doe <jane@example.io>",
+				CommitTime: "2021-03-01T10:00:00+05:30",
+			})
+			assert.Nil(t, err)
+			header := "# Git commit: 4f1b2c3d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b\n" +
+				"# Git branch: main\n" +
+				"# Git author: Jane Doe <jane@example.io>\n" +
+				"# Git commit time: 2021-03-01T10:00:00+05:30\n\n" +
+				"from typing"
+			assert.Contains(t, string(compiled.Contents), header)
+			// the rest of the job compiles the same
+			assert.Equal(t, string(CompiledTemplate), strings.Replace(string(compiled.Contents), strings.TrimSuffix(header, "\nfrom typing"), "", 1))
+		})
 	})
 }
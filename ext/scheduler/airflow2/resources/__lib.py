@@ -1,7 +1,7 @@
 import json
 import logging
 import os
-from datetime import datetime
+from datetime import datetime, timedelta, timezone
 from typing import List
 import pendulum
 
@@ -387,6 +387,24 @@ class CrossTenantDependencySensor(BaseSensorOperator):
             return datetime.strptime(timestamp, self.TIMESTAMP_MS_FORMAT)
 
 
+def outside_blackout_windows(blackout_windows: List[dict], **context) -> bool:
+    """
+    short circuits the run if it is scheduled at or after the start of one of
+    the blackout windows and before its end, windows start at a cron expression
+    in utc and last for duration_in_secs
+    """
+    scheduled_at = context["next_execution_date"].astimezone(timezone.utc).replace(tzinfo=None)
+    for window in blackout_windows:
+        # latest start of the window at or before the run
+        window_start = croniter(lookup_non_standard_cron_expression(window["start"]),
+                                scheduled_at + timedelta(seconds=1)).get_prev(datetime)
+        if scheduled_at < window_start + timedelta(seconds=window["duration_in_secs"]):
+            log.info("skipping run scheduled at {} in the blackout window starting at {}".format(
+                scheduled_at, window_start))
+            return False
+    return True
+
+
 def optimus_failure_notify(context):
     params = context.get("params")
     optimus_client = OptimusAPIClient(params["optimus_hostname"])
@@ -438,6 +456,28 @@ def optimus_failure_notify(context):
     return
 
 
+def optimus_success_notify(context):
+    params = context.get("params")
+    optimus_client = OptimusAPIClient(params["optimus_hostname"])
+
+    current_dag_id = context.get('dag').dag_id
+    current_execution_date = context.get('execution_date')
+    webserver_url = conf.get(section='webserver', key='base_url')
+    message = {
+        "run_id": context.get('run_id'),
+        "scheduled_at": current_execution_date.strftime("%Y-%m-%dT%H:%M:%SZ"),
+        "job_url": "{}/tree?dag_id={}".format(webserver_url, current_dag_id),
+    }
+    event = {
+        "type": "SUCCESS",
+        "value": message,
+    }
+    # post event
+    resp = optimus_client.notify_event(params["project_name"], params["namespace"], params["job_name"], event)
+    print("posted event ", params, event, resp)
+    return
+
+
 def optimus_sla_miss_notify(dag, task_list, blocking_task_list, slas, blocking_tis):
     params = dag.params
     optimus_client = OptimusAPIClient(params["optimus_hostname"])
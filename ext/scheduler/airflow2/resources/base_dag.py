@@ -1,16 +1,30 @@
 # Code generated by optimus {{.Version}}. DO NOT EDIT.
+{{- if .GIT_SHA}}
+# Git commit: {{.GIT_SHA}}
+{{- if .GIT_BRANCH}}
+# Git branch: {{.GIT_BRANCH}}
+{{- end}}
+{{- if .GIT_AUTHOR}}
+# Git author: {{.GIT_AUTHOR}}
+{{- end}}
+{{- if .GIT_COMMIT_TIME}}
+# Git commit time: {{.GIT_COMMIT_TIME}}
+{{- end}}
+{{- end}}
 
 from typing import Any, Callable, Dict, Optional
 from datetime import datetime, timedelta, timezone
 
+import pendulum
 from airflow.models import DAG, Variable, DagRun, DagModel, TaskInstance, BaseOperator, XCom, XCOM_RETURN_KEY
 from airflow.kubernetes.secret import Secret
 from airflow.configuration import conf
+from airflow.operators.python import ShortCircuitOperator
 from airflow.utils.weight_rule import WeightRule
 from kubernetes.client import models as k8s
 
-from __lib import optimus_failure_notify, optimus_sla_miss_notify, SuperKubernetesPodOperator, \
-    SuperExternalTaskSensor, CrossTenantDependencySensor
+from __lib import optimus_failure_notify, optimus_sla_miss_notify, optimus_success_notify, \
+    SuperKubernetesPodOperator, SuperExternalTaskSensor, CrossTenantDependencySensor, outside_blackout_windows
 
 SENSOR_DEFAULT_POKE_INTERVAL_IN_SECS = int(Variable.get("sensor_poke_interval_in_secs", default_var=15 * 60))
 SENSOR_DEFAULT_TIMEOUT_IN_SECS = int(Variable.get("sensor_timeout_in_secs", default_var=15 * 60 * 60))
@@ -30,8 +44,8 @@ default_args = {
     "retry_delay": {{ if gt .Job.Behavior.Retry.Delay.Nanoseconds 0 -}} timedelta(seconds={{.Job.Behavior.Retry.Delay.Seconds}}) {{- else -}} timedelta(seconds=DAG_RETRY_DELAY) {{- end}},
     "retry_exponential_backoff": {{if .Job.Behavior.Retry.ExponentialBackoff -}}True{{- else -}}False{{- end -}},
     "priority_weight": {{.Job.Task.Priority}},
-    "start_date": datetime.strptime({{ .Job.Schedule.StartDate.Format "2006-01-02T15:04:05" | quote }}, "%Y-%m-%dT%H:%M:%S"),
-    {{if .Job.Schedule.EndDate -}}"end_date": datetime.strptime({{ .Job.Schedule.EndDate.Format "2006-01-02T15:04:05" | quote}},"%Y-%m-%dT%H:%M:%S"),{{- else -}}{{- end}}
+    "start_date": {{ if .Job.Schedule.Timezone }}pendulum.instance({{ end }}datetime.strptime({{ .Job.Schedule.StartDate.Format "2006-01-02T15:04:05" | quote }}, "%Y-%m-%dT%H:%M:%S"){{ if .Job.Schedule.Timezone }}, tz={{ .Job.Schedule.Timezone | quote }}){{ end }},
+    {{if .Job.Schedule.EndDate -}}"end_date": {{ if .Job.Schedule.Timezone }}pendulum.instance({{ end }}datetime.strptime({{ .Job.Schedule.EndDate.Format "2006-01-02T15:04:05" | quote}},"%Y-%m-%dT%H:%M:%S"){{ if .Job.Schedule.Timezone }}, tz={{ .Job.Schedule.Timezone | quote }}){{ end }},{{- else -}}{{- end}}
     "on_failure_callback": optimus_failure_notify,
     "weight_rule": WeightRule.ABSOLUTE
 }
@@ -41,9 +55,25 @@ dag = DAG(
     default_args=default_args,
     schedule_interval={{.Job.Schedule.Interval | quote}},
     sla_miss_callback=optimus_sla_miss_notify,
-    catchup = {{ if .Job.Behavior.CatchUp -}} True{{- else -}} False {{- end }}
+    on_success_callback=optimus_success_notify,
+    catchup = {{ if .Job.Behavior.CatchUp -}} True{{- else -}} False {{- end }},
+    tags = [{{ printf "project:%s" .Namespace.ProjectSpec.Name | quote }}{{ range $key, $value := .Job.Labels }}, {{ printf "%s:%s" $key $value | quote }}{{ end }}]
 )
 
+{{ if .BlackoutWindows -}}
+# skips runs scheduled within the blackout windows of the project
+blackout_windows = ShortCircuitOperator(
+    task_id="blackout_windows",
+    python_callable=outside_blackout_windows,
+    op_kwargs={"blackout_windows": [
+        {{- range $_, $window := .BlackoutWindows }}
+        {"start": {{ $window.Start | quote }}, "duration_in_secs": {{ $window.Duration.Seconds }}},
+        {{- end }}
+    ]},
+    dag=dag
+)
+
+{{ end -}}
 {{$baseTaskSchema := .Job.Task.Unit.Info -}}
 {{ if ne $baseTaskSchema.SecretPath "" -}}
 transformation_secret = Secret(
@@ -77,6 +107,26 @@ transformation_{{$baseTaskSchema.Name | replace "-" "__dash__" | replace "." "__
         k8s.V1EnvVar(name="INSTANCE_NAME",value='{{$baseTaskSchema.Name}}'),
         k8s.V1EnvVar(name="SCHEDULED_AT",value='{{ "{{ next_execution_date }}" }}'),
     ],
+{{- with .Job.Task.Resources }}{{ if not .IsZero }}
+    resources=k8s.V1ResourceRequirements(
+        requests={
+        {{- with .CPURequest }}
+            "cpu": {{ . | quote }},
+        {{- end }}
+        {{- with .MemoryRequest }}
+            "memory": {{ . | quote }},
+        {{- end }}
+        },
+        limits={
+        {{- with .CPULimit }}
+            "cpu": {{ . | quote }},
+        {{- end }}
+        {{- with .MemoryLimit }}
+            "memory": {{ . | quote }},
+        {{- end }}
+        },
+    ),
+{{- end }}{{ end }}
 {{- if gt .SLAMissDurationInSec 0 }}
     sla=timedelta(seconds={{ .SLAMissDurationInSec }}),
 {{- end }}
@@ -124,6 +174,11 @@ hook_{{$hookSchema.Name | replace "-" "__dash__"}} = SuperKubernetesPodOperator(
     {{ if eq $hookSchema.HookType $.HookTypeFail -}}
         trigger_rule="one_failed",
     {{ end -}}
+    {{ if gt $t.Retry.Count 0 -}}
+    retries={{ $t.Retry.Count }},
+    retry_delay=timedelta(seconds={{ $t.Retry.Delay.Seconds }}),
+    retry_exponential_backoff={{ if $t.Retry.ExponentialBackoff -}} True {{- else -}} False {{- end }},
+    {{ end -}}
     reattach_on_restart=True
 )
 {{- end }}
@@ -164,6 +219,20 @@ wait_{{$dependency.Job.Name | replace "-" "__dash__" | replace "." "__dot__"}} =
 
 # arrange inter task dependencies
 ####################################
+{{- if .BlackoutWindows }}
+
+# blackout windows -> upstream sensors, pre hooks and base transformation task
+blackout_windows >> transformation_{{$baseTaskSchema.Name | replace "-" "__dash__" | replace "." "__dot__"}}
+{{- range $i, $t := $.Job.Dependencies }}
+blackout_windows >> wait_{{ $t.Job.Name | replace "-" "__dash__" | replace "." "__dot__" }}
+{{- end}}
+{{- range $_, $task := .Job.Hooks }}
+{{- $hookSchema := $task.Unit.Info }}
+{{- if eq $hookSchema.HookType $.HookTypePre }}
+blackout_windows >> hook_{{$hookSchema.Name | replace "-" "__dash__"}}
+{{- end -}}
+{{- end }}
+{{- end }}
 
 # upstream sensors -> base transformation task
 {{- range $i, $t := $.Job.Dependencies }}
@@ -191,6 +260,9 @@ transformation_{{$baseTaskSchema.Name | replace "-" "__dash__" | replace "." "__
 {{- $dependHookSchema := $depend.Unit.Info }}
 hook_{{$dependHookSchema.Name | replace "-" "__dash__"}} >> hook_{{$hookSchema.Name | replace "-" "__dash__"}}
 {{- end }}
+{{- range $_, $after := $t.RunAfter }}
+hook_{{$after | replace "-" "__dash__"}} >> hook_{{$hookSchema.Name | replace "-" "__dash__"}}
+{{- end }}
 {{- end }}
 
 # arrange failure hook after post hooks
@@ -3,14 +3,16 @@
 from typing import Any, Callable, Dict, Optional
 from datetime import datetime, timedelta, timezone
 
+import pendulum
 from airflow.models import DAG, Variable, DagRun, DagModel, TaskInstance, BaseOperator, XCom, XCOM_RETURN_KEY
 from airflow.kubernetes.secret import Secret
 from airflow.configuration import conf
+from airflow.operators.python import ShortCircuitOperator
 from airflow.utils.weight_rule import WeightRule
 from kubernetes.client import models as k8s
 
-from __lib import optimus_failure_notify, optimus_sla_miss_notify, SuperKubernetesPodOperator, \
-    SuperExternalTaskSensor, CrossTenantDependencySensor
+from __lib import optimus_failure_notify, optimus_sla_miss_notify, optimus_success_notify, \
+    SuperKubernetesPodOperator, SuperExternalTaskSensor, CrossTenantDependencySensor, outside_blackout_windows
 
 SENSOR_DEFAULT_POKE_INTERVAL_IN_SECS = int(Variable.get("sensor_poke_interval_in_secs", default_var=15 * 60))
 SENSOR_DEFAULT_TIMEOUT_IN_SECS = int(Variable.get("sensor_timeout_in_secs", default_var=15 * 60 * 60))
@@ -30,8 +32,8 @@ default_args = {
     "retry_delay": timedelta(seconds=DAG_RETRY_DELAY),
     "retry_exponential_backoff": True,
     "priority_weight": 2000,
-    "start_date": datetime.strptime("2000-11-11T00:00:00", "%Y-%m-%dT%H:%M:%S"),
-    "end_date": datetime.strptime("2020-11-11T00:00:00","%Y-%m-%dT%H:%M:%S"),
+    "start_date": pendulum.instance(datetime.strptime("2000-11-11T00:00:00", "%Y-%m-%dT%H:%M:%S"), tz="Asia/Jakarta"),
+    "end_date": pendulum.instance(datetime.strptime("2020-11-11T00:00:00","%Y-%m-%dT%H:%M:%S"), tz="Asia/Jakarta"),
     "on_failure_callback": optimus_failure_notify,
     "weight_rule": WeightRule.ABSOLUTE
 }
@@ -41,7 +43,19 @@ dag = DAG(
     default_args=default_args,
     schedule_interval="* * * * *",
     sla_miss_callback=optimus_sla_miss_notify,
-    catchup = True
+    on_success_callback=optimus_success_notify,
+    catchup = True,
+    tags = ["project:foo-project", "orchestrator:optimus"]
+)
+
+# skips runs scheduled within the blackout windows of the project
+blackout_windows = ShortCircuitOperator(
+    task_id="blackout_windows",
+    python_callable=outside_blackout_windows,
+    op_kwargs={"blackout_windows": [
+        {"start": "0 2 * * 0", "duration_in_secs": 7200},
+    ]},
+    dag=dag
 )
 
 transformation_secret = Secret(
@@ -74,6 +88,15 @@ transformation_bq = SuperKubernetesPodOperator(
         k8s.V1EnvVar(name="INSTANCE_NAME",value='bq'),
         k8s.V1EnvVar(name="SCHEDULED_AT",value='{{ next_execution_date }}'),
     ],
+    resources=k8s.V1ResourceRequirements(
+        requests={
+            "cpu": "500m",
+            "memory": "1Gi",
+        },
+        limits={
+            "memory": "2Gi",
+        },
+    ),
     sla=timedelta(seconds=7200),
     reattach_on_restart=True
 )
@@ -141,6 +164,9 @@ hook_predator = SuperKubernetesPodOperator(
         k8s.V1EnvVar(name="SCHEDULED_AT",value='{{ next_execution_date }}'),
         # rest of the env vars are pulled from the container by making a GRPC call to optimus
     ],
+    retries=2,
+    retry_delay=timedelta(seconds=300),
+    retry_exponential_backoff=True,
     reattach_on_restart=True
 )
 
@@ -202,6 +228,12 @@ wait_foo__dash__inter__dash__dep__dash__job = CrossTenantDependencySensor(
 # arrange inter task dependencies
 ####################################
 
+# blackout windows -> upstream sensors, pre hooks and base transformation task
+blackout_windows >> transformation_bq
+blackout_windows >> wait_foo__dash__intra__dash__dep__dash__job
+blackout_windows >> wait_foo__dash__inter__dash__dep__dash__job
+blackout_windows >> hook_transporter
+
 # upstream sensors -> base transformation task
 wait_foo__dash__intra__dash__dep__dash__job >> transformation_bq
 wait_foo__dash__inter__dash__dep__dash__job >> transformation_bq
@@ -0,0 +1,158 @@
+package instance
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	// AssetCacheTTL is the default duration compiled assets are reused for
+	AssetCacheTTL = 5 * time.Minute
+
+	// AssetCacheSize is the default number of compiled assets kept, the least
+	// recently used assets are evicted first
+	AssetCacheSize = 1000
+)
+
+// AssetCache reuses asset files compiled for running instances of a job,
+// fetched again by each task and hook of the instance. Compiled assets are
+// keyed by project, job and scheduled time, and are compiled again once they
+// expire or if the job, its task plugin, the project or the instance data
+// they were compiled from changed
+type AssetCache struct {
+	Now func() time.Time
+
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	recency *list.List
+}
+
+type cachedAssets struct {
+	key       string
+	hash      string
+	assets    map[string]string
+	expiresAt time.Time
+}
+
+// compile returns the cached assets of key if they were compiled from inputs
+// with the same hash, compiles them with compileFn otherwise
+func (c *AssetCache) compile(key, hash string, compileFn func() (map[string]string, error)) (map[string]string, error) {
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		cached := elem.Value.(*cachedAssets)
+		if cached.hash == hash && c.Now().Before(cached.expiresAt) {
+			c.recency.MoveToFront(elem)
+			c.mu.Unlock()
+			return copyAssets(cached.assets), nil
+		}
+		c.recency.Remove(elem)
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+
+	assets, err := compileFn()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		// compiled by a concurrent call meanwhile
+		c.recency.Remove(elem)
+		delete(c.entries, key)
+	}
+	c.entries[key] = c.recency.PushFront(&cachedAssets{
+		key:       key,
+		hash:      hash,
+		assets:    copyAssets(assets),
+		expiresAt: c.Now().Add(c.ttl),
+	})
+	for c.recency.Len() > c.size {
+		oldest := c.recency.Back()
+		c.recency.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedAssets).key)
+	}
+	return assets, nil
+}
+
+// assetCacheKey identifies the instance of a job assets are compiled for
+func assetCacheKey(namespace models.NamespaceSpec, jobSpec models.JobSpec, scheduledAt time.Time) string {
+	return namespace.ProjectSpec.Name + "/" + jobSpec.Name + "/" + strconv.FormatInt(scheduledAt.Unix(), 10)
+}
+
+// assetInputHash hashes everything assets of an instance are compiled from
+// besides the scheduled time
+func assetInputHash(namespace models.NamespaceSpec, jobSpec models.JobSpec, instanceSpec models.InstanceSpec) string {
+	h := sha256.New()
+	if jobSpec.Task.Unit != nil && jobSpec.Task.Unit.Base != nil {
+		if info := jobSpec.Task.Unit.Info(); info != nil {
+			fmt.Fprintf(h, "plugin\x00%s\x00%s\x00%s\x00", info.Name, info.PluginVersion, info.Image)
+		}
+	}
+	fmt.Fprintf(h, "window\x00%v\x00", jobSpec.Task.Window)
+	for _, conf := range jobSpec.Task.Config {
+		fmt.Fprintf(h, "config\x00%s\x00%s\x00", conf.Name, conf.Value)
+	}
+	for _, asset := range jobSpec.Assets.GetAll() {
+		fmt.Fprintf(h, "asset\x00%s\x00%s\x00", asset.Name, asset.Value)
+	}
+	for _, data := range instanceSpec.Data {
+		fmt.Fprintf(h, "data\x00%s\x00%s\x00%s\x00", data.Type, data.Name, data.Value)
+	}
+	hashStringMap(h, "project", namespace.ProjectSpec.Config)
+	hashStringMap(h, "namespace", namespace.Config)
+	hashStringMap(h, "variable", namespace.ProjectSpec.Variables)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashStringMap writes the values of m to w sorted by their keys
+func hashStringMap(w io.Writer, kind string, m map[string]string) {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\x00%s\x00%s\x00", kind, key, m[key])
+	}
+}
+
+// copyAssets keeps callers modifying returned assets from changing the cache
+func copyAssets(assets map[string]string) map[string]string {
+	copied := make(map[string]string, len(assets))
+	for name, value := range assets {
+		copied[name] = value
+	}
+	return copied
+}
+
+// NewAssetCache caches compiled assets, size and ttl default to
+// AssetCacheSize and AssetCacheTTL if not positive
+func NewAssetCache(size int, ttl time.Duration) *AssetCache {
+	if size <= 0 {
+		size = AssetCacheSize
+	}
+	if ttl <= 0 {
+		ttl = AssetCacheTTL
+	}
+	return &AssetCache{
+		Now:     time.Now,
+		size:    size,
+		ttl:     ttl,
+		entries: map[string]*list.Element{},
+		recency: list.New(),
+	}
+}
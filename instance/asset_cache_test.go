@@ -0,0 +1,209 @@
+package instance_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/instance"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingCLIMod returns the assets of the job as they are and counts how
+// often they are compiled
+type countingCLIMod struct {
+	models.CommandLineMod
+	compiled int32
+}
+
+func (m *countingCLIMod) CompileAssets(ctx context.Context, req models.CompileAssetsRequest) (*models.CompileAssetsResponse, error) {
+	atomic.AddInt32(&m.compiled, 1)
+	return &models.CompileAssetsResponse{Assets: req.Assets}, nil
+}
+
+type versionedPlugin struct {
+	models.BasePlugin
+	version string
+}
+
+func (p *versionedPlugin) PluginInfo() (*models.PluginInfoResponse, error) {
+	return &models.PluginInfoResponse{Name: "bq2bq", PluginVersion: p.version}, nil
+}
+
+func TestAssetCache(t *testing.T) {
+	namespaceSpec := models.NamespaceSpec{
+		Name: "namespace-1",
+		ProjectSpec: models.ProjectSpec{
+			Name:   "project-1",
+			Config: map[string]string{"DATASET": "warehouse"},
+		},
+	}
+	scheduledAt := time.Date(2021, 3, 2, 2, 0, 0, 0, time.UTC)
+	newJobSpec := func(cliMod *countingCLIMod, version, query string) models.JobSpec {
+		return models.JobSpec{
+			Name: "foo",
+			Task: models.JobSpecTask{
+				Unit: &models.Plugin{Base: &versionedPlugin{version: version}, CLIMod: cliMod},
+				Window: models.JobSpecTaskWindow{
+					Size:       time.Hour * 24,
+					TruncateTo: "d",
+				},
+			},
+			Assets: *models.JobAssets{}.New(
+				[]models.JobSpecAsset{
+					{
+						Name:  "query.sql",
+						Value: query,
+					},
+				},
+			),
+		}
+	}
+	newInstance := func(jobSpec models.JobSpec, scheduledAt time.Time) models.InstanceSpec {
+		return models.InstanceSpec{
+			Job:         jobSpec,
+			ScheduledAt: scheduledAt,
+			Data: []models.InstanceSpecData{
+				{
+					Name:  instance.ConfigKeyDstart,
+					Value: jobSpec.Task.Window.GetStart(scheduledAt).Format(models.InstanceScheduledAtTimeLayout),
+					Type:  models.InstanceDataTypeEnv,
+				},
+			},
+		}
+	}
+	newService := func(cache *instance.AssetCache) *instance.Service {
+		service := instance.NewService(nil, time.Now, instance.NewGoEngine())
+		service.AssetCache = cache
+		return service
+	}
+	query := "select * from {{.GLOBAL__DATASET}}.orders where ts >= '{{.DSTART}}'"
+
+	t.Run("should reuse assets compiled for the same instance", func(t *testing.T) {
+		cliMod := &countingCLIMod{}
+		service := newService(instance.NewAssetCache(0, 0))
+		jobSpec := newJobSpec(cliMod, "1.0.0", query)
+
+		for i := 0; i < 3; i++ {
+			_, fileMap, err := service.Compile(namespaceSpec, jobSpec, newInstance(jobSpec, scheduledAt), models.InstanceTypeTask, "bq2bq")
+			assert.Nil(t, err)
+			assert.Equal(t, "select * from warehouse.orders where ts >= '2021-03-01T00:00:00Z'", fileMap["query.sql"])
+		}
+		assert.Equal(t, int32(1), cliMod.compiled)
+
+		_, _, err := service.Compile(namespaceSpec, jobSpec, newInstance(jobSpec, scheduledAt.Add(time.Hour*24)),
+			models.InstanceTypeTask, "bq2bq")
+		assert.Nil(t, err)
+		assert.Equal(t, int32(2), cliMod.compiled)
+	})
+	t.Run("should not reuse assets of jobs with the same name in other projects", func(t *testing.T) {
+		cliMod := &countingCLIMod{}
+		service := newService(instance.NewAssetCache(0, 0))
+		jobSpec := newJobSpec(cliMod, "1.0.0", query)
+		otherNamespace := namespaceSpec
+		otherNamespace.ProjectSpec = models.ProjectSpec{Name: "project-2", Config: map[string]string{"DATASET": "playground"}}
+
+		_, _, err := service.Compile(namespaceSpec, jobSpec, newInstance(jobSpec, scheduledAt), models.InstanceTypeTask, "bq2bq")
+		assert.Nil(t, err)
+		_, fileMap, err := service.Compile(otherNamespace, jobSpec, newInstance(jobSpec, scheduledAt), models.InstanceTypeTask, "bq2bq")
+		assert.Nil(t, err)
+		assert.Contains(t, fileMap["query.sql"], "playground.orders")
+		assert.Equal(t, int32(2), cliMod.compiled)
+	})
+	t.Run("should compile again if anything the assets are compiled from changed", func(t *testing.T) {
+		cliMod := &countingCLIMod{}
+		service := newService(instance.NewAssetCache(0, 0))
+		jobSpec := newJobSpec(cliMod, "1.0.0", query)
+		changedProject := namespaceSpec
+		changedProject.ProjectSpec.Config = map[string]string{"DATASET": "archive"}
+		changedVariables := changedProject
+		changedVariables.ProjectSpec.Variables = models.ProjectVariables{"GCP_PROJECT": "data-project"}
+		changedData := newInstance(jobSpec, scheduledAt)
+		changedData.Data = append(changedData.Data, models.InstanceSpecData{
+			Name: "input.json", Value: "{}", Type: models.InstanceDataTypeFile,
+		})
+
+		calls := []struct {
+			namespace models.NamespaceSpec
+			jobSpec   models.JobSpec
+			instance  models.InstanceSpec
+		}{
+			{namespaceSpec, jobSpec, newInstance(jobSpec, scheduledAt)},
+			{namespaceSpec, newJobSpec(cliMod, "1.1.0", query), newInstance(jobSpec, scheduledAt)},
+			{namespaceSpec, newJobSpec(cliMod, "1.1.0", "select 1"), newInstance(jobSpec, scheduledAt)},
+			{changedProject, newJobSpec(cliMod, "1.1.0", "select 1"), newInstance(jobSpec, scheduledAt)},
+			{changedProject, newJobSpec(cliMod, "1.1.0", "select 1"), changedData},
+			{changedVariables, newJobSpec(cliMod, "1.1.0", "select 1"), changedData},
+		}
+		for _, call := range calls {
+			_, _, err := service.Compile(call.namespace, call.jobSpec, call.instance, models.InstanceTypeTask, "bq2bq")
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, int32(len(calls)), cliMod.compiled)
+	})
+	t.Run("should compile again once compiled assets expired", func(t *testing.T) {
+		cliMod := &countingCLIMod{}
+		cache := instance.NewAssetCache(0, time.Minute)
+		now := time.Date(2021, 3, 2, 3, 0, 0, 0, time.UTC)
+		cache.Now = func() time.Time { return now }
+		service := newService(cache)
+		jobSpec := newJobSpec(cliMod, "1.0.0", query)
+		instanceSpec := newInstance(jobSpec, scheduledAt)
+
+		_, _, err := service.Compile(namespaceSpec, jobSpec, instanceSpec, models.InstanceTypeTask, "bq2bq")
+		assert.Nil(t, err)
+		now = now.Add(time.Second * 59)
+		_, _, err = service.Compile(namespaceSpec, jobSpec, instanceSpec, models.InstanceTypeTask, "bq2bq")
+		assert.Nil(t, err)
+		assert.Equal(t, int32(1), cliMod.compiled)
+
+		now = now.Add(time.Second)
+		_, _, err = service.Compile(namespaceSpec, jobSpec, instanceSpec, models.InstanceTypeTask, "bq2bq")
+		assert.Nil(t, err)
+		assert.Equal(t, int32(2), cliMod.compiled)
+	})
+	t.Run("should evict the least recently used assets", func(t *testing.T) {
+		cliMod := &countingCLIMod{}
+		service := newService(instance.NewAssetCache(2, 0))
+		jobSpec := newJobSpec(cliMod, "1.0.0", query)
+		first, second, third := scheduledAt, scheduledAt.Add(time.Hour), scheduledAt.Add(time.Hour*2)
+
+		for _, at := range []time.Time{first, second, first, third, first, second} {
+			_, _, err := service.Compile(namespaceSpec, jobSpec, newInstance(jobSpec, at), models.InstanceTypeTask, "bq2bq")
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, int32(4), cliMod.compiled)
+	})
+	t.Run("should compile on every call without a cache", func(t *testing.T) {
+		cliMod := &countingCLIMod{}
+		service := newService(nil)
+		jobSpec := newJobSpec(cliMod, "1.0.0", query)
+
+		for i := 0; i < 2; i++ {
+			_, _, err := service.Compile(namespaceSpec, jobSpec, newInstance(jobSpec, scheduledAt), models.InstanceTypeTask, "bq2bq")
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, int32(2), cliMod.compiled)
+	})
+	t.Run("should be safe to call concurrently", func(t *testing.T) {
+		cliMod := &countingCLIMod{}
+		service := newService(instance.NewAssetCache(4, 0))
+		jobSpec := newJobSpec(cliMod, "1.0.0", query)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				at := scheduledAt.Add(time.Hour * 24 * time.Duration(i%8))
+				_, fileMap, err := service.Compile(namespaceSpec, jobSpec, newInstance(jobSpec, at), models.InstanceTypeTask, "bq2bq")
+				assert.Nil(t, err)
+				assert.Contains(t, fileMap["query.sql"], "warehouse.orders")
+			}(i)
+		}
+		wg.Wait()
+	})
+}
@@ -37,6 +37,10 @@ type ContextManager struct {
 	namespace models.NamespaceSpec
 	jobSpec   models.JobSpec
 	engine    models.TemplateEngine
+
+	// assetCache reuses assets compiled for the instance, assets are
+	// compiled on every call if nil
+	assetCache *AssetCache
 }
 
 // Generate fetches and compiles all config data related to an instance and
@@ -70,7 +74,27 @@ func (fm *ContextManager) Generate(
 		}
 	}
 
-	// do the same for asset files
+	// do the same for asset files, like when jobs are compiled variables of
+	// the project and configs of the job are available in their templates
+	assetContext := MergeInterfaceMapToInterface(fm.assetVariables(), projectInstanceContext)
+	compileFiles := func() (map[string]string, error) {
+		return fm.compileFiles(instanceSpec, instanceFileMap, assetContext)
+	}
+	if fm.assetCache != nil {
+		fileMap, err = fm.assetCache.compile(assetCacheKey(fm.namespace, fm.jobSpec, instanceSpec.ScheduledAt),
+			assetInputHash(fm.namespace, fm.jobSpec, instanceSpec), compileFiles)
+	} else {
+		fileMap, err = compileFiles()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return envMap, fileMap, nil
+}
+
+// compileFiles compiles the assets of the job with the files of the instance
+func (fm *ContextManager) compileFiles(instanceSpec models.InstanceSpec, instanceFileMap map[string]string,
+	projectInstanceContext map[string]interface{}) (map[string]string, error) {
 	// check if task needs to override the compilation behaviour
 	compiledAssetResponse, err := fm.jobSpec.Task.Unit.CLIMod.CompileAssets(context.Background(), models.CompileAssetsRequest{
 		Window:           fm.jobSpec.Task.Window,
@@ -80,15 +104,12 @@ func (fm *ContextManager) Generate(
 		InstanceData:     instanceSpec.Data,
 	})
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// append job spec assets to list of files need to write
-	fileMap = MergeStringMap(instanceFileMap, compiledAssetResponse.Assets.ToJobSpec().ToMap())
-	if fileMap, err = fm.engine.CompileFiles(fileMap, projectInstanceContext); err != nil {
-		return
-	}
-	return envMap, fileMap, nil
+	fileMap := MergeStringMap(instanceFileMap, compiledAssetResponse.Assets.ToJobSpec().ToMap())
+	return fm.engine.CompileFiles(fileMap, projectInstanceContext)
 }
 
 func (fm *ContextManager) projectEnvs() (map[string]interface{}, map[string]interface{}) {
@@ -110,6 +131,19 @@ func (fm *ContextManager) projectEnvs() (map[string]interface{}, map[string]inte
 	return projectPrefixedConfig, projRawConfig
 }
 
+// assetVariables are the variables of the project overridden by configs of
+// the job with the same name
+func (fm *ContextManager) assetVariables() map[string]interface{} {
+	variables := map[string]interface{}{}
+	for key, val := range fm.namespace.ProjectSpec.Variables {
+		variables[key] = val
+	}
+	for _, config := range fm.jobSpec.Task.Config {
+		variables[config.Name] = config.Value
+	}
+	return variables
+}
+
 func (fm *ContextManager) generateEnvs(runName string, runType models.InstanceType,
 	projectInstanceContext map[string]interface{}) (map[string]string, error) {
 	transformationConfigs, hookConfigs, err := fm.getConfigMaps(fm.jobSpec, runName, runType)
@@ -260,8 +294,11 @@ func MergeInterfaceMapToString(mp1, mp2 map[string]interface{}) (mp3 map[string]
 	return mp3
 }
 
-// DumpAssets used for dry run and does not effect actual execution of a job
-func DumpAssets(jobSpec models.JobSpec, scheduledAt time.Time, engine models.TemplateEngine, allowOverride bool) (map[string]string, error) {
+// DumpAssets used for dry run and does not effect actual execution of a job.
+// Variables of the project are available in the templates, configs of the job
+// take precedence over them
+func DumpAssets(jobSpec models.JobSpec, variables models.ProjectVariables, scheduledAt time.Time, engine models.TemplateEngine,
+	allowOverride bool) (map[string]string, error) {
 	var jobDestination string
 	if jobSpec.Task.Unit.DependencyMod != nil {
 		jobDestinationResponse, err := jobSpec.Task.Unit.DependencyMod.GenerateDestination(context.TODO(), models.GenerateDestinationRequest{
@@ -303,13 +340,20 @@ func DumpAssets(jobSpec models.JobSpec, scheduledAt time.Time, engine models.Tem
 		assetsToDump = compiledAssetResponse.Assets.ToJobSpec().ToMap()
 	}
 
+	templateContext := map[string]interface{}{}
+	for key, value := range variables {
+		templateContext[key] = value
+	}
+	for _, config := range jobSpec.Task.Config {
+		templateContext[config.Name] = config.Value
+	}
+	templateContext[ConfigKeyDstart] = jobSpec.Task.Window.GetStart(scheduledAt).Format(models.InstanceScheduledAtTimeLayout)
+	templateContext[ConfigKeyDend] = jobSpec.Task.Window.GetEnd(scheduledAt).Format(models.InstanceScheduledAtTimeLayout)
+	templateContext[ConfigKeyExecutionTime] = scheduledAt.Format(models.InstanceScheduledAtTimeLayout)
+	templateContext[ConfigKeyDestination] = jobDestination
+
 	// compile again if needed
-	templates, err := engine.CompileFiles(assetsToDump, map[string]interface{}{
-		ConfigKeyDstart:        jobSpec.Task.Window.GetStart(scheduledAt).Format(models.InstanceScheduledAtTimeLayout),
-		ConfigKeyDend:          jobSpec.Task.Window.GetEnd(scheduledAt).Format(models.InstanceScheduledAtTimeLayout),
-		ConfigKeyExecutionTime: scheduledAt.Format(models.InstanceScheduledAtTimeLayout),
-		ConfigKeyDestination:   jobDestination,
-	})
+	templates, err := engine.CompileFiles(assetsToDump, templateContext)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to compile templates")
 	}
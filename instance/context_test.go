@@ -11,6 +11,7 @@ import (
 	"github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
 	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
 )
 
 func TestContextManager(t *testing.T) {
@@ -435,5 +436,97 @@ func TestContextManager(t *testing.T) {
 				fileMap["query.sql"],
 			)
 		})
+		t.Run("should render variables of the project in assets like when jobs are compiled", func(t *testing.T) {
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "humara-projectSpec",
+				Variables: models.ProjectVariables{
+					"GCP_PROJECT": "data-project",
+					"DATASET":     "warehouse",
+				},
+			}
+			namespaceSpec := models.NamespaceSpec{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "namespace-1",
+				Config:      map[string]string{},
+				ProjectSpec: projectSpec,
+			}
+
+			cliMod := new(mock.CLIMod)
+			jobSpec := models.JobSpec{
+				Name: "foo",
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{Base: new(mock.BasePlugin), CLIMod: cliMod},
+					Window: models.JobSpecTaskWindow{
+						Size:       time.Hour,
+						TruncateTo: "d",
+					},
+					Config: models.JobSpecConfigs{
+						{Name: "DATASET", Value: "sales"},
+					},
+				},
+				Dependencies: map[string]models.JobSpecDependency{},
+			}
+			query := "select * from `{{.GCP_PROJECT}}.{{.DATASET}}.orders` WHERE event_timestamp < '{{.DEND}}'"
+
+			scheduledAt := time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC)
+			instanceSpec := models.InstanceSpec{
+				Job:         jobSpec,
+				ScheduledAt: scheduledAt,
+				Data: []models.InstanceSpecData{
+					{
+						Name:  instance.ConfigKeyDend,
+						Value: jobSpec.Task.Window.GetEnd(scheduledAt).Format(models.InstanceScheduledAtTimeLayout),
+						Type:  models.InstanceDataTypeEnv,
+					},
+				},
+			}
+			cliMod.On("CompileAssets", mock2.Anything, mock2.Anything).Return(&models.CompileAssetsResponse{
+				Assets: models.PluginAssets{{Name: "query.sql", Value: query}},
+			}, nil)
+			defer cliMod.AssertExpectations(t)
+
+			envMap, fileMap, err := instance.NewContextManager(namespaceSpec, jobSpec, instance.NewGoEngine()).Generate(instanceSpec, models.InstanceTypeTask, "bq")
+			assert.Nil(t, err)
+
+			// configs of the job take precedence over variables of the project
+			assert.Equal(t, "select * from `data-project.sales.orders` WHERE event_timestamp < '2020-11-11T00:00:00Z'",
+				fileMap["query.sql"])
+			assert.NotContains(t, envMap, "GCP_PROJECT")
+		})
 	})
 }
+
+func TestDumpAssets(t *testing.T) {
+	execUnit := new(mock.BasePlugin)
+	jobSpec := models.JobSpec{
+		Name: "foo",
+		Task: models.JobSpecTask{
+			Unit: &models.Plugin{Base: execUnit},
+			Window: models.JobSpecTaskWindow{
+				Size:       time.Hour * 24,
+				TruncateTo: "d",
+			},
+			Config: models.JobSpecConfigs{
+				{Name: "DATASET", Value: "playground"},
+			},
+		},
+		Assets: *models.JobAssets{}.New(
+			[]models.JobSpecAsset{
+				{
+					Name:  "query.sql",
+					Value: "select * from `{{.GCP_PROJECT}}.{{.DATASET}}.orders` where ts >= '{{.DSTART}}'",
+				},
+			},
+		),
+	}
+	variables := models.ProjectVariables{
+		"GCP_PROJECT": "data-project",
+		"DATASET":     "warehouse",
+	}
+	scheduledAt := time.Date(2021, 3, 2, 2, 0, 0, 0, time.UTC)
+
+	assets, err := instance.DumpAssets(jobSpec, variables, scheduledAt, instance.NewGoEngine(), false)
+	assert.Nil(t, err)
+	assert.Equal(t, "select * from `data-project.playground.orders` where ts >= '2021-03-01T00:00:00Z'", assets["query.sql"])
+}
@@ -2,6 +2,9 @@ package instance
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
 	"strings"
 	"text/template"
 	"time"
@@ -11,6 +14,19 @@ import (
 	"github.com/Masterminds/sprig/v3"
 )
 
+const (
+	// GoEngineName selects the go engine, the default engine
+	GoEngineName = models.DefaultTemplateEngine
+
+	// MaxAssetIncludeDepth limits nested asset includes to catch circular includes
+	MaxAssetIncludeDepth = 5
+)
+
+var (
+	// assetIncludePattern matches asset includes in raw asset content
+	assetIncludePattern = regexp.MustCompile(`{{-?\s*asset\s+"([^"]+)"\s*-?}}`)
+)
+
 // GoEngine compiles a set of defined macros using the provided context
 type GoEngine struct {
 	baseFns template.FuncMap
@@ -26,7 +42,9 @@ func (e *GoEngine) CompileFiles(files map[string]string, context map[string]inte
 	var err error
 	rendered := map[string]string{}
 	// prepare template list
-	root := template.New("base").Funcs(e.baseFns)
+	root := template.New("base").Funcs(e.baseFns).Funcs(template.FuncMap{
+		"asset": assetIncludeFn(files),
+	})
 	for name, content := range files {
 		root, err = root.New(name).Parse(content)
 		if err != nil {
@@ -62,6 +80,34 @@ func (e *GoEngine) CompileString(input string, context map[string]interface{}) (
 	return strings.TrimSpace(buf.String()), nil
 }
 
+// assetIncludeFn returns the raw content of another asset, assets included
+// in it are expanded as well
+func assetIncludeFn(files map[string]string) func(string) (string, error) {
+	var include func(name string, depth int) (string, error)
+	include = func(name string, depth int) (string, error) {
+		if depth > MaxAssetIncludeDepth {
+			return "", fmt.Errorf("asset %s exceeds include depth of %d, check for circular includes", name, MaxAssetIncludeDepth)
+		}
+		content, ok := files[name]
+		if !ok {
+			return "", fmt.Errorf("asset %s not found", name)
+		}
+		var err error
+		expanded := assetIncludePattern.ReplaceAllStringFunc(content, func(match string) string {
+			if err != nil {
+				return match
+			}
+			var included string
+			included, err = include(assetIncludePattern.FindStringSubmatch(match)[1], depth+1)
+			return included
+		})
+		return expanded, err
+	}
+	return func(name string) (string, error) {
+		return include(name, 1)
+	}
+}
+
 func shouldIgnoreFile(name string) bool {
 	for _, ext := range IgnoreTemplateRenderExtension {
 		if strings.HasSuffix(name, ext) {
@@ -74,6 +120,11 @@ func shouldIgnoreFile(name string) bool {
 func (e *GoEngine) init() {
 	e.baseFns = sprig.TxtFuncMap()
 	e.baseFns["Date"] = goDateFn
+	e.baseFns["daysAgo"] = daysAgoFn
+	e.baseFns["dateAdd"] = dateAddFn
+	e.baseFns["toPartitionSuffix"] = toPartitionSuffixFn
+	e.baseFns["toJSON"] = toJSONFn
+	e.baseFns["fromJSON"] = fromJSONFn
 }
 
 func goDateFn(timeStr string) (string, error) {
@@ -83,3 +134,46 @@ func goDateFn(timeStr string) (string, error) {
 	}
 	return t.Format(models.JobDatetimeLayout), nil
 }
+
+// daysAgoFn returns the date n calendar days before t, days are counted in
+// the location of t so a day across a DST change is not 23 or 25 hours
+func daysAgoFn(n int, t time.Time) string {
+	return t.AddDate(0, 0, -n).Format(models.JobDatetimeLayout)
+}
+
+// dateAddFn adds n calendar days to a date or timestamp, the result keeps
+// the layout of the input
+func dateAddFn(d string, n int) (string, error) {
+	for _, layout := range []string{models.JobDatetimeLayout, models.InstanceScheduledAtTimeLayout} {
+		t, err := time.Parse(layout, d)
+		if err != nil {
+			continue
+		}
+		return t.AddDate(0, 0, n).Format(layout), nil
+	}
+	return "", fmt.Errorf("failed to parse %s as a date or timestamp", d)
+}
+
+// toPartitionSuffixFn returns the partition of a day partitioned BigQuery table
+// holding t, used as table decorator like project.dataset.table${{ toPartitionSuffix $t }}
+func toPartitionSuffixFn(t time.Time) string {
+	return t.Format("20060102")
+}
+
+// toJSONFn encodes v as json, failing the render if v can't be encoded
+func toJSONFn(v interface{}) (string, error) {
+	out, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %v as json: %w", v, err)
+	}
+	return string(out), nil
+}
+
+// fromJSONFn decodes a json document, failing the render if s isn't json
+func fromJSONFn(s string) (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, fmt.Errorf("failed to decode %q as json: %w", s, err)
+	}
+	return v, nil
+}
@@ -2,6 +2,7 @@ package instance_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/odpf/optimus/instance"
 	"github.com/stretchr/testify/assert"
@@ -129,5 +130,158 @@ func TestGoEngine(t *testing.T) {
 				assert.Equal(t, testCase.Expected, compiledExpr)
 			}
 		})
+		t.Run("should include raw content of other assets", func(t *testing.T) {
+			files := map[string]string{
+				"query.sql":       "{{ asset \"common_cte.sql\" }}\nselect * from filtered where ts > '{{.DSTART}}'",
+				"common_cte.sql":  "with filtered as (select * from {{ asset \"table_name.sql\" }})",
+				"table_name.sql":  "project.dataset.events",
+				"query_copy.tmpl": "{{ asset \"table_name.sql\" }}",
+			}
+
+			comp := instance.NewGoEngine()
+			compiled, err := comp.CompileFiles(files, map[string]interface{}{
+				"DSTART": "2021-02-10T10:00:00+00:00",
+			})
+
+			assert.Nil(t, err)
+			assert.Equal(t, "with filtered as (select * from project.dataset.events)\n"+
+				"select * from filtered where ts > '2021-02-10T10:00:00+00:00'", compiled["query.sql"])
+			assert.Equal(t, "with filtered as (select * from project.dataset.events)", compiled["common_cte.sql"])
+			// ignored files are not rendered
+			assert.Equal(t, files["query_copy.tmpl"], compiled["query_copy.tmpl"])
+		})
+		t.Run("should fail for circular asset includes", func(t *testing.T) {
+			files := map[string]string{
+				"a.sql": `{{ asset "b.sql" }}`,
+				"b.sql": `{{ asset "a.sql" }}`,
+			}
+
+			comp := instance.NewGoEngine()
+			_, err := comp.CompileFiles(files, map[string]interface{}{})
+
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), "include depth")
+		})
+		t.Run("should fail if included asset doesn't exist", func(t *testing.T) {
+			comp := instance.NewGoEngine()
+			_, err := comp.CompileFiles(map[string]string{
+				"query.sql": `{{ asset "missing.sql" }}`,
+			}, map[string]interface{}{})
+
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), "asset missing.sql not found")
+		})
+	})
+	t.Run("helper functions", func(t *testing.T) {
+		newYork, err := time.LoadLocation("America/New_York")
+		if err != nil {
+			t.Fatal(err)
+		}
+		testCases := []struct {
+			Name     string
+			Input    string
+			Values   map[string]interface{}
+			Expected string
+		}{
+			{
+				"daysAgo counts calendar days across a DST gap",
+				`{{ daysAgo 1 .T }}`,
+				// 2021-03-14 in New York is only 23 hours long
+				map[string]interface{}{"T": time.Date(2021, 3, 15, 0, 30, 0, 0, newYork)},
+				"2021-03-14",
+			},
+			{
+				"daysAgo counts calendar days across a DST fold",
+				`{{ daysAgo 1 .T }}`,
+				// 2021-11-07 in New York is 25 hours long
+				map[string]interface{}{"T": time.Date(2021, 11, 8, 23, 30, 0, 0, newYork)},
+				"2021-11-07",
+			},
+			{
+				"daysAgo steps back over a leap day",
+				`{{ daysAgo 1 .T }}`,
+				map[string]interface{}{"T": time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)},
+				"2020-02-29",
+			},
+			{
+				"dateAdd adds days to a date in a leap year",
+				`{{ dateAdd "2020-02-28" 1 }}`,
+				nil,
+				"2020-02-29",
+			},
+			{
+				"dateAdd adds days to a date in a non leap year",
+				`{{ dateAdd "2021-02-28" 1 }}`,
+				nil,
+				"2021-03-01",
+			},
+			{
+				"dateAdd keeps the offset of timestamps",
+				`{{ dateAdd "2021-03-13T10:00:00-05:00" 1 }}`,
+				nil,
+				"2021-03-14T10:00:00-05:00",
+			},
+			{
+				"dateAdd subtracts days over a leap day",
+				`{{ dateAdd .DSTART -2 }}`,
+				map[string]interface{}{"DSTART": "2024-03-01T00:00:00Z"},
+				"2024-02-28T00:00:00Z",
+			},
+			{
+				"toPartitionSuffix uses the date in the location of the time",
+				`table${{ toPartitionSuffix .T }}`,
+				// ambiguous hour repeated at the end of DST
+				map[string]interface{}{"T": time.Date(2021, 11, 7, 1, 30, 0, 0, newYork)},
+				"table$20211107",
+			},
+			{
+				"toPartitionSuffix on a leap day",
+				`{{ toPartitionSuffix (toDate "2006-01-02" "2020-02-29") }}`,
+				nil,
+				"20200229",
+			},
+			{
+				"toJSON and fromJSON round trip values",
+				`{{ $v := fromJSON .PAYLOAD }}{{ $v.date }} {{ toJSON $v }}`,
+				map[string]interface{}{"PAYLOAD": `{"date":"2020-02-29","days":[1,2]}`},
+				`2020-02-29 {"date":"2020-02-29","days":[1,2]}`,
+			},
+			{
+				"toJSON encodes times with their offset",
+				`{{ toJSON .T }}`,
+				map[string]interface{}{"T": time.Date(2021, 3, 14, 3, 0, 0, 0, newYork)},
+				`"2021-03-14T03:00:00-04:00"`,
+			},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.Name, func(t *testing.T) {
+				comp := instance.NewGoEngine()
+				compiled, err := comp.CompileString(testCase.Input, testCase.Values)
+
+				assert.Nil(t, err)
+				assert.Equal(t, testCase.Expected, compiled)
+			})
+		}
+		t.Run("dateAdd fails for values not being a date", func(t *testing.T) {
+			comp := instance.NewGoEngine()
+			_, err := comp.CompileString(`{{ dateAdd "yesterday" 1 }}`, nil)
+
+			assert.NotNil(t, err)
+		})
+		t.Run("fromJSON fails for values not being json", func(t *testing.T) {
+			comp := instance.NewGoEngine()
+			_, err := comp.CompileString(`{{ $v := fromJSON .PAYLOAD }}{{ $v.date }}`,
+				map[string]interface{}{"PAYLOAD": `{"date":`})
+
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), "failed to decode")
+		})
+		t.Run("toJSON fails for values that can't be encoded", func(t *testing.T) {
+			comp := instance.NewGoEngine()
+			_, err := comp.CompileString(`{{ toJSON .V }}`, map[string]interface{}{"V": func() {}})
+
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), "failed to encode")
+		})
 	})
 }
@@ -11,6 +11,11 @@ import (
 	"github.com/odpf/optimus/models"
 )
 
+const (
+	// JinjaEngineName selects the jinja engine
+	JinjaEngineName = "jinja"
+)
+
 // JinjaEngine compiles a set of defined macros using the provided context
 type JinjaEngine struct {
 }
@@ -2,10 +2,15 @@ package instance
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/odpf/optimus/core/tracing"
 	"github.com/odpf/optimus/models"
 	"github.com/odpf/optimus/store"
 )
@@ -16,24 +21,57 @@ const (
 	ConfigKeyDend          = "DEND"
 	ConfigKeyExecutionTime = "EXECUTION_TIME"
 	ConfigKeyDestination   = "JOB_DESTINATION"
+
+	// StatusSummaryCacheTTL is the duration instance counts of a project are reused for
+	StatusSummaryCacheTTL = time.Minute
+
+	// DefaultDeadInstanceThreshold is how long an instance has to be running
+	// before it is checked against the scheduler on reconciliation
+	DefaultDeadInstanceThreshold = 2 * time.Hour
+)
+
+var (
+	// ErrReconcileUnsupported is returned on reconciliation when the service
+	// isn't given a scheduler to look runs up with
+	ErrReconcileUnsupported = errors.New("reconciling instances requires a scheduler")
 )
 
 type InstanceSpecRepoFactory interface {
 	New(models.JobSpec) store.InstanceSpecRepository
 }
 
+type cachedStatusSummary struct {
+	counts    map[string]int64
+	expiresAt time.Time
+}
+
 type Service struct {
 	repoFac        InstanceSpecRepoFactory
 	Now            func() time.Time
 	templateEngine models.TemplateEngine
+
+	summaryMu    sync.Mutex
+	summaryCache map[string]cachedStatusSummary
+
+	// Scheduler is asked for the runs of instances when reconciling dead
+	// instances, reconciliation is unsupported if not set
+	Scheduler models.SchedulerUnit
+	// DeadInstanceThreshold overrides DefaultDeadInstanceThreshold if set
+	DeadInstanceThreshold time.Duration
+	// AssetCache reuses assets compiled for an instance across its tasks
+	// and hooks, assets are compiled on every call if not set
+	AssetCache *AssetCache
 }
 
 func (s *Service) Compile(namespace models.NamespaceSpec, jobSpec models.JobSpec, instanceSpec models.InstanceSpec,
 	runType models.InstanceType, runName string) (envMap map[string]string, fileMap map[string]string, err error) {
-	return NewContextManager(
-		namespace, jobSpec, s.templateEngine).Generate(
-		instanceSpec, runType, runName,
-	)
+	engine, err := ProjectTemplateEngine(namespace.ProjectSpec, s.templateEngine)
+	if err != nil {
+		return nil, nil, err
+	}
+	contextManager := NewContextManager(namespace, jobSpec, engine)
+	contextManager.assetCache = s.AssetCache
+	return contextManager.Generate(instanceSpec, runType, runName)
 }
 
 func (s *Service) Register(jobSpec models.JobSpec, scheduledAt time.Time,
@@ -77,6 +115,104 @@ func (s *Service) Register(jobSpec models.JobSpec, scheduledAt time.Time,
 	return instanceSpec, nil
 }
 
+// ListActive returns instances of all projects still running and created within maxAge
+func (s *Service) ListActive(ctx context.Context, maxAge time.Duration) ([]models.InstanceSpec, error) {
+	ctx, span := tracing.Start(ctx, "instance.Service.ListActive")
+	// listing is not scoped to a job, repository is built without one
+	instances, err := s.repoFac.New(models.JobSpec{}).ListActiveInstances(ctx, maxAge)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list active instances")
+	}
+	return instances, nil
+}
+
+// GetStatusSummary returns the number of instances of a project created within
+// window keyed by their state, counts are cached for StatusSummaryCacheTTL
+func (s *Service) GetStatusSummary(ctx context.Context, projectSpec models.ProjectSpec, window time.Duration) (map[string]int64, error) {
+	cacheKey := fmt.Sprintf("%s/%s", projectSpec.Name, window)
+	s.summaryMu.Lock()
+	cached, ok := s.summaryCache[cacheKey]
+	s.summaryMu.Unlock()
+	if ok && s.Now().Before(cached.expiresAt) {
+		return cached.counts, nil
+	}
+
+	ctx, span := tracing.Start(ctx, "instance.Service.GetStatusSummary", attribute.String("project", projectSpec.Name))
+	// counts are not scoped to a job, repository is built without one
+	counts, err := s.repoFac.New(models.JobSpec{}).AggregateByStatus(ctx, projectSpec, window)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to count instances of project %s", projectSpec.Name)
+	}
+
+	s.summaryMu.Lock()
+	s.summaryCache[cacheKey] = cachedStatusSummary{
+		counts:    counts,
+		expiresAt: s.Now().Add(StatusSummaryCacheTTL),
+	}
+	s.summaryMu.Unlock()
+	return counts, nil
+}
+
+// ReconcileDeadInstances fails the instances of a project running for longer
+// than the dead instance threshold whose run is no longer active in the
+// scheduler, left behind when the scheduler crashed mid run. Instances are
+// reconciled independently, errors are returned along with the number of
+// instances failed
+func (s *Service) ReconcileDeadInstances(ctx context.Context, projectSpec models.ProjectSpec) (int, error) {
+	if s.Scheduler == nil {
+		return 0, ErrReconcileUnsupported
+	}
+	threshold := s.DeadInstanceThreshold
+	if threshold <= 0 {
+		threshold = DefaultDeadInstanceThreshold
+	}
+
+	// listing is not scoped to a job, repository is built without one
+	repo := s.repoFac.New(models.JobSpec{})
+	instances, err := repo.ListStaleInstances(ctx, projectSpec, threshold)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to list running instances of project %s", projectSpec.Name)
+	}
+
+	var reconciled int
+	var errs error
+	for _, instanceSpec := range instances {
+		runs, err := s.Scheduler.GetDagRunStatus(ctx, projectSpec, instanceSpec.Job.Name,
+			instanceSpec.ScheduledAt, instanceSpec.ScheduledAt, 1)
+		if err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "failed to get run of job %s at %s",
+				instanceSpec.Job.Name, instanceSpec.ScheduledAt))
+			continue
+		}
+		if hasActiveRun(runs, instanceSpec.ScheduledAt) {
+			continue
+		}
+		if err := repo.UpdateState(ctx, instanceSpec.ID, models.InstanceStateFailed, models.InstanceStateReasonOrphaned); err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "failed to fail instance of job %s at %s",
+				instanceSpec.Job.Name, instanceSpec.ScheduledAt))
+			continue
+		}
+		reconciled++
+	}
+	return reconciled, errs
+}
+
+// hasActiveRun reports if runs has a run scheduled at scheduledAt that
+// hasn't finished yet
+func hasActiveRun(runs []models.JobStatus, scheduledAt time.Time) bool {
+	for _, run := range runs {
+		if !run.ScheduledAt.Equal(scheduledAt) {
+			continue
+		}
+		if run.State != models.JobStatusStateSuccess && run.State != models.JobStatusStateFailed {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Service) PrepInstance(jobSpec models.JobSpec, scheduledAt time.Time) (models.InstanceSpec, error) {
 	var jobDestination string
 	if jobSpec.Task.Unit.DependencyMod != nil {
@@ -126,5 +262,6 @@ func NewService(repoFac InstanceSpecRepoFactory, timeFunc func() time.Time, te m
 		repoFac:        repoFac,
 		Now:            timeFunc,
 		templateEngine: te,
+		summaryCache:   map[string]cachedStatusSummary{},
 	}
 }
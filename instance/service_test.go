@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	mock2 "github.com/stretchr/testify/mock"
 
 	"github.com/odpf/optimus/store"
@@ -251,6 +252,180 @@ func TestService(t *testing.T) {
 		})
 	})
 
+	t.Run("ListActive", func(t *testing.T) {
+		t.Run("should return running instances of all projects", func(t *testing.T) {
+			ctx := context.Background()
+			activeInstances := []models.InstanceSpec{
+				{
+					Job:         models.JobSpec{Name: "foo"},
+					Project:     models.ProjectSpec{Name: "proj"},
+					ScheduledAt: time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC),
+					State:       models.InstanceStateRunning,
+				},
+			}
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("ListActiveInstances", mock2.Anything, time.Hour).Return(activeInstances, nil)
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", models.JobSpec{}).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			instanceService := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil)
+			returnedInstances, err := instanceService.ListActive(ctx, time.Hour)
+			assert.Nil(t, err)
+			assert.Equal(t, activeInstances, returnedInstances)
+		})
+		t.Run("should return error if listing fails", func(t *testing.T) {
+			ctx := context.Background()
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("ListActiveInstances", mock2.Anything, time.Hour).Return(nil, errors.New("a random error"))
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", models.JobSpec{}).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			instanceService := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil)
+			_, err := instanceService.ListActive(ctx, time.Hour)
+			assert.Equal(t, "failed to list active instances: a random error", err.Error())
+		})
+	})
+
+	t.Run("GetStatusSummary", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{Name: "proj"}
+		t.Run("should return instance counts of the project and cache them", func(t *testing.T) {
+			ctx := context.Background()
+			counts := map[string]int64{
+				models.InstanceStateRunning: 2,
+				models.InstanceStateFailed:  1,
+			}
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("AggregateByStatus", mock2.Anything, projectSpec, time.Hour).Return(counts, nil).Once()
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", models.JobSpec{}).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			now := mockedTimeNow
+			instanceService := instance.NewService(jobRunSpecRep, func() time.Time { return now }, nil)
+			returnedCounts, err := instanceService.GetStatusSummary(ctx, projectSpec, time.Hour)
+			assert.Nil(t, err)
+			assert.Equal(t, counts, returnedCounts)
+
+			// served from cache, the repository is called only once
+			now = now.Add(30 * time.Second)
+			returnedCounts, err = instanceService.GetStatusSummary(ctx, projectSpec, time.Hour)
+			assert.Nil(t, err)
+			assert.Equal(t, counts, returnedCounts)
+
+			// cache expired
+			refreshedCounts := map[string]int64{models.InstanceStateSuccess: 3}
+			instanceSpecRepo.On("AggregateByStatus", mock2.Anything, projectSpec, time.Hour).Return(refreshedCounts, nil).Once()
+			now = now.Add(instance.StatusSummaryCacheTTL)
+			returnedCounts, err = instanceService.GetStatusSummary(ctx, projectSpec, time.Hour)
+			assert.Nil(t, err)
+			assert.Equal(t, refreshedCounts, returnedCounts)
+		})
+		t.Run("should return error if counting fails", func(t *testing.T) {
+			ctx := context.Background()
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("AggregateByStatus", mock2.Anything, projectSpec, time.Hour).Return(nil, errors.New("a random error"))
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", models.JobSpec{}).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			instanceService := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil)
+			_, err := instanceService.GetStatusSummary(ctx, projectSpec, time.Hour)
+			assert.Equal(t, "failed to count instances of project proj: a random error", err.Error())
+		})
+	})
+
+	t.Run("ReconcileDeadInstances", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{Name: "proj"}
+		orphanedInstance := models.InstanceSpec{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Job:         models.JobSpec{Name: "orphaned-job"},
+			ScheduledAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			State:       models.InstanceStateRunning,
+		}
+		runningInstance := models.InstanceSpec{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Job:         models.JobSpec{Name: "running-job"},
+			ScheduledAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			State:       models.InstanceStateRunning,
+		}
+		finishedInstance := models.InstanceSpec{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Job:         models.JobSpec{Name: "finished-job"},
+			ScheduledAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			State:       models.InstanceStateRunning,
+		}
+
+		t.Run("should fail running instances without an active run in the scheduler", func(t *testing.T) {
+			ctx := context.Background()
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("ListStaleInstances", ctx, projectSpec, instance.DefaultDeadInstanceThreshold).
+				Return([]models.InstanceSpec{orphanedInstance, runningInstance, finishedInstance}, nil)
+			instanceSpecRepo.On("UpdateState", ctx, orphanedInstance.ID, models.InstanceStateFailed, models.InstanceStateReasonOrphaned).Return(nil)
+			instanceSpecRepo.On("UpdateState", ctx, finishedInstance.ID, models.InstanceStateFailed, models.InstanceStateReasonOrphaned).Return(nil)
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", models.JobSpec{}).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			scheduler := new(mock.Scheduler)
+			scheduler.On("GetDagRunStatus", ctx, projectSpec, "orphaned-job", orphanedInstance.ScheduledAt, orphanedInstance.ScheduledAt, 1).
+				Return([]models.JobStatus{}, nil)
+			scheduler.On("GetDagRunStatus", ctx, projectSpec, "running-job", runningInstance.ScheduledAt, runningInstance.ScheduledAt, 1).
+				Return([]models.JobStatus{{ScheduledAt: runningInstance.ScheduledAt, State: models.JobStatusStateRunning}}, nil)
+			scheduler.On("GetDagRunStatus", ctx, projectSpec, "finished-job", finishedInstance.ScheduledAt, finishedInstance.ScheduledAt, 1).
+				Return([]models.JobStatus{{ScheduledAt: finishedInstance.ScheduledAt, State: models.JobStatusStateSuccess}}, nil)
+			defer scheduler.AssertExpectations(t)
+
+			instanceService := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil)
+			instanceService.Scheduler = scheduler
+			reconciled, err := instanceService.ReconcileDeadInstances(ctx, projectSpec)
+			assert.Nil(t, err)
+			assert.Equal(t, 2, reconciled)
+		})
+		t.Run("should keep reconciling other instances when the scheduler fails for one", func(t *testing.T) {
+			ctx := context.Background()
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("ListStaleInstances", ctx, projectSpec, time.Hour).
+				Return([]models.InstanceSpec{runningInstance, orphanedInstance}, nil)
+			instanceSpecRepo.On("UpdateState", ctx, orphanedInstance.ID, models.InstanceStateFailed, models.InstanceStateReasonOrphaned).Return(nil)
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", models.JobSpec{}).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			scheduler := new(mock.Scheduler)
+			scheduler.On("GetDagRunStatus", ctx, projectSpec, "running-job", runningInstance.ScheduledAt, runningInstance.ScheduledAt, 1).
+				Return([]models.JobStatus{}, errors.New("scheduler unavailable"))
+			scheduler.On("GetDagRunStatus", ctx, projectSpec, "orphaned-job", orphanedInstance.ScheduledAt, orphanedInstance.ScheduledAt, 1).
+				Return([]models.JobStatus{}, nil)
+			defer scheduler.AssertExpectations(t)
+
+			instanceService := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil)
+			instanceService.Scheduler = scheduler
+			instanceService.DeadInstanceThreshold = time.Hour
+			reconciled, err := instanceService.ReconcileDeadInstances(ctx, projectSpec)
+			assert.Contains(t, err.Error(), "scheduler unavailable")
+			assert.Equal(t, 1, reconciled)
+		})
+		t.Run("should fail without a scheduler", func(t *testing.T) {
+			instanceService := instance.NewService(nil, mockedTimeFunc, nil)
+			_, err := instanceService.ReconcileDeadInstances(context.Background(), projectSpec)
+			assert.Equal(t, instance.ErrReconcileUnsupported, err)
+		})
+	})
+
 	t.Run("PrepInstance", func(t *testing.T) {
 		t.Run("while preparing instance execution time should be correct", func(t *testing.T) {
 			scheduledAt := time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC)
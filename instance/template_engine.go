@@ -0,0 +1,29 @@
+package instance
+
+import (
+	"github.com/odpf/optimus/models"
+)
+
+// TemplateEngineByName returns the builtin go and jinja engines or an engine
+// registered in models.TemplateEngineRegistry, the default engine if the name
+// is empty
+func TemplateEngineByName(name string) (models.TemplateEngine, error) {
+	switch name {
+	case "", GoEngineName:
+		return NewGoEngine(), nil
+	case JinjaEngineName:
+		return NewJinjaEngine(), nil
+	}
+	return models.TemplateEngineRegistry.GetByName(name)
+}
+
+// ProjectTemplateEngine returns the engine assets of jobs of the project are
+// rendered with, the one named in the TEMPLATE_ENGINE config of the project
+// or defaultEngine if the project doesn't set one
+func ProjectTemplateEngine(projectSpec models.ProjectSpec, defaultEngine models.TemplateEngine) (models.TemplateEngine, error) {
+	name, ok := projectSpec.Config[models.ProjectTemplateEngine]
+	if !ok || name == "" {
+		return defaultEngine, nil
+	}
+	return TemplateEngineByName(name)
+}
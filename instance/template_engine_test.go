@@ -0,0 +1,62 @@
+package instance_test
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/instance"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateEngine(t *testing.T) {
+	t.Run("TemplateEngineByName", func(t *testing.T) {
+		t.Run("should return the builtin engines by name", func(t *testing.T) {
+			engine, err := instance.TemplateEngineByName(models.DefaultTemplateEngine)
+			assert.Nil(t, err)
+			assert.IsType(t, &instance.GoEngine{}, engine)
+
+			engine, err = instance.TemplateEngineByName("")
+			assert.Nil(t, err)
+			assert.IsType(t, &instance.GoEngine{}, engine)
+
+			engine, err = instance.TemplateEngineByName(instance.JinjaEngineName)
+			assert.Nil(t, err)
+			assert.IsType(t, &instance.JinjaEngine{}, engine)
+		})
+		t.Run("should return engines registered by name", func(t *testing.T) {
+			registered := instance.NewJinjaEngine()
+			assert.Nil(t, models.TemplateEngineRegistry.Add("jinja-registered", registered))
+
+			engine, err := instance.TemplateEngineByName("jinja-registered")
+			assert.Nil(t, err)
+			assert.Same(t, registered, engine)
+		})
+		t.Run("should return error for unknown engines", func(t *testing.T) {
+			_, err := instance.TemplateEngineByName("mustache")
+			assert.ErrorIs(t, err, models.ErrUnsupportedTemplateEngine)
+		})
+	})
+	t.Run("ProjectTemplateEngine", func(t *testing.T) {
+		defaultEngine := instance.NewGoEngine()
+		t.Run("should return the default engine if the project doesn't set one", func(t *testing.T) {
+			engine, err := instance.ProjectTemplateEngine(models.ProjectSpec{Name: "a-data-project"}, defaultEngine)
+			assert.Nil(t, err)
+			assert.Same(t, defaultEngine, engine)
+		})
+		t.Run("should return the engine set in the project config", func(t *testing.T) {
+			engine, err := instance.ProjectTemplateEngine(models.ProjectSpec{
+				Name:   "a-data-project",
+				Config: map[string]string{models.ProjectTemplateEngine: instance.JinjaEngineName},
+			}, defaultEngine)
+			assert.Nil(t, err)
+			assert.IsType(t, &instance.JinjaEngine{}, engine)
+		})
+		t.Run("should return error if the project sets an unknown engine", func(t *testing.T) {
+			_, err := instance.ProjectTemplateEngine(models.ProjectSpec{
+				Name:   "a-data-project",
+				Config: map[string]string{models.ProjectTemplateEngine: "mustache"},
+			}, defaultEngine)
+			assert.ErrorIs(t, err, models.ErrUnsupportedTemplateEngine)
+		})
+	})
+}
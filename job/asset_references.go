@@ -0,0 +1,107 @@
+package job
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	// AssetReferenceCheckTimeout bounds the time taken to check a table
+	// referenced by an asset exists
+	AssetReferenceCheckTimeout = 2 * time.Second
+
+	// TableExistenceCacheTTL is the duration the existence of a table is
+	// reused for
+	TableExistenceCacheTTL = 5 * time.Minute
+)
+
+var (
+	// tables are read after FROM or JOIN as project.dataset.table, quoted in
+	// backticks or not, project:dataset.table is accepted as well
+	sqlTableReferencePattern = regexp.MustCompile("(?i)\\b(?:FROM|JOIN)\\s+`?([\\w-]+[.:][\\w-]+\\.[\\w-]+\\*?)`?")
+	sqlLineCommentPattern    = regexp.MustCompile(`--[^\n]*`)
+	sqlBlockCommentPattern   = regexp.MustCompile(`(?s)/\*.*?\*/`)
+)
+
+type cachedTableExistence struct {
+	exists    bool
+	expiresAt time.Time
+}
+
+// ValidateAssetReferences warns of bigquery tables read by sql assets of the
+// job that don't exist. It is opt-in per project through the
+// VALIDATE_BQ_REFERENCES config, tables that can't be checked are skipped
+func (srv *Service) ValidateAssetReferences(ctx context.Context, projectSpec models.ProjectSpec, jobSpec models.JobSpec) []models.AssetWarning {
+	if srv.SchemaInspector == nil || projectSpec.Config[models.ProjectValidateBQReferences] != "true" {
+		return nil
+	}
+
+	var warnings []models.AssetWarning
+	for _, asset := range jobSpec.Assets.GetAll() {
+		if !strings.HasSuffix(asset.Name, ".sql") {
+			continue
+		}
+		for _, table := range extractTableReferences(asset.Value) {
+			exists, err := srv.tableExists(ctx, projectSpec, table)
+			if err != nil || exists {
+				continue
+			}
+			warnings = append(warnings, models.AssetWarning{
+				Asset:  asset.Name,
+				Table:  table,
+				Reason: "table doesn't exist",
+			})
+		}
+	}
+	return warnings
+}
+
+// tableExists checks the table through the schema inspector, results are
+// cached per project for TableExistenceCacheTTL
+func (srv *Service) tableExists(ctx context.Context, projectSpec models.ProjectSpec, table string) (bool, error) {
+	cacheKey := projectSpec.Name + "/" + table
+	srv.tableExistenceMu.Lock()
+	cached, ok := srv.tableExistenceCache[cacheKey]
+	srv.tableExistenceMu.Unlock()
+	if ok && srv.Now().Before(cached.expiresAt) {
+		return cached.exists, nil
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, AssetReferenceCheckTimeout)
+	defer cancel()
+	exists, err := srv.SchemaInspector.TableExists(checkCtx, projectSpec, table)
+	if err != nil {
+		return false, err
+	}
+
+	srv.tableExistenceMu.Lock()
+	srv.tableExistenceCache[cacheKey] = cachedTableExistence{
+		exists:    exists,
+		expiresAt: srv.Now().Add(TableExistenceCacheTTL),
+	}
+	srv.tableExistenceMu.Unlock()
+	return exists, nil
+}
+
+// extractTableReferences lists the tables read by a query in the order they
+// are first read, wildcard tables and commented out queries are skipped
+func extractTableReferences(query string) []string {
+	query = sqlBlockCommentPattern.ReplaceAllString(query, " ")
+	query = sqlLineCommentPattern.ReplaceAllString(query, "")
+
+	var tables []string
+	seen := map[string]bool{}
+	for _, match := range sqlTableReferencePattern.FindAllStringSubmatch(query, -1) {
+		table := match[1]
+		if strings.HasSuffix(table, "*") || seen[table] {
+			continue
+		}
+		seen[table] = true
+		tables = append(tables, table)
+	}
+	return tables
+}
@@ -0,0 +1,89 @@
+package job_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestValidateAssetReferences(t *testing.T) {
+	ctx := context.Background()
+	projSpec := models.ProjectSpec{
+		Name:   "proj",
+		Config: map[string]string{models.ProjectValidateBQReferences: "true"},
+	}
+	jobSpec := models.JobSpec{
+		Name: "daily-orders",
+		Assets: *models.JobAssets{}.New([]models.JobSpecAsset{
+			{Name: job.QueryAssetName, Value: "-- reads from `proj.dataset.legacy`\n" +
+				"SELECT * FROM `proj.dataset.orders` o\n" +
+				"JOIN proj.dataset.customers c ON o.customer_id = c.id\n" +
+				"LEFT JOIN `proj.dataset.events_*` e ON e.order_id = o.id\n" +
+				"WHERE o.id IN (SELECT id FROM `proj.dataset.orders`)"},
+			{Name: "README.md", Value: "data is read from proj.dataset.readme"},
+		}),
+	}
+
+	t.Run("should warn of tables read by sql assets that don't exist", func(t *testing.T) {
+		inspector := new(mock.DatastoreSchemaInspector)
+		defer inspector.AssertExpectations(t)
+		// each check is bound by a timeout
+		withTimeout := mock2.MatchedBy(func(ctx context.Context) bool {
+			deadline, ok := ctx.Deadline()
+			return ok && time.Until(deadline) <= job.AssetReferenceCheckTimeout
+		})
+		inspector.On("TableExists", withTimeout, projSpec, "proj.dataset.orders").Return(true, nil).Once()
+		inspector.On("TableExists", withTimeout, projSpec, "proj.dataset.customers").Return(false, nil).Once()
+
+		svc := job.NewService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		svc.SchemaInspector = inspector
+		warnings := svc.ValidateAssetReferences(ctx, projSpec, jobSpec)
+		assert.Equal(t, []models.AssetWarning{
+			{Asset: job.QueryAssetName, Table: "proj.dataset.customers", Reason: "table doesn't exist"},
+		}, warnings)
+
+		// existence of the tables is cached
+		assert.Equal(t, warnings, svc.ValidateAssetReferences(ctx, projSpec, jobSpec))
+	})
+	t.Run("should check tables again once the cache expires", func(t *testing.T) {
+		inspector := new(mock.DatastoreSchemaInspector)
+		defer inspector.AssertExpectations(t)
+		inspector.On("TableExists", mock2.Anything, projSpec, "proj.dataset.orders").Return(true, nil).Twice()
+		inspector.On("TableExists", mock2.Anything, projSpec, "proj.dataset.customers").Return(false, nil).Once()
+		inspector.On("TableExists", mock2.Anything, projSpec, "proj.dataset.customers").Return(true, nil).Once()
+
+		now := time.Date(2021, 5, 10, 2, 0, 0, 0, time.UTC)
+		svc := job.NewService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		svc.SchemaInspector = inspector
+		svc.Now = func() time.Time { return now }
+		assert.Equal(t, 1, len(svc.ValidateAssetReferences(ctx, projSpec, jobSpec)))
+
+		now = now.Add(job.TableExistenceCacheTTL)
+		assert.Empty(t, svc.ValidateAssetReferences(ctx, projSpec, jobSpec))
+	})
+	t.Run("should skip tables that can't be checked", func(t *testing.T) {
+		inspector := new(mock.DatastoreSchemaInspector)
+		defer inspector.AssertExpectations(t)
+		inspector.On("TableExists", mock2.Anything, projSpec, "proj.dataset.orders").Return(false, context.DeadlineExceeded)
+		inspector.On("TableExists", mock2.Anything, projSpec, "proj.dataset.customers").Return(false, errors.New("permission denied"))
+
+		svc := job.NewService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		svc.SchemaInspector = inspector
+		assert.Empty(t, svc.ValidateAssetReferences(ctx, projSpec, jobSpec))
+	})
+	t.Run("should not validate projects that haven't opted in", func(t *testing.T) {
+		inspector := new(mock.DatastoreSchemaInspector)
+		defer inspector.AssertExpectations(t)
+
+		svc := job.NewService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		svc.SchemaInspector = inspector
+		assert.Empty(t, svc.ValidateAssetReferences(ctx, models.ProjectSpec{Name: "proj"}, jobSpec))
+	})
+}
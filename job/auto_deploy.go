@@ -0,0 +1,259 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// DeploymentStateRepoFactory is used to manage the deployment state of a project
+type DeploymentStateRepoFactory interface {
+	New(proj models.ProjectSpec) store.DeploymentStateRepository
+}
+
+// DeployModified compiles and uploads the job specs of a project modified
+// after since and removes the jobs of specs deleted after since from the
+// scheduler, dependencies are resolved against all job specs of the project.
+// It returns the number of deployed and undeployed jobs.
+func (srv *Service) DeployModified(ctx context.Context, projectSpec models.ProjectSpec, since time.Time,
+	progressObserver progress.Observer) (deployed, undeployed int, err error) {
+	projectJobSpecRepo := srv.projectJobSpecRepoFactory.New(projectSpec)
+	modifiedSpecs, namespaces, err := projectJobSpecRepo.GetModifiedSince(since)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to retrieve jobs modified since %s", since)
+	}
+	deletedNamespaces, err := projectJobSpecRepo.GetDeletedSince(since)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to retrieve jobs deleted since %s", since)
+	}
+	if len(modifiedSpecs) == 0 && len(deletedNamespaces) == 0 {
+		return 0, 0, nil
+	}
+
+	jobRepo, err := srv.jobRepoFactory.New(ctx, projectSpec)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(modifiedSpecs) > 0 {
+		if deployed, err = srv.deploySpecs(ctx, projectSpec, projectJobSpecRepo, jobRepo, namespaces, progressObserver); err != nil {
+			return deployed, 0, err
+		}
+	}
+
+	// specs deleted and created again under the same name were deployed above
+	var deletedNames []string
+	for name := range deletedNamespaces {
+		if _, ok := namespaces[name]; !ok {
+			deletedNames = append(deletedNames, name)
+		}
+	}
+	sort.Strings(deletedNames)
+	for _, name := range deletedNames {
+		if err := jobRepo.Delete(ctx, deletedNamespaces[name], name); err != nil {
+			if errors.Is(err, models.ErrNoSuchJob) {
+				continue
+			}
+			return deployed, undeployed, err
+		}
+		srv.notifyProgress(progressObserver, &EventJobRemoteDelete{name})
+		undeployed++
+	}
+	return deployed, undeployed, nil
+}
+
+// deploySpecs compiles and uploads the job specs of the project in namespaces
+// keyed by job name
+func (srv *Service) deploySpecs(ctx context.Context, projectSpec models.ProjectSpec, projectJobSpecRepo store.ProjectJobSpecRepository,
+	jobRepo store.JobRepository, namespaces map[string]models.NamespaceSpec, progressObserver progress.Observer) (int, error) {
+	jobSpecs, err := srv.GetDependencyResolvedSpecs(projectSpec, projectJobSpecRepo, progressObserver)
+	if err != nil {
+		return 0, err
+	}
+	srv.notifyProgress(progressObserver, &EventJobSpecDependencyResolve{})
+
+	jobSpecs, err = srv.priorityResolver.Resolve(jobSpecs)
+	if err != nil {
+		return 0, err
+	}
+	srv.notifyProgress(progressObserver, &EventJobPriorityWeightAssign{})
+
+	// group modified specs by namespace, compilation needs the resolved specs
+	namespaceJobSpecs := map[string][]models.JobSpec{}
+	for _, jobSpec := range jobSpecs {
+		namespace, ok := namespaces[jobSpec.Name]
+		if !ok {
+			continue
+		}
+		namespaceJobSpecs[namespace.Name] = append(namespaceJobSpecs[namespace.Name], jobSpec)
+	}
+	var namespaceNames []string
+	for name := range namespaceJobSpecs {
+		namespaceNames = append(namespaceNames, name)
+	}
+	sort.Strings(namespaceNames)
+
+	var deployed int
+	for _, name := range namespaceNames {
+		specs := namespaceJobSpecs[name]
+		namespace := namespaces[specs[0].Name]
+		if err = srv.uploadSpecs(ctx, specs, jobRepo, namespace, progressObserver); err != nil {
+			return deployed, err
+		}
+		if err = srv.publishMetadata(namespace, specs, progressObserver); err != nil {
+			return deployed, err
+		}
+		deployed += len(specs)
+	}
+	return deployed, nil
+}
+
+// AutoDeployer periodically deploys the job specs of all projects modified
+// since their last deployment and undeploys the ones deleted since
+type AutoDeployer struct {
+	jobService                 *Service
+	projectRepoFactory         ProjectRepoFactory
+	deploymentStateRepoFactory DeploymentStateRepoFactory
+	interval                   time.Duration
+	progressObserver           progress.Observer
+
+	Now func() time.Time
+}
+
+// Run reconciles the projects every interval until ctx is cancelled
+func (d *AutoDeployer) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.Reconcile(ctx); err != nil {
+				d.notifyProgress(&EventAutoDeployFailed{Err: err})
+			}
+		}
+	}
+}
+
+// Reconcile deploys the job specs of each project modified since it was last
+// deployed, failing projects are retried in the next cycle
+func (d *AutoDeployer) Reconcile(ctx context.Context) error {
+	projectSpecs, err := d.projectRepoFactory.New().GetAll()
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve projects")
+	}
+	for _, projectSpec := range projectSpecs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		d.reconcileProject(ctx, projectSpec)
+	}
+	return nil
+}
+
+func (d *AutoDeployer) reconcileProject(ctx context.Context, projectSpec models.ProjectSpec) {
+	deploymentStateRepo := d.deploymentStateRepoFactory.New(projectSpec)
+	// servers deploy a project one at a time, the project is left to the
+	// server holding the lock
+	unlock, locked, err := deploymentStateRepo.TryLock(ctx)
+	if err != nil {
+		d.notifyProgress(&EventAutoDeployCompleted{Project: projectSpec.Name, Err: err})
+		return
+	}
+	if !locked {
+		return
+	}
+	defer func() {
+		if err := unlock(); err != nil {
+			d.notifyProgress(&EventAutoDeployFailed{Err: errors.Wrapf(err, "failed to unlock deployment of %s", projectSpec.Name)})
+		}
+	}()
+
+	since, err := deploymentStateRepo.GetLastDeployedAt()
+	if err != nil {
+		d.notifyProgress(&EventAutoDeployCompleted{Project: projectSpec.Name, Err: err})
+		return
+	}
+
+	// specs modified while deploying are picked up by the next cycle
+	deployStartedAt := d.Now()
+	d.notifyProgress(&EventAutoDeployStarted{Project: projectSpec.Name, Since: since})
+	deployed, undeployed, err := d.jobService.DeployModified(ctx, projectSpec, since, d.progressObserver)
+	if err == nil {
+		err = deploymentStateRepo.SetLastDeployedAt(deployStartedAt)
+	}
+	d.notifyProgress(&EventAutoDeployCompleted{
+		Project:    projectSpec.Name,
+		Deployed:   deployed,
+		Undeployed: undeployed,
+		Err:        err,
+	})
+}
+
+func (d *AutoDeployer) notifyProgress(e progress.Event) {
+	if d.progressObserver == nil {
+		return
+	}
+	d.progressObserver.Notify(e)
+}
+
+func NewAutoDeployer(jobService *Service, projectRepoFactory ProjectRepoFactory,
+	deploymentStateRepoFactory DeploymentStateRepoFactory, interval time.Duration,
+	progressObserver progress.Observer) *AutoDeployer {
+	return &AutoDeployer{
+		jobService:                 jobService,
+		projectRepoFactory:         projectRepoFactory,
+		deploymentStateRepoFactory: deploymentStateRepoFactory,
+		interval:                   interval,
+		progressObserver:           progressObserver,
+		Now:                        time.Now,
+	}
+}
+
+type (
+	// EventAutoDeployStarted signifies that job specs of a
+	// project modified since the last deployment are being deployed
+	EventAutoDeployStarted struct {
+		Project string
+		Since   time.Time
+	}
+
+	// EventAutoDeployCompleted signifies that the auto deployment
+	// of a project finished
+	EventAutoDeployCompleted struct {
+		Project    string
+		Deployed   int
+		Undeployed int
+		Err        error
+	}
+
+	// EventAutoDeployFailed signifies that an auto deployment
+	// cycle couldn't reconcile the projects
+	EventAutoDeployFailed struct {
+		Err error
+	}
+)
+
+func (e *EventAutoDeployStarted) String() string {
+	if e.Since.IsZero() {
+		return fmt.Sprintf("auto deploying all jobs of %s", e.Project)
+	}
+	return fmt.Sprintf("auto deploying jobs of %s modified since %s", e.Project, e.Since.Format(time.RFC3339))
+}
+
+func (e *EventAutoDeployCompleted) String() string {
+	if e.Err != nil {
+		return fmt.Sprintf("auto deploying jobs of %s failed with error: %s", e.Project, e.Err.Error())
+	}
+	return fmt.Sprintf("auto deployed %d and undeployed %d jobs of %s", e.Deployed, e.Undeployed, e.Project)
+}
+
+func (e *EventAutoDeployFailed) String() string {
+	return fmt.Sprintf("auto deploy failed with error: %s", e.Err.Error())
+}
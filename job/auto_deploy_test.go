@@ -0,0 +1,221 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	testMock "github.com/stretchr/testify/mock"
+)
+
+func TestAutoDeployer(t *testing.T) {
+	ctx := context.Background()
+	dumpAssets := func(jobSpec models.JobSpec, _ models.ProjectSpec, scheduledAt time.Time) (models.JobAssets, error) {
+		return jobSpec.Assets, nil
+	}
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+	}
+	namespace1 := models.NamespaceSpec{Name: "ns1", ProjectSpec: projSpec}
+	namespace2 := models.NamespaceSpec{Name: "ns2", ProjectSpec: projSpec}
+	jobSpecs := []models.JobSpec{
+		{Name: "job-a"},
+		{Name: "job-b"},
+		{Name: "job-c"},
+	}
+	cycleTime := func(cycle int) time.Time {
+		return time.Date(2021, 8, 1, 0, cycle*5, 0, 0, time.UTC)
+	}
+
+	type autoDeployMocks struct {
+		projectJobSpecRepo  *mock.ProjectJobSpecRepository
+		jobRepo             *mock.JobRepository
+		compiler            *mock.Compiler
+		deploymentStateRepo *mock.DeploymentStateRepository
+		observer            *mock.PipelineLogObserver
+	}
+	setup := func() (*job.AutoDeployer, autoDeployMocks) {
+		m := autoDeployMocks{
+			projectJobSpecRepo:  new(mock.ProjectJobSpecRepository),
+			jobRepo:             new(mock.JobRepository),
+			compiler:            new(mock.Compiler),
+			deploymentStateRepo: new(mock.DeploymentStateRepository),
+			observer:            new(mock.PipelineLogObserver),
+		}
+		m.projectJobSpecRepo.On("GetAll").Return(jobSpecs, nil)
+		m.observer.On("Notify", testMock.Anything)
+
+		projectRepo := new(mock.ProjectRepository)
+		projectRepo.On("GetAll").Return([]models.ProjectSpec{projSpec}, nil)
+		projectRepoFac := new(mock.ProjectRepoFactory)
+		projectRepoFac.On("New").Return(projectRepo)
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(m.projectJobSpecRepo)
+		jobRepoFac := new(mock.JobRepoFactory)
+		jobRepoFac.On("New", ctx, projSpec).Return(m.jobRepo, nil)
+		deploymentStateRepoFac := new(mock.DeploymentStateRepoFactory)
+		deploymentStateRepoFac.On("New", projSpec).Return(m.deploymentStateRepo)
+
+		depenResolver := new(mock.DependencyResolver)
+		for _, jobSpec := range jobSpecs {
+			depenResolver.On("Resolve", projSpec, m.projectJobSpecRepo, jobSpec, m.observer).Return(jobSpec, nil)
+		}
+		priorityResolver := new(mock.PriorityResolver)
+		priorityResolver.On("Resolve", testMock.Anything).Return(jobSpecs, nil)
+
+		jobSvc := job.NewService(nil, jobRepoFac, m.compiler, dumpAssets, depenResolver, priorityResolver, nil,
+			projJobSpecRepoFac, nil, nil, nil)
+		deployer := job.NewAutoDeployer(jobSvc, projectRepoFac, deploymentStateRepoFac, time.Minute, m.observer)
+		return deployer, m
+	}
+	// expectLock lets the deployer take the lock of the project, it returns
+	// the number of times the lock was released
+	expectLock := func(m autoDeployMocks) *int {
+		unlocked := 0
+		m.deploymentStateRepo.On("TryLock", ctx).Return(func() error {
+			unlocked++
+			return nil
+		}, true, nil)
+		return &unlocked
+	}
+	expectDeploy := func(m autoDeployMocks, namespace models.NamespaceSpec, jobSpec models.JobSpec) {
+		compiledJob := models.Job{Name: jobSpec.Name, NamespaceID: namespace.Name}
+		m.compiler.On("Compile", namespace, jobSpec).Return(compiledJob, nil).Once()
+		m.jobRepo.On("Save", ctx, compiledJob).Return(nil).Once()
+	}
+	completedEvents := func(m autoDeployMocks) []*job.EventAutoDeployCompleted {
+		var events []*job.EventAutoDeployCompleted
+		for _, call := range m.observer.Calls {
+			if e, ok := call.Arguments.Get(0).(*job.EventAutoDeployCompleted); ok {
+				events = append(events, e)
+			}
+		}
+		return events
+	}
+
+	t.Run("should deploy only job specs modified in each cycle", func(t *testing.T) {
+		deployer, m := setup()
+		defer m.compiler.AssertExpectations(t)
+		defer m.jobRepo.AssertExpectations(t)
+		defer m.deploymentStateRepo.AssertExpectations(t)
+		unlocked := expectLock(m)
+		m.projectJobSpecRepo.On("GetDeletedSince", testMock.Anything).Return(map[string]models.NamespaceSpec{}, nil)
+
+		// first cycle deploys everything modified since the project was never deployed
+		deployer.Now = func() time.Time { return cycleTime(1) }
+		m.deploymentStateRepo.On("GetLastDeployedAt").Return(time.Time{}, nil).Once()
+		m.projectJobSpecRepo.On("GetModifiedSince", time.Time{}).Return(
+			[]models.JobSpec{jobSpecs[0], jobSpecs[2]},
+			map[string]models.NamespaceSpec{"job-a": namespace1, "job-c": namespace2}, nil).Once()
+		expectDeploy(m, namespace1, jobSpecs[0])
+		expectDeploy(m, namespace2, jobSpecs[2])
+		m.deploymentStateRepo.On("SetLastDeployedAt", cycleTime(1)).Return(nil).Once()
+		assert.Nil(t, deployer.Reconcile(ctx))
+
+		// second cycle deploys the spec modified since the first cycle
+		deployer.Now = func() time.Time { return cycleTime(2) }
+		m.deploymentStateRepo.On("GetLastDeployedAt").Return(cycleTime(1), nil).Once()
+		m.projectJobSpecRepo.On("GetModifiedSince", cycleTime(1)).Return(
+			[]models.JobSpec{jobSpecs[1]},
+			map[string]models.NamespaceSpec{"job-b": namespace1}, nil).Once()
+		expectDeploy(m, namespace1, jobSpecs[1])
+		m.deploymentStateRepo.On("SetLastDeployedAt", cycleTime(2)).Return(nil).Once()
+		assert.Nil(t, deployer.Reconcile(ctx))
+
+		// third cycle has nothing to deploy
+		deployer.Now = func() time.Time { return cycleTime(3) }
+		m.deploymentStateRepo.On("GetLastDeployedAt").Return(cycleTime(2), nil).Once()
+		m.projectJobSpecRepo.On("GetModifiedSince", cycleTime(2)).Return(
+			[]models.JobSpec{}, map[string]models.NamespaceSpec{}, nil).Once()
+		m.deploymentStateRepo.On("SetLastDeployedAt", cycleTime(3)).Return(nil).Once()
+		assert.Nil(t, deployer.Reconcile(ctx))
+
+		m.compiler.AssertNumberOfCalls(t, "Compile", 3)
+		m.projectJobSpecRepo.AssertNumberOfCalls(t, "GetAll", 2)
+		var deployed []int
+		for _, e := range completedEvents(m) {
+			assert.Nil(t, e.Err)
+			deployed = append(deployed, e.Deployed)
+		}
+		assert.Equal(t, []int{2, 1, 0}, deployed)
+		assert.Equal(t, 3, *unlocked)
+	})
+	t.Run("should undeploy job specs deleted since the last deployment", func(t *testing.T) {
+		deployer, m := setup()
+		defer m.jobRepo.AssertExpectations(t)
+		defer m.deploymentStateRepo.AssertExpectations(t)
+		expectLock(m)
+
+		deployer.Now = func() time.Time { return cycleTime(2) }
+		m.deploymentStateRepo.On("GetLastDeployedAt").Return(cycleTime(1), nil)
+		m.projectJobSpecRepo.On("GetModifiedSince", cycleTime(1)).Return(
+			[]models.JobSpec{jobSpecs[1]},
+			map[string]models.NamespaceSpec{"job-b": namespace1}, nil)
+		m.projectJobSpecRepo.On("GetDeletedSince", cycleTime(1)).Return(map[string]models.NamespaceSpec{
+			// deleted and created again
+			"job-b": namespace2,
+			"job-d": namespace2,
+			// never deployed
+			"job-e": namespace1,
+		}, nil)
+		expectDeploy(m, namespace1, jobSpecs[1])
+		m.jobRepo.On("Delete", ctx, namespace2, "job-d").Return(nil).Once()
+		m.jobRepo.On("Delete", ctx, namespace1, "job-e").Return(errors.Wrap(models.ErrNoSuchJob, "job-e")).Once()
+		m.deploymentStateRepo.On("SetLastDeployedAt", cycleTime(2)).Return(nil)
+		assert.Nil(t, deployer.Reconcile(ctx))
+
+		m.jobRepo.AssertNotCalled(t, "Delete", ctx, testMock.Anything, "job-b")
+		events := completedEvents(m)
+		assert.Len(t, events, 1)
+		assert.Nil(t, events[0].Err)
+		assert.Equal(t, 1, events[0].Deployed)
+		assert.Equal(t, 1, events[0].Undeployed)
+	})
+	t.Run("should leave projects locked by another server", func(t *testing.T) {
+		deployer, m := setup()
+		defer m.deploymentStateRepo.AssertExpectations(t)
+
+		m.deploymentStateRepo.On("TryLock", ctx).Return(nil, false, nil)
+		assert.Nil(t, deployer.Reconcile(ctx))
+
+		m.deploymentStateRepo.AssertNotCalled(t, "GetLastDeployedAt")
+		assert.Empty(t, completedEvents(m))
+	})
+	t.Run("should not advance last deploy time if deployment fails", func(t *testing.T) {
+		deployer, m := setup()
+		defer m.deploymentStateRepo.AssertExpectations(t)
+		expectLock(m)
+
+		deployer.Now = func() time.Time { return cycleTime(1) }
+		m.deploymentStateRepo.On("GetLastDeployedAt").Return(time.Time{}, nil)
+		m.projectJobSpecRepo.On("GetModifiedSince", time.Time{}).Return(nil, nil, errors.New("connection closed"))
+		assert.Nil(t, deployer.Reconcile(ctx))
+
+		m.deploymentStateRepo.AssertNotCalled(t, "SetLastDeployedAt", testMock.Anything)
+		events := completedEvents(m)
+		assert.Len(t, events, 1)
+		assert.Contains(t, events[0].Err.Error(), "connection closed")
+	})
+	t.Run("should emit started event with the last deploy time", func(t *testing.T) {
+		deployer, m := setup()
+		expectLock(m)
+		m.projectJobSpecRepo.On("GetDeletedSince", cycleTime(1)).Return(map[string]models.NamespaceSpec{}, nil)
+
+		deployer.Now = func() time.Time { return cycleTime(2) }
+		m.deploymentStateRepo.On("GetLastDeployedAt").Return(cycleTime(1), nil)
+		m.projectJobSpecRepo.On("GetModifiedSince", cycleTime(1)).Return(
+			[]models.JobSpec{}, map[string]models.NamespaceSpec{}, nil)
+		m.deploymentStateRepo.On("SetLastDeployedAt", cycleTime(2)).Return(nil)
+		assert.Nil(t, deployer.Reconcile(ctx))
+
+		m.observer.AssertCalled(t, "Notify", &job.EventAutoDeployStarted{
+			Project: projSpec.Name,
+			Since:   cycleTime(1),
+		})
+	})
+}
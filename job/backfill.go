@@ -0,0 +1,150 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultMaxBackfillLookback is the age after which missing runs are no longer backfilled
+	DefaultMaxBackfillLookback = time.Hour * 24 * 30
+
+	BackfillSkipReasonLookback     = "older than max backfill lookback"
+	BackfillSkipReasonNotScheduled = "job not scheduled"
+	BackfillSkipReasonNotDue       = "not due yet"
+	BackfillSkipReasonPaused       = "job paused"
+)
+
+// BackfillMissingRuns compares the runs expected by the job schedule between start and
+// end dates with the runs known to the scheduler and replays the missing ones. Runs
+// older than MaxBackfillLookback and runs outside the schedule start and end date of
+// the job, when it was not scheduled, are skipped along with runs scheduled while the
+// job was paused. Each window of consecutive missing
+// runs is replayed as a single request, replay works on dates so windows sharing a
+// date are merged.
+func (srv *Service) BackfillMissingRuns(ctx context.Context, projectSpec models.ProjectSpec, jobName string,
+	start, end time.Time, dryRun bool) (models.BackfillPlan, error) {
+	jobSpec, _, err := srv.GetByNameForProject(jobName, projectSpec)
+	if err != nil {
+		return models.BackfillPlan{}, err
+	}
+	plan := models.BackfillPlan{
+		Job:    jobSpec,
+		DryRun: dryRun,
+	}
+
+	expectedRuns, err := getRunsBetweenDates(start, end, jobSpec.Schedule)
+	if err != nil {
+		return plan, errors.Wrapf(err, "failed to find runs of %s", jobName)
+	}
+	existingRuns, err := srv.replayManager.GetRunStatus(ctx, projectSpec, start, end.AddDate(0, 0, 1), jobName)
+	if err != nil {
+		return plan, errors.Wrapf(err, "failed to fetch runs of %s from scheduler", jobName)
+	}
+	var pausePeriods []models.JobPausePeriod
+	if srv.PauseRepo != nil {
+		if pausePeriods, err = srv.PauseRepo.GetPausePeriods(ctx, projectSpec.Name, jobName); err != nil {
+			return plan, errors.Wrapf(err, "failed to fetch pause periods of %s", jobName)
+		}
+	}
+	coveredRuns := map[int64]bool{}
+	for _, run := range existingRuns {
+		coveredRuns[run.ScheduledAt.Unix()] = true
+	}
+
+	now := srv.Now()
+	lookbackLimit := now.Add(-srv.MaxBackfillLookback)
+	var windows []backfillWindow
+	var window []time.Time
+	for _, run := range expectedRuns {
+		if coveredRuns[run.Unix()] {
+			windows, window = closeBackfillWindow(windows, window)
+			continue
+		}
+
+		skipReason := ""
+		switch {
+		case run.After(now):
+			skipReason = BackfillSkipReasonNotDue
+		case run.Before(lookbackLimit):
+			skipReason = BackfillSkipReasonLookback
+		case run.Before(jobSpec.Schedule.StartDate),
+			jobSpec.Schedule.EndDate != nil && run.After(*jobSpec.Schedule.EndDate):
+			skipReason = BackfillSkipReasonNotScheduled
+		case pausedAt(pausePeriods, run):
+			skipReason = BackfillSkipReasonPaused
+		}
+		if skipReason != "" {
+			plan.SkippedRuns = append(plan.SkippedRuns, models.BackfillSkippedRun{ScheduledAt: run, Reason: skipReason})
+			windows, window = closeBackfillWindow(windows, window)
+			continue
+		}
+		plan.MissingRuns = append(plan.MissingRuns, run)
+		window = append(window, run)
+	}
+	windows, _ = closeBackfillWindow(windows, window)
+
+	if dryRun || len(windows) == 0 {
+		return plan, nil
+	}
+
+	replayRequest := &models.ReplayWorkerRequest{
+		Job:     jobSpec,
+		Project: projectSpec,
+	}
+	if err := srv.populateRequestWithJobSpecs(replayRequest); err != nil {
+		return plan, err
+	}
+	for _, w := range windows {
+		windowRequest := *replayRequest
+		windowRequest.Start = w.start
+		windowRequest.End = w.end
+		replayID, err := srv.replayManager.Replay(ctx, &windowRequest)
+		if err != nil {
+			return plan, errors.Wrapf(err, "failed to replay %s between %s and %s", jobName,
+				windowRequest.Start.Format(ReplayDateFormat), windowRequest.End.Format(ReplayDateFormat))
+		}
+		plan.ReplayIDs = append(plan.ReplayIDs, replayID)
+	}
+	return plan, nil
+}
+
+// pausedAt tells if the run scheduled at t fell in any of the pause periods
+func pausedAt(periods []models.JobPausePeriod, t time.Time) bool {
+	for _, period := range periods {
+		if period.Covers(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// backfillWindow is the date range of consecutive missing runs
+type backfillWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+// closeBackfillWindow adds the date range of runs to windows, merging it with
+// the previous window if they share a date
+func closeBackfillWindow(windows []backfillWindow, runs []time.Time) ([]backfillWindow, []time.Time) {
+	if len(runs) == 0 {
+		return windows, runs
+	}
+	w := backfillWindow{
+		start: truncateToDate(runs[0]),
+		end:   truncateToDate(runs[len(runs)-1]),
+	}
+	if last := len(windows) - 1; last >= 0 && !w.start.After(windows[last].end) {
+		windows[last].end = w.end
+		return windows, nil
+	}
+	return append(windows, w), nil
+}
+
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
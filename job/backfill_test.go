@@ -0,0 +1,143 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestBackfillMissingRuns(t *testing.T) {
+	ctx := context.Background()
+	dumpAssets := func(jobSpec models.JobSpec, _ models.ProjectSpec, scheduledAt time.Time) (models.JobAssets, error) {
+		return jobSpec.Assets, nil
+	}
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		Name:        "ns",
+		ProjectSpec: projSpec,
+	}
+	day := func(d int) time.Time {
+		return time.Date(2021, 8, d, 0, 0, 0, 0, time.UTC)
+	}
+	jobSpec := models.JobSpec{
+		Name: "daily-job",
+		Schedule: models.JobSpecSchedule{
+			StartDate: day(1).AddDate(0, -1, 0),
+			Interval:  "0 0 * * *",
+		},
+		Dependencies: map[string]models.JobSpecDependency{},
+	}
+	now := day(5).Add(time.Hour * 12)
+	// runs of 2021-08-01 to 2021-08-05 are expected, run of 2021-08-03 exists
+	existingRuns := []models.JobStatus{
+		{ScheduledAt: day(3), State: models.JobStatusStateSuccess},
+	}
+
+	// setup wires a service which finds jobSpec in the project and the existing runs in the scheduler
+	setup := func(t *testing.T, spec models.JobSpec) (*job.Service, *mock.ReplayManager, *mock.ProjectJobSpecRepository) {
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepo.On("GetByName", spec.Name).Return(spec, namespaceSpec, nil)
+		t.Cleanup(func() { projectJobSpecRepo.AssertExpectations(t) })
+
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+		t.Cleanup(func() { projJobSpecRepoFac.AssertExpectations(t) })
+
+		replayManager := new(mock.ReplayManager)
+		replayManager.On("GetRunStatus", ctx, projSpec, day(1), day(6), spec.Name).Return(existingRuns, nil)
+		t.Cleanup(func() { replayManager.AssertExpectations(t) })
+
+		depenResolver := new(mock.DependencyResolver)
+		depenResolver.On("Resolve", projSpec, projectJobSpecRepo, spec, nil).Return(spec, nil)
+
+		jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, replayManager, nil, nil)
+		jobSvc.Now = func() time.Time { return now }
+		jobSvc.MaxBackfillLookback = time.Hour * 24 * 3
+		return jobSvc, replayManager, projectJobSpecRepo
+	}
+
+	t.Run("should return missing runs without replaying them on dry run", func(t *testing.T) {
+		jobSvc, _, _ := setup(t, jobSpec)
+
+		plan, err := jobSvc.BackfillMissingRuns(ctx, projSpec, jobSpec.Name, day(1), day(5), true)
+		assert.Nil(t, err)
+		assert.True(t, plan.DryRun)
+		assert.Equal(t, []time.Time{day(4), day(5)}, plan.MissingRuns)
+		assert.Equal(t, []models.BackfillSkippedRun{
+			{ScheduledAt: day(1), Reason: job.BackfillSkipReasonLookback},
+			{ScheduledAt: day(2), Reason: job.BackfillSkipReasonLookback},
+		}, plan.SkippedRuns)
+		assert.Empty(t, plan.ReplayIDs)
+	})
+	t.Run("should skip runs after the schedule end date of the job", func(t *testing.T) {
+		endDate := day(4)
+		endedSpec := jobSpec
+		endedSpec.Schedule.EndDate = &endDate
+		jobSvc, _, _ := setup(t, endedSpec)
+
+		plan, err := jobSvc.BackfillMissingRuns(ctx, projSpec, jobSpec.Name, day(1), day(5), true)
+		assert.Nil(t, err)
+		assert.Equal(t, []time.Time{day(4)}, plan.MissingRuns)
+		assert.Equal(t, models.BackfillSkippedRun{ScheduledAt: day(5), Reason: job.BackfillSkipReasonNotScheduled}, plan.SkippedRuns[2])
+	})
+	t.Run("should skip runs scheduled while the job was paused", func(t *testing.T) {
+		jobSvc, _, _ := setup(t, jobSpec)
+		resumedAt := day(4).Add(time.Hour)
+		pauseRepo := new(mock.JobPauseRepository)
+		pauseRepo.On("GetPausePeriods", ctx, projSpec.Name, jobSpec.Name).Return([]models.JobPausePeriod{
+			{PausedAt: day(3).Add(time.Hour), ResumedAt: &resumedAt},
+			{PausedAt: day(5)},
+		}, nil)
+		defer pauseRepo.AssertExpectations(t)
+		jobSvc.PauseRepo = pauseRepo
+
+		plan, err := jobSvc.BackfillMissingRuns(ctx, projSpec, jobSpec.Name, day(1), day(5), true)
+		assert.Nil(t, err)
+		assert.Empty(t, plan.MissingRuns)
+		assert.Equal(t, []models.BackfillSkippedRun{
+			{ScheduledAt: day(1), Reason: job.BackfillSkipReasonLookback},
+			{ScheduledAt: day(2), Reason: job.BackfillSkipReasonLookback},
+			{ScheduledAt: day(4), Reason: job.BackfillSkipReasonPaused},
+			{ScheduledAt: day(5), Reason: job.BackfillSkipReasonPaused},
+		}, plan.SkippedRuns)
+	})
+	t.Run("should replay each window of missing runs", func(t *testing.T) {
+		jobSvc, replayManager, projectJobSpecRepo := setup(t, jobSpec)
+		projectJobSpecRepo.On("GetAll").Return([]models.JobSpec{jobSpec}, nil)
+		replayManager.On("Replay", ctx, mock2.MatchedBy(func(req *models.ReplayWorkerRequest) bool {
+			return req.Job.Name == jobSpec.Name && req.Start.Equal(day(4)) && req.End.Equal(day(5)) &&
+				len(req.JobSpecMap) == 1
+		})).Return("replay-id", nil)
+
+		plan, err := jobSvc.BackfillMissingRuns(ctx, projSpec, jobSpec.Name, day(1), day(5), false)
+		assert.Nil(t, err)
+		assert.False(t, plan.DryRun)
+		assert.Equal(t, []string{"replay-id"}, plan.ReplayIDs)
+	})
+	t.Run("should fail if runs can't be fetched from the scheduler", func(t *testing.T) {
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepo.On("GetByName", jobSpec.Name).Return(jobSpec, namespaceSpec, nil)
+		defer projectJobSpecRepo.AssertExpectations(t)
+
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+		defer projJobSpecRepoFac.AssertExpectations(t)
+
+		replayManager := new(mock.ReplayManager)
+		replayManager.On("GetRunStatus", ctx, projSpec, day(1), day(6), jobSpec.Name).Return(nil, errors.New("scheduler unreachable"))
+		defer replayManager.AssertExpectations(t)
+
+		jobSvc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, replayManager, nil, nil)
+		_, err := jobSvc.BackfillMissingRuns(ctx, projSpec, jobSpec.Name, day(1), day(5), true)
+		assert.Contains(t, err.Error(), "scheduler unreachable")
+	})
+}
@@ -0,0 +1,68 @@
+package job
+
+import (
+	"time"
+
+	"github.com/odpf/optimus/core/cron"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+// SuppressedRuns lists which of the count runs of the job following from
+// fall in a blackout window of its project and are skipped by the scheduler
+func SuppressedRuns(projectSpec models.ProjectSpec, jobSpec models.JobSpec, from time.Time, count int) ([]models.SuppressedRun, error) {
+	if count <= 0 {
+		count = DefaultScheduleValidationRuns
+	}
+	if count > MaxScheduleValidationRuns {
+		return nil, errors.Errorf("at most %d runs can be listed", MaxScheduleValidationRuns)
+	}
+	loc, err := jobSpec.Schedule.Location()
+	if err != nil {
+		return nil, err
+	}
+	jobSchedule, err := cron.ParseCronSchedule(jobSpec.Schedule.Interval)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse schedule interval of %s", jobSpec.Name)
+	}
+
+	var windows []models.BlackoutWindow
+	var windowSchedules []*cron.ScheduleSpec
+	for _, window := range projectSpec.BlackoutWindows {
+		if !window.AppliesTo(jobSpec.Name) {
+			continue
+		}
+		windowSchedule, err := cron.ParseCronSchedule(window.Start)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse blackout window start %s", window.Start)
+		}
+		windows = append(windows, window)
+		windowSchedules = append(windowSchedules, windowSchedule)
+	}
+
+	suppressed := []models.SuppressedRun{}
+	if len(windows) == 0 {
+		return suppressed, nil
+	}
+	run := from.In(loc)
+	for i := 0; i < count; i++ {
+		// zero if the schedule doesn't run within the next five years
+		if run = jobSchedule.Next(run); run.IsZero() {
+			break
+		}
+		for j, window := range windows {
+			// the window covers the run if it starts within its duration
+			// before the run
+			start := windowSchedules[j].Next(run.UTC().Add(-window.Duration))
+			if !start.IsZero() && !start.After(run) {
+				suppressed = append(suppressed, models.SuppressedRun{
+					ScheduledAt: run,
+					Window:      window,
+					WindowStart: start,
+				})
+				break
+			}
+		}
+	}
+	return suppressed, nil
+}
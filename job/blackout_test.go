@@ -0,0 +1,64 @@
+package job_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuppressedRuns(t *testing.T) {
+	from := time.Date(2021, 2, 28, 1, 30, 0, 0, time.UTC)
+	maintenance := models.BlackoutWindow{Start: "0 2 * * 0", Duration: time.Hour * 2}
+	projectSpec := models.ProjectSpec{
+		Name:            "foo-project",
+		BlackoutWindows: []models.BlackoutWindow{maintenance},
+	}
+	jobSpec := models.JobSpec{
+		Name:     "foo",
+		Schedule: models.JobSpecSchedule{Interval: "0 * * * *"},
+	}
+
+	t.Run("should list the runs starting within a blackout window", func(t *testing.T) {
+		suppressed, err := job.SuppressedRuns(projectSpec, jobSpec, from, 5)
+
+		assert.Nil(t, err)
+		windowStart := time.Date(2021, 2, 28, 2, 0, 0, 0, time.UTC)
+		assert.Equal(t, []models.SuppressedRun{
+			{ScheduledAt: time.Date(2021, 2, 28, 2, 0, 0, 0, time.UTC), Window: maintenance, WindowStart: windowStart},
+			{ScheduledAt: time.Date(2021, 2, 28, 3, 0, 0, 0, time.UTC), Window: maintenance, WindowStart: windowStart},
+		}, suppressed)
+	})
+	t.Run("should check runs in the timezone of the job against windows in utc", func(t *testing.T) {
+		tempSpec := jobSpec
+		tempSpec.Schedule = models.JobSpecSchedule{Interval: "0 9 * * *", Timezone: "Asia/Jakarta"}
+		suppressed, err := job.SuppressedRuns(projectSpec, tempSpec, time.Date(2021, 2, 27, 10, 30, 0, 0, time.UTC), 2)
+
+		assert.Nil(t, err)
+		jakarta, _ := time.LoadLocation("Asia/Jakarta")
+		assert.Equal(t, []models.SuppressedRun{
+			{
+				ScheduledAt: time.Date(2021, 2, 28, 9, 0, 0, 0, jakarta),
+				Window:      maintenance,
+				WindowStart: time.Date(2021, 2, 28, 2, 0, 0, 0, time.UTC),
+			},
+		}, suppressed)
+	})
+	t.Run("should skip windows of other jobs", func(t *testing.T) {
+		tempProject := projectSpec
+		tempProject.BlackoutWindows = []models.BlackoutWindow{
+			{Start: "0 2 * * 0", Duration: time.Hour * 2, JobPattern: "^bar-"},
+		}
+		suppressed, err := job.SuppressedRuns(tempProject, jobSpec, from, 5)
+
+		assert.Nil(t, err)
+		assert.Empty(t, suppressed)
+	})
+	t.Run("should return error if too many runs are requested", func(t *testing.T) {
+		_, err := job.SuppressedRuns(projectSpec, jobSpec, from, job.MaxScheduleValidationRuns+1)
+
+		assert.EqualError(t, err, "at most 100 runs can be listed")
+	})
+}
@@ -0,0 +1,186 @@
+package job
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrJobAlreadyExists is returned when a job is cloned to the name of a
+	// job registered in the project
+	ErrJobAlreadyExists = errors.New("job already exists")
+
+	// ErrCrossProjectCloneUnsupported is returned when jobs can't be saved
+	// to another project
+	ErrCrossProjectCloneUnsupported = errors.New("cloning jobs across projects is not supported")
+)
+
+// CloneJobSpec copies the spec of a job to a new job in the same namespace.
+// The clone is saved as a draft, it has no version history and isn't
+// deployed to the scheduler until its namespace is synced
+func (srv *Service) CloneJobSpec(projectSpec models.ProjectSpec, sourceJobName, destJobName string) (models.JobSpec, models.NamespaceSpec, error) {
+	projectJobSpecRepo := srv.projectJobSpecRepoFactory.New(projectSpec)
+	sourceSpec, namespace, err := projectJobSpecRepo.GetByName(sourceJobName)
+	if err != nil {
+		return models.JobSpec{}, models.NamespaceSpec{}, errors.Wrapf(err, "failed to find job %s", sourceJobName)
+	}
+	if _, _, err := projectJobSpecRepo.GetByName(destJobName); err == nil {
+		return models.JobSpec{}, models.NamespaceSpec{}, errors.Wrapf(ErrJobAlreadyExists, "failed to clone job %s to %s", sourceJobName, destJobName)
+	} else if !errors.Is(err, store.ErrResourceNotFound) {
+		return models.JobSpec{}, models.NamespaceSpec{}, errors.Wrapf(err, "failed to find job %s", destJobName)
+	}
+
+	clonedSpec := cloneJobSpec(sourceSpec, destJobName)
+	if err := srv.jobSpecRepoFactory.New(namespace).Save(clonedSpec); err != nil {
+		return models.JobSpec{}, models.NamespaceSpec{}, errors.Wrapf(err, "failed to save job %s", destJobName)
+	}
+	return clonedSpec, namespace, nil
+}
+
+// CloneProject copies the job specs of each source namespace to the
+// destination namespace of the same name, replacements are applied in order to
+// the assets and config values of the copies. Either all jobs are created or
+// none, jobs of the destination project must not share names with them
+func (srv *Service) CloneProject(ctx context.Context, sourceNamespaces, destNamespaces []models.NamespaceSpec,
+	replacements []models.StringReplacement) (int, error) {
+	if srv.CrossProjectJobSpecRepo == nil {
+		return 0, ErrCrossProjectCloneUnsupported
+	}
+	destNamespacesByName := map[string]models.NamespaceSpec{}
+	for _, namespace := range destNamespaces {
+		destNamespacesByName[namespace.Name] = namespace
+	}
+	var replacementPairs []string
+	for _, replacement := range replacements {
+		replacementPairs = append(replacementPairs, replacement.Find, replacement.Replace)
+	}
+	replacer := strings.NewReplacer(replacementPairs...)
+
+	var clonedJobs []models.JobSpecWithNamespace
+	for _, sourceNamespace := range sourceNamespaces {
+		jobSpecs, err := srv.jobSpecRepoFactory.New(sourceNamespace).GetAll()
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to retrieve jobs of namespace %s", sourceNamespace.Name)
+		}
+		if len(jobSpecs) == 0 {
+			continue
+		}
+		destNamespace, ok := destNamespacesByName[sourceNamespace.Name]
+		if !ok {
+			return 0, errors.Wrapf(store.ErrResourceNotFound, "namespace %s isn't registered in the destination project", sourceNamespace.Name)
+		}
+		for _, jobSpec := range jobSpecs {
+			clonedJobs = append(clonedJobs, models.JobSpecWithNamespace{
+				Namespace: destNamespace,
+				Spec:      replaceJobSpecValues(cloneJobSpec(jobSpec, jobSpec.Name), replacer),
+			})
+		}
+	}
+	if len(clonedJobs) == 0 {
+		return 0, nil
+	}
+
+	destProject := clonedJobs[0].Namespace.ProjectSpec
+	existingSpecs, err := srv.projectJobSpecRepoFactory.New(destProject).GetAll()
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to retrieve jobs of project %s", destProject.Name)
+	}
+	existingNames := map[string]bool{}
+	for _, existingSpec := range existingSpecs {
+		existingNames[existingSpec.Name] = true
+	}
+	var conflicts []string
+	for _, clonedJob := range clonedJobs {
+		if existingNames[clonedJob.Spec.Name] {
+			conflicts = append(conflicts, clonedJob.Spec.Name)
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return 0, errors.Wrapf(ErrJobAlreadyExists, "jobs %s of project %s", strings.Join(conflicts, ", "), destProject.Name)
+	}
+
+	if err := srv.CrossProjectJobSpecRepo.InsertAll(ctx, clonedJobs); err != nil {
+		return 0, errors.Wrapf(err, "failed to save jobs to project %s", destProject.Name)
+	}
+	return len(clonedJobs), nil
+}
+
+// replaceJobSpecValues applies replacer to the asset and config values of a
+// cloned spec
+func replaceJobSpecValues(spec models.JobSpec, replacer *strings.Replacer) models.JobSpec {
+	assets := spec.Assets.GetAll()
+	for i := range assets {
+		assets[i].Value = replacer.Replace(assets[i].Value)
+	}
+	for i := range spec.Task.Config {
+		spec.Task.Config[i].Value = replacer.Replace(spec.Task.Config[i].Value)
+	}
+	for _, hook := range spec.Hooks {
+		for i := range hook.Config {
+			hook.Config[i].Value = replacer.Replace(hook.Config[i].Value)
+		}
+	}
+	return spec
+}
+
+// cloneJobSpec deep copies spec under a new name, dependencies of the job on
+// itself are renamed to the clone. Resolved dependencies and hook ordering
+// are left for the next sync to resolve
+func cloneJobSpec(spec models.JobSpec, name string) models.JobSpec {
+	clone := spec
+	clone.ID = uuid.Nil
+	clone.Name = name
+
+	if spec.Labels != nil {
+		clone.Labels = map[string]string{}
+		for key, value := range spec.Labels {
+			clone.Labels[key] = value
+		}
+	}
+
+	clone.Behavior.Notify = nil
+	for _, notify := range spec.Behavior.Notify {
+		config := map[string]string{}
+		for key, value := range notify.Config {
+			config[key] = value
+		}
+		clone.Behavior.Notify = append(clone.Behavior.Notify, models.JobSpecNotifier{
+			On:       notify.On,
+			Config:   config,
+			Channels: append([]string(nil), notify.Channels...),
+		})
+	}
+
+	clone.Task.Config = append(models.JobSpecConfigs(nil), spec.Task.Config...)
+
+	clone.Dependencies = map[string]models.JobSpecDependency{}
+	for depName, dep := range spec.Dependencies {
+		if depName == spec.Name {
+			depName = name
+		}
+		clone.Dependencies[depName] = models.JobSpecDependency{
+			Type:        dep.Type,
+			ProjectName: dep.ProjectName,
+		}
+	}
+
+	clone.Assets = *models.JobAssets{}.New(append([]models.JobSpecAsset(nil), spec.Assets.GetAll()...))
+
+	clone.Hooks = nil
+	for _, hook := range spec.Hooks {
+		clone.Hooks = append(clone.Hooks, models.JobSpecHook{
+			Config:   append(models.JobSpecConfigs(nil), hook.Config...),
+			Unit:     hook.Unit,
+			RunAfter: append([]string(nil), hook.RunAfter...),
+			Retry:    hook.Retry,
+		})
+	}
+	return clone
+}
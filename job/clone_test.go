@@ -0,0 +1,198 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestCloneJobSpec(t *testing.T) {
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		Name:        "dev-team-1",
+		ProjectSpec: projSpec,
+	}
+	newSourceSpec := func() models.JobSpec {
+		return models.JobSpec{
+			Name:   "source-job",
+			Labels: map[string]string{"team": "data"},
+			Task: models.JobSpecTask{
+				Config: models.JobSpecConfigs{{Name: "DATASET", Value: "playground"}},
+			},
+			Dependencies: map[string]models.JobSpecDependency{
+				"source-job":   {Type: models.JobSpecDependencyTypeIntra},
+				"upstream-job": {Type: models.JobSpecDependencyTypeIntra},
+			},
+			Assets: *models.JobAssets{}.New([]models.JobSpecAsset{
+				{Name: job.QueryAssetName, Value: "SELECT 1"},
+			}),
+			Hooks: []models.JobSpecHook{
+				{Config: models.JobSpecConfigs{{Name: "TOPIC", Value: "source"}}},
+			},
+		}
+	}
+
+	t.Run("should save an independent copy of the job spec", func(t *testing.T) {
+		sourceSpec := newSourceSpec()
+
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepo.On("GetByName", "source-job").Return(sourceSpec, namespaceSpec, nil)
+		projectJobSpecRepo.On("GetByName", "cloned-job").Return(models.JobSpec{}, models.NamespaceSpec{}, store.ErrResourceNotFound)
+		defer projectJobSpecRepo.AssertExpectations(t)
+
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+		defer projJobSpecRepoFac.AssertExpectations(t)
+
+		jobSpecRepo := new(mock.JobSpecRepository)
+		jobSpecRepo.On("Save", mock2.AnythingOfType("models.JobSpec")).Return(nil)
+		defer jobSpecRepo.AssertExpectations(t)
+
+		jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+		jobSpecRepoFac.On("New", namespaceSpec).Return(jobSpecRepo)
+		defer jobSpecRepoFac.AssertExpectations(t)
+
+		svc := job.NewService(jobSpecRepoFac, nil, nil, nil, nil, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+		clonedSpec, clonedNamespace, err := svc.CloneJobSpec(projSpec, "source-job", "cloned-job")
+		assert.Nil(t, err)
+		assert.Equal(t, namespaceSpec, clonedNamespace)
+		assert.Equal(t, "cloned-job", clonedSpec.Name)
+		assert.Equal(t, map[string]models.JobSpecDependency{
+			"cloned-job":   {Type: models.JobSpecDependencyTypeIntra},
+			"upstream-job": {Type: models.JobSpecDependencyTypeIntra},
+		}, clonedSpec.Dependencies)
+		assert.Equal(t, sourceSpec.Assets.ToMap(), clonedSpec.Assets.ToMap())
+
+		// changes of the clone don't leak into the source spec
+		clonedSpec.Labels["team"] = "growth"
+		clonedSpec.Task.Config[0].Value = "production"
+		clonedSpec.Hooks[0].Config[0].Value = "cloned"
+		clonedSpec.Assets.GetAll()[0].Value = "SELECT 2"
+		assert.Equal(t, newSourceSpec(), sourceSpec)
+	})
+	t.Run("should fail if a job with the name of the clone exists", func(t *testing.T) {
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepo.On("GetByName", "source-job").Return(newSourceSpec(), namespaceSpec, nil)
+		projectJobSpecRepo.On("GetByName", "cloned-job").Return(models.JobSpec{Name: "cloned-job"}, namespaceSpec, nil)
+		defer projectJobSpecRepo.AssertExpectations(t)
+
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+		defer projJobSpecRepoFac.AssertExpectations(t)
+
+		svc := job.NewService(nil, nil, nil, nil, nil, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+		_, _, err := svc.CloneJobSpec(projSpec, "source-job", "cloned-job")
+		assert.True(t, errors.Is(err, job.ErrJobAlreadyExists))
+	})
+	t.Run("should fail if the source job isn't registered", func(t *testing.T) {
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepo.On("GetByName", "source-job").Return(models.JobSpec{}, models.NamespaceSpec{}, store.ErrResourceNotFound)
+		defer projectJobSpecRepo.AssertExpectations(t)
+
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+		defer projJobSpecRepoFac.AssertExpectations(t)
+
+		svc := job.NewService(nil, nil, nil, nil, nil, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+		_, _, err := svc.CloneJobSpec(projSpec, "source-job", "cloned-job")
+		assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+	})
+}
+
+func TestCloneProject(t *testing.T) {
+	ctx := context.Background()
+	prodProject := models.ProjectSpec{Name: "prod"}
+	stagingProject := models.ProjectSpec{Name: "staging"}
+	prodNamespace := models.NamespaceSpec{Name: "team-a", ProjectSpec: prodProject}
+	stagingNamespace := models.NamespaceSpec{Name: "team-a", ProjectSpec: stagingProject}
+	newProdSpec := func() models.JobSpec {
+		return models.JobSpec{
+			Name: "daily-orders",
+			Task: models.JobSpecTask{
+				Config: models.JobSpecConfigs{{Name: "DATASET", Value: "prod_dataset"}},
+			},
+			Assets: *models.JobAssets{}.New([]models.JobSpecAsset{
+				{Name: job.QueryAssetName, Value: "SELECT * FROM `prod_dataset.orders`"},
+			}),
+			Hooks: []models.JobSpecHook{
+				{Config: models.JobSpecConfigs{{Name: "TOPIC", Value: "prod_orders"}}},
+			},
+		}
+	}
+	replacements := []models.StringReplacement{
+		{Find: "prod_dataset", Replace: "staging_dataset"},
+		{Find: "prod_", Replace: "staging_"},
+	}
+	newService := func(t *testing.T, stagingSpecs []models.JobSpec) (*job.Service, *mock.CrossProjectJobSpecRepository) {
+		jobSpecRepo := new(mock.JobSpecRepository)
+		jobSpecRepo.On("GetAll").Return([]models.JobSpec{newProdSpec()}, nil)
+		t.Cleanup(func() { jobSpecRepo.AssertExpectations(t) })
+
+		jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+		jobSpecRepoFac.On("New", prodNamespace).Return(jobSpecRepo)
+		t.Cleanup(func() { jobSpecRepoFac.AssertExpectations(t) })
+
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepo.On("GetAll").Return(stagingSpecs, nil)
+		t.Cleanup(func() { projectJobSpecRepo.AssertExpectations(t) })
+
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", stagingProject).Return(projectJobSpecRepo)
+		t.Cleanup(func() { projJobSpecRepoFac.AssertExpectations(t) })
+
+		crossProjectRepo := new(mock.CrossProjectJobSpecRepository)
+		t.Cleanup(func() { crossProjectRepo.AssertExpectations(t) })
+
+		svc := job.NewService(jobSpecRepoFac, nil, nil, nil, nil, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+		svc.CrossProjectJobSpecRepo = crossProjectRepo
+		return svc, crossProjectRepo
+	}
+
+	t.Run("should copy jobs with replacements to the namespaces of the destination project", func(t *testing.T) {
+		svc, crossProjectRepo := newService(t, nil)
+		stagingSpec := newProdSpec()
+		stagingSpec.Task.Config[0].Value = "staging_dataset"
+		stagingSpec.Assets = *models.JobAssets{}.New([]models.JobSpecAsset{
+			{Name: job.QueryAssetName, Value: "SELECT * FROM `staging_dataset.orders`"},
+		})
+		stagingSpec.Hooks[0].Config[0].Value = "staging_orders"
+		stagingSpec.Dependencies = map[string]models.JobSpecDependency{}
+		crossProjectRepo.On("InsertAll", ctx, []models.JobSpecWithNamespace{
+			{Namespace: stagingNamespace, Spec: stagingSpec},
+		}).Return(nil)
+
+		created, err := svc.CloneProject(ctx, []models.NamespaceSpec{prodNamespace}, []models.NamespaceSpec{stagingNamespace}, replacements)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, created)
+	})
+	t.Run("should fail without creating jobs if the destination project has jobs of the same names", func(t *testing.T) {
+		svc, _ := newService(t, []models.JobSpec{{Name: "daily-orders"}})
+
+		_, err := svc.CloneProject(ctx, []models.NamespaceSpec{prodNamespace}, []models.NamespaceSpec{stagingNamespace}, replacements)
+		assert.True(t, errors.Is(err, job.ErrJobAlreadyExists))
+		assert.Contains(t, err.Error(), "daily-orders")
+	})
+	t.Run("should fail if a namespace with jobs isn't registered in the destination project", func(t *testing.T) {
+		jobSpecRepo := new(mock.JobSpecRepository)
+		jobSpecRepo.On("GetAll").Return([]models.JobSpec{newProdSpec()}, nil)
+		defer jobSpecRepo.AssertExpectations(t)
+
+		jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+		jobSpecRepoFac.On("New", prodNamespace).Return(jobSpecRepo)
+		defer jobSpecRepoFac.AssertExpectations(t)
+
+		svc := job.NewService(jobSpecRepoFac, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		svc.CrossProjectJobSpecRepo = new(mock.CrossProjectJobSpecRepository)
+		_, err := svc.CloneProject(ctx, []models.NamespaceSpec{prodNamespace}, nil, replacements)
+		assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+	})
+}
@@ -0,0 +1,160 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+// ValidateDependencyVersionCompatibility compares specs with the registered
+// specs of the project and warns about changes that may break the jobs
+// depending on them: columns of the destination removed or retyped while a
+// dependent query still references them, and changed window sizes, retries or
+// task configs
+func (srv *Service) ValidateDependencyVersionCompatibility(ctx context.Context, namespace models.NamespaceSpec,
+	specs []models.JobSpec) ([]models.CompatibilityWarning, error) {
+	projectJobSpecRepo := srv.projectJobSpecRepoFactory.New(namespace.ProjectSpec)
+	resolvedSpecs, err := srv.GetDependencyResolvedSpecs(namespace.ProjectSpec, projectJobSpecRepo, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve dependencies")
+	}
+
+	registeredSpecs := map[string]models.JobSpec{}
+	dependents := map[string][]models.JobSpec{}
+	for _, resolvedSpec := range resolvedSpecs {
+		registeredSpecs[resolvedSpec.Name] = resolvedSpec
+		for depName, dep := range resolvedSpec.Dependencies {
+			if dep.Type == models.JobSpecDependencyTypeIntra {
+				dependents[depName] = append(dependents[depName], resolvedSpec)
+			}
+		}
+	}
+
+	var warnings []models.CompatibilityWarning
+	for _, spec := range specs {
+		specDependents := dependents[spec.Name]
+		if len(specDependents) == 0 {
+			continue
+		}
+		sort.Slice(specDependents, func(i, j int) bool { return specDependents[i].Name < specDependents[j].Name })
+
+		if registeredSpec, ok := registeredSpecs[spec.Name]; ok {
+			var reasons []string
+			if registeredSpec.Task.Window.Size != spec.Task.Window.Size {
+				reasons = append(reasons, fmt.Sprintf("window size changes from %s to %s",
+					registeredSpec.Task.Window.Size, spec.Task.Window.Size))
+			}
+			if registeredSpec.Behavior.Retry.Count != spec.Behavior.Retry.Count {
+				reasons = append(reasons, fmt.Sprintf("retry count changes from %d to %d",
+					registeredSpec.Behavior.Retry.Count, spec.Behavior.Retry.Count))
+			}
+			reasons = append(reasons, taskConfigChanges(registeredSpec.Task.Config, spec.Task.Config)...)
+			for _, dependent := range specDependents {
+				for _, reason := range reasons {
+					warnings = append(warnings, models.CompatibilityWarning{
+						Job:       spec.Name,
+						Dependent: dependent.Name,
+						Reason:    reason,
+					})
+				}
+			}
+		}
+
+		drift, err := srv.DetectSchemaDrift(ctx, namespace.ProjectSpec, spec)
+		if errors.Is(err, ErrSchemaDriftUnsupported) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for _, dependent := range specDependents {
+			// assets of resolved specs are already compiled
+			query, err := dependent.Assets.GetByName(QueryAssetName)
+			if err != nil {
+				continue
+			}
+
+			var removedColumns, retypedColumns []string
+			for _, field := range drift.RemovedColumns {
+				if referencesColumn(query.Value, field.Name) {
+					removedColumns = append(removedColumns, field.Name)
+				}
+			}
+			for _, change := range drift.TypeChanges {
+				if referencesColumn(query.Value, change.Name) {
+					retypedColumns = append(retypedColumns, change.Name)
+				}
+			}
+			if len(removedColumns) > 0 {
+				warnings = append(warnings, models.CompatibilityWarning{
+					Job:       spec.Name,
+					Dependent: dependent.Name,
+					Dataset:   models.DatasetRef{Destination: drift.Destination, Columns: removedColumns},
+					Reason:    "referenced columns are no longer produced",
+				})
+			}
+			if len(retypedColumns) > 0 {
+				warnings = append(warnings, models.CompatibilityWarning{
+					Job:       spec.Name,
+					Dependent: dependent.Name,
+					Dataset:   models.DatasetRef{Destination: drift.Destination, Columns: retypedColumns},
+					Reason:    "referenced columns change their type",
+				})
+			}
+		}
+	}
+	return warnings, nil
+}
+
+// taskConfigChanges describes the task configs added, removed or changed by
+// spec ordered by their name, values are left out as they may hold secrets
+func taskConfigChanges(registered, spec models.JobSpecConfigs) []string {
+	registeredValues := map[string]string{}
+	for _, item := range registered {
+		registeredValues[item.Name] = item.Value
+	}
+	specValues := map[string]string{}
+	for _, item := range spec {
+		specValues[item.Name] = item.Value
+	}
+
+	var names []string
+	for name := range registeredValues {
+		names = append(names, name)
+	}
+	for name := range specValues {
+		if _, ok := registeredValues[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []string
+	for _, name := range names {
+		registeredValue, registeredOk := registeredValues[name]
+		specValue, specOk := specValues[name]
+		switch {
+		case !registeredOk:
+			changes = append(changes, fmt.Sprintf("task config %s is added", name))
+		case !specOk:
+			changes = append(changes, fmt.Sprintf("task config %s is removed", name))
+		case registeredValue != specValue:
+			changes = append(changes, fmt.Sprintf("task config %s changes", name))
+		}
+	}
+	return changes
+}
+
+// referencesColumn reports if query mentions column as a whole identifier,
+// columns of records are also matched by their last field
+func referencesColumn(query, column string) bool {
+	pattern := regexp.QuoteMeta(column)
+	if idx := strings.LastIndex(column, "."); idx >= 0 {
+		pattern += "|" + regexp.QuoteMeta(column[idx+1:])
+	}
+	return regexp.MustCompile(`(?i)(^|[^\w])(` + pattern + `)([^\w]|$)`).MatchString(query)
+}
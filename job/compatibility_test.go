@@ -0,0 +1,151 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestValidateDependencyVersionCompatibility(t *testing.T) {
+	ctx := context.Background()
+	dumpAssets := func(jobSpec models.JobSpec, _ models.ProjectSpec, scheduledAt time.Time) (models.JobAssets, error) {
+		return jobSpec.Assets, nil
+	}
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		Name:        "dev-team-1",
+		ProjectSpec: projSpec,
+	}
+	newJobSpec := func(name, query string, window time.Duration) models.JobSpec {
+		return models.JobSpec{
+			Name: name,
+			Task: models.JobSpecTask{
+				Window: models.JobSpecTaskWindow{Size: window},
+			},
+			Assets: *models.JobAssets{}.New([]models.JobSpecAsset{
+				{Name: job.QueryAssetName, Value: query},
+			}),
+			Dependencies: map[string]models.JobSpecDependency{},
+		}
+	}
+	// upstream-job writes proj:dataset.upstream, downstream-job reads from it
+	upstreamSpec := newJobSpec("upstream-job", "SELECT id, legacy_code FROM `proj.dataset.source`", 24*time.Hour)
+	newDownstreamSpec := func(query string) models.JobSpec {
+		downstreamSpec := newJobSpec("downstream-job", query, 24*time.Hour)
+		downstreamSpec.Dependencies = map[string]models.JobSpecDependency{
+			upstreamSpec.Name: {Job: &upstreamSpec, Type: models.JobSpecDependencyTypeIntra},
+		}
+		return downstreamSpec
+	}
+	newService := func(t *testing.T, downstreamSpec models.JobSpec, inspector models.DatastoreSchemaInspector) *job.Service {
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepo.On("GetAll").Return([]models.JobSpec{upstreamSpec, downstreamSpec}, nil)
+		t.Cleanup(func() { projectJobSpecRepo.AssertExpectations(t) })
+
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+		t.Cleanup(func() { projJobSpecRepoFac.AssertExpectations(t) })
+
+		depenResolver := new(mock.DependencyResolver)
+		depenResolver.On("Resolve", projSpec, projectJobSpecRepo, upstreamSpec, nil).Return(upstreamSpec, nil)
+		depenResolver.On("Resolve", projSpec, projectJobSpecRepo, downstreamSpec, nil).Return(downstreamSpec, nil)
+		t.Cleanup(func() { depenResolver.AssertExpectations(t) })
+
+		svc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+		svc.SchemaInspector = inspector
+		return svc
+	}
+	// the change of upstream-job drops legacy_code from its destination
+	newChangedUpstreamSpec := func(t *testing.T, window time.Duration) (models.JobSpec, *mock.DatastoreSchemaInspector) {
+		depMod := new(mock.DependencyResolverMod)
+		t.Cleanup(func() { depMod.AssertExpectations(t) })
+		changedSpec := newJobSpec(upstreamSpec.Name, "SELECT id FROM `proj.dataset.source`", window)
+		changedSpec.Task.Unit = &models.Plugin{DependencyMod: depMod}
+		depMod.On("GenerateDestination", ctx, mock2.Anything).Return(&models.GenerateDestinationResponse{Destination: "proj:dataset.upstream"}, nil)
+
+		inspector := new(mock.DatastoreSchemaInspector)
+		t.Cleanup(func() { inspector.AssertExpectations(t) })
+		inspector.On("TableSchema", ctx, projSpec, "proj:dataset.upstream").Return([]models.SchemaField{
+			{Name: "id", Type: "INTEGER"},
+			{Name: "legacy_code", Type: "STRING"},
+		}, nil)
+		inspector.On("QuerySchema", ctx, projSpec, "SELECT id FROM `proj.dataset.source`").Return([]models.SchemaField{
+			{Name: "id", Type: "INTEGER"},
+		}, nil)
+		return changedSpec, inspector
+	}
+
+	t.Run("should warn when an upstream job removes a column referenced by a downstream job", func(t *testing.T) {
+		downstreamSpec := newDownstreamSpec("SELECT id, LEGACY_CODE FROM `proj.dataset.upstream`")
+		changedSpec, inspector := newChangedUpstreamSpec(t, 24*time.Hour)
+
+		warnings, err := newService(t, downstreamSpec, inspector).ValidateDependencyVersionCompatibility(ctx, namespaceSpec, []models.JobSpec{changedSpec})
+		assert.Nil(t, err)
+		assert.Equal(t, []models.CompatibilityWarning{
+			{
+				Job:       "upstream-job",
+				Dependent: "downstream-job",
+				Dataset:   models.DatasetRef{Destination: "proj:dataset.upstream", Columns: []string{"legacy_code"}},
+				Reason:    "referenced columns are no longer produced",
+			},
+		}, warnings)
+	})
+	t.Run("should not warn when the removed column isn't referenced by downstream jobs", func(t *testing.T) {
+		downstreamSpec := newDownstreamSpec("SELECT id, legacy_code_v2 FROM `proj.dataset.upstream`")
+		changedSpec, inspector := newChangedUpstreamSpec(t, 24*time.Hour)
+
+		warnings, err := newService(t, downstreamSpec, inspector).ValidateDependencyVersionCompatibility(ctx, namespaceSpec, []models.JobSpec{changedSpec})
+		assert.Nil(t, err)
+		assert.Empty(t, warnings)
+	})
+	t.Run("should warn when an upstream job changes its window size", func(t *testing.T) {
+		downstreamSpec := newDownstreamSpec("SELECT id FROM `proj.dataset.upstream`")
+		changedSpec, inspector := newChangedUpstreamSpec(t, time.Hour)
+
+		warnings, err := newService(t, downstreamSpec, inspector).ValidateDependencyVersionCompatibility(ctx, namespaceSpec, []models.JobSpec{changedSpec})
+		assert.Nil(t, err)
+		assert.Equal(t, []models.CompatibilityWarning{
+			{
+				Job:       "upstream-job",
+				Dependent: "downstream-job",
+				Reason:    "window size changes from 24h0m0s to 1h0m0s",
+			},
+		}, warnings)
+	})
+	t.Run("should warn when an upstream job changes its task config", func(t *testing.T) {
+		downstreamSpec := newDownstreamSpec("SELECT id FROM `proj.dataset.upstream`")
+		changedSpec, inspector := newChangedUpstreamSpec(t, 24*time.Hour)
+		changedSpec.Task.Config = models.JobSpecConfigs{
+			{Name: "LOAD_METHOD", Value: "APPEND"},
+			{Name: "DATASET", Value: "dataset_v2"},
+		}
+		upstreamSpec.Task.Config = models.JobSpecConfigs{
+			{Name: "DATASET", Value: "dataset"},
+			{Name: "TABLE", Value: "upstream"},
+		}
+		defer func() { upstreamSpec.Task.Config = nil }()
+
+		warnings, err := newService(t, downstreamSpec, inspector).ValidateDependencyVersionCompatibility(ctx, namespaceSpec, []models.JobSpec{changedSpec})
+		assert.Nil(t, err)
+		assert.Equal(t, []models.CompatibilityWarning{
+			{Job: "upstream-job", Dependent: "downstream-job", Reason: "task config DATASET changes"},
+			{Job: "upstream-job", Dependent: "downstream-job", Reason: "task config LOAD_METHOD is added"},
+			{Job: "upstream-job", Dependent: "downstream-job", Reason: "task config TABLE is removed"},
+		}, warnings)
+	})
+	t.Run("should skip jobs without dependents", func(t *testing.T) {
+		downstreamSpec := newDownstreamSpec("SELECT id FROM `proj.dataset.upstream`")
+
+		warnings, err := newService(t, downstreamSpec, nil).ValidateDependencyVersionCompatibility(ctx, namespaceSpec, []models.JobSpec{downstreamSpec})
+		assert.Nil(t, err)
+		assert.Empty(t, warnings)
+	})
+}
@@ -2,6 +2,10 @@ package job
 
 import (
 	"bytes"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
 	"text/template"
 	"time"
 
@@ -21,16 +25,70 @@ var (
 type Compiler struct {
 	schedulerTemplate []byte // template string for dag generation
 	hostname          string
+	envParams         ParameterStore
+
+	// dependencyPinRepoFactory is optional, upstream specs are used as
+	// they are when it is not set
+	dependencyPinRepoFactory DependencyPinRepoFactory
+
+	// validator is optional, compiled jobs are not validated when it is not set
+	validator CompiledJobValidator
+
+	// EmbedDocs inserts a docstring describing the job at the top of
+	// compiled jobs
+	EmbedDocs bool
+
+	// Now is when upcoming runs of jobs are checked for daylight saving
+	// time changes from
+	Now func() time.Time
 }
 
 // Compile use golang template engine to parse and insert job
 // specific details in template file
 func (com *Compiler) Compile(namespaceSpec models.NamespaceSpec, jobSpec models.JobSpec) (job models.Job, err error) {
+	return com.CompileWithGit(namespaceSpec, jobSpec, GitContext{})
+}
+
+// CompileWithGit compiles the job like Compile, with the commit in git
+// available to the template as GIT_SHA, GIT_BRANCH, GIT_AUTHOR and
+// GIT_COMMIT_TIME
+func (com *Compiler) CompileWithGit(namespaceSpec models.NamespaceSpec, jobSpec models.JobSpec, git GitContext) (job models.Job, err error) {
 	if len(com.schedulerTemplate) == 0 {
 		return models.Job{}, ErrEmptyTemplateFile
 	}
+	if err := validateHookOrder(jobSpec); err != nil {
+		return models.Job{}, err
+	}
+	if err := validateHookRetries(jobSpec); err != nil {
+		return models.Job{}, err
+	}
+	if err := validateTaskResources(jobSpec); err != nil {
+		return models.Job{}, err
+	}
+	warnings, err := scheduleDSTWarnings(jobSpec, com.Now())
+	if err != nil {
+		return models.Job{}, err
+	}
+
+	if com.dependencyPinRepoFactory != nil {
+		pins, err := com.dependencyPinRepoFactory.New(namespaceSpec.ProjectSpec).GetByJob(jobSpec.Name)
+		if err != nil {
+			return models.Job{}, errors.Wrapf(err, "failed to read dependency pins of %s", jobSpec.Name)
+		}
+		jobSpec = applyDependencyPins(jobSpec, pins)
+	}
 
-	tmpl, err := template.New("compiler").Funcs(sprig.TxtFuncMap()).Parse(string(com.schedulerTemplate))
+	// params are looked up in job config, then project config and then
+	// OPTIMUS_PARAM_ prefixed env variables
+	params := ChainedParameterStore{
+		NewJobParameterStore(jobSpec),
+		NewProjectParameterStore(namespaceSpec.ProjectSpec),
+		com.envParams,
+	}
+	tmpl, err := template.New("compiler").Funcs(sprig.TxtFuncMap()).Funcs(template.FuncMap{
+		"param":   params.Get,
+		"feature": namespaceSpec.ProjectSpec.IsFeatureEnabled,
+	}).Parse(string(com.schedulerTemplate))
 	if err != nil {
 		return models.Job{}, err
 	}
@@ -50,6 +108,13 @@ func (com *Compiler) Compile(namespaceSpec models.NamespaceSpec, jobSpec models.
 		}
 	}
 
+	var blackoutWindows []models.BlackoutWindow
+	for _, window := range namespaceSpec.ProjectSpec.BlackoutWindows {
+		if window.AppliesTo(jobSpec.Name) {
+			blackoutWindows = append(blackoutWindows, window)
+		}
+	}
+
 	var buf bytes.Buffer
 	if err = tmpl.Execute(&buf, struct {
 		Namespace                  models.NamespaceSpec
@@ -64,7 +129,12 @@ func (com *Compiler) Compile(namespaceSpec models.NamespaceSpec, jobSpec models.
 		JobSpecDependencyTypeInter string
 		JobSpecDependencyTypeExtra string
 		SLAMissDurationInSec       int64
+		BlackoutWindows            []models.BlackoutWindow
 		Version                    string
+		GIT_SHA                    string
+		GIT_BRANCH                 string
+		GIT_AUTHOR                 string
+		GIT_COMMIT_TIME            string
 	}{
 		Namespace:                  namespaceSpec,
 		Job:                        jobSpec,
@@ -78,22 +148,122 @@ func (com *Compiler) Compile(namespaceSpec models.NamespaceSpec, jobSpec models.
 		JobSpecDependencyTypeInter: string(models.JobSpecDependencyTypeInter),
 		JobSpecDependencyTypeExtra: string(models.JobSpecDependencyTypeExtra),
 		SLAMissDurationInSec:       slaMissDurationInSec,
+		BlackoutWindows:            blackoutWindows,
 		Version:                    config.Version,
+		GIT_SHA:                    singleLine(git.CommitSHA),
+		GIT_BRANCH:                 singleLine(git.Branch),
+		GIT_AUTHOR:                 singleLine(git.Author),
+		GIT_COMMIT_TIME:            singleLine(git.CommitTime),
 	}); err != nil {
 		return models.Job{}, errors.Wrap(err, "failed to templatize job")
 	}
+	contents := buf.Bytes()
+	if com.EmbedDocs {
+		contents = embedDocstring(contents, jobDocstring(namespaceSpec, jobSpec, com.hostname))
+	}
+	if com.validator != nil {
+		if err := com.validator(jobSpec, contents); err != nil {
+			return models.Job{}, err
+		}
+	}
 
 	return models.Job{
 		Name:        jobSpec.Name,
-		Contents:    buf.Bytes(),
+		Contents:    contents,
 		NamespaceID: namespaceSpec.ID.String(),
+		Warnings:    warnings,
 	}, nil
 }
 
-// NewCompiler constructs a new Compiler that satisfies dag.Compiler
-func NewCompiler(schedulerTemplate []byte, hostname string) *Compiler {
+// jobDocstring describes the job for readers of the compiled file, along
+// with a link to the job on the optimus server at hostname
+func jobDocstring(namespaceSpec models.NamespaceSpec, jobSpec models.JobSpec, hostname string) string {
+	var doc strings.Builder
+	fmt.Fprintf(&doc, "Optimus job: %s\n", jobSpec.Name)
+	fmt.Fprintf(&doc, "Project: %s\n", namespaceSpec.ProjectSpec.Name)
+	fmt.Fprintf(&doc, "Namespace: %s\n", namespaceSpec.Name)
+	fmt.Fprintf(&doc, "Owner: %s\n", jobSpec.Owner)
+	if hostname != "" {
+		if !strings.Contains(hostname, "://") {
+			hostname = "http://" + hostname
+		}
+		fmt.Fprintf(&doc, "Details: %s/v1/project/%s/namespace/%s/job/%s\n", strings.TrimRight(hostname, "/"),
+			url.PathEscape(namespaceSpec.ProjectSpec.Name), url.PathEscape(namespaceSpec.Name), url.PathEscape(jobSpec.Name))
+	}
+	if description := strings.TrimSpace(jobSpec.Description); description != "" {
+		fmt.Fprintf(&doc, "\n%s\n", description)
+	}
+
+	if len(jobSpec.Labels) > 0 {
+		var labelKeys []string
+		for key := range jobSpec.Labels {
+			labelKeys = append(labelKeys, key)
+		}
+		sort.Strings(labelKeys)
+		doc.WriteString("\nLabels:\n")
+		for _, key := range labelKeys {
+			fmt.Fprintf(&doc, "  %s: %s\n", key, jobSpec.Labels[key])
+		}
+	}
+
+	if len(jobSpec.Dependencies) > 0 {
+		var dependencyNames []string
+		for name := range jobSpec.Dependencies {
+			dependencyNames = append(dependencyNames, name)
+		}
+		sort.Strings(dependencyNames)
+		doc.WriteString("\nDependencies:\n")
+		for _, name := range dependencyNames {
+			fmt.Fprintf(&doc, "  - %s (%s)\n", name, jobSpec.Dependencies[name].Type)
+		}
+	}
+	return doc.String()
+}
+
+// singleLine keeps values sent by clients from breaking out of the line they
+// are interpolated in
+func singleLine(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// embedDocstring places doc as the python docstring of the module in
+// contents, after the leading comments like the generated code notice
+func embedDocstring(contents []byte, doc string) []byte {
+	// the docstring can't end early or escape characters on its own
+	doc = strings.ReplaceAll(doc, `\`, `\\`)
+	doc = strings.ReplaceAll(doc, `"""`, `\"\"\"`)
+
+	var header int
+	for header < len(contents) && contents[header] == '#' {
+		lineEnd := bytes.IndexByte(contents[header:], '\n')
+		if lineEnd < 0 {
+			header = len(contents)
+			break
+		}
+		header += lineEnd + 1
+	}
+
+	var embedded bytes.Buffer
+	embedded.Write(contents[:header])
+	if header > 0 && contents[header-1] != '\n' {
+		embedded.WriteByte('\n')
+	}
+	embedded.WriteString(`"""` + "\n" + doc + `"""` + "\n")
+	embedded.Write(contents[header:])
+	return embedded.Bytes()
+}
+
+// NewCompiler constructs a new Compiler that satisfies dag.Compiler, upstream
+// specs pinned in dependencyPinRepoFactory are used in place of the latest ones
+// and compiled jobs are checked with validator
+func NewCompiler(schedulerTemplate []byte, hostname string, dependencyPinRepoFactory DependencyPinRepoFactory,
+	validator CompiledJobValidator) *Compiler {
 	return &Compiler{
-		schedulerTemplate: schedulerTemplate,
-		hostname:          hostname,
+		schedulerTemplate:        schedulerTemplate,
+		hostname:                 hostname,
+		envParams:                NewEnvParameterStore(),
+		dependencyPinRepoFactory: dependencyPinRepoFactory,
+		validator:                validator,
+		Now:                      time.Now,
 	}
 }
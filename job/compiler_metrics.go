@@ -0,0 +1,56 @@
+package job
+
+import (
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricJobCompileDuration = "optimus_job_compile_duration_seconds"
+
+// InstrumentedCompiler observes the duration of compilations of the
+// wrapped compiler, labelled by their status
+type InstrumentedCompiler struct {
+	compiler models.JobCompiler
+	duration *prometheus.HistogramVec
+}
+
+func (c *InstrumentedCompiler) Compile(namespaceSpec models.NamespaceSpec, jobSpec models.JobSpec) (models.Job, error) {
+	return c.CompileWithGit(namespaceSpec, jobSpec, GitContext{})
+}
+
+// CompileWithGit falls back to Compile if the wrapped compiler isn't a
+// GitCompiler
+func (c *InstrumentedCompiler) CompileWithGit(namespaceSpec models.NamespaceSpec, jobSpec models.JobSpec, git GitContext) (models.Job, error) {
+	start := time.Now()
+	var compiled models.Job
+	var err error
+	if gitCompiler, ok := c.compiler.(GitCompiler); ok {
+		compiled, err = gitCompiler.CompileWithGit(namespaceSpec, jobSpec, git)
+	} else {
+		compiled, err = c.compiler.Compile(namespaceSpec, jobSpec)
+	}
+	status := "success"
+	if err != nil {
+		status = "failure"
+	}
+	c.duration.WithLabelValues(status).Observe(time.Since(start).Seconds())
+	return compiled, err
+}
+
+// NewInstrumentedCompiler wraps compiler and registers the compile duration histogram with reg
+func NewInstrumentedCompiler(compiler models.JobCompiler, reg prometheus.Registerer) (*InstrumentedCompiler, error) {
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    metricJobCompileDuration,
+		Help:    "Duration of job compilations in seconds",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 8),
+	}, []string{"status"})
+	if err := reg.Register(duration); err != nil {
+		return nil, err
+	}
+	return &InstrumentedCompiler{
+		compiler: compiler,
+		duration: duration,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package job_test
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentedCompiler(t *testing.T) {
+	namespaceSpec := models.NamespaceSpec{Name: "foo-namespace"}
+	compileCounts := func(t *testing.T, reg *prometheus.Registry) map[string]uint64 {
+		families, err := reg.Gather()
+		assert.Nil(t, err)
+		counts := map[string]uint64{}
+		for _, family := range families {
+			if family.GetName() != "optimus_job_compile_duration_seconds" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				counts[metric.GetLabel()[0].GetValue()] = metric.GetHistogram().GetSampleCount()
+			}
+		}
+		return counts
+	}
+
+	t.Run("should observe compile durations by status", func(t *testing.T) {
+		specA := models.JobSpec{Name: "foo"}
+		specB := models.JobSpec{Name: "bar"}
+		compiler := new(mock.Compiler)
+		compiler.On("Compile", namespaceSpec, specA).Return(models.Job{Name: "foo"}, nil)
+		compiler.On("Compile", namespaceSpec, specB).Return(models.Job{}, errors.New("invalid template"))
+		defer compiler.AssertExpectations(t)
+
+		reg := prometheus.NewRegistry()
+		instrumented, err := job.NewInstrumentedCompiler(compiler, reg)
+		assert.Nil(t, err)
+
+		compiled, err := instrumented.Compile(namespaceSpec, specA)
+		assert.Nil(t, err)
+		assert.Equal(t, "foo", compiled.Name)
+		_, err = instrumented.Compile(namespaceSpec, specA)
+		assert.Nil(t, err)
+		_, err = instrumented.Compile(namespaceSpec, specB)
+		assert.NotNil(t, err)
+
+		assert.Equal(t, map[string]uint64{"success": 2, "failure": 1}, compileCounts(t, reg))
+	})
+	t.Run("should fail if metrics are already registered", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		_, err := job.NewInstrumentedCompiler(new(mock.Compiler), reg)
+		assert.Nil(t, err)
+		_, err = job.NewInstrumentedCompiler(new(mock.Compiler), reg)
+		assert.NotNil(t, err)
+	})
+}
@@ -1,11 +1,15 @@
 package job_test
 
 import (
+	"fmt"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -60,6 +64,8 @@ func TestCompiler(t *testing.T) {
 			com := job.NewCompiler(
 				[]byte("content = {{.Job.Name}}"),
 				"",
+				nil,
+				nil,
 			)
 			dag, err := com.Compile(namespaceSpec, spec)
 
@@ -72,27 +78,445 @@ func TestCompiler(t *testing.T) {
 			com := job.NewCompiler(
 				[]byte("content = {{.Job.Name}}"),
 				"",
+				nil,
+				nil,
 			)
 			dag, err := com.Compile(namespaceSpec, tempSpec)
 
 			assert.Equal(t, dag.Contents, []byte("content = foo"))
 			assert.Nil(t, err)
 		})
+		t.Run("should compile template with params from job, project and env", func(t *testing.T) {
+			os.Setenv("OPTIMUS_PARAM_REGION", "asia")
+			defer os.Unsetenv("OPTIMUS_PARAM_REGION")
+
+			tempSpec := spec
+			tempSpec.Task.Config = []models.JobSpecConfigItem{{Name: "DATASET", Value: "job_dataset"}}
+			tempNamespace := namespaceSpec
+			tempNamespace.ProjectSpec.Config = map[string]string{"DATASET": "project_dataset", "BUCKET": "bucket"}
+			com := job.NewCompiler(
+				[]byte(`{{ param "DATASET" }} {{ param "BUCKET" }} {{ param "REGION" }}`),
+				"",
+				nil,
+				nil,
+			)
+			dag, err := com.Compile(tempNamespace, tempSpec)
+
+			assert.Nil(t, err)
+			assert.Equal(t, "job_dataset bucket asia", string(dag.Contents))
+		})
+		t.Run("should compile parts of the template behind enabled feature flags", func(t *testing.T) {
+			com := job.NewCompiler(
+				[]byte(`hooks = [{{ if feature "use_new_hook" }}"new_hook"{{ else }}"old_hook"{{ end }}]`),
+				"",
+				nil,
+				nil,
+			)
+			baseline, err := com.Compile(namespaceSpec, spec)
+			assert.Nil(t, err)
+			assert.Equal(t, `hooks = ["old_hook"]`, string(baseline.Contents))
+
+			tempNamespace := namespaceSpec
+			tempNamespace.ProjectSpec.FeatureFlags = map[string]bool{"use_new_hook": true}
+			enabled, err := com.Compile(tempNamespace, spec)
+			assert.Nil(t, err)
+			assert.Equal(t, `hooks = ["new_hook"]`, string(enabled.Contents))
+
+			tempNamespace.ProjectSpec.FeatureFlags = map[string]bool{"use_new_hook": false}
+			disabled, err := com.Compile(tempNamespace, spec)
+			assert.Nil(t, err)
+			assert.Equal(t, baseline.Contents, disabled.Contents)
+		})
+		t.Run("should compile template with the git commit the job is deployed from", func(t *testing.T) {
+			com := job.NewCompiler(
+				[]byte(`sha = "{{ .GIT_SHA }}", branch = "{{ .GIT_BRANCH }}", author = "{{ .GIT_AUTHOR }}"`),
+				"",
+				nil,
+				nil,
+			)
+			dag, err := com.CompileWithGit(namespaceSpec, spec, job.GitContext{
+				CommitSHA: "4f1b2c3d",
+				Branch:    "main",
+				Author:    "Jane Doe\n<jane@example.io>",
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, `sha = "4f1b2c3d", branch = "main", author = "Jane Doe <jane@example.io>"`, string(dag.Contents))
+
+			withoutGit, err := com.Compile(namespaceSpec, spec)
+			assert.Nil(t, err)
+			assert.Equal(t, `sha = "", branch = "", author = ""`, string(withoutGit.Contents))
+		})
+		t.Run("should return error if param is not found", func(t *testing.T) {
+			com := job.NewCompiler(
+				[]byte(`{{ param "OPTIMUS_TEST_MISSING" }}`),
+				"",
+				nil,
+				nil,
+			)
+			_, err := com.Compile(namespaceSpec, spec)
+			assert.True(t, errors.Is(err, job.ErrParameterNotFound))
+		})
+		t.Run("should compile against pinned upstream spec after the upstream is updated", func(t *testing.T) {
+			pinnedUpstream := models.JobSpec{Name: "upstream", Owner: "old-owner@mee"}
+			updatedUpstream := models.JobSpec{Name: "upstream", Owner: "new-owner@mee"}
+			tempSpec := spec
+			tempSpec.Dependencies = map[string]models.JobSpecDependency{
+				"upstream": {Job: &updatedUpstream, Type: models.JobSpecDependencyTypeIntra},
+			}
+
+			pinRepo := new(mock.DependencyPinRepository)
+			pinRepo.On("GetByJob", spec.Name).Return([]models.DependencyPin{
+				{JobName: spec.Name, DependencyName: "upstream", SpecVersion: "v1", DependencySpec: pinnedUpstream},
+			}, nil)
+			defer pinRepo.AssertExpectations(t)
+			pinRepoFac := new(mock.DependencyPinRepoFactory)
+			pinRepoFac.On("New", projSpec).Return(pinRepo)
+			defer pinRepoFac.AssertExpectations(t)
+
+			com := job.NewCompiler(
+				[]byte(`{{range $_, $dep := .Job.Dependencies}}{{$dep.Job.Owner}}{{end}}`),
+				"",
+				pinRepoFac,
+				nil,
+			)
+			dag, err := com.Compile(namespaceSpec, tempSpec)
+
+			assert.Nil(t, err)
+			assert.Equal(t, "old-owner@mee", string(dag.Contents))
+			assert.Equal(t, "new-owner@mee", tempSpec.Dependencies["upstream"].Job.Owner)
+		})
 		t.Run("should return error if failed to read template", func(t *testing.T) {
 			com := job.NewCompiler(
 				[]byte(""),
 				"",
+				nil,
+				nil,
 			)
 			_, err := com.Compile(namespaceSpec, spec)
 			assert.Equal(t, err, job.ErrEmptyTemplateFile)
 		})
+		t.Run("should validate compiled dag", func(t *testing.T) {
+			dagTemplate := `default_args = {
+    "start_date": datetime.strptime({{ .Job.Schedule.StartDate.Format "2006-01-02T15:04:05" | quote }}, "%Y-%m-%dT%H:%M:%S"),
+}
+dag = DAG(
+    dag_id={{.Job.Name | quote}},
+    schedule_interval={{.Job.Schedule.Interval | quote}},
+)`
+			com := job.NewCompiler([]byte(dagTemplate), "", nil, job.ValidateAirflowDAG)
+			_, err := com.Compile(namespaceSpec, spec)
+			assert.Nil(t, err)
+
+			tempSpec := spec
+			tempSpec.Schedule = models.JobSpecSchedule{}
+			_, err = com.Compile(namespaceSpec, tempSpec)
+			var validationErr *job.CompilationValidationError
+			assert.True(t, errors.As(err, &validationErr))
+			assert.Equal(t, []string{"schedule_interval is empty", "start_date is not set"}, validationErr.Reasons)
+		})
+		t.Run("should validate compiled dag with a timezone aware start date", func(t *testing.T) {
+			dagTemplate := `default_args = {
+    "start_date": pendulum.instance(datetime.strptime({{ .Job.Schedule.StartDate.Format "2006-01-02T15:04:05" | quote }}, "%Y-%m-%dT%H:%M:%S"), tz={{ .Job.Schedule.Timezone | quote }}),
+}
+dag = DAG(
+    dag_id={{.Job.Name | quote}},
+    schedule_interval={{.Job.Schedule.Interval | quote}},
+)`
+			com := job.NewCompiler([]byte(dagTemplate), "", nil, job.ValidateAirflowDAG)
+			tempSpec := spec
+			tempSpec.Schedule.Timezone = "Asia/Jakarta"
+			_, err := com.Compile(namespaceSpec, tempSpec)
+			assert.Nil(t, err)
+		})
+		t.Run("should return error if compiled dag misses required fields", func(t *testing.T) {
+			com := job.NewCompiler([]byte("dag = DAG(dag_id=\"bar\")"), "", nil, job.ValidateAirflowDAG)
+			_, err := com.Compile(namespaceSpec, spec)
+			assert.Equal(t, &job.CompilationValidationError{
+				JobName: "foo",
+				Reasons: []string{"dag_id \"bar\" doesn't match job name", "schedule_interval is missing", "start_date is missing"},
+			}, err)
+		})
+		t.Run("should embed docs of the job as a docstring after the leading comments", func(t *testing.T) {
+			com := job.NewCompiler(
+				[]byte("# Code generated by optimus. DO NOT EDIT.\ndag_id = {{.Job.Name | quote}}"),
+				"optimus.example.io:80",
+				nil,
+				nil,
+			)
+			com.EmbedDocs = true
+
+			tempSpec := spec
+			tempSpec.Description = `loads "foo" """daily"""`
+			tempSpec.Labels = map[string]string{"team": "data", "orchestrator": "optimus"}
+			tempSpec.Dependencies = map[string]models.JobSpecDependency{
+				"bar":                     {Type: models.JobSpecDependencyTypeIntra},
+				"other-project/baz-model": {Type: models.JobSpecDependencyTypeInter},
+			}
+			dag, err := com.Compile(namespaceSpec, tempSpec)
+			assert.Nil(t, err)
+			assert.Equal(t, `# Code generated by optimus. DO NOT EDIT.
+"""
+Optimus job: foo
+Project: foo-project
+Namespace: foo-namespace
+Owner: mee@mee
+Details: http://optimus.example.io:80/v1/project/foo-project/namespace/foo-namespace/job/foo
+
+loads "foo" \"\"\"daily\"\"\"
+
+Labels:
+  orchestrator: optimus
+  team: data
+
+Dependencies:
+  - bar (intra)
+  - other-project/baz-model (inter)
+"""
+dag_id = "foo"`, string(dag.Contents))
+		})
 		t.Run("should return error if failed to parse template", func(t *testing.T) {
 			com := job.NewCompiler(
 				[]byte("content = {{.Tob.Name}}"),
 				"",
+				nil,
+				nil,
 			)
 			_, err := com.Compile(namespaceSpec, spec)
 			assert.Error(t, err)
 		})
+		t.Run("hook ordering", func(t *testing.T) {
+			newHook := func(name string, dependsOn []string, runAfter ...string) models.JobSpecHook {
+				hookUnit := new(mock.BasePlugin)
+				hookUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+					Name:      name,
+					HookType:  models.HookTypePost,
+					DependsOn: dependsOn,
+				}, nil)
+				return models.JobSpecHook{
+					Unit:     &models.Plugin{Base: hookUnit},
+					RunAfter: runAfter,
+				}
+			}
+			com := job.NewCompiler(
+				[]byte(`{{ range .Job.Hooks }}{{ range .RunAfter }}{{ . }} >> {{ end }}{{ .Unit.Info.Name }};{{ end }}`),
+				"",
+				nil,
+				nil,
+			)
+
+			t.Run("should compile hooks running after other hooks", func(t *testing.T) {
+				tempSpec := spec
+				tempSpec.Hooks = []models.JobSpecHook{
+					newHook("notify", nil, "quality-check"),
+					newHook("quality-check", []string{"transporter"}),
+					newHook("transporter", nil),
+				}
+				dag, err := com.Compile(namespaceSpec, tempSpec)
+
+				assert.Nil(t, err)
+				assert.Equal(t, "quality-check >> notify;quality-check;transporter;", string(dag.Contents))
+			})
+			t.Run("should return error if a hook runs after a hook the job doesn't have", func(t *testing.T) {
+				tempSpec := spec
+				tempSpec.Hooks = []models.JobSpecHook{
+					newHook("notify", nil, "quality-check"),
+				}
+				_, err := com.Compile(namespaceSpec, tempSpec)
+
+				assert.EqualError(t, err, "hook notify of job foo runs after quality-check which is not a hook of the job")
+			})
+			t.Run("should return error if hooks run after each other in a cycle", func(t *testing.T) {
+				tempSpec := spec
+				tempSpec.Hooks = []models.JobSpecHook{
+					newHook("notify", nil, "quality-check"),
+					newHook("quality-check", []string{"transporter"}),
+					newHook("transporter", nil, "notify"),
+				}
+				_, err := com.Compile(namespaceSpec, tempSpec)
+
+				assert.EqualError(t, err, "hooks of job foo run after each other in a cycle: "+
+					"notify -> quality-check -> transporter -> notify")
+			})
+			t.Run("should return error if a hook runs after a hook of another type", func(t *testing.T) {
+				preHookUnit := new(mock.BasePlugin)
+				preHookUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+					Name:     "transporter",
+					HookType: models.HookTypePre,
+				}, nil)
+				tempSpec := spec
+				tempSpec.Hooks = []models.JobSpecHook{
+					newHook("notify", nil, "transporter"),
+					{Unit: &models.Plugin{Base: preHookUnit}},
+				}
+				_, err := com.Compile(namespaceSpec, tempSpec)
+
+				assert.EqualError(t, err, "post hook notify of job foo can't run after pre hook transporter")
+			})
+			t.Run("should return error if a hook runs after itself", func(t *testing.T) {
+				tempSpec := spec
+				tempSpec.Hooks = []models.JobSpecHook{
+					newHook("notify", nil, "notify"),
+				}
+				_, err := com.Compile(namespaceSpec, tempSpec)
+
+				assert.EqualError(t, err, "hooks of job foo run after each other in a cycle: notify -> notify")
+			})
+		})
+		t.Run("hook retries", func(t *testing.T) {
+			com := job.NewCompiler(
+				[]byte(`{{ range .Job.Hooks }}{{ .Unit.Info.Name }}:{{ .Retry.Count }}:{{ .Retry.Delay }};{{ end }}`),
+				"",
+				nil,
+				nil,
+			)
+			newHook := func(retry models.JobSpecBehaviorRetry) models.JobSpecHook {
+				hookUnit := new(mock.BasePlugin)
+				hookUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+					Name:     "notify",
+					HookType: models.HookTypePost,
+				}, nil)
+				return models.JobSpecHook{
+					Unit:  &models.Plugin{Base: hookUnit},
+					Retry: retry,
+				}
+			}
+
+			t.Run("should compile hooks with retries", func(t *testing.T) {
+				tempSpec := spec
+				tempSpec.Hooks = []models.JobSpecHook{
+					newHook(models.JobSpecBehaviorRetry{Count: 10, Delay: time.Minute}),
+				}
+				dag, err := com.Compile(namespaceSpec, tempSpec)
+
+				assert.Nil(t, err)
+				assert.Equal(t, "notify:10:1m0s;", string(dag.Contents))
+			})
+			t.Run("should return error if a hook is retried too many times", func(t *testing.T) {
+				tempSpec := spec
+				tempSpec.Hooks = []models.JobSpecHook{
+					newHook(models.JobSpecBehaviorRetry{Count: 11}),
+				}
+				_, err := com.Compile(namespaceSpec, tempSpec)
+
+				assert.EqualError(t, err, "retry count of hook notify of job foo should be between 0 and 10")
+			})
+			t.Run("should return error if a hook is retried with a negative delay", func(t *testing.T) {
+				tempSpec := spec
+				tempSpec.Hooks = []models.JobSpecHook{
+					newHook(models.JobSpecBehaviorRetry{Count: 1, Delay: -time.Second}),
+				}
+				_, err := com.Compile(namespaceSpec, tempSpec)
+
+				assert.EqualError(t, err, "retry delay of hook notify of job foo can't be negative")
+			})
+		})
+		t.Run("task resources", func(t *testing.T) {
+			com := job.NewCompiler(
+				[]byte(`{{ with .Job.Task.Resources }}{{ .CPURequest }}/{{ .CPULimit }}/{{ .MemoryRequest }}/{{ .MemoryLimit }}{{ end }}`),
+				"",
+				nil,
+				nil,
+			)
+
+			t.Run("should compile kubernetes resource quantities", func(t *testing.T) {
+				tempSpec := spec
+				tempSpec.Task.Resources = models.JobSpecTaskResources{
+					CPURequest:    "500m",
+					CPULimit:      "1.5",
+					MemoryRequest: "1e9",
+					MemoryLimit:   "2Gi",
+				}
+				dag, err := com.Compile(namespaceSpec, tempSpec)
+
+				assert.Nil(t, err)
+				assert.Equal(t, "500m/1.5/1e9/2Gi", string(dag.Contents))
+			})
+			t.Run("should return error if a resource is not a kubernetes quantity", func(t *testing.T) {
+				tempSpec := spec
+				tempSpec.Task.Resources = models.JobSpecTaskResources{
+					CPURequest:  "500m",
+					MemoryLimit: "2 GB",
+				}
+				_, err := com.Compile(namespaceSpec, tempSpec)
+
+				assert.EqualError(t, err, `memory_limit "2 GB" of job foo is not a valid resource quantity`)
+			})
+			t.Run("should return error if a resource doesn't follow the quantity grammar", func(t *testing.T) {
+				for _, quantity := range []string{"1.2.3", "2Gb", "5Mm", "1e", "e3", "Ki", ".", "1ki", "1e1.5"} {
+					tempSpec := spec
+					tempSpec.Task.Resources = models.JobSpecTaskResources{CPULimit: quantity}
+					_, err := com.Compile(namespaceSpec, tempSpec)
+
+					assert.EqualError(t, err, fmt.Sprintf("cpu_limit %q of job foo is not a valid resource quantity", quantity))
+				}
+			})
+			t.Run("should accept every form of the quantity grammar", func(t *testing.T) {
+				for _, quantity := range []string{"0", "+1", "1.", ".5", "100n", "250u", "1k", "3E", "1Ei", "12e-3", "5E+2"} {
+					tempSpec := spec
+					tempSpec.Task.Resources = models.JobSpecTaskResources{MemoryRequest: quantity}
+					_, err := com.Compile(namespaceSpec, tempSpec)
+
+					assert.Nil(t, err, quantity)
+				}
+			})
+			t.Run("should return error if a resource is negative", func(t *testing.T) {
+				tempSpec := spec
+				tempSpec.Task.Resources = models.JobSpecTaskResources{MemoryRequest: "-1Gi"}
+				_, err := com.Compile(namespaceSpec, tempSpec)
+
+				assert.EqualError(t, err, `memory_request "-1Gi" of job foo can't be negative`)
+			})
+		})
+		t.Run("daylight saving time warnings", func(t *testing.T) {
+			com := job.NewCompiler(
+				[]byte("content = {{.Job.Name}}"),
+				"",
+				nil,
+				nil,
+			)
+			com.Now = func() time.Time {
+				return time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+			}
+
+			t.Run("should warn about runs skipped by a daylight saving time change", func(t *testing.T) {
+				tempSpec := spec
+				tempSpec.Schedule.Interval = "30 2 * * *"
+				tempSpec.Schedule.Timezone = "America/New_York"
+				dag, err := com.Compile(namespaceSpec, tempSpec)
+
+				assert.Nil(t, err)
+				assert.Equal(t, []string{
+					"schedule 30 2 * * * of job foo doesn't run at 2021-03-14 02:30 as the daylight saving time change of America/New_York skips it",
+				}, dag.Warnings)
+			})
+			t.Run("should warn about runs repeated by a daylight saving time change", func(t *testing.T) {
+				tempSpec := spec
+				tempSpec.Schedule.Interval = "30 1 * * *"
+				tempSpec.Schedule.Timezone = "America/New_York"
+				dag, err := com.Compile(namespaceSpec, tempSpec)
+
+				assert.Nil(t, err)
+				assert.Equal(t, []string{
+					"schedule 30 1 * * * of job foo runs once at the first 2021-11-07 01:30 as the daylight saving time change of America/New_York repeats it",
+				}, dag.Warnings)
+			})
+			t.Run("should not warn about runs away from daylight saving time changes", func(t *testing.T) {
+				tempSpec := spec
+				tempSpec.Schedule.Interval = "0 5 * * *"
+				tempSpec.Schedule.Timezone = "America/New_York"
+				dag, err := com.Compile(namespaceSpec, tempSpec)
+
+				assert.Nil(t, err)
+				assert.Empty(t, dag.Warnings)
+			})
+			t.Run("should not warn about schedules in utc", func(t *testing.T) {
+				tempSpec := spec
+				tempSpec.Schedule.Interval = "30 2 * * *"
+				dag, err := com.Compile(namespaceSpec, tempSpec)
+
+				assert.Nil(t, err)
+				assert.Empty(t, dag.Warnings)
+			})
+		})
 	})
 }
@@ -0,0 +1,69 @@
+package job
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/odpf/optimus/models"
+)
+
+// CompilationValidationError is returned when the compiled job doesn't
+// satisfy the scheduler, e.g. when a template renders an empty field
+type CompilationValidationError struct {
+	JobName string
+	Reasons []string
+}
+
+func (e *CompilationValidationError) Error() string {
+	return fmt.Sprintf("compiled job %s is invalid: %s", e.JobName, strings.Join(e.Reasons, ", "))
+}
+
+// CompiledJobValidator checks the contents of a compiled job before it is
+// deployed, it should return a CompilationValidationError on failure
+type CompiledJobValidator func(jobSpec models.JobSpec, contents []byte) error
+
+var (
+	airflowDagIDPattern            = regexp.MustCompile(`\bdag_id\s*=\s*"([^"]*)"`)
+	airflowScheduleIntervalPattern = regexp.MustCompile(`\bschedule_interval\s*=\s*("([^"]*)"|None\b)`)
+	airflowStartDatePattern        = regexp.MustCompile(`"start_date"\s*:\s*(?:pendulum\.instance\(\s*)?datetime\.strptime\(\s*"([^"]*)"`)
+)
+
+// airflowStartDateLayout is the layout start_date is rendered with in DAGs
+const airflowStartDateLayout = "2006-01-02T15:04:05"
+
+// ValidateAirflowDAG checks the compiled python DAG declares the fields
+// airflow requires to schedule it: a dag_id matching the job name, a
+// schedule_interval and a start_date
+func ValidateAirflowDAG(jobSpec models.JobSpec, contents []byte) error {
+	var reasons []string
+
+	if match := airflowDagIDPattern.FindSubmatch(contents); match == nil {
+		reasons = append(reasons, "dag_id is missing")
+	} else if string(match[1]) != jobSpec.Name {
+		reasons = append(reasons, fmt.Sprintf("dag_id %q doesn't match job name", match[1]))
+	}
+
+	if match := airflowScheduleIntervalPattern.FindSubmatch(contents); match == nil {
+		reasons = append(reasons, "schedule_interval is missing")
+	} else if string(match[1]) != "None" && strings.TrimSpace(string(match[2])) == "" {
+		reasons = append(reasons, "schedule_interval is empty")
+	}
+
+	if match := airflowStartDatePattern.FindSubmatch(contents); match == nil {
+		reasons = append(reasons, "start_date is missing")
+	} else if startDate, err := time.Parse(airflowStartDateLayout, string(match[1])); err != nil {
+		reasons = append(reasons, fmt.Sprintf("start_date %q is invalid", match[1]))
+	} else if startDate.IsZero() {
+		reasons = append(reasons, "start_date is not set")
+	}
+
+	if len(reasons) > 0 {
+		return &CompilationValidationError{
+			JobName: jobSpec.Name,
+			Reasons: reasons,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package job
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultBigQuerySlotCostPerTB is the on-demand price in USD of a TB
+	// read by bigquery, used for projects without
+	// models.ProjectBigQuerySlotCostPerTB
+	DefaultBigQuerySlotCostPerTB = 5.0
+
+	// bytesPerTB is the TB bigquery bills by
+	bytesPerTB = 1 << 40
+)
+
+var (
+	// ErrCostEstimateUnsupported is returned for jobs without a query the
+	// datastore can estimate the cost of
+	ErrCostEstimateUnsupported = errors.New("cost estimation is not supported for the job")
+)
+
+// EstimateCost estimates the cost of a run of the job from the bytes its
+// compiled query reads, found through a dry run of the query
+func (srv *Service) EstimateCost(ctx context.Context, projectSpec models.ProjectSpec, jobSpec models.JobSpec) (*models.CostEstimate, error) {
+	if srv.CostEstimator == nil {
+		return nil, ErrCostEstimateUnsupported
+	}
+	if _, err := jobSpec.Assets.GetByName(QueryAssetName); err != nil {
+		return nil, ErrCostEstimateUnsupported
+	}
+	costPerTB := DefaultBigQuerySlotCostPerTB
+	if configured, ok := projectSpec.Config[models.ProjectBigQuerySlotCostPerTB]; ok {
+		parsed, err := strconv.ParseFloat(configured, 64)
+		if err != nil || parsed < 0 {
+			return nil, errors.Errorf("invalid %s of project %s: %s", models.ProjectBigQuerySlotCostPerTB,
+				projectSpec.Name, configured)
+		}
+		costPerTB = parsed
+	}
+
+	assets, err := srv.assetCompiler(jobSpec, projectSpec, srv.Now())
+	if err != nil {
+		return nil, errors.Wrap(err, "asset compilation")
+	}
+	query, err := assets.GetByName(QueryAssetName)
+	if err != nil {
+		return nil, err
+	}
+	bytesProcessed, err := srv.CostEstimator.QueryBytesProcessed(ctx, projectSpec, query.Value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dry run the query of %s", jobSpec.Name)
+	}
+	return &models.CostEstimate{
+		TotalBytesProcessed: bytesProcessed,
+		EstimatedCostUSD:    float64(bytesProcessed) / bytesPerTB * costPerTB,
+	}, nil
+}
@@ -0,0 +1,83 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateCost(t *testing.T) {
+	ctx := context.Background()
+	dumpAssets := func(jobSpec models.JobSpec, _ models.ProjectSpec, scheduledAt time.Time) (models.JobAssets, error) {
+		return jobSpec.Assets, nil
+	}
+	query := "SELECT id, amount FROM `proj.dataset.source`"
+	jobSpec := models.JobSpec{
+		Name: "bq-job",
+		Assets: *models.JobAssets{}.New([]models.JobSpecAsset{
+			{Name: job.QueryAssetName, Value: query},
+		}),
+	}
+	newService := func(estimator models.DatastoreCostEstimator) *job.Service {
+		svc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil, nil)
+		svc.CostEstimator = estimator
+		return svc
+	}
+
+	t.Run("should estimate the cost from the bytes of a dry run", func(t *testing.T) {
+		projSpec := models.ProjectSpec{Name: "proj"}
+		estimator := new(mock.DatastoreCostEstimator)
+		defer estimator.AssertExpectations(t)
+		// half a TB
+		estimator.On("QueryBytesProcessed", ctx, projSpec, query).Return(int64(549755813888), nil)
+
+		estimate, err := newService(estimator).EstimateCost(ctx, projSpec, jobSpec)
+		assert.Nil(t, err)
+		assert.Equal(t, &models.CostEstimate{
+			TotalBytesProcessed: 549755813888,
+			EstimatedCostUSD:    2.5,
+		}, estimate)
+	})
+	t.Run("should use the cost per TB configured in the project", func(t *testing.T) {
+		projSpec := models.ProjectSpec{
+			Name:   "proj",
+			Config: map[string]string{models.ProjectBigQuerySlotCostPerTB: "6.25"},
+		}
+		estimator := new(mock.DatastoreCostEstimator)
+		defer estimator.AssertExpectations(t)
+		estimator.On("QueryBytesProcessed", ctx, projSpec, query).Return(int64(2199023255552), nil)
+
+		estimate, err := newService(estimator).EstimateCost(ctx, projSpec, jobSpec)
+		assert.Nil(t, err)
+		assert.Equal(t, 12.5, estimate.EstimatedCostUSD)
+	})
+	t.Run("should fail for an invalid cost per TB", func(t *testing.T) {
+		projSpec := models.ProjectSpec{
+			Name:   "proj",
+			Config: map[string]string{models.ProjectBigQuerySlotCostPerTB: "five"},
+		}
+		_, err := newService(new(mock.DatastoreCostEstimator)).EstimateCost(ctx, projSpec, jobSpec)
+		assert.NotNil(t, err)
+	})
+	t.Run("should fail for jobs without a query", func(t *testing.T) {
+		_, err := newService(new(mock.DatastoreCostEstimator)).EstimateCost(ctx, models.ProjectSpec{}, models.JobSpec{Name: "python-job"})
+		assert.True(t, errors.Is(err, job.ErrCostEstimateUnsupported))
+
+		_, err = newService(nil).EstimateCost(ctx, models.ProjectSpec{}, jobSpec)
+		assert.True(t, errors.Is(err, job.ErrCostEstimateUnsupported))
+	})
+	t.Run("should fail if the dry run fails", func(t *testing.T) {
+		projSpec := models.ProjectSpec{Name: "proj"}
+		estimator := new(mock.DatastoreCostEstimator)
+		estimator.On("QueryBytesProcessed", ctx, projSpec, query).Return(int64(0), errors.New("syntax error"))
+
+		_, err := newService(estimator).EstimateCost(ctx, projSpec, jobSpec)
+		assert.NotNil(t, err)
+	})
+}
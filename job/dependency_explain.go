@@ -0,0 +1,31 @@
+package job
+
+import (
+	"context"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// ErrDependencyExplanationUnsupported is returned when the dependency
+// resolver of the service can't explain its decisions
+var ErrDependencyExplanationUnsupported = errors.New("dependency resolution can't be explained")
+
+// DependencyExplainer is implemented by dependency resolvers that can
+// explain how they resolve the dependencies of a job
+type DependencyExplainer interface {
+	Explain(ctx context.Context, projectSpec models.ProjectSpec, projectJobSpecRepo store.ProjectJobSpecRepository,
+		jobSpec models.JobSpec) (*models.ResolutionTrace, error)
+}
+
+// ExplainDependencyResolution lists the declared and inferred dependencies of
+// a job and the job each of them resolves to
+func (srv *Service) ExplainDependencyResolution(ctx context.Context, projectSpec models.ProjectSpec,
+	jobSpec models.JobSpec) (*models.ResolutionTrace, error) {
+	explainer, ok := srv.dependencyResolver.(DependencyExplainer)
+	if !ok {
+		return nil, ErrDependencyExplanationUnsupported
+	}
+	return explainer.Explain(ctx, projectSpec, srv.projectJobSpecRepoFactory.New(projectSpec), jobSpec)
+}
@@ -0,0 +1,157 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+// GetDependencyGraph resolves the dependencies of all jobs of the project and
+// walks them breadth first from the root job, or from every job of the project
+// if rootJobName is empty. Jobs of other projects are added to the graph but
+// their dependencies aren't followed
+func (srv *Service) GetDependencyGraph(ctx context.Context, projectSpec models.ProjectSpec, rootJobName string,
+	depth int) (models.DependencyGraph, error) {
+	projectJobSpecRepo := srv.projectJobSpecRepoFactory.New(projectSpec)
+	jobSpecs, err := srv.GetDependencyResolvedSpecs(projectSpec, projectJobSpecRepo, nil)
+	if err != nil {
+		return models.DependencyGraph{}, err
+	}
+
+	specs := map[jobNode]models.JobSpec{}
+	var roots []jobNode
+	for _, jobSpec := range jobSpecs {
+		node := jobNode{project: projectSpec.Name, job: jobSpec.Name}
+		specs[node] = jobSpec
+		if rootJobName == "" || jobSpec.Name == rootJobName {
+			roots = append(roots, node)
+		}
+	}
+	if len(roots) == 0 {
+		if rootJobName != "" {
+			return models.DependencyGraph{}, errors.Wrapf(models.ErrNoSuchJob, "%s", rootJobName)
+		}
+		return models.DependencyGraph{}, nil
+	}
+	sort.Slice(roots, func(i, j int) bool { return roots[i].job < roots[j].job })
+
+	toGraphNode := func(node jobNode) models.DependencyGraphNode {
+		return models.DependencyGraphNode{
+			ProjectName:  node.project,
+			JobName:      node.job,
+			CrossProject: node.project != projectSpec.Name,
+		}
+	}
+	graph := models.DependencyGraph{}
+	levels := map[jobNode]int{}
+	queue := roots
+	for _, root := range roots {
+		levels[root] = 0
+		graph.Nodes = append(graph.Nodes, toGraphNode(root))
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if depth > 0 && levels[node] >= depth {
+			continue
+		}
+		spec, ok := specs[node]
+		if !ok {
+			continue
+		}
+		for depName, dep := range spec.Dependencies {
+			depNode := dependencyNode(node, depName, dep)
+			graph.Edges = append(graph.Edges, models.DependencyGraphEdge{
+				Job:        toGraphNode(node),
+				Dependency: toGraphNode(depNode),
+				Type:       dep.Type,
+			})
+			if _, seen := levels[depNode]; seen {
+				continue
+			}
+			levels[depNode] = levels[node] + 1
+			graph.Nodes = append(graph.Nodes, toGraphNode(depNode))
+			queue = append(queue, depNode)
+		}
+	}
+
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graphNodeID(graph.Nodes[i]) < graphNodeID(graph.Nodes[j])
+	})
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].Job != graph.Edges[j].Job {
+			return graphNodeID(graph.Edges[i].Job) < graphNodeID(graph.Edges[j].Job)
+		}
+		return graphNodeID(graph.Edges[i].Dependency) < graphNodeID(graph.Edges[j].Dependency)
+	})
+	return graph, nil
+}
+
+// graphNodeID names jobs by project and job so they are unique across projects
+func graphNodeID(node models.DependencyGraphNode) string {
+	return node.ProjectName + "/" + node.JobName
+}
+
+// graphNodeLabel names jobs of the project of the graph by their job name alone
+func graphNodeLabel(node models.DependencyGraphNode) string {
+	if node.CrossProject {
+		return graphNodeID(node)
+	}
+	return node.JobName
+}
+
+// RenderDependencyGraphDOT writes the graph in the GraphViz DOT language, edges
+// point from dependencies to the jobs depending on them. Jobs of the project
+// are boxes and jobs of other projects dashed ellipses
+func RenderDependencyGraphDOT(graph models.DependencyGraph) string {
+	quote := func(s string) string {
+		return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+	}
+
+	var sb strings.Builder
+	sb.WriteString("digraph dependencies {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	for _, node := range graph.Nodes {
+		attrs := "shape=box"
+		if node.CrossProject {
+			attrs = "shape=ellipse, style=dashed"
+		}
+		fmt.Fprintf(&sb, "  %s [label=%s, %s];\n", quote(graphNodeID(node)), quote(graphNodeLabel(node)), attrs)
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(&sb, "  %s -> %s;\n", quote(graphNodeID(edge.Dependency)), quote(graphNodeID(edge.Job)))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// RenderDependencyGraphMermaid writes the graph as a mermaid flowchart, edges
+// point from dependencies to the jobs depending on them. Jobs of the project
+// are rectangles and jobs of other projects rounded
+func RenderDependencyGraphMermaid(graph models.DependencyGraph) string {
+	// mermaid ids can't hold most characters of job names, nodes are
+	// numbered instead and named by their labels
+	ids := map[models.DependencyGraphNode]string{}
+	for idx, node := range graph.Nodes {
+		ids[node] = fmt.Sprintf("n%d", idx)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+	for _, node := range graph.Nodes {
+		label := strings.ReplaceAll(graphNodeLabel(node), `"`, "#quot;")
+		if node.CrossProject {
+			fmt.Fprintf(&sb, "  %s([\"%s\"])\n", ids[node], label)
+		} else {
+			fmt.Fprintf(&sb, "  %s[\"%s\"]\n", ids[node], label)
+		}
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(&sb, "  %s --> %s\n", ids[edge.Dependency], ids[edge.Job])
+	}
+	return sb.String()
+}
@@ -0,0 +1,125 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	ctx := context.Background()
+	dumpAssets := func(jobSpec models.JobSpec, _ models.ProjectSpec, scheduledAt time.Time) (models.JobAssets, error) {
+		return jobSpec.Assets, nil
+	}
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+	}
+	otherProjSpec := models.ProjectSpec{
+		Name: "other-proj",
+	}
+
+	jobC := models.JobSpec{Name: "job-c"}
+	jobB := models.JobSpec{Name: "job-b"}
+	jobA := models.JobSpec{Name: "job-a"}
+	jobX := models.JobSpec{Name: "job-x"}
+	resolvedJobB := models.JobSpec{
+		Name: "job-b",
+		Dependencies: map[string]models.JobSpecDependency{
+			"job-c": {Job: &jobC, Project: &projSpec, Type: models.JobSpecDependencyTypeIntra},
+		},
+	}
+	resolvedJobA := models.JobSpec{
+		Name: "job-a",
+		Dependencies: map[string]models.JobSpecDependency{
+			"job-b":            {Job: &resolvedJobB, Project: &projSpec, Type: models.JobSpecDependencyTypeIntra},
+			"other-proj/job-x": {Job: &jobX, Project: &otherProjSpec, Type: models.JobSpecDependencyTypeInter},
+		},
+	}
+	newService := func(t *testing.T) *job.Service {
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepo.On("GetAll").Return([]models.JobSpec{jobA, jobB, jobC}, nil)
+		t.Cleanup(func() { projectJobSpecRepo.AssertExpectations(t) })
+
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+
+		depenResolver := new(mock.DependencyResolver)
+		depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jobA, nil).Return(resolvedJobA, nil)
+		depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jobB, nil).Return(resolvedJobB, nil)
+		depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jobC, nil).Return(jobC, nil)
+		t.Cleanup(func() { depenResolver.AssertExpectations(t) })
+
+		return job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+	}
+
+	nodeA := models.DependencyGraphNode{ProjectName: "proj", JobName: "job-a"}
+	nodeB := models.DependencyGraphNode{ProjectName: "proj", JobName: "job-b"}
+	nodeC := models.DependencyGraphNode{ProjectName: "proj", JobName: "job-c"}
+	nodeX := models.DependencyGraphNode{ProjectName: "other-proj", JobName: "job-x", CrossProject: true}
+
+	t.Run("GetDependencyGraph", func(t *testing.T) {
+		t.Run("should return dependencies of all jobs of the project", func(t *testing.T) {
+			graph, err := newService(t).GetDependencyGraph(ctx, projSpec, "", 0)
+			assert.Nil(t, err)
+			assert.Equal(t, []models.DependencyGraphNode{nodeX, nodeA, nodeB, nodeC}, graph.Nodes)
+			assert.Equal(t, []models.DependencyGraphEdge{
+				{Job: nodeA, Dependency: nodeX, Type: models.JobSpecDependencyTypeInter},
+				{Job: nodeA, Dependency: nodeB, Type: models.JobSpecDependencyTypeIntra},
+				{Job: nodeB, Dependency: nodeC, Type: models.JobSpecDependencyTypeIntra},
+			}, graph.Edges)
+		})
+		t.Run("should only follow dependencies of the root job up to depth", func(t *testing.T) {
+			graph, err := newService(t).GetDependencyGraph(ctx, projSpec, "job-a", 1)
+			assert.Nil(t, err)
+			assert.Equal(t, []models.DependencyGraphNode{nodeX, nodeA, nodeB}, graph.Nodes)
+			assert.Equal(t, []models.DependencyGraphEdge{
+				{Job: nodeA, Dependency: nodeX, Type: models.JobSpecDependencyTypeInter},
+				{Job: nodeA, Dependency: nodeB, Type: models.JobSpecDependencyTypeIntra},
+			}, graph.Edges)
+		})
+		t.Run("should fail if the root job isn't in the project", func(t *testing.T) {
+			_, err := newService(t).GetDependencyGraph(ctx, projSpec, "job-z", 0)
+			assert.True(t, errors.Is(err, models.ErrNoSuchJob))
+		})
+	})
+
+	graph := models.DependencyGraph{
+		Nodes: []models.DependencyGraphNode{nodeX, nodeA, nodeB},
+		Edges: []models.DependencyGraphEdge{
+			{Job: nodeA, Dependency: nodeX, Type: models.JobSpecDependencyTypeInter},
+			{Job: nodeA, Dependency: nodeB, Type: models.JobSpecDependencyTypeIntra},
+		},
+	}
+	t.Run("RenderDependencyGraphDOT", func(t *testing.T) {
+		t.Run("should draw jobs of other projects as dashed ellipses", func(t *testing.T) {
+			expected := `digraph dependencies {
+  rankdir=LR;
+  "other-proj/job-x" [label="other-proj/job-x", shape=ellipse, style=dashed];
+  "proj/job-a" [label="job-a", shape=box];
+  "proj/job-b" [label="job-b", shape=box];
+  "other-proj/job-x" -> "proj/job-a";
+  "proj/job-b" -> "proj/job-a";
+}
+`
+			assert.Equal(t, expected, job.RenderDependencyGraphDOT(graph))
+		})
+	})
+	t.Run("RenderDependencyGraphMermaid", func(t *testing.T) {
+		t.Run("should draw jobs of other projects rounded", func(t *testing.T) {
+			expected := `graph LR
+  n0(["other-proj/job-x"])
+  n1["job-a"]
+  n2["job-b"]
+  n0 --> n1
+  n2 --> n1
+`
+			assert.Equal(t, expected, job.RenderDependencyGraphMermaid(graph))
+		})
+	})
+}
@@ -0,0 +1,77 @@
+package job
+
+import (
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNotAnUpstream is thrown when a job is pinned to a job it doesn't depend on
+	ErrNotAnUpstream = errors.New("job is not an upstream in the same project")
+)
+
+// PinDependencyVersion pins the current spec of the upstream dependencyName for
+// jobName as specVersion, the job is compiled against the pinned spec until it
+// is unpinned, even if the upstream spec is updated
+func (srv *Service) PinDependencyVersion(projectSpec models.ProjectSpec, jobName, dependencyName, specVersion string) error {
+	projectJobSpecRepo := srv.projectJobSpecRepoFactory.New(projectSpec)
+	jobSpec, _, err := projectJobSpecRepo.GetByName(jobName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve job %s", jobName)
+	}
+
+	// inferred dependencies are pinned as well, they are only known after resolution
+	resolvedSpec, err := srv.dependencyResolver.Resolve(projectSpec, projectJobSpecRepo, jobSpec, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve dependencies of %s", jobName)
+	}
+	dependency, ok := resolvedSpec.Dependencies[dependencyName]
+	if !ok || dependency.Type != models.JobSpecDependencyTypeIntra {
+		return errors.Wrapf(ErrNotAnUpstream, "%s for %s", dependencyName, jobName)
+	}
+
+	if err := srv.dependencyPinRepoFactory.New(projectSpec).Pin(jobName, dependencyName, specVersion); err != nil {
+		return errors.Wrapf(err, "failed to pin %s for %s", dependencyName, jobName)
+	}
+	return nil
+}
+
+// UnpinDependency removes the pin of dependencyName for jobName, the job is
+// compiled against the latest spec of the upstream again
+func (srv *Service) UnpinDependency(projectSpec models.ProjectSpec, jobName, dependencyName string) error {
+	if err := srv.dependencyPinRepoFactory.New(projectSpec).Delete(jobName, dependencyName); err != nil {
+		return errors.Wrapf(err, "failed to unpin %s for %s", dependencyName, jobName)
+	}
+	return nil
+}
+
+// ListDependencyPins returns the dependency pins of all jobs in the project
+func (srv *Service) ListDependencyPins(projectSpec models.ProjectSpec) ([]models.DependencyPin, error) {
+	pins, err := srv.dependencyPinRepoFactory.New(projectSpec).GetAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list dependency pins")
+	}
+	return pins, nil
+}
+
+// applyDependencyPins replaces the upstream specs of jobSpec with the pinned ones
+func applyDependencyPins(jobSpec models.JobSpec, pins []models.DependencyPin) models.JobSpec {
+	if len(pins) == 0 {
+		return jobSpec
+	}
+	dependencies := make(map[string]models.JobSpecDependency, len(jobSpec.Dependencies))
+	for name, dependency := range jobSpec.Dependencies {
+		dependencies[name] = dependency
+	}
+	for _, pin := range pins {
+		dependency, ok := dependencies[pin.DependencyName]
+		if !ok {
+			continue
+		}
+		pinnedSpec := pin.DependencySpec
+		dependency.Job = &pinnedSpec
+		dependencies[pin.DependencyName] = dependency
+	}
+	jobSpec.Dependencies = dependencies
+	return jobSpec
+}
@@ -0,0 +1,89 @@
+package job_test
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyPins(t *testing.T) {
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		Name:        "ns",
+		ProjectSpec: projSpec,
+	}
+	upstreamSpec := models.JobSpec{Name: "upstream"}
+	jobSpec := models.JobSpec{
+		Name: "downstream",
+		Dependencies: map[string]models.JobSpecDependency{
+			"upstream": {Job: &upstreamSpec, Project: &projSpec, Type: models.JobSpecDependencyTypeIntra},
+		},
+	}
+
+	t.Run("PinDependencyVersion", func(t *testing.T) {
+		t.Run("should pin an upstream of the job", func(t *testing.T) {
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetByName", jobSpec.Name).Return(jobSpec, namespaceSpec, nil)
+			defer projectJobSpecRepo.AssertExpectations(t)
+
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+			defer projJobSpecRepoFac.AssertExpectations(t)
+
+			depenResolver := new(mock.DependencyResolver)
+			depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jobSpec, nil).Return(jobSpec, nil)
+			defer depenResolver.AssertExpectations(t)
+
+			pinRepo := new(mock.DependencyPinRepository)
+			pinRepo.On("Pin", jobSpec.Name, upstreamSpec.Name, "v1").Return(nil)
+			defer pinRepo.AssertExpectations(t)
+
+			pinRepoFac := new(mock.DependencyPinRepoFactory)
+			pinRepoFac.On("New", projSpec).Return(pinRepo)
+			defer pinRepoFac.AssertExpectations(t)
+
+			jobSvc := job.NewService(nil, nil, nil, nil, depenResolver, nil, nil, projJobSpecRepoFac, nil, pinRepoFac, nil)
+			err := jobSvc.PinDependencyVersion(projSpec, jobSpec.Name, upstreamSpec.Name, "v1")
+			assert.Nil(t, err)
+		})
+		t.Run("should fail when the dependency is not an upstream of the job", func(t *testing.T) {
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetByName", jobSpec.Name).Return(jobSpec, namespaceSpec, nil)
+			defer projectJobSpecRepo.AssertExpectations(t)
+
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+			defer projJobSpecRepoFac.AssertExpectations(t)
+
+			depenResolver := new(mock.DependencyResolver)
+			depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jobSpec, nil).Return(jobSpec, nil)
+			defer depenResolver.AssertExpectations(t)
+
+			jobSvc := job.NewService(nil, nil, nil, nil, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+			err := jobSvc.PinDependencyVersion(projSpec, jobSpec.Name, "unrelated", "v1")
+			assert.True(t, errors.Is(err, job.ErrNotAnUpstream))
+		})
+	})
+	t.Run("UnpinDependency", func(t *testing.T) {
+		t.Run("should return not found when the dependency is not pinned", func(t *testing.T) {
+			pinRepo := new(mock.DependencyPinRepository)
+			pinRepo.On("Delete", jobSpec.Name, upstreamSpec.Name).Return(store.ErrResourceNotFound)
+			defer pinRepo.AssertExpectations(t)
+
+			pinRepoFac := new(mock.DependencyPinRepoFactory)
+			pinRepoFac.On("New", projSpec).Return(pinRepo)
+			defer pinRepoFac.AssertExpectations(t)
+
+			jobSvc := job.NewService(nil, nil, nil, nil, nil, nil, nil, nil, nil, pinRepoFac, nil)
+			err := jobSvc.UnpinDependency(projSpec, jobSpec.Name, upstreamSpec.Name)
+			assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+		})
+	})
+}
@@ -2,6 +2,8 @@ package job
 
 import (
 	"context"
+	"sort"
+	"strings"
 
 	"github.com/odpf/optimus/core/progress"
 	"github.com/odpf/optimus/models"
@@ -11,25 +13,74 @@ import (
 
 var (
 	ErrUnknownDependency            = errors.New("unknown local dependency")
+	ErrCyclicDependency             = errors.New("cyclic dependency")
 	UnknownRuntimeDependencyMessage = "could not find registered destination '%s' during compiling dependencies for the provided job '%s', " +
 		"please check if the source is correct, " +
 		"if it is and want this to be ignored as dependency, " +
 		"check docs how this can be done in used transformation task"
 )
 
-type dependencyResolver struct{}
+// ProjectJobLookup finds a job by its name in the named project, static
+// dependencies on jobs of other projects are resolved with it
+type ProjectJobLookup func(projectName, jobName string) (models.JobSpec, models.ProjectSpec, error)
+
+type dependencyResolver struct {
+	projectJobLookup ProjectJobLookup
+}
 
 // Resolve resolves all kind of dependencies (inter/intra project, static deps) of a given JobSpec
 func (r *dependencyResolver) Resolve(projectSpec models.ProjectSpec, projectJobSpecRepo store.ProjectJobSpecRepository,
 	jobSpec models.JobSpec, observer progress.Observer) (models.JobSpec, error) {
+	return r.resolve(context.TODO(), projectSpec, projectJobSpecRepo, jobSpec, observer, nil)
+}
+
+// Explain resolves the dependencies of jobSpec like Resolve and records why
+// each of them is included or excluded, dependencies that can't be found are
+// reported instead of failing
+func (r *dependencyResolver) Explain(ctx context.Context, projectSpec models.ProjectSpec,
+	projectJobSpecRepo store.ProjectJobSpecRepository, jobSpec models.JobSpec) (*models.ResolutionTrace, error) {
+	// resolving fills in the dependencies of the spec, which is only read here
+	dependencies := map[string]models.JobSpecDependency{}
+	for depName, dep := range jobSpec.Dependencies {
+		dependencies[depName] = dep
+	}
+	jobSpec.Dependencies = dependencies
+
+	trace := &resolutionTrace{ResolutionTrace: models.ResolutionTrace{JobName: jobSpec.Name}}
+	if _, err := r.resolve(ctx, projectSpec, projectJobSpecRepo, jobSpec, nil, trace); err != nil {
+		return nil, err
+	}
+	trace.Steps = append(trace.declaredSteps, trace.inferredSteps...)
+	return &trace.ResolutionTrace, nil
+}
+
+// resolutionTrace collects the steps of a resolution, declared dependencies
+// are resolved after inferred ones but explained first
+type resolutionTrace struct {
+	models.ResolutionTrace
+	declaredSteps []models.DependencyResolutionStep
+	inferredSteps []models.DependencyResolutionStep
+}
+
+// resolve resolves the dependencies of jobSpec, with a trace the decisions
+// are recorded in it and dependencies that can't be found are skipped
+func (r *dependencyResolver) resolve(ctx context.Context, projectSpec models.ProjectSpec,
+	projectJobSpecRepo store.ProjectJobSpecRepository, jobSpec models.JobSpec, observer progress.Observer,
+	trace *resolutionTrace) (models.JobSpec, error) {
+	var declared []string
+	for depName := range jobSpec.Dependencies {
+		declared = append(declared, depName)
+	}
+	sort.Strings(declared)
+
 	// resolve inter/intra dependencies inferred by optimus
-	jobSpec, err := r.resolveInferredDependencies(jobSpec, projectSpec, projectJobSpecRepo, observer)
+	jobSpec, err := r.resolveInferredDependencies(ctx, jobSpec, projectSpec, projectJobSpecRepo, observer, trace)
 	if err != nil {
 		return models.JobSpec{}, err
 	}
 
 	// resolve statically defined dependencies
-	jobSpec, err = r.resolveStaticDependencies(jobSpec, projectSpec, projectJobSpecRepo)
+	jobSpec, err = r.resolveStaticDependencies(jobSpec, projectSpec, projectJobSpecRepo, declared, trace)
 	if err != nil {
 		return models.JobSpec{}, err
 	}
@@ -40,15 +91,26 @@ func (r *dependencyResolver) Resolve(projectSpec models.ProjectSpec, projectJobS
 		return models.JobSpec{}, err
 	}
 
+	// dependencies of the resolved dependencies are followed as far as
+	// their specs are known, cycles through inferred dependencies of other
+	// jobs are only visible once every job of the project is resolved
+	root := jobNode{project: projectSpec.Name, job: jobSpec.Name}
+	specs := map[jobNode]models.JobSpec{}
+	collectDependencySpecs(root, jobSpec, specs)
+	if err := checkCyclicDependencies(projectSpec.Name, specs, []jobNode{root}); err != nil {
+		return models.JobSpec{}, err
+	}
+
 	return jobSpec, nil
 }
 
-func (r *dependencyResolver) resolveInferredDependencies(jobSpec models.JobSpec, projectSpec models.ProjectSpec,
-	projectJobSpecRepo store.ProjectJobSpecRepository, observer progress.Observer) (models.JobSpec, error) {
+func (r *dependencyResolver) resolveInferredDependencies(ctx context.Context, jobSpec models.JobSpec,
+	projectSpec models.ProjectSpec, projectJobSpecRepo store.ProjectJobSpecRepository, observer progress.Observer,
+	trace *resolutionTrace) (models.JobSpec, error) {
 	// get destinations of dependencies, assets should be dependent on
 	var jobDependencies []string
-	if jobSpec.Task.Unit.DependencyMod != nil {
-		resp, err := jobSpec.Task.Unit.DependencyMod.GenerateDependencies(context.TODO(), models.GenerateDependenciesRequest{
+	if jobSpec.Task.Unit != nil && jobSpec.Task.Unit.DependencyMod != nil {
+		resp, err := jobSpec.Task.Unit.DependencyMod.GenerateDependencies(ctx, models.GenerateDependenciesRequest{
 			Config:  models.PluginConfigs{}.FromJobSpec(jobSpec.Task.Config),
 			Assets:  models.PluginAssets{}.FromJobSpec(jobSpec.Assets),
 			Project: projectSpec,
@@ -57,15 +119,25 @@ func (r *dependencyResolver) resolveInferredDependencies(jobSpec models.JobSpec,
 			return models.JobSpec{}, err
 		}
 		jobDependencies = resp.Dependencies
+		if trace != nil {
+			trace.InferenceEnabled = true
+			trace.InferredDependencies = jobDependencies
+		}
 	}
 
 	// get job spec of these destinations and append to current jobSpec
 	for _, depDestination := range jobDependencies {
+		step := models.DependencyResolutionStep{
+			Source:     models.DependencySourceInferred,
+			Dependency: depDestination,
+			Reason:     models.DependencyReasonNotFound,
+		}
 		depSpec, depProj, err := projectJobSpecRepo.GetByDestination(depDestination)
 		if err != nil {
 			if err == store.ErrResourceNotFound {
 				// should not fail for unknown dependency
 				r.notifyProgress(observer, &EventJobSpecUnknownDependencyUsed{Job: jobSpec.Name, Dependency: depDestination})
+				r.traceStep(trace, step)
 				continue
 			}
 			return jobSpec, errors.Wrap(err, "runtime dependency evaluation failed")
@@ -74,12 +146,36 @@ func (r *dependencyResolver) resolveInferredDependencies(jobSpec models.JobSpec,
 		// determine the type of dependency
 		dep := models.JobSpecDependency{Job: &depSpec, Project: &depProj}
 		dep.Type = r.getJobSpecDependencyType(dep, projectSpec.Name)
+		if dep.Type == models.JobSpecDependencyTypeInter {
+			dep.ProjectName = depProj.Name
+		}
 		jobSpec.Dependencies[depSpec.Name] = dep
+
+		step.JobName = depSpec.Name
+		step.ProjectName = depProj.Name
+		step.Type = dep.Type
+		step.Included = true
+		step.Reason = models.DependencyReasonDestinationMatch
+		r.traceStep(trace, step)
 	}
 
 	return jobSpec, nil
 }
 
+// traceStep records step with the steps of its source if the resolution is
+// traced
+func (r *dependencyResolver) traceStep(trace *resolutionTrace, step models.DependencyResolutionStep) {
+	if trace == nil {
+		return
+	}
+	if step.Source == models.DependencySourceDeclared {
+		trace.DeclaredDependencies = append(trace.DeclaredDependencies, step.Dependency)
+		trace.declaredSteps = append(trace.declaredSteps, step)
+		return
+	}
+	trace.inferredSteps = append(trace.inferredSteps, step)
+}
+
 func (r *dependencyResolver) getJobSpecDependencyType(dependency models.JobSpecDependency, currentJobSpecProject string) models.JobSpecDependencyType {
 	if dependency.Project.Name == currentJobSpecProject {
 		return models.JobSpecDependencyTypeIntra
@@ -88,19 +184,83 @@ func (r *dependencyResolver) getJobSpecDependencyType(dependency models.JobSpecD
 }
 
 // update named (explicit/static) dependencies if unresolved with its spec model
-// this can normally happen when reading specs from a store[local/postgres]
-func (r *dependencyResolver) resolveStaticDependencies(jobSpec models.JobSpec, projectSpec models.ProjectSpec, projectJobSpecRepo store.ProjectJobSpecRepository) (models.JobSpec, error) {
+// this can normally happen when reading specs from a store[local/postgres],
+// declared are the names of the dependencies set in the spec
+func (r *dependencyResolver) resolveStaticDependencies(jobSpec models.JobSpec, projectSpec models.ProjectSpec,
+	projectJobSpecRepo store.ProjectJobSpecRepository, declared []string, trace *resolutionTrace) (models.JobSpec, error) {
 	// update static dependencies if unresolved with its spec model
-	for depName, depSpec := range jobSpec.Dependencies {
-		if depSpec.Job == nil {
-			job, _, err := projectJobSpecRepo.GetByName(depName)
+	for _, depName := range declared {
+		depSpec := jobSpec.Dependencies[depName]
+		step := models.DependencyResolutionStep{
+			Source:     models.DependencySourceDeclared,
+			Dependency: depName,
+			Reason:     models.DependencyReasonNotFound,
+		}
+		if depSpec.ProjectName != "" && depSpec.ProjectName != projectSpec.Name {
+			step.Dependency = depSpec.ProjectName + "/" + depName
+		}
+		if depSpec.Job != nil {
+			step.JobName = depSpec.Job.Name
+			step.ProjectName = projectSpec.Name
+			step.Type = models.JobSpecDependencyTypeIntra
+			step.Reason = models.DependencyReasonExactMatch
+			if depSpec.Project != nil && depSpec.Project.Name != projectSpec.Name {
+				step.ProjectName = depSpec.Project.Name
+				step.Type = models.JobSpecDependencyTypeInter
+				step.Reason = models.DependencyReasonCrossProjectMatch
+			}
+			step.Included = true
+			r.traceStep(trace, step)
+			continue
+		}
+		if depSpec.ProjectName != "" && depSpec.ProjectName != projectSpec.Name {
+			if r.projectJobLookup == nil {
+				if trace != nil {
+					r.traceStep(trace, step)
+					continue
+				}
+				return models.JobSpec{}, errors.Errorf("%s for job %s of project %s", ErrUnknownDependency, depName, depSpec.ProjectName)
+			}
+			job, depProject, err := r.projectJobLookup(depSpec.ProjectName, depName)
 			if err != nil {
-				return models.JobSpec{}, errors.Wrapf(err, "%s for job %s", ErrUnknownDependency, depName)
+				if trace != nil && errors.Is(err, store.ErrResourceNotFound) {
+					r.traceStep(trace, step)
+					continue
+				}
+				return models.JobSpec{}, errors.Wrapf(err, "%s for job %s of project %s", ErrUnknownDependency, depName, depSpec.ProjectName)
 			}
 			depSpec.Job = &job
-			depSpec.Project = &projectSpec
+			depSpec.Project = &depProject
+			depSpec.Type = models.JobSpecDependencyTypeInter
 			jobSpec.Dependencies[depName] = depSpec
+
+			step.JobName = job.Name
+			step.ProjectName = depProject.Name
+			step.Type = models.JobSpecDependencyTypeInter
+			step.Included = true
+			step.Reason = models.DependencyReasonCrossProjectMatch
+			r.traceStep(trace, step)
+			continue
+		}
+
+		job, _, err := projectJobSpecRepo.GetByName(depName)
+		if err != nil {
+			if trace != nil && errors.Is(err, store.ErrResourceNotFound) {
+				r.traceStep(trace, step)
+				continue
+			}
+			return models.JobSpec{}, errors.Wrapf(err, "%s for job %s", ErrUnknownDependency, depName)
 		}
+		depSpec.Job = &job
+		depSpec.Project = &projectSpec
+		jobSpec.Dependencies[depName] = depSpec
+
+		step.JobName = job.Name
+		step.ProjectName = projectSpec.Name
+		step.Type = models.JobSpecDependencyTypeIntra
+		step.Included = true
+		step.Reason = models.DependencyReasonExactMatch
+		r.traceStep(trace, step)
 	}
 
 	return jobSpec, nil
@@ -129,7 +289,116 @@ func (r *dependencyResolver) notifyProgress(observer progress.Observer, e progre
 	observer.Notify(e)
 }
 
-// NewDependencyResolver creates a new instance of Resolver
-func NewDependencyResolver() *dependencyResolver {
-	return &dependencyResolver{}
+// jobNode identifies a job in the dependency graph, which spans projects
+type jobNode struct {
+	project string
+	job     string
+}
+
+// dependencyNode is the node of dep, a dependency of the job of node
+func dependencyNode(node jobNode, depName string, dep models.JobSpecDependency) jobNode {
+	depNode := jobNode{project: node.project, job: depName}
+	if dep.Job != nil {
+		depNode.job = dep.Job.Name
+	}
+	if dep.Project != nil {
+		depNode.project = dep.Project.Name
+	} else if dep.ProjectName != "" {
+		depNode.project = dep.ProjectName
+	}
+	return depNode
+}
+
+// collectDependencySpecs adds jobSpec and every spec reachable through its
+// dependencies to specs, specs already present are kept as they are
+func collectDependencySpecs(node jobNode, jobSpec models.JobSpec, specs map[jobNode]models.JobSpec) {
+	if _, ok := specs[node]; ok {
+		return
+	}
+	specs[node] = jobSpec
+	for depName, dep := range jobSpec.Dependencies {
+		if dep.Job != nil {
+			collectDependencySpecs(dependencyNode(node, depName, dep), *dep.Job, specs)
+		}
+	}
+}
+
+// checkCyclicDependencies runs a depth first search on specs starting with
+// roots and returns ErrCyclicDependency with the path of the first cycle
+// found, e.g. job-a -> job-b -> job-a. Jobs outside of projectName are
+// named with their project, e.g. other-project/job-c. Dependencies missing
+// in specs are treated as leaves
+func checkCyclicDependencies(projectName string, specs map[jobNode]models.JobSpec, roots []jobNode) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[jobNode]int{}
+	var path []jobNode
+
+	var visit func(node jobNode) []jobNode
+	visit = func(node jobNode) []jobNode {
+		switch state[node] {
+		case visiting:
+			for idx, pathNode := range path {
+				if pathNode == node {
+					return append(append([]jobNode{}, path[idx:]...), node)
+				}
+			}
+		case visited:
+			return nil
+		}
+
+		spec, ok := specs[node]
+		if !ok {
+			return nil
+		}
+		state[node] = visiting
+		path = append(path, node)
+
+		// map iteration is random, sort to always report the same cycle
+		var depNodes []jobNode
+		for depName, dep := range spec.Dependencies {
+			depNodes = append(depNodes, dependencyNode(node, depName, dep))
+		}
+		sort.Slice(depNodes, func(i, j int) bool {
+			if depNodes[i].project != depNodes[j].project {
+				return depNodes[i].project < depNodes[j].project
+			}
+			return depNodes[i].job < depNodes[j].job
+		})
+		for _, depNode := range depNodes {
+			if cycle := visit(depNode); cycle != nil {
+				return cycle
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[node] = visited
+		return nil
+	}
+
+	for _, root := range roots {
+		if cycle := visit(root); cycle != nil {
+			var names []string
+			for _, node := range cycle {
+				if node.project == projectName {
+					names = append(names, node.job)
+				} else {
+					names = append(names, node.project+"/"+node.job)
+				}
+			}
+			return errors.Wrapf(ErrCyclicDependency, "dependency path %s", strings.Join(names, " -> "))
+		}
+	}
+	return nil
+}
+
+// NewDependencyResolver creates a new instance of Resolver, projectJobLookup
+// can be nil if dependencies never cross projects
+func NewDependencyResolver(projectJobLookup ProjectJobLookup) *dependencyResolver {
+	return &dependencyResolver{
+		projectJobLookup: projectJobLookup,
+	}
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/odpf/optimus/job"
 	"github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -116,7 +117,7 @@ func TestDependencyResolver(t *testing.T) {
 				DependsOn: []string{"hook1"},
 			}, nil)
 
-			resolver := job.NewDependencyResolver()
+			resolver := job.NewDependencyResolver(nil)
 			resolvedJobSpec1, err := resolver.Resolve(projectSpec, jobSpecRepository, jobSpec1, nil)
 			assert.Nil(t, err)
 			resolvedJobSpec2, err := resolver.Resolve(projectSpec, jobSpecRepository, jobSpec2, nil)
@@ -208,7 +209,7 @@ func TestDependencyResolver(t *testing.T) {
 			}, nil)
 			execUnit.On("GenerateDependencies", context.TODO(), unitData2).Return(&models.GenerateDependenciesResponse{}, nil)
 
-			resolver := job.NewDependencyResolver()
+			resolver := job.NewDependencyResolver(nil)
 			resolvedJobSpec1, err := resolver.Resolve(projectSpec, jobSpecRepository, jobSpec1, nil)
 			assert.Nil(t, err)
 			resolvedJobSpec2, err := resolver.Resolve(projectSpec, jobSpecRepository, jobSpec2, nil)
@@ -272,7 +273,7 @@ func TestDependencyResolver(t *testing.T) {
 			execUnit.On("GenerateDependencies", context.Background(), unitData).Return(
 				&models.GenerateDependenciesResponse{Dependencies: []string{"project.dataset.table2_destination"}}, nil)
 
-			resolver := job.NewDependencyResolver()
+			resolver := job.NewDependencyResolver(nil)
 			resolvedJobSpec1, err := resolver.Resolve(projectSpec, jobSpecRepository, jobSpec1, nil)
 
 			assert.Error(t, errors.Wrapf(errors.New("random error"), job.UnknownRuntimeDependencyMessage,
@@ -311,7 +312,7 @@ func TestDependencyResolver(t *testing.T) {
 			unitData := models.GenerateDependenciesRequest{Config: models.PluginConfigs{}.FromJobSpec(jobSpec1.Task.Config), Assets: models.PluginAssets{}.FromJobSpec(jobSpec1.Assets), Project: projectSpec}
 			execUnit.On("GenerateDependencies", context.Background(), unitData).Return(&models.GenerateDependenciesResponse{}, errors.New("random error"))
 
-			resolver := job.NewDependencyResolver()
+			resolver := job.NewDependencyResolver(nil)
 			resolvedJobSpec1, err := resolver.Resolve(projectSpec, jobSpecRepository, jobSpec1, nil)
 
 			assert.Equal(t, "random error", err.Error())
@@ -350,7 +351,7 @@ func TestDependencyResolver(t *testing.T) {
 			execUnit.On("GenerateDependencies", context.Background(), unitData).Return(&models.GenerateDependenciesResponse{
 				Dependencies: []string{"project.dataset.table3_destination"}}, nil)
 
-			resolver := job.NewDependencyResolver()
+			resolver := job.NewDependencyResolver(nil)
 			_, err := resolver.Resolve(projectSpec, jobSpecRepository, jobSpec1, nil)
 			assert.Error(t, errors.Wrapf(errors.New("spec not found"), job.UnknownRuntimeDependencyMessage,
 				"project.dataset.table3_destination", jobSpec1.Name),
@@ -410,7 +411,7 @@ func TestDependencyResolver(t *testing.T) {
 				Dependencies: []string{"project.dataset.table1_destination"},
 			}, nil)
 
-			resolver := job.NewDependencyResolver()
+			resolver := job.NewDependencyResolver(nil)
 			_, err := resolver.Resolve(projectSpec, jobSpecRepository, jobSpec2, nil)
 			assert.Equal(t, "unknown local dependency for job static_dep: spec not found", err.Error())
 		})
@@ -497,7 +498,7 @@ func TestDependencyResolver(t *testing.T) {
 			}, nil)
 			execUnit.On("GenerateDependencies", context.Background(), unitData2).Return(&models.GenerateDependenciesResponse{}, nil)
 
-			resolver := job.NewDependencyResolver()
+			resolver := job.NewDependencyResolver(nil)
 			resolvedJobSpec1, err := resolver.Resolve(projectSpec, jobSpecRepository, jobSpec1, nil)
 			assert.Nil(t, err)
 			resolvedJobSpec2, err := resolver.Resolve(projectSpec, jobSpecRepository, jobSpec2, nil)
@@ -624,7 +625,7 @@ func TestDependencyResolver(t *testing.T) {
 			}, nil)
 			execUnit.On("GenerateDependencies", context.Background(), unitData2).Return(&models.GenerateDependenciesResponse{}, nil)
 
-			resolver := job.NewDependencyResolver()
+			resolver := job.NewDependencyResolver(nil)
 			resolvedJobSpec1, err := resolver.Resolve(projectSpec, jobSpecRepository, jobSpec1, nil)
 			assert.Nil(t, err)
 			resolvedJobSpec2, err := resolver.Resolve(projectSpec, jobSpecRepository, jobSpec2, nil)
@@ -632,9 +633,347 @@ func TestDependencyResolver(t *testing.T) {
 
 			assert.Nil(t, err)
 			assert.Equal(t, models.JobSpecDependency{Job: &jobSpec2, Project: &projectSpec, Type: models.JobSpecDependencyTypeIntra}, resolvedJobSpec1.Dependencies[jobSpec2.Name])
-			assert.Equal(t, models.JobSpecDependency{Job: &jobSpecExternal, Project: &externalProjectSpec, Type: models.JobSpecDependencyTypeInter}, resolvedJobSpec1.Dependencies[jobSpecExternal.Name])
+			assert.Equal(t, models.JobSpecDependency{Job: &jobSpecExternal, Project: &externalProjectSpec, Type: models.JobSpecDependencyTypeInter, ProjectName: externalProjectName}, resolvedJobSpec1.Dependencies[jobSpecExternal.Name])
 			assert.Equal(t, models.JobSpecDependency{Job: &jobSpec3, Project: &projectSpec, Type: models.JobSpecDependencyTypeIntra}, resolvedJobSpec1.Dependencies[jobSpec3.Name])
 			assert.Equal(t, map[string]models.JobSpecDependency{}, resolvedJobSpec2.Dependencies)
 		})
+
+		t.Run("it should fail for cyclic dependencies", func(t *testing.T) {
+			newSpec := func(name string) *models.JobSpec {
+				return &models.JobSpec{
+					Name:         name,
+					Task:         models.JobSpecTask{Unit: &models.Plugin{}},
+					Dependencies: map[string]models.JobSpecDependency{},
+				}
+			}
+			dependsOn := func(spec *models.JobSpec, deps ...*models.JobSpec) {
+				for _, dep := range deps {
+					spec.Dependencies[dep.Name] = models.JobSpecDependency{Job: dep, Project: &projectSpec, Type: models.JobSpecDependencyTypeIntra}
+				}
+			}
+
+			testCases := []struct {
+				name      string
+				buildSpec func() *models.JobSpec
+				cycle     string
+			}{
+				{
+					name: "direct",
+					buildSpec: func() *models.JobSpec {
+						jobA, jobB := newSpec("job-a"), newSpec("job-b")
+						dependsOn(jobA, jobB)
+						dependsOn(jobB, jobA)
+						return jobA
+					},
+					cycle: "job-a -> job-b -> job-a",
+				},
+				{
+					name: "indirect",
+					buildSpec: func() *models.JobSpec {
+						jobA, jobB, jobC, jobD := newSpec("job-a"), newSpec("job-b"), newSpec("job-c"), newSpec("job-d")
+						dependsOn(jobA, jobB, jobD)
+						dependsOn(jobB, jobC)
+						dependsOn(jobC, jobA)
+						return jobA
+					},
+					cycle: "job-a -> job-b -> job-c -> job-a",
+				},
+				{
+					name: "through a dependency",
+					buildSpec: func() *models.JobSpec {
+						jobA, jobB, jobC := newSpec("job-a"), newSpec("job-b"), newSpec("job-c")
+						dependsOn(jobA, jobB)
+						dependsOn(jobB, jobC)
+						dependsOn(jobC, jobB)
+						return jobA
+					},
+					cycle: "job-b -> job-c -> job-b",
+				},
+				{
+					name: "self referential",
+					buildSpec: func() *models.JobSpec {
+						jobA := newSpec("job-a")
+						dependsOn(jobA, jobA)
+						return jobA
+					},
+					cycle: "job-a -> job-a",
+				},
+			}
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					jobSpecRepository := new(mock.ProjectJobSpecRepository)
+					defer jobSpecRepository.AssertExpectations(t)
+
+					resolver := job.NewDependencyResolver(nil)
+					_, err := resolver.Resolve(projectSpec, jobSpecRepository, *tc.buildSpec(), nil)
+					assert.True(t, errors.Is(err, job.ErrCyclicDependency))
+					assert.Equal(t, "dependency path "+tc.cycle+": cyclic dependency", err.Error())
+				})
+			}
+		})
+
+		t.Run("it should resolve dependencies shared by multiple jobs", func(t *testing.T) {
+			jobC := models.JobSpec{Name: "job-c", Dependencies: map[string]models.JobSpecDependency{}}
+			jobB := models.JobSpec{Name: "job-b", Dependencies: map[string]models.JobSpecDependency{
+				jobC.Name: {Job: &jobC, Project: &projectSpec, Type: models.JobSpecDependencyTypeIntra},
+			}}
+			jobA := models.JobSpec{
+				Name: "job-a",
+				Task: models.JobSpecTask{Unit: &models.Plugin{}},
+				Dependencies: map[string]models.JobSpecDependency{
+					jobB.Name: {Job: &jobB, Project: &projectSpec, Type: models.JobSpecDependencyTypeIntra},
+					jobC.Name: {Job: &jobC, Project: &projectSpec, Type: models.JobSpecDependencyTypeIntra},
+				},
+			}
+
+			jobSpecRepository := new(mock.ProjectJobSpecRepository)
+			defer jobSpecRepository.AssertExpectations(t)
+
+			resolver := job.NewDependencyResolver(nil)
+			resolvedJobSpec, err := resolver.Resolve(projectSpec, jobSpecRepository, jobA, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, jobA, resolvedJobSpec)
+		})
+
+		t.Run("it should resolve static dependencies on jobs of other projects", func(t *testing.T) {
+			externalProjectSpec := models.ProjectSpec{Name: "an-external-data-project"}
+			externalJob := models.JobSpec{Name: "external-job", Dependencies: map[string]models.JobSpecDependency{}}
+			jobA := models.JobSpec{
+				Name: "job-a",
+				Task: models.JobSpecTask{Unit: &models.Plugin{}},
+				Dependencies: map[string]models.JobSpecDependency{
+					externalJob.Name: {ProjectName: externalProjectSpec.Name},
+				},
+			}
+
+			jobSpecRepository := new(mock.ProjectJobSpecRepository)
+			defer jobSpecRepository.AssertExpectations(t)
+
+			var lookups []string
+			resolver := job.NewDependencyResolver(func(projectName, jobName string) (models.JobSpec, models.ProjectSpec, error) {
+				lookups = append(lookups, projectName+"/"+jobName)
+				return externalJob, externalProjectSpec, nil
+			})
+			resolvedJobSpec, err := resolver.Resolve(projectSpec, jobSpecRepository, jobA, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"an-external-data-project/external-job"}, lookups)
+			assert.Equal(t, models.JobSpecDependency{
+				Job:         &externalJob,
+				Project:     &externalProjectSpec,
+				Type:        models.JobSpecDependencyTypeInter,
+				ProjectName: externalProjectSpec.Name,
+			}, resolvedJobSpec.Dependencies[externalJob.Name])
+		})
+
+		t.Run("it should fail for static dependencies on jobs of other projects without a lookup", func(t *testing.T) {
+			jobA := models.JobSpec{
+				Name: "job-a",
+				Task: models.JobSpecTask{Unit: &models.Plugin{}},
+				Dependencies: map[string]models.JobSpecDependency{
+					"external-job": {ProjectName: "an-external-data-project"},
+				},
+			}
+
+			jobSpecRepository := new(mock.ProjectJobSpecRepository)
+			defer jobSpecRepository.AssertExpectations(t)
+
+			resolver := job.NewDependencyResolver(nil)
+			_, err := resolver.Resolve(projectSpec, jobSpecRepository, jobA, nil)
+			assert.Equal(t, "unknown local dependency for job external-job of project an-external-data-project", err.Error())
+		})
+
+		t.Run("it should fail if the job of another project can't be found", func(t *testing.T) {
+			jobA := models.JobSpec{
+				Name: "job-a",
+				Task: models.JobSpecTask{Unit: &models.Plugin{}},
+				Dependencies: map[string]models.JobSpecDependency{
+					"external-job": {ProjectName: "an-external-data-project"},
+				},
+			}
+
+			jobSpecRepository := new(mock.ProjectJobSpecRepository)
+			defer jobSpecRepository.AssertExpectations(t)
+
+			resolver := job.NewDependencyResolver(func(projectName, jobName string) (models.JobSpec, models.ProjectSpec, error) {
+				return models.JobSpec{}, models.ProjectSpec{}, errors.New("spec not found")
+			})
+			_, err := resolver.Resolve(projectSpec, jobSpecRepository, jobA, nil)
+			assert.Equal(t, "unknown local dependency for job external-job of project an-external-data-project: spec not found", err.Error())
+		})
+
+		t.Run("it should tell jobs of the same name in different projects apart", func(t *testing.T) {
+			externalProjectSpec := models.ProjectSpec{Name: "an-external-data-project"}
+			externalJobA := models.JobSpec{Name: "job-a", Dependencies: map[string]models.JobSpecDependency{}}
+			jobA := models.JobSpec{
+				Name: "job-a",
+				Task: models.JobSpecTask{Unit: &models.Plugin{}},
+				Dependencies: map[string]models.JobSpecDependency{
+					externalJobA.Name: {Job: &externalJobA, Project: &externalProjectSpec, Type: models.JobSpecDependencyTypeInter,
+						ProjectName: externalProjectSpec.Name},
+				},
+			}
+
+			jobSpecRepository := new(mock.ProjectJobSpecRepository)
+			defer jobSpecRepository.AssertExpectations(t)
+
+			resolver := job.NewDependencyResolver(nil)
+			resolvedJobSpec, err := resolver.Resolve(projectSpec, jobSpecRepository, jobA, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, jobA, resolvedJobSpec)
+		})
+
+		t.Run("it should fail for cyclic dependencies across projects", func(t *testing.T) {
+			externalProjectSpec := models.ProjectSpec{Name: "an-external-data-project"}
+			jobA := models.JobSpec{
+				Name:         "job-a",
+				Task:         models.JobSpecTask{Unit: &models.Plugin{}},
+				Dependencies: map[string]models.JobSpecDependency{},
+			}
+			externalJobB := models.JobSpec{Name: "job-b", Dependencies: map[string]models.JobSpecDependency{
+				jobA.Name: {Job: &jobA, Project: &projectSpec, Type: models.JobSpecDependencyTypeInter, ProjectName: projectSpec.Name},
+			}}
+			jobA.Dependencies[externalJobB.Name] = models.JobSpecDependency{Job: &externalJobB, Project: &externalProjectSpec,
+				Type: models.JobSpecDependencyTypeInter, ProjectName: externalProjectSpec.Name}
+
+			jobSpecRepository := new(mock.ProjectJobSpecRepository)
+			defer jobSpecRepository.AssertExpectations(t)
+
+			resolver := job.NewDependencyResolver(nil)
+			_, err := resolver.Resolve(projectSpec, jobSpecRepository, jobA, nil)
+			assert.True(t, errors.Is(err, job.ErrCyclicDependency))
+			assert.Equal(t, "dependency path job-a -> an-external-data-project/job-b -> job-a: cyclic dependency", err.Error())
+		})
+	})
+}
+
+func TestDependencyResolverExplain(t *testing.T) {
+	ctx := context.Background()
+	projectSpec := models.ProjectSpec{Name: "a-data-project"}
+	externalProjectSpec := models.ProjectSpec{Name: "an-external-data-project"}
+
+	t.Run("should explain how declared and inferred dependencies are resolved", func(t *testing.T) {
+		execUnit := new(mock.DependencyResolverMod)
+		defer execUnit.AssertExpectations(t)
+		jobSpec := models.JobSpec{
+			Name: "test1",
+			Task: models.JobSpecTask{
+				Unit:   &models.Plugin{DependencyMod: execUnit},
+				Config: models.JobSpecConfigs{{Name: "foo", Value: "bar"}},
+			},
+			Dependencies: map[string]models.JobSpecDependency{
+				"test2":   {Type: models.JobSpecDependencyTypeIntra},
+				"removed": {Type: models.JobSpecDependencyTypeIntra},
+				"test3":   {Type: models.JobSpecDependencyTypeInter, ProjectName: externalProjectSpec.Name},
+			},
+		}
+		execUnit.On("GenerateDependencies", ctx, models.GenerateDependenciesRequest{
+			Config:  models.PluginConfigs{}.FromJobSpec(jobSpec.Task.Config),
+			Assets:  models.PluginAssets{}.FromJobSpec(jobSpec.Assets),
+			Project: projectSpec,
+		}).Return(&models.GenerateDependenciesResponse{Dependencies: []string{
+			"project.dataset.table4", "project.dataset.unknown",
+		}}, nil)
+
+		jobSpecRepository := new(mock.ProjectJobSpecRepository)
+		jobSpecRepository.On("GetByName", "test2").Return(models.JobSpec{Name: "test2"}, models.NamespaceSpec{}, nil)
+		jobSpecRepository.On("GetByName", "removed").Return(nil, store.ErrResourceNotFound)
+		jobSpecRepository.On("GetByDestination", "project.dataset.table4").Return(models.JobSpec{Name: "test4"}, externalProjectSpec, nil)
+		jobSpecRepository.On("GetByDestination", "project.dataset.unknown").Return(nil, nil, store.ErrResourceNotFound)
+		defer jobSpecRepository.AssertExpectations(t)
+
+		lookup := func(projectName, jobName string) (models.JobSpec, models.ProjectSpec, error) {
+			assert.Equal(t, externalProjectSpec.Name, projectName)
+			return models.JobSpec{Name: jobName}, externalProjectSpec, nil
+		}
+		trace, err := job.NewDependencyResolver(lookup).Explain(ctx, projectSpec, jobSpecRepository, jobSpec)
+		assert.Nil(t, err)
+		assert.Equal(t, &models.ResolutionTrace{
+			JobName:              "test1",
+			DeclaredDependencies: []string{"removed", "test2", "an-external-data-project/test3"},
+			InferenceEnabled:     true,
+			InferredDependencies: []string{"project.dataset.table4", "project.dataset.unknown"},
+			Steps: []models.DependencyResolutionStep{
+				{
+					Source:     models.DependencySourceDeclared,
+					Dependency: "removed",
+					Reason:     models.DependencyReasonNotFound,
+				},
+				{
+					Source:      models.DependencySourceDeclared,
+					Dependency:  "test2",
+					JobName:     "test2",
+					ProjectName: projectSpec.Name,
+					Type:        models.JobSpecDependencyTypeIntra,
+					Included:    true,
+					Reason:      models.DependencyReasonExactMatch,
+				},
+				{
+					Source:      models.DependencySourceDeclared,
+					Dependency:  "an-external-data-project/test3",
+					JobName:     "test3",
+					ProjectName: externalProjectSpec.Name,
+					Type:        models.JobSpecDependencyTypeInter,
+					Included:    true,
+					Reason:      models.DependencyReasonCrossProjectMatch,
+				},
+				{
+					Source:      models.DependencySourceInferred,
+					Dependency:  "project.dataset.table4",
+					JobName:     "test4",
+					ProjectName: externalProjectSpec.Name,
+					Type:        models.JobSpecDependencyTypeInter,
+					Included:    true,
+					Reason:      models.DependencyReasonDestinationMatch,
+				},
+				{
+					Source:     models.DependencySourceInferred,
+					Dependency: "project.dataset.unknown",
+					Reason:     models.DependencyReasonNotFound,
+				},
+			},
+		}, trace)
+	})
+	t.Run("should not infer dependencies of tasks without a dependency mod", func(t *testing.T) {
+		jobSpec := models.JobSpec{
+			Name: "test1",
+			Task: models.JobSpecTask{
+				Unit: &models.Plugin{Base: new(mock.BasePlugin)},
+			},
+		}
+		trace, err := job.NewDependencyResolver(nil).Explain(ctx, projectSpec, new(mock.ProjectJobSpecRepository), jobSpec)
+		assert.Nil(t, err)
+		assert.False(t, trace.InferenceEnabled)
+		assert.Empty(t, trace.Steps)
+	})
+	t.Run("should report dependencies on other projects without a lookup and leave the spec unresolved", func(t *testing.T) {
+		jobSpec := models.JobSpec{
+			Name: "test1",
+			Dependencies: map[string]models.JobSpecDependency{
+				"test3": {Type: models.JobSpecDependencyTypeInter, ProjectName: externalProjectSpec.Name},
+			},
+		}
+		trace, err := job.NewDependencyResolver(nil).Explain(ctx, projectSpec, new(mock.ProjectJobSpecRepository), jobSpec)
+		assert.Nil(t, err)
+		assert.Equal(t, []models.DependencyResolutionStep{
+			{
+				Source:     models.DependencySourceDeclared,
+				Dependency: "an-external-data-project/test3",
+				Reason:     models.DependencyReasonNotFound,
+			},
+		}, trace.Steps)
+		assert.Nil(t, jobSpec.Dependencies["test3"].Job)
+	})
+	t.Run("should fail if dependencies can't be looked up", func(t *testing.T) {
+		jobSpec := models.JobSpec{
+			Name: "test1",
+			Dependencies: map[string]models.JobSpecDependency{
+				"test2": {Type: models.JobSpecDependencyTypeIntra},
+			},
+		}
+		jobSpecRepository := new(mock.ProjectJobSpecRepository)
+		jobSpecRepository.On("GetByName", "test2").Return(nil, errors.New("db down"))
+
+		_, err := job.NewDependencyResolver(nil).Explain(ctx, projectSpec, jobSpecRepository, jobSpec)
+		assert.Contains(t, err.Error(), "unknown local dependency for job test2: db down")
 	})
 }
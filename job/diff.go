@@ -0,0 +1,210 @@
+package job
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// DiffJobSpec compares two versions of a job spec archived on each change,
+// models.JobSpecVersionCurrent compares the registered spec
+func (srv *Service) DiffJobSpec(projectSpec models.ProjectSpec, jobName string, fromVersion, toVersion int) (models.JobSpecDiff, error) {
+	projectJobSpecRepo := srv.projectJobSpecRepoFactory.New(projectSpec)
+	from, err := getJobSpecVersion(projectJobSpecRepo, jobName, fromVersion)
+	if err != nil {
+		return models.JobSpecDiff{}, err
+	}
+	to, err := getJobSpecVersion(projectJobSpecRepo, jobName, toVersion)
+	if err != nil {
+		return models.JobSpecDiff{}, err
+	}
+	return diffJobSpecs(from, to), nil
+}
+
+func getJobSpecVersion(projectJobSpecRepo store.ProjectJobSpecRepository, jobName string, version int) (models.JobSpec, error) {
+	if version == models.JobSpecVersionCurrent {
+		jobSpec, _, err := projectJobSpecRepo.GetByName(jobName)
+		if err != nil {
+			return models.JobSpec{}, errors.Wrapf(err, "failed to find job %s", jobName)
+		}
+		return jobSpec, nil
+	}
+	jobSpecVersion, err := projectJobSpecRepo.GetVersion(jobName, version)
+	if err != nil {
+		return models.JobSpec{}, errors.Wrapf(err, "failed to find version %d of job %s", version, jobName)
+	}
+	return jobSpecVersion.Spec, nil
+}
+
+func diffJobSpecs(from, to models.JobSpec) models.JobSpecDiff {
+	var diff models.JobSpecDiff
+
+	diff.Metadata = diffValue(diff.Metadata, "owner", from.Owner, to.Owner)
+	diff.Metadata = diffValue(diff.Metadata, "description", from.Description, to.Description)
+	diff.Metadata = append(diff.Metadata, diffValues("labels.", from.Labels, to.Labels)...)
+	diff.Metadata = append(diff.Metadata, diffValues("dependencies.", dependencyTypes(from), dependencyTypes(to))...)
+
+	diff.Schedule = diffValue(diff.Schedule, "start_date", formatDate(&from.Schedule.StartDate), formatDate(&to.Schedule.StartDate))
+	diff.Schedule = diffValue(diff.Schedule, "end_date", formatDate(from.Schedule.EndDate), formatDate(to.Schedule.EndDate))
+	diff.Schedule = diffValue(diff.Schedule, "interval", from.Schedule.Interval, to.Schedule.Interval)
+	diff.Schedule = diffValue(diff.Schedule, "timezone", from.Schedule.Timezone, to.Schedule.Timezone)
+
+	diff.Task = diffValue(diff.Task, "name", pluginName(from.Task.Unit), pluginName(to.Task.Unit))
+	diff.Task = diffValue(diff.Task, "priority", strconv.Itoa(from.Task.Priority), strconv.Itoa(to.Task.Priority))
+	diff.Task = diffValue(diff.Task, "window.size", from.Task.Window.Size.String(), to.Task.Window.Size.String())
+	diff.Task = diffValue(diff.Task, "window.offset", from.Task.Window.Offset.String(), to.Task.Window.Offset.String())
+	diff.Task = diffValue(diff.Task, "window.truncate_to", from.Task.Window.TruncateTo, to.Task.Window.TruncateTo)
+	diff.Task = diffValue(diff.Task, "resources.cpu_request", from.Task.Resources.CPURequest, to.Task.Resources.CPURequest)
+	diff.Task = diffValue(diff.Task, "resources.cpu_limit", from.Task.Resources.CPULimit, to.Task.Resources.CPULimit)
+	diff.Task = diffValue(diff.Task, "resources.memory_request", from.Task.Resources.MemoryRequest, to.Task.Resources.MemoryRequest)
+	diff.Task = diffValue(diff.Task, "resources.memory_limit", from.Task.Resources.MemoryLimit, to.Task.Resources.MemoryLimit)
+
+	diff.Config = diffValues("", configValues(from.Task.Config), configValues(to.Task.Config))
+	diff.Assets = diffValues("", assetValues(from.Assets), assetValues(to.Assets))
+
+	fromHooks, toHooks := hookConfigs(from.Hooks), hookConfigs(to.Hooks)
+	hookNames := map[string]bool{}
+	for name := range fromHooks {
+		hookNames[name] = true
+	}
+	for name := range toHooks {
+		hookNames[name] = true
+	}
+	for _, name := range sortedNames(hookNames) {
+		fromConfig, inFrom := fromHooks[name]
+		toConfig, inTo := toHooks[name]
+		switch {
+		case !inFrom:
+			diff.Hooks = append(diff.Hooks, models.JobSpecHookDiff{Name: name, Type: models.JobSpecChangeAdded})
+		case !inTo:
+			diff.Hooks = append(diff.Hooks, models.JobSpecHookDiff{Name: name, Type: models.JobSpecChangeRemoved})
+		default:
+			if configChanges := diffValues("", fromConfig, toConfig); len(configChanges) > 0 {
+				diff.Hooks = append(diff.Hooks, models.JobSpecHookDiff{
+					Name:   name,
+					Type:   models.JobSpecChangeModified,
+					Config: configChanges,
+				})
+			}
+		}
+	}
+
+	diff.Behavior = diffValue(diff.Behavior, "depends_on_past",
+		strconv.FormatBool(from.Behavior.DependsOnPast), strconv.FormatBool(to.Behavior.DependsOnPast))
+	diff.Behavior = diffValue(diff.Behavior, "catch_up",
+		strconv.FormatBool(from.Behavior.CatchUp), strconv.FormatBool(to.Behavior.CatchUp))
+	diff.Behavior = diffValue(diff.Behavior, "retry.count",
+		strconv.Itoa(from.Behavior.Retry.Count), strconv.Itoa(to.Behavior.Retry.Count))
+	diff.Behavior = diffValue(diff.Behavior, "retry.delay",
+		from.Behavior.Retry.Delay.String(), to.Behavior.Retry.Delay.String())
+	diff.Behavior = diffValue(diff.Behavior, "retry.exponential_backoff",
+		strconv.FormatBool(from.Behavior.Retry.ExponentialBackoff), strconv.FormatBool(to.Behavior.Retry.ExponentialBackoff))
+	return diff
+}
+
+// diffValue appends a change of a field present in both versions
+func diffValue(changes []models.JobSpecChange, name, from, to string) []models.JobSpecChange {
+	if from == to {
+		return changes
+	}
+	return append(changes, models.JobSpecChange{
+		Name: name,
+		Type: models.JobSpecChangeModified,
+		From: from,
+		To:   to,
+	})
+}
+
+// diffValues compares keyed values, the changes are named by their key
+// after prefix and ordered by it
+func diffValues(prefix string, from, to map[string]string) []models.JobSpecChange {
+	keys := map[string]bool{}
+	for key := range from {
+		keys[key] = true
+	}
+	for key := range to {
+		keys[key] = true
+	}
+
+	var changes []models.JobSpecChange
+	for _, key := range sortedNames(keys) {
+		fromValue, inFrom := from[key]
+		toValue, inTo := to[key]
+		change := models.JobSpecChange{Name: prefix + key, From: fromValue, To: toValue}
+		switch {
+		case !inFrom:
+			change.Type = models.JobSpecChangeAdded
+		case !inTo:
+			change.Type = models.JobSpecChangeRemoved
+		case fromValue != toValue:
+			change.Type = models.JobSpecChangeModified
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func sortedNames(names map[string]bool) []string {
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func dependencyTypes(jobSpec models.JobSpec) map[string]string {
+	types := map[string]string{}
+	for name, dependency := range jobSpec.Dependencies {
+		types[name] = dependency.Type.String()
+	}
+	return types
+}
+
+func configValues(config models.JobSpecConfigs) map[string]string {
+	values := map[string]string{}
+	for _, item := range config {
+		values[item.Name] = item.Value
+	}
+	return values
+}
+
+func assetValues(assets models.JobAssets) map[string]string {
+	values := map[string]string{}
+	for _, asset := range assets.GetAll() {
+		values[asset.Name] = asset.Value
+	}
+	return values
+}
+
+// hookConfigs returns config values of hooks by hook name
+func hookConfigs(hooks []models.JobSpecHook) map[string]map[string]string {
+	configs := map[string]map[string]string{}
+	for _, hook := range hooks {
+		configs[pluginName(hook.Unit)] = configValues(hook.Config)
+	}
+	return configs
+}
+
+func pluginName(unit *models.Plugin) string {
+	if unit == nil || unit.Base == nil {
+		return ""
+	}
+	if info := unit.Info(); info != nil {
+		return info.Name
+	}
+	return ""
+}
+
+func formatDate(date *time.Time) string {
+	if date == nil || date.IsZero() {
+		return ""
+	}
+	return date.Format(models.JobDatetimeLayout)
+}
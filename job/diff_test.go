@@ -0,0 +1,136 @@
+package job_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffJobSpec(t *testing.T) {
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+	}
+	hookUnit := new(mock.BasePlugin)
+	hookUnit.On("PluginInfo").Return(&models.PluginInfoResponse{Name: "transporter"}, nil)
+
+	archivedSpec := models.JobSpec{
+		Name:   "job-a",
+		Owner:  "mee@mee",
+		Labels: map[string]string{"team": "data"},
+		Schedule: models.JobSpecSchedule{
+			StartDate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			Interval:  "@daily",
+		},
+		Task: models.JobSpecTask{
+			Config: models.JobSpecConfigs{
+				{Name: "DATASET", Value: "playground"},
+				{Name: "LOAD_METHOD", Value: "APPEND"},
+			},
+			Window: models.JobSpecTaskWindow{Size: 24 * time.Hour, TruncateTo: "d"},
+		},
+		Assets: *models.JobAssets{}.New([]models.JobSpecAsset{
+			{Name: "query.sql", Value: "select 1"},
+		}),
+		Hooks: []models.JobSpecHook{
+			{
+				Unit:   &models.Plugin{Base: hookUnit},
+				Config: models.JobSpecConfigs{{Name: "TOPIC", Value: "events"}},
+			},
+		},
+	}
+	currentSpec := archivedSpec
+	currentSpec.Owner = "you@you"
+	currentSpec.Labels = map[string]string{"team": "data", "tier": "1"}
+	currentSpec.Schedule.Interval = "@hourly"
+	currentSpec.Task.Window.Size = time.Hour
+	currentSpec.Task.Config = models.JobSpecConfigs{
+		{Name: "DATASET", Value: "playground"},
+		{Name: "LOAD_METHOD", Value: "REPLACE"},
+		{Name: "PARTITION_FILTER", Value: "true"},
+	}
+	currentSpec.Assets = *models.JobAssets{}.New([]models.JobSpecAsset{
+		{Name: "query.sql", Value: "select 2"},
+	})
+	currentSpec.Hooks = []models.JobSpecHook{
+		{
+			Unit:   &models.Plugin{Base: hookUnit},
+			Config: models.JobSpecConfigs{{Name: "TOPIC", Value: "events_v2"}},
+		},
+	}
+	currentSpec.Behavior.CatchUp = true
+
+	newService := func(t *testing.T, projectJobSpecRepo *mock.ProjectJobSpecRepository) *job.Service {
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+		t.Cleanup(func() { projJobSpecRepoFac.AssertExpectations(t) })
+		return job.NewService(nil, nil, nil, nil, nil, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+	}
+
+	t.Run("should diff an archived version against the current spec", func(t *testing.T) {
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		defer projectJobSpecRepo.AssertExpectations(t)
+		projectJobSpecRepo.On("GetVersion", "job-a", 1).Return(models.JobSpecVersion{Version: 1, Spec: archivedSpec}, nil)
+		projectJobSpecRepo.On("GetByName", "job-a").Return(currentSpec, models.NamespaceSpec{}, nil)
+
+		diff, err := newService(t, projectJobSpecRepo).DiffJobSpec(projSpec, "job-a", 1, models.JobSpecVersionCurrent)
+		assert.Nil(t, err)
+		assert.Equal(t, models.JobSpecDiff{
+			Metadata: []models.JobSpecChange{
+				{Name: "owner", Type: models.JobSpecChangeModified, From: "mee@mee", To: "you@you"},
+				{Name: "labels.tier", Type: models.JobSpecChangeAdded, To: "1"},
+			},
+			Schedule: []models.JobSpecChange{
+				{Name: "interval", Type: models.JobSpecChangeModified, From: "@daily", To: "@hourly"},
+			},
+			Task: []models.JobSpecChange{
+				{Name: "window.size", Type: models.JobSpecChangeModified, From: "24h0m0s", To: "1h0m0s"},
+			},
+			Config: []models.JobSpecChange{
+				{Name: "LOAD_METHOD", Type: models.JobSpecChangeModified, From: "APPEND", To: "REPLACE"},
+				{Name: "PARTITION_FILTER", Type: models.JobSpecChangeAdded, To: "true"},
+			},
+			Assets: []models.JobSpecChange{
+				{Name: "query.sql", Type: models.JobSpecChangeModified, From: "select 1", To: "select 2"},
+			},
+			Hooks: []models.JobSpecHookDiff{
+				{
+					Name: "transporter",
+					Type: models.JobSpecChangeModified,
+					Config: []models.JobSpecChange{
+						{Name: "TOPIC", Type: models.JobSpecChangeModified, From: "events", To: "events_v2"},
+					},
+				},
+			},
+			Behavior: []models.JobSpecChange{
+				{Name: "catch_up", Type: models.JobSpecChangeModified, From: "false", To: "true"},
+			},
+		}, diff)
+	})
+	t.Run("should list removed hooks", func(t *testing.T) {
+		withoutHooks := currentSpec
+		withoutHooks.Hooks = nil
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		defer projectJobSpecRepo.AssertExpectations(t)
+		projectJobSpecRepo.On("GetVersion", "job-a", 1).Return(models.JobSpecVersion{Version: 1, Spec: currentSpec}, nil)
+		projectJobSpecRepo.On("GetVersion", "job-a", 2).Return(models.JobSpecVersion{Version: 2, Spec: withoutHooks}, nil)
+
+		diff, err := newService(t, projectJobSpecRepo).DiffJobSpec(projSpec, "job-a", 1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []models.JobSpecHookDiff{{Name: "transporter", Type: models.JobSpecChangeRemoved}}, diff.Hooks)
+		assert.Empty(t, diff.Config)
+	})
+	t.Run("should fail if the version doesn't exist", func(t *testing.T) {
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		defer projectJobSpecRepo.AssertExpectations(t)
+		projectJobSpecRepo.On("GetVersion", "job-a", 3).Return(models.JobSpecVersion{}, store.ErrResourceNotFound)
+
+		_, err := newService(t, projectJobSpecRepo).DiffJobSpec(projSpec, "job-a", 3, models.JobSpecVersionCurrent)
+		assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+	})
+}
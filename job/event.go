@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	log "github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/core/progress"
 
 	"github.com/pkg/errors"
 
@@ -16,10 +17,31 @@ import (
 type eventService struct {
 	// scheme -> notifier
 	notifyChannels map[string]models.Notifier
+
+	// LifecycleObserver is notified of failed and successful runs and sla
+	// misses of every job regardless of the notify behavior of their specs
+	LifecycleObserver progress.Observer
 }
 
 func (e *eventService) Register(ctx context.Context, namespace models.NamespaceSpec, jobSpec models.JobSpec,
 	evt models.JobEvent) error {
+	if e.LifecycleObserver != nil {
+		switch evt.Type {
+		case models.JobEventTypeFailure, models.JobEventTypeSuccess:
+			e.LifecycleObserver.Notify(&EventJobRunFinished{
+				Namespace: namespace,
+				Job:       jobSpec,
+				Event:     evt,
+			})
+		case models.JobEventTypeSLAMiss:
+			e.LifecycleObserver.Notify(&EventJobSLAMiss{
+				Namespace: namespace,
+				Job:       jobSpec,
+				Event:     evt,
+			})
+		}
+	}
+
 	var err error
 	for _, notify := range jobSpec.Behavior.Notify {
 		if notify.On == evt.Type {
@@ -108,6 +108,37 @@ func TestEventService(t *testing.T) {
 		err := evtService.Register(context.Background(), namespaceSpec, jobSpec, je)
 		assert.Nil(t, err)
 	})
+	t.Run("should notify the lifecycle observer of finished runs and sla misses", func(t *testing.T) {
+		namespaceSpec := models.NamespaceSpec{
+			Name:        "game_jam",
+			ProjectSpec: models.ProjectSpec{Name: "a-data-project"},
+		}
+		jobSpec := models.JobSpec{Name: "transform-tables"}
+		je := models.JobEvent{
+			Type:  models.JobEventTypeSuccess,
+			Value: eventValues.GetFields(),
+		}
+
+		observer := new(mock.PipelineLogObserver)
+		observer.On("Notify", &job.EventJobRunFinished{
+			Namespace: namespaceSpec,
+			Job:       jobSpec,
+			Event:     je,
+		}).Return()
+		defer observer.AssertExpectations(t)
+
+		slaMiss := models.JobEvent{Type: models.JobEventTypeSLAMiss}
+		observer.On("Notify", &job.EventJobSLAMiss{
+			Namespace: namespaceSpec,
+			Job:       jobSpec,
+			Event:     slaMiss,
+		}).Return()
+
+		evtService := job.NewEventService(map[string]models.Notifier{})
+		evtService.LifecycleObserver = observer
+		assert.Nil(t, evtService.Register(context.Background(), namespaceSpec, jobSpec, je))
+		assert.Nil(t, evtService.Register(context.Background(), namespaceSpec, jobSpec, slaMiss))
+	})
 	t.Run("should fail if failed to notify registered notifiers on valid event", func(t *testing.T) {
 		projectSpec := models.ProjectSpec{
 			ID:   uuid.Must(uuid.NewRandom()),
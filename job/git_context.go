@@ -0,0 +1,38 @@
+package job
+
+import (
+	"context"
+
+	"github.com/odpf/optimus/models"
+)
+
+// GitContext is the git commit jobs are deployed from, compilers implementing
+// GitCompiler interpolate it in compiled jobs for auditing which commit
+// produced a running job
+type GitContext struct {
+	CommitSHA  string
+	Branch     string
+	Author     string
+	CommitTime string
+}
+
+// GitCompiler is a models.JobCompiler which can also interpolate the git
+// commit a job is deployed from
+type GitCompiler interface {
+	CompileWithGit(models.NamespaceSpec, models.JobSpec, GitContext) (models.Job, error)
+}
+
+type gitContextKey struct{}
+
+// WithGitContext returns a copy of ctx carrying git, jobs synced with it are
+// compiled with git when the compiler supports it
+func WithGitContext(ctx context.Context, git GitContext) context.Context {
+	return context.WithValue(ctx, gitContextKey{}, git)
+}
+
+// GitContextFromContext returns the git commit carried by ctx, it is empty
+// if ctx doesn't carry any
+func GitContextFromContext(ctx context.Context) GitContext {
+	git, _ := ctx.Value(gitContextKey{}).(GitContext)
+	return git
+}
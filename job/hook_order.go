@@ -0,0 +1,105 @@
+package job
+
+import (
+	"strings"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxHookRetryCount is the most a hook can be retried
+	MaxHookRetryCount = 10
+)
+
+// validateHookRetries checks hooks are retried at most MaxHookRetryCount
+// times with a delay that isn't negative
+func validateHookRetries(jobSpec models.JobSpec) error {
+	for _, hook := range jobSpec.Hooks {
+		if hook.Retry.Count < 0 || hook.Retry.Count > MaxHookRetryCount {
+			return errors.Errorf("retry count of hook %s of job %s should be between 0 and %d",
+				hook.Unit.Info().Name, jobSpec.Name, MaxHookRetryCount)
+		}
+		if hook.Retry.Delay < 0 {
+			return errors.Errorf("retry delay of hook %s of job %s can't be negative",
+				hook.Unit.Info().Name, jobSpec.Name)
+		}
+	}
+	return nil
+}
+
+// validateHookOrder checks hooks of the job only run after other hooks of
+// the job of the same type and that hooks don't run after each other in a
+// cycle. Both the hooks named in RunAfter and the hooks their plugins depend
+// on are ordered. Pre, post and fail hooks run at different stages of a run
+// so they can't be ordered against each other
+func validateHookOrder(jobSpec models.JobSpec) error {
+	var names []string
+	runsAfter := map[string][]string{}
+	hookTypes := map[string]models.HookType{}
+	for _, hook := range jobSpec.Hooks {
+		info := hook.Unit.Info()
+		names = append(names, info.Name)
+		runsAfter[info.Name] = nil
+		hookTypes[info.Name] = info.HookType
+	}
+	for _, hook := range jobSpec.Hooks {
+		info := hook.Unit.Info()
+		for _, after := range hook.RunAfter {
+			if _, ok := runsAfter[after]; !ok {
+				return errors.Errorf("hook %s of job %s runs after %s which is not a hook of the job",
+					info.Name, jobSpec.Name, after)
+			}
+			if hookTypes[after] != info.HookType {
+				return errors.Errorf("%s hook %s of job %s can't run after %s hook %s",
+					info.HookType, info.Name, jobSpec.Name, hookTypes[after], after)
+			}
+			runsAfter[info.Name] = append(runsAfter[info.Name], after)
+		}
+		for _, after := range info.DependsOn {
+			// plugins can depend on hooks the job doesn't use
+			if _, ok := runsAfter[after]; ok {
+				runsAfter[info.Name] = append(runsAfter[info.Name], after)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			for idx := range path {
+				if path[idx] == name {
+					cycle := append(append([]string{}, path[idx:]...), name)
+					return errors.Errorf("hooks of job %s run after each other in a cycle: %s",
+						jobSpec.Name, strings.Join(cycle, " -> "))
+				}
+			}
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, after := range runsAfter[name] {
+			if err := visit(after); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package job
+
+import (
+	"sort"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/utils"
+	"github.com/pkg/errors"
+)
+
+// MaxTemplateChainDepth is the number of templates a job can inherit from
+// through templates extending other templates
+const MaxTemplateChainDepth = 3
+
+var (
+	// ErrNotTemplate is returned when a job extends a job that isn't tagged
+	// as a template
+	ErrNotTemplate = errors.New("job is not a template")
+
+	// ErrTemplateChainTooDeep is returned when a job inherits from more than
+	// MaxTemplateChainDepth templates or the templates extend each other
+	ErrTemplateChainTooDeep = errors.New("template chain is too deep")
+)
+
+// ResolveInheritance merges the templates a spec extends into it, values of
+// the spec take precedence over the ones of its templates. Templates are
+// looked up in the project of the spec
+func (srv *Service) ResolveInheritance(projectSpec models.ProjectSpec, spec models.JobSpec) (models.JobSpec, error) {
+	if spec.Extends == "" {
+		return spec, nil
+	}
+	projectJobSpecRepo := srv.projectJobSpecRepoFactory.New(projectSpec)
+
+	// templates from the closest to the furthest
+	var templates []models.JobSpec
+	seen := map[string]bool{spec.Name: true}
+	for name := spec.Extends; name != ""; {
+		if len(templates) == MaxTemplateChainDepth || seen[name] {
+			return models.JobSpec{}, errors.Wrapf(ErrTemplateChainTooDeep, "job %s can inherit from at most %d templates",
+				spec.Name, MaxTemplateChainDepth)
+		}
+		seen[name] = true
+		template, _, err := projectJobSpecRepo.GetByName(name)
+		if err != nil {
+			return models.JobSpec{}, errors.Wrapf(err, "failed to find template %s of job %s", name, spec.Name)
+		}
+		if !template.IsTemplate {
+			return models.JobSpec{}, errors.Wrapf(ErrNotTemplate, "job %s can't extend %s", spec.Name, name)
+		}
+		templates = append(templates, template)
+		name = template.Extends
+	}
+
+	merged := cloneJobSpec(templates[len(templates)-1], spec.Name)
+	for i := len(templates) - 2; i >= 0; i-- {
+		merged = mergeJobSpec(merged, templates[i])
+	}
+	return mergeJobSpec(merged, spec), nil
+}
+
+// OrderByInheritance sorts specs so templates come before the specs
+// extending them, specs are otherwise kept in order
+func OrderByInheritance(specs []models.JobSpec) []models.JobSpec {
+	byName := map[string]models.JobSpec{}
+	for _, spec := range specs {
+		byName[spec.Name] = spec
+	}
+	depth := func(spec models.JobSpec) int {
+		d := 0
+		for ; d <= MaxTemplateChainDepth && spec.Extends != ""; d++ {
+			parent, ok := byName[spec.Extends]
+			if !ok {
+				break
+			}
+			spec = parent
+		}
+		return d
+	}
+
+	ordered := append([]models.JobSpec(nil), specs...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return depth(ordered[i]) < depth(ordered[j])
+	})
+	return ordered
+}
+
+// mergeJobSpec deep merges child over a copy of parent. Values set in the
+// child are kept, maps and lists keyed by name are merged and boolean
+// behaviors enabled in the parent stay enabled
+func mergeJobSpec(parent, child models.JobSpec) models.JobSpec {
+	merged := cloneJobSpec(child, child.Name)
+	merged.ID = child.ID
+	parent = cloneJobSpec(parent, parent.Name)
+
+	if merged.Owner == "" {
+		merged.Owner = parent.Owner
+	}
+	if merged.Description == "" {
+		merged.Description = parent.Description
+	}
+	if len(parent.Labels) > 0 && merged.Labels == nil {
+		merged.Labels = map[string]string{}
+	}
+	for key, value := range parent.Labels {
+		if _, ok := merged.Labels[key]; !ok {
+			merged.Labels[key] = value
+		}
+	}
+
+	if merged.Schedule.StartDate.IsZero() {
+		merged.Schedule.StartDate = parent.Schedule.StartDate
+	}
+	if merged.Schedule.EndDate == nil {
+		merged.Schedule.EndDate = parent.Schedule.EndDate
+	}
+	if merged.Schedule.Interval == "" {
+		merged.Schedule.Interval = parent.Schedule.Interval
+	}
+	if merged.Schedule.Timezone == "" {
+		merged.Schedule.Timezone = parent.Schedule.Timezone
+	}
+	if merged.SLADuration == 0 {
+		merged.SLADuration = parent.SLADuration
+	}
+
+	merged.Behavior.DependsOnPast = merged.Behavior.DependsOnPast || parent.Behavior.DependsOnPast
+	merged.Behavior.CatchUp = merged.Behavior.CatchUp || parent.Behavior.CatchUp
+	if merged.Behavior.Retry == (models.JobSpecBehaviorRetry{}) {
+		merged.Behavior.Retry = parent.Behavior.Retry
+	}
+	for _, parentNotify := range parent.Behavior.Notify {
+		idx := -1
+		for i, notify := range merged.Behavior.Notify {
+			if notify.On == parentNotify.On {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			merged.Behavior.Notify = append(merged.Behavior.Notify, parentNotify)
+			continue
+		}
+		if merged.Behavior.Notify[idx].Config == nil {
+			merged.Behavior.Notify[idx].Config = map[string]string{}
+		}
+		for key, value := range parentNotify.Config {
+			if _, ok := merged.Behavior.Notify[idx].Config[key]; !ok {
+				merged.Behavior.Notify[idx].Config[key] = value
+			}
+		}
+		for _, channel := range parentNotify.Channels {
+			if !utils.ContainsString(merged.Behavior.Notify[idx].Channels, channel) {
+				merged.Behavior.Notify[idx].Channels = append(merged.Behavior.Notify[idx].Channels, channel)
+			}
+		}
+	}
+
+	if merged.Task.Unit == nil {
+		merged.Task.Unit = parent.Task.Unit
+	}
+	if merged.Task.Window == (models.JobSpecTaskWindow{}) {
+		merged.Task.Window = parent.Task.Window
+	}
+	if merged.Task.Resources.IsZero() {
+		merged.Task.Resources = parent.Task.Resources
+	}
+	merged.Task.Config = mergeJobSpecConfigs(parent.Task.Config, merged.Task.Config)
+
+	for depName, dep := range parent.Dependencies {
+		if _, ok := merged.Dependencies[depName]; !ok {
+			merged.Dependencies[depName] = dep
+		}
+	}
+
+	assets := merged.Assets.GetAll()
+	assetNames := map[string]bool{}
+	for _, asset := range assets {
+		assetNames[asset.Name] = true
+	}
+	for _, asset := range parent.Assets.GetAll() {
+		if !assetNames[asset.Name] {
+			assets = append(assets, asset)
+		}
+	}
+	merged.Assets = *models.JobAssets{}.New(assets)
+
+	for _, parentHook := range parent.Hooks {
+		idx := -1
+		for i, hook := range merged.Hooks {
+			if hook.Unit != nil && parentHook.Unit != nil && hook.Unit.Info().Name == parentHook.Unit.Info().Name {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			merged.Hooks = append(merged.Hooks, parentHook)
+			continue
+		}
+		merged.Hooks[idx].Config = mergeJobSpecConfigs(parentHook.Config, merged.Hooks[idx].Config)
+	}
+	return merged
+}
+
+// mergeJobSpecConfigs appends configs of the parent missing in the child
+func mergeJobSpecConfigs(parent, child models.JobSpecConfigs) models.JobSpecConfigs {
+	for _, parentConfig := range parent {
+		exists := false
+		for _, config := range child {
+			if config.Name == parentConfig.Name {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			child = append(child, parentConfig)
+		}
+	}
+	return child
+}
@@ -0,0 +1,184 @@
+package job_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveInheritance(t *testing.T) {
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+	}
+	transporterHook := new(mock.BasePlugin)
+	transporterHook.On("PluginInfo").Return(&models.PluginInfoResponse{Name: "transporter"}, nil)
+	predatorHook := new(mock.BasePlugin)
+	predatorHook.On("PluginInfo").Return(&models.PluginInfoResponse{Name: "predator"}, nil)
+
+	baseTemplate := models.JobSpec{
+		Name:       "bq-base",
+		Owner:      "data@example.io",
+		IsTemplate: true,
+		Labels:     map[string]string{"team": "data", "tier": "2"},
+		Schedule: models.JobSpecSchedule{
+			StartDate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			Interval:  "0 2 * * *",
+		},
+		Behavior: models.JobSpecBehavior{
+			Retry: models.JobSpecBehaviorRetry{Count: 3, Delay: time.Minute},
+		},
+		Task: models.JobSpecTask{
+			Config: models.JobSpecConfigs{
+				{Name: "PROJECT", Value: "data-project"},
+				{Name: "LOAD_METHOD", Value: "REPLACE"},
+			},
+		},
+		Hooks: []models.JobSpecHook{
+			{
+				Unit:   &models.Plugin{Base: transporterHook},
+				Config: models.JobSpecConfigs{{Name: "KAFKA_TOPIC", Value: "default"}, {Name: "FILTER", Value: "true"}},
+			},
+			{
+				Unit:   &models.Plugin{Base: predatorHook},
+				Config: models.JobSpecConfigs{{Name: "AUDIT_TIME", Value: "{{.EXECUTION_TIME}}"}},
+			},
+		},
+	}
+	newService := func(t *testing.T, templates ...models.JobSpec) *job.Service {
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		for _, template := range templates {
+			projectJobSpecRepo.On("GetByName", template.Name).Return(template, models.NamespaceSpec{}, nil)
+		}
+		t.Cleanup(func() { projectJobSpecRepo.AssertExpectations(t) })
+
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+		t.Cleanup(func() { projJobSpecRepoFac.AssertExpectations(t) })
+
+		return job.NewService(nil, nil, nil, nil, nil, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+	}
+
+	t.Run("should inherit hooks of the template and override its schedule", func(t *testing.T) {
+		svc := newService(t, baseTemplate)
+		childSpec := models.JobSpec{
+			Name:    "daily-orders",
+			Extends: "bq-base",
+			Labels:  map[string]string{"tier": "1"},
+			Schedule: models.JobSpecSchedule{
+				Interval: "0 5 * * *",
+			},
+			Task: models.JobSpecTask{
+				Config: models.JobSpecConfigs{{Name: "LOAD_METHOD", Value: "APPEND"}},
+			},
+			Hooks: []models.JobSpecHook{
+				{
+					Unit:   &models.Plugin{Base: transporterHook},
+					Config: models.JobSpecConfigs{{Name: "KAFKA_TOPIC", Value: "orders"}},
+				},
+			},
+		}
+
+		resolved, err := svc.ResolveInheritance(projSpec, childSpec)
+		assert.Nil(t, err)
+		assert.Equal(t, "daily-orders", resolved.Name)
+		assert.Equal(t, "bq-base", resolved.Extends)
+		assert.False(t, resolved.IsTemplate)
+		assert.Equal(t, "data@example.io", resolved.Owner)
+		assert.Equal(t, map[string]string{"team": "data", "tier": "1"}, resolved.Labels)
+		assert.Equal(t, "0 5 * * *", resolved.Schedule.Interval)
+		assert.Equal(t, baseTemplate.Schedule.StartDate, resolved.Schedule.StartDate)
+		assert.Equal(t, baseTemplate.Behavior.Retry, resolved.Behavior.Retry)
+		assert.Equal(t, models.JobSpecConfigs{
+			{Name: "LOAD_METHOD", Value: "APPEND"},
+			{Name: "PROJECT", Value: "data-project"},
+		}, resolved.Task.Config)
+
+		assert.Equal(t, 2, len(resolved.Hooks))
+		assert.Equal(t, models.JobSpecConfigs{
+			{Name: "KAFKA_TOPIC", Value: "orders"},
+			{Name: "FILTER", Value: "true"},
+		}, resolved.Hooks[0].Config)
+		assert.Equal(t, "predator", resolved.Hooks[1].Unit.Info().Name)
+
+		// the template isn't changed by its children
+		resolved.Hooks[1].Config[0].Value = "changed"
+		assert.Equal(t, "{{.EXECUTION_TIME}}", baseTemplate.Hooks[1].Config[0].Value)
+	})
+	t.Run("should merge templates extending other templates", func(t *testing.T) {
+		hourlyTemplate := models.JobSpec{
+			Name:       "bq-hourly",
+			Extends:    "bq-base",
+			IsTemplate: true,
+			Schedule: models.JobSpecSchedule{
+				Interval: "0 * * * *",
+			},
+		}
+		svc := newService(t, baseTemplate, hourlyTemplate)
+
+		resolved, err := svc.ResolveInheritance(projSpec, models.JobSpec{Name: "hourly-orders", Extends: "bq-hourly"})
+		assert.Nil(t, err)
+		assert.Equal(t, "0 * * * *", resolved.Schedule.Interval)
+		assert.Equal(t, 2, len(resolved.Hooks))
+		assert.Equal(t, "data@example.io", resolved.Owner)
+	})
+	t.Run("should fail if the template chain is deeper than the limit", func(t *testing.T) {
+		templates := []models.JobSpec{
+			{Name: "template-1", IsTemplate: true, Extends: "template-2"},
+			{Name: "template-2", IsTemplate: true, Extends: "template-3"},
+			{Name: "template-3", IsTemplate: true, Extends: "template-4"},
+		}
+		svc := newService(t, templates...)
+
+		_, err := svc.ResolveInheritance(projSpec, models.JobSpec{Name: "a-job", Extends: "template-1"})
+		assert.True(t, errors.Is(err, job.ErrTemplateChainTooDeep))
+	})
+	t.Run("should fail if templates extend each other", func(t *testing.T) {
+		templates := []models.JobSpec{
+			{Name: "template-1", IsTemplate: true, Extends: "template-2"},
+			{Name: "template-2", IsTemplate: true, Extends: "template-1"},
+		}
+		svc := newService(t, templates...)
+
+		_, err := svc.ResolveInheritance(projSpec, models.JobSpec{Name: "a-job", Extends: "template-1"})
+		assert.True(t, errors.Is(err, job.ErrTemplateChainTooDeep))
+	})
+	t.Run("should fail if the extended job isn't a template", func(t *testing.T) {
+		svc := newService(t, models.JobSpec{Name: "daily-orders"})
+
+		_, err := svc.ResolveInheritance(projSpec, models.JobSpec{Name: "a-job", Extends: "daily-orders"})
+		assert.True(t, errors.Is(err, job.ErrNotTemplate))
+	})
+	t.Run("should fail if the template isn't registered in the project", func(t *testing.T) {
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepo.On("GetByName", "bq-base").Return(models.JobSpec{}, models.NamespaceSpec{}, store.ErrResourceNotFound)
+		defer projectJobSpecRepo.AssertExpectations(t)
+
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+		defer projJobSpecRepoFac.AssertExpectations(t)
+
+		svc := job.NewService(nil, nil, nil, nil, nil, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+		_, err := svc.ResolveInheritance(projSpec, models.JobSpec{Name: "a-job", Extends: "bq-base"})
+		assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+	})
+}
+
+func TestOrderByInheritance(t *testing.T) {
+	specs := []models.JobSpec{
+		{Name: "hourly-orders", Extends: "bq-hourly"},
+		{Name: "bq-hourly", Extends: "bq-base", IsTemplate: true},
+		{Name: "daily-orders", Extends: "external-template"},
+		{Name: "bq-base", IsTemplate: true},
+	}
+	var names []string
+	for _, spec := range job.OrderByInheritance(specs) {
+		names = append(names, spec.Name)
+	}
+	assert.Equal(t, []string{"daily-orders", "bq-base", "bq-hourly", "hourly-orders"}, names)
+}
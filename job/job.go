@@ -5,7 +5,21 @@ import "github.com/odpf/optimus/models"
 // SpecRepository represents a storage interface for Job specifications at a namespace level
 type SpecRepository interface {
 	Save(models.JobSpec) error
+	// InsertAll saves new job specs of the namespace in a single transaction,
+	// it fails without saving any of them if one is already registered
+	InsertAll([]models.JobSpec) error
 	GetByName(string) (models.JobSpec, error)
 	GetAll() ([]models.JobSpec, error)
+	// GetPage returns at most limit job specs ordered by name whose name
+	// sorts after afterName, an empty afterName starts from the first job.
+	// Only jobs having all of labels are returned
+	GetPage(afterName string, limit int, labels map[string]string) ([]models.JobSpec, error)
 	Delete(string) error
+	// Undelete restores the soft deleted job spec with name
+	Undelete(name string) error
+	// GetSpecChecksum returns the checksum of the job spec computed on save
+	GetSpecChecksum(jobName string) (string, error)
+	// GetSpecChecksums returns the checksums of job specs keyed by job name,
+	// jobs that aren't stored are left out
+	GetSpecChecksums(jobNames []string) (map[string]string, error)
 }
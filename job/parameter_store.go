@@ -0,0 +1,99 @@
+package job
+
+import (
+	"os"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrParameterNotFound = errors.New("parameter not found")
+)
+
+// EnvParameterPrefix prefixes the environment variables readable as
+// parameters, other variables of the server like its config are never exposed
+const EnvParameterPrefix = "OPTIMUS_PARAM_"
+
+// ParameterStore provides values of parameters shared across job templates
+type ParameterStore interface {
+	Get(key string) (string, error)
+}
+
+// JobParameterStore looks up parameters in the task config of a job
+type JobParameterStore struct {
+	config []models.JobSpecConfigItem
+}
+
+func (s *JobParameterStore) Get(key string) (string, error) {
+	for _, item := range s.config {
+		if item.Name == key {
+			return item.Value, nil
+		}
+	}
+	return "", errors.Wrap(ErrParameterNotFound, key)
+}
+
+// NewJobParameterStore creates a parameter store over the task config of jobSpec
+func NewJobParameterStore(jobSpec models.JobSpec) *JobParameterStore {
+	return &JobParameterStore{
+		config: jobSpec.Task.Config,
+	}
+}
+
+// ProjectParameterStore looks up parameters in the config of a registered project
+type ProjectParameterStore struct {
+	config map[string]string
+}
+
+func (s *ProjectParameterStore) Get(key string) (string, error) {
+	if val, ok := s.config[key]; ok {
+		return val, nil
+	}
+	return "", errors.Wrap(ErrParameterNotFound, key)
+}
+
+// NewProjectParameterStore creates a parameter store over the config of a project
+// as stored by the project repository
+func NewProjectParameterStore(projectSpec models.ProjectSpec) *ProjectParameterStore {
+	return &ProjectParameterStore{
+		config: projectSpec.Config,
+	}
+}
+
+// EnvParameterStore looks up parameters in the environment variables, the
+// parameter KEY is read from OPTIMUS_PARAM_KEY
+type EnvParameterStore struct {
+	lookup func(string) (string, bool)
+}
+
+func (s *EnvParameterStore) Get(key string) (string, error) {
+	if val, ok := s.lookup(EnvParameterPrefix + key); ok {
+		return val, nil
+	}
+	return "", errors.Wrap(ErrParameterNotFound, key)
+}
+
+// NewEnvParameterStore creates a parameter store over the environment of the process
+func NewEnvParameterStore() *EnvParameterStore {
+	return &EnvParameterStore{
+		lookup: os.LookupEnv,
+	}
+}
+
+// ChainedParameterStore looks up parameters in each of the stores in order and
+// returns the first value found
+type ChainedParameterStore []ParameterStore
+
+func (stores ChainedParameterStore) Get(key string) (string, error) {
+	for _, store := range stores {
+		val, err := store.Get(key)
+		if err == nil {
+			return val, nil
+		}
+		if !errors.Is(err, ErrParameterNotFound) {
+			return "", err
+		}
+	}
+	return "", errors.Wrap(ErrParameterNotFound, key)
+}
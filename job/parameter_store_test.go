@@ -0,0 +1,71 @@
+package job_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterStore(t *testing.T) {
+	jobSpec := models.JobSpec{
+		Task: models.JobSpecTask{
+			Config: []models.JobSpecConfigItem{
+				{Name: "DATASET", Value: "job_dataset"},
+			},
+		},
+	}
+	projectSpec := models.ProjectSpec{
+		Config: map[string]string{
+			"DATASET": "project_dataset",
+			"BUCKET":  "project_bucket",
+		},
+	}
+	os.Setenv("OPTIMUS_PARAM_DATASET", "env_dataset")
+	os.Setenv("OPTIMUS_PARAM_BUCKET", "env_bucket")
+	os.Setenv("OPTIMUS_PARAM_REGION", "env_region")
+	os.Setenv("ZONE", "env_zone")
+	os.Setenv("OPTIMUS_SERVE_APP_KEY", "32charshtesthashtesthashtesthash")
+	defer func() {
+		os.Unsetenv("OPTIMUS_PARAM_DATASET")
+		os.Unsetenv("OPTIMUS_PARAM_BUCKET")
+		os.Unsetenv("OPTIMUS_PARAM_REGION")
+		os.Unsetenv("ZONE")
+		os.Unsetenv("OPTIMUS_SERVE_APP_KEY")
+	}()
+
+	params := job.ChainedParameterStore{
+		job.NewJobParameterStore(jobSpec),
+		job.NewProjectParameterStore(projectSpec),
+		job.NewEnvParameterStore(),
+	}
+
+	t.Run("should prefer job config over project config and env", func(t *testing.T) {
+		val, err := params.Get("DATASET")
+		assert.Nil(t, err)
+		assert.Equal(t, "job_dataset", val)
+	})
+	t.Run("should prefer project config over env", func(t *testing.T) {
+		val, err := params.Get("BUCKET")
+		assert.Nil(t, err)
+		assert.Equal(t, "project_bucket", val)
+	})
+	t.Run("should fallback to env", func(t *testing.T) {
+		val, err := params.Get("REGION")
+		assert.Nil(t, err)
+		assert.Equal(t, "env_region", val)
+	})
+	t.Run("should only read env variables with the param prefix", func(t *testing.T) {
+		for _, key := range []string{"ZONE", "OPTIMUS_SERVE_APP_KEY"} {
+			_, err := params.Get(key)
+			assert.True(t, errors.Is(err, job.ErrParameterNotFound), key)
+		}
+	})
+	t.Run("should return not found error if no store has the key", func(t *testing.T) {
+		_, err := params.Get("OPTIMUS_TEST_MISSING")
+		assert.True(t, errors.Is(err, job.ErrParameterNotFound))
+	})
+}
@@ -1,6 +1,9 @@
 package job
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/odpf/optimus/core/tree"
 	"github.com/odpf/optimus/models"
 	"github.com/pkg/errors"
@@ -33,14 +36,15 @@ type PriorityResolver interface {
 	Resolve([]models.JobSpec) ([]models.JobSpec, error)
 }
 
-// priorityResolver runs a breadth first traversal on DAG/Job dependencies trees
-// and returns highest weight for the DAG that do not have any dependencies, dynamically.
+// priorityResolver sorts the DAG/Job dependencies topologically and gives
+// the highest weight to the DAGs that do not have any dependencies, dynamically.
 // eg, consider following DAGs and dependencies: [dag1 <- dag2 <- dag3] [dag4] [dag5 <- dag6]
 // In this example, we've 6 DAGs in which dag1, dag2, dag5 have dependent DAGs. which means,
 // It'd be preferable to run dag1, dag4, dag5 before other DAGs. Results would be:
 // dag1, dag4, dag5 will get highest weight (maxWeight)
 // dag2, dag6 will get weight of maxWeight-1
 // dag3 will get maxWeight-2
+// DAGs depending on others through several paths get their weight from the longest one.
 // Note: it's crucial that dependencies of all Jobs are already resolved
 type priorityResolver struct {
 }
@@ -59,98 +63,98 @@ func (a *priorityResolver) Resolve(jobSpecs []models.JobSpec) ([]models.JobSpec,
 	return jobSpecs, nil
 }
 
-// resolvePriorities resolves priorities of all provided jobs
+// resolvePriorities resolves priorities of all provided jobs, higher weights
+// are assigned to the jobs on the top, and the weight reduces as we go down
+// the dependency levels
 func (a *priorityResolver) resolvePriorities(jobSpecs []models.JobSpec) error {
-	// build a multi-root tree from all jobs based on their dependencies
-	multiRootTree, err := a.buildMultiRootDependencyTree(jobSpecs)
+	levels, err := a.resolveDependencyLevels(jobSpecs)
 	if err != nil {
 		return err
 	}
 
-	// perform a breadth first traversal on all trees and assign weights.
-	// higher weights are assign to the nodes on the top, and the weight
-	// reduces as we go down the tree level
-	taskPriorityMap := map[string]int{}
-	a.assignWeight(multiRootTree.GetRootNodes(), MaxPriorityWeight, taskPriorityMap)
-
 	for idx, jobSpec := range jobSpecs {
-		priority, ok := taskPriorityMap[jobSpec.Name]
+		level, ok := levels[jobSpec.Name]
 		if !ok {
 			return errors.Wrap(ErrPriorityNotFound, jobSpec.Name)
 		}
-		jobSpec.Task.Priority = priority
+		jobSpec.Task.Priority = MaxPriorityWeight - level*PriorityWeightGap
 		jobSpecs[idx] = jobSpec
 	}
 
 	return nil
 }
 
-func (a *priorityResolver) assignWeight(rootNodes []*tree.TreeNode, weight int, taskPriorityMap map[string]int) {
-	subChildren := []*tree.TreeNode{}
-	for _, rootNode := range rootNodes {
-		taskPriorityMap[rootNode.GetName()] = weight
-		subChildren = append(subChildren, rootNode.Dependents...)
-	}
-	if len(subChildren) > 0 {
-		a.assignWeight(subChildren, weight-PriorityWeightGap, taskPriorityMap)
-	}
-}
-
-// buildMultiRootDependencyTree - converts []JobSpec into a MultiRootTree
-// based on the dependencies of each DAG.
-func (a *priorityResolver) buildMultiRootDependencyTree(jobSpecs []models.JobSpec) (*tree.MultiRootTree, error) {
+// resolveDependencyLevels runs Kahn's algorithm on the dependencies of the jobs,
+// visiting every job once after all of its dependencies and every dependency
+// once, and returns the length of the longest chain of dependencies leading to
+// each job. Jobs with no dependencies are on level 0
+func (a *priorityResolver) resolveDependencyLevels(jobSpecs []models.JobSpec) (map[string]int, error) {
 	// creates map[jobName]jobSpec for faster retrieval
-	jobSpecMap := make(map[string]models.JobSpec)
+	jobSpecMap := make(map[string]models.JobSpec, len(jobSpecs))
 	for _, dagSpec := range jobSpecs {
 		jobSpecMap[dagSpec.Name] = dagSpec
 	}
 
-	// build a multi root tree and assign dependencies
-	// ignore any other dependency apart from intra-tenant
-	tree := tree.NewMultiRootTree()
+	// count dependencies of each job and collect the dependents of each
+	// dependency, ignore any other dependency apart from intra-tenant
+	inDegree := make(map[string]int, len(jobSpecMap))
+	dependents := make(map[string][]string, len(jobSpecMap))
+	for name := range jobSpecMap {
+		inDegree[name] = 0
+	}
 	for _, childSpec := range jobSpecMap {
-		childNode := a.findOrCreateDAGNode(tree, childSpec)
 		for _, depDAG := range childSpec.Dependencies {
-			var isExternal = false
-			parentSpec, ok := jobSpecMap[depDAG.Job.Name]
-			if !ok {
+			parentName := depDAG.Job.Name
+			if _, ok := jobSpecMap[parentName]; !ok {
 				if depDAG.Type == models.JobSpecDependencyTypeIntra {
-					return nil, errors.Wrap(ErrJobSpecNotFound, depDAG.Job.Name)
+					return nil, errors.Wrap(ErrJobSpecNotFound, parentName)
 				}
 
 				// when the dependency of a jobSpec belong to some other tenant or is external, the jobSpec won't
-				// be available in jobSpecs []models.JobSpec object (which is tenant specific)
-				// so we'll add a dummy JobSpec for that cross tenant/external dependency.
-				parentSpec = models.JobSpec{Name: depDAG.Job.Name, Dependencies: make(map[string]models.JobSpecDependency)}
-				isExternal = true
-			}
-			parentNode := a.findOrCreateDAGNode(tree, parentSpec)
-			parentNode.AddDependent(childNode)
-			tree.AddNode(parentNode)
-			if isExternal {
-				// dependency that are outside current project will be considered as root because
+				// be available in jobSpecs []models.JobSpec object (which is tenant specific), dependencies
+				// that are outside current project are considered as having no dependencies because
 				// optimus don't know dependencies of those external parents
-				tree.MarkRoot(parentNode)
+				if _, ok := inDegree[parentName]; !ok {
+					inDegree[parentName] = 0
+				}
 			}
+			dependents[parentName] = append(dependents[parentName], childSpec.Name)
+			inDegree[childSpec.Name]++
 		}
+	}
 
-		// the DAGs with no dependencies are root nodes for the tree
-		if len(childSpec.Dependencies) == 0 {
-			tree.MarkRoot(childNode)
+	levels := make(map[string]int, len(inDegree))
+	queue := make([]string, 0, len(inDegree))
+	for name, degree := range inDegree {
+		if degree == 0 {
+			levels[name] = 0
+			queue = append(queue, name)
 		}
 	}
-
-	if err := tree.IsCyclic(); err != nil {
-		return nil, err
+	for head := 0; head < len(queue); head++ {
+		parentName := queue[head]
+		for _, childName := range dependents[parentName] {
+			if levels[parentName]+1 > levels[childName] {
+				levels[childName] = levels[parentName] + 1
+			}
+			inDegree[childName]--
+			if inDegree[childName] == 0 {
+				queue = append(queue, childName)
+			}
+		}
 	}
-	return tree, nil
-}
 
-func (a *priorityResolver) findOrCreateDAGNode(dagTree *tree.MultiRootTree, dagSpec models.JobSpec) *tree.TreeNode {
-	node, ok := dagTree.GetNodeByName(dagSpec.Name)
-	if !ok {
-		node = tree.NewTreeNode(dagSpec)
-		dagTree.AddNode(node)
+	// jobs never left without unvisited dependencies are on a cycle or
+	// depend on one
+	if len(queue) < len(inDegree) {
+		var cyclicNames []string
+		for name, degree := range inDegree {
+			if degree > 0 {
+				cyclicNames = append(cyclicNames, name)
+			}
+		}
+		sort.Strings(cyclicNames)
+		return nil, errors.Wrap(tree.ErrCyclicDependencyEncountered, strings.Join(cyclicNames, ", "))
 	}
-	return node
+	return levels, nil
 }
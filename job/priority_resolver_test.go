@@ -1,6 +1,8 @@
 package job_test
 
 import (
+	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/odpf/optimus/core/tree"
@@ -273,6 +275,37 @@ func TestPriorityWeightResolver(t *testing.T) {
 		}
 	})
 
+	t.Run("Resolve should assign weights by the longest chain of dependencies", func(t *testing.T) {
+		spec1 := "dag1-no-deps"
+		spec2 := "dag2-deps-on-dag1"
+		spec3 := "dag3-deps-on-dag1-dag2"
+
+		var (
+			specs    = make(map[string]models.JobSpec)
+			jobSpecs = make([]models.JobSpec, 0)
+		)
+
+		specs[spec1] = models.JobSpec{Name: spec1, Dependencies: noDependency}
+		jobSpecs = append(jobSpecs, specs[spec1])
+
+		specs[spec2] = models.JobSpec{Name: spec2, Dependencies: getDependencyObject(specs, spec1)}
+		jobSpecs = append(jobSpecs, specs[spec2])
+
+		specs[spec3] = models.JobSpec{Name: spec3, Dependencies: getMultiDependencyObject(specs, spec1, spec2)}
+		jobSpecs = append(jobSpecs, specs[spec3])
+
+		assginer := job.NewPriorityResolver()
+		resolvedJobSpecs, err := assginer.Resolve(jobSpecs)
+		assert.Nil(t, err)
+
+		max := job.MaxPriorityWeight
+		expectedWeights := map[string]int{spec1: max, spec2: max - job.PriorityWeightGap*1,
+			spec3: max - job.PriorityWeightGap*2}
+		for _, jobSpec := range resolvedJobSpecs {
+			assert.Equal(t, expectedWeights[jobSpec.Name], jobSpec.Task.Priority)
+		}
+	})
+
 	t.Run("Resolve should fail when circular dependency is detected (atleast one DAG with no dependency)", func(t *testing.T) {
 		spec1 := "dag1-no-deps"
 		spec2 := "dag2-deps-on-dag1"
@@ -412,6 +445,45 @@ func TestPriorityWeightResolver(t *testing.T) {
 	})
 }
 
+// BenchmarkPriorityResolver resolves priorities of a project with 10000 jobs
+// and 50000 dependencies between them, each job depends on random jobs
+// created before it so the dependencies are free of cycles
+func BenchmarkPriorityResolver(b *testing.B) {
+	const (
+		jobCount  = 10000
+		edgeCount = 50000
+	)
+	random := rand.New(rand.NewSource(1))
+
+	jobSpecs := make([]models.JobSpec, jobCount)
+	for idx := range jobSpecs {
+		jobSpecs[idx] = models.JobSpec{
+			Name:         fmt.Sprintf("job-%d", idx),
+			Dependencies: map[string]models.JobSpecDependency{},
+		}
+	}
+	for edges := 0; edges < edgeCount; {
+		childIdx := 1 + random.Intn(jobCount-1)
+		parentSpec := jobSpecs[random.Intn(childIdx)]
+		if _, ok := jobSpecs[childIdx].Dependencies[parentSpec.Name]; ok {
+			continue
+		}
+		jobSpecs[childIdx].Dependencies[parentSpec.Name] = models.JobSpecDependency{
+			Job:  &parentSpec,
+			Type: models.JobSpecDependencyTypeIntra,
+		}
+		edges++
+	}
+
+	resolver := job.NewPriorityResolver()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := resolver.Resolve(jobSpecs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func TestDAGNode(t *testing.T) {
 	t.Run("TreeNode should handle all TreeNode operations", func(t *testing.T) {
 		dagSpec := models.JobSpec{Name: "testdag"}
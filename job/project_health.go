@@ -0,0 +1,167 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ProjectHealthCacheTTL is the duration diagnostics of a project are reused for
+	ProjectHealthCacheTTL = time.Minute
+
+	// healthProbeJobName is looked up in the storage of compiled jobs, not
+	// finding it means the storage is accessible
+	healthProbeJobName = "__optimus_health_probe"
+)
+
+// ProjectRepoFactory is used to manage projects
+type ProjectRepoFactory interface {
+	New() store.ProjectRepository
+}
+
+type cachedProjectHealth struct {
+	health    *models.ProjectHealth
+	expiresAt time.Time
+}
+
+// ProjectHealthService diagnoses the subsystems used by a project, the database,
+// the storage of compiled jobs, the scheduler and the dependencies of its jobs
+type ProjectHealthService struct {
+	projectRepoFactory        ProjectRepoFactory
+	jobRepoFactory            JobRepoFactory
+	projectJobSpecRepoFactory ProjectJobSpecRepoFactory
+	dependencyResolver        DependencyResolver
+	scheduler                 models.SchedulerUnit
+
+	mu    sync.Mutex
+	cache map[string]cachedProjectHealth
+
+	Now func() time.Time
+}
+
+// GetProjectHealth returns the diagnostics of a project, they are cached per
+// project for ProjectHealthCacheTTL
+func (srv *ProjectHealthService) GetProjectHealth(ctx context.Context, projectSpec models.ProjectSpec) (*models.ProjectHealth, error) {
+	srv.mu.Lock()
+	cached, ok := srv.cache[projectSpec.Name]
+	srv.mu.Unlock()
+	if ok && srv.Now().Before(cached.expiresAt) {
+		return cached.health, nil
+	}
+
+	health, err := srv.projectRepoFactory.New().GetProjectHealth(ctx, projectSpec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check database of project %s", projectSpec.Name)
+	}
+	dependencyCheck, unresolvable := srv.checkDependencies(projectSpec)
+	health.Checks = append(health.Checks,
+		srv.checkStorage(ctx, projectSpec),
+		srv.checkScheduler(ctx, projectSpec),
+		dependencyCheck,
+	)
+	health.UnresolvableDependencies = unresolvable
+	health.CheckedAt = srv.Now()
+
+	srv.mu.Lock()
+	srv.cache[projectSpec.Name] = cachedProjectHealth{
+		health:    health,
+		expiresAt: health.CheckedAt.Add(ProjectHealthCacheTTL),
+	}
+	srv.mu.Unlock()
+	return health, nil
+}
+
+func (srv *ProjectHealthService) checkStorage(ctx context.Context, projectSpec models.ProjectSpec) models.HealthCheck {
+	return runHealthCheck(models.ProjectHealthCheckStorage, func() (string, error) {
+		return "", srv.ProbeStorage(ctx, projectSpec)
+	})
+}
+
+// ProbeStorage fails if the storage of compiled jobs of the project can't be read
+func (srv *ProjectHealthService) ProbeStorage(ctx context.Context, projectSpec models.ProjectSpec) error {
+	jobRepo, err := srv.jobRepoFactory.New(ctx, projectSpec)
+	if err != nil {
+		return err
+	}
+	if _, err := jobRepo.GetByName(ctx, healthProbeJobName); err != nil && !errors.Is(err, models.ErrNoSuchJob) {
+		return err
+	}
+	return nil
+}
+
+func (srv *ProjectHealthService) checkScheduler(ctx context.Context, projectSpec models.ProjectSpec) models.HealthCheck {
+	return runHealthCheck(models.ProjectHealthCheckScheduler, func() (string, error) {
+		runningJobs, err := srv.scheduler.GetRunningJobs(ctx, projectSpec)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d jobs running", len(runningJobs)), nil
+	})
+}
+
+// checkDependencies resolves the dependencies of all jobs in the project and
+// returns the number of jobs failing resolution
+func (srv *ProjectHealthService) checkDependencies(projectSpec models.ProjectSpec) (models.HealthCheck, int) {
+	var unresolvable []string
+	check := runHealthCheck(models.ProjectHealthCheckDependencies, func() (string, error) {
+		projectJobSpecRepo := srv.projectJobSpecRepoFactory.New(projectSpec)
+		jobSpecs, err := projectJobSpecRepo.GetAll()
+		if err != nil {
+			return "", err
+		}
+		for _, jobSpec := range jobSpecs {
+			if _, err := srv.dependencyResolver.Resolve(projectSpec, projectJobSpecRepo, jobSpec, nil); err != nil {
+				unresolvable = append(unresolvable, jobSpec.Name)
+			}
+		}
+		if len(unresolvable) > 0 {
+			return "", errors.Errorf("dependencies of %d jobs can't be resolved: %s", len(unresolvable),
+				strings.Join(unresolvable, ", "))
+		}
+		return fmt.Sprintf("dependencies of %d jobs resolved", len(jobSpecs)), nil
+	})
+	return check, len(unresolvable)
+}
+
+// runHealthCheck measures the latency of probe, probes not supported by the
+// subsystem are skipped
+func runHealthCheck(name string, probe func() (string, error)) models.HealthCheck {
+	start := time.Now()
+	message, err := probe()
+	check := models.HealthCheck{
+		Name:    name,
+		Status:  models.HealthCheckStatusOK,
+		Message: message,
+		Latency: time.Since(start),
+	}
+	switch {
+	case errors.Is(err, models.ErrUnsupportedSchedulerOperation):
+		check.Status = models.HealthCheckStatusSkipped
+		check.Message = err.Error()
+	case err != nil:
+		check.Status = models.HealthCheckStatusFailed
+		check.Message = err.Error()
+	}
+	return check
+}
+
+func NewProjectHealthService(projectRepoFactory ProjectRepoFactory, jobRepoFactory JobRepoFactory,
+	projectJobSpecRepoFactory ProjectJobSpecRepoFactory, dependencyResolver DependencyResolver,
+	scheduler models.SchedulerUnit) *ProjectHealthService {
+	return &ProjectHealthService{
+		projectRepoFactory:        projectRepoFactory,
+		jobRepoFactory:            jobRepoFactory,
+		projectJobSpecRepoFactory: projectJobSpecRepoFactory,
+		dependencyResolver:        dependencyResolver,
+		scheduler:                 scheduler,
+		cache:                     map[string]cachedProjectHealth{},
+		Now:                       time.Now,
+	}
+}
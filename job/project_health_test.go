@@ -0,0 +1,176 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectHealthService(t *testing.T) {
+	ctx := context.Background()
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+	}
+	jobSpecs := []models.JobSpec{
+		{Name: "job-1"},
+		{Name: "job-2"},
+	}
+	lastDeployedAt := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2021, 8, 2, 0, 0, 0, 0, time.UTC)
+
+	type healthMocks struct {
+		projectRepo        *mock.ProjectRepository
+		jobRepoFac         *mock.JobRepoFactory
+		jobRepo            *mock.JobRepository
+		projectJobSpecRepo *mock.ProjectJobSpecRepository
+		depenResolver      *mock.DependencyResolver
+		scheduler          *mock.Scheduler
+	}
+	// setup wires a service with all subsystems healthy, checks can be made to
+	// fail by registering failing expectations before calling the service
+	setup := func() (*job.ProjectHealthService, healthMocks) {
+		m := healthMocks{
+			projectRepo:        new(mock.ProjectRepository),
+			jobRepoFac:         new(mock.JobRepoFactory),
+			jobRepo:            new(mock.JobRepository),
+			projectJobSpecRepo: new(mock.ProjectJobSpecRepository),
+			depenResolver:      new(mock.DependencyResolver),
+			scheduler:          new(mock.Scheduler),
+		}
+		projectRepoFac := new(mock.ProjectRepoFactory)
+		projectRepoFac.On("New").Return(m.projectRepo)
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(m.projectJobSpecRepo)
+		m.projectJobSpecRepo.On("GetAll").Return(jobSpecs, nil)
+
+		svc := job.NewProjectHealthService(projectRepoFac, m.jobRepoFac, projJobSpecRepoFac, m.depenResolver, m.scheduler)
+		svc.Now = func() time.Time { return now }
+		return svc, m
+	}
+	healthyDatabase := func() *models.ProjectHealth {
+		return &models.ProjectHealth{
+			Checks: []models.HealthCheck{
+				{Name: models.ProjectHealthCheckDatabase, Status: models.HealthCheckStatusOK},
+			},
+			LastDeployedAt: lastDeployedAt,
+		}
+	}
+	statusOf := func(health *models.ProjectHealth) map[string]models.HealthCheckStatus {
+		statuses := map[string]models.HealthCheckStatus{}
+		for _, check := range health.Checks {
+			statuses[check.Name] = check.Status
+		}
+		return statuses
+	}
+
+	t.Run("should report all checks healthy", func(t *testing.T) {
+		svc, m := setup()
+		m.projectRepo.On("GetProjectHealth", ctx, projSpec).Return(healthyDatabase(), nil)
+		m.jobRepoFac.On("New", ctx, projSpec).Return(m.jobRepo, nil)
+		m.jobRepo.On("GetByName", ctx, "__optimus_health_probe").Return(models.Job{}, errors.Wrap(models.ErrNoSuchJob, "__optimus_health_probe"))
+		m.scheduler.On("GetRunningJobs", ctx, projSpec).Return([]models.RunningJob{}, nil)
+		m.depenResolver.On("Resolve", projSpec, m.projectJobSpecRepo, jobSpecs[0], nil).Return(jobSpecs[0], nil)
+		m.depenResolver.On("Resolve", projSpec, m.projectJobSpecRepo, jobSpecs[1], nil).Return(jobSpecs[1], nil)
+
+		health, err := svc.GetProjectHealth(ctx, projSpec)
+		assert.Nil(t, err)
+		assert.True(t, health.Healthy())
+		assert.Equal(t, map[string]models.HealthCheckStatus{
+			models.ProjectHealthCheckDatabase:     models.HealthCheckStatusOK,
+			models.ProjectHealthCheckStorage:      models.HealthCheckStatusOK,
+			models.ProjectHealthCheckScheduler:    models.HealthCheckStatusOK,
+			models.ProjectHealthCheckDependencies: models.HealthCheckStatusOK,
+		}, statusOf(health))
+		assert.Equal(t, 0, health.UnresolvableDependencies)
+		assert.Equal(t, lastDeployedAt, health.LastDeployedAt)
+		assert.Equal(t, now, health.CheckedAt)
+	})
+	t.Run("should report failure of each subsystem", func(t *testing.T) {
+		svc, m := setup()
+		failedDatabase := healthyDatabase()
+		failedDatabase.Checks[0].Status = models.HealthCheckStatusFailed
+		m.projectRepo.On("GetProjectHealth", ctx, projSpec).Return(failedDatabase, nil)
+		m.jobRepoFac.On("New", ctx, projSpec).Return(m.jobRepo, nil)
+		m.jobRepo.On("GetByName", ctx, "__optimus_health_probe").Return(models.Job{}, errors.New("bucket not found"))
+		m.scheduler.On("GetRunningJobs", ctx, projSpec).Return([]models.RunningJob{}, errors.New("connection refused"))
+		m.depenResolver.On("Resolve", projSpec, m.projectJobSpecRepo, jobSpecs[0], nil).Return(jobSpecs[0], nil)
+		m.depenResolver.On("Resolve", projSpec, m.projectJobSpecRepo, jobSpecs[1], nil).Return(models.JobSpec{}, errors.New("unknown local dependency"))
+
+		health, err := svc.GetProjectHealth(ctx, projSpec)
+		assert.Nil(t, err)
+		assert.False(t, health.Healthy())
+		assert.Equal(t, map[string]models.HealthCheckStatus{
+			models.ProjectHealthCheckDatabase:     models.HealthCheckStatusFailed,
+			models.ProjectHealthCheckStorage:      models.HealthCheckStatusFailed,
+			models.ProjectHealthCheckScheduler:    models.HealthCheckStatusFailed,
+			models.ProjectHealthCheckDependencies: models.HealthCheckStatusFailed,
+		}, statusOf(health))
+		assert.Equal(t, 1, health.UnresolvableDependencies)
+		assert.Equal(t, "bucket not found", health.Checks[1].Message)
+		assert.Equal(t, "connection refused", health.Checks[2].Message)
+		assert.Contains(t, health.Checks[3].Message, "job-2")
+	})
+	t.Run("should fail storage check if storage is not configured", func(t *testing.T) {
+		svc, m := setup()
+		m.projectRepo.On("GetProjectHealth", ctx, projSpec).Return(healthyDatabase(), nil)
+		m.jobRepoFac.On("New", ctx, projSpec).Return(m.jobRepo, errors.New("STORAGE secret not configured"))
+		m.scheduler.On("GetRunningJobs", ctx, projSpec).Return([]models.RunningJob{}, nil)
+		m.depenResolver.On("Resolve", projSpec, m.projectJobSpecRepo, jobSpecs[0], nil).Return(jobSpecs[0], nil)
+		m.depenResolver.On("Resolve", projSpec, m.projectJobSpecRepo, jobSpecs[1], nil).Return(jobSpecs[1], nil)
+
+		health, err := svc.GetProjectHealth(ctx, projSpec)
+		assert.Nil(t, err)
+		assert.Equal(t, models.HealthCheckStatusFailed, statusOf(health)[models.ProjectHealthCheckStorage])
+	})
+	t.Run("should skip scheduler check if scheduler can't be probed", func(t *testing.T) {
+		svc, m := setup()
+		m.projectRepo.On("GetProjectHealth", ctx, projSpec).Return(healthyDatabase(), nil)
+		m.jobRepoFac.On("New", ctx, projSpec).Return(m.jobRepo, nil)
+		m.jobRepo.On("GetByName", ctx, "__optimus_health_probe").Return(models.Job{}, models.ErrNoSuchJob)
+		m.scheduler.On("GetRunningJobs", ctx, projSpec).Return([]models.RunningJob{}, errors.Wrap(models.ErrUnsupportedSchedulerOperation, "listing running jobs"))
+		m.depenResolver.On("Resolve", projSpec, m.projectJobSpecRepo, jobSpecs[0], nil).Return(jobSpecs[0], nil)
+		m.depenResolver.On("Resolve", projSpec, m.projectJobSpecRepo, jobSpecs[1], nil).Return(jobSpecs[1], nil)
+
+		health, err := svc.GetProjectHealth(ctx, projSpec)
+		assert.Nil(t, err)
+		assert.True(t, health.Healthy())
+		assert.Equal(t, models.HealthCheckStatusSkipped, statusOf(health)[models.ProjectHealthCheckScheduler])
+	})
+	t.Run("should fail if project health can't be fetched from store", func(t *testing.T) {
+		svc, m := setup()
+		m.projectRepo.On("GetProjectHealth", ctx, projSpec).Return(nil, errors.New("connection closed"))
+
+		_, err := svc.GetProjectHealth(ctx, projSpec)
+		assert.Contains(t, err.Error(), "connection closed")
+	})
+	t.Run("should reuse diagnostics until cache expires", func(t *testing.T) {
+		svc, m := setup()
+		m.projectRepo.On("GetProjectHealth", ctx, projSpec).Return(healthyDatabase(), nil).Once()
+		m.projectRepo.On("GetProjectHealth", ctx, projSpec).Return(healthyDatabase(), nil).Once()
+		m.jobRepoFac.On("New", ctx, projSpec).Return(m.jobRepo, nil)
+		m.jobRepo.On("GetByName", ctx, "__optimus_health_probe").Return(models.Job{}, models.ErrNoSuchJob)
+		m.scheduler.On("GetRunningJobs", ctx, projSpec).Return([]models.RunningJob{}, nil)
+		m.depenResolver.On("Resolve", projSpec, m.projectJobSpecRepo, jobSpecs[0], nil).Return(jobSpecs[0], nil)
+		m.depenResolver.On("Resolve", projSpec, m.projectJobSpecRepo, jobSpecs[1], nil).Return(jobSpecs[1], nil)
+
+		first, err := svc.GetProjectHealth(ctx, projSpec)
+		assert.Nil(t, err)
+		svc.Now = func() time.Time { return now.Add(job.ProjectHealthCacheTTL - time.Second) }
+		cached, err := svc.GetProjectHealth(ctx, projSpec)
+		assert.Nil(t, err)
+		assert.Same(t, first, cached)
+		m.projectRepo.AssertNumberOfCalls(t, "GetProjectHealth", 1)
+
+		svc.Now = func() time.Time { return now.Add(job.ProjectHealthCacheTTL) }
+		refreshed, err := svc.GetProjectHealth(ctx, projSpec)
+		assert.Nil(t, err)
+		assert.NotSame(t, first, refreshed)
+		m.projectRepo.AssertNumberOfCalls(t, "GetProjectHealth", 2)
+	})
+}
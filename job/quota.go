@@ -0,0 +1,118 @@
+package job
+
+import (
+	"time"
+
+	"github.com/odpf/optimus/core/cron"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+// scheduleIntervalSamples is the number of runs of a schedule compared to
+// find the shortest time between them
+const scheduleIntervalSamples = 500
+
+// checkQuota fails with models.ErrQuotaExceeded if saving specs would take the
+// project of namespace over its quota, limits not set for the project fall
+// back to DefaultQuota
+func (srv *Service) checkQuota(namespace models.NamespaceSpec, specs []models.JobSpec) error {
+	quota, err := srv.newJobQuota(namespace)
+	if err != nil {
+		return err
+	}
+	return quota.check(specs...)
+}
+
+// jobQuota checks jobs saved in a batch against the quota of their project,
+// the jobs registered in the project are only counted once for the batch
+type jobQuota struct {
+	projectName string
+	limits      models.ProjectQuota
+	now         time.Time
+	// names of the jobs of the project, nil if their count isn't limited
+	registered map[string]bool
+}
+
+func (srv *Service) newJobQuota(namespace models.NamespaceSpec) (*jobQuota, error) {
+	quota := &jobQuota{
+		projectName: namespace.ProjectSpec.Name,
+		limits:      namespace.ProjectSpec.Quota.Or(srv.DefaultQuota),
+		now:         srv.Now(),
+	}
+	if quota.limits.MaxJobCount > 0 {
+		existingSpecs, err := srv.projectJobSpecRepoFactory.New(namespace.ProjectSpec).GetAll()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to count jobs of the project")
+		}
+		quota.registered = map[string]bool{}
+		for _, spec := range existingSpecs {
+			quota.registered[spec.Name] = true
+		}
+	}
+	return quota, nil
+}
+
+// check fails with models.ErrQuotaExceeded if saving specs after the ones
+// checked before would take the project over its quota, specs within the
+// quota are counted as registered
+func (q *jobQuota) check(specs ...models.JobSpec) error {
+	if q.limits.MinScheduleInterval > 0 {
+		for _, spec := range specs {
+			if spec.IsTemplate || spec.Schedule.Interval == "" {
+				continue
+			}
+			interval, err := minScheduleInterval(spec.Schedule, q.now)
+			if err != nil {
+				return errors.Wrapf(err, "failed to parse schedule interval of %s", spec.Name)
+			}
+			if interval < q.limits.MinScheduleInterval {
+				return errors.Wrapf(models.ErrQuotaExceeded, "job %s runs every %s, more often than every %s",
+					spec.Name, interval, q.limits.MinScheduleInterval)
+			}
+		}
+	}
+
+	if q.registered != nil {
+		var added []string
+		for _, spec := range specs {
+			if !q.registered[spec.Name] {
+				q.registered[spec.Name] = true
+				added = append(added, spec.Name)
+			}
+		}
+		if len(q.registered) > q.limits.MaxJobCount {
+			for _, name := range added {
+				delete(q.registered, name)
+			}
+			return errors.Wrapf(models.ErrQuotaExceeded, "project %s can't have more than %d jobs",
+				q.projectName, q.limits.MaxJobCount)
+		}
+	}
+	return nil
+}
+
+// minScheduleInterval is the shortest time between the upcoming runs of a
+// schedule in its timezone
+func minScheduleInterval(jobSchedule models.JobSpecSchedule, from time.Time) (time.Duration, error) {
+	schedule, err := cron.ParseCronSchedule(jobSchedule.Interval)
+	if err != nil {
+		return 0, err
+	}
+	loc, err := jobSchedule.Location()
+	if err != nil {
+		return 0, err
+	}
+	var shortest time.Duration
+	prev := schedule.Next(from.In(loc))
+	for i := 0; i < scheduleIntervalSamples; i++ {
+		next := schedule.Next(prev)
+		if next.IsZero() {
+			break
+		}
+		if gap := next.Sub(prev); shortest == 0 || gap < shortest {
+			shortest = gap
+		}
+		prev = next
+	}
+	return shortest, nil
+}
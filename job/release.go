@@ -0,0 +1,146 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrEmptyReleaseTag is thrown when a release is tagged without a name
+	ErrEmptyReleaseTag = errors.New("release tag cannot be empty")
+)
+
+// ReleaseRepoFactory is used to manage the releases of a project
+type ReleaseRepoFactory interface {
+	New(proj models.ProjectSpec) store.ReleaseRepository
+}
+
+// TagRelease snapshots the current job specs of the project under tag, the
+// project can be rolled back to the snapshot with RollbackToRelease
+func (srv *Service) TagRelease(projectSpec models.ProjectSpec, tag, description string, progressObserver progress.Observer) error {
+	if strings.TrimSpace(tag) == "" {
+		return ErrEmptyReleaseTag
+	}
+	if err := srv.releaseRepoFactory.New(projectSpec).Create(tag, description); err != nil {
+		return errors.Wrapf(err, "failed to tag release %s", tag)
+	}
+	srv.notifyProgress(progressObserver, &EventReleaseTagged{
+		Project:     projectSpec.Name,
+		Tag:         tag,
+		Description: description,
+	})
+	return nil
+}
+
+// RollbackToRelease restores the job specs of the project to the ones
+// snapshotted in the release and deploys the affected namespaces. Specs created
+// after the release are deleted, specs with unchanged checksums are kept as is.
+func (srv *Service) RollbackToRelease(ctx context.Context, projectSpec models.ProjectSpec, tag string,
+	progressObserver progress.Observer) (models.Release, error) {
+	release, err := srv.releaseRepoFactory.New(projectSpec).GetByTag(tag)
+	if err != nil {
+		return models.Release{}, errors.Wrapf(err, "failed to retrieve release %s", tag)
+	}
+
+	// all specs of the project are modified since zero time
+	currentSpecs, currentNamespaces, err := srv.projectJobSpecRepoFactory.New(projectSpec).GetModifiedSince(time.Time{})
+	if err != nil {
+		return models.Release{}, errors.Wrap(err, "failed to retrieve current job specs")
+	}
+	releaseJobs := map[string]models.ReleaseJob{}
+	for _, releaseJob := range release.Jobs {
+		releaseJobs[releaseJob.Spec.Name] = releaseJob
+	}
+
+	affectedNamespaces := map[string]models.NamespaceSpec{}
+	var deleted, restored int
+	// delete specs created after the release or moved to another namespace
+	for _, currentSpec := range currentSpecs {
+		namespace := currentNamespaces[currentSpec.Name]
+		releaseJob, ok := releaseJobs[currentSpec.Name]
+		if ok && releaseJob.Namespace.ID == namespace.ID {
+			continue
+		}
+		if err := srv.jobSpecRepoFactory.New(namespace).Delete(currentSpec.Name); err != nil {
+			return release, errors.Wrapf(err, "failed to delete spec: %s", currentSpec.Name)
+		}
+		srv.notifyProgress(progressObserver, &EventSavedJobDelete{Name: currentSpec.Name})
+		affectedNamespaces[namespace.Name] = namespace
+		deleted++
+	}
+
+	// restore specs changed after the release
+	for _, releaseJob := range release.Jobs {
+		jobSpecRepo := srv.jobSpecRepoFactory.New(releaseJob.Namespace)
+		if namespace, ok := currentNamespaces[releaseJob.Spec.Name]; ok && namespace.ID == releaseJob.Namespace.ID &&
+			releaseJob.SpecChecksum != "" {
+			checksum, err := jobSpecRepo.GetSpecChecksum(releaseJob.Spec.Name)
+			if err != nil {
+				return release, errors.Wrapf(err, "failed to retrieve checksum of %s", releaseJob.Spec.Name)
+			}
+			if checksum == releaseJob.SpecChecksum {
+				continue
+			}
+		}
+		if err := jobSpecRepo.Save(releaseJob.Spec); err != nil {
+			return release, errors.Wrapf(err, "failed to restore spec: %s", releaseJob.Spec.Name)
+		}
+		affectedNamespaces[releaseJob.Namespace.Name] = releaseJob.Namespace
+		restored++
+	}
+
+	var namespaceNames []string
+	for name := range affectedNamespaces {
+		namespaceNames = append(namespaceNames, name)
+	}
+	sort.Strings(namespaceNames)
+	for _, name := range namespaceNames {
+		if err := srv.Sync(ctx, affectedNamespaces[name], progressObserver); err != nil {
+			return release, errors.Wrapf(err, "failed to deploy namespace %s", name)
+		}
+	}
+
+	srv.notifyProgress(progressObserver, &EventReleaseRolledBack{
+		Project:  projectSpec.Name,
+		Tag:      tag,
+		Restored: restored,
+		Deleted:  deleted,
+	})
+	return release, nil
+}
+
+type (
+	// EventReleaseTagged signifies that the job specs of
+	// a project are snapshotted under a release tag
+	EventReleaseTagged struct {
+		Project     string
+		Tag         string
+		Description string
+	}
+
+	// EventReleaseRolledBack signifies that the job specs of
+	// a project are restored to a release
+	EventReleaseRolledBack struct {
+		Project  string
+		Tag      string
+		Restored int
+		Deleted  int
+	}
+)
+
+func (e *EventReleaseTagged) String() string {
+	return fmt.Sprintf("tagged release %s of %s: %s", e.Tag, e.Project, e.Description)
+}
+
+func (e *EventReleaseRolledBack) String() string {
+	return fmt.Sprintf("rolled back %s to release %s, restored %d and deleted %d job specs",
+		e.Project, e.Tag, e.Restored, e.Deleted)
+}
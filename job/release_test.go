@@ -0,0 +1,204 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	testMock "github.com/stretchr/testify/mock"
+)
+
+func TestRelease(t *testing.T) {
+	ctx := context.Background()
+	dumpAssets := func(jobSpec models.JobSpec, _ models.ProjectSpec, _ time.Time) (models.JobAssets, error) {
+		return jobSpec.Assets, nil
+	}
+	projSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "proj",
+	}
+	namespace1 := models.NamespaceSpec{ID: uuid.Must(uuid.NewRandom()), Name: "ns1", ProjectSpec: projSpec}
+	namespace2 := models.NamespaceSpec{ID: uuid.Must(uuid.NewRandom()), Name: "ns2", ProjectSpec: projSpec}
+
+	t.Run("TagRelease", func(t *testing.T) {
+		t.Run("should snapshot job specs of the project under tag", func(t *testing.T) {
+			releaseRepo := new(mock.ReleaseRepository)
+			releaseRepo.On("Create", "v1.0.0", "first release").Return(nil)
+			defer releaseRepo.AssertExpectations(t)
+			releaseRepoFac := new(mock.ReleaseRepoFactory)
+			releaseRepoFac.On("New", projSpec).Return(releaseRepo)
+			observer := new(mock.PipelineLogObserver)
+			observer.On("Notify", &job.EventReleaseTagged{
+				Project:     projSpec.Name,
+				Tag:         "v1.0.0",
+				Description: "first release",
+			})
+			defer observer.AssertExpectations(t)
+
+			svc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil, releaseRepoFac)
+			err := svc.TagRelease(projSpec, "v1.0.0", "first release", observer)
+			assert.Nil(t, err)
+		})
+		t.Run("should fail if tag already exists", func(t *testing.T) {
+			releaseRepo := new(mock.ReleaseRepository)
+			releaseRepo.On("Create", "v1.0.0", "").Return(store.ErrResourceExists)
+			releaseRepoFac := new(mock.ReleaseRepoFactory)
+			releaseRepoFac.On("New", projSpec).Return(releaseRepo)
+
+			svc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil, releaseRepoFac)
+			err := svc.TagRelease(projSpec, "v1.0.0", "", nil)
+			assert.True(t, errors.Is(err, store.ErrResourceExists))
+		})
+		t.Run("should fail if tag is empty", func(t *testing.T) {
+			svc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil, nil)
+			err := svc.TagRelease(projSpec, " ", "", nil)
+			assert.Equal(t, job.ErrEmptyReleaseTag, err)
+		})
+	})
+	t.Run("RollbackToRelease", func(t *testing.T) {
+		t.Run("should restore job specs changed after the release", func(t *testing.T) {
+			specA := models.JobSpec{Name: "job-a", Owner: "tagged"}
+			specB := models.JobSpec{Name: "job-b", Owner: "tagged"}
+			specC := models.JobSpec{Name: "job-c", Owner: "tagged"}
+			release := models.Release{
+				Tag: "v1.0.0",
+				Jobs: []models.ReleaseJob{
+					{Namespace: namespace1, Spec: specA, SpecChecksum: "a1"},
+					{Namespace: namespace1, Spec: specB, SpecChecksum: "b1"},
+					{Namespace: namespace2, Spec: specC, SpecChecksum: "c1"},
+				},
+			}
+			releaseRepo := new(mock.ReleaseRepository)
+			releaseRepo.On("GetByTag", "v1.0.0").Return(release, nil)
+			releaseRepoFac := new(mock.ReleaseRepoFactory)
+			releaseRepoFac.On("New", projSpec).Return(releaseRepo)
+
+			// after the release job-a was modified, job-c deleted and job-d created
+			type storedSpec struct {
+				namespace models.NamespaceSpec
+				spec      models.JobSpec
+			}
+			stored := map[string]storedSpec{
+				"job-a": {namespace1, models.JobSpec{Name: "job-a", Owner: "modified"}},
+				"job-b": {namespace1, specB},
+				"job-d": {namespace2, models.JobSpec{Name: "job-d", Owner: "created"}},
+			}
+			var currentSpecs []models.JobSpec
+			currentNamespaces := map[string]models.NamespaceSpec{}
+			for name, s := range stored {
+				currentSpecs = append(currentSpecs, s.spec)
+				currentNamespaces[name] = s.namespace
+			}
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetModifiedSince", time.Time{}).Return(currentSpecs, currentNamespaces, nil)
+			projectJobSpecRepo.On("GetAll").Return([]models.JobSpec{specA, specB, specC}, nil)
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+
+			jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+			for _, namespace := range []models.NamespaceSpec{namespace1, namespace2} {
+				namespace := namespace
+				jobSpecRepo := new(mock.JobSpecRepository)
+				jobSpecRepo.On("Save", testMock.Anything).Return(nil).Run(func(args testMock.Arguments) {
+					spec := args.Get(0).(models.JobSpec)
+					stored[spec.Name] = storedSpec{namespace, spec}
+				})
+				jobSpecRepo.On("Delete", testMock.Anything).Return(nil).Run(func(args testMock.Arguments) {
+					delete(stored, args.String(0))
+				})
+				jobSpecRepoFac.On("New", namespace).Return(jobSpecRepo)
+			}
+			ns1JobSpecRepo := jobSpecRepoFac.New(namespace1).(*mock.JobSpecRepository)
+			ns1JobSpecRepo.On("GetSpecChecksum", "job-a").Return("a2", nil)
+			ns1JobSpecRepo.On("GetSpecChecksum", "job-b").Return("b1", nil)
+			ns1JobSpecRepo.On("GetAll").Return([]models.JobSpec{specA, specB}, nil)
+			ns2JobSpecRepo := jobSpecRepoFac.New(namespace2).(*mock.JobSpecRepository)
+			ns2JobSpecRepo.On("GetAll").Return([]models.JobSpec{specC}, nil)
+
+			// deploy both affected namespaces
+			depenResolver := new(mock.DependencyResolver)
+			for _, spec := range []models.JobSpec{specA, specB, specC} {
+				depenResolver.On("Resolve", projSpec, projectJobSpecRepo, spec, nil).Return(spec, nil)
+			}
+			priorityResolver := new(mock.PriorityResolver)
+			priorityResolver.On("Resolve", testMock.Anything).Return([]models.JobSpec{specA, specB, specC}, nil)
+			compiler := new(mock.Compiler)
+			compiler.On("Compile", testMock.Anything, testMock.Anything).Return(models.Job{}, nil)
+			jobRepo := new(mock.JobRepository)
+			jobRepo.On("Save", testMock.Anything, testMock.Anything).Return(nil)
+			jobRepo.On("ListNames", testMock.Anything, namespace1).Return([]string{"job-a", "job-b"}, nil)
+			jobRepo.On("ListNames", testMock.Anything, namespace2).Return([]string{"job-d"}, nil)
+			jobRepo.On("Delete", testMock.Anything, namespace2, "job-d").Return(nil)
+			defer jobRepo.AssertExpectations(t)
+			jobRepoFac := new(mock.JobRepoFactory)
+			jobRepoFac.On("New", testMock.Anything, projSpec).Return(jobRepo, nil)
+
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver,
+				nil, projJobSpecRepoFac, nil, nil, releaseRepoFac)
+			rolledBack, err := svc.RollbackToRelease(ctx, projSpec, "v1.0.0", nil)
+			assert.Nil(t, err)
+			assert.Equal(t, release, rolledBack)
+
+			assert.Equal(t, map[string]storedSpec{
+				"job-a": {namespace1, specA},
+				"job-b": {namespace1, specB},
+				"job-c": {namespace2, specC},
+			}, stored)
+			// unchanged specs are not saved again
+			ns1JobSpecRepo.AssertNotCalled(t, "Save", specB)
+			compiler.AssertNumberOfCalls(t, "Compile", 3)
+		})
+		t.Run("should move job specs back to their released namespace", func(t *testing.T) {
+			specA := models.JobSpec{Name: "job-a", Owner: "tagged"}
+			releaseRepo := new(mock.ReleaseRepository)
+			releaseRepo.On("GetByTag", "v1.0.0").Return(models.Release{
+				Tag:  "v1.0.0",
+				Jobs: []models.ReleaseJob{{Namespace: namespace1, Spec: specA, SpecChecksum: "a1"}},
+			}, nil)
+			releaseRepoFac := new(mock.ReleaseRepoFactory)
+			releaseRepoFac.On("New", projSpec).Return(releaseRepo)
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetModifiedSince", time.Time{}).Return([]models.JobSpec{specA},
+				map[string]models.NamespaceSpec{"job-a": namespace2}, nil)
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+
+			ns1JobSpecRepo := new(mock.JobSpecRepository)
+			ns1JobSpecRepo.On("Save", specA).Return(nil)
+			defer ns1JobSpecRepo.AssertExpectations(t)
+			ns2JobSpecRepo := new(mock.JobSpecRepository)
+			ns2JobSpecRepo.On("Delete", "job-a").Return(nil)
+			defer ns2JobSpecRepo.AssertExpectations(t)
+			jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+			jobSpecRepoFac.On("New", namespace1).Return(ns1JobSpecRepo)
+			jobSpecRepoFac.On("New", namespace2).Return(ns2JobSpecRepo)
+
+			// deployment fails after the specs are restored
+			projectJobSpecRepo.On("GetAll").Return(nil, errors.New("connection closed"))
+
+			svc := job.NewService(jobSpecRepoFac, nil, nil, dumpAssets, nil, nil,
+				nil, projJobSpecRepoFac, nil, nil, releaseRepoFac)
+			_, err := svc.RollbackToRelease(ctx, projSpec, "v1.0.0", nil)
+			assert.Contains(t, err.Error(), "failed to deploy namespace ns1")
+		})
+		t.Run("should fail if release doesn't exist", func(t *testing.T) {
+			releaseRepo := new(mock.ReleaseRepository)
+			releaseRepo.On("GetByTag", "unknown").Return(models.Release{}, store.ErrResourceNotFound)
+			releaseRepoFac := new(mock.ReleaseRepoFactory)
+			releaseRepoFac.On("New", projSpec).Return(releaseRepo)
+
+			svc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil, releaseRepoFac)
+			_, err := svc.RollbackToRelease(ctx, projSpec, "unknown", nil)
+			assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+		})
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/odpf/optimus/core/cron"
 	"github.com/odpf/optimus/core/tree"
 	"github.com/odpf/optimus/models"
@@ -55,6 +56,11 @@ func (srv *Service) Replay(ctx context.Context, replayRequest *models.ReplayWork
 	return replayUUID, nil
 }
 
+// GetReplay returns the replay of jobSpec with the given id
+func (srv *Service) GetReplay(jobSpec models.JobSpec, replayID uuid.UUID) (models.ReplaySpec, error) {
+	return srv.replayManager.GetReplay(jobSpec, replayID)
+}
+
 // prepareTree creates a execution tree for replay operation
 func prepareTree(replayRequest *models.ReplayWorkerRequest) (*tree.TreeNode, error) {
 	replayJobSpec, found := replayRequest.JobSpecMap[replayRequest.Job.Name]
@@ -65,7 +71,7 @@ func prepareTree(replayRequest *models.ReplayWorkerRequest) (*tree.TreeNode, err
 	// compute runs that require replay
 	dagTree := tree.NewMultiRootTree()
 	parentNode := tree.NewTreeNode(replayJobSpec)
-	if runs, err := getRunsBetweenDates(replayRequest.Start, replayRequest.End, replayJobSpec.Schedule.Interval); err == nil {
+	if runs, err := getRunsBetweenDates(replayRequest.Start, replayRequest.End, replayJobSpec.Schedule); err == nil {
 		for _, run := range runs {
 			parentNode.Runs.Add(run)
 		}
@@ -145,6 +151,10 @@ func populateDownstreamRuns(parentNode *tree.TreeNode) (*tree.TreeNode, error) {
 		if err != nil {
 			return nil, err
 		}
+		loc, err := childDag.Schedule.Location()
+		if err != nil {
+			return nil, err
+		}
 
 		for _, parentRunDateRaw := range parentNode.Runs.Values() { //
 			parentRunDate := parentRunDateRaw.(time.Time)
@@ -154,14 +164,14 @@ func populateDownstreamRuns(parentNode *tree.TreeNode) (*tree.TreeNode, error) {
 
 			// subtracting 1 sec to accommodate next call of cron
 			// where parent task and current task has same scheduled interval
-			taskFirstEffectedRun := taskSchedule.Next(parentRunDate.Add(-1 * time.Second))
+			taskFirstEffectedRun := taskSchedule.Next(parentRunDate.Add(-1 * time.Second).In(loc))
 
 			//make sure it is after current dag start date
 			if taskFirstEffectedRun.Before(childDag.Schedule.StartDate) {
 				continue
 			}
 
-			runs, err := getRunsBetweenDates(parentRunDate, parentEndDate, childDag.Schedule.Interval)
+			runs, err := getRunsBetweenDates(parentRunDate, parentEndDate, childDag.Schedule)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to find runs with parent dag")
 			}
@@ -179,15 +189,20 @@ func populateDownstreamRuns(parentNode *tree.TreeNode) (*tree.TreeNode, error) {
 }
 
 // getRunsBetweenDates provides execution runs from start to end following a schedule interval
-// start and end both are inclusive
-func getRunsBetweenDates(start time.Time, end time.Time, schedule string) ([]time.Time, error) {
+// in the timezone of the schedule, runs are returned in utc. start and end both are inclusive
+func getRunsBetweenDates(start time.Time, end time.Time, schedule models.JobSpecSchedule) ([]time.Time, error) {
 	var runs []time.Time
 
 	// standard cron parser without descriptors
-	schd, err := cron.ParseCronSchedule(schedule)
+	schd, err := cron.ParseCronSchedule(schedule.Interval)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := schedule.Location()
 	if err != nil {
 		return nil, err
 	}
+	start, end = start.In(loc), end.In(loc)
 
 	replayRunEnd := schd.Next(end)
 	for replayRunEnd.Before(end.AddDate(0, 0, 1)) {
@@ -196,7 +211,7 @@ func getRunsBetweenDates(start time.Time, end time.Time, schedule string) ([]tim
 
 	// loop until start date reaches end date
 	for run := schd.Next(start.Add(time.Second * -1)); run.Before(replayRunEnd); run = schd.Next(run) {
-		runs = append(runs, run)
+		runs = append(runs, run.UTC())
 	}
 
 	return runs, nil
@@ -21,6 +21,8 @@ var (
 	ErrRequestQueueFull = errors.New("request queue is full")
 	// ErrConflictedJobRun signifies other replay job / dependency run is active or instance already running
 	ErrConflictedJobRun = errors.New("conflicted job run found")
+	// ErrReplayRangeTooLarge signifies the replay covers more days than allowed
+	ErrReplayRangeTooLarge = errors.New("replay date range is too large")
 	//ReplayRunTimeout signifies type of replay failure caused by timeout
 	ReplayRunTimeout = "long running replay timeout"
 	// TimestampLogFormat format of a timestamp will be used in logs
@@ -33,11 +35,17 @@ type ReplayManagerConfig struct {
 	NumWorkers    int
 	WorkerTimeout time.Duration
 	RunTimeout    time.Duration
+
+	// MaxRangeDays limits the days a replay can cover, unlimited if zero
+	MaxRangeDays int
 }
 
 type ReplayManager interface {
 	Init()
 	Replay(context.Context, *models.ReplayWorkerRequest) (string, error)
+	GetReplay(jobSpec models.JobSpec, replayID uuid.UUID) (models.ReplaySpec, error)
+	GetRunStatus(ctx context.Context, projectSpec models.ProjectSpec, startDate time.Time, endDate time.Time,
+		jobName string) ([]models.JobStatus, error)
 }
 
 // Manager for replaying operation(s).
@@ -68,6 +76,13 @@ type Manager struct {
 // Replay a request asynchronously, returns a replay id that can
 // can be used to query its status
 func (m *Manager) Replay(ctx context.Context, reqInput *models.ReplayWorkerRequest) (string, error) {
+	if m.config.MaxRangeDays > 0 {
+		// both start and end date are replayed
+		if rangeDays := int(reqInput.End.Sub(reqInput.Start).Hours()/24) + 1; rangeDays > m.config.MaxRangeDays {
+			return "", errors.Wrapf(ErrReplayRangeTooLarge, "%d days requested, at most %d days allowed", rangeDays, m.config.MaxRangeDays)
+		}
+	}
+
 	replaySpecRepo := m.replaySpecRepoFac.New(reqInput.Job)
 
 	err := m.validate(ctx, replaySpecRepo, reqInput)
@@ -109,6 +124,11 @@ func (m *Manager) Replay(ctx context.Context, reqInput *models.ReplayWorkerReque
 	}
 }
 
+// GetReplay returns the persisted replay of jobSpec with the given id
+func (m *Manager) GetReplay(jobSpec models.JobSpec, replayID uuid.UUID) (models.ReplaySpec, error) {
+	return m.replaySpecRepoFac.New(jobSpec).GetByID(replayID)
+}
+
 func (m *Manager) validate(ctx context.Context, replaySpecRepo store.ReplaySpecRepository, reqInput *models.ReplayWorkerRequest) error {
 	reqReplayTree, err := prepareTree(reqInput)
 	if err != nil {
@@ -220,6 +240,13 @@ func checkAnyConflictedRuns(activeNode *tree.TreeNode, reqNode *tree.TreeNode) e
 	return nil
 }
 
+// GetRunStatus returns the runs of a job known to the scheduler between start and end dates
+func (m *Manager) GetRunStatus(ctx context.Context, projectSpec models.ProjectSpec, startDate time.Time,
+	endDate time.Time, jobName string) ([]models.JobStatus, error) {
+	batchSize := 100
+	return m.scheduler.GetDagRunStatus(ctx, projectSpec, jobName, startDate, endDate, batchSize)
+}
+
 // start a worker goroutine that runs the deployment pipeline in background
 func (m *Manager) spawnServiceWorker() {
 	defer m.wg.Done()
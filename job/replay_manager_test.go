@@ -473,5 +473,48 @@ func TestReplayManager(t *testing.T) {
 			_, err := replayManager.Replay(ctx, replayRequest)
 			assert.Equal(t, errMessage, err.Error())
 		})
+		t.Run("should fail if the replay covers more days than allowed", func(t *testing.T) {
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("GetByStatus", job.ReplayStatusToValidate).Return([]models.ReplaySpec{}, nil)
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
+
+			replayManager := job.NewManager(nil, replaySpecRepoFac, nil, job.ReplayManagerConfig{
+				WorkerTimeout: 1000,
+				MaxRangeDays:  4,
+			}, nil)
+			_, err := replayManager.Replay(ctx, replayRequest)
+			assert.True(t, errors.Is(err, job.ErrReplayRangeTooLarge))
+			assert.Equal(t, "5 days requested, at most 4 days allowed: replay date range is too large", err.Error())
+		})
+	})
+	t.Run("GetReplay", func(t *testing.T) {
+		jobSpec := models.JobSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "job-name",
+		}
+		replaySpec := models.ReplaySpec{
+			ID:     uuid.Must(uuid.NewRandom()),
+			Job:    jobSpec,
+			Status: models.ReplayStatusInProgress,
+		}
+
+		replayRepository := new(mock.ReplayRepository)
+		defer replayRepository.AssertExpectations(t)
+		replayRepository.On("GetByStatus", job.ReplayStatusToValidate).Return([]models.ReplaySpec{}, nil)
+		replayRepository.On("GetByID", replaySpec.ID).Return(replaySpec, nil)
+
+		replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+		defer replaySpecRepoFac.AssertExpectations(t)
+		replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
+		replaySpecRepoFac.On("New", jobSpec).Return(replayRepository)
+
+		replayManager := job.NewManager(nil, replaySpecRepoFac, nil, job.ReplayManagerConfig{WorkerTimeout: 1000}, nil)
+		replay, err := replayManager.GetReplay(jobSpec, replaySpec.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, replaySpec, replay)
 	})
 }
@@ -39,7 +39,7 @@ func getRuns(root *tree.TreeNode, countMap map[string][]time.Time) {
 func TestReplay(t *testing.T) {
 	ctx := context.TODO()
 	noDependency := map[string]models.JobSpecDependency{}
-	dumpAssets := func(jobSpec models.JobSpec, scheduledAt time.Time) (models.JobAssets, error) {
+	dumpAssets := func(jobSpec models.JobSpec, _ models.ProjectSpec, scheduledAt time.Time) (models.JobAssets, error) {
 		return jobSpec.Assets, nil
 	}
 	var (
@@ -107,7 +107,7 @@ func TestReplay(t *testing.T) {
 			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-07")
 
-			jobSvc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil)
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil, nil, nil)
 			replayRequest := &models.ReplayWorkerRequest{
 				Job:     specs[spec1],
 				Start:   replayStart,
@@ -141,7 +141,7 @@ func TestReplay(t *testing.T) {
 			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-07")
 
-			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil)
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil, nil)
 			replayRequest := &models.ReplayWorkerRequest{
 				Job:     specs[spec1],
 				Start:   replayStart,
@@ -184,7 +184,7 @@ func TestReplay(t *testing.T) {
 			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-07")
 
-			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil)
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil, nil)
 			replayRequest := &models.ReplayWorkerRequest{
 				Job:     cyclicDagSpec[0],
 				Start:   replayStart,
@@ -193,8 +193,9 @@ func TestReplay(t *testing.T) {
 			}
 			_, err := jobSvc.ReplayDryRun(replayRequest)
 
+			// cycles are caught while dependencies are resolved, before the tree is built
 			assert.NotNil(t, err)
-			assert.Contains(t, err.Error(), "a cycle dependency encountered in the tree")
+			assert.True(t, errors.Is(err, job.ErrCyclicDependency))
 		})
 
 		t.Run("resolve create replay tree for a dag with three day task window and mentioned dependencies", func(t *testing.T) {
@@ -219,7 +220,7 @@ func TestReplay(t *testing.T) {
 			compiler := new(mock.Compiler)
 			defer compiler.AssertExpectations(t)
 
-			jobSvc := job.NewService(nil, nil, compiler, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil)
+			jobSvc := job.NewService(nil, nil, compiler, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil, nil)
 			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-07")
 			replayRequest := &models.ReplayWorkerRequest{
@@ -271,7 +272,7 @@ func TestReplay(t *testing.T) {
 			compiler := new(mock.Compiler)
 			defer compiler.AssertExpectations(t)
 
-			jobSvc := job.NewService(nil, nil, compiler, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil)
+			jobSvc := job.NewService(nil, nil, compiler, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil, nil)
 			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayRequest := &models.ReplayWorkerRequest{
@@ -302,6 +303,54 @@ func TestReplay(t *testing.T) {
 				assert.Equal(t, expectedRunMap[k], v)
 			}
 		})
+		t.Run("resolve create replay tree for a dag scheduled in another timezone", func(t *testing.T) {
+			jakartaSpec := models.JobSpec{
+				Name:         "dag7-in-jakarta",
+				Dependencies: noDependency,
+				Schedule: models.JobSpecSchedule{
+					StartDate: dagStartTime,
+					Interval:  "0 2 * * *",
+					Timezone:  "Asia/Jakarta",
+				},
+				Task: oneDayTaskWindow,
+			}
+			jakartaDagSpec := []models.JobSpec{jakartaSpec}
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetAll").Return(jakartaDagSpec, nil)
+			defer projectJobSpecRepo.AssertExpectations(t)
+
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+			defer projJobSpecRepoFac.AssertExpectations(t)
+
+			depenResolver := new(mock.DependencyResolver)
+			depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jakartaSpec, nil).Return(jakartaSpec, nil)
+			defer depenResolver.AssertExpectations(t)
+
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
+			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-06")
+			replayRequest := &models.ReplayWorkerRequest{
+				Job:     jakartaSpec,
+				Start:   replayStart,
+				End:     replayEnd,
+				Project: projSpec,
+			}
+
+			tree, err := jobSvc.ReplayDryRun(replayRequest)
+
+			assert.Nil(t, err)
+			countMap := make(map[string][]time.Time)
+			getRuns(tree, countMap)
+			// 2am in jakarta is 7pm utc of the day before
+			assert.Equal(t, map[string][]time.Time{
+				jakartaSpec.Name: {
+					time.Date(2020, time.Month(8), 5, 19, 0, 0, 0, time.UTC),
+					time.Date(2020, time.Month(8), 6, 19, 0, 0, 0, time.UTC),
+				},
+			}, countMap)
+		})
 	})
 
 	t.Run("Replay", func(t *testing.T) {
@@ -317,7 +366,7 @@ func TestReplay(t *testing.T) {
 			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-07")
 
-			jobSvc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil)
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil, nil, nil)
 			replayRequest := &models.ReplayWorkerRequest{
 				Job:     specs[spec1],
 				Start:   replayStart,
@@ -362,7 +411,7 @@ func TestReplay(t *testing.T) {
 			replayManager.On("Replay", ctx, replayRequest).Return("", errors.New(errMessage))
 			defer replayManager.AssertExpectations(t)
 
-			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, replayManager)
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, replayManager, nil, nil)
 
 			_, err := jobSvc.Replay(ctx, replayRequest)
 			assert.NotNil(t, err)
@@ -402,7 +451,7 @@ func TestReplay(t *testing.T) {
 			replayManager.On("Replay", ctx, replayRequest).Return(objUUID.String(), nil)
 			defer replayManager.AssertExpectations(t)
 
-			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, replayManager)
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, replayManager, nil, nil)
 
 			replayUUID, err := jobSvc.Replay(ctx, replayRequest)
 			assert.Nil(t, err)
@@ -0,0 +1,116 @@
+package job
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/odpf/optimus/core/tracing"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// AlignToUpstreamLabel when set to true on a job, marks the job schedule to
+	// follow the schedule of its upstream dependencies
+	AlignToUpstreamLabel = "align_to_upstream"
+)
+
+// PropagateScheduleChanges walks the downstream jobs of rootJobName and proposes
+// a new schedule interval for every job that is labelled to align with its upstream.
+// Propagation continues only through aligned jobs, nothing is persisted, the changes
+// are returned for review.
+// Aligned jobs simply take over the interval of their upstream, start dates are not
+// compared or proposed so runs will only line up if the start dates are aligned too.
+func (srv *Service) PropagateScheduleChanges(ctx context.Context, projectSpec models.ProjectSpec, rootJobName string) (_ []models.ScheduleChange, err error) {
+	_, span := tracing.Start(ctx, "job.Service.PropagateScheduleChanges", attribute.String("project", projectSpec.Name),
+		attribute.String("job", rootJobName))
+	defer func() { tracing.End(span, err) }()
+
+	projectJobSpecRepo := srv.projectJobSpecRepoFactory.New(projectSpec)
+	jobSpecs, err := srv.GetDependencyResolvedSpecs(projectSpec, projectJobSpecRepo, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	jobSpecMap := make(map[string]models.JobSpec)
+	for _, currSpec := range jobSpecs {
+		jobSpecMap[currSpec.Name] = currSpec
+	}
+	rootSpec, ok := jobSpecMap[rootJobName]
+	if !ok {
+		return nil, errors.Wrap(ErrJobSpecNotFound, rootJobName)
+	}
+
+	// build a lookup from a job to the jobs of this project depending on it,
+	// upstream jobs are keyed by project as job names are only unique within a project
+	dependents := make(map[string][]string)
+	for _, currSpec := range jobSpecs {
+		for _, dep := range currSpec.Dependencies {
+			depKey, ok := dependencyKey(projectSpec, dep)
+			if !ok {
+				continue
+			}
+			dependents[depKey] = append(dependents[depKey], currSpec.Name)
+		}
+	}
+	for key := range dependents {
+		sort.Strings(dependents[key])
+	}
+
+	// proposed interval for each visited job, root keeps its own interval
+	intervals := map[string]string{rootSpec.Name: rootSpec.Schedule.Interval}
+	queue := []string{rootSpec.Name}
+	var changes []models.ScheduleChange
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, dependentName := range dependents[jobKey(projectSpec.Name, current)] {
+			if _, visited := intervals[dependentName]; visited {
+				continue
+			}
+			dependentSpec := jobSpecMap[dependentName]
+			if !isAlignedToUpstream(dependentSpec) {
+				continue
+			}
+
+			intervals[dependentName] = intervals[current]
+			if dependentSpec.Schedule.Interval != intervals[current] {
+				changes = append(changes, models.ScheduleChange{
+					JobName:          dependentName,
+					UpstreamJobName:  current,
+					CurrentInterval:  dependentSpec.Schedule.Interval,
+					ProposedInterval: intervals[current],
+				})
+			}
+			queue = append(queue, dependentName)
+		}
+	}
+	return changes, nil
+}
+
+// dependencyKey returns the project qualified name of an upstream job, external
+// dependencies and cross project dependencies without a project are skipped
+func dependencyKey(projectSpec models.ProjectSpec, dep models.JobSpecDependency) (string, bool) {
+	if dep.Job == nil || dep.Type == models.JobSpecDependencyTypeExtra {
+		return "", false
+	}
+	if dep.Project != nil {
+		return jobKey(dep.Project.Name, dep.Job.Name), true
+	}
+	if dep.Type == models.JobSpecDependencyTypeInter {
+		return "", false
+	}
+	return jobKey(projectSpec.Name, dep.Job.Name), true
+}
+
+func jobKey(projectName, jobName string) string {
+	return projectName + "/" + jobName
+}
+
+func isAlignedToUpstream(jobSpec models.JobSpec) bool {
+	val, ok := jobSpec.Labels[AlignToUpstreamLabel]
+	return ok && strings.EqualFold(strings.TrimSpace(val), "true")
+}
@@ -0,0 +1,106 @@
+package job
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/odpf/optimus/core/cron"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+// dstWarningHorizon is how far ahead daylight saving time changes are
+// looked for, every zone observing them changes at least twice a year
+const dstWarningHorizon = time.Hour * 24 * 366
+
+// scheduleDSTWarnings warns about runs of the schedule falling on a wall
+// clock time skipped or repeated by a daylight saving time change of its
+// timezone within a year of from. Like the scheduler, runs in a skipped
+// hour are skipped and runs in a repeated hour only run at the first
+// occurrence
+func scheduleDSTWarnings(jobSpec models.JobSpec, from time.Time) ([]string, error) {
+	schedule := jobSpec.Schedule
+	// utc has no daylight saving time, runs @every duration are independent
+	// of the wall clock
+	if schedule.Timezone == "" || schedule.Interval == "" || strings.HasPrefix(schedule.Interval, "@every") {
+		return nil, nil
+	}
+	loc, err := schedule.Location()
+	if err != nil {
+		return nil, err
+	}
+	cronSchedule, err := cron.ParseCronSchedule(schedule.Interval)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse schedule interval of %s", jobSpec.Name)
+	}
+
+	var warnings []string
+	for _, change := range dstChanges(loc, from, from.Add(dstWarningHorizon)) {
+		// wall clock times around the change are matched in a zone without
+		// daylight saving time, starting at the first ambiguous time
+		shift := time.Duration(change.offsetAfter-change.offsetBefore) * time.Second
+		var wallStart time.Time
+		if shift > 0 {
+			wallStart = change.at.In(time.FixedZone("", change.offsetBefore))
+		} else {
+			shift = -shift
+			wallStart = change.at.In(time.FixedZone("", change.offsetAfter))
+		}
+		run := cronSchedule.Next(wallStart.Add(-time.Second))
+		if run.IsZero() || !run.Before(wallStart.Add(shift)) {
+			continue
+		}
+
+		if change.offsetAfter > change.offsetBefore {
+			warnings = append(warnings, fmt.Sprintf(
+				"schedule %s of job %s doesn't run at %s as the daylight saving time change of %s skips it",
+				schedule.Interval, jobSpec.Name, run.Format("2006-01-02 15:04"), loc))
+		} else {
+			warnings = append(warnings, fmt.Sprintf(
+				"schedule %s of job %s runs once at the first %s as the daylight saving time change of %s repeats it",
+				schedule.Interval, jobSpec.Name, run.Format("2006-01-02 15:04"), loc))
+		}
+	}
+	return warnings, nil
+}
+
+// dstChange is a change of the utc offset of a timezone
+type dstChange struct {
+	at           time.Time
+	offsetBefore int
+	offsetAfter  int
+}
+
+// dstChanges lists the changes of the utc offset of loc between from and to
+func dstChanges(loc *time.Location, from, to time.Time) []dstChange {
+	offset := func(t time.Time) int {
+		_, off := t.In(loc).Zone()
+		return off
+	}
+
+	var changes []dstChange
+	prev := from
+	for prev.Before(to) {
+		next := prev.Add(time.Hour)
+		if offset(prev) != offset(next) {
+			// changes happen at most once within an hour, on a whole second
+			low, high := prev.Unix(), next.Unix()
+			for high-low > 1 {
+				mid := (low + high) / 2
+				if offset(time.Unix(mid, 0)) == offset(time.Unix(low, 0)) {
+					low = mid
+				} else {
+					high = mid
+				}
+			}
+			changes = append(changes, dstChange{
+				at:           time.Unix(high, 0),
+				offsetBefore: offset(time.Unix(low, 0)),
+				offsetAfter:  offset(time.Unix(high, 0)),
+			})
+		}
+		prev = next
+	}
+	return changes
+}
@@ -0,0 +1,165 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropagateScheduleChanges(t *testing.T) {
+	dumpAssets := func(jobSpec models.JobSpec, _ models.ProjectSpec, scheduledAt time.Time) (models.JobAssets, error) {
+		return jobSpec.Assets, nil
+	}
+	ctx := context.Background()
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+	}
+	externalProjSpec := models.ProjectSpec{
+		Name: "external-proj",
+	}
+	startDate, _ := time.Parse(job.ReplayDateFormat, "2021-01-01")
+	hourly := models.JobSpecSchedule{StartDate: startDate, Interval: "0 * * * *"}
+	daily := models.JobSpecSchedule{StartDate: startDate, Interval: "0 0 * * *"}
+	aligned := map[string]string{job.AlignToUpstreamLabel: "true"}
+
+	newSpec := func(name string, schedule models.JobSpecSchedule, labels map[string]string, upstreams ...models.JobSpec) models.JobSpec {
+		deps := map[string]models.JobSpecDependency{}
+		for i := range upstreams {
+			deps[upstreams[i].Name] = models.JobSpecDependency{
+				Job:     &upstreams[i],
+				Project: &projSpec,
+				Type:    models.JobSpecDependencyTypeIntra,
+			}
+		}
+		return models.JobSpec{Name: name, Schedule: schedule, Labels: labels, Dependencies: deps}
+	}
+	// chain of 5 jobs, root schedule is changed from hourly to daily
+	// and every downstream job is still hourly
+	chainOf5 := func(labels ...map[string]string) []models.JobSpec {
+		specs := []models.JobSpec{newSpec("job-1", daily, nil)}
+		for i, name := range []string{"job-2", "job-3", "job-4", "job-5"} {
+			specs = append(specs, newSpec(name, hourly, labels[i], specs[i]))
+		}
+		return specs
+	}
+	change := func(name, upstream string) models.ScheduleChange {
+		return models.ScheduleChange{JobName: name, UpstreamJobName: upstream, CurrentInterval: hourly.Interval, ProposedInterval: daily.Interval}
+	}
+
+	// setup wires the mocks for a successful dependency resolution of the given specs
+	setup := func(t *testing.T, specs []models.JobSpec) *job.Service {
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepo.On("GetAll").Return(specs, nil)
+		t.Cleanup(func() { projectJobSpecRepo.AssertExpectations(t) })
+
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+		t.Cleanup(func() { projJobSpecRepoFac.AssertExpectations(t) })
+
+		depenResolver := new(mock.DependencyResolver)
+		for _, spec := range specs {
+			depenResolver.On("Resolve", projSpec, projectJobSpecRepo, spec, nil).Return(spec, nil)
+		}
+		t.Cleanup(func() { depenResolver.AssertExpectations(t) })
+
+		return job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+	}
+
+	diamondRoot := newSpec("root", daily, nil)
+	diamondLeft := newSpec("left", hourly, aligned, diamondRoot)
+	diamondRight := newSpec("right", hourly, aligned, diamondRoot)
+	diamondBottom := newSpec("bottom", hourly, aligned, diamondLeft, diamondRight)
+
+	// job-1 of another project shares the name of the root job of this project
+	externalRoot := models.JobSpec{Name: "job-1", Schedule: hourly}
+	crossProjectDependent := newSpec("cross-project-dependent", hourly, aligned)
+	crossProjectDependent.Dependencies["job-1"] = models.JobSpecDependency{
+		Job:     &externalRoot,
+		Project: &externalProjSpec,
+		Type:    models.JobSpecDependencyTypeInter,
+	}
+
+	cases := []struct {
+		name     string
+		specs    []models.JobSpec
+		root     string
+		expected []models.ScheduleChange
+	}{
+		{
+			name:     "should propose schedule for all aligned downstream jobs",
+			specs:    chainOf5(aligned, aligned, aligned, aligned),
+			root:     "job-1",
+			expected: []models.ScheduleChange{change("job-2", "job-1"), change("job-3", "job-2"), change("job-4", "job-3"), change("job-5", "job-4")},
+		},
+		{
+			name:     "should stop propagation at a job not aligned to its upstream",
+			specs:    chainOf5(aligned, map[string]string{"owner": "team"}, aligned, aligned),
+			root:     "job-1",
+			expected: []models.ScheduleChange{change("job-2", "job-1")},
+		},
+		{
+			name: "should not propose a change for aligned job already matching the upstream schedule",
+			specs: []models.JobSpec{
+				newSpec("job-1", daily, nil),
+				newSpec("job-2", daily, aligned, newSpec("job-1", daily, nil)),
+				newSpec("job-3", hourly, aligned, newSpec("job-2", daily, aligned)),
+			},
+			root:     "job-1",
+			expected: []models.ScheduleChange{change("job-3", "job-2")},
+		},
+		{
+			name: "should not propose a change for job not aligned to its upstream even if the schedule matches",
+			specs: []models.JobSpec{
+				newSpec("job-1", daily, nil),
+				newSpec("job-2", daily, nil, newSpec("job-1", daily, nil)),
+			},
+			root:     "job-1",
+			expected: nil,
+		},
+		{
+			name:     "should propose a single change for a job reachable through multiple upstreams",
+			specs:    []models.JobSpec{diamondRoot, diamondLeft, diamondRight, diamondBottom},
+			root:     "root",
+			expected: []models.ScheduleChange{change("left", "root"), change("right", "root"), change("bottom", "left")},
+		},
+		{
+			name:     "should ignore dependency on job of another project with the same name",
+			specs:    []models.JobSpec{newSpec("job-1", daily, nil), crossProjectDependent},
+			root:     "job-1",
+			expected: nil,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			jobSvc := setup(t, tc.specs)
+			changes, err := jobSvc.PropagateScheduleChanges(ctx, projSpec, tc.root)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, changes)
+		})
+	}
+
+	t.Run("should fail if root job is not found", func(t *testing.T) {
+		jobSvc := setup(t, chainOf5(aligned, aligned, aligned, aligned))
+		_, err := jobSvc.PropagateScheduleChanges(ctx, projSpec, "job-0")
+		assert.True(t, errors.Is(err, job.ErrJobSpecNotFound))
+	})
+	t.Run("should fail if unable to fetch jobSpecs from project jobSpecRepo", func(t *testing.T) {
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepo.On("GetAll").Return(nil, errors.New("error while getting all dags"))
+		defer projectJobSpecRepo.AssertExpectations(t)
+
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+		defer projJobSpecRepoFac.AssertExpectations(t)
+
+		jobSvc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+		_, err := jobSvc.PropagateScheduleChanges(ctx, projSpec, "job-1")
+		assert.NotNil(t, err)
+	})
+}
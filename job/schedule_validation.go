@@ -0,0 +1,65 @@
+package job
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/odpf/optimus/core/cron"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultScheduleValidationRuns is the number of upcoming runs listed
+	// when validating a schedule if not set
+	DefaultScheduleValidationRuns = 5
+
+	// MaxScheduleValidationRuns is the most upcoming runs listed when
+	// validating a schedule
+	MaxScheduleValidationRuns = 100
+)
+
+// ValidateSchedule parses a cron schedule interval and lists the count runs
+// following from in the timezone of the schedule. Schedules running more
+// often than once a minute or never running are valid but warned about
+func ValidateSchedule(schedule models.JobSpecSchedule, from time.Time, count int) (models.ScheduleValidation, error) {
+	if count <= 0 {
+		count = DefaultScheduleValidationRuns
+	}
+	if count > MaxScheduleValidationRuns {
+		return models.ScheduleValidation{}, errors.Errorf("at most %d runs can be listed", MaxScheduleValidationRuns)
+	}
+	loc, err := schedule.Location()
+	if err != nil {
+		return models.ScheduleValidation{}, err
+	}
+	interval := schedule.Interval
+	cronSchedule, err := cron.ParseCronSchedule(interval)
+	if err != nil {
+		return models.ScheduleValidation{}, errors.Wrapf(err, "failed to parse schedule %s", interval)
+	}
+
+	validation := models.ScheduleValidation{}
+	next := from.In(loc)
+	for len(validation.NextRuns) < count {
+		// zero if the schedule doesn't run within the next five years
+		if next = cronSchedule.Next(next); next.IsZero() {
+			break
+		}
+		validation.NextRuns = append(validation.NextRuns, next)
+	}
+	if len(validation.NextRuns) == 0 {
+		validation.Warnings = append(validation.Warnings, fmt.Sprintf("schedule %s never runs", interval))
+		return validation, nil
+	}
+
+	shortest, err := minScheduleInterval(schedule, from)
+	if err != nil {
+		return models.ScheduleValidation{}, err
+	}
+	if shortest > 0 && shortest < time.Minute {
+		validation.Warnings = append(validation.Warnings,
+			fmt.Sprintf("schedule %s runs every %s, more often than once a minute", interval, shortest))
+	}
+	return validation, nil
+}
@@ -0,0 +1,83 @@
+package job_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	from := time.Date(2021, 2, 27, 10, 30, 0, 0, time.UTC)
+
+	t.Run("should list the next five runs by default", func(t *testing.T) {
+		validation, err := job.ValidateSchedule(models.JobSpecSchedule{Interval: "0 2 * * *"}, from, 0)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2021, 2, 28, 2, 0, 0, 0, time.UTC),
+			time.Date(2021, 3, 1, 2, 0, 0, 0, time.UTC),
+			time.Date(2021, 3, 2, 2, 0, 0, 0, time.UTC),
+			time.Date(2021, 3, 3, 2, 0, 0, 0, time.UTC),
+			time.Date(2021, 3, 4, 2, 0, 0, 0, time.UTC),
+		}, validation.NextRuns)
+		assert.Empty(t, validation.Warnings)
+	})
+	t.Run("should list the requested number of runs", func(t *testing.T) {
+		validation, err := job.ValidateSchedule(models.JobSpecSchedule{Interval: "@hourly"}, from, 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2021, 2, 27, 11, 0, 0, 0, time.UTC),
+			time.Date(2021, 2, 27, 12, 0, 0, 0, time.UTC),
+		}, validation.NextRuns)
+	})
+	t.Run("should list the runs in the timezone of the schedule", func(t *testing.T) {
+		validation, err := job.ValidateSchedule(models.JobSpecSchedule{
+			Interval: "0 2 * * *",
+			Timezone: "Asia/Jakarta",
+		}, from, 2)
+
+		assert.Nil(t, err)
+		jakarta, _ := time.LoadLocation("Asia/Jakarta")
+		assert.Equal(t, []time.Time{
+			time.Date(2021, 2, 28, 2, 0, 0, 0, jakarta),
+			time.Date(2021, 3, 1, 2, 0, 0, 0, jakarta),
+		}, validation.NextRuns)
+	})
+	t.Run("should return error if the timezone is unknown", func(t *testing.T) {
+		_, err := job.ValidateSchedule(models.JobSpecSchedule{
+			Interval: "0 2 * * *",
+			Timezone: "Asia/Nowhere",
+		}, from, 0)
+
+		assert.True(t, errors.Is(err, models.ErrInvalidTimezone))
+	})
+	t.Run("should warn if the schedule runs more often than once a minute", func(t *testing.T) {
+		validation, err := job.ValidateSchedule(models.JobSpecSchedule{Interval: "@every 30s"}, from, 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []time.Time{from.Add(time.Second * 30)}, validation.NextRuns)
+		assert.Equal(t, []string{"schedule @every 30s runs every 30s, more often than once a minute"}, validation.Warnings)
+	})
+	t.Run("should warn if the schedule never runs", func(t *testing.T) {
+		validation, err := job.ValidateSchedule(models.JobSpecSchedule{Interval: "0 0 30 2 *"}, from, 0)
+
+		assert.Nil(t, err)
+		assert.Empty(t, validation.NextRuns)
+		assert.Equal(t, []string{"schedule 0 0 30 2 * never runs"}, validation.Warnings)
+	})
+	t.Run("should return error if the schedule can't be parsed", func(t *testing.T) {
+		_, err := job.ValidateSchedule(models.JobSpecSchedule{Interval: "0 25 * * *"}, from, 0)
+
+		assert.EqualError(t, err, "failed to parse schedule 0 25 * * *: end of range (25) above maximum (23): 25")
+	})
+	t.Run("should return error if too many runs are requested", func(t *testing.T) {
+		_, err := job.ValidateSchedule(models.JobSpecSchedule{Interval: "@daily"}, from, job.MaxScheduleValidationRuns+1)
+
+		assert.EqualError(t, err, "at most 100 runs can be listed")
+	})
+}
@@ -0,0 +1,95 @@
+package job
+
+import (
+	"context"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// QueryAssetName is the asset holding the query of jobs writing the
+	// result of a query to a table
+	QueryAssetName = "query.sql"
+)
+
+var (
+	// ErrSchemaDriftUnsupported is returned for jobs not writing a query result
+	// to a table of a datastore that can be inspected
+	ErrSchemaDriftUnsupported = errors.New("schema drift detection is not supported for the job")
+)
+
+// DetectSchemaDrift compares the schema of the destination table of a job with
+// the schema of its compiled query, found through a dry run of the query
+func (srv *Service) DetectSchemaDrift(ctx context.Context, projectSpec models.ProjectSpec, jobSpec models.JobSpec) (*models.SchemaDrift, error) {
+	if srv.SchemaInspector == nil || jobSpec.Task.Unit == nil || jobSpec.Task.Unit.DependencyMod == nil {
+		return nil, ErrSchemaDriftUnsupported
+	}
+	if _, err := jobSpec.Assets.GetByName(QueryAssetName); err != nil {
+		return nil, ErrSchemaDriftUnsupported
+	}
+
+	assets, err := srv.assetCompiler(jobSpec, projectSpec, srv.Now())
+	if err != nil {
+		return nil, errors.Wrap(err, "asset compilation")
+	}
+	query, err := assets.GetByName(QueryAssetName)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := jobSpec.Task.Unit.DependencyMod.GenerateDestination(ctx, models.GenerateDestinationRequest{
+		Config:  models.PluginConfigs{}.FromJobSpec(jobSpec.Task.Config),
+		Assets:  models.PluginAssets{}.FromJobSpec(assets),
+		Project: projectSpec,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to generate destination of %s", jobSpec.Name)
+	}
+
+	tableFields, err := srv.SchemaInspector.TableSchema(ctx, projectSpec, destination.Destination)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read schema of %s", destination.Destination)
+	}
+	queryFields, err := srv.SchemaInspector.QuerySchema(ctx, projectSpec, query.Value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to infer schema of the query of %s", jobSpec.Name)
+	}
+
+	drift := diffSchema(tableFields, queryFields)
+	drift.Destination = destination.Destination
+	return &drift, nil
+}
+
+// diffSchema lists added columns and type changes in the order of the query
+// fields and removed columns in the order of the table fields
+func diffSchema(tableFields, queryFields []models.SchemaField) models.SchemaDrift {
+	tableTypes := map[string]string{}
+	for _, field := range tableFields {
+		tableTypes[field.Name] = field.Type
+	}
+	queryTypes := map[string]string{}
+	for _, field := range queryFields {
+		queryTypes[field.Name] = field.Type
+	}
+
+	var drift models.SchemaDrift
+	for _, field := range queryFields {
+		tableType, ok := tableTypes[field.Name]
+		if !ok {
+			drift.AddedColumns = append(drift.AddedColumns, field)
+		} else if tableType != field.Type {
+			drift.TypeChanges = append(drift.TypeChanges, models.SchemaTypeChange{
+				Name:         field.Name,
+				CurrentType:  tableType,
+				ExpectedType: field.Type,
+			})
+		}
+	}
+	for _, field := range tableFields {
+		if _, ok := queryTypes[field.Name]; !ok {
+			drift.RemovedColumns = append(drift.RemovedColumns, field)
+		}
+	}
+	return drift
+}
@@ -0,0 +1,112 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestDetectSchemaDrift(t *testing.T) {
+	ctx := context.Background()
+	dumpAssets := func(jobSpec models.JobSpec, _ models.ProjectSpec, scheduledAt time.Time) (models.JobAssets, error) {
+		return jobSpec.Assets, nil
+	}
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+	}
+	query := "SELECT id, name, amount FROM `proj.dataset.source`"
+	newJobSpec := func(depMod models.DependencyResolverMod) models.JobSpec {
+		return models.JobSpec{
+			Name: "bq-job",
+			Task: models.JobSpecTask{
+				Unit: &models.Plugin{DependencyMod: depMod},
+			},
+			Assets: *models.JobAssets{}.New([]models.JobSpecAsset{
+				{Name: job.QueryAssetName, Value: query},
+			}),
+		}
+	}
+	newService := func(inspector models.DatastoreSchemaInspector) *job.Service {
+		svc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil, nil)
+		svc.SchemaInspector = inspector
+		return svc
+	}
+
+	t.Run("should return added, removed and retyped columns", func(t *testing.T) {
+		depMod := new(mock.DependencyResolverMod)
+		defer depMod.AssertExpectations(t)
+		jobSpec := newJobSpec(depMod)
+		depMod.On("GenerateDestination", ctx, models.GenerateDestinationRequest{
+			Config:  models.PluginConfigs{}.FromJobSpec(jobSpec.Task.Config),
+			Assets:  models.PluginAssets{}.FromJobSpec(jobSpec.Assets),
+			Project: projSpec,
+		}).Return(&models.GenerateDestinationResponse{Destination: "proj:dataset.table"}, nil)
+
+		inspector := new(mock.DatastoreSchemaInspector)
+		defer inspector.AssertExpectations(t)
+		inspector.On("TableSchema", ctx, projSpec, "proj:dataset.table").Return([]models.SchemaField{
+			{Name: "id", Type: "INTEGER"},
+			{Name: "amount", Type: "INTEGER"},
+			{Name: "legacy_code", Type: "STRING"},
+		}, nil)
+		inspector.On("QuerySchema", ctx, projSpec, query).Return([]models.SchemaField{
+			{Name: "id", Type: "INTEGER"},
+			{Name: "name", Type: "STRING"},
+			{Name: "amount", Type: "FLOAT"},
+		}, nil)
+
+		drift, err := newService(inspector).DetectSchemaDrift(ctx, projSpec, jobSpec)
+		assert.Nil(t, err)
+		assert.True(t, drift.HasDrift())
+		assert.Equal(t, &models.SchemaDrift{
+			Destination:    "proj:dataset.table",
+			AddedColumns:   []models.SchemaField{{Name: "name", Type: "STRING"}},
+			RemovedColumns: []models.SchemaField{{Name: "legacy_code", Type: "STRING"}},
+			TypeChanges:    []models.SchemaTypeChange{{Name: "amount", CurrentType: "INTEGER", ExpectedType: "FLOAT"}},
+		}, drift)
+	})
+	t.Run("should return no drift when schemas match", func(t *testing.T) {
+		depMod := new(mock.DependencyResolverMod)
+		defer depMod.AssertExpectations(t)
+		depMod.On("GenerateDestination", ctx, mock2.Anything).Return(&models.GenerateDestinationResponse{Destination: "proj:dataset.table"}, nil)
+
+		fields := []models.SchemaField{{Name: "id", Type: "INTEGER"}}
+		inspector := new(mock.DatastoreSchemaInspector)
+		defer inspector.AssertExpectations(t)
+		inspector.On("TableSchema", ctx, projSpec, "proj:dataset.table").Return(fields, nil)
+		inspector.On("QuerySchema", ctx, projSpec, query).Return(fields, nil)
+
+		drift, err := newService(inspector).DetectSchemaDrift(ctx, projSpec, newJobSpec(depMod))
+		assert.Nil(t, err)
+		assert.False(t, drift.HasDrift())
+	})
+	t.Run("should not support jobs without a query asset", func(t *testing.T) {
+		jobSpec := newJobSpec(new(mock.DependencyResolverMod))
+		jobSpec.Assets = *models.JobAssets{}.New(nil)
+
+		_, err := newService(new(mock.DatastoreSchemaInspector)).DetectSchemaDrift(ctx, projSpec, jobSpec)
+		assert.Equal(t, job.ErrSchemaDriftUnsupported, err)
+	})
+	t.Run("should not support jobs when no schema inspector is set", func(t *testing.T) {
+		_, err := newService(nil).DetectSchemaDrift(ctx, projSpec, newJobSpec(new(mock.DependencyResolverMod)))
+		assert.Equal(t, job.ErrSchemaDriftUnsupported, err)
+	})
+	t.Run("should fail if the table schema can't be read", func(t *testing.T) {
+		depMod := new(mock.DependencyResolverMod)
+		depMod.On("GenerateDestination", ctx, mock2.Anything).Return(&models.GenerateDestinationResponse{Destination: "proj:dataset.table"}, nil)
+
+		inspector := new(mock.DatastoreSchemaInspector)
+		defer inspector.AssertExpectations(t)
+		inspector.On("TableSchema", ctx, projSpec, "proj:dataset.table").Return(nil, errors.New("not found"))
+
+		_, err := newService(inspector).DetectSchemaDrift(ctx, projSpec, newJobSpec(depMod))
+		assert.Equal(t, "failed to read schema of proj:dataset.table: not found", err.Error())
+	})
+}
@@ -3,17 +3,21 @@ package job
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
 
 	"github.com/kushsharma/parallel"
 	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/core/tracing"
 	"github.com/odpf/optimus/meta"
 	"github.com/odpf/optimus/models"
 	"github.com/odpf/optimus/store"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -24,7 +28,19 @@ const (
 	ConcurrentLimit        = 600
 )
 
-type AssetCompiler func(jobSpec models.JobSpec, scheduledAt time.Time) (models.JobAssets, error)
+var (
+	// ErrCrossProjectListingUnsupported is returned when jobs of all
+	// projects can't be read
+	ErrCrossProjectListingUnsupported = errors.New("listing jobs across projects is not supported")
+
+	// ErrCrossProjectPurgeUnsupported is returned when deleted jobs of all
+	// projects can't be purged
+	ErrCrossProjectPurgeUnsupported = errors.New("purging deleted jobs across projects is not supported")
+)
+
+// AssetCompiler renders the assets of a job of the project with the
+// variables and template engine of the project
+type AssetCompiler func(jobSpec models.JobSpec, projectSpec models.ProjectSpec, scheduledAt time.Time) (models.JobAssets, error)
 
 // DependencyResolver compiles static and runtime dependencies
 type DependencyResolver interface {
@@ -52,6 +68,11 @@ type JobRepoFactory interface {
 	New(context.Context, models.ProjectSpec) (store.JobRepository, error)
 }
 
+// DependencyPinRepoFactory is used to manage the dependency pins of jobs in a project
+type DependencyPinRepoFactory interface {
+	New(proj models.ProjectSpec) store.DependencyPinRepository
+}
+
 // ReplaySpecRepoFactory is used to manage replay spec objects from store
 type ReplaySpecRepoFactory interface {
 	New(jobSpec models.JobSpec) store.ReplaySpecRepository
@@ -69,20 +90,121 @@ type Service struct {
 	metaSvcFactory            meta.MetaSvcFactory
 	projectJobSpecRepoFactory ProjectJobSpecRepoFactory
 	replayManager             ReplayManager
+	dependencyPinRepoFactory  DependencyPinRepoFactory
+	releaseRepoFactory        ReleaseRepoFactory
 
 	Now           func() time.Time
 	assetCompiler AssetCompiler
+
+	// MaxBackfillLookback is the age after which missing runs are no longer backfilled
+	MaxBackfillLookback time.Duration
+
+	// PauseRepo reads the times jobs were paused, runs scheduled while a job
+	// was paused are backfilled if not set
+	PauseRepo store.JobPauseRepository
+
+	// SchemaInspector reads schemas of job destinations, schema drift
+	// detection and asset reference validation are not supported if not set
+	SchemaInspector models.DatastoreSchemaInspector
+
+	// CostEstimator reads the bytes processed by queries of jobs, costs
+	// can't be estimated if not set
+	CostEstimator models.DatastoreCostEstimator
+
+	tableExistenceMu    sync.Mutex
+	tableExistenceCache map[string]cachedTableExistence
+
+	// CrossProjectJobSpecRepo reads and writes job specs of all projects,
+	// jobs can't be listed by owner, purged or cloned to another project if
+	// not set
+	CrossProjectJobSpecRepo store.JobSpecRepository
+
+	// Scheduler lists jobs deployed to the scheduler, jobs left deployed
+	// without a spec in the project aren't looked up during sync if not set
+	Scheduler models.SchedulerUnit
+
+	// LifecycleObserver is notified of every job deployed across requests,
+	// e.g. to call the webhooks of projects
+	LifecycleObserver progress.Observer
+
+	// DefaultQuota limits the jobs of projects that haven't set their own
+	// limits, unlimited if not set
+	DefaultQuota models.ProjectQuota
 }
 
-// Create constructs a Job for a namespace and commits it to the store
+// Create constructs a Job for a namespace and commits it to the store, the
+// templates the job extends are merged into it. Jobs over the quota of the
+// project are rejected with models.ErrQuotaExceeded, specs with an unknown
+// timezone with models.ErrInvalidTimezone
 func (srv *Service) Create(namespace models.NamespaceSpec, spec models.JobSpec) error {
-	jobRepo := srv.jobSpecRepoFactory.New(namespace)
-	if err := jobRepo.Save(spec); err != nil {
-		return errors.Wrapf(err, "failed to save job: %s", spec.Name)
+	return srv.SaveAll(namespace, []models.JobSpec{spec})
+}
+
+// SaveAll creates or updates the job specs of a namespace one after another
+// like Create, templates have to come before the jobs extending them. The
+// jobs of the project are counted once for the quota of all specs
+func (srv *Service) SaveAll(namespace models.NamespaceSpec, specs []models.JobSpec) error {
+	quota, err := srv.newJobQuota(namespace)
+	if err != nil {
+		return err
+	}
+	var jobRepo SpecRepository
+	for _, spec := range specs {
+		spec, err := srv.ResolveInheritance(namespace.ProjectSpec, spec)
+		if err != nil {
+			return err
+		}
+		if _, err := spec.Schedule.Location(); err != nil {
+			return errors.Wrapf(err, "failed to save job: %s", spec.Name)
+		}
+		if err := quota.check(spec); err != nil {
+			return err
+		}
+		if jobRepo == nil {
+			jobRepo = srv.jobSpecRepoFactory.New(namespace)
+		}
+		if err := jobRepo.Save(spec); err != nil {
+			return errors.Wrapf(err, "failed to save job: %s", spec.Name)
+		}
 	}
 	return nil
 }
 
+// CreateAll commits new job specs of a namespace to the store at once, none
+// of them are saved if any fails
+func (srv *Service) CreateAll(namespace models.NamespaceSpec, specs []models.JobSpec) error {
+	specs = append([]models.JobSpec(nil), specs...)
+	for i := range specs {
+		resolved, err := srv.ResolveInheritance(namespace.ProjectSpec, specs[i])
+		if err != nil {
+			return err
+		}
+		if _, err := resolved.Schedule.Location(); err != nil {
+			return errors.Wrapf(err, "failed to save job: %s", resolved.Name)
+		}
+		specs[i] = resolved
+	}
+	if err := srv.checkQuota(namespace, specs); err != nil {
+		return err
+	}
+	if err := srv.jobSpecRepoFactory.New(namespace).InsertAll(specs); err != nil {
+		return errors.Wrapf(err, "failed to save %d jobs", len(specs))
+	}
+	return nil
+}
+
+// GetJobsByOwner lists the jobs of all projects owned by owner
+func (srv *Service) GetJobsByOwner(ctx context.Context, owner string) ([]models.JobSpecWithNamespace, error) {
+	if srv.CrossProjectJobSpecRepo == nil {
+		return nil, ErrCrossProjectListingUnsupported
+	}
+	jobSpecs, err := srv.CrossProjectJobSpecRepo.GetJobsByOwner(ctx, owner)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list jobs of owner %s", owner)
+	}
+	return jobSpecs, nil
+}
+
 // GetByName fetches a Job by name for a specific namespace
 func (srv *Service) GetByName(name string, namespace models.NamespaceSpec) (models.JobSpec, error) {
 	jobSpec, err := srv.jobSpecRepoFactory.New(namespace).GetByName(name)
@@ -92,6 +214,16 @@ func (srv *Service) GetByName(name string, namespace models.NamespaceSpec) (mode
 	return jobSpec, nil
 }
 
+// GetSpecChecksums returns the checksums of job specs in a namespace keyed
+// by job name
+func (srv *Service) GetSpecChecksums(names []string, namespace models.NamespaceSpec) (map[string]string, error) {
+	checksums, err := srv.jobSpecRepoFactory.New(namespace).GetSpecChecksums(names)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve checksums of jobs in namespace %s", namespace.Name)
+	}
+	return checksums, nil
+}
+
 // GetByNameForProject fetches a Job by name for a specific project
 func (srv *Service) GetByNameForProject(name string, proj models.ProjectSpec) (models.JobSpec, models.NamespaceSpec, error) {
 	jobSpec, namespace, err := srv.projectJobSpecRepoFactory.New(proj).GetByName(name)
@@ -109,6 +241,17 @@ func (srv *Service) GetAll(namespace models.NamespaceSpec) ([]models.JobSpec, er
 	return jobSpecs, nil
 }
 
+// GetPage returns at most limit job specs of a namespace ordered by name,
+// starting after the job named afterName and having all of labels
+func (srv *Service) GetPage(namespace models.NamespaceSpec, afterName string, limit int,
+	labels map[string]string) ([]models.JobSpec, error) {
+	jobSpecs, err := srv.jobSpecRepoFactory.New(namespace).GetPage(afterName, limit, labels)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve jobs")
+	}
+	return jobSpecs, nil
+}
+
 // Dump takes a jobSpec of a project, resolves dependencies, priorities and returns the compiled Job
 func (srv *Service) Dump(namespace models.NamespaceSpec, jobSpec models.JobSpec) (models.Job, error) {
 	projectJobSpecRepo := srv.projectJobSpecRepoFactory.New(namespace.ProjectSpec)
@@ -146,7 +289,7 @@ func (srv *Service) Dump(namespace models.NamespaceSpec, jobSpec models.JobSpec)
 func (srv *Service) Check(namespace models.NamespaceSpec, jobSpecs []models.JobSpec, obs progress.Observer) (err error) {
 	for i, jSpec := range jobSpecs {
 		// compile assets
-		if jobSpecs[i].Assets, err = srv.assetCompiler(jSpec, srv.Now()); err != nil {
+		if jobSpecs[i].Assets, err = srv.assetCompiler(jSpec, namespace.ProjectSpec, srv.Now()); err != nil {
 			return errors.Wrap(err, "asset compilation")
 		}
 
@@ -176,7 +319,8 @@ func (srv *Service) Check(namespace models.NamespaceSpec, jobSpecs []models.JobS
 				}
 
 				// check compilation
-				if _, err := srv.compiler.Compile(namespace, currentSpec); err != nil {
+				compiledJob, err := srv.compiler.Compile(namespace, currentSpec)
+				if err != nil {
 					if obs != nil {
 						obs.Notify(&EventJobCheckFailed{Name: currentSpec.Name, Reason: fmt.Sprintf("compilation: %s\n", err.Error())})
 					}
@@ -184,6 +328,9 @@ func (srv *Service) Check(namespace models.NamespaceSpec, jobSpecs []models.JobS
 				}
 
 				if obs != nil {
+					for _, warning := range compiledJob.Warnings {
+						obs.Notify(&EventJobCompileWarning{Name: currentSpec.Name, Warning: warning})
+					}
 					obs.Notify(&EventJobCheckSuccess{Name: currentSpec.Name})
 				}
 				return nil, nil
@@ -199,7 +346,11 @@ func (srv *Service) Check(namespace models.NamespaceSpec, jobSpecs []models.JobS
 }
 
 // Delete deletes a job spec from all spec repos
-func (srv *Service) Delete(ctx context.Context, namespace models.NamespaceSpec, jobSpec models.JobSpec) error {
+func (srv *Service) Delete(ctx context.Context, namespace models.NamespaceSpec, jobSpec models.JobSpec) (err error) {
+	ctx, span := tracing.Start(ctx, "job.Service.Delete", attribute.String("project", namespace.ProjectSpec.Name),
+		attribute.String("namespace", namespace.Name), attribute.String("job", jobSpec.Name))
+	defer func() { tracing.End(span, err) }()
+
 	if err := srv.isJobDeletable(namespace.ProjectSpec, jobSpec); err != nil {
 		return err
 	}
@@ -216,23 +367,61 @@ func (srv *Service) Delete(ctx context.Context, namespace models.NamespaceSpec,
 	return nil
 }
 
+// Undelete restores a soft deleted job spec of the namespace, the namespace
+// is synced so the job is scheduled again
+func (srv *Service) Undelete(ctx context.Context, namespace models.NamespaceSpec, jobName string) (err error) {
+	ctx, span := tracing.Start(ctx, "job.Service.Undelete", attribute.String("project", namespace.ProjectSpec.Name),
+		attribute.String("namespace", namespace.Name), attribute.String("job", jobName))
+	defer func() { tracing.End(span, err) }()
+
+	if err := srv.jobSpecRepoFactory.New(namespace).Undelete(jobName); err != nil {
+		return errors.Wrapf(err, "failed to undelete spec: %s", jobName)
+	}
+	return srv.Sync(ctx, namespace, nil)
+}
+
+// PurgeDeleted permanently removes job specs of all projects soft deleted
+// more than olderThan ago, deleted specs can't be restored afterwards
+func (srv *Service) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int, error) {
+	if srv.CrossProjectJobSpecRepo == nil {
+		return 0, ErrCrossProjectPurgeUnsupported
+	}
+	purged, err := srv.CrossProjectJobSpecRepo.PurgeDeleted(ctx, olderThan)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to purge deleted jobs")
+	}
+	return purged, nil
+}
+
 // Sync fetches all the jobs that belong to a project, resolves its dependencies
 // assign proper priority weights, compiles it and uploads it to the destination
 // store
-func (srv *Service) Sync(ctx context.Context, namespace models.NamespaceSpec, progressObserver progress.Observer) error {
+func (srv *Service) Sync(ctx context.Context, namespace models.NamespaceSpec, progressObserver progress.Observer) (err error) {
+	ctx, span := tracing.Start(ctx, "job.Service.Sync", attribute.String("project", namespace.ProjectSpec.Name),
+		attribute.String("namespace", namespace.Name))
+	defer func() { tracing.End(span, err) }()
+
 	projectJobSpecRepo := srv.projectJobSpecRepoFactory.New(namespace.ProjectSpec)
+	_, resolveSpan := tracing.Start(ctx, "job.Service.GetDependencyResolvedSpecs")
 	jobSpecs, err := srv.GetDependencyResolvedSpecs(namespace.ProjectSpec, projectJobSpecRepo, progressObserver)
+	tracing.End(resolveSpan, err)
 	if err != nil {
 		return err
 	}
 	srv.notifyProgress(progressObserver, &EventJobSpecDependencyResolve{})
 
+	_, prioritySpan := tracing.Start(ctx, "job.PriorityResolver.Resolve")
 	jobSpecs, err = srv.priorityResolver.Resolve(jobSpecs)
+	tracing.End(prioritySpan, err)
 	if err != nil {
 		return err
 	}
 	srv.notifyProgress(progressObserver, &EventJobPriorityWeightAssign{})
 
+	var projectJobNames []string
+	for _, jobSpec := range jobSpecs {
+		projectJobNames = append(projectJobNames, jobSpec.Name)
+	}
 	jobSpecs, err = srv.filterJobSpecForNamespace(jobSpecs, namespace)
 	if err != nil {
 		return err
@@ -271,9 +460,40 @@ func (srv *Service) Sync(ctx context.Context, namespace models.NamespaceSpec, pr
 		}
 		srv.notifyProgress(progressObserver, &EventJobRemoteDelete{dagName})
 	}
+
+	// jobs still deployed to the scheduler without a spec in the project are
+	// deleted too, compiled jobs of other namespaces are not found here and
+	// are left to the sync of their namespace
+	staleJobNames := jobDeletionFilter(setSubstract(srv.listDeployedJobs(ctx, namespace.ProjectSpec, progressObserver),
+		projectJobNames))
+	for _, dagName := range setSubstract(staleJobNames, jobsToDelete) {
+		if err := jobRepo.Delete(ctx, namespace, dagName); err != nil {
+			if errors.Is(err, models.ErrNoSuchJob) {
+				continue
+			}
+			return err
+		}
+		srv.notifyProgress(progressObserver, &EventJobRemoteDelete{dagName})
+	}
 	return nil
 }
 
+// listDeployedJobs returns jobs of the project deployed to the scheduler,
+// sync goes on without them if the scheduler can't list its jobs
+func (srv *Service) listDeployedJobs(ctx context.Context, projectSpec models.ProjectSpec, progressObserver progress.Observer) []string {
+	if srv.Scheduler == nil {
+		return nil
+	}
+	jobNames, err := srv.Scheduler.ListDeployedJobs(ctx, projectSpec)
+	if err != nil {
+		if !errors.Is(err, models.ErrUnsupportedSchedulerOperation) {
+			srv.notifyProgress(progressObserver, &EventDeployedJobsListFailed{Err: err})
+		}
+		return nil
+	}
+	return jobNames
+}
+
 // KeepOnly only keeps the provided jobSpecs in argument and deletes rest from spec repository
 func (srv *Service) KeepOnly(namespace models.NamespaceSpec, specsToKeep []models.JobSpec, progressObserver progress.Observer) error {
 	jobSpecRepo := srv.jobSpecRepoFactory.New(namespace)
@@ -319,6 +539,10 @@ func (srv *Service) filterJobSpecForNamespace(jobSpecs []models.JobSpec, namespa
 
 	var filteredJobSpecs []models.JobSpec
 	for _, jobSpec := range jobSpecs {
+		// templates are only extended by other jobs
+		if jobSpec.IsTemplate {
+			continue
+		}
 		if srv.ifPresentInJobSpec(namespaceJobSpecNames, jobSpec.Name) {
 			filteredJobSpecs = append(filteredJobSpecs, jobSpec)
 		}
@@ -337,7 +561,7 @@ func (srv *Service) GetDependencyResolvedSpecs(proj models.ProjectSpec, projectJ
 
 	// compile assets first
 	for i, jSpec := range jobSpecs {
-		if jobSpecs[i].Assets, err = srv.assetCompiler(jSpec, srv.Now()); err != nil {
+		if jobSpecs[i].Assets, err = srv.assetCompiler(jSpec, proj, srv.Now()); err != nil {
 			return nil, errors.Wrap(err, "asset compilation")
 		}
 	}
@@ -363,8 +587,25 @@ func (srv *Service) GetDependencyResolvedSpecs(proj models.ProjectSpec, projectJ
 			resolvedSpecs = append(resolvedSpecs, state.Val.(models.JobSpec))
 		}
 	}
+	if resolvedErrors != nil {
+		return resolvedSpecs, resolvedErrors
+	}
 
-	return resolvedSpecs, resolvedErrors
+	// priorities can't be assigned to cyclic jobs, check with the dependencies
+	// of every job resolved before they are used
+	specs := map[jobNode]models.JobSpec{}
+	var roots []jobNode
+	for _, resolvedSpec := range resolvedSpecs {
+		node := jobNode{project: proj.Name, job: resolvedSpec.Name}
+		specs[node] = resolvedSpec
+		roots = append(roots, node)
+	}
+	sort.Slice(roots, func(i, j int) bool { return roots[i].job < roots[j].job })
+	if err := checkCyclicDependencies(proj.Name, specs, roots); err != nil {
+		return nil, err
+	}
+
+	return resolvedSpecs, nil
 }
 
 // uploadSpecs compiles a Job and uploads it to the destination store
@@ -374,29 +615,56 @@ func (srv *Service) uploadSpecs(ctx context.Context, jobSpecs []models.JobSpec,
 	for _, jobSpec := range jobSpecs {
 		runner.Add(func(currentSpec models.JobSpec) func() (interface{}, error) {
 			return func() (interface{}, error) {
-				compiledJob, err := srv.compiler.Compile(namespace, currentSpec)
-				if err != nil {
-					return nil, err
-				}
-				srv.notifyProgress(progressObserver, &EventJobSpecCompile{
-					Name: currentSpec.Name,
+				// each upload is notified as soon as it is done so deployments
+				// can stream progress instead of waiting for all jobs
+				err := srv.compileAndUpload(ctx, currentSpec, jobRepo, namespace, progressObserver)
+				srv.notifyProgress(progressObserver, &EventJobUpload{
+					Job: currentSpec,
+					Err: err,
 				})
-
-				if err = jobRepo.Save(ctx, compiledJob); err != nil {
-					return nil, err
+				if err == nil {
+					srv.notifyProgress(srv.LifecycleObserver, &EventJobDeployed{
+						Namespace: namespace,
+						Job:       currentSpec,
+					})
 				}
-				return nil, nil
+				return nil, err
 			}
 		}(jobSpec))
 	}
+	runner.Run()
+	return nil
+}
+
+// compile interpolates the git commit carried by ctx in the job if the
+// compiler supports it
+func (srv *Service) compile(ctx context.Context, namespace models.NamespaceSpec, jobSpec models.JobSpec) (models.Job, error) {
+	if gitCompiler, ok := srv.compiler.(GitCompiler); ok {
+		if git := GitContextFromContext(ctx); git != (GitContext{}) {
+			return gitCompiler.CompileWithGit(namespace, jobSpec, git)
+		}
+	}
+	return srv.compiler.Compile(namespace, jobSpec)
+}
 
-	for runIdx, state := range runner.Run() {
-		srv.notifyProgress(progressObserver, &EventJobUpload{
-			Job: jobSpecs[runIdx],
-			Err: state.Err,
+func (srv *Service) compileAndUpload(ctx context.Context, jobSpec models.JobSpec, jobRepo store.JobRepository,
+	namespace models.NamespaceSpec, progressObserver progress.Observer) error {
+	compileCtx, compileSpan := tracing.Start(ctx, "job.Compiler.Compile", attribute.String("job", jobSpec.Name))
+	compiledJob, err := srv.compile(compileCtx, namespace, jobSpec)
+	tracing.End(compileSpan, err)
+	if err != nil {
+		return err
+	}
+	srv.notifyProgress(progressObserver, &EventJobSpecCompile{
+		Name: jobSpec.Name,
+	})
+	for _, warning := range compiledJob.Warnings {
+		srv.notifyProgress(progressObserver, &EventJobCompileWarning{
+			Name:    jobSpec.Name,
+			Warning: warning,
 		})
 	}
-	return nil
+	return jobRepo.Save(ctx, compiledJob)
 }
 
 func (srv *Service) publishMetadata(namespace models.NamespaceSpec, jobSpecs []models.JobSpec,
@@ -486,6 +754,8 @@ func NewService(jobSpecRepoFactory SpecRepoFactory, jobRepoFact JobRepoFactory,
 	priorityResolver PriorityResolver, metaSvcFactory meta.MetaSvcFactory,
 	projectJobSpecRepoFactory ProjectJobSpecRepoFactory,
 	replayManager ReplayManager,
+	dependencyPinRepoFactory DependencyPinRepoFactory,
+	releaseRepoFactory ReleaseRepoFactory,
 ) *Service {
 	return &Service{
 		jobSpecRepoFactory:        jobSpecRepoFactory,
@@ -496,9 +766,13 @@ func NewService(jobSpecRepoFactory SpecRepoFactory, jobRepoFact JobRepoFactory,
 		metaSvcFactory:            metaSvcFactory,
 		projectJobSpecRepoFactory: projectJobSpecRepoFactory,
 		replayManager:             replayManager,
+		dependencyPinRepoFactory:  dependencyPinRepoFactory,
+		releaseRepoFactory:        releaseRepoFactory,
 
-		assetCompiler: assetCompiler,
-		Now:           time.Now,
+		assetCompiler:       assetCompiler,
+		Now:                 time.Now,
+		MaxBackfillLookback: DefaultMaxBackfillLookback,
+		tableExistenceCache: map[string]cachedTableExistence{},
 	}
 }
 
@@ -529,6 +803,29 @@ type (
 		Err error
 	}
 
+	// EventJobDeployed signifies that a compiled job
+	// is deployed to the scheduler
+	EventJobDeployed struct {
+		Namespace models.NamespaceSpec
+		Job       models.JobSpec
+	}
+
+	// EventJobRunFinished signifies that the scheduler
+	// reported a run of the job failing or succeeding
+	EventJobRunFinished struct {
+		Namespace models.NamespaceSpec
+		Job       models.JobSpec
+		Event     models.JobEvent
+	}
+
+	// EventJobSLAMiss signifies that the scheduler
+	// reported runs of the job breaching their SLA
+	EventJobSLAMiss struct {
+		Namespace models.NamespaceSpec
+		Job       models.JobSpec
+		Event     models.JobEvent
+	}
+
 	// EventJobRemoteDelete signifies that a
 	// compiled job from a remote repository is being deleted
 	EventJobRemoteDelete struct{ Name string }
@@ -537,10 +834,22 @@ type (
 	// job from a repository is being deleted
 	EventSavedJobDelete struct{ Name string }
 
+	// EventDeployedJobsListFailed signifies that jobs deployed
+	// to the scheduler couldn't be listed during sync
+	EventDeployedJobsListFailed struct{ Err error }
+
 	// EventJobPriorityWeightAssign signifies that a
 	// job is being assigned a priority weight
 	EventJobPriorityWeightAssign struct{}
 
+	// EventJobCompileWarning signifies that a job compiled
+	// but likely won't run as intended, e.g. when daylight
+	// saving time changes skip or repeat its runs
+	EventJobCompileWarning struct {
+		Name    string
+		Warning string
+	}
+
 	// job check events
 	EventJobCheckFailed struct {
 		Name   string
@@ -566,6 +875,18 @@ func (e *EventJobUpload) String() string {
 	return fmt.Sprintf("uploaded: %s", e.Job.Name)
 }
 
+func (e *EventJobDeployed) String() string {
+	return fmt.Sprintf("deployed: %s", e.Job.Name)
+}
+
+func (e *EventJobRunFinished) String() string {
+	return fmt.Sprintf("run of %s finished with %s", e.Job.Name, e.Event.Type)
+}
+
+func (e *EventJobSLAMiss) String() string {
+	return fmt.Sprintf("sla of %s breached", e.Job.Name)
+}
+
 func (e *EventJobRemoteDelete) String() string {
 	return fmt.Sprintf("deleting: %s", e.Name)
 }
@@ -574,10 +895,18 @@ func (e *EventSavedJobDelete) String() string {
 	return fmt.Sprintf("deleting: %s", e.Name)
 }
 
+func (e *EventDeployedJobsListFailed) String() string {
+	return fmt.Sprintf("failed to list jobs deployed to the scheduler: %s", e.Err.Error())
+}
+
 func (e *EventJobPriorityWeightAssign) String() string {
 	return fmt.Sprintf("assigned priority weights")
 }
 
+func (e *EventJobCompileWarning) String() string {
+	return fmt.Sprintf("warning for job %s: %s", e.Name, e.Warning)
+}
+
 func (e *EventJobSpecDependencyResolve) String() string {
 	return fmt.Sprintf("dependencies resolved")
 }
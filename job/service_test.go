@@ -2,15 +2,18 @@ package job_test
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
+	"github.com/odpf/optimus/core/progress"
 	"github.com/odpf/optimus/job"
 	"github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
 	"github.com/stretchr/testify/assert"
 	testMock "github.com/stretchr/testify/mock"
 )
@@ -18,7 +21,7 @@ import (
 func TestService(t *testing.T) {
 	ctx := context.Background()
 
-	dumpAssets := func(jobSpec models.JobSpec, _ time.Time) (models.JobAssets, error) {
+	dumpAssets := func(jobSpec models.JobSpec, _ models.ProjectSpec, _ time.Time) (models.JobAssets, error) {
 		return jobSpec.Assets, nil
 	}
 
@@ -53,7 +56,7 @@ func TestService(t *testing.T) {
 			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
 			defer projJobSpecRepoFac.AssertExpectations(t)
 
-			svc := job.NewService(repoFac, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(repoFac, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil, nil, nil)
 			err := svc.Create(namespaceSpec, jobSpec)
 			assert.Nil(t, err)
 		})
@@ -85,10 +88,189 @@ func TestService(t *testing.T) {
 			repoFac.On("New", namespaceSpec).Return(repo)
 			defer repoFac.AssertExpectations(t)
 
-			svc := job.NewService(repoFac, nil, nil, dumpAssets, nil, nil, nil, nil, nil)
+			svc := job.NewService(repoFac, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil, nil)
 			err := svc.Create(namespaceSpec, jobSpec)
 			assert.NotNil(t, err)
 		})
+
+		t.Run("should reject jobs with a timezone that isn't in the timezone database", func(t *testing.T) {
+			namespaceSpec := models.NamespaceSpec{
+				Name:        "dev-team-1",
+				ProjectSpec: models.ProjectSpec{Name: "proj"},
+			}
+			jobSpec := models.JobSpec{
+				Name: "test",
+				Schedule: models.JobSpecSchedule{
+					StartDate: time.Date(2020, 12, 02, 0, 0, 0, 0, time.UTC),
+					Interval:  "@daily",
+					Timezone:  "Asia/Nowhere",
+				},
+			}
+
+			svc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil, nil)
+			err := svc.Create(namespaceSpec, jobSpec)
+			assert.True(t, errors.Is(err, models.ErrInvalidTimezone))
+			assert.EqualError(t, err, "failed to save job: test: invalid timezone: Asia/Nowhere")
+		})
+
+		t.Run("should reject jobs scheduled more often than the quota of the project allows", func(t *testing.T) {
+			namespaceSpec := models.NamespaceSpec{
+				Name: "dev-team-1",
+				ProjectSpec: models.ProjectSpec{
+					Name:  "proj",
+					Quota: models.ProjectQuota{MinScheduleInterval: time.Hour},
+				},
+			}
+			jobSpec := models.JobSpec{
+				Name: "test",
+				Schedule: models.JobSpecSchedule{
+					StartDate: time.Date(2020, 12, 02, 0, 0, 0, 0, time.UTC),
+					Interval:  "0,30 * * * *",
+				},
+			}
+
+			svc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil, nil)
+			err := svc.Create(namespaceSpec, jobSpec)
+			assert.True(t, errors.Is(err, models.ErrQuotaExceeded))
+			assert.Contains(t, err.Error(), "job test runs every 30m0s, more often than every 1h0m0s")
+		})
+
+		t.Run("should limit the jobs of projects without a quota to the default quota", func(t *testing.T) {
+			namespaceSpec := models.NamespaceSpec{
+				Name:        "dev-team-1",
+				ProjectSpec: models.ProjectSpec{Name: "proj"},
+			}
+			existingSpec := models.JobSpec{Name: "existing", Schedule: models.JobSpecSchedule{Interval: "@daily"}}
+
+			projJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projJobSpecRepo.On("GetAll").Return([]models.JobSpec{existingSpec}, nil)
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", namespaceSpec.ProjectSpec).Return(projJobSpecRepo)
+
+			repo := new(mock.JobSpecRepository)
+			repo.On("Save", existingSpec).Return(nil)
+			defer repo.AssertExpectations(t)
+			repoFac := new(mock.JobSpecRepoFactory)
+			repoFac.On("New", namespaceSpec).Return(repo)
+
+			svc := job.NewService(repoFac, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+			svc.DefaultQuota = models.ProjectQuota{MaxJobCount: 1, MinScheduleInterval: time.Hour}
+
+			// registered jobs can still be updated
+			err := svc.Create(namespaceSpec, existingSpec)
+			assert.Nil(t, err)
+
+			err = svc.Create(namespaceSpec, models.JobSpec{Name: "test", Schedule: models.JobSpecSchedule{Interval: "@daily"}})
+			assert.True(t, errors.Is(err, models.ErrQuotaExceeded))
+			assert.Contains(t, err.Error(), "project proj can't have more than 1 jobs")
+		})
+	})
+
+	t.Run("SaveAll", func(t *testing.T) {
+		namespaceSpec := models.NamespaceSpec{
+			Name:        "dev-team-1",
+			ProjectSpec: models.ProjectSpec{Name: "proj"},
+		}
+		existingSpec := models.JobSpec{Name: "existing", Schedule: models.JobSpecSchedule{Interval: "@daily"}}
+		newSpecs := []models.JobSpec{
+			{Name: "test-1", Schedule: models.JobSpecSchedule{Interval: "@daily"}},
+			{Name: "test-2", Schedule: models.JobSpecSchedule{Interval: "@daily"}},
+		}
+
+		t.Run("should count the jobs of the project once for all specs", func(t *testing.T) {
+			projJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projJobSpecRepo.On("GetAll").Return([]models.JobSpec{existingSpec}, nil).Once()
+			defer projJobSpecRepo.AssertExpectations(t)
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", namespaceSpec.ProjectSpec).Return(projJobSpecRepo)
+
+			repo := new(mock.JobSpecRepository)
+			repo.On("Save", existingSpec).Return(nil)
+			repo.On("Save", newSpecs[0]).Return(nil)
+			defer repo.AssertExpectations(t)
+			repoFac := new(mock.JobSpecRepoFactory)
+			repoFac.On("New", namespaceSpec).Return(repo)
+
+			svc := job.NewService(repoFac, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+			svc.DefaultQuota = models.ProjectQuota{MaxJobCount: 2}
+
+			err := svc.SaveAll(namespaceSpec, append([]models.JobSpec{existingSpec}, newSpecs...))
+			assert.True(t, errors.Is(err, models.ErrQuotaExceeded))
+			assert.Contains(t, err.Error(), "project proj can't have more than 2 jobs")
+		})
+		t.Run("should not limit projects with an unlimited quota", func(t *testing.T) {
+			unlimitedNamespace := namespaceSpec
+			unlimitedNamespace.ProjectSpec.Quota = models.ProjectQuota{
+				MaxJobCount:         models.QuotaUnlimited,
+				MinScheduleInterval: models.QuotaUnlimited,
+			}
+
+			repo := new(mock.JobSpecRepository)
+			repo.On("Save", newSpecs[0]).Return(nil)
+			repo.On("Save", newSpecs[1]).Return(nil)
+			defer repo.AssertExpectations(t)
+			repoFac := new(mock.JobSpecRepoFactory)
+			repoFac.On("New", unlimitedNamespace).Return(repo)
+
+			svc := job.NewService(repoFac, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil, nil)
+			svc.DefaultQuota = models.ProjectQuota{MaxJobCount: 1, MinScheduleInterval: time.Hour * 24 * 7}
+
+			err := svc.SaveAll(unlimitedNamespace, newSpecs)
+			assert.Nil(t, err)
+		})
+	})
+
+	t.Run("CreateAll", func(t *testing.T) {
+		namespaceSpec := models.NamespaceSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "dev-team-1",
+			ProjectSpec: models.ProjectSpec{
+				Name: "proj",
+			},
+		}
+		jobSpecs := []models.JobSpec{{Name: "test-1"}, {Name: "test-2"}}
+
+		t.Run("should save all job specs at once", func(t *testing.T) {
+			repo := new(mock.JobSpecRepository)
+			repo.On("InsertAll", jobSpecs).Return(nil)
+			defer repo.AssertExpectations(t)
+
+			repoFac := new(mock.JobSpecRepoFactory)
+			repoFac.On("New", namespaceSpec).Return(repo)
+			defer repoFac.AssertExpectations(t)
+
+			svc := job.NewService(repoFac, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil, nil)
+			err := svc.CreateAll(namespaceSpec, jobSpecs)
+			assert.Nil(t, err)
+		})
+		t.Run("should fail if any of the job specs is already registered", func(t *testing.T) {
+			repo := new(mock.JobSpecRepository)
+			repo.On("InsertAll", jobSpecs).Return(store.ErrResourceExists)
+			defer repo.AssertExpectations(t)
+
+			repoFac := new(mock.JobSpecRepoFactory)
+			repoFac.On("New", namespaceSpec).Return(repo)
+			defer repoFac.AssertExpectations(t)
+
+			svc := job.NewService(repoFac, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil, nil)
+			err := svc.CreateAll(namespaceSpec, jobSpecs)
+			assert.True(t, errors.Is(err, store.ErrResourceExists))
+		})
+		t.Run("should save none of the job specs if they exceed the quota of the project", func(t *testing.T) {
+			limitedNamespace := namespaceSpec
+			limitedNamespace.ProjectSpec.Quota = models.ProjectQuota{MaxJobCount: 2}
+			projJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projJobSpecRepo.On("GetAll").Return([]models.JobSpec{{Name: "existing"}}, nil)
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", limitedNamespace.ProjectSpec).Return(projJobSpecRepo)
+
+			repoFac := new(mock.JobSpecRepoFactory)
+			defer repoFac.AssertNotCalled(t, "New", limitedNamespace)
+
+			svc := job.NewService(repoFac, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil, nil, nil)
+			err := svc.CreateAll(limitedNamespace, jobSpecs)
+			assert.True(t, errors.Is(err, models.ErrQuotaExceeded))
+		})
 	})
 
 	t.Run("Check", func(t *testing.T) {
@@ -118,7 +300,7 @@ func TestService(t *testing.T) {
 			compiler.On("Compile", namespaceSpec, currentSpec).Return(models.Job{}, nil)
 			defer compiler.AssertExpectations(t)
 
-			service := job.NewService(nil, nil, compiler, dumpAssets, nil, nil, nil, nil, nil)
+			service := job.NewService(nil, nil, compiler, dumpAssets, nil, nil, nil, nil, nil, nil, nil)
 			err := service.Check(namespaceSpec, []models.JobSpec{currentSpec}, nil)
 			assert.Nil(t, err)
 		})
@@ -151,7 +333,7 @@ func TestService(t *testing.T) {
 			compiler.On("Compile", namespaceSpec, currentSpec).Return(models.Job{}, nil)
 			defer compiler.AssertExpectations(t)
 
-			service := job.NewService(nil, nil, compiler, dumpAssets, nil, nil, nil, nil, nil)
+			service := job.NewService(nil, nil, compiler, dumpAssets, nil, nil, nil, nil, nil, nil, nil)
 			err := service.Check(namespaceSpec, []models.JobSpec{currentSpec}, nil)
 			assert.Nil(t, err)
 		})
@@ -234,11 +416,11 @@ func TestService(t *testing.T) {
 
 			// used to store compiled job specs
 			jobRepo := new(mock.JobRepository)
-			jobRepo.On("ListNames", ctx, namespaceSpec).Return([]string{"test"}, nil)
+			jobRepo.On("ListNames", testMock.Anything, namespaceSpec).Return([]string{"test"}, nil)
 			defer jobRepo.AssertExpectations(t)
 
 			jobRepoFac := new(mock.JobRepoFactory)
-			jobRepoFac.On("New", context.Background(), projSpec).Return(jobRepo, nil)
+			jobRepoFac.On("New", testMock.Anything, projSpec).Return(jobRepo, nil)
 			defer jobRepoFac.AssertExpectations(t)
 
 			// resolve dependencies
@@ -257,14 +439,133 @@ func TestService(t *testing.T) {
 			// compile to dag and save
 			for idx, compiledJob := range jobs {
 				compiler.On("Compile", namespaceSpec, jobSpecsAfterPriorityResolve[idx]).Return(compiledJob, nil)
-				jobRepo.On("Save", ctx, compiledJob).Return(nil)
+				jobRepo.On("Save", testMock.Anything, compiledJob).Return(nil)
 			}
 
-			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, nil, nil)
 			err := svc.Sync(ctx, namespaceSpec, nil)
 			assert.Nil(t, err)
 		})
 
+		t.Run("should notify the upload of each job as soon as it is done", func(t *testing.T) {
+			jobSpecs := []models.JobSpec{
+				{Name: "job-a", Dependencies: map[string]models.JobSpecDependency{}},
+				{Name: "job-b", Dependencies: map[string]models.JobSpecDependency{}},
+			}
+			compiledJobs := []models.Job{
+				{Name: "job-a", Contents: []byte("a"), NamespaceID: namespaceSpec.Name},
+				{Name: "job-b", Contents: []byte("b"), NamespaceID: namespaceSpec.Name},
+			}
+
+			// job-b finishes while the upload of job-a is still in progress
+			jobBUploaded := make(chan struct{})
+			var mu sync.Mutex
+			var events []string
+			observer := new(mock.PipelineLogObserver)
+			observer.On("Notify", testMock.Anything).Run(func(args testMock.Arguments) {
+				evt := args.Get(0).(progress.Event)
+				mu.Lock()
+				events = append(events, evt.String())
+				mu.Unlock()
+				if upload, ok := evt.(*job.EventJobUpload); ok && upload.Job.Name == "job-b" {
+					close(jobBUploaded)
+				}
+			})
+
+			jobSpecRepo := new(mock.JobSpecRepository)
+			jobSpecRepo.On("GetAll").Return(jobSpecs, nil)
+			defer jobSpecRepo.AssertExpectations(t)
+
+			jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+			jobSpecRepoFac.On("New", namespaceSpec).Return(jobSpecRepo)
+			defer jobSpecRepoFac.AssertExpectations(t)
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetAll").Return(jobSpecs, nil)
+			defer projectJobSpecRepo.AssertExpectations(t)
+
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+			defer projJobSpecRepoFac.AssertExpectations(t)
+
+			jobRepo := new(mock.JobRepository)
+			jobRepo.On("Save", testMock.Anything, compiledJobs[0]).Run(func(args testMock.Arguments) {
+				select {
+				case <-jobBUploaded:
+				case <-time.After(5 * time.Second):
+					t.Error("upload of job-b wasn't notified before all uploads were done")
+				}
+			}).Return(errors.New("bucket is unreachable"))
+			jobRepo.On("Save", testMock.Anything, compiledJobs[1]).Return(nil)
+			jobRepo.On("ListNames", testMock.Anything, namespaceSpec).Return([]string{"job-b"}, nil)
+			defer jobRepo.AssertExpectations(t)
+
+			jobRepoFac := new(mock.JobRepoFactory)
+			jobRepoFac.On("New", testMock.Anything, projSpec).Return(jobRepo, nil)
+			defer jobRepoFac.AssertExpectations(t)
+
+			depenResolver := new(mock.DependencyResolver)
+			depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jobSpecs[0], observer).Return(jobSpecs[0], nil)
+			depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jobSpecs[1], observer).Return(jobSpecs[1], nil)
+			defer depenResolver.AssertExpectations(t)
+
+			priorityResolver := new(mock.PriorityResolver)
+			priorityResolver.On("Resolve", testMock.Anything).Return(jobSpecs, nil)
+			defer priorityResolver.AssertExpectations(t)
+
+			compiler := new(mock.Compiler)
+			compiler.On("Compile", namespaceSpec, jobSpecs[0]).Return(compiledJobs[0], nil)
+			compiler.On("Compile", namespaceSpec, jobSpecs[1]).Return(compiledJobs[1], nil)
+			defer compiler.AssertExpectations(t)
+
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, nil, nil)
+			err := svc.Sync(ctx, namespaceSpec, observer)
+			assert.Nil(t, err)
+
+			assert.Equal(t, []string{"fetching job specs", "dependencies resolved", "assigned priority weights"}, events[:3])
+			assert.ElementsMatch(t, []string{
+				"compiling: job-a",
+				"compiling: job-b",
+				"uploaded: job-b",
+				"uploading: job-a, failed with error): bucket is unreachable",
+			}, events[3:])
+		})
+
+		t.Run("should fail before assigning priorities if resolved dependencies are cyclic", func(t *testing.T) {
+			jobSpecsBase := []models.JobSpec{
+				{Name: "job-a", Dependencies: map[string]models.JobSpecDependency{}},
+				{Name: "job-b", Dependencies: map[string]models.JobSpecDependency{}},
+			}
+			// dependencies inferred from the assets of both jobs point to each other
+			jobA := models.JobSpec{Name: "job-a", Dependencies: map[string]models.JobSpecDependency{
+				"job-b": {Job: &jobSpecsBase[1], Project: &projSpec, Type: models.JobSpecDependencyTypeIntra},
+			}}
+			jobB := models.JobSpec{Name: "job-b", Dependencies: map[string]models.JobSpecDependency{
+				"job-a": {Job: &jobSpecsBase[0], Project: &projSpec, Type: models.JobSpecDependencyTypeIntra},
+			}}
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetAll").Return(jobSpecsBase, nil)
+			defer projectJobSpecRepo.AssertExpectations(t)
+
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+			defer projJobSpecRepoFac.AssertExpectations(t)
+
+			depenResolver := new(mock.DependencyResolver)
+			depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jobSpecsBase[0], nil).Return(jobA, nil)
+			depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jobSpecsBase[1], nil).Return(jobB, nil)
+			defer depenResolver.AssertExpectations(t)
+
+			priorityResolver := new(mock.PriorityResolver)
+			defer priorityResolver.AssertExpectations(t)
+
+			svc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, nil, nil)
+			err := svc.Sync(ctx, namespaceSpec, nil)
+			assert.True(t, errors.Is(err, job.ErrCyclicDependency))
+			assert.Equal(t, "dependency path job-a -> job-b -> job-a: cyclic dependency", err.Error())
+		})
+
 		t.Run("should delete job specs from target store if there are existing specs that are no longer present in job specs", func(t *testing.T) {
 			jobSpecsBase := []models.JobSpec{
 				{
@@ -337,7 +638,7 @@ func TestService(t *testing.T) {
 
 			// used to store compiled job specs
 			jobRepo := new(mock.JobRepository)
-			jobRepo.On("ListNames", ctx, namespaceSpec).Return([]string{"test", "test2"}, nil)
+			jobRepo.On("ListNames", testMock.Anything, namespaceSpec).Return([]string{"test", "test2"}, nil)
 			defer jobRepo.AssertExpectations(t)
 
 			jobRepoFac := new(mock.JobRepoFactory)
@@ -357,30 +658,109 @@ func TestService(t *testing.T) {
 			compiler := new(mock.Compiler)
 			defer compiler.AssertExpectations(t)
 
-			jobRepo.On("ListNames", ctx, namespaceSpec).Return([]string{"test", "test2"}, nil)
+			jobRepo.On("ListNames", testMock.Anything, namespaceSpec).Return([]string{"test", "test2"}, nil)
 
 			// resolve dependencies
 			depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jobSpecsBase[0], nil).Return(jobSpecsAfterDepenResolve[0], nil)
 
 			// resolve priority
 			priorityResolver.On("Resolve", jobSpecsAfterDepenResolve).Return(jobSpecsAfterPriorityResolve, nil)
-			jobRepoFac.On("New", context.Background(), projSpec).Return(jobRepo, nil)
+			jobRepoFac.On("New", testMock.Anything, projSpec).Return(jobRepo, nil)
 
 			// compile to dag and save the first one
 			compiler.On("Compile", namespaceSpec, jobSpecsAfterPriorityResolve[0]).Return(jobs[0], nil)
-			jobRepo.On("Save", ctx, jobs[0]).Return(nil)
+			jobRepo.On("Save", testMock.Anything, jobs[0]).Return(nil)
 
 			// fetch currently stored
 			projectJobSpecRepo.On("GetAll").Return(jobSpecsBase, nil)
 
 			// delete unwanted
-			jobRepo.On("Delete", ctx, namespaceSpec, jobs[1].Name).Return(nil)
+			jobRepo.On("Delete", testMock.Anything, namespaceSpec, jobs[1].Name).Return(nil)
 
-			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, nil, nil)
 			err := svc.Sync(ctx, namespaceSpec, nil)
 			assert.Nil(t, err)
 		})
 
+		t.Run("should delete jobs deployed to the scheduler without a spec in the project", func(t *testing.T) {
+			newService := func(t *testing.T, jobRepo *mock.JobRepository, scheduler *mock.Scheduler) *job.Service {
+				jobSpecs := []models.JobSpec{
+					{Name: "test", Dependencies: map[string]models.JobSpecDependency{}},
+				}
+				jobSpecRepo := new(mock.JobSpecRepository)
+				jobSpecRepo.On("GetAll").Return(jobSpecs, nil)
+				jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+				jobSpecRepoFac.On("New", namespaceSpec).Return(jobSpecRepo)
+
+				projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+				projectJobSpecRepo.On("GetAll").Return(jobSpecs, nil)
+				projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+				projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+
+				jobRepoFac := new(mock.JobRepoFactory)
+				jobRepoFac.On("New", testMock.Anything, projSpec).Return(jobRepo, nil)
+
+				depenResolver := new(mock.DependencyResolver)
+				depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jobSpecs[0], testMock.Anything).Return(jobSpecs[0], nil)
+				priorityResolver := new(mock.PriorityResolver)
+				priorityResolver.On("Resolve", jobSpecs).Return(jobSpecs, nil)
+
+				compiledJob := models.Job{Name: "test", Contents: []byte("test"), NamespaceID: namespaceSpec.Name}
+				compiler := new(mock.Compiler)
+				compiler.On("Compile", namespaceSpec, jobSpecs[0]).Return(compiledJob, nil)
+				jobRepo.On("Save", testMock.Anything, compiledJob).Return(nil)
+				jobRepo.On("ListNames", testMock.Anything, namespaceSpec).Return([]string{"test", "test2"}, nil)
+				jobRepo.On("Delete", testMock.Anything, namespaceSpec, "test2").Return(nil)
+
+				svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, nil, nil)
+				svc.Scheduler = scheduler
+				return svc
+			}
+
+			t.Run("should delete jobs of the namespace left deployed", func(t *testing.T) {
+				scheduler := new(mock.Scheduler)
+				scheduler.On("ListDeployedJobs", testMock.Anything, projSpec).Return([]string{"test", "test2", "stale-job", "job-of-other-namespace", "__optimus_health_probe"}, nil)
+				defer scheduler.AssertExpectations(t)
+
+				jobRepo := new(mock.JobRepository)
+				jobRepo.On("Delete", testMock.Anything, namespaceSpec, "stale-job").Return(nil)
+				jobRepo.On("Delete", testMock.Anything, namespaceSpec, "job-of-other-namespace").Return(errors.Wrap(models.ErrNoSuchJob, "job-of-other-namespace"))
+				defer jobRepo.AssertExpectations(t)
+
+				var deleted []string
+				observer := new(mock.PipelineLogObserver)
+				observer.On("Notify", testMock.Anything).Run(func(args testMock.Arguments) {
+					if evt, ok := args.Get(0).(*job.EventJobRemoteDelete); ok {
+						deleted = append(deleted, evt.Name)
+					}
+				})
+
+				err := newService(t, jobRepo, scheduler).Sync(ctx, namespaceSpec, observer)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"test2", "stale-job"}, deleted)
+			})
+			t.Run("should sync without them if the scheduler can't list its jobs", func(t *testing.T) {
+				scheduler := new(mock.Scheduler)
+				scheduler.On("ListDeployedJobs", testMock.Anything, projSpec).Return([]string{}, errors.New("scheduler host not set for proj"))
+				defer scheduler.AssertExpectations(t)
+
+				jobRepo := new(mock.JobRepository)
+				defer jobRepo.AssertExpectations(t)
+
+				var listFailed bool
+				observer := new(mock.PipelineLogObserver)
+				observer.On("Notify", testMock.Anything).Run(func(args testMock.Arguments) {
+					if _, ok := args.Get(0).(*job.EventDeployedJobsListFailed); ok {
+						listFailed = true
+					}
+				})
+
+				err := newService(t, jobRepo, scheduler).Sync(ctx, namespaceSpec, observer)
+				assert.Nil(t, err)
+				assert.True(t, listFailed)
+			})
+		})
+
 		t.Run("should batch dependency resolution errors if any for all jobs", func(t *testing.T) {
 			jobSpecsBase := []models.JobSpec{
 				{
@@ -424,7 +804,7 @@ func TestService(t *testing.T) {
 				errors.New("error test-2"))
 			defer depenResolver.AssertExpectations(t)
 
-			svc := job.NewService(jobSpecRepoFac, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil, nil)
 			err := svc.Sync(ctx, namespaceSpec, nil)
 			assert.NotNil(t, err)
 			assert.Contains(t, err.Error(), "2 errors occurred")
@@ -498,11 +878,11 @@ func TestService(t *testing.T) {
 
 			// used to store compiled job specs
 			jobRepo := new(mock.JobRepository)
-			jobRepo.On("ListNames", ctx, namespaceSpec).Return([]string{"test"}, nil)
+			jobRepo.On("ListNames", testMock.Anything, namespaceSpec).Return([]string{"test"}, nil)
 			defer jobRepo.AssertExpectations(t)
 
 			jobRepoFac := new(mock.JobRepoFactory)
-			jobRepoFac.On("New", context.Background(), projSpec).Return(jobRepo, nil)
+			jobRepoFac.On("New", testMock.Anything, projSpec).Return(jobRepo, nil)
 			defer jobRepoFac.AssertExpectations(t)
 
 			// resolve dependencies
@@ -529,10 +909,10 @@ func TestService(t *testing.T) {
 			// compile to dag and save
 			for idx, compiledJob := range jobs {
 				compiler.On("Compile", namespaceSpec, jobSpecsAfterPriorityResolve[idx]).Return(compiledJob, nil)
-				jobRepo.On("Save", ctx, compiledJob).Return(nil)
+				jobRepo.On("Save", testMock.Anything, compiledJob).Return(nil)
 			}
 
-			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, metaSvcFact, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, metaSvcFact, projJobSpecRepoFac, nil, nil, nil)
 			err := svc.Sync(ctx, namespaceSpec, nil)
 			assert.Nil(t, err)
 		})
@@ -606,7 +986,7 @@ func TestService(t *testing.T) {
 			// delete unwanted
 			jobSpecRepo.On("Delete", jobSpecsBase[0].Name).Return(nil)
 
-			svc := job.NewService(jobSpecRepoFac, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil, nil, nil)
 			err := svc.KeepOnly(namespaceSpec, toKeep, nil)
 			assert.Nil(t, err)
 		})
@@ -711,7 +1091,7 @@ func TestService(t *testing.T) {
 				compiler.On("Compile", namespaceSpec, jobSpecsAfterPriorityResolve[idx]).Return(compiledJob, nil)
 			}
 
-			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, nil, nil)
 			compiledJob, err := svc.Dump(namespaceSpec, jobSpecsBase[0])
 			assert.Nil(t, err)
 			assert.Equal(t, "come string", string(compiledJob.Contents))
@@ -797,11 +1177,11 @@ func TestService(t *testing.T) {
 
 			// used to store compiled job specs
 			jobRepo := new(mock.JobRepository)
-			jobRepo.On("ListNames", ctx, namespaceSpec).Return([]string{"test"}, nil)
+			jobRepo.On("ListNames", testMock.Anything, namespaceSpec).Return([]string{"test"}, nil)
 			defer jobRepo.AssertExpectations(t)
 
 			jobRepoFac := new(mock.JobRepoFactory)
-			jobRepoFac.On("New", context.Background(), projSpec).Return(jobRepo, nil)
+			jobRepoFac.On("New", testMock.Anything, projSpec).Return(jobRepo, nil)
 			defer jobRepoFac.AssertExpectations(t)
 
 			// resolve dependencies
@@ -820,10 +1200,10 @@ func TestService(t *testing.T) {
 			// compile to dag and save
 			for idx, compiledJob := range jobs {
 				compiler.On("Compile", namespaceSpec, jobSpecsAfterPriorityResolve[idx]).Return(compiledJob, nil)
-				jobRepo.On("Save", ctx, compiledJob).Return(nil)
+				jobRepo.On("Save", testMock.Anything, compiledJob).Return(nil)
 			}
 
-			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, nil, nil)
 			err := svc.Delete(ctx, namespaceSpec, jobSpecsBase[0])
 			assert.Nil(t, err)
 		})
@@ -912,10 +1292,53 @@ func TestService(t *testing.T) {
 			compiler := new(mock.Compiler)
 			defer compiler.AssertExpectations(t)
 
-			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, nil, nil)
 			err := svc.Delete(ctx, namespaceSpec, jobSpecsBase[0])
 			assert.NotNil(t, err)
 			assert.Equal(t, "cannot delete job test since it's dependency of job downstream-test", err.Error())
 		})
 	})
+
+	t.Run("Undelete", func(t *testing.T) {
+		namespaceSpec := models.NamespaceSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "dev-team-1",
+			ProjectSpec: models.ProjectSpec{
+				Name: "proj",
+			},
+		}
+
+		t.Run("should fail without syncing if the job isn't deleted", func(t *testing.T) {
+			jobSpecRepo := new(mock.JobSpecRepository)
+			jobSpecRepo.On("Undelete", "test").Return(store.ErrResourceNotFound)
+			defer jobSpecRepo.AssertExpectations(t)
+
+			jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+			jobSpecRepoFac.On("New", namespaceSpec).Return(jobSpecRepo)
+			defer jobSpecRepoFac.AssertExpectations(t)
+
+			svc := job.NewService(jobSpecRepoFac, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil, nil)
+			err := svc.Undelete(ctx, namespaceSpec, "test")
+			assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+		})
+	})
+
+	t.Run("PurgeDeleted", func(t *testing.T) {
+		t.Run("should purge jobs of all projects", func(t *testing.T) {
+			crossProjectRepo := new(mock.CrossProjectJobSpecRepository)
+			crossProjectRepo.On("PurgeDeleted", ctx, 30*24*time.Hour).Return(2, nil)
+			defer crossProjectRepo.AssertExpectations(t)
+
+			svc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil, nil)
+			svc.CrossProjectJobSpecRepo = crossProjectRepo
+			purged, err := svc.PurgeDeleted(ctx, 30*24*time.Hour)
+			assert.Nil(t, err)
+			assert.Equal(t, 2, purged)
+		})
+		t.Run("should fail if jobs of all projects can't be purged", func(t *testing.T) {
+			svc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil, nil)
+			_, err := svc.PurgeDeleted(ctx, time.Hour)
+			assert.Equal(t, job.ErrCrossProjectPurgeUnsupported, err)
+		})
+	})
 }
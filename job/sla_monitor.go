@@ -0,0 +1,234 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/odpf/optimus/core/cron"
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultSLALookback is how far back runs are checked against the
+	// sla of their job
+	DefaultSLALookback = time.Hour * 24
+
+	slaMonitorRunBatchSize = 100
+)
+
+// SLAMonitor periodically checks that the runs of jobs having an sla
+// duration were started by the scheduler in time. A run scheduled at a time
+// is due at the next tick of the job schedule, as that is when the scheduler
+// starts it, and breaches the sla if it isn't started within the sla duration
+// of its job after that. Breaches are saved and notified once
+type SLAMonitor struct {
+	projectRepoFactory        ProjectRepoFactory
+	projectJobSpecRepoFactory ProjectJobSpecRepoFactory
+	scheduler                 models.SchedulerUnit
+	breachRepo                store.SLABreachRepository
+	interval                  time.Duration
+	progressObserver          progress.Observer
+
+	Now func() time.Time
+	// Lookback limits the runs checked to the ones scheduled within it
+	Lookback time.Duration
+}
+
+// Run checks the runs of all projects every interval until ctx is cancelled
+func (m *SLAMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Check(ctx); err != nil {
+				m.notifyProgress(&EventSLAMonitorFailed{Err: err})
+			}
+		}
+	}
+}
+
+// Check compares the start time of the recent runs of jobs having an sla
+// duration with their deadline, failing projects are checked again in the
+// next cycle
+func (m *SLAMonitor) Check(ctx context.Context) error {
+	projectSpecs, err := m.projectRepoFactory.New().GetAll()
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve projects")
+	}
+	now := m.Now()
+	for _, projectSpec := range projectSpecs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := m.checkProject(ctx, projectSpec, now); err != nil {
+			m.notifyProgress(&EventSLAMonitorFailed{Project: projectSpec.Name, Err: err})
+		}
+	}
+	return nil
+}
+
+func (m *SLAMonitor) checkProject(ctx context.Context, projectSpec models.ProjectSpec, now time.Time) error {
+	jobSpecs, namespaces, err := m.projectJobSpecRepoFactory.New(projectSpec).GetModifiedSince(time.Time{})
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve jobs")
+	}
+	for _, jobSpec := range jobSpecs {
+		if jobSpec.IsTemplate || jobSpec.SLADuration <= 0 {
+			continue
+		}
+		breaches, err := m.findBreaches(ctx, projectSpec, jobSpec, now)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check sla of %s", jobSpec.Name)
+		}
+		namespace := namespaces[jobSpec.Name]
+		for _, breach := range breaches {
+			breach.NamespaceName = namespace.Name
+			if err := m.breachRepo.Save(ctx, breach); err != nil {
+				if errors.Is(err, store.ErrResourceExists) {
+					// notified when first detected
+					continue
+				}
+				return errors.Wrapf(err, "failed to save sla breach of %s", jobSpec.Name)
+			}
+			m.notifyProgress(&EventJobSLABreached{
+				Namespace: namespace,
+				Job:       jobSpec,
+				Breach:    breach,
+			})
+		}
+	}
+	return nil
+}
+
+// findBreaches returns the runs of the job scheduled within the lookback
+// whose deadline has passed without them being started in time
+func (m *SLAMonitor) findBreaches(ctx context.Context, projectSpec models.ProjectSpec, jobSpec models.JobSpec,
+	now time.Time) ([]models.SLABreach, error) {
+	schedule, err := cron.ParseCronSchedule(jobSpec.Schedule.Interval)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse schedule interval %s", jobSpec.Schedule.Interval)
+	}
+	loc, err := jobSpec.Schedule.Location()
+	if err != nil {
+		return nil, err
+	}
+
+	type dueRun struct {
+		scheduledAt time.Time
+		dueAt       time.Time
+	}
+	from := now.Add(-m.Lookback)
+	if from.Before(jobSpec.Schedule.StartDate) {
+		from = jobSpec.Schedule.StartDate
+	}
+	var runs []dueRun
+	for scheduledAt := schedule.Next(from.Add(-time.Second).In(loc)); scheduledAt.Before(now); scheduledAt = schedule.Next(scheduledAt) {
+		if jobSpec.Schedule.EndDate != nil && scheduledAt.After(*jobSpec.Schedule.EndDate) {
+			break
+		}
+		dueAt := schedule.Next(scheduledAt)
+		if dueAt.Add(jobSpec.SLADuration).After(now) {
+			break
+		}
+		runs = append(runs, dueRun{scheduledAt: scheduledAt.UTC(), dueAt: dueAt.UTC()})
+	}
+	if len(runs) == 0 {
+		return nil, nil
+	}
+
+	statuses, err := m.scheduler.GetDagRunStatus(ctx, projectSpec, jobSpec.Name, runs[0].scheduledAt,
+		runs[len(runs)-1].scheduledAt, slaMonitorRunBatchSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch runs")
+	}
+	statusByTime := map[int64]models.JobStatus{}
+	for _, status := range statuses {
+		statusByTime[status.ScheduledAt.Unix()] = status
+	}
+
+	var breaches []models.SLABreach
+	for _, run := range runs {
+		deadline := run.dueAt.Add(jobSpec.SLADuration)
+		status, ok := statusByTime[run.scheduledAt.Unix()]
+		if ok && status.StartTime.IsZero() && (status.State == models.JobStatusStateRunning ||
+			status.State == models.JobStatusStateSuccess || status.State == models.JobStatusStateFailed) {
+			// the run started but the scheduler didn't report when
+			continue
+		}
+		if ok && !status.StartTime.IsZero() && !status.StartTime.After(deadline) {
+			continue
+		}
+		breaches = append(breaches, models.SLABreach{
+			ProjectName: projectSpec.Name,
+			JobName:     jobSpec.Name,
+			ScheduledAt: run.scheduledAt,
+			DueAt:       run.dueAt,
+			SLADuration: jobSpec.SLADuration,
+			StartedAt:   status.StartTime,
+			DetectedAt:  now,
+		})
+	}
+	return breaches, nil
+}
+
+func (m *SLAMonitor) notifyProgress(e progress.Event) {
+	if m.progressObserver == nil {
+		return
+	}
+	m.progressObserver.Notify(e)
+}
+
+func NewSLAMonitor(projectRepoFactory ProjectRepoFactory, projectJobSpecRepoFactory ProjectJobSpecRepoFactory,
+	scheduler models.SchedulerUnit, breachRepo store.SLABreachRepository, interval time.Duration,
+	progressObserver progress.Observer) *SLAMonitor {
+	return &SLAMonitor{
+		projectRepoFactory:        projectRepoFactory,
+		projectJobSpecRepoFactory: projectJobSpecRepoFactory,
+		scheduler:                 scheduler,
+		breachRepo:                breachRepo,
+		interval:                  interval,
+		progressObserver:          progressObserver,
+		Now:                       time.Now,
+		Lookback:                  DefaultSLALookback,
+	}
+}
+
+type (
+	// EventJobSLABreached signifies that a run of the job
+	// wasn't started within the sla duration of the job
+	EventJobSLABreached struct {
+		Namespace models.NamespaceSpec
+		Job       models.JobSpec
+		Breach    models.SLABreach
+	}
+
+	// EventSLAMonitorFailed signifies that runs of a project, or
+	// of all projects when Project is empty, couldn't be checked
+	EventSLAMonitorFailed struct {
+		Project string
+		Err     error
+	}
+)
+
+func (e *EventJobSLABreached) String() string {
+	if e.Breach.StartedAt.IsZero() {
+		return fmt.Sprintf("run of %s scheduled at %s not started within sla of %s", e.Job.Name,
+			e.Breach.ScheduledAt.Format(time.RFC3339), e.Breach.SLADuration)
+	}
+	return fmt.Sprintf("run of %s scheduled at %s started %s after its due time, sla is %s", e.Job.Name,
+		e.Breach.ScheduledAt.Format(time.RFC3339), e.Breach.StartedAt.Sub(e.Breach.DueAt), e.Breach.SLADuration)
+}
+
+func (e *EventSLAMonitorFailed) String() string {
+	if e.Project == "" {
+		return fmt.Sprintf("sla monitor failed with error: %s", e.Err.Error())
+	}
+	return fmt.Sprintf("sla monitor failed for %s with error: %s", e.Project, e.Err.Error())
+}
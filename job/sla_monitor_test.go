@@ -0,0 +1,149 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	testMock "github.com/stretchr/testify/mock"
+)
+
+func TestSLAMonitor(t *testing.T) {
+	ctx := context.Background()
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+	}
+	namespace := models.NamespaceSpec{Name: "ns1", ProjectSpec: projSpec}
+	hourly := models.JobSpecSchedule{
+		StartDate: time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC),
+		Interval:  "0 * * * *",
+	}
+	lateJob := models.JobSpec{Name: "job-late", Schedule: hourly, SLADuration: time.Minute * 30}
+	missingJob := models.JobSpec{Name: "job-missing", Schedule: hourly, SLADuration: time.Minute * 30}
+	jobSpecs := []models.JobSpec{
+		lateJob,
+		missingJob,
+		{Name: "job-without-sla", Schedule: hourly},
+		{Name: "template", Schedule: hourly, SLADuration: time.Minute, IsTemplate: true},
+	}
+	namespaces := map[string]models.NamespaceSpec{}
+	for _, jobSpec := range jobSpecs {
+		namespaces[jobSpec.Name] = namespace
+	}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2021, 8, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	type slaMonitorMocks struct {
+		projectJobSpecRepo *mock.ProjectJobSpecRepository
+		scheduler          *mock.Scheduler
+		breachRepo         *mock.SLABreachRepository
+		observer           *mock.PipelineLogObserver
+	}
+	setup := func() (*job.SLAMonitor, slaMonitorMocks) {
+		m := slaMonitorMocks{
+			projectJobSpecRepo: new(mock.ProjectJobSpecRepository),
+			scheduler:          new(mock.Scheduler),
+			breachRepo:         new(mock.SLABreachRepository),
+			observer:           new(mock.PipelineLogObserver),
+		}
+		projectRepo := new(mock.ProjectRepository)
+		projectRepo.On("GetAll").Return([]models.ProjectSpec{projSpec}, nil)
+		projectRepoFac := new(mock.ProjectRepoFactory)
+		projectRepoFac.On("New").Return(projectRepo)
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(m.projectJobSpecRepo)
+
+		monitor := job.NewSLAMonitor(projectRepoFac, projJobSpecRepoFac, m.scheduler, m.breachRepo, time.Minute, m.observer)
+		monitor.Now = func() time.Time { return at(5, 10) }
+		monitor.Lookback = time.Hour * 4
+		return monitor, m
+	}
+
+	t.Run("should save and notify runs not started within the sla of their job", func(t *testing.T) {
+		monitor, m := setup()
+		m.projectJobSpecRepo.On("GetModifiedSince", time.Time{}).Return(jobSpecs, namespaces, nil)
+		// runs of 02:00 and 03:00 are due at 03:00 and 04:00, the run of 04:00
+		// is still within its sla
+		m.scheduler.On("GetDagRunStatus", ctx, projSpec, lateJob.Name, at(2, 0), at(3, 0), 100).Return([]models.JobStatus{
+			{ScheduledAt: at(2, 0), State: models.JobStatusStateSuccess, StartTime: at(3, 5)},
+			{ScheduledAt: at(3, 0), State: models.JobStatusStateSuccess, StartTime: at(4, 45)},
+		}, nil)
+		m.scheduler.On("GetDagRunStatus", ctx, projSpec, missingJob.Name, at(2, 0), at(3, 0), 100).Return([]models.JobStatus{
+			{ScheduledAt: at(2, 0), State: models.JobStatusStateSuccess},
+		}, nil)
+
+		lateBreach := models.SLABreach{
+			ProjectName:   projSpec.Name,
+			NamespaceName: namespace.Name,
+			JobName:       lateJob.Name,
+			ScheduledAt:   at(3, 0),
+			DueAt:         at(4, 0),
+			SLADuration:   time.Minute * 30,
+			StartedAt:     at(4, 45),
+			DetectedAt:    at(5, 10),
+		}
+		missingBreach := models.SLABreach{
+			ProjectName:   projSpec.Name,
+			NamespaceName: namespace.Name,
+			JobName:       missingJob.Name,
+			ScheduledAt:   at(3, 0),
+			DueAt:         at(4, 0),
+			SLADuration:   time.Minute * 30,
+			DetectedAt:    at(5, 10),
+		}
+		m.breachRepo.On("Save", ctx, lateBreach).Return(nil).Once()
+		m.breachRepo.On("Save", ctx, missingBreach).Return(nil).Once()
+		m.observer.On("Notify", &job.EventJobSLABreached{Namespace: namespace, Job: lateJob, Breach: lateBreach}).Once()
+		m.observer.On("Notify", &job.EventJobSLABreached{Namespace: namespace, Job: missingJob, Breach: missingBreach}).Once()
+
+		err := monitor.Check(ctx)
+		assert.Nil(t, err)
+		m.scheduler.AssertExpectations(t)
+		m.breachRepo.AssertExpectations(t)
+		m.observer.AssertExpectations(t)
+	})
+	t.Run("should not notify breaches already saved", func(t *testing.T) {
+		monitor, m := setup()
+		m.projectJobSpecRepo.On("GetModifiedSince", time.Time{}).Return([]models.JobSpec{missingJob}, namespaces, nil)
+		m.scheduler.On("GetDagRunStatus", ctx, projSpec, missingJob.Name, at(2, 0), at(3, 0), 100).
+			Return([]models.JobStatus{}, nil)
+		m.breachRepo.On("Save", ctx, testMock.Anything).Return(store.ErrResourceExists).Twice()
+
+		err := monitor.Check(ctx)
+		assert.Nil(t, err)
+		m.breachRepo.AssertExpectations(t)
+		m.observer.AssertNotCalled(t, "Notify", testMock.Anything)
+	})
+	t.Run("should notify failing projects and keep checking the others", func(t *testing.T) {
+		monitor, m := setup()
+		m.projectJobSpecRepo.On("GetModifiedSince", time.Time{}).Return([]models.JobSpec{missingJob}, namespaces, nil)
+		schedulerErr := errors.New("airflow unreachable")
+		m.scheduler.On("GetDagRunStatus", ctx, projSpec, missingJob.Name, at(2, 0), at(3, 0), 100).
+			Return([]models.JobStatus{}, schedulerErr)
+		m.observer.On("Notify", testMock.MatchedBy(func(e *job.EventSLAMonitorFailed) bool {
+			return e.Project == projSpec.Name && errors.Is(e.Err, schedulerErr)
+		})).Once()
+
+		err := monitor.Check(ctx)
+		assert.Nil(t, err)
+		m.observer.AssertExpectations(t)
+		m.breachRepo.AssertNotCalled(t, "Save", testMock.Anything, testMock.Anything)
+	})
+	t.Run("should fail when projects can't be listed", func(t *testing.T) {
+		projectRepo := new(mock.ProjectRepository)
+		projectRepo.On("GetAll").Return([]models.ProjectSpec{}, errors.New("db down"))
+		projectRepoFac := new(mock.ProjectRepoFactory)
+		projectRepoFac.On("New").Return(projectRepo)
+
+		monitor := job.NewSLAMonitor(projectRepoFac, nil, nil, nil, time.Minute, nil)
+		err := monitor.Check(ctx)
+		assert.Contains(t, err.Error(), "failed to retrieve projects")
+	})
+}
@@ -0,0 +1,42 @@
+package job
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+// quantityPattern matches the kubernetes resource quantity grammar, a number
+// with an optional binary SI suffix, decimal SI suffix or decimal exponent,
+// see https://github.com/kubernetes/apimachinery/blob/master/pkg/api/resource/quantity.go
+var quantityPattern = regexp.MustCompile(
+	`^[+-]?(?:[0-9]+(?:\.[0-9]*)?|\.[0-9]+)(?:[KMGTPE]i|[numkMGTPE]|[eE][+-]?[0-9]+)?$`)
+
+// validateTaskResources checks the resources of the task are valid
+// kubernetes quantities that aren't negative
+func validateTaskResources(jobSpec models.JobSpec) error {
+	resources := jobSpec.Task.Resources
+	for _, quantity := range []struct {
+		name, value string
+	}{
+		{"cpu_request", resources.CPURequest},
+		{"cpu_limit", resources.CPULimit},
+		{"memory_request", resources.MemoryRequest},
+		{"memory_limit", resources.MemoryLimit},
+	} {
+		if quantity.value == "" {
+			continue
+		}
+		if !quantityPattern.MatchString(quantity.value) {
+			return errors.Errorf("%s %q of job %s is not a valid resource quantity",
+				quantity.name, quantity.value, jobSpec.Name)
+		}
+		if strings.HasPrefix(quantity.value, "-") {
+			return errors.Errorf("%s %q of job %s can't be negative",
+				quantity.name, quantity.value, jobSpec.Name)
+		}
+	}
+	return nil
+}
@@ -30,7 +30,7 @@ var (
 func main() {
 	rand.Seed(time.Now().UTC().UnixNano())
 
-	configuration, err := config.InitOptimus()
+	configuration, err := config.InitOptimus(config.LookupFile(os.Args[1:]))
 	if err != nil {
 		fmt.Printf("ERROR: %s\n", err.Error())
 		os.Exit(1)
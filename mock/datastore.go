@@ -38,6 +38,40 @@ func (d *Datastorer) DeleteResource(ctx context.Context, inp models.DeleteResour
 	return d.Called(ctx, inp).Error(0)
 }
 
+type DatastoreSchemaInspector struct {
+	mock.Mock
+}
+
+func (d *DatastoreSchemaInspector) TableSchema(ctx context.Context, project models.ProjectSpec, tableName string) ([]models.SchemaField, error) {
+	args := d.Called(ctx, project, tableName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SchemaField), args.Error(1)
+}
+
+func (d *DatastoreSchemaInspector) QuerySchema(ctx context.Context, project models.ProjectSpec, query string) ([]models.SchemaField, error) {
+	args := d.Called(ctx, project, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SchemaField), args.Error(1)
+}
+
+func (d *DatastoreSchemaInspector) TableExists(ctx context.Context, project models.ProjectSpec, tableName string) (bool, error) {
+	args := d.Called(ctx, project, tableName)
+	return args.Bool(0), args.Error(1)
+}
+
+type DatastoreCostEstimator struct {
+	mock.Mock
+}
+
+func (d *DatastoreCostEstimator) QueryBytesProcessed(ctx context.Context, project models.ProjectSpec, query string) (int64, error) {
+	args := d.Called(ctx, project, query)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 type DatastoreTypeController struct {
 	mock.Mock
 }
@@ -1,8 +1,10 @@
 package mock
 
 import (
+	"context"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/odpf/optimus/models"
 	"github.com/odpf/optimus/store"
 	"github.com/stretchr/testify/mock"
@@ -38,6 +40,39 @@ func (repo *InstanceSpecRepository) Clear(st time.Time) error {
 	return repo.Called(st).Error(0)
 }
 
+func (repo *InstanceSpecRepository) ListActiveInstances(ctx context.Context, maxAge time.Duration) ([]models.InstanceSpec, error) {
+	args := repo.Called(ctx, maxAge)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.InstanceSpec), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (repo *InstanceSpecRepository) GetRunningDuration(ctx context.Context, jobSpec models.JobSpec) (time.Duration, error) {
+	args := repo.Called(ctx, jobSpec)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (repo *InstanceSpecRepository) AggregateByStatus(ctx context.Context, projectSpec models.ProjectSpec, window time.Duration) (map[string]int64, error) {
+	args := repo.Called(ctx, projectSpec, window)
+	if args.Get(0) != nil {
+		return args.Get(0).(map[string]int64), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (repo *InstanceSpecRepository) ListStaleInstances(ctx context.Context, projectSpec models.ProjectSpec, minAge time.Duration) ([]models.InstanceSpec, error) {
+	args := repo.Called(ctx, projectSpec, minAge)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.InstanceSpec), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (repo *InstanceSpecRepository) UpdateState(ctx context.Context, id uuid.UUID, state, reason string) error {
+	return repo.Called(ctx, id, state, reason).Error(0)
+}
+
 type InstanceService struct {
 	mock.Mock
 }
@@ -52,3 +87,24 @@ func (s *InstanceService) Register(jobSpec models.JobSpec, scheduledAt time.Time
 	args := s.Called(jobSpec, scheduledAt, taskType)
 	return args.Get(0).(models.InstanceSpec), args.Error(1)
 }
+
+func (s *InstanceService) ListActive(ctx context.Context, maxAge time.Duration) ([]models.InstanceSpec, error) {
+	args := s.Called(ctx, maxAge)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.InstanceSpec), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (s *InstanceService) GetStatusSummary(ctx context.Context, projectSpec models.ProjectSpec, window time.Duration) (map[string]int64, error) {
+	args := s.Called(ctx, projectSpec, window)
+	if args.Get(0) != nil {
+		return args.Get(0).(map[string]int64), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (s *InstanceService) ReconcileDeadInstances(ctx context.Context, projectSpec models.ProjectSpec) (int, error) {
+	args := s.Called(ctx, projectSpec)
+	return args.Int(0), args.Error(1)
+}
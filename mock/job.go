@@ -2,7 +2,10 @@ package mock
 
 import (
 	"context"
+	"io"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/odpf/optimus/job"
 
 	"github.com/odpf/optimus/core/tree"
@@ -52,6 +55,78 @@ func (repo *ProjectJobSpecRepository) GetByDestination(dest string) (models.JobS
 	return models.JobSpec{}, models.ProjectSpec{}, args.Error(2)
 }
 
+func (repo *ProjectJobSpecRepository) GetDependents(jobName string) ([]models.JobSpec, error) {
+	args := repo.Called(jobName)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.JobSpec), args.Error(1)
+	}
+	return []models.JobSpec{}, args.Error(1)
+}
+
+func (repo *ProjectJobSpecRepository) GetModifiedSince(since time.Time) ([]models.JobSpec, map[string]models.NamespaceSpec, error) {
+	args := repo.Called(since)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.JobSpec), args.Get(1).(map[string]models.NamespaceSpec), args.Error(2)
+	}
+	return nil, nil, args.Error(2)
+}
+
+func (repo *ProjectJobSpecRepository) GetDeletedSince(since time.Time) (map[string]models.NamespaceSpec, error) {
+	args := repo.Called(since)
+	if args.Get(0) != nil {
+		return args.Get(0).(map[string]models.NamespaceSpec), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (repo *ProjectJobSpecRepository) ExportCSV(ctx context.Context) (io.ReadCloser, error) {
+	args := repo.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).(io.ReadCloser), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (repo *ProjectJobSpecRepository) GetVersions(jobName string) ([]models.JobSpecVersion, error) {
+	args := repo.Called(jobName)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.JobSpecVersion), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (repo *ProjectJobSpecRepository) GetVersion(jobName string, version int) (models.JobSpecVersion, error) {
+	args := repo.Called(jobName, version)
+	return args.Get(0).(models.JobSpecVersion), args.Error(1)
+}
+
+// CrossProjectJobSpecRepository to read specs of all projects
+type CrossProjectJobSpecRepository struct {
+	mock.Mock
+}
+
+func (repo *CrossProjectJobSpecRepository) GetJobsByOwner(ctx context.Context, owner string) ([]models.JobSpecWithNamespace, error) {
+	args := repo.Called(ctx, owner)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.JobSpecWithNamespace), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (repo *CrossProjectJobSpecRepository) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int, error) {
+	args := repo.Called(ctx, olderThan)
+	return args.Int(0), args.Error(1)
+}
+
+func (repo *CrossProjectJobSpecRepository) GetCronExpression(ctx context.Context, projectSpec models.ProjectSpec, jobName string) (string, error) {
+	args := repo.Called(ctx, projectSpec, jobName)
+	return args.String(0), args.Error(1)
+}
+
+func (repo *CrossProjectJobSpecRepository) InsertAll(ctx context.Context, jobs []models.JobSpecWithNamespace) error {
+	return repo.Called(ctx, jobs).Error(0)
+}
+
 // JobSpecRepoFactory to store raw specs at namespace level
 type JobSpecRepoFactory struct {
 	mock.Mock
@@ -70,6 +145,10 @@ func (repo *JobSpecRepository) Save(t models.JobSpec) error {
 	return repo.Called(t).Error(0)
 }
 
+func (repo *JobSpecRepository) InsertAll(specs []models.JobSpec) error {
+	return repo.Called(specs).Error(0)
+}
+
 func (repo *JobSpecRepository) GetByName(name string) (models.JobSpec, error) {
 	args := repo.Called(name)
 	if args.Get(0) != nil {
@@ -82,6 +161,23 @@ func (repo *JobSpecRepository) Delete(name string) error {
 	return repo.Called(name).Error(0)
 }
 
+func (repo *JobSpecRepository) Undelete(name string) error {
+	return repo.Called(name).Error(0)
+}
+
+func (repo *JobSpecRepository) GetSpecChecksum(jobName string) (string, error) {
+	args := repo.Called(jobName)
+	return args.String(0), args.Error(1)
+}
+
+func (repo *JobSpecRepository) GetSpecChecksums(jobNames []string) (map[string]string, error) {
+	args := repo.Called(jobNames)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
 func (repo *JobSpecRepository) GetAll() ([]models.JobSpec, error) {
 	args := repo.Called()
 	if args.Get(0) != nil {
@@ -90,6 +186,11 @@ func (repo *JobSpecRepository) GetAll() ([]models.JobSpec, error) {
 	return []models.JobSpec{}, args.Error(1)
 }
 
+func (repo *JobSpecRepository) GetPage(afterName string, limit int, labels map[string]string) ([]models.JobSpec, error) {
+	args := repo.Called(afterName, limit, labels)
+	return args.Get(0).([]models.JobSpec), args.Error(1)
+}
+
 func (repo *JobSpecRepository) GetByDestination(dest string) (models.JobSpec, models.ProjectSpec, error) {
 	args := repo.Called(dest)
 	if args.Get(0) != nil {
@@ -156,11 +257,27 @@ func (srv *JobService) Create(spec2 models.NamespaceSpec, spec models.JobSpec) e
 	return args.Error(0)
 }
 
+func (srv *JobService) SaveAll(namespace models.NamespaceSpec, specs []models.JobSpec) error {
+	return srv.Called(namespace, specs).Error(0)
+}
+
+func (srv *JobService) CreateAll(namespace models.NamespaceSpec, specs []models.JobSpec) error {
+	return srv.Called(namespace, specs).Error(0)
+}
+
 func (srv *JobService) GetByName(s string, spec models.NamespaceSpec) (models.JobSpec, error) {
 	args := srv.Called(s, spec)
 	return args.Get(0).(models.JobSpec), args.Error(1)
 }
 
+func (srv *JobService) GetSpecChecksums(names []string, spec models.NamespaceSpec) (map[string]string, error) {
+	args := srv.Called(names, spec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
 func (srv *JobService) Dump(spec2 models.NamespaceSpec, spec3 models.JobSpec) (models.Job, error) {
 	args := srv.Called(spec2, spec3)
 	return args.Get(0).(models.Job), args.Error(1)
@@ -176,6 +293,11 @@ func (srv *JobService) GetAll(spec models.NamespaceSpec) ([]models.JobSpec, erro
 	return args.Get(0).([]models.JobSpec), args.Error(1)
 }
 
+func (srv *JobService) GetPage(spec models.NamespaceSpec, afterName string, limit int, labels map[string]string) ([]models.JobSpec, error) {
+	args := srv.Called(spec, afterName, limit, labels)
+	return args.Get(0).([]models.JobSpec), args.Error(1)
+}
+
 func (srv *JobService) GetByNameForProject(s string, spec models.ProjectSpec) (models.JobSpec, models.NamespaceSpec, error) {
 	args := srv.Called(s, spec)
 	return args.Get(0).(models.JobSpec), args.Get(1).(models.NamespaceSpec), args.Error(2)
@@ -196,6 +318,15 @@ func (j *JobService) Delete(ctx context.Context, c models.NamespaceSpec, job mod
 	return args.Error(0)
 }
 
+func (j *JobService) Undelete(ctx context.Context, namespace models.NamespaceSpec, jobName string) error {
+	return j.Called(ctx, namespace, jobName).Error(0)
+}
+
+func (j *JobService) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int, error) {
+	args := j.Called(ctx, olderThan)
+	return args.Int(0), args.Error(1)
+}
+
 func (j *JobService) ReplayDryRun(replayRequest *models.ReplayWorkerRequest) (*tree.TreeNode, error) {
 	args := j.Called(replayRequest)
 	return args.Get(0).(*tree.TreeNode), args.Error(1)
@@ -206,6 +337,121 @@ func (j *JobService) Replay(ctx context.Context, replayRequest *models.ReplayWor
 	return args.Get(0).(string), args.Error(1)
 }
 
+func (j *JobService) ValidateDependencyVersionCompatibility(ctx context.Context, namespace models.NamespaceSpec,
+	specs []models.JobSpec) ([]models.CompatibilityWarning, error) {
+	args := j.Called(ctx, namespace, specs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CompatibilityWarning), args.Error(1)
+}
+
+func (j *JobService) GetReplay(jobSpec models.JobSpec, replayID uuid.UUID) (models.ReplaySpec, error) {
+	args := j.Called(jobSpec, replayID)
+	return args.Get(0).(models.ReplaySpec), args.Error(1)
+}
+
+func (j *JobService) BackfillMissingRuns(ctx context.Context, projectSpec models.ProjectSpec, jobName string,
+	start, end time.Time, dryRun bool) (models.BackfillPlan, error) {
+	args := j.Called(ctx, projectSpec, jobName, start, end, dryRun)
+	return args.Get(0).(models.BackfillPlan), args.Error(1)
+}
+
+func (j *JobService) PinDependencyVersion(projectSpec models.ProjectSpec, jobName, dependencyName, specVersion string) error {
+	return j.Called(projectSpec, jobName, dependencyName, specVersion).Error(0)
+}
+
+func (j *JobService) UnpinDependency(projectSpec models.ProjectSpec, jobName, dependencyName string) error {
+	return j.Called(projectSpec, jobName, dependencyName).Error(0)
+}
+
+func (j *JobService) ListDependencyPins(projectSpec models.ProjectSpec) ([]models.DependencyPin, error) {
+	args := j.Called(projectSpec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DependencyPin), args.Error(1)
+}
+
+func (j *JobService) PropagateScheduleChanges(ctx context.Context, projSpec models.ProjectSpec, jobName string) ([]models.ScheduleChange, error) {
+	args := j.Called(ctx, projSpec, jobName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ScheduleChange), args.Error(1)
+}
+
+func (j *JobService) TagRelease(projectSpec models.ProjectSpec, tag, description string, observer progress.Observer) error {
+	return j.Called(projectSpec, tag, description, observer).Error(0)
+}
+
+func (j *JobService) RollbackToRelease(ctx context.Context, projectSpec models.ProjectSpec, tag string, observer progress.Observer) (models.Release, error) {
+	args := j.Called(ctx, projectSpec, tag, observer)
+	return args.Get(0).(models.Release), args.Error(1)
+}
+
+func (j *JobService) DetectSchemaDrift(ctx context.Context, projectSpec models.ProjectSpec, jobSpec models.JobSpec) (*models.SchemaDrift, error) {
+	args := j.Called(ctx, projectSpec, jobSpec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SchemaDrift), args.Error(1)
+}
+
+func (j *JobService) EstimateCost(ctx context.Context, projectSpec models.ProjectSpec, jobSpec models.JobSpec) (*models.CostEstimate, error) {
+	args := j.Called(ctx, projectSpec, jobSpec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CostEstimate), args.Error(1)
+}
+
+func (j *JobService) DiffJobSpec(projectSpec models.ProjectSpec, jobName string, fromVersion, toVersion int) (models.JobSpecDiff, error) {
+	args := j.Called(projectSpec, jobName, fromVersion, toVersion)
+	return args.Get(0).(models.JobSpecDiff), args.Error(1)
+}
+
+func (j *JobService) GetJobsByOwner(ctx context.Context, owner string) ([]models.JobSpecWithNamespace, error) {
+	args := j.Called(ctx, owner)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.JobSpecWithNamespace), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (j *JobService) CloneJobSpec(projectSpec models.ProjectSpec, sourceJobName, destJobName string) (models.JobSpec, models.NamespaceSpec, error) {
+	args := j.Called(projectSpec, sourceJobName, destJobName)
+	return args.Get(0).(models.JobSpec), args.Get(1).(models.NamespaceSpec), args.Error(2)
+}
+
+func (j *JobService) CloneProject(ctx context.Context, sourceNamespaces, destNamespaces []models.NamespaceSpec,
+	replacements []models.StringReplacement) (int, error) {
+	args := j.Called(ctx, sourceNamespaces, destNamespaces, replacements)
+	return args.Int(0), args.Error(1)
+}
+
+func (j *JobService) ExplainDependencyResolution(ctx context.Context, projectSpec models.ProjectSpec, jobSpec models.JobSpec) (*models.ResolutionTrace, error) {
+	args := j.Called(ctx, projectSpec, jobSpec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ResolutionTrace), args.Error(1)
+}
+
+func (j *JobService) GetDependencyGraph(ctx context.Context, projectSpec models.ProjectSpec, rootJobName string,
+	depth int) (models.DependencyGraph, error) {
+	args := j.Called(ctx, projectSpec, rootJobName, depth)
+	return args.Get(0).(models.DependencyGraph), args.Error(1)
+}
+
+func (j *JobService) ValidateAssetReferences(ctx context.Context, projectSpec models.ProjectSpec, jobSpec models.JobSpec) []models.AssetWarning {
+	args := j.Called(ctx, projectSpec, jobSpec)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).([]models.AssetWarning)
+}
+
 type Compiler struct {
 	mock.Mock
 }
@@ -253,3 +499,138 @@ func (n *Notifier) Close() error {
 func (n *Notifier) Notify(ctx context.Context, attr models.NotifyAttrs) error {
 	return n.Called(ctx, attr).Error(0)
 }
+
+// DependencyPinRepoFactory to manage dependency pins of a project
+type DependencyPinRepoFactory struct {
+	mock.Mock
+}
+
+func (fac *DependencyPinRepoFactory) New(proj models.ProjectSpec) store.DependencyPinRepository {
+	return fac.Called(proj).Get(0).(store.DependencyPinRepository)
+}
+
+type DependencyPinRepository struct {
+	mock.Mock
+}
+
+func (repo *DependencyPinRepository) Pin(jobName, dependencyName, specVersion string) error {
+	return repo.Called(jobName, dependencyName, specVersion).Error(0)
+}
+
+func (repo *DependencyPinRepository) Delete(jobName, dependencyName string) error {
+	return repo.Called(jobName, dependencyName).Error(0)
+}
+
+func (repo *DependencyPinRepository) GetByJob(jobName string) ([]models.DependencyPin, error) {
+	args := repo.Called(jobName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DependencyPin), args.Error(1)
+}
+
+func (repo *DependencyPinRepository) GetAll() ([]models.DependencyPin, error) {
+	args := repo.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DependencyPin), args.Error(1)
+}
+
+// ReleaseRepoFactory to manage releases of a project
+type ReleaseRepoFactory struct {
+	mock.Mock
+}
+
+func (fac *ReleaseRepoFactory) New(proj models.ProjectSpec) store.ReleaseRepository {
+	return fac.Called(proj).Get(0).(store.ReleaseRepository)
+}
+
+type ReleaseRepository struct {
+	mock.Mock
+}
+
+func (repo *ReleaseRepository) Create(tag, description string) error {
+	return repo.Called(tag, description).Error(0)
+}
+
+func (repo *ReleaseRepository) GetByTag(tag string) (models.Release, error) {
+	args := repo.Called(tag)
+	return args.Get(0).(models.Release), args.Error(1)
+}
+
+// DeploymentStateRepoFactory to manage the deployment state of a project
+type DeploymentStateRepoFactory struct {
+	mock.Mock
+}
+
+func (fac *DeploymentStateRepoFactory) New(proj models.ProjectSpec) store.DeploymentStateRepository {
+	return fac.Called(proj).Get(0).(store.DeploymentStateRepository)
+}
+
+type DeploymentStateRepository struct {
+	mock.Mock
+}
+
+func (repo *DeploymentStateRepository) GetLastDeployedAt() (time.Time, error) {
+	args := repo.Called()
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (repo *DeploymentStateRepository) SetLastDeployedAt(deployedAt time.Time) error {
+	return repo.Called(deployedAt).Error(0)
+}
+
+func (repo *DeploymentStateRepository) TryLock(ctx context.Context) (func() error, bool, error) {
+	args := repo.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(func() error), args.Bool(1), args.Error(2)
+}
+
+type SLABreachRepository struct {
+	mock.Mock
+}
+
+func (repo *SLABreachRepository) Save(ctx context.Context, breach models.SLABreach) error {
+	return repo.Called(ctx, breach).Error(0)
+}
+
+func (repo *SLABreachRepository) GetAll(ctx context.Context, projectName string, filter models.SLABreachFilter) ([]models.SLABreach, error) {
+	args := repo.Called(ctx, projectName, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.SLABreach), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type JobRunTriggerRepository struct {
+	mock.Mock
+}
+
+func (repo *JobRunTriggerRepository) Save(ctx context.Context, trigger models.JobRunTrigger) error {
+	return repo.Called(ctx, trigger).Error(0)
+}
+
+type JobPauseRepository struct {
+	mock.Mock
+}
+
+func (repo *JobPauseRepository) Save(ctx context.Context, projectName string, jobNames []string, paused bool,
+	principal string, at time.Time) error {
+	return repo.Called(ctx, projectName, jobNames, paused, principal, at).Error(0)
+}
+
+func (repo *JobPauseRepository) GetPaused(ctx context.Context, projectName string) ([]string, error) {
+	args := repo.Called(ctx, projectName)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (repo *JobPauseRepository) GetPausePeriods(ctx context.Context, projectName, jobName string) ([]models.JobPausePeriod, error) {
+	args := repo.Called(ctx, projectName, jobName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.JobPausePeriod), args.Error(1)
+}
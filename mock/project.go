@@ -1,6 +1,9 @@
 package mock
 
 import (
+	"context"
+	"time"
+
 	"github.com/odpf/optimus/core/progress"
 	"github.com/odpf/optimus/models"
 	"github.com/odpf/optimus/store"
@@ -25,6 +28,22 @@ func (pr *ProjectRepository) GetAll() ([]models.ProjectSpec, error) {
 	return args.Get(0).([]models.ProjectSpec), args.Error(1)
 }
 
+func (pr *ProjectRepository) SaveVariables(projectName string, variables models.ProjectVariables) error {
+	return pr.Called(projectName, variables).Error(0)
+}
+
+func (pr *ProjectRepository) SaveQuota(projectName string, quota models.ProjectQuota) error {
+	return pr.Called(projectName, quota).Error(0)
+}
+
+func (pr *ProjectRepository) GetProjectHealth(ctx context.Context, spec models.ProjectSpec) (*models.ProjectHealth, error) {
+	args := pr.Called(ctx, spec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProjectHealth), args.Error(1)
+}
+
 type ProjectRepoFactory struct {
 	mock.Mock
 }
@@ -61,6 +80,105 @@ func (pr *ProjectSecretRepository) GetAll() ([]models.ProjectSecretItem, error)
 	return args.Get(0).([]models.ProjectSecretItem), args.Error(1)
 }
 
+func (pr *ProjectSecretRepository) GetVersion(name string, version int) (models.ProjectSecretItem, error) {
+	args := pr.Called(name, version)
+	return args.Get(0).(models.ProjectSecretItem), args.Error(1)
+}
+
+func (pr *ProjectSecretRepository) ListVersions(name string) ([]models.ProjectSecretVersion, error) {
+	args := pr.Called(name)
+	return args.Get(0).([]models.ProjectSecretVersion), args.Error(1)
+}
+
+func (pr *ProjectSecretRepository) Migrate(fromHash, toHash models.ApplicationKey) error {
+	return pr.Called(fromHash, toHash).Error(0)
+}
+
+type RoleBindingRepoFactory struct {
+	mock.Mock
+}
+
+func (fac *RoleBindingRepoFactory) New(p models.ProjectSpec) store.ProjectRoleBindingRepository {
+	args := fac.Called(p)
+	return args.Get(0).(store.ProjectRoleBindingRepository)
+}
+
+type RoleBindingRepository struct {
+	mock.Mock
+}
+
+func (repo *RoleBindingRepository) Save(binding models.ProjectRoleBinding) error {
+	return repo.Called(binding).Error(0)
+}
+
+func (repo *RoleBindingRepository) Delete(subject string) error {
+	return repo.Called(subject).Error(0)
+}
+
+func (repo *RoleBindingRepository) GetAll() ([]models.ProjectRoleBinding, error) {
+	args := repo.Called()
+	return args.Get(0).([]models.ProjectRoleBinding), args.Error(1)
+}
+
+type APIKeyRepoFactory struct {
+	mock.Mock
+}
+
+func (fac *APIKeyRepoFactory) New(p models.ProjectSpec) store.APIKeyRepository {
+	args := fac.Called(p)
+	return args.Get(0).(store.APIKeyRepository)
+}
+
+type APIKeyRepository struct {
+	mock.Mock
+}
+
+func (repo *APIKeyRepository) Save(key models.APIKey, keyHash string) error {
+	return repo.Called(key, keyHash).Error(0)
+}
+
+type SecretRotator struct {
+	mock.Mock
+}
+
+func (r *SecretRotator) RotateSecrets(oldKey, newKey models.ApplicationKey) error {
+	return r.Called(oldKey, newKey).Error(0)
+}
+
+type SecretExpiryLister struct {
+	mock.Mock
+}
+
+func (l *SecretExpiryLister) GetExpiring(before time.Time) ([]models.ExpiringProjectSecret, error) {
+	args := l.Called(before)
+	return args.Get(0).([]models.ExpiringProjectSecret), args.Error(1)
+}
+
+type AuditLogRepository struct {
+	mock.Mock
+}
+
+func (r *AuditLogRepository) Save(ctx context.Context, entry models.AuditLogEntry) error {
+	return r.Called(ctx, entry).Error(0)
+}
+
+func (r *AuditLogRepository) GetAll(ctx context.Context, filter models.AuditLogFilter) ([]models.AuditLogEntry, error) {
+	args := r.Called(ctx, filter)
+	return args.Get(0).([]models.AuditLogEntry), args.Error(1)
+}
+
+type ProjectHealthService struct {
+	mock.Mock
+}
+
+func (srv *ProjectHealthService) GetProjectHealth(ctx context.Context, spec models.ProjectSpec) (*models.ProjectHealth, error) {
+	args := srv.Called(ctx, spec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProjectHealth), args.Error(1)
+}
+
 type PipelineLogObserver struct {
 	mock.Mock
 }
@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/odpf/optimus/models"
@@ -53,6 +54,20 @@ func (rm *ReplayManager) Replay(ctx context.Context, reqInput *models.ReplayWork
 	return args.Get(0).(string), args.Error(1)
 }
 
+func (rm *ReplayManager) GetReplay(jobSpec models.JobSpec, replayID uuid.UUID) (models.ReplaySpec, error) {
+	args := rm.Called(jobSpec, replayID)
+	return args.Get(0).(models.ReplaySpec), args.Error(1)
+}
+
+func (rm *ReplayManager) GetRunStatus(ctx context.Context, projectSpec models.ProjectSpec, startDate time.Time,
+	endDate time.Time, jobName string) ([]models.JobStatus, error) {
+	args := rm.Called(ctx, projectSpec, startDate, endDate, jobName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.JobStatus), args.Error(1)
+}
+
 func (rm *ReplayManager) Init() {
 	rm.Called()
 	return
@@ -41,3 +41,37 @@ func (r *RuntimeService_DeployJobSpecificationServer) SendMsg(m interface{}) err
 func (r *RuntimeService_DeployJobSpecificationServer) RecvMsg(m interface{}) error {
 	panic("implement me")
 }
+
+type RuntimeService_CheckJobSpecificationsServer struct {
+	mock.Mock
+}
+
+func (r *RuntimeService_CheckJobSpecificationsServer) Send(response *pb.CheckJobSpecificationsResponse) error {
+	args := r.Called(response)
+	return args.Error(0)
+}
+
+func (r *RuntimeService_CheckJobSpecificationsServer) SetHeader(md metadata.MD) error {
+	panic("implement me")
+}
+
+func (r *RuntimeService_CheckJobSpecificationsServer) SendHeader(md metadata.MD) error {
+	panic("implement me")
+}
+
+func (r *RuntimeService_CheckJobSpecificationsServer) SetTrailer(md metadata.MD) {
+	panic("implement me")
+}
+
+func (r *RuntimeService_CheckJobSpecificationsServer) Context() context.Context {
+	args := r.Called()
+	return args.Get(0).(context.Context)
+}
+
+func (r *RuntimeService_CheckJobSpecificationsServer) SendMsg(m interface{}) error {
+	panic("implement me")
+}
+
+func (r *RuntimeService_CheckJobSpecificationsServer) RecvMsg(m interface{}) error {
+	panic("implement me")
+}
@@ -47,3 +47,31 @@ func (ms *Scheduler) GetDagRunStatus(ctx context.Context, projSpec models.Projec
 	args := ms.Called(ctx, projSpec, jobName, startDate, endDate, batchSize)
 	return args.Get(0).([]models.JobStatus), args.Error(1)
 }
+
+func (ms *Scheduler) GetRunningJobs(ctx context.Context, projSpec models.ProjectSpec) ([]models.RunningJob, error) {
+	args := ms.Called(ctx, projSpec)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.RunningJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (ms *Scheduler) ListDeployedJobs(ctx context.Context, projSpec models.ProjectSpec) ([]string, error) {
+	args := ms.Called(ctx, projSpec)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (ms *Scheduler) TriggerJobRun(ctx context.Context, projSpec models.ProjectSpec, jobName string, logicalDate time.Time,
+	conf map[string]string) (string, error) {
+	args := ms.Called(ctx, projSpec, jobName, logicalDate, conf)
+	return args.String(0), args.Error(1)
+}
+
+func (ms *Scheduler) SetJobPaused(ctx context.Context, projSpec models.ProjectSpec, jobName string, paused bool) error {
+	return ms.Called(ctx, projSpec, jobName, paused).Error(0)
+}
+
+func (ms *Scheduler) GetSchedulerVersion(ctx context.Context, projSpec models.ProjectSpec) (string, error) {
+	args := ms.Called(ctx, projSpec)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey authenticates machine callers, like a ci pipeline, with a role in a
+// single project. Only the hash of the key is stored
+type APIKey struct {
+	ID          uuid.UUID
+	ProjectName string
+	Description string
+	Role        Role
+	CreatedAt   time.Time
+	// ExpiresAt is the time after which the key is rejected, keys without it
+	// don't expire
+	ExpiresAt *time.Time
+}
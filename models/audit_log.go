@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditLogEntry records a call that changes the state of optimus
+type AuditLogEntry struct {
+	Time time.Time
+	// Principal is the subject of the token or the api key of the caller,
+	// the address of anonymous callers
+	Principal   string
+	Method      string
+	ProjectName string
+	// Request is the json of the request with sensitive fields redacted,
+	// large requests are truncated
+	Request string
+	// Status is the grpc code the call returned
+	Status string
+}
+
+// AuditLogFilter selects entries of the audit log, empty fields and zero
+// times match all entries
+type AuditLogFilter struct {
+	ProjectName string
+	Principal   string
+	Since       time.Time
+	Until       time.Time
+	Limit       int
+}
@@ -64,6 +64,26 @@ type Datastorer interface {
 	DeleteResource(context.Context, DeleteResourceRequest) error
 }
 
+// DatastoreSchemaInspector is implemented by datastores that can read the
+// schema of a table and infer the schema of a query without running it
+type DatastoreSchemaInspector interface {
+	// TableSchema returns the columns of the table
+	TableSchema(ctx context.Context, project ProjectSpec, tableName string) ([]SchemaField, error)
+
+	// QuerySchema returns the columns the query would produce
+	QuerySchema(ctx context.Context, project ProjectSpec, query string) ([]SchemaField, error)
+
+	// TableExists reports if the table exists
+	TableExists(ctx context.Context, project ProjectSpec, tableName string) (bool, error)
+}
+
+// DatastoreCostEstimator is implemented by datastores that can tell the bytes
+// a query reads without running it
+type DatastoreCostEstimator interface {
+	// QueryBytesProcessed returns the bytes the query would read
+	QueryBytesProcessed(ctx context.Context, project ProjectSpec, query string) (int64, error)
+}
+
 type DatastoreTypeController interface {
 	Adapter() DatastoreSpecAdapter
 	Validator() DatastoreSpecValidator
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 
@@ -28,6 +29,10 @@ const (
 	InstanceStateFailed  = "failed"
 	InstanceStateSuccess = "success"
 
+	// InstanceStateReasonOrphaned marks instances failed on reconciliation
+	// since the scheduler has no active run for them
+	InstanceStateReasonOrphaned = "orphaned"
+
 	// InstanceType is the kind of execution happening at the time
 	InstanceTypeTask InstanceType = "task"
 	InstanceTypeHook InstanceType = "hook"
@@ -54,10 +59,14 @@ func (I InstanceType) New(val string) (InstanceType, error) {
 }
 
 type InstanceSpec struct {
-	ID          uuid.UUID
-	Job         JobSpec
+	ID  uuid.UUID
+	Job JobSpec
+	// Project owning the job, only ID and Name are populated
+	Project     ProjectSpec
 	ScheduledAt time.Time
 	State       string
+	// StateReason tells why the instance moved to its state, if known
+	StateReason string
 	Data        []InstanceSpecData
 }
 
@@ -78,10 +87,65 @@ type InstanceService interface {
 	Register(jobSpec JobSpec, scheduledAt time.Time, taskType InstanceType) (InstanceSpec, error)
 	Compile(namespaceSpec NamespaceSpec, jobSpec JobSpec, instanceSpec InstanceSpec,
 		runType InstanceType, runName string) (envMap map[string]string, fileMap map[string]string, err error)
+
+	// ListActive returns running instances of all projects created within maxAge
+	ListActive(ctx context.Context, maxAge time.Duration) ([]InstanceSpec, error)
+
+	// GetStatusSummary counts instances of the project created within window by their state
+	GetStatusSummary(ctx context.Context, projectSpec ProjectSpec, window time.Duration) (map[string]int64, error)
+
+	// ReconcileDeadInstances fails the running instances of the project the
+	// scheduler has no active run for, returns the number of instances failed
+	ReconcileDeadInstances(ctx context.Context, projectSpec ProjectSpec) (int, error)
 }
 
-// TemplateEngine compiles raw text templates using provided values
+// TemplateEngine compiles raw text templates using provided values.
+// Engines other than the builtin go and jinja engines can be registered in
+// TemplateEngineRegistry before the server starts, for example an engine
+// calling a jinja2 subprocess, and selected with serve.template_engine or the
+// TEMPLATE_ENGINE config of a project
 type TemplateEngine interface {
+	// CompileFiles renders a set of assets, assets can refer to each other
+	// by name. Assets with an extension in IgnoreTemplateRenderExtension
+	// are returned as they are
 	CompileFiles(files map[string]string, context map[string]interface{}) (map[string]string, error)
+
+	// CompileString renders a single template like a config value
 	CompileString(input string, context map[string]interface{}) (string, error)
 }
+
+const (
+	// DefaultTemplateEngine renders assets if no engine is configured
+	DefaultTemplateEngine = "go"
+)
+
+var (
+	// TemplateEngineRegistry holds the engines assets can be rendered with
+	// besides the builtin ones
+	TemplateEngineRegistry = &supportedTemplateEngines{
+		data: map[string]TemplateEngine{},
+	}
+	ErrUnsupportedTemplateEngine = errors.New("unsupported template engine requested")
+)
+
+type supportedTemplateEngines struct {
+	data map[string]TemplateEngine
+}
+
+func (s *supportedTemplateEngines) GetByName(name string) (TemplateEngine, error) {
+	if engine, ok := s.data[name]; ok {
+		return engine, nil
+	}
+	return nil, errors.Wrap(ErrUnsupportedTemplateEngine, name)
+}
+
+func (s *supportedTemplateEngines) Add(name string, engine TemplateEngine) error {
+	if name == "" {
+		return errors.New("template engine name cannot be empty")
+	}
+	if _, ok := s.data[name]; ok {
+		return errors.Errorf("template engine name already in use %s", name)
+	}
+	s.data[name] = engine
+	return nil
+}
@@ -24,9 +24,21 @@ var (
 	ErrNoResources = errors.New("no resources found")
 	ErrNoSuchAsset = errors.New("asset not found")
 	ErrNoSuchHook  = errors.New("hook not found")
+
+	// ErrSchemaVersionTooNew is returned when a job spec was stored by a newer
+	// version of optimus than the one reading it
+	ErrSchemaVersionTooNew = errors.New("job spec schema version is newer than supported")
+
+	// ErrInvalidTimezone is returned for schedules with a timezone that
+	// isn't in the IANA timezone database
+	ErrInvalidTimezone = errors.New("invalid timezone")
 )
 
 const (
+	// JobSpecSchemaVersion is the version of the job spec schema written by
+	// this version of optimus
+	JobSpecSchemaVersion = 1
+
 	JobDatetimeLayout = "2006-01-02"
 
 	// assuming all month are 30 days long for simplicity
@@ -41,23 +53,36 @@ const (
 
 	JobEventTypeSLAMiss JobEventType = "sla_miss"
 	JobEventTypeFailure JobEventType = "failure"
+	JobEventTypeSuccess JobEventType = "success"
 )
 
 // JobSpec represents a job
 // internal representation of the job
 type JobSpec struct {
-	ID           uuid.UUID
-	Version      int
-	Name         string
-	Description  string
-	Labels       map[string]string
-	Owner        string
-	Schedule     JobSpecSchedule
-	Behavior     JobSpecBehavior
-	Task         JobSpecTask
-	Dependencies map[string]JobSpecDependency // job name to dependency
-	Assets       JobAssets
-	Hooks        []JobSpecHook
+	ID            uuid.UUID
+	Version       int
+	SchemaVersion int
+	Name          string
+	Description   string
+	Labels        map[string]string
+	Owner         string
+	Schedule      JobSpecSchedule
+	Behavior      JobSpecBehavior
+	Task          JobSpecTask
+	Dependencies  map[string]JobSpecDependency // job name to dependency
+	Assets        JobAssets
+	Hooks         []JobSpecHook
+
+	// Extends is the name of a template job of the project the spec
+	// inherits from
+	Extends string
+	// IsTemplate marks specs other jobs can extend, templates are not
+	// deployed to the scheduler
+	IsTemplate bool
+
+	// SLADuration is the time runs of the job should start within once
+	// they are due, runs are not monitored if it is zero
+	SLADuration time.Duration
 }
 
 func (js JobSpec) GetName() string {
@@ -85,6 +110,24 @@ type JobSpecSchedule struct {
 	StartDate time.Time
 	EndDate   *time.Time
 	Interval  string
+
+	// Timezone is the IANA name of the timezone the interval and dates are
+	// in, e.g. Asia/Jakarta. Schedules are in UTC if not set
+	Timezone string
+}
+
+// Location of the timezone of the schedule, fails with ErrInvalidTimezone
+// if it isn't in the IANA timezone database
+func (s JobSpecSchedule) Location() (*time.Location, error) {
+	// the local timezone of the server isn't the one of the scheduler
+	if s.Timezone == "Local" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTimezone, s.Timezone)
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTimezone, s.Timezone)
+	}
+	return loc, nil
 }
 
 type JobSpecBehavior struct {
@@ -107,10 +150,26 @@ type JobSpecNotifier struct {
 }
 
 type JobSpecTask struct {
-	Unit     *Plugin
-	Config   JobSpecConfigs
-	Window   JobSpecTaskWindow
-	Priority int
+	Unit      *Plugin
+	Config    JobSpecConfigs
+	Window    JobSpecTaskWindow
+	Priority  int
+	Resources JobSpecTaskResources
+}
+
+// JobSpecTaskResources are the compute resources the pod of the task
+// requests and is limited to, quantities follow the kubernetes format
+// e.g. 500m cpu or 2Gi memory. Unset quantities are left to the scheduler
+type JobSpecTaskResources struct {
+	CPURequest    string
+	CPULimit      string
+	MemoryRequest string
+	MemoryLimit   string
+}
+
+// IsZero is true if none of the resources are set
+func (r JobSpecTaskResources) IsZero() bool {
+	return r == JobSpecTaskResources{}
 }
 
 // using array to keep order, map would be more performant
@@ -205,6 +264,14 @@ type JobSpecHook struct {
 	Config    JobSpecConfigs
 	Unit      *Plugin
 	DependsOn []*JobSpecHook
+
+	// RunAfter names the hooks of the job this hook runs after, besides
+	// the hooks its plugin depends on
+	RunAfter []string
+
+	// Retry of the hook if it fails, hooks are retried like the job
+	// if the count is not set
+	Retry JobSpecBehaviorRetry
 }
 
 type JobSpecAsset struct {
@@ -291,12 +358,260 @@ type JobSpecDependency struct {
 	Project *ProjectSpec
 	Job     *JobSpec
 	Type    JobSpecDependencyType
+
+	// ProjectName is set for dependencies on jobs of another project
+	ProjectName string
+}
+
+// DependencyPin is the snapshot of an upstream job spec used in place of the
+// latest spec of the upstream while compiling a job
+type DependencyPin struct {
+	JobName        string
+	DependencyName string
+	SpecVersion    string
+	DependencySpec JobSpec
+	CreatedAt      time.Time
+}
+
+const (
+	// DependencySourceDeclared and DependencySourceInferred tell whether a
+	// dependency is listed in the spec or inferred from the assets of the
+	// job by its task
+	DependencySourceDeclared = "declared"
+	DependencySourceInferred = "inferred"
+
+	// reasons a dependency is included in or excluded from a job
+	DependencyReasonExactMatch        = "exact match"
+	DependencyReasonCrossProjectMatch = "cross project match"
+	DependencyReasonDestinationMatch  = "destination match"
+	DependencyReasonNotFound          = "not found"
+)
+
+// ResolutionTrace explains how the dependencies of a job are resolved
+type ResolutionTrace struct {
+	JobName string
+
+	// DeclaredDependencies are named in the spec, dependencies on jobs of
+	// another project are named with their project, e.g. project/job
+	DeclaredDependencies []string
+
+	// InferredDependencies are the destinations the task of the job reads
+	// from, only set if InferenceEnabled
+	InferenceEnabled     bool
+	InferredDependencies []string
+
+	// Steps resolve each declared and inferred dependency to a job
+	Steps []DependencyResolutionStep
+}
+
+// DependencyResolutionStep is the job a declared or inferred dependency is
+// resolved to and why it is included or excluded
+type DependencyResolutionStep struct {
+	Source     string
+	Dependency string
+
+	// JobName and ProjectName are empty if the dependency isn't resolved
+	JobName     string
+	ProjectName string
+	Type        JobSpecDependencyType
+
+	Included bool
+	Reason   string
+}
+
+// DependencyGraph is the graph of jobs and the jobs they depend on, nodes and
+// edges are sorted by the names of their jobs
+type DependencyGraph struct {
+	Nodes []DependencyGraphNode
+	Edges []DependencyGraphEdge
+}
+
+// DependencyGraphNode is a job in the graph, jobs of projects other than the
+// one the graph is built for are cross project
+type DependencyGraphNode struct {
+	ProjectName  string
+	JobName      string
+	CrossProject bool
+}
+
+// DependencyGraphEdge connects a job to a job it depends on
+type DependencyGraphEdge struct {
+	Job        DependencyGraphNode
+	Dependency DependencyGraphNode
+	Type       JobSpecDependencyType
+}
+
+// Release is a named snapshot of the job specs of a project
+type Release struct {
+	Tag         string
+	Description string
+	Jobs        []ReleaseJob
+	CreatedAt   time.Time
+}
+
+// JobSpecWithNamespace is a job spec listed across projects along with the
+// namespace and project it is registered in
+type JobSpecWithNamespace struct {
+	Namespace NamespaceSpec
+	Spec      JobSpec
+}
+
+// ReleaseJob is the spec of a job at the time a release was tagged
+type ReleaseJob struct {
+	Namespace    NamespaceSpec
+	Spec         JobSpec
+	SpecChecksum string
+}
+
+// JobSpecVersion is the spec of a job before one of its changes, the time
+// of the change is CreatedAt
+type JobSpecVersion struct {
+	Version      int
+	Namespace    NamespaceSpec
+	Spec         JobSpec
+	SpecChecksum string
+	CreatedAt    time.Time
+}
+
+// JobSpecVersionCurrent refers to the registered spec of a job in place of
+// one of its archived versions, which are numbered from 1
+const JobSpecVersionCurrent = 0
+
+type JobSpecChangeType string
+
+const (
+	JobSpecChangeAdded    JobSpecChangeType = "added"
+	JobSpecChangeRemoved  JobSpecChangeType = "removed"
+	JobSpecChangeModified JobSpecChangeType = "modified"
+)
+
+// JobSpecChange is a changed field, config key or asset of a job spec with
+// its values in both versions
+type JobSpecChange struct {
+	Name string
+	Type JobSpecChangeType
+	From string
+	To   string
+}
+
+// JobSpecHookDiff is an added, removed or reconfigured hook of a job spec
+type JobSpecHookDiff struct {
+	Name   string
+	Type   JobSpecChangeType
+	Config []JobSpecChange
+}
+
+// JobSpecDiff lists the changes between two versions of a job spec
+type JobSpecDiff struct {
+	Metadata []JobSpecChange
+	Schedule []JobSpecChange
+	Task     []JobSpecChange
+	Config   []JobSpecChange
+	Assets   []JobSpecChange
+	Hooks    []JobSpecHookDiff
+	Behavior []JobSpecChange
+}
+
+// ScheduleChange is a proposed update of a job schedule interval caused
+// by a change in the schedule of one of its upstream jobs
+type ScheduleChange struct {
+	JobName          string
+	UpstreamJobName  string
+	CurrentInterval  string
+	ProposedInterval string
+}
+
+// ScheduleValidation lists the upcoming runs of a schedule interval with
+// warnings about schedules that likely aren't intended
+type ScheduleValidation struct {
+	NextRuns []time.Time
+	Warnings []string
+}
+
+// SuppressedRun is a scheduled run of a job skipped as it falls in a
+// blackout window of its project
+type SuppressedRun struct {
+	ScheduledAt time.Time
+	Window      BlackoutWindow
+
+	// WindowStart is when the occurrence of the window the run falls in
+	// starts
+	WindowStart time.Time
+}
+
+// SchemaField is a column of a table, nested columns are named by their
+// path, e.g. address.city
+type SchemaField struct {
+	Name string
+	Type string
+}
+
+// SchemaTypeChange is a column whose type in the destination table differs
+// from the type produced by the job query
+type SchemaTypeChange struct {
+	Name         string
+	CurrentType  string
+	ExpectedType string
+}
+
+// DatasetRef points at columns of a dataset written or read by jobs
+type DatasetRef struct {
+	Destination string
+	Columns     []string
+}
+
+// CompatibilityWarning is a change of a job that may break a job depending on it
+type CompatibilityWarning struct {
+	Job       string
+	Dependent string
+
+	// Dataset holds the columns of the job destination read by the
+	// dependent that the change affects, empty for behavior changes
+	Dataset DatasetRef
+	Reason  string
+}
+
+// AssetWarning is a problem found in an asset of a job that doesn't stop the
+// job from being deployed
+type AssetWarning struct {
+	Asset  string
+	Table  string
+	Reason string
+}
+
+// SchemaDrift is the difference between the schema of the destination table
+// of a job and the schema its query produces
+type SchemaDrift struct {
+	Destination string
+
+	// columns produced by the query missing in the table
+	AddedColumns []SchemaField
+	// columns of the table no longer produced by the query
+	RemovedColumns []SchemaField
+	TypeChanges    []SchemaTypeChange
+}
+
+// HasDrift is true when the table and query schemas differ
+func (d SchemaDrift) HasDrift() bool {
+	return len(d.AddedColumns) > 0 || len(d.RemovedColumns) > 0 || len(d.TypeChanges) > 0
+}
+
+// CostEstimate is the cost of a single run of the query of a job
+type CostEstimate struct {
+	TotalBytesProcessed int64
+	EstimatedCostUSD    float64
 }
 
 // JobService provides a high-level operations on DAGs
 type JobService interface {
 	// Create constructs a Job and commits it to a storage
 	Create(NamespaceSpec, JobSpec) error
+	// SaveAll creates or updates jobs of a namespace one after another,
+	// templates have to come before the jobs extending them
+	SaveAll(NamespaceSpec, []JobSpec) error
+	// CreateAll commits new jobs of a namespace to a storage at once, none
+	// are saved if any of them is already registered
+	CreateAll(NamespaceSpec, []JobSpec) error
 	// GetByName fetches a Job by name for a specific namespace
 	GetByName(string, NamespaceSpec) (JobSpec, error)
 	// Dump returns the compiled Job
@@ -305,8 +620,20 @@ type JobService interface {
 	KeepOnly(NamespaceSpec, []JobSpec, progress.Observer) error
 	// GetAll reads all job specifications of the given namespace
 	GetAll(NamespaceSpec) ([]JobSpec, error)
+	// GetPage reads at most limit job specifications of the namespace ordered
+	// by name, starting after the job named afterName. Only jobs having all
+	// of labels are read
+	GetPage(namespace NamespaceSpec, afterName string, limit int, labels map[string]string) ([]JobSpec, error)
 	// Delete deletes a job spec from all repos
 	Delete(context.Context, NamespaceSpec, JobSpec) error
+	// Undelete restores a deleted job spec of the namespace and deploys it again
+	Undelete(ctx context.Context, namespace NamespaceSpec, jobName string) error
+	// PurgeDeleted permanently removes job specs of all projects deleted
+	// more than olderThan ago
+	PurgeDeleted(ctx context.Context, olderThan time.Duration) (int, error)
+	// GetSpecChecksums returns the checksums of job specs stored in a
+	// namespace keyed by job name
+	GetSpecChecksums([]string, NamespaceSpec) (map[string]string, error)
 
 	// following methods are executed at a project level, instead of a client
 	// GetByNameForProject fetches a Job by name for a specific project
@@ -317,6 +644,68 @@ type JobService interface {
 	ReplayDryRun(*ReplayWorkerRequest) (*tree.TreeNode, error)
 	// Replay replays the jobSpec and its dependencies between start and endDate
 	Replay(context.Context, *ReplayWorkerRequest) (string, error)
+	// GetReplay returns the replay of the jobSpec with the given id
+	GetReplay(JobSpec, uuid.UUID) (ReplaySpec, error)
+	// BackfillMissingRuns replays the runs of a job between start and end dates that
+	// are missing in the scheduler, nothing is replayed for a dry run
+	BackfillMissingRuns(ctx context.Context, projectSpec ProjectSpec, jobName string, start, end time.Time, dryRun bool) (BackfillPlan, error)
+	// PinDependencyVersion compiles a job against the current spec of its upstream
+	// until it is unpinned, the pin is recorded with the provided spec version
+	PinDependencyVersion(projectSpec ProjectSpec, jobName, dependencyName, specVersion string) error
+	// UnpinDependency compiles a job against the latest spec of its upstream again
+	UnpinDependency(projectSpec ProjectSpec, jobName, dependencyName string) error
+	// ListDependencyPins returns the dependency pins of all jobs in the project
+	ListDependencyPins(projectSpec ProjectSpec) ([]DependencyPin, error)
+	// PropagateScheduleChanges proposes schedule updates for downstream jobs aligned to the given job
+	PropagateScheduleChanges(context.Context, ProjectSpec, string) ([]ScheduleChange, error)
+	// TagRelease snapshots the current job specs of a project under tag
+	TagRelease(projectSpec ProjectSpec, tag, description string, observer progress.Observer) error
+	// RollbackToRelease restores the job specs of a project to the ones tagged
+	// in the release and deploys them, specs created after the release are deleted
+	RollbackToRelease(ctx context.Context, projectSpec ProjectSpec, tag string, observer progress.Observer) (Release, error)
+	// DetectSchemaDrift compares the schema of the destination table of a job
+	// with the schema its query produces
+	DetectSchemaDrift(ctx context.Context, projectSpec ProjectSpec, jobSpec JobSpec) (*SchemaDrift, error)
+	// EstimateCost estimates the cost of a run of the job from the bytes its
+	// query reads
+	EstimateCost(ctx context.Context, projectSpec ProjectSpec, jobSpec JobSpec) (*CostEstimate, error)
+	// ValidateDependencyVersionCompatibility lists changes in the specs that may
+	// break jobs of the project depending on them
+	ValidateDependencyVersionCompatibility(ctx context.Context, namespace NamespaceSpec, specs []JobSpec) ([]CompatibilityWarning, error)
+	// DiffJobSpec compares two versions of a job spec, JobSpecVersionCurrent
+	// refers to the registered spec
+	DiffJobSpec(projectSpec ProjectSpec, jobName string, fromVersion, toVersion int) (JobSpecDiff, error)
+	// GetJobsByOwner lists the jobs of all projects owned by owner
+	GetJobsByOwner(ctx context.Context, owner string) ([]JobSpecWithNamespace, error)
+	// CloneJobSpec copies a job spec to a new undeployed job in the namespace
+	// of the source job
+	CloneJobSpec(projectSpec ProjectSpec, sourceJobName, destJobName string) (JobSpec, NamespaceSpec, error)
+	// CloneProject copies the job specs of the source namespaces to the
+	// destination namespaces of the same names with replacements applied to
+	// their assets and configs, returns the number of jobs created
+	CloneProject(ctx context.Context, sourceNamespaces, destNamespaces []NamespaceSpec, replacements []StringReplacement) (int, error)
+	// ValidateAssetReferences warns of tables read by sql assets of the job
+	// that don't exist, projects opt in through ProjectValidateBQReferences
+	ValidateAssetReferences(ctx context.Context, projectSpec ProjectSpec, jobSpec JobSpec) []AssetWarning
+	// ExplainDependencyResolution lists the dependencies of a job and how
+	// each is resolved
+	ExplainDependencyResolution(ctx context.Context, projectSpec ProjectSpec, jobSpec JobSpec) (*ResolutionTrace, error)
+	// GetDependencyGraph returns the graph of resolved dependencies of the jobs
+	// of a project, or of the root job if set, up to depth levels of
+	// dependencies, all levels if depth isn't positive
+	GetDependencyGraph(ctx context.Context, projectSpec ProjectSpec, rootJobName string, depth int) (DependencyGraph, error)
+}
+
+// StringReplacement replaces all occurrences of Find with Replace
+type StringReplacement struct {
+	Find    string
+	Replace string
+}
+
+// JobSpecHasher computes a checksum identifying the contents of a job spec,
+// specs with equal checksums are identical
+type JobSpecHasher interface {
+	JobSpecChecksum(JobSpec) (string, error)
 }
 
 // JobCompiler takes template file of a scheduler and after applying
@@ -331,6 +720,10 @@ type Job struct {
 	Name        string
 	NamespaceID string
 	Contents    []byte
+
+	// Warnings about the job compiling fine but likely not running as
+	// intended, e.g. runs skipped by daylight saving time changes
+	Warnings []string
 }
 
 type JobEventType string
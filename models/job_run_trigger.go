@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// JobRunTrigger records a run of a job started on demand outside its
+// schedule
+type JobRunTrigger struct {
+	RunID       string
+	ProjectName string
+	JobName     string
+	// LogicalDate is the scheduled time the run is started for, like the
+	// execution date of airflow
+	LogicalDate time.Time
+	// Config is passed to the run by the scheduler
+	Config      map[string]string
+	Principal   string
+	TriggeredAt time.Time
+}
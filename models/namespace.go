@@ -2,6 +2,9 @@ package models
 
 import "github.com/google/uuid"
 
+// DefaultNamespaceName is the namespace of jobs whose requests don't name one
+const DefaultNamespaceName = "default"
+
 // NamespaceSpec represents a namespace which is an individual or a team with an unique name.
 // A Project can have any number of namespaces (with unique names).
 type NamespaceSpec struct {
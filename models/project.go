@@ -4,8 +4,14 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"net"
+	"net/url"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/odpf/optimus/core/cron"
+	"github.com/odpf/optimus/utils"
 	"github.com/pkg/errors"
 
 	"github.com/google/uuid"
@@ -15,8 +21,32 @@ const (
 	ProjectStoragePathKey = "STORAGE_PATH"
 	ProjectSchedulerHost  = "SCHEDULER_HOST"
 
+	// ProjectSyncSecretsToAirflow set to true registers secrets prefixed
+	// with AIRFLOW_CONN_ as airflow connections when bootstrapping
+	ProjectSyncSecretsToAirflow = "SYNC_SECRETS_TO_AIRFLOW"
+
+	// ProjectValidateBQReferences set to true warns of bigquery tables read
+	// by sql assets of jobs that don't exist
+	ProjectValidateBQReferences = "VALIDATE_BQ_REFERENCES"
+
+	// ProjectSlackFailureChannel and ProjectSlackSLAMissChannel override the
+	// channel of the SLACK_WEBHOOK_URL secret for failures and sla misses
+	ProjectSlackFailureChannel = "SLACK_FAILURE_CHANNEL"
+	ProjectSlackSLAMissChannel = "SLACK_SLA_MISS_CHANNEL"
+
+	// ProjectBigQuerySlotCostPerTB is the price in USD of a TB read by
+	// queries of the project, used to estimate the cost of jobs
+	ProjectBigQuerySlotCostPerTB = "BIGQUERY_SLOT_COST_PER_TB"
+
+	// ProjectTemplateEngine names the engine assets of jobs of the project
+	// are rendered with, e.g. jinja, overriding serve.template_engine
+	ProjectTemplateEngine = "TEMPLATE_ENGINE"
+
 	// Secret used for uploading prepared scheduler specifications to cloud
-	// e.g. for gcs it will be base64 encoded service account for the bucket
+	// e.g. for gcs it will be base64 encoded service account for the bucket,
+	// for s3 it will be json encoded access keys, for azure blob storage it
+	// will be the storage account connection string, local file storage
+	// doesn't need credentials and accepts any value
 	ProjectSecretStorageKey = "STORAGE"
 
 	// Secret used to authenticate with scheduler provided at ProjectSchedulerHost
@@ -38,9 +68,17 @@ type ProjectSpec struct {
 
 	// configuration for the registered projects
 	// - ProjectStoragePathKey: specification store for scheduler inputs
-	// suggested are gcs/s3 or similar object store
+	// suggested are gcs/s3/azure blob storage or similar object store, a local
+	// directory can be used for development
 	// - ProjectSchedulerHost: host url to connect with the scheduler used by
 	// the tenant
+	// - ProjectSyncSecretsToAirflow: register AIRFLOW_CONN_ secrets as
+	// airflow connections when bootstrapping
+	// - ProjectValidateBQReferences: warn of missing tables read by sql
+	// assets of jobs
+	// - ProjectBigQuerySlotCostPerTB: price of a TB read by queries
+	// - ProjectSlackFailureChannel, ProjectSlackSLAMissChannel: slack
+	// channels notified of failures and sla misses
 	Config map[string]string
 
 	// Secret contains key value pair for project level credentials and gets
@@ -51,6 +89,163 @@ type ProjectSpec struct {
 	// Name: <plugintype>_<plugin_name>, Value: <base64encodedstring>
 	// For example: TASK_BQ2BQ: secret_as_base64
 	Secret ProjectSecrets
+
+	// FeatureFlags switch parts of compiled jobs without changing their
+	// specs, flags not set are disabled
+	FeatureFlags map[string]bool
+
+	// Variables are shared by the jobs of the project, they are registered
+	// separately from the project
+	Variables ProjectVariables
+
+	// Webhooks are called back on lifecycle events of the jobs of the project
+	Webhooks []WebhookConfig
+
+	// BlackoutWindows are periods scheduled runs of the jobs of the project
+	// are skipped in, e.g. during maintenance of a database
+	BlackoutWindows []BlackoutWindow
+
+	// Quota limits the jobs of the project, it is set separately from the
+	// project by admins
+	Quota ProjectQuota
+}
+
+// ErrQuotaExceeded is returned for jobs that can't be registered within the
+// quota of their project
+var ErrQuotaExceeded = errors.New("project quota exceeded")
+
+// ProjectQuota limits the jobs a project can register. Limits not set are
+// zero and fall back to the default quota, negative limits are explicitly
+// unlimited, e.g. QuotaUnlimited
+type ProjectQuota struct {
+	MaxJobCount int `json:"max_job_count,omitempty"`
+	// MinScheduleInterval is the shortest time allowed between two runs of
+	// a job
+	MinScheduleInterval time.Duration `json:"min_schedule_interval,omitempty"`
+}
+
+// QuotaUnlimited is a limit of a ProjectQuota not limiting the project
+const QuotaUnlimited = -1
+
+// Or replaces the limits not set in the quota with the ones of defaults,
+// unlimited ones are kept
+func (q ProjectQuota) Or(defaults ProjectQuota) ProjectQuota {
+	if q.MaxJobCount == 0 {
+		q.MaxJobCount = defaults.MaxJobCount
+	}
+	if q.MinScheduleInterval == 0 {
+		q.MinScheduleInterval = defaults.MinScheduleInterval
+	}
+	return q
+}
+
+const (
+	WebhookEventJobDeployed = "job_deployed"
+	WebhookEventJobFailure  = "job_failure"
+	WebhookEventJobSuccess  = "job_success"
+)
+
+// WebhookConfig is an endpoint receiving a json payload over http POST for
+// every event of a job it subscribes to
+type WebhookConfig struct {
+	URL string `json:"url"`
+
+	// SecretName is the project secret payloads are signed with using
+	// HMAC-SHA256, payloads aren't sent if the secret isn't registered
+	SecretName string `json:"secret_name"`
+
+	// Events the webhook is called for, all events if empty
+	Events []string `json:"events,omitempty"`
+}
+
+// Validate fails for urls that aren't http(s), urls of loopback, link local
+// and private hosts, and unknown events. Names resolving to such hosts are
+// refused when webhooks are sent
+func (w WebhookConfig) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Errorf("invalid webhook url %q", w.URL)
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errors.Errorf("webhook url %q should not be a local host", w.URL)
+	}
+	if ip := net.ParseIP(host); ip != nil && !utils.IsPublicIP(ip) {
+		return errors.Errorf("webhook url %q should not be an internal address", w.URL)
+	}
+	for _, event := range w.Events {
+		switch event {
+		case WebhookEventJobDeployed, WebhookEventJobFailure, WebhookEventJobSuccess:
+		default:
+			return errors.Errorf("unknown webhook event %q, should be one of %s, %s, %s", event,
+				WebhookEventJobDeployed, WebhookEventJobFailure, WebhookEventJobSuccess)
+		}
+	}
+	return nil
+}
+
+// Subscribes is true if the webhook should be called for the event
+func (w WebhookConfig) Subscribes(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// BlackoutWindow is a recurring period scheduled runs of jobs are skipped
+// in, runs starting at or after the start of the window and before its end
+// are skipped
+type BlackoutWindow struct {
+	// Start is a cron expression of when the window starts in UTC, e.g.
+	// 0 2 * * 0 for every Sunday at 02:00
+	Start string `json:"start"`
+
+	Duration time.Duration `json:"duration"`
+
+	// JobPattern is a regular expression of the names of the jobs skipped,
+	// all jobs of the project if empty
+	JobPattern string `json:"job_pattern,omitempty"`
+}
+
+// Validate fails for starts that aren't cron expressions of the wall clock,
+// windows without a duration and invalid job patterns
+func (w BlackoutWindow) Validate() error {
+	if strings.HasPrefix(w.Start, "@every") {
+		return errors.Errorf("blackout window start %q should be a cron expression of the wall clock", w.Start)
+	}
+	if _, err := cron.ParseCronSchedule(w.Start); err != nil {
+		return errors.Wrapf(err, "invalid blackout window start %q", w.Start)
+	}
+	if w.Duration <= 0 {
+		return errors.Errorf("duration of blackout window %q should be positive", w.Start)
+	}
+	if _, err := regexp.Compile(w.JobPattern); err != nil {
+		return errors.Wrapf(err, "invalid job pattern %q of blackout window %q", w.JobPattern, w.Start)
+	}
+	return nil
+}
+
+// AppliesTo is true if runs of the job are skipped in the window
+func (w BlackoutWindow) AppliesTo(jobName string) bool {
+	if w.JobPattern == "" {
+		return true
+	}
+	matched, err := regexp.MatchString(w.JobPattern, jobName)
+	return err == nil && matched
+}
+
+// ProjectVariables are key value pairs available in the templates of job
+// assets, values set in the config of a job take precedence over them
+type ProjectVariables map[string]string
+
+// IsFeatureEnabled is true if the feature flag is enabled for the project
+func (s ProjectSpec) IsFeatureEnabled(name string) bool {
+	return s.FeatureFlags[name]
 }
 
 func (s ProjectSpec) String() string {
@@ -77,10 +272,31 @@ type ProjectSecretItem struct {
 
 	Name  string
 	Value string
+
+	// ExpiresAt is the time after which the secret can't be read, secrets
+	// without it don't expire
+	ExpiresAt *time.Time
+}
+
+// ExpiringProjectSecret is a secret of a project close to its expiry
+type ExpiringProjectSecret struct {
+	ProjectName string
+	SecretName  string
+	ExpiresAt   time.Time
+}
+
+// ProjectSecretVersion is a value a secret was saved with
+type ProjectSecretVersion struct {
+	Name      string
+	Version   int
+	CreatedAt time.Time
 }
 
 type ApplicationKey struct {
 	key *[32]byte
+
+	// previous key values are still decrypted with while they are rotated
+	previous *[32]byte
 }
 
 func NewApplicationSecret(k string) (ApplicationKey, error) {
@@ -97,3 +313,65 @@ func NewApplicationSecret(k string) (ApplicationKey, error) {
 func (s *ApplicationKey) GetKey() *[32]byte {
 	return s.key
 }
+
+// WithPrevious keeps values encrypted with prev readable during the rotation
+// of secrets to s, values are always encrypted with s
+func (s ApplicationKey) WithPrevious(prev ApplicationKey) ApplicationKey {
+	s.previous = prev.key
+	return s
+}
+
+// GetPreviousKey is nil unless secrets are being rotated
+func (s *ApplicationKey) GetPreviousKey() *[32]byte {
+	return s.previous
+}
+
+const (
+	ProjectHealthCheckDatabase     = "database"
+	ProjectHealthCheckStorage      = "storage"
+	ProjectHealthCheckScheduler    = "scheduler"
+	ProjectHealthCheckDependencies = "dependencies"
+
+	HealthCheckStatusOK      HealthCheckStatus = "ok"
+	HealthCheckStatusFailed  HealthCheckStatus = "failed"
+	HealthCheckStatusSkipped HealthCheckStatus = "skipped"
+)
+
+type HealthCheckStatus string
+
+func (s HealthCheckStatus) String() string {
+	return string(s)
+}
+
+// HealthCheck is the result of probing a single subsystem used by a project
+type HealthCheck struct {
+	Name    string
+	Status  HealthCheckStatus
+	Message string
+	Latency time.Duration
+}
+
+// ProjectHealth contains diagnostics of the subsystems a project depends on
+type ProjectHealth struct {
+	Checks []HealthCheck
+
+	// UnresolvableDependencies is the number of jobs whose dependencies
+	// can't be resolved
+	UnresolvableDependencies int
+
+	// LastDeployedAt is the last time a job spec of the project was deployed,
+	// zero if none were deployed
+	LastDeployedAt time.Time
+
+	CheckedAt time.Time
+}
+
+// Healthy is true if none of the checks failed
+func (h ProjectHealth) Healthy() bool {
+	for _, check := range h.Checks {
+		if check.Status == HealthCheckStatusFailed {
+			return false
+		}
+	}
+	return true
+}
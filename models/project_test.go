@@ -3,6 +3,7 @@ package models_test
 import (
 	"encoding/base64"
 	"testing"
+	"time"
 
 	"github.com/gtank/cryptopasta"
 	"github.com/stretchr/testify/assert"
@@ -102,4 +103,53 @@ func TestProjectSpec(t *testing.T) {
 			assert.Equal(t, rawSecret, string(value))
 		})
 	})
+	t.Run("BlackoutWindow", func(t *testing.T) {
+		t.Run("should validate windows starting at a cron expression", func(t *testing.T) {
+			window := models.BlackoutWindow{Start: "0 2 * * 0", Duration: time.Hour * 2, JobPattern: "^bq-"}
+			assert.Nil(t, window.Validate())
+		})
+		t.Run("should fail for invalid windows", func(t *testing.T) {
+			tests := map[string]models.BlackoutWindow{
+				"unparsable start":     {Start: "every sunday", Duration: time.Hour},
+				"start without a time": {Start: "@every 1h", Duration: time.Hour},
+				"no duration":          {Start: "0 2 * * 0"},
+				"invalid job pattern":  {Start: "0 2 * * 0", Duration: time.Hour, JobPattern: "bq-("},
+			}
+			for name, window := range tests {
+				assert.NotNil(t, window.Validate(), name)
+			}
+		})
+		t.Run("should apply to jobs matching the job pattern", func(t *testing.T) {
+			window := models.BlackoutWindow{Start: "0 2 * * 0", Duration: time.Hour, JobPattern: "^bq-"}
+			assert.True(t, window.AppliesTo("bq-orders"))
+			assert.False(t, window.AppliesTo("gcs-orders"))
+			assert.True(t, models.BlackoutWindow{Start: "0 2 * * 0", Duration: time.Hour}.AppliesTo("gcs-orders"))
+		})
+	})
+	t.Run("WebhookConfig", func(t *testing.T) {
+		t.Run("should validate public http urls", func(t *testing.T) {
+			for _, webhookURL := range []string{"https://hooks.example.io/optimus", "http://8.8.8.8:8080/hook"} {
+				webhook := models.WebhookConfig{URL: webhookURL, Events: []string{models.WebhookEventJobFailure}}
+				assert.Nil(t, webhook.Validate(), webhookURL)
+			}
+		})
+		t.Run("should fail for urls of internal hosts", func(t *testing.T) {
+			for _, webhookURL := range []string{
+				"http://localhost:8080/hook",
+				"http://api.localhost/hook",
+				"http://127.0.0.1/hook",
+				"http://169.254.169.254/latest/meta-data",
+				"http://10.0.0.12/hook",
+				"http://192.168.1.1/hook",
+				"http://[::1]/hook",
+				"http://[fe80::1]/hook",
+			} {
+				assert.NotNil(t, models.WebhookConfig{URL: webhookURL}.Validate(), webhookURL)
+			}
+		})
+		t.Run("should fail for other schemes and unknown events", func(t *testing.T) {
+			assert.NotNil(t, models.WebhookConfig{URL: "file:///etc/passwd"}.Validate())
+			assert.NotNil(t, models.WebhookConfig{URL: "https://hooks.example.io", Events: []string{"job_started"}}.Validate())
+		})
+	})
 }
@@ -40,3 +40,33 @@ type ReplaySpec struct {
 	Message   ReplayMessage
 	CreatedAt time.Time
 }
+
+// BackfillSkippedRun is a missing run of a job left out of a backfill
+type BackfillSkippedRun struct {
+	ScheduledAt time.Time
+	Reason      string
+}
+
+// JobPausePeriod is a time the scheduled runs of a job were paused, ResumedAt
+// is nil while the job is still paused
+type JobPausePeriod struct {
+	PausedAt  time.Time
+	ResumedAt *time.Time
+}
+
+// Covers tells if runs scheduled at t were paused
+func (p JobPausePeriod) Covers(t time.Time) bool {
+	return !t.Before(p.PausedAt) && (p.ResumedAt == nil || t.Before(*p.ResumedAt))
+}
+
+// BackfillPlan lists the runs of a job missing in the scheduler between the
+// requested dates, missing runs are replayed unless the plan is a dry run
+type BackfillPlan struct {
+	Job         JobSpec
+	MissingRuns []time.Time
+	SkippedRuns []BackfillSkippedRun
+	DryRun      bool
+
+	// ReplayIDs are the ids of the replays triggered for the missing runs
+	ReplayIDs []string
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Role is the level of access of a caller, each role is allowed everything
+// the roles before it are
+type Role int
+
+const (
+	// RoleNone is held by callers that aren't authenticated
+	RoleNone Role = iota
+	// RoleViewer can read specifications and state of projects
+	RoleViewer
+	// RoleEditor can also change specifications, secrets and runs of projects
+	RoleEditor
+	// RoleAdmin can also run operations across projects
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{
+	RoleNone:   "NONE",
+	RoleViewer: "VIEWER",
+	RoleEditor: "EDITOR",
+	RoleAdmin:  "ADMIN",
+}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+// ProjectRoleBinding grants a role in a project to the subject of tokens
+type ProjectRoleBinding struct {
+	Subject string
+	Role    Role
+}
+
+// ParseRole returns the role of name, names are case insensitive
+func ParseRole(name string) (Role, error) {
+	for role, roleName := range roleNames {
+		if strings.EqualFold(name, roleName) {
+			return role, nil
+		}
+	}
+	return RoleNone, errors.Errorf("unknown role %s", name)
+}
@@ -0,0 +1,25 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole(t *testing.T) {
+	t.Run("should parse role names", func(t *testing.T) {
+		role, err := models.ParseRole("editor")
+		assert.Nil(t, err)
+		assert.Equal(t, models.RoleEditor, role)
+		assert.Equal(t, "EDITOR", role.String())
+
+		_, err = models.ParseRole("OWNER")
+		assert.NotNil(t, err)
+	})
+	t.Run("should order roles by access", func(t *testing.T) {
+		assert.True(t, models.RoleNone < models.RoleViewer)
+		assert.True(t, models.RoleViewer < models.RoleEditor)
+		assert.True(t, models.RoleEditor < models.RoleAdmin)
+	})
+}
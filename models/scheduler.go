@@ -3,6 +3,8 @@ package models
 import (
 	"context"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 var (
@@ -14,6 +16,12 @@ var (
 	JobStatusStateSuccess JobStatusState = "success"
 	JobStatusStateFailed  JobStatusState = "failed"
 	JobStatusStateRunning JobStatusState = "running"
+
+	ErrUnsupportedSchedulerOperation = errors.New("operation not supported by the scheduler")
+
+	// ErrJobRunExists is returned for runs triggered at a logical date the
+	// job already has a run at
+	ErrJobRunExists = errors.New("job run already exists")
 )
 
 // SchedulerUnit is implemented by supported schedulers
@@ -45,6 +53,27 @@ type SchedulerUnit interface {
 	// GetDagRunStatus should return batch of runs of a job
 	GetDagRunStatus(ctx context.Context, projSpec ProjectSpec, jobName string, startDate time.Time, endDate time.Time,
 		batchSize int) ([]JobStatus, error)
+
+	// GetRunningJobs returns the runs of jobs in the project currently being
+	// executed by the scheduler
+	GetRunningJobs(ctx context.Context, projSpec ProjectSpec) ([]RunningJob, error)
+
+	// GetSchedulerVersion returns the version of the scheduler the project
+	// deploys its jobs to, used to check compatibility of compiled jobs
+	GetSchedulerVersion(ctx context.Context, projSpec ProjectSpec) (string, error)
+
+	// ListDeployedJobs returns names of the jobs of the project deployed to
+	// the scheduler by optimus, unrelated jobs of the scheduler are left out
+	ListDeployedJobs(ctx context.Context, projSpec ProjectSpec) ([]string, error)
+
+	// TriggerJobRun starts a run of the job at the logical date outside its
+	// schedule, conf is passed to the run. It returns the id of the run
+	TriggerJobRun(ctx context.Context, projSpec ProjectSpec, jobName string, logicalDate time.Time,
+		conf map[string]string) (string, error)
+
+	// SetJobPaused pauses or resumes the scheduled runs of the job, runs
+	// already started are left running
+	SetJobPaused(ctx context.Context, projSpec ProjectSpec, jobName string, paused bool) error
 }
 
 type JobStatusState string
@@ -56,4 +85,15 @@ func (j JobStatusState) String() string {
 type JobStatus struct {
 	ScheduledAt time.Time
 	State       JobStatusState
+	// StartTime is when the scheduler started the run, zero if it hasn't
+	// started or the scheduler doesn't report it
+	StartTime time.Time
+}
+
+// RunningJob is a run of a job currently being executed by the scheduler
+type RunningJob struct {
+	JobName       string
+	DAGRunID      string
+	StartTime     time.Time
+	ExecutorState string
 }
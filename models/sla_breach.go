@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// SLABreach records a run of a job that hadn't started within the
+// SLADuration of the job once it was due
+type SLABreach struct {
+	ProjectName   string
+	NamespaceName string
+	JobName       string
+	// ScheduledAt is the scheduled time of the run, like the execution date
+	// of airflow
+	ScheduledAt time.Time
+	// DueAt is when the scheduler should have started the run
+	DueAt       time.Time
+	SLADuration time.Duration
+	// StartedAt is when the run started, zero if it hadn't started yet when
+	// the breach was detected
+	StartedAt  time.Time
+	DetectedAt time.Time
+}
+
+// SLABreachFilter selects breaches of a project, empty fields and zero
+// times match all breaches of the project
+type SLABreachFilter struct {
+	JobName string
+	Since   time.Time
+	Limit   int
+}
@@ -0,0 +1,166 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/pkg/errors"
+)
+
+const (
+	// UploadBufferSize is the size of the blocks blobs are uploaded in, blobs
+	// larger than a single block are uploaded as multiple blocks
+	UploadBufferSize = 4 * 1024 * 1024
+	// UploadMaxBuffers is the number of blocks uploaded concurrently
+	UploadMaxBuffers = 4
+
+	defaultEndpointSuffix = "core.windows.net"
+)
+
+// NewServiceURL creates the url of the blob service of a storage account
+// authenticated with its connection string. Connection strings with an
+// account key or a shared access signature are supported.
+func NewServiceURL(connectionString string) (azblob.ServiceURL, error) {
+	settings := map[string]string{}
+	for _, part := range strings.Split(connectionString, ";") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return azblob.ServiceURL{}, errors.New("invalid azure storage connection string")
+		}
+		settings[kv[0]] = kv[1]
+	}
+
+	endpoint := settings["BlobEndpoint"]
+	if endpoint == "" {
+		if settings["AccountName"] == "" {
+			return azblob.ServiceURL{}, errors.New("azure storage connection string requires AccountName or BlobEndpoint")
+		}
+		protocol := settings["DefaultEndpointsProtocol"]
+		if protocol == "" {
+			protocol = "https"
+		}
+		suffix := settings["EndpointSuffix"]
+		if suffix == "" {
+			suffix = defaultEndpointSuffix
+		}
+		endpoint = fmt.Sprintf("%s://%s.blob.%s", protocol, settings["AccountName"], suffix)
+	}
+	serviceURL, err := url.Parse(endpoint)
+	if err != nil {
+		return azblob.ServiceURL{}, errors.Wrap(err, "invalid azure blob endpoint")
+	}
+
+	var credential azblob.Credential
+	switch {
+	case settings["AccountKey"] != "":
+		credential, err = azblob.NewSharedKeyCredential(settings["AccountName"], settings["AccountKey"])
+		if err != nil {
+			return azblob.ServiceURL{}, errors.Wrap(err, "invalid azure storage account key")
+		}
+	case settings["SharedAccessSignature"] != "":
+		credential = azblob.NewAnonymousCredential()
+		serviceURL.RawQuery = settings["SharedAccessSignature"]
+	default:
+		return azblob.ServiceURL{}, errors.New("azure storage connection string requires AccountKey or SharedAccessSignature")
+	}
+	return azblob.NewServiceURL(*serviceURL, azblob.NewPipeline(credential, azblob.PipelineOptions{})), nil
+}
+
+// ParseURL returns the container and the blob prefix of az://container/prefix
+// and abfs://container@account.dfs.core.windows.net/prefix urls
+func ParseURL(storageURL *url.URL) (container, prefix string, err error) {
+	switch storageURL.Scheme {
+	case "az":
+		container = storageURL.Hostname()
+	case "abfs":
+		container = storageURL.User.Username()
+	default:
+		return "", "", errors.Errorf("invalid scheme %s, expected az or abfs", storageURL.Scheme)
+	}
+	if container == "" {
+		return "", "", errors.Errorf("container missing in %s", storageURL.String())
+	}
+	return container, strings.Trim(storageURL.Path, "/"), nil
+}
+
+type AzureBlobObjectWriter struct {
+	ServiceURL azblob.ServiceURL
+
+	// Container overrides the bucket objects are written to, abfs urls carry
+	// the container as user info so their host is the storage account
+	Container string
+}
+
+// NewWriter streams the blob to the container, the upload is complete once
+// the writer is closed
+func (az *AzureBlobObjectWriter) NewWriter(ctx context.Context, bucket, path string) (io.WriteCloser, error) {
+	if az.Container != "" {
+		bucket = az.Container
+	}
+	containerURL := az.ServiceURL.NewContainerURL(bucket)
+	if _, err := containerURL.GetProperties(ctx, azblob.LeaseAccessConditions{}); err != nil {
+		return nil, errors.Wrapf(err, "failed to access container %s", bucket)
+	}
+
+	pr, pw := io.Pipe()
+	w := &uploadWriter{
+		pw:        pw,
+		done:      make(chan error, 1),
+		container: bucket,
+		blob:      strings.TrimPrefix(path, "/"),
+	}
+	blobURL := containerURL.NewBlockBlobURL(w.blob)
+	go func() {
+		_, err := azblob.UploadStreamToBlockBlob(ctx, pr, blobURL, azblob.UploadStreamToBlockBlobOptions{
+			BufferSize: UploadBufferSize,
+			MaxBuffers: UploadMaxBuffers,
+		})
+		// unblock pending writes if upload stopped before reading everything
+		pr.CloseWithError(err)
+		w.done <- err
+	}()
+	return w, nil
+}
+
+// uploadWriter pipes the written bytes to a running upload
+type uploadWriter struct {
+	pw        *io.PipeWriter
+	done      chan error
+	container string
+	blob      string
+}
+
+func (w *uploadWriter) Write(p []byte) (int, error) {
+	return w.pw.Write(p)
+}
+
+func (w *uploadWriter) Close() error {
+	if err := w.pw.Close(); err != nil {
+		return err
+	}
+	if err := <-w.done; err != nil {
+		return errors.Wrapf(err, "failed to upload %s/%s", w.container, w.blob)
+	}
+	return nil
+}
+
+type azureBlobObjectReader struct {
+	serviceURL azblob.ServiceURL
+}
+
+func (az *azureBlobObjectReader) NewReader(bucket, path string) (io.ReadCloser, error) {
+	blobURL := az.serviceURL.NewContainerURL(bucket).NewBlobURL(strings.TrimPrefix(path, "/"))
+	resp, err := blobURL.Download(context.Background(), 0, azblob.CountToEnd, azblob.BlobAccessConditions{},
+		false, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s/%s", bucket, path)
+	}
+	return resp.Body(azblob.RetryReaderOptions{}), nil
+}
@@ -0,0 +1,245 @@
+package azure_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/odpf/optimus/store/azure"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryBlobService is an in memory azure blob service serving a single
+// container over the blob rest api
+type memoryBlobService struct {
+	mu        sync.Mutex
+	container string
+	blobs     map[string][]byte
+	blocks    map[string][]byte
+	// stagedBlocks counts blocks uploaded before being committed to blobs
+	stagedBlocks int
+	// pageSize limits the number of blobs returned per list call
+	pageSize int
+}
+
+func newMemoryBlobService(t *testing.T, container string) (*memoryBlobService, azblob.ServiceURL) {
+	svc := &memoryBlobService{
+		container: container,
+		blobs:     map[string][]byte{},
+		blocks:    map[string][]byte{},
+		pageSize:  1000,
+	}
+	srv := httptest.NewServer(svc)
+	t.Cleanup(srv.Close)
+
+	serviceURL, err := azure.NewServiceURL(fmt.Sprintf("BlobEndpoint=%s;SharedAccessSignature=sv=2020-02-10&sig=test", srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc, serviceURL
+}
+
+func (svc *memoryBlobService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	if parts[0] != svc.container {
+		writeStorageError(w, http.StatusNotFound, azblob.ServiceCodeContainerNotFound)
+		return
+	}
+	query := r.URL.Query()
+	if len(parts) == 1 {
+		switch {
+		case query.Get("comp") == "list":
+			svc.list(w, query)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+		return
+	}
+
+	blob := parts[1]
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := ioutil.ReadAll(r.Body)
+		switch query.Get("comp") {
+		case "block":
+			svc.blocks[blob+query.Get("blockid")] = body
+			svc.stagedBlocks++
+		case "blocklist":
+			var blockList struct {
+				Latest []string `xml:"Latest"`
+			}
+			if err := xml.Unmarshal(body, &blockList); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			var contents []byte
+			for _, id := range blockList.Latest {
+				contents = append(contents, svc.blocks[blob+id]...)
+			}
+			svc.blobs[blob] = contents
+		default:
+			svc.blobs[blob] = body
+		}
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet, http.MethodHead:
+		contents, ok := svc.blobs[blob]
+		if !ok {
+			writeStorageError(w, http.StatusNotFound, azblob.ServiceCodeBlobNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(contents)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(contents)
+	case http.MethodDelete:
+		if _, ok := svc.blobs[blob]; !ok {
+			writeStorageError(w, http.StatusNotFound, azblob.ServiceCodeBlobNotFound)
+			return
+		}
+		delete(svc.blobs, blob)
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func (svc *memoryBlobService) list(w http.ResponseWriter, query url.Values) {
+	var names []string
+	for name := range svc.blobs {
+		if strings.HasPrefix(name, query.Get("prefix")) && name > query.Get("marker") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var nextMarker string
+	if len(names) > svc.pageSize {
+		names = names[:svc.pageSize]
+		nextMarker = names[len(names)-1]
+	}
+	var blobs strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&blobs, "<Blob><Name>%s</Name><Properties></Properties></Blob>", name)
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="utf-8"?><EnumerationResults ContainerName="%s"><Blobs>%s</Blobs><NextMarker>%s</NextMarker></EnumerationResults>`,
+		svc.container, blobs.String(), nextMarker)
+}
+
+func writeStorageError(w http.ResponseWriter, status int, code azblob.ServiceCodeType) {
+	w.Header().Set("x-ms-error-code", string(code))
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="utf-8"?><Error><Code>%s</Code><Message>not found</Message></Error>`, code)
+}
+
+func TestAzureBlobObjectWriter(t *testing.T) {
+	ctx := context.Background()
+	t.Run("should upload small objects in a single block", func(t *testing.T) {
+		svc, serviceURL := newMemoryBlobService(t, "dags")
+		writer := &azure.AzureBlobObjectWriter{ServiceURL: serviceURL}
+
+		w, err := writer.NewWriter(ctx, "dags", "/jobs/ns/hello.py")
+		assert.Nil(t, err)
+		_, err = io.Copy(w, strings.NewReader("print('hello')"))
+		assert.Nil(t, err)
+		assert.Nil(t, w.Close())
+
+		assert.Equal(t, "print('hello')", string(svc.blobs["jobs/ns/hello.py"]))
+		assert.Equal(t, 1, svc.stagedBlocks)
+	})
+	t.Run("should upload objects larger than a buffer in multiple blocks", func(t *testing.T) {
+		svc, serviceURL := newMemoryBlobService(t, "dags")
+		writer := &azure.AzureBlobObjectWriter{ServiceURL: serviceURL}
+		contents := bytes.Repeat([]byte("a"), azure.UploadBufferSize*2+10)
+
+		w, err := writer.NewWriter(ctx, "dags", "jobs/ns/large.py")
+		assert.Nil(t, err)
+		_, err = io.Copy(w, bytes.NewReader(contents))
+		assert.Nil(t, err)
+		assert.Nil(t, w.Close())
+
+		assert.Equal(t, contents, svc.blobs["jobs/ns/large.py"])
+		assert.Equal(t, 3, svc.stagedBlocks)
+	})
+	t.Run("should write to configured container regardless of bucket", func(t *testing.T) {
+		svc, serviceURL := newMemoryBlobService(t, "dags")
+		writer := &azure.AzureBlobObjectWriter{ServiceURL: serviceURL, Container: "dags"}
+
+		w, err := writer.NewWriter(ctx, "account.dfs.core.windows.net", "jobs/ns/hello.py")
+		assert.Nil(t, err)
+		_, err = io.Copy(w, strings.NewReader("print('hello')"))
+		assert.Nil(t, err)
+		assert.Nil(t, w.Close())
+
+		assert.Equal(t, "print('hello')", string(svc.blobs["jobs/ns/hello.py"]))
+	})
+	t.Run("should fail if container is not accessible", func(t *testing.T) {
+		_, serviceURL := newMemoryBlobService(t, "dags")
+		writer := &azure.AzureBlobObjectWriter{ServiceURL: serviceURL}
+
+		_, err := writer.NewWriter(ctx, "unknown", "jobs/ns/hello.py")
+		var storageErr azblob.StorageError
+		assert.True(t, errors.As(err, &storageErr))
+		assert.Equal(t, azblob.ServiceCodeContainerNotFound, storageErr.ServiceCode())
+		assert.Contains(t, err.Error(), "failed to access container unknown")
+	})
+}
+
+func TestParseURL(t *testing.T) {
+	t.Run("should return container and prefix of az url", func(t *testing.T) {
+		u, _ := url.Parse("az://dags/optimus/project/")
+		container, prefix, err := azure.ParseURL(u)
+		assert.Nil(t, err)
+		assert.Equal(t, "dags", container)
+		assert.Equal(t, "optimus/project", prefix)
+	})
+	t.Run("should return container and prefix of abfs url", func(t *testing.T) {
+		u, _ := url.Parse("abfs://dags@account.dfs.core.windows.net/optimus/project")
+		container, prefix, err := azure.ParseURL(u)
+		assert.Nil(t, err)
+		assert.Equal(t, "dags", container)
+		assert.Equal(t, "optimus/project", prefix)
+	})
+	t.Run("should fail if container is missing", func(t *testing.T) {
+		u, _ := url.Parse("abfs://account.dfs.core.windows.net/optimus")
+		_, _, err := azure.ParseURL(u)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestNewServiceURL(t *testing.T) {
+	t.Run("should build blob endpoint from account name", func(t *testing.T) {
+		serviceURL, err := azure.NewServiceURL("DefaultEndpointsProtocol=https;AccountName=optimus;AccountKey=a2V5;EndpointSuffix=core.windows.net")
+		assert.Nil(t, err)
+		u := serviceURL.URL()
+		assert.Equal(t, "https://optimus.blob.core.windows.net", u.String())
+	})
+	t.Run("should use blob endpoint with shared access signature", func(t *testing.T) {
+		serviceURL, err := azure.NewServiceURL("BlobEndpoint=http://127.0.0.1:10000/devstoreaccount1;SharedAccessSignature=sv=2020-02-10&sig=abc")
+		assert.Nil(t, err)
+		u := serviceURL.URL()
+		assert.Equal(t, "http://127.0.0.1:10000/devstoreaccount1?sv=2020-02-10&sig=abc", u.String())
+	})
+	t.Run("should fail if credentials are missing", func(t *testing.T) {
+		_, err := azure.NewServiceURL("AccountName=optimus")
+		assert.NotNil(t, err)
+	})
+	t.Run("should fail if connection string is malformed", func(t *testing.T) {
+		_, err := azure.NewServiceURL("not-a-connection-string")
+		assert.NotNil(t, err)
+	})
+}
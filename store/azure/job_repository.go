@@ -0,0 +1,183 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+var (
+	errEmptyJobName = errors.New("job name cannot be an empty string")
+)
+
+type JobRepository struct {
+	ObjectReader store.ObjectReader
+	ObjectWriter store.ObjectWriter
+	ServiceURL   azblob.ServiceURL
+	Container    string
+	Prefix       string
+	Suffix       string
+}
+
+func (repo *JobRepository) Save(ctx context.Context, j models.Job) (err error) {
+	dst, err := repo.ObjectWriter.NewWriter(ctx, repo.Container, repo.pathFor(j))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if derr := dst.Close(); derr != nil {
+			if err == nil {
+				err = derr
+			} else {
+				err = errors.Wrap(err, derr.Error())
+			}
+		}
+	}()
+	src := bytes.NewBuffer(j.Contents)
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+func (repo *JobRepository) Delete(ctx context.Context, namespace models.NamespaceSpec, jobName string) error {
+	if strings.TrimSpace(jobName) == "" {
+		return errEmptyJobName
+	}
+
+	filePath := fmt.Sprintf("%s%s", path.Join(repo.Prefix, namespace.ID.String(), jobName), repo.Suffix)
+	blobURL := repo.containerURL().NewBlobURL(filePath)
+	if _, err := blobURL.Delete(ctx, azblob.DeleteSnapshotsOptionInclude, azblob.BlobAccessConditions{}); err != nil {
+		if isBlobNotFound(err) {
+			return errors.Wrap(models.ErrNoSuchJob, jobName)
+		}
+		return errors.Wrapf(err, "failed to delete %s", jobName)
+	}
+	return nil
+}
+
+func (repo *JobRepository) GetAll(ctx context.Context) ([]models.Job, error) {
+	blobNames, err := repo.listBlobs(ctx, repo.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []models.Job
+	for _, blobName := range blobNames {
+		contents, err := repo.read(blobName)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, models.Job{
+			Name:     repo.jobNameFromPath(blobName),
+			Contents: contents,
+		})
+	}
+	return jobs, nil
+}
+
+func (repo *JobRepository) ListNames(ctx context.Context, namespace models.NamespaceSpec) ([]string, error) {
+	blobNames, err := repo.listBlobs(ctx, path.Join(repo.Prefix, namespace.ID.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	var jobNames []string
+	for _, blobName := range blobNames {
+		jobNames = append(jobNames, repo.jobNameFromPath(blobName))
+	}
+	return jobNames, nil
+}
+
+func (repo *JobRepository) GetByName(ctx context.Context, jobName string) (models.Job, error) {
+	if strings.TrimSpace(jobName) == "" {
+		return models.Job{}, errEmptyJobName
+	}
+
+	filePath := fmt.Sprintf("%s%s", path.Join(repo.Prefix, jobName), repo.Suffix)
+	contents, err := repo.read(filePath)
+	if err != nil {
+		if isBlobNotFound(err) {
+			return models.Job{}, errors.Wrap(models.ErrNoSuchJob, jobName)
+		}
+		return models.Job{}, err
+	}
+	return models.Job{
+		Name:     jobName,
+		Contents: contents,
+	}, nil
+}
+
+func (repo *JobRepository) containerURL() azblob.ContainerURL {
+	return repo.ServiceURL.NewContainerURL(repo.Container)
+}
+
+// listBlobs returns the names of blobs under prefix with the job suffix,
+// following markers for containers with more blobs than a single segment
+func (repo *JobRepository) listBlobs(ctx context.Context, prefix string) ([]string, error) {
+	containerURL := repo.containerURL()
+
+	var blobNames []string
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		segment, err := containerURL.ListBlobsFlatSegment(ctx, marker, azblob.ListBlobsSegmentOptions{
+			Prefix: prefix,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list blobs in %s", repo.Container)
+		}
+		for _, blob := range segment.Segment.BlobItems {
+			if strings.HasSuffix(blob.Name, repo.Suffix) {
+				blobNames = append(blobNames, blob.Name)
+			}
+		}
+		marker = segment.NextMarker
+	}
+	return blobNames, nil
+}
+
+func (repo *JobRepository) read(filePath string) ([]byte, error) {
+	reader, err := repo.ObjectReader.NewReader(repo.Container, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var b bytes.Buffer
+	if _, err := b.ReadFrom(reader); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (repo *JobRepository) pathFor(j models.Job) string {
+	return fmt.Sprintf("%s%s", path.Join(repo.Prefix, j.NamespaceID, j.Name), repo.Suffix)
+}
+
+func (repo *JobRepository) jobNameFromPath(filePath string) string {
+	jobFileName := path.Base(filePath)
+	return strings.TrimSuffix(jobFileName, repo.Suffix)
+}
+
+func isBlobNotFound(err error) bool {
+	var storageErr azblob.StorageError
+	return errors.As(err, &storageErr) && storageErr.ServiceCode() == azblob.ServiceCodeBlobNotFound
+}
+
+// NewJobRepository constructs a new azure blob storage backed JobRepository,
+// blobs are created under prefix in container
+func NewJobRepository(container, prefix, suffix string, serviceURL azblob.ServiceURL) *JobRepository {
+	return &JobRepository{
+		ObjectReader: &azureBlobObjectReader{serviceURL: serviceURL},
+		ObjectWriter: &AzureBlobObjectWriter{ServiceURL: serviceURL},
+		ServiceURL:   serviceURL,
+		Container:    container,
+		Prefix:       strings.Trim(prefix, "/"),
+		Suffix:       suffix,
+	}
+}
@@ -0,0 +1,119 @@
+package azure_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store/azure"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobRepository(t *testing.T) {
+	ctx := context.Background()
+	container := "scheduled-tasks"
+	namespace := models.NamespaceSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "dev-team-1",
+	}
+	testJob := models.Job{
+		Name:        "test",
+		NamespaceID: namespace.ID.String(),
+		Contents:    []byte("print('this is a job')"),
+	}
+	jobKey := fmt.Sprintf("resources/jobs/%s/test.py", namespace.ID.String())
+
+	t.Run("Save", func(t *testing.T) {
+		t.Run("should write job contents under prefix without leading slash", func(t *testing.T) {
+			svc, serviceURL := newMemoryBlobService(t, container)
+			repo := azure.NewJobRepository(container, "/resources/jobs", ".py", serviceURL)
+
+			err := repo.Save(ctx, testJob)
+			assert.Nil(t, err)
+			assert.Equal(t, testJob.Contents, svc.blobs[jobKey])
+		})
+		t.Run("should fail if container doesn't exist", func(t *testing.T) {
+			_, serviceURL := newMemoryBlobService(t, container)
+			repo := azure.NewJobRepository("unknown", "resources/jobs", ".py", serviceURL)
+
+			err := repo.Save(ctx, testJob)
+			assert.NotNil(t, err)
+		})
+	})
+	t.Run("GetByName", func(t *testing.T) {
+		t.Run("should read job contents", func(t *testing.T) {
+			svc, serviceURL := newMemoryBlobService(t, container)
+			svc.blobs["resources/jobs/test.py"] = testJob.Contents
+			repo := azure.NewJobRepository(container, "resources/jobs", ".py", serviceURL)
+
+			job, err := repo.GetByName(ctx, "test")
+			assert.Nil(t, err)
+			assert.Equal(t, "test", job.Name)
+			assert.Equal(t, testJob.Contents, job.Contents)
+		})
+		t.Run("should return ErrNoSuchJob if job doesn't exist", func(t *testing.T) {
+			_, serviceURL := newMemoryBlobService(t, container)
+			repo := azure.NewJobRepository(container, "resources/jobs", ".py", serviceURL)
+
+			_, err := repo.GetByName(ctx, "test")
+			assert.True(t, errors.Is(err, models.ErrNoSuchJob))
+		})
+	})
+	t.Run("GetAll", func(t *testing.T) {
+		t.Run("should read jobs from all pages with the suffix", func(t *testing.T) {
+			svc, serviceURL := newMemoryBlobService(t, container)
+			svc.pageSize = 1
+			svc.blobs["resources/jobs/a.py"] = []byte("a")
+			svc.blobs["resources/jobs/b.py"] = []byte("b")
+			svc.blobs["resources/jobs/__lib.txt"] = []byte("lib")
+			repo := azure.NewJobRepository(container, "resources/jobs", ".py", serviceURL)
+
+			jobs, err := repo.GetAll(ctx)
+			assert.Nil(t, err)
+			assert.Equal(t, []models.Job{
+				{Name: "a", Contents: []byte("a")},
+				{Name: "b", Contents: []byte("b")},
+			}, jobs)
+		})
+	})
+	t.Run("ListNames", func(t *testing.T) {
+		t.Run("should list job names of the namespace", func(t *testing.T) {
+			svc, serviceURL := newMemoryBlobService(t, container)
+			svc.blobs[jobKey] = testJob.Contents
+			svc.blobs["resources/jobs/other-namespace/other.py"] = []byte("other")
+			repo := azure.NewJobRepository(container, "resources/jobs", ".py", serviceURL)
+
+			names, err := repo.ListNames(ctx, namespace)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"test"}, names)
+		})
+	})
+	t.Run("Delete", func(t *testing.T) {
+		t.Run("should delete job of the namespace", func(t *testing.T) {
+			svc, serviceURL := newMemoryBlobService(t, container)
+			svc.blobs[jobKey] = testJob.Contents
+			repo := azure.NewJobRepository(container, "resources/jobs", ".py", serviceURL)
+
+			err := repo.Delete(ctx, namespace, "test")
+			assert.Nil(t, err)
+			assert.Empty(t, svc.blobs)
+		})
+		t.Run("should return ErrNoSuchJob if job doesn't exist", func(t *testing.T) {
+			_, serviceURL := newMemoryBlobService(t, container)
+			repo := azure.NewJobRepository(container, "resources/jobs", ".py", serviceURL)
+
+			err := repo.Delete(ctx, namespace, "test")
+			assert.True(t, errors.Is(err, models.ErrNoSuchJob))
+		})
+		t.Run("should fail if job name is empty", func(t *testing.T) {
+			_, serviceURL := newMemoryBlobService(t, container)
+			repo := azure.NewJobRepository(container, "resources/jobs", ".py", serviceURL)
+
+			err := repo.Delete(ctx, namespace, " ")
+			assert.NotNil(t, err)
+		})
+	})
+}
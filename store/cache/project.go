@@ -0,0 +1,235 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// ProjectCacheSize is the default number of projects kept, the least
+	// recently used projects are evicted first
+	ProjectCacheSize = 100
+
+	// ProjectCacheTTL is the default duration projects are reused for
+	ProjectCacheTTL = time.Minute
+
+	metricProjectCacheHits   = "optimus_project_cache_hits_total"
+	metricProjectCacheMisses = "optimus_project_cache_misses_total"
+)
+
+// ProjectRepository is a project repository caching the projects it reads
+type ProjectRepository interface {
+	store.ProjectRepository
+
+	// Invalidate evicts the project, it is read from the wrapped repository
+	// next time
+	Invalidate(projectName string)
+}
+
+// NewSecretRepository evicts the project from projects when its secrets,
+// which are read with the project, are changed through repo
+func NewSecretRepository(projectName string, repo store.ProjectSecretRepository, projects ProjectRepository) store.ProjectSecretRepository {
+	return &projectSecretRepository{
+		ProjectSecretRepository: repo,
+		projectName:             projectName,
+		projects:                projects,
+	}
+}
+
+type projectSecretRepository struct {
+	store.ProjectSecretRepository
+	projectName string
+	projects    ProjectRepository
+}
+
+func (r *projectSecretRepository) Save(item models.ProjectSecretItem) error {
+	defer r.projects.Invalidate(r.projectName)
+	return r.ProjectSecretRepository.Save(item)
+}
+
+func (r *projectSecretRepository) Migrate(fromHash, toHash models.ApplicationKey) error {
+	defer r.projects.Invalidate(r.projectName)
+	return r.ProjectSecretRepository.Migrate(fromHash, toHash)
+}
+
+// ProjectCache keeps projects read by name from the wrapped repository.
+// Projects are read again once they expire or are changed through the
+// cache, changes made by other servers are seen once they expire
+type ProjectCache struct {
+	Now func() time.Time
+
+	repo   store.ProjectRepository
+	size   int
+	ttl    time.Duration
+	hits   prometheus.Counter
+	misses prometheus.Counter
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	recency *list.List
+	// incremented by every invalidation, projects read while one happened
+	// might be stale and aren't cached
+	generation uint64
+}
+
+type cachedProject struct {
+	spec      models.ProjectSpec
+	expiresAt time.Time
+}
+
+// GetByName returns the cached project if it didn't expire, reads it from
+// the repository otherwise
+func (c *ProjectCache) GetByName(name string) (models.ProjectSpec, error) {
+	c.mu.Lock()
+	if elem, ok := c.entries[name]; ok {
+		cached := elem.Value.(*cachedProject)
+		if c.Now().Before(cached.expiresAt) {
+			c.recency.MoveToFront(elem)
+			c.mu.Unlock()
+			c.hits.Inc()
+			return copyProject(cached.spec), nil
+		}
+		c.recency.Remove(elem)
+		delete(c.entries, name)
+	}
+	generation := c.generation
+	c.mu.Unlock()
+	c.misses.Inc()
+
+	spec, err := c.repo.GetByName(name)
+	if err != nil {
+		return spec, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return spec, nil
+	}
+	if elem, ok := c.entries[name]; ok {
+		// read by a concurrent call meanwhile
+		c.recency.Remove(elem)
+		delete(c.entries, name)
+	}
+	c.entries[name] = c.recency.PushFront(&cachedProject{
+		spec:      copyProject(spec),
+		expiresAt: c.Now().Add(c.ttl),
+	})
+	for c.recency.Len() > c.size {
+		oldest := c.recency.Back()
+		c.recency.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedProject).spec.Name)
+	}
+	return spec, nil
+}
+
+func (c *ProjectCache) GetAll() ([]models.ProjectSpec, error) {
+	return c.repo.GetAll()
+}
+
+func (c *ProjectCache) Save(spec models.ProjectSpec) error {
+	defer c.Invalidate(spec.Name)
+	return c.repo.Save(spec)
+}
+
+func (c *ProjectCache) SaveVariables(projectName string, variables models.ProjectVariables) error {
+	defer c.Invalidate(projectName)
+	return c.repo.SaveVariables(projectName, variables)
+}
+
+func (c *ProjectCache) SaveQuota(projectName string, quota models.ProjectQuota) error {
+	defer c.Invalidate(projectName)
+	return c.repo.SaveQuota(projectName, quota)
+}
+
+func (c *ProjectCache) GetProjectHealth(ctx context.Context, spec models.ProjectSpec) (*models.ProjectHealth, error) {
+	return c.repo.GetProjectHealth(ctx, spec)
+}
+
+func (c *ProjectCache) Invalidate(projectName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	if elem, ok := c.entries[projectName]; ok {
+		c.recency.Remove(elem)
+		delete(c.entries, projectName)
+	}
+}
+
+// copyProject keeps callers modifying returned projects from changing the cache
+func copyProject(spec models.ProjectSpec) models.ProjectSpec {
+	copied := spec
+	if spec.Config != nil {
+		copied.Config = make(map[string]string, len(spec.Config))
+		for key, value := range spec.Config {
+			copied.Config[key] = value
+		}
+	}
+	if spec.FeatureFlags != nil {
+		copied.FeatureFlags = make(map[string]bool, len(spec.FeatureFlags))
+		for key, value := range spec.FeatureFlags {
+			copied.FeatureFlags[key] = value
+		}
+	}
+	if spec.Variables != nil {
+		copied.Variables = make(models.ProjectVariables, len(spec.Variables))
+		for key, value := range spec.Variables {
+			copied.Variables[key] = value
+		}
+	}
+	copied.Secret = append(models.ProjectSecrets(nil), spec.Secret...)
+	copied.Webhooks = append([]models.WebhookConfig(nil), spec.Webhooks...)
+	copied.BlackoutWindows = append([]models.BlackoutWindow(nil), spec.BlackoutWindows...)
+	return copied
+}
+
+// NewProjectCache caches projects of repo and registers the hit and miss
+// counters with reg, size and ttl default to ProjectCacheSize and
+// ProjectCacheTTL if not positive
+func NewProjectCache(repo store.ProjectRepository, size int, ttl time.Duration, reg prometheus.Registerer) (*ProjectCache, error) {
+	if size <= 0 {
+		size = ProjectCacheSize
+	}
+	if ttl <= 0 {
+		ttl = ProjectCacheTTL
+	}
+	hits, misses, err := newProjectCacheCounters(reg)
+	if err != nil {
+		return nil, err
+	}
+	return &ProjectCache{
+		Now:     time.Now,
+		repo:    repo,
+		size:    size,
+		ttl:     ttl,
+		hits:    hits,
+		misses:  misses,
+		entries: map[string]*list.Element{},
+		recency: list.New(),
+	}, nil
+}
+
+// newProjectCacheCounters creates the hit and miss counters of a project
+// cache and registers them with reg
+func newProjectCacheCounters(reg prometheus.Registerer) (prometheus.Counter, prometheus.Counter, error) {
+	hits := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: metricProjectCacheHits,
+		Help: "Number of projects read from the project cache",
+	})
+	misses := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: metricProjectCacheMisses,
+		Help: "Number of projects not found in the project cache and read from the database",
+	})
+	for _, counter := range []prometheus.Collector{hits, misses} {
+		if err := reg.Register(counter); err != nil {
+			return nil, nil, err
+		}
+	}
+	return hits, misses, nil
+}
@@ -0,0 +1,137 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store/cache"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectCache(t *testing.T) {
+	projA := models.ProjectSpec{
+		Name:   "project-a",
+		Config: map[string]string{models.ProjectStoragePathKey: "gs://bucket-a"},
+	}
+	projB := models.ProjectSpec{Name: "project-b"}
+	newCache := func(t *testing.T, repo *mock.ProjectRepository, size int) (*cache.ProjectCache, *prometheus.Registry) {
+		reg := prometheus.NewRegistry()
+		projectCache, err := cache.NewProjectCache(repo, size, time.Minute, reg)
+		assert.Nil(t, err)
+		return projectCache, reg
+	}
+	counts := func(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+		families, err := reg.Gather()
+		assert.Nil(t, err)
+		values := map[string]float64{}
+		for _, family := range families {
+			values[family.GetName()] = family.GetMetric()[0].GetCounter().GetValue()
+		}
+		return values
+	}
+
+	t.Run("should read projects once till they expire", func(t *testing.T) {
+		repo := new(mock.ProjectRepository)
+		repo.On("GetByName", projA.Name).Return(projA, nil).Twice()
+		defer repo.AssertExpectations(t)
+		projectCache, reg := newCache(t, repo, 0)
+		now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+		projectCache.Now = func() time.Time { return now }
+
+		for idx := 0; idx < 3; idx++ {
+			spec, err := projectCache.GetByName(projA.Name)
+			assert.Nil(t, err)
+			assert.Equal(t, projA, spec)
+		}
+		now = now.Add(time.Minute)
+		_, err := projectCache.GetByName(projA.Name)
+		assert.Nil(t, err)
+
+		assert.Equal(t, map[string]float64{
+			"optimus_project_cache_hits_total":   2,
+			"optimus_project_cache_misses_total": 2,
+		}, counts(t, reg))
+	})
+	t.Run("should evict the least recently used project", func(t *testing.T) {
+		projC := models.ProjectSpec{Name: "project-c"}
+		repo := new(mock.ProjectRepository)
+		repo.On("GetByName", projA.Name).Return(projA, nil).Once()
+		repo.On("GetByName", projB.Name).Return(projB, nil).Twice()
+		repo.On("GetByName", projC.Name).Return(projC, nil).Once()
+		defer repo.AssertExpectations(t)
+		projectCache, _ := newCache(t, repo, 2)
+
+		for _, name := range []string{projA.Name, projB.Name, projA.Name, projC.Name, projA.Name, projB.Name} {
+			_, err := projectCache.GetByName(name)
+			assert.Nil(t, err)
+		}
+	})
+	t.Run("should read projects again once they are changed", func(t *testing.T) {
+		variables := models.ProjectVariables{"DATASET": "warehouse"}
+		quota := models.ProjectQuota{MaxJobCount: 10}
+		repo := new(mock.ProjectRepository)
+		repo.On("GetByName", projA.Name).Return(projA, nil).Times(4)
+		repo.On("Save", projA).Return(nil)
+		repo.On("SaveVariables", projA.Name, variables).Return(nil)
+		repo.On("SaveQuota", projA.Name, quota).Return(nil)
+		defer repo.AssertExpectations(t)
+		projectCache, _ := newCache(t, repo, 0)
+
+		_, err := projectCache.GetByName(projA.Name)
+		assert.Nil(t, err)
+		assert.Nil(t, projectCache.Save(projA))
+		_, err = projectCache.GetByName(projA.Name)
+		assert.Nil(t, err)
+		assert.Nil(t, projectCache.SaveVariables(projA.Name, variables))
+		_, err = projectCache.GetByName(projA.Name)
+		assert.Nil(t, err)
+		assert.Nil(t, projectCache.SaveQuota(projA.Name, quota))
+		_, err = projectCache.GetByName(projA.Name)
+		assert.Nil(t, err)
+	})
+	t.Run("should read projects again once their secrets are changed", func(t *testing.T) {
+		secret := models.ProjectSecretItem{Name: "STORAGE", Value: "secret"}
+		repo := new(mock.ProjectRepository)
+		repo.On("GetByName", projA.Name).Return(projA, nil).Twice()
+		defer repo.AssertExpectations(t)
+		secretRepo := new(mock.ProjectSecretRepository)
+		secretRepo.On("Save", secret).Return(nil)
+		defer secretRepo.AssertExpectations(t)
+		projectCache, _ := newCache(t, repo, 0)
+
+		_, err := projectCache.GetByName(projA.Name)
+		assert.Nil(t, err)
+		assert.Nil(t, cache.NewSecretRepository(projA.Name, secretRepo, projectCache).Save(secret))
+		_, err = projectCache.GetByName(projA.Name)
+		assert.Nil(t, err)
+	})
+	t.Run("should not cache projects failing to be read", func(t *testing.T) {
+		repo := new(mock.ProjectRepository)
+		repo.On("GetByName", projB.Name).Return(models.ProjectSpec{}, errors.New("connection refused")).Twice()
+		defer repo.AssertExpectations(t)
+		projectCache, reg := newCache(t, repo, 0)
+
+		for idx := 0; idx < 2; idx++ {
+			_, err := projectCache.GetByName(projB.Name)
+			assert.NotNil(t, err)
+		}
+		assert.Equal(t, float64(2), counts(t, reg)["optimus_project_cache_misses_total"])
+	})
+	t.Run("should not share the cached project with callers", func(t *testing.T) {
+		repo := new(mock.ProjectRepository)
+		repo.On("GetByName", projA.Name).Return(projA, nil).Once()
+		defer repo.AssertExpectations(t)
+		projectCache, _ := newCache(t, repo, 0)
+
+		spec, err := projectCache.GetByName(projA.Name)
+		assert.Nil(t, err)
+		spec.Config[models.ProjectStoragePathKey] = "gs://changed"
+		spec, err = projectCache.GetByName(projA.Name)
+		assert.Nil(t, err)
+		assert.Equal(t, "gs://bucket-a", spec.Config[models.ProjectStoragePathKey])
+	})
+}
@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gtank/cryptopasta"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// RedisBreakerThreshold is the number of consecutive failed calls after
+	// which redis isn't called for RedisBreakerCooldown, projects are read
+	// from the wrapped repository meanwhile
+	RedisBreakerThreshold = 5
+	RedisBreakerCooldown  = 30 * time.Second
+
+	// redisTimeout bounds connecting to redis and each call, unless set in
+	// the redis url
+	redisTimeout = 500 * time.Millisecond
+
+	// redisVersionTTL keeps versions of projects for longer than any
+	// cached project lives
+	redisVersionTTL = 24 * time.Hour
+
+	redisProjectKeyPrefix = "optimus:project:"
+	redisVersionKeyPrefix = "optimus:project-version:"
+
+	metricProjectCacheErrors = "optimus_project_cache_errors_total"
+)
+
+// errRedisUnavailable is returned for calls not made while the breaker is open
+var errRedisUnavailable = errors.New("redis is unavailable")
+
+// RedisProjectCache keeps projects read by name in redis, shared by all
+// servers using the same redis. Cached projects are encrypted with the app
+// key as they contain the secrets of the project.
+//
+// Every change of a project increments its version, projects cached with
+// an older version are read again. Redis failing RedisBreakerThreshold
+// times in a row isn't called for RedisBreakerCooldown, projects are read
+// from the wrapped repository and aren't evicted meanwhile
+type RedisProjectCache struct {
+	Now func() time.Time
+
+	repo    store.ProjectRepository
+	client  *redis.Client
+	hash    models.ApplicationKey
+	ttl     time.Duration
+	breaker *circuitBreaker
+	hits    prometheus.Counter
+	misses  prometheus.Counter
+	errors  prometheus.Counter
+}
+
+type redisCachedProject struct {
+	Version string             `json:"version"`
+	Spec    models.ProjectSpec `json:"spec"`
+}
+
+// GetByName returns the cached project if it is of the current version,
+// reads it from the repository otherwise
+func (c *RedisProjectCache) GetByName(name string) (models.ProjectSpec, error) {
+	ctx := context.Background()
+	var values []interface{}
+	if err := c.call(func() (err error) {
+		values, err = c.client.MGet(ctx, redisProjectKeyPrefix+name, redisVersionKeyPrefix+name).Result()
+		return err
+	}); err != nil {
+		c.misses.Inc()
+		return c.repo.GetByName(name)
+	}
+	version, _ := values[1].(string)
+	if encrypted, ok := values[0].(string); ok {
+		if cached, err := c.decrypt(encrypted); err == nil && cached.Version == version {
+			c.hits.Inc()
+			return cached.Spec, nil
+		}
+	}
+	c.misses.Inc()
+
+	spec, err := c.repo.GetByName(name)
+	if err != nil {
+		return spec, err
+	}
+	// projects changed meanwhile have a newer version, the project read is
+	// cached with the version read before it so it isn't used
+	if encrypted, err := c.encrypt(redisCachedProject{Version: version, Spec: spec}); err == nil {
+		_ = c.call(func() error {
+			return c.client.Set(ctx, redisProjectKeyPrefix+name, encrypted, c.ttl).Err()
+		})
+	}
+	return spec, nil
+}
+
+func (c *RedisProjectCache) GetAll() ([]models.ProjectSpec, error) {
+	return c.repo.GetAll()
+}
+
+func (c *RedisProjectCache) Save(spec models.ProjectSpec) error {
+	defer c.Invalidate(spec.Name)
+	return c.repo.Save(spec)
+}
+
+func (c *RedisProjectCache) SaveVariables(projectName string, variables models.ProjectVariables) error {
+	defer c.Invalidate(projectName)
+	return c.repo.SaveVariables(projectName, variables)
+}
+
+func (c *RedisProjectCache) SaveQuota(projectName string, quota models.ProjectQuota) error {
+	defer c.Invalidate(projectName)
+	return c.repo.SaveQuota(projectName, quota)
+}
+
+func (c *RedisProjectCache) GetProjectHealth(ctx context.Context, spec models.ProjectSpec) (*models.ProjectHealth, error) {
+	return c.repo.GetProjectHealth(ctx, spec)
+}
+
+func (c *RedisProjectCache) Invalidate(projectName string) {
+	ctx := context.Background()
+	_ = c.call(func() error {
+		_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Incr(ctx, redisVersionKeyPrefix+projectName)
+			pipe.Expire(ctx, redisVersionKeyPrefix+projectName, redisVersionTTL)
+			return nil
+		})
+		return err
+	})
+}
+
+// Close closes the connections to redis
+func (c *RedisProjectCache) Close() error {
+	return c.client.Close()
+}
+
+// call calls redis unless the breaker is open
+func (c *RedisProjectCache) call(fn func() error) error {
+	if !c.breaker.Allow(c.Now()) {
+		return errRedisUnavailable
+	}
+	err := fn()
+	c.breaker.Record(c.Now(), err)
+	if err != nil {
+		c.errors.Inc()
+	}
+	return err
+}
+
+func (c *RedisProjectCache) encrypt(cached redisCachedProject) ([]byte, error) {
+	cleartext, err := json.Marshal(cached)
+	if err != nil {
+		return nil, err
+	}
+	return cryptopasta.Encrypt(cleartext, c.hash.GetKey())
+}
+
+// decrypt fails for projects cached by servers with another app key
+func (c *RedisProjectCache) decrypt(encrypted string) (redisCachedProject, error) {
+	var cached redisCachedProject
+	cleartext, err := cryptopasta.Decrypt([]byte(encrypted), c.hash.GetKey())
+	if err != nil {
+		return cached, err
+	}
+	err = json.Unmarshal(cleartext, &cached)
+	return cached, err
+}
+
+// NewRedisProjectCache caches projects of repo in the redis at redisURL,
+// e.g. redis://:password@localhost:6379/0, and registers the hit, miss and
+// error counters with reg. ttl defaults to ProjectCacheTTL if not positive
+func NewRedisProjectCache(repo store.ProjectRepository, redisURL string, hash models.ApplicationKey, ttl time.Duration,
+	reg prometheus.Registerer) (*RedisProjectCache, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, err
+	}
+	// an unreachable redis shouldn't slow calls down till the breaker opens
+	if opts.DialTimeout == 0 {
+		opts.DialTimeout = redisTimeout
+	}
+	if opts.ReadTimeout == 0 {
+		opts.ReadTimeout = redisTimeout
+	}
+	if opts.WriteTimeout == 0 {
+		opts.WriteTimeout = redisTimeout
+	}
+	if ttl <= 0 {
+		ttl = ProjectCacheTTL
+	}
+	hits, misses, err := newProjectCacheCounters(reg)
+	if err != nil {
+		return nil, err
+	}
+	errorCount := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: metricProjectCacheErrors,
+		Help: "Number of failed calls to redis by the project cache",
+	})
+	if err := reg.Register(errorCount); err != nil {
+		return nil, err
+	}
+	return &RedisProjectCache{
+		Now:     time.Now,
+		repo:    repo,
+		client:  redis.NewClient(opts),
+		hash:    hash,
+		ttl:     ttl,
+		breaker: newCircuitBreaker(RedisBreakerThreshold, RedisBreakerCooldown),
+		hits:    hits,
+		misses:  misses,
+		errors:  errorCount,
+	}, nil
+}
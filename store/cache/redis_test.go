@@ -0,0 +1,139 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store/cache"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestRedisProjectCache(t *testing.T) {
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+	projA := models.ProjectSpec{
+		Name:   "project-a",
+		Config: map[string]string{models.ProjectStoragePathKey: "gs://bucket-a"},
+		Secret: models.ProjectSecrets{{Name: "STORAGE", Value: "secret"}},
+	}
+	newCache := func(t *testing.T, repo *mock.ProjectRepository, redisServer *miniredis.Miniredis) (*cache.RedisProjectCache, *prometheus.Registry) {
+		reg := prometheus.NewRegistry()
+		projectCache, err := cache.NewRedisProjectCache(repo, "redis://"+redisServer.Addr(), hash, time.Minute, reg)
+		assert.Nil(t, err)
+		t.Cleanup(func() { projectCache.Close() })
+		return projectCache, reg
+	}
+	counts := func(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+		families, err := reg.Gather()
+		assert.Nil(t, err)
+		values := map[string]float64{}
+		for _, family := range families {
+			values[family.GetName()] = family.GetMetric()[0].GetCounter().GetValue()
+		}
+		return values
+	}
+
+	t.Run("should read projects once till they expire", func(t *testing.T) {
+		redisServer := miniredis.RunT(t)
+		repo := new(mock.ProjectRepository)
+		repo.On("GetByName", projA.Name).Return(projA, nil).Twice()
+		defer repo.AssertExpectations(t)
+		projectCache, reg := newCache(t, repo, redisServer)
+
+		for idx := 0; idx < 3; idx++ {
+			spec, err := projectCache.GetByName(projA.Name)
+			assert.Nil(t, err)
+			assert.Equal(t, projA, spec)
+		}
+		redisServer.FastForward(time.Minute)
+		_, err := projectCache.GetByName(projA.Name)
+		assert.Nil(t, err)
+
+		assert.Equal(t, map[string]float64{
+			"optimus_project_cache_hits_total":   2,
+			"optimus_project_cache_misses_total": 2,
+			"optimus_project_cache_errors_total": 0,
+		}, counts(t, reg))
+	})
+	t.Run("should keep projects encrypted in redis", func(t *testing.T) {
+		redisServer := miniredis.RunT(t)
+		repo := new(mock.ProjectRepository)
+		repo.On("GetByName", projA.Name).Return(projA, nil).Once()
+		projectCache, _ := newCache(t, repo, redisServer)
+
+		_, err := projectCache.GetByName(projA.Name)
+		assert.Nil(t, err)
+		cached, err := redisServer.Get("optimus:project:" + projA.Name)
+		assert.Nil(t, err)
+		assert.NotContains(t, cached, "secret")
+		assert.NotContains(t, cached, "gs://bucket-a")
+	})
+	t.Run("should read projects again once they are changed through any server", func(t *testing.T) {
+		redisServer := miniredis.RunT(t)
+		changed := projA
+		changed.Config = map[string]string{models.ProjectStoragePathKey: "gs://bucket-b"}
+		repo := new(mock.ProjectRepository)
+		repo.On("GetByName", projA.Name).Return(projA, nil).Once()
+		repo.On("Save", changed).Return(nil)
+		repo.On("GetByName", projA.Name).Return(changed, nil).Once()
+		defer repo.AssertExpectations(t)
+		serverA, _ := newCache(t, repo, redisServer)
+		serverB, _ := newCache(t, repo, redisServer)
+
+		_, err := serverA.GetByName(projA.Name)
+		assert.Nil(t, err)
+		assert.Nil(t, serverB.Save(changed))
+		for _, projectCache := range []*cache.RedisProjectCache{serverA, serverB} {
+			spec, err := projectCache.GetByName(projA.Name)
+			assert.Nil(t, err)
+			assert.Equal(t, changed, spec)
+		}
+	})
+	t.Run("should not cache projects changed while being read", func(t *testing.T) {
+		redisServer := miniredis.RunT(t)
+		repo := new(mock.ProjectRepository)
+		projectCache, _ := newCache(t, repo, redisServer)
+		repo.On("GetByName", projA.Name).Run(func(args mock2.Arguments) {
+			projectCache.Invalidate(projA.Name)
+		}).Return(projA, nil).Twice()
+		defer repo.AssertExpectations(t)
+
+		for idx := 0; idx < 2; idx++ {
+			_, err := projectCache.GetByName(projA.Name)
+			assert.Nil(t, err)
+		}
+	})
+	t.Run("should read projects from the repository while redis is down", func(t *testing.T) {
+		redisServer := miniredis.RunT(t)
+		repo := new(mock.ProjectRepository)
+		repo.On("GetByName", projA.Name).Return(projA, nil)
+		projectCache, reg := newCache(t, repo, redisServer)
+		now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+		projectCache.Now = func() time.Time { return now }
+		redisServer.Close()
+
+		for idx := 0; idx < cache.RedisBreakerThreshold*2; idx++ {
+			spec, err := projectCache.GetByName(projA.Name)
+			assert.Nil(t, err)
+			assert.Equal(t, projA, spec)
+		}
+		// redis isn't called once the breaker opened
+		assert.Equal(t, float64(cache.RedisBreakerThreshold), counts(t, reg)["optimus_project_cache_errors_total"])
+
+		assert.Nil(t, redisServer.Restart())
+		// redis is called again after the cooldown, the client reconnects in
+		// background once it failed to
+		assert.Eventually(t, func() bool {
+			now = now.Add(cache.RedisBreakerCooldown)
+			for idx := 0; idx < 2; idx++ {
+				_, err := projectCache.GetByName(projA.Name)
+				assert.Nil(t, err)
+			}
+			return counts(t, reg)["optimus_project_cache_hits_total"] > 0
+		}, time.Second*5, time.Millisecond*100)
+	})
+}
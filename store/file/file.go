@@ -0,0 +1,137 @@
+package file
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrDisabled is returned for file urls when no root directory is
+// configured, file storage is disabled by default
+var ErrDisabled = errors.New("file storage is disabled, set serve.file_storage_root to enable it")
+
+// ParseURL returns the directory of file:///path urls, only local hosts and
+// directories under root are supported
+func ParseURL(storageURL *url.URL, root string) (string, error) {
+	if storageURL.Scheme != "file" {
+		return "", errors.Errorf("invalid scheme %s, expected file", storageURL.Scheme)
+	}
+	if err := checkLocalHost(storageURL.Hostname()); err != nil {
+		return "", err
+	}
+	if storageURL.Path == "" {
+		return "", errors.Errorf("directory missing in %s", storageURL.String())
+	}
+	return Resolve(root, storageURL.Path)
+}
+
+// Resolve returns path resolved from the filesystem root, it fails if the
+// cleaned path isn't root or a path under it
+func Resolve(root, path string) (string, error) {
+	if root == "" {
+		return "", ErrDisabled
+	}
+	if !filepath.IsAbs(root) {
+		return "", errors.Errorf("file storage root %s is not an absolute path", root)
+	}
+	root = filepath.Clean(root)
+	target := filepath.Join(string(filepath.Separator), path)
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("%s is outside of the file storage root %s", target, root)
+	}
+	return target, nil
+}
+
+func checkLocalHost(host string) error {
+	if host != "" && host != "localhost" {
+		return errors.Errorf("file storage only supports local paths, found host %s", host)
+	}
+	return nil
+}
+
+// FileObjectWriter writes objects to the local filesystem, paths are resolved
+// from the filesystem root and the bucket is the host of file urls. Only
+// paths under Root can be written
+type FileObjectWriter struct {
+	Root string
+}
+
+// NewWriter writes the object to a temporary file next to its path, the file
+// is renamed to path once the writer is closed so readers never see partial
+// writes. Missing directories are created.
+func (fw *FileObjectWriter) NewWriter(ctx context.Context, bucket, path string) (io.WriteCloser, error) {
+	if err := checkLocalHost(bucket); err != nil {
+		return nil, err
+	}
+	target, err := Resolve(fw.Root, path)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create directory %s", dir)
+	}
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return nil, errors.Wrapf(err, "directory %s is not writable", dir)
+	}
+	return &atomicWriter{
+		tmp:    tmp,
+		target: target,
+	}, nil
+}
+
+// atomicWriter writes to a temporary file renamed to target on close
+type atomicWriter struct {
+	tmp    *os.File
+	target string
+}
+
+func (w *atomicWriter) Write(p []byte) (int, error) {
+	return w.tmp.Write(p)
+}
+
+func (w *atomicWriter) Close() (err error) {
+	defer func() {
+		if err != nil {
+			os.Remove(w.tmp.Name())
+		}
+	}()
+	if err = w.tmp.Sync(); err != nil {
+		w.tmp.Close()
+		return errors.Wrapf(err, "failed to write %s", w.target)
+	}
+	if err = w.tmp.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write %s", w.target)
+	}
+	// temporary files are created readable only by the owner
+	if err = os.Chmod(w.tmp.Name(), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write %s", w.target)
+	}
+	if err = os.Rename(w.tmp.Name(), w.target); err != nil {
+		return errors.Wrapf(err, "failed to write %s", w.target)
+	}
+	return nil
+}
+
+type fileObjectReader struct {
+	root string
+}
+
+func (fr *fileObjectReader) NewReader(bucket, path string) (io.ReadCloser, error) {
+	if err := checkLocalHost(bucket); err != nil {
+		return nil, err
+	}
+	target, err := Resolve(fr.root, path)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(target)
+}
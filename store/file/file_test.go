@@ -0,0 +1,143 @@
+package file_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/odpf/optimus/store/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileObjectWriter(t *testing.T) {
+	ctx := context.Background()
+	t.Run("should create missing directories and write object", func(t *testing.T) {
+		dir := t.TempDir()
+		writer := &file.FileObjectWriter{Root: dir}
+
+		// object paths are relative to the filesystem root
+		w, err := writer.NewWriter(ctx, "", strings.TrimPrefix(filepath.Join(dir, "jobs", "ns", "hello.py"), "/"))
+		assert.Nil(t, err)
+		_, err = io.Copy(w, strings.NewReader("print('hello')"))
+		assert.Nil(t, err)
+		assert.Nil(t, w.Close())
+
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "jobs", "ns", "hello.py"))
+		assert.Nil(t, err)
+		assert.Equal(t, "print('hello')", string(contents))
+	})
+	t.Run("should not expose partial writes before close", func(t *testing.T) {
+		dir := t.TempDir()
+		target := filepath.Join(dir, "hello.py")
+		assert.Nil(t, ioutil.WriteFile(target, []byte("old"), 0644))
+		writer := &file.FileObjectWriter{Root: dir}
+
+		w, err := writer.NewWriter(ctx, "localhost", target)
+		assert.Nil(t, err)
+		_, err = io.Copy(w, strings.NewReader("new"))
+		assert.Nil(t, err)
+
+		contents, _ := ioutil.ReadFile(target)
+		assert.Equal(t, "old", string(contents))
+		assert.Nil(t, w.Close())
+		contents, _ = ioutil.ReadFile(target)
+		assert.Equal(t, "new", string(contents))
+
+		entries, _ := ioutil.ReadDir(dir)
+		assert.Len(t, entries, 1)
+	})
+	t.Run("should fail if directory can't be created", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "jobs"), nil, 0644))
+		writer := &file.FileObjectWriter{Root: dir}
+
+		_, err := writer.NewWriter(ctx, "", filepath.Join(dir, "jobs", "hello.py"))
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "failed to create directory")
+	})
+	t.Run("should fail for remote hosts", func(t *testing.T) {
+		writer := &file.FileObjectWriter{Root: "/tmp"}
+
+		_, err := writer.NewWriter(ctx, "example.com", "/tmp/hello.py")
+		assert.NotNil(t, err)
+	})
+	t.Run("should remove temporary file if rename fails", func(t *testing.T) {
+		dir := t.TempDir()
+		target := filepath.Join(dir, "hello.py")
+		writer := &file.FileObjectWriter{Root: dir}
+
+		w, err := writer.NewWriter(ctx, "", target)
+		assert.Nil(t, err)
+		// a non empty directory can't be replaced by a file
+		assert.Nil(t, os.MkdirAll(filepath.Join(target, "nested"), 0755))
+		assert.NotNil(t, w.Close())
+
+		entries, _ := ioutil.ReadDir(dir)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "hello.py", entries[0].Name())
+	})
+	t.Run("should not write outside of the root", func(t *testing.T) {
+		dir := t.TempDir()
+		writer := &file.FileObjectWriter{Root: filepath.Join(dir, "dags")}
+
+		for _, path := range []string{
+			filepath.Join(dir, "hello.py"),
+			filepath.Join(dir, "dags", "..", "hello.py"),
+			filepath.Join(dir, "dags-other", "hello.py"),
+			"/etc/cron.d/hello",
+		} {
+			_, err := writer.NewWriter(ctx, "", path)
+			assert.NotNil(t, err, path)
+		}
+		entries, _ := ioutil.ReadDir(dir)
+		assert.Empty(t, entries)
+	})
+	t.Run("should fail if no root is configured", func(t *testing.T) {
+		writer := &file.FileObjectWriter{}
+
+		_, err := writer.NewWriter(ctx, "", filepath.Join(t.TempDir(), "hello.py"))
+		assert.Equal(t, file.ErrDisabled, err)
+	})
+}
+
+func TestParseURL(t *testing.T) {
+	t.Run("should return directory of file url", func(t *testing.T) {
+		u, _ := url.Parse("file:///var/optimus/dags/")
+		dir, err := file.ParseURL(u, "/var/optimus")
+		assert.Nil(t, err)
+		assert.Equal(t, "/var/optimus/dags", dir)
+	})
+	t.Run("should accept localhost", func(t *testing.T) {
+		u, _ := url.Parse("file://localhost/var/optimus")
+		dir, err := file.ParseURL(u, "/var/optimus")
+		assert.Nil(t, err)
+		assert.Equal(t, "/var/optimus", dir)
+	})
+	t.Run("should fail for remote hosts", func(t *testing.T) {
+		u, _ := url.Parse("file://example.com/var/optimus")
+		_, err := file.ParseURL(u, "/var/optimus")
+		assert.NotNil(t, err)
+	})
+	t.Run("should fail if directory is missing", func(t *testing.T) {
+		u, _ := url.Parse("file://localhost")
+		_, err := file.ParseURL(u, "/var/optimus")
+		assert.NotNil(t, err)
+	})
+	t.Run("should fail for directories outside of the root", func(t *testing.T) {
+		for _, storageURL := range []string{"file:///etc", "file:///var/optimus/../../etc", "file:///var/optimus-other"} {
+			u, _ := url.Parse(storageURL)
+			_, err := file.ParseURL(u, "/var/optimus")
+			assert.NotNil(t, err, storageURL)
+		}
+	})
+	t.Run("should fail if no root is configured", func(t *testing.T) {
+		u, _ := url.Parse("file:///var/optimus")
+		_, err := file.ParseURL(u, "")
+		assert.Equal(t, file.ErrDisabled, err)
+	})
+}
@@ -0,0 +1,173 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+var (
+	errEmptyJobName = errors.New("job name cannot be an empty string")
+)
+
+// JobRepository stores compiled jobs as files under a local directory,
+// jobs of each namespace are kept in a sub directory named after its id.
+// Files outside of Root are never read, written or deleted
+type JobRepository struct {
+	ObjectReader store.ObjectReader
+	ObjectWriter store.ObjectWriter
+	Root         string
+	Dir          string
+	Suffix       string
+}
+
+func (repo *JobRepository) Save(ctx context.Context, j models.Job) (err error) {
+	dst, err := repo.ObjectWriter.NewWriter(ctx, "", repo.pathFor(j))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if derr := dst.Close(); derr != nil {
+			if err == nil {
+				err = derr
+			} else {
+				err = errors.Wrap(err, derr.Error())
+			}
+		}
+	}()
+	src := bytes.NewBuffer(j.Contents)
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+func (repo *JobRepository) Delete(ctx context.Context, namespace models.NamespaceSpec, jobName string) error {
+	if strings.TrimSpace(jobName) == "" {
+		return errEmptyJobName
+	}
+
+	filePath, err := Resolve(repo.Root, fmt.Sprintf("%s%s", filepath.Join(repo.Dir, namespace.ID.String(), jobName), repo.Suffix))
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil {
+		if os.IsNotExist(err) {
+			return errors.Wrap(models.ErrNoSuchJob, jobName)
+		}
+		return errors.Wrapf(err, "failed to delete %s", jobName)
+	}
+	return nil
+}
+
+func (repo *JobRepository) GetAll(ctx context.Context) ([]models.Job, error) {
+	filePaths, err := repo.listFiles(repo.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []models.Job
+	for _, filePath := range filePaths {
+		contents, err := repo.read(filePath)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, models.Job{
+			Name:     repo.jobNameFromPath(filePath),
+			Contents: contents,
+		})
+	}
+	return jobs, nil
+}
+
+func (repo *JobRepository) ListNames(ctx context.Context, namespace models.NamespaceSpec) ([]string, error) {
+	filePaths, err := repo.listFiles(filepath.Join(repo.Dir, namespace.ID.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	var jobNames []string
+	for _, filePath := range filePaths {
+		jobNames = append(jobNames, repo.jobNameFromPath(filePath))
+	}
+	return jobNames, nil
+}
+
+func (repo *JobRepository) GetByName(ctx context.Context, jobName string) (models.Job, error) {
+	if strings.TrimSpace(jobName) == "" {
+		return models.Job{}, errEmptyJobName
+	}
+
+	filePath := fmt.Sprintf("%s%s", filepath.Join(repo.Dir, jobName), repo.Suffix)
+	contents, err := repo.read(filePath)
+	if err != nil {
+		if os.IsNotExist(errors.Cause(err)) {
+			return models.Job{}, errors.Wrap(models.ErrNoSuchJob, jobName)
+		}
+		return models.Job{}, err
+	}
+	return models.Job{
+		Name:     jobName,
+		Contents: contents,
+	}, nil
+}
+
+// listFiles walks dir for files with the job suffix, a missing directory
+// has no jobs
+func (repo *JobRepository) listFiles(dir string) ([]string, error) {
+	var filePaths []string
+	err := filepath.Walk(dir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && filePath == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		// temporary files of writes in progress are hidden
+		if !info.IsDir() && !strings.HasPrefix(info.Name(), ".") && strings.HasSuffix(info.Name(), repo.Suffix) {
+			filePaths = append(filePaths, filePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list jobs in %s", dir)
+	}
+	return filePaths, nil
+}
+
+func (repo *JobRepository) read(filePath string) ([]byte, error) {
+	reader, err := repo.ObjectReader.NewReader("", filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+func (repo *JobRepository) pathFor(j models.Job) string {
+	return fmt.Sprintf("%s%s", filepath.Join(repo.Dir, j.NamespaceID, j.Name), repo.Suffix)
+}
+
+func (repo *JobRepository) jobNameFromPath(filePath string) string {
+	jobFileName := filepath.Base(filePath)
+	return strings.TrimSuffix(jobFileName, repo.Suffix)
+}
+
+// NewJobRepository constructs a new local directory backed JobRepository,
+// directories are created on demand. dir has to be under root
+func NewJobRepository(root, dir, suffix string) *JobRepository {
+	return &JobRepository{
+		ObjectReader: &fileObjectReader{root: root},
+		ObjectWriter: &FileObjectWriter{Root: root},
+		Root:         root,
+		Dir:          filepath.Clean(dir),
+		Suffix:       suffix,
+	}
+}
@@ -0,0 +1,158 @@
+package file_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobRepository(t *testing.T) {
+	ctx := context.Background()
+	namespace := models.NamespaceSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "dev-team-1",
+	}
+	testJob := models.Job{
+		Name:        "test",
+		NamespaceID: namespace.ID.String(),
+		Contents:    []byte("print('this is a job')"),
+	}
+	writeJob := func(t *testing.T, path string, contents []byte) {
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, ioutil.WriteFile(path, contents, 0644))
+	}
+
+	t.Run("Save", func(t *testing.T) {
+		t.Run("should write job contents under namespace directory", func(t *testing.T) {
+			dir := t.TempDir()
+			repo := file.NewJobRepository(dir, filepath.Join(dir, "jobs"), ".py")
+
+			err := repo.Save(ctx, testJob)
+			assert.Nil(t, err)
+			contents, err := ioutil.ReadFile(filepath.Join(dir, "jobs", namespace.ID.String(), "test.py"))
+			assert.Nil(t, err)
+			assert.Equal(t, testJob.Contents, contents)
+		})
+		t.Run("should fail if directory is not writable", func(t *testing.T) {
+			dir := t.TempDir()
+			writeJob(t, filepath.Join(dir, "jobs"), nil)
+			repo := file.NewJobRepository(dir, filepath.Join(dir, "jobs"), ".py")
+
+			err := repo.Save(ctx, testJob)
+			assert.NotNil(t, err)
+		})
+	})
+	t.Run("GetByName", func(t *testing.T) {
+		t.Run("should read job contents", func(t *testing.T) {
+			dir := t.TempDir()
+			writeJob(t, filepath.Join(dir, "test.py"), testJob.Contents)
+			repo := file.NewJobRepository(dir, dir, ".py")
+
+			job, err := repo.GetByName(ctx, "test")
+			assert.Nil(t, err)
+			assert.Equal(t, "test", job.Name)
+			assert.Equal(t, testJob.Contents, job.Contents)
+		})
+		t.Run("should not read files outside of the root", func(t *testing.T) {
+			dir := t.TempDir()
+			writeJob(t, filepath.Join(dir, "secret.py"), []byte("secret"))
+			repo := file.NewJobRepository(filepath.Join(dir, "jobs"), filepath.Join(dir, "jobs"), ".py")
+
+			_, err := repo.GetByName(ctx, "../secret")
+			assert.NotNil(t, err)
+		})
+		t.Run("should return ErrNoSuchJob if job doesn't exist", func(t *testing.T) {
+			dir := t.TempDir()
+			repo := file.NewJobRepository(dir, dir, ".py")
+
+			_, err := repo.GetByName(ctx, "test")
+			assert.True(t, errors.Is(err, models.ErrNoSuchJob))
+		})
+	})
+	t.Run("GetAll", func(t *testing.T) {
+		t.Run("should read jobs with the suffix from all directories", func(t *testing.T) {
+			dir := t.TempDir()
+			writeJob(t, filepath.Join(dir, "ns1", "a.py"), []byte("a"))
+			writeJob(t, filepath.Join(dir, "ns2", "b.py"), []byte("b"))
+			writeJob(t, filepath.Join(dir, "__lib.txt"), []byte("lib"))
+			writeJob(t, filepath.Join(dir, "ns1", ".c.py.123.tmp"), []byte("c"))
+			repo := file.NewJobRepository(dir, dir, ".py")
+
+			jobs, err := repo.GetAll(ctx)
+			assert.Nil(t, err)
+			assert.Equal(t, []models.Job{
+				{Name: "a", Contents: []byte("a")},
+				{Name: "b", Contents: []byte("b")},
+			}, jobs)
+		})
+		t.Run("should return no jobs if directory doesn't exist", func(t *testing.T) {
+			dir := t.TempDir()
+			repo := file.NewJobRepository(dir, filepath.Join(dir, "jobs"), ".py")
+
+			jobs, err := repo.GetAll(ctx)
+			assert.Nil(t, err)
+			assert.Empty(t, jobs)
+		})
+	})
+	t.Run("ListNames", func(t *testing.T) {
+		t.Run("should list job names of the namespace", func(t *testing.T) {
+			dir := t.TempDir()
+			writeJob(t, filepath.Join(dir, namespace.ID.String(), "test.py"), testJob.Contents)
+			writeJob(t, filepath.Join(dir, "other-namespace", "other.py"), []byte("other"))
+			repo := file.NewJobRepository(dir, dir, ".py")
+
+			names, err := repo.ListNames(ctx, namespace)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"test"}, names)
+		})
+	})
+	t.Run("Delete", func(t *testing.T) {
+		t.Run("should delete job of the namespace", func(t *testing.T) {
+			dir := t.TempDir()
+			jobPath := filepath.Join(dir, namespace.ID.String(), "test.py")
+			writeJob(t, jobPath, testJob.Contents)
+			repo := file.NewJobRepository(dir, dir, ".py")
+
+			err := repo.Delete(ctx, namespace, "test")
+			assert.Nil(t, err)
+			_, err = os.Stat(jobPath)
+			assert.True(t, os.IsNotExist(err))
+		})
+		t.Run("should return ErrNoSuchJob if job doesn't exist", func(t *testing.T) {
+			dir := t.TempDir()
+			repo := file.NewJobRepository(dir, dir, ".py")
+
+			err := repo.Delete(ctx, namespace, "test")
+			assert.True(t, errors.Is(err, models.ErrNoSuchJob))
+		})
+		t.Run("should fail if job name is empty", func(t *testing.T) {
+			dir := t.TempDir()
+			repo := file.NewJobRepository(dir, dir, ".py")
+
+			err := repo.Delete(ctx, namespace, " ")
+			assert.NotNil(t, err)
+		})
+		t.Run("should not delete files outside of the root", func(t *testing.T) {
+			root := t.TempDir()
+			outside := filepath.Join(t.TempDir(), "victim.py")
+			writeJob(t, outside, []byte("keep"))
+			repo := file.NewJobRepository(filepath.Join(root, "jobs"), filepath.Join(root, "jobs"), ".py")
+
+			rel, err := filepath.Rel(filepath.Join(root, "jobs", namespace.ID.String()), outside)
+			assert.Nil(t, err)
+			err = repo.Delete(ctx, namespace, strings.TrimSuffix(rel, ".py"))
+			assert.NotNil(t, err)
+			_, err = os.Stat(outside)
+			assert.Nil(t, err)
+		})
+	})
+}
@@ -42,12 +42,20 @@ type Job struct {
 	Labels       map[string]string `yaml:"labels,omitempty"`
 	Dependencies []JobDependency
 	Hooks        []JobHook
+	Extends      string `yaml:"extends,omitempty"`
+	IsTemplate   bool   `yaml:"is_template,omitempty"`
 }
 
 type JobSchedule struct {
 	StartDate string `yaml:"start_date" json:"start_date" validate:"regexp=^\\d{4}-\\d{2}-\\d{2}$"`
 	EndDate   string `yaml:"end_date,omitempty" json:"end_date"`
 	Interval  string `yaml:"interval" validate:"isCron"`
+	// SLADuration is the time runs should start within once they are due,
+	// e.g. 2h
+	SLADuration string `yaml:"sla_duration,omitempty" json:"sla_duration,omitempty"`
+	// Timezone is the IANA name of the timezone of the interval and dates,
+	// e.g. Asia/Jakarta
+	Timezone string `yaml:"timezone,omitempty" json:"timezone,omitempty"`
 }
 
 type JobBehavior struct {
@@ -70,9 +78,18 @@ type JobNotifier struct {
 }
 
 type JobTask struct {
-	Name   string
-	Config yaml.MapSlice `yaml:"config,omitempty"`
-	Window JobTaskWindow
+	Name      string
+	Config    yaml.MapSlice `yaml:"config,omitempty"`
+	Window    JobTaskWindow
+	Resources JobTaskResources `yaml:"resources,omitempty"`
+}
+
+// JobTaskResources are kubernetes quantities, e.g. 500m or 2Gi
+type JobTaskResources struct {
+	CPURequest    string `yaml:"cpu_request,omitempty"`
+	CPULimit      string `yaml:"cpu_limit,omitempty"`
+	MemoryRequest string `yaml:"memory_request,omitempty"`
+	MemoryLimit   string `yaml:"memory_limit,omitempty"`
 }
 
 type JobTaskWindow struct {
@@ -84,6 +101,12 @@ type JobTaskWindow struct {
 type JobHook struct {
 	Name   string
 	Config yaml.MapSlice `yaml:"config,omitempty"`
+
+	// hooks of the job this hook runs after
+	RunAfter []string `yaml:"run_after,omitempty"`
+
+	// retries of the hook, hooks are retried like the job if not set
+	Retry JobBehaviorRetry `yaml:"retry,omitempty"`
 }
 
 // ToSpec converts the local's JobHook representation to the optimus' models.JobSpecHook
@@ -92,17 +115,40 @@ func (a JobHook) ToSpec(pluginsRepo models.PluginRepository) (models.JobSpecHook
 	if err != nil {
 		return models.JobSpecHook{}, errors.Wrap(err, "spec reading error")
 	}
+	retryDelay := time.Duration(0)
+	if a.Retry.Delay != "" {
+		retryDelay, err = time.ParseDuration(a.Retry.Delay)
+		if err != nil {
+			return models.JobSpecHook{}, errors.Wrapf(err, "failed to parse retry delay of hook %s", a.Name)
+		}
+	}
 	return models.JobSpecHook{
-		Config: JobSpecConfigFromYamlSlice(a.Config),
-		Unit:   hookUnit,
+		Config:   JobSpecConfigFromYamlSlice(a.Config),
+		Unit:     hookUnit,
+		RunAfter: a.RunAfter,
+		Retry: models.JobSpecBehaviorRetry{
+			Count:              a.Retry.Count,
+			Delay:              retryDelay,
+			ExponentialBackoff: a.Retry.ExponentialBackoff,
+		},
 	}, nil
 }
 
 // FromSpec converts the optimus' models.JobSpecHook representation to the local's JobHook
 func (a JobHook) FromSpec(spec models.JobSpecHook) (JobHook, error) {
+	retryDelay := ""
+	if spec.Retry.Delay > 0 {
+		retryDelay = spec.Retry.Delay.String()
+	}
 	return JobHook{
-		Name:   spec.Unit.Info().Name,
-		Config: JobSpecConfigToYamlSlice(spec.Config),
+		Name:     spec.Unit.Info().Name,
+		Config:   JobSpecConfigToYamlSlice(spec.Config),
+		RunAfter: spec.RunAfter,
+		Retry: JobBehaviorRetry{
+			Count:              spec.Retry.Count,
+			Delay:              retryDelay,
+			ExponentialBackoff: spec.Retry.ExponentialBackoff,
+		},
 	}, nil
 }
 
@@ -118,12 +164,18 @@ func (conf *Job) MergeFrom(parent Job) {
 	if conf.Schedule.Interval == "" {
 		conf.Schedule.Interval = parent.Schedule.Interval
 	}
+	if conf.Schedule.Timezone == "" {
+		conf.Schedule.Timezone = parent.Schedule.Timezone
+	}
 	if conf.Schedule.StartDate == "" {
 		conf.Schedule.StartDate = parent.Schedule.StartDate
 	}
 	if conf.Schedule.EndDate == "" {
 		conf.Schedule.EndDate = parent.Schedule.EndDate
 	}
+	if conf.Schedule.SLADuration == "" {
+		conf.Schedule.SLADuration = parent.Schedule.SLADuration
+	}
 
 	if conf.Behavior.Retry.ExponentialBackoff == false {
 		conf.Behavior.Retry.ExponentialBackoff = parent.Behavior.Retry.ExponentialBackoff
@@ -231,6 +283,9 @@ func (conf *Job) MergeFrom(parent Job) {
 	if conf.Task.Window.Size == "" {
 		conf.Task.Window.Size = parent.Task.Window.Size
 	}
+	if conf.Task.Resources == (JobTaskResources{}) {
+		conf.Task.Resources = parent.Task.Resources
+	}
 	if parent.Task.Config != nil {
 		if conf.Task.Config == nil {
 			conf.Task.Config = []yaml.MapItem{}
@@ -273,6 +328,12 @@ func (conf *Job) MergeFrom(parent Job) {
 						conf.Hooks[chi].Config = append(conf.Hooks[chi].Config, phc)
 					}
 				}
+				if len(conf.Hooks[chi].RunAfter) == 0 {
+					conf.Hooks[chi].RunAfter = ph.RunAfter
+				}
+				if conf.Hooks[chi].Retry.Count == 0 {
+					conf.Hooks[chi].Retry = ph.Retry
+				}
 			}
 		}
 	}
@@ -280,8 +341,10 @@ func (conf *Job) MergeFrom(parent Job) {
 		// copy non existing hooks
 		if _, ok := existingHooks[ph.Name]; !ok {
 			conf.Hooks = append(conf.Hooks, JobHook{
-				Name:   ph.Name,
-				Config: append(yaml.MapSlice{}, ph.Config...),
+				Name:     ph.Name,
+				Config:   append(yaml.MapSlice{}, ph.Config...),
+				RunAfter: append([]string(nil), ph.RunAfter...),
+				Retry:    ph.Retry,
 			})
 		}
 	}
@@ -328,6 +391,9 @@ func (conf *Job) prepareWindow() (models.JobSpecTaskWindow, error) {
 type JobDependency struct {
 	JobName string `yaml:"job"`
 	Type    string `yaml:"type,omitempty"`
+
+	// Project of the job when it belongs to another project
+	Project string `yaml:"project,omitempty"`
 }
 
 type JobSpecAdapter struct {
@@ -355,6 +421,9 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 	dependencies := map[string]models.JobSpecDependency{}
 	for _, dep := range conf.Dependencies {
 		depType := models.JobSpecDependencyTypeIntra
+		if dep.Project != "" {
+			depType = models.JobSpecDependencyTypeInter
+		}
 		switch dep.Type {
 		case string(models.JobSpecDependencyTypeIntra):
 			depType = models.JobSpecDependencyTypeIntra
@@ -364,7 +433,8 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 			depType = models.JobSpecDependencyTypeExtra
 		}
 		dependencies[dep.JobName] = models.JobSpecDependency{
-			Type: depType,
+			Type:        depType,
+			ProjectName: dep.Project,
 		}
 	}
 
@@ -410,6 +480,14 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 		}
 	}
 
+	slaDuration := time.Duration(0)
+	if conf.Schedule.SLADuration != "" {
+		slaDuration, err = time.ParseDuration(conf.Schedule.SLADuration)
+		if err != nil {
+			return models.JobSpec{}, errors.Wrapf(err, "failed to parse sla duration %s", conf.Schedule.SLADuration)
+		}
+	}
+
 	var jobNotifiers []models.JobSpecNotifier
 	for _, notify := range conf.Behavior.Notify {
 		jobNotifiers = append(jobNotifiers, models.JobSpecNotifier{
@@ -429,6 +507,7 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 			StartDate: startDate,
 			EndDate:   endDate,
 			Interval:  conf.Schedule.Interval,
+			Timezone:  conf.Schedule.Timezone,
 		},
 		Behavior: models.JobSpecBehavior{
 			CatchUp:       conf.Behavior.Catchup,
@@ -444,10 +523,19 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 			Unit:   execUnit,
 			Config: taskConf,
 			Window: window,
+			Resources: models.JobSpecTaskResources{
+				CPURequest:    conf.Task.Resources.CPURequest,
+				CPULimit:      conf.Task.Resources.CPULimit,
+				MemoryRequest: conf.Task.Resources.MemoryRequest,
+				MemoryLimit:   conf.Task.Resources.MemoryLimit,
+			},
 		},
 		Assets:       models.JobAssets{}.FromMap(conf.Asset),
 		Dependencies: dependencies,
 		Hooks:        hooks,
+		Extends:      conf.Extends,
+		IsTemplate:   conf.IsTemplate,
+		SLADuration:  slaDuration,
 	}
 	return job, nil
 }
@@ -492,6 +580,7 @@ func (adapt JobSpecAdapter) FromSpec(spec models.JobSpec) (Job, error) {
 		Labels:      labels,
 		Schedule: JobSchedule{
 			Interval:  spec.Schedule.Interval,
+			Timezone:  spec.Schedule.Timezone,
 			StartDate: spec.Schedule.StartDate.Format(models.JobDatetimeLayout),
 		},
 		Behavior: JobBehavior{
@@ -512,19 +601,31 @@ func (adapt JobSpecAdapter) FromSpec(spec models.JobSpec) (Job, error) {
 				Offset:     spec.Task.Window.OffsetString(),
 				TruncateTo: spec.Task.Window.TruncateTo,
 			},
+			Resources: JobTaskResources{
+				CPURequest:    spec.Task.Resources.CPURequest,
+				CPULimit:      spec.Task.Resources.CPULimit,
+				MemoryRequest: spec.Task.Resources.MemoryRequest,
+				MemoryLimit:   spec.Task.Resources.MemoryLimit,
+			},
 		},
 		Asset:        spec.Assets.ToMap(),
 		Dependencies: []JobDependency{},
 		Hooks:        []JobHook{},
+		Extends:      spec.Extends,
+		IsTemplate:   spec.IsTemplate,
 	}
 
 	if spec.Schedule.EndDate != nil {
 		parsed.Schedule.EndDate = spec.Schedule.EndDate.Format(models.JobDatetimeLayout)
 	}
+	if spec.SLADuration > 0 {
+		parsed.Schedule.SLADuration = spec.SLADuration.String()
+	}
 	for name, dep := range spec.Dependencies {
 		parsed.Dependencies = append(parsed.Dependencies, JobDependency{
 			JobName: name,
 			Type:    dep.Type.String(),
+			Project: dep.ProjectName,
 		})
 	}
 
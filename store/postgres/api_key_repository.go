@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// APIKey is the postgres representation of an api key, the key itself is
+// never stored
+type APIKey struct {
+	ID uuid.UUID `gorm:"primary_key;type:uuid"`
+
+	KeyHash string `gorm:"not null"`
+
+	ProjectID uuid.UUID `gorm:"not null"`
+	Project   Project   `gorm:"foreignKey:ProjectID"`
+
+	Description string
+	Role        string `gorm:"not null"`
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	ExpiresAt *time.Time
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+type apiKeyRepository struct {
+	db      *gorm.DB
+	project models.ProjectSpec
+}
+
+func (repo *apiKeyRepository) Save(key models.APIKey, keyHash string) error {
+	return repo.db.Create(&APIKey{
+		ID:          key.ID,
+		KeyHash:     keyHash,
+		ProjectID:   repo.project.ID,
+		Description: key.Description,
+		Role:        key.Role.String(),
+		CreatedAt:   key.CreatedAt,
+		ExpiresAt:   key.ExpiresAt,
+	}).Error
+}
+
+// GetByHash returns the key of any project with the hash, expired keys are
+// returned as well
+func (repo *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (models.APIKey, error) {
+	var key APIKey
+	if err := repo.db.Preload("Project").Where("key_hash = ?", keyHash).Find(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.APIKey{}, store.ErrResourceNotFound
+		}
+		return models.APIKey{}, errors.Wrap(err, "failed to fetch api key")
+	}
+	role, err := models.ParseRole(key.Role)
+	if err != nil {
+		return models.APIKey{}, errors.Wrapf(err, "invalid role of api key %s", key.ID)
+	}
+	return models.APIKey{
+		ID:          key.ID,
+		ProjectName: key.Project.Name,
+		Description: key.Description,
+		Role:        role,
+		CreatedAt:   key.CreatedAt,
+		ExpiresAt:   key.ExpiresAt,
+	}, nil
+}
+
+// NewAPIKeyRepository creates a repository for the api keys of project, keys
+// of any project can be looked up by hash regardless
+func NewAPIKeyRepository(db *gorm.DB, project models.ProjectSpec) *apiKeyRepository {
+	return &apiKeyRepository{
+		db:      db,
+		project: project,
+	}
+}
@@ -0,0 +1,77 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyRepository(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "t-optimus-project",
+	}
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+
+	DBSetup := func() *gorm.DB {
+		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
+		if !ok {
+			panic("unable to find TEST_OPTIMUS_DB_URL env var")
+		}
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
+		if err != nil {
+			panic(err)
+		}
+		m, err := NewHTTPFSMigrator(dbURL)
+		if err != nil {
+			panic(err)
+		}
+		if err := m.Drop(); err != nil {
+			panic(err)
+		}
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
+			panic(err)
+		}
+
+		projRepo := NewProjectRepository(dbConn, hash)
+		assert.Nil(t, projRepo.Save(projectSpec))
+		return dbConn
+	}
+
+	t.Run("should look up saved keys of any project by hash", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		key := models.APIKey{
+			ID:          uuid.New(),
+			ProjectName: projectSpec.Name,
+			Description: "deploy pipeline",
+			Role:        models.RoleEditor,
+			CreatedAt:   time.Now().UTC().Truncate(time.Second),
+			ExpiresAt:   &expiresAt,
+		}
+		assert.Nil(t, NewAPIKeyRepository(db, projectSpec).Save(key, "a1b2c3"))
+
+		repo := NewAPIKeyRepository(db, models.ProjectSpec{})
+		saved, err := repo.GetByHash(context.Background(), "a1b2c3")
+		assert.Nil(t, err)
+		assert.Equal(t, key.ID, saved.ID)
+		assert.Equal(t, projectSpec.Name, saved.ProjectName)
+		assert.Equal(t, "deploy pipeline", saved.Description)
+		assert.Equal(t, models.RoleEditor, saved.Role)
+		assert.True(t, expiresAt.Equal(*saved.ExpiresAt))
+
+		_, err = repo.GetByHash(context.Background(), "d4e5f6")
+		assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+	})
+}
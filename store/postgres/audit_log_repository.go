@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+// AuditLog is an entry of the audit log, projects are referenced by name so
+// entries outlive the projects they belong to
+type AuditLog struct {
+	ID          int64     `gorm:"primary_key"`
+	CreatedAt   time.Time `gorm:"not null"`
+	Principal   string
+	Method      string `gorm:"not null"`
+	ProjectName string
+	Request     string
+	Status      string
+}
+
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func (repo *auditLogRepository) Save(ctx context.Context, entry models.AuditLogEntry) error {
+	return repo.db.Create(&AuditLog{
+		CreatedAt:   entry.Time,
+		Principal:   entry.Principal,
+		Method:      entry.Method,
+		ProjectName: entry.ProjectName,
+		Request:     entry.Request,
+		Status:      entry.Status,
+	}).Error
+}
+
+func (repo *auditLogRepository) GetAll(ctx context.Context, filter models.AuditLogFilter) ([]models.AuditLogEntry, error) {
+	query := repo.db
+	if filter.ProjectName != "" {
+		query = query.Where("project_name = ?", filter.ProjectName)
+	}
+	if filter.Principal != "" {
+		query = query.Where("principal = ?", filter.Principal)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("created_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("created_at < ?", filter.Until)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var logs []AuditLog
+	if err := query.Order("created_at DESC, id DESC").Find(&logs).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to fetch audit log")
+	}
+	entries := []models.AuditLogEntry{}
+	for _, log := range logs {
+		entries = append(entries, models.AuditLogEntry{
+			Time:        log.CreatedAt,
+			Principal:   log.Principal,
+			Method:      log.Method,
+			ProjectName: log.ProjectName,
+			Request:     log.Request,
+			Status:      log.Status,
+		})
+	}
+	return entries, nil
+}
+
+func NewAuditLogRepository(db *gorm.DB) *auditLogRepository {
+	return &auditLogRepository{
+		db: db,
+	}
+}
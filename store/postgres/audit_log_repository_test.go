@@ -0,0 +1,103 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLogRepository(t *testing.T) {
+	DBSetup := func() *gorm.DB {
+		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
+		if !ok {
+			panic("unable to find TEST_OPTIMUS_DB_URL env var")
+		}
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
+		if err != nil {
+			panic(err)
+		}
+		m, err := NewHTTPFSMigrator(dbURL)
+		if err != nil {
+			panic(err)
+		}
+		if err := m.Drop(); err != nil {
+			panic(err)
+		}
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
+			panic(err)
+		}
+		return dbConn
+	}
+
+	ctx := context.Background()
+	start := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	entries := []models.AuditLogEntry{
+		{
+			Time:        start,
+			Principal:   "user@example.io",
+			Method:      "/odpf.optimus.RuntimeService/RegisterProject",
+			ProjectName: "a-data-project",
+			Request:     `{"project":{"name":"a-data-project"}}`,
+			Status:      "OK",
+		},
+		{
+			Time:        start.Add(time.Hour),
+			Principal:   "admin@example.io",
+			Method:      "/odpf.optimus.RuntimeService/GrantProjectRole",
+			ProjectName: "a-data-project",
+			Status:      "OK",
+		},
+		{
+			Time:        start.Add(2 * time.Hour),
+			Principal:   "user@example.io",
+			Method:      "/odpf.optimus.RuntimeService/RegisterProject",
+			ProjectName: "other-project",
+			Status:      "PermissionDenied",
+		},
+	}
+
+	t.Run("GetAll", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		repo := NewAuditLogRepository(db)
+		for _, entry := range entries {
+			assert.Nil(t, repo.Save(ctx, entry))
+		}
+
+		t.Run("should list entries from the latest", func(t *testing.T) {
+			all, err := repo.GetAll(ctx, models.AuditLogFilter{})
+			assert.Nil(t, err)
+			assert.Equal(t, 3, len(all))
+			assert.Equal(t, "other-project", all[0].ProjectName)
+			assert.True(t, start.Equal(all[2].Time))
+			assert.Equal(t, entries[0].Request, all[2].Request)
+		})
+		t.Run("should filter entries by project, principal and time", func(t *testing.T) {
+			filtered, err := repo.GetAll(ctx, models.AuditLogFilter{
+				ProjectName: "a-data-project",
+				Principal:   "user@example.io",
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(filtered))
+
+			filtered, err = repo.GetAll(ctx, models.AuditLogFilter{
+				Since: start.Add(time.Hour),
+				Until: start.Add(2 * time.Hour),
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(filtered))
+			assert.Equal(t, "admin@example.io", filtered[0].Principal)
+
+			filtered, err = repo.GetAll(ctx, models.AuditLogFilter{Limit: 2})
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(filtered))
+		})
+	})
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"gorm.io/datatypes"
+)
+
+// DependencyPin is the postgres representation of a pinned upstream job,
+// DependencySpec holds the job row of the upstream at the time of pinning
+type DependencyPin struct {
+	ID uuid.UUID `gorm:"primary_key;type:uuid"`
+
+	JobID uuid.UUID `gorm:"not null"`
+	Job   Job       `gorm:"foreignKey:JobID"`
+
+	DependencyJobID uuid.UUID `gorm:"not null"`
+	DependencyJob   Job       `gorm:"foreignKey:DependencyJobID"`
+
+	SpecVersion    string `gorm:"not null"`
+	DependencySpec datatypes.JSON
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
+}
+
+func (DependencyPin) TableName() string {
+	return "dependency_pins"
+}
+
+type dependencyPinRepository struct {
+	db      *gorm.DB
+	project models.ProjectSpec
+	adapter *JobSpecAdapter
+}
+
+func (repo *dependencyPinRepository) getJob(name string) (Job, error) {
+	var r Job
+	if err := repo.db.Where("project_id = ? AND name = ?", repo.project.ID, name).Find(&r).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Job{}, errors.Wrapf(store.ErrResourceNotFound, "job %s", name)
+		}
+		return Job{}, err
+	}
+	return r, nil
+}
+
+func (repo *dependencyPinRepository) Pin(jobName, dependencyName, specVersion string) error {
+	job, err := repo.getJob(jobName)
+	if err != nil {
+		return err
+	}
+	dependencyJob, err := repo.getJob(dependencyName)
+	if err != nil {
+		return err
+	}
+
+	// relations are not part of the snapshot
+	snapshot := dependencyJob
+	snapshot.Project = Project{}
+	snapshot.Namespace = Namespace{}
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	var existing DependencyPin
+	err = repo.db.Where("job_id = ? AND dependency_job_id = ?", job.ID, dependencyJob.ID).Find(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repo.db.Create(&DependencyPin{
+			ID:              uuid.New(),
+			JobID:           job.ID,
+			DependencyJobID: dependencyJob.ID,
+			SpecVersion:     specVersion,
+			DependencySpec:  snapshotJSON,
+		}).Error
+	} else if err != nil {
+		return err
+	}
+	existing.SpecVersion = specVersion
+	existing.DependencySpec = snapshotJSON
+	return repo.db.Save(&existing).Error
+}
+
+func (repo *dependencyPinRepository) Delete(jobName, dependencyName string) error {
+	var pins []DependencyPin
+	if err := repo.projectPins().
+		Joins("JOIN job dependency_job ON dependency_job.id = dependency_pins.dependency_job_id").
+		Where("job.name = ? AND dependency_job.name = ?", jobName, dependencyName).
+		Find(&pins).Error; err != nil {
+		return err
+	}
+	if len(pins) == 0 {
+		return store.ErrResourceNotFound
+	}
+	return repo.db.Where("id = ?", pins[0].ID).Delete(&DependencyPin{}).Error
+}
+
+func (repo *dependencyPinRepository) GetByJob(jobName string) ([]models.DependencyPin, error) {
+	var pins []DependencyPin
+	if err := repo.projectPins().Where("job.name = ?", jobName).
+		Preload("Job").Preload("DependencyJob").Find(&pins).Error; err != nil {
+		return nil, err
+	}
+	return repo.toSpecs(pins)
+}
+
+func (repo *dependencyPinRepository) GetAll() ([]models.DependencyPin, error) {
+	var pins []DependencyPin
+	if err := repo.projectPins().Preload("Job").Preload("DependencyJob").Find(&pins).Error; err != nil {
+		return nil, err
+	}
+	return repo.toSpecs(pins)
+}
+
+// projectPins scopes the query to the pins of jobs in the project
+func (repo *dependencyPinRepository) projectPins() *gorm.DB {
+	return repo.db.Joins("JOIN job ON job.id = dependency_pins.job_id").
+		Where("job.project_id = ? AND job.deleted_at IS NULL", repo.project.ID).
+		Order("dependency_pins.created_at")
+}
+
+func (repo *dependencyPinRepository) toSpecs(pins []DependencyPin) ([]models.DependencyPin, error) {
+	var specs []models.DependencyPin
+	for _, pin := range pins {
+		var snapshot Job
+		if err := json.Unmarshal(pin.DependencySpec, &snapshot); err != nil {
+			return nil, errors.Wrapf(err, "failed to read pinned spec of %s", pin.DependencyJob.Name)
+		}
+		dependencySpec, err := repo.adapter.ToSpec(snapshot)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read pinned spec of %s", pin.DependencyJob.Name)
+		}
+		specs = append(specs, models.DependencyPin{
+			JobName:        pin.Job.Name,
+			DependencyName: pin.DependencyJob.Name,
+			SpecVersion:    pin.SpecVersion,
+			DependencySpec: dependencySpec,
+			CreatedAt:      pin.CreatedAt,
+		})
+	}
+	return specs, nil
+}
+
+// NewDependencyPinRepository creates a repository for the dependency pins of jobs in project
+func NewDependencyPinRepository(db *gorm.DB, project models.ProjectSpec, adapter *JobSpecAdapter) *dependencyPinRepository {
+	return &dependencyPinRepository{
+		db:      db,
+		project: project,
+		adapter: adapter,
+	}
+}
@@ -0,0 +1,142 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestDependencyPinRepository(t *testing.T) {
+	DBSetup := func() *gorm.DB {
+		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
+		if !ok {
+			panic("unable to find TEST_OPTIMUS_DB_URL env var")
+		}
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
+		if err != nil {
+			panic(err)
+		}
+		m, err := NewHTTPFSMigrator(dbURL)
+		if err != nil {
+			panic(err)
+		}
+		if err := m.Drop(); err != nil {
+			panic(err)
+		}
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
+			panic(err)
+		}
+		return dbConn
+	}
+
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "t-optimus-id",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "dev-team-1",
+		ProjectSpec: projectSpec,
+	}
+
+	gTask := "g-task"
+	execUnit := new(mock.BasePlugin)
+	execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+		Name: gTask,
+	}, nil)
+	depMod := new(mock.DependencyResolverMod)
+	depMod.On("GenerateDestination", context.TODO(), mock2.Anything).Return(&models.GenerateDestinationResponse{Destination: "p.d.t"}, nil)
+	pluginRepo := new(mock.SupportedPluginRepo)
+	pluginRepo.On("GetByName", gTask).Return(&models.Plugin{Base: execUnit, DependencyMod: depMod}, nil)
+	adapter := NewAdapter(pluginRepo)
+
+	newSpec := func(name, owner string) models.JobSpec {
+		return models.JobSpec{
+			ID:    uuid.Must(uuid.NewRandom()),
+			Name:  name,
+			Owner: owner,
+			Task: models.JobSpecTask{
+				Unit: &models.Plugin{Base: execUnit, DependencyMod: depMod},
+				Window: models.JobSpecTaskWindow{
+					Size:       time.Hour * 24,
+					TruncateTo: "d",
+				},
+			},
+		}
+	}
+	upstreamSpec := newSpec("upstream", "old-owner@mee")
+	downstreamSpec := newSpec("downstream", "mee@mee")
+
+	t.Run("Pin", func(t *testing.T) {
+		t.Run("should keep pinned spec after the upstream is updated", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+
+			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+			jobRepo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+			assert.Nil(t, jobRepo.Insert(upstreamSpec))
+			assert.Nil(t, jobRepo.Insert(downstreamSpec))
+
+			repo := NewDependencyPinRepository(db, projectSpec, adapter)
+			assert.Nil(t, repo.Pin(downstreamSpec.Name, upstreamSpec.Name, "v1"))
+
+			updatedUpstream := upstreamSpec
+			updatedUpstream.Owner = "new-owner@mee"
+			assert.Nil(t, jobRepo.Save(updatedUpstream))
+
+			pins, err := repo.GetByJob(downstreamSpec.Name)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(pins))
+			assert.Equal(t, upstreamSpec.Name, pins[0].DependencyName)
+			assert.Equal(t, "v1", pins[0].SpecVersion)
+			assert.Equal(t, "old-owner@mee", pins[0].DependencySpec.Owner)
+		})
+		t.Run("should replace the existing pin", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+
+			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+			jobRepo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+			assert.Nil(t, jobRepo.Insert(upstreamSpec))
+			assert.Nil(t, jobRepo.Insert(downstreamSpec))
+
+			repo := NewDependencyPinRepository(db, projectSpec, adapter)
+			assert.Nil(t, repo.Pin(downstreamSpec.Name, upstreamSpec.Name, "v1"))
+			assert.Nil(t, repo.Pin(downstreamSpec.Name, upstreamSpec.Name, "v2"))
+
+			pins, err := repo.GetAll()
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(pins))
+			assert.Equal(t, "v2", pins[0].SpecVersion)
+		})
+	})
+	t.Run("Delete", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+
+		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+		jobRepo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+		assert.Nil(t, jobRepo.Insert(upstreamSpec))
+		assert.Nil(t, jobRepo.Insert(downstreamSpec))
+
+		repo := NewDependencyPinRepository(db, projectSpec, adapter)
+		assert.Nil(t, repo.Pin(downstreamSpec.Name, upstreamSpec.Name, "v1"))
+		assert.Nil(t, repo.Delete(downstreamSpec.Name, upstreamSpec.Name))
+		assert.Equal(t, store.ErrResourceNotFound, repo.Delete(downstreamSpec.Name, upstreamSpec.Name))
+
+		pins, err := repo.GetAll()
+		assert.Nil(t, err)
+		assert.Empty(t, pins)
+	})
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+// deploymentLockPrefix namespaces advisory locks of project deployments
+const deploymentLockPrefix = "optimus.deployment/"
+
+// DeploymentState is the last time jobs of a project were deployed
+type DeploymentState struct {
+	ProjectID uuid.UUID `gorm:"primary_key;type:uuid"`
+	Project   Project   `gorm:"foreignKey:ProjectID"`
+
+	LastDeployedAt time.Time `gorm:"not null"`
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
+}
+
+func (DeploymentState) TableName() string {
+	return "deployment_state"
+}
+
+type deploymentStateRepository struct {
+	db      *gorm.DB
+	project models.ProjectSpec
+}
+
+func (repo *deploymentStateRepository) GetLastDeployedAt() (time.Time, error) {
+	var r DeploymentState
+	if err := repo.db.Where("project_id = ?", repo.project.ID).Find(&r).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	return r.LastDeployedAt, nil
+}
+
+func (repo *deploymentStateRepository) SetLastDeployedAt(deployedAt time.Time) error {
+	var existing DeploymentState
+	err := repo.db.Where("project_id = ?", repo.project.ID).Find(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repo.db.Create(&DeploymentState{
+			ProjectID:      repo.project.ID,
+			LastDeployedAt: deployedAt,
+		}).Error
+	}
+	if err != nil {
+		return err
+	}
+	return repo.db.Model(&existing).Update("last_deployed_at", deployedAt).Error
+}
+
+// TryLock takes a transaction level advisory lock keyed by the project, the
+// transaction is held until unlock so the lock also works through a
+// transaction mode pooler
+func (repo *deploymentStateRepository) TryLock(ctx context.Context) (func() error, bool, error) {
+	tx, err := repo.db.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	var locked bool
+	if err := tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock(hashtext($1))",
+		deploymentLockPrefix+repo.project.ID.String()).Scan(&locked); err != nil {
+		tx.Rollback()
+		return nil, false, err
+	}
+	if !locked {
+		return nil, false, tx.Rollback()
+	}
+	return tx.Rollback, true, nil
+}
+
+func NewDeploymentStateRepository(db *gorm.DB, project models.ProjectSpec) *deploymentStateRepository {
+	return &deploymentStateRepository{
+		db:      db,
+		project: project,
+	}
+}
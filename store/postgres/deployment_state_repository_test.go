@@ -0,0 +1,102 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeploymentStateRepository(t *testing.T) {
+	DBSetup := func() *gorm.DB {
+		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
+		if !ok {
+			panic("unable to find TEST_OPTIMUS_DB_URL env var")
+		}
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
+		if err != nil {
+			panic(err)
+		}
+		m, err := NewHTTPFSMigrator(dbURL)
+		if err != nil {
+			panic(err)
+		}
+		if err := m.Drop(); err != nil {
+			panic(err)
+		}
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
+			panic(err)
+		}
+		return dbConn
+	}
+
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "t-optimus-id",
+	}
+
+	t.Run("should return zero time if project was never deployed", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		assert.Nil(t, NewProjectRepository(db, hash).Save(projectSpec))
+
+		lastDeployedAt, err := NewDeploymentStateRepository(db, projectSpec).GetLastDeployedAt()
+		assert.Nil(t, err)
+		assert.True(t, lastDeployedAt.IsZero())
+	})
+	t.Run("should update last deploy time", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		assert.Nil(t, NewProjectRepository(db, hash).Save(projectSpec))
+		repo := NewDeploymentStateRepository(db, projectSpec)
+
+		firstDeploy := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+		assert.Nil(t, repo.SetLastDeployedAt(firstDeploy))
+		secondDeploy := firstDeploy.Add(5 * time.Minute)
+		assert.Nil(t, repo.SetLastDeployedAt(secondDeploy))
+
+		lastDeployedAt, err := repo.GetLastDeployedAt()
+		assert.Nil(t, err)
+		assert.True(t, secondDeploy.Equal(lastDeployedAt))
+	})
+	t.Run("should lock deployments of a project for one server at a time", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		assert.Nil(t, NewProjectRepository(db, hash).Save(projectSpec))
+		otherProjectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "t-optimus-other-id",
+		}
+		assert.Nil(t, NewProjectRepository(db, hash).Save(otherProjectSpec))
+		// each server locks with its own connection
+		db.DB().SetMaxOpenConns(3)
+		ctx := context.Background()
+
+		unlock, locked, err := NewDeploymentStateRepository(db, projectSpec).TryLock(ctx)
+		assert.Nil(t, err)
+		assert.True(t, locked)
+
+		_, locked, err = NewDeploymentStateRepository(db, projectSpec).TryLock(ctx)
+		assert.Nil(t, err)
+		assert.False(t, locked)
+
+		unlockOther, locked, err := NewDeploymentStateRepository(db, otherProjectSpec).TryLock(ctx)
+		assert.Nil(t, err)
+		assert.True(t, locked)
+		assert.Nil(t, unlockOther())
+
+		assert.Nil(t, unlock())
+		unlock, locked, err = NewDeploymentStateRepository(db, projectSpec).TryLock(ctx)
+		assert.Nil(t, err)
+		assert.True(t, locked)
+		assert.Nil(t, unlock())
+	})
+}
@@ -1,18 +1,25 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"time"
 
+	"github.com/odpf/optimus/core/tracing"
 	"github.com/odpf/optimus/store"
 
 	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
 	"github.com/odpf/optimus/models"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/datatypes"
 )
 
+// dbSystemAttribute identifies the database of spans around queries
+var dbSystemAttribute = attribute.String("db.system", "postgresql")
+
 type Instance struct {
 	ID uuid.UUID `gorm:"primary_key;type:uuid;"`
 
@@ -21,6 +28,7 @@ type Instance struct {
 
 	ScheduledAt *time.Time `gorm:"not null"`
 	State       string
+	StateReason string
 	Data        datatypes.JSON
 
 	CreatedAt time.Time `gorm:"not null" json:"created_at"`
@@ -45,6 +53,7 @@ func (j Instance) ToSpec(job models.JobSpec) (models.InstanceSpec, error) {
 		ID:          j.ID,
 		ScheduledAt: schdAt,
 		State:       j.State,
+		StateReason: j.StateReason,
 		Data:        data,
 		Job:         job,
 	}, nil
@@ -64,11 +73,14 @@ func (j Instance) FromSpec(spec models.InstanceSpec, job Job) (Instance, error)
 		ID:          spec.ID,
 		ScheduledAt: schdAt,
 		State:       spec.State,
+		StateReason: spec.StateReason,
 		Data:        dataJSON,
 		JobID:       job.ID,
 	}, nil
 }
 
+// instanceRepository methods taking a context check it before gorm queries,
+// the gorm version in use can't cancel a running query
 type instanceRepository struct {
 	db         *gorm.DB
 	job        models.JobSpec
@@ -130,6 +142,132 @@ func (repo *instanceRepository) GetByScheduledAt(scheduled time.Time) (models.In
 	return r.ToSpec(repo.job)
 }
 
+func (repo *instanceRepository) ListActiveInstances(ctx context.Context, maxAge time.Duration) (specs []models.InstanceSpec, err error) {
+	_, span := tracing.Start(ctx, "postgres.instanceRepository.ListActiveInstances", dbSystemAttribute)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var resources []Instance
+	if err := repo.db.Preload("Job").Preload("Job.Project").
+		Where("state = ? AND created_at > now() - (? * interval '1 second')", models.InstanceStateRunning, maxAge.Seconds()).
+		Order("created_at").Find(&resources).Error; err != nil {
+		return nil, err
+	}
+
+	for _, resource := range resources {
+		// job is only identified, adapting the complete spec requires its plugins
+		spec, err := resource.ToSpec(models.JobSpec{
+			ID:   resource.Job.ID,
+			Name: resource.Job.Name,
+		})
+		if err != nil {
+			return nil, err
+		}
+		spec.Project = models.ProjectSpec{
+			ID:   resource.Job.Project.ID,
+			Name: resource.Job.Project.Name,
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func (repo *instanceRepository) GetRunningDuration(ctx context.Context, jobSpec models.JobSpec) (_ time.Duration, err error) {
+	_, span := tracing.Start(ctx, "postgres.instanceRepository.GetRunningDuration", dbSystemAttribute)
+	defer func() { tracing.End(span, err) }()
+
+	var runningSecs int64
+	if err := repo.db.DB().QueryRowContext(ctx, `SELECT EXTRACT(EPOCH FROM now() - created_at)::int FROM instance
+		WHERE job_id = $1 AND state = $2 AND deleted_at IS NULL ORDER BY created_at DESC LIMIT 1`,
+		jobSpec.ID, models.InstanceStateRunning).Scan(&runningSecs); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, store.ErrResourceNotFound
+		}
+		return 0, err
+	}
+	return time.Duration(runningSecs) * time.Second, nil
+}
+
+func (repo *instanceRepository) AggregateByStatus(ctx context.Context, projectSpec models.ProjectSpec, window time.Duration) (_ map[string]int64, err error) {
+	_, span := tracing.Start(ctx, "postgres.instanceRepository.AggregateByStatus", dbSystemAttribute)
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := repo.db.DB().QueryContext(ctx, `SELECT instance.state, COUNT(*) FROM instance
+		JOIN job ON job.id = instance.job_id
+		WHERE job.project_id = $1 AND job.deleted_at IS NULL AND instance.created_at > now() - ($2 * interval '1 second') AND instance.deleted_at IS NULL
+		GROUP BY instance.state`, projectSpec.ID, window.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int64{}
+	for rows.Next() {
+		var state string
+		var count int64
+		if err := rows.Scan(&state, &count); err != nil {
+			return nil, err
+		}
+		counts[state] = count
+	}
+	return counts, rows.Err()
+}
+
+func (repo *instanceRepository) ListStaleInstances(ctx context.Context, projectSpec models.ProjectSpec, minAge time.Duration) (specs []models.InstanceSpec, err error) {
+	_, span := tracing.Start(ctx, "postgres.instanceRepository.ListStaleInstances", dbSystemAttribute)
+	defer func() { tracing.End(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var resources []Instance
+	if err := repo.db.Preload("Job").
+		Joins("JOIN job ON job.id = instance.job_id").
+		Where("job.project_id = ? AND job.deleted_at IS NULL AND instance.state = ? AND instance.created_at < now() - (? * interval '1 second')",
+			projectSpec.ID, models.InstanceStateRunning, minAge.Seconds()).
+		Order("instance.created_at").Find(&resources).Error; err != nil {
+		return nil, err
+	}
+
+	for _, resource := range resources {
+		// job is only identified, adapting the complete spec requires its plugins
+		spec, err := resource.ToSpec(models.JobSpec{
+			ID:   resource.Job.ID,
+			Name: resource.Job.Name,
+		})
+		if err != nil {
+			return nil, err
+		}
+		spec.Project = models.ProjectSpec{
+			ID:   projectSpec.ID,
+			Name: projectSpec.Name,
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func (repo *instanceRepository) UpdateState(ctx context.Context, id uuid.UUID, state, reason string) (err error) {
+	_, span := tracing.Start(ctx, "postgres.instanceRepository.UpdateState", dbSystemAttribute)
+	defer func() { tracing.End(span, err) }()
+
+	result := repo.db.Model(&Instance{ID: id}).Updates(map[string]interface{}{
+		"state":        state,
+		"state_reason": reason,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return store.ErrResourceNotFound
+	}
+	return nil
+}
+
 func NewInstanceRepository(db *gorm.DB, job models.JobSpec, jobAdapter *JobSpecAdapter) *instanceRepository {
 	return &instanceRepository{
 		db:         db,
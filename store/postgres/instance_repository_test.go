@@ -12,6 +12,7 @@ import (
 	"github.com/jinzhu/gorm"
 	"github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -98,7 +99,7 @@ func TestInstanceRepository(t *testing.T) {
 		if !ok {
 			panic("unable to find TEST_OPTIMUS_DB_URL env var")
 		}
-		dbConn, err := Connect(dbURL, 1, 1)
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
 		if err != nil {
 			panic(err)
 		}
@@ -109,7 +110,7 @@ func TestInstanceRepository(t *testing.T) {
 		if err := m.Drop(); err != nil {
 			panic(err)
 		}
-		if err := Migrate(dbURL); err != nil {
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
 			panic(err)
 		}
 
@@ -118,7 +119,7 @@ func TestInstanceRepository(t *testing.T) {
 		assert.Nil(t, prepo.Save(projectSpec))
 
 		projectJobSpecRepo := NewProjectJobSpecRepository(dbConn, projectSpec, adapter)
-		jrepo := NewJobSpecRepository(dbConn, namespaceSpec, projectJobSpecRepo, adapter)
+		jrepo := NewJobSpecRepository(dbConn, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 		assert.Nil(t, jrepo.Save(jobConfigs[0]))
 		assert.Equal(t, "task unit cannot be empty", jrepo.Save(jobConfigs[1]).Error())
 		return dbConn
@@ -148,7 +149,7 @@ func TestInstanceRepository(t *testing.T) {
 		testModels = append(testModels, testSpecs...)
 
 		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
-		jobRepo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+		jobRepo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 		err := jobRepo.Insert(testModels[0].Job)
 		assert.Nil(t, err)
 
@@ -214,4 +215,168 @@ func TestInstanceRepository(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, []models.InstanceSpecData{}, checkModel.Data)
 	})
+	t.Run("ListActiveInstances", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+
+		fixtures := []models.InstanceSpec{
+			{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Job:         jobConfigs[0],
+				State:       models.InstanceStateRunning,
+				ScheduledAt: time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Job:         jobConfigs[0],
+				State:       models.InstanceStateSuccess,
+				ScheduledAt: time.Date(2020, 11, 12, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Job:         jobConfigs[0],
+				State:       models.InstanceStateRunning,
+				ScheduledAt: time.Date(2020, 11, 13, 0, 0, 0, 0, time.UTC),
+			},
+		}
+		iRepo := NewInstanceRepository(db, jobConfigs[0], adapter)
+		for _, fixture := range fixtures {
+			assert.Nil(t, iRepo.Insert(fixture))
+		}
+		// last running instance was started long before the requested age
+		assert.Nil(t, db.Exec("UPDATE instance SET created_at = now() - interval '3 hours' WHERE id = ?", fixtures[2].ID).Error)
+
+		activeInstances, err := iRepo.ListActiveInstances(context.Background(), time.Hour)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(activeInstances))
+		assert.Equal(t, fixtures[0].ID, activeInstances[0].ID)
+		assert.Equal(t, jobConfigs[0].Name, activeInstances[0].Job.Name)
+		assert.Equal(t, projectSpec.Name, activeInstances[0].Project.Name)
+
+		activeInstances, err = iRepo.ListActiveInstances(context.Background(), 4*time.Hour)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(activeInstances))
+		assert.Equal(t, fixtures[2].ID, activeInstances[0].ID)
+	})
+	t.Run("ListStaleInstances", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+
+		fixtures := []models.InstanceSpec{
+			{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Job:         jobConfigs[0],
+				State:       models.InstanceStateRunning,
+				ScheduledAt: time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Job:         jobConfigs[0],
+				State:       models.InstanceStateSuccess,
+				ScheduledAt: time.Date(2020, 11, 12, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Job:         jobConfigs[0],
+				State:       models.InstanceStateRunning,
+				ScheduledAt: time.Date(2020, 11, 13, 0, 0, 0, 0, time.UTC),
+			},
+		}
+		iRepo := NewInstanceRepository(db, jobConfigs[0], adapter)
+		for _, fixture := range fixtures {
+			assert.Nil(t, iRepo.Insert(fixture))
+		}
+		assert.Nil(t, db.Exec("UPDATE instance SET created_at = now() - interval '3 hours' WHERE id IN (?, ?)",
+			fixtures[1].ID, fixtures[2].ID).Error)
+
+		staleInstances, err := iRepo.ListStaleInstances(context.Background(), projectSpec, 2*time.Hour)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(staleInstances))
+		assert.Equal(t, fixtures[2].ID, staleInstances[0].ID)
+		assert.Equal(t, jobConfigs[0].Name, staleInstances[0].Job.Name)
+
+		err = iRepo.UpdateState(context.Background(), fixtures[2].ID, models.InstanceStateFailed, models.InstanceStateReasonOrphaned)
+		assert.Nil(t, err)
+		failedInstance, err := iRepo.GetByScheduledAt(fixtures[2].ScheduledAt)
+		assert.Nil(t, err)
+		assert.Equal(t, models.InstanceStateFailed, failedInstance.State)
+		assert.Equal(t, models.InstanceStateReasonOrphaned, failedInstance.StateReason)
+
+		staleInstances, err = iRepo.ListStaleInstances(context.Background(), projectSpec, 2*time.Hour)
+		assert.Nil(t, err)
+		assert.Empty(t, staleInstances)
+
+		err = iRepo.UpdateState(context.Background(), uuid.Must(uuid.NewRandom()), models.InstanceStateFailed, "")
+		assert.Equal(t, store.ErrResourceNotFound, err)
+	})
+	t.Run("GetRunningDuration", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+
+		iRepo := NewInstanceRepository(db, jobConfigs[0], adapter)
+		_, err := iRepo.GetRunningDuration(context.Background(), jobConfigs[0])
+		assert.Equal(t, store.ErrResourceNotFound, err)
+
+		fixtures := []models.InstanceSpec{
+			{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Job:         jobConfigs[0],
+				State:       models.InstanceStateRunning,
+				ScheduledAt: time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Job:         jobConfigs[0],
+				State:       models.InstanceStateRunning,
+				ScheduledAt: time.Date(2020, 11, 12, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Job:         jobConfigs[0],
+				State:       models.InstanceStateSuccess,
+				ScheduledAt: time.Date(2020, 11, 13, 0, 0, 0, 0, time.UTC),
+			},
+		}
+		for _, fixture := range fixtures {
+			assert.Nil(t, iRepo.Insert(fixture))
+		}
+		assert.Nil(t, db.Exec("UPDATE instance SET created_at = now() - interval '3 hours' WHERE id = ?", fixtures[0].ID).Error)
+		assert.Nil(t, db.Exec("UPDATE instance SET created_at = now() - interval '90 minutes' WHERE id = ?", fixtures[1].ID).Error)
+
+		// latest running instance is considered, finished instances are ignored
+		duration, err := iRepo.GetRunningDuration(context.Background(), jobConfigs[0])
+		assert.Nil(t, err)
+		assert.InDelta(t, (90 * time.Minute).Seconds(), duration.Seconds(), 5)
+	})
+	t.Run("AggregateByStatus", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+
+		iRepo := NewInstanceRepository(db, jobConfigs[0], adapter)
+		counts, err := iRepo.AggregateByStatus(context.Background(), projectSpec, time.Hour)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]int64{}, counts)
+
+		states := []string{models.InstanceStateRunning, models.InstanceStateSuccess, models.InstanceStateSuccess, models.InstanceStateFailed}
+		scheduledAt := time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC)
+		for i := 0; i < 1000; i++ {
+			assert.Nil(t, iRepo.Insert(models.InstanceSpec{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Job:         jobConfigs[0],
+				State:       states[i%len(states)],
+				ScheduledAt: scheduledAt.Add(time.Duration(i) * time.Hour),
+			}))
+		}
+		// instances created before the window are not counted
+		assert.Nil(t, db.Exec(`UPDATE instance SET created_at = now() - interval '2 hours'
+			WHERE id IN (SELECT id FROM instance WHERE state = ? LIMIT 50)`, models.InstanceStateFailed).Error)
+
+		counts, err = iRepo.AggregateByStatus(context.Background(), projectSpec, time.Hour)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]int64{
+			models.InstanceStateRunning: 250,
+			models.InstanceStateSuccess: 500,
+			models.InstanceStateFailed:  200,
+		}, counts)
+	})
 }
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+// JobPause marks a job with its scheduled runs paused, jobs are referenced
+// by name as pausing doesn't change their specs
+type JobPause struct {
+	ProjectName string `gorm:"primary_key"`
+	JobName     string `gorm:"primary_key"`
+	Principal   string
+	PausedAt    time.Time `gorm:"not null"`
+}
+
+func (JobPause) TableName() string {
+	return "job_pause"
+}
+
+// JobPausePeriod keeps the times a job was paused after it is resumed, runs
+// scheduled while it was paused are not expected to exist
+type JobPausePeriod struct {
+	ProjectName string    `gorm:"primary_key"`
+	JobName     string    `gorm:"primary_key"`
+	PausedAt    time.Time `gorm:"primary_key"`
+	ResumedAt   *time.Time
+}
+
+func (JobPausePeriod) TableName() string {
+	return "job_pause_period"
+}
+
+type jobPauseRepository struct {
+	db *gorm.DB
+}
+
+// Save pauses or resumes all the jobs in a single transaction, pause periods
+// of jobs are opened and closed along
+func (repo *jobPauseRepository) Save(ctx context.Context, projectName string, jobNames []string, paused bool,
+	principal string, at time.Time) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_name = ? AND job_name IN (?)", projectName, jobNames).
+			Delete(&JobPause{}).Error; err != nil {
+			return err
+		}
+		if !paused {
+			return tx.Model(&JobPausePeriod{}).
+				Where("project_name = ? AND job_name IN (?) AND resumed_at IS NULL", projectName, jobNames).
+				Update("resumed_at", at).Error
+		}
+		for _, jobName := range jobNames {
+			// jobs paused again stay in the period they were first paused in
+			var openPeriods int
+			if err := tx.Model(&JobPausePeriod{}).
+				Where("project_name = ? AND job_name = ? AND resumed_at IS NULL", projectName, jobName).
+				Count(&openPeriods).Error; err != nil {
+				return err
+			}
+			if openPeriods == 0 {
+				if err := tx.Create(&JobPausePeriod{
+					ProjectName: projectName,
+					JobName:     jobName,
+					PausedAt:    at,
+				}).Error; err != nil {
+					return err
+				}
+			}
+
+			if err := tx.Create(&JobPause{
+				ProjectName: projectName,
+				JobName:     jobName,
+				Principal:   principal,
+				PausedAt:    at,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to save paused state of jobs of %s", projectName)
+	}
+	return nil
+}
+
+func (repo *jobPauseRepository) GetPaused(ctx context.Context, projectName string) ([]string, error) {
+	var pauses []JobPause
+	if err := repo.db.Where("project_name = ?", projectName).Order("job_name").Find(&pauses).Error; err != nil {
+		return nil, err
+	}
+	jobNames := []string{}
+	for _, pause := range pauses {
+		jobNames = append(jobNames, pause.JobName)
+	}
+	return jobNames, nil
+}
+
+func (repo *jobPauseRepository) GetPausePeriods(ctx context.Context, projectName, jobName string) ([]models.JobPausePeriod, error) {
+	var periods []JobPausePeriod
+	if err := repo.db.Where("project_name = ? AND job_name = ?", projectName, jobName).Order("paused_at").
+		Find(&periods).Error; err != nil {
+		return nil, err
+	}
+	pausePeriods := []models.JobPausePeriod{}
+	for _, period := range periods {
+		pausePeriods = append(pausePeriods, models.JobPausePeriod{
+			PausedAt:  period.PausedAt,
+			ResumedAt: period.ResumedAt,
+		})
+	}
+	return pausePeriods, nil
+}
+
+func NewJobPauseRepository(db *gorm.DB) *jobPauseRepository {
+	return &jobPauseRepository{
+		db: db,
+	}
+}
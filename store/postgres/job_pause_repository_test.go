@@ -0,0 +1,93 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobPauseRepository(t *testing.T) {
+	DBSetup := func() *gorm.DB {
+		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
+		if !ok {
+			panic("unable to find TEST_OPTIMUS_DB_URL env var")
+		}
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
+		if err != nil {
+			panic(err)
+		}
+		m, err := NewHTTPFSMigrator(dbURL)
+		if err != nil {
+			panic(err)
+		}
+		if err := m.Drop(); err != nil {
+			panic(err)
+		}
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
+			panic(err)
+		}
+		return dbConn
+	}
+
+	ctx := context.Background()
+	pausedAt := time.Date(2021, 3, 1, 2, 0, 0, 0, time.UTC)
+	t.Run("should pause and resume jobs of the project", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		repo := NewJobPauseRepository(db)
+
+		assert.Nil(t, repo.Save(ctx, "a-data-project", []string{"daily-orders", "hourly-events"}, true, "data@example.io", pausedAt))
+		assert.Nil(t, repo.Save(ctx, "another-project", []string{"daily-orders"}, true, "data@example.io", pausedAt))
+		paused, err := repo.GetPaused(ctx, "a-data-project")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"daily-orders", "hourly-events"}, paused)
+
+		assert.Nil(t, repo.Save(ctx, "a-data-project", []string{"daily-orders"}, false, "data@example.io", pausedAt))
+		paused, err = repo.GetPaused(ctx, "a-data-project")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"hourly-events"}, paused)
+	})
+	t.Run("should keep jobs paused when paused again", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		repo := NewJobPauseRepository(db)
+
+		assert.Nil(t, repo.Save(ctx, "a-data-project", []string{"daily-orders"}, true, "data@example.io", pausedAt))
+		assert.Nil(t, repo.Save(ctx, "a-data-project", []string{"daily-orders"}, true, "ops@example.io", pausedAt.Add(time.Hour)))
+
+		var pauses []JobPause
+		assert.Nil(t, db.Where("project_name = ?", "a-data-project").Find(&pauses).Error)
+		assert.Equal(t, 1, len(pauses))
+		assert.Equal(t, "ops@example.io", pauses[0].Principal)
+	})
+	t.Run("should keep the periods jobs were paused in", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		repo := NewJobPauseRepository(db)
+		resumedAt := pausedAt.Add(time.Hour * 24)
+		pausedAgainAt := resumedAt.Add(time.Hour * 24)
+
+		assert.Nil(t, repo.Save(ctx, "a-data-project", []string{"daily-orders"}, true, "data@example.io", pausedAt))
+		assert.Nil(t, repo.Save(ctx, "a-data-project", []string{"daily-orders"}, true, "ops@example.io", pausedAt.Add(time.Hour)))
+		assert.Nil(t, repo.Save(ctx, "a-data-project", []string{"daily-orders"}, false, "data@example.io", resumedAt))
+		assert.Nil(t, repo.Save(ctx, "a-data-project", []string{"daily-orders"}, true, "data@example.io", pausedAgainAt))
+
+		periods, err := repo.GetPausePeriods(ctx, "a-data-project", "daily-orders")
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(periods))
+		assert.True(t, pausedAt.Equal(periods[0].PausedAt))
+		assert.True(t, resumedAt.Equal(*periods[0].ResumedAt))
+		assert.True(t, pausedAgainAt.Equal(periods[1].PausedAt))
+		assert.Nil(t, periods[1].ResumedAt)
+
+		periods, err = repo.GetPausePeriods(ctx, "another-project", "daily-orders")
+		assert.Nil(t, err)
+		assert.Empty(t, periods)
+	})
+}
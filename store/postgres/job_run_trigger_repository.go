@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"gorm.io/datatypes"
+)
+
+// JobRunTrigger is a run of a job started on demand, projects and jobs are
+// referenced by name so triggers outlive the jobs they started
+type JobRunTrigger struct {
+	ID          int64     `gorm:"primary_key"`
+	RunID       string    `gorm:"not null"`
+	ProjectName string    `gorm:"not null"`
+	JobName     string    `gorm:"not null"`
+	LogicalDate time.Time `gorm:"not null"`
+	Config      datatypes.JSON
+	Principal   string
+	TriggeredAt time.Time `gorm:"not null"`
+}
+
+func (JobRunTrigger) TableName() string {
+	return "job_run_trigger"
+}
+
+type jobRunTriggerRepository struct {
+	db *gorm.DB
+}
+
+func (repo *jobRunTriggerRepository) Save(ctx context.Context, trigger models.JobRunTrigger) error {
+	configBytes, err := json.Marshal(trigger.Config)
+	if err != nil {
+		return err
+	}
+	if err := repo.db.Create(&JobRunTrigger{
+		RunID:       trigger.RunID,
+		ProjectName: trigger.ProjectName,
+		JobName:     trigger.JobName,
+		LogicalDate: trigger.LogicalDate,
+		Config:      configBytes,
+		Principal:   trigger.Principal,
+		TriggeredAt: trigger.TriggeredAt,
+	}).Error; err != nil {
+		return errors.Wrapf(err, "failed to save trigger of run %s of %s", trigger.RunID, trigger.JobName)
+	}
+	return nil
+}
+
+func NewJobRunTriggerRepository(db *gorm.DB) *jobRunTriggerRepository {
+	return &jobRunTriggerRepository{
+		db: db,
+	}
+}
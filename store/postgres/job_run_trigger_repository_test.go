@@ -0,0 +1,65 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobRunTriggerRepository(t *testing.T) {
+	DBSetup := func() *gorm.DB {
+		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
+		if !ok {
+			panic("unable to find TEST_OPTIMUS_DB_URL env var")
+		}
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
+		if err != nil {
+			panic(err)
+		}
+		m, err := NewHTTPFSMigrator(dbURL)
+		if err != nil {
+			panic(err)
+		}
+		if err := m.Drop(); err != nil {
+			panic(err)
+		}
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
+			panic(err)
+		}
+		return dbConn
+	}
+
+	ctx := context.Background()
+	t.Run("should save the trigger of a run", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		repo := NewJobRunTriggerRepository(db)
+
+		logicalDate := time.Date(2021, 3, 1, 2, 0, 0, 0, time.UTC)
+		assert.Nil(t, repo.Save(ctx, models.JobRunTrigger{
+			RunID:       "manual__2021-03-01T02:00:00Z",
+			ProjectName: "a-data-project",
+			JobName:     "daily-orders",
+			LogicalDate: logicalDate,
+			Config:      map[string]string{"backfill": "true"},
+			Principal:   "data@example.io",
+			TriggeredAt: logicalDate.Add(time.Hour),
+		}))
+
+		var triggers []JobRunTrigger
+		assert.Nil(t, db.Where("project_name = ?", "a-data-project").Find(&triggers).Error)
+		assert.Equal(t, 1, len(triggers))
+		assert.Equal(t, "manual__2021-03-01T02:00:00Z", triggers[0].RunID)
+		assert.Equal(t, "daily-orders", triggers[0].JobName)
+		assert.Equal(t, logicalDate, triggers[0].LogicalDate.UTC())
+		assert.JSONEq(t, `{"backfill": "true"}`, string(triggers[0].Config))
+		assert.Equal(t, "data@example.io", triggers[0].Principal)
+	})
+}
@@ -14,18 +14,20 @@ import (
 // Job are inputs from user to create a job
 // postgres representation of the job
 type Job struct {
-	ID           uuid.UUID `gorm:"primary_key;type:uuid;"`
-	Version      int
-	Name         string `gorm:"not null" json:"name"`
-	Owner        string
-	Description  string
-	Labels       datatypes.JSON
-	StartDate    time.Time
-	EndDate      *time.Time
-	Interval     string
-	Destination  string
-	Dependencies datatypes.JSON
-	Behavior     datatypes.JSON
+	ID            uuid.UUID `gorm:"primary_key;type:uuid;"`
+	Version       int
+	SchemaVersion int
+	Name          string `gorm:"not null" json:"name"`
+	Owner         string
+	Description   string
+	Labels        datatypes.JSON
+	StartDate     time.Time
+	EndDate       *time.Time
+	Interval      string
+	Timezone      string
+	Destination   string
+	Dependencies  datatypes.JSON
+	Behavior      datatypes.JSON
 
 	ProjectID uuid.UUID
 	Project   Project `gorm:"foreignKey:ProjectID"`
@@ -38,10 +40,19 @@ type Job struct {
 	WindowSize       *int64 //duration in nanos
 	WindowOffset     *int64
 	WindowTruncateTo *string
+	TaskResources    datatypes.JSON
 
 	Assets datatypes.JSON
 	Hooks  datatypes.JSON
 
+	// SpecChecksum is the checksum of the job spec at the time it was saved
+	SpecChecksum string
+
+	Extends    string
+	IsTemplate bool
+
+	SLADuration int64 //duration in nanos
+
 	CreatedAt time.Time `gorm:"not null" json:"created_at"`
 	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
 	DeletedAt *time.Time
@@ -54,6 +65,13 @@ type JobBehavior struct {
 	Notify        []JobBehaviorNotifier
 }
 
+type JobTaskResources struct {
+	CPURequest    string `json:",omitempty"`
+	CPULimit      string `json:",omitempty"`
+	MemoryRequest string `json:",omitempty"`
+	MemoryLimit   string `json:",omitempty"`
+}
+
 type JobBehaviorRetry struct {
 	Count              int
 	Delay              int64
@@ -86,8 +104,10 @@ func (a JobAsset) FromSpec(spec models.JobSpecAsset) JobAsset {
 }
 
 type JobHook struct {
-	Name   string
-	Config datatypes.JSON
+	Name     string
+	Config   datatypes.JSON
+	RunAfter []string `json:",omitempty"`
+	Retry    JobBehaviorRetry
 }
 
 // ToSpec converts the postgres' JobHook representation to the optimus' models.JobSpecHook
@@ -103,8 +123,14 @@ func (a JobHook) ToSpec(pluginRepo models.PluginRepository) (models.JobSpecHook,
 	}
 
 	return models.JobSpecHook{
-		Config: conf,
-		Unit:   hookUnit,
+		Config:   conf,
+		Unit:     hookUnit,
+		RunAfter: a.RunAfter,
+		Retry: models.JobSpecBehaviorRetry{
+			Count:              a.Retry.Count,
+			Delay:              time.Duration(a.Retry.Delay),
+			ExponentialBackoff: a.Retry.ExponentialBackoff,
+		},
 	}, nil
 }
 
@@ -114,8 +140,14 @@ func (a JobHook) FromSpec(spec models.JobSpecHook) (JobHook, error) {
 		return JobHook{}, err
 	}
 	return JobHook{
-		Name:   spec.Unit.Info().Name,
-		Config: configJSON,
+		Name:     spec.Unit.Info().Name,
+		Config:   configJSON,
+		RunAfter: spec.RunAfter,
+		Retry: JobBehaviorRetry{
+			Count:              spec.Retry.Count,
+			Delay:              spec.Retry.Delay.Nanoseconds(),
+			ExponentialBackoff: spec.Retry.ExponentialBackoff,
+		},
 	}, nil
 }
 
@@ -129,8 +161,41 @@ func NewAdapter(pluginRepo models.PluginRepository) *JobSpecAdapter {
 	}
 }
 
+// jobSchemaUpgrades upgrade a stored job to the next schema version, the
+// upgrade at index i converts a job of schema version i to version i+1
+var jobSchemaUpgrades = []func(Job) (Job, error){
+	// jobs stored before schema versioning was introduced have version 0
+	// and share the layout of version 1
+	func(conf Job) (Job, error) {
+		return conf, nil
+	},
+}
+
+// upgradeJobSchema applies the upgrades required to bring a stored job to
+// the current schema version
+func upgradeJobSchema(conf Job) (Job, error) {
+	if conf.SchemaVersion > models.JobSpecSchemaVersion {
+		return Job{}, errors.Wrapf(models.ErrSchemaVersionTooNew, "job %s has schema version %d, supported up to %d",
+			conf.Name, conf.SchemaVersion, models.JobSpecSchemaVersion)
+	}
+	for conf.SchemaVersion < models.JobSpecSchemaVersion {
+		upgraded, err := jobSchemaUpgrades[conf.SchemaVersion](conf)
+		if err != nil {
+			return Job{}, errors.Wrapf(err, "failed to upgrade job %s from schema version %d", conf.Name, conf.SchemaVersion)
+		}
+		upgraded.SchemaVersion = conf.SchemaVersion + 1
+		conf = upgraded
+	}
+	return conf, nil
+}
+
 // ToSpec converts the postgres' Job representation to the optimus' JobSpec
 func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
+	conf, err := upgradeJobSchema(conf)
+	if err != nil {
+		return models.JobSpec{}, err
+	}
+
 	labels := map[string]string{}
 	if conf.Labels != nil {
 		if err := json.Unmarshal(conf.Labels, &labels); err != nil {
@@ -156,6 +221,12 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 	if err := json.Unmarshal(conf.TaskConfig, &taskConf); err != nil {
 		return models.JobSpec{}, err
 	}
+	taskResources := JobTaskResources{}
+	if conf.TaskResources != nil {
+		if err := json.Unmarshal(conf.TaskResources, &taskResources); err != nil {
+			return models.JobSpec{}, err
+		}
+	}
 
 	//prep assets
 	jobAssets := []models.JobSpecAsset{}
@@ -196,16 +267,18 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 	}
 
 	job := models.JobSpec{
-		ID:          conf.ID,
-		Version:     conf.Version,
-		Name:        conf.Name,
-		Owner:       conf.Owner,
-		Description: conf.Description,
-		Labels:      labels,
+		ID:            conf.ID,
+		Version:       conf.Version,
+		SchemaVersion: conf.SchemaVersion,
+		Name:          conf.Name,
+		Owner:         conf.Owner,
+		Description:   conf.Description,
+		Labels:        labels,
 		Schedule: models.JobSpecSchedule{
 			StartDate: conf.StartDate,
 			EndDate:   conf.EndDate,
 			Interval:  conf.Interval,
+			Timezone:  conf.Timezone,
 		},
 		Behavior: models.JobSpecBehavior{
 			DependsOnPast: behavior.DependsOnPast,
@@ -225,10 +298,19 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 				Offset:     time.Duration(*conf.WindowOffset),
 				TruncateTo: *conf.WindowTruncateTo,
 			},
+			Resources: models.JobSpecTaskResources{
+				CPURequest:    taskResources.CPURequest,
+				CPULimit:      taskResources.CPULimit,
+				MemoryRequest: taskResources.MemoryRequest,
+				MemoryLimit:   taskResources.MemoryLimit,
+			},
 		},
 		Assets:       *(models.JobAssets{}).New(jobAssets),
 		Dependencies: dependencies,
 		Hooks:        jobHooks,
+		Extends:      conf.Extends,
+		IsTemplate:   conf.IsTemplate,
+		SLADuration:  time.Duration(conf.SLADuration),
 	}
 	return job, nil
 }
@@ -283,6 +365,15 @@ func (adapt JobSpecAdapter) FromSpec(spec models.JobSpec) (Job, error) {
 	if err != nil {
 		return Job{}, err
 	}
+	taskResourcesJSON, err := json.Marshal(JobTaskResources{
+		CPURequest:    spec.Task.Resources.CPURequest,
+		CPULimit:      spec.Task.Resources.CPULimit,
+		MemoryRequest: spec.Task.Resources.MemoryRequest,
+		MemoryLimit:   spec.Task.Resources.MemoryLimit,
+	})
+	if err != nil {
+		return Job{}, err
+	}
 
 	// prep assets
 	assets := []JobAsset{}
@@ -325,6 +416,7 @@ func (adapt JobSpecAdapter) FromSpec(spec models.JobSpec) (Job, error) {
 	return Job{
 		ID:               spec.ID,
 		Version:          spec.Version,
+		SchemaVersion:    models.JobSpecSchemaVersion,
 		Name:             spec.Name,
 		Owner:            spec.Owner,
 		Description:      spec.Description,
@@ -332,16 +424,21 @@ func (adapt JobSpecAdapter) FromSpec(spec models.JobSpec) (Job, error) {
 		StartDate:        spec.Schedule.StartDate,
 		EndDate:          spec.Schedule.EndDate,
 		Interval:         spec.Schedule.Interval,
+		Timezone:         spec.Schedule.Timezone,
 		Behavior:         behaviorJSON,
 		Destination:      jobDestination,
 		Dependencies:     dependenciesJSON,
 		TaskName:         spec.Task.Unit.Info().Name,
 		TaskConfig:       taskConfigJSON,
+		TaskResources:    taskResourcesJSON,
 		WindowSize:       &wsize,
 		WindowOffset:     &woffset,
 		WindowTruncateTo: &spec.Task.Window.TruncateTo,
 		Assets:           assetsJSON,
 		Hooks:            hooksJSON,
+		Extends:          spec.Extends,
+		IsTemplate:       spec.IsTemplate,
+		SLADuration:      int64(spec.SLADuration),
 	}, nil
 }
 
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+)
+
+func TestJobSpecAdapter(t *testing.T) {
+	taskName := "g-task"
+	execUnit := new(mock.BasePlugin)
+	execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+		Name: taskName,
+	}, nil)
+	pluginRepo := new(mock.SupportedPluginRepo)
+	pluginRepo.On("GetByName", taskName).Return(&models.Plugin{Base: execUnit}, nil)
+	adapter := NewAdapter(pluginRepo)
+
+	windowSize, windowOffset, windowTruncateTo := int64(0), int64(0), "d"
+	storedJob := func(schemaVersion int) Job {
+		return Job{
+			Name:             "job-1",
+			SchemaVersion:    schemaVersion,
+			Dependencies:     datatypes.JSON(`{}`),
+			TaskName:         taskName,
+			TaskConfig:       datatypes.JSON(`[]`),
+			WindowSize:       &windowSize,
+			WindowOffset:     &windowOffset,
+			WindowTruncateTo: &windowTruncateTo,
+			Assets:           datatypes.JSON(`[]`),
+			Hooks:            datatypes.JSON(`[]`),
+		}
+	}
+
+	t.Run("ToSpec", func(t *testing.T) {
+		t.Run("should upgrade jobs stored with an older schema version", func(t *testing.T) {
+			spec, err := adapter.ToSpec(storedJob(0))
+			assert.Nil(t, err)
+			assert.Equal(t, models.JobSpecSchemaVersion, spec.SchemaVersion)
+			assert.Equal(t, "job-1", spec.Name)
+		})
+		t.Run("should read jobs stored with the current schema version", func(t *testing.T) {
+			spec, err := adapter.ToSpec(storedJob(models.JobSpecSchemaVersion))
+			assert.Nil(t, err)
+			assert.Equal(t, models.JobSpecSchemaVersion, spec.SchemaVersion)
+		})
+		t.Run("should fail for jobs stored with a newer schema version", func(t *testing.T) {
+			_, err := adapter.ToSpec(storedJob(models.JobSpecSchemaVersion + 1))
+			assert.True(t, errors.Is(err, models.ErrSchemaVersionTooNew))
+		})
+	})
+	t.Run("FromSpec", func(t *testing.T) {
+		t.Run("should store jobs with the current schema version", func(t *testing.T) {
+			job, err := adapter.FromSpec(models.JobSpec{
+				Name: "job-1",
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{Base: execUnit},
+				},
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, models.JobSpecSchemaVersion, job.SchemaVersion)
+		})
+	})
+}
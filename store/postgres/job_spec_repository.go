@@ -1,7 +1,15 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
@@ -63,6 +71,76 @@ func (repo *ProjectJobSpecRepository) GetAll() ([]models.JobSpec, error) {
 	return specs, nil
 }
 
+// GetDependents finds jobs listing jobName in their dependencies, dependencies
+// are stored keyed by the name of the job depended on
+func (repo *ProjectJobSpecRepository) GetDependents(jobName string) ([]models.JobSpec, error) {
+	dependencyJSON, err := json.Marshal(map[string]struct{}{jobName: {}})
+	if err != nil {
+		return nil, err
+	}
+	specs := []models.JobSpec{}
+	jobs := []Job{}
+	if err := repo.db.Where("project_id = ? AND dependencies @> ?::jsonb", repo.project.ID, string(dependencyJSON)).
+		Order("name").Find(&jobs).Error; err != nil {
+		return specs, err
+	}
+
+	for _, job := range jobs {
+		adapt, err := repo.adapter.ToSpec(job)
+		if err != nil {
+			return specs, err
+		}
+		specs = append(specs, adapt)
+	}
+	return specs, nil
+}
+
+// GetModifiedSince returns job specs updated after since, deleted specs are
+// not returned
+func (repo *ProjectJobSpecRepository) GetModifiedSince(since time.Time) ([]models.JobSpec, map[string]models.NamespaceSpec, error) {
+	var jobs []Job
+	if err := repo.db.Preload("Namespace").Where("project_id = ? AND updated_at > ?", repo.project.ID, since).
+		Order("name").Find(&jobs).Error; err != nil {
+		return nil, nil, err
+	}
+
+	specs := []models.JobSpec{}
+	namespaces := map[string]models.NamespaceSpec{}
+	for _, job := range jobs {
+		jobSpec, err := repo.adapter.ToSpec(job)
+		if err != nil {
+			return nil, nil, err
+		}
+		namespaceSpec, err := job.Namespace.ToSpec(repo.project)
+		if err != nil {
+			return nil, nil, err
+		}
+		specs = append(specs, jobSpec)
+		namespaces[jobSpec.Name] = namespaceSpec
+	}
+	return specs, namespaces, nil
+}
+
+// GetDeletedSince returns the namespaces of job specs soft deleted after
+// since keyed by job name
+func (repo *ProjectJobSpecRepository) GetDeletedSince(since time.Time) (map[string]models.NamespaceSpec, error) {
+	var jobs []Job
+	if err := repo.db.Unscoped().Preload("Namespace").Where("project_id = ? AND deleted_at > ?", repo.project.ID, since).
+		Order("name").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	namespaces := map[string]models.NamespaceSpec{}
+	for _, job := range jobs {
+		namespaceSpec, err := job.Namespace.ToSpec(repo.project)
+		if err != nil {
+			return nil, err
+		}
+		namespaces[job.Name] = namespaceSpec
+	}
+	return namespaces, nil
+}
+
 func (repo *ProjectJobSpecRepository) GetByDestination(destination string) (models.JobSpec, models.ProjectSpec, error) {
 	var r Job
 	if err := repo.db.Preload("Project").Where("destination = ?", destination).Find(&r).Error; err != nil {
@@ -85,11 +163,229 @@ func (repo *ProjectJobSpecRepository) GetByDestination(destination string) (mode
 	return jSpec, pSpec, err
 }
 
+// exportJobSpecCSVQuery selects the columns of store.JobSpecCSVColumns, the
+// sla duration is read from the sla_miss notifier of the job behavior
+const exportJobSpecCSVQuery = `
+SELECT job.name,
+	COALESCE(job.task_name, ''),
+	COALESCE(job.owner, ''),
+	COALESCE(job.interval, ''),
+	COALESCE((
+		SELECT notifier->'Config'->>'duration'
+		FROM jsonb_array_elements(CASE WHEN jsonb_typeof(job.behavior->'Notify') = 'array'
+			THEN job.behavior->'Notify' ELSE '[]'::jsonb END) notifier
+		WHERE notifier->>'On' = $2
+		LIMIT 1
+	), ''),
+	COALESCE(job.labels::text, ''),
+	(
+		SELECT count(*)
+		FROM jsonb_object_keys(CASE WHEN jsonb_typeof(job.dependencies) = 'object'
+			THEN job.dependencies ELSE '{}'::jsonb END)
+	),
+	(
+		SELECT max(instance.scheduled_at)
+		FROM instance
+		WHERE instance.job_id = job.id AND instance.state = $3 AND instance.deleted_at IS NULL
+	),
+	job.created_at,
+	job.updated_at
+FROM job
+WHERE job.project_id = $1 AND job.deleted_at IS NULL
+ORDER BY job.name`
+
+// ExportCSV streams the job specs of the project as csv. lib/pq doesn't
+// support COPY TO STDOUT, rows are encoded as they are read instead so the
+// export is never held in memory. The reader must be closed to release the
+// query if it is not read till the end.
+func (repo *ProjectJobSpecRepository) ExportCSV(ctx context.Context) (io.ReadCloser, error) {
+	rows, err := repo.db.DB().QueryContext(ctx, exportJobSpecCSVQuery, repo.project.ID,
+		string(models.JobEventTypeSLAMiss), models.InstanceStateSuccess)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to export jobs of %s", repo.project.Name)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		defer rows.Close()
+		pw.CloseWithError(writeJobSpecCSV(pw, rows))
+	}()
+	return pr, nil
+}
+
+func writeJobSpecCSV(w io.Writer, rows *sql.Rows) error {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write(store.JobSpecCSVColumns); err != nil {
+		return err
+	}
+	for rows.Next() {
+		var (
+			name, taskName, owner, interval, slaDuration, labels string
+			dependenciesCount                                    int
+			lastSuccessfulRun                                    sql.NullTime
+			createdAt, updatedAt                                 time.Time
+		)
+		if err := rows.Scan(&name, &taskName, &owner, &interval, &slaDuration, &labels,
+			&dependenciesCount, &lastSuccessfulRun, &createdAt, &updatedAt); err != nil {
+			return err
+		}
+		lastRun := ""
+		if lastSuccessfulRun.Valid {
+			lastRun = lastSuccessfulRun.Time.UTC().Format(time.RFC3339)
+		}
+		if err := csvWriter.Write([]string{
+			name, taskName, owner, interval, slaDuration, labels, strconv.Itoa(dependenciesCount),
+			lastRun, createdAt.UTC().Format(time.RFC3339), updatedAt.UTC().Format(time.RFC3339),
+		}); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// CrossProjectJobSpecRepository reads and writes job specifications of all
+// projects
+type CrossProjectJobSpecRepository struct {
+	db      *gorm.DB
+	adapter *JobSpecAdapter
+	hasher  models.JobSpecHasher
+}
+
+func (repo *CrossProjectJobSpecRepository) GetJobsByOwner(ctx context.Context, owner string) ([]models.JobSpecWithNamespace, error) {
+	var jobs []Job
+	if err := repo.db.Select("job.*").Joins("JOIN project ON project.id = job.project_id").
+		Where("job.owner = ?", owner).Preload("Namespace").Preload("Project").
+		Order("project.name, job.name").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	var jobSpecs []models.JobSpecWithNamespace
+	for _, job := range jobs {
+		jobSpec, err := repo.adapter.ToSpec(job)
+		if err != nil {
+			return nil, err
+		}
+		// secrets of the projects are not read for listings
+		projectSpec, err := job.Project.ToSpec()
+		if err != nil {
+			return nil, err
+		}
+		namespaceSpec, err := job.Namespace.ToSpec(projectSpec)
+		if err != nil {
+			return nil, err
+		}
+		jobSpecs = append(jobSpecs, models.JobSpecWithNamespace{
+			Namespace: namespaceSpec,
+			Spec:      jobSpec,
+		})
+	}
+	return jobSpecs, nil
+}
+
+// PurgeDeleted removes the tombstones of job specs along with their
+// instances, pins of the jobs are removed by the database
+func (repo *CrossProjectJobSpecRepository) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	var purged int
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		deletedJobs := tx.Unscoped().Model(&Job{}).Select("id").
+			Where("deleted_at < now() - (? * interval '1 second')", olderThan.Seconds()).QueryExpr()
+		if err := tx.Unscoped().Where("job_id IN (?)", deletedJobs).Delete(&Instance{}).Error; err != nil {
+			return errors.Wrap(err, "failed to cascade delete instances of deleted jobs")
+		}
+		result := tx.Unscoped().Where("deleted_at < now() - (? * interval '1 second')", olderThan.Seconds()).Delete(&Job{})
+		if result.Error != nil {
+			return result.Error
+		}
+		purged = int(result.RowsAffected)
+		return nil
+	})
+	return purged, err
+}
+
+func (repo *CrossProjectJobSpecRepository) InsertAll(ctx context.Context, jobs []models.JobSpecWithNamespace) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		for _, job := range jobs {
+			resource, err := repo.adapter.FromSpecWithNamespace(job.Spec, job.Namespace)
+			if err != nil {
+				return err
+			}
+			if len(resource.Name) == 0 {
+				return errors.New("name cannot be empty")
+			}
+			if resource.SpecChecksum, err = repo.hasher.JobSpecChecksum(job.Spec); err != nil {
+				return errors.Wrapf(err, "failed to compute checksum of %s", job.Spec.Name)
+			}
+
+			var existing Job
+			err = tx.Unscoped().Where("project_id = ? AND name = ?", resource.ProjectID, resource.Name).Find(&existing).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.Wrapf(err, "failed to fetch job %s", resource.Name)
+			}
+			if err == nil {
+				if existing.DeletedAt == nil {
+					return errors.Wrapf(store.ErrResourceExists, "job %s of project %s", resource.Name, job.Namespace.ProjectSpec.Name)
+				}
+				// soft deleted jobs are replaced along with their instances
+				if err := tx.Unscoped().Where("job_id = ?", existing.ID).Delete(&Instance{}).Error; err != nil {
+					return errors.Wrap(err, "failed to cascade delete instances for the job")
+				}
+				if err := tx.Unscoped().Where("id = ?", existing.ID).Delete(&Job{}).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Create(&resource).Error; err != nil {
+				return errors.Wrapf(err, "failed to insert job %s", resource.Name)
+			}
+		}
+		return nil
+	})
+}
+
+func (repo *CrossProjectJobSpecRepository) GetCronExpression(ctx context.Context, projectSpec models.ProjectSpec, jobName string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	// interval is a keyword in postgres and has to be quoted
+	var intervals []string
+	if err := repo.db.Model(&Job{}).Where("project_id = ? AND name = ?", projectSpec.ID, jobName).
+		Pluck(`"interval"`, &intervals).Error; err != nil {
+		return "", err
+	}
+	if len(intervals) == 0 {
+		return "", store.ErrResourceNotFound
+	}
+	return intervals[0], nil
+}
+
+// NewCrossProjectJobSpecRepository creates a repository for job specs of
+// all projects
+func NewCrossProjectJobSpecRepository(db *gorm.DB, adapter *JobSpecAdapter, hasher models.JobSpecHasher) *CrossProjectJobSpecRepository {
+	return &CrossProjectJobSpecRepository{
+		db:      db,
+		adapter: adapter,
+		hasher:  hasher,
+	}
+}
+
 type JobSpecRepository struct {
 	db                 *gorm.DB
 	namespace          models.NamespaceSpec
 	projectJobSpecRepo store.ProjectJobSpecRepository
 	adapter            *JobSpecAdapter
+	hasher             models.JobSpecHasher
 }
 
 func (repo *JobSpecRepository) Insert(spec models.JobSpec) error {
@@ -100,6 +396,9 @@ func (repo *JobSpecRepository) Insert(spec models.JobSpec) error {
 	if len(resource.Name) == 0 {
 		return errors.New("name cannot be empty")
 	}
+	if resource.SpecChecksum, err = repo.hasher.JobSpecChecksum(spec); err != nil {
+		return errors.Wrapf(err, "failed to compute checksum of %s", spec.Name)
+	}
 	// if soft deleted earlier
 	if err := repo.HardDelete(spec.Name); err != nil {
 		return err
@@ -107,6 +406,69 @@ func (repo *JobSpecRepository) Insert(spec models.JobSpec) error {
 	return repo.db.Create(&resource).Error
 }
 
+// InsertAll saves new job specs of the namespace in bulk, none of the specs
+// are saved if any of them is already registered in the project. Soft
+// deleted jobs of the same names are replaced
+func (repo *JobSpecRepository) InsertAll(specs []models.JobSpec) error {
+	if len(specs) == 0 {
+		return nil
+	}
+	resources := make([]Job, len(specs))
+	names := make([]string, len(specs))
+	for i, spec := range specs {
+		resource, err := repo.adapter.FromSpecWithNamespace(spec, repo.namespace)
+		if err != nil {
+			return err
+		}
+		if len(resource.Name) == 0 {
+			return errors.New("name cannot be empty")
+		}
+		if resource.SpecChecksum, err = repo.hasher.JobSpecChecksum(spec); err != nil {
+			return errors.Wrapf(err, "failed to compute checksum of %s", spec.Name)
+		}
+		if resource.ID == uuid.Nil {
+			resource.ID = uuid.New()
+		}
+		resources[i] = resource
+		names[i] = resource.Name
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var existing []Job
+		if err := tx.Unscoped().Select("id, name, deleted_at").
+			Where("project_id = ? AND name IN (?)", repo.namespace.ProjectSpec.ID, names).
+			Find(&existing).Error; err != nil {
+			return errors.Wrap(err, "failed to fetch existing jobs")
+		}
+		var deletedJobIDs []uuid.UUID
+		for _, job := range existing {
+			if job.DeletedAt == nil {
+				return errors.Wrapf(store.ErrResourceExists, "job %s of project %s", job.Name, repo.namespace.ProjectSpec.Name)
+			}
+			deletedJobIDs = append(deletedJobIDs, job.ID)
+		}
+		if len(deletedJobIDs) > 0 {
+			if err := tx.Unscoped().Where("job_id IN (?)", deletedJobIDs).Delete(&Instance{}).Error; err != nil {
+				return errors.Wrap(err, "failed to cascade delete instances for the jobs")
+			}
+			if err := tx.Unscoped().Where("id IN (?)", deletedJobIDs).Delete(&Job{}).Error; err != nil {
+				return err
+			}
+		}
+
+		for start := 0; start < len(resources); start += jobBulkInsertSize {
+			end := start + jobBulkInsertSize
+			if end > len(resources) {
+				end = len(resources)
+			}
+			if err := bulkInsertJobs(tx, resources[start:end]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (repo *JobSpecRepository) Save(spec models.JobSpec) error {
 	// while saving a JobSpec, we need to ensure that it's name is unique for a project
 	existingJobSpec, namespaceSpec, err := repo.projectJobSpecRepo.GetByName(spec.Name)
@@ -125,8 +487,28 @@ func (repo *JobSpecRepository) Save(spec models.JobSpec) error {
 		return err
 	}
 	resource.ID = existingJobSpec.ID
+	if resource.SpecChecksum, err = repo.hasher.JobSpecChecksum(spec); err != nil {
+		return errors.Wrapf(err, "failed to compute checksum of %s", spec.Name)
+	}
 
-	return repo.db.Model(resource).Updates(resource).Error
+	// the spec being replaced is kept as a version of the job
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := archiveJobSpec(tx, existingJobSpec.ID, resource.SpecChecksum); err != nil {
+			return err
+		}
+		if err := tx.Model(resource).Updates(resource).Error; err != nil {
+			return err
+		}
+		// zero values are skipped by Updates, a job can stop extending a
+		// template, being one, being monitored for sla breaches or having
+		// a timezone
+		return tx.Model(resource).Updates(map[string]interface{}{
+			"extends":      resource.Extends,
+			"is_template":  resource.IsTemplate,
+			"sla_duration": resource.SLADuration,
+			"timezone":     resource.Timezone,
+		}).Error
+	})
 }
 
 func (repo *JobSpecRepository) GetByID(id uuid.UUID) (models.JobSpec, error) {
@@ -153,10 +535,71 @@ func (repo *JobSpecRepository) GetByName(name string) (models.JobSpec, error) {
 	return repo.adapter.ToSpec(r)
 }
 
+// GetSpecChecksum returns the checksum stored with the job spec, it is
+// computed for specs saved before checksums were stored
+func (repo *JobSpecRepository) GetSpecChecksum(jobName string) (string, error) {
+	var r Job
+	if err := repo.db.Where("namespace_id = ? AND name = ?", repo.namespace.ID, jobName).Find(&r).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", store.ErrResourceNotFound
+		}
+		return "", err
+	}
+	return repo.specChecksum(r)
+}
+
+// GetSpecChecksums returns the checksums of the job specs with jobNames read
+// at once, jobs that aren't stored are left out
+func (repo *JobSpecRepository) GetSpecChecksums(jobNames []string) (map[string]string, error) {
+	checksums := map[string]string{}
+	if len(jobNames) == 0 {
+		return checksums, nil
+	}
+	var jobs []Job
+	if err := repo.db.Where("namespace_id = ? AND name IN (?)", repo.namespace.ID, jobNames).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range jobs {
+		checksum, err := repo.specChecksum(r)
+		if err != nil {
+			return nil, err
+		}
+		checksums[r.Name] = checksum
+	}
+	return checksums, nil
+}
+
+func (repo *JobSpecRepository) specChecksum(r Job) (string, error) {
+	if r.SpecChecksum != "" {
+		return r.SpecChecksum, nil
+	}
+
+	jobSpec, err := repo.adapter.ToSpec(r)
+	if err != nil {
+		return "", err
+	}
+	return repo.hasher.JobSpecChecksum(jobSpec)
+}
+
 func (repo *JobSpecRepository) Delete(name string) error {
 	return repo.db.Where("namespace_id = ? AND name = ?", repo.namespace.ID, name).Delete(&Job{}).Error
 }
 
+// Undelete clears the deletion time of a soft deleted job spec, specs
+// deleted and created again with the same name can't be restored
+func (repo *JobSpecRepository) Undelete(name string) error {
+	result := repo.db.Unscoped().Model(&Job{}).
+		Where("namespace_id = ? AND name = ? AND deleted_at IS NOT NULL", repo.namespace.ID, name).
+		Update("deleted_at", gorm.Expr("NULL"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return store.ErrResourceNotFound
+	}
+	return nil
+}
+
 func (repo *JobSpecRepository) HardDelete(name string) error {
 	//find the base job
 	var r Job
@@ -190,11 +633,62 @@ func (repo *JobSpecRepository) GetAll() ([]models.JobSpec, error) {
 	return specs, nil
 }
 
-func NewJobSpecRepository(db *gorm.DB, namespace models.NamespaceSpec, projectJobSpecRepo store.ProjectJobSpecRepository, adapter *JobSpecAdapter) *JobSpecRepository {
+func (repo *JobSpecRepository) GetPage(afterName string, limit int, labels map[string]string) ([]models.JobSpec, error) {
+	specs := []models.JobSpec{}
+	jobs := []Job{}
+	query := repo.db.Where("namespace_id = ? AND name > ?", repo.namespace.ID, afterName)
+	if len(labels) > 0 {
+		labelsJSON, err := json.Marshal(labels)
+		if err != nil {
+			return specs, err
+		}
+		query = query.Where("labels @> ?::jsonb", string(labelsJSON))
+	}
+	if err := query.Order("name").Limit(limit).Find(&jobs).Error; err != nil {
+		return specs, err
+	}
+
+	for _, job := range jobs {
+		adapt, err := repo.adapter.ToSpec(job)
+		if err != nil {
+			return specs, err
+		}
+		specs = append(specs, adapt)
+	}
+	return specs, nil
+}
+
+func NewJobSpecRepository(db *gorm.DB, namespace models.NamespaceSpec, projectJobSpecRepo store.ProjectJobSpecRepository,
+	adapter *JobSpecAdapter, hasher models.JobSpecHasher) *JobSpecRepository {
 	return &JobSpecRepository{
 		db:                 db,
 		namespace:          namespace,
 		projectJobSpecRepo: projectJobSpecRepo,
 		adapter:            adapter,
+		hasher:             hasher,
+	}
+}
+
+// jobBulkInsertSize keeps the parameters of a bulk insert of jobs well under
+// the limit of postgres
+const jobBulkInsertSize = 500
+
+// bulkInsertJobs inserts jobs with a single multi row insert
+func bulkInsertJobs(tx *gorm.DB, jobs []Job) error {
+	now := time.Now()
+	rows := make([]string, len(jobs))
+	var values []interface{}
+	for i, job := range jobs {
+		rows[i] = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		values = append(values, job.ID, job.Version, job.SchemaVersion, job.Name, job.Owner, job.Description,
+			job.Labels, job.StartDate, job.EndDate, job.Interval, job.Timezone, job.Destination, job.Dependencies,
+			job.Behavior, job.ProjectID, job.NamespaceID, job.TaskName, job.TaskConfig, job.WindowSize,
+			job.WindowOffset, job.WindowTruncateTo, job.TaskResources, job.Assets, job.Hooks, job.SpecChecksum,
+			job.Extends, job.IsTemplate, job.SLADuration, now, now)
 	}
+	query := `INSERT INTO job (id, version, schema_version, name, owner, description, labels, start_date, end_date,
+"interval", timezone, destination, dependencies, behavior, project_id, namespace_id, task_name, task_config,
+window_size, window_offset, window_truncate_to, task_resources, assets, hooks, spec_checksum, extends, is_template,
+sla_duration, created_at, updated_at) VALUES ` + strings.Join(rows, ", ")
+	return tx.Exec(query, values...).Error
 }
@@ -4,24 +4,32 @@ package postgres
 
 import (
 	"context"
+	"encoding/csv"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
+	v1 "github.com/odpf/optimus/api/handler/v1"
 	"github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
 )
 
+// jobSpecHasher computes checksums of job specs saved in tests
+var jobSpecHasher = v1.NewAdapter(nil, nil)
+
 func TestJobRepository(t *testing.T) {
 	DBSetup := func() *gorm.DB {
 		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
 		if !ok {
 			panic("unable to find TEST_OPTIMUS_DB_URL env var")
 		}
-		dbConn, err := Connect(dbURL, 1, 1)
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
 		if err != nil {
 			panic(err)
 		}
@@ -32,7 +40,7 @@ func TestJobRepository(t *testing.T) {
 		if err := m.Drop(); err != nil {
 			panic(err)
 		}
-		if err := Migrate(dbURL); err != nil {
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
 			panic(err)
 		}
 		return dbConn
@@ -173,7 +181,7 @@ func TestJobRepository(t *testing.T) {
 			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
 			defer projectJobSpecRepo.AssertExpectations(t)
 
-			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 
 			err := repo.Insert(testModels[0])
 			assert.Nil(t, err)
@@ -217,7 +225,7 @@ func TestJobRepository(t *testing.T) {
 			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
 			defer projectJobSpecRepo.AssertExpectations(t)
 
-			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 
 			// first insert
 			err := repo.Insert(testModels[0])
@@ -272,7 +280,7 @@ func TestJobRepository(t *testing.T) {
 			defer depMod2.AssertExpectations(t)
 
 			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
-			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 
 			//try for create
 			err := repo.Save(testModelA)
@@ -313,7 +321,7 @@ func TestJobRepository(t *testing.T) {
 			defer depMod2.AssertExpectations(t)
 
 			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
-			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 
 			//try for create
 			testModelA.Task.Unit = &models.Plugin{Base: execUnit1, DependencyMod: depMod1}
@@ -348,7 +356,7 @@ func TestJobRepository(t *testing.T) {
 			testModelA.ID = uuid.Nil
 
 			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
-			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 
 			//try for create
 			err := repo.Save(testModelA)
@@ -369,7 +377,7 @@ func TestJobRepository(t *testing.T) {
 			}, nil)
 
 			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
-			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 
 			err := repo.Insert(testModel)
 			assert.Nil(t, err)
@@ -463,8 +471,8 @@ func TestJobRepository(t *testing.T) {
 			defer depMod1.AssertExpectations(t)
 
 			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
-			jobRepoNamespace1 := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
-			jobRepoNamespace2 := NewJobSpecRepository(db, namespaceSpec2, projectJobSpecRepo, adapter)
+			jobRepoNamespace1 := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+			jobRepoNamespace2 := NewJobSpecRepository(db, namespaceSpec2, projectJobSpecRepo, adapter, jobSpecHasher)
 
 			// try to create with first namespace
 			err := jobRepoNamespace1.Save(testModelA)
@@ -494,7 +502,7 @@ func TestJobRepository(t *testing.T) {
 			defer depMod1.AssertExpectations(t)
 
 			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
-			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 
 			//try for create
 			err := repo.Save(testModelA)
@@ -529,7 +537,7 @@ func TestJobRepository(t *testing.T) {
 		testModels = append(testModels, testConfigs...)
 
 		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
-		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 
 		err := repo.Insert(testModels[0])
 		assert.Nil(t, err)
@@ -540,6 +548,62 @@ func TestJobRepository(t *testing.T) {
 		assert.Equal(t, "this", checkModel.Task.Config[0].Value)
 	})
 
+	t.Run("GetSpecChecksum", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		testModels := []models.JobSpec{}
+		testModels = append(testModels, testConfigs...)
+
+		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+
+		err := repo.Insert(testModels[0])
+		assert.Nil(t, err)
+		checksum, err := repo.GetSpecChecksum(testModels[0].Name)
+		assert.Nil(t, err)
+		expected, _ := jobSpecHasher.JobSpecChecksum(testModels[0])
+		assert.Equal(t, expected, checksum)
+
+		updated := testModels[0]
+		updated.Owner = "new-owner@mee"
+		err = repo.Save(updated)
+		assert.Nil(t, err)
+		updatedChecksum, err := repo.GetSpecChecksum(testModels[0].Name)
+		assert.Nil(t, err)
+		assert.NotEqual(t, checksum, updatedChecksum)
+
+		_, err = repo.GetSpecChecksum("unknown")
+		assert.Equal(t, store.ErrResourceNotFound, err)
+	})
+
+	t.Run("GetSpecChecksums", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		testModels := []models.JobSpec{}
+		testModels = append(testModels, testConfigs...)
+
+		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+
+		err := repo.Insert(testModels[0])
+		assert.Nil(t, err)
+		err = repo.Insert(testModels[2])
+		assert.Nil(t, err)
+
+		checksums, err := repo.GetSpecChecksums([]string{testModels[0].Name, testModels[2].Name, "unknown"})
+		assert.Nil(t, err)
+		expected0, _ := jobSpecHasher.JobSpecChecksum(testModels[0])
+		expected2, _ := jobSpecHasher.JobSpecChecksum(testModels[2])
+		assert.Equal(t, map[string]string{
+			testModels[0].Name: expected0,
+			testModels[2].Name: expected2,
+		}, checksums)
+
+		checksums, err = repo.GetSpecChecksums(nil)
+		assert.Nil(t, err)
+		assert.Empty(t, checksums)
+	})
+
 	t.Run("GetAll", func(t *testing.T) {
 		db := DBSetup()
 		defer db.Close()
@@ -547,7 +611,7 @@ func TestJobRepository(t *testing.T) {
 		testModels = append(testModels, testConfigs...)
 
 		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
-		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 
 		err := repo.Insert(testModels[0])
 		assert.Nil(t, err)
@@ -558,6 +622,370 @@ func TestJobRepository(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, 2, len(checkModels))
 	})
+
+	t.Run("GetPage", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		testModels := []models.JobSpec{}
+		testModels = append(testModels, testConfigs...)
+
+		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+
+		err := repo.Insert(testModels[2])
+		assert.Nil(t, err)
+		err = repo.Insert(testModels[0])
+		assert.Nil(t, err)
+
+		firstPage, err := repo.GetPage("", 1, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(firstPage))
+		assert.Equal(t, "g-optimus-id", firstPage[0].Name)
+
+		nextPage, err := repo.GetPage(firstPage[0].Name, 1, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(nextPage))
+		assert.Equal(t, "t-optimus-id", nextPage[0].Name)
+
+		lastPage, err := repo.GetPage(nextPage[0].Name, 1, nil)
+		assert.Nil(t, err)
+		assert.Empty(t, lastPage)
+	})
+
+	t.Run("GetPage with labels", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		testModels := []models.JobSpec{}
+		testModels = append(testModels, testConfigs...)
+		testModels[0].Labels = map[string]string{"tier": "critical", "team": "data"}
+		testModels[2].Labels = map[string]string{"tier": "critical", "team": "growth"}
+
+		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+
+		err := repo.Insert(testModels[0])
+		assert.Nil(t, err)
+		err = repo.Insert(testModels[2])
+		assert.Nil(t, err)
+
+		criticalJobs, err := repo.GetPage("", 10, map[string]string{"tier": "critical"})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(criticalJobs))
+
+		dataJobs, err := repo.GetPage("", 10, map[string]string{"tier": "critical", "team": "data"})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(dataJobs))
+		assert.Equal(t, "g-optimus-id", dataJobs[0].Name)
+
+		noJobs, err := repo.GetPage("", 10, map[string]string{"tier": "low"})
+		assert.Nil(t, err)
+		assert.Empty(t, noJobs)
+	})
+
+	t.Run("Undelete", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		testModels := []models.JobSpec{}
+		testModels = append(testModels, testConfigs...)
+
+		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+
+		err := repo.Insert(testModels[0])
+		assert.Nil(t, err)
+
+		// restoring a job that isn't deleted should fail
+		err = repo.Undelete(testModels[0].Name)
+		assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+
+		err = repo.Delete(testModels[0].Name)
+		assert.Nil(t, err)
+		_, err = repo.GetByName(testModels[0].Name)
+		assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+
+		err = repo.Undelete(testModels[0].Name)
+		assert.Nil(t, err)
+
+		checkModel, err := repo.GetByName(testModels[0].Name)
+		assert.Nil(t, err)
+		assert.Equal(t, testModels[0].ID, checkModel.ID)
+	})
+	t.Run("InsertAll", func(t *testing.T) {
+		t.Run("should insert all jobs and replace soft deleted ones", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			testModels := []models.JobSpec{}
+			testModels = append(testModels, testConfigs...)
+
+			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+
+			err := repo.Insert(testModels[0])
+			assert.Nil(t, err)
+			err = repo.Delete(testModels[0].Name)
+			assert.Nil(t, err)
+
+			err = repo.InsertAll([]models.JobSpec{testModels[0], testModels[2]})
+			assert.Nil(t, err)
+
+			checkModel, err := repo.GetByName(testModels[0].Name)
+			assert.Nil(t, err)
+			assert.Equal(t, "query.sql", checkModel.Assets.GetAll()[0].Name)
+			assert.Equal(t, 1, len(checkModel.Hooks))
+
+			jobs, err := repo.GetAll()
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(jobs))
+		})
+		t.Run("should keep the sla duration of inserted jobs", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			withSLA := testConfigs[0]
+			withSLA.SLADuration = 90 * time.Minute
+
+			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+			err := repo.InsertAll([]models.JobSpec{withSLA, testConfigs[2]})
+			assert.Nil(t, err)
+
+			checkModel, err := repo.GetByName(withSLA.Name)
+			assert.Nil(t, err)
+			assert.Equal(t, 90*time.Minute, checkModel.SLADuration)
+		})
+		t.Run("should insert none of the jobs if one of them is registered in the project", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			testModels := []models.JobSpec{}
+			testModels = append(testModels, testConfigs...)
+
+			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+			err := repo.Insert(testModels[2])
+			assert.Nil(t, err)
+
+			otherRepo := NewJobSpecRepository(db, namespaceSpec2, projectJobSpecRepo, adapter, jobSpecHasher)
+			err = otherRepo.InsertAll([]models.JobSpec{testModels[0], testModels[2]})
+			assert.True(t, errors.Is(err, store.ErrResourceExists))
+
+			_, err = repo.GetByName(testModels[0].Name)
+			assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+		})
+	})
+}
+
+func TestCrossProjectJobSpecRepository(t *testing.T) {
+	DBSetup := func() *gorm.DB {
+		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
+		if !ok {
+			panic("unable to find TEST_OPTIMUS_DB_URL env var")
+		}
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
+		if err != nil {
+			panic(err)
+		}
+		m, err := NewHTTPFSMigrator(dbURL)
+		if err != nil {
+			panic(err)
+		}
+		if err := m.Drop(); err != nil {
+			panic(err)
+		}
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
+			panic(err)
+		}
+		return dbConn
+	}
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+
+	gTask := "g-task"
+	execUnit := new(mock.BasePlugin)
+	execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+		Name: gTask,
+	}, nil)
+	depMod := new(mock.DependencyResolverMod)
+	depMod.On("GenerateDestination", context.TODO(), mock2.Anything).Return(&models.GenerateDestinationResponse{Destination: "p.d.t"}, nil)
+	pluginRepo := new(mock.SupportedPluginRepo)
+	pluginRepo.On("GetByName", gTask).Return(&models.Plugin{Base: execUnit, DependencyMod: depMod}, nil)
+	adapter := NewAdapter(pluginRepo)
+
+	newSpec := func(name, owner string) models.JobSpec {
+		return models.JobSpec{
+			ID:    uuid.Must(uuid.NewRandom()),
+			Name:  name,
+			Owner: owner,
+			Task: models.JobSpecTask{
+				Unit: &models.Plugin{Base: execUnit, DependencyMod: depMod},
+				Window: models.JobSpecTaskWindow{
+					Size:       time.Hour * 24,
+					TruncateTo: "d",
+				},
+			},
+		}
+	}
+	// registers a project with a single namespace and returns its job repository
+	setupProject := func(db *gorm.DB, projectName, namespaceName string) *JobSpecRepository {
+		projectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: projectName,
+			Secret: models.ProjectSecrets{
+				{Name: "STORAGE", Value: "secret"},
+			},
+		}
+		namespaceSpec := models.NamespaceSpec{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Name:        namespaceName,
+			ProjectSpec: projectSpec,
+		}
+		assert.Nil(t, NewProjectRepository(db, hash).Save(projectSpec))
+		assert.Nil(t, NewNamespaceRepository(db, projectSpec, hash).Insert(namespaceSpec))
+		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+		return NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+	}
+
+	t.Run("GetJobsByOwner", func(t *testing.T) {
+		t.Run("should list jobs of the owner across projects", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			marketingRepo := setupProject(db, "marketing", "marketing-team")
+			financeRepo := setupProject(db, "finance", "finance-team")
+			assert.Nil(t, marketingRepo.Insert(newSpec("campaign-daily", "data@mee")))
+			assert.Nil(t, marketingRepo.Insert(newSpec("campaign-hourly", "other@mee")))
+			assert.Nil(t, financeRepo.Insert(newSpec("ledger-daily", "data@mee")))
+			assert.Nil(t, financeRepo.Insert(newSpec("ledger-removed", "data@mee")))
+			assert.Nil(t, financeRepo.Delete("ledger-removed"))
+
+			repo := NewCrossProjectJobSpecRepository(db, adapter, jobSpecHasher)
+			jobs, err := repo.GetJobsByOwner(context.Background(), "data@mee")
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(jobs))
+			assert.Equal(t, "ledger-daily", jobs[0].Spec.Name)
+			assert.Equal(t, "finance-team", jobs[0].Namespace.Name)
+			assert.Equal(t, "finance", jobs[0].Namespace.ProjectSpec.Name)
+			assert.Empty(t, jobs[0].Namespace.ProjectSpec.Secret)
+			assert.Equal(t, "campaign-daily", jobs[1].Spec.Name)
+			assert.Equal(t, "marketing", jobs[1].Namespace.ProjectSpec.Name)
+		})
+		t.Run("should return no jobs for an unknown owner", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			marketingRepo := setupProject(db, "marketing", "marketing-team")
+			assert.Nil(t, marketingRepo.Insert(newSpec("campaign-daily", "data@mee")))
+
+			repo := NewCrossProjectJobSpecRepository(db, adapter, jobSpecHasher)
+			jobs, err := repo.GetJobsByOwner(context.Background(), "nobody@mee")
+			assert.Nil(t, err)
+			assert.Empty(t, jobs)
+		})
+	})
+	t.Run("PurgeDeleted", func(t *testing.T) {
+		t.Run("should hard delete jobs soft deleted before the cutoff", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			marketingRepo := setupProject(db, "marketing", "marketing-team")
+			financeRepo := setupProject(db, "finance", "finance-team")
+			assert.Nil(t, marketingRepo.Insert(newSpec("campaign-daily", "data@mee")))
+			assert.Nil(t, marketingRepo.Insert(newSpec("campaign-removed", "data@mee")))
+			assert.Nil(t, financeRepo.Insert(newSpec("ledger-removed", "data@mee")))
+			assert.Nil(t, marketingRepo.Delete("campaign-removed"))
+			assert.Nil(t, financeRepo.Delete("ledger-removed"))
+
+			repo := NewCrossProjectJobSpecRepository(db, adapter, jobSpecHasher)
+			purged, err := repo.PurgeDeleted(context.Background(), 0)
+			assert.Nil(t, err)
+			assert.Equal(t, 2, purged)
+
+			// purged jobs can't be restored anymore
+			err = financeRepo.Undelete("ledger-removed")
+			assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+			_, err = marketingRepo.GetByName("campaign-daily")
+			assert.Nil(t, err)
+		})
+		t.Run("should keep jobs deleted after the cutoff", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			marketingRepo := setupProject(db, "marketing", "marketing-team")
+			assert.Nil(t, marketingRepo.Insert(newSpec("campaign-removed", "data@mee")))
+			assert.Nil(t, marketingRepo.Delete("campaign-removed"))
+
+			repo := NewCrossProjectJobSpecRepository(db, adapter, jobSpecHasher)
+			purged, err := repo.PurgeDeleted(context.Background(), 24*time.Hour)
+			assert.Nil(t, err)
+			assert.Equal(t, 0, purged)
+			assert.Nil(t, marketingRepo.Undelete("campaign-removed"))
+		})
+	})
+	t.Run("GetCronExpression", func(t *testing.T) {
+		t.Run("should return the schedule interval of the job spec", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			marketingRepo := setupProject(db, "marketing", "marketing-team")
+			spec := newSpec("campaign-daily", "data@mee")
+			spec.Schedule = models.JobSpecSchedule{
+				StartDate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				Interval:  "0 2 * * *",
+			}
+			assert.Nil(t, marketingRepo.Insert(spec))
+
+			repo := NewCrossProjectJobSpecRepository(db, adapter, jobSpecHasher)
+			cronExpression, err := repo.GetCronExpression(context.Background(), marketingRepo.namespace.ProjectSpec, "campaign-daily")
+			assert.Nil(t, err)
+			fullSpec, err := marketingRepo.GetByName("campaign-daily")
+			assert.Nil(t, err)
+			assert.Equal(t, fullSpec.Schedule.Interval, cronExpression)
+			assert.Equal(t, "0 2 * * *", cronExpression)
+		})
+		t.Run("should return not found for jobs of other projects and deleted jobs", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			marketingRepo := setupProject(db, "marketing", "marketing-team")
+			financeRepo := setupProject(db, "finance", "finance-team")
+			assert.Nil(t, financeRepo.Insert(newSpec("ledger-daily", "data@mee")))
+			assert.Nil(t, marketingRepo.Insert(newSpec("campaign-removed", "data@mee")))
+			assert.Nil(t, marketingRepo.Delete("campaign-removed"))
+
+			repo := NewCrossProjectJobSpecRepository(db, adapter, jobSpecHasher)
+			_, err := repo.GetCronExpression(context.Background(), marketingRepo.namespace.ProjectSpec, "ledger-daily")
+			assert.Equal(t, store.ErrResourceNotFound, err)
+			_, err = repo.GetCronExpression(context.Background(), marketingRepo.namespace.ProjectSpec, "campaign-removed")
+			assert.Equal(t, store.ErrResourceNotFound, err)
+		})
+	})
+	t.Run("InsertAll", func(t *testing.T) {
+		t.Run("should insert jobs of multiple namespaces", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			stagingRepo := setupProject(db, "staging", "staging-team")
+			assert.Nil(t, stagingRepo.Insert(newSpec("campaign-removed", "data@mee")))
+			assert.Nil(t, stagingRepo.Delete("campaign-removed"))
+
+			repo := NewCrossProjectJobSpecRepository(db, adapter, jobSpecHasher)
+			err := repo.InsertAll(context.Background(), []models.JobSpecWithNamespace{
+				{Namespace: stagingRepo.namespace, Spec: newSpec("campaign-daily", "data@mee")},
+				{Namespace: stagingRepo.namespace, Spec: newSpec("campaign-removed", "data@mee")},
+			})
+			assert.Nil(t, err)
+
+			jobs, err := stagingRepo.GetAll()
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(jobs))
+		})
+		t.Run("should insert none of the jobs if one of them exists", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			stagingRepo := setupProject(db, "staging", "staging-team")
+			assert.Nil(t, stagingRepo.Insert(newSpec("campaign-hourly", "data@mee")))
+
+			repo := NewCrossProjectJobSpecRepository(db, adapter, jobSpecHasher)
+			err := repo.InsertAll(context.Background(), []models.JobSpecWithNamespace{
+				{Namespace: stagingRepo.namespace, Spec: newSpec("campaign-daily", "data@mee")},
+				{Namespace: stagingRepo.namespace, Spec: newSpec("campaign-hourly", "data@mee")},
+			})
+			assert.True(t, errors.Is(err, store.ErrResourceExists))
+
+			_, err = stagingRepo.GetByName("campaign-daily")
+			assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+		})
+	})
 }
 
 func TestProjectJobRepository(t *testing.T) {
@@ -566,7 +994,7 @@ func TestProjectJobRepository(t *testing.T) {
 		if !ok {
 			panic("unable to find TEST_OPTIMUS_DB_URL env var")
 		}
-		dbConn, err := Connect(dbURL, 1, 1)
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
 		if err != nil {
 			panic(err)
 		}
@@ -577,7 +1005,7 @@ func TestProjectJobRepository(t *testing.T) {
 		if err := m.Drop(); err != nil {
 			panic(err)
 		}
-		if err := Migrate(dbURL); err != nil {
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
 			panic(err)
 		}
 		return dbConn
@@ -701,7 +1129,7 @@ func TestProjectJobRepository(t *testing.T) {
 		defer execUnit2.AssertExpectations(t)
 
 		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
-		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 
 		err := repo.Insert(testModels[0])
 		assert.Nil(t, err)
@@ -734,7 +1162,7 @@ func TestProjectJobRepository(t *testing.T) {
 		defer execUnit2.AssertExpectations(t)
 
 		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
-		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 
 		err := repo.Insert(testModels[0])
 		assert.Nil(t, err)
@@ -764,7 +1192,7 @@ func TestProjectJobRepository(t *testing.T) {
 		testModels = append(testModels, testConfigs...)
 
 		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
-		jobRepo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+		jobRepo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 		err := jobRepo.Insert(testModels[0])
 		assert.Nil(t, err)
 
@@ -773,4 +1201,183 @@ func TestProjectJobRepository(t *testing.T) {
 		assert.Equal(t, testConfigs[0].Name, j.Name)
 		assert.Equal(t, projectSpec.Name, p.Name)
 	})
+
+	t.Run("ExportCSV", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		testModels := []models.JobSpec{}
+		testModels = append(testModels, testConfigs...)
+
+		unitData1 := models.GenerateDestinationRequest{Config: models.PluginConfigs{}.FromJobSpec(testConfigs[0].Task.Config), Assets: models.PluginAssets{}.FromJobSpec(testConfigs[0].Assets)}
+		depMod.On("GenerateDestination", context.TODO(), unitData1).Return(&models.GenerateDestinationResponse{Destination: destination}, nil)
+
+		execUnit2.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name: tTask,
+		}, nil)
+		unitData2 := models.GenerateDestinationRequest{Config: models.PluginConfigs{}.FromJobSpec(testConfigs[2].Task.Config), Assets: models.PluginAssets{}.FromJobSpec(testConfigs[2].Assets)}
+		depMod2.On("GenerateDestination", context.TODO(), unitData2).Return(&models.GenerateDestinationResponse{Destination: destination}, nil)
+
+		defer depMod.AssertExpectations(t)
+		defer depMod2.AssertExpectations(t)
+		defer execUnit1.AssertExpectations(t)
+		defer execUnit2.AssertExpectations(t)
+
+		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+
+		err := repo.Insert(testModels[0])
+		assert.Nil(t, err)
+		err = repo.Insert(testModels[2])
+		assert.Nil(t, err)
+
+		export, err := projectJobSpecRepo.ExportCSV(context.Background())
+		assert.Nil(t, err)
+		records, err := csv.NewReader(export).ReadAll()
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(records))
+		assert.Equal(t, store.JobSpecCSVColumns, records[0])
+		assert.Equal(t, []string{testModels[0].Name, gTask}, records[1][:2])
+		assert.Equal(t, []string{testModels[2].Name, tTask}, records[2][:2])
+	})
+
+	t.Run("GetModifiedSince", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		testModels := []models.JobSpec{}
+		testModels = append(testModels, testConfigs...)
+
+		unitData1 := models.GenerateDestinationRequest{Config: models.PluginConfigs{}.FromJobSpec(testConfigs[0].Task.Config), Assets: models.PluginAssets{}.FromJobSpec(testConfigs[0].Assets)}
+		depMod.On("GenerateDestination", context.TODO(), unitData1).Return(&models.GenerateDestinationResponse{Destination: destination}, nil)
+
+		execUnit2.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name: tTask,
+		}, nil)
+		unitData2 := models.GenerateDestinationRequest{Config: models.PluginConfigs{}.FromJobSpec(testConfigs[2].Task.Config), Assets: models.PluginAssets{}.FromJobSpec(testConfigs[2].Assets)}
+		depMod2.On("GenerateDestination", context.TODO(), unitData2).Return(&models.GenerateDestinationResponse{Destination: destination}, nil)
+
+		defer depMod.AssertExpectations(t)
+		defer depMod2.AssertExpectations(t)
+		defer execUnit1.AssertExpectations(t)
+		defer execUnit2.AssertExpectations(t)
+
+		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+
+		err := repo.Insert(testModels[0])
+		assert.Nil(t, err)
+		since := time.Now()
+		time.Sleep(10 * time.Millisecond)
+		err = repo.Insert(testModels[2])
+		assert.Nil(t, err)
+
+		modified, namespaces, err := projectJobSpecRepo.GetModifiedSince(since)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(modified))
+		assert.Equal(t, testModels[2].Name, modified[0].Name)
+		assert.Equal(t, namespaceSpec.Name, namespaces[testModels[2].Name].Name)
+
+		modified, _, err = projectJobSpecRepo.GetModifiedSince(time.Now())
+		assert.Nil(t, err)
+		assert.Empty(t, modified)
+	})
+	t.Run("GetDeletedSince", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		testModels := []models.JobSpec{}
+		testModels = append(testModels, testConfigs...)
+
+		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+
+		assert.Nil(t, repo.Insert(testModels[0]))
+		assert.Nil(t, repo.Insert(testModels[2]))
+		assert.Nil(t, repo.Delete(testModels[0].Name))
+		since := time.Now()
+		time.Sleep(10 * time.Millisecond)
+		assert.Nil(t, repo.Delete(testModels[2].Name))
+
+		deleted, err := projectJobSpecRepo.GetDeletedSince(since)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(deleted))
+		assert.Equal(t, namespaceSpec.Name, deleted[testModels[2].Name].Name)
+
+		deleted, err = projectJobSpecRepo.GetDeletedSince(time.Now())
+		assert.Nil(t, err)
+		assert.Empty(t, deleted)
+	})
+	t.Run("GetDependents", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		testModels := []models.JobSpec{}
+		testModels = append(testModels, testConfigs...)
+		dependentSpec := testModels[2]
+		dependentSpec.Dependencies = map[string]models.JobSpecDependency{
+			testModels[0].Name: {Type: models.JobSpecDependencyTypeIntra},
+		}
+
+		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+
+		assert.Nil(t, repo.Insert(testModels[0]))
+		assert.Nil(t, repo.Insert(dependentSpec))
+
+		dependents, err := projectJobSpecRepo.GetDependents(testModels[0].Name)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(dependents))
+		assert.Equal(t, dependentSpec.Name, dependents[0].Name)
+
+		dependents, err = projectJobSpecRepo.GetDependents(dependentSpec.Name)
+		assert.Nil(t, err)
+		assert.Empty(t, dependents)
+	})
+	t.Run("GetVersions", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+		assert.Nil(t, NewProjectRepository(db, hash).Save(projectSpec))
+		assert.Nil(t, NewNamespaceRepository(db, projectSpec, hash).Insert(namespaceSpec))
+
+		unitData1 := models.GenerateDestinationRequest{Config: models.PluginConfigs{}.FromJobSpec(testConfigs[0].Task.Config), Assets: models.PluginAssets{}.FromJobSpec(testConfigs[0].Assets)}
+		depMod.On("GenerateDestination", context.TODO(), unitData1).Return(&models.GenerateDestinationResponse{Destination: destination}, nil)
+		defer depMod.AssertExpectations(t)
+		defer execUnit1.AssertExpectations(t)
+
+		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+		repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+
+		testModel := testConfigs[0]
+		testModel.Owner = "first@mee"
+		assert.Nil(t, repo.Save(testModel))
+		versions, err := projectJobSpecRepo.GetVersions(testModel.Name)
+		assert.Nil(t, err)
+		assert.Empty(t, versions)
+
+		// saving the same spec again isn't a change
+		assert.Nil(t, repo.Save(testModel))
+		testModel.Owner = "second@mee"
+		assert.Nil(t, repo.Save(testModel))
+		testModel.Owner = "third@mee"
+		assert.Nil(t, repo.Save(testModel))
+
+		versions, err = projectJobSpecRepo.GetVersions(testModel.Name)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(versions))
+		assert.Equal(t, 1, versions[0].Version)
+		assert.Equal(t, "first@mee", versions[0].Spec.Owner)
+		assert.Equal(t, 2, versions[1].Version)
+		assert.Equal(t, "second@mee", versions[1].Spec.Owner)
+		assert.Equal(t, namespaceSpec.Name, versions[1].Namespace.Name)
+		assert.False(t, versions[1].CreatedAt.Before(versions[0].CreatedAt))
+
+		version, err := projectJobSpecRepo.GetVersion(testModel.Name, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, "second@mee", version.Spec.Owner)
+
+		current, _, err := projectJobSpecRepo.GetByName(testModel.Name)
+		assert.Nil(t, err)
+		assert.Equal(t, "third@mee", current.Owner)
+
+		_, err = projectJobSpecRepo.GetVersion(testModel.Name, 3)
+		assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+	})
 }
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"gorm.io/datatypes"
+)
+
+// JobSpecVersion holds the job row of a job as it was before a change,
+// versions of a job are numbered from 1 within the project
+type JobSpecVersion struct {
+	ProjectID uuid.UUID `gorm:"primary_key;type:uuid"`
+	JobName   string    `gorm:"primary_key"`
+	Version   int       `gorm:"primary_key"`
+
+	NamespaceID uuid.UUID `gorm:"not null"`
+	Namespace   Namespace `gorm:"foreignKey:NamespaceID"`
+
+	SpecChecksum string
+	Spec         datatypes.JSON
+
+	// CreatedAt is the time the job was changed from this version
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (JobSpecVersion) TableName() string {
+	return "job_spec_versions"
+}
+
+// archiveJobSpec snapshots the job row as the next version of the job before
+// it gets updated to a spec with newChecksum, saving an unchanged spec
+// doesn't add a version
+func archiveJobSpec(tx *gorm.DB, jobID uuid.UUID, newChecksum string) error {
+	var current Job
+	// the row lock keeps version numbers of concurrent saves in order
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", jobID).Find(&current).Error; err != nil {
+		return errors.Wrap(err, "failed to fetch job to archive")
+	}
+	if current.SpecChecksum != "" && current.SpecChecksum == newChecksum {
+		return nil
+	}
+
+	var latest struct {
+		Version int
+	}
+	if err := tx.Raw(`SELECT COALESCE(MAX(version), 0) AS version FROM job_spec_versions WHERE project_id = ? AND job_name = ?`,
+		current.ProjectID, current.Name).Scan(&latest).Error; err != nil {
+		return errors.Wrapf(err, "failed to find latest version of %s", current.Name)
+	}
+
+	// relations are not part of the snapshot
+	snapshot := current
+	snapshot.Project = Project{}
+	snapshot.Namespace = Namespace{}
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&JobSpecVersion{
+		ProjectID:    current.ProjectID,
+		JobName:      current.Name,
+		Version:      latest.Version + 1,
+		NamespaceID:  current.NamespaceID,
+		SpecChecksum: current.SpecChecksum,
+		Spec:         snapshotJSON,
+	}).Error
+}
+
+// GetVersions returns the archived versions of a job ordered by version
+func (repo *ProjectJobSpecRepository) GetVersions(jobName string) ([]models.JobSpecVersion, error) {
+	var rows []JobSpecVersion
+	if err := repo.db.Where("project_id = ? AND job_name = ?", repo.project.ID, jobName).
+		Preload("Namespace").Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var versions []models.JobSpecVersion
+	for _, row := range rows {
+		version, err := repo.toJobSpecVersion(row)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// GetVersion returns a single archived version of a job
+func (repo *ProjectJobSpecRepository) GetVersion(jobName string, version int) (models.JobSpecVersion, error) {
+	var row JobSpecVersion
+	if err := repo.db.Where("project_id = ? AND job_name = ? AND version = ?", repo.project.ID, jobName, version).
+		Preload("Namespace").Find(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.JobSpecVersion{}, errors.Wrapf(store.ErrResourceNotFound, "version %d of %s", version, jobName)
+		}
+		return models.JobSpecVersion{}, err
+	}
+	return repo.toJobSpecVersion(row)
+}
+
+func (repo *ProjectJobSpecRepository) toJobSpecVersion(row JobSpecVersion) (models.JobSpecVersion, error) {
+	var snapshot Job
+	if err := json.Unmarshal(row.Spec, &snapshot); err != nil {
+		return models.JobSpecVersion{}, errors.Wrapf(err, "failed to read version %d of %s", row.Version, row.JobName)
+	}
+	jobSpec, err := repo.adapter.ToSpec(snapshot)
+	if err != nil {
+		return models.JobSpecVersion{}, errors.Wrapf(err, "failed to read version %d of %s", row.Version, row.JobName)
+	}
+	namespaceSpec, err := row.Namespace.ToSpec(repo.project)
+	if err != nil {
+		return models.JobSpecVersion{}, err
+	}
+	return models.JobSpecVersion{
+		Version:      row.Version,
+		Namespace:    namespaceSpec,
+		Spec:         jobSpec,
+		SpecChecksum: row.SpecChecksum,
+		CreatedAt:    row.CreatedAt,
+	}, nil
+}
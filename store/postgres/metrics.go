@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DBStatsExportInterval is how often connection pool stats are exported
+const DBStatsExportInterval = 15 * time.Second
+
+// DBStatsMetrics exports the connection pool stats of the database as gauges
+type DBStatsMetrics struct {
+	maxOpen      prometheus.Gauge
+	open         prometheus.Gauge
+	inUse        prometheus.Gauge
+	idle         prometheus.Gauge
+	waitCount    prometheus.Gauge
+	waitDuration prometheus.Gauge
+}
+
+// Export sets the gauges to the current stats of db
+func (m *DBStatsMetrics) Export(stats sql.DBStats) {
+	m.maxOpen.Set(float64(stats.MaxOpenConnections))
+	m.open.Set(float64(stats.OpenConnections))
+	m.inUse.Set(float64(stats.InUse))
+	m.idle.Set(float64(stats.Idle))
+	m.waitCount.Set(float64(stats.WaitCount))
+	m.waitDuration.Set(stats.WaitDuration.Seconds())
+}
+
+// Run exports the stats of db every interval until ctx is cancelled
+func (m *DBStatsMetrics) Run(ctx context.Context, db *sql.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.Export(db.Stats())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// NewDBStatsMetrics creates the connection pool gauges and registers them with reg
+func NewDBStatsMetrics(reg prometheus.Registerer) (*DBStatsMetrics, error) {
+	newGauge := func(name, help string) prometheus.Gauge {
+		return prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "optimus_db_" + name,
+			Help: help,
+		})
+	}
+	m := &DBStatsMetrics{
+		maxOpen:      newGauge("max_open_connections", "Maximum number of open connections to the database"),
+		open:         newGauge("open_connections", "Number of established connections, both in use and idle"),
+		inUse:        newGauge("in_use_connections", "Number of connections currently in use"),
+		idle:         newGauge("idle_connections", "Number of idle connections"),
+		waitCount:    newGauge("wait_count", "Total number of connections waited for"),
+		waitDuration: newGauge("wait_duration_seconds", "Total time blocked waiting for a new connection"),
+	}
+	for _, gauge := range []prometheus.Collector{m.maxOpen, m.open, m.inUse, m.idle, m.waitCount, m.waitDuration} {
+		if err := reg.Register(gauge); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS instance_state_created_at_idx;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS instance_state_created_at_idx ON instance (state, created_at);
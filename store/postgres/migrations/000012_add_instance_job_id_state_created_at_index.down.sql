@@ -0,0 +1 @@
+DROP INDEX IF EXISTS instance_job_id_state_created_at_idx;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS instance_job_id_state_created_at_idx ON instance (job_id, state, created_at);
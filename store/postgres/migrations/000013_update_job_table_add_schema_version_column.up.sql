@@ -0,0 +1 @@
+ALTER TABLE job ADD IF NOT EXISTS schema_version INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS dependency_pins (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  job_id UUID NOT NULL REFERENCES job (id) ON DELETE CASCADE,
+  dependency_job_id UUID NOT NULL REFERENCES job (id) ON DELETE CASCADE,
+  spec_version VARCHAR(100) NOT NULL,
+  dependency_spec JSONB NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+  UNIQUE (job_id, dependency_job_id)
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS deployment_state (
+  project_id UUID PRIMARY KEY REFERENCES project (id) ON DELETE CASCADE,
+  last_deployed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
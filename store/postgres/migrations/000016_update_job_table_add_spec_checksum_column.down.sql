@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS job_spec_checksum_idx;
+ALTER TABLE job DROP IF EXISTS spec_checksum;
@@ -0,0 +1,2 @@
+ALTER TABLE job ADD IF NOT EXISTS spec_checksum VARCHAR(64);
+CREATE INDEX IF NOT EXISTS job_spec_checksum_idx ON job (spec_checksum);
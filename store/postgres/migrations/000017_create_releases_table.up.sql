@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS releases (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  project_id UUID NOT NULL REFERENCES project (id) ON DELETE CASCADE,
+  tag VARCHAR(100) NOT NULL,
+  description TEXT,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+  UNIQUE (project_id, tag)
+);
+
+CREATE TABLE IF NOT EXISTS release_jobs (
+  release_id UUID NOT NULL REFERENCES releases (id) ON DELETE CASCADE,
+  job_name VARCHAR(220) NOT NULL,
+  namespace_id UUID NOT NULL REFERENCES namespace (id) ON DELETE CASCADE,
+  spec_checksum VARCHAR(64),
+  spec JSONB NOT NULL,
+
+  PRIMARY KEY (release_id, job_name)
+);
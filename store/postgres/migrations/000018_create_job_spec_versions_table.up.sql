@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS job_spec_versions (
+  project_id UUID NOT NULL REFERENCES project (id) ON DELETE CASCADE,
+  job_name VARCHAR(220) NOT NULL,
+  version INTEGER NOT NULL,
+  namespace_id UUID NOT NULL REFERENCES namespace (id) ON DELETE CASCADE,
+  spec_checksum VARCHAR(64),
+  spec JSONB NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+  PRIMARY KEY (project_id, job_name, version)
+);
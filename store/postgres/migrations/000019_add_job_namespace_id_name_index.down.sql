@@ -0,0 +1 @@
+DROP INDEX IF EXISTS job_namespace_id_name_idx;
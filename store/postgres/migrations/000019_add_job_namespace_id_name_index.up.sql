@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS job_namespace_id_name_idx ON job (namespace_id, name);
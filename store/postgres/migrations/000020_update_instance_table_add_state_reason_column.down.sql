@@ -0,0 +1 @@
+ALTER TABLE instance DROP IF EXISTS state_reason;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS secret_backup (
+  id UUID PRIMARY KEY,
+  secret_id UUID NOT NULL,
+  project_id UUID NOT NULL REFERENCES project (id) ON DELETE CASCADE,
+  name VARCHAR(100) NOT NULL,
+  value TEXT,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS secret_backup_project_id_idx ON secret_backup (project_id);
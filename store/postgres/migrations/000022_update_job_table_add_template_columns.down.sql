@@ -0,0 +1,2 @@
+ALTER TABLE job DROP IF EXISTS is_template;
+ALTER TABLE job DROP IF EXISTS extends;
@@ -0,0 +1,2 @@
+ALTER TABLE job ADD IF NOT EXISTS extends VARCHAR(220);
+ALTER TABLE job ADD IF NOT EXISTS is_template BOOLEAN NOT NULL DEFAULT FALSE;
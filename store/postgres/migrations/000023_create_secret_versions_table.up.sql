@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS secret_versions (
+  id UUID PRIMARY KEY,
+  project_id UUID NOT NULL REFERENCES project (id) ON DELETE CASCADE,
+  secret_name VARCHAR(100) NOT NULL,
+  encrypted_value TEXT,
+  version INTEGER NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS secret_versions_project_id_secret_name_version_idx ON secret_versions (project_id, secret_name, version);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS secret_expires_at_idx;
+ALTER TABLE secret DROP IF EXISTS expires_at;
@@ -0,0 +1,2 @@
+ALTER TABLE secret ADD IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS secret_expires_at_idx ON secret (expires_at);
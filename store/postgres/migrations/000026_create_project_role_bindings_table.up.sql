@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS project_role_bindings (
+  id UUID PRIMARY KEY,
+  project_id UUID NOT NULL REFERENCES project (id) ON DELETE CASCADE,
+  subject VARCHAR(250) NOT NULL,
+  role VARCHAR(20) NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS project_role_bindings_project_id_subject_idx ON project_role_bindings (project_id, subject);
@@ -0,0 +1 @@
+ALTER TABLE project ADD IF NOT EXISTS variables JSONB;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+  id BIGSERIAL PRIMARY KEY,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  principal VARCHAR(300),
+  method VARCHAR(200) NOT NULL,
+  project_name VARCHAR(100),
+  request TEXT,
+  status VARCHAR(30)
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at);
+CREATE INDEX IF NOT EXISTS audit_log_project_name_created_at_idx ON audit_log (project_name, created_at);
+CREATE INDEX IF NOT EXISTS audit_log_principal_created_at_idx ON audit_log (principal, created_at);
@@ -0,0 +1 @@
+ALTER TABLE project DROP IF EXISTS webhooks;
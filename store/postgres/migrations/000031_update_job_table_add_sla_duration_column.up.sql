@@ -0,0 +1 @@
+ALTER TABLE job ADD IF NOT EXISTS sla_duration BIGINT NOT NULL DEFAULT 0;
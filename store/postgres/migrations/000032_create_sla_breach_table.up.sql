@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS sla_breach (
+  id BIGSERIAL PRIMARY KEY,
+  project_name VARCHAR(100) NOT NULL,
+  namespace_name VARCHAR(100),
+  job_name VARCHAR(220) NOT NULL,
+  scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  sla_duration BIGINT NOT NULL,
+  started_at TIMESTAMP WITH TIME ZONE,
+  detected_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  UNIQUE (project_name, job_name, scheduled_at)
+);
+
+CREATE INDEX IF NOT EXISTS sla_breach_project_name_scheduled_at_idx ON sla_breach (project_name, scheduled_at);
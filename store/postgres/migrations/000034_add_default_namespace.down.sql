@@ -0,0 +1,4 @@
+DELETE FROM namespace
+WHERE name = 'default'
+  AND id NOT IN (SELECT namespace_id FROM job)
+  AND id NOT IN (SELECT namespace_id FROM resource);
@@ -0,0 +1,4 @@
+INSERT INTO namespace (project_id, name, config, created_at, updated_at)
+SELECT id, 'default', '{}', NOW(), NOW() FROM project
+WHERE deleted_at IS NULL
+ON CONFLICT (project_id, name) DO NOTHING;
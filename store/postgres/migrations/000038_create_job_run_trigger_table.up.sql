@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS job_run_trigger (
+  id BIGSERIAL PRIMARY KEY,
+  run_id VARCHAR(250) NOT NULL,
+  project_name VARCHAR(100) NOT NULL,
+  job_name VARCHAR(220) NOT NULL,
+  logical_date TIMESTAMP WITH TIME ZONE NOT NULL,
+  config JSONB,
+  principal VARCHAR(300),
+  triggered_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS job_run_trigger_project_name_job_name_idx ON job_run_trigger (project_name, job_name);
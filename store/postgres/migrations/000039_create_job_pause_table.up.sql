@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS job_pause (
+  project_name VARCHAR(100) NOT NULL,
+  job_name VARCHAR(220) NOT NULL,
+  principal VARCHAR(300),
+  paused_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  PRIMARY KEY (project_name, job_name)
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS job_pause_period (
+  project_name VARCHAR(100) NOT NULL,
+  job_name VARCHAR(220) NOT NULL,
+  paused_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  resumed_at TIMESTAMP WITH TIME ZONE,
+  PRIMARY KEY (project_name, job_name, paused_at)
+);
+
+INSERT INTO job_pause_period (project_name, job_name, paused_at)
+SELECT project_name, job_name, paused_at FROM job_pause
+ON CONFLICT DO NOTHING;
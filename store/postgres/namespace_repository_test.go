@@ -18,7 +18,7 @@ func TestNamespaceRepository(t *testing.T) {
 		if !ok {
 			panic("unable to find TEST_OPTIMUS_DB_URL env var")
 		}
-		dbConn, err := Connect(dbURL, 1, 1)
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
 		if err != nil {
 			panic(err)
 		}
@@ -29,7 +29,7 @@ func TestNamespaceRepository(t *testing.T) {
 		if err := m.Drop(); err != nil {
 			panic(err)
 		}
-		if err := Migrate(dbURL); err != nil {
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
 			panic(err)
 		}
 
@@ -0,0 +1,265 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PoolerModeNone connects to postgres directly or through a session
+	// mode pooler, where a client keeps its server connection
+	PoolerModeNone = ""
+
+	// PoolerModePgBouncer connects through pgbouncer in transaction mode,
+	// consecutive transactions of a client can run on different server
+	// connections so nothing may outlive a transaction
+	PoolerModePgBouncer = "pgbouncer"
+
+	migrationsTable    = "schema_migrations"
+	migrationLockTable = "schema_migrations_lock"
+
+	// a lock older than this was left behind by a crashed migrator
+	migrationLockExpiry = 10 * time.Minute
+)
+
+var (
+	// sessionSettings are runtime parameters accepted in the connection url,
+	// pgbouncer rejects them as startup parameters so they are applied to
+	// each transaction with SET LOCAL instead
+	sessionSettings = []string{"idle_in_transaction_session_timeout"}
+
+	// queryTimeoutSettings would only be applied to the queries gorm wraps in
+	// a transaction, reads run on their own and would silently lose them, so
+	// they are refused in pgbouncer mode and have to be set on the role
+	queryTimeoutSettings = []string{"statement_timeout", "lock_timeout"}
+
+	// migrationLockTimeout bounds waiting for another migrator, the same
+	// way migrate bounds waiting for an advisory lock
+	migrationLockTimeout      = 15 * time.Second
+	migrationLockPollInterval = 500 * time.Millisecond
+)
+
+type sessionSetting struct {
+	name  string
+	value string
+}
+
+func (s sessionSetting) setLocal() string {
+	return fmt.Sprintf("SET LOCAL %s = %s", s.name, pq.QuoteLiteral(s.value))
+}
+
+func validatePoolerMode(poolerMode string) error {
+	switch poolerMode {
+	case PoolerModeNone, PoolerModePgBouncer:
+		return nil
+	}
+	return errors.Errorf("unsupported db pooler mode: %s", poolerMode)
+}
+
+// pgbouncerConnURL moves session settings out of the connection url and
+// makes lib/pq send parameters along with the query, avoiding the separate
+// prepare round trip whose statement may land on another server connection
+func pgbouncerConnURL(connURL string) (string, []sessionSetting, error) {
+	parsed, err := url.Parse(connURL)
+	if err != nil || (parsed.Scheme != "postgres" && parsed.Scheme != "postgresql") {
+		return "", nil, errors.New("pgbouncer pooler mode requires a postgres:// connection url")
+	}
+
+	query := parsed.Query()
+	for _, name := range queryTimeoutSettings {
+		if query.Get(name) != "" {
+			return "", nil, errors.Errorf("%s can't be applied to every query in pgbouncer pooler mode, "+
+				"set it on the database role with ALTER ROLE instead", name)
+		}
+	}
+	var settings []sessionSetting
+	for _, name := range sessionSettings {
+		if value := query.Get(name); value != "" {
+			settings = append(settings, sessionSetting{name: name, value: value})
+		}
+		query.Del(name)
+	}
+	query.Set("binary_parameters", "yes")
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), settings, nil
+}
+
+// registerSetLocalCallbacks applies session settings at the start of the
+// transaction gorm wraps creates, updates and deletes in
+func registerSetLocalCallbacks(db *gorm.DB, settings []sessionSetting) {
+	if len(settings) == 0 {
+		return
+	}
+	setLocal := func(scope *gorm.Scope) {
+		if scope.HasError() {
+			return
+		}
+		for _, setting := range settings {
+			if _, err := scope.SQLDB().Exec(setting.setLocal()); err != nil {
+				scope.Err(err)
+				return
+			}
+		}
+	}
+	db.Callback().Create().After("gorm:begin_transaction").Register("optimus:set_local", setLocal)
+	db.Callback().Update().After("gorm:begin_transaction").Register("optimus:set_local", setLocal)
+	db.Callback().Delete().After("gorm:begin_transaction").Register("optimus:set_local", setLocal)
+}
+
+// tableLockDriver runs migrations through a transaction mode pooler. The
+// postgres driver of migrate holds an advisory lock on its session, which
+// pgbouncer can hand to another client and never release, so the lock is a
+// row of a table instead and every statement stands on its own
+type tableLockDriver struct {
+	db       *sql.DB
+	settings []sessionSetting
+	isLocked bool
+}
+
+func newTableLockDriver(db *sql.DB, settings []sessionSetting) (*tableLockDriver, error) {
+	driver := &tableLockDriver{
+		db:       db,
+		settings: settings,
+	}
+	for _, query := range []string{
+		`CREATE TABLE IF NOT EXISTS ` + migrationLockTable + ` (id int not null primary key, locked_at timestamptz not null)`,
+		`CREATE TABLE IF NOT EXISTS ` + migrationsTable + ` (version bigint not null primary key, dirty boolean not null)`,
+	} {
+		if _, err := db.Exec(query); err != nil {
+			return nil, &database.Error{OrigErr: err, Query: []byte(query)}
+		}
+	}
+	return driver, nil
+}
+
+func (d *tableLockDriver) Open(string) (database.Driver, error) {
+	return nil, errors.New("table lock driver is created with newTableLockDriver")
+}
+
+func (d *tableLockDriver) Close() error {
+	return d.db.Close()
+}
+
+func (d *tableLockDriver) Lock() error {
+	if d.isLocked {
+		return database.ErrLocked
+	}
+
+	deadline := time.Now().Add(migrationLockTimeout)
+	for {
+		query := `DELETE FROM ` + migrationLockTable + ` WHERE locked_at < now() - $1 * interval '1 second'`
+		if _, err := d.db.Exec(query, migrationLockExpiry.Seconds()); err != nil {
+			return &database.Error{OrigErr: err, Err: "try lock failed", Query: []byte(query)}
+		}
+
+		query = `INSERT INTO ` + migrationLockTable + ` (id, locked_at) VALUES (1, now()) ON CONFLICT DO NOTHING`
+		res, err := d.db.Exec(query)
+		if err != nil {
+			return &database.Error{OrigErr: err, Err: "try lock failed", Query: []byte(query)}
+		}
+		if acquired, err := res.RowsAffected(); err != nil {
+			return &database.Error{OrigErr: err, Err: "try lock failed", Query: []byte(query)}
+		} else if acquired == 1 {
+			d.isLocked = true
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return database.ErrLocked
+		}
+		time.Sleep(migrationLockPollInterval)
+	}
+}
+
+func (d *tableLockDriver) Unlock() error {
+	if !d.isLocked {
+		return nil
+	}
+
+	query := `DELETE FROM ` + migrationLockTable + ` WHERE id = 1`
+	if _, err := d.db.Exec(query); err != nil {
+		return &database.Error{OrigErr: err, Query: []byte(query)}
+	}
+	d.isLocked = false
+	return nil
+}
+
+func (d *tableLockDriver) Run(migration io.Reader) error {
+	migr, err := ioutil.ReadAll(migration)
+	if err != nil {
+		return err
+	}
+
+	return d.inTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(string(migr)); err != nil {
+			return database.Error{OrigErr: err, Err: "migration failed", Query: migr}
+		}
+		return nil
+	})
+}
+
+func (d *tableLockDriver) SetVersion(version int, dirty bool) error {
+	return d.inTx(func(tx *sql.Tx) error {
+		query := `TRUNCATE ` + migrationsTable
+		if _, err := tx.Exec(query); err != nil {
+			return &database.Error{OrigErr: err, Query: []byte(query)}
+		}
+
+		// same as migrate, a nil dirty version is kept so a failed first
+		// down migration isn't mistaken for an empty database
+		if version >= 0 || (version == database.NilVersion && dirty) {
+			query = `INSERT INTO ` + migrationsTable + ` (version, dirty) VALUES ($1, $2)`
+			if _, err := tx.Exec(query, version, dirty); err != nil {
+				return &database.Error{OrigErr: err, Query: []byte(query)}
+			}
+		}
+		return nil
+	})
+}
+
+func (d *tableLockDriver) Version() (version int, dirty bool, err error) {
+	query := `SELECT version, dirty FROM ` + migrationsTable + ` LIMIT 1`
+	err = d.db.QueryRow(query).Scan(&version, &dirty)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return database.NilVersion, false, nil
+	case err != nil:
+		return 0, false, &database.Error{OrigErr: err, Query: []byte(query)}
+	}
+	return version, dirty, nil
+}
+
+func (d *tableLockDriver) Drop() error {
+	return errors.New("dropping the database is not supported in pgbouncer pooler mode")
+}
+
+// inTx runs fn in a transaction started with the session settings
+func (d *tableLockDriver) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return &database.Error{OrigErr: err, Err: "transaction start failed"}
+	}
+	for _, setting := range d.settings {
+		if _, err := tx.Exec(setting.setLocal()); err != nil {
+			tx.Rollback()
+			return &database.Error{OrigErr: err, Query: []byte(setting.setLocal())}
+		}
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return &database.Error{OrigErr: err, Err: "transaction commit failed"}
+	}
+	return nil
+}
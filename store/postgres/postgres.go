@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"database/sql"
 	"embed"
 	"fmt"
 	"net/http"
@@ -33,7 +34,19 @@ func NewHTTPFSMigrator(DBConnURL string) (*migrate.Migrate, error) {
 }
 
 // Connect connect to the DB with custom configuration.
-func Connect(connURL string, maxIdleConnections, maxOpenConnections int) (*gorm.DB, error) {
+func Connect(connURL string, maxIdleConnections, maxOpenConnections int, poolerMode string) (*gorm.DB, error) {
+	if err := validatePoolerMode(poolerMode); err != nil {
+		return nil, err
+	}
+
+	var settings []sessionSetting
+	if poolerMode == PoolerModePgBouncer {
+		var err error
+		if connURL, settings, err = pgbouncerConnURL(connURL); err != nil {
+			return nil, err
+		}
+	}
+
 	var db *gorm.DB
 	var err error
 
@@ -44,12 +57,23 @@ func Connect(connURL string, maxIdleConnections, maxOpenConnections int) (*gorm.
 	db.DB().SetMaxIdleConns(maxIdleConnections)
 	db.DB().SetMaxOpenConns(maxOpenConnections)
 	db.SingularTable(true)
+	registerSetLocalCallbacks(db, settings)
 	return db, nil
 }
 
 // Migrate to run up migrations
-func Migrate(connURL string) error {
-	m, err := NewHTTPFSMigrator(connURL)
+func Migrate(connURL string, poolerMode string) error {
+	if err := validatePoolerMode(poolerMode); err != nil {
+		return err
+	}
+
+	var m *migrate.Migrate
+	var err error
+	if poolerMode == PoolerModePgBouncer {
+		m, err = newTableLockMigrator(connURL)
+	} else {
+		m, err = NewHTTPFSMigrator(connURL)
+	}
 	if err != nil {
 		return errors.Wrap(err, "db migrator")
 	}
@@ -60,3 +84,26 @@ func Migrate(connURL string) error {
 	}
 	return nil
 }
+
+// newTableLockMigrator reads the migrations from httpfs and runs them
+// without session state, for connections through pgbouncer
+func newTableLockMigrator(connURL string) (*migrate.Migrate, error) {
+	connURL, settings, err := pgbouncerConnURL(connURL)
+	if err != nil {
+		return nil, err
+	}
+	src, err := httpfs.New(http.FS(migrationFs), resourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("db migrator: %v", err)
+	}
+	sqlDB, err := sql.Open("postgres", connURL)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := newTableLockDriver(sqlDB, settings)
+	if err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return migrate.NewWithInstance("httpfs", src, "postgres", driver)
+}
@@ -1,7 +1,10 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/odpf/optimus/store"
@@ -18,6 +21,18 @@ type Project struct {
 	Name   string    `gorm:"not null;unique"`
 	Config datatypes.JSON
 
+	FeatureFlags datatypes.JSON
+
+	Webhooks datatypes.JSON
+
+	BlackoutWindows datatypes.JSON
+
+	// Variables are only saved by SaveVariables
+	Variables datatypes.JSON
+
+	// Quota is only saved by SaveQuota
+	Quota datatypes.JSON
+
 	// Secrets are read only and will not be saved by updating it here
 	Secrets []Secret
 
@@ -31,10 +46,25 @@ func (p Project) FromSpec(spec models.ProjectSpec) (Project, error) {
 	if err != nil {
 		return Project{}, nil
 	}
+	flagBytes, err := json.Marshal(spec.FeatureFlags)
+	if err != nil {
+		return Project{}, err
+	}
+	webhookBytes, err := json.Marshal(spec.Webhooks)
+	if err != nil {
+		return Project{}, err
+	}
+	blackoutWindowBytes, err := json.Marshal(spec.BlackoutWindows)
+	if err != nil {
+		return Project{}, err
+	}
 	return Project{
-		ID:     spec.ID,
-		Name:   spec.Name,
-		Config: jsonBytes,
+		ID:              spec.ID,
+		Name:            spec.Name,
+		Config:          jsonBytes,
+		FeatureFlags:    flagBytes,
+		Webhooks:        webhookBytes,
+		BlackoutWindows: blackoutWindowBytes,
 	}, nil
 }
 
@@ -43,13 +73,40 @@ func (p Project) ToSpec() (models.ProjectSpec, error) {
 	if err := json.Unmarshal(p.Config, &conf); err != nil {
 		return models.ProjectSpec{}, nil
 	}
+	flags, err := p.featureFlags()
+	if err != nil {
+		return models.ProjectSpec{}, err
+	}
+	variables, err := p.variables()
+	if err != nil {
+		return models.ProjectSpec{}, err
+	}
+	webhooks, err := p.webhooks()
+	if err != nil {
+		return models.ProjectSpec{}, err
+	}
+	quota, err := p.quota()
+	if err != nil {
+		return models.ProjectSpec{}, err
+	}
+	blackoutWindows, err := p.blackoutWindows()
+	if err != nil {
+		return models.ProjectSpec{}, err
+	}
 	return models.ProjectSpec{
-		ID:     p.ID,
-		Name:   p.Name,
-		Config: conf,
+		ID:              p.ID,
+		Name:            p.Name,
+		Config:          conf,
+		FeatureFlags:    flags,
+		Variables:       variables,
+		Webhooks:        webhooks,
+		Quota:           quota,
+		BlackoutWindows: blackoutWindows,
 	}, nil
 }
 
+// ToSpecWithSecrets leaves out secrets past their expiry, they are read as if
+// they were never registered
 func (p Project) ToSpecWithSecrets(h models.ApplicationKey) (models.ProjectSpec, error) {
 	var conf map[string]string
 	if err := json.Unmarshal(p.Config, &conf); err != nil {
@@ -57,20 +114,109 @@ func (p Project) ToSpecWithSecrets(h models.ApplicationKey) (models.ProjectSpec,
 	}
 	specSecrets := models.ProjectSecrets{}
 	for _, sec := range p.Secrets {
+		if sec.expired() {
+			continue
+		}
 		specSecret, err := sec.ToSpec(h)
 		if err != nil {
 			return models.ProjectSpec{}, err
 		}
 		specSecrets = append(specSecrets, specSecret)
 	}
+	flags, err := p.featureFlags()
+	if err != nil {
+		return models.ProjectSpec{}, err
+	}
+	variables, err := p.variables()
+	if err != nil {
+		return models.ProjectSpec{}, err
+	}
+	webhooks, err := p.webhooks()
+	if err != nil {
+		return models.ProjectSpec{}, err
+	}
+	quota, err := p.quota()
+	if err != nil {
+		return models.ProjectSpec{}, err
+	}
+	blackoutWindows, err := p.blackoutWindows()
+	if err != nil {
+		return models.ProjectSpec{}, err
+	}
 	return models.ProjectSpec{
-		ID:     p.ID,
-		Name:   p.Name,
-		Config: conf,
-		Secret: specSecrets,
+		ID:              p.ID,
+		Name:            p.Name,
+		Config:          conf,
+		Secret:          specSecrets,
+		FeatureFlags:    flags,
+		Variables:       variables,
+		Webhooks:        webhooks,
+		Quota:           quota,
+		BlackoutWindows: blackoutWindows,
 	}, nil
 }
 
+// featureFlags are not set for projects registered before flags existed
+func (p Project) featureFlags() (map[string]bool, error) {
+	var flags map[string]bool
+	if len(p.FeatureFlags) == 0 {
+		return flags, nil
+	}
+	if err := json.Unmarshal(p.FeatureFlags, &flags); err != nil {
+		return nil, errors.Wrapf(err, "failed to read feature flags of project %s", p.Name)
+	}
+	return flags, nil
+}
+
+// variables are not set for projects that never registered them
+func (p Project) variables() (models.ProjectVariables, error) {
+	var variables models.ProjectVariables
+	if len(p.Variables) == 0 {
+		return variables, nil
+	}
+	if err := json.Unmarshal(p.Variables, &variables); err != nil {
+		return nil, errors.Wrapf(err, "failed to read variables of project %s", p.Name)
+	}
+	return variables, nil
+}
+
+// webhooks are not set for projects registered before webhooks existed
+func (p Project) webhooks() ([]models.WebhookConfig, error) {
+	var webhooks []models.WebhookConfig
+	if len(p.Webhooks) == 0 {
+		return webhooks, nil
+	}
+	if err := json.Unmarshal(p.Webhooks, &webhooks); err != nil {
+		return nil, errors.Wrapf(err, "failed to read webhooks of project %s", p.Name)
+	}
+	return webhooks, nil
+}
+
+// blackoutWindows are not set for projects registered before blackout
+// windows existed
+func (p Project) blackoutWindows() ([]models.BlackoutWindow, error) {
+	var windows []models.BlackoutWindow
+	if len(p.BlackoutWindows) == 0 {
+		return windows, nil
+	}
+	if err := json.Unmarshal(p.BlackoutWindows, &windows); err != nil {
+		return nil, errors.Wrapf(err, "failed to read blackout windows of project %s", p.Name)
+	}
+	return windows, nil
+}
+
+// quota is not set for projects falling back to the default quota
+func (p Project) quota() (models.ProjectQuota, error) {
+	var quota models.ProjectQuota
+	if len(p.Quota) == 0 {
+		return quota, nil
+	}
+	if err := json.Unmarshal(p.Quota, &quota); err != nil {
+		return quota, errors.Wrapf(err, "failed to read quota of project %s", p.Name)
+	}
+	return quota, nil
+}
+
 type ProjectRepository struct {
 	db   *gorm.DB
 	hash models.ApplicationKey
@@ -140,6 +286,75 @@ func (repo *ProjectRepository) GetAll() ([]models.ProjectSpec, error) {
 	return specs, nil
 }
 
+// SaveVariables replaces the variables of a registered project
+func (repo *ProjectRepository) SaveVariables(projectName string, variables models.ProjectVariables) error {
+	if variables == nil {
+		variables = models.ProjectVariables{}
+	}
+	variableBytes, err := json.Marshal(variables)
+	if err != nil {
+		return err
+	}
+	result := repo.db.Model(&Project{}).Where("name = ?", projectName).Update("variables", datatypes.JSON(variableBytes))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return store.ErrResourceNotFound
+	}
+	return nil
+}
+
+// SaveQuota replaces the quota of a registered project
+func (repo *ProjectRepository) SaveQuota(projectName string, quota models.ProjectQuota) error {
+	quotaBytes, err := json.Marshal(quota)
+	if err != nil {
+		return err
+	}
+	result := repo.db.Model(&Project{}).Where("name = ?", projectName).Update("quota", datatypes.JSON(quotaBytes))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return store.ErrResourceNotFound
+	}
+	return nil
+}
+
+// GetProjectHealth counts the namespaces, jobs and resources of the project to
+// probe its tables, projects not deployed by the auto deployer use the latest
+// update of a job spec as the last deployment
+func (repo *ProjectRepository) GetProjectHealth(ctx context.Context, spec models.ProjectSpec) (*models.ProjectHealth, error) {
+	start := time.Now()
+	var namespaces, jobs, resources int64
+	var lastDeployedAt sql.NullTime
+	err := repo.db.DB().QueryRowContext(ctx, `SELECT
+		(SELECT count(*) FROM namespace WHERE project_id = $1),
+		(SELECT count(*) FROM job WHERE project_id = $1 AND deleted_at IS NULL),
+		(SELECT count(*) FROM resource WHERE project_id = $1),
+		COALESCE(
+			(SELECT last_deployed_at FROM deployment_state WHERE project_id = $1),
+			(SELECT max(updated_at) FROM job WHERE project_id = $1 AND deleted_at IS NULL)
+		)`, spec.ID,
+	).Scan(&namespaces, &jobs, &resources, &lastDeployedAt)
+
+	check := models.HealthCheck{
+		Name:    models.ProjectHealthCheckDatabase,
+		Status:  models.HealthCheckStatusOK,
+		Latency: time.Since(start),
+	}
+	if err != nil {
+		check.Status = models.HealthCheckStatusFailed
+		check.Message = err.Error()
+	} else {
+		check.Message = fmt.Sprintf("%d namespaces, %d jobs, %d resources", namespaces, jobs, resources)
+	}
+	return &models.ProjectHealth{
+		Checks:         []models.HealthCheck{check},
+		LastDeployedAt: lastDeployedAt.Time,
+	}, nil
+}
+
 func NewProjectRepository(db *gorm.DB, hash models.ApplicationKey) *ProjectRepository {
 	return &ProjectRepository{
 		db:   db,
@@ -3,13 +3,16 @@
 package postgres
 
 import (
+	"context"
 	"os"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
 	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,7 +22,7 @@ func TestProjectRepository(t *testing.T) {
 		if !ok {
 			panic("unable to find TEST_OPTIMUS_DB_URL env var")
 		}
-		dbConn, err := Connect(dbURL, 1, 1)
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
 		if err != nil {
 			panic(err)
 		}
@@ -30,7 +33,7 @@ func TestProjectRepository(t *testing.T) {
 		if err := m.Drop(); err != nil {
 			panic(err)
 		}
-		if err := Migrate(dbURL); err != nil {
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
 			panic(err)
 		}
 
@@ -176,6 +179,129 @@ func TestProjectRepository(t *testing.T) {
 		sec, _ := checkModel.Secret.GetByName("t1")
 		assert.Equal(t, "v1", sec)
 	})
+	t.Run("GetByName should leave out expired secrets", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		repo := NewProjectRepository(db, hash)
+		assert.Nil(t, repo.Insert(testConfigs[0]))
+
+		expiresAt := time.Now().Add(-time.Minute)
+		secretRepo := NewSecretRepository(db, testConfigs[0], hash)
+		assert.Nil(t, secretRepo.Save(models.ProjectSecretItem{Name: "t1", Value: "v1", ExpiresAt: &expiresAt}))
+		assert.Nil(t, secretRepo.Save(models.ProjectSecretItem{Name: "t2", Value: "v2"}))
+
+		checkModel, err := repo.GetByName(testConfigs[0].Name)
+		assert.Nil(t, err)
+		_, ok := checkModel.Secret.GetByName("t1")
+		assert.False(t, ok)
+		sec, _ := checkModel.Secret.GetByName("t2")
+		assert.Equal(t, "v2", sec)
+	})
+	t.Run("FeatureFlags", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		repo := NewProjectRepository(db, hash)
+
+		projectSpec := testConfigs[2]
+		projectSpec.FeatureFlags = map[string]bool{"use_new_hook": true, "legacy_sensor": false}
+		assert.Nil(t, repo.Save(projectSpec))
+
+		checkModel, err := repo.GetByName(projectSpec.Name)
+		assert.Nil(t, err)
+		assert.True(t, checkModel.IsFeatureEnabled("use_new_hook"))
+		assert.False(t, checkModel.IsFeatureEnabled("legacy_sensor"))
+		assert.False(t, checkModel.IsFeatureEnabled("unknown"))
+
+		projectSpec.FeatureFlags = nil
+		assert.Nil(t, repo.Save(projectSpec))
+		checkModel, err = repo.GetByName(projectSpec.Name)
+		assert.Nil(t, err)
+		assert.False(t, checkModel.IsFeatureEnabled("use_new_hook"))
+	})
+	t.Run("Webhooks", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		repo := NewProjectRepository(db, hash)
+
+		projectSpec := testConfigs[2]
+		projectSpec.Webhooks = []models.WebhookConfig{
+			{URL: "https://hooks.example.io/optimus", SecretName: "WEBHOOK_SECRET", Events: []string{models.WebhookEventJobFailure}},
+		}
+		assert.Nil(t, repo.Save(projectSpec))
+
+		checkModel, err := repo.GetByName(projectSpec.Name)
+		assert.Nil(t, err)
+		assert.Equal(t, projectSpec.Webhooks, checkModel.Webhooks)
+
+		projectSpec.Webhooks = nil
+		assert.Nil(t, repo.Save(projectSpec))
+		checkModel, err = repo.GetByName(projectSpec.Name)
+		assert.Nil(t, err)
+		assert.Empty(t, checkModel.Webhooks)
+	})
+	t.Run("BlackoutWindows", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		repo := NewProjectRepository(db, hash)
+
+		projectSpec := testConfigs[2]
+		projectSpec.BlackoutWindows = []models.BlackoutWindow{
+			{Start: "0 2 * * 0", Duration: time.Hour * 2, JobPattern: "^bq-"},
+		}
+		assert.Nil(t, repo.Save(projectSpec))
+
+		checkModel, err := repo.GetByName(projectSpec.Name)
+		assert.Nil(t, err)
+		assert.Equal(t, projectSpec.BlackoutWindows, checkModel.BlackoutWindows)
+
+		projectSpec.BlackoutWindows = nil
+		assert.Nil(t, repo.Save(projectSpec))
+		checkModel, err = repo.GetByName(projectSpec.Name)
+		assert.Nil(t, err)
+		assert.Empty(t, checkModel.BlackoutWindows)
+	})
+	t.Run("SaveVariables", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		repo := NewProjectRepository(db, hash)
+
+		projectSpec := testConfigs[2]
+		assert.Nil(t, repo.Save(projectSpec))
+		assert.Nil(t, repo.SaveVariables(projectSpec.Name, models.ProjectVariables{"GCP_PROJECT": "data-project"}))
+
+		// saving the project keeps its variables
+		assert.Nil(t, repo.Save(projectSpec))
+		checkModel, err := repo.GetByName(projectSpec.Name)
+		assert.Nil(t, err)
+		assert.Equal(t, models.ProjectVariables{"GCP_PROJECT": "data-project"}, checkModel.Variables)
+
+		assert.Nil(t, repo.SaveVariables(projectSpec.Name, nil))
+		checkModel, err = repo.GetByName(projectSpec.Name)
+		assert.Nil(t, err)
+		assert.Empty(t, checkModel.Variables)
+
+		err = repo.SaveVariables("unknown-project", models.ProjectVariables{})
+		assert.Equal(t, store.ErrResourceNotFound, err)
+	})
+	t.Run("SaveQuota", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		repo := NewProjectRepository(db, hash)
+
+		projectSpec := testConfigs[2]
+		quota := models.ProjectQuota{MaxJobCount: 50, MinScheduleInterval: time.Hour}
+		assert.Nil(t, repo.Save(projectSpec))
+		assert.Nil(t, repo.SaveQuota(projectSpec.Name, quota))
+
+		// saving the project keeps its quota
+		assert.Nil(t, repo.Save(projectSpec))
+		checkModel, err := repo.GetByName(projectSpec.Name)
+		assert.Nil(t, err)
+		assert.Equal(t, quota, checkModel.Quota)
+
+		err = repo.SaveQuota("unknown-project", quota)
+		assert.Equal(t, store.ErrResourceNotFound, err)
+	})
 	t.Run("GetAll", func(t *testing.T) {
 		db := DBSetup()
 		defer db.Close()
@@ -212,4 +338,25 @@ func TestProjectRepository(t *testing.T) {
 		sec, _ = checkModels[1].Secret.GetByName("t2")
 		assert.Equal(t, "v2", sec)
 	})
+	t.Run("GetProjectHealth", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+
+		repo := NewProjectRepository(db, hash)
+		assert.Nil(t, repo.Insert(testConfigs[0]))
+		namespaceSpec := models.NamespaceSpec{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Name:        "dev-team-1",
+			ProjectSpec: testConfigs[0],
+		}
+		assert.Nil(t, NewNamespaceRepository(db, testConfigs[0], hash).Insert(namespaceSpec))
+
+		health, err := repo.GetProjectHealth(context.Background(), testConfigs[0])
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(health.Checks))
+		assert.Equal(t, models.ProjectHealthCheckDatabase, health.Checks[0].Name)
+		assert.Equal(t, models.HealthCheckStatusOK, health.Checks[0].Status)
+		assert.Equal(t, "1 namespaces, 0 jobs, 0 resources", health.Checks[0].Message)
+		assert.True(t, health.LastDeployedAt.IsZero())
+	})
 }
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+const queryPlanNodeSeqScan = "Seq Scan"
+
+// QueryPlanNode is a node of a plan explained by postgres in json, only the
+// fields used to find slow scans are read
+type QueryPlanNode struct {
+	NodeType          string          `json:"Node Type"`
+	RelationName      string          `json:"Relation Name"`
+	IndexName         string          `json:"Index Name"`
+	Filter            string          `json:"Filter"`
+	ActualRows        float64         `json:"Actual Rows"`
+	RowsRemovedFilter float64         `json:"Rows Removed by Filter"`
+	Plans             []QueryPlanNode `json:"Plans"`
+}
+
+// AnalyzeQueryPlan runs the query built by queryFn with
+// EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) and returns the plan in json. The
+// query is executed, it runs in a transaction that is rolled back so
+// analyzing updates doesn't change the tables
+func AnalyzeQueryPlan(ctx context.Context, db *gorm.DB, queryFn func(*gorm.DB) *gorm.DB) (string, error) {
+	tx := db.BeginTx(ctx, nil)
+	if tx.Error != nil {
+		return "", tx.Error
+	}
+	defer tx.Rollback()
+
+	var plan string
+	if err := tx.Raw("EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) ?", queryFn(tx).QueryExpr()).Row().Scan(&plan); err != nil {
+		return "", errors.Wrap(err, "failed to analyze query plan")
+	}
+	return plan, nil
+}
+
+// CapturedQuery is a select statement gorm sent to postgres along with its
+// bind variables
+type CapturedQuery struct {
+	SQL  string
+	Vars []interface{}
+}
+
+// queryCaptureLogger collects the statements gorm logs in its detailed log mode
+type queryCaptureLogger struct {
+	queries []CapturedQuery
+}
+
+// Print receives "sql", source, duration, statement, vars and rows affected
+// for each statement
+func (l *queryCaptureLogger) Print(values ...interface{}) {
+	if len(values) < 5 || values[0] != "sql" {
+		return
+	}
+	statement, ok := values[3].(string)
+	if !ok || !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(statement)), "SELECT") {
+		return
+	}
+	vars, _ := values[4].([]interface{})
+	l.queries = append(l.queries, CapturedQuery{SQL: statement, Vars: vars})
+}
+
+// CaptureQueries returns the select statements sent to postgres by fn, the
+// repositories called in fn are to be built on the db it is passed so the
+// queries they generate can be analyzed with AnalyzeCapturedQueryPlan
+func CaptureQueries(db *gorm.DB, fn func(db *gorm.DB) error) ([]CapturedQuery, error) {
+	logger := &queryCaptureLogger{}
+	capturing := db.New()
+	capturing.SetLogger(logger)
+	if err := fn(capturing.LogMode(true)); err != nil {
+		return logger.queries, err
+	}
+	return logger.queries, nil
+}
+
+// AnalyzeCapturedQueryPlan analyzes a query of CaptureQueries like
+// AnalyzeQueryPlan does
+func AnalyzeCapturedQueryPlan(ctx context.Context, db *gorm.DB, query CapturedQuery) (string, error) {
+	tx := db.BeginTx(ctx, nil)
+	if tx.Error != nil {
+		return "", tx.Error
+	}
+	defer tx.Rollback()
+
+	var plan string
+	if err := tx.CommonDB().QueryRow("EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "+query.SQL, query.Vars...).
+		Scan(&plan); err != nil {
+		return "", errors.Wrap(err, "failed to analyze query plan")
+	}
+	return plan, nil
+}
+
+// SequentialScans returns the sequential scans in a plan of AnalyzeQueryPlan.
+// Scans with a filter read the whole relation to find a few rows, they point
+// to a missing index unless the relation is small
+func SequentialScans(plan string) ([]QueryPlanNode, error) {
+	var explained []struct {
+		Plan QueryPlanNode `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(plan), &explained); err != nil {
+		return nil, errors.Wrap(err, "failed to parse query plan")
+	}
+
+	var scans []QueryPlanNode
+	var walk func(node QueryPlanNode)
+	walk = func(node QueryPlanNode) {
+		if node.NodeType == queryPlanNodeSeqScan {
+			scans = append(scans, node)
+		}
+		for _, child := range node.Plans {
+			walk(child)
+		}
+	}
+	for _, e := range explained {
+		walk(e.Plan)
+	}
+	return scans, nil
+}
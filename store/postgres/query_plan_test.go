@@ -0,0 +1,144 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequentialScans(t *testing.T) {
+	plan := `[{"Plan": {"Node Type": "Nested Loop", "Plans": [
+		{"Node Type": "Index Scan", "Relation Name": "namespace", "Index Name": "namespace_pkey"},
+		{"Node Type": "Seq Scan", "Relation Name": "job", "Filter": "((name)::text = 'job-1'::text)",
+			"Actual Rows": 1, "Rows Removed by Filter": 999}
+	]}, "Execution Time": 0.5}]`
+
+	scans, err := SequentialScans(plan)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(scans))
+	assert.Equal(t, "job", scans[0].RelationName)
+	assert.Equal(t, float64(999), scans[0].RowsRemovedFilter)
+
+	_, err = SequentialScans("not a plan")
+	assert.NotNil(t, err)
+}
+
+func TestJobSpecRepositoryQueryPlans(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "t-optimus-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "dev-team-1",
+		ProjectSpec: projectSpec,
+	}
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+
+	DBSetup := func(jobCount int) *gorm.DB {
+		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
+		if !ok {
+			panic("unable to find TEST_OPTIMUS_DB_URL env var")
+		}
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
+		if err != nil {
+			panic(err)
+		}
+		m, err := NewHTTPFSMigrator(dbURL)
+		if err != nil {
+			panic(err)
+		}
+		if err := m.Drop(); err != nil {
+			panic(err)
+		}
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
+			panic(err)
+		}
+
+		assert.Nil(t, NewProjectRepository(dbConn, hash).Save(projectSpec))
+		assert.Nil(t, NewNamespaceRepository(dbConn, projectSpec, hash).Save(namespaceSpec))
+		// each job depends on the job before it
+		assert.Nil(t, dbConn.Exec(`INSERT INTO job (project_id, namespace_id, name, destination, start_date, task_name,
+				task_config, assets, hooks, dependencies, created_at, updated_at)
+			SELECT ?, ?, 'job-' || i, 'proj.dataset.table_' || i, now(), 'bq2bq', '[]', '[]', '[]',
+				jsonb_build_object('job-' || (i - 1), jsonb_build_object('Type', 'intra')), now(), now()
+			FROM generate_series(1, ?) i`,
+			projectSpec.ID, namespaceSpec.ID, jobCount).Error)
+		assert.Nil(t, dbConn.Exec("ANALYZE job").Error)
+
+		// small tables are cheaper to scan than their indexes, with sequential
+		// scans disabled postgres only falls back to them when no index
+		// serves the query. The connection pool has a single connection
+		assert.Nil(t, dbConn.Exec("SET enable_seqscan = off").Error)
+		return dbConn
+	}
+
+	pluginRepo := new(mock.SupportedPluginRepo)
+	pluginRepo.On("GetByName", "bq2bq").Return(&models.Plugin{Base: new(mock.BasePlugin)}, nil)
+	adapter := NewAdapter(pluginRepo)
+	// repository calls whose queries are analyzed, they run on the db they
+	// are passed so the queries can be captured
+	calls := map[string]func(*gorm.DB) error{
+		"ProjectJobSpecRepository.GetByName": func(db *gorm.DB) error {
+			_, _, err := NewProjectJobSpecRepository(db, projectSpec, adapter).GetByName("job-50")
+			return err
+		},
+		"ProjectJobSpecRepository.GetAll": func(db *gorm.DB) error {
+			_, err := NewProjectJobSpecRepository(db, projectSpec, adapter).GetAll()
+			return err
+		},
+		"ProjectJobSpecRepository.GetByDestination": func(db *gorm.DB) error {
+			_, _, err := NewProjectJobSpecRepository(db, projectSpec, adapter).GetByDestination("proj.dataset.table_50")
+			return err
+		},
+		"ProjectJobSpecRepository.GetDependents": func(db *gorm.DB) error {
+			dependents, err := NewProjectJobSpecRepository(db, projectSpec, adapter).GetDependents("job-50")
+			if err == nil && len(dependents) != 1 {
+				err = fmt.Errorf("expected a single dependent of job-50, found %d", len(dependents))
+			}
+			return err
+		},
+		"JobSpecRepository.GetByName": func(db *gorm.DB) error {
+			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+			_, err := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher).GetByName("job-50")
+			return err
+		},
+		"JobSpecRepository.GetAll": func(db *gorm.DB) error {
+			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+			_, err := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher).GetAll()
+			return err
+		},
+	}
+
+	for _, jobCount := range []int{100, 1000, 10000} {
+		t.Run(fmt.Sprintf("%d jobs", jobCount), func(t *testing.T) {
+			db := DBSetup(jobCount)
+			defer db.Close()
+
+			for name, call := range calls {
+				queries, err := CaptureQueries(db, call)
+				assert.Nil(t, err, name)
+				assert.NotEmpty(t, queries, name)
+				for _, query := range queries {
+					plan, err := AnalyzeCapturedQueryPlan(context.Background(), db, query)
+					assert.Nil(t, err, name)
+					scans, err := SequentialScans(plan)
+					assert.Nil(t, err, name)
+					for _, scan := range scans {
+						t.Errorf("%s scans %s sequentially with filter %s, it is missing an index: %s", name,
+							scan.RelationName, scan.Filter, query.SQL)
+					}
+				}
+			}
+		})
+	}
+}
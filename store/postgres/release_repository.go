@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"gorm.io/datatypes"
+)
+
+// Release is the postgres representation of a tagged snapshot of the jobs of a project
+type Release struct {
+	ID uuid.UUID `gorm:"primary_key;type:uuid"`
+
+	ProjectID uuid.UUID `gorm:"not null"`
+	Project   Project   `gorm:"foreignKey:ProjectID"`
+
+	Tag         string `gorm:"not null"`
+	Description string
+	Jobs        []ReleaseJob `gorm:"foreignKey:ReleaseID"`
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
+}
+
+func (Release) TableName() string {
+	return "releases"
+}
+
+// ReleaseJob holds the job row of a job at the time of tagging the release
+type ReleaseJob struct {
+	ReleaseID uuid.UUID `gorm:"primary_key;type:uuid"`
+	JobName   string    `gorm:"primary_key"`
+
+	NamespaceID uuid.UUID `gorm:"not null"`
+	Namespace   Namespace `gorm:"foreignKey:NamespaceID"`
+
+	SpecChecksum string
+	Spec         datatypes.JSON
+}
+
+func (ReleaseJob) TableName() string {
+	return "release_jobs"
+}
+
+type releaseRepository struct {
+	db      *gorm.DB
+	project models.ProjectSpec
+	adapter *JobSpecAdapter
+}
+
+func (repo *releaseRepository) Create(tag, description string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var existing Release
+		err := tx.Where("project_id = ? AND tag = ?", repo.project.ID, tag).Find(&existing).Error
+		if err == nil {
+			return errors.Wrapf(store.ErrResourceExists, "release %s", tag)
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var jobs []Job
+		if err := tx.Where("project_id = ?", repo.project.ID).Find(&jobs).Error; err != nil {
+			return err
+		}
+		release := Release{
+			ID:          uuid.New(),
+			ProjectID:   repo.project.ID,
+			Tag:         tag,
+			Description: description,
+		}
+		if err := tx.Create(&release).Error; err != nil {
+			return err
+		}
+		for _, job := range jobs {
+			// relations are not part of the snapshot
+			snapshot := job
+			snapshot.Project = Project{}
+			snapshot.Namespace = Namespace{}
+			snapshotJSON, err := json.Marshal(snapshot)
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(&ReleaseJob{
+				ReleaseID:    release.ID,
+				JobName:      job.Name,
+				NamespaceID:  job.NamespaceID,
+				SpecChecksum: job.SpecChecksum,
+				Spec:         snapshotJSON,
+			}).Error; err != nil {
+				return errors.Wrapf(err, "failed to snapshot %s", job.Name)
+			}
+		}
+		return nil
+	})
+}
+
+func (repo *releaseRepository) GetByTag(tag string) (models.Release, error) {
+	var r Release
+	if err := repo.db.Where("project_id = ? AND tag = ?", repo.project.ID, tag).
+		Preload("Jobs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("release_jobs.job_name")
+		}).Preload("Jobs.Namespace").Find(&r).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Release{}, errors.Wrapf(store.ErrResourceNotFound, "release %s", tag)
+		}
+		return models.Release{}, err
+	}
+
+	release := models.Release{
+		Tag:         r.Tag,
+		Description: r.Description,
+		CreatedAt:   r.CreatedAt,
+	}
+	for _, releaseJob := range r.Jobs {
+		var snapshot Job
+		if err := json.Unmarshal(releaseJob.Spec, &snapshot); err != nil {
+			return models.Release{}, errors.Wrapf(err, "failed to read released spec of %s", releaseJob.JobName)
+		}
+		jobSpec, err := repo.adapter.ToSpec(snapshot)
+		if err != nil {
+			return models.Release{}, errors.Wrapf(err, "failed to read released spec of %s", releaseJob.JobName)
+		}
+		namespaceSpec, err := releaseJob.Namespace.ToSpec(repo.project)
+		if err != nil {
+			return models.Release{}, err
+		}
+		release.Jobs = append(release.Jobs, models.ReleaseJob{
+			Namespace:    namespaceSpec,
+			Spec:         jobSpec,
+			SpecChecksum: releaseJob.SpecChecksum,
+		})
+	}
+	return release, nil
+}
+
+// NewReleaseRepository creates a repository for the releases of project
+func NewReleaseRepository(db *gorm.DB, project models.ProjectSpec, adapter *JobSpecAdapter) *releaseRepository {
+	return &releaseRepository{
+		db:      db,
+		project: project,
+		adapter: adapter,
+	}
+}
@@ -0,0 +1,159 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestReleaseRepository(t *testing.T) {
+	DBSetup := func() *gorm.DB {
+		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
+		if !ok {
+			panic("unable to find TEST_OPTIMUS_DB_URL env var")
+		}
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
+		if err != nil {
+			panic(err)
+		}
+		m, err := NewHTTPFSMigrator(dbURL)
+		if err != nil {
+			panic(err)
+		}
+		if err := m.Drop(); err != nil {
+			panic(err)
+		}
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
+			panic(err)
+		}
+		return dbConn
+	}
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "t-optimus-id",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "dev-team-1",
+		ProjectSpec: projectSpec,
+	}
+
+	gTask := "g-task"
+	execUnit := new(mock.BasePlugin)
+	execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+		Name: gTask,
+	}, nil)
+	depMod := new(mock.DependencyResolverMod)
+	depMod.On("GenerateDestination", context.TODO(), mock2.Anything).Return(&models.GenerateDestinationResponse{Destination: "p.d.t"}, nil)
+	pluginRepo := new(mock.SupportedPluginRepo)
+	pluginRepo.On("GetByName", gTask).Return(&models.Plugin{Base: execUnit, DependencyMod: depMod}, nil)
+	adapter := NewAdapter(pluginRepo)
+
+	newSpec := func(name, owner string) models.JobSpec {
+		return models.JobSpec{
+			ID:    uuid.Must(uuid.NewRandom()),
+			Name:  name,
+			Owner: owner,
+			Task: models.JobSpecTask{
+				Unit: &models.Plugin{Base: execUnit, DependencyMod: depMod},
+				Window: models.JobSpecTaskWindow{
+					Size:       time.Hour * 24,
+					TruncateTo: "d",
+				},
+			},
+		}
+	}
+	setup := func(db *gorm.DB) *JobSpecRepository {
+		assert.Nil(t, NewProjectRepository(db, hash).Save(projectSpec))
+		assert.Nil(t, NewNamespaceRepository(db, projectSpec, hash).Insert(namespaceSpec))
+		projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+		return NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
+	}
+
+	t.Run("should keep released specs after jobs are updated", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		jobRepo := setup(db)
+		specA := newSpec("job-a", "tagged@mee")
+		specB := newSpec("job-b", "tagged@mee")
+		assert.Nil(t, jobRepo.Insert(specA))
+		assert.Nil(t, jobRepo.Insert(specB))
+
+		repo := NewReleaseRepository(db, projectSpec, adapter)
+		assert.Nil(t, repo.Create("v1.0.0", "first release"))
+
+		updatedA := specA
+		updatedA.Owner = "modified@mee"
+		assert.Nil(t, jobRepo.Save(updatedA))
+		assert.Nil(t, jobRepo.Delete(specB.Name))
+		assert.Nil(t, jobRepo.Insert(newSpec("job-c", "created@mee")))
+
+		release, err := repo.GetByTag("v1.0.0")
+		assert.Nil(t, err)
+		assert.Equal(t, "v1.0.0", release.Tag)
+		assert.Equal(t, "first release", release.Description)
+		assert.Equal(t, 2, len(release.Jobs))
+		assert.Equal(t, "job-a", release.Jobs[0].Spec.Name)
+		assert.Equal(t, "tagged@mee", release.Jobs[0].Spec.Owner)
+		assert.Equal(t, namespaceSpec.ID, release.Jobs[0].Namespace.ID)
+		assert.Equal(t, "job-b", release.Jobs[1].Spec.Name)
+
+		checksum, err := jobSpecHasher.JobSpecChecksum(specA)
+		assert.Nil(t, err)
+		assert.Equal(t, checksum, release.Jobs[0].SpecChecksum)
+	})
+	t.Run("should restore released specs", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		jobRepo := setup(db)
+		specA := newSpec("job-a", "tagged@mee")
+		assert.Nil(t, jobRepo.Insert(specA))
+		repo := NewReleaseRepository(db, projectSpec, adapter)
+		assert.Nil(t, repo.Create("v1.0.0", ""))
+		updatedA := specA
+		updatedA.Owner = "modified@mee"
+		assert.Nil(t, jobRepo.Save(updatedA))
+
+		release, err := repo.GetByTag("v1.0.0")
+		assert.Nil(t, err)
+		assert.Nil(t, jobRepo.Save(release.Jobs[0].Spec))
+
+		restored, err := jobRepo.GetByName(specA.Name)
+		assert.Nil(t, err)
+		assert.Equal(t, "tagged@mee", restored.Owner)
+		checksum, err := jobRepo.GetSpecChecksum(specA.Name)
+		assert.Nil(t, err)
+		assert.Equal(t, release.Jobs[0].SpecChecksum, checksum)
+	})
+	t.Run("should fail if tag already exists", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		setup(db)
+
+		repo := NewReleaseRepository(db, projectSpec, adapter)
+		assert.Nil(t, repo.Create("v1.0.0", ""))
+		err := repo.Create("v1.0.0", "")
+		assert.ErrorIs(t, err, store.ErrResourceExists)
+	})
+	t.Run("should return not found for unknown tag", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		setup(db)
+
+		_, err := NewReleaseRepository(db, projectSpec, adapter).GetByTag("unknown")
+		assert.ErrorIs(t, err, store.ErrResourceNotFound)
+	})
+}
@@ -81,9 +81,11 @@ func (repo *replayRepository) Insert(replay *models.ReplaySpec) error {
 	return repo.DB.Create(&r).Error
 }
 
+// GetByID returns the replay with the given id if it is a replay of the job
+// of the repository
 func (repo *replayRepository) GetByID(id uuid.UUID) (models.ReplaySpec, error) {
 	var r Replay
-	if err := repo.DB.Where("id = ?", id).Find(&r).Error; err != nil {
+	if err := repo.DB.Where("id = ? AND job_id = ?", id, repo.jobSpec.ID).Find(&r).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return models.ReplaySpec{}, store.ErrResourceNotFound
 		}
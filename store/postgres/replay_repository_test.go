@@ -4,6 +4,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/jinzhu/gorm"
 	"github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -72,7 +74,7 @@ func TestReplayRepository(t *testing.T) {
 		if !ok {
 			panic("unable to find TEST_OPTIMUS_DB_URL env var")
 		}
-		dbConn, err := Connect(dbURL, 1, 1)
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
 		if err != nil {
 			panic(err)
 		}
@@ -83,7 +85,7 @@ func TestReplayRepository(t *testing.T) {
 		if err := m.Drop(); err != nil {
 			panic(err)
 		}
-		if err := Migrate(dbURL); err != nil {
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
 			panic(err)
 		}
 
@@ -117,6 +119,18 @@ func TestReplayRepository(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, testModels[0].ID, checkModel.ID)
 	})
+	t.Run("GetByID should not return replays of other jobs", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+
+		testConfigs[0].Job = jobConfigs[0]
+		adapter := NewAdapter(new(mock.SupportedPluginRepo))
+		err := NewReplayRepository(db, jobConfigs[0], adapter).Insert(testConfigs[0])
+		assert.Nil(t, err)
+
+		_, err = NewReplayRepository(db, jobConfigs[1], adapter).GetByID(testConfigs[0].ID)
+		assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+	})
 
 	t.Run("UpdateStatus", func(t *testing.T) {
 		db := DBSetup()
@@ -186,7 +200,7 @@ func TestReplayRepository(t *testing.T) {
 			depMod1.On("GenerateDestination", context.TODO(), unitData).Return(&models.GenerateDestinationResponse{Destination: "p.d.t"}, nil)
 
 			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
-			jobRepo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+			jobRepo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 			err := jobRepo.Insert(testModels[0].Job)
 			assert.Nil(t, err)
 			err = jobRepo.Insert(testModels[1].Job)
@@ -241,7 +255,7 @@ func TestReplayRepository(t *testing.T) {
 			depMod1.On("GenerateDestination", context.TODO(), unitData).Return(&models.GenerateDestinationResponse{Destination: "p.d.t"}, nil)
 
 			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
-			jobRepo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+			jobRepo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter, jobSpecHasher)
 			err := jobRepo.Insert(testModels[0].Job)
 			assert.Nil(t, err)
 			err = jobRepo.Insert(testModels[1].Job)
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// ProjectRoleBinding is the postgres representation of the role a subject
+// has in a project
+type ProjectRoleBinding struct {
+	ID uuid.UUID `gorm:"primary_key;type:uuid"`
+
+	ProjectID uuid.UUID `gorm:"not null"`
+	Project   Project   `gorm:"foreignKey:ProjectID"`
+
+	Subject string `gorm:"not null"`
+	Role    string `gorm:"not null"`
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
+}
+
+func (ProjectRoleBinding) TableName() string {
+	return "project_role_bindings"
+}
+
+type roleBindingRepository struct {
+	db      *gorm.DB
+	project models.ProjectSpec
+}
+
+func (repo *roleBindingRepository) Save(binding models.ProjectRoleBinding) error {
+	var existing ProjectRoleBinding
+	err := repo.db.Where("project_id = ? AND subject = ?", repo.project.ID, binding.Subject).Find(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repo.db.Create(&ProjectRoleBinding{
+			ID:        uuid.New(),
+			ProjectID: repo.project.ID,
+			Subject:   binding.Subject,
+			Role:      binding.Role.String(),
+		}).Error
+	} else if err != nil {
+		return err
+	}
+	existing.Role = binding.Role.String()
+	return repo.db.Save(&existing).Error
+}
+
+func (repo *roleBindingRepository) Delete(subject string) error {
+	result := repo.db.Where("project_id = ? AND subject = ?", repo.project.ID, subject).Delete(&ProjectRoleBinding{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.Wrapf(store.ErrResourceNotFound, "role of %s", subject)
+	}
+	return nil
+}
+
+func (repo *roleBindingRepository) GetAll() ([]models.ProjectRoleBinding, error) {
+	var bindings []ProjectRoleBinding
+	if err := repo.db.Where("project_id = ?", repo.project.ID).Order("subject").Find(&bindings).Error; err != nil {
+		return nil, err
+	}
+	specs := []models.ProjectRoleBinding{}
+	for _, binding := range bindings {
+		role, err := models.ParseRole(binding.Role)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid role of %s", binding.Subject)
+		}
+		specs = append(specs, models.ProjectRoleBinding{
+			Subject: binding.Subject,
+			Role:    role,
+		})
+	}
+	return specs, nil
+}
+
+// GetProjectRole returns the role of the subject in a project looked up by
+// name, subjects without a role in the project have models.RoleNone
+func (repo *roleBindingRepository) GetProjectRole(ctx context.Context, projectName, subject string) (models.Role, error) {
+	var binding ProjectRoleBinding
+	err := repo.db.Joins("JOIN project ON project.id = project_role_bindings.project_id").
+		Where("project.name = ? AND project_role_bindings.subject = ?", projectName, subject).
+		Find(&binding).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.RoleNone, nil
+	} else if err != nil {
+		return models.RoleNone, errors.Wrapf(err, "failed to fetch role of %s in project %s", subject, projectName)
+	}
+	return models.ParseRole(binding.Role)
+}
+
+// NewRoleBindingRepository creates a repository for the role bindings of
+// project, roles of any project can be looked up by name regardless
+func NewRoleBindingRepository(db *gorm.DB, project models.ProjectSpec) *roleBindingRepository {
+	return &roleBindingRepository{
+		db:      db,
+		project: project,
+	}
+}
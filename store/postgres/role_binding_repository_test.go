@@ -0,0 +1,106 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleBindingRepository(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "t-optimus-project",
+	}
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+
+	DBSetup := func() *gorm.DB {
+		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
+		if !ok {
+			panic("unable to find TEST_OPTIMUS_DB_URL env var")
+		}
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
+		if err != nil {
+			panic(err)
+		}
+		m, err := NewHTTPFSMigrator(dbURL)
+		if err != nil {
+			panic(err)
+		}
+		if err := m.Drop(); err != nil {
+			panic(err)
+		}
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
+			panic(err)
+		}
+
+		projRepo := NewProjectRepository(dbConn, hash)
+		assert.Nil(t, projRepo.Save(projectSpec))
+		return dbConn
+	}
+
+	t.Run("Save", func(t *testing.T) {
+		t.Run("should replace the role a subject had", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			repo := NewRoleBindingRepository(db, projectSpec)
+
+			assert.Nil(t, repo.Save(models.ProjectRoleBinding{Subject: "user@example.io", Role: models.RoleViewer}))
+			assert.Nil(t, repo.Save(models.ProjectRoleBinding{Subject: "admin@example.io", Role: models.RoleAdmin}))
+			assert.Nil(t, repo.Save(models.ProjectRoleBinding{Subject: "user@example.io", Role: models.RoleEditor}))
+
+			bindings, err := repo.GetAll()
+			assert.Nil(t, err)
+			assert.Equal(t, []models.ProjectRoleBinding{
+				{Subject: "admin@example.io", Role: models.RoleAdmin},
+				{Subject: "user@example.io", Role: models.RoleEditor},
+			}, bindings)
+		})
+	})
+	t.Run("Delete", func(t *testing.T) {
+		t.Run("should delete the role of a subject", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			repo := NewRoleBindingRepository(db, projectSpec)
+			assert.Nil(t, repo.Save(models.ProjectRoleBinding{Subject: "user@example.io", Role: models.RoleViewer}))
+
+			assert.Nil(t, repo.Delete("user@example.io"))
+			err := repo.Delete("user@example.io")
+			assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+
+			bindings, err := repo.GetAll()
+			assert.Nil(t, err)
+			assert.Empty(t, bindings)
+		})
+	})
+	t.Run("GetProjectRole", func(t *testing.T) {
+		t.Run("should look up the role of a subject by project name", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			assert.Nil(t, NewRoleBindingRepository(db, projectSpec).Save(models.ProjectRoleBinding{
+				Subject: "user@example.io",
+				Role:    models.RoleEditor,
+			}))
+			repo := NewRoleBindingRepository(db, models.ProjectSpec{})
+
+			role, err := repo.GetProjectRole(context.Background(), projectSpec.Name, "user@example.io")
+			assert.Nil(t, err)
+			assert.Equal(t, models.RoleEditor, role)
+
+			role, err = repo.GetProjectRole(context.Background(), projectSpec.Name, "other@example.io")
+			assert.Nil(t, err)
+			assert.Equal(t, models.RoleNone, role)
+			role, err = repo.GetProjectRole(context.Background(), "other-project", "user@example.io")
+			assert.Nil(t, err)
+			assert.Equal(t, models.RoleNone, role)
+		})
+	})
+}
@@ -2,6 +2,9 @@ package postgres
 
 import (
 	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/odpf/optimus/store"
@@ -21,6 +24,8 @@ type Secret struct {
 	Name  string `gorm:"not null"`
 	Value string
 
+	ExpiresAt *time.Time
+
 	CreatedAt time.Time `gorm:"not null" json:"created_at"`
 	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
 	DeletedAt *time.Time
@@ -41,6 +46,7 @@ func (p Secret) FromSpec(spec models.ProjectSecretItem, proj models.ProjectSpec,
 		Name:      spec.Name,
 		Value:     base64cipher,
 		ProjectID: proj.ID,
+		ExpiresAt: spec.ExpiresAt,
 	}, nil
 }
 
@@ -51,24 +57,75 @@ func (p Secret) ToSpec(hash models.ApplicationKey) (models.ProjectSecretItem, er
 		return models.ProjectSecretItem{}, err
 	}
 
-	// decrypt secret
+	// decrypt secret, values not rotated yet are encrypted with the
+	// previous key
 	cleartext, err := cryptopasta.Decrypt(encrypted, hash.GetKey())
+	if err != nil && hash.GetPreviousKey() != nil {
+		cleartext, err = cryptopasta.Decrypt(encrypted, hash.GetPreviousKey())
+	}
 	if err != nil {
 		return models.ProjectSecretItem{}, err
 	}
 
 	return models.ProjectSecretItem{
-		ID:    p.ID,
-		Name:  p.Name,
-		Value: string(cleartext),
+		ID:        p.ID,
+		Name:      p.Name,
+		Value:     string(cleartext),
+		ExpiresAt: p.ExpiresAt,
 	}, nil
 }
 
+// expired tells if the secret is past its expiry and can't be read
+func (p Secret) expired() bool {
+	return p.ExpiresAt != nil && !p.ExpiresAt.After(time.Now())
+}
+
+// SecretBackup keeps the encrypted value of a secret as it was before its
+// application key was migrated
+type SecretBackup struct {
+	ID        uuid.UUID `gorm:"primary_key;type:uuid"`
+	SecretID  uuid.UUID
+	ProjectID uuid.UUID
+
+	Name  string `gorm:"not null"`
+	Value string
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+}
+
+func (SecretBackup) TableName() string {
+	return "secret_backup"
+}
+
+// SecretVersion keeps an encrypted value a secret was saved with, the
+// latest version holds the current value
+type SecretVersion struct {
+	ID         uuid.UUID `gorm:"primary_key;type:uuid"`
+	ProjectID  uuid.UUID
+	SecretName string `gorm:"not null"`
+
+	EncryptedValue string
+	Version        int `gorm:"not null"`
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+}
+
+func (SecretVersion) TableName() string {
+	return "secret_versions"
+}
+
+// DefaultSecretVersionRetention is the number of versions kept for each
+// secret, older versions are deleted when a secret is saved
+const DefaultSecretVersionRetention = 5
+
 type secretRepository struct {
 	db      *gorm.DB
 	project models.ProjectSpec
 
 	hash models.ApplicationKey
+
+	// VersionRetention is the number of versions kept for each secret
+	VersionRetention int
 }
 
 func (repo *secretRepository) Insert(resource models.ProjectSecretItem) error {
@@ -79,26 +136,118 @@ func (repo *secretRepository) Insert(resource models.ProjectSecretItem) error {
 	if len(p.Name) == 0 {
 		return errors.New("name cannot be empty")
 	}
-	return repo.db.Create(&p).Error
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&p).Error; err != nil {
+			return err
+		}
+		return repo.addVersion(tx, p.Name, p.Value)
+	})
 }
 
 func (repo *secretRepository) Save(spec models.ProjectSecretItem) error {
-	existingResource, err := repo.GetByName(spec.Name)
-	if errors.Is(err, store.ErrResourceNotFound) {
-		return repo.Insert(spec)
-	} else if err != nil {
+	var existingResource Secret
+	if err := repo.db.Where("name = ? AND project_id = ?", spec.Name, repo.project.ID).Find(&existingResource).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repo.Insert(spec)
+		}
 		return errors.Wrap(err, "unable to find secret by name")
 	}
 	resource, err := Secret{}.FromSpec(spec, repo.project, repo.hash)
 	if err != nil {
 		return err
 	}
-	if err == nil {
-		resource.ID = existingResource.ID
+	resource.ID = existingResource.ID
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		// secrets saved before versions were kept get their current value
+		// as the first version
+		var versions int
+		if err := tx.Model(&SecretVersion{}).Where("project_id = ? AND secret_name = ?", repo.project.ID, spec.Name).
+			Count(&versions).Error; err != nil {
+			return errors.Wrapf(err, "failed to count versions of secret %s", spec.Name)
+		}
+		if versions == 0 {
+			if err := repo.addVersion(tx, spec.Name, existingResource.Value); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(resource).Updates(resource).Error; err != nil {
+			return err
+		}
+		// updates skip a nil expiry, secrets saved again without one don't expire
+		if err := tx.Model(resource).Update("expires_at", resource.ExpiresAt).Error; err != nil {
+			return err
+		}
+		return repo.addVersion(tx, spec.Name, resource.Value)
+	})
+}
+
+// addVersion records the encrypted value as the next version of the secret
+// and deletes versions over the retention count
+func (repo *secretRepository) addVersion(tx *gorm.DB, name, encryptedValue string) error {
+	var latest struct{ Version int }
+	if err := tx.Model(&SecretVersion{}).Select("COALESCE(MAX(version), 0) AS version").
+		Where("project_id = ? AND secret_name = ?", repo.project.ID, name).Scan(&latest).Error; err != nil {
+		return errors.Wrapf(err, "failed to find latest version of secret %s", name)
+	}
+	version := SecretVersion{
+		ID:             uuid.New(),
+		ProjectID:      repo.project.ID,
+		SecretName:     name,
+		EncryptedValue: encryptedValue,
+		Version:        latest.Version + 1,
+	}
+	if err := tx.Create(&version).Error; err != nil {
+		return errors.Wrapf(err, "failed to add version of secret %s", name)
 	}
-	return repo.db.Model(resource).Updates(resource).Error
+
+	retention := repo.VersionRetention
+	if retention <= 0 {
+		retention = DefaultSecretVersionRetention
+	}
+	if err := tx.Where("project_id = ? AND secret_name = ? AND version <= ?", repo.project.ID, name, version.Version-retention).
+		Delete(&SecretVersion{}).Error; err != nil {
+		return errors.Wrapf(err, "failed to delete old versions of secret %s", name)
+	}
+	return nil
 }
 
+// GetVersion returns the value a secret had at the version, versions over
+// the retention count are not found
+func (repo *secretRepository) GetVersion(name string, version int) (models.ProjectSecretItem, error) {
+	var r SecretVersion
+	if err := repo.db.Where("project_id = ? AND secret_name = ? AND version = ?", repo.project.ID, name, version).
+		Find(&r).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ProjectSecretItem{}, store.ErrResourceNotFound
+		}
+		return models.ProjectSecretItem{}, err
+	}
+	return Secret{Name: r.SecretName, Value: r.EncryptedValue}.ToSpec(repo.hash)
+}
+
+// ListVersions returns versions of a secret from the oldest to the latest
+// without their values
+func (repo *secretRepository) ListVersions(name string) ([]models.ProjectSecretVersion, error) {
+	resources := []SecretVersion{}
+	if err := repo.db.Where("project_id = ? AND secret_name = ?", repo.project.ID, name).Order("version").
+		Find(&resources).Error; err != nil {
+		return nil, err
+	}
+	if len(resources) == 0 {
+		return nil, store.ErrResourceNotFound
+	}
+	versions := make([]models.ProjectSecretVersion, 0, len(resources))
+	for _, res := range resources {
+		versions = append(versions, models.ProjectSecretVersion{
+			Name:      res.SecretName,
+			Version:   res.Version,
+			CreatedAt: res.CreatedAt,
+		})
+	}
+	return versions, nil
+}
+
+// GetByName fails with store.ErrSecretExpired for secrets past their expiry
 func (repo *secretRepository) GetByName(name string) (models.ProjectSecretItem, error) {
 	var r Secret
 	if err := repo.db.Where("name = ? AND project_id = ?", name, repo.project.ID).Find(&r).Error; err != nil {
@@ -107,9 +256,32 @@ func (repo *secretRepository) GetByName(name string) (models.ProjectSecretItem,
 		}
 		return models.ProjectSecretItem{}, err
 	}
+	if r.expired() {
+		return models.ProjectSecretItem{}, errors.Wrapf(store.ErrSecretExpired, "secret %s expired at %s",
+			name, r.ExpiresAt.UTC().Format(time.RFC3339))
+	}
 	return r.ToSpec(repo.hash)
 }
 
+// GetExpiring returns secrets of all projects expiring before the time,
+// from the earliest expiry
+func (repo *secretRepository) GetExpiring(before time.Time) ([]models.ExpiringProjectSecret, error) {
+	resources := []Secret{}
+	if err := repo.db.Preload("Project").Where("expires_at IS NOT NULL AND expires_at < ?", before).
+		Order("expires_at").Find(&resources).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to fetch expiring secrets")
+	}
+	secrets := make([]models.ExpiringProjectSecret, 0, len(resources))
+	for _, res := range resources {
+		secrets = append(secrets, models.ExpiringProjectSecret{
+			ProjectName: res.Project.Name,
+			SecretName:  res.Name,
+			ExpiresAt:   *res.ExpiresAt,
+		})
+	}
+	return secrets, nil
+}
+
 func (repo *secretRepository) GetByID(id uuid.UUID) (models.ProjectSecretItem, error) {
 	var r Secret
 	if err := repo.db.Where("id = ?", id).Find(&r).Error; err != nil {
@@ -121,13 +293,23 @@ func (repo *secretRepository) GetByID(id uuid.UUID) (models.ProjectSecretItem, e
 	return r.ToSpec(repo.hash)
 }
 
+// GetAll returns secrets past their expiry without their value, they are
+// still listed to be saved again
 func (repo *secretRepository) GetAll() ([]models.ProjectSecretItem, error) {
 	specs := []models.ProjectSecretItem{}
 	resources := []Secret{}
-	if err := repo.db.Find(&resources).Error; err != nil {
+	if err := repo.db.Where("project_id = ?", repo.project.ID).Find(&resources).Error; err != nil {
 		return specs, err
 	}
 	for _, res := range resources {
+		if res.expired() {
+			specs = append(specs, models.ProjectSecretItem{
+				ID:        res.ID,
+				Name:      res.Name,
+				ExpiresAt: res.ExpiresAt,
+			})
+			continue
+		}
 		adapted, err := res.ToSpec(repo.hash)
 		if err != nil {
 			return specs, errors.Wrap(err, "failed to adapt secret")
@@ -137,10 +319,160 @@ func (repo *secretRepository) GetAll() ([]models.ProjectSecretItem, error) {
 	return specs, nil
 }
 
+// Migrate re-encrypts all secrets of the project with toHash in a single
+// transaction. Encrypted values are backed up first, nothing is migrated if
+// any secret can't be decrypted with fromHash or read back with toHash
+func (repo *secretRepository) Migrate(fromHash, toHash models.ApplicationKey) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		scope := func(db *gorm.DB) *gorm.DB {
+			return db.Where("project_id = ?", repo.project.ID)
+		}
+		name := func(res Secret) string {
+			return res.Name
+		}
+		if err := migrateSecrets(tx, scope, name, fromHash, toHash, "of project "+repo.project.Name); err != nil {
+			return err
+		}
+		return migrateSecretVersions(tx, scope, fromHash, toHash, "of project "+repo.project.Name)
+	})
+}
+
+// RotateSecrets re-encrypts secrets of all projects with newKey in a single
+// transaction. Values already encrypted with newKey, e.g. registered while
+// the server used oldKey as its previous key, are kept as they are
+func (repo *secretRepository) RotateSecrets(oldKey, newKey models.ApplicationKey) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		scope := func(db *gorm.DB) *gorm.DB {
+			return db.Preload("Project")
+		}
+		name := func(res Secret) string {
+			return res.Project.Name + "/" + res.Name
+		}
+		if err := migrateSecrets(tx, scope, name, newKey.WithPrevious(oldKey), newKey, "of all projects"); err != nil {
+			return err
+		}
+		all := func(db *gorm.DB) *gorm.DB {
+			return db
+		}
+		return migrateSecretVersions(tx, all, newKey.WithPrevious(oldKey), newKey, "of all projects")
+	})
+}
+
+// migrateSecrets backs up and re-encrypts secrets selected by scope from
+// fromHash to toHash, values are read back before the transaction commits to
+// make sure none is lost
+func migrateSecrets(tx *gorm.DB, scope func(*gorm.DB) *gorm.DB, name func(Secret) string,
+	fromHash, toHash models.ApplicationKey, owner string) error {
+	resources := []Secret{}
+	if err := scope(tx).Find(&resources).Error; err != nil {
+		return errors.Wrap(err, "failed to fetch secrets")
+	}
+	if err := preMigrationBackup(tx, resources); err != nil {
+		return err
+	}
+
+	specs := make([]models.ProjectSecretItem, 0, len(resources))
+	var failedNames []string
+	for _, res := range resources {
+		spec, err := res.ToSpec(fromHash)
+		if err != nil {
+			failedNames = append(failedNames, name(res))
+			continue
+		}
+		specs = append(specs, spec)
+	}
+	if len(failedNames) > 0 {
+		sort.Strings(failedNames)
+		return errors.Wrapf(store.ErrSecretDecryption, "secrets %s %s with the current key",
+			strings.Join(failedNames, ", "), owner)
+	}
+
+	for _, spec := range specs {
+		resource, err := Secret{}.FromSpec(spec, models.ProjectSpec{}, toHash)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encrypt secret %s", spec.Name)
+		}
+		if err := tx.Model(&Secret{}).Where("id = ?", spec.ID).Update("value", resource.Value).Error; err != nil {
+			return errors.Wrapf(err, "failed to update secret %s", spec.Name)
+		}
+	}
+
+	migrated := []Secret{}
+	if err := scope(tx).Find(&migrated).Error; err != nil {
+		return errors.Wrap(err, "failed to fetch migrated secrets")
+	}
+	values := map[uuid.UUID]string{}
+	for _, spec := range specs {
+		values[spec.ID] = spec.Value
+	}
+	for _, res := range migrated {
+		spec, err := res.ToSpec(toHash)
+		if err != nil || spec.Value != values[res.ID] {
+			failedNames = append(failedNames, name(res))
+		}
+	}
+	if len(failedNames) > 0 {
+		sort.Strings(failedNames)
+		return errors.Wrapf(store.ErrSecretDecryption, "secrets %s %s with the new key",
+			strings.Join(failedNames, ", "), owner)
+	}
+	return nil
+}
+
+// migrateSecretVersions re-encrypts versions of secrets selected by scope
+// from fromHash to toHash
+func migrateSecretVersions(tx *gorm.DB, scope func(*gorm.DB) *gorm.DB, fromHash, toHash models.ApplicationKey, owner string) error {
+	versions := []SecretVersion{}
+	if err := scope(tx).Find(&versions).Error; err != nil {
+		return errors.Wrap(err, "failed to fetch secret versions")
+	}
+	var failedNames []string
+	for _, version := range versions {
+		spec, err := Secret{Name: version.SecretName, Value: version.EncryptedValue}.ToSpec(fromHash)
+		if err != nil {
+			failedNames = append(failedNames, fmt.Sprintf("%s@%d", version.SecretName, version.Version))
+			continue
+		}
+		resource, err := Secret{}.FromSpec(spec, models.ProjectSpec{}, toHash)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encrypt version %d of secret %s", version.Version, version.SecretName)
+		}
+		if err := tx.Model(&SecretVersion{}).Where("id = ?", version.ID).Update("encrypted_value", resource.Value).Error; err != nil {
+			return errors.Wrapf(err, "failed to update version %d of secret %s", version.Version, version.SecretName)
+		}
+	}
+	if len(failedNames) > 0 {
+		sort.Strings(failedNames)
+		return errors.Wrapf(store.ErrSecretDecryption, "secret versions %s %s with the current key",
+			strings.Join(failedNames, ", "), owner)
+	}
+	return nil
+}
+
+// preMigrationBackup copies encrypted values of secrets to the backup table
+// before they are migrated
+func preMigrationBackup(tx *gorm.DB, resources []Secret) error {
+	for _, res := range resources {
+		backup := SecretBackup{
+			ID:        uuid.New(),
+			SecretID:  res.ID,
+			ProjectID: res.ProjectID,
+			Name:      res.Name,
+			Value:     res.Value,
+		}
+		if err := tx.Create(&backup).Error; err != nil {
+			return errors.Wrapf(err, "failed to backup secret %s", res.Name)
+		}
+	}
+	return nil
+}
+
 func NewSecretRepository(db *gorm.DB, project models.ProjectSpec, hash models.ApplicationKey) *secretRepository {
 	return &secretRepository{
 		db:      db,
 		project: project,
 		hash:    hash,
+
+		VersionRetention: DefaultSecretVersionRetention,
 	}
 }
@@ -5,10 +5,13 @@ package postgres
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
 	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,7 +30,7 @@ func TestSecretRepository(t *testing.T) {
 		if !ok {
 			panic("unable to find TEST_OPTIMUS_DB_URL env var")
 		}
-		dbConn, err := Connect(dbURL, 1, 1)
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
 		if err != nil {
 			panic(err)
 		}
@@ -38,7 +41,7 @@ func TestSecretRepository(t *testing.T) {
 		if err := m.Drop(); err != nil {
 			panic(err)
 		}
-		if err := Migrate(dbURL); err != nil {
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
 			panic(err)
 		}
 
@@ -164,4 +167,303 @@ func TestSecretRepository(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, "g-optimus", checkModel.Name)
 	})
+	t.Run("GetAll", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		testModels := []models.ProjectSecretItem{}
+		testModels = append(testModels, testConfigs...)
+
+		otherProjectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "t-optimus-other-project",
+		}
+		assert.Nil(t, NewProjectRepository(db, hash).Save(otherProjectSpec))
+
+		repo := NewSecretRepository(db, projectSpec, hash)
+		assert.Nil(t, repo.Insert(testModels[0]))
+		otherRepo := NewSecretRepository(db, otherProjectSpec, hash)
+		assert.Nil(t, otherRepo.Insert(testModels[2]))
+
+		checkModels, err := repo.GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, checkModels, 1)
+		assert.Equal(t, "g-optimus", checkModels[0].Name)
+		assert.Equal(t, "secret", checkModels[0].Value)
+	})
+	t.Run("Expiry", func(t *testing.T) {
+		t.Run("should fail to read expired secrets", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			repo := NewSecretRepository(db, projectSpec, hash)
+			expiresAt := time.Now().Add(-time.Minute)
+			assert.Nil(t, repo.Save(models.ProjectSecretItem{Name: "g-optimus", Value: "secret", ExpiresAt: &expiresAt}))
+
+			_, err := repo.GetByName("g-optimus")
+			assert.True(t, errors.Is(err, store.ErrSecretExpired))
+
+			// expired secrets are listed without their value
+			checkModels, err := repo.GetAll()
+			assert.Nil(t, err)
+			assert.Len(t, checkModels, 1)
+			assert.Equal(t, "g-optimus", checkModels[0].Name)
+			assert.Empty(t, checkModels[0].Value)
+
+			// saving again without an expiry clears it
+			assert.Nil(t, repo.Save(models.ProjectSecretItem{Name: "g-optimus", Value: "secret"}))
+			checkModel, err := repo.GetByName("g-optimus")
+			assert.Nil(t, err)
+			assert.Nil(t, checkModel.ExpiresAt)
+		})
+		t.Run("should list secrets expiring before a time", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			repo := NewSecretRepository(db, projectSpec, hash)
+			soon := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+			later := time.Now().Add(30 * 24 * time.Hour)
+			assert.Nil(t, repo.Save(models.ProjectSecretItem{Name: "g-optimus", Value: "secret", ExpiresAt: &soon}))
+			assert.Nil(t, repo.Save(models.ProjectSecretItem{Name: "t-optimus", Value: "secret", ExpiresAt: &later}))
+			assert.Nil(t, repo.Save(models.ProjectSecretItem{Name: "n-optimus", Value: "secret"}))
+
+			secrets, err := repo.GetExpiring(time.Now().Add(7 * 24 * time.Hour))
+			assert.Nil(t, err)
+			assert.Len(t, secrets, 1)
+			assert.Equal(t, projectSpec.Name, secrets[0].ProjectName)
+			assert.Equal(t, "g-optimus", secrets[0].SecretName)
+			assert.True(t, soon.Equal(secrets[0].ExpiresAt))
+		})
+	})
+	t.Run("Versions", func(t *testing.T) {
+		t.Run("should keep a version for every saved value", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			repo := NewSecretRepository(db, projectSpec, hash)
+			for _, value := range []string{"secret-1", "secret-2", "secret-3"} {
+				assert.Nil(t, repo.Save(models.ProjectSecretItem{Name: "g-optimus", Value: value}))
+			}
+
+			versions, err := repo.ListVersions("g-optimus")
+			assert.Nil(t, err)
+			assert.Len(t, versions, 3)
+			for i, version := range versions {
+				assert.Equal(t, "g-optimus", version.Name)
+				assert.Equal(t, i+1, version.Version)
+			}
+
+			checkModel, err := repo.GetVersion("g-optimus", 1)
+			assert.Nil(t, err)
+			assert.Equal(t, "secret-1", checkModel.Value)
+			checkModel, err = repo.GetByName("g-optimus")
+			assert.Nil(t, err)
+			assert.Equal(t, "secret-3", checkModel.Value)
+
+			_, err = repo.GetVersion("g-optimus", 4)
+			assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+			_, err = repo.ListVersions("t-optimus")
+			assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+		})
+		t.Run("should delete versions over the retention count", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			repo := NewSecretRepository(db, projectSpec, hash)
+			repo.VersionRetention = 2
+			for _, value := range []string{"secret-1", "secret-2", "secret-3", "secret-4"} {
+				assert.Nil(t, repo.Save(models.ProjectSecretItem{Name: "g-optimus", Value: value}))
+			}
+
+			versions, err := repo.ListVersions("g-optimus")
+			assert.Nil(t, err)
+			assert.Len(t, versions, 2)
+			assert.Equal(t, 3, versions[0].Version)
+			assert.Equal(t, 4, versions[1].Version)
+
+			_, err = repo.GetVersion("g-optimus", 2)
+			assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+		})
+		t.Run("should keep the value of secrets saved before versions as the first version", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			repo := NewSecretRepository(db, projectSpec, hash)
+			resource, err := Secret{}.FromSpec(testConfigs[0], projectSpec, hash)
+			assert.Nil(t, err)
+			assert.Nil(t, db.Create(&resource).Error)
+
+			assert.Nil(t, repo.Save(models.ProjectSecretItem{Name: "g-optimus", Value: "new-secret"}))
+
+			checkModel, err := repo.GetVersion("g-optimus", 1)
+			assert.Nil(t, err)
+			assert.Equal(t, "secret", checkModel.Value)
+			checkModel, err = repo.GetVersion("g-optimus", 2)
+			assert.Nil(t, err)
+			assert.Equal(t, "new-secret", checkModel.Value)
+		})
+		t.Run("should re-encrypt versions when secrets are migrated", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			repo := NewSecretRepository(db, projectSpec, hash)
+			assert.Nil(t, repo.Save(models.ProjectSecretItem{Name: "g-optimus", Value: "secret-1"}))
+			assert.Nil(t, repo.Save(models.ProjectSecretItem{Name: "g-optimus", Value: "secret-2"}))
+			newHash, _ := models.NewApplicationSecret("32charsnewhashnewhashnewhashnewh")
+
+			assert.Nil(t, repo.Migrate(hash, newHash))
+
+			checkModel, err := NewSecretRepository(db, projectSpec, newHash).GetVersion("g-optimus", 1)
+			assert.Nil(t, err)
+			assert.Equal(t, "secret-1", checkModel.Value)
+		})
+	})
+	t.Run("Migrate", func(t *testing.T) {
+		newHash, _ := models.NewApplicationSecret("32charsnewhashnewhashnewhashnewh")
+		otherHash, _ := models.NewApplicationSecret("32charsotherhashotherhashotherha")
+
+		t.Run("should re-encrypt all secrets with the new key and back up old values", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			repo := NewSecretRepository(db, projectSpec, hash)
+			assert.Nil(t, repo.Insert(testConfigs[0]))
+			assert.Nil(t, repo.Insert(testConfigs[2]))
+
+			err := repo.Migrate(hash, newHash)
+			assert.Nil(t, err)
+
+			migratedRepo := NewSecretRepository(db, projectSpec, newHash)
+			checkModels, err := migratedRepo.GetAll()
+			assert.Nil(t, err)
+			assert.Len(t, checkModels, 2)
+			checkModel, err := migratedRepo.GetByName("t-optimus")
+			assert.Nil(t, err)
+			assert.Equal(t, "super-secret", checkModel.Value)
+
+			// values can't be read with the old key anymore
+			_, err = repo.GetAll()
+			assert.NotNil(t, err)
+
+			var backups []SecretBackup
+			assert.Nil(t, db.Where("project_id = ?", projectSpec.ID).Find(&backups).Error)
+			assert.Len(t, backups, 2)
+			for _, backup := range backups {
+				restored, err := Secret{ID: backup.SecretID, Name: backup.Name, Value: backup.Value}.ToSpec(hash)
+				assert.Nil(t, err)
+				assert.NotEmpty(t, restored.Value)
+			}
+		})
+		t.Run("should migrate nothing and list secrets that can't be decrypted with the old key", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			repo := NewSecretRepository(db, projectSpec, hash)
+			assert.Nil(t, repo.Insert(testConfigs[0]))
+			// encrypted with another key than the rest
+			assert.Nil(t, NewSecretRepository(db, projectSpec, otherHash).Insert(testConfigs[2]))
+
+			err := repo.Migrate(hash, newHash)
+			assert.True(t, errors.Is(err, store.ErrSecretDecryption))
+			assert.Contains(t, err.Error(), "secrets t-optimus of project t-optimus-project")
+			assert.NotContains(t, err.Error(), "g-optimus")
+
+			checkModel, err := repo.GetByName("g-optimus")
+			assert.Nil(t, err)
+			assert.Equal(t, "secret", checkModel.Value)
+
+			var backups []SecretBackup
+			assert.Nil(t, db.Where("project_id = ?", projectSpec.ID).Find(&backups).Error)
+			assert.Empty(t, backups)
+		})
+		t.Run("should migrate nothing if the old key is wrong", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			repo := NewSecretRepository(db, projectSpec, hash)
+			assert.Nil(t, repo.Insert(testConfigs[0]))
+			assert.Nil(t, repo.Insert(testConfigs[2]))
+
+			err := repo.Migrate(otherHash, newHash)
+			assert.True(t, errors.Is(err, store.ErrSecretDecryption))
+			assert.Contains(t, err.Error(), "secrets g-optimus, t-optimus of project t-optimus-project")
+
+			checkModels, err := repo.GetAll()
+			assert.Nil(t, err)
+			assert.Len(t, checkModels, 2)
+		})
+		t.Run("should succeed for projects without secrets", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			repo := NewSecretRepository(db, projectSpec, hash)
+
+			err := repo.Migrate(hash, newHash)
+			assert.Nil(t, err)
+		})
+	})
+	t.Run("RotateSecrets", func(t *testing.T) {
+		newHash, _ := models.NewApplicationSecret("32charsnewhashnewhashnewhashnewh")
+		otherHash, _ := models.NewApplicationSecret("32charsotherhashotherhashotherha")
+		otherProjectSpec := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "t-optimus-other-project",
+		}
+
+		t.Run("should re-encrypt secrets of all projects with the new key", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			assert.Nil(t, NewProjectRepository(db, hash).Save(otherProjectSpec))
+			repo := NewSecretRepository(db, projectSpec, hash)
+			assert.Nil(t, repo.Insert(testConfigs[0]))
+			assert.Nil(t, NewSecretRepository(db, otherProjectSpec, hash).Insert(testConfigs[2]))
+
+			err := repo.RotateSecrets(hash, newHash)
+			assert.Nil(t, err)
+
+			checkModel, err := NewSecretRepository(db, projectSpec, newHash).GetByName("g-optimus")
+			assert.Nil(t, err)
+			assert.Equal(t, "secret", checkModel.Value)
+			checkModel, err = NewSecretRepository(db, otherProjectSpec, newHash).GetByName("t-optimus")
+			assert.Nil(t, err)
+			assert.Equal(t, "super-secret", checkModel.Value)
+
+			var backups []SecretBackup
+			assert.Nil(t, db.Find(&backups).Error)
+			assert.Len(t, backups, 2)
+		})
+		t.Run("should keep secrets already encrypted with the new key", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			repo := NewSecretRepository(db, projectSpec, hash)
+			assert.Nil(t, repo.Insert(testConfigs[0]))
+			// registered while the server used the old key as its previous key
+			assert.Nil(t, NewSecretRepository(db, projectSpec, newHash.WithPrevious(hash)).Insert(testConfigs[2]))
+
+			err := repo.RotateSecrets(hash, newHash)
+			assert.Nil(t, err)
+
+			checkModels, err := NewSecretRepository(db, projectSpec, newHash).GetAll()
+			assert.Nil(t, err)
+			assert.Len(t, checkModels, 2)
+		})
+		t.Run("should rotate nothing and list secrets that can't be decrypted", func(t *testing.T) {
+			db := DBSetup()
+			defer db.Close()
+			repo := NewSecretRepository(db, projectSpec, hash)
+			assert.Nil(t, repo.Insert(testConfigs[0]))
+			assert.Nil(t, NewSecretRepository(db, projectSpec, otherHash).Insert(testConfigs[2]))
+
+			err := repo.RotateSecrets(hash, newHash)
+			assert.True(t, errors.Is(err, store.ErrSecretDecryption))
+			assert.Contains(t, err.Error(), "secrets t-optimus-project/t-optimus of all projects")
+
+			checkModel, err := repo.GetByName("g-optimus")
+			assert.Nil(t, err)
+			assert.Equal(t, "secret", checkModel.Value)
+		})
+	})
+	t.Run("GetByNameWithPreviousKey", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		assert.Nil(t, NewSecretRepository(db, projectSpec, hash).Insert(testConfigs[0]))
+		newHash, _ := models.NewApplicationSecret("32charsnewhashnewhashnewhashnewh")
+
+		// secrets not rotated yet are read with the previous key
+		checkModel, err := NewSecretRepository(db, projectSpec, newHash.WithPrevious(hash)).GetByName("g-optimus")
+		assert.Nil(t, err)
+		assert.Equal(t, "secret", checkModel.Value)
+
+		_, err = NewSecretRepository(db, projectSpec, newHash).GetByName("g-optimus")
+		assert.NotNil(t, err)
+	})
 }
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// SLABreach is a run of a job that didn't start within its sla duration,
+// projects and jobs are referenced by name so breaches can be reported
+// after the jobs are removed
+type SLABreach struct {
+	ID            int64  `gorm:"primary_key"`
+	ProjectName   string `gorm:"not null"`
+	NamespaceName string
+	JobName       string    `gorm:"not null"`
+	ScheduledAt   time.Time `gorm:"not null"`
+	DueAt         time.Time `gorm:"not null"`
+	SLADuration   int64     `gorm:"not null"` //duration in nanos
+	StartedAt     *time.Time
+	DetectedAt    time.Time `gorm:"not null"`
+}
+
+func (SLABreach) TableName() string {
+	return "sla_breach"
+}
+
+type slaBreachRepository struct {
+	db *gorm.DB
+}
+
+func (repo *slaBreachRepository) Save(ctx context.Context, breach models.SLABreach) error {
+	resource := SLABreach{
+		ProjectName:   breach.ProjectName,
+		NamespaceName: breach.NamespaceName,
+		JobName:       breach.JobName,
+		ScheduledAt:   breach.ScheduledAt,
+		DueAt:         breach.DueAt,
+		SLADuration:   int64(breach.SLADuration),
+		DetectedAt:    breach.DetectedAt,
+	}
+	if !breach.StartedAt.IsZero() {
+		resource.StartedAt = &breach.StartedAt
+	}
+
+	// breaches of a run are detected on every check till it starts, only
+	// the first is kept
+	result := repo.db.Set("gorm:insert_option", "ON CONFLICT (project_name, job_name, scheduled_at) DO NOTHING").
+		Create(&resource)
+	if result.Error != nil {
+		return errors.Wrapf(result.Error, "failed to save sla breach of %s at %s", breach.JobName, breach.ScheduledAt)
+	}
+	if result.RowsAffected == 0 {
+		return store.ErrResourceExists
+	}
+	return nil
+}
+
+func (repo *slaBreachRepository) GetAll(ctx context.Context, projectName string, filter models.SLABreachFilter) ([]models.SLABreach, error) {
+	query := repo.db.Where("project_name = ?", projectName)
+	if filter.JobName != "" {
+		query = query.Where("job_name = ?", filter.JobName)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("scheduled_at >= ?", filter.Since)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var resources []SLABreach
+	if err := query.Order("scheduled_at DESC, job_name").Find(&resources).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to fetch sla breaches")
+	}
+	breaches := []models.SLABreach{}
+	for _, resource := range resources {
+		breach := models.SLABreach{
+			ProjectName:   resource.ProjectName,
+			NamespaceName: resource.NamespaceName,
+			JobName:       resource.JobName,
+			ScheduledAt:   resource.ScheduledAt,
+			DueAt:         resource.DueAt,
+			SLADuration:   time.Duration(resource.SLADuration),
+			DetectedAt:    resource.DetectedAt,
+		}
+		if resource.StartedAt != nil {
+			breach.StartedAt = *resource.StartedAt
+		}
+		breaches = append(breaches, breach)
+	}
+	return breaches, nil
+}
+
+func NewSLABreachRepository(db *gorm.DB) *slaBreachRepository {
+	return &slaBreachRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,102 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSLABreachRepository(t *testing.T) {
+	DBSetup := func() *gorm.DB {
+		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
+		if !ok {
+			panic("unable to find TEST_OPTIMUS_DB_URL env var")
+		}
+		dbConn, err := Connect(dbURL, 1, 1, PoolerModeNone)
+		if err != nil {
+			panic(err)
+		}
+		m, err := NewHTTPFSMigrator(dbURL)
+		if err != nil {
+			panic(err)
+		}
+		if err := m.Drop(); err != nil {
+			panic(err)
+		}
+		if err := Migrate(dbURL, PoolerModeNone); err != nil {
+			panic(err)
+		}
+		return dbConn
+	}
+
+	ctx := context.Background()
+	scheduledAt := time.Date(2021, 3, 1, 2, 0, 0, 0, time.UTC)
+	newBreach := func(projectName, jobName string, scheduledAt time.Time) models.SLABreach {
+		return models.SLABreach{
+			ProjectName:   projectName,
+			NamespaceName: "data-namespace",
+			JobName:       jobName,
+			ScheduledAt:   scheduledAt,
+			DueAt:         scheduledAt.Add(24 * time.Hour),
+			SLADuration:   2 * time.Hour,
+			DetectedAt:    scheduledAt.Add(27 * time.Hour),
+		}
+	}
+
+	t.Run("should save breaches and list the ones of the project from the latest run", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		repo := NewSLABreachRepository(db)
+
+		started := newBreach("a-data-project", "daily-orders", scheduledAt)
+		started.StartedAt = scheduledAt.Add(26 * time.Hour)
+		assert.Nil(t, repo.Save(ctx, started))
+		assert.Nil(t, repo.Save(ctx, newBreach("a-data-project", "daily-orders", scheduledAt.AddDate(0, 0, 1))))
+		assert.Nil(t, repo.Save(ctx, newBreach("a-data-project", "daily-refunds", scheduledAt)))
+		assert.Nil(t, repo.Save(ctx, newBreach("another-project", "daily-orders", scheduledAt)))
+
+		breaches, err := repo.GetAll(ctx, "a-data-project", models.SLABreachFilter{})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(breaches))
+		assert.Equal(t, scheduledAt.AddDate(0, 0, 1), breaches[0].ScheduledAt.UTC())
+		assert.True(t, breaches[0].StartedAt.IsZero())
+		assert.Equal(t, "daily-orders", breaches[1].JobName)
+		assert.Equal(t, 2*time.Hour, breaches[1].SLADuration)
+		assert.Equal(t, started.StartedAt, breaches[1].StartedAt.UTC())
+		assert.Equal(t, "daily-refunds", breaches[2].JobName)
+
+		breaches, err = repo.GetAll(ctx, "a-data-project", models.SLABreachFilter{
+			JobName: "daily-orders",
+			Since:   scheduledAt.Add(time.Hour),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(breaches))
+
+		breaches, err = repo.GetAll(ctx, "a-data-project", models.SLABreachFilter{Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(breaches))
+	})
+	t.Run("should keep the first breach recorded for a run", func(t *testing.T) {
+		db := DBSetup()
+		defer db.Close()
+		repo := NewSLABreachRepository(db)
+
+		assert.Nil(t, repo.Save(ctx, newBreach("a-data-project", "daily-orders", scheduledAt)))
+		later := newBreach("a-data-project", "daily-orders", scheduledAt)
+		later.DetectedAt = later.DetectedAt.Add(time.Hour)
+		assert.Equal(t, store.ErrResourceExists, repo.Save(ctx, later))
+
+		breaches, err := repo.GetAll(ctx, "a-data-project", models.SLABreachFilter{})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(breaches))
+		assert.Equal(t, scheduledAt.Add(27*time.Hour), breaches[0].DetectedAt.UTC())
+	})
+}
@@ -0,0 +1,196 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+var (
+	errEmptyJobName = errors.New("job name cannot be an empty string")
+)
+
+type JobRepository struct {
+	ObjectReader store.ObjectReader
+	ObjectWriter store.ObjectWriter
+	Client       Client
+	Bucket       string
+	Prefix       string
+	Suffix       string
+}
+
+func (repo *JobRepository) Save(ctx context.Context, j models.Job) (err error) {
+	dst, err := repo.ObjectWriter.NewWriter(ctx, repo.Bucket, repo.pathFor(j))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if derr := dst.Close(); derr != nil {
+			if err == nil {
+				err = derr
+			} else {
+				err = errors.Wrap(err, derr.Error())
+			}
+		}
+	}()
+	src := bytes.NewBuffer(j.Contents)
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+func (repo *JobRepository) Delete(ctx context.Context, namespace models.NamespaceSpec, jobName string) error {
+	if strings.TrimSpace(jobName) == "" {
+		return errEmptyJobName
+	}
+
+	filePath := fmt.Sprintf("%s%s", path.Join(repo.Prefix, namespace.ID.String(), jobName), repo.Suffix)
+	if err := repo.exists(ctx, filePath); err != nil {
+		return errors.Wrap(err, jobName)
+	}
+
+	if _, err := repo.Client.DeleteObject(ctx, &awss3.DeleteObjectInput{
+		Bucket: aws.String(repo.Bucket),
+		Key:    aws.String(filePath),
+	}); err != nil {
+		return errors.Wrapf(err, "failed to delete %s", jobName)
+	}
+	return nil
+}
+
+func (repo *JobRepository) GetAll(ctx context.Context) ([]models.Job, error) {
+	keys, err := repo.listKeys(ctx, repo.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []models.Job
+	for _, key := range keys {
+		contents, err := repo.read(key)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, models.Job{
+			Name:     repo.jobNameFromPath(key),
+			Contents: contents,
+		})
+	}
+	return jobs, nil
+}
+
+func (repo *JobRepository) ListNames(ctx context.Context, namespace models.NamespaceSpec) ([]string, error) {
+	keys, err := repo.listKeys(ctx, path.Join(repo.Prefix, namespace.ID.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	var jobNames []string
+	for _, key := range keys {
+		jobNames = append(jobNames, repo.jobNameFromPath(key))
+	}
+	return jobNames, nil
+}
+
+func (repo *JobRepository) GetByName(ctx context.Context, jobName string) (models.Job, error) {
+	if strings.TrimSpace(jobName) == "" {
+		return models.Job{}, errEmptyJobName
+	}
+
+	filePath := fmt.Sprintf("%s%s", path.Join(repo.Prefix, jobName), repo.Suffix)
+	if err := repo.exists(ctx, filePath); err != nil {
+		return models.Job{}, errors.Wrap(err, jobName)
+	}
+
+	contents, err := repo.read(filePath)
+	if err != nil {
+		return models.Job{}, err
+	}
+	return models.Job{
+		Name:     jobName,
+		Contents: contents,
+	}, nil
+}
+
+// exists returns models.ErrNoSuchJob if there is no object at filePath
+func (repo *JobRepository) exists(ctx context.Context, filePath string) error {
+	_, err := repo.Client.HeadObject(ctx, &awss3.HeadObjectInput{
+		Bucket: aws.String(repo.Bucket),
+		Key:    aws.String(filePath),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return models.ErrNoSuchJob
+		}
+		return err
+	}
+	return nil
+}
+
+// listKeys returns the keys under prefix with the job suffix, following
+// continuation tokens for buckets with more keys than a single page
+func (repo *JobRepository) listKeys(ctx context.Context, prefix string) ([]string, error) {
+	paginator := awss3.NewListObjectsV2Paginator(repo.Client, &awss3.ListObjectsV2Input{
+		Bucket: aws.String(repo.Bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	var keys []string
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list objects in %s", repo.Bucket)
+		}
+		for _, object := range page.Contents {
+			if key := aws.ToString(object.Key); strings.HasSuffix(key, repo.Suffix) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys, nil
+}
+
+func (repo *JobRepository) read(filePath string) ([]byte, error) {
+	reader, err := repo.ObjectReader.NewReader(repo.Bucket, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var b bytes.Buffer
+	if _, err := b.ReadFrom(reader); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (repo *JobRepository) pathFor(j models.Job) string {
+	return fmt.Sprintf("%s%s", path.Join(repo.Prefix, j.NamespaceID, j.Name), repo.Suffix)
+}
+
+func (repo *JobRepository) jobNameFromPath(filePath string) string {
+	jobFileName := path.Base(filePath)
+	return strings.TrimSuffix(jobFileName, repo.Suffix)
+}
+
+// NewJobRepository constructs a new s3 backed JobRepository, keys are
+// created under prefix in bucket
+func NewJobRepository(bucket, prefix, suffix string, c Client) *JobRepository {
+	return &JobRepository{
+		ObjectReader: &s3ObjectReader{c: c},
+		ObjectWriter: &S3ObjectWriter{Client: c},
+		Client:       c,
+		Bucket:       bucket,
+		Prefix:       strings.Trim(prefix, "/"),
+		Suffix:       suffix,
+	}
+}
@@ -0,0 +1,115 @@
+package s3_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store/s3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobRepository(t *testing.T) {
+	ctx := context.Background()
+	bucket := "scheduled-tasks"
+	namespace := models.NamespaceSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "dev-team-1",
+	}
+	testJob := models.Job{
+		Name:        "test",
+		NamespaceID: namespace.ID.String(),
+		Contents:    []byte("print('this is a job')"),
+	}
+	jobKey := fmt.Sprintf("resources/jobs/%s/test.py", namespace.ID.String())
+
+	t.Run("Save", func(t *testing.T) {
+		t.Run("should write job contents under prefix without leading slash", func(t *testing.T) {
+			client := newMemoryClient(bucket)
+			repo := s3.NewJobRepository(bucket, "/resources/jobs", ".py", client)
+
+			err := repo.Save(ctx, testJob)
+			assert.Nil(t, err)
+			assert.Equal(t, testJob.Contents, client.objects[jobKey])
+		})
+		t.Run("should fail if bucket doesn't exist", func(t *testing.T) {
+			repo := s3.NewJobRepository("unknown", "resources/jobs", ".py", newMemoryClient(bucket))
+
+			err := repo.Save(ctx, testJob)
+			assert.NotNil(t, err)
+		})
+	})
+	t.Run("GetByName", func(t *testing.T) {
+		t.Run("should read job contents", func(t *testing.T) {
+			client := newMemoryClient(bucket)
+			client.objects["resources/jobs/test.py"] = testJob.Contents
+			repo := s3.NewJobRepository(bucket, "resources/jobs", ".py", client)
+
+			job, err := repo.GetByName(ctx, "test")
+			assert.Nil(t, err)
+			assert.Equal(t, "test", job.Name)
+			assert.Equal(t, testJob.Contents, job.Contents)
+		})
+		t.Run("should return ErrNoSuchJob if job doesn't exist", func(t *testing.T) {
+			repo := s3.NewJobRepository(bucket, "resources/jobs", ".py", newMemoryClient(bucket))
+
+			_, err := repo.GetByName(ctx, "test")
+			assert.True(t, errors.Is(err, models.ErrNoSuchJob))
+		})
+	})
+	t.Run("GetAll", func(t *testing.T) {
+		t.Run("should read jobs from all pages with the suffix", func(t *testing.T) {
+			client := newMemoryClient(bucket)
+			client.pageSize = 1
+			client.objects["resources/jobs/a.py"] = []byte("a")
+			client.objects["resources/jobs/b.py"] = []byte("b")
+			client.objects["resources/jobs/__lib.txt"] = []byte("lib")
+			repo := s3.NewJobRepository(bucket, "resources/jobs", ".py", client)
+
+			jobs, err := repo.GetAll(ctx)
+			assert.Nil(t, err)
+			assert.Equal(t, []models.Job{
+				{Name: "a", Contents: []byte("a")},
+				{Name: "b", Contents: []byte("b")},
+			}, jobs)
+		})
+	})
+	t.Run("ListNames", func(t *testing.T) {
+		t.Run("should list job names of the namespace", func(t *testing.T) {
+			client := newMemoryClient(bucket)
+			client.objects[jobKey] = testJob.Contents
+			client.objects["resources/jobs/other-namespace/other.py"] = []byte("other")
+			repo := s3.NewJobRepository(bucket, "resources/jobs", ".py", client)
+
+			names, err := repo.ListNames(ctx, namespace)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"test"}, names)
+		})
+	})
+	t.Run("Delete", func(t *testing.T) {
+		t.Run("should delete job of the namespace", func(t *testing.T) {
+			client := newMemoryClient(bucket)
+			client.objects[jobKey] = testJob.Contents
+			repo := s3.NewJobRepository(bucket, "resources/jobs", ".py", client)
+
+			err := repo.Delete(ctx, namespace, "test")
+			assert.Nil(t, err)
+			assert.Empty(t, client.objects)
+		})
+		t.Run("should return ErrNoSuchJob if job doesn't exist", func(t *testing.T) {
+			repo := s3.NewJobRepository(bucket, "resources/jobs", ".py", newMemoryClient(bucket))
+
+			err := repo.Delete(ctx, namespace, "test")
+			assert.True(t, errors.Is(err, models.ErrNoSuchJob))
+		})
+		t.Run("should fail if job name is empty", func(t *testing.T) {
+			repo := s3.NewJobRepository(bucket, "resources/jobs", ".py", newMemoryClient(bucket))
+
+			err := repo.Delete(ctx, namespace, " ")
+			assert.NotNil(t, err)
+		})
+	})
+}
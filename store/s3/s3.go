@@ -0,0 +1,153 @@
+package s3
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/pkg/errors"
+)
+
+const (
+	// UploadPartSize is the size of the parts objects are uploaded in, objects
+	// larger than a single part are uploaded using multipart upload
+	UploadPartSize = manager.DefaultUploadPartSize
+
+	defaultRegion = "us-east-1"
+)
+
+// Client is the part of the s3 api used to read and write objects
+type Client interface {
+	manager.UploadAPIClient
+	awss3.HeadBucketAPIClient
+	awss3.HeadObjectAPIClient
+	awss3.ListObjectsV2APIClient
+	GetObject(ctx context.Context, params *awss3.GetObjectInput, optFns ...func(*awss3.Options)) (*awss3.GetObjectOutput, error)
+	DeleteObject(ctx context.Context, params *awss3.DeleteObjectInput, optFns ...func(*awss3.Options)) (*awss3.DeleteObjectOutput, error)
+}
+
+// Credentials are used to authenticate with aws s3 or any s3 compatible
+// storage like minio, stored as json in project secret
+type Credentials struct {
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	SessionToken    string `json:"session_token"`
+	Region          string `json:"region"`
+
+	// Endpoint of s3 compatible storage, buckets are addressed using
+	// path style when it is set
+	Endpoint string `json:"endpoint"`
+}
+
+// NewClient creates a s3 client authenticated with the json encoded Credentials
+func NewClient(secret string) (*awss3.Client, error) {
+	var creds Credentials
+	if err := json.Unmarshal([]byte(secret), &creds); err != nil {
+		return nil, errors.Wrap(err, "failed to parse s3 credentials")
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, errors.New("s3 credentials require access_key_id and secret_access_key")
+	}
+	if creds.Region == "" {
+		creds.Region = defaultRegion
+	}
+
+	cfg := aws.Config{
+		Region:      creds.Region,
+		Credentials: credentials.NewStaticCredentialsProvider(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken),
+	}
+	return awss3.NewFromConfig(cfg, func(o *awss3.Options) {
+		if creds.Endpoint != "" {
+			o.EndpointResolver = awss3.EndpointResolverFromURL(creds.Endpoint)
+			o.UsePathStyle = true
+		}
+	}), nil
+}
+
+// ParseURL returns the bucket and the key prefix of a s3://bucket/prefix url
+func ParseURL(storageURL *url.URL) (bucket, prefix string, err error) {
+	if storageURL.Scheme != "s3" {
+		return "", "", errors.Errorf("invalid scheme %s, expected s3", storageURL.Scheme)
+	}
+	if storageURL.Hostname() == "" {
+		return "", "", errors.Errorf("bucket missing in %s", storageURL.String())
+	}
+	return storageURL.Hostname(), strings.Trim(storageURL.Path, "/"), nil
+}
+
+type S3ObjectWriter struct {
+	Client Client
+}
+
+// NewWriter streams the object to the bucket, the upload is complete once the
+// writer is closed
+func (s *S3ObjectWriter) NewWriter(ctx context.Context, bucket, path string) (io.WriteCloser, error) {
+	if _, err := s.Client.HeadBucket(ctx, &awss3.HeadBucketInput{Bucket: aws.String(bucket)}); err != nil {
+		return nil, errors.Wrapf(err, "failed to access bucket %s", bucket)
+	}
+
+	pr, pw := io.Pipe()
+	w := &uploadWriter{
+		pw:     pw,
+		done:   make(chan error, 1),
+		bucket: bucket,
+		key:    strings.TrimPrefix(path, "/"),
+	}
+	uploader := manager.NewUploader(s.Client, func(u *manager.Uploader) {
+		u.PartSize = UploadPartSize
+	})
+	go func() {
+		_, err := uploader.Upload(ctx, &awss3.PutObjectInput{
+			Bucket: aws.String(w.bucket),
+			Key:    aws.String(w.key),
+			Body:   pr,
+		})
+		// unblock pending writes if upload stopped before reading everything
+		pr.CloseWithError(err)
+		w.done <- err
+	}()
+	return w, nil
+}
+
+// uploadWriter pipes the written bytes to a running upload
+type uploadWriter struct {
+	pw     *io.PipeWriter
+	done   chan error
+	bucket string
+	key    string
+}
+
+func (w *uploadWriter) Write(p []byte) (int, error) {
+	return w.pw.Write(p)
+}
+
+func (w *uploadWriter) Close() error {
+	if err := w.pw.Close(); err != nil {
+		return err
+	}
+	if err := <-w.done; err != nil {
+		return errors.Wrapf(err, "failed to upload s3://%s/%s", w.bucket, w.key)
+	}
+	return nil
+}
+
+type s3ObjectReader struct {
+	c Client
+}
+
+func (s *s3ObjectReader) NewReader(bucket, path string) (io.ReadCloser, error) {
+	out, err := s.c.GetObject(context.Background(), &awss3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(strings.TrimPrefix(path, "/")),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read s3://%s/%s", bucket, path)
+	}
+	return out.Body, nil
+}
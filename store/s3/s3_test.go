@@ -0,0 +1,225 @@
+package s3_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/odpf/optimus/store/s3"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryClient is an in memory s3 bucket
+type memoryClient struct {
+	mu         sync.Mutex
+	bucket     string
+	objects    map[string][]byte
+	parts      map[string][][]byte
+	multiparts int
+	// pageSize limits the number of keys returned per list call
+	pageSize int
+}
+
+func newMemoryClient(bucket string) *memoryClient {
+	return &memoryClient{
+		bucket:   bucket,
+		objects:  map[string][]byte{},
+		parts:    map[string][][]byte{},
+		pageSize: 1000,
+	}
+}
+
+func (c *memoryClient) checkBucket(bucket *string) error {
+	if aws.ToString(bucket) != c.bucket {
+		return &types.NoSuchBucket{}
+	}
+	return nil
+}
+
+func (c *memoryClient) HeadBucket(ctx context.Context, params *awss3.HeadBucketInput, optFns ...func(*awss3.Options)) (*awss3.HeadBucketOutput, error) {
+	if err := c.checkBucket(params.Bucket); err != nil {
+		return nil, err
+	}
+	return &awss3.HeadBucketOutput{}, nil
+}
+
+func (c *memoryClient) HeadObject(ctx context.Context, params *awss3.HeadObjectInput, optFns ...func(*awss3.Options)) (*awss3.HeadObjectOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.objects[aws.ToString(params.Key)]; !ok {
+		return nil, &types.NotFound{}
+	}
+	return &awss3.HeadObjectOutput{}, nil
+}
+
+func (c *memoryClient) GetObject(ctx context.Context, params *awss3.GetObjectInput, optFns ...func(*awss3.Options)) (*awss3.GetObjectOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	object, ok := c.objects[aws.ToString(params.Key)]
+	if !ok {
+		return nil, &types.NoSuchKey{}
+	}
+	return &awss3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(object))}, nil
+}
+
+func (c *memoryClient) DeleteObject(ctx context.Context, params *awss3.DeleteObjectInput, optFns ...func(*awss3.Options)) (*awss3.DeleteObjectOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.objects, aws.ToString(params.Key))
+	return &awss3.DeleteObjectOutput{}, nil
+}
+
+func (c *memoryClient) ListObjectsV2(ctx context.Context, params *awss3.ListObjectsV2Input, optFns ...func(*awss3.Options)) (*awss3.ListObjectsV2Output, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var keys []string
+	for key := range c.objects {
+		if strings.HasPrefix(key, aws.ToString(params.Prefix)) && key > aws.ToString(params.ContinuationToken) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	out := &awss3.ListObjectsV2Output{}
+	if len(keys) > c.pageSize {
+		keys = keys[:c.pageSize]
+		out.IsTruncated = true
+		out.NextContinuationToken = aws.String(keys[len(keys)-1])
+	}
+	for _, key := range keys {
+		out.Contents = append(out.Contents, types.Object{Key: aws.String(key)})
+	}
+	return out, nil
+}
+
+func (c *memoryClient) PutObject(ctx context.Context, params *awss3.PutObjectInput, optFns ...func(*awss3.Options)) (*awss3.PutObjectOutput, error) {
+	if err := c.checkBucket(params.Bucket); err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.objects[aws.ToString(params.Key)] = body
+	return &awss3.PutObjectOutput{}, nil
+}
+
+func (c *memoryClient) CreateMultipartUpload(ctx context.Context, params *awss3.CreateMultipartUploadInput, optFns ...func(*awss3.Options)) (*awss3.CreateMultipartUploadOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.multiparts++
+	return &awss3.CreateMultipartUploadOutput{UploadId: params.Key}, nil
+}
+
+func (c *memoryClient) UploadPart(ctx context.Context, params *awss3.UploadPartInput, optFns ...func(*awss3.Options)) (*awss3.UploadPartOutput, error) {
+	body, err := ioutil.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	uploadID := aws.ToString(params.UploadId)
+	for int32(len(c.parts[uploadID])) < params.PartNumber {
+		c.parts[uploadID] = append(c.parts[uploadID], nil)
+	}
+	c.parts[uploadID][params.PartNumber-1] = body
+	return &awss3.UploadPartOutput{ETag: aws.String("etag")}, nil
+}
+
+func (c *memoryClient) CompleteMultipartUpload(ctx context.Context, params *awss3.CompleteMultipartUploadInput, optFns ...func(*awss3.Options)) (*awss3.CompleteMultipartUploadOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	uploadID := aws.ToString(params.UploadId)
+	c.objects[aws.ToString(params.Key)] = bytes.Join(c.parts[uploadID], nil)
+	delete(c.parts, uploadID)
+	return &awss3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (c *memoryClient) AbortMultipartUpload(ctx context.Context, params *awss3.AbortMultipartUploadInput, optFns ...func(*awss3.Options)) (*awss3.AbortMultipartUploadOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.parts, aws.ToString(params.UploadId))
+	return &awss3.AbortMultipartUploadOutput{}, nil
+}
+
+func TestS3ObjectWriter(t *testing.T) {
+	ctx := context.Background()
+	t.Run("should upload small objects in a single request", func(t *testing.T) {
+		client := newMemoryClient("dags")
+		writer := &s3.S3ObjectWriter{Client: client}
+
+		w, err := writer.NewWriter(ctx, "dags", "/jobs/ns/hello.py")
+		assert.Nil(t, err)
+		_, err = io.Copy(w, strings.NewReader("print('hello')"))
+		assert.Nil(t, err)
+		assert.Nil(t, w.Close())
+
+		assert.Equal(t, "print('hello')", string(client.objects["jobs/ns/hello.py"]))
+		assert.Equal(t, 0, client.multiparts)
+	})
+	t.Run("should use multipart upload for objects larger than a part", func(t *testing.T) {
+		client := newMemoryClient("dags")
+		writer := &s3.S3ObjectWriter{Client: client}
+		contents := bytes.Repeat([]byte("a"), int(s3.UploadPartSize)*2+10)
+
+		w, err := writer.NewWriter(ctx, "dags", "jobs/ns/large.py")
+		assert.Nil(t, err)
+		_, err = io.Copy(w, bytes.NewReader(contents))
+		assert.Nil(t, err)
+		assert.Nil(t, w.Close())
+
+		assert.Equal(t, contents, client.objects["jobs/ns/large.py"])
+		assert.Equal(t, 1, client.multiparts)
+	})
+	t.Run("should fail if bucket is not accessible", func(t *testing.T) {
+		writer := &s3.S3ObjectWriter{Client: newMemoryClient("dags")}
+
+		_, err := writer.NewWriter(ctx, "unknown", "jobs/ns/hello.py")
+		var noSuchBucket *types.NoSuchBucket
+		assert.True(t, errors.As(err, &noSuchBucket))
+		assert.Contains(t, err.Error(), "failed to access bucket unknown")
+	})
+}
+
+func TestParseURL(t *testing.T) {
+	t.Run("should return bucket and prefix", func(t *testing.T) {
+		u, _ := url.Parse("s3://dags-bucket/optimus/project/")
+		bucket, prefix, err := s3.ParseURL(u)
+		assert.Nil(t, err)
+		assert.Equal(t, "dags-bucket", bucket)
+		assert.Equal(t, "optimus/project", prefix)
+	})
+	t.Run("should fail if bucket is missing", func(t *testing.T) {
+		u, _ := url.Parse("s3:///optimus")
+		_, _, err := s3.ParseURL(u)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("should create client for s3 compatible endpoint", func(t *testing.T) {
+		client, err := s3.NewClient(`{"access_key_id": "minio", "secret_access_key": "minio123", "endpoint": "http://localhost:9000"}`)
+		assert.Nil(t, err)
+		assert.NotNil(t, client)
+	})
+	t.Run("should fail if keys are missing", func(t *testing.T) {
+		_, err := s3.NewClient(`{"region": "eu-west-1"}`)
+		assert.NotNil(t, err)
+	})
+	t.Run("should fail if credentials are not json", func(t *testing.T) {
+		_, err := s3.NewClient(`not-json`)
+		assert.NotNil(t, err)
+	})
+}
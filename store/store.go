@@ -13,13 +13,63 @@ import (
 
 var (
 	ErrResourceNotFound = errors.New("resource not found")
+	ErrResourceExists   = errors.New("resource already exists")
+
+	// ErrSecretDecryption is returned when stored secrets can't be decrypted
+	// with the given application key
+	ErrSecretDecryption = errors.New("secret can't be decrypted")
+
+	// ErrSecretExpired is returned when a secret is read after its expiry
+	ErrSecretExpired = errors.New("secret has expired")
 )
 
+// JobSpecCSVColumns are the columns of job specifications exported as csv
+var JobSpecCSVColumns = []string{
+	"job_name", "task_type", "owner", "cron_schedule", "sla_duration", "labels",
+	"dependencies_count", "last_successful_run", "created_at", "updated_at",
+}
+
 // ProjectJobSpecRepository represents a storage interface for Job specifications at a project level
 type ProjectJobSpecRepository interface {
 	GetByName(string) (models.JobSpec, models.NamespaceSpec, error)
 	GetAll() ([]models.JobSpec, error)
 	GetByDestination(string) (models.JobSpec, models.ProjectSpec, error)
+	// GetDependents returns the job specifications of the project depending
+	// on the job by name
+	GetDependents(jobName string) ([]models.JobSpec, error)
+	// ExportCSV streams the job specifications of the project as csv with a
+	// header row, see JobSpecCSVColumns
+	ExportCSV(context.Context) (io.ReadCloser, error)
+	// GetModifiedSince returns the job specifications of the project updated
+	// after since along with their namespaces keyed by job name
+	GetModifiedSince(since time.Time) ([]models.JobSpec, map[string]models.NamespaceSpec, error)
+	// GetDeletedSince returns the namespaces of job specifications of the
+	// project deleted after since keyed by job name
+	GetDeletedSince(since time.Time) (map[string]models.NamespaceSpec, error)
+	// GetVersions returns the earlier specs of a job archived each time its
+	// spec changed, GetVersion returns one of them by version number
+	GetVersions(jobName string) ([]models.JobSpecVersion, error)
+	GetVersion(jobName string, version int) (models.JobSpecVersion, error)
+}
+
+// JobSpecRepository represents a storage interface for Job specifications
+// across all projects
+type JobSpecRepository interface {
+	// GetJobsByOwner returns the job specifications of all projects owned
+	// by owner along with their namespaces
+	GetJobsByOwner(ctx context.Context, owner string) ([]models.JobSpecWithNamespace, error)
+
+	// PurgeDeleted permanently removes job specifications of all projects
+	// soft deleted more than olderThan ago, returns the number removed
+	PurgeDeleted(ctx context.Context, olderThan time.Duration) (int, error)
+
+	// InsertAll saves new job specifications in their namespaces, none of
+	// the jobs are saved if any of them is already registered in its project
+	InsertAll(ctx context.Context, jobs []models.JobSpecWithNamespace) error
+
+	// GetCronExpression returns the schedule interval of a job of the
+	// project without reading the rest of its specification
+	GetCronExpression(ctx context.Context, projectSpec models.ProjectSpec, jobName string) (string, error)
 }
 
 // ProjectRepository represents a storage interface for registered projects
@@ -27,6 +77,18 @@ type ProjectRepository interface {
 	Save(models.ProjectSpec) error
 	GetByName(string) (models.ProjectSpec, error)
 	GetAll() ([]models.ProjectSpec, error)
+
+	// SaveVariables replaces the variables of the project, they aren't
+	// changed by saving the project
+	SaveVariables(projectName string, variables models.ProjectVariables) error
+
+	// SaveQuota replaces the quota of the project, it isn't changed by
+	// saving the project
+	SaveQuota(projectName string, quota models.ProjectQuota) error
+
+	// GetProjectHealth probes the tables of the project, the returned health
+	// contains the database check and the last deployment time
+	GetProjectHealth(context.Context, models.ProjectSpec) (*models.ProjectHealth, error)
 }
 
 // ProjectSecretRepository stores secrets attached to projects
@@ -34,6 +96,74 @@ type ProjectSecretRepository interface {
 	Save(item models.ProjectSecretItem) error
 	GetByName(string) (models.ProjectSecretItem, error)
 	GetAll() ([]models.ProjectSecretItem, error)
+
+	// GetVersion returns the value a secret was saved with at the version,
+	// versions start at 1
+	GetVersion(name string, version int) (models.ProjectSecretItem, error)
+	// ListVersions returns the versions kept for a secret, values are left out
+	ListVersions(name string) ([]models.ProjectSecretVersion, error)
+
+	// Migrate re-encrypts all secrets of the project from one application
+	// key to another, used when the key is rotated
+	Migrate(fromHash, toHash models.ApplicationKey) error
+}
+
+// ProjectRoleBindingRepository stores the roles subjects have in a project
+type ProjectRoleBindingRepository interface {
+	// Save binds the role to the subject, replacing the role it had
+	Save(models.ProjectRoleBinding) error
+	Delete(subject string) error
+	GetAll() ([]models.ProjectRoleBinding, error)
+}
+
+// APIKeyRepository stores api keys of a project by the hash of the key
+type APIKeyRepository interface {
+	Save(key models.APIKey, keyHash string) error
+}
+
+// AuditLogRepository stores the calls changing the state of optimus, entries
+// are listed from the latest
+type AuditLogRepository interface {
+	Save(ctx context.Context, entry models.AuditLogEntry) error
+	GetAll(ctx context.Context, filter models.AuditLogFilter) ([]models.AuditLogEntry, error)
+}
+
+// SLABreachRepository stores runs of jobs that didn't start within their
+// SLA duration, breaches are listed from the latest scheduled run
+type SLABreachRepository interface {
+	// Save records the breach, ErrResourceExists is returned if the run was
+	// already recorded as breached
+	Save(ctx context.Context, breach models.SLABreach) error
+	GetAll(ctx context.Context, projectName string, filter models.SLABreachFilter) ([]models.SLABreach, error)
+}
+
+// JobRunTriggerRepository stores runs of jobs started on demand for audit
+type JobRunTriggerRepository interface {
+	Save(ctx context.Context, trigger models.JobRunTrigger) error
+}
+
+// JobPauseRepository stores the jobs with their scheduled runs paused, so the
+// paused state can be restored on the scheduler
+type JobPauseRepository interface {
+	// Save pauses or resumes all the jobs of the project together
+	Save(ctx context.Context, projectName string, jobNames []string, paused bool, principal string, at time.Time) error
+	// GetPaused returns names of the paused jobs of the project
+	GetPaused(ctx context.Context, projectName string) ([]string, error)
+	// GetPausePeriods returns the times the job was paused ordered by when
+	// it was paused, including the one it is still paused in
+	GetPausePeriods(ctx context.Context, projectName, jobName string) ([]models.JobPausePeriod, error)
+}
+
+// SecretExpiryLister finds secrets of all projects expiring before a time,
+// including the ones already expired
+type SecretExpiryLister interface {
+	GetExpiring(before time.Time) ([]models.ExpiringProjectSecret, error)
+}
+
+// SecretRotator re-encrypts secrets of all projects when the application
+// key of the server is rotated
+type SecretRotator interface {
+	RotateSecrets(oldKey, newKey models.ApplicationKey) error
 }
 
 // NamespaceRepository represents a storage interface for registered namespaces
@@ -61,6 +191,25 @@ type InstanceSpecRepository interface {
 
 	// Clear will not delete the record but will reset all the run details
 	Clear(time.Time) error
+
+	// ListActiveInstances returns running instances of all jobs across projects
+	// created within maxAge, it is not scoped to the job of the repository
+	ListActiveInstances(ctx context.Context, maxAge time.Duration) ([]models.InstanceSpec, error)
+
+	// GetRunningDuration returns for how long the latest running instance of
+	// the job has been running
+	GetRunningDuration(ctx context.Context, jobSpec models.JobSpec) (time.Duration, error)
+
+	// AggregateByStatus counts instances of all jobs in the project created
+	// within window keyed by their state, it is not scoped to the job of the repository
+	AggregateByStatus(ctx context.Context, projectSpec models.ProjectSpec, window time.Duration) (map[string]int64, error)
+
+	// ListStaleInstances returns running instances of all jobs in the project
+	// created more than minAge ago, it is not scoped to the job of the repository
+	ListStaleInstances(ctx context.Context, projectSpec models.ProjectSpec, minAge time.Duration) ([]models.InstanceSpec, error)
+
+	// UpdateState moves the instance with id to state for reason
+	UpdateState(ctx context.Context, id uuid.UUID, state, reason string) error
 }
 
 // ProjectResourceSpecRepository represents a storage interface for Resource specifications at project level
@@ -88,6 +237,33 @@ type ObjectReader interface {
 	NewReader(bucket, path string) (io.ReadCloser, error)
 }
 
+// DependencyPinRepository stores the upstream job specs pinned by the jobs of a project
+type DependencyPinRepository interface {
+	// Pin snapshots the current spec of the dependency for the job under specVersion,
+	// replacing the existing pin between them
+	Pin(jobName, dependencyName, specVersion string) error
+	Delete(jobName, dependencyName string) error
+	GetByJob(jobName string) ([]models.DependencyPin, error)
+	GetAll() ([]models.DependencyPin, error)
+}
+
+// ReleaseRepository stores named snapshots of the job specs of a project
+type ReleaseRepository interface {
+	// Create snapshots the current job specs of the project under tag
+	Create(tag, description string) error
+	GetByTag(tag string) (models.Release, error)
+}
+
+// DeploymentStateRepository stores the time jobs of a project were last deployed
+type DeploymentStateRepository interface {
+	// GetLastDeployedAt returns zero time if the project was never deployed
+	GetLastDeployedAt() (time.Time, error)
+	SetLastDeployedAt(time.Time) error
+	// TryLock takes the deployment lock of the project shared by all servers
+	// until unlock is called, it returns false if another server holds it
+	TryLock(ctx context.Context) (unlock func() error, locked bool, err error)
+}
+
 // ReplaySpecRepository represents a storage interface for replay objects
 type ReplaySpecRepository interface {
 	Insert(replay *models.ReplaySpec) error
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNonPublicAddress is returned for connections to addresses not reachable
+// over the internet
+var ErrNonPublicAddress = errors.New("address is not public")
+
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",      // this network
+	"10.0.0.0/8",     // private
+	"100.64.0.0/10",  // carrier grade nat
+	"127.0.0.0/8",    // loopback
+	"169.254.0.0/16", // link local, includes cloud metadata endpoints
+	"172.16.0.0/12",  // private
+	"192.0.0.0/24",   // ietf protocol assignments
+	"192.168.0.0/16", // private
+	"198.18.0.0/15",  // benchmarking
+	"224.0.0.0/4",    // multicast
+	"240.0.0.0/4",    // reserved, includes broadcast
+	"::/128",         // unspecified
+	"::1/128",        // loopback
+	"fc00::/7",       // unique local
+	"fe80::/10",      // link local
+	"ff00::/8",       // multicast
+	"64:ff9b:1::/48", // local use nat64
+	"2001:db8::/32",  // documentation
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// IsPublicIP is false for loopback, link local, private and other addresses
+// not reachable over the internet
+func IsPublicIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	// ipv4 mapped ipv6 addresses are checked as ipv4
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewPublicDialer dials public addresses only. Addresses are checked once
+// host names are resolved, so names resolving to internal addresses are
+// refused whenever they are dialed
+func NewPublicDialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !IsPublicIP(net.ParseIP(host)) {
+				return errors.Wrap(ErrNonPublicAddress, host)
+			}
+			return nil
+		},
+	}
+}
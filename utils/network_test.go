@@ -0,0 +1,41 @@
+package utils_test
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	t.Run("should be false for internal addresses", func(t *testing.T) {
+		for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.20.0.1", "192.168.1.1", "169.254.169.254",
+			"100.64.0.1", "0.0.0.0", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1", "::ffff:10.0.0.1"} {
+			assert.False(t, utils.IsPublicIP(net.ParseIP(ip)), ip)
+		}
+	})
+	t.Run("should be true for public addresses", func(t *testing.T) {
+		for _, ip := range []string{"8.8.8.8", "172.32.0.1", "2606:4700:4700::1111"} {
+			assert.True(t, utils.IsPublicIP(net.ParseIP(ip)), ip)
+		}
+	})
+	t.Run("should be false for missing addresses", func(t *testing.T) {
+		assert.False(t, utils.IsPublicIP(nil))
+	})
+}
+
+func TestNewPublicDialer(t *testing.T) {
+	t.Run("should refuse to connect to internal addresses", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer srv.Close()
+		client := &http.Client{Transport: &http.Transport{DialContext: utils.NewPublicDialer(time.Second).DialContext}}
+
+		_, err := client.Get(srv.URL)
+		assert.True(t, errors.Is(err, utils.ErrNonPublicAddress))
+	})
+}